@@ -0,0 +1,196 @@
+package amount
+
+import (
+	"math/big"
+	"regexp"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// RoundingMode is a way of rounding a Decimal to a whole number of stroops.
+type RoundingMode int
+
+const (
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor RoundingMode = iota
+	// RoundCeil rounds towards positive infinity.
+	RoundCeil
+	// RoundHalfEven rounds to the nearest stroop, and halves to the even
+	// stroop (banker's rounding).
+	RoundHalfEven
+	// RoundDown rounds towards zero.
+	RoundDown
+)
+
+var (
+	bigHundred = big.NewRat(100, 1)
+	// validDecimal limits the length of decimal strings for the same reason as
+	// validAmountSimple, but allows more fractional digits as decimals are
+	// used for rates and intermediate results.
+	validDecimal = regexp.MustCompile(`^-?([0-9]{1,40}(\.[0-9]{0,40})?|\.[0-9]{1,40})$`)
+)
+
+// Decimal is an arbitrary-precision decimal number of units of an asset, to
+// be used for computations on amounts, e.g. fees or interests, whose
+// intermediate results do not fit in stroops. A Decimal is rounded back to
+// stroops with Round. Operations return a new Decimal and never modify their
+// operands. The zero value is 0.
+type Decimal struct {
+	r *big.Rat
+}
+
+// NewDecimal returns the Decimal of an amount in stroops.
+func NewDecimal(stroops int64) Decimal {
+	return Decimal{r: new(big.Rat).SetFrac(big.NewInt(stroops), big.NewInt(One))}
+}
+
+// NewDecimalFromRat returns the Decimal of the number of units r. r is
+// copied.
+func NewDecimalFromRat(r *big.Rat) Decimal {
+	return Decimal{r: new(big.Rat).Set(r)}
+}
+
+// MustParseDecimal is the panicking version of ParseDecimal.
+func MustParseDecimal(v string) Decimal {
+	d, err := ParseDecimal(v)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal number of units, e.g. "12.5" or "0.0025",
+// with up to 40 digits in its integer and fractional parts.
+func ParseDecimal(v string) (Decimal, error) {
+	if !validDecimal.MatchString(v) {
+		return Decimal{}, errors.Errorf("invalid decimal format: %s", v)
+	}
+
+	r, ok := new(big.Rat).SetString(v)
+	if !ok {
+		return Decimal{}, errors.Errorf("cannot parse decimal: %s", v)
+	}
+	return Decimal{r: r}, nil
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d.r == nil {
+		return new(big.Rat)
+	}
+	return d.r
+}
+
+// Add returns d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Add(d.rat(), o.rat())}
+}
+
+// Sub returns d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Sub(d.rat(), o.rat())}
+}
+
+// Mul returns d * o.
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Mul(d.rat(), o.rat())}
+}
+
+// Quo returns d / o, or an error if o is 0.
+func (d Decimal) Quo(o Decimal) (Decimal, error) {
+	if o.Sign() == 0 {
+		return Decimal{}, errors.New("division by zero")
+	}
+	return Decimal{r: new(big.Rat).Quo(d.rat(), o.rat())}, nil
+}
+
+// Percent returns percent % of d, e.g. d.Percent(MustParseDecimal("2.5"))
+// is 2.5% of d.
+func (d Decimal) Percent(percent Decimal) Decimal {
+	r := new(big.Rat).Mul(d.rat(), percent.rat())
+	return Decimal{r: r.Quo(r, bigHundred)}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{r: new(big.Rat).Neg(d.rat())}
+}
+
+// Cmp compares d and o, and returns -1 if d < o, 0 if d == o and +1 if
+// d > o.
+func (d Decimal) Cmp(o Decimal) int {
+	return d.rat().Cmp(o.rat())
+}
+
+// Sign returns -1 if d < 0, 0 if d == 0 and +1 if d > 0.
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// Rat returns a copy of d as a number of units.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).Set(d.rat())
+}
+
+// Round rounds d to a whole number of stroops using mode, and returns an
+// error if the result does not fit in an int64.
+func (d Decimal) Round(mode RoundingMode) (int64, error) {
+	stroops := new(big.Rat).Mul(d.rat(), bigOne)
+	i, err := roundRat(stroops, mode)
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, errors.Errorf("amount outside bounds of int64: %s", i.String())
+	}
+	return i.Int64(), nil
+}
+
+// StringRounded returns the "amount string" of d rounded to stroops using
+// mode.
+func (d Decimal) StringRounded(mode RoundingMode) (string, error) {
+	stroops, err := d.Round(mode)
+	if err != nil {
+		return "", err
+	}
+	return StringFromInt64(stroops), nil
+}
+
+// String returns d with 7 fractional digits, rounding halves away from
+// zero. Use StringRounded to choose the rounding mode.
+func (d Decimal) String() string {
+	return d.rat().FloatString(7)
+}
+
+// roundRat rounds r to an integer using mode.
+func roundRat(r *big.Rat, mode RoundingMode) (*big.Int, error) {
+	// the denominator of r is positive, so q is the floor of r and m is
+	// positive
+	q, m := new(big.Int).DivMod(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() == 0 {
+		return q, nil
+	}
+
+	roundUp := false
+	switch mode {
+	case RoundFloor:
+	case RoundCeil:
+		roundUp = true
+	case RoundDown:
+		roundUp = r.Sign() < 0
+	case RoundHalfEven:
+		switch new(big.Int).Lsh(m, 1).Cmp(r.Denom()) {
+		case -1:
+		case 1:
+			roundUp = true
+		default:
+			roundUp = q.Bit(0) == 1
+		}
+	default:
+		return nil, errors.Errorf("invalid rounding mode: %d", mode)
+	}
+
+	if roundUp {
+		q.Add(q, big.NewInt(1))
+	}
+	return q, nil
+}
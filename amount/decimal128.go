@@ -0,0 +1,314 @@
+package amount
+
+import (
+	"encoding/binary"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	decimal128Bias        = 6176
+	decimal128MinExponent = -6176
+	decimal128MaxExponent = 6111
+	decimal128MaxDigits   = 34
+)
+
+var (
+	bigTen               = big.NewInt(10)
+	decimal128MaxCoeff   = new(big.Int).Sub(new(big.Int).Exp(bigTen, big.NewInt(decimal128MaxDigits), nil), big.NewInt(1))
+	errNotWholeStroops   = errors.New("value is not a whole number of stroops")
+	errDecimal128Special = errors.New("value is NaN or infinite")
+)
+
+// Decimal128 is an IEEE 754-2008 decimal128 floating-point number in the
+// binary integer decimal (BID) encoding, as stored by BSON, Parquet or Arrow
+// writers supporting it, split into its High and Low 64 bits. Decimal128
+// represents every amount exactly, with the 7 fractional digits of stroops.
+type Decimal128 struct {
+	High, Low uint64
+}
+
+// ToDecimal128 returns the decimal128 of an amount in stroops. The conversion
+// is always exact, and the exponent of the result is -7 so that the number
+// of fractional digits of amounts is kept, e.g. 10000000 is 1.0000000.
+func ToDecimal128(stroops int64) Decimal128 {
+	neg := stroops < 0
+	coeff := uint64(stroops)
+	if neg {
+		coeff = -coeff
+	}
+	d := Decimal128{
+		High: uint64(decimal128Bias-7) << 49,
+		Low:  coeff,
+	}
+	if neg {
+		d.High |= 1 << 63
+	}
+	return d
+}
+
+// FromDecimal128 returns the amount in stroops of d, or an error if d is NaN
+// or infinite, is not a whole number of stroops, or does not fit in an int64.
+// Values are never rounded.
+func FromDecimal128(d Decimal128) (int64, error) {
+	dec, err := NewDecimalFromDecimal128(d)
+	if err != nil {
+		return 0, err
+	}
+	return dec.exactStroops()
+}
+
+// ToAPD returns the apd.Decimal of an amount in stroops, with an exponent of
+// -7. The conversion is always exact.
+func ToAPD(stroops int64) *apd.Decimal {
+	return apd.New(stroops, -7)
+}
+
+// FromAPD returns the amount in stroops of d, or an error if d is not finite,
+// is not a whole number of stroops, or does not fit in an int64. Values are
+// never rounded.
+func FromAPD(d *apd.Decimal) (int64, error) {
+	dec, err := NewDecimalFromAPD(d)
+	if err != nil {
+		return 0, err
+	}
+	return dec.exactStroops()
+}
+
+// NewDecimalFromDecimal128 returns the Decimal of the number of units d, or
+// an error if d is NaN or infinite.
+func NewDecimalFromDecimal128(d Decimal128) (Decimal, error) {
+	neg, coeff, exp, err := d.parts()
+	if err != nil {
+		return Decimal{}, err
+	}
+	if neg {
+		coeff.Neg(coeff)
+	}
+	return Decimal{r: scaledRat(coeff, exp)}, nil
+}
+
+// NewDecimalFromAPD returns the Decimal of the number of units d, or an error
+// if d is not finite or its exponent is outside the range of decimal128.
+func NewDecimalFromAPD(d *apd.Decimal) (Decimal, error) {
+	if d.Form != apd.Finite {
+		return Decimal{}, errors.Errorf("value is not finite: %s", d.String())
+	}
+	exp := int(d.Exponent)
+	if exp < decimal128MinExponent || exp > decimal128MaxExponent {
+		return Decimal{}, errors.Errorf("exponent out of range: %d", exp)
+	}
+	coeff := new(big.Int).Set(&d.Coeff)
+	if d.Negative {
+		coeff.Neg(coeff)
+	}
+	return Decimal{r: scaledRat(coeff, exp)}, nil
+}
+
+// Decimal128 returns d as a decimal128, or an error if d has no exact
+// decimal128 representation, i.e. if it has an infinite decimal expansion
+// (e.g. 1/3), more than 34 significant digits, or is too large or too small.
+func (d Decimal) Decimal128() (Decimal128, error) {
+	coeff, exp, err := d.decimalParts()
+	if err != nil {
+		return Decimal128{}, err
+	}
+	return newDecimal128(coeff, exp)
+}
+
+// APD returns d as an apd.Decimal, or an error if d has an infinite decimal
+// expansion (e.g. 1/3).
+func (d Decimal) APD() (*apd.Decimal, error) {
+	coeff, exp, err := d.decimalParts()
+	if err != nil {
+		return nil, err
+	}
+	return apd.NewWithBigInt(coeff, int32(exp)), nil
+}
+
+// exactStroops returns d in stroops, or an error if d is not a whole number
+// of stroops or does not fit in an int64.
+func (d Decimal) exactStroops() (int64, error) {
+	stroops := new(big.Rat).Mul(d.rat(), bigOne)
+	if !stroops.IsInt() {
+		return 0, errNotWholeStroops
+	}
+	if !stroops.Num().IsInt64() {
+		return 0, errors.Errorf("amount outside bounds of int64: %s", stroops.Num().String())
+	}
+	return stroops.Num().Int64(), nil
+}
+
+// decimalParts returns the signed coefficient and the exponent of the
+// shortest decimal representation of d, or an error if d has an infinite
+// decimal expansion.
+func (d Decimal) decimalParts() (*big.Int, int, error) {
+	r := d.rat()
+	num, denom := new(big.Int).Set(r.Num()), r.Denom()
+
+	// The decimal expansion of a reduced fraction is finite if and only if
+	// its denominator is of the form 2^a * 5^b.
+	rest := new(big.Int).Set(denom)
+	twos, fives := 0, 0
+	two, five, m := big.NewInt(2), big.NewInt(5), new(big.Int)
+	for {
+		q, _ := new(big.Int).QuoRem(rest, two, m)
+		if m.Sign() != 0 {
+			break
+		}
+		rest = q
+		twos++
+	}
+	for {
+		q, _ := new(big.Int).QuoRem(rest, five, m)
+		if m.Sign() != 0 {
+			break
+		}
+		rest = q
+		fives++
+	}
+	if rest.Cmp(big.NewInt(1)) != 0 {
+		return nil, 0, errors.Errorf("value has no exact decimal representation: %s", r.String())
+	}
+
+	// num / (2^a * 5^b) = num * 10^k / (2^a * 5^b) / 10^k, with k = max(a, b)
+	k := twos
+	if fives > k {
+		k = fives
+	}
+	coeff := num.Mul(num, new(big.Int).Exp(bigTen, big.NewInt(int64(k)), nil))
+	coeff.Quo(coeff, denom)
+	return coeff, -k, nil
+}
+
+// newDecimal128 encodes coeff * 10^exp, removing trailing zeros of coeff if
+// it has too many digits.
+func newDecimal128(coeff *big.Int, exp int) (Decimal128, error) {
+	neg := coeff.Sign() < 0
+	coeff = new(big.Int).Abs(coeff)
+
+	m := new(big.Int)
+	for coeff.Cmp(decimal128MaxCoeff) > 0 && exp < decimal128MaxExponent {
+		q, _ := new(big.Int).QuoRem(coeff, bigTen, m)
+		if m.Sign() != 0 {
+			break
+		}
+		coeff = q
+		exp++
+	}
+	if coeff.Cmp(decimal128MaxCoeff) > 0 {
+		return Decimal128{}, errors.Errorf("value has more than %d significant digits", decimal128MaxDigits)
+	}
+	if exp < decimal128MinExponent || exp > decimal128MaxExponent {
+		return Decimal128{}, errors.Errorf("exponent out of range: %d", exp)
+	}
+
+	// The coefficient has at most 113 bits, the upper 49 of which are stored
+	// in High.
+	var b [16]byte
+	coeff.FillBytes(b[:])
+	d := Decimal128{
+		High: uint64(exp+decimal128Bias)<<49 | binary.BigEndian.Uint64(b[:8]),
+		Low:  binary.BigEndian.Uint64(b[8:]),
+	}
+	if neg {
+		d.High |= 1 << 63
+	}
+	return d, nil
+}
+
+// IsNaN returns true if d is a NaN.
+func (d Decimal128) IsNaN() bool {
+	return d.High>>58&0x1f == 0x1f
+}
+
+// IsInf returns true if d is positive or negative infinity.
+func (d Decimal128) IsInf() bool {
+	return d.High>>58&0x1f == 0x1e
+}
+
+// parts decodes d into its sign, coefficient and exponent, or returns an
+// error if d is NaN or infinite. Non-canonical coefficients, which exceed
+// 34 digits, are decoded as 0 as required by IEEE 754.
+func (d Decimal128) parts() (bool, *big.Int, int, error) {
+	if d.IsNaN() || d.IsInf() {
+		return false, nil, 0, errDecimal128Special
+	}
+	neg := d.High>>63 == 1
+
+	var exp int
+	coeff := new(big.Int)
+	if d.High>>61&3 == 3 {
+		// The coefficient is 100 followed by 111 bits, which is larger than
+		// the largest coefficient.
+		exp = int(d.High>>47&0x3fff) - decimal128Bias
+	} else {
+		exp = int(d.High>>49&0x3fff) - decimal128Bias
+		coeff.SetUint64(d.High & (1<<49 - 1))
+		coeff.Lsh(coeff, 64)
+		coeff.Or(coeff, new(big.Int).SetUint64(d.Low))
+		if coeff.Cmp(decimal128MaxCoeff) > 0 {
+			coeff.SetInt64(0)
+		}
+	}
+	return neg, coeff, exp, nil
+}
+
+// String returns d in the scientific notation of IEEE 754, e.g. "1.0000000",
+// "-0.5" or "1.2E+40".
+func (d Decimal128) String() string {
+	if d.IsNaN() {
+		return "NaN"
+	}
+	sign := ""
+	if d.High>>63 == 1 {
+		sign = "-"
+	}
+	if d.IsInf() {
+		return sign + "Infinity"
+	}
+
+	_, coeff, exp, _ := d.parts()
+	digits := coeff.String()
+	adjusted := exp + len(digits) - 1
+	if exp <= 0 && adjusted >= -6 {
+		if exp == 0 {
+			return sign + digits
+		}
+		point := len(digits) + exp
+		if point > 0 {
+			return sign + digits[:point] + "." + digits[point:]
+		}
+		return sign + "0." + strings.Repeat("0", -point) + digits
+	}
+
+	s := digits[:1]
+	if len(digits) > 1 {
+		s += "." + digits[1:]
+	}
+	s += "E"
+	if adjusted >= 0 {
+		s += "+"
+	}
+	return sign + s + strconv.Itoa(adjusted)
+}
+
+// scaledRat returns coeff * 10^exp.
+func scaledRat(coeff *big.Int, exp int) *big.Rat {
+	scale := new(big.Int).Exp(bigTen, big.NewInt(int64(abs(exp))), nil)
+	if exp >= 0 {
+		return new(big.Rat).SetInt(coeff.Mul(coeff, scale))
+	}
+	return new(big.Rat).SetFrac(coeff, scale)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,109 @@
+package amount_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/amount"
+)
+
+func TestDecimal128Stroops(t *testing.T) {
+	for _, c := range []struct {
+		stroops int64
+		s       string
+	}{
+		{0, "0E-7"},
+		{1, "1E-7"},
+		{10000000, "1.0000000"},
+		{-15000000, "-1.5000000"},
+		{math.MaxInt64, "922337203685.4775807"},
+		{math.MinInt64, "-922337203685.4775808"},
+	} {
+		d := amount.ToDecimal128(c.stroops)
+		assert.Equal(t, c.s, d.String())
+		stroops, err := amount.FromDecimal128(d)
+		require.NoError(t, err)
+		assert.Equal(t, c.stroops, stroops)
+
+		a := amount.ToAPD(c.stroops)
+		stroops, err = amount.FromAPD(a)
+		require.NoError(t, err)
+		assert.Equal(t, c.stroops, stroops)
+	}
+
+	// 1 with an exponent of 0
+	one := amount.Decimal128{High: 0x3040000000000000, Low: 1}
+	assert.Equal(t, "1", one.String())
+	stroops, err := amount.FromDecimal128(one)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000000), stroops)
+
+	// 1E-8
+	_, err = amount.FromDecimal128(amount.Decimal128{High: 0x302e000000000000, Low: 1})
+	assert.EqualError(t, err, "value is not a whole number of stroops")
+	// 1E+12
+	_, err = amount.FromDecimal128(amount.Decimal128{High: 0x3058000000000000, Low: 1})
+	assert.EqualError(t, err, "amount outside bounds of int64: 10000000000000000000")
+
+	nan := amount.Decimal128{High: 0x7c00000000000000}
+	inf := amount.Decimal128{High: 0xf800000000000000}
+	assert.True(t, nan.IsNaN())
+	assert.True(t, inf.IsInf())
+	assert.Equal(t, "NaN", nan.String())
+	assert.Equal(t, "-Infinity", inf.String())
+	_, err = amount.FromDecimal128(nan)
+	assert.EqualError(t, err, "value is NaN or infinite")
+
+	_, err = amount.FromAPD(apd.New(1, -8))
+	assert.EqualError(t, err, "value is not a whole number of stroops")
+	_, err = amount.FromAPD(&apd.Decimal{Form: apd.Infinite})
+	assert.EqualError(t, err, "value is not finite: Infinity")
+	_, err = amount.FromAPD(apd.New(1, math.MaxInt32))
+	assert.EqualError(t, err, "exponent out of range: 2147483647")
+}
+
+func TestDecimalDecimal128(t *testing.T) {
+	for _, c := range []struct {
+		v string
+		s string
+	}{
+		{"0", "0"},
+		{"1.5", "1.5"},
+		{"-0.0000001", "-1E-7"},
+		{"0.000000015", "1.5E-8"},
+		{"123456789012345678901234567890.1234", "123456789012345678901234567890.1234"},
+	} {
+		d, err := amount.MustParseDecimal(c.v).Decimal128()
+		require.NoError(t, err)
+		assert.Equal(t, c.s, d.String())
+		back, err := amount.NewDecimalFromDecimal128(d)
+		require.NoError(t, err)
+		assert.Equal(t, 0, back.Cmp(amount.MustParseDecimal(c.v)), c.v)
+
+		a, err := amount.MustParseDecimal(c.v).APD()
+		require.NoError(t, err)
+		back, err = amount.NewDecimalFromAPD(a)
+		require.NoError(t, err)
+		assert.Equal(t, 0, back.Cmp(amount.MustParseDecimal(c.v)), c.v)
+	}
+
+	// Trailing zeros are removed from coefficients with more than 34 digits.
+	e20 := amount.MustParseDecimal("100000000000000000000")
+	d, err := e20.Mul(e20).Decimal128()
+	require.NoError(t, err)
+	assert.Equal(t, "1.000000000000000000000000000000000E+40", d.String())
+
+	third, err := amount.NewDecimal(1).Quo(amount.NewDecimal(3))
+	require.NoError(t, err)
+	_, err = third.Decimal128()
+	assert.EqualError(t, err, "value has no exact decimal representation: 1/3")
+	_, err = third.APD()
+	assert.EqualError(t, err, "value has no exact decimal representation: 1/3")
+
+	_, err = amount.MustParseDecimal("1234567890123456789012345678901234.5").Decimal128()
+	assert.EqualError(t, err, "value has more than 34 significant digits")
+}
@@ -0,0 +1,83 @@
+package amount_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/amount"
+)
+
+func TestDecimalRound(t *testing.T) {
+	for _, c := range []struct {
+		v        string
+		floor    int64
+		ceil     int64
+		halfEven int64
+		down     int64
+	}{
+		{"1", 10000000, 10000000, 10000000, 10000000},
+		{"0.00000015", 1, 2, 2, 1},
+		{"0.00000025", 2, 3, 2, 2},
+		{"0.00000026", 2, 3, 3, 2},
+		{"-0.00000015", -2, -1, -2, -1},
+		{"-0.00000025", -3, -2, -2, -2},
+		{"-0.00000024", -3, -2, -2, -2},
+	} {
+		d := amount.MustParseDecimal(c.v)
+		for mode, expected := range map[amount.RoundingMode]int64{
+			amount.RoundFloor:    c.floor,
+			amount.RoundCeil:     c.ceil,
+			amount.RoundHalfEven: c.halfEven,
+			amount.RoundDown:     c.down,
+		} {
+			stroops, err := d.Round(mode)
+			require.NoError(t, err)
+			assert.Equal(t, expected, stroops, "%s rounded with mode %d", c.v, mode)
+		}
+	}
+
+	_, err := amount.MustParseDecimal("922337203685.4775808").Round(amount.RoundFloor)
+	assert.EqualError(t, err, "amount outside bounds of int64: 9223372036854775808")
+
+	_, err = amount.MustParseDecimal("0.00000001").Round(amount.RoundingMode(42))
+	assert.EqualError(t, err, "invalid rounding mode: 42")
+}
+
+func TestDecimalOperations(t *testing.T) {
+	// 100 XLM at 3.75% a year for 45 days
+	principal := amount.NewDecimal(1000000000)
+	days, err := amount.MustParseDecimal("45").Quo(amount.MustParseDecimal("365"))
+	require.NoError(t, err)
+	interest := principal.Percent(amount.MustParseDecimal("3.75")).Mul(days)
+
+	s, err := interest.StringRounded(amount.RoundFloor)
+	require.NoError(t, err)
+	assert.Equal(t, "0.4623287", s)
+	s, err = interest.StringRounded(amount.RoundCeil)
+	require.NoError(t, err)
+	assert.Equal(t, "0.4623288", s)
+	assert.Equal(t, "0.4623288", interest.String())
+
+	total := principal.Add(interest)
+	assert.Equal(t, 1, total.Cmp(principal))
+	assert.Equal(t, 0, total.Sub(interest).Cmp(principal))
+	assert.Equal(t, -1, interest.Neg().Sign())
+	assert.Equal(t, 0, amount.Decimal{}.Sign())
+	assert.Equal(t, "0.0000000", amount.Decimal{}.String())
+
+	_, err = principal.Quo(amount.Decimal{})
+	assert.EqualError(t, err, "division by zero")
+}
+
+func TestParseDecimal(t *testing.T) {
+	for _, v := range []string{"12.5", "-0.0025", "7", "0.00000000001"} {
+		_, err := amount.ParseDecimal(v)
+		assert.NoError(t, err, v)
+	}
+	for _, v := range []string{"", "-", ".", "1e10", "1/3", "Inf", "1.2.3", "100000000000000000000000000000000000000000"} {
+		_, err := amount.ParseDecimal(v)
+		assert.Error(t, err, v)
+	}
+}
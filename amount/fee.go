@@ -0,0 +1,66 @@
+package amount
+
+import (
+	"math/big"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// BasisPointsPerUnit is the number of basis points in one, i.e. 100%.
+const BasisPointsPerUnit = 10000
+
+// BasisPoints returns bps basis points of d, e.g. d.BasisPoints(25) is 0.25%
+// of d.
+func (d Decimal) BasisPoints(bps int64) Decimal {
+	r := new(big.Rat).Mul(d.rat(), big.NewRat(bps, BasisPointsPerUnit))
+	return Decimal{r: r}
+}
+
+// Fee is a fee computed on an amount.
+type Fee struct {
+	// Amount is the fee in stroops, rounded with the requested mode.
+	Amount int64
+	// Remainder is the exact fee minus Amount, i.e. the fraction of a stroop
+	// dropped by rounding down (positive) or added by rounding up
+	// (negative). Adding up the remainders of many fees tells how much was
+	// lost or gained to rounding.
+	Remainder Decimal
+}
+
+// Net returns the amount left after deducting the fee from amount stroops.
+func (f Fee) Net(amount int64) int64 {
+	return amount - f.Amount
+}
+
+// BasisPointsFee returns the fee of bps basis points on amount stroops, e.g.
+// a 0.3% withdrawal fee is BasisPointsFee(amount, 30, RoundCeil).
+func BasisPointsFee(amount, bps int64, mode RoundingMode) (Fee, error) {
+	if bps < 0 {
+		return Fee{}, errors.Errorf("basis points cannot be negative: %d", bps)
+	}
+	return fee(amount, NewDecimal(amount).BasisPoints(bps), mode)
+}
+
+// PercentFee returns the fee of percent % on amount stroops, e.g. a 2.5%
+// deposit fee is PercentFee(amount, MustParseDecimal("2.5"), RoundFloor).
+func PercentFee(amount int64, percent Decimal, mode RoundingMode) (Fee, error) {
+	if percent.Sign() < 0 {
+		return Fee{}, errors.Errorf("percent cannot be negative: %s", percent.rat().RatString())
+	}
+	return fee(amount, NewDecimal(amount).Percent(percent), mode)
+}
+
+func fee(amount int64, exact Decimal, mode RoundingMode) (Fee, error) {
+	if amount < 0 {
+		return Fee{}, errors.Errorf("amount cannot be negative: %d", amount)
+	}
+
+	stroops, err := exact.Round(mode)
+	if err != nil {
+		return Fee{}, err
+	}
+	return Fee{
+		Amount:    stroops,
+		Remainder: exact.Sub(NewDecimal(stroops)),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package amount_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/amount"
+)
+
+func TestBasisPointsFee(t *testing.T) {
+	// 30 bps of 10.0000033 is 0.0300000099
+	deposit := amount.MustParse("10.0000033")
+
+	fee, err := amount.BasisPointsFee(int64(deposit), 30, amount.RoundFloor)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300000), fee.Amount)
+	assert.Equal(t, int64(99700033), fee.Net(int64(deposit)))
+	assert.Equal(t, 0, fee.Remainder.Cmp(amount.MustParseDecimal("0.0000000099")))
+
+	fee, err = amount.BasisPointsFee(int64(deposit), 30, amount.RoundCeil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300001), fee.Amount)
+	assert.Equal(t, 0, fee.Remainder.Cmp(amount.MustParseDecimal("-0.0000000901")))
+
+	fee, err = amount.BasisPointsFee(int64(deposit), 0, amount.RoundCeil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), fee.Amount)
+	assert.Equal(t, 0, fee.Remainder.Sign())
+
+	_, err = amount.BasisPointsFee(int64(deposit), -1, amount.RoundCeil)
+	assert.EqualError(t, err, "basis points cannot be negative: -1")
+	_, err = amount.BasisPointsFee(-1, 30, amount.RoundCeil)
+	assert.EqualError(t, err, "amount cannot be negative: -1")
+}
+
+func TestPercentFee(t *testing.T) {
+	// 2.5% of 0.0000015 is 0.0000000375
+	fee, err := amount.PercentFee(15, amount.MustParseDecimal("2.5"), amount.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), fee.Amount)
+	assert.Equal(t, 0, fee.Remainder.Cmp(amount.MustParseDecimal("0.0000000375")))
+
+	fee, err = amount.PercentFee(1000000000, amount.MustParseDecimal("2.5"), amount.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, int64(25000000), fee.Amount)
+	assert.Equal(t, 0, fee.Remainder.Sign())
+
+	_, err = amount.PercentFee(15, amount.MustParseDecimal("-0.5"), amount.RoundFloor)
+	assert.EqualError(t, err, "percent cannot be negative: -1/2")
+}
+
+func TestDecimalBasisPoints(t *testing.T) {
+	d := amount.MustParseDecimal("200").BasisPoints(125)
+	assert.Equal(t, "2.5000000", d.String())
+}
@@ -0,0 +1,119 @@
+package amount
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// FormatOptions configures how Format renders an amount for humans, and how
+// ParseFormatted reads it back.
+type FormatOptions struct {
+	// DecimalPlaces is the number of fractional digits, from 0 to 7.
+	DecimalPlaces int
+	// Rounding is the rounding mode used when the amount has more
+	// fractional digits than DecimalPlaces. The zero value is RoundFloor.
+	Rounding RoundingMode
+	// ThousandsSeparator separates groups of three digits of the integer
+	// part, e.g. "," in English or "." in German. Digits are not grouped
+	// when it is empty.
+	ThousandsSeparator string
+	// DecimalSeparator separates the integer and fractional parts, e.g. "."
+	// in English or "," in German. It defaults to ".".
+	DecimalSeparator string
+	// Suffix, e.g. an asset code, is appended after a space.
+	Suffix string
+}
+
+func (opts FormatOptions) decimalSeparator() string {
+	if opts.DecimalSeparator == "" {
+		return "."
+	}
+	return opts.DecimalSeparator
+}
+
+// Format returns the amount v in stroops formatted with opts, e.g. 12345678900
+// formatted with 2 decimal places, the "," thousands separator and the "USD"
+// suffix is "1,234.57 USD".
+func Format(v int64, opts FormatOptions) (string, error) {
+	if opts.DecimalPlaces < 0 || opts.DecimalPlaces > 7 {
+		return "", errors.Errorf("invalid number of decimal places: %d", opts.DecimalPlaces)
+	}
+	if opts.ThousandsSeparator != "" && opts.ThousandsSeparator == opts.decimalSeparator() {
+		return "", errors.New("thousands and decimal separators cannot be the same")
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(7-opts.DecimalPlaces)), nil)
+	rounded, err := roundRat(new(big.Rat).SetFrac(big.NewInt(v), unit), opts.Rounding)
+	if err != nil {
+		return "", err
+	}
+
+	digits := new(big.Int).Abs(rounded).String()
+	if len(digits) <= opts.DecimalPlaces {
+		digits = strings.Repeat("0", opts.DecimalPlaces-len(digits)+1) + digits
+	}
+	integer, fraction := digits[:len(digits)-opts.DecimalPlaces], digits[len(digits)-opts.DecimalPlaces:]
+
+	var b strings.Builder
+	if rounded.Sign() < 0 {
+		b.WriteString("-")
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(opts.ThousandsSeparator)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(opts.decimalSeparator())
+		b.WriteString(fraction)
+	}
+	if opts.Suffix != "" {
+		b.WriteString(" ")
+		b.WriteString(opts.Suffix)
+	}
+	return b.String(), nil
+}
+
+// ParseLenient parses an amount typed by a human in English notation, e.g.
+// "1,234.56", and returns it in stroops. See ParseFormatted.
+func ParseLenient(v string) (int64, error) {
+	return ParseFormatted(v, FormatOptions{ThousandsSeparator: ","})
+}
+
+// ParseFormatted parses an amount formatted with the separators and suffix
+// of opts and returns it in stroops. It is lenient: surrounding spaces, the
+// suffix and thousands separators are optional, and thousands separators are
+// not required to separate groups of three digits. Amounts with more than 7
+// fractional digits are rejected.
+func ParseFormatted(v string, opts FormatOptions) (int64, error) {
+	s := strings.TrimSpace(v)
+	if opts.Suffix != "" {
+		s = strings.TrimSpace(strings.TrimSuffix(s, opts.Suffix))
+	}
+	integer, fraction := s, ""
+	i := strings.Index(s, opts.decimalSeparator())
+	if i >= 0 {
+		integer, fraction = s[:i], s[i+len(opts.decimalSeparator()):]
+	}
+	if opts.ThousandsSeparator != "" {
+		digits := strings.TrimPrefix(integer, "-")
+		if strings.HasPrefix(digits, opts.ThousandsSeparator) || strings.HasSuffix(digits, opts.ThousandsSeparator) ||
+			strings.Contains(digits, opts.ThousandsSeparator+opts.ThousandsSeparator) {
+			return 0, errors.Errorf("invalid amount format: %s", v)
+		}
+		integer = strings.Replace(integer, opts.ThousandsSeparator, "", -1)
+	}
+	number := integer
+	if i >= 0 {
+		number += "." + fraction
+	}
+
+	stroops, err := ParseInt64(number)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid amount: %s", v)
+	}
+	return stroops, nil
+}
@@ -0,0 +1,97 @@
+package amount
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	english := FormatOptions{DecimalPlaces: 2, ThousandsSeparator: ","}
+	german := FormatOptions{DecimalPlaces: 2, ThousandsSeparator: ".", DecimalSeparator: ","}
+
+	testCases := []struct {
+		name     string
+		v        int64
+		opts     FormatOptions
+		expected string
+	}{
+		{"zero", 0, english, "0.00"},
+		{"small", 1234, english, "0.00"},
+		{"hundreds", 5000000000, english, "500.00"},
+		{"thousands", 12345678900, english, "1,234.56"},
+		{"millions", 12345678900000, english, "1,234,567.89"},
+		{"negative", -12345678900, english, "-1,234.57"},
+		{"negative down", -12345678900, FormatOptions{DecimalPlaces: 2, ThousandsSeparator: ",", Rounding: RoundDown}, "-1,234.56"},
+		{"german", 12345678900000, german, "1.234.567,89"},
+		{"no separator", 12345678900, FormatOptions{DecimalPlaces: 2}, "1234.56"},
+		{"no decimals", 12345678900, FormatOptions{ThousandsSeparator: ","}, "1,234"},
+		{"all decimals", 12345678901, FormatOptions{DecimalPlaces: 7}, "1234.5678901"},
+		{"suffix", 5000000000, FormatOptions{DecimalPlaces: 2, Suffix: "USDC"}, "500.00 USDC"},
+		{"ceil", 12345678900, FormatOptions{DecimalPlaces: 1, Rounding: RoundCeil}, "1234.6"},
+		{"half even", 12345000000, FormatOptions{DecimalPlaces: 0, Rounding: RoundHalfEven}, "1234"},
+		{"half even up", 12355000000, FormatOptions{DecimalPlaces: 0, Rounding: RoundHalfEven}, "1236"},
+		{"floor negative", -1, FormatOptions{DecimalPlaces: 2}, "-0.01"},
+		{"max", math.MaxInt64, english, "922,337,203,685.47"},
+		{"max ceil", math.MaxInt64, FormatOptions{DecimalPlaces: 2, ThousandsSeparator: ",", Rounding: RoundCeil}, "922,337,203,685.48"},
+		{"min", math.MinInt64, FormatOptions{DecimalPlaces: 7}, "-922337203685.4775808"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := Format(tc.v, tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, s)
+		})
+	}
+
+	_, err := Format(1, FormatOptions{DecimalPlaces: 8})
+	assert.EqualError(t, err, "invalid number of decimal places: 8")
+	_, err = Format(1, FormatOptions{ThousandsSeparator: "."})
+	assert.EqualError(t, err, "thousands and decimal separators cannot be the same")
+}
+
+func TestParseFormatted(t *testing.T) {
+	german := FormatOptions{ThousandsSeparator: ".", DecimalSeparator: ",", Suffix: "EUR"}
+
+	testCases := []struct {
+		v        string
+		opts     FormatOptions
+		expected int64
+	}{
+		{"1,234.56", FormatOptions{ThousandsSeparator: ","}, 12345600000},
+		{" 1,234,567 ", FormatOptions{ThousandsSeparator: ","}, 12345670000000},
+		{"-1,234.5", FormatOptions{ThousandsSeparator: ","}, -12345000000},
+		{"12,34.5", FormatOptions{ThousandsSeparator: ","}, 12345000000},
+		{"1234.5678901", FormatOptions{}, 12345678901},
+		{"1.234,56 EUR", german, 12345600000},
+		{"1234,56", german, 12345600000},
+		{"500 USDC", FormatOptions{Suffix: "USDC"}, 5000000000},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.v, func(t *testing.T) {
+			v, err := ParseFormatted(tc.v, tc.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+
+	for _, v := range []string{"", ",123", "1,,234", "1,.5", "1.2.3", "1234.56789012", "1,234 USD", "abc"} {
+		_, err := ParseLenient(v)
+		assert.Error(t, err, v)
+	}
+	_, err := ParseFormatted("1.234,56,7", german)
+	assert.Error(t, err)
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	opts := FormatOptions{DecimalPlaces: 7, ThousandsSeparator: " ", DecimalSeparator: ",", Suffix: "XLM"}
+	for _, v := range []int64{0, 1, -1, 10000000, 12345678901, math.MaxInt64, math.MinInt64} {
+		s, err := Format(v, opts)
+		require.NoError(t, err)
+		parsed, err := ParseFormatted(s, opts)
+		require.NoError(t, err, s)
+		assert.Equal(t, v, parsed, s)
+	}
+}
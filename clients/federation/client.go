@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/stellar/go/address"
+	"github.com/stellar/go/clients/stellartoml"
 	proto "github.com/stellar/go/protocols/federation"
 	"github.com/stellar/go/support/errors"
 )
@@ -104,13 +106,13 @@ func (c *Client) ForwardRequest(domain string, fields url.Values) (*proto.NameRe
 }
 
 func (c *Client) getFederationServer(domain string) (string, error) {
-	stoml, err := c.StellarTOML.GetStellarToml(domain)
+	stoml, err := c.stellarTOML().GetStellarToml(domain)
 	if err != nil {
-		return "", errors.Wrap(err, "get stellar.toml failed")
+		return c.getFederationServerFromDNS(domain, errors.Wrap(err, "get stellar.toml failed"))
 	}
 
 	if stoml.FederationServer == "" {
-		return "", errors.New("stellar.toml is missing federation server info")
+		return c.getFederationServerFromDNS(domain, errors.New("stellar.toml is missing federation server info"))
 	}
 
 	if !c.AllowHTTP && !strings.HasPrefix(stoml.FederationServer, "https://") {
@@ -120,10 +122,63 @@ func (c *Client) getFederationServer(domain string) (string, error) {
 	return stoml.FederationServer, nil
 }
 
+// getFederationServerFromDNS returns the federation server named by the SRV
+// records of domain if the client allows it. tomlErr, the reason the
+// federation server is not known from stellar.toml, is returned if it does not
+// or the domain has no records.
+func (c *Client) getFederationServerFromDNS(domain string, tomlErr error) (string, error) {
+	if !c.AllowSRVFallback || c.DNS == nil {
+		return "", tomlErr
+	}
+
+	_, addrs, err := c.DNS.LookupSRV(FederationSRVService, "tcp", domain)
+	if err != nil {
+		return "", errors.Wrap(err, "lookup federation SRV record failed")
+	}
+	if len(addrs) == 0 {
+		return "", tomlErr
+	}
+
+	fserv := federationServerFromSRV(addrs[0])
+	if fserv == "" {
+		return "", tomlErr
+	}
+	return fserv, nil
+}
+
+// stellarTOML returns the client fetching stellar.toml files.
+func (c *Client) stellarTOML() StellarTOML {
+	if c.StellarTOML != nil {
+		return c.StellarTOML
+	}
+	return &stellartoml.Client{HTTP: c.dnsHTTPClient(), UseHTTP: c.AllowHTTP}
+}
+
+// http returns the client sending requests to federation servers.
+func (c *Client) http() HTTP {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return c.dnsHTTPClient()
+}
+
+// dnsHTTPClient returns the http client resolving hosts with DNS, or
+// http.DefaultClient if it is not set.
+func (c *Client) dnsHTTPClient() *http.Client {
+	c.dnsHTTPOnce.Do(func() {
+		if c.DNS == nil {
+			c.dnsHTTP = http.DefaultClient
+		} else {
+			c.dnsHTTP = newDNSHTTP(c.DNS)
+		}
+	})
+	return c.dnsHTTP
+}
+
 // getJSON populates `dest` with the contents at `url`, provided the request
 // succeeds and the json can be successfully decoded.
 func (c *Client) getJSON(url string, dest interface{}) error {
-	hresp, err := c.HTTP.Get(url)
+	hresp, err := c.http().Get(url)
 	if err != nil {
 		return errors.Wrap(err, "http get errored")
 	}
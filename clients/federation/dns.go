@@ -0,0 +1,363 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+// FederationSRVService is the service of the SRV records naming the federation
+// server of a domain, i.e. `_stellar-federation._tcp.<domain>`. The federation
+// server of a record with target fed.example.com and port 8443 is
+// https://fed.example.com:8443/federation.
+const FederationSRVService = "stellar-federation"
+
+// DefaultDoHEndpoint is the DNS-over-HTTPS endpoint used by DoHResolver if
+// none is set.
+const DefaultDoHEndpoint = "https://cloudflare-dns.com/dns-query"
+
+// DNSResponseMaxSize is the maximum size of a response from a DNS-over-HTTPS
+// server.
+const DNSResponseMaxSize = 64 * 1024
+
+// the types of the resource records looked up by DoHResolver
+const (
+	dnsTypeA    = 1
+	dnsTypeAAAA = 28
+	dnsTypeSRV  = 33
+)
+
+// dnsTypeNames are the names of the types of resource records sent in the
+// queries.
+var dnsTypeNames = map[int]string{
+	dnsTypeA:    "A",
+	dnsTypeAAAA: "AAAA",
+	dnsTypeSRV:  "SRV",
+}
+
+// rcodeNameError is the DNS response code of queries for names which do not
+// exist (NXDOMAIN).
+const rcodeNameError = 3
+
+// DNS represents a DNS resolver that a federation client uses to resolve the
+// hosts of the stellar.toml files and federation servers it connects to, and
+// optionally to find the federation server of a domain from its SRV records.
+// Its LookupHost and LookupSRV methods behave like net.LookupHost and
+// net.LookupSRV, which can be wrapped to use the system resolver.
+type DNS interface {
+	LookupHost(host string) (addrs []string, err error)
+	LookupSRV(service, proto, name string) (cname string, addrs []*net.SRV, err error)
+}
+
+// DNSSECValidator validates the DNSSEC status of the responses of a
+// DNS-over-HTTPS server, returning an error if the response must not be
+// trusted.
+type DNSSECValidator interface {
+	ValidateDNSSEC(name string, response *DNSResponse) error
+}
+
+// RequireAuthenticatedData is a DNSSECValidator which trusts the DNSSEC
+// validation of the DNS-over-HTTPS server, rejecting responses it could not
+// authenticate (with the AD flag unset), including responses for unsigned
+// zones.
+type RequireAuthenticatedData struct{}
+
+// ValidateDNSSEC returns an error if the AD flag of response is unset.
+func (RequireAuthenticatedData) ValidateDNSSEC(name string, response *DNSResponse) error {
+	if !response.AD {
+		return errors.Errorf("dns response for %s is not authenticated with DNSSEC", name)
+	}
+	return nil
+}
+
+// DNSResponse is the response of a DNS-over-HTTPS server in the JSON format
+// supported by Cloudflare and Google Public DNS.
+type DNSResponse struct {
+	Status int         `json:"Status"`
+	TC     bool        `json:"TC"`
+	AD     bool        `json:"AD"`
+	CD     bool        `json:"CD"`
+	Answer []DNSAnswer `json:"Answer"`
+}
+
+// DNSAnswer is a resource record of a DNSResponse.
+type DNSAnswer struct {
+	Name string `json:"name"`
+	Type int    `json:"type"`
+	TTL  uint32 `json:"TTL"`
+	Data string `json:"data"`
+}
+
+// DoHResolver is a DNS resolver which sends queries to a DNS-over-HTTPS
+// server, for wallets in networks where plain DNS queries are blocked or
+// tampered with. Responses are cached for the lowest TTL of their records.
+type DoHResolver struct {
+	// Endpoint is the URL of a DNS-over-HTTPS server supporting the JSON
+	// format. DefaultDoHEndpoint is used if it is empty.
+	Endpoint string
+
+	// HTTP is the http client used to send the queries. http.DefaultClient is
+	// used if it is nil.
+	HTTP HTTP
+
+	// DNSSEC validates every response before it is used or cached.
+	// RequireAuthenticatedData is used if it is nil.
+	DNSSEC DNSSECValidator
+
+	// InsecureSkipDNSSEC disables the validation of the DNSSEC status of the
+	// responses, e.g. to resolve domains whose zones are not signed. The
+	// responses can then be forged by the DNS-over-HTTPS server.
+	InsecureSkipDNSSEC bool
+
+	clock     *clock.Clock
+	cacheLock sync.Mutex
+	cache     map[string]dohCacheEntry
+}
+
+type dohCacheEntry struct {
+	answers []DNSAnswer
+	expires time.Time
+}
+
+// LookupHost looks up the IPv4 and IPv6 addresses of host. It returns an
+// error if host has none.
+func (r *DoHResolver) LookupHost(host string) ([]string, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{host}, nil
+	}
+	name := strings.TrimSuffix(host, ".")
+
+	var addrs []string
+	for _, rrType := range []int{dnsTypeA, dnsTypeAAAA} {
+		answers, err := r.lookup(name, rrType)
+		if err != nil {
+			return nil, err
+		}
+		for _, answer := range answers {
+			if net.ParseIP(answer.Data) == nil {
+				return nil, errors.Errorf("invalid address record for %s: %q", name, answer.Data)
+			}
+			addrs = append(addrs, answer.Data)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, errors.Errorf("no address found for %s", name)
+	}
+	return addrs, nil
+}
+
+// LookupSRV looks up the SRV records of `_service._proto.name`, sorted by
+// priority and by descending weight. Names which do not exist have no records
+// and no error.
+func (r *DoHResolver) LookupSRV(service, proto, name string) (string, []*net.SRV, error) {
+	cname := fmt.Sprintf("_%s._%s.%s", service, proto, strings.TrimSuffix(name, "."))
+
+	answers, err := r.lookup(cname, dnsTypeSRV)
+	if err != nil {
+		return "", nil, err
+	}
+
+	addrs := []*net.SRV{}
+	for _, answer := range answers {
+		srv, err := parseSRV(answer.Data)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "invalid SRV record for %s", cname)
+		}
+		addrs = append(addrs, srv)
+	}
+	sort.SliceStable(addrs, func(i, j int) bool {
+		if addrs[i].Priority != addrs[j].Priority {
+			return addrs[i].Priority < addrs[j].Priority
+		}
+		return addrs[i].Weight > addrs[j].Weight
+	})
+
+	return cname + ".", addrs, nil
+}
+
+// lookup returns the records of type rrType of name, from the cache if they
+// did not expire. Names which do not exist have no records and no error.
+func (r *DoHResolver) lookup(name string, rrType int) ([]DNSAnswer, error) {
+	key := strconv.Itoa(rrType) + " " + name
+
+	r.cacheLock.Lock()
+	entry, ok := r.cache[key]
+	r.cacheLock.Unlock()
+	if ok && r.clock.Now().Before(entry.expires) {
+		return entry.answers, nil
+	}
+
+	response, err := r.query(name, rrType)
+	if err != nil {
+		return nil, err
+	}
+	if response.Status == rcodeNameError {
+		return nil, nil
+	}
+	if response.Status != 0 {
+		return nil, errors.Errorf("dns query for %s failed with rcode %d", name, response.Status)
+	}
+
+	answers := []DNSAnswer{}
+	var ttl uint32
+	for _, answer := range response.Answer {
+		if answer.Type != rrType {
+			continue
+		}
+		answers = append(answers, answer)
+		if len(answers) == 1 || answer.TTL < ttl {
+			ttl = answer.TTL
+		}
+	}
+
+	if len(answers) > 0 && ttl > 0 {
+		r.cacheLock.Lock()
+		if r.cache == nil {
+			r.cache = map[string]dohCacheEntry{}
+		}
+		r.cache[key] = dohCacheEntry{
+			answers: answers,
+			expires: r.clock.Now().Add(time.Duration(ttl) * time.Second),
+		}
+		r.cacheLock.Unlock()
+	}
+	return answers, nil
+}
+
+// query sends a query for the records of type rrType of name and validates the
+// DNSSEC status of the response.
+func (r *DoHResolver) query(name string, rrType int) (*DNSResponse, error) {
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultDoHEndpoint
+	}
+	var client HTTP = http.DefaultClient
+	if r.HTTP != nil {
+		client = r.HTTP
+	}
+
+	qstr := url.Values{}
+	qstr.Add("name", name)
+	qstr.Add("type", dnsTypeNames[rrType])
+	// the content type is requested in the query as HTTP only sends GET
+	// requests without headers
+	qstr.Add("ct", "application/dns-json")
+
+	hresp, err := client.Get(fmt.Sprintf("%s?%s", endpoint, qstr.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "http get errored")
+	}
+	defer hresp.Body.Close()
+
+	if !(hresp.StatusCode >= 200 && hresp.StatusCode < 300) {
+		return nil, errors.Errorf("http get failed with (%d) status code", hresp.StatusCode)
+	}
+
+	var response DNSResponse
+	err = json.NewDecoder(io.LimitReader(hresp.Body, DNSResponseMaxSize)).Decode(&response)
+	if err != nil {
+		return nil, errors.Wrap(err, "json decode errored")
+	}
+	if response.TC {
+		return nil, errors.Errorf("dns response for %s is truncated", name)
+	}
+
+	if !r.InsecureSkipDNSSEC {
+		var validator DNSSECValidator = RequireAuthenticatedData{}
+		if r.DNSSEC != nil {
+			validator = r.DNSSEC
+		}
+		if err = validator.ValidateDNSSEC(name, &response); err != nil {
+			return nil, err
+		}
+	}
+	return &response, nil
+}
+
+// newDNSHTTP returns an http client connecting to the addresses dns resolves
+// the hosts of the requests to, instead of the addresses the system resolver
+// resolves them to.
+func newDNSHTTP(dns DNS) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dnsDialContext(dns, &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	})
+	return &http.Client{Transport: transport}
+}
+
+// dnsDialContext returns a dial function resolving the host of the address
+// with dns, and connecting to the first of its addresses which accepts the
+// connection.
+func dnsDialContext(dns DNS, dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := dns.LookupHost(host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "lookup %s failed", host)
+		}
+
+		for _, ip := range addrs {
+			var conn net.Conn
+			conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// parseSRV parses the presentation format of SRV records, e.g.
+// "10 5 443 fed.example.com.".
+func parseSRV(data string) (*net.SRV, error) {
+	fields := strings.Fields(data)
+	if len(fields) != 4 {
+		return nil, errors.Errorf("expected 4 fields, got %q", data)
+	}
+
+	var values [3]uint16
+	for i := range values {
+		v, err := strconv.ParseUint(fields[i], 10, 16)
+		if err != nil {
+			return nil, errors.Errorf("invalid field %q", fields[i])
+		}
+		values[i] = uint16(v)
+	}
+
+	return &net.SRV{
+		Priority: values[0],
+		Weight:   values[1],
+		Port:     values[2],
+		Target:   fields[3],
+	}, nil
+}
+
+// federationServerFromSRV returns the URL of the federation server named by
+// an SRV record, or the empty string if the record is a target of "." which
+// denotes that the domain has no federation server.
+func federationServerFromSRV(srv *net.SRV) string {
+	host := strings.TrimSuffix(srv.Target, ".")
+	if host == "" {
+		return ""
+	}
+	if srv.Port != 443 {
+		host = net.JoinHostPort(host, strconv.Itoa(int(srv.Port)))
+	}
+	return "https://" + host + "/federation"
+}
@@ -0,0 +1,298 @@
+package federation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/clock/clocktest"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const srvQueryURL = "https://dns.example/dns-query?ct=application%2Fdns-json&name=_stellar-federation._tcp.stellar.org&type=SRV"
+
+func countingDNSResponder(count *int, body string) httpmock.Responder {
+	return func(*http.Request) (*http.Response, error) {
+		*count++
+		return httpmock.NewStringResponse(http.StatusOK, body), nil
+	}
+}
+
+func TestDoHResolverLookupSRV(t *testing.T) {
+	hmock := httptest.NewClient()
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	r := &DoHResolver{
+		Endpoint: "https://dns.example/dns-query",
+		HTTP:     hmock,
+		clock:    &clock.Clock{Source: clocktest.FixedSource(now)},
+	}
+
+	requests := 0
+	hmock.On("GET", srvQueryURL).Return(countingDNSResponder(&requests, `{
+		"Status": 0,
+		"AD": true,
+		"Answer": [
+			{"name": "_stellar-federation._tcp.stellar.org", "type": 5, "TTL": 10, "data": "ignored.stellar.org."},
+			{"name": "_stellar-federation._tcp.stellar.org", "type": 33, "TTL": 300, "data": "20 0 443 backup.stellar.org."},
+			{"name": "_stellar-federation._tcp.stellar.org", "type": 33, "TTL": 60, "data": "10 5 8443 fed.stellar.org."},
+			{"name": "_stellar-federation._tcp.stellar.org", "type": 33, "TTL": 300, "data": "10 10 443 fed2.stellar.org."}
+		]
+	}`))
+
+	cname, addrs, err := r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, "_stellar-federation._tcp.stellar.org.", cname)
+	assert.Equal(t, []*net.SRV{
+		{Target: "fed2.stellar.org.", Port: 443, Priority: 10, Weight: 10},
+		{Target: "fed.stellar.org.", Port: 8443, Priority: 10, Weight: 5},
+		{Target: "backup.stellar.org.", Port: 443, Priority: 20, Weight: 0},
+	}, addrs)
+	assert.Equal(t, 1, requests)
+
+	// the response is cached for the lowest TTL of the SRV records
+	r.clock.Source = clocktest.FixedSource(now.Add(59 * time.Second))
+	_, cached, err := r.LookupSRV(FederationSRVService, "tcp", "stellar.org.")
+	require.NoError(t, err)
+	assert.Equal(t, addrs, cached)
+	assert.Equal(t, 1, requests)
+
+	r.clock.Source = clocktest.FixedSource(now.Add(60 * time.Second))
+	_, _, err = r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestDoHResolverLookupSRVErrors(t *testing.T) {
+	hmock := httptest.NewClient()
+	r := &DoHResolver{Endpoint: "https://dns.example/dns-query", HTTP: hmock, InsecureSkipDNSSEC: true}
+
+	// names which do not exist have no records
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 3}`)
+	_, addrs, err := r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	assert.NoError(t, err)
+	assert.Empty(t, addrs)
+
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 2}`)
+	_, _, err = r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	assert.EqualError(t, err, "dns query for _stellar-federation._tcp.stellar.org failed with rcode 2")
+
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 0, "Answer": [{"type": 33, "TTL": 0, "data": "10 5 fed.stellar.org."}]}`)
+	_, _, err = r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	assert.EqualError(t, err, `invalid SRV record for _stellar-federation._tcp.stellar.org: expected 4 fields, got "10 5 fed.stellar.org."`)
+
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 0, "TC": true}`)
+	_, _, err = r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	assert.EqualError(t, err, "dns response for _stellar-federation._tcp.stellar.org is truncated")
+
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusServiceUnavailable, "")
+	_, _, err = r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	assert.EqualError(t, err, "http get failed with (503) status code")
+}
+
+func TestDoHResolverDNSSEC(t *testing.T) {
+	hmock := httptest.NewClient()
+	// responses are authenticated with DNSSEC by default
+	r := &DoHResolver{
+		Endpoint: "https://dns.example/dns-query",
+		HTTP:     hmock,
+	}
+	answer := `"Answer": [{"type": 33, "TTL": 0, "data": "10 5 443 fed.stellar.org."}]`
+
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 0, "AD": false, `+answer+`}`)
+	_, _, err := r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	assert.EqualError(t, err, "dns response for _stellar-federation._tcp.stellar.org is not authenticated with DNSSEC")
+
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 3, "AD": false}`)
+	_, _, err = r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	assert.EqualError(t, err, "dns response for _stellar-federation._tcp.stellar.org is not authenticated with DNSSEC")
+
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 0, "AD": true, `+answer+`}`)
+	_, addrs, err := r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	require.NoError(t, err)
+	assert.Len(t, addrs, 1)
+
+	r.InsecureSkipDNSSEC = true
+	hmock.On("GET", srvQueryURL).ReturnString(http.StatusOK, `{"Status": 0, "AD": false, `+answer+`}`)
+	_, addrs, err = r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	require.NoError(t, err)
+	assert.Len(t, addrs, 1)
+}
+
+func TestDoHResolverLookupHost(t *testing.T) {
+	hmock := httptest.NewClient()
+	r := &DoHResolver{Endpoint: "https://dns.example/dns-query", HTTP: hmock}
+
+	hmock.On("GET", "https://dns.example/dns-query?ct=application%2Fdns-json&name=stellar.org&type=A").
+		ReturnString(http.StatusOK, `{"Status": 0, "AD": true, "Answer": [
+			{"name": "stellar.org", "type": 5, "TTL": 60, "data": "www.stellar.org."},
+			{"name": "www.stellar.org", "type": 1, "TTL": 60, "data": "192.0.2.1"}
+		]}`)
+	hmock.On("GET", "https://dns.example/dns-query?ct=application%2Fdns-json&name=stellar.org&type=AAAA").
+		ReturnString(http.StatusOK, `{"Status": 0, "AD": true, "Answer": [
+			{"name": "stellar.org", "type": 28, "TTL": 60, "data": "2001:db8::1"}
+		]}`)
+	addrs, err := r.LookupHost("stellar.org.")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1", "2001:db8::1"}, addrs)
+
+	// addresses are not looked up
+	addrs, err = r.LookupHost("192.0.2.2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.2"}, addrs)
+
+	hmock.On("GET", "https://dns.example/dns-query?ct=application%2Fdns-json&name=missing.org&type=A").
+		ReturnString(http.StatusOK, `{"Status": 3, "AD": true}`)
+	hmock.On("GET", "https://dns.example/dns-query?ct=application%2Fdns-json&name=missing.org&type=AAAA").
+		ReturnString(http.StatusOK, `{"Status": 3, "AD": true}`)
+	_, err = r.LookupHost("missing.org")
+	assert.EqualError(t, err, "no address found for missing.org")
+}
+
+func TestDoHResolverDefaultHTTP(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://cloudflare-dns.com/dns-query",
+		httpmock.NewStringResponder(http.StatusOK, `{"Status": 0, "AD": true, "Answer": [
+			{"type": 33, "TTL": 60, "data": "10 5 443 fed.stellar.org."}
+		]}`))
+	r := &DoHResolver{}
+	_, addrs, err := r.LookupSRV(FederationSRVService, "tcp", "stellar.org")
+	require.NoError(t, err)
+	assert.Len(t, addrs, 1)
+}
+
+func TestDNSDialContext(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+
+	dns := mockDNS{}
+	dial := dnsDialContext(dns, &net.Dialer{})
+
+	// the first address accepting the connection is used
+	conn, err := dial(context.Background(), "tcp", "stellar.org:"+port)
+	require.NoError(t, err)
+	conn.Close()
+
+	_, err = dial(context.Background(), "tcp", "broken.org:"+port)
+	assert.EqualError(t, err, "lookup broken.org failed: dns failed")
+}
+
+func TestClientDefaultsResolveWithDNS(t *testing.T) {
+	dns := mockDNS{}
+	c := &Client{DNS: dns, AllowHTTP: true}
+	toml, ok := c.stellarTOML().(*stellartoml.Client)
+	require.True(t, ok)
+	assert.True(t, toml.UseHTTP)
+	assert.Same(t, c.dnsHTTPClient(), toml.HTTP)
+	assert.Same(t, c.dnsHTTPClient(), c.http())
+	assert.NotSame(t, http.DefaultClient, c.http())
+
+	// the system resolver is used without DNS
+	c = &Client{}
+	assert.Same(t, http.DefaultClient, c.http())
+}
+
+type mockDNS map[string][]*net.SRV
+
+// LookupHost resolves every host but broken.org to a closed port of the
+// loopback interface and then to the loopback interface.
+func (m mockDNS) LookupHost(host string) ([]string, error) {
+	if host == "broken.org" {
+		return nil, errors.New("dns failed")
+	}
+	return []string{"127.0.0.2", "127.0.0.1"}, nil
+}
+
+func (m mockDNS) LookupSRV(service, proto, name string) (string, []*net.SRV, error) {
+	if name == "broken.org" {
+		return "", nil, errors.New("dns failed")
+	}
+	return "_" + service + "._" + proto + "." + name + ".", m[name], nil
+}
+
+func TestLookupByAddressWithDNS(t *testing.T) {
+	hmock := httptest.NewClient()
+	tomlmock := &stellartoml.MockClient{}
+	c := &Client{
+		StellarTOML:      tomlmock,
+		HTTP:             hmock,
+		AllowSRVFallback: true,
+		DNS: mockDNS{
+			"stellar.org":   {{Target: "fed.stellar.org.", Port: 8443}},
+			"disabled.org":  {{Target: ".", Port: 0}},
+			"preferred.org": {{Target: "fed.preferred.org.", Port: 443}},
+		},
+	}
+
+	// the SRV record is used if stellar.toml can't be fetched
+	tomlmock.On("GetStellarToml", "stellar.org").Return(
+		(*stellartoml.Response)(nil),
+		errors.New("toml failed"),
+	).Once()
+	federationResponse := map[string]string{
+		"stellar_address": "scott*stellar.org",
+		"account_id":      "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C",
+	}
+	hmock.On("GET", "https://fed.stellar.org:8443/federation").
+		ReturnJSON(http.StatusOK, federationResponse)
+	resp, err := c.LookupByAddress("scott*stellar.org")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C", resp.AccountID)
+	}
+
+	// or if it does not name a federation server
+	tomlmock.On("GetStellarToml", "stellar.org").Return(&stellartoml.Response{}, nil).Once()
+	hmock.On("GET", "https://fed.stellar.org:8443/federation").
+		ReturnJSON(http.StatusOK, federationResponse)
+	_, err = c.LookupByAddress("scott*stellar.org")
+	assert.NoError(t, err)
+
+	// stellar.toml takes precedence over the SRV record
+	tomlmock.On("GetStellarToml", "preferred.org").Return(&stellartoml.Response{
+		FederationServer: "https://preferred.org/federation",
+	}, nil)
+	hmock.On("GET", "https://preferred.org/federation").
+		ReturnJSON(http.StatusOK, map[string]string{
+			"stellar_address": "scott*preferred.org",
+			"account_id":      "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C",
+		})
+	_, err = c.LookupByAddress("scott*preferred.org")
+	assert.NoError(t, err)
+
+	// the error of stellar.toml is returned if there is no record
+	tomlmock.On("GetStellarToml", "norecord.org").Return(&stellartoml.Response{}, nil)
+	_, err = c.LookupByAddress("scott*norecord.org")
+	assert.EqualError(t, err, "lookup federation server failed: stellar.toml is missing federation server info")
+
+	tomlmock.On("GetStellarToml", "disabled.org").Return(&stellartoml.Response{}, nil)
+	_, err = c.LookupByAddress("scott*disabled.org")
+	assert.EqualError(t, err, "lookup federation server failed: stellar.toml is missing federation server info")
+
+	tomlmock.On("GetStellarToml", "broken.org").Return(&stellartoml.Response{}, nil)
+	_, err = c.LookupByAddress("scott*broken.org")
+	assert.EqualError(t, err, "lookup federation server failed: lookup federation SRV record failed: dns failed")
+
+	// the SRV records are not used unless allowed
+	c.AllowSRVFallback = false
+	tomlmock.On("GetStellarToml", "stellar.org").Return(&stellartoml.Response{}, nil).Once()
+	_, err = c.LookupByAddress("scott*stellar.org")
+	assert.EqualError(t, err, "lookup federation server failed: stellar.toml is missing federation server info")
+}
+
+func TestFederationServerFromSRV(t *testing.T) {
+	assert.Equal(t, "https://fed.stellar.org/federation", federationServerFromSRV(&net.SRV{Target: "fed.stellar.org.", Port: 443}))
+	assert.Equal(t, "https://fed.stellar.org:8443/federation", federationServerFromSRV(&net.SRV{Target: "fed.stellar.org", Port: 8443}))
+	assert.Equal(t, "", federationServerFromSRV(&net.SRV{Target: "."}))
+}
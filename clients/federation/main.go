@@ -3,6 +3,7 @@ package federation
 import (
 	"net/http"
 	"net/url"
+	"sync"
 
 	hc "github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/clients/stellartoml"
@@ -29,10 +30,26 @@ var DefaultPublicNetClient = &Client{
 // Client represents a client that is capable of resolving a federation request
 // using the internet.
 type Client struct {
+	// StellarTOML and HTTP, if nil, default to clients resolving the hosts
+	// they connect to with DNS if it is set, or with the system resolver.
 	StellarTOML StellarTOML
 	HTTP        HTTP
 	Horizon     Horizon
 	AllowHTTP   bool
+
+	// DNS, if set, resolves the hosts of the stellar.toml files and federation
+	// servers fetched with the default StellarTOML and HTTP. Use a DoHResolver
+	// in networks where plain DNS queries are blocked.
+	DNS DNS
+
+	// AllowSRVFallback enables finding the federation server of domains whose
+	// stellar.toml file can't be fetched or has no FEDERATION_SERVER from their
+	// SRV records (see FederationSRVService), looked up with DNS. The records
+	// are not part of SEP-2, and are only as trustworthy as the resolver.
+	AllowSRVFallback bool
+
+	dnsHTTPOnce sync.Once
+	dnsHTTP     *http.Client
 }
 
 type ClientInterface interface {
@@ -64,3 +81,4 @@ type StellarTOML interface {
 var _ StellarTOML = stellartoml.DefaultClient
 var _ HTTP = http.DefaultClient
 var _ ClientInterface = &Client{}
+var _ DNS = &DoHResolver{}
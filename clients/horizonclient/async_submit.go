@@ -0,0 +1,211 @@
+package horizonclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// AsyncSubmissionError is returned by SubmitTransactionXDRAsyncAndWait when
+// stellar-core rejects the transaction with the ERROR status.
+type AsyncSubmissionError struct {
+	Response hProtocol.AsyncTransactionSubmissionResponse
+}
+
+func (e *AsyncSubmissionError) Error() string {
+	msg := "transaction " + e.Response.Hash + " rejected by stellar-core"
+	if result, err := e.Response.ErrorResult(); err == nil {
+		msg += ": " + result.Result.Code.String()
+	}
+	return msg
+}
+
+// PollPolicy configures how SubmitTransactionXDRAsyncAndWait waits for a
+// transaction. Waits grow exponentially, with jitter, from InitialBackoff to
+// MaxBackoff.
+type PollPolicy struct {
+	// MaxAttempts is the maximum number of times the transaction is
+	// submitted again while stellar-core responds TRY_AGAIN_LATER, and
+	// separately the maximum number of times it is looked up while it is
+	// pending. Zero or negative means no limit, the context passed to
+	// SubmitTransactionXDRAsyncAndWait being the only limit.
+	MaxAttempts int
+
+	// InitialBackoff is the time waited before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum time waited between retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultPollPolicy waits from one second up to ten seconds between
+// attempts, with no limit on the number of attempts.
+var DefaultPollPolicy = PollPolicy{
+	InitialBackoff: time.Second,
+	MaxBackoff:     10 * time.Second,
+}
+
+func (p PollPolicy) backoff(attempts int) time.Duration {
+	retry := StreamRetryPolicy{InitialBackoff: p.InitialBackoff, MaxBackoff: p.MaxBackoff}
+	return retry.backoff(attempts)
+}
+
+// SubmitTransactionXDRAsync submits a transaction represented as a base64 XDR
+// string to the /transactions_async endpoint, which returns as soon as
+// stellar-core has accepted or rejected the transaction instead of waiting for
+// it to be included in a ledger. The status of the submission is in the
+// TxStatus of the response; err is only set when the request fails or Horizon
+// responds with an error other than a submission status, in which case it can
+// be a horizon.Error object.
+func (c *Client) SubmitTransactionXDRAsync(transactionXdr string) (hProtocol.AsyncTransactionSubmissionResponse, error) {
+	request := submitRequest{endpoint: "transactions_async", transactionXdr: transactionXdr}
+	endpoint, err := request.BuildURL()
+	if err != nil {
+		return hProtocol.AsyncTransactionSubmissionResponse{}, err
+	}
+
+	if horizonURL := c.fixHorizonURL(); horizonURL != c.HorizonURL {
+		c.HorizonURL = horizonURL
+	}
+	resp, cancel, err := c.doRequestURL(c.HorizonURL+endpoint, "post")
+	if err != nil {
+		return hProtocol.AsyncTransactionSubmissionResponse{}, err
+	}
+	defer cancel()
+	return decodeAsyncSubmitResponse(resp, c)
+}
+
+// SubmitTransactionAsync submits a transaction to the /transactions_async
+// endpoint, see SubmitTransactionXDRAsync.
+//
+// This function will always check if the destination account requires a memo in the transaction as
+// defined in SEP0029: https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0029.md
+//
+// If you want to skip this check, use SubmitTransactionAsyncWithOptions.
+func (c *Client) SubmitTransactionAsync(transaction *txnbuild.Transaction) (hProtocol.AsyncTransactionSubmissionResponse, error) {
+	return c.SubmitTransactionAsyncWithOptions(transaction, SubmitTxOpts{})
+}
+
+// SubmitTransactionAsyncWithOptions submits a transaction to the
+// /transactions_async endpoint, allowing you to pass SubmitTxOpts. See
+// SubmitTransactionXDRAsync.
+func (c *Client) SubmitTransactionAsyncWithOptions(transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.AsyncTransactionSubmissionResponse, error) {
+	// only check if memo is required if skip is false and the transaction
+	// doesn't have a memo.
+	if !opts.SkipMemoRequiredCheck && transaction.Memo() == nil {
+		if err := c.checkMemoRequired(transaction); err != nil {
+			return hProtocol.AsyncTransactionSubmissionResponse{}, err
+		}
+	}
+
+	txeBase64, err := transaction.Base64()
+	if err != nil {
+		return hProtocol.AsyncTransactionSubmissionResponse{}, errors.Wrap(err, "Unable to convert transaction object to base64 string")
+	}
+
+	return c.SubmitTransactionXDRAsync(txeBase64)
+}
+
+// SubmitTransactionXDRAsyncAndWait submits a transaction with
+// SubmitTransactionXDRAsync, submitting it again while stellar-core responds
+// TRY_AGAIN_LATER, then polls TransactionDetail until the transaction is
+// included in a ledger, waiting between attempts as configured by policy.
+// The returned transaction can be successful or failed. An
+// *AsyncSubmissionError is returned if stellar-core rejects the transaction.
+// ctx bounds the whole operation.
+func (c *Client) SubmitTransactionXDRAsyncAndWait(ctx context.Context, transactionXdr string, policy PollPolicy) (hProtocol.Transaction, error) {
+	var resp hProtocol.AsyncTransactionSubmissionResponse
+	for attempts := 1; ; attempts++ {
+		var err error
+		resp, err = c.SubmitTransactionXDRAsync(transactionXdr)
+		if err != nil {
+			return hProtocol.Transaction{}, err
+		}
+		if resp.TxStatus != hProtocol.TxStatusTryAgainLater {
+			break
+		}
+		if policy.MaxAttempts > 0 && attempts >= policy.MaxAttempts {
+			return hProtocol.Transaction{}, errors.Errorf("transaction %s not accepted by stellar-core after %d attempts", resp.Hash, attempts)
+		}
+		backoff := policy.backoff(attempts)
+		c.instrumentRetry("POST", c.HorizonURL+"transactions_async", EndpointClassSubmit, attempts, backoff,
+			errors.Errorf("transaction %s: stellar-core responded %s", resp.Hash, resp.TxStatus))
+		if err := sleepContext(ctx, backoff); err != nil {
+			return hProtocol.Transaction{}, err
+		}
+	}
+
+	switch resp.TxStatus {
+	case hProtocol.TxStatusPending, hProtocol.TxStatusDuplicate:
+	case hProtocol.TxStatusError:
+		return hProtocol.Transaction{}, &AsyncSubmissionError{Response: resp}
+	default:
+		return hProtocol.Transaction{}, errors.Errorf("unknown transaction status: %s", resp.TxStatus)
+	}
+
+	for attempts := 1; ; attempts++ {
+		if err := sleepContext(ctx, policy.backoff(attempts)); err != nil {
+			return hProtocol.Transaction{}, err
+		}
+		tx, err := c.TransactionDetail(resp.Hash)
+		if err == nil {
+			return tx, nil
+		}
+		if !IsNotFoundError(err) {
+			return hProtocol.Transaction{}, err
+		}
+		if policy.MaxAttempts > 0 && attempts >= policy.MaxAttempts {
+			return hProtocol.Transaction{}, errors.Errorf("transaction %s not found after %d attempts", resp.Hash, attempts)
+		}
+	}
+}
+
+// decodeAsyncSubmitResponse decodes the response of the /transactions_async
+// endpoint. Horizon responds with an error status code when the transaction
+// is not pending, but the body is still a submission response.
+func decodeAsyncSubmitResponse(resp *http.Response, hc *Client) (hProtocol.AsyncTransactionSubmissionResponse, error) {
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if isResponseLimitError(err) {
+		return hProtocol.AsyncTransactionSubmissionResponse{}, err
+	}
+	if err != nil {
+		return hProtocol.AsyncTransactionSubmissionResponse{}, errors.Wrap(err, "error reading response")
+	}
+
+	var submission hProtocol.AsyncTransactionSubmissionResponse
+	if err := json.Unmarshal(body, &submission); err == nil && submission.TxStatus != "" {
+		if u, err := url.Parse(hc.HorizonURL); err == nil {
+			setCurrentServerTime(u.Hostname(), resp.Header["Date"], hc)
+		}
+		return submission, nil
+	}
+
+	// not a submission response, decode it as usual
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	err = decodeResponse(resp, &submission, hc)
+	if err == nil {
+		err = errors.New("response has no transaction status")
+	}
+	return hProtocol.AsyncTransactionSubmissionResponse{}, err
+}
+
+// sleepContext waits for d, or returns the error of ctx if it is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+package horizonclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stellar/go/xdr"
+)
+
+const asyncTxHash = "bcc7a97264dca0a51a63f7ea971b5e7458e334489673078bb2a34eb0cce910ca"
+
+var testPollPolicy = PollPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func asyncSubmitResponse(status hProtocol.AsyncTransactionStatus, errorResultXDR string) string {
+	return fmt.Sprintf(`{"tx_status": %q, "hash": %q, "errorResultXdr": %q}`, status, asyncTxHash, errorResultXDR)
+}
+
+func badSeqResultXDR(t *testing.T) string {
+	result, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestSubmitTransactionXDRAsync(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	testCases := []struct {
+		code   int
+		status hProtocol.AsyncTransactionStatus
+	}{
+		{http.StatusCreated, hProtocol.TxStatusPending},
+		{http.StatusConflict, hProtocol.TxStatusDuplicate},
+		{http.StatusServiceUnavailable, hProtocol.TxStatusTryAgainLater},
+		{http.StatusBadRequest, hProtocol.TxStatusError},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.status), func(t *testing.T) {
+			hmock.
+				On("POST", "https://localhost/transactions_async?tx=AAAA").
+				ReturnString(tc.code, asyncSubmitResponse(tc.status, ""))
+
+			resp, err := client.SubmitTransactionXDRAsync("AAAA")
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.TxStatus)
+			assert.Equal(t, asyncTxHash, resp.Hash)
+		})
+	}
+
+	// errors which are not submission responses
+	hmock.
+		On("POST", "https://localhost/transactions_async?tx=AAAA").
+		ReturnString(400, transactionFailure)
+	_, err := client.SubmitTransactionXDRAsync("AAAA")
+	require.Error(t, err)
+	hErr := GetError(err)
+	require.NotNil(t, hErr)
+	assert.Equal(t, "Transaction Failed", hErr.Problem.Title)
+
+	hmock.
+		On("POST", "https://localhost/transactions_async?tx=AAAA").
+		ReturnError("http.Client error")
+	_, err = client.SubmitTransactionXDRAsync("AAAA")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "http.Client error")
+		assert.Nil(t, GetError(err))
+	}
+}
+
+func TestAsyncTransactionSubmissionResponseErrorResult(t *testing.T) {
+	resp := hProtocol.AsyncTransactionSubmissionResponse{
+		TxStatus:       hProtocol.TxStatusError,
+		Hash:           asyncTxHash,
+		ErrorResultXDR: badSeqResultXDR(t),
+	}
+	result, err := resp.ErrorResult()
+	require.NoError(t, err)
+	assert.Equal(t, xdr.TransactionResultCodeTxBadSeq, result.Result.Code)
+
+	_, err = hProtocol.AsyncTransactionSubmissionResponse{TxStatus: hProtocol.TxStatusPending}.ErrorResult()
+	assert.EqualError(t, err, "response has no error result")
+}
+
+func TestSubmitTransactionXDRAsyncAndWait(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	var submissions, lookups int32
+	hmock.
+		On("POST", "https://localhost/transactions_async?tx=AAAA").
+		Return(func(*http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&submissions, 1) < 3 {
+				return httpmock.NewStringResponse(503, asyncSubmitResponse(hProtocol.TxStatusTryAgainLater, "")), nil
+			}
+			return httpmock.NewStringResponse(201, asyncSubmitResponse(hProtocol.TxStatusPending, "")), nil
+		})
+	hmock.
+		On("GET", "https://localhost/transactions/"+asyncTxHash).
+		Return(func(*http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&lookups, 1) < 2 {
+				return httpmock.NewStringResponse(404, notFoundResponse), nil
+			}
+			return httpmock.NewStringResponse(200, txSuccess), nil
+		})
+
+	tx, err := client.SubmitTransactionXDRAsyncAndWait(context.Background(), "AAAA", testPollPolicy)
+	require.NoError(t, err)
+	assert.Equal(t, asyncTxHash, tx.Hash)
+	assert.Equal(t, int32(3), submissions)
+	assert.Equal(t, int32(2), lookups)
+
+	// the transaction is not found in time
+	atomic.StoreInt32(&submissions, 3)
+	atomic.StoreInt32(&lookups, -100)
+	policy := testPollPolicy
+	policy.MaxAttempts = 2
+	_, err = client.SubmitTransactionXDRAsyncAndWait(context.Background(), "AAAA", policy)
+	assert.EqualError(t, err, "transaction "+asyncTxHash+" not found after 2 attempts")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.SubmitTransactionXDRAsyncAndWait(ctx, "AAAA", testPollPolicy)
+	assert.Equal(t, context.Canceled, err)
+
+	// stellar-core rejects the transaction
+	hmock.
+		On("POST", "https://localhost/transactions_async?tx=AAAA").
+		ReturnString(400, asyncSubmitResponse(hProtocol.TxStatusError, badSeqResultXDR(t)))
+	_, err = client.SubmitTransactionXDRAsyncAndWait(context.Background(), "AAAA", testPollPolicy)
+	require.Error(t, err)
+	submissionErr, ok := err.(*AsyncSubmissionError)
+	require.True(t, ok)
+	assert.Equal(t, asyncTxHash, submissionErr.Response.Hash)
+	assert.EqualError(t, err, "transaction "+asyncTxHash+" rejected by stellar-core: TransactionResultCodeTxBadSeq")
+
+	// stellar-core never accepts the transaction
+	hmock.
+		On("POST", "https://localhost/transactions_async?tx=AAAA").
+		ReturnString(503, asyncSubmitResponse(hProtocol.TxStatusTryAgainLater, ""))
+	_, err = client.SubmitTransactionXDRAsyncAndWait(context.Background(), "AAAA", policy)
+	assert.EqualError(t, err, "transaction "+asyncTxHash+" not accepted by stellar-core after 2 attempts")
+}
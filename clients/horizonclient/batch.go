@@ -0,0 +1,125 @@
+package horizonclient
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchRequest is a single request executed as part of a batch. It is called
+// with the client executing the batch and returns the response of the
+// request, e.g. a hProtocol.Account for BatchAccountDetail.
+type BatchRequest func(c *Client) (interface{}, error)
+
+// BatchResult is the outcome of a BatchRequest.
+type BatchResult struct {
+	Response interface{}
+	Err      error
+}
+
+// BatchAccountDetail returns a BatchRequest calling AccountDetail.
+func BatchAccountDetail(request AccountRequest) BatchRequest {
+	return func(c *Client) (interface{}, error) {
+		return c.AccountDetail(request)
+	}
+}
+
+// BatchAccountData returns a BatchRequest calling AccountData.
+func BatchAccountData(request AccountRequest) BatchRequest {
+	return func(c *Client) (interface{}, error) {
+		return c.AccountData(request)
+	}
+}
+
+// BatchLedgerDetail returns a BatchRequest calling LedgerDetail.
+func BatchLedgerDetail(sequence uint32) BatchRequest {
+	return func(c *Client) (interface{}, error) {
+		return c.LedgerDetail(sequence)
+	}
+}
+
+// BatchOperationDetail returns a BatchRequest calling OperationDetail.
+func BatchOperationDetail(id string) BatchRequest {
+	return func(c *Client) (interface{}, error) {
+		return c.OperationDetail(id)
+	}
+}
+
+// BatchTransactionDetail returns a BatchRequest calling TransactionDetail.
+func BatchTransactionDetail(txHash string) BatchRequest {
+	return func(c *Client) (interface{}, error) {
+		return c.TransactionDetail(txHash)
+	}
+}
+
+// BatchOfferDetails returns a BatchRequest calling OfferDetails.
+func BatchOfferDetails(offerID string) BatchRequest {
+	return func(c *Client) (interface{}, error) {
+		return c.OfferDetails(offerID)
+	}
+}
+
+// BatchClaimableBalance returns a BatchRequest calling ClaimableBalance.
+func BatchClaimableBalance(id string) BatchRequest {
+	return func(c *Client) (interface{}, error) {
+		return c.ClaimableBalance(id)
+	}
+}
+
+// Batch executes the given requests with at most concurrency requests in
+// flight at a time, and returns their results in the order of the requests.
+// A failed request does not stop the others. Once ctx is done no more
+// requests are started, and the results of the requests not started hold
+// ctx.Err(). A concurrency lower than 1 is treated as 1.
+func (c *Client) Batch(ctx context.Context, concurrency int, requests []BatchRequest) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(requests) {
+		concurrency = len(requests)
+	}
+
+	// Normalize the client up front so that the requests running concurrently
+	// do not modify it.
+	if horizonURL := c.fixHorizonURL(); horizonURL != c.HorizonURL {
+		c.HorizonURL = horizonURL
+	}
+	c.setDefaultClient()
+	if c.horizonTimeout == 0 {
+		c.horizonTimeout = HorizonTimeout
+	}
+
+	results := make([]BatchResult, len(requests))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[i] = BatchResult{Err: err}
+					continue
+				}
+				response, err := requests[i](c)
+				results[i] = BatchResult{Response: response, Err: err}
+			}
+		}()
+	}
+
+	next := 0
+dispatch:
+	for ; next < len(requests) && ctx.Err() == nil; next++ {
+		select {
+		case indexes <- next:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for ; next < len(requests); next++ {
+		results[next] = BatchResult{Err: ctx.Err()}
+	}
+	return results
+}
@@ -0,0 +1,93 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchReturnsResultsInOrder(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	var inFlight, maxInFlight int32
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).Return(func(*http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return httpmock.NewStringResponse(200, accountResponse), nil
+	})
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+	).ReturnString(404, notFoundResponse)
+
+	requests := []BatchRequest{
+		BatchAccountDetail(AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}),
+		BatchAccountDetail(AccountRequest{AccountID: "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"}),
+		BatchAccountDetail(AccountRequest{}),
+	}
+	for i := 0; i < 5; i++ {
+		requests = append(requests, BatchAccountDetail(AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}))
+	}
+
+	results := client.Batch(context.Background(), 2, requests)
+	require.Len(t, results, len(requests))
+
+	assert.NoError(t, results[0].Err)
+	account, ok := results[0].Response.(hProtocol.Account)
+	require.True(t, ok)
+	assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.AccountID)
+
+	horizonError, ok := results[1].Err.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, 404, horizonError.Problem.Status)
+
+	assert.EqualError(t, results[2].Err, "no account ID provided")
+
+	for _, result := range results[3:] {
+		assert.NoError(t, result.Err)
+	}
+	assert.True(t, maxInFlight <= 2, maxInFlight)
+}
+
+func TestBatchStopsWhenContextIsDone(t *testing.T) {
+	client := &Client{HorizonURL: "https://localhost/"}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	request := func(*Client) (interface{}, error) {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return calls, nil
+	}
+
+	results := client.Batch(ctx, 1, []BatchRequest{request, request, request, request})
+	require.Len(t, results, 4)
+	assert.Equal(t, BatchResult{Response: 1}, results[0])
+	assert.Equal(t, BatchResult{Response: 2}, results[1])
+	assert.Equal(t, BatchResult{Err: context.Canceled}, results[2])
+	assert.Equal(t, BatchResult{Err: context.Canceled}, results[3])
+}
@@ -0,0 +1,245 @@
+package horizonclient
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/errors"
+)
+
+// CacheConfig configures a CachingHTTP.
+type CacheConfig struct {
+	// TTL is how long responses are cached. Responses with a shorter
+	// max-age in their Cache-Control header are cached for max-age instead.
+	TTL time.Duration
+
+	// MaxEntries is the maximum number of cached responses. The least
+	// recently used responses are evicted first.
+	MaxEntries int
+
+	// CacheImmutable caches the responses of the resources which never
+	// change once they exist, i.e. the ledgers, transactions and operations
+	// requested by id, for TTL regardless of their Cache-Control header.
+	CacheImmutable bool
+}
+
+// CachingHTTP is an HTTP decorator caching the successful responses of GET
+// requests, keyed by URL. It can be set as the HTTP of a Client to reduce the
+// load on Horizon when polling it, e.g. for fee stats or account details.
+// Stale responses with an ETag are revalidated with Horizon. Streaming
+// requests are never cached.
+//
+// The Cache-Control directives of requests and responses are honored:
+// requests with no-store bypass the cache, requests with no-cache are sent to
+// Horizon, responses with no-store are not cached and responses with no-cache
+// are revalidated with every request. Horizon sends no-store with all its
+// responses by default, they are only cached if a proxy in front of Horizon
+// replaces the header, or for immutable resources if CacheImmutable is set.
+type CachingHTTP struct {
+	HTTP   HTTP
+	config CacheConfig
+	cache  *lru.Cache
+
+	// clock is a Clock returning the current time.
+	clock *clock.Clock
+}
+
+// cacheEntry is a response stored in a CachingHTTP.
+type cacheEntry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// NewCachingHTTP returns a CachingHTTP sending the requests which are not
+// served from the cache with next.
+func NewCachingHTTP(next HTTP, config CacheConfig) (*CachingHTTP, error) {
+	if config.TTL <= 0 {
+		return nil, errors.New("cache TTL must be positive")
+	}
+	cache, err := lru.New(config.MaxEntries)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating cache")
+	}
+	return &CachingHTTP{HTTP: next, config: config, cache: cache}, nil
+}
+
+// Do sends req, or returns its cached response.
+func (c *CachingHTTP) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Accept") == "text/event-stream" {
+		return c.HTTP.Do(req)
+	}
+
+	key := req.URL.String()
+	noStore, noCache := cacheControl(req.Header)
+	if noStore {
+		c.cache.Remove(key)
+		return c.HTTP.Do(req)
+	}
+	var entry *cacheEntry
+	if value, ok := c.cache.Get(key); ok {
+		entry = value.(*cacheEntry)
+		if !noCache && c.clock.Now().Before(entry.expires) {
+			return entry.response(req), nil
+		}
+		if etag := entry.header.Get("ETag"); etag != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set("If-None-Match", etag)
+		} else {
+			entry = nil
+		}
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		// A 304 response without Cache-Control keeps the directives of the
+		// cached response.
+		header := resp.Header
+		if header.Get("Cache-Control") == "" {
+			header = entry.header
+		}
+		ttl, store := c.ttl(req, header)
+		if !store {
+			c.cache.Remove(key)
+			return entry.response(req), nil
+		}
+		c.cache.Add(key, &cacheEntry{
+			status:  entry.status,
+			header:  entry.header,
+			body:    entry.body,
+			expires: c.clock.Now().Add(ttl),
+		})
+		return entry.response(req), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	ttl, store := c.ttl(req, resp.Header)
+	if !store || ttl == 0 && resp.Header.Get("ETag") == "" {
+		c.cache.Remove(key)
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response body")
+	}
+	entry = &cacheEntry{
+		status:  resp.StatusCode,
+		header:  resp.Header.Clone(),
+		body:    body,
+		expires: c.clock.Now().Add(ttl),
+	}
+	c.cache.Add(key, entry)
+	return entry.response(req), nil
+}
+
+// Get sends a GET request to url, or returns its cached response.
+func (c *CachingHTTP) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// PostForm sends a POST request, which is never cached.
+func (c *CachingHTTP) PostForm(url string, data url.Values) (*http.Response, error) {
+	return c.HTTP.PostForm(url, data)
+}
+
+// Purge removes all the cached responses.
+func (c *CachingHTTP) Purge() {
+	c.cache.Purge()
+}
+
+// ttl returns how long the response to req with the given header can be
+// served from the cache without being revalidated, and false if it must not
+// be stored.
+func (c *CachingHTTP) ttl(req *http.Request, header http.Header) (time.Duration, bool) {
+	if c.config.CacheImmutable && immutableResource(req.URL.Path) {
+		return c.config.TTL, true
+	}
+	noStore, noCache := cacheControl(header)
+	if noStore {
+		return 0, false
+	}
+	if noCache {
+		return 0, true
+	}
+	ttl := c.config.TTL
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+		if err != nil || seconds < 0 {
+			continue
+		}
+		if maxAge := time.Duration(seconds) * time.Second; maxAge < ttl {
+			ttl = maxAge
+		}
+	}
+	return ttl, true
+}
+
+// immutableResource returns whether path is the path of a ledger, transaction
+// or operation, which never change once they exist.
+func immutableResource(path string) bool {
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(segments) < 2 || segments[len(segments)-1] == "" {
+		return false
+	}
+	switch segments[len(segments)-2] {
+	case "ledgers", "transactions", "operations":
+		return true
+	}
+	return false
+}
+
+// cacheControl returns whether the Cache-Control header has the no-store and
+// no-cache directives.
+func cacheControl(header http.Header) (noStore, noCache bool) {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-store":
+			noStore = true
+		case "no-cache":
+			noCache = true
+		}
+	}
+	return noStore, noCache
+}
+
+// response returns a new response to req built from the cache entry. The Date
+// header is removed so that the client does not take it for the current
+// server time.
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := e.header.Clone()
+	header.Del("Date")
+	return &http.Response{
+		Status:        strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,242 @@
+package horizonclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/clock/clocktest"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCachingHTTP(t *testing.T, config CacheConfig) (*CachingHTTP, *httptest.Client, *time.Time) {
+	hmock := httptest.NewClient()
+	cachingHTTP, err := NewCachingHTTP(hmock, config)
+	require.NoError(t, err)
+	now := time.Unix(1560947096, 0)
+	cachingHTTP.clock = &clock.Clock{Source: clockSourceFunc(func() time.Time { return now })}
+	return cachingHTTP, hmock, &now
+}
+
+type clockSourceFunc func() time.Time
+
+func (f clockSourceFunc) Now() time.Time {
+	return f()
+}
+
+func countingResponder(count *int, status int, body string, header http.Header) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		*count++
+		resp := httpmock.NewStringResponse(status, body)
+		for key, values := range header {
+			resp.Header[key] = values
+		}
+		return resp, nil
+	}
+}
+
+func TestCachingHTTPCachesAccountDetail(t *testing.T) {
+	cachingHTTP, hmock, now := newTestCachingHTTP(t, CacheConfig{TTL: 5 * time.Second, MaxEntries: 10})
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       cachingHTTP,
+		clock:      &clock.Clock{Source: clocktest.FixedSource(*now)},
+	}
+
+	requests := 0
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).Return(countingResponder(&requests, 200, accountResponse, nil))
+
+	request := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+	for i := 0; i < 3; i++ {
+		account, err := client.AccountDetail(request)
+		require.NoError(t, err)
+		assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.AccountID)
+	}
+	assert.Equal(t, 1, requests)
+
+	*now = now.Add(5 * time.Second)
+	_, err := client.AccountDetail(request)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCachingHTTPHonorsMaxAge(t *testing.T) {
+	cachingHTTP, hmock, now := newTestCachingHTTP(t, CacheConfig{TTL: time.Minute, MaxEntries: 10})
+
+	requests := 0
+	hmock.On("GET", "https://localhost/fee_stats").
+		Return(countingResponder(&requests, 200, feesResponse, http.Header{
+			"Cache-Control": []string{"max-age=5"},
+		}))
+
+	for i := 0; i < 2; i++ {
+		_, err := cachingHTTP.Get("https://localhost/fee_stats")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, requests)
+
+	*now = now.Add(5 * time.Second)
+	_, err := cachingHTTP.Get("https://localhost/fee_stats")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCachingHTTPRevalidatesWithETag(t *testing.T) {
+	cachingHTTP, hmock, now := newTestCachingHTTP(t, CacheConfig{TTL: time.Second, MaxEntries: 10})
+
+	requests := 0
+	hmock.On("GET", "https://localhost/fee_stats").
+		Return(func(req *http.Request) (*http.Response, error) {
+			requests++
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+			}
+			resp := httpmock.NewStringResponse(200, feesResponse)
+			resp.Header.Set("ETag", `"v1"`)
+			return resp, nil
+		})
+
+	resp, err := cachingHTTP.Get("https://localhost/fee_stats")
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	*now = now.Add(time.Second)
+	resp, err = cachingHTTP.Get("https://localhost/fee_stats")
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `"v1"`, resp.Header.Get("ETag"))
+	assert.Equal(t, 2, requests)
+
+	_, err = cachingHTTP.Get("https://localhost/fee_stats")
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestCachingHTTPDoesNotCache(t *testing.T) {
+	cachingHTTP, hmock, _ := newTestCachingHTTP(t, CacheConfig{TTL: time.Minute, MaxEntries: 10})
+
+	notFound := 0
+	hmock.On("GET", "https://localhost/ledgers/10").
+		Return(countingResponder(&notFound, 404, notFoundResponse, nil))
+	noStore := 0
+	hmock.On("GET", "https://localhost/fee_stats").
+		Return(countingResponder(&noStore, 200, feesResponse, http.Header{
+			"Cache-Control": []string{"no-cache, no-store, max-age=0"},
+		}))
+	stream := 0
+	hmock.On("GET", "https://localhost/ledgers").
+		Return(countingResponder(&stream, 200, ledgerStreamResponse, nil))
+
+	for i := 0; i < 2; i++ {
+		_, err := cachingHTTP.Get("https://localhost/ledgers/10")
+		require.NoError(t, err)
+		_, err = cachingHTTP.Get("https://localhost/fee_stats")
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("GET", "https://localhost/ledgers", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", "text/event-stream")
+		_, err = cachingHTTP.Do(req)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, notFound)
+	assert.Equal(t, 2, noStore)
+	assert.Equal(t, 2, stream)
+}
+
+func TestCachingHTTPCachesImmutableResources(t *testing.T) {
+	cachingHTTP, hmock, now := newTestCachingHTTP(t, CacheConfig{TTL: time.Minute, MaxEntries: 10, CacheImmutable: true})
+
+	// Horizon sends no-store with all its responses.
+	noStore := http.Header{"Cache-Control": []string{"no-cache, no-store, max-age=0"}}
+	paths := []string{"/ledgers/10", "/transactions/abc", "/operations/42", "/ledgers/10/transactions", "/fee_stats"}
+	requests := make([]int, len(paths))
+	for i, path := range paths {
+		hmock.On("GET", "https://localhost"+path).
+			Return(countingResponder(&requests[i], 200, "{}", noStore))
+	}
+
+	get := func() {
+		for _, path := range paths {
+			_, err := cachingHTTP.Get("https://localhost" + path)
+			require.NoError(t, err)
+		}
+	}
+	get()
+	get()
+	assert.Equal(t, []int{1, 1, 1, 2, 2}, requests)
+
+	*now = now.Add(time.Minute)
+	get()
+	assert.Equal(t, []int{2, 2, 2, 3, 3}, requests)
+}
+
+func TestCachingHTTPHonorsNoCache(t *testing.T) {
+	cachingHTTP, hmock, _ := newTestCachingHTTP(t, CacheConfig{TTL: time.Minute, MaxEntries: 10})
+
+	requests := 0
+	revalidations := 0
+	hmock.On("GET", "https://localhost/fee_stats").
+		Return(func(req *http.Request) (*http.Response, error) {
+			requests++
+			if req.Header.Get("If-None-Match") == `"v1"` {
+				revalidations++
+				return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+			}
+			resp := httpmock.NewStringResponse(200, feesResponse)
+			resp.Header.Set("ETag", `"v1"`)
+			resp.Header.Set("Cache-Control", "no-cache")
+			return resp, nil
+		})
+
+	// Responses with no-cache are revalidated with every request.
+	for i := 0; i < 3; i++ {
+		resp, err := cachingHTTP.Get("https://localhost/fee_stats")
+		require.NoError(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+	}
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 2, revalidations)
+}
+
+func TestCachingHTTPHonorsRequestCacheControl(t *testing.T) {
+	cachingHTTP, hmock, _ := newTestCachingHTTP(t, CacheConfig{TTL: time.Minute, MaxEntries: 10})
+
+	requests := 0
+	hmock.On("GET", "https://localhost/fee_stats").
+		Return(countingResponder(&requests, 200, feesResponse, nil))
+
+	get := func(cacheControl string) {
+		req, err := http.NewRequest("GET", "https://localhost/fee_stats", nil)
+		require.NoError(t, err)
+		req.Header.Set("Cache-Control", cacheControl)
+		_, err = cachingHTTP.Do(req)
+		require.NoError(t, err)
+	}
+
+	// Requests with no-store are not cached.
+	get("no-store")
+	get("")
+	assert.Equal(t, 2, requests)
+
+	// Requests with no-cache are sent even if the response is cached.
+	get("no-cache")
+	assert.Equal(t, 3, requests)
+	get("")
+	assert.Equal(t, 3, requests)
+}
+
+func TestNewCachingHTTPValidatesConfig(t *testing.T) {
+	_, err := NewCachingHTTP(http.DefaultClient, CacheConfig{MaxEntries: 10})
+	assert.EqualError(t, err, "cache TTL must be positive")
+
+	_, err = NewCachingHTTP(http.DefaultClient, CacheConfig{TTL: time.Second})
+	assert.Error(t, err)
+}
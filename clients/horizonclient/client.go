@@ -31,7 +31,9 @@ func (c *Client) sendRequest(hr HorizonRequest, resp interface{}) (err error) {
 		return
 	}
 
-	c.HorizonURL = c.fixHorizonURL()
+	if horizonURL := c.fixHorizonURL(); horizonURL != c.HorizonURL {
+		c.HorizonURL = horizonURL
+	}
 	_, ok := hr.(submitRequest)
 	if ok {
 		return c.sendRequestURL(c.HorizonURL+endpoint, "post", resp)
@@ -108,6 +110,19 @@ func (c *Client) checkMemoRequired(transaction *txnbuild.Transaction) error {
 // sendRequestURL sends a url to a horizon server.
 // It can be used for requests that do not implement the HorizonRequest interface.
 func (c *Client) sendRequestURL(requestURL string, method string, a interface{}) (err error) {
+	resp, cancel, err := c.doRequestURL(requestURL, method)
+	if err != nil {
+		return
+	}
+
+	err = decodeResponse(resp, &a, c)
+	cancel()
+	return
+}
+
+// doRequestURL sends a url to a horizon server and returns the response. The
+// body of the response must be read before calling cancel.
+func (c *Client) doRequestURL(requestURL string, method string) (resp *http.Response, cancel context.CancelFunc, err error) {
 	var req *http.Request
 
 	if method == "post" || method == "POST" {
@@ -118,7 +133,7 @@ func (c *Client) sendRequestURL(requestURL string, method string, a interface{})
 	}
 
 	if err != nil {
-		return errors.Wrap(err, "error creating HTTP request")
+		return nil, nil, errors.Wrap(err, "error creating HTTP request")
 	}
 	c.setClientAppHeaders(req)
 	c.setDefaultClient()
@@ -126,15 +141,16 @@ func (c *Client) sendRequestURL(requestURL string, method string, a interface{})
 		c.horizonTimeout = HorizonTimeout
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), c.horizonTimeout)
-	resp, err := c.HTTP.Do(req.WithContext(ctx))
+	class := endpointClass(requestURL, method)
+	req, done := c.instrumentRequest(req.WithContext(ctx), class)
+	resp, err = c.HTTP.Do(req)
+	done(resp, err)
 	if err != nil {
 		cancel()
-		return
+		return nil, nil, err
 	}
-
-	err = decodeResponse(resp, &a, c)
-	cancel()
-	return
+	resp.Body = newLimitedBody(resp.Body, class, c.responseLimits(class), cancel)
+	return resp, cancel, nil
 }
 
 // stream handles connections to endpoints that support streaming on a horizon server
@@ -142,6 +158,18 @@ func (c *Client) stream(
 	ctx context.Context,
 	streamURL string,
 	handler func(data []byte) error,
+) error {
+	return c.resumableStream(ctx, streamURL, handler, nil)
+}
+
+// resumableStream is stream calling resume, if not nil, before each
+// reconnection with the cursor the stream would resume from. The stream
+// resumes from the cursor returned by resume instead.
+func (c *Client) resumableStream(
+	ctx context.Context,
+	streamURL string,
+	handler func(data []byte) error,
+	resume func(cursor string) (string, error),
 ) error {
 	su, err := url.Parse(streamURL)
 	if err != nil {
@@ -153,118 +181,205 @@ func (c *Client) stream(
 		query.Set("cursor", "now")
 	}
 
-	for {
+	failures := 0
+	for connections := 0; ; connections++ {
+		if connections > 0 && resume != nil {
+			cursor, err := resume(query.Get("cursor"))
+			if err != nil {
+				return err
+			}
+			query.Set("cursor", cursor)
+		}
+
 		// updates the url with new cursor
 		su.RawQuery = query.Encode()
-		req, err := http.NewRequest("GET", su.String(), nil)
-		if err != nil {
-			return errors.Wrap(err, "error creating HTTP request")
+		eventsRead, err := c.streamConnection(ctx, su.String(), query, handler)
+		if err == nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			// The stream was closed by the server, reconnect from the
+			// last cursor.
+			failures = 0
+			continue
 		}
-		req.Header.Set("Accept", "text/event-stream")
-		c.setDefaultClient()
-		c.setClientAppHeaders(req)
 
-		// We can use c.HTTP here because we set Timeout per request not on the client. See sendRequest()
-		resp, err := c.HTTP.Do(req)
-		if err != nil {
-			return errors.Wrap(err, "error sending HTTP request")
+		retryable, ok := err.(*streamRetryableError)
+		if !ok || c.StreamRetryPolicy == nil {
+			return err
+		}
+		if eventsRead > 0 {
+			failures = 0
+		}
+		failures++
+		if c.StreamRetryPolicy.MaxRetries >= 0 && failures > c.StreamRetryPolicy.MaxRetries {
+			return errors.Wrapf(retryable.err, "giving up after %d retries", failures-1)
 		}
 
-		// Expected statusCode are 200-299
-		if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
-			return fmt.Errorf("got bad HTTP status code %d", resp.StatusCode)
+		backoff := c.StreamRetryPolicy.backoff(failures)
+		c.instrumentRetry("GET", su.String(), EndpointClassStream, failures, backoff, retryable.err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
 		}
-		defer resp.Body.Close()
+	}
+}
 
-		reader := bufio.NewReader(resp.Body)
+// streamRetryableError is an error of a stream connection after which the
+// stream may be resumed, such as a network error or Horizon restarting.
+type streamRetryableError struct {
+	err error
+}
 
-		// Read events one by one. Break this loop when there is no more data to be
-		// read from resp.Body (io.EOF).
-	Events:
-		for {
-			// Read until empty line = event delimiter. The perfect solution would be to read
-			// as many bytes as possible and forward them to sse.Decode. However this
-			// requires much more complicated code.
-			// We could also write our own `sse` package that works fine with streams directly
-			// (github.com/manucorporat/sse is just using io/ioutils.ReadAll).
-			var buffer bytes.Buffer
-			nonEmptylinesRead := 0
-			for {
-				// Check if ctx is not cancelled
-				select {
-				case <-ctx.Done():
-					return nil
-				default:
-					// Continue
-				}
+func (e *streamRetryableError) Error() string {
+	return e.err.Error()
+}
 
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					if err == io.EOF || err == io.ErrUnexpectedEOF {
-						// We catch EOF errors to handle two possible situations:
-						// - The last line before closing the stream was not empty. This should never
-						//   happen in Horizon as it always sends an empty line after each event.
-						// - The stream was closed by the server/proxy because the connection was idle.
-						//
-						// In the former case, that (again) should never happen in Horizon, we need to
-						// check if there are any events we need to decode. We do this in the `if`
-						// statement below just in case if Horizon behaviour changes in a future.
-						//
-						// From spec:
-						// > Once the end of the file is reached, the user agent must dispatch the
-						// > event one final time, as defined below.
-						if nonEmptylinesRead == 0 {
-							break Events
-						}
-					} else {
-						return errors.Wrap(err, "error reading line")
-					}
-				}
-				buffer.WriteString(line)
+// streamConnection opens a single connection to the stream and passes the
+// events received to the handler, updating the cursor in query as events are
+// read. It returns the number of events read, and a nil error if the context
+// was cancelled or the server closed the connection.
+func (c *Client) streamConnection(
+	ctx context.Context,
+	streamURL string,
+	query url.Values,
+	handler func(data []byte) error,
+) (int, error) {
+	req, err := http.NewRequest("GET", streamURL, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "error creating HTTP request")
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	c.setDefaultClient()
+	c.setClientAppHeaders(req)
 
-				if strings.TrimRight(line, "\n\r") == "" {
-					break
-				}
+	// We can use c.HTTP here because we set Timeout per request not on the client. See sendRequest()
+	req, done := c.instrumentRequest(req.WithContext(ctx), EndpointClassStream)
+	resp, err := c.HTTP.Do(req)
+	done(resp, err)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0, nil
+		}
+		return 0, &streamRetryableError{errors.Wrap(err, "error sending HTTP request")}
+	}
+	defer resp.Body.Close()
+
+	// Expected statusCode are 200-299
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		err = fmt.Errorf("got bad HTTP status code %d", resp.StatusCode)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return 0, &streamRetryableError{err}
+		}
+		return 0, err
+	}
 
-				nonEmptylinesRead++
+	reader := bufio.NewReader(resp.Body)
+	eventsRead := 0
+	maxEventSize := c.responseLimits(EndpointClassStream).MaxBodySize
+
+	// Read events one by one. Return when there is no more data to be read
+	// from resp.Body (io.EOF).
+	for {
+		// Read until empty line = event delimiter. The perfect solution would be to read
+		// as many bytes as possible and forward them to sse.Decode. However this
+		// requires much more complicated code.
+		// We could also write our own `sse` package that works fine with streams directly
+		// (github.com/manucorporat/sse is just using io/ioutils.ReadAll).
+		var buffer bytes.Buffer
+		nonEmptylinesRead := 0
+		for {
+			// Check if ctx is not cancelled
+			select {
+			case <-ctx.Done():
+				return eventsRead, nil
+			default:
+				// Continue
 			}
 
-			events, err := sse.Decode(strings.NewReader(buffer.String()))
+			line, err := readEventLine(reader, int64(buffer.Len()), maxEventSize)
+			if _, ok := err.(*ResponseTooLargeError); ok {
+				return eventsRead, err
+			}
 			if err != nil {
-				return errors.Wrap(err, "error decoding event")
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					// We catch EOF errors to handle two possible situations:
+					// - The last line before closing the stream was not empty. This should never
+					//   happen in Horizon as it always sends an empty line after each event.
+					// - The stream was closed by the server/proxy because the connection was idle.
+					//
+					// In the former case, that (again) should never happen in Horizon, we need to
+					// check if there are any events we need to decode. We do this in the `if`
+					// statement below just in case if Horizon behaviour changes in a future.
+					//
+					// From spec:
+					// > Once the end of the file is reached, the user agent must dispatch the
+					// > event one final time, as defined below.
+					if nonEmptylinesRead == 0 {
+						return eventsRead, nil
+					}
+				} else {
+					if ctx.Err() != nil {
+						return eventsRead, nil
+					}
+					return eventsRead, &streamRetryableError{errors.Wrap(err, "error reading line")}
+				}
 			}
+			buffer.WriteString(line)
 
-			// Right now len(events) should always be 1. This loop will be helpful after writing
-			// new SSE decoder that can handle io.Reader without using ioutils.ReadAll().
-			for _, event := range events {
-				if event.Event != "message" {
-					continue
-				}
+			if strings.TrimRight(line, "\n\r") == "" {
+				break
+			}
 
-				// Update cursor with event ID
-				if event.Id != "" {
-					query.Set("cursor", event.Id)
-				}
+			nonEmptylinesRead++
+		}
 
-				switch data := event.Data.(type) {
-				case string:
-					err = handler([]byte(data))
-					err = errors.Wrap(err, "handler error")
-				case []byte:
-					err = handler(data)
-					err = errors.Wrap(err, "handler error")
-				default:
-					err = errors.New("invalid event.Data type")
-				}
-				if err != nil {
-					return err
-				}
+		events, err := sse.Decode(strings.NewReader(buffer.String()))
+		if err != nil {
+			return eventsRead, errors.Wrap(err, "error decoding event")
+		}
+
+		// Right now len(events) should always be 1. This loop will be helpful after writing
+		// new SSE decoder that can handle io.Reader without using ioutils.ReadAll().
+		for _, event := range events {
+			if event.Event != "message" {
+				continue
+			}
+
+			// Update cursor with event ID
+			if event.Id != "" {
+				query.Set("cursor", event.Id)
+			}
+
+			switch data := event.Data.(type) {
+			case string:
+				err = handler([]byte(data))
+				err = errors.Wrap(err, "handler error")
+			case []byte:
+				err = handler(data)
+				err = errors.Wrap(err, "handler error")
+			default:
+				err = errors.New("invalid event.Data type")
 			}
+			if err != nil {
+				return eventsRead, err
+			}
+			eventsRead++
 		}
 	}
 }
 
 func (c *Client) setClientAppHeaders(req *http.Request) {
+	for name, values := range c.Headers {
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 	req.Header.Set("X-Client-Name", "go-stellar-sdk")
 	req.Header.Set("X-Client-Version", c.Version())
 	req.Header.Set("X-App-Name", c.AppName)
@@ -0,0 +1,168 @@
+package horizonclient
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/contextutil"
+	"github.com/stellar/go/support/errors"
+)
+
+// DedupingHTTP is an HTTP decorator sharing a single round trip among
+// concurrent identical GET requests, e.g. when many goroutines fetch the
+// details of the same issuer account. Requests are identical when they have
+// the same URL and headers. Each caller receives its own copy of the
+// response. The shared request is sent with the values and the deadline of
+// the context of the first caller, or with Timeout when it has no deadline,
+// but is not canceled with it, so that the callers waiting for the same
+// response are not affected when the first one gives up. Every caller stops
+// waiting for the response when its context is done. Streaming requests are
+// never shared.
+//
+// Deduplication is enabled for a Client by wrapping its HTTP:
+//
+//	client.HTTP = horizonclient.NewDedupingHTTP(http.DefaultClient)
+type DedupingHTTP struct {
+	HTTP HTTP
+	// Timeout bounds the shared requests whose first caller has no deadline.
+	// HorizonTimeout is used when it is zero.
+	Timeout time.Duration
+
+	lock  sync.Mutex
+	calls map[string]*dedupCall
+}
+
+// dedupCall is a request in flight shared by DedupingHTTP.
+type dedupCall struct {
+	done   chan struct{}
+	status int
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// NewDedupingHTTP returns a DedupingHTTP sending requests with next.
+func NewDedupingHTTP(next HTTP) *DedupingHTTP {
+	return &DedupingHTTP{HTTP: next}
+}
+
+// Do sends req, or waits for the response of an identical request in flight.
+func (d *DedupingHTTP) Do(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Accept") == "text/event-stream" {
+		return d.HTTP.Do(req)
+	}
+
+	key := dedupKey(req)
+	d.lock.Lock()
+	call, ok := d.calls[key]
+	if !ok {
+		if d.calls == nil {
+			d.calls = map[string]*dedupCall{}
+		}
+		call = &dedupCall{done: make(chan struct{})}
+		d.calls[key] = call
+		ctx, cancel := d.sharedContext(req.Context())
+		go func() {
+			defer cancel()
+			d.send(key, call, req.Clone(ctx))
+		}()
+	}
+	d.lock.Unlock()
+
+	select {
+	case <-call.done:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	return call.response(req)
+}
+
+// sharedContext returns the context of a shared request first sent with ctx.
+func (d *DedupingHTTP) sharedContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(contextutil.Detach(ctx), deadline)
+	}
+	timeout := d.Timeout
+	if timeout == 0 {
+		timeout = HorizonTimeout
+	}
+	return context.WithTimeout(contextutil.Detach(ctx), timeout)
+}
+
+// send sends the shared request req and stores its response in call.
+func (d *DedupingHTTP) send(key string, call *dedupCall, req *http.Request) {
+	resp, err := d.HTTP.Do(req)
+	if err == nil {
+		call.status = resp.StatusCode
+		call.header = resp.Header
+		call.body, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			err = errors.Wrap(err, "reading response body")
+		}
+	}
+	call.err = err
+
+	d.lock.Lock()
+	delete(d.calls, key)
+	d.lock.Unlock()
+	close(call.done)
+}
+
+// Get sends a GET request to url, or waits for the response of an identical
+// request in flight.
+func (d *DedupingHTTP) Get(url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return d.Do(req)
+}
+
+// PostForm sends a POST request, which is never shared.
+func (d *DedupingHTTP) PostForm(url string, data url.Values) (*http.Response, error) {
+	return d.HTTP.PostForm(url, data)
+}
+
+// dedupKey returns the URL and headers of req, which identify the identical
+// requests.
+func dedupKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var key strings.Builder
+	key.WriteString(req.URL.String())
+	for _, name := range names {
+		key.WriteString("\n" + name + ": " + strings.Join(req.Header[name], ", "))
+	}
+	return key.String()
+}
+
+// response returns a new response to req built from the shared response.
+func (c *dedupCall) response(req *http.Request) (*http.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(c.status) + " " + http.StatusText(c.status),
+		StatusCode:    c.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,213 @@
+package horizonclient
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingHTTP is an HTTP counting the requests it receives, and answering
+// them once released.
+type blockingHTTP struct {
+	requests int32
+	received chan struct{}
+	release  chan struct{}
+	// canceled records whether the context of a request was canceled when
+	// it was answered.
+	canceled int32
+}
+
+func (b *blockingHTTP) Do(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&b.requests, 1)
+	b.received <- struct{}{}
+	<-b.release
+	if req.Context().Err() != nil {
+		atomic.AddInt32(&b.canceled, 1)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/hal+json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(req.URL.Path)),
+	}, nil
+}
+
+func (b *blockingHTTP) Get(url string) (*http.Response, error) {
+	panic("unexpected call")
+}
+
+func (b *blockingHTTP) PostForm(url string, data url.Values) (*http.Response, error) {
+	panic("unexpected call")
+}
+
+// waitingContext signals when a request starts waiting for its response, i.e.
+// when its Done channel is requested.
+type waitingContext struct {
+	context.Context
+	waiting chan<- struct{}
+}
+
+func (c waitingContext) Done() <-chan struct{} {
+	c.waiting <- struct{}{}
+	return c.Context.Done()
+}
+
+func TestDedupingHTTPSharesInFlightRequests(t *testing.T) {
+	next := &blockingHTTP{received: make(chan struct{}, 10), release: make(chan struct{})}
+	dedup := NewDedupingHTTP(next)
+
+	var wg sync.WaitGroup
+	bodies := make(chan string, 5)
+	waiting := make(chan struct{}, 10)
+	get := func(path string) {
+		defer wg.Done()
+		req, err := http.NewRequest(http.MethodGet, "https://localhost"+path, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		ctx := waitingContext{Context: context.Background(), waiting: waiting}
+		resp, err := dedup.Do(req.WithContext(ctx))
+		if !assert.NoError(t, err) {
+			return
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		bodies <- string(body)
+	}
+
+	wg.Add(1)
+	go get("/accounts/a")
+	<-next.received
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go get("/accounts/a")
+	}
+	wg.Add(1)
+	go get("/accounts/b")
+	<-next.received
+	// wait for all the requests to wait for their response
+	for i := 0; i < 5; i++ {
+		<-waiting
+	}
+
+	close(next.release)
+	wg.Wait()
+	close(bodies)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&next.requests))
+	counts := map[string]int{}
+	for body := range bodies {
+		counts[body]++
+	}
+	assert.Equal(t, map[string]int{"/accounts/a": 4, "/accounts/b": 1}, counts)
+	assert.Empty(t, dedup.calls)
+}
+
+func TestDedupingHTTPWaiterContext(t *testing.T) {
+	next := &blockingHTTP{received: make(chan struct{}, 10), release: make(chan struct{})}
+	dedup := NewDedupingHTTP(next)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := dedup.Get("https://localhost/fee_stats")
+		assert.NoError(t, err)
+	}()
+	<-next.received
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequest(http.MethodGet, "https://localhost/fee_stats", nil)
+	require.NoError(t, err)
+	_, err = dedup.Do(req.WithContext(ctx))
+	assert.Equal(t, context.Canceled, err)
+
+	close(next.release)
+	<-done
+	assert.Equal(t, int32(1), atomic.LoadInt32(&next.requests))
+}
+
+func TestDedupingHTTPFirstCallerContext(t *testing.T) {
+	next := &blockingHTTP{received: make(chan struct{}, 10), release: make(chan struct{})}
+	dedup := NewDedupingHTTP(next)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		req, err := http.NewRequest(http.MethodGet, "https://localhost/fee_stats", nil)
+		require.NoError(t, err)
+		_, err = dedup.Do(req.WithContext(ctx))
+		first <- err
+	}()
+	<-next.received
+
+	waiting := make(chan struct{}, 1)
+	second := make(chan string)
+	go func() {
+		req, err := http.NewRequest(http.MethodGet, "https://localhost/fee_stats", nil)
+		require.NoError(t, err)
+		resp, err := dedup.Do(req.WithContext(waitingContext{Context: context.Background(), waiting: waiting}))
+		if !assert.NoError(t, err) {
+			second <- ""
+			return
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		second <- string(body)
+	}()
+	<-waiting
+
+	// The first caller giving up does not cancel the shared request.
+	cancel()
+	assert.Equal(t, context.Canceled, <-first)
+	close(next.release)
+	assert.Equal(t, "/fee_stats", <-second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&next.requests))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&next.canceled))
+}
+
+// hungHTTP is an HTTP which never answers, until the context of the request
+// is done.
+type hungHTTP struct {
+	blockingHTTP
+}
+
+func (h *hungHTTP) Do(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestDedupingHTTPFirstCallerDeadline(t *testing.T) {
+	dedup := NewDedupingHTTP(&hungHTTP{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, "https://localhost/fee_stats", nil)
+	require.NoError(t, err)
+	_, err = dedup.Do(req.WithContext(ctx))
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the shared request is given up at the deadline of the first caller
+	assert.Eventually(t, func() bool {
+		dedup.lock.Lock()
+		defer dedup.lock.Unlock()
+		return len(dedup.calls) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestDedupingHTTPTimeout(t *testing.T) {
+	dedup := NewDedupingHTTP(&hungHTTP{})
+	dedup.Timeout = 10 * time.Millisecond
+
+	_, err := dedup.Get("https://localhost/fee_stats")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Empty(t, dedup.calls)
+}
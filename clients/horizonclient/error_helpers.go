@@ -1,6 +1,87 @@
 package horizonclient
 
-import "github.com/stellar/go/support/errors"
+import (
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// ProblemType is the type of a problem returned by Horizon, without the
+// "https://stellar.org/horizon-errors/" prefix of the problem's Type field.
+type ProblemType string
+
+// Problem types returned by Horizon.
+const (
+	ProblemBadRequest           ProblemType = "bad_request"
+	ProblemBeforeHistory        ProblemType = "before_history"
+	ProblemNotAcceptable        ProblemType = "not_acceptable"
+	ProblemNotFound             ProblemType = "not_found"
+	ProblemNotImplemented       ProblemType = "not_implemented"
+	ProblemRateLimitExceeded    ProblemType = "rate_limit_exceeded"
+	ProblemServerError          ProblemType = "server_error"
+	ProblemServerOverCapacity   ProblemType = "server_over_capacity"
+	ProblemServiceUnavailable   ProblemType = "service_unavailable"
+	ProblemStaleHistory         ProblemType = "stale_history"
+	ProblemStillIngesting       ProblemType = "still_ingesting"
+	ProblemTimeout              ProblemType = "timeout"
+	ProblemTransactionFailed    ProblemType = "transaction_failed"
+	ProblemTransactionMalformed ProblemType = "transaction_malformed"
+	ProblemUnsupportedMediaType ProblemType = "unsupported_media_type"
+)
+
+// ProblemType returns the type of the problem, e.g. ProblemNotFound.
+func (herr *Error) ProblemType() ProblemType {
+	if herr == nil {
+		return ""
+	}
+	t := herr.Problem.Type
+	if i := strings.LastIndex(t, "/"); i >= 0 {
+		t = t[i+1:]
+	}
+	return ProblemType(t)
+}
+
+// IsNotFound returns true if the resource requested was not found.
+func (herr *Error) IsNotFound() bool {
+	return herr.ProblemType() == ProblemNotFound
+}
+
+// IsRateLimited returns true if the request was rejected because the client
+// exceeded Horizon's rate limit.
+func (herr *Error) IsRateLimited() bool {
+	if herr == nil {
+		return false
+	}
+	return herr.ProblemType() == ProblemRateLimitExceeded ||
+		(herr.Response != nil && herr.Response.StatusCode == http.StatusTooManyRequests)
+}
+
+// IsTxMalformed returns true if the submitted transaction could not be
+// decoded.
+func (herr *Error) IsTxMalformed() bool {
+	return herr.ProblemType() == ProblemTransactionMalformed
+}
+
+// IsBadSequence returns true if the submitted transaction failed because its
+// sequence number was not the next sequence number of its source account.
+func (herr *Error) IsBadSequence() bool {
+	return herr.hasTransactionResultCode(TxBadSeq)
+}
+
+// IsInsufficientFee returns true if the submitted transaction failed because
+// its fee was too low, e.g. because of surge pricing.
+func (herr *Error) IsInsufficientFee() bool {
+	return herr.hasTransactionResultCode(TxInsufficientFee)
+}
+
+func (herr *Error) hasTransactionResultCode(code TransactionResultCode) bool {
+	if herr.ProblemType() != ProblemTransactionFailed {
+		return false
+	}
+	txCode, err := herr.TransactionResultCode()
+	return err == nil && txCode == code
+}
 
 // IsNotFoundError returns true if the error is a horizonclient.Error with
 // a not_found problem indicating that the resource is not found on
@@ -1,6 +1,7 @@
 package horizonclient
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stellar/go/support/errors"
@@ -229,3 +230,79 @@ func TestGetError(t *testing.T) {
 		})
 	}
 }
+
+func txProblem(txCode string, opCodes ...string) *Error {
+	codes := map[string]interface{}{"transaction": txCode}
+	if len(opCodes) > 0 {
+		codes["operations"] = opCodes
+	}
+	return &Error{
+		Problem: problem.P{
+			Type:   "https://stellar.org/horizon-errors/transaction_failed",
+			Title:  "Transaction Failed",
+			Status: 400,
+			Extras: map[string]interface{}{"result_codes": codes},
+		},
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	notFound := &Error{Problem: problem.P{Type: "https://stellar.org/horizon-errors/not_found", Status: 404}}
+	rateLimited := &Error{Problem: problem.P{Type: "https://stellar.org/horizon-errors/rate_limit_exceeded", Status: 429}}
+	tooManyRequests := &Error{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}
+	malformed := &Error{Problem: problem.P{Type: "https://stellar.org/horizon-errors/transaction_malformed", Status: 400}}
+	badSeq := txProblem("tx_bad_seq")
+	insufficientFee := txProblem("tx_insufficient_fee")
+	txFailed := txProblem("tx_failed", "op_success", "op_underfunded")
+	var nilErr *Error
+
+	testCases := []struct {
+		desc      string
+		predicate func(*Error) bool
+		matches   []*Error
+	}{
+		{"IsNotFound", (*Error).IsNotFound, []*Error{notFound}},
+		{"IsRateLimited", (*Error).IsRateLimited, []*Error{rateLimited, tooManyRequests}},
+		{"IsTxMalformed", (*Error).IsTxMalformed, []*Error{malformed}},
+		{"IsBadSequence", (*Error).IsBadSequence, []*Error{badSeq}},
+		{"IsInsufficientFee", (*Error).IsInsufficientFee, []*Error{insufficientFee}},
+	}
+	all := []*Error{notFound, rateLimited, tooManyRequests, malformed, badSeq, insufficientFee, txFailed, nilErr}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			for i, herr := range all {
+				matches := false
+				for _, match := range tc.matches {
+					matches = matches || herr == match
+				}
+				assert.Equal(t, matches, tc.predicate(herr), "error %d", i)
+			}
+		})
+	}
+}
+
+func TestErrorProblemType(t *testing.T) {
+	assert.Equal(t, ProblemNotFound, (&Error{Problem: problem.P{Type: "https://stellar.org/horizon-errors/not_found"}}).ProblemType())
+	assert.Equal(t, ProblemTransactionFailed, (&Error{Problem: problem.P{Type: "transaction_failed"}}).ProblemType())
+	assert.Equal(t, ProblemType(""), (*Error)(nil).ProblemType())
+}
+
+func TestErrorResultCodes(t *testing.T) {
+	herr := txProblem("tx_failed", "op_success", "op_underfunded")
+	txCode, err := herr.TransactionResultCode()
+	assert.NoError(t, err)
+	assert.Equal(t, TxFailed, txCode)
+	opCodes, err := herr.OperationResultCodes()
+	assert.NoError(t, err)
+	assert.Equal(t, []OperationResultCode{OpSuccess, OpUnderfunded}, opCodes)
+
+	herr = txProblem("tx_bad_seq")
+	opCodes, err = herr.OperationResultCodes()
+	assert.NoError(t, err)
+	assert.Empty(t, opCodes)
+
+	_, err = (&Error{}).TransactionResultCode()
+	assert.Equal(t, ErrResultCodesNotPopulated, err)
+	_, err = (&Error{}).OperationResultCodes()
+	assert.Equal(t, ErrResultCodesNotPopulated, err)
+}
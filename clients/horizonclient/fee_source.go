@@ -0,0 +1,74 @@
+package horizonclient
+
+import (
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// FeeStatsSource is a txnbuild.FeeSource resolving dynamic fees with the
+// max_fee distribution of Horizon's fee stats, i.e. the fees offered by the
+// transactions of the last ledgers, e.g.
+//
+//	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+//		...
+//		BaseFee:   txnbuild.DynamicFee(txnbuild.FeePercentile90),
+//		FeeSource: horizonclient.FeeStatsSource{Client: client, MaxBaseFee: 10000},
+//	})
+type FeeStatsSource struct {
+	Client ClientInterface
+	// MaxBaseFee, if not zero, caps the resolved base fee to bound the fees
+	// paid during surge pricing.
+	MaxBaseFee int64
+}
+
+// BaseFee returns the base fee offered by the given percentile of recent
+// transactions, capped to MaxBaseFee.
+func (s FeeStatsSource) BaseFee(percentile txnbuild.FeePercentile) (int64, error) {
+	if s.Client == nil {
+		return 0, errors.New("fee stats source has no client")
+	}
+
+	feeStats, err := s.Client.FeeStats()
+	if err != nil {
+		return 0, errors.Wrap(err, "could not get fee stats")
+	}
+
+	baseFee, err := feeDistributionPercentile(feeStats.MaxFee, percentile)
+	if err != nil {
+		return 0, err
+	}
+	if s.MaxBaseFee != 0 && baseFee > s.MaxBaseFee {
+		baseFee = s.MaxBaseFee
+	}
+	return baseFee, nil
+}
+
+func feeDistributionPercentile(d hProtocol.FeeDistribution, percentile txnbuild.FeePercentile) (int64, error) {
+	switch percentile {
+	case txnbuild.FeePercentile10:
+		return d.P10, nil
+	case txnbuild.FeePercentile20:
+		return d.P20, nil
+	case txnbuild.FeePercentile30:
+		return d.P30, nil
+	case txnbuild.FeePercentile40:
+		return d.P40, nil
+	case txnbuild.FeePercentile50:
+		return d.P50, nil
+	case txnbuild.FeePercentile60:
+		return d.P60, nil
+	case txnbuild.FeePercentile70:
+		return d.P70, nil
+	case txnbuild.FeePercentile80:
+		return d.P80, nil
+	case txnbuild.FeePercentile90:
+		return d.P90, nil
+	case txnbuild.FeePercentile95:
+		return d.P95, nil
+	case txnbuild.FeePercentile99:
+		return d.P99, nil
+	default:
+		return 0, errors.Errorf("unsupported fee percentile %d", percentile)
+	}
+}
@@ -0,0 +1,52 @@
+package horizonclient
+
+import (
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeStatsSource(t *testing.T) {
+	client := &MockClient{}
+	client.On("FeeStats").Return(hProtocol.FeeStats{
+		MaxFee: hProtocol.FeeDistribution{P50: 150, P90: 5000, P99: 50000},
+	}, nil)
+	source := FeeStatsSource{Client: client, MaxBaseFee: 10000}
+
+	baseFee, err := source.BaseFee(txnbuild.FeePercentile90)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5000), baseFee)
+
+	baseFee, err = source.BaseFee(txnbuild.FeePercentile99)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10000), baseFee)
+
+	_, err = source.BaseFee(txnbuild.FeePercentile(42))
+	assert.EqualError(t, err, "unsupported fee percentile 42")
+
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &txnbuild.SimpleAccount{AccountID: keypair.MustRandom().Address(), Sequence: 1},
+		Operations:    []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 2}},
+		BaseFee:       txnbuild.DynamicFee(txnbuild.FeePercentile50),
+		FeeSource:     source,
+		Timebounds:    txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(150), tx.BaseFee())
+}
+
+func TestFeeStatsSourceError(t *testing.T) {
+	client := &MockClient{}
+	client.On("FeeStats").Return(hProtocol.FeeStats{}, errors.New("horizon is down"))
+
+	_, err := FeeStatsSource{Client: client}.BaseFee(txnbuild.FeePercentile90)
+	assert.EqualError(t, err, "could not get fee stats: horizon is down")
+
+	_, err = FeeStatsSource{}.BaseFee(txnbuild.FeePercentile90)
+	assert.EqualError(t, err, "fee stats source has no client")
+}
@@ -0,0 +1,164 @@
+package horizonclient
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Instrumentation receives the events of the requests a Client sends to
+// Horizon, so that callers can record metrics such as latencies and error
+// rates per endpoint without wrapping the HTTP client. Its methods are called
+// synchronously by the goroutine sending the request, and must be safe for
+// concurrent use.
+type Instrumentation interface {
+	// OnRequest is called before a request is sent.
+	OnRequest(info RequestInfo)
+	// OnResponse is called once the response headers are received, or when
+	// the request failed without a response.
+	OnResponse(info ResponseInfo)
+	// OnRetry is called before a request is sent again: before a stream
+	// reconnects, and before SubmitTransactionXDRAsyncAndWait submits a
+	// transaction again.
+	OnRetry(info RetryInfo)
+}
+
+// RequestInfo describes a request sent to Horizon.
+type RequestInfo struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// URL is the full URL of the request.
+	URL string
+	// Endpoint is the path of the URL with the resource ids, such as account
+	// ids, hashes and sequences, replaced by {id}, e.g.
+	// /accounts/{id}/operations. It is meant to be used as a metric label.
+	Endpoint string
+	// Class is the endpoint class of the request.
+	Class EndpointClass
+}
+
+// ResponseInfo describes the outcome of a request sent to Horizon.
+type ResponseInfo struct {
+	RequestInfo
+	// StatusCode is the HTTP status code of the response, 0 if the request
+	// failed.
+	StatusCode int
+	// Err is the error of the request, if it failed without a response.
+	// Horizon errors are responses with an error StatusCode.
+	Err error
+	// Duration is the time elapsed until the response headers were
+	// received. It does not include reading the body.
+	Duration time.Duration
+	// ConnReused is true if the request was sent on a connection reused from
+	// the connection pool of the HTTP client rather than a new one. It is
+	// only known when HTTP is an *http.Client.
+	ConnReused bool
+}
+
+// RetryInfo describes a request about to be sent again.
+type RetryInfo struct {
+	RequestInfo
+	// Attempt is the number of the retry, starting at 1.
+	Attempt int
+	// Backoff is the time waited before the retry.
+	Backoff time.Duration
+	// Err is the error causing the retry.
+	Err error
+}
+
+// endpointSegments are the path segments of the Horizon endpoints which are
+// not resource ids.
+var endpointSegments = map[string]bool{
+	"data":               true,
+	"fee_stats":          true,
+	"friendbot":          true,
+	"transactions_async": true,
+}
+
+func init() {
+	for segment := range collectionEndpoints {
+		endpointSegments[segment] = true
+	}
+}
+
+// endpointName returns the path of a request URL with its resource ids
+// replaced by {id}.
+func endpointName(requestURL string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "unknown"
+	}
+	path := strings.Trim(u.Path, "/")
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !endpointSegments[segment] {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+func newRequestInfo(req *http.Request, class EndpointClass) RequestInfo {
+	return RequestInfo{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Endpoint: endpointName(req.URL.String()),
+		Class:    class,
+	}
+}
+
+// instrumentRequest calls the OnRequest hook of c.Instrumentation, if set,
+// and returns the request to send, traced to know if its connection is
+// reused, and a function to call with the outcome of the request.
+func (c *Client) instrumentRequest(req *http.Request, class EndpointClass) (*http.Request, func(resp *http.Response, err error)) {
+	if c.Instrumentation == nil {
+		return req, func(*http.Response, error) {}
+	}
+
+	info := newRequestInfo(req, class)
+	var connReused bool
+	trace := &httptrace.ClientTrace{
+		GotConn: func(conn httptrace.GotConnInfo) {
+			connReused = conn.Reused
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	c.Instrumentation.OnRequest(info)
+	start := time.Now()
+	return req, func(resp *http.Response, err error) {
+		respInfo := ResponseInfo{
+			RequestInfo: info,
+			Err:         err,
+			Duration:    time.Since(start),
+			ConnReused:  connReused,
+		}
+		if resp != nil {
+			respInfo.StatusCode = resp.StatusCode
+		}
+		c.Instrumentation.OnResponse(respInfo)
+	}
+}
+
+// instrumentRetry calls the OnRetry hook of c.Instrumentation, if set.
+func (c *Client) instrumentRetry(method, requestURL string, class EndpointClass, attempt int, backoff time.Duration, err error) {
+	if c.Instrumentation == nil {
+		return
+	}
+	c.Instrumentation.OnRetry(RetryInfo{
+		RequestInfo: RequestInfo{
+			Method:   method,
+			URL:      requestURL,
+			Endpoint: endpointName(requestURL),
+			Class:    class,
+		},
+		Attempt: attempt,
+		Backoff: backoff,
+		Err:     err,
+	})
+}
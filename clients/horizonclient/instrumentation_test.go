@@ -0,0 +1,160 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingInstrumentation records the events it receives.
+type recordingInstrumentation struct {
+	mutex     sync.Mutex
+	requests  []RequestInfo
+	responses []ResponseInfo
+	retries   []RetryInfo
+}
+
+func (r *recordingInstrumentation) OnRequest(info RequestInfo) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests = append(r.requests, info)
+}
+
+func (r *recordingInstrumentation) OnResponse(info ResponseInfo) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.responses = append(r.responses, info)
+}
+
+func (r *recordingInstrumentation) OnRetry(info RetryInfo) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.retries = append(r.retries, info)
+}
+
+func TestEndpointName(t *testing.T) {
+	for _, tc := range []struct {
+		url      string
+		endpoint string
+	}{
+		{"https://localhost/", "/"},
+		{"https://localhost/accounts/GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A", "/accounts/{id}"},
+		{"https://localhost/accounts/GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A/operations?limit=200", "/accounts/{id}/operations"},
+		{"https://localhost/accounts/GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A/data/config.memo_required", "/accounts/{id}/data/{id}"},
+		{"https://localhost/ledgers/1/transactions", "/ledgers/{id}/transactions"},
+		{"https://localhost/paths/strict-send?destination_assets=native", "/paths/strict-send"},
+		{"https://localhost/fee_stats", "/fee_stats"},
+		{"https://localhost/transactions_async?tx=AAAA", "/transactions_async"},
+	} {
+		assert.Equal(t, tc.endpoint, endpointName(tc.url), tc.url)
+	}
+}
+
+func TestInstrumentationRequests(t *testing.T) {
+	hmock := httptest.NewClient()
+	instrumentation := &recordingInstrumentation{}
+	client := &Client{
+		HorizonURL:      "https://localhost/",
+		HTTP:            hmock,
+		Instrumentation: instrumentation,
+	}
+
+	hmock.On("GET", "https://localhost/ledgers/1").ReturnString(404, notFoundResponse)
+	hmock.On("GET", "https://localhost/ledgers/2").ReturnError("connection refused")
+
+	_, err := client.LedgerDetail(1)
+	assert.True(t, IsNotFoundError(err))
+	_, err = client.LedgerDetail(2)
+	assert.Error(t, err)
+
+	request := RequestInfo{
+		Method:   "GET",
+		URL:      "https://localhost/ledgers/1",
+		Endpoint: "/ledgers/{id}",
+		Class:    EndpointClassDetail,
+	}
+	failedRequest := request
+	failedRequest.URL = "https://localhost/ledgers/2"
+	assert.Equal(t, []RequestInfo{request, failedRequest}, instrumentation.requests)
+
+	require.Len(t, instrumentation.responses, 2)
+	assert.Equal(t, request, instrumentation.responses[0].RequestInfo)
+	assert.Equal(t, 404, instrumentation.responses[0].StatusCode)
+	assert.NoError(t, instrumentation.responses[0].Err)
+	assert.Equal(t, failedRequest, instrumentation.responses[1].RequestInfo)
+	assert.Equal(t, 0, instrumentation.responses[1].StatusCode)
+	assert.Error(t, instrumentation.responses[1].Err)
+	assert.Empty(t, instrumentation.retries)
+}
+
+func TestInstrumentationConnReused(t *testing.T) {
+	server := stdhttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"sequence": 1}`))
+	}))
+	defer server.Close()
+
+	instrumentation := &recordingInstrumentation{}
+	client := &Client{
+		HorizonURL:      server.URL,
+		HTTP:            server.Client(),
+		Instrumentation: instrumentation,
+	}
+	for i := 0; i < 2; i++ {
+		_, err := client.LedgerDetail(1)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, instrumentation.responses, 2)
+	assert.Equal(t, 200, instrumentation.responses[0].StatusCode)
+	assert.False(t, instrumentation.responses[0].ConnReused)
+	assert.True(t, instrumentation.responses[1].ConnReused)
+}
+
+func TestInstrumentationRetries(t *testing.T) {
+	hmock := httptest.NewClient()
+	instrumentation := &recordingInstrumentation{}
+	client := &Client{
+		HorizonURL:      "https://localhost/",
+		HTTP:            hmock,
+		Instrumentation: instrumentation,
+	}
+
+	submissions := 0
+	hmock.
+		On("POST", "https://localhost/transactions_async?tx=AAAA").
+		Return(func(*http.Request) (*http.Response, error) {
+			submissions++
+			if submissions < 3 {
+				return httpmock.NewStringResponse(503, asyncSubmitResponse(hProtocol.TxStatusTryAgainLater, "")), nil
+			}
+			return httpmock.NewStringResponse(201, asyncSubmitResponse(hProtocol.TxStatusPending, "")), nil
+		})
+	hmock.
+		On("GET", "https://localhost/transactions/"+asyncTxHash).
+		ReturnString(200, txSuccess)
+
+	_, err := client.SubmitTransactionXDRAsyncAndWait(context.Background(), "AAAA", testPollPolicy)
+	require.NoError(t, err)
+
+	require.Len(t, instrumentation.retries, 2)
+	for i, retry := range instrumentation.retries {
+		assert.Equal(t, i+1, retry.Attempt)
+		assert.Equal(t, "POST", retry.Method)
+		assert.Equal(t, "/transactions_async", retry.Endpoint)
+		assert.Equal(t, EndpointClassSubmit, retry.Class)
+		assert.EqualError(t, retry.Err, "transaction "+asyncTxHash+": stellar-core responded TRY_AGAIN_LATER")
+	}
+	require.Len(t, instrumentation.responses, 4)
+	assert.Equal(t, 503, instrumentation.responses[0].StatusCode)
+	assert.Equal(t, 201, instrumentation.responses[2].StatusCode)
+	assert.Equal(t, "/transactions/{id}", instrumentation.responses[3].Endpoint)
+}
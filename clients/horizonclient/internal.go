@@ -26,6 +26,9 @@ func decodeResponse(resp *http.Response, object interface{}, hc *Client) (err er
 			Response: resp,
 		}
 		decodeError := decoder.Decode(&horizonError.Problem)
+		if isResponseLimitError(decodeError) {
+			return decodeError
+		}
 		if decodeError != nil {
 			return errors.Wrap(decodeError, "error decoding horizon.Problem")
 		}
@@ -33,6 +36,9 @@ func decodeResponse(resp *http.Response, object interface{}, hc *Client) (err er
 	}
 
 	err = decoder.Decode(&object)
+	if isResponseLimitError(err) {
+		return err
+	}
 	if err != nil {
 		return errors.Wrap(err, "error decoding response")
 	}
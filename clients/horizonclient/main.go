@@ -88,6 +88,11 @@ var (
 	// "result_xdr" extra field populated when it is expected to be.
 	ErrResultNotPopulated = errors.New("result_xdr not populated")
 
+	// ErrOperationResultsNotPopulated is the error returned from a call to
+	// OperationResults() against a `Problem` value for a transaction which
+	// failed before its operations were applied, e.g. with tx_bad_seq.
+	ErrOperationResultsNotPopulated = errors.New("operation results not populated")
+
 	// ErrAccountRequiresMemo is the error returned from a call to checkMemoRequired
 	// when any of the destination accounts required a memo in the transaction.
 	ErrAccountRequiresMemo = errors.New("destination account requires a memo in the transaction")
@@ -138,7 +143,30 @@ type Client struct {
 	AppName string
 
 	// AppVersion is the version of the application using the horizonclient package
-	AppVersion     string
+	AppVersion string
+
+	// UserAgent, if set, is sent as the User-Agent header of every request
+	UserAgent string
+
+	// Headers are additional headers sent with every request. They cannot
+	// override the X-Client-* and X-App-* headers identifying the client.
+	Headers http.Header
+
+	// StreamRetryPolicy, if set, makes the Stream* methods reconnect after
+	// network errors and 5xx responses, resuming from the cursor of the last
+	// event received. If nil, streams return these errors.
+	StreamRetryPolicy *StreamRetryPolicy
+
+	// ResponseLimits overrides the DefaultResponseLimits of endpoint classes,
+	// bounding the size of the responses and the time decoding them takes.
+	// Requests return a *ResponseTooLargeError or a *DecodeTimeoutError when
+	// a limit is exceeded.
+	ResponseLimits map[EndpointClass]ResponseLimits
+
+	// Instrumentation, if set, is called before and after each request sent
+	// to Horizon and before each retry, see Instrumentation.
+	Instrumentation Instrumentation
+
 	horizonTimeout time.Duration
 	isTestNet      bool
 
@@ -170,6 +198,9 @@ type ClientInterface interface {
 	SubmitTransactionWithOptions(transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.Transaction, error)
 	SubmitFeeBumpTransaction(transaction *txnbuild.FeeBumpTransaction) (hProtocol.Transaction, error)
 	SubmitTransaction(transaction *txnbuild.Transaction) (hProtocol.Transaction, error)
+	SubmitTransactionXDRAsync(transactionXdr string) (hProtocol.AsyncTransactionSubmissionResponse, error)
+	SubmitTransactionAsyncWithOptions(transaction *txnbuild.Transaction, opts SubmitTxOpts) (hProtocol.AsyncTransactionSubmissionResponse, error)
+	SubmitTransactionAsync(transaction *txnbuild.Transaction) (hProtocol.AsyncTransactionSubmissionResponse, error)
 	Transactions(request TransactionRequest) (hProtocol.TransactionsPage, error)
 	TransactionDetail(txHash string) (hProtocol.Transaction, error)
 	OrderBook(request OrderBookRequest) (hProtocol.OrderBookSummary, error)
@@ -19,6 +19,7 @@ import (
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,6 +34,40 @@ func TestFixHTTP(t *testing.T) {
 	assert.IsType(t, client.HTTP, &http.Client{})
 }
 
+func TestClientIdentificationHeaders(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		AppName:    "wallet",
+		AppVersion: "1.2.3",
+		UserAgent:  "wallet/1.2.3",
+		Headers: http.Header{
+			"X-Request-Source": []string{"backend"},
+			"X-Client-Name":    []string{"overridden"},
+		},
+	}
+
+	var headers http.Header
+	hmock.On(
+		"GET",
+		"https://localhost/",
+	).Return(func(req *http.Request) (*http.Response, error) {
+		headers = req.Header
+		return httpmock.NewStringResponse(200, rootResponse), nil
+	})
+
+	_, err := client.Root()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "wallet/1.2.3", headers.Get("User-Agent"))
+		assert.Equal(t, "wallet", headers.Get("X-App-Name"))
+		assert.Equal(t, "1.2.3", headers.Get("X-App-Version"))
+		assert.Equal(t, "backend", headers.Get("X-Request-Source"))
+		assert.Equal(t, "go-stellar-sdk", headers.Get("X-Client-Name"))
+		assert.Equal(t, client.Version(), headers.Get("X-Client-Version"))
+	}
+}
+
 func TestCheckMemoRequired(t *testing.T) {
 	tt := assert.New(t)
 
@@ -311,6 +346,8 @@ func TestAccountDetail(t *testing.T) {
 		assert.NotNil(t, account.LastModifiedTime)
 		assert.Equal(t, "2019-03-05 13:23:50 +0000 UTC", account.LastModifiedTime.String())
 		assert.Equal(t, uint32(103307), account.LastModifiedLedger)
+		assert.Equal(t, uint32(103307), account.SequenceLedger)
+		assert.Equal(t, int64(1551792230), account.SequenceTime)
 	}
 
 	// failure response
@@ -1351,6 +1388,15 @@ func TestTransactionsRequest(t *testing.T) {
 		assert.Equal(t, record.Successful, true)
 		assert.Equal(t, record.Hash, "5131aed266a639a6eb4802a92fba310454e711ded830ed899745b9e777d7110c")
 		assert.Equal(t, record.Memo, "2A1V6J5703G47XHY")
+		if assert.NotNil(t, record.Preconditions) {
+			preconditions := record.Preconditions
+			assert.Equal(t, &hProtocol.TransactionPreconditionsTimebounds{MinTime: 1553509673}, preconditions.TimeBounds)
+			assert.Equal(t, &hProtocol.TransactionPreconditionsLedgerbounds{MinLedger: 438000, MaxLedger: 439000}, preconditions.LedgerBounds)
+			assert.Equal(t, "1881766906298368", preconditions.MinAccountSequence)
+			assert.Equal(t, uint64(3600), preconditions.MinAccountSequenceAge)
+			assert.Equal(t, uint32(10), preconditions.MinAccountSequenceLedgerGap)
+			assert.Equal(t, []string{"GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}, preconditions.ExtraSigners)
+		}
 	}
 }
 
@@ -1578,6 +1624,8 @@ var accountResponse = `{
   "paging_token": "1",
   "account_id": "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
   "sequence": "9865509814140929",
+  "sequence_ledger": 103307,
+  "sequence_time": "1551792230",
   "subentry_count": 1,
   "thresholds": {
     "low_threshold": 0,
@@ -2446,7 +2494,22 @@ var txDetailResponse = `{
   "memo_type": "text",
   "signatures": [
     "kOZumR7L/Pxnf2kSdhDC7qyTMRcp0+ymw+dU+4A/dRqqf387ER4pUhqFUsOc7ZrSW9iz+6N20G4mcp0IiT5fAg=="
-  ]
+  ],
+  "preconditions": {
+    "timebounds": {
+      "min_time": "1553509673"
+    },
+    "ledgerbounds": {
+      "min_ledger": 438000,
+      "max_ledger": 439000
+    },
+    "min_account_sequence": "1881766906298368",
+    "min_account_sequence_age": "3600",
+    "min_account_sequence_ledger_gap": 10,
+    "extra_signers": [
+      "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"
+    ]
+  }
 }`
 
 var orderbookResponse = `{
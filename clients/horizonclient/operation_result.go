@@ -0,0 +1,169 @@
+package horizonclient
+
+import (
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// OperationResultCode is the result code of an operation, as reported by
+// Horizon in the "result_codes" extra field of a failed submission.
+type OperationResultCode string
+
+// Operation result codes reported by Horizon. Codes shared by several
+// operation types (e.g. op_underfunded) are reported with the same value.
+const (
+	OpSuccess            OperationResultCode = "op_success"
+	OpMalformed          OperationResultCode = "op_malformed"
+	OpUnderfunded        OperationResultCode = "op_underfunded"
+	OpLowReserve         OperationResultCode = "op_low_reserve"
+	OpLineFull           OperationResultCode = "op_line_full"
+	OpNoIssuer           OperationResultCode = "op_no_issuer"
+	OpNoTrust            OperationResultCode = "op_no_trust"
+	OpSrcNoTrust         OperationResultCode = "op_src_no_trust"
+	OpSellNoTrust        OperationResultCode = "op_sell_no_trust"
+	OpBuyNoTrust         OperationResultCode = "op_buy_no_trust"
+	OpNotAuthorized      OperationResultCode = "op_not_authorized"
+	OpSrcNotAuthorized   OperationResultCode = "op_src_not_authorized"
+	OpNoDestination      OperationResultCode = "op_no_destination"
+	OpAlreadyExists      OperationResultCode = "op_already_exists"
+	OpDoesNotExist       OperationResultCode = "op_does_not_exist"
+	OpBadAuth            OperationResultCode = "op_bad_auth"
+	OpNoSourceAccount    OperationResultCode = "op_no_source_account"
+	OpNotSupported       OperationResultCode = "op_not_supported"
+	OpTooManySubentries  OperationResultCode = "op_too_many_subentries"
+	OpTooFewOffers       OperationResultCode = "op_too_few_offers"
+	OpOverSourceMax      OperationResultCode = "op_over_source_max"
+	OpUnderDestMin       OperationResultCode = "op_under_dest_min"
+	OpCrossSelf          OperationResultCode = "op_cross_self"
+	OpOfferNotFound      OperationResultCode = "op_offer_not_found"
+	OpExceededWorkLimit  OperationResultCode = "op_exceeded_work_limit"
+	OpNotClawbackEnabled OperationResultCode = "op_not_clawback_enabled"
+)
+
+// OperationResult is the result of an operation within a failed transaction.
+type OperationResult struct {
+	// Index is the index of the operation within the transaction.
+	Index int
+	// Code is the result code Horizon reported for the operation.
+	Code OperationResultCode
+	// Result is the operation result decoded from the transaction result
+	// XDR.
+	Result xdr.OperationResult
+}
+
+// Successful returns true if the operation succeeded. Operations may succeed
+// within a failed transaction, in which case their effects are rolled back.
+func (r OperationResult) Successful() bool {
+	return r.Code == OpSuccess
+}
+
+// IsUnderfunded returns true if the operation failed because the source
+// account did not have enough funds.
+func (r OperationResult) IsUnderfunded() bool {
+	return r.Code == OpUnderfunded
+}
+
+// IsNoTrust returns true if the operation failed because an account involved
+// did not have a trustline to the asset.
+func (r OperationResult) IsNoTrust() bool {
+	switch r.Code {
+	case OpNoTrust, OpSrcNoTrust, OpSellNoTrust, OpBuyNoTrust:
+		return true
+	}
+	return false
+}
+
+// IsNotAuthorized returns true if the operation failed because an account
+// involved was not authorized to hold the asset.
+func (r OperationResult) IsNotAuthorized() bool {
+	return r.Code == OpNotAuthorized || r.Code == OpSrcNotAuthorized
+}
+
+// IsLowReserve returns true if the operation failed because an account would
+// go below its minimum balance.
+func (r OperationResult) IsLowReserve() bool {
+	return r.Code == OpLowReserve
+}
+
+// IsLineFull returns true if the operation failed because the destination
+// trustline would exceed its limit.
+func (r OperationResult) IsLineFull() bool {
+	return r.Code == OpLineFull
+}
+
+// IsNoDestination returns true if the operation failed because the
+// destination account does not exist.
+func (r OperationResult) IsNoDestination() bool {
+	return r.Code == OpNoDestination
+}
+
+// IsAlreadyExists returns true if the operation failed because the account
+// or entry it creates already exists.
+func (r OperationResult) IsAlreadyExists() bool {
+	return r.Code == OpAlreadyExists
+}
+
+// Result decodes the transaction result from the "result_xdr" extra field.
+func (herr *Error) Result() (xdr.TransactionResult, error) {
+	var result xdr.TransactionResult
+	b64, err := herr.ResultString()
+	if err != nil {
+		return result, err
+	}
+	err = xdr.SafeUnmarshalBase64(b64, &result)
+	return result, errors.Wrap(err, "xdr decode failed")
+}
+
+// OperationResults returns the result of every operation of a transaction
+// which failed with tx_failed (or tx_fee_bump_inner_failed), combining the
+// result codes reported by Horizon with the decoded result XDR. If the
+// transaction failed before its operations were applied,
+// ErrOperationResultsNotPopulated is returned.
+func (herr *Error) OperationResults() ([]OperationResult, error) {
+	result, err := herr.Result()
+	if err != nil {
+		return nil, err
+	}
+	opResults, ok := result.OperationResults()
+	if !ok {
+		return nil, ErrOperationResultsNotPopulated
+	}
+
+	codes, err := herr.ResultCodes()
+	if err != nil {
+		return nil, err
+	}
+	if len(codes.OperationCodes) != len(opResults) {
+		return nil, errors.Errorf(
+			"result_codes has %d operation codes but result_xdr has %d operation results",
+			len(codes.OperationCodes),
+			len(opResults),
+		)
+	}
+
+	results := make([]OperationResult, len(opResults))
+	for i, opResult := range opResults {
+		results[i] = OperationResult{
+			Index:  i,
+			Code:   OperationResultCode(codes.OperationCodes[i]),
+			Result: opResult,
+		}
+	}
+	return results, nil
+}
+
+// FailedOperations returns the operations which did not succeed within a
+// transaction which failed with tx_failed.
+func (herr *Error) FailedOperations() ([]OperationResult, error) {
+	results, err := herr.OperationResults()
+	if err != nil {
+		return nil, err
+	}
+	var failed []OperationResult
+	for _, result := range results {
+		if !result.Successful() {
+			failed = append(failed, result)
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,127 @@
+package horizonclient
+
+import (
+	"testing"
+
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func txFailedError(t *testing.T, result xdr.TransactionResult, opCodes []string) *Error {
+	resultXDR, err := xdr.MarshalBase64(result)
+	require.NoError(t, err)
+	return &Error{
+		Problem: problem.P{
+			Title: "Transaction Failed",
+			Type:  "transaction_failed",
+			Extras: map[string]interface{}{
+				"result_xdr": resultXDR,
+				"result_codes": map[string]interface{}{
+					"transaction": "tx_failed",
+					"operations":  opCodes,
+				},
+			},
+		},
+	}
+}
+
+func TestError_OperationResults(t *testing.T) {
+	opResults := []xdr.OperationResult{
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypeCreateAccount,
+				CreateAccountResult: &xdr.CreateAccountResult{
+					Code: xdr.CreateAccountResultCodeCreateAccountSuccess,
+				},
+			},
+		},
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypePayment,
+				PaymentResult: &xdr.PaymentResult{
+					Code: xdr.PaymentResultCodePaymentUnderfunded,
+				},
+			},
+		},
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypePayment,
+				PaymentResult: &xdr.PaymentResult{
+					Code: xdr.PaymentResultCodePaymentNoTrust,
+				},
+			},
+		},
+	}
+	herr := txFailedError(t, xdr.TransactionResult{
+		FeeCharged: 300,
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &opResults,
+		},
+	}, []string{"op_success", "op_underfunded", "op_no_trust"})
+
+	results, err := herr.OperationResults()
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, 0, results[0].Index)
+	assert.True(t, results[0].Successful())
+	assert.Equal(t, xdr.CreateAccountResultCodeCreateAccountSuccess, results[0].Result.Tr.CreateAccountResult.Code)
+
+	assert.Equal(t, 1, results[1].Index)
+	assert.Equal(t, OpUnderfunded, results[1].Code)
+	assert.True(t, results[1].IsUnderfunded())
+	assert.False(t, results[1].IsNoTrust())
+	assert.Equal(t, xdr.PaymentResultCodePaymentUnderfunded, results[1].Result.Tr.PaymentResult.Code)
+
+	assert.Equal(t, OpNoTrust, results[2].Code)
+	assert.True(t, results[2].IsNoTrust())
+	assert.False(t, results[2].IsUnderfunded())
+
+	failed, err := herr.FailedOperations()
+	require.NoError(t, err)
+	require.Len(t, failed, 2)
+	assert.Equal(t, 1, failed[0].Index)
+	assert.Equal(t, 2, failed[1].Index)
+
+	// sad path: mismatched result codes
+	herr = txFailedError(t, xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &opResults,
+		},
+	}, []string{"op_underfunded"})
+	_, err = herr.OperationResults()
+	assert.EqualError(t, err, "result_codes has 1 operation codes but result_xdr has 3 operation results")
+
+	// sad path: transaction failed before applying its operations
+	herr = txFailedError(t, xdr.TransactionResult{
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxBadSeq,
+		},
+	}, nil)
+	_, err = herr.OperationResults()
+	assert.Equal(t, ErrOperationResultsNotPopulated, err)
+
+	// sad path: missing result_xdr extra
+	herr = &Error{Problem: problem.P{Extras: map[string]interface{}{}}}
+	_, err = herr.OperationResults()
+	assert.Equal(t, ErrResultNotPopulated, err)
+}
+
+func TestOperationResult_predicates(t *testing.T) {
+	assert.True(t, OperationResult{Code: OpSrcNoTrust}.IsNoTrust())
+	assert.True(t, OperationResult{Code: OpSellNoTrust}.IsNoTrust())
+	assert.True(t, OperationResult{Code: OpBuyNoTrust}.IsNoTrust())
+	assert.True(t, OperationResult{Code: OpSrcNotAuthorized}.IsNotAuthorized())
+	assert.True(t, OperationResult{Code: OpLowReserve}.IsLowReserve())
+	assert.True(t, OperationResult{Code: OpLineFull}.IsLineFull())
+	assert.True(t, OperationResult{Code: OpNoDestination}.IsNoDestination())
+	assert.True(t, OperationResult{Code: OpAlreadyExists}.IsAlreadyExists())
+	assert.False(t, OperationResult{Code: OpMalformed}.IsUnderfunded())
+}
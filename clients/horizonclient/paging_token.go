@@ -0,0 +1,172 @@
+package horizonclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	pagingTokenLedgerShift      = 32
+	pagingTokenTransactionShift = 12
+	pagingTokenTransactionMask  = (1 << 20) - 1
+	pagingTokenOperationMask    = (1 << 12) - 1
+)
+
+// PagingToken is a decoded Horizon paging token. Ledgers, transactions and
+// operations are paged by their total order ID, which packs the sequence of
+// the ledger, the application order of the transaction within the ledger and
+// the index of the operation within the transaction (all starting at 1).
+// Effects and trades add the index of the effect or trade within its
+// operation, separated by a dash.
+type PagingToken struct {
+	LedgerSequence   int32
+	TransactionOrder int32
+	OperationOrder   int32
+
+	// HasIndex is true for paging tokens of effects and trades, whose Index
+	// is the index of the effect or trade within its operation.
+	HasIndex bool
+	Index    int64
+}
+
+// ParsePagingToken decodes a paging token as found in the paging_token
+// field of Horizon resources.
+func ParsePagingToken(token string) (PagingToken, error) {
+	var pt PagingToken
+	id := token
+	if i := strings.IndexByte(token, '-'); i >= 0 {
+		index, err := strconv.ParseInt(token[i+1:], 10, 64)
+		if err != nil || index < 0 {
+			return pt, errors.Errorf("invalid paging token %q", token)
+		}
+		id = token[:i]
+		pt.HasIndex = true
+		pt.Index = index
+	}
+
+	toid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || toid < 0 {
+		return pt, errors.Errorf("invalid paging token %q", token)
+	}
+	pt.LedgerSequence = int32(toid >> pagingTokenLedgerShift)
+	pt.TransactionOrder = int32((toid >> pagingTokenTransactionShift) & pagingTokenTransactionMask)
+	pt.OperationOrder = int32(toid & pagingTokenOperationMask)
+	return pt, nil
+}
+
+// LedgerPagingToken returns the paging token of the ledger with the given
+// sequence. Transactions and operations of the ledger come after it.
+func LedgerPagingToken(sequence int32) PagingToken {
+	return PagingToken{LedgerSequence: sequence}
+}
+
+// AfterLedgerPagingToken returns a paging token which comes after all the
+// transactions and operations of the ledger with the given sequence, and
+// before the next ledger.
+func AfterLedgerPagingToken(sequence int32) PagingToken {
+	return PagingToken{
+		LedgerSequence:   sequence,
+		TransactionOrder: pagingTokenTransactionMask,
+		OperationOrder:   pagingTokenOperationMask,
+	}
+}
+
+// ID returns the total order ID encoded in the paging token.
+func (pt PagingToken) ID() int64 {
+	return int64(pt.LedgerSequence)<<pagingTokenLedgerShift |
+		int64(pt.TransactionOrder&pagingTokenTransactionMask)<<pagingTokenTransactionShift |
+		int64(pt.OperationOrder&pagingTokenOperationMask)
+}
+
+// String encodes the paging token so that it can be used as a cursor.
+func (pt PagingToken) String() string {
+	if pt.HasIndex {
+		return fmt.Sprintf("%d-%d", pt.ID(), pt.Index)
+	}
+	return strconv.FormatInt(pt.ID(), 10)
+}
+
+// Compare returns -1, 0 or 1 if pt comes before, at or after other. Paging
+// tokens without an index come before those with an index at the same ID.
+func (pt PagingToken) Compare(other PagingToken) int {
+	switch a, b := pt.ID(), other.ID(); {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	switch {
+	case !pt.HasIndex && other.HasIndex:
+		return -1
+	case pt.HasIndex && !other.HasIndex:
+		return 1
+	case pt.Index < other.Index:
+		return -1
+	case pt.Index > other.Index:
+		return 1
+	}
+	return 0
+}
+
+// ComparePagingTokens parses and compares two paging tokens. It returns -1, 0
+// or 1 if a comes before, at or after b.
+func ComparePagingTokens(a, b string) (int, error) {
+	pa, err := ParsePagingToken(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, err := ParsePagingToken(b)
+	if err != nil {
+		return 0, err
+	}
+	return pa.Compare(pb), nil
+}
+
+// CursorForTime returns a cursor from which ledgers, transactions and
+// operations closed at or after t are paged or streamed. It binary searches
+// the ledgers kept by Horizon for the first ledger closed at or after t. If t
+// is before the oldest ledger the cursor starts at the oldest ledger, and if
+// it is after the latest ledger the cursor starts after the latest ledger.
+func (c *Client) CursorForTime(t time.Time) (string, error) {
+	latest, err := c.Ledgers(LedgerRequest{Order: OrderDesc, Limit: 1})
+	if err != nil {
+		return "", errors.Wrap(err, "getting latest ledger")
+	}
+	if len(latest.Embedded.Records) == 0 {
+		return "", errors.New("no ledgers found")
+	}
+	hi := latest.Embedded.Records[0]
+	if hi.ClosedAt.Before(t) {
+		return AfterLedgerPagingToken(hi.Sequence).String(), nil
+	}
+
+	oldest, err := c.Ledgers(LedgerRequest{Order: OrderAsc, Limit: 1})
+	if err != nil {
+		return "", errors.Wrap(err, "getting oldest ledger")
+	}
+	if len(oldest.Embedded.Records) == 0 {
+		return "", errors.New("no ledgers found")
+	}
+	lo := oldest.Embedded.Records[0]
+	if !lo.ClosedAt.Before(t) {
+		return AfterLedgerPagingToken(lo.Sequence - 1).String(), nil
+	}
+
+	// lo is closed before t and hi at or after t.
+	for hi.Sequence-lo.Sequence > 1 {
+		mid, err := c.LedgerDetail(uint32(lo.Sequence + (hi.Sequence-lo.Sequence)/2))
+		if err != nil {
+			return "", errors.Wrap(err, "getting ledger")
+		}
+		if mid.ClosedAt.Before(t) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return AfterLedgerPagingToken(lo.Sequence).String(), nil
+}
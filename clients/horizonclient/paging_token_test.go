@@ -0,0 +1,121 @@
+package horizonclient
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePagingToken(t *testing.T) {
+	pt, err := ParsePagingToken("2406637679673344")
+	require.NoError(t, err)
+	assert.Equal(t, PagingToken{LedgerSequence: 560339}, pt)
+	assert.Equal(t, "2406637679673344", pt.String())
+
+	pt, err = ParsePagingToken("1234566874402817")
+	require.NoError(t, err)
+	assert.Equal(t, PagingToken{LedgerSequence: 287445, TransactionOrder: 1, OperationOrder: 1}, pt)
+	assert.Equal(t, "1234566874402817", pt.String())
+
+	pt, err = ParsePagingToken("1234566874402817-2")
+	require.NoError(t, err)
+	assert.Equal(t, PagingToken{LedgerSequence: 287445, TransactionOrder: 1, OperationOrder: 1, HasIndex: true, Index: 2}, pt)
+	assert.Equal(t, "1234566874402817-2", pt.String())
+
+	for _, token := range []string{"", "now", "-1", "1-", "1-a", "1--1"} {
+		_, err = ParsePagingToken(token)
+		assert.EqualError(t, err, fmt.Sprintf("invalid paging token %q", token))
+	}
+}
+
+func TestComparePagingTokens(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"1234566874402817", "1234566874402817", 0},
+		{"1234566874402817", "1234566874402818", -1},
+		{"1234566874402818", "1234566874402817", 1},
+		{"1234566874402817", "1234566874402817-1", -1},
+		{"1234566874402817-2", "1234566874402817-1", 1},
+		{"1234566874402817-2", "1234566874402818", -1},
+	} {
+		c, err := ComparePagingTokens(tc.a, tc.b)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, c, "%s %s", tc.a, tc.b)
+	}
+
+	_, err := ComparePagingTokens("1", "now")
+	assert.EqualError(t, err, `invalid paging token "now"`)
+}
+
+func TestLedgerPagingTokens(t *testing.T) {
+	ledger := LedgerPagingToken(10)
+	after := AfterLedgerPagingToken(10)
+	next := LedgerPagingToken(11)
+	tx, err := ParsePagingToken(PagingToken{LedgerSequence: 10, TransactionOrder: 3, OperationOrder: 2}.String())
+	require.NoError(t, err)
+
+	assert.Equal(t, -1, ledger.Compare(tx))
+	assert.Equal(t, -1, tx.Compare(after))
+	assert.Equal(t, -1, after.Compare(next))
+}
+
+func TestCursorForTime(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	closedAt := func(sequence int) time.Time {
+		return start.Add(time.Duration(sequence-100) * 5 * time.Second)
+	}
+	ledgerJSON := func(sequence int) string {
+		return fmt.Sprintf(`{"sequence": %d, "closed_at": "%s"}`, sequence, closedAt(sequence).Format(time.RFC3339))
+	}
+
+	// CursorForTime requests the latest and oldest ledgers on every call, so
+	// their responders return a new body each time.
+	pageResponder := func(sequence int) httpmock.Responder {
+		return func(*http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(200, `{"_embedded": {"records": [`+ledgerJSON(sequence)+`]}}`), nil
+		}
+	}
+	hmock.On("GET", "https://localhost/ledgers?limit=1&order=desc").Return(pageResponder(1100))
+	hmock.On("GET", "https://localhost/ledgers?limit=1&order=asc").Return(pageResponder(100))
+	requests := 0
+	for sequence := 101; sequence < 1100; sequence++ {
+		hmock.On("GET", fmt.Sprintf("https://localhost/ledgers/%d", sequence)).
+			Return(func(sequence int) httpmock.Responder {
+				return func(*http.Request) (*http.Response, error) {
+					requests++
+					return httpmock.NewStringResponse(200, ledgerJSON(sequence)), nil
+				}
+			}(sequence))
+	}
+
+	cursor, err := client.CursorForTime(closedAt(500))
+	require.NoError(t, err)
+	assert.Equal(t, AfterLedgerPagingToken(499).String(), cursor)
+	assert.True(t, requests <= 10, requests)
+
+	cursor, err = client.CursorForTime(closedAt(500).Add(-time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, AfterLedgerPagingToken(499).String(), cursor)
+
+	cursor, err = client.CursorForTime(closedAt(50))
+	require.NoError(t, err)
+	assert.Equal(t, AfterLedgerPagingToken(99).String(), cursor)
+
+	cursor, err = client.CursorForTime(closedAt(1200))
+	require.NoError(t, err)
+	assert.Equal(t, AfterLedgerPagingToken(1100).String(), cursor)
+}
@@ -0,0 +1,430 @@
+package horizonclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// PreflightIssue is a likely reason for a transaction to fail, found by
+// Preflight.
+type PreflightIssue struct {
+	// Operation is the index of the operation the issue applies to, or -1 if
+	// it applies to the whole transaction.
+	Operation int
+	// Code is the result code the transaction or operation would likely fail
+	// with, e.g. "tx_bad_seq" or "op_underfunded".
+	Code string
+	// Detail describes the issue.
+	Detail string
+}
+
+// PreflightReport lists the issues found by Preflight.
+type PreflightReport struct {
+	Issues []PreflightIssue
+}
+
+// OK returns true if no issues were found.
+func (r PreflightReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Preflight checks the transaction against the current state of the ledger
+// as reported by Horizon, and returns the reasons it would likely fail if it
+// was submitted. It checks:
+//
+//   - the time bounds and sequence number of the transaction,
+//   - that the source accounts exist and the signatures of the transaction
+//     meet their thresholds (pre-authorized transaction and hash signers are
+//     taken into account),
+//   - that the source account can pay the fee,
+//   - that the source accounts of payments, path payments and account
+//     creations hold enough of the asset sent, taking reserves and liabilities
+//     into account,
+//   - that the destinations of these operations exist (or do not, for account
+//     creations) and have authorized trustlines to the asset received.
+//
+// Other operations are only checked for their source account and threshold.
+// Passing the checks does not guarantee that the transaction succeeds, as the
+// ledger can change before it is applied and path payments may not find
+// offers.
+func Preflight(client ClientInterface, tx *txnbuild.Transaction) (PreflightReport, error) {
+	root, err := client.Root()
+	if err != nil {
+		return PreflightReport{}, errors.Wrap(err, "getting root resource")
+	}
+	ledgers, err := client.Ledgers(LedgerRequest{Order: OrderDesc, Limit: 1})
+	if err != nil {
+		return PreflightReport{}, errors.Wrap(err, "getting latest ledger")
+	}
+	if len(ledgers.Embedded.Records) == 0 {
+		return PreflightReport{}, errors.New("no ledgers found")
+	}
+	hash, err := tx.Hash(root.NetworkPassphrase)
+	if err != nil {
+		return PreflightReport{}, errors.Wrap(err, "hashing transaction")
+	}
+
+	p := &preflight{
+		client:      client,
+		tx:          tx,
+		hash:        hash,
+		baseReserve: int64(ledgers.Embedded.Records[0].BaseReserve),
+		accounts:    map[string]*hProtocol.Account{},
+		available:   map[string]int64{},
+		created:     map[string]bool{},
+	}
+	if err := p.run(time.Now()); err != nil {
+		return PreflightReport{}, err
+	}
+	return p.report, nil
+}
+
+// preflight holds the state of the checks of a transaction.
+type preflight struct {
+	client      ClientInterface
+	tx          *txnbuild.Transaction
+	hash        [32]byte
+	baseReserve int64
+	report      PreflightReport
+
+	// accounts are the accounts loaded from Horizon, nil if they do not
+	// exist.
+	accounts map[string]*hProtocol.Account
+	// available is the amount of an asset an account can still send, keyed
+	// by account and asset.
+	available map[string]int64
+	// created are the accounts created by the transaction, which are not
+	// checked.
+	created map[string]bool
+}
+
+func (p *preflight) addIssue(operation int, code string, format string, args ...interface{}) {
+	p.report.Issues = append(p.report.Issues, PreflightIssue{
+		Operation: operation,
+		Code:      code,
+		Detail:    fmt.Sprintf(format, args...),
+	})
+}
+
+func (p *preflight) run(now time.Time) error {
+	timebounds := p.tx.Timebounds()
+	if timebounds.MinTime > 0 && now.Unix() < timebounds.MinTime {
+		p.addIssue(-1, string(TxTooEarly), "transaction is not valid before %d", timebounds.MinTime)
+	}
+	if timebounds.MaxTime > 0 && now.Unix() > timebounds.MaxTime {
+		p.addIssue(-1, string(TxTooLate), "transaction is not valid after %d", timebounds.MaxTime)
+	}
+
+	txSource, err := baseAddress(p.tx.SourceAccount().AccountID)
+	if err != nil {
+		return errors.Wrap(err, "invalid transaction source account")
+	}
+	account, err := p.account(txSource)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		p.addIssue(-1, string(TxNoSourceAccount), "source account %s does not exist", txSource)
+		return nil
+	}
+
+	sequence, err := account.GetSequenceNumber()
+	if err != nil {
+		return err
+	}
+	if p.tx.SequenceNumber() != sequence+1 {
+		p.addIssue(-1, string(TxBadSeq), "sequence number is %d but account %s expects %d", p.tx.SequenceNumber(), txSource, sequence+1)
+	}
+	if !p.authorized(account, account.Thresholds.LowThreshold) {
+		p.addIssue(-1, string(TxBadAuth), "signatures do not meet the low threshold of source account %s", txSource)
+	}
+	available, err := p.availableBalance(account, txnbuild.NativeAsset{})
+	if err != nil {
+		return err
+	}
+	if available < p.tx.MaxFee() {
+		p.addIssue(-1, string(TxInsufficientBalance), "source account %s cannot pay the fee of %s XLM", txSource, amount.StringFromInt64(p.tx.MaxFee()))
+	}
+	p.available[balanceKey(txSource, txnbuild.NativeAsset{})] = available - p.tx.MaxFee()
+
+	for i, op := range p.tx.Operations() {
+		source := txSource
+		if op.GetSourceAccount() != "" {
+			if source, err = baseAddress(op.GetSourceAccount()); err != nil {
+				return errors.Wrapf(err, "invalid source account of operation %d", i)
+			}
+		}
+		if err := p.checkOperation(i, op, source); err != nil {
+			return errors.Wrapf(err, "checking operation %d", i)
+		}
+	}
+	return nil
+}
+
+func (p *preflight) checkOperation(index int, op txnbuild.Operation, source string) error {
+	if !p.created[source] {
+		account, err := p.account(source)
+		if err != nil {
+			return err
+		}
+		if account == nil {
+			p.addIssue(index, string(OpNoSourceAccount), "source account %s does not exist", source)
+			return nil
+		}
+		if threshold := operationThreshold(op, account.Thresholds); !p.authorized(account, threshold) {
+			p.addIssue(index, string(OpBadAuth), "signatures do not meet the threshold %d of source account %s", threshold, source)
+		}
+	}
+
+	switch op := op.(type) {
+	case *txnbuild.CreateAccount:
+		return p.checkCreateAccount(index, op, source)
+	case *txnbuild.Payment:
+		return p.checkPayment(index, source, op.Destination, op.Asset, op.Amount, op.Asset)
+	case *txnbuild.PathPaymentStrictReceive:
+		return p.checkPayment(index, source, op.Destination, op.SendAsset, op.SendMax, op.DestAsset)
+	case *txnbuild.PathPaymentStrictSend:
+		return p.checkPayment(index, source, op.Destination, op.SendAsset, op.SendAmount, op.DestAsset)
+	}
+	return nil
+}
+
+func (p *preflight) checkCreateAccount(index int, op *txnbuild.CreateAccount, source string) error {
+	destination, err := baseAddress(op.Destination)
+	if err != nil {
+		return errors.Wrap(err, "invalid destination")
+	}
+	account, err := p.account(destination)
+	if err != nil {
+		return err
+	}
+	if account != nil || p.created[destination] {
+		p.addIssue(index, string(OpAlreadyExists), "destination account %s already exists", destination)
+	}
+	if err := p.debit(index, source, txnbuild.NativeAsset{}, op.Amount); err != nil {
+		return err
+	}
+	p.created[destination] = true
+	return nil
+}
+
+func (p *preflight) checkPayment(index int, source, destination string, sendAsset txnbuild.Asset, sendAmount string, destAsset txnbuild.Asset) error {
+	destination, err := baseAddress(destination)
+	if err != nil {
+		return errors.Wrap(err, "invalid destination")
+	}
+	if err := p.debit(index, source, sendAsset, sendAmount); err != nil {
+		return err
+	}
+
+	if p.created[destination] {
+		return nil
+	}
+	account, err := p.account(destination)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		p.addIssue(index, string(OpNoDestination), "destination account %s does not exist", destination)
+		return nil
+	}
+	if destAsset.IsNative() || destAsset.GetIssuer() == destination {
+		return nil
+	}
+	balance, ok := findBalance(account, destAsset)
+	switch {
+	case !ok:
+		p.addIssue(index, string(OpNoTrust), "destination account %s does not trust %s", destination, assetString(destAsset))
+	case balance.IsAuthorized != nil && !*balance.IsAuthorized:
+		p.addIssue(index, string(OpNotAuthorized), "destination account %s is not authorized to hold %s", destination, assetString(destAsset))
+	}
+	return nil
+}
+
+// debit records that the source account sends the given amount of an asset,
+// adding an issue if it cannot.
+func (p *preflight) debit(index int, source string, asset txnbuild.Asset, amountString string) error {
+	if p.created[source] || !asset.IsNative() && asset.GetIssuer() == source {
+		return nil
+	}
+	debit, err := amount.ParseInt64(amountString)
+	if err != nil {
+		return errors.Wrapf(err, "invalid amount %s", amountString)
+	}
+	account, err := p.account(source)
+	if err != nil || account == nil {
+		return err
+	}
+
+	key := balanceKey(source, asset)
+	available, ok := p.available[key]
+	if !ok {
+		if !asset.IsNative() {
+			balance, ok := findBalance(account, asset)
+			if !ok {
+				p.addIssue(index, string(OpSrcNoTrust), "source account %s does not trust %s", source, assetString(asset))
+				return nil
+			}
+			if balance.IsAuthorized != nil && !*balance.IsAuthorized {
+				p.addIssue(index, string(OpSrcNotAuthorized), "source account %s is not authorized to send %s", source, assetString(asset))
+				return nil
+			}
+		}
+		if available, err = p.availableBalance(account, asset); err != nil {
+			return err
+		}
+	}
+	if debit > available {
+		p.addIssue(index, string(OpUnderfunded), "source account %s can send %s %s but sends %s", source, amount.StringFromInt64(available), assetString(asset), amountString)
+	}
+	p.available[key] = available - debit
+	return nil
+}
+
+// availableBalance returns how much of an asset the account can send, that
+// is its balance minus its selling liabilities and, for XLM, its minimum
+// balance.
+func (p *preflight) availableBalance(account *hProtocol.Account, asset txnbuild.Asset) (int64, error) {
+	balance, ok := findBalance(account, asset)
+	if !ok {
+		return 0, nil
+	}
+	available, err := amount.ParseInt64(balance.Balance)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid balance")
+	}
+	if balance.SellingLiabilities != "" {
+		liabilities, err := amount.ParseInt64(balance.SellingLiabilities)
+		if err != nil {
+			return 0, errors.Wrap(err, "invalid selling liabilities")
+		}
+		available -= liabilities
+	}
+	if asset.IsNative() {
+		entries := 2 + int64(account.SubentryCount) + int64(account.NumSponsoring) - int64(account.NumSponsored)
+		available -= entries * p.baseReserve
+	}
+	return available, nil
+}
+
+// authorized returns true if the signatures of the transaction meet the
+// given threshold of the account.
+func (p *preflight) authorized(account *hProtocol.Account, threshold byte) bool {
+	var weight int32
+	for _, signer := range account.Signers {
+		if signer.Weight > 0 && p.signedBy(signer) {
+			weight += signer.Weight
+		}
+	}
+	return weight > 0 && weight >= int32(threshold)
+}
+
+// signedBy returns true if the transaction is signed by the signer.
+func (p *preflight) signedBy(signer hProtocol.Signer) bool {
+	switch signer.Type {
+	case hProtocol.KeyTypeNames[strkey.VersionByteAccountID]:
+		kp, err := keypair.ParseAddress(signer.Key)
+		if err != nil {
+			return false
+		}
+		hint := kp.Hint()
+		for _, signature := range p.tx.Signatures() {
+			if signature.Hint == xdr.SignatureHint(hint) && kp.Verify(p.hash[:], signature.Signature) == nil {
+				return true
+			}
+		}
+	case hProtocol.KeyTypeNames[strkey.VersionByteHashTx]:
+		key, err := strkey.Decode(strkey.VersionByteHashTx, signer.Key)
+		return err == nil && bytes.Equal(key, p.hash[:])
+	case hProtocol.KeyTypeNames[strkey.VersionByteHashX]:
+		key, err := strkey.Decode(strkey.VersionByteHashX, signer.Key)
+		if err != nil {
+			return false
+		}
+		for _, signature := range p.tx.Signatures() {
+			preimageHash := sha256.Sum256(signature.Signature)
+			if bytes.Equal(key, preimageHash[:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// account returns the account with the given address, or nil if it does not
+// exist.
+func (p *preflight) account(address string) (*hProtocol.Account, error) {
+	if account, ok := p.accounts[address]; ok {
+		return account, nil
+	}
+	account, err := p.client.AccountDetail(AccountRequest{AccountID: address})
+	if IsNotFoundError(err) {
+		p.accounts[address] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting account %s", address)
+	}
+	p.accounts[address] = &account
+	return &account, nil
+}
+
+// operationThreshold returns the threshold of the source account the
+// signatures of the transaction must meet for the operation.
+func operationThreshold(op txnbuild.Operation, thresholds hProtocol.AccountThresholds) byte {
+	switch op := op.(type) {
+	case *txnbuild.AllowTrust, *txnbuild.BumpSequence, *txnbuild.ClaimClaimableBalance,
+		*txnbuild.Inflation, *txnbuild.SetTrustLineFlags:
+		return thresholds.LowThreshold
+	case *txnbuild.AccountMerge:
+		return thresholds.HighThreshold
+	case *txnbuild.SetOptions:
+		if op.MasterWeight != nil || op.LowThreshold != nil || op.MediumThreshold != nil ||
+			op.HighThreshold != nil || op.Signer != nil {
+			return thresholds.HighThreshold
+		}
+	}
+	return thresholds.MedThreshold
+}
+
+// baseAddress returns the G-address of an account, which can be given as a
+// muxed M-address.
+func baseAddress(address string) (string, error) {
+	muxed, err := xdr.AddressToMuxedAccount(address)
+	if err != nil {
+		return "", err
+	}
+	accountID := muxed.ToAccountId()
+	return accountID.Address(), nil
+}
+
+func findBalance(account *hProtocol.Account, asset txnbuild.Asset) (hProtocol.Balance, bool) {
+	for _, balance := range account.Balances {
+		if asset.IsNative() && balance.Type == "native" ||
+			!asset.IsNative() && balance.Code == asset.GetCode() && balance.Issuer == asset.GetIssuer() {
+			return balance, true
+		}
+	}
+	return hProtocol.Balance{}, false
+}
+
+func balanceKey(address string, asset txnbuild.Asset) string {
+	return address + "/" + assetString(asset)
+}
+
+func assetString(asset txnbuild.Asset) string {
+	if asset.IsNative() {
+		return "XLM"
+	}
+	return asset.GetCode() + ":" + asset.GetIssuer()
+}
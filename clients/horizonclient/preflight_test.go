@@ -0,0 +1,132 @@
+package horizonclient
+
+import (
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func preflightTestClient(accounts ...hProtocol.Account) *MockClient {
+	client := &MockClient{}
+	client.On("Root").Return(hProtocol.Root{NetworkPassphrase: network.TestNetworkPassphrase}, nil)
+	ledgers := hProtocol.LedgersPage{}
+	ledgers.Embedded.Records = []hProtocol.Ledger{{BaseReserve: 5000000}}
+	client.On("Ledgers", LedgerRequest{Order: OrderDesc, Limit: 1}).Return(ledgers, nil)
+	for _, account := range accounts {
+		client.On("AccountDetail", AccountRequest{AccountID: account.AccountID}).Return(account, nil)
+	}
+	client.On("AccountDetail", AccountRequest{AccountID: "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"}).
+		Return(hProtocol.Account{}, &Error{Problem: problem.P{Type: "https://stellar.org/horizon-errors/not_found"}})
+	return client
+}
+
+func preflightTestAccount(kp *keypair.Full, sequence string, balances ...hProtocol.Balance) hProtocol.Account {
+	return hProtocol.Account{
+		AccountID: kp.Address(),
+		Sequence:  sequence,
+		Balances:  balances,
+		Signers: []hProtocol.Signer{
+			{Key: kp.Address(), Type: "ed25519_public_key", Weight: 1},
+		},
+	}
+}
+
+func TestPreflightOK(t *testing.T) {
+	source := keypair.MustRandom()
+	destination := keypair.MustRandom()
+	issuer := keypair.MustRandom()
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: issuer.Address()}
+	authorized := true
+	client := preflightTestClient(
+		preflightTestAccount(source, "100",
+			hProtocol.Balance{Balance: "100.0000000", Asset: base.Asset{Type: "native"}},
+			hProtocol.Balance{Balance: "5.0000000", Asset: base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer.Address()}},
+		),
+		preflightTestAccount(destination, "200",
+			hProtocol.Balance{Balance: "2.0000000", Asset: base.Asset{Type: "native"}},
+			hProtocol.Balance{Balance: "0.0000000", IsAuthorized: &authorized, Asset: base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer.Address()}},
+		),
+	)
+
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 100},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: destination.Address(), Amount: "98.9", Asset: txnbuild.NativeAsset{}},
+			&txnbuild.Payment{Destination: destination.Address(), Amount: "5", Asset: usd},
+			&txnbuild.CreateAccount{Destination: "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", Amount: "0.0999", SourceAccount: destination.Address()},
+		},
+		BaseFee:    txnbuild.MinBaseFee,
+		Timebounds: txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, source, destination)
+	require.NoError(t, err)
+
+	report, err := Preflight(client, tx)
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.Issues)
+}
+
+func TestPreflightIssues(t *testing.T) {
+	source := keypair.MustRandom()
+	destination := keypair.MustRandom()
+	issuer := keypair.MustRandom()
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: issuer.Address()}
+	eur := txnbuild.CreditAsset{Code: "EUR", Issuer: issuer.Address()}
+	notAuthorized := false
+	client := preflightTestClient(
+		preflightTestAccount(source, "100",
+			hProtocol.Balance{Balance: "100.0000000", SellingLiabilities: "10.0000000", Asset: base.Asset{Type: "native"}},
+			hProtocol.Balance{Balance: "5.0000000", IsAuthorized: &notAuthorized, Asset: base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer.Address()}},
+		),
+		preflightTestAccount(destination, "200",
+			hProtocol.Balance{Balance: "1.0000000", Asset: base.Asset{Type: "native"}},
+		),
+	)
+
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 100},
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: destination.Address(), Amount: "89", Asset: txnbuild.NativeAsset{}},
+			&txnbuild.Payment{Destination: destination.Address(), Amount: "5", Asset: usd},
+			&txnbuild.Payment{Destination: destination.Address(), Amount: "5", Asset: eur},
+			&txnbuild.Payment{Destination: destination.Address(), Amount: "1", Asset: usd, SourceAccount: "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"},
+			&txnbuild.CreateAccount{Destination: destination.Address(), Amount: "1"},
+		},
+		BaseFee:    txnbuild.MinBaseFee,
+		Timebounds: txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+
+	report, err := Preflight(client, tx)
+	require.NoError(t, err)
+	codes := []PreflightIssue{}
+	for _, issue := range report.Issues {
+		codes = append(codes, PreflightIssue{Operation: issue.Operation, Code: issue.Code})
+	}
+	assert.Equal(t, []PreflightIssue{
+		{Operation: -1, Code: string(TxBadSeq)},
+		{Operation: -1, Code: string(TxBadAuth)},
+		{Operation: 0, Code: string(OpBadAuth)},
+		{Operation: 0, Code: string(OpUnderfunded)},
+		{Operation: 1, Code: string(OpBadAuth)},
+		{Operation: 1, Code: string(OpSrcNotAuthorized)},
+		{Operation: 1, Code: string(OpNoTrust)},
+		{Operation: 2, Code: string(OpBadAuth)},
+		{Operation: 2, Code: string(OpSrcNoTrust)},
+		{Operation: 2, Code: string(OpNoTrust)},
+		{Operation: 3, Code: string(OpNoSourceAccount)},
+		{Operation: 4, Code: string(OpBadAuth)},
+		{Operation: 4, Code: string(OpAlreadyExists)},
+		{Operation: 4, Code: string(OpUnderfunded)},
+	}, codes)
+	assert.False(t, report.OK())
+}
@@ -0,0 +1,204 @@
+package horizonclient
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointClass is a class of Horizon endpoints whose responses share the same
+// ResponseLimits.
+type EndpointClass string
+
+const (
+	// EndpointClassDetail is the class of the endpoints returning a single
+	// resource, e.g. an account or a transaction.
+	EndpointClassDetail EndpointClass = "detail"
+	// EndpointClassPage is the class of the endpoints returning a page of
+	// records, paths or an order book.
+	EndpointClassPage EndpointClass = "page"
+	// EndpointClassSubmit is the class of the transaction submission
+	// endpoints.
+	EndpointClassSubmit EndpointClass = "submit"
+	// EndpointClassStream is the class of the streaming endpoints. Its
+	// MaxBodySize limits the size of each event, and its DecodeTimeout is not
+	// used.
+	EndpointClassStream EndpointClass = "stream"
+)
+
+// ResponseLimits bounds the responses of a class of endpoints, so that a
+// misbehaving Horizon cannot make the client run out of memory or hang.
+type ResponseLimits struct {
+	// MaxBodySize is the maximum size of a response body in bytes. Zero means
+	// no limit.
+	MaxBodySize int64
+	// DecodeTimeout is the maximum time reading and decoding a response body
+	// can take, once the response headers are received. Zero means the body
+	// is only bounded by the timeout of the request, see SetHorizonTimeout.
+	DecodeTimeout time.Duration
+}
+
+// DefaultResponseLimits are the limits of the endpoint classes which are not
+// in Client.ResponseLimits. The decode timeouts bound the bodies trickling in
+// once the headers are received, independently of the request timeout.
+var DefaultResponseLimits = map[EndpointClass]ResponseLimits{
+	EndpointClassDetail: {MaxBodySize: 4 << 20, DecodeTimeout: 30 * time.Second},
+	EndpointClassPage:   {MaxBodySize: 32 << 20, DecodeTimeout: 60 * time.Second},
+	EndpointClassSubmit: {MaxBodySize: 4 << 20, DecodeTimeout: 30 * time.Second},
+	EndpointClassStream: {MaxBodySize: 4 << 20},
+}
+
+// ResponseTooLargeError is returned when the body of a response, or an event
+// of a stream, is larger than the MaxBodySize of its endpoint class.
+type ResponseTooLargeError struct {
+	Class       EndpointClass
+	MaxBodySize int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("horizon response exceeds the %d bytes limit of %s endpoints", e.MaxBodySize, e.Class)
+}
+
+// DecodeTimeoutError is returned when reading and decoding the body of a
+// response takes longer than the DecodeTimeout of its endpoint class.
+type DecodeTimeoutError struct {
+	Class         EndpointClass
+	DecodeTimeout time.Duration
+}
+
+func (e *DecodeTimeoutError) Error() string {
+	return fmt.Sprintf("decoding horizon response exceeded the %s timeout of %s endpoints", e.DecodeTimeout, e.Class)
+}
+
+// isResponseLimitError returns true if err is a *ResponseTooLargeError or a
+// *DecodeTimeoutError, which are returned as is rather than wrapped.
+func isResponseLimitError(err error) bool {
+	switch err.(type) {
+	case *ResponseTooLargeError, *DecodeTimeoutError:
+		return true
+	default:
+		return false
+	}
+}
+
+// collectionEndpoints are the last path segments of the endpoints of
+// EndpointClassPage.
+var collectionEndpoints = map[string]bool{
+	"accounts":           true,
+	"assets":             true,
+	"claimable_balances": true,
+	"effects":            true,
+	"ledgers":            true,
+	"liquidity_pools":    true,
+	"offers":             true,
+	"operations":         true,
+	"order_book":         true,
+	"paths":              true,
+	"payments":           true,
+	"strict-receive":     true,
+	"strict-send":        true,
+	"trade_aggregations": true,
+	"trades":             true,
+	"transactions":       true,
+}
+
+// endpointClass returns the class of the endpoint of a request.
+func endpointClass(requestURL string, method string) EndpointClass {
+	if strings.EqualFold(method, "post") {
+		return EndpointClassSubmit
+	}
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return EndpointClassDetail
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if collectionEndpoints[segments[len(segments)-1]] {
+		return EndpointClassPage
+	}
+	return EndpointClassDetail
+}
+
+// responseLimits returns the limits of an endpoint class, from
+// c.ResponseLimits or from DefaultResponseLimits.
+func (c *Client) responseLimits(class EndpointClass) ResponseLimits {
+	if limits, ok := c.ResponseLimits[class]; ok {
+		return limits
+	}
+	return DefaultResponseLimits[class]
+}
+
+// limitedBody is a response body enforcing the ResponseLimits of its endpoint
+// class.
+type limitedBody struct {
+	io.ReadCloser
+	class    EndpointClass
+	limits   ResponseLimits
+	read     int64
+	timer    *time.Timer
+	timedOut int32
+}
+
+// newLimitedBody returns body enforcing limits. The decode timeout starts
+// now, and calls cancel, which must cancel the request, when it expires.
+func newLimitedBody(body io.ReadCloser, class EndpointClass, limits ResponseLimits, cancel func()) *limitedBody {
+	b := &limitedBody{ReadCloser: body, class: class, limits: limits}
+	if limits.DecodeTimeout > 0 {
+		b.timer = time.AfterFunc(limits.DecodeTimeout, func() {
+			atomic.StoreInt32(&b.timedOut, 1)
+			cancel()
+		})
+	}
+	return b
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if max := b.limits.MaxBodySize; max > 0 {
+		if b.read > max {
+			return 0, &ResponseTooLargeError{Class: b.class, MaxBodySize: max}
+		}
+		// Read one byte more than the limit at most, to detect bodies
+		// exceeding it.
+		if remaining := max - b.read + 1; int64(len(p)) > remaining {
+			p = p[:remaining]
+		}
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if max := b.limits.MaxBodySize; max > 0 && b.read > max {
+		return n - 1, &ResponseTooLargeError{Class: b.class, MaxBodySize: max}
+	}
+	if err != nil && err != io.EOF && atomic.LoadInt32(&b.timedOut) == 1 {
+		err = &DecodeTimeoutError{Class: b.class, DecodeTimeout: b.limits.DecodeTimeout}
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	return b.ReadCloser.Close()
+}
+
+// readEventLine reads a line of a stream event like reader.ReadString('\n').
+// If maxEventSize is positive, it returns a *ResponseTooLargeError as soon as
+// the line makes the event, of which eventSize bytes were already read, larger
+// than maxEventSize, without reading the rest of the line.
+func readEventLine(reader *bufio.Reader, eventSize, maxEventSize int64) (string, error) {
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if maxEventSize > 0 && eventSize+int64(len(line)) > maxEventSize {
+			return "", &ResponseTooLargeError{Class: EndpointClassStream, MaxBodySize: maxEventSize}
+		}
+		if err != bufio.ErrBufferFull {
+			return string(line), err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointClass(t *testing.T) {
+	for _, tc := range []struct {
+		url    string
+		method string
+		class  EndpointClass
+	}{
+		{"https://localhost/accounts/GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A", "get", EndpointClassDetail},
+		{"https://localhost/accounts/GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A/operations?limit=200", "get", EndpointClassPage},
+		{"https://localhost/ledgers/1", "get", EndpointClassDetail},
+		{"https://localhost/ledgers?order=desc", "get", EndpointClassPage},
+		{"https://localhost/paths/strict-send?destination_assets=native", "get", EndpointClassPage},
+		{"https://localhost/order_book?selling_asset_type=native", "get", EndpointClassPage},
+		{"https://localhost/fee_stats", "get", EndpointClassDetail},
+		{"https://localhost/", "get", EndpointClassDetail},
+		{"https://localhost/transactions?tx=AAAA", "post", EndpointClassSubmit},
+	} {
+		assert.Equal(t, tc.class, endpointClass(tc.url, tc.method), tc.url)
+	}
+}
+
+func TestResponseLimitsMaxBodySize(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		ResponseLimits: map[EndpointClass]ResponseLimits{
+			EndpointClassDetail: {MaxBodySize: 100},
+		},
+	}
+
+	hmock.On("GET", "https://localhost/ledgers/1").
+		ReturnString(200, `{"sequence":1,"hash":"`+strings.Repeat("a", 100)+`"}`)
+	_, err := client.LedgerDetail(1)
+	assert.Equal(t, &ResponseTooLargeError{Class: EndpointClassDetail, MaxBodySize: 100}, err)
+	assert.EqualError(t, err, "horizon response exceeds the 100 bytes limit of detail endpoints")
+
+	// Error responses are limited too.
+	hmock.On("GET", "https://localhost/ledgers/2").
+		ReturnString(404, `{"type":"not_found","title":"`+strings.Repeat("a", 100)+`"}`)
+	_, err = client.LedgerDetail(2)
+	assert.IsType(t, &ResponseTooLargeError{}, err)
+
+	// A body of exactly the limit is decoded.
+	body := `{"sequence":3,"hash":"` + strings.Repeat("a", 100-len(`{"sequence":3,"hash":""}`)) + `"}`
+	require.Len(t, body, 100)
+	hmock.On("GET", "https://localhost/ledgers/3").ReturnString(200, body)
+	ledger, err := client.LedgerDetail(3)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), ledger.Sequence)
+
+	// The other classes keep their default limits.
+	hmock.On("GET", "https://localhost/ledgers").
+		ReturnString(200, `{"_embedded":{"records":[{"sequence":1,"hash":"`+strings.Repeat("a", 100)+`"}]}}`)
+	ledgers, err := client.Ledgers(LedgerRequest{})
+	require.NoError(t, err)
+	assert.Len(t, ledgers.Embedded.Records, 1)
+}
+
+func TestResponseLimitsDecodeTimeout(t *testing.T) {
+	server := stdhttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"sequence":1,`))
+		w.(http.Flusher).Flush()
+		// Never finish the body.
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HorizonURL: server.URL + "/",
+		ResponseLimits: map[EndpointClass]ResponseLimits{
+			EndpointClassDetail: {DecodeTimeout: 50 * time.Millisecond},
+		},
+	}
+	start := time.Now()
+	_, err := client.LedgerDetail(1)
+	assert.Equal(t, &DecodeTimeoutError{Class: EndpointClassDetail, DecodeTimeout: 50 * time.Millisecond}, err)
+	assert.True(t, time.Since(start) < 10*time.Second)
+}
+
+func TestDefaultResponseLimitsDecodeTimeout(t *testing.T) {
+	for _, class := range []EndpointClass{EndpointClassDetail, EndpointClassPage, EndpointClassSubmit} {
+		assert.True(t, DefaultResponseLimits[class].DecodeTimeout > 0, class)
+	}
+}
+
+func TestResponseLimitsStreamEventSize(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+		ResponseLimits: map[EndpointClass]ResponseLimits{
+			EndpointClassStream: {MaxBodySize: 100},
+		},
+	}
+	hmock.On("GET", "https://localhost/ledgers?cursor=now").
+		ReturnString(200, "data: {\"sequence\":1,\"hash\":\""+strings.Repeat("a", 100)+"\"}\n\n")
+
+	err := client.StreamLedgers(context.Background(), LedgerRequest{}, func(hProtocol.Ledger) {
+		t.Fatal("the event exceeding the limit must not be handled")
+	})
+	assert.Equal(t, &ResponseTooLargeError{Class: EndpointClassStream, MaxBodySize: 100}, err)
+}
@@ -0,0 +1,50 @@
+package horizonclient
+
+// TransactionResultCode is the result code of a transaction, as reported by
+// Horizon in the "result_codes" extra field of a failed submission.
+type TransactionResultCode string
+
+// Transaction result codes reported by Horizon.
+const (
+	TxSuccess             TransactionResultCode = "tx_success"
+	TxFailed              TransactionResultCode = "tx_failed"
+	TxTooEarly            TransactionResultCode = "tx_too_early"
+	TxTooLate             TransactionResultCode = "tx_too_late"
+	TxMissingOperation    TransactionResultCode = "tx_missing_operation"
+	TxBadSeq              TransactionResultCode = "tx_bad_seq"
+	TxBadAuth             TransactionResultCode = "tx_bad_auth"
+	TxInsufficientBalance TransactionResultCode = "tx_insufficient_balance"
+	TxNoSourceAccount     TransactionResultCode = "tx_no_source_account"
+	TxInsufficientFee     TransactionResultCode = "tx_insufficient_fee"
+	TxBadAuthExtra        TransactionResultCode = "tx_bad_auth_extra"
+	TxInternalError       TransactionResultCode = "tx_internal_error"
+	TxNotSupported        TransactionResultCode = "tx_not_supported"
+	TxFeeBumpInnerSuccess TransactionResultCode = "tx_fee_bump_inner_success"
+	TxFeeBumpInnerFailed  TransactionResultCode = "tx_fee_bump_inner_failed"
+	TxBadSponsorship      TransactionResultCode = "tx_bad_sponsorship"
+)
+
+// TransactionResultCode returns the result code of the transaction whose
+// submission failed, from the "result_codes" extra field.
+func (herr *Error) TransactionResultCode() (TransactionResultCode, error) {
+	codes, err := herr.ResultCodes()
+	if err != nil {
+		return "", err
+	}
+	return TransactionResultCode(codes.TransactionCode), nil
+}
+
+// OperationResultCodes returns the result codes of the operations of the
+// transaction whose submission failed, from the "result_codes" extra field.
+// They are only reported when the transaction failed with tx_failed.
+func (herr *Error) OperationResultCodes() ([]OperationResultCode, error) {
+	codes, err := herr.ResultCodes()
+	if err != nil {
+		return nil, err
+	}
+	opCodes := make([]OperationResultCode, len(codes.OperationCodes))
+	for i, code := range codes.OperationCodes {
+		opCodes[i] = OperationResultCode(code)
+	}
+	return opCodes, nil
+}
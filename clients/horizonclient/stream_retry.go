@@ -0,0 +1,51 @@
+package horizonclient
+
+import (
+	"math/rand"
+	"time"
+)
+
+// StreamRetryPolicy configures how streams reconnect after errors, such as
+// Horizon restarting or the network being unavailable. Reconnections wait
+// with exponential backoff and jitter, and resume from the cursor of the last
+// event received so no events are lost or repeated.
+type StreamRetryPolicy struct {
+	// MaxRetries is the number of consecutive failed connection attempts
+	// after which the stream gives up and returns the error. The count is
+	// reset once events are received. Negative means retry forever.
+	MaxRetries int
+
+	// InitialBackoff is the time waited before the first retry, doubling
+	// with each subsequent retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff is the maximum time waited between retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultStreamRetryPolicy retries forever, waiting from one second up to one
+// minute between attempts.
+var DefaultStreamRetryPolicy = &StreamRetryPolicy{
+	MaxRetries:     -1,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+}
+
+// backoff returns the time to wait before the retry following the given
+// number of consecutive failures. The wait is randomly chosen between half and
+// all of the exponential backoff so that clients disconnected at the same time
+// do not reconnect at the same time.
+func (p *StreamRetryPolicy) backoff(failures int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < failures && (p.MaxBackoff <= 0 || backoff < p.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
@@ -0,0 +1,105 @@
+package horizonclient
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRetryPolicyBackoff(t *testing.T) {
+	p := &StreamRetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for i := 0; i < 100; i++ {
+		b := p.backoff(1)
+		assert.True(t, b >= 500*time.Millisecond && b <= time.Second, b)
+		b = p.backoff(2)
+		assert.True(t, b >= time.Second && b <= 2*time.Second, b)
+		b = p.backoff(10)
+		assert.True(t, b >= 2500*time.Millisecond && b <= 5*time.Second, b)
+	}
+}
+
+func TestStreamLedgersRetriesAndResumesFromCursor(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:        "https://localhost/",
+		HTTP:              hmock,
+		StreamRetryPolicy: &StreamRetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The stream resumes from the id of the last event read. httpmock bodies
+	// rewind at EOF, so the first stream is closed with a plain reader.
+	hmock.On("GET", "https://localhost/ledgers?cursor=1").
+		Return(func(*http.Request) (*http.Response, error) {
+			body := "id: 2406637679673344\n" + ledgerStreamResponse
+			return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		})
+	resumed := 0
+	hmock.On("GET", "https://localhost/ledgers?cursor=2406637679673344").
+		Return(func(*http.Request) (*http.Response, error) {
+			resumed++
+			if resumed == 1 {
+				return httpmock.NewStringResponse(503, ""), nil
+			}
+			return httpmock.NewStringResponse(200, ledgerStreamResponse), nil
+		})
+
+	ledgers := []hProtocol.Ledger{}
+	err := client.StreamLedgers(ctx, LedgerRequest{Cursor: "1"}, func(ledger hProtocol.Ledger) {
+		ledgers = append(ledgers, ledger)
+		if len(ledgers) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	assert.Len(t, ledgers, 2)
+	assert.Equal(t, 2, resumed)
+}
+
+func TestStreamLedgersGivesUpAfterMaxRetries(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:        "https://localhost/",
+		HTTP:              hmock,
+		StreamRetryPolicy: &StreamRetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond},
+	}
+
+	requests := 0
+	hmock.On("GET", "https://localhost/ledgers?cursor=now").
+		Return(func(*http.Request) (*http.Response, error) {
+			requests++
+			return httpmock.NewStringResponse(500, ""), nil
+		})
+
+	err := client.StreamLedgers(context.Background(), LedgerRequest{}, func(ledger hProtocol.Ledger) {})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "giving up after 2 retries")
+	assert.Contains(t, err.Error(), "got bad HTTP status code 500")
+	assert.Equal(t, 3, requests)
+}
+
+func TestStreamLedgersDoesNotRetryClientErrors(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:        "https://localhost/",
+		HTTP:              hmock,
+		StreamRetryPolicy: &StreamRetryPolicy{MaxRetries: -1, InitialBackoff: time.Millisecond},
+	}
+
+	hmock.On("GET", "https://localhost/ledgers?cursor=now").
+		ReturnString(400, "")
+
+	err := client.StreamLedgers(context.Background(), LedgerRequest{}, func(ledger hProtocol.Ledger) {})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "got bad HTTP status code 400")
+}
@@ -0,0 +1,157 @@
+package horizonclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+)
+
+// tradeFeedBackfillLimit is the page size of the trades fetched when a trade
+// feed reconnects.
+const tradeFeedBackfillLimit = 200
+
+// FeedTrade is a trade delivered by StreamTradeFeed, annotated with the ledger
+// it was executed in.
+type FeedTrade struct {
+	hProtocol.Trade
+
+	// LedgerSequence is the sequence of the ledger the trade was executed
+	// in, decoded from its paging token.
+	LedgerSequence int32
+
+	// Backfilled is true for trades fetched from the trades endpoint after
+	// the stream reconnected, rather than received from the stream.
+	Backfilled bool
+}
+
+// FeedTradeHandler is a function that is called with each trade of a trade
+// feed.
+type FeedTradeHandler func(FeedTrade)
+
+// StreamTradeFeed streams executed trades like StreamTrades, delivering each
+// trade exactly once and in order, for bots which must not miss a trade.
+//
+// A reconnection, after an error when StreamRetryPolicy is set or after
+// Horizon closed the stream, is treated as a potential gap: the trades
+// executed after the last trade delivered are fetched from the trades
+// endpoint, and the stream resumes after them. If they cannot be fetched the
+// stream resumes from the last trade delivered. Trades repeated by the stream
+// are dropped.
+//
+// Each trade is annotated with the sequence of its ledger, and its
+// LedgerCloseTime is filled in from the ledger if Horizon did not report it.
+// A cursor of "now", or no cursor, starts the feed after the latest ledger.
+func (c *Client) StreamTradeFeed(ctx context.Context, request TradeRequest, handler FeedTradeHandler) error {
+	if request.Order == OrderDesc {
+		return errors.New("trade feed must be in ascending order")
+	}
+
+	cursor := request.Cursor
+	if cursor == "" || cursor == "now" {
+		latest, err := c.Ledgers(LedgerRequest{Order: OrderDesc, Limit: 1})
+		if err != nil {
+			return errors.Wrap(err, "getting latest ledger")
+		}
+		if len(latest.Embedded.Records) == 0 {
+			return errors.New("no ledgers found")
+		}
+		cursor = AfterLedgerPagingToken(latest.Embedded.Records[0].Sequence).String()
+	}
+	last, err := ParsePagingToken(cursor)
+	if err != nil {
+		return errors.Wrap(err, "invalid cursor")
+	}
+
+	feed := &tradeFeed{client: c, request: request, handler: handler, last: last}
+	feed.request.Cursor = cursor
+	feed.request.Order = OrderAsc
+	endpoint, err := feed.request.BuildURL()
+	if err != nil {
+		return errors.Wrap(err, "unable to build endpoint")
+	}
+
+	url := fmt.Sprintf("%s%s", c.fixHorizonURL(), endpoint)
+	return c.resumableStream(ctx, url, feed.receive, feed.backfill)
+}
+
+// tradeFeed is the state of a StreamTradeFeed call.
+type tradeFeed struct {
+	client  *Client
+	request TradeRequest
+	handler FeedTradeHandler
+
+	// last is the paging token of the last trade delivered, or the cursor
+	// the feed started from.
+	last PagingToken
+
+	// closeTimeLedger is the sequence of the ledger whose close time was
+	// last fetched.
+	closeTimeLedger int32
+	closeTime       time.Time
+}
+
+// receive delivers a trade received from the stream.
+func (f *tradeFeed) receive(data []byte) error {
+	var trade hProtocol.Trade
+	err := json.Unmarshal(data, &trade)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshaling data")
+	}
+	return f.deliver(trade, false)
+}
+
+// deliver passes trade to the handler, unless it was already delivered.
+func (f *tradeFeed) deliver(trade hProtocol.Trade, backfilled bool) error {
+	pt, err := ParsePagingToken(trade.PT)
+	if err != nil {
+		return err
+	}
+	if pt.Compare(f.last) <= 0 {
+		return nil
+	}
+
+	if trade.LedgerCloseTime.IsZero() {
+		if pt.LedgerSequence != f.closeTimeLedger {
+			ledger, err := f.client.LedgerDetail(uint32(pt.LedgerSequence))
+			if err != nil {
+				return errors.Wrap(err, "getting ledger of trade")
+			}
+			f.closeTimeLedger, f.closeTime = pt.LedgerSequence, ledger.ClosedAt
+		}
+		trade.LedgerCloseTime = f.closeTime
+	}
+
+	f.last = pt
+	f.handler(FeedTrade{Trade: trade, LedgerSequence: pt.LedgerSequence, Backfilled: backfilled})
+	return nil
+}
+
+// backfill delivers the trades executed after the last trade delivered, and
+// returns the cursor the stream resumes from.
+func (f *tradeFeed) backfill(string) (string, error) {
+	request := f.request
+	request.Limit = tradeFeedBackfillLimit
+	for {
+		request.Cursor = f.last.String()
+		page, err := f.client.Trades(request)
+		if err != nil {
+			// The stream resumes from the last trade delivered and is
+			// backfilled after its next reconnection.
+			break
+		}
+		for _, trade := range page.Embedded.Records {
+			err = f.deliver(trade, true)
+			if err != nil {
+				return "", err
+			}
+		}
+		if len(page.Embedded.Records) < tradeFeedBackfillLimit {
+			break
+		}
+	}
+	return f.last.String(), nil
+}
@@ -0,0 +1,125 @@
+package horizonclient
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feedTradeJSON(pt, closeTime string) string {
+	trade := fmt.Sprintf(`{"id":"%s","paging_token":"%s","base_amount":"1.0000000"`, pt, pt)
+	if closeTime != "" {
+		trade += fmt.Sprintf(`,"ledger_close_time":"%s"`, closeTime)
+	}
+	return trade + "}"
+}
+
+func feedTradeEvent(pt, closeTime string) string {
+	return fmt.Sprintf("id: %s\ndata: %s\n\n", pt, feedTradeJSON(pt, closeTime))
+}
+
+func TestStreamTradeFeedBackfillsAfterReconnect(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ledger 5 starts at paging token 21474836480 and ledger 6 at
+	// 25769803776.
+	hmock.On("GET", "https://localhost/trades?cursor=21474836481&order=asc").
+		Return(func(*http.Request) (*http.Response, error) {
+			body := feedTradeEvent("21474836481-0", "2021-06-01T10:00:00Z")
+			return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(strings.NewReader(body))}, nil
+		})
+	hmock.On("GET", "https://localhost/trades?cursor=21474836481-0&limit=200&order=asc").
+		ReturnString(200, `{"_embedded":{"records":[`+
+			feedTradeJSON("21474836481-0", "2021-06-01T10:00:00Z")+`,`+
+			feedTradeJSON("21474836481-1", "2021-06-01T10:00:00Z")+`,`+
+			feedTradeJSON("25769803777-0", "")+`]}}`)
+	hmock.On("GET", "https://localhost/ledgers/6").
+		ReturnString(200, `{"sequence":6,"closed_at":"2021-06-01T10:00:05Z"}`)
+	hmock.On("GET", "https://localhost/trades?cursor=25769803777-0&order=asc").
+		ReturnString(200, feedTradeEvent("25769803777-0", "")+feedTradeEvent("25769803778-0", "2021-06-01T10:00:05Z"))
+
+	trades := []FeedTrade{}
+	err := client.StreamTradeFeed(ctx, TradeRequest{Cursor: "21474836481"}, func(trade FeedTrade) {
+		trades = append(trades, trade)
+		if len(trades) == 4 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	require.Len(t, trades, 4)
+
+	closeTime5 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	closeTime6 := time.Date(2021, 6, 1, 10, 0, 5, 0, time.UTC)
+	for i, expected := range []struct {
+		pt         string
+		ledger     int32
+		closeTime  time.Time
+		backfilled bool
+	}{
+		{"21474836481-0", 5, closeTime5, false},
+		{"21474836481-1", 5, closeTime5, true},
+		{"25769803777-0", 6, closeTime6, true},
+		{"25769803778-0", 6, closeTime6, false},
+	} {
+		assert.Equal(t, expected.pt, trades[i].PT)
+		assert.Equal(t, expected.ledger, trades[i].LedgerSequence)
+		assert.True(t, expected.closeTime.Equal(trades[i].LedgerCloseTime), trades[i].LedgerCloseTime)
+		assert.Equal(t, expected.backfilled, trades[i].Backfilled)
+	}
+}
+
+func TestStreamTradeFeedStartsAfterLatestLedger(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{HorizonURL: "https://localhost/", HTTP: hmock}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hmock.On("GET", "https://localhost/ledgers?limit=1&order=desc").
+		ReturnString(200, `{"_embedded":{"records":[{"sequence":5}]}}`)
+	// The stream repeats a trade of the latest ledger, which is dropped.
+	hmock.On("GET", "https://localhost/trades?cursor=25769803775&order=asc").
+		ReturnString(200, feedTradeEvent("21474836481-0", "2021-06-01T10:00:00Z")+feedTradeEvent("25769803777-0", "2021-06-01T10:00:05Z"))
+
+	trades := []FeedTrade{}
+	err := client.StreamTradeFeed(ctx, TradeRequest{}, func(trade FeedTrade) {
+		trades = append(trades, trade)
+		cancel()
+	})
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	assert.Equal(t, "25769803777-0", trades[0].PT)
+}
+
+func TestStreamTradeFeedResumesWhenBackfillFails(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:        "https://localhost/",
+		HTTP:              hmock,
+		StreamRetryPolicy: &StreamRetryPolicy{MaxRetries: 1, InitialBackoff: time.Millisecond},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hmock.On("GET", "https://localhost/trades?cursor=21474836481&order=asc").
+		ReturnString(503, "")
+	hmock.On("GET", "https://localhost/trades?cursor=21474836481&limit=200&order=asc").
+		ReturnString(503, "")
+
+	err := client.StreamTradeFeed(ctx, TradeRequest{Cursor: "21474836481"}, func(trade FeedTrade) {})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "giving up after 1 retries")
+
+	err = client.StreamTradeFeed(ctx, TradeRequest{Order: OrderDesc}, func(trade FeedTrade) {})
+	assert.EqualError(t, err, "trade feed must be in ascending order")
+}
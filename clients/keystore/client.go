@@ -0,0 +1,180 @@
+package keystore
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+type keysResponse struct {
+	KeysBlob   string     `json:"keysBlob"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ModifiedAt *time.Time `json:"modifiedAt,omitempty"`
+}
+
+type putKeysRequest struct {
+	KeysBlob string `json:"keysBlob"`
+}
+
+// PutKeys stores the encrypted keys, replacing any keys stored before.
+func (c *Client) PutKeys(ctx context.Context, keys []EncryptedKey) (*KeysData, error) {
+	keysJSON, err := json.Marshal(keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding keys")
+	}
+	body, err := json.Marshal(putKeysRequest{
+		KeysBlob: base64.RawURLEncoding.EncodeToString(keysJSON),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding request")
+	}
+
+	resp := keysResponse{}
+	err = c.do(ctx, http.MethodPut, body, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.keysData()
+}
+
+// GetKeys returns the encrypted keys stored for the user.
+func (c *Client) GetKeys(ctx context.Context) (*KeysData, error) {
+	resp := keysResponse{}
+	err := c.do(ctx, http.MethodGet, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.keysData()
+}
+
+// DeleteKeys deletes the encrypted keys stored for the user.
+func (c *Client) DeleteKeys(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, nil, nil)
+}
+
+func (r keysResponse) keysData() (*KeysData, error) {
+	keysJSON, err := base64.RawURLEncoding.DecodeString(r.KeysBlob)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding keys blob")
+	}
+	keys := []EncryptedKey{}
+	err = json.Unmarshal(keysJSON, &keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding keys")
+	}
+	return &KeysData{
+		EncryptedKeys: keys,
+		CreatedAt:     r.CreatedAt,
+		ModifiedAt:    r.ModifiedAt,
+	}, nil
+}
+
+// do sends the request to the keys endpoint, retrying it on network errors
+// and 5xx responses, and decodes the response into out if it is not nil.
+func (c *Client) do(ctx context.Context, method string, body []byte, out interface{}) error {
+	wait := c.RetryWait
+	if wait == 0 {
+		wait = DefaultRetryWait
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = c.doOnce(ctx, method, body, out)
+		if !retry || attempt >= c.MaxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "waiting to retry request")
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// doOnce sends the request once and reports whether it should be retried.
+func (c *Client) doOnce(ctx context.Context, method string, body []byte, out interface{}) (bool, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, c.url(), bodyReader)
+	if err != nil {
+		return false, errors.Wrap(err, "building request")
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, errors.Wrap(err, "http request errored")
+	}
+	defer resp.Body.Close()
+
+	limitReader := io.LimitReader(resp.Body, KeysResponseMaxSize)
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		kerr := &Error{Response: resp}
+		decodeErr := json.NewDecoder(limitReader).Decode(&kerr.Problem)
+		if decodeErr != nil {
+			kerr.Problem.Status = resp.StatusCode
+			kerr.Problem.Title = http.StatusText(resp.StatusCode)
+		}
+		return resp.StatusCode >= 500, kerr
+	}
+
+	if out == nil {
+		return false, nil
+	}
+	err = json.NewDecoder(limitReader).Decode(out)
+	if err != nil {
+		return false, errors.Wrap(err, "decoding response")
+	}
+	return false, nil
+}
+
+func (c *Client) url() string {
+	return strings.TrimSuffix(c.URL, "/") + "/keys"
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("keystore error: %q (%s) - check keystore.Error.Problem for more information", e.Problem.Title, e.Problem.Type)
+}
+
+// IsNotAuthorizedError returns true if the error is a keystore error
+// indicating the AuthToken was not accepted.
+func IsNotAuthorizedError(err error) bool {
+	kerr, ok := errors.Cause(err).(*Error)
+	return ok && kerr.Problem.Status == http.StatusUnauthorized
+}
+
+// IsNotFoundError returns true if the error is a keystore error indicating
+// no keys are stored for the user.
+func IsNotFoundError(err error) bool {
+	kerr, ok := errors.Cause(err).(*Error)
+	return ok && kerr.Problem.Status == http.StatusNotFound
+}
+
+// IsInvalidRequestError returns true if the error is a keystore error
+// indicating the request, such as the keys being stored, was invalid.
+func IsInvalidRequestError(err error) bool {
+	kerr, ok := errors.Cause(err).(*Error)
+	return ok && kerr.Problem.Status == http.StatusBadRequest
+}
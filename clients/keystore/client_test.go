@@ -0,0 +1,116 @@
+package keystore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutKeys(t *testing.T) {
+	keys := []EncryptedKey{{
+		ID:            "id",
+		Salt:          "salt",
+		EncrypterName: "encrypter",
+		EncryptedBlob: "blob",
+	}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/keys", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		req := putKeysRequest{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		keysJSON, err := base64.RawURLEncoding.DecodeString(req.KeysBlob)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"id":"id","salt":"salt","encrypterName":"encrypter","encryptedBlob":"blob"}]`, string(keysJSON))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"keysBlob":"` + req.KeysBlob + `","createdAt":"2019-07-10T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	c := &Client{URL: server.URL + "/", AuthToken: "token"}
+	data, err := c.PutKeys(context.Background(), keys)
+	require.NoError(t, err)
+	assert.Equal(t, keys, data.EncryptedKeys)
+	assert.Equal(t, time.Date(2019, 7, 10, 0, 0, 0, 0, time.UTC), data.CreatedAt)
+	assert.Nil(t, data.ModifiedAt)
+}
+
+func TestGetKeys_notFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"type":"not_found","title":"Resource Missing","status":404}`))
+	}))
+	defer server.Close()
+
+	c := &Client{URL: server.URL, MaxRetries: 3}
+	_, err := c.GetKeys(context.Background())
+	require.Error(t, err)
+	assert.True(t, IsNotFoundError(err))
+	assert.False(t, IsNotAuthorizedError(err))
+	assert.EqualError(t, err, `keystore error: "Resource Missing" (not_found) - check keystore.Error.Problem for more information`)
+}
+
+func TestDeleteKeys_retriesServerErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := &Client{URL: server.URL, MaxRetries: 2, RetryWait: time.Millisecond}
+	err := c.DeleteKeys(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+}
+
+func TestDeleteKeys_retriesExhausted(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := &Client{URL: server.URL, MaxRetries: 1, RetryWait: time.Millisecond}
+	err := c.DeleteKeys(context.Background())
+	require.Error(t, err)
+	kerr, ok := err.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, kerr.Problem.Status)
+	assert.Equal(t, 2, requests)
+}
+
+func TestGetKeys_doesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"type":"not_authorized","title":"Not Authorized","status":401}`))
+	}))
+	defer server.Close()
+
+	c := &Client{URL: server.URL, MaxRetries: 3, RetryWait: time.Millisecond}
+	_, err := c.GetKeys(context.Background())
+	assert.True(t, IsNotAuthorizedError(err))
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,77 @@
+// Package keystore provides a client for the keystore service, which stores
+// the encrypted keys of non-custodial wallets, see services/keystore.
+package keystore
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/support/render/problem"
+)
+
+// KeysResponseMaxSize is the maximum size of a response from the keystore.
+const KeysResponseMaxSize = 1024 * 1024
+
+// HTTP represents the http client that the keystore client uses to make http
+// requests.
+type HTTP interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client represents a client of a keystore server. Requests are
+// authenticated as the user identified by the AuthToken, which the keystore
+// forwards to the application's auth endpoint.
+type Client struct {
+	// URL is the base URL of the keystore server.
+	URL string
+
+	// HTTP is the http client used to make requests, http.DefaultClient if
+	// nil.
+	HTTP HTTP
+
+	// AuthToken is sent to the keystore as a bearer token in the
+	// Authorization header.
+	AuthToken string
+
+	// MaxRetries is the number of times a request is retried after a network
+	// error or a 5xx response. All keystore requests are idempotent.
+	MaxRetries int
+
+	// RetryWait is the time waited before the first retry, doubling with
+	// each subsequent retry. Defaults to DefaultRetryWait.
+	RetryWait time.Duration
+}
+
+// DefaultRetryWait is the time waited before the first retry of a request.
+const DefaultRetryWait = 500 * time.Millisecond
+
+type ClientInterface interface {
+	PutKeys(ctx context.Context, keys []EncryptedKey) (*KeysData, error)
+	GetKeys(ctx context.Context) (*KeysData, error)
+	DeleteKeys(ctx context.Context) error
+}
+
+// EncryptedKey is a key encrypted by the wallet before it is stored in the
+// keystore.
+type EncryptedKey struct {
+	ID            string `json:"id"`
+	Salt          string `json:"salt"`
+	EncrypterName string `json:"encrypterName"`
+	EncryptedBlob string `json:"encryptedBlob"`
+}
+
+// KeysData is the blob of encrypted keys stored for a user.
+type KeysData struct {
+	EncryptedKeys []EncryptedKey
+	CreatedAt     time.Time
+	ModifiedAt    *time.Time
+}
+
+// Error is returned when the keystore responds with a problem.
+type Error struct {
+	Response *http.Response
+	Problem  problem.P
+}
+
+var _ ClientInterface = &Client{}
@@ -27,29 +27,45 @@ type Client struct {
 	URL string
 }
 
+// UpgradeParameters are the network upgrades set with the `upgrades` command,
+// which stellar-core votes for when closing ledgers. Zero values leave the
+// corresponding network setting unchanged.
+type UpgradeParameters struct {
+	// ProtocolVersion is the ledger protocol version to upgrade to.
+	ProtocolVersion uint32
+	// BaseFee is the base fee to upgrade to, in stroops.
+	BaseFee uint32
+	// BaseReserve is the base reserve to upgrade to, in stroops.
+	BaseReserve uint32
+	// MaxTxSetSize is the maximum transaction set size to upgrade to.
+	MaxTxSetSize uint32
+}
+
 // Upgrade upgrades the protocol version running on the stellar core instance
 func (c *Client) Upgrade(ctx context.Context, version int) error {
-	queryParams := url.Values{}
-	queryParams.Add("mode", "set")
-	queryParams.Add("upgradetime", "1970-01-01T00:00:00Z")
-	queryParams.Add("protocolversion", strconv.Itoa(version))
+	return c.SetUpgrades(ctx, UpgradeParameters{ProtocolVersion: uint32(version)})
+}
 
-	req, err := c.simpleGet(ctx, "upgrades", queryParams)
-	if err != nil {
-		return errors.Wrap(err, "failed to create request")
+// SetUpgrades calls the `upgrades` command on the connected stellar-core,
+// which then votes for the given upgrades when closing the following ledgers.
+func (c *Client) SetUpgrades(ctx context.Context, params UpgradeParameters) error {
+	q := url.Values{}
+	q.Set("mode", "set")
+	q.Set("upgradetime", "1970-01-01T00:00:00Z")
+	if params.ProtocolVersion != 0 {
+		q.Set("protocolversion", strconv.FormatUint(uint64(params.ProtocolVersion), 10))
 	}
-
-	hresp, err := c.http().Do(req)
-	if err != nil {
-		return errors.Wrap(err, "http request errored")
+	if params.BaseFee != 0 {
+		q.Set("basefee", strconv.FormatUint(uint64(params.BaseFee), 10))
 	}
-	defer hresp.Body.Close()
-
-	if !(hresp.StatusCode >= 200 && hresp.StatusCode < 300) {
-		return errors.New("http request failed with non-200 status code")
+	if params.BaseReserve != 0 {
+		q.Set("basereserve", strconv.FormatUint(uint64(params.BaseReserve), 10))
+	}
+	if params.MaxTxSetSize != 0 {
+		q.Set("maxtxsetsize", strconv.FormatUint(uint64(params.MaxTxSetSize), 10))
 	}
 
-	return nil
+	return c.simpleCommand(ctx, "upgrades", q)
 }
 
 // Info calls the `info` command on the connected stellar core and returns the
@@ -205,6 +221,95 @@ func (c *Client) ManualClose(ctx context.Context) (err error) {
 	return
 }
 
+// ManualCloseAt closes the given ledger with the given close time when Core
+// is running standalone in `MANUAL_CLOSE` mode, e.g. to close ledgers with the
+// close time they had on the network.
+func (c *Client) ManualCloseAt(ctx context.Context, ledgerSeq uint32, closeTime time.Time) error {
+	q := url.Values{}
+	q.Set("ledgerSeq", strconv.FormatUint(uint64(ledgerSeq), 10))
+	q.Set("closeTime", strconv.FormatInt(closeTime.Unix(), 10))
+
+	return c.simpleCommand(ctx, "manualclose", q)
+}
+
+// SurveyTopology calls the `surveytopology` command on the connected
+// stellar-core, adding the node to the survey backlog. The survey is started
+// if it is not running, and lasts for the given duration.
+func (c *Client) SurveyTopology(ctx context.Context, nodeID string, duration time.Duration) error {
+	q := url.Values{}
+	q.Set("node", nodeID)
+	q.Set("duration", strconv.Itoa(int(duration.Seconds())))
+
+	return c.simpleCommand(ctx, "surveytopology", q)
+}
+
+// GetSurveyResult calls the `getsurveyresult` command on the connected
+// stellar-core and returns the responses collected by the current survey.
+func (c *Client) GetSurveyResult(ctx context.Context) (resp *proto.GetSurveyResultResponse, err error) {
+	req, err := c.simpleGet(ctx, "getsurveyresult", nil)
+	if err != nil {
+		err = errors.Wrap(err, "failed to create request")
+		return
+	}
+
+	hresp, err := c.http().Do(req)
+	if err != nil {
+		err = errors.Wrap(err, "http request errored")
+		return
+	}
+	defer hresp.Body.Close()
+
+	if !(hresp.StatusCode >= 200 && hresp.StatusCode < 300) {
+		err = errors.New("http request failed with non-200 status code")
+		return
+	}
+
+	err = json.NewDecoder(hresp.Body).Decode(&resp)
+	if err != nil {
+		err = errors.Wrap(err, "json decode failed")
+		return
+	}
+
+	return
+}
+
+// StopSurvey calls the `stopsurvey` command on the connected stellar-core.
+func (c *Client) StopSurvey(ctx context.Context) error {
+	return c.simpleCommand(ctx, "stopsurvey", nil)
+}
+
+// simpleCommand calls a command whose response is either a plain text
+// message or a json object with an exception.
+func (c *Client) simpleCommand(ctx context.Context, command string, query url.Values) error {
+	req, err := c.simpleGet(ctx, command, query)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	hresp, err := c.http().Do(req)
+	if err != nil {
+		return errors.Wrap(err, "http request errored")
+	}
+	defer hresp.Body.Close()
+
+	if !(hresp.StatusCode >= 200 && hresp.StatusCode < 300) {
+		return errors.New("http request failed with non-200 status code")
+	}
+
+	// verify there wasn't an exception
+	resp := struct {
+		Exception string `json:"exception"`
+	}{}
+	if decErr := json.NewDecoder(hresp.Body).Decode(&resp); decErr != nil {
+		return nil
+	}
+	if resp.Exception != "" {
+		return fmt.Errorf("exception in response: %s", resp.Exception)
+	}
+
+	return nil
+}
+
 func (c *Client) http() HTTP {
 	if c.HTTP == nil {
 		return http.DefaultClient
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	proto "github.com/stellar/go/protocols/stellarcore"
 	"github.com/stellar/go/support/http/httptest"
@@ -54,3 +55,27 @@ func TestManualClose_NotAvailable(t *testing.T) {
 
 	assert.EqualError(t, err, "exception in response: Set MANUAL_CLOSE=true")
 }
+
+func TestManualCloseAt(t *testing.T) {
+	hmock := httptest.NewClient()
+	c := &Client{HTTP: hmock, URL: "http://localhost:11626"}
+
+	hmock.On("GET", "http://localhost:11626/manualclose?closeTime=1577836800&ledgerSeq=7").
+		ReturnString(http.StatusOK, "Manually triggered a ledger close with sequence number 7")
+
+	err := c.ManualCloseAt(context.Background(), 7, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.NoError(t, err)
+}
+
+func TestSetUpgrades(t *testing.T) {
+	hmock := httptest.NewClient()
+	c := &Client{HTTP: hmock, URL: "http://localhost:11626"}
+
+	hmock.On("GET", "http://localhost:11626/upgrades?basefee=200&mode=set&protocolversion=17&upgradetime=1970-01-01T00%3A00%3A00Z").
+		ReturnString(http.StatusOK, "")
+
+	err := c.SetUpgrades(context.Background(), UpgradeParameters{ProtocolVersion: 17, BaseFee: 200})
+
+	assert.NoError(t, err)
+}
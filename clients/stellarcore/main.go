@@ -1,5 +1,7 @@
 // Package stellarcore is a client library for communicating with an
-// instance of stellar-core using through the server's HTTP port.
+// instance of stellar-core using through the server's HTTP port. It also
+// provides Process, which manages the lifecycle of a stellar-core subprocess
+// so that services can embed a captive stellar-core.
 package stellarcore
 
 import "net/http"
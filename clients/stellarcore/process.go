@@ -0,0 +1,459 @@
+package stellarcore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// ProcessMode is the mode in which a stellar-core subprocess is run.
+type ProcessMode int
+
+const (
+	// ProcessModeOnline runs stellar-core connected to the network, with
+	// `stellar-core run`.
+	ProcessModeOnline ProcessMode = iota
+	// ProcessModeOffline replays a range of ledgers from the history
+	// archives, with `stellar-core catchup`, and exits.
+	ProcessModeOffline
+)
+
+// ProcessState is the state of a stellar-core subprocess managed by a
+// Process.
+type ProcessState string
+
+const (
+	// ProcessStateIdle is the state of a Process which was not started.
+	ProcessStateIdle ProcessState = "idle"
+	// ProcessStateRunning is the state of a Process whose subprocess is
+	// running.
+	ProcessStateRunning ProcessState = "running"
+	// ProcessStateRestarting is the state of a Process waiting to restart
+	// its subprocess, after it exited or was found unhealthy.
+	ProcessStateRestarting ProcessState = "restarting"
+	// ProcessStateExited is the state of a Process which was stopped, whose
+	// catchup completed, or which exhausted its restarts.
+	ProcessStateExited ProcessState = "exited"
+)
+
+// RestartPolicy configures the restarts of a stellar-core subprocess which
+// exited unexpectedly or was found unhealthy.
+type RestartPolicy struct {
+	// MaxRestarts is the maximum number of consecutive restarts. Zero
+	// disables restarts.
+	MaxRestarts int
+	// Backoff is the delay before the first restart, doubled for each
+	// consecutive restart up to MaxBackoff.
+	Backoff time.Duration
+	// MaxBackoff is the maximum delay between restarts. Zero means no
+	// maximum.
+	MaxBackoff time.Duration
+	// ResetAfter resets the count of consecutive restarts once a subprocess
+	// ran for that long. Zero never resets it.
+	ResetAfter time.Duration
+}
+
+func (p RestartPolicy) backoff(restarts int) time.Duration {
+	backoff := p.Backoff
+	for i := 1; i < restarts; i++ {
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			break
+		}
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// ProcessConfig configures a Process.
+type ProcessConfig struct {
+	// BinaryPath is the path of the stellar-core executable.
+	BinaryPath string
+	// ConfigPath is the path of the stellar-core configuration file.
+	ConfigPath string
+	// Dir is the working directory of the subprocess. If empty, the
+	// working directory of the current process is used.
+	Dir string
+	// Env is the environment of the subprocess. If nil, the environment of
+	// the current process is used.
+	Env []string
+	// Stdout and Stderr receive the output of the subprocess. If nil, it is
+	// discarded.
+	Stdout io.Writer
+	Stderr io.Writer
+	// ExtraFiles are passed to the subprocess as file descriptors 3 and
+	// following, e.g. the write end of a pipe for the
+	// --metadata-output-stream of captive core.
+	ExtraFiles []*os.File
+	// Log receives the lifecycle events of the subprocess. If nil,
+	// log.DefaultLogger is used.
+	Log *log.Entry
+
+	// ShutdownTimeout is how long a subprocess is given to exit after being
+	// interrupted, before it is killed. Zero kills it immediately.
+	ShutdownTimeout time.Duration
+	// Restart is the restart policy of the subprocess.
+	Restart RestartPolicy
+
+	// Client is used to probe the health of online subprocesses through
+	// their HTTP port. If nil, the health is not probed.
+	Client *Client
+	// HealthCheckInterval is the interval between health probes. It defaults
+	// to 10 seconds.
+	HealthCheckInterval time.Duration
+	// HealthCheckGracePeriod is the delay after starting a subprocess before
+	// it is first probed, to give it time to open its HTTP port.
+	HealthCheckGracePeriod time.Duration
+	// UnhealthyThreshold is the number of consecutive failed probes after
+	// which a subprocess is restarted. Zero never restarts it because of
+	// failed probes.
+	UnhealthyThreshold int
+}
+
+// ProcessStatus is a snapshot of the status of a Process.
+type ProcessStatus struct {
+	Mode  ProcessMode
+	State ProcessState
+	// PID is the process id of the running subprocess, zero if none.
+	PID int
+	// Restarts is the number of consecutive restarts of the subprocess.
+	Restarts int
+	// LastExitError is the error the last subprocess exited with, if any.
+	LastExitError error
+	// Info is the response of the last successful health probe, nil if
+	// there was none since the subprocess was started.
+	Info *proto.InfoResponse
+	// ProbeFailures is the number of consecutive failed health probes.
+	ProbeFailures int
+	// LastProbeError is the error of the last failed health probe, if any.
+	LastProbeError error
+}
+
+// Healthy returns true if the subprocess is running and answered the last
+// health probe.
+func (s ProcessStatus) Healthy() bool {
+	return s.State == ProcessStateRunning && s.Info != nil && s.ProbeFailures == 0
+}
+
+// Synced returns true if the subprocess is healthy and synced with the
+// network.
+func (s ProcessStatus) Synced() bool {
+	return s.Healthy() && s.Info.IsSynced()
+}
+
+// Process manages the lifecycle of a stellar-core subprocess: it launches it
+// in online or offline mode, probes its health, restarts it according to its
+// restart policy and stops it gracefully. A Process runs a single
+// subprocess at a time and cannot be started again once it exited.
+type Process struct {
+	config ProcessConfig
+
+	lock    sync.Mutex
+	started bool
+	status  ProcessStatus
+	cancel  context.CancelFunc
+	done    chan struct{}
+	err     error
+}
+
+// NewProcess returns a Process managing stellar-core subprocesses with the
+// given configuration.
+func NewProcess(config ProcessConfig) (*Process, error) {
+	if config.BinaryPath == "" {
+		return nil, errors.New("stellar-core binary path is required")
+	}
+	if config.ConfigPath == "" {
+		return nil, errors.New("stellar-core configuration path is required")
+	}
+	if config.Log == nil {
+		config.Log = log.DefaultLogger
+	}
+	if config.HealthCheckInterval == 0 {
+		config.HealthCheckInterval = 10 * time.Second
+	}
+	return &Process{
+		config: config,
+		status: ProcessStatus{State: ProcessStateIdle},
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Run starts stellar-core in online mode, with `stellar-core run` followed
+// by args. The subprocess is restarted according to the restart policy
+// whenever it exits, until Stop is called or ctx is done.
+func (p *Process) Run(ctx context.Context, args ...string) error {
+	return p.start(ctx, ProcessModeOnline, [][]string{
+		append([]string{"run"}, args...),
+	})
+}
+
+// Catchup starts stellar-core in offline mode, replaying the ledgers from
+// `from` to `to` with `stellar-core catchup` followed by args, after
+// initializing a new database. The subprocess is restarted according to the
+// restart policy if it fails. Wait returns once the catchup completed.
+func (p *Process) Catchup(ctx context.Context, from, to uint32, args ...string) error {
+	if from == 0 || from > to {
+		return errors.Errorf("invalid catchup range [%d, %d]", from, to)
+	}
+	return p.start(ctx, ProcessModeOffline, [][]string{
+		{"new-db"},
+		append([]string{"catchup", fmt.Sprintf("%d/%d", to, to-from+1)}, args...),
+	})
+}
+
+func (p *Process) start(ctx context.Context, mode ProcessMode, commands [][]string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.started {
+		return errors.New("process already started")
+	}
+	p.started = true
+	p.status.Mode = mode
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	go p.supervise(ctx, mode, commands)
+	return nil
+}
+
+// Status returns the current status of the process.
+func (p *Process) Status() ProcessStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.status
+}
+
+// Done returns a channel closed once the process exited for good.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits until the process exited for good, and returns the error of the
+// last subprocess if it exhausted its restarts. It returns nil if the
+// process was stopped, or if its catchup completed.
+func (p *Process) Wait() error {
+	<-p.done
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.err
+}
+
+// Stop gracefully stops the subprocess: it is interrupted, and killed if it
+// does not exit within the shutdown timeout. Stop waits for it to exit, and
+// can be called several times.
+func (p *Process) Stop() error {
+	p.lock.Lock()
+	if !p.started {
+		p.started = true
+		p.status.State = ProcessStateExited
+		close(p.done)
+	} else {
+		p.cancel()
+	}
+	p.lock.Unlock()
+	<-p.done
+	return nil
+}
+
+func (p *Process) supervise(ctx context.Context, mode ProcessMode, commands [][]string) {
+	var err error
+	defer func() {
+		p.lock.Lock()
+		p.status.State = ProcessStateExited
+		p.status.PID = 0
+		p.err = err
+		p.lock.Unlock()
+		close(p.done)
+	}()
+
+	for {
+		startTime := time.Now()
+		err = p.runCommands(ctx, mode, commands)
+		if ctx.Err() != nil {
+			err = nil
+			return
+		}
+		if err == nil {
+			if mode == ProcessModeOffline {
+				p.config.Log.Info("stellar-core catchup completed")
+				return
+			}
+			err = errors.New("stellar-core exited unexpectedly")
+		}
+
+		p.lock.Lock()
+		restarts := p.status.Restarts
+		if p.config.Restart.ResetAfter > 0 && time.Since(startTime) >= p.config.Restart.ResetAfter {
+			restarts = 0
+		}
+		if restarts >= p.config.Restart.MaxRestarts {
+			p.status.Restarts = restarts
+			p.lock.Unlock()
+			p.config.Log.WithError(err).Error("stellar-core failed, not restarting")
+			return
+		}
+		restarts++
+		p.status.Restarts = restarts
+		p.status.State = ProcessStateRestarting
+		p.lock.Unlock()
+
+		backoff := p.config.Restart.backoff(restarts)
+		p.config.Log.WithError(err).Warnf(
+			"stellar-core failed, restarting in %s (restart %d of %d)",
+			backoff, restarts, p.config.Restart.MaxRestarts,
+		)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			err = nil
+			return
+		}
+	}
+}
+
+// runCommands runs the stellar-core commands in turn, stopping at the first
+// failure.
+func (p *Process) runCommands(ctx context.Context, mode ProcessMode, commands [][]string) error {
+	for _, args := range commands {
+		probe := mode == ProcessModeOnline && p.config.Client != nil
+		if err := p.runCommand(ctx, args, probe); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+// runCommand runs a stellar-core command until it exits, ctx is done or it
+// is found unhealthy.
+func (p *Process) runCommand(ctx context.Context, args []string, probe bool) error {
+	cmd := exec.Command(p.config.BinaryPath, append([]string{"--conf", p.config.ConfigPath}, args...)...)
+	cmd.Dir = p.config.Dir
+	cmd.Env = p.config.Env
+	cmd.Stdout = p.config.Stdout
+	cmd.Stderr = p.config.Stderr
+	cmd.ExtraFiles = p.config.ExtraFiles
+	if err := cmd.Start(); err != nil {
+		return errors.Wrapf(err, "could not start `stellar-core %s`", args[0])
+	}
+	p.config.Log.WithField("pid", cmd.Process.Pid).Infof("started `stellar-core %s`", args[0])
+
+	p.lock.Lock()
+	p.status.State = ProcessStateRunning
+	p.status.PID = cmd.Process.Pid
+	p.status.Info = nil
+	p.status.ProbeFailures = 0
+	p.status.LastProbeError = nil
+	p.lock.Unlock()
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	unhealthy := make(chan struct{})
+	probeCtx, stopProbing := context.WithCancel(ctx)
+	defer stopProbing()
+	if probe {
+		go p.probe(probeCtx, unhealthy)
+	}
+
+	var err error
+	select {
+	case err = <-exited:
+	case <-ctx.Done():
+		p.terminate(cmd, exited)
+		err = nil
+	case <-unhealthy:
+		p.terminate(cmd, exited)
+		err = errors.Errorf("stellar-core failed %d consecutive health probes", p.config.UnhealthyThreshold)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "`stellar-core %s` failed", args[0])
+	}
+
+	p.lock.Lock()
+	p.status.PID = 0
+	p.status.LastExitError = err
+	p.lock.Unlock()
+	return err
+}
+
+// terminate interrupts the subprocess, and kills it if it does not exit
+// within the shutdown timeout.
+func (p *Process) terminate(cmd *exec.Cmd, exited <-chan error) {
+	var timeout <-chan time.Time
+	// Interrupting a process is not supported on Windows.
+	if p.config.ShutdownTimeout > 0 && cmd.Process.Signal(os.Interrupt) == nil {
+		timeout = time.After(p.config.ShutdownTimeout)
+	} else {
+		cmd.Process.Kill()
+	}
+
+	select {
+	case <-exited:
+		return
+	case <-timeout:
+		p.config.Log.Warnf("stellar-core did not exit within %s, killing it", p.config.ShutdownTimeout)
+		cmd.Process.Kill()
+		<-exited
+	}
+}
+
+// probe queries the info of the subprocess at each health check interval,
+// and closes unhealthy once UnhealthyThreshold consecutive probes failed.
+func (p *Process) probe(ctx context.Context, unhealthy chan<- struct{}) {
+	select {
+	case <-time.After(p.config.HealthCheckGracePeriod):
+	case <-ctx.Done():
+		return
+	}
+
+	ticker := time.NewTicker(p.config.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		probeCtx, cancel := context.WithTimeout(ctx, p.config.HealthCheckInterval)
+		info, err := p.config.Client.Info(probeCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		p.lock.Lock()
+		if err != nil {
+			p.status.ProbeFailures++
+			p.status.LastProbeError = err
+		} else {
+			p.status.Info = info
+			p.status.ProbeFailures = 0
+			p.status.LastProbeError = nil
+		}
+		failures := p.status.ProbeFailures
+		p.lock.Unlock()
+
+		if err != nil {
+			p.config.Log.WithError(err).Warn("stellar-core health probe failed")
+			if p.config.UnhealthyThreshold > 0 && failures >= p.config.UnhealthyThreshold {
+				close(unhealthy)
+				return
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// +build !windows
+
+package stellarcore
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCore writes a shell script standing in for stellar-core to a temporary
+// directory. The script appends its arguments to the file args in the same
+// directory before running body.
+func fakeCore(t *testing.T, body string) (ProcessConfig, string) {
+	dir, err := ioutil.TempDir("", "fake-stellar-core")
+	require.NoError(t, err)
+	script := "#!/bin/sh\necho \"$@\" >> " + filepath.Join(dir, "args") + "\n" + body + "\n"
+	binary := filepath.Join(dir, "stellar-core")
+	require.NoError(t, ioutil.WriteFile(binary, []byte(script), 0755))
+	return ProcessConfig{BinaryPath: binary, ConfigPath: "stellar-core.cfg", Dir: dir}, dir
+}
+
+func readLines(t *testing.T, path string) []string {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestProcessCatchup(t *testing.T) {
+	config, dir := fakeCore(t, "exit 0")
+	defer os.RemoveAll(dir)
+
+	process, err := NewProcess(config)
+	require.NoError(t, err)
+	assert.EqualError(t, process.Catchup(context.Background(), 20, 10), "invalid catchup range [20, 10]")
+	require.NoError(t, process.Catchup(context.Background(), 10, 20, "--replay-in-memory"))
+	assert.NoError(t, process.Wait())
+	assert.EqualError(t, process.Run(context.Background()), "process already started")
+
+	assert.Equal(t, []string{
+		"--conf stellar-core.cfg new-db",
+		"--conf stellar-core.cfg catchup 20/11 --replay-in-memory",
+	}, readLines(t, filepath.Join(dir, "args")))
+	status := process.Status()
+	assert.Equal(t, ProcessModeOffline, status.Mode)
+	assert.Equal(t, ProcessStateExited, status.State)
+	assert.NoError(t, status.LastExitError)
+}
+
+func TestProcessExtraFiles(t *testing.T) {
+	config, dir := fakeCore(t, "echo meta >&3")
+	defer os.RemoveAll(dir)
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	config.ExtraFiles = []*os.File{w}
+
+	process, err := NewProcess(config)
+	require.NoError(t, err)
+	require.NoError(t, process.Catchup(context.Background(), 10, 20))
+	assert.NoError(t, process.Wait())
+	w.Close()
+
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	// Both new-db and catchup inherit the extra files.
+	assert.Equal(t, "meta\nmeta\n", string(data))
+}
+
+func TestProcessRestart(t *testing.T) {
+	config, dir := fakeCore(t, "exit 3")
+	defer os.RemoveAll(dir)
+	config.Restart = RestartPolicy{MaxRestarts: 2, Backoff: time.Millisecond}
+
+	process, err := NewProcess(config)
+	require.NoError(t, err)
+	require.NoError(t, process.Run(context.Background(), "--in-memory"))
+	assert.EqualError(t, process.Wait(), "`stellar-core run` failed: exit status 3")
+
+	assert.Equal(t, []string{
+		"--conf stellar-core.cfg run --in-memory",
+		"--conf stellar-core.cfg run --in-memory",
+		"--conf stellar-core.cfg run --in-memory",
+	}, readLines(t, filepath.Join(dir, "args")))
+	status := process.Status()
+	assert.Equal(t, ProcessStateExited, status.State)
+	assert.Equal(t, 2, status.Restarts)
+	assert.Error(t, status.LastExitError)
+}
+
+func TestRestartPolicyBackoff(t *testing.T) {
+	policy := RestartPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+	assert.Equal(t, 5*time.Second, policy.backoff(100))
+}
+
+// runningCore is a fake stellar-core running until it is interrupted, which
+// creates the file ready in its directory once it can be interrupted.
+const runningCore = `trap 'echo interrupted >> interrupts; exit 0' INT
+touch ready
+while true; do sleep 0.01; done`
+
+func waitForFile(t *testing.T, path string) {
+	for i := 0; i < 500; i++ {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s was not created", path)
+}
+
+func TestProcessStop(t *testing.T) {
+	config, dir := fakeCore(t, runningCore)
+	defer os.RemoveAll(dir)
+	config.ShutdownTimeout = 5 * time.Second
+
+	process, err := NewProcess(config)
+	require.NoError(t, err)
+	require.NoError(t, process.Run(context.Background()))
+	waitForFile(t, filepath.Join(dir, "ready"))
+	status := process.Status()
+	assert.Equal(t, ProcessStateRunning, status.State)
+	assert.NotZero(t, status.PID)
+	assert.False(t, status.Healthy())
+
+	assert.NoError(t, process.Stop())
+	assert.NoError(t, process.Stop())
+	assert.NoError(t, process.Wait())
+	assert.Equal(t, []string{"interrupted"}, readLines(t, filepath.Join(dir, "interrupts")))
+	assert.Equal(t, ProcessStateExited, process.Status().State)
+	assert.Zero(t, process.Status().PID)
+}
+
+func TestProcessHealthProbes(t *testing.T) {
+	config, dir := fakeCore(t, runningCore)
+	defer os.RemoveAll(dir)
+	hmock := httptest.NewClient()
+	config.Client = &Client{HTTP: hmock, URL: "http://localhost:11626"}
+	config.HealthCheckInterval = 10 * time.Millisecond
+	config.ShutdownTimeout = 5 * time.Second
+
+	var info proto.InfoResponse
+	info.Info.State = "Synced!"
+	hmock.On("GET", "http://localhost:11626/info").ReturnJSON(http.StatusOK, info)
+
+	process, err := NewProcess(config)
+	require.NoError(t, err)
+	require.NoError(t, process.Run(context.Background()))
+	for i := 0; i < 500 && !process.Status().Synced(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, process.Status().Synced())
+	assert.NoError(t, process.Stop())
+}
+
+func TestProcessUnhealthy(t *testing.T) {
+	config, dir := fakeCore(t, runningCore)
+	defer os.RemoveAll(dir)
+	hmock := httptest.NewClient()
+	config.Client = &Client{HTTP: hmock, URL: "http://localhost:11626"}
+	config.HealthCheckInterval = 10 * time.Millisecond
+	config.UnhealthyThreshold = 2
+	config.ShutdownTimeout = 5 * time.Second
+	config.Restart = RestartPolicy{MaxRestarts: 1, Backoff: time.Millisecond}
+
+	hmock.On("GET", "http://localhost:11626/info").ReturnString(http.StatusServiceUnavailable, "")
+
+	process, err := NewProcess(config)
+	require.NoError(t, err)
+	require.NoError(t, process.Run(context.Background()))
+	assert.EqualError(t, process.Wait(), "`stellar-core run` failed: stellar-core failed 2 consecutive health probes")
+
+	status := process.Status()
+	assert.Equal(t, 1, status.Restarts)
+	assert.Equal(t, 2, status.ProbeFailures)
+	assert.EqualError(t, status.LastProbeError, "http request failed with non-200 status code")
+	assert.Equal(t, []string{"interrupted", "interrupted"}, readLines(t, filepath.Join(dir, "interrupts")))
+}
@@ -0,0 +1,116 @@
+package stellarcore
+
+import (
+	"context"
+	"time"
+
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/support/errors"
+)
+
+// Survey runs an overlay survey from the connected stellar-core and builds
+// the topology of the network from its results.
+type Survey struct {
+	Client *Client
+	// Duration is how long stellar-core keeps the survey running. Responses
+	// arriving after it are ignored.
+	Duration time.Duration
+	// PollInterval is the time between checks of the survey results.
+	// Defaults to 5 seconds.
+	PollInterval time.Duration
+	// Crawl surveys the peers found in the responses too, so that the whole
+	// network reachable from the initial nodes is surveyed.
+	Crawl bool
+}
+
+// Run surveys the given nodes, and the peers found in their responses if
+// Crawl is set, until all of them responded or the survey expired. The
+// survey is stopped before returning.
+func (s Survey) Run(ctx context.Context, nodeIDs []string) (*Topology, error) {
+	if len(nodeIDs) == 0 {
+		return nil, errors.New("no nodes to survey")
+	}
+	pollInterval := s.PollInterval
+	if pollInterval == 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	defer func() {
+		// stop the survey even if ctx was canceled
+		_ = s.Client.StopSurvey(context.Background())
+	}()
+
+	requested := map[string]bool{}
+	request := func(nodeID string) error {
+		if requested[nodeID] {
+			return nil
+		}
+		requested[nodeID] = true
+		return errors.Wrapf(s.Client.SurveyTopology(ctx, nodeID, s.Duration), "surveying node %s", nodeID)
+	}
+	for _, nodeID := range nodeIDs {
+		if err := request(nodeID); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		result, err := s.Client.GetSurveyResult(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting survey result")
+		}
+
+		if s.Crawl {
+			for _, peerID := range peerIDs(result) {
+				if err := request(peerID); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if !result.SurveyInProgress || surveyComplete(result, requested) {
+			return NewTopology(result), nil
+		}
+	}
+}
+
+// peerIDs returns the ids of the peers found in the responses of a survey.
+func peerIDs(result *proto.GetSurveyResultResponse) []string {
+	var ids []string
+	for _, response := range result.Topology {
+		if response == nil {
+			continue
+		}
+		for _, peer := range response.InboundPeers {
+			ids = append(ids, peer.NodeID)
+		}
+		for _, peer := range response.OutboundPeers {
+			ids = append(ids, peer.NodeID)
+		}
+	}
+	return ids
+}
+
+// surveyComplete returns true if all the requested nodes either responded or
+// sent an invalid response.
+func surveyComplete(result *proto.GetSurveyResultResponse, requested map[string]bool) bool {
+	if len(result.Backlog) > 0 {
+		return false
+	}
+	bad := map[string]bool{}
+	for _, id := range result.BadResponseNodes {
+		bad[id] = true
+	}
+	for id := range requested {
+		if result.Topology[id] == nil && !bad[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package stellarcore
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSurveyTopology_Exception(t *testing.T) {
+	hmock := httptest.NewClient()
+	c := &Client{HTTP: hmock, URL: "http://localhost:11626"}
+
+	hmock.On("GET", "http://localhost:11626/surveytopology?duration=60&node=GA").
+		ReturnString(http.StatusOK, `{"exception": "surveying is disabled"}`)
+
+	err := c.SurveyTopology(context.Background(), "GA", time.Minute)
+	assert.EqualError(t, err, "exception in response: surveying is disabled")
+}
+
+func TestSurveyRun(t *testing.T) {
+	hmock := httptest.NewClient()
+	c := &Client{HTTP: hmock, URL: "http://localhost:11626"}
+
+	hmock.On("GET", "http://localhost:11626/surveytopology?duration=60&node=GA").
+		ReturnString(http.StatusOK, "Adding node.")
+	hmock.On("GET", "http://localhost:11626/surveytopology?duration=60&node=GB").
+		ReturnString(http.StatusOK, "Adding node.")
+	hmock.On("GET", "http://localhost:11626/getsurveyresult").
+		ReturnString(http.StatusOK, surveyResultJSON)
+	hmock.On("GET", "http://localhost:11626/stopsurvey").
+		ReturnString(http.StatusOK, "survey stopped")
+
+	survey := Survey{
+		Client:       c,
+		Duration:     time.Minute,
+		PollInterval: time.Millisecond,
+	}
+	topology, err := survey.Run(context.Background(), []string{"GA", "GB"})
+	require.NoError(t, err)
+	assert.Len(t, topology.Nodes, 4)
+	assert.Len(t, topology.Edges, 2)
+}
@@ -0,0 +1,170 @@
+package stellarcore
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	proto "github.com/stellar/go/protocols/stellarcore"
+)
+
+// Topology is the graph of the overlay network built from the responses of a
+// survey.
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+	// BadResponseNodes are the nodes which responded with invalid data.
+	BadResponseNodes []string `json:"bad_response_nodes,omitempty"`
+}
+
+// TopologyNode is a node of the overlay network, either surveyed or seen as
+// the peer of a surveyed node.
+type TopologyNode struct {
+	ID      string `json:"id"`
+	Version string `json:"version,omitempty"`
+	// Surveyed is true if the node responded to the survey, in which case
+	// the total number of its peers is known.
+	Surveyed              bool `json:"surveyed"`
+	NumTotalInboundPeers  int  `json:"num_total_inbound_peers,omitempty"`
+	NumTotalOutboundPeers int  `json:"num_total_outbound_peers,omitempty"`
+}
+
+// TopologyEdge is a connection from the node which initiated it to the node
+// which accepted it.
+type TopologyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// ReportedBy is the surveyed node whose statistics are reported, as
+	// connections between two surveyed nodes are only included once.
+	ReportedBy       string  `json:"reported_by"`
+	MessagesRead     uint64  `json:"messages_read"`
+	MessagesWritten  uint64  `json:"messages_written"`
+	BytesRead        uint64  `json:"bytes_read"`
+	BytesWritten     uint64  `json:"bytes_written"`
+	SecondsConnected uint64  `json:"seconds_connected"`
+	AverageLatencyMs float64 `json:"average_latency_ms,omitempty"`
+}
+
+// NewTopology builds the topology of the network from the result of a
+// survey. Nodes and edges are sorted by id.
+func NewTopology(result *proto.GetSurveyResultResponse) *Topology {
+	nodes := map[string]*TopologyNode{}
+	node := func(id string) *TopologyNode {
+		n, ok := nodes[id]
+		if !ok {
+			n = &TopologyNode{ID: id}
+			nodes[id] = n
+		}
+		return n
+	}
+
+	type edgeKey struct{ from, to string }
+	edges := map[edgeKey]TopologyEdge{}
+	addEdge := func(from, to, reportedBy string, peer proto.SurveyPeer) {
+		key := edgeKey{from, to}
+		if _, ok := edges[key]; ok {
+			return
+		}
+		edges[key] = TopologyEdge{
+			From:             from,
+			To:               to,
+			ReportedBy:       reportedBy,
+			MessagesRead:     peer.MessagesRead,
+			MessagesWritten:  peer.MessagesWritten,
+			BytesRead:        peer.BytesRead,
+			BytesWritten:     peer.BytesWritten,
+			SecondsConnected: peer.SecondsConnected,
+			AverageLatencyMs: peer.AverageLatencyMs,
+		}
+	}
+
+	// iterate in a stable order, so the statistics of an edge between two
+	// surveyed nodes are always reported by the same node
+	surveyedIDs := make([]string, 0, len(result.Topology))
+	for id := range result.Topology {
+		surveyedIDs = append(surveyedIDs, id)
+	}
+	sort.Strings(surveyedIDs)
+
+	for _, id := range surveyedIDs {
+		n := node(id)
+		response := result.Topology[id]
+		if response == nil {
+			continue
+		}
+		n.Surveyed = true
+		n.NumTotalInboundPeers = response.NumTotalInboundPeers
+		n.NumTotalOutboundPeers = response.NumTotalOutboundPeers
+
+		for _, peer := range response.InboundPeers {
+			node(peer.NodeID).Version = peer.Version
+			addEdge(peer.NodeID, id, id, peer)
+		}
+		for _, peer := range response.OutboundPeers {
+			node(peer.NodeID).Version = peer.Version
+			addEdge(id, peer.NodeID, id, peer)
+		}
+	}
+
+	topology := &Topology{
+		Nodes:            make([]TopologyNode, 0, len(nodes)),
+		Edges:            make([]TopologyEdge, 0, len(edges)),
+		BadResponseNodes: result.BadResponseNodes,
+	}
+	for _, n := range nodes {
+		topology.Nodes = append(topology.Nodes, *n)
+	}
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		return topology.Nodes[i].ID < topology.Nodes[j].ID
+	})
+	for _, e := range edges {
+		topology.Edges = append(topology.Edges, e)
+	}
+	sort.Slice(topology.Edges, func(i, j int) bool {
+		if topology.Edges[i].From != topology.Edges[j].From {
+			return topology.Edges[i].From < topology.Edges[j].From
+		}
+		return topology.Edges[i].To < topology.Edges[j].To
+	})
+	return topology
+}
+
+// WriteDOT writes the topology in the Graphviz DOT format. Surveyed nodes are
+// drawn with a double border, and edges are labeled with their average
+// latency when it is known.
+func (t *Topology) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph topology {"); err != nil {
+		return err
+	}
+	for _, n := range t.Nodes {
+		label := dotEscape(n.ID)
+		if n.Version != "" {
+			label += "\\n" + dotEscape(n.Version)
+		}
+		shape := "ellipse"
+		if n.Surveyed {
+			shape = "doublecircle"
+		}
+		if _, err := fmt.Fprintf(w, "  %q [label=\"%s\", shape=%s];\n", n.ID, label, shape); err != nil {
+			return err
+		}
+	}
+	for _, e := range t.Edges {
+		attrs := ""
+		if e.AverageLatencyMs > 0 {
+			attrs = fmt.Sprintf(" [label=\"%.1fms\"]", e.AverageLatencyMs)
+		}
+		if _, err := fmt.Fprintf(w, "  %q -> %q%s;\n", e.From, e.To, attrs); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// dotEscape escapes the double quotes of s so it can be used in a quoted DOT
+// string.
+func dotEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
@@ -0,0 +1,80 @@
+package stellarcore
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const surveyResultJSON = `{
+	"backlog": [],
+	"badResponseNodes": ["GC"],
+	"surveyInProgress": true,
+	"topology": {
+		"GA": {
+			"inboundPeers": [
+				{"nodeId": "GB", "version": "v17.0.0", "bytesRead": 10, "bytesWritten": 20, "messagesRead": 1, "messagesWritten": 2, "secondsConnected": 30, "averageLatencyMs": 12.5}
+			],
+			"outboundPeers": [
+				{"nodeId": "GD", "version": "v16.0.0", "bytesRead": 5, "bytesWritten": 6, "messagesRead": 1, "messagesWritten": 1, "secondsConnected": 40}
+			],
+			"numTotalInboundPeers": 1,
+			"numTotalOutboundPeers": 1
+		},
+		"GB": {
+			"inboundPeers": null,
+			"outboundPeers": [
+				{"nodeId": "GA", "version": "v17.1.0", "bytesRead": 20, "bytesWritten": 10, "messagesRead": 2, "messagesWritten": 1, "secondsConnected": 30}
+			],
+			"numTotalInboundPeers": 0,
+			"numTotalOutboundPeers": 1
+		},
+		"GC": null
+	}
+}`
+
+func TestNewTopology(t *testing.T) {
+	var result proto.GetSurveyResultResponse
+	require.NoError(t, json.Unmarshal([]byte(surveyResultJSON), &result))
+
+	topology := NewTopology(&result)
+	assert.Equal(t, []TopologyNode{
+		{ID: "GA", Version: "v17.1.0", Surveyed: true, NumTotalInboundPeers: 1, NumTotalOutboundPeers: 1},
+		{ID: "GB", Version: "v17.0.0", Surveyed: true, NumTotalOutboundPeers: 1},
+		{ID: "GC"},
+		{ID: "GD", Version: "v16.0.0"},
+	}, topology.Nodes)
+	// the GB -> GA connection is reported by both nodes, but included once
+	assert.Equal(t, []TopologyEdge{
+		{From: "GA", To: "GD", ReportedBy: "GA", BytesRead: 5, BytesWritten: 6, MessagesRead: 1, MessagesWritten: 1, SecondsConnected: 40},
+		{From: "GB", To: "GA", ReportedBy: "GA", BytesRead: 10, BytesWritten: 20, MessagesRead: 1, MessagesWritten: 2, SecondsConnected: 30, AverageLatencyMs: 12.5},
+	}, topology.Edges)
+	assert.Equal(t, []string{"GC"}, topology.BadResponseNodes)
+}
+
+func TestTopologyWriteDOT(t *testing.T) {
+	topology := Topology{
+		Nodes: []TopologyNode{
+			{ID: "GA", Version: "v17.1.0", Surveyed: true},
+			{ID: "GB"},
+		},
+		Edges: []TopologyEdge{
+			{From: "GB", To: "GA", AverageLatencyMs: 12.5},
+			{From: "GA", To: "GB"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, topology.WriteDOT(&buf))
+	assert.Equal(t, `digraph topology {
+  "GA" [label="GA\nv17.1.0", shape=doublecircle];
+  "GB" [label="GB", shape=ellipse];
+  "GB" -> "GA" [label="12.5ms"];
+  "GA" -> "GB";
+}
+`, buf.String())
+}
@@ -0,0 +1,220 @@
+package webauth
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+type challengeResponse struct {
+	Transaction       string `json:"transaction"`
+	NetworkPassphrase string `json:"network_passphrase"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// LookupServer returns the web auth server of the home domain on the
+// network, from the WEB_AUTH_ENDPOINT and SIGNING_KEY of its stellar.toml.
+// An error is returned if the stellar.toml is for another network.
+func (c *Client) LookupServer(homeDomain string, networkPassphrase string) (Server, error) {
+	stoml, err := c.StellarTOML.GetStellarToml(homeDomain)
+	if err != nil {
+		return Server{}, errors.Wrap(err, "get stellar.toml failed")
+	}
+
+	if stoml.WebAuthEndpoint == "" {
+		return Server{}, errors.New("stellar.toml is missing web auth endpoint")
+	}
+
+	if !c.AllowHTTP && !strings.HasPrefix(stoml.WebAuthEndpoint, "https://") {
+		return Server{}, errors.New("non-https web auth server disallowed")
+	}
+
+	if !strkey.IsValidEd25519PublicKey(stoml.SigningKey) {
+		return Server{}, errors.New("stellar.toml is missing a valid signing key")
+	}
+
+	if stoml.NetworkPassphrase != "" && stoml.NetworkPassphrase != networkPassphrase {
+		return Server{}, errors.Errorf("stellar.toml is for network %q", stoml.NetworkPassphrase)
+	}
+
+	return Server{
+		HomeDomain:        homeDomain,
+		Endpoint:          stoml.WebAuthEndpoint,
+		SigningKey:        stoml.SigningKey,
+		NetworkPassphrase: networkPassphrase,
+	}, nil
+}
+
+// Challenge requests a challenge for the account from the server, with the
+// client domain if it is not empty. The challenge is verified to be a valid
+// SEP-10 challenge signed by the server for the account, the home domain and
+// the host of the server's endpoint, and to carry the client domain
+// requested.
+func (c *Client) Challenge(server Server, account string, clientDomain string) (*txnbuild.Transaction, error) {
+	endpoint, err := url.Parse(server.Endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse web auth endpoint failed")
+	}
+
+	qstr := url.Values{}
+	qstr.Set("account", account)
+	qstr.Set("home_domain", server.HomeDomain)
+	if clientDomain != "" {
+		qstr.Set("client_domain", clientDomain)
+	}
+	u := *endpoint
+	u.RawQuery = qstr.Encode()
+
+	hresp, err := c.HTTP.Get(u.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "http get errored")
+	}
+
+	var resp challengeResponse
+	err = decodeResponse(hresp, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "get challenge failed")
+	}
+
+	if resp.NetworkPassphrase != "" && resp.NetworkPassphrase != server.NetworkPassphrase {
+		return nil, errors.Errorf("challenge is for network %q", resp.NetworkPassphrase)
+	}
+
+	tx, clientAccountID, _, err := txnbuild.ReadChallengeTx(
+		resp.Transaction,
+		server.SigningKey,
+		server.NetworkPassphrase,
+		endpoint.Host,
+		[]string{server.HomeDomain},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid challenge")
+	}
+
+	if clientAccountID != account {
+		return nil, errors.Errorf("challenge is for account %s", clientAccountID)
+	}
+
+	challengeClientDomain, _ := txnbuild.ChallengeTxClientDomain(tx)
+	if challengeClientDomain != clientDomain {
+		return nil, errors.Errorf("challenge client domain is %q but expect %q", challengeClientDomain, clientDomain)
+	}
+
+	return tx, nil
+}
+
+// Token exchanges a challenge signed by the client for a JWT.
+func (c *Client) Token(server Server, tx *txnbuild.Transaction) (string, error) {
+	txe, err := tx.Base64()
+	if err != nil {
+		return "", errors.Wrap(err, "encode challenge failed")
+	}
+
+	hresp, err := c.HTTP.PostForm(server.Endpoint, url.Values{"transaction": {txe}})
+	if err != nil {
+		return "", errors.Wrap(err, "http post errored")
+	}
+
+	var resp tokenResponse
+	err = decodeResponse(hresp, &resp)
+	if err != nil {
+		return "", errors.Wrap(err, "get token failed")
+	}
+
+	if resp.Token == "" {
+		return "", errors.New("token response is missing token")
+	}
+
+	return resp.Token, nil
+}
+
+// Authenticate authenticates the account with the web auth server of the
+// home domain, signing the challenge with signers, and returns the JWT.
+func (c *Client) Authenticate(homeDomain string, networkPassphrase string, account string, signers ...keypair.Signer) (string, error) {
+	return c.authenticate(homeDomain, networkPassphrase, account, "", nil, signers)
+}
+
+// AuthenticateWithClientDomain authenticates the account with the web auth
+// server of the home domain like Authenticate, attributing the client to the
+// client domain. The challenge is also signed with clientDomainSigner, which
+// must be the SIGNING_KEY of the stellar.toml of the client domain.
+func (c *Client) AuthenticateWithClientDomain(homeDomain string, networkPassphrase string, account string, clientDomain string, clientDomainSigner keypair.Signer, signers ...keypair.Signer) (string, error) {
+	if clientDomain == "" {
+		return "", errors.New("client domain cannot be empty")
+	}
+	if clientDomainSigner == nil {
+		return "", errors.New("client domain signer cannot be nil")
+	}
+	return c.authenticate(homeDomain, networkPassphrase, account, clientDomain, clientDomainSigner, signers)
+}
+
+func (c *Client) authenticate(homeDomain string, networkPassphrase string, account string, clientDomain string, clientDomainSigner keypair.Signer, signers []keypair.Signer) (string, error) {
+	server, err := c.LookupServer(homeDomain, networkPassphrase)
+	if err != nil {
+		return "", errors.Wrap(err, "lookup web auth server failed")
+	}
+
+	tx, err := c.Challenge(server, account, clientDomain)
+	if err != nil {
+		return "", err
+	}
+
+	if clientDomainSigner != nil {
+		// The server includes the signing key it found in the stellar.toml
+		// of the client domain, which could be outdated.
+		_, clientDomainAccountID := txnbuild.ChallengeTxClientDomain(tx)
+		if clientDomainAccountID != clientDomainSigner.Address() {
+			return "", errors.Errorf("challenge client domain signing key is %s but signer is %s", clientDomainAccountID, clientDomainSigner.Address())
+		}
+		signers = append([]keypair.Signer{clientDomainSigner}, signers...)
+	}
+
+	tx, err = tx.Sign(server.NetworkPassphrase, signers...)
+	if err != nil {
+		return "", errors.Wrap(err, "sign challenge failed")
+	}
+
+	return c.Token(server, tx)
+}
+
+// decodeResponse populates `dest` with the JSON body of the response,
+// provided the request succeeded. The error message of the server is
+// returned otherwise.
+func decodeResponse(hresp *http.Response, dest interface{}) error {
+	defer hresp.Body.Close()
+
+	limitReader := io.LimitReader(hresp.Body, WebAuthResponseMaxSize)
+
+	if !(hresp.StatusCode >= 200 && hresp.StatusCode < 300) {
+		var resp errorResponse
+		if json.NewDecoder(limitReader).Decode(&resp) == nil && resp.Error != "" {
+			return errors.Errorf("http request failed with (%d) status code: %s", hresp.StatusCode, resp.Error)
+		}
+		return errors.Errorf("http request failed with (%d) status code", hresp.StatusCode)
+	}
+
+	err := json.NewDecoder(limitReader).Decode(dest)
+	if err == io.ErrUnexpectedEOF && limitReader.(*io.LimitedReader).N == 0 {
+		return errors.Errorf("web auth response exceeds %d bytes limit", WebAuthResponseMaxSize)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "json decode errored")
+	}
+
+	return nil
+}
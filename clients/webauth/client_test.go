@@ -0,0 +1,184 @@
+package webauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer is a minimal SEP-10 server, whose tokens are the account and
+// client domain authenticated.
+func testServer(t *testing.T, serverKey *keypair.Full, stellarTOML stellartoml.ClientInterface) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		webAuthDomain := r.Host
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+
+		if r.Method == http.MethodGet {
+			q := r.URL.Query()
+			var (
+				tx  *txnbuild.Transaction
+				err error
+			)
+			if clientDomain := q.Get("client_domain"); clientDomain != "" {
+				resp, tomlErr := stellarTOML.GetStellarToml(clientDomain)
+				require.NoError(t, tomlErr)
+				tx, err = txnbuild.BuildChallengeTxWithClientDomain(serverKey.Seed(), q.Get("account"), webAuthDomain, q.Get("home_domain"), clientDomain, resp.SigningKey, network.TestNetworkPassphrase, time.Minute)
+			} else {
+				tx, err = txnbuild.BuildChallengeTx(serverKey.Seed(), q.Get("account"), webAuthDomain, q.Get("home_domain"), network.TestNetworkPassphrase, time.Minute)
+			}
+			require.NoError(t, err)
+			txe, err := tx.Base64()
+			require.NoError(t, err)
+			require.NoError(t, enc.Encode(challengeResponse{Transaction: txe, NetworkPassphrase: network.TestNetworkPassphrase}))
+			return
+		}
+
+		require.NoError(t, r.ParseForm())
+		txe := r.PostForm.Get("transaction")
+		tx, account, _, err := txnbuild.ReadChallengeTx(txe, serverKey.Address(), network.TestNetworkPassphrase, webAuthDomain, []string{"example.com"})
+		require.NoError(t, err)
+		_, err = txnbuild.VerifyChallengeTxSigners(txe, serverKey.Address(), network.TestNetworkPassphrase, webAuthDomain, []string{"example.com"}, account)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			require.NoError(t, enc.Encode(errorResponse{Error: "The request could not be authenticated."}))
+			return
+		}
+		clientDomain, _ := txnbuild.ChallengeTxClientDomain(tx)
+		require.NoError(t, enc.Encode(tokenResponse{Token: account + " " + clientDomain}))
+	}))
+	return srv
+}
+
+func newTestClient(t *testing.T) (*Client, *stellartoml.MockClient, *keypair.Full) {
+	serverKey := keypair.MustRandom()
+	walletKey := keypair.MustRandom()
+
+	stellarTOML := &stellartoml.MockClient{}
+	srv := testServer(t, serverKey, stellarTOML)
+	t.Cleanup(srv.Close)
+
+	stellarTOML.
+		On("GetStellarToml", "example.com").
+		Return(&stellartoml.Response{
+			WebAuthEndpoint:   srv.URL + "/auth",
+			SigningKey:        serverKey.Address(),
+			NetworkPassphrase: network.TestNetworkPassphrase,
+		}, nil)
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: walletKey.Address()}, nil)
+
+	c := &Client{
+		HTTP:        srv.Client(),
+		StellarTOML: stellarTOML,
+		AllowHTTP:   true,
+	}
+	return c, stellarTOML, walletKey
+}
+
+func TestAuthenticate(t *testing.T) {
+	c, _, _ := newTestClient(t)
+	account := keypair.MustRandom()
+
+	token, err := c.Authenticate("example.com", network.TestNetworkPassphrase, account.Address(), account)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address()+" ", token)
+
+	// The server rejects challenges not signed by the account.
+	_, err = c.Authenticate("example.com", network.TestNetworkPassphrase, account.Address(), keypair.MustRandom())
+	assert.EqualError(t, err, "get token failed: http request failed with (401) status code: The request could not be authenticated.")
+}
+
+func TestAuthenticateWithClientDomain(t *testing.T) {
+	c, _, walletKey := newTestClient(t)
+	account := keypair.MustRandom()
+
+	token, err := c.AuthenticateWithClientDomain("example.com", network.TestNetworkPassphrase, account.Address(), "wallet.example.com", walletKey, account)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address()+" wallet.example.com", token)
+
+	otherKey := keypair.MustRandom()
+	_, err = c.AuthenticateWithClientDomain("example.com", network.TestNetworkPassphrase, account.Address(), "wallet.example.com", otherKey, account)
+	assert.EqualError(t, err, "challenge client domain signing key is "+walletKey.Address()+" but signer is "+otherKey.Address())
+
+	_, err = c.AuthenticateWithClientDomain("example.com", network.TestNetworkPassphrase, account.Address(), "", walletKey, account)
+	assert.EqualError(t, err, "client domain cannot be empty")
+}
+
+func TestChallenge_invalid(t *testing.T) {
+	c, _, _ := newTestClient(t)
+	account := keypair.MustRandom()
+
+	server, err := c.LookupServer("example.com", network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	tx, err := c.Challenge(server, account.Address(), "")
+	require.NoError(t, err)
+	_, clientDomainAccountID := txnbuild.ChallengeTxClientDomain(tx)
+	assert.Empty(t, clientDomainAccountID)
+
+	// Challenges signed by another key than the SIGNING_KEY are rejected.
+	forgedServer := server
+	forgedServer.SigningKey = keypair.MustRandom().Address()
+	_, err = c.Challenge(forgedServer, account.Address(), "")
+	assert.EqualError(t, err, "invalid challenge: transaction source account is not equal to server's account")
+
+}
+
+func TestLookupServer(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "example.com").
+		Return(&stellartoml.Response{
+			WebAuthEndpoint: "https://example.com/auth",
+			SigningKey:      serverKey.Address(),
+		}, nil)
+	stellarTOML.
+		On("GetStellarToml", "http.example.com").
+		Return(&stellartoml.Response{
+			WebAuthEndpoint: "http://http.example.com/auth",
+			SigningKey:      serverKey.Address(),
+		}, nil)
+	stellarTOML.
+		On("GetStellarToml", "nosep10.example.com").
+		Return(&stellartoml.Response{}, nil)
+	stellarTOML.
+		On("GetStellarToml", "pubnet.example.com").
+		Return(&stellartoml.Response{
+			WebAuthEndpoint:   "https://pubnet.example.com/auth",
+			SigningKey:        serverKey.Address(),
+			NetworkPassphrase: network.PublicNetworkPassphrase,
+		}, nil)
+
+	c := &Client{StellarTOML: stellarTOML}
+
+	server, err := c.LookupServer("example.com", network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, Server{
+		HomeDomain:        "example.com",
+		Endpoint:          "https://example.com/auth",
+		SigningKey:        serverKey.Address(),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	}, server)
+
+	_, err = c.LookupServer("http.example.com", network.TestNetworkPassphrase)
+	assert.EqualError(t, err, "non-https web auth server disallowed")
+
+	_, err = c.LookupServer("nosep10.example.com", network.TestNetworkPassphrase)
+	assert.EqualError(t, err, "stellar.toml is missing web auth endpoint")
+
+	_, err = c.LookupServer("pubnet.example.com", network.TestNetworkPassphrase)
+	assert.EqualError(t, err, `stellar.toml is for network "Public Global Stellar Network ; September 2015"`)
+}
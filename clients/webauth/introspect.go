@@ -0,0 +1,93 @@
+package webauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpauthz"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+// Introspection is the state of a JWT according to the web auth server that
+// issued it. The claims are only set if the token is active.
+type Introspection struct {
+	// Active is true if the token was issued by the server, has not expired
+	// and has not been revoked.
+	Active    bool   `json:"active"`
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	ID        string `json:"jti,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// Introspector checks with the introspection endpoint of a web auth server
+// that JWTs have not been revoked. It is used by resource servers accepting
+// the JWTs of the server, which can verify the signature of a JWT on their
+// own but cannot know that it was revoked.
+type Introspector struct {
+	// Endpoint is the URL of the introspection endpoint, e.g.
+	// https://webauth.example.com/introspect.
+	Endpoint string
+	// Secret is the introspection secret of the server, sent as a bearer
+	// token.
+	Secret string
+	// HTTP is the client making the requests. Defaults to
+	// http.DefaultClient.
+	HTTP *http.Client
+}
+
+// Introspect returns the state of the token.
+func (i *Introspector) Introspect(ctx context.Context, token string) (Introspection, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.Endpoint, strings.NewReader(url.Values{"token": {token}}.Encode()))
+	if err != nil {
+		return Introspection{}, errors.Wrap(err, "building request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+i.Secret)
+
+	httpClient := i.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	hresp, err := httpClient.Do(req)
+	if err != nil {
+		return Introspection{}, errors.Wrap(err, "http post errored")
+	}
+
+	var resp Introspection
+	err = decodeResponse(hresp, &resp)
+	if err != nil {
+		return Introspection{}, errors.Wrap(err, "introspect token failed")
+	}
+	return resp, nil
+}
+
+// Middleware rejects requests carrying a bearer token in the Authorization
+// header that is not active, e.g. because it was revoked, with a 401
+// Unauthorized. Requests without a bearer token are passed on, it is up to
+// the resource server to require one. Requests are rejected with a 503
+// Service Unavailable if the server cannot be reached.
+func (i *Introspector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := httpauthz.ParseBearerToken(r.Header.Get("Authorization"))
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		introspection, err := i.Introspect(r.Context(), token)
+		if err != nil {
+			httpjson.RenderStatus(w, http.StatusServiceUnavailable, errorResponse{Error: "The token could not be verified."}, httpjson.JSON)
+			return
+		}
+		if !introspection.Active {
+			httpjson.RenderStatus(w, http.StatusUnauthorized, errorResponse{Error: "The request could not be authenticated."}, httpjson.JSON)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,72 @@
+package webauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testIntrospectionServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"The request could not be authenticated."}`))
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		switch r.PostForm.Get("token") {
+		case "active":
+			w.Write([]byte(`{"active":true,"sub":"GA...","jti":"1"}`))
+		default:
+			w.Write([]byte(`{"active":false}`))
+		}
+	}))
+}
+
+func TestIntrospector_Introspect(t *testing.T) {
+	srv := testIntrospectionServer(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	i := &Introspector{Endpoint: srv.URL, Secret: "secret"}
+	introspection, err := i.Introspect(ctx, "active")
+	require.NoError(t, err)
+	assert.Equal(t, Introspection{Active: true, Subject: "GA...", ID: "1"}, introspection)
+
+	introspection, err = i.Introspect(ctx, "revoked")
+	require.NoError(t, err)
+	assert.Equal(t, Introspection{}, introspection)
+
+	i.Secret = "wrong"
+	_, err = i.Introspect(ctx, "active")
+	assert.EqualError(t, err, "introspect token failed: http request failed with (401) status code: The request could not be authenticated.")
+}
+
+func TestIntrospector_Middleware(t *testing.T) {
+	srv := testIntrospectionServer(t)
+	defer srv.Close()
+
+	i := &Introspector{Endpoint: srv.URL, Secret: "secret"}
+	h := i.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serve := func(authorization string) int {
+		r := httptest.NewRequest("GET", "/", nil)
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result().StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, serve(""))
+	assert.Equal(t, http.StatusOK, serve("Bearer active"))
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer revoked"))
+
+	i.Endpoint = "http://127.0.0.1:0/introspect"
+	assert.Equal(t, http.StatusServiceUnavailable, serve("Bearer active"))
+}
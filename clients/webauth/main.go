@@ -0,0 +1,110 @@
+// Package webauth is a client of SEP-10 Web Authentication servers. It
+// proves to the server of a home domain that the client holds the keys of an
+// account, and returns the JWT issued by the server in exchange.
+//
+// The client can also attribute itself to a client domain, e.g. the home
+// domain of a wallet, in which case the challenge must be signed with the
+// SIGNING_KEY of the stellar.toml of the client domain as well.
+//
+// SEP-10: https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0010.md
+package webauth
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+)
+
+// WebAuthResponseMaxSize is the maximum size of responses from a web auth
+// server.
+const WebAuthResponseMaxSize = 100 * 1024
+
+// DefaultClient is a default client using the default parameters
+var DefaultClient = &Client{
+	HTTP:        http.DefaultClient,
+	StellarTOML: stellartoml.DefaultClient,
+}
+
+// Client represents a client that is capable of authenticating with SEP-10
+// web auth servers.
+type Client struct {
+	StellarTOML StellarTOML
+	HTTP        HTTP
+
+	// AllowHTTP allows web auth servers whose endpoint is served over plain
+	// HTTP. Useful for debugging.
+	AllowHTTP bool
+}
+
+type ClientInterface interface {
+	LookupServer(homeDomain string, networkPassphrase string) (Server, error)
+	Challenge(server Server, account string, clientDomain string) (*txnbuild.Transaction, error)
+	Token(server Server, tx *txnbuild.Transaction) (string, error)
+	Authenticate(homeDomain string, networkPassphrase string, account string, signers ...keypair.Signer) (string, error)
+	AuthenticateWithClientDomain(homeDomain string, networkPassphrase string, account string, clientDomain string, clientDomainSigner keypair.Signer, signers ...keypair.Signer) (string, error)
+}
+
+// HTTP represents the http client that a web auth client uses to make http
+// requests.
+type HTTP interface {
+	Get(url string) (*http.Response, error)
+	PostForm(url string, data url.Values) (*http.Response, error)
+}
+
+// StellarTOML represents a client that can resolve a given domain name to
+// stellar.toml file.  The response is used to find the web auth server of a
+// home domain.
+type StellarTOML interface {
+	GetStellarToml(domain string) (*stellartoml.Response, error)
+}
+
+// Server describes the web auth server of a home domain, as published in
+// the stellar.toml of the home domain.
+type Server struct {
+	// HomeDomain is the home domain of the service requiring
+	// authentication.
+	HomeDomain string
+	// Endpoint is the WEB_AUTH_ENDPOINT of the stellar.toml.
+	Endpoint string
+	// SigningKey is the SIGNING_KEY of the stellar.toml, the account the
+	// server signs challenges with.
+	SigningKey string
+	// NetworkPassphrase is the passphrase of the network challenges are
+	// signed for.
+	NetworkPassphrase string
+}
+
+// LookupServer returns the web auth server of the home domain on the
+// network, from its stellar.toml.
+func LookupServer(homeDomain string, networkPassphrase string) (Server, error) {
+	return DefaultClient.LookupServer(homeDomain, networkPassphrase)
+}
+
+// Challenge requests a challenge for the account from the server, and
+// verifies it.
+func Challenge(server Server, account string, clientDomain string) (*txnbuild.Transaction, error) {
+	return DefaultClient.Challenge(server, account, clientDomain)
+}
+
+// Token exchanges a signed challenge for a JWT.
+func Token(server Server, tx *txnbuild.Transaction) (string, error) {
+	return DefaultClient.Token(server, tx)
+}
+
+// Authenticate authenticates the account with the web auth server of the
+// home domain, signing the challenge with signers, and returns the JWT.
+func Authenticate(homeDomain string, networkPassphrase string, account string, signers ...keypair.Signer) (string, error) {
+	return DefaultClient.Authenticate(homeDomain, networkPassphrase, account, signers...)
+}
+
+// AuthenticateWithClientDomain authenticates the account with the web auth
+// server of the home domain like Authenticate, attributing the client to the
+// client domain.
+func AuthenticateWithClientDomain(homeDomain string, networkPassphrase string, account string, clientDomain string, clientDomainSigner keypair.Signer, signers ...keypair.Signer) (string, error) {
+	return DefaultClient.AuthenticateWithClientDomain(homeDomain, networkPassphrase, account, clientDomain, clientDomainSigner, signers...)
+}
+
+var _ ClientInterface = &Client{}
@@ -0,0 +1,18 @@
+/*
+Package cases provides shared database models and HTTP handlers for
+compliance cases, so that compliance-adjacent services such as the regulated
+assets approval server's KYC flow and SEP-31 receivers do not each invent
+their own tables.
+
+A case tracks the review of an entity, e.g. a Stellar account or a SEP-31
+sender, by a compliance team. It has a status, an optional reviewer, and
+accumulates notes and attachments while it is being reviewed. Attachments
+are references to documents stored elsewhere, the package does not store
+their content.
+
+The tables are created by the migrations returned by Migrations, which are
+prefixed so that they can be applied alongside a service's own migrations.
+
+This package is experimental and its API may change.
+*/
+package cases
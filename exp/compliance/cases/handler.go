@@ -0,0 +1,251 @@
+package cases
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpdecode"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+const (
+	defaultListLimit = 10
+	maxListLimit     = 200
+)
+
+// Routes returns a function registering the case handlers on a chi router,
+// to be used with Route, e.g. mux.Route("/cases", cases.Routes(store)):
+//
+//	GET  /                    lists the cases, see ListHandler
+//	POST /                    opens a case
+//	GET  /{id}                returns a case with its notes and attachments
+//	POST /{id}/status         sets the status and reviewer of a case
+//	POST /{id}/notes          adds a note to a case
+//	POST /{id}/attachments    adds an attachment to a case
+//
+// The handlers do not authenticate requests, they must be mounted behind the
+// authentication used by the service's admin endpoints.
+func Routes(store *Store) func(chi.Router) {
+	return func(mux chi.Router) {
+		mux.Get("/", ListHandler{Store: store}.ServeHTTP)
+		mux.Post("/", CreateHandler{Store: store}.ServeHTTP)
+		mux.Get("/{id}", GetHandler{Store: store}.ServeHTTP)
+		mux.Post("/{id}/status", UpdateStatusHandler{Store: store}.ServeHTTP)
+		mux.Post("/{id}/notes", AddNoteHandler{Store: store}.ServeHTTP)
+		mux.Post("/{id}/attachments", AddAttachmentHandler{Store: store}.ServeHTTP)
+	}
+}
+
+// httpError is an error rendered as a JSON object with an error field, the
+// same as the errors of the services embedding the handlers.
+type httpError struct {
+	ErrorMessage string `json:"error"`
+	Status       int    `json:"-"`
+}
+
+func (e *httpError) Error() string {
+	return e.ErrorMessage
+}
+
+func newHTTPError(status int, errorMessage string) *httpError {
+	return &httpError{ErrorMessage: errorMessage, Status: status}
+}
+
+var (
+	errInternalServer = newHTTPError(http.StatusInternalServerError, "An error occurred while processing this request.")
+	errBadRequest     = newHTTPError(http.StatusBadRequest, "The request was invalid in some way.")
+	errNotFound       = newHTTPError(http.StatusNotFound, "Not found.")
+)
+
+// serve validates the store, decodes the request into in and renders the
+// result of handle.
+func serve(w http.ResponseWriter, r *http.Request, store *Store, in interface{}, handle func(context.Context) (interface{}, error)) {
+	ctx := r.Context()
+	if store == nil || store.DB == nil {
+		log.Ctx(ctx).Error("validating cases handler: store cannot be nil")
+		httpjson.RenderStatus(w, errInternalServer.Status, errInternalServer, httpjson.JSON)
+		return
+	}
+
+	err := httpdecode.Decode(r, in)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "decoding cases request"))
+		httpjson.RenderStatus(w, errBadRequest.Status, errBadRequest, httpjson.JSON)
+		return
+	}
+
+	resp, err := handle(ctx)
+	switch errors.Cause(err) {
+	case nil:
+		httpjson.Render(w, resp, httpjson.JSON)
+		return
+	case ErrNotFound:
+		err = errNotFound
+	case ErrInvalidStatus, ErrReviewerRequired:
+		err = newHTTPError(http.StatusBadRequest, err.Error())
+	}
+	httpErr, ok := err.(*httpError)
+	if !ok {
+		log.Ctx(ctx).Error(errors.Wrap(err, "handling cases request"))
+		httpErr = errInternalServer
+	}
+	httpjson.RenderStatus(w, httpErr.Status, httpErr, httpjson.JSON)
+}
+
+// ListHandler lists the cases, oldest first. They can be filtered with the
+// kind, entity and status query parameters, and paged with the cursor and
+// limit query parameters.
+type ListHandler struct {
+	Store *Store
+}
+
+type listRequest struct {
+	Kind   string `query:"kind"`
+	Entity string `query:"entity"`
+	Status string `query:"status"`
+	Cursor string `query:"cursor"`
+	Limit  string `query:"limit"`
+}
+
+type listResponse struct {
+	Records []Case `json:"records"`
+	// NextCursor is the cursor of the next page, empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in := listRequest{}
+	serve(w, r, h.Store, &in, func(ctx context.Context) (interface{}, error) {
+		limit := defaultListLimit
+		if in.Limit != "" {
+			var err error
+			limit, err = strconv.Atoi(in.Limit)
+			if err != nil || limit < 1 || limit > maxListLimit {
+				return nil, newHTTPError(http.StatusBadRequest, "Invalid limit.")
+			}
+		}
+
+		records, err := h.Store.List(ctx, ListFilter{
+			Kind:   in.Kind,
+			Entity: in.Entity,
+			Status: Status(in.Status),
+			Cursor: in.Cursor,
+			Limit:  limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp := listResponse{Records: records}
+		if len(records) == limit {
+			resp.NextCursor = records[len(records)-1].ID
+		}
+		return resp, nil
+	})
+}
+
+// CreateHandler opens a case for the kind and entity given in the JSON body.
+type CreateHandler struct {
+	Store *Store
+}
+
+type createRequest struct {
+	Kind   string `json:"kind"`
+	Entity string `json:"entity"`
+}
+
+func (h CreateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in := createRequest{}
+	serve(w, r, h.Store, &in, func(ctx context.Context) (interface{}, error) {
+		if in.Kind == "" || in.Entity == "" {
+			return nil, newHTTPError(http.StatusBadRequest, "Missing kind or entity.")
+		}
+		return h.Store.Create(ctx, in.Kind, in.Entity)
+	})
+}
+
+// GetHandler returns a case with its notes and attachments.
+type GetHandler struct {
+	Store *Store
+}
+
+type getRequest struct {
+	ID string `path:"id"`
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in := getRequest{}
+	serve(w, r, h.Store, &in, func(ctx context.Context) (interface{}, error) {
+		return h.Store.Get(ctx, in.ID)
+	})
+}
+
+// UpdateStatusHandler sets the status of a case, and its reviewer when one
+// is given in the JSON body. Approving or rejecting a case requires a
+// reviewer.
+type UpdateStatusHandler struct {
+	Store *Store
+}
+
+type updateStatusRequest struct {
+	ID       string `path:"id"`
+	Status   string `json:"status"`
+	Reviewer string `json:"reviewer"`
+}
+
+func (h UpdateStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in := updateStatusRequest{}
+	serve(w, r, h.Store, &in, func(ctx context.Context) (interface{}, error) {
+		return h.Store.UpdateStatus(ctx, in.ID, Status(in.Status), in.Reviewer)
+	})
+}
+
+// AddNoteHandler adds the note given in the JSON body to a case.
+type AddNoteHandler struct {
+	Store *Store
+}
+
+type addNoteRequest struct {
+	ID     string `path:"id"`
+	Author string `json:"author"`
+	Body   string `json:"body"`
+}
+
+func (h AddNoteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in := addNoteRequest{}
+	serve(w, r, h.Store, &in, func(ctx context.Context) (interface{}, error) {
+		if in.Author == "" || in.Body == "" {
+			return nil, newHTTPError(http.StatusBadRequest, "Missing author or body.")
+		}
+		return h.Store.AddNote(ctx, in.ID, in.Author, in.Body)
+	})
+}
+
+// AddAttachmentHandler adds the attachment given in the JSON body to a case.
+type AddAttachmentHandler struct {
+	Store *Store
+}
+
+type addAttachmentRequest struct {
+	ID          string `path:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	URL         string `json:"url"`
+}
+
+func (h AddAttachmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	in := addAttachmentRequest{}
+	serve(w, r, h.Store, &in, func(ctx context.Context) (interface{}, error) {
+		if in.Name == "" || in.URL == "" {
+			return nil, newHTTPError(http.StatusBadRequest, "Missing name or url.")
+		}
+		return h.Store.AddAttachment(ctx, in.ID, Attachment{
+			Name:        in.Name,
+			ContentType: in.ContentType,
+			URL:         in.URL,
+		})
+	})
+}
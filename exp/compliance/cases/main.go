@@ -0,0 +1,82 @@
+package cases
+
+import (
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Status is the status of a case.
+type Status string
+
+const (
+	// StatusOpen is the status of a case which has not been reviewed yet.
+	StatusOpen Status = "open"
+	// StatusInReview is the status of a case assigned to a reviewer.
+	StatusInReview Status = "in_review"
+	// StatusApproved is the status of a case whose entity was approved.
+	StatusApproved Status = "approved"
+	// StatusRejected is the status of a case whose entity was rejected.
+	StatusRejected Status = "rejected"
+)
+
+// Valid returns true if s is one of the known statuses.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusOpen, StatusInReview, StatusApproved, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// Decided returns true if s is a final decision, which requires a reviewer.
+func (s Status) Decided() bool {
+	return s == StatusApproved || s == StatusRejected
+}
+
+var (
+	// ErrNotFound is returned when a case does not exist.
+	ErrNotFound = errors.New("case not found")
+	// ErrInvalidStatus is returned when setting a status which is not valid.
+	ErrInvalidStatus = errors.New("invalid case status")
+	// ErrReviewerRequired is returned when deciding a case without a
+	// reviewer.
+	ErrReviewerRequired = errors.New("a reviewer is required to approve or reject a case")
+)
+
+// Case is the compliance review of an entity.
+type Case struct {
+	ID string `json:"id" db:"id"`
+	// Kind identifies the flow which opened the case, e.g. kyc or sep31.
+	Kind string `json:"kind" db:"kind"`
+	// Entity identifies the entity under review, e.g. a Stellar address or a
+	// SEP-12 customer ID.
+	Entity    string    `json:"entity" db:"entity"`
+	Status    Status    `json:"status" db:"status"`
+	Reviewer  string    `json:"reviewer,omitempty" db:"reviewer"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	Notes       []Note       `json:"notes,omitempty" db:"-"`
+	Attachments []Attachment `json:"attachments,omitempty" db:"-"`
+}
+
+// Note is a free-form note added to a case.
+type Note struct {
+	ID        string    `json:"id" db:"id"`
+	CaseID    string    `json:"-" db:"case_id"`
+	Author    string    `json:"author" db:"author"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Attachment is a reference to a document stored outside of the database,
+// e.g. an identity document uploaded to an object store.
+type Attachment struct {
+	ID          string    `json:"id" db:"id"`
+	CaseID      string    `json:"-" db:"case_id"`
+	Name        string    `json:"name" db:"name"`
+	ContentType string    `json:"content_type,omitempty" db:"content_type"`
+	URL         string    `json:"url" db:"url"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
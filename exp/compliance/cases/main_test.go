@@ -0,0 +1,159 @@
+package cases
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStore(t *testing.T) (*Store, func()) {
+	db := dbtest.Postgres(t)
+	conn := db.Open()
+	_, err := migrate.Exec(conn.DB, "postgres", Migrations(), migrate.Up)
+	require.NoError(t, err)
+	return &Store{DB: conn}, func() {
+		conn.Close()
+		db.Close()
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store, closeStore := openStore(t)
+	defer closeStore()
+
+	c, err := store.Create(ctx, "kyc", "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOpen, c.Status)
+	assert.Empty(t, c.Reviewer)
+
+	_, err = store.UpdateStatus(ctx, c.ID, StatusApproved, "")
+	assert.Equal(t, ErrReviewerRequired, err)
+	_, err = store.UpdateStatus(ctx, c.ID, "done", "alice")
+	assert.Equal(t, ErrInvalidStatus, err)
+	_, err = store.UpdateStatus(ctx, "unknown", StatusInReview, "alice")
+	assert.Equal(t, ErrNotFound, err)
+
+	updated, err := store.UpdateStatus(ctx, c.ID, StatusInReview, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, StatusInReview, updated.Status)
+	assert.Equal(t, "alice", updated.Reviewer)
+	updated, err = store.UpdateStatus(ctx, c.ID, StatusApproved, "")
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, updated.Status)
+	assert.Equal(t, "alice", updated.Reviewer)
+
+	_, err = store.AddNote(ctx, c.ID, "alice", "Documents verified.")
+	require.NoError(t, err)
+	_, err = store.AddAttachment(ctx, c.ID, Attachment{Name: "passport.pdf", ContentType: "application/pdf", URL: "https://example.com/passport.pdf"})
+	require.NoError(t, err)
+	_, err = store.AddNote(ctx, "unknown", "alice", "Documents verified.")
+	assert.Equal(t, ErrNotFound, err)
+
+	got, err := store.Get(ctx, c.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Notes, 1)
+	assert.Equal(t, "Documents verified.", got.Notes[0].Body)
+	require.Len(t, got.Attachments, 1)
+	assert.Equal(t, "https://example.com/passport.pdf", got.Attachments[0].URL)
+
+	_, err = store.Get(ctx, "unknown")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestStoreList(t *testing.T) {
+	ctx := context.Background()
+	store, closeStore := openStore(t)
+	defer closeStore()
+
+	ids := []string{}
+	for _, entity := range []string{"a", "b", "c"} {
+		c, err := store.Create(ctx, "sep31", entity)
+		require.NoError(t, err)
+		ids = append(ids, c.ID)
+	}
+	_, err := store.Create(ctx, "kyc", "a")
+	require.NoError(t, err)
+	_, err = store.UpdateStatus(ctx, ids[1], StatusRejected, "bob")
+	require.NoError(t, err)
+
+	cases, err := store.List(ctx, ListFilter{Kind: "sep31", Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+	assert.Equal(t, ids[0], cases[0].ID)
+	assert.Equal(t, ids[1], cases[1].ID)
+
+	cases, err = store.List(ctx, ListFilter{Kind: "sep31", Cursor: ids[1]})
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	assert.Equal(t, ids[2], cases[0].ID)
+
+	cases, err = store.List(ctx, ListFilter{Status: StatusOpen})
+	require.NoError(t, err)
+	assert.Len(t, cases, 3)
+
+	cases, err = store.List(ctx, ListFilter{Entity: "a"})
+	require.NoError(t, err)
+	assert.Len(t, cases, 2)
+}
+
+func TestHandlers(t *testing.T) {
+	store, closeStore := openStore(t)
+	defer closeStore()
+	mux := chi.NewMux()
+	mux.Route("/cases", Routes(store))
+
+	do := func(method, path, body string) (int, map[string]interface{}) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		resp := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w.Code, resp
+	}
+
+	status, resp := do("POST", "/cases", `{"kind": "kyc"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]interface{}{"error": "Missing kind or entity."}, resp)
+
+	status, resp = do("POST", "/cases", `{"kind": "kyc", "entity": "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "open", resp["status"])
+	id := resp["id"].(string)
+
+	status, resp = do("POST", "/cases/"+id+"/status", `{"status": "rejected"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, map[string]interface{}{"error": ErrReviewerRequired.Error()}, resp)
+
+	status, _ = do("POST", "/cases/"+id+"/status", `{"status": "rejected", "reviewer": "alice"}`)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do("POST", "/cases/"+id+"/notes", `{"author": "alice", "body": "Sanctioned."}`)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do("POST", "/cases/"+id+"/attachments", `{"name": "report.pdf", "url": "https://example.com/report.pdf"}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, resp = do("GET", "/cases/"+id, "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "rejected", resp["status"])
+	assert.Equal(t, "alice", resp["reviewer"])
+	assert.Len(t, resp["notes"], 1)
+	assert.Len(t, resp["attachments"], 1)
+
+	status, resp = do("GET", "/cases?status=rejected&limit=1", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, resp["records"], 1)
+	assert.Equal(t, id, resp["next_cursor"])
+
+	status, resp = do("GET", "/cases/unknown", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, map[string]interface{}{"error": "Not found."}, resp)
+}
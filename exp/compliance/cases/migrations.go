@@ -0,0 +1,53 @@
+package cases
+
+import (
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Migrations returns the migrations creating the tables used by Store. Their
+// IDs are prefixed with "cases-" so that they can be combined with the
+// migrations of the service embedding the package.
+func Migrations() *migrate.MemoryMigrationSource {
+	return &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "cases-2021-06-15.0.initial.sql",
+				Up: []string{
+					`CREATE TABLE compliance_cases (
+						id TEXT PRIMARY KEY,
+						kind TEXT NOT NULL,
+						entity TEXT NOT NULL,
+						status TEXT NOT NULL,
+						reviewer TEXT NOT NULL DEFAULT '',
+						created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+						updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+					)`,
+					`CREATE INDEX compliance_cases_entity_idx ON compliance_cases (kind, entity)`,
+					`CREATE INDEX compliance_cases_status_idx ON compliance_cases (status, created_at)`,
+					`CREATE TABLE compliance_case_notes (
+						id TEXT PRIMARY KEY,
+						case_id TEXT NOT NULL REFERENCES compliance_cases (id) ON DELETE CASCADE,
+						author TEXT NOT NULL,
+						body TEXT NOT NULL,
+						created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+					)`,
+					`CREATE INDEX compliance_case_notes_case_id_idx ON compliance_case_notes (case_id, created_at)`,
+					`CREATE TABLE compliance_case_attachments (
+						id TEXT PRIMARY KEY,
+						case_id TEXT NOT NULL REFERENCES compliance_cases (id) ON DELETE CASCADE,
+						name TEXT NOT NULL,
+						content_type TEXT NOT NULL DEFAULT '',
+						url TEXT NOT NULL,
+						created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+					)`,
+					`CREATE INDEX compliance_case_attachments_case_id_idx ON compliance_case_attachments (case_id, created_at)`,
+				},
+				Down: []string{
+					`DROP TABLE compliance_case_attachments`,
+					`DROP TABLE compliance_case_notes`,
+					`DROP TABLE compliance_cases`,
+				},
+			},
+		},
+	}
+}
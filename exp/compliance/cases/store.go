@@ -0,0 +1,188 @@
+package cases
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/support/errors"
+)
+
+// Store reads and writes cases in the tables created by Migrations.
+type Store struct {
+	DB *sqlx.DB
+}
+
+// ListFilter filters the cases returned by Store.List. Empty fields match all
+// cases.
+type ListFilter struct {
+	Kind   string
+	Entity string
+	Status Status
+	// Cursor is the ID of the case after which the cases are listed.
+	Cursor string
+	// Limit is the maximum number of cases returned, all the cases are
+	// returned when it is zero.
+	Limit int
+}
+
+const caseColumns = "id, kind, entity, status, reviewer, created_at, updated_at"
+
+// Create opens a case for the entity.
+func (s *Store) Create(ctx context.Context, kind, entity string) (*Case, error) {
+	if kind == "" || entity == "" {
+		return nil, errors.New("kind and entity cannot be empty")
+	}
+
+	c := Case{}
+	const q = `
+		INSERT INTO compliance_cases (id, kind, entity, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + caseColumns
+	err := s.DB.GetContext(ctx, &c, q, uuid.New().String(), kind, entity, StatusOpen)
+	if err != nil {
+		return nil, errors.Wrap(err, "inserting case")
+	}
+	return &c, nil
+}
+
+// Get returns a case with its notes and attachments.
+func (s *Store) Get(ctx context.Context, id string) (*Case, error) {
+	c := Case{}
+	err := s.DB.GetContext(ctx, &c, `SELECT `+caseColumns+` FROM compliance_cases WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "querying case")
+	}
+
+	err = s.DB.SelectContext(ctx, &c.Notes, `
+		SELECT id, case_id, author, body, created_at
+		FROM compliance_case_notes
+		WHERE case_id = $1
+		ORDER BY created_at, id
+	`, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "querying case notes")
+	}
+
+	err = s.DB.SelectContext(ctx, &c.Attachments, `
+		SELECT id, case_id, name, content_type, url, created_at
+		FROM compliance_case_attachments
+		WHERE case_id = $1
+		ORDER BY created_at, id
+	`, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "querying case attachments")
+	}
+
+	return &c, nil
+}
+
+// List returns the cases matching the filter, oldest first, without their
+// notes and attachments.
+func (s *Store) List(ctx context.Context, filter ListFilter) ([]Case, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+
+	const q = `
+		SELECT ` + caseColumns + `
+		FROM compliance_cases
+		WHERE ($1 = '' OR kind = $1)
+		AND ($2 = '' OR entity = $2)
+		AND ($3 = '' OR status = $3)
+		AND ($4 = '' OR (created_at, id) > (SELECT created_at, id FROM compliance_cases WHERE id = $4))
+		ORDER BY created_at, id
+		LIMIT NULLIF($5, 0)
+	`
+	cases := []Case{}
+	err := s.DB.SelectContext(ctx, &cases, q, filter.Kind, filter.Entity, filter.Status, filter.Cursor, filter.Limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "querying cases")
+	}
+	return cases, nil
+}
+
+// UpdateStatus sets the status of a case. Approving or rejecting a case
+// requires a reviewer. When reviewer is empty the current reviewer is kept.
+func (s *Store) UpdateStatus(ctx context.Context, id string, status Status, reviewer string) (*Case, error) {
+	if !status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+
+	c := Case{}
+	const q = `
+		UPDATE compliance_cases
+		SET status = $2, reviewer = COALESCE(NULLIF($3, ''), reviewer), updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + caseColumns
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "beginning transaction")
+	}
+	defer tx.Rollback()
+
+	err = tx.GetContext(ctx, &c, q, id, status, reviewer)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "updating case status")
+	}
+	if status.Decided() && c.Reviewer == "" {
+		return nil, ErrReviewerRequired
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "committing transaction")
+	}
+	return &c, nil
+}
+
+// AddNote adds a note to a case.
+func (s *Store) AddNote(ctx context.Context, caseID, author, body string) (*Note, error) {
+	if author == "" || body == "" {
+		return nil, errors.New("author and body cannot be empty")
+	}
+
+	n := Note{}
+	const q = `
+		INSERT INTO compliance_case_notes (id, case_id, author, body)
+		SELECT $1, id, $3, $4 FROM compliance_cases WHERE id = $2
+		RETURNING id, case_id, author, body, created_at
+	`
+	err := s.DB.GetContext(ctx, &n, q, uuid.New().String(), caseID, author, body)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "inserting case note")
+	}
+	return &n, nil
+}
+
+// AddAttachment adds a reference to a document to a case.
+func (s *Store) AddAttachment(ctx context.Context, caseID string, a Attachment) (*Attachment, error) {
+	if a.Name == "" || a.URL == "" {
+		return nil, errors.New("name and url cannot be empty")
+	}
+
+	const q = `
+		INSERT INTO compliance_case_attachments (id, case_id, name, content_type, url)
+		SELECT $1, id, $3, $4, $5 FROM compliance_cases WHERE id = $2
+		RETURNING id, case_id, name, content_type, url, created_at
+	`
+	inserted := Attachment{}
+	err := s.DB.GetContext(ctx, &inserted, q, uuid.New().String(), caseID, a.Name, a.ContentType, a.URL)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "inserting case attachment")
+	}
+	return &inserted, nil
+}
@@ -1,3 +1,6 @@
 // Package derivation provides functions for ed25519 key derivation as described in:
 // https://github.com/satoshilabs/slips/blob/master/slip-0010.md
+//
+// It also derives the keypairs of accounts from BIP-39 mnemonics as described
+// in SEP-0005.
 package derivation
@@ -0,0 +1,83 @@
+package derivation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/stellar/go/keypair"
+)
+
+// ErrInvalidMnemonic is returned when a mnemonic has words which are not in
+// the BIP-39 English wordlist, an invalid number of words or a wrong
+// checksum.
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+// NewMnemonic returns a new BIP-39 mnemonic encoding entropyBits bits of
+// randomness, which must be a multiple of 32 between 128 (12 words) and 256
+// (24 words).
+func NewMnemonic(entropyBits int) (string, error) {
+	entropy, err := bip39.NewEntropy(entropyBits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// HDWallet derives the keypairs of the accounts of a BIP-39 mnemonic as
+// described in SEP-0005, i.e. account i has the key derived at the path
+// m/44'/148'/i'.
+type HDWallet struct {
+	// key is the key derived at m/44'/148'.
+	key *Key
+}
+
+// FromMnemonic returns the HDWallet of the mnemonic and passphrase, which
+// can be empty. Words of the mnemonic are separated by whitespace.
+func FromMnemonic(mnemonic, passphrase string) (*HDWallet, error) {
+	mnemonic = strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, ErrInvalidMnemonic
+	}
+
+	key, err := DeriveForPath(StellarAccountPrefix, seed)
+	if err != nil {
+		return nil, err
+	}
+	return &HDWallet{key: key}, nil
+}
+
+// Account returns the keypair of the account with the given index, derived
+// at the path m/44'/148'/index'. The first account has the index 0.
+func (w *HDWallet) Account(index uint32) (*keypair.Full, error) {
+	if index >= FirstHardenedIndex {
+		return nil, fmt.Errorf("account index %d is too large", index)
+	}
+
+	key, err := w.key.Derive(FirstHardenedIndex + index)
+	if err != nil {
+		return nil, err
+	}
+	return keypair.FromRawSeed(key.RawSeed())
+}
+
+// Accounts returns the keypairs of count accounts starting at the index
+// start.
+func (w *HDWallet) Accounts(start, count uint32) ([]*keypair.Full, error) {
+	if uint64(start)+uint64(count) > uint64(FirstHardenedIndex) {
+		return nil, fmt.Errorf("account index %d is too large", uint64(start)+uint64(count)-1)
+	}
+
+	kps := make([]*keypair.Full, 0, count)
+	for i := uint32(0); i < count; i++ {
+		kp, err := w.Account(start + i)
+		if err != nil {
+			return nil, err
+		}
+		kps = append(kps, kp)
+	}
+	return kps, nil
+}
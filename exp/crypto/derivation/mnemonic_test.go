@@ -0,0 +1,84 @@
+package derivation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test vectors from SEP-0005.
+func TestFromMnemonic(t *testing.T) {
+	for _, c := range []struct {
+		mnemonic   string
+		passphrase string
+		accounts   []string
+	}{
+		{
+			mnemonic: "illness spike retreat truth genius clock brain pass fit cave bargain toe",
+			accounts: []string{
+				"GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6 SBGWSG6BTNCKCOB3DIFBGCVMUPQFYPA2G4O34RMTB343OYPXU5DJDVMN",
+				"GBAW5XGWORWVFE2XTJYDTLDHXTY2Q2MO73HYCGB3XMFMQ562Q2W2GJQX SCEPFFWGAG5P2VX5DHIYK3XEMZYLTYWIPWYEKXFHSK25RVMIUNJ7CTIS",
+				"GAY5PRAHJ2HIYBYCLZXTHID6SPVELOOYH2LBPH3LD4RUMXUW3DOYTLXW SDAILLEZCSA67DUEP3XUPZJ7NYG7KGVRM46XA7K5QWWUIGADUZCZWTJP",
+			},
+		},
+		{
+			mnemonic:   "cable spray genius state float twenty onion head street palace net private method loan turn phrase state blanket interest dry amazing dress blast tube",
+			passphrase: "p4ssphr4se",
+			accounts: []string{
+				"GDAHPZ2NSYIIHZXM56Y36SBVTV5QKFIZGYMMBHOU53ETUSWTP62B63EQ SAFWTGXVS7ELMNCXELFWCFZOPMHUZ5LXNBGUVRCY3FHLFPXK4QPXYP2X",
+				"GDY47CJARRHHL66JH3RJURDYXAMIQ5DMXZLP3TDAUJ6IN2GUOFX4OJOC SBQPDFUGLMWJYEYXFRM5TQX3AX2BR47WKI4FDS7EJQUSEUUVY72MZPJF",
+				"GCLAQF5H5LGJ2A6ACOMNEHSWYDJ3VKVBUBHDWFGRBEPAVZ56L4D7JJID SAF2LXRW6FOSVQNC4HHIIDURZL4SCGCG7UEGG23ZQG6Q2DKIGMPZV6BZ",
+			},
+		},
+	} {
+		wallet, err := FromMnemonic(c.mnemonic, c.passphrase)
+		require.NoError(t, err)
+
+		kps, err := wallet.Accounts(0, uint32(len(c.accounts)))
+		require.NoError(t, err)
+		for i, kp := range kps {
+			assert.Equal(t, c.accounts[i], kp.Address()+" "+kp.Seed())
+		}
+
+		kp, err := wallet.Account(1)
+		require.NoError(t, err)
+		assert.Equal(t, kps[1].Seed(), kp.Seed())
+	}
+}
+
+func TestFromMnemonicNormalizesWhitespace(t *testing.T) {
+	wallet, err := FromMnemonic("  Illness spike retreat truth\ngenius clock brain pass fit cave bargain  toe ", "")
+	require.NoError(t, err)
+	kp, err := wallet.Account(0)
+	require.NoError(t, err)
+	assert.Equal(t, "GDRXE2BQUC3AZNPVFSCEZ76NJ3WWL25FYFK6RGZGIEKWE4SOOHSUJUJ6", kp.Address())
+}
+
+func TestFromMnemonicErrors(t *testing.T) {
+	_, err := FromMnemonic("illness spike retreat truth genius clock brain pass fit cave bargain illness", "")
+	assert.Equal(t, ErrInvalidMnemonic, err)
+
+	_, err = FromMnemonic("illness spike retreat", "")
+	assert.Equal(t, ErrInvalidMnemonic, err)
+
+	wallet, err := FromMnemonic("illness spike retreat truth genius clock brain pass fit cave bargain toe", "")
+	require.NoError(t, err)
+	_, err = wallet.Account(1 << 31)
+	assert.EqualError(t, err, "account index 2147483648 is too large")
+	_, err = wallet.Accounts(1<<31-1, 2)
+	assert.EqualError(t, err, "account index 2147483648 is too large")
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic(256)
+	require.NoError(t, err)
+	assert.Len(t, strings.Fields(mnemonic), 24)
+
+	_, err = FromMnemonic(mnemonic, "passphrase")
+	assert.NoError(t, err)
+
+	_, err = NewMnemonic(100)
+	assert.Error(t, err)
+}
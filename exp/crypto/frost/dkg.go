@@ -0,0 +1,289 @@
+package frost
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"filippo.io/edwards25519"
+)
+
+const dkgContext = "stellar-frost-ed25519-v1 dkg"
+
+// DKGRound1Package is broadcast by a participant to all the others in the
+// first round of distributed key generation.
+type DKGRound1Package struct {
+	Identifier uint16
+	// Commitments are the Feldman commitments to the coefficients of the
+	// participant's secret polynomial.
+	Commitments [][32]byte
+	// ProofR and ProofZ prove knowledge of the constant term of the
+	// polynomial, which prevents a participant from choosing its
+	// contribution as a function of the others' (rogue key attacks).
+	ProofR [32]byte
+	ProofZ [32]byte
+}
+
+// DKGRound2Package carries the secret share computed by one participant for
+// another in the second round of distributed key generation. It must be sent
+// over a private, authenticated channel.
+type DKGRound2Package struct {
+	From  uint16
+	To    uint16
+	Share [32]byte
+}
+
+// DKGParticipant runs the distributed key generation protocol of the FROST
+// paper (Pedersen DKG with proofs of knowledge) for a single participant.
+// Unlike Generate, no party ever learns the group secret key.
+//
+// Every participant creates a DKGParticipant, broadcasts the package returned
+// by Round1, passes the packages of all the others to Round2, privately sends
+// each of the returned packages to its recipient and finally passes the
+// packages it received to Finish.
+type DKGParticipant struct {
+	identifier   uint16
+	threshold    int
+	participants int
+	coefficients []*edwards25519.Scalar
+	round1       DKGRound1Package
+	received     map[uint16]DKGRound1Package
+}
+
+// NewDKGParticipant starts distributed key generation for the participant
+// with the given identifier, between 1 and participants.
+func NewDKGParticipant(identifier uint16, threshold, participants int, rand io.Reader) (*DKGParticipant, error) {
+	if err := checkParameters(threshold, participants); err != nil {
+		return nil, err
+	}
+	if identifier == 0 || int(identifier) > participants {
+		return nil, fmt.Errorf("identifier must be between 1 and %d", participants)
+	}
+
+	secret, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	coefficients, err := randomPolynomial(secret, threshold, rand)
+	if err != nil {
+		return nil, err
+	}
+	commitments := commitPolynomial(coefficients)
+
+	k, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroScalar(k)
+	r := encodePoint(baseMul(k))
+	c := dkgChallenge(identifier, commitments[0], r)
+	z := edwards25519.NewScalar().MultiplyAdd(secret, c, k)
+
+	return &DKGParticipant{
+		identifier:   identifier,
+		threshold:    threshold,
+		participants: participants,
+		coefficients: coefficients,
+		round1: DKGRound1Package{
+			Identifier:  identifier,
+			Commitments: commitments,
+			ProofR:      r,
+			ProofZ:      encodeScalar(z),
+		},
+	}, nil
+}
+
+// Round1 returns the package to broadcast to all the other participants.
+func (p *DKGParticipant) Round1() DKGRound1Package {
+	return p.round1
+}
+
+// Round2 verifies the round one packages of all the other participants and
+// returns the packages to send privately to each of them.
+func (p *DKGParticipant) Round2(packages []DKGRound1Package) ([]DKGRound2Package, error) {
+	if p.coefficients == nil {
+		return nil, errors.New("key generation has already finished")
+	}
+	if len(packages) != p.participants-1 {
+		return nil, fmt.Errorf("expected %d round one packages, got %d", p.participants-1, len(packages))
+	}
+
+	received := map[uint16]DKGRound1Package{}
+	for _, pkg := range packages {
+		if pkg.Identifier == 0 || int(pkg.Identifier) > p.participants || pkg.Identifier == p.identifier {
+			return nil, fmt.Errorf("unexpected round one package from participant %d", pkg.Identifier)
+		}
+		if _, ok := received[pkg.Identifier]; ok {
+			return nil, fmt.Errorf("duplicate round one package from participant %d", pkg.Identifier)
+		}
+		if err := pkg.verify(p.threshold); err != nil {
+			return nil, fmt.Errorf("invalid round one package from participant %d: %v", pkg.Identifier, err)
+		}
+		received[pkg.Identifier] = pkg
+	}
+	p.received = received
+
+	var shares []DKGRound2Package
+	for identifier := 1; identifier <= p.participants; identifier++ {
+		if uint16(identifier) == p.identifier {
+			continue
+		}
+		share := evaluatePolynomial(p.coefficients, uint16(identifier))
+		shares = append(shares, DKGRound2Package{
+			From:  p.identifier,
+			To:    uint16(identifier),
+			Share: encodeScalar(share),
+		})
+		zeroScalar(share)
+	}
+	return shares, nil
+}
+
+// Finish verifies the packages received from all the other participants in
+// the second round and returns the participant's KeyShare together with the
+// public key package, which is the same for every participant.
+func (p *DKGParticipant) Finish(packages []DKGRound2Package) (KeyShare, PublicKeyPackage, error) {
+	if p.coefficients == nil {
+		return KeyShare{}, PublicKeyPackage{}, errors.New("key generation has already finished")
+	}
+	if p.received == nil {
+		return KeyShare{}, PublicKeyPackage{}, errors.New("round two has not been run")
+	}
+	if len(packages) != p.participants-1 {
+		return KeyShare{}, PublicKeyPackage{}, fmt.Errorf("expected %d round two packages, got %d", p.participants-1, len(packages))
+	}
+
+	secret := evaluatePolynomial(p.coefficients, p.identifier)
+	seen := map[uint16]bool{}
+	for _, pkg := range packages {
+		round1, ok := p.received[pkg.From]
+		if !ok || seen[pkg.From] || pkg.To != p.identifier {
+			return KeyShare{}, PublicKeyPackage{}, fmt.Errorf("unexpected round two package from participant %d", pkg.From)
+		}
+		seen[pkg.From] = true
+
+		share, err := decodeScalar(pkg.Share)
+		if err != nil {
+			return KeyShare{}, PublicKeyPackage{}, fmt.Errorf("invalid share from participant %d: %v", pkg.From, err)
+		}
+		expected, err := evaluateCommitments(round1.Commitments, p.identifier)
+		if err != nil {
+			return KeyShare{}, PublicKeyPackage{}, err
+		}
+		if encodePoint(baseMul(share)) != expected {
+			return KeyShare{}, PublicKeyPackage{}, fmt.Errorf("share from participant %d does not match its commitments", pkg.From)
+		}
+		secret.Add(secret, share)
+		zeroScalar(share)
+	}
+
+	defer zeroScalar(secret)
+
+	publicKeys, err := p.publicKeyPackage()
+	if err != nil {
+		return KeyShare{}, PublicKeyPackage{}, err
+	}
+	keyShare := KeyShare{
+		Identifier:     p.identifier,
+		Secret:         encodeScalar(secret),
+		PublicKey:      publicKeys.VerificationShares[p.identifier],
+		GroupPublicKey: publicKeys.GroupPublicKey,
+		Threshold:      p.threshold,
+	}
+	if encodePoint(baseMul(secret)) != keyShare.PublicKey {
+		return KeyShare{}, PublicKeyPackage{}, errors.New("public key does not match the secret share")
+	}
+
+	for _, coefficient := range p.coefficients {
+		zeroScalar(coefficient)
+	}
+	p.coefficients = nil
+
+	return keyShare, publicKeys, nil
+}
+
+// publicKeyPackage derives the group public key and the verification shares
+// of all participants from the commitments of every participant.
+func (p *DKGParticipant) publicKeyPackage() (PublicKeyPackage, error) {
+	all := [][][32]byte{p.round1.Commitments}
+	for _, pkg := range p.received {
+		all = append(all, pkg.Commitments)
+	}
+
+	groupPublicKey := edwards25519.NewIdentityPoint()
+	verificationShares := make([]*edwards25519.Point, p.participants+1)
+	for identifier := 1; identifier <= p.participants; identifier++ {
+		verificationShares[identifier] = edwards25519.NewIdentityPoint()
+	}
+	for _, commitments := range all {
+		constant, err := decodePoint(commitments[0])
+		if err != nil {
+			return PublicKeyPackage{}, err
+		}
+		groupPublicKey.Add(groupPublicKey, constant)
+
+		for identifier := 1; identifier <= p.participants; identifier++ {
+			encoded, err := evaluateCommitments(commitments, uint16(identifier))
+			if err != nil {
+				return PublicKeyPackage{}, err
+			}
+			point, err := decodePoint(encoded)
+			if err != nil {
+				return PublicKeyPackage{}, err
+			}
+			verificationShares[identifier].Add(verificationShares[identifier], point)
+		}
+	}
+
+	publicKeys := PublicKeyPackage{
+		GroupPublicKey:     encodePoint(groupPublicKey),
+		VerificationShares: map[uint16][32]byte{},
+		Threshold:          p.threshold,
+	}
+	for identifier := 1; identifier <= p.participants; identifier++ {
+		publicKeys.VerificationShares[uint16(identifier)] = encodePoint(verificationShares[identifier])
+	}
+	return publicKeys, nil
+}
+
+// verify checks the proof of knowledge of the package.
+func (pkg DKGRound1Package) verify(threshold int) error {
+	if len(pkg.Commitments) != threshold {
+		return fmt.Errorf("expected %d commitments, got %d", threshold, len(pkg.Commitments))
+	}
+	constant, err := decodePoint(pkg.Commitments[0])
+	if err != nil {
+		return err
+	}
+	r, err := decodePoint(pkg.ProofR)
+	if err != nil {
+		return err
+	}
+	z, err := decodeScalar(pkg.ProofZ)
+	if err != nil {
+		return err
+	}
+
+	// z*B == R + c*C0
+	c := dkgChallenge(pkg.Identifier, pkg.Commitments[0], pkg.ProofR)
+	expected := new(edwards25519.Point).VarTimeMultiScalarMult(
+		[]*edwards25519.Scalar{c},
+		[]*edwards25519.Point{constant},
+	)
+	expected.Add(expected, r)
+	if baseMul(z).Equal(expected) != 1 {
+		return errors.New("proof of knowledge does not verify")
+	}
+	return nil
+}
+
+func dkgChallenge(identifier uint16, constant, r [32]byte) *edwards25519.Scalar {
+	h := sha512.New()
+	h.Write([]byte(dkgContext))
+	binary.Write(h, binary.BigEndian, identifier)
+	h.Write(constant[:])
+	h.Write(r[:])
+	return scalarReduce(h.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package frost
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runDKG runs the distributed key generation protocol between all the
+// participants, letting tamper modify the round two packages in flight.
+func runDKG(t *testing.T, threshold, participants int, tamper func([]DKGRound2Package)) ([]KeyShare, []PublicKeyPackage, error) {
+	var dkgParticipants []*DKGParticipant
+	var round1 []DKGRound1Package
+	for i := 1; i <= participants; i++ {
+		p, err := NewDKGParticipant(uint16(i), threshold, participants, rand.Reader)
+		require.NoError(t, err)
+		dkgParticipants = append(dkgParticipants, p)
+		round1 = append(round1, p.Round1())
+	}
+
+	var round2 []DKGRound2Package
+	for i, p := range dkgParticipants {
+		var others []DKGRound1Package
+		others = append(others, round1[:i]...)
+		others = append(others, round1[i+1:]...)
+		packages, err := p.Round2(others)
+		require.NoError(t, err)
+		round2 = append(round2, packages...)
+	}
+	if tamper != nil {
+		tamper(round2)
+	}
+
+	var keyShares []KeyShare
+	var publicKeys []PublicKeyPackage
+	for _, p := range dkgParticipants {
+		var received []DKGRound2Package
+		for _, pkg := range round2 {
+			if pkg.To == p.identifier {
+				received = append(received, pkg)
+			}
+		}
+		keyShare, pkg, err := p.Finish(received)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyShares = append(keyShares, keyShare)
+		publicKeys = append(publicKeys, pkg)
+	}
+	return keyShares, publicKeys, nil
+}
+
+func TestDKG(t *testing.T) {
+	keyShares, publicKeys, err := runDKG(t, 2, 3, nil)
+	require.NoError(t, err)
+	for _, pkg := range publicKeys[1:] {
+		assert.Equal(t, publicKeys[0], pkg)
+	}
+	for _, keyShare := range keyShares {
+		assert.Equal(t, publicKeys[0].VerificationShares[keyShare.Identifier], keyShare.PublicKey)
+	}
+
+	dealing := &Dealing{PublicKeys: publicKeys[0]}
+	message := []byte("message")
+	for _, signers := range [][]int{{0, 1}, {1, 2}, {0, 1, 2}} {
+		var shares []KeyShare
+		for _, i := range signers {
+			shares = append(shares, keyShares[i])
+		}
+		signature, err := sign(t, dealing, shares, message)
+		require.NoError(t, err)
+		assert.True(t, ed25519.Verify(publicKeys[0].GroupPublicKey[:], message, signature))
+	}
+}
+
+func TestDKGTamperedShare(t *testing.T) {
+	_, _, err := runDKG(t, 2, 3, func(packages []DKGRound2Package) {
+		packages[0].Share[0] ^= 1
+	})
+	assert.EqualError(t, err, "share from participant 1 does not match its commitments")
+}
+
+func TestDKGInvalidProof(t *testing.T) {
+	p1, err := NewDKGParticipant(1, 2, 2, rand.Reader)
+	require.NoError(t, err)
+	p2, err := NewDKGParticipant(2, 2, 2, rand.Reader)
+	require.NoError(t, err)
+
+	pkg := p2.Round1()
+	pkg.ProofZ = p1.Round1().ProofZ
+	_, err = p1.Round2([]DKGRound1Package{pkg})
+	assert.EqualError(t, err, "invalid round one package from participant 2: proof of knowledge does not verify")
+
+	_, err = p1.Round2([]DKGRound1Package{p1.Round1()})
+	assert.EqualError(t, err, "unexpected round one package from participant 1")
+}
+
+func TestDKGInvalidParameters(t *testing.T) {
+	_, err := NewDKGParticipant(1, 3, 2, rand.Reader)
+	assert.Equal(t, ErrInvalidThreshold, err)
+	_, err = NewDKGParticipant(3, 2, 2, rand.Reader)
+	assert.EqualError(t, err, "identifier must be between 1 and 2")
+}
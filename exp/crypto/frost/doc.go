@@ -0,0 +1,26 @@
+// Package frost provides experimental support for FROST threshold signing
+// (https://datatracker.ietf.org/doc/draft-irtf-cfrg-frost/) over ed25519.
+//
+// A signing key is split into shares held by separate participants, any
+// threshold of which can cooperate to produce a standard ed25519 signature
+// that verifies against the group public key, i.e. a regular Stellar
+// address. The protocol has three steps:
+//
+//  1. Key generation: either the participants run a distributed key
+//     generation (DKGParticipant), after which no single party has ever known
+//     the group secret key, or a trusted dealer splits an existing keypair
+//     (Split) or a fresh random key (Generate) into KeyShares, publishing
+//     Feldman commitments so every participant can verify its share. A
+//     dealer sees the whole secret key, so it must run on a trusted machine
+//     that discards the key once the shares are distributed.
+//  2. Round one: every signing participant generates single-use Nonces and
+//     sends the corresponding Commitment to the coordinator.
+//  3. Round two: the coordinator sends the message and the list of
+//     commitments to the participants, who each return a SignatureShare. The
+//     coordinator then calls Aggregate to verify the shares and combine them
+//     into the final signature.
+//
+// Group arithmetic on secrets is constant time, provided by
+// filippo.io/edwards25519. This package is nevertheless experimental and has
+// not been audited.
+package frost
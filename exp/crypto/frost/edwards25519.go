@@ -0,0 +1,80 @@
+package frost
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"filippo.io/edwards25519"
+)
+
+// This file contains the helpers converting between the wire encodings used
+// by this package and the edwards25519 group elements. The group arithmetic
+// itself is delegated to filippo.io/edwards25519, whose scalar
+// multiplications run in constant time so that secret shares and nonces do
+// not leak through timing side channels.
+
+var (
+	errInvalidPoint  = errors.New("invalid point encoding")
+	errInvalidScalar = errors.New("invalid scalar encoding")
+)
+
+// decodePoint parses the RFC 8032 encoding of a point.
+func decodePoint(encoded [32]byte) (*edwards25519.Point, error) {
+	p, err := new(edwards25519.Point).SetBytes(encoded[:])
+	if err != nil {
+		return nil, errInvalidPoint
+	}
+	return p, nil
+}
+
+// encodePoint returns the RFC 8032 encoding of p.
+func encodePoint(p *edwards25519.Point) (out [32]byte) {
+	copy(out[:], p.Bytes())
+	return
+}
+
+// baseMul returns [s]B in constant time.
+func baseMul(s *edwards25519.Scalar) *edwards25519.Point {
+	return new(edwards25519.Point).ScalarBaseMult(s)
+}
+
+// decodeScalar parses a canonical 32 byte little-endian scalar.
+func decodeScalar(encoded [32]byte) (*edwards25519.Scalar, error) {
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(encoded[:])
+	if err != nil {
+		return nil, errInvalidScalar
+	}
+	return s, nil
+}
+
+// encodeScalar returns the 32 byte little-endian encoding of s.
+func encodeScalar(s *edwards25519.Scalar) (out [32]byte) {
+	copy(out[:], s.Bytes())
+	return
+}
+
+// scalarReduce returns the 64 byte little-endian integer b, typically a
+// SHA-512 digest, reduced modulo the group order.
+func scalarReduce(b []byte) *edwards25519.Scalar {
+	s, err := edwards25519.NewScalar().SetUniformBytes(b)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// scalarFromIdentifier returns the participant identifier as a scalar.
+func scalarFromIdentifier(identifier uint16) *edwards25519.Scalar {
+	var b [32]byte
+	binary.LittleEndian.PutUint16(b[:], identifier)
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(b[:])
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// zeroScalar overwrites the secret value of s.
+func zeroScalar(s *edwards25519.Scalar) {
+	s.Set(edwards25519.NewScalar())
+}
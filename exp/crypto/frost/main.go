@@ -0,0 +1,553 @@
+package frost
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"filippo.io/edwards25519"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	// MaxParticipants is the maximum number of participants a key can be
+	// split into.
+	MaxParticipants = 0xffff
+
+	bindingFactorContext = "stellar-frost-ed25519-v1 rho"
+	nonceContext         = "stellar-frost-ed25519-v1 nonce"
+)
+
+var (
+	ErrInvalidThreshold    = errors.New("threshold must be between 1 and the number of participants")
+	ErrInvalidParticipants = errors.New("invalid number of participants")
+	ErrNoncesUsed          = errors.New("nonces have already been used")
+	ErrNotEnoughSigners    = errors.New("not enough signers to reach the threshold")
+	ErrInvalidSignature    = errors.New("aggregated signature is not valid")
+)
+
+// KeyShare is the share of a group signing key held by a single participant.
+// Secret must never leave the participant.
+type KeyShare struct {
+	// Identifier is the non-zero identifier of the participant.
+	Identifier uint16
+	// Secret is the participant's share of the group secret scalar.
+	Secret [32]byte
+	// PublicKey is the verification share of the participant, used by the
+	// coordinator to check its signature shares.
+	PublicKey [32]byte
+	// GroupPublicKey is the ed25519 public key signatures are produced for.
+	GroupPublicKey [32]byte
+	// Threshold is the minimum number of participants needed to sign.
+	Threshold int
+}
+
+// PublicKeyPackage contains the public information about a split key needed
+// by the coordinator of a signing session.
+type PublicKeyPackage struct {
+	GroupPublicKey     [32]byte
+	VerificationShares map[uint16][32]byte
+	Threshold          int
+}
+
+// Dealing is the outcome of a key generation ceremony.
+type Dealing struct {
+	// Shares must be distributed privately, one to each participant.
+	Shares []KeyShare
+	// Commitments are the Feldman commitments to the coefficients of the
+	// secret sharing polynomial. They are public and let every participant
+	// verify its share with KeyShare.Verify.
+	Commitments [][32]byte
+	PublicKeys  PublicKeyPackage
+}
+
+// Commitment is the public part of the nonces generated by a participant in
+// the first round of a signing session.
+type Commitment struct {
+	Identifier uint16
+	Hiding     [32]byte
+	Binding    [32]byte
+}
+
+// Nonces are the secret, single-use nonces generated by a participant in the
+// first round of a signing session. They must be kept by the participant
+// until the second round and never reused.
+type Nonces struct {
+	identifier uint16
+	hiding     *edwards25519.Scalar
+	binding    *edwards25519.Scalar
+	commitment Commitment
+	used       bool
+}
+
+// Commitment returns the commitment to share with the coordinator.
+func (n *Nonces) Commitment() Commitment {
+	return n.commitment
+}
+
+// SignatureShare is the contribution of a participant to a signature.
+type SignatureShare struct {
+	Identifier uint16
+	Z          [32]byte
+}
+
+// Generate runs a trusted dealer key generation ceremony for a new random
+// key, split into the given number of participants.
+//
+// The dealer knows the whole secret key while dealing, so Generate must run
+// on a trusted machine that discards the dealing once the shares have been
+// distributed. Use a DKGParticipant instead to generate a key that no single
+// party ever learns.
+func Generate(threshold, participants int, rand io.Reader) (*Dealing, error) {
+	secret, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	return deal(secret, threshold, participants, rand)
+}
+
+// Split runs a trusted dealer key generation ceremony splitting an existing
+// keypair, e.g. an issuing account, into the given number of participants.
+// Signatures aggregated from the shares verify against kp's address.
+//
+// As with Generate, the dealer holds the whole secret key, which is inherent
+// to splitting an existing key.
+func Split(kp *keypair.Full, threshold, participants int, rand io.Reader) (*Dealing, error) {
+	rawSeed, err := strkey.Decode(strkey.VersionByteSeed, kp.Seed())
+	if err != nil {
+		return nil, err
+	}
+
+	// Derive the secret scalar the same way ed25519 does.
+	h := sha512.Sum512(rawSeed)
+	secret, err := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	return deal(secret, threshold, participants, rand)
+}
+
+func deal(secret *edwards25519.Scalar, threshold, participants int, rand io.Reader) (*Dealing, error) {
+	if err := checkParameters(threshold, participants); err != nil {
+		return nil, err
+	}
+
+	coefficients, err := randomPolynomial(secret, threshold, rand)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, coefficient := range coefficients {
+			zeroScalar(coefficient)
+		}
+	}()
+
+	dealing := &Dealing{
+		PublicKeys: PublicKeyPackage{
+			VerificationShares: map[uint16][32]byte{},
+			Threshold:          threshold,
+		},
+	}
+	dealing.Commitments = commitPolynomial(coefficients)
+	dealing.PublicKeys.GroupPublicKey = dealing.Commitments[0]
+
+	for i := 1; i <= participants; i++ {
+		identifier := uint16(i)
+		share := evaluatePolynomial(coefficients, identifier)
+		publicKey := encodePoint(baseMul(share))
+		dealing.Shares = append(dealing.Shares, KeyShare{
+			Identifier:     identifier,
+			Secret:         encodeScalar(share),
+			PublicKey:      publicKey,
+			GroupPublicKey: dealing.PublicKeys.GroupPublicKey,
+			Threshold:      threshold,
+		})
+		dealing.PublicKeys.VerificationShares[identifier] = publicKey
+	}
+
+	return dealing, nil
+}
+
+// Verify checks the share against the Feldman commitments published by the
+// dealer.
+func (s KeyShare) Verify(commitments [][32]byte) error {
+	if len(commitments) != s.Threshold {
+		return fmt.Errorf("expected %d commitments, got %d", s.Threshold, len(commitments))
+	}
+	if commitments[0] != s.GroupPublicKey {
+		return errors.New("group public key does not match the commitments")
+	}
+
+	secret, err := decodeScalar(s.Secret)
+	if err != nil {
+		return err
+	}
+	if encodePoint(baseMul(secret)) != s.PublicKey {
+		return errors.New("public key does not match the secret share")
+	}
+
+	expected, err := evaluateCommitments(commitments, s.Identifier)
+	if err != nil {
+		return err
+	}
+	if expected != s.PublicKey {
+		return errors.New("secret share does not match the commitments")
+	}
+	return nil
+}
+
+// Commit generates the nonces for a signing session, the first round of the
+// protocol. The returned Nonces can only be used to sign once.
+func (s KeyShare) Commit(rand io.Reader) (*Nonces, error) {
+	hiding, err := s.nonce(rand)
+	if err != nil {
+		return nil, err
+	}
+	binding, err := s.nonce(rand)
+	if err != nil {
+		return nil, err
+	}
+	return &Nonces{
+		identifier: s.Identifier,
+		hiding:     hiding,
+		binding:    binding,
+		commitment: Commitment{
+			Identifier: s.Identifier,
+			Hiding:     encodePoint(baseMul(hiding)),
+			Binding:    encodePoint(baseMul(binding)),
+		},
+	}, nil
+}
+
+// nonce generates a nonce from fresh randomness hedged with the secret share,
+// so that a weak random source does not leak the share.
+func (s KeyShare) nonce(rand io.Reader) (*edwards25519.Scalar, error) {
+	var random [32]byte
+	if _, err := io.ReadFull(rand, random[:]); err != nil {
+		return nil, err
+	}
+	h := sha512.New()
+	h.Write([]byte(nonceContext))
+	h.Write(random[:])
+	h.Write(s.Secret[:])
+	return scalarReduce(h.Sum(nil)), nil
+}
+
+// Sign produces the participant's signature share for message, the second
+// round of the protocol. commitments must contain the commitments of all the
+// participants of the signing session, including this one.
+func (s KeyShare) Sign(message []byte, nonces *Nonces, commitments []Commitment) (SignatureShare, error) {
+	if nonces.used {
+		return SignatureShare{}, ErrNoncesUsed
+	}
+	if nonces.identifier != s.Identifier {
+		return SignatureShare{}, errors.New("nonces belong to another participant")
+	}
+
+	ctx, err := newSigningContext(s.GroupPublicKey, s.Threshold, message, commitments)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+	commitment, ok := ctx.commitments[s.Identifier]
+	if !ok {
+		return SignatureShare{}, errors.New("participant is not part of the signing session")
+	}
+	if commitment != nonces.commitment {
+		return SignatureShare{}, errors.New("commitment does not match the nonces")
+	}
+
+	secret, err := decodeScalar(s.Secret)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+
+	// Nonces must never be reused, even if signing fails afterwards.
+	nonces.used = true
+	defer func() {
+		zeroScalar(nonces.hiding)
+		zeroScalar(nonces.binding)
+		zeroScalar(secret)
+	}()
+
+	// z = d + e*rho + lambda*s*c
+	lc := edwards25519.NewScalar().Multiply(ctx.lagrangeCoefficient(s.Identifier), ctx.challenge)
+	z := edwards25519.NewScalar().MultiplyAdd(lc, secret, nonces.hiding)
+	z.MultiplyAdd(nonces.binding, ctx.bindingFactors[s.Identifier], z)
+
+	return SignatureShare{Identifier: s.Identifier, Z: encodeScalar(z)}, nil
+}
+
+// Aggregate verifies the signature shares of a signing session and combines
+// them into an ed25519 signature of message by the group public key.
+func Aggregate(publicKeys PublicKeyPackage, message []byte, commitments []Commitment, shares []SignatureShare) ([]byte, error) {
+	ctx, err := newSigningContext(publicKeys.GroupPublicKey, publicKeys.Threshold, message, commitments)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) != len(ctx.identifiers) {
+		return nil, fmt.Errorf("expected %d signature shares, got %d", len(ctx.identifiers), len(shares))
+	}
+
+	z := edwards25519.NewScalar()
+	seen := map[uint16]bool{}
+	for _, share := range shares {
+		commitment, ok := ctx.commitments[share.Identifier]
+		if !ok || seen[share.Identifier] {
+			return nil, fmt.Errorf("unexpected signature share from participant %d", share.Identifier)
+		}
+		seen[share.Identifier] = true
+
+		if err := ctx.verifyShare(publicKeys, commitment, share); err != nil {
+			return nil, fmt.Errorf("invalid signature share from participant %d: %v", share.Identifier, err)
+		}
+		zi, _ := decodeScalar(share.Z)
+		z.Add(z, zi)
+	}
+
+	groupCommitment := encodePoint(ctx.groupCommitment)
+	encodedZ := encodeScalar(z)
+	signature := append(groupCommitment[:], encodedZ[:]...)
+	if !ed25519.Verify(publicKeys.GroupPublicKey[:], message, signature) {
+		return nil, ErrInvalidSignature
+	}
+	return signature, nil
+}
+
+// Address returns the Stellar address of the group public key.
+func (p PublicKeyPackage) Address() string {
+	return strkey.MustEncode(strkey.VersionByteAccountID, p.GroupPublicKey[:])
+}
+
+// Hint returns the signature hint of the group public key.
+func (p PublicKeyPackage) Hint() (r [4]byte) {
+	copy(r[:], p.GroupPublicKey[28:])
+	return
+}
+
+// DecoratedSignature wraps a signature returned by Aggregate so it can be
+// added to a transaction envelope.
+func (p PublicKeyPackage) DecoratedSignature(signature []byte) xdr.DecoratedSignature {
+	return xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(p.Hint()),
+		Signature: xdr.Signature(signature),
+	}
+}
+
+// signingContext holds the values derived from the message and the
+// commitments of a signing session, shared by all participants.
+type signingContext struct {
+	identifiers     []uint16
+	commitments     map[uint16]Commitment
+	bindingFactors  map[uint16]*edwards25519.Scalar
+	groupCommitment *edwards25519.Point
+	challenge       *edwards25519.Scalar
+}
+
+func newSigningContext(groupPublicKey [32]byte, threshold int, message []byte, commitments []Commitment) (*signingContext, error) {
+	if len(commitments) < threshold {
+		return nil, ErrNotEnoughSigners
+	}
+
+	ctx := &signingContext{
+		commitments:    map[uint16]Commitment{},
+		bindingFactors: map[uint16]*edwards25519.Scalar{},
+	}
+	for _, commitment := range commitments {
+		if commitment.Identifier == 0 {
+			return nil, errors.New("participant identifiers must be non-zero")
+		}
+		if _, ok := ctx.commitments[commitment.Identifier]; ok {
+			return nil, fmt.Errorf("duplicate commitment from participant %d", commitment.Identifier)
+		}
+		ctx.commitments[commitment.Identifier] = commitment
+		ctx.identifiers = append(ctx.identifiers, commitment.Identifier)
+	}
+	sort.Slice(ctx.identifiers, func(i, j int) bool {
+		return ctx.identifiers[i] < ctx.identifiers[j]
+	})
+
+	// The binding factors bind every nonce to the message and to the whole
+	// set of commitments, which prevents forgeries from concurrent sessions.
+	var encodedCommitments bytes.Buffer
+	for _, identifier := range ctx.identifiers {
+		commitment := ctx.commitments[identifier]
+		binary.Write(&encodedCommitments, binary.BigEndian, identifier)
+		encodedCommitments.Write(commitment.Hiding[:])
+		encodedCommitments.Write(commitment.Binding[:])
+	}
+	messageHash := sha512.Sum512(message)
+	commitmentsHash := sha512.Sum512(encodedCommitments.Bytes())
+
+	ctx.groupCommitment = edwards25519.NewIdentityPoint()
+	for _, identifier := range ctx.identifiers {
+		h := sha512.New()
+		h.Write([]byte(bindingFactorContext))
+		h.Write(groupPublicKey[:])
+		h.Write(messageHash[:])
+		h.Write(commitmentsHash[:])
+		binary.Write(h, binary.BigEndian, identifier)
+		rho := scalarReduce(h.Sum(nil))
+		ctx.bindingFactors[identifier] = rho
+
+		hiding, err := decodePoint(ctx.commitments[identifier].Hiding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hiding commitment from participant %d", identifier)
+		}
+		binding, err := decodePoint(ctx.commitments[identifier].Binding)
+		if err != nil {
+			return nil, fmt.Errorf("invalid binding commitment from participant %d", identifier)
+		}
+		// Commitments are public, so variable time arithmetic is fine here.
+		ctx.groupCommitment.Add(ctx.groupCommitment, hiding)
+		ctx.groupCommitment.Add(ctx.groupCommitment, new(edwards25519.Point).VarTimeMultiScalarMult(
+			[]*edwards25519.Scalar{rho},
+			[]*edwards25519.Point{binding},
+		))
+	}
+
+	// The challenge is computed exactly as in ed25519 so that the aggregated
+	// signature is a standard one.
+	r := encodePoint(ctx.groupCommitment)
+	h := sha512.New()
+	h.Write(r[:])
+	h.Write(groupPublicKey[:])
+	h.Write(message)
+	ctx.challenge = scalarReduce(h.Sum(nil))
+
+	return ctx, nil
+}
+
+// lagrangeCoefficient returns the Lagrange coefficient of identifier at zero
+// over the identifiers of the signing session.
+func (ctx *signingContext) lagrangeCoefficient(identifier uint16) *edwards25519.Scalar {
+	numerator := scalarFromIdentifier(1)
+	denominator := scalarFromIdentifier(1)
+	x := scalarFromIdentifier(identifier)
+	for _, other := range ctx.identifiers {
+		if other == identifier {
+			continue
+		}
+		xj := scalarFromIdentifier(other)
+		numerator.Multiply(numerator, xj)
+		denominator.Multiply(denominator, edwards25519.NewScalar().Subtract(xj, x))
+	}
+	denominator.Invert(denominator)
+	return numerator.Multiply(numerator, denominator)
+}
+
+// verifyShare checks z*B == D + rho*E + (c*lambda)*Y for a signature share.
+func (ctx *signingContext) verifyShare(publicKeys PublicKeyPackage, commitment Commitment, share SignatureShare) error {
+	encodedPublicKey, ok := publicKeys.VerificationShares[share.Identifier]
+	if !ok {
+		return errors.New("unknown participant")
+	}
+	publicKey, err := decodePoint(encodedPublicKey)
+	if err != nil {
+		return err
+	}
+	z, err := decodeScalar(share.Z)
+	if err != nil {
+		return err
+	}
+	hiding, err := decodePoint(commitment.Hiding)
+	if err != nil {
+		return err
+	}
+	binding, err := decodePoint(commitment.Binding)
+	if err != nil {
+		return err
+	}
+
+	// Everything involved is public, so variable time arithmetic is fine.
+	cl := edwards25519.NewScalar().Multiply(ctx.challenge, ctx.lagrangeCoefficient(share.Identifier))
+	expected := new(edwards25519.Point).VarTimeMultiScalarMult(
+		[]*edwards25519.Scalar{ctx.bindingFactors[share.Identifier], cl},
+		[]*edwards25519.Point{binding, publicKey},
+	)
+	expected.Add(expected, hiding)
+	if baseMul(z).Equal(expected) != 1 {
+		return errors.New("signature share does not verify")
+	}
+	return nil
+}
+
+// randomScalar returns a uniformly distributed random scalar.
+func randomScalar(rand io.Reader) (*edwards25519.Scalar, error) {
+	var b [64]byte
+	if _, err := io.ReadFull(rand, b[:]); err != nil {
+		return nil, err
+	}
+	return scalarReduce(b[:]), nil
+}
+
+func checkParameters(threshold, participants int) error {
+	if participants < 1 || participants > MaxParticipants {
+		return ErrInvalidParticipants
+	}
+	if threshold < 1 || threshold > participants {
+		return ErrInvalidThreshold
+	}
+	return nil
+}
+
+// randomPolynomial returns the coefficients of a random polynomial of degree
+// threshold-1 whose constant term is secret.
+func randomPolynomial(secret *edwards25519.Scalar, threshold int, rand io.Reader) ([]*edwards25519.Scalar, error) {
+	coefficients := []*edwards25519.Scalar{secret}
+	for i := 1; i < threshold; i++ {
+		coefficient, err := randomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		coefficients = append(coefficients, coefficient)
+	}
+	return coefficients, nil
+}
+
+// commitPolynomial returns the Feldman commitments to the coefficients.
+func commitPolynomial(coefficients []*edwards25519.Scalar) [][32]byte {
+	commitments := make([][32]byte, 0, len(coefficients))
+	for _, coefficient := range coefficients {
+		commitments = append(commitments, encodePoint(baseMul(coefficient)))
+	}
+	return commitments
+}
+
+// evaluatePolynomial returns the value of the polynomial at the identifier
+// using Horner's method.
+func evaluatePolynomial(coefficients []*edwards25519.Scalar, identifier uint16) *edwards25519.Scalar {
+	x := scalarFromIdentifier(identifier)
+	value := edwards25519.NewScalar()
+	for j := len(coefficients) - 1; j >= 0; j-- {
+		value.MultiplyAdd(value, x, coefficients[j])
+	}
+	return value
+}
+
+// evaluateCommitments returns the commitment to the value of the committed
+// polynomial at the identifier, i.e. the expected public key of the share of
+// that participant.
+func evaluateCommitments(commitments [][32]byte, identifier uint16) ([32]byte, error) {
+	x := scalarFromIdentifier(identifier)
+	power := scalarFromIdentifier(1)
+	var scalars []*edwards25519.Scalar
+	var points []*edwards25519.Point
+	for _, encoded := range commitments {
+		commitment, err := decodePoint(encoded)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		scalars = append(scalars, edwards25519.NewScalar().Set(power))
+		points = append(points, commitment)
+		power.Multiply(power, x)
+	}
+	return encodePoint(new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)), nil
+}
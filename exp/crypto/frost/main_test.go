@@ -0,0 +1,123 @@
+package frost
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sign runs both rounds of a signing session with the given shares and
+// returns the aggregated signature.
+func sign(t *testing.T, dealing *Dealing, shares []KeyShare, message []byte) ([]byte, error) {
+	var nonces []*Nonces
+	var commitments []Commitment
+	for _, share := range shares {
+		n, err := share.Commit(rand.Reader)
+		require.NoError(t, err)
+		nonces = append(nonces, n)
+		commitments = append(commitments, n.Commitment())
+	}
+
+	var signatureShares []SignatureShare
+	for i, share := range shares {
+		signatureShare, err := share.Sign(message, nonces[i], commitments)
+		require.NoError(t, err)
+		signatureShares = append(signatureShares, signatureShare)
+	}
+
+	return Aggregate(dealing.PublicKeys, message, commitments, signatureShares)
+}
+
+func TestSplit(t *testing.T) {
+	kp := keypair.MustRandom()
+	dealing, err := Split(kp, 2, 3, rand.Reader)
+	require.NoError(t, err)
+	assert.Equal(t, kp.Address(), dealing.PublicKeys.Address())
+	assert.Equal(t, kp.Hint(), dealing.PublicKeys.Hint())
+	require.Len(t, dealing.Shares, 3)
+
+	for _, share := range dealing.Shares {
+		assert.NoError(t, share.Verify(dealing.Commitments))
+	}
+
+	message := []byte("transaction hash")
+	for _, signers := range [][]int{{0, 1}, {1, 2}, {2, 0}, {0, 1, 2}} {
+		var shares []KeyShare
+		for _, i := range signers {
+			shares = append(shares, dealing.Shares[i])
+		}
+		signature, err := sign(t, dealing, shares, message)
+		require.NoError(t, err)
+		assert.NoError(t, kp.FromAddress().Verify(message, signature))
+
+		decorated := dealing.PublicKeys.DecoratedSignature(signature)
+		expected, err := kp.SignDecorated(message)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Hint, decorated.Hint)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dealing, err := Generate(3, 5, rand.Reader)
+	require.NoError(t, err)
+
+	message := []byte("message")
+	signature, err := sign(t, dealing, dealing.Shares[1:4], message)
+	require.NoError(t, err)
+
+	kp, err := keypair.ParseAddress(dealing.PublicKeys.Address())
+	require.NoError(t, err)
+	assert.NoError(t, kp.Verify(message, signature))
+}
+
+func TestInvalidParameters(t *testing.T) {
+	_, err := Generate(0, 3, rand.Reader)
+	assert.Equal(t, ErrInvalidThreshold, err)
+	_, err = Generate(4, 3, rand.Reader)
+	assert.Equal(t, ErrInvalidThreshold, err)
+	_, err = Generate(1, 0, rand.Reader)
+	assert.Equal(t, ErrInvalidParticipants, err)
+}
+
+func TestVerifyTamperedShare(t *testing.T) {
+	dealing, err := Generate(2, 3, rand.Reader)
+	require.NoError(t, err)
+
+	share := dealing.Shares[0]
+	share.Secret[0] ^= 1
+	assert.Error(t, share.Verify(dealing.Commitments))
+}
+
+func TestSignErrors(t *testing.T) {
+	dealing, err := Generate(2, 3, rand.Reader)
+	require.NoError(t, err)
+	message := []byte("message")
+
+	n0, err := dealing.Shares[0].Commit(rand.Reader)
+	require.NoError(t, err)
+	n1, err := dealing.Shares[1].Commit(rand.Reader)
+	require.NoError(t, err)
+
+	_, err = dealing.Shares[0].Sign(message, n0, []Commitment{n0.Commitment()})
+	assert.Equal(t, ErrNotEnoughSigners, err)
+
+	_, err = dealing.Shares[1].Sign(message, n0, []Commitment{n0.Commitment(), n1.Commitment()})
+	assert.EqualError(t, err, "nonces belong to another participant")
+
+	commitments := []Commitment{n0.Commitment(), n1.Commitment()}
+	share0, err := dealing.Shares[0].Sign(message, n0, commitments)
+	require.NoError(t, err)
+	_, err = dealing.Shares[0].Sign(message, n0, commitments)
+	assert.Equal(t, ErrNoncesUsed, err)
+
+	share1, err := dealing.Shares[1].Sign(message, n1, commitments)
+	require.NoError(t, err)
+
+	// A corrupted share is attributed to its participant
+	share1.Z[0] ^= 1
+	_, err = Aggregate(dealing.PublicKeys, message, commitments, []SignatureShare{share0, share1})
+	assert.EqualError(t, err, "invalid signature share from participant 2: signature share does not verify")
+}
@@ -95,6 +95,7 @@ func main() {
 			OptType:   types.String,
 			Required:  false,
 			Usage:     "horizon postgres database to connect with",
+			Secret:    true,
 		},
 		&config.ConfigOption{
 			Name:           "stellar-captive-core-http-port",
@@ -27,6 +27,7 @@ func (c *DBCommand) Command() *cobra.Command {
 			ConfigKey:   &c.DatabaseURL,
 			FlagDefault: "postgres://localhost:5432/?sslmode=disable",
 			Required:    true,
+			Secret:      true,
 		},
 	}
 	cmd := &cobra.Command{
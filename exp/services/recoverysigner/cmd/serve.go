@@ -34,6 +34,7 @@ func (c *ServeCommand) Command() *cobra.Command {
 			ConfigKey:   &opts.DatabaseURL,
 			FlagDefault: "postgres://localhost:5432/?sslmode=disable",
 			Required:    false,
+			Secret:      true,
 		},
 		{
 			Name:        "db-max-open-conns",
@@ -102,6 +103,54 @@ func (c *ServeCommand) Command() *cobra.Command {
 			ConfigKey: &opts.AllowedSourceAccounts,
 			Required:  false,
 		},
+		{
+			Name:      "security-event-sink",
+			Usage:     "URL of the sink security events are sent to: syslog:// for the local syslog, syslog://host:port or syslog+tcp://host:port for a remote syslog, or an http(s) URL events are posted to as JSON (events are logged if empty)",
+			OptType:   types.String,
+			ConfigKey: &opts.SecurityEventSinkURL,
+			Required:  false,
+		},
+		{
+			Name:      "security-event-sink-token",
+			Usage:     "Bearer token sent with security events posted to an http(s) security event sink",
+			OptType:   types.String,
+			ConfigKey: &opts.SecurityEventSinkToken,
+			Required:  false,
+			Secret:    true,
+		},
+		{
+			Name:        "signing-velocity-limit",
+			Usage:       "Number of signing requests for an account within the signing velocity window above which a security event is emitted (disabled if 0)",
+			OptType:     types.Int,
+			ConfigKey:   &opts.SigningVelocityLimit,
+			FlagDefault: 0,
+			Required:    false,
+		},
+		{
+			Name:           "signing-velocity-window",
+			Usage:          "The time period in seconds over which signing requests for an account are counted for the signing velocity limit",
+			OptType:        types.Int,
+			CustomSetValue: config.SetDuration,
+			ConfigKey:      &opts.SigningVelocityWindow,
+			FlagDefault:    3600,
+			Required:       false,
+		},
+		{
+			Name:      "signing-caps",
+			Usage:     "Amounts transactions signed for a client authenticated with an auth method type may move out of an account within the signing cap window comma separated, each formatted <auth-method-type>:<amount>:<asset> where the asset is native or CODE:ISSUER (e.g. phone_number:1000:native,email:500:native) (clients authenticated as the account, and auth method types without a cap for an asset, are not limited)",
+			OptType:   types.String,
+			ConfigKey: &opts.SigningCaps,
+			Required:  false,
+		},
+		{
+			Name:           "signing-cap-window",
+			Usage:          "The time period in seconds over which the amounts moved by signed transactions are counted for the signing caps",
+			OptType:        types.Int,
+			CustomSetValue: config.SetDuration,
+			ConfigKey:      &opts.SigningCapWindow,
+			FlagDefault:    86400,
+			Required:       false,
+		},
 	}
 	cmd := &cobra.Command{
 		Use:   "serve",
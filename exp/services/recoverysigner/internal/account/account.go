@@ -1,5 +1,11 @@
 package account
 
+import (
+	"github.com/stellar/go/protocols/sep9"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+)
+
 type Account struct {
 	Address    string
 	Identities []Identity
@@ -16,6 +22,24 @@ func (t AuthMethodType) Valid() bool {
 	return AuthMethodTypes[t]
 }
 
+// ValidateValue returns an error if value is not a valid value of an auth
+// method of the type. Phone numbers and emails are validated like the SEP-9
+// mobile_number and email_address fields.
+func (t AuthMethodType) ValidateValue(value string) error {
+	switch t {
+	case AuthMethodTypeAddress:
+		if !strkey.IsValidEd25519PublicKey(value) {
+			return errors.Errorf("auth method %s value is not a valid stellar address", t)
+		}
+		return nil
+	case AuthMethodTypePhoneNumber:
+		return errors.Wrapf(sep9.Validate("mobile_number", value), "auth method %s value", t)
+	case AuthMethodTypeEmail:
+		return errors.Wrapf(sep9.Validate("email_address", value), "auth method %s value", t)
+	}
+	return errors.Errorf("auth method type %q unrecognized", t)
+}
+
 const (
 	AuthMethodTypeAddress     AuthMethodType = "stellar_address"
 	AuthMethodTypePhoneNumber AuthMethodType = "phone_number"
@@ -0,0 +1,34 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMethodTypeValidateValue(t *testing.T) {
+	testCases := []struct {
+		Type    AuthMethodType
+		Value   string
+		WantErr string
+	}{
+		{AuthMethodTypeAddress, "GBF3XFXGBGNQDN3HOSZ7NVRF6TJ2JOD5U6ELIWJOOEI6T5WKMQT2YSXQ", ""},
+		{AuthMethodTypeAddress, "GBF3XFXGBGNQDN3HOSZ7NVRF6TJ2JOD5U6ELIWJOOEI6T5WKMQT2YSX", "auth method stellar_address value is not a valid stellar address"},
+		{AuthMethodTypePhoneNumber, "+10000000000", ""},
+		{AuthMethodTypePhoneNumber, "10000000000", "auth method phone_number value: mobile_number is not a valid phone"},
+		{AuthMethodTypeEmail, "user1@example.com", ""},
+		{AuthMethodTypeEmail, "user1", "auth method email value: email_address is not a valid email"},
+		{AuthMethodTypeEmail, "", "auth method email value: email_address cannot be empty"},
+		{AuthMethodType("wormhole_technology"), "galaxy5.earth3.asdfuaiosufd", `auth method type "wormhole_technology" unrecognized`},
+	}
+	for _, tc := range testCases {
+		t.Run(string(tc.Type)+" "+tc.Value, func(t *testing.T) {
+			err := tc.Type.ValidateValue(tc.Value)
+			if tc.WantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.WantErr)
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@
 // migrations/20200320000000-create-accounts-audit.sql (1.23kB)
 // migrations/20200320000001-create-identities-audit.sql (1.166kB)
 // migrations/20200320000002-create-auth-methods-audit.sql (1.192kB)
+// migrations/20210401000000-create-signing-cap-overrides.sql (511B)
+// migrations/20210401000001-create-signing-cap-usage.sql (509B)
+// migrations/20210401000002-create-signing-cap-overrides-audit.sql (1.309kB)
 
 package dbmigrate
 
@@ -236,6 +239,66 @@ func migrations20200320000002CreateAuthMethodsAuditSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations20210401000000CreateSigningCapOverridesSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x91\x4b\x6f\x83\x30\x0c\x80\xef\xf9\x15\x3e\x82\x06\xd2\xee\xd5\x26\xa5\xc4\x6d\xa3\x42\x60\x21\xa8\x63\x17\x84\x20\x6a\x39\xf0\x10\x8f\x4d\xfb\xf7\x0b\x6c\xdd\x43\x4c\xdb\x21\x87\xd8\xf1\xf7\x39\xb6\xeb\xc2\x4d\x5d\x9d\xfb\x7c\xd4\x90\x74\x84\x78\x12\xa9\x42\x50\x74\xeb\x23\x0c\xd5\xb9\xa9\x9a\x73\x56\xe4\x5d\xd6\x3e\xeb\xbe\xaf\x4a\x3d\x80\x45\x00\xf2\xa2\x68\xa7\x66\xcc\xaa\x12\xb6\x7c\xcf\x85\x02\x11\x9a\x93\xf8\x3e\x48\xdc\xa1\x44\xe1\x61\x7c\x7d\x65\x4a\xaa\xd2\x86\x50\x00\x43\x1f\x0d\xdd\xa3\xb1\x47\x19\x3a\x06\xf4\x0b\x20\x92\x3c\xa0\x32\x85\x23\xa6\xb0\x47\x81\xd2\x34\xc4\x80\xfa\x27\x9a\xc6\x40\x63\xe0\x0c\x85\xe2\x2a\x75\x88\xa9\x2f\x7a\x6d\x5a\x2f\xb3\x7c\x04\xc5\x03\x8c\x15\x0d\x22\x38\x71\x75\x58\xae\xf0\x14\x0a\xfc\x22\x33\xdc\xd1\xc4\x9f\x55\x27\xcb\x9e\xed\x53\x57\xfe\x57\xbd\x58\xf2\x69\xbc\x64\xb5\x1e\x2f\x6d\x99\x8d\xaf\x9d\x5e\x07\xae\x8e\x99\x9a\x0f\x83\x36\x40\x7c\x54\x3f\xc3\xf5\x3c\x8c\xd5\x77\xbd\x03\x7a\x47\xb0\x3e\xb2\xf7\x77\x70\x6b\x2f\xce\x44\xf0\x87\x04\x4d\xe2\x73\xd4\xce\x4a\xeb\xbc\xbb\x6c\x62\x6f\x08\x71\xbf\xad\x92\xb5\x2f\x0d\x21\x4c\x86\xd1\x5f\xab\xdc\x90\x37\xe9\xcf\xac\x82\xff\x01\x00\x00")
+
+func migrations20210401000000CreateSigningCapOverridesSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20210401000000CreateSigningCapOverridesSql,
+		"migrations/20210401000000-create-signing-cap-overrides.sql",
+	)
+}
+
+func migrations20210401000000CreateSigningCapOverridesSql() (*asset, error) {
+	bytes, err := migrations20210401000000CreateSigningCapOverridesSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20210401000000-create-signing-cap-overrides.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9a, 0xfe, 0xf3, 0xc1, 0x9a, 0x9c, 0xa6, 0x25, 0x7d, 0xd5, 0x96, 0x3c, 0xf1, 0x31, 0x9f, 0x12, 0xc2, 0x2c, 0xa2, 0x1b, 0x3b, 0x43, 0xbb, 0x3, 0x40, 0xaa, 0xa0, 0x66, 0x8, 0x6f, 0x19, 0xfc}}
+	return a, nil
+}
+
+var _migrations20210401000001CreateSigningCapUsageSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x91\xcb\x6e\x83\x30\x10\x45\xf7\xfe\x8a\x59\x82\x0a\x52\xf7\x51\x2b\x39\x66\xd2\xa0\xf0\x12\x18\x25\xe9\xc6\xb2\x00\x11\x16\x3c\x84\x8d\x50\xff\xbe\x86\x36\x6d\xa3\xa8\x6a\x17\x5e\x78\x1e\xe7\xde\x99\x71\x5d\x78\x68\x9b\x7a\x94\xba\x82\x7c\x20\x84\xa5\x48\x39\x02\xa7\xdb\x00\x41\x35\x75\xd7\x74\xb5\x28\xe4\x20\x26\x25\xeb\x0a\x2c\x02\x20\x8b\xa2\x9f\x3a\x2d\x9a\x12\xb6\xfe\x8b\x1f\x71\x88\x62\xf3\xf2\x20\x80\x14\x77\x98\x62\xc4\x30\xbb\x56\x29\xb0\x9a\xd2\x86\x38\x02\x0f\x03\x34\x64\x46\x33\x46\x3d\x74\x88\x21\x15\x63\x65\x74\x4b\x21\x35\x70\x3f\xc4\x8c\xd3\x30\x81\xa3\xcf\xf7\xeb\x17\x5e\xe3\x08\xbf\xd9\x1e\xee\x68\x1e\x2c\x62\x47\xcb\x76\x4c\xf7\x34\x94\x7f\x75\xaf\x2a\x72\xd2\x17\xd1\x56\xfa\xd2\x97\x42\xbf\x0d\xd5\x7d\xe0\xaa\xb1\x50\xa5\x52\x95\x01\xe2\x89\xdf\x84\xe7\xa6\x2b\xfb\x59\x28\x2d\xc7\x7f\x98\x5d\x41\xed\x32\xff\xdd\x8a\xd8\x1e\xd9\x01\xac\xcf\xec\xf3\x13\x3c\xda\xab\xcb\x24\xf5\x43\x9a\x9e\xe1\x80\x67\x93\xfd\x5a\xb1\x73\xe7\xd6\xf9\xb0\xe8\xdc\x58\xb2\x89\xbd\x21\xc4\xfd\x71\x4c\xaf\x9f\x3b\x42\xbc\x34\x4e\x7e\x3b\xe6\x86\xbc\x03\xb6\xa5\xf9\xd8\xfd\x01\x00\x00")
+
+func migrations20210401000001CreateSigningCapUsageSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20210401000001CreateSigningCapUsageSql,
+		"migrations/20210401000001-create-signing-cap-usage.sql",
+	)
+}
+
+func migrations20210401000001CreateSigningCapUsageSql() (*asset, error) {
+	bytes, err := migrations20210401000001CreateSigningCapUsageSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20210401000001-create-signing-cap-usage.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x5, 0x6e, 0xc8, 0xe, 0xf6, 0x56, 0x33, 0x55, 0x3b, 0x5e, 0x88, 0xbc, 0xe7, 0xd6, 0x10, 0xbb, 0x8a, 0xb8, 0x54, 0xd7, 0x71, 0x97, 0x39, 0x54, 0x75, 0x96, 0xa2, 0xc6, 0x79, 0x8a, 0x6d, 0xff}}
+	return a, nil
+}
+
+var _migrations20210401000002CreateSigningCapOverridesAuditSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcd\x54\x4d\x8f\x9b\x30\x10\xbd\xfb\x57\xcc\x61\xa5\x25\xdd\xa4\x3f\x60\x51\x0f\x06\x0f\xc4\x5a\xc7\x46\xc6\x2e\x4b\x2f\x28\x5a\x10\x42\xda\x25\x94\xb0\xed\xdf\xaf\x09\xc9\xd2\x36\xfb\x79\xa9\x7a\x00\x0f\xe3\xe7\xf1\x9b\x37\x33\xac\x56\x70\xf5\xd0\xd4\xfd\x76\xa8\xc0\x76\x84\x84\x1a\xa9\x41\x30\x34\x10\x08\xfb\xa6\x6e\x9b\xb6\x2e\xee\xb6\x5d\xb1\xfb\x51\xf5\x7d\x53\x56\xfb\x62\xfb\x58\x36\x03\x78\x04\xe0\x60\x15\x4d\x09\x01\x8f\xb9\x34\x20\x95\x7b\xac\x10\x90\x68\xbe\xa1\x3a\x87\x1b\xcc\x21\x46\x89\xda\xc5\x64\x40\x45\x46\xf3\x14\x68\x0a\x9c\xa1\x34\xdc\xe4\xcb\xa7\x20\xdb\x01\x0c\xdf\x60\x6a\xe8\x26\x81\x8c\x9b\xf5\xe1\x13\xbe\x29\x89\x73\x58\x86\x11\xb5\x62\xbc\x27\xf3\x16\xf3\xd9\xc7\x7d\xd5\x83\xc1\x5b\x73\x8e\xb4\x29\xea\x19\xb8\xeb\x66\xe3\x04\x1d\x77\x05\xbf\x79\x21\x59\xb2\xf0\x09\x59\xfd\xa6\x51\x3a\xb8\xf7\x43\xd5\x0e\x41\x55\x37\xed\x49\xae\xc8\xca\xd0\x70\x25\xa1\xaf\xee\x76\x7d\x59\xbc\x22\x9c\xb7\x00\x8d\xc6\x6a\x99\x82\xd1\x3c\x8e\x51\x8f\x82\x5c\x04\x8a\xe5\x17\x8e\x4a\x80\x4e\x49\xb7\x02\xf0\x08\x3c\x13\x17\x2a\x81\x2f\x70\xc9\xa5\x4b\xc4\x5c\x2e\xc0\xac\x71\xda\x76\x80\x83\xcf\x2d\x46\xbd\x5a\xa9\xaf\x54\x58\x4c\xc1\x3b\x6a\xb2\x84\x73\xe3\x70\xcf\xf5\xf5\x49\x9c\x25\x48\xcc\x3e\x7f\x72\xb9\x4f\x37\x4d\x84\x47\xe7\xe4\x41\x91\xfe\xc1\xce\x26\xcc\xa9\xf0\xbf\xb2\x63\x28\xf0\x5f\xb2\x53\x82\x9d\xb3\x73\xce\x23\x3b\xc9\x5c\x69\x47\xdb\x59\x3e\x99\x0a\x0f\x82\xca\xd8\xd2\x18\xa1\xbb\xef\xea\xfd\xf7\x7b\xff\xf9\xa6\xc3\xb6\x9c\x47\xf4\xd8\x3d\x6f\xb7\x1c\xa1\x91\x71\xc0\x63\xce\x4a\xc3\x54\xaf\xd1\x9a\xb4\x01\xd7\xb9\xcf\xb7\x3f\x40\xe4\x50\x48\xc3\x35\x68\x95\x01\xde\x62\x68\x1d\x3e\xd1\x2a\x44\x66\x35\xbe\xab\xe1\xff\x1a\x21\xb6\xfb\xd9\x12\xc2\xb4\xab\xce\xfb\x73\x78\x91\xa2\x3f\x45\xfa\xc0\x04\x1e\x4f\xbc\xf9\x8b\xf3\xc9\x2f\x6f\x1c\x66\x2b\x1d\x05\x00\x00")
+
+func migrations20210401000002CreateSigningCapOverridesAuditSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20210401000002CreateSigningCapOverridesAuditSql,
+		"migrations/20210401000002-create-signing-cap-overrides-audit.sql",
+	)
+}
+
+func migrations20210401000002CreateSigningCapOverridesAuditSql() (*asset, error) {
+	bytes, err := migrations20210401000002CreateSigningCapOverridesAuditSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20210401000002-create-signing-cap-overrides-audit.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x65, 0xe9, 0x46, 0x44, 0x5c, 0xd, 0x3e, 0xb8, 0x20, 0x6, 0xc0, 0x4b, 0x97, 0x15, 0xb7, 0x61, 0x9e, 0xa6, 0x27, 0x57, 0xa2, 0x95, 0x5e, 0xbe, 0xf4, 0x49, 0x57, 0x15, 0x4f, 0x3c, 0x27, 0xe3}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -327,14 +390,17 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"migrations/20200309000000-initial-1.sql":                 migrations20200309000000Initial1Sql,
-	"migrations/20200309000001-initial-2.sql":                 migrations20200309000001Initial2Sql,
-	"migrations/20200311000000-create-accounts.sql":           migrations20200311000000CreateAccountsSql,
-	"migrations/20200311000001-create-identities.sql":         migrations20200311000001CreateIdentitiesSql,
-	"migrations/20200311000002-create-auth-methods.sql":       migrations20200311000002CreateAuthMethodsSql,
-	"migrations/20200320000000-create-accounts-audit.sql":     migrations20200320000000CreateAccountsAuditSql,
-	"migrations/20200320000001-create-identities-audit.sql":   migrations20200320000001CreateIdentitiesAuditSql,
-	"migrations/20200320000002-create-auth-methods-audit.sql": migrations20200320000002CreateAuthMethodsAuditSql,
+	"migrations/20200309000000-initial-1.sql":                          migrations20200309000000Initial1Sql,
+	"migrations/20200309000001-initial-2.sql":                          migrations20200309000001Initial2Sql,
+	"migrations/20200311000000-create-accounts.sql":                    migrations20200311000000CreateAccountsSql,
+	"migrations/20200311000001-create-identities.sql":                  migrations20200311000001CreateIdentitiesSql,
+	"migrations/20200311000002-create-auth-methods.sql":                migrations20200311000002CreateAuthMethodsSql,
+	"migrations/20200320000000-create-accounts-audit.sql":              migrations20200320000000CreateAccountsAuditSql,
+	"migrations/20200320000001-create-identities-audit.sql":            migrations20200320000001CreateIdentitiesAuditSql,
+	"migrations/20200320000002-create-auth-methods-audit.sql":          migrations20200320000002CreateAuthMethodsAuditSql,
+	"migrations/20210401000000-create-signing-cap-overrides.sql":       migrations20210401000000CreateSigningCapOverridesSql,
+	"migrations/20210401000001-create-signing-cap-usage.sql":           migrations20210401000001CreateSigningCapUsageSql,
+	"migrations/20210401000002-create-signing-cap-overrides-audit.sql": migrations20210401000002CreateSigningCapOverridesAuditSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -379,14 +445,17 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"migrations": &bintree{nil, map[string]*bintree{
-		"20200309000000-initial-1.sql":                 &bintree{migrations20200309000000Initial1Sql, map[string]*bintree{}},
-		"20200309000001-initial-2.sql":                 &bintree{migrations20200309000001Initial2Sql, map[string]*bintree{}},
-		"20200311000000-create-accounts.sql":           &bintree{migrations20200311000000CreateAccountsSql, map[string]*bintree{}},
-		"20200311000001-create-identities.sql":         &bintree{migrations20200311000001CreateIdentitiesSql, map[string]*bintree{}},
-		"20200311000002-create-auth-methods.sql":       &bintree{migrations20200311000002CreateAuthMethodsSql, map[string]*bintree{}},
-		"20200320000000-create-accounts-audit.sql":     &bintree{migrations20200320000000CreateAccountsAuditSql, map[string]*bintree{}},
-		"20200320000001-create-identities-audit.sql":   &bintree{migrations20200320000001CreateIdentitiesAuditSql, map[string]*bintree{}},
-		"20200320000002-create-auth-methods-audit.sql": &bintree{migrations20200320000002CreateAuthMethodsAuditSql, map[string]*bintree{}},
+		"20200309000000-initial-1.sql":                          &bintree{migrations20200309000000Initial1Sql, map[string]*bintree{}},
+		"20200309000001-initial-2.sql":                          &bintree{migrations20200309000001Initial2Sql, map[string]*bintree{}},
+		"20200311000000-create-accounts.sql":                    &bintree{migrations20200311000000CreateAccountsSql, map[string]*bintree{}},
+		"20200311000001-create-identities.sql":                  &bintree{migrations20200311000001CreateIdentitiesSql, map[string]*bintree{}},
+		"20200311000002-create-auth-methods.sql":                &bintree{migrations20200311000002CreateAuthMethodsSql, map[string]*bintree{}},
+		"20200320000000-create-accounts-audit.sql":              &bintree{migrations20200320000000CreateAccountsAuditSql, map[string]*bintree{}},
+		"20200320000001-create-identities-audit.sql":            &bintree{migrations20200320000001CreateIdentitiesAuditSql, map[string]*bintree{}},
+		"20200320000002-create-auth-methods-audit.sql":          &bintree{migrations20200320000002CreateAuthMethodsAuditSql, map[string]*bintree{}},
+		"20210401000000-create-signing-cap-overrides.sql":       &bintree{migrations20210401000000CreateSigningCapOverridesSql, map[string]*bintree{}},
+		"20210401000001-create-signing-cap-usage.sql":           &bintree{migrations20210401000001CreateSigningCapUsageSql, map[string]*bintree{}},
+		"20210401000002-create-signing-cap-overrides-audit.sql": &bintree{migrations20210401000002CreateSigningCapOverridesAuditSql, map[string]*bintree{}},
 	}},
 }}
 
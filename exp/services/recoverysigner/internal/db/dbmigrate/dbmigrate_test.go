@@ -157,6 +157,9 @@ func TestMigrate_upApplyAll(t *testing.T) {
 		"20200320000000-create-accounts-audit.sql",
 		"20200320000001-create-identities-audit.sql",
 		"20200320000002-create-auth-methods-audit.sql",
+		"20210401000000-create-signing-cap-overrides.sql",
+		"20210401000001-create-signing-cap-usage.sql",
+		"20210401000002-create-signing-cap-overrides-audit.sql",
 	}
 	assert.Equal(t, wantIDs, ids)
 }
@@ -186,6 +189,9 @@ func TestMigrate_upApplyNone(t *testing.T) {
 		"20200320000000-create-accounts-audit.sql",
 		"20200320000001-create-identities-audit.sql",
 		"20200320000002-create-auth-methods-audit.sql",
+		"20210401000000-create-signing-cap-overrides.sql",
+		"20210401000001-create-signing-cap-usage.sql",
+		"20210401000002-create-signing-cap-overrides-audit.sql",
 	}
 	assert.Equal(t, wantIDs, ids)
 }
@@ -0,0 +1,19 @@
+-- +migrate Up
+
+CREATE TABLE signing_cap_overrides (
+  account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+  id BIGINT NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE,
+
+  auth_method_type auth_method_type NOT NULL,
+  asset TEXT NOT NULL,
+  amount BIGINT NOT NULL CHECK (amount >= 0),
+
+  UNIQUE (account_id, auth_method_type, asset)
+);
+
+-- +migrate Down
+
+DROP TABLE signing_cap_overrides;
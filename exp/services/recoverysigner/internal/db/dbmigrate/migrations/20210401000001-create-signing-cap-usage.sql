@@ -0,0 +1,19 @@
+-- +migrate Up
+
+CREATE TABLE signing_cap_usage (
+  account_id BIGINT NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMP WITH TIME ZONE,
+
+  auth_method_type auth_method_type NOT NULL,
+  asset TEXT NOT NULL,
+  window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+  amount BIGINT NOT NULL CHECK (amount >= 0),
+
+  PRIMARY KEY (account_id, auth_method_type, asset, window_start)
+);
+
+-- +migrate Down
+
+DROP TABLE signing_cap_usage;
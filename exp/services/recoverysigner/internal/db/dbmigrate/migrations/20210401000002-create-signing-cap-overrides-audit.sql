@@ -0,0 +1,36 @@
+-- +migrate Up
+
+CREATE TABLE signing_cap_overrides_audit (
+  audit_id BIGINT NOT NULL PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+  audit_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  audit_user TEXT NOT NULL DEFAULT USER,
+  audit_op audit_op NOT NULL,
+  LIKE signing_cap_overrides
+);
+
+-- +migrate StatementBegin
+CREATE FUNCTION record_signing_cap_overrides_audit() RETURNS TRIGGER AS $BODY$
+  BEGIN
+    IF (TG_OP = 'INSERT') THEN
+      INSERT INTO signing_cap_overrides_audit VALUES (DEFAULT, DEFAULT, DEFAULT, TG_OP::audit_op, NEW.*);
+      RETURN NEW;
+    ELSIF (TG_OP = 'UPDATE') THEN
+      INSERT INTO signing_cap_overrides_audit VALUES (DEFAULT, DEFAULT, DEFAULT, TG_OP::audit_op, NEW.*);
+      RETURN NEW;
+    ELSIF (TG_OP = 'DELETE') THEN
+      INSERT INTO signing_cap_overrides_audit VALUES (DEFAULT, DEFAULT, DEFAULT, TG_OP::audit_op, OLD.*);
+      RETURN OLD;
+    END IF;
+  END;
+$BODY$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER record_signing_cap_overrides_audit
+AFTER INSERT OR UPDATE OR DELETE ON signing_cap_overrides
+  FOR EACH ROW EXECUTE PROCEDURE record_signing_cap_overrides_audit();
+
+-- +migrate Down
+
+DROP TRIGGER record_signing_cap_overrides_audit ON signing_cap_overrides;
+DROP FUNCTION record_signing_cap_overrides_audit;
+DROP TABLE signing_cap_overrides_audit;
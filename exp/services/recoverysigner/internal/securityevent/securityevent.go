@@ -0,0 +1,148 @@
+// Package securityevent emits structured security events, such as failed
+// authentication attempts and unusual signing velocity, to sinks that forward
+// them to an external SIEM so that abuse of the recovery service can be
+// alerted on.
+package securityevent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// DefaultQueueSize is the number of events an Emitter holds while its sink is
+// slow or unreachable when Emitter.QueueSize is not set.
+const DefaultQueueSize = 1000
+
+// sinkTimeout bounds the time spent sending an event to a sink.
+const sinkTimeout = 10 * time.Second
+
+type Type string
+
+const (
+	// TypeAuthenticationFailed is emitted when a request carries credentials
+	// that could not be verified.
+	TypeAuthenticationFailed Type = "authentication_failed"
+	// TypeAuthorizationFailed is emitted when an authenticated client
+	// requests access to an account it is not registered with.
+	TypeAuthorizationFailed Type = "authorization_failed"
+	// TypeSigningVelocityExceeded is emitted when an account requests more
+	// signatures within a window than is expected.
+	TypeSigningVelocityExceeded Type = "signing_velocity_exceeded"
+	// TypeSigningCapExceeded is emitted when a transaction is refused because
+	// it would move more than the authenticated identity is permitted to.
+	TypeSigningCapExceeded Type = "signing_cap_exceeded"
+)
+
+// Event is a security relevant occurrence.
+type Event struct {
+	Type       Type              `json:"type"`
+	Time       time.Time         `json:"time"`
+	Account    string            `json:"account,omitempty"`
+	RemoteAddr string            `json:"remote_addr,omitempty"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+}
+
+// Sink receives security events.
+type Sink interface {
+	Emit(ctx context.Context, e Event) error
+}
+
+// LogSink writes security events to the log. It is used when no external
+// sink is configured.
+type LogSink struct {
+	Logger *supportlog.Entry
+}
+
+func (s LogSink) Emit(ctx context.Context, e Event) error {
+	l := s.Logger.Ctx(ctx).
+		WithField("security_event", e.Type).
+		WithField("account", e.Account).
+		WithField("remote_addr", e.RemoteAddr).
+		WithField("req", e.RequestID)
+	for k, v := range e.Details {
+		l = l.WithField(k, v)
+	}
+	l.Warn("Security event.")
+	return nil
+}
+
+// Emitter sends events to a sink asynchronously, so that a slow or
+// unreachable sink never delays the request that caused the event. Events
+// are queued and sent one at a time by a background goroutine; failing to
+// send an event, or to queue it because the queue is full, is logged.
+type Emitter struct {
+	Logger *supportlog.Entry
+	Sink   Sink
+	// QueueSize is the number of events held while the sink is busy, events
+	// emitted while the queue is full are dropped. Defaults to
+	// DefaultQueueSize.
+	QueueSize int
+
+	startOnce sync.Once
+	queue     chan Event
+	done      chan struct{}
+}
+
+func (em *Emitter) start() {
+	em.startOnce.Do(func() {
+		size := em.QueueSize
+		if size <= 0 {
+			size = DefaultQueueSize
+		}
+		em.queue = make(chan Event, size)
+		em.done = make(chan struct{})
+		go em.run()
+	})
+}
+
+func (em *Emitter) run() {
+	defer close(em.done)
+	for e := range em.queue {
+		// The request that caused the event may be over, so the event is
+		// not sent with its context.
+		ctx, cancel := context.WithTimeout(context.Background(), sinkTimeout)
+		err := em.Sink.Emit(ctx, e)
+		cancel()
+		if err != nil {
+			em.Logger.
+				WithField("security_event", e.Type).
+				WithField("req", e.RequestID).
+				Error("Error emitting security event: ", err)
+		}
+	}
+}
+
+// Emit queues the event to be sent to the sink, setting its time if it is
+// not set, and returns without waiting for it to be sent. A nil Emitter
+// discards events.
+func (em *Emitter) Emit(ctx context.Context, e Event) {
+	if em == nil || em.Sink == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	em.start()
+	select {
+	case em.queue <- e:
+	default:
+		em.Logger.Ctx(ctx).
+			WithField("security_event", e.Type).
+			Error("Security event queue is full, dropping event.")
+	}
+}
+
+// Close waits for the queued events to be sent. Events must not be emitted
+// after Close is called.
+func (em *Emitter) Close() {
+	if em == nil || em.Sink == nil {
+		return
+	}
+	em.start()
+	close(em.queue)
+	<-em.done
+}
@@ -0,0 +1,154 @@
+package securityevent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkFunc func(ctx context.Context, e Event) error
+
+func (f sinkFunc) Emit(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+func TestEmitter_setsTime(t *testing.T) {
+	events := []Event{}
+	em := &Emitter{
+		Logger: supportlog.DefaultLogger,
+		Sink: sinkFunc(func(ctx context.Context, e Event) error {
+			events = append(events, e)
+			return nil
+		}),
+	}
+	em.Emit(context.Background(), Event{Type: TypeAuthenticationFailed})
+	em.Close()
+
+	require.Len(t, events, 1)
+	assert.Equal(t, TypeAuthenticationFailed, events[0].Type)
+	assert.False(t, events[0].Time.IsZero())
+}
+
+func TestEmitter_nil(t *testing.T) {
+	var em *Emitter
+	em.Emit(context.Background(), Event{Type: TypeAuthenticationFailed})
+	em.Close()
+}
+
+func TestEmitter_doesNotWaitForSink(t *testing.T) {
+	release := make(chan struct{})
+	events := []Event{}
+	em := &Emitter{
+		Logger:    supportlog.DefaultLogger,
+		QueueSize: 1,
+		Sink: sinkFunc(func(ctx context.Context, e Event) error {
+			<-release
+			events = append(events, e)
+			return nil
+		}),
+	}
+
+	// The first event is taken by the sink, which blocks, the second is
+	// queued and the third is dropped because the queue is full.
+	em.Emit(context.Background(), Event{Type: TypeAuthenticationFailed})
+	for len(em.queue) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	em.Emit(context.Background(), Event{Type: TypeAuthorizationFailed})
+	em.Emit(context.Background(), Event{Type: TypeSigningCapExceeded})
+
+	close(release)
+	em.Close()
+	require.Len(t, events, 2)
+	assert.Equal(t, TypeAuthenticationFailed, events[0].Type)
+	assert.Equal(t, TypeAuthorizationFailed, events[1].Type)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		err := json.NewDecoder(r.Body).Decode(&received)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	sink := HTTPSink{URL: server.URL, Token: "secret"}
+	e := Event{
+		Type:    TypeSigningVelocityExceeded,
+		Time:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Account: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+		Details: map[string]string{"count": "11"},
+	}
+	err := sink.Emit(context.Background(), e)
+	require.NoError(t, err)
+	assert.Equal(t, e, received)
+}
+
+func TestHTTPSink_errorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := HTTPSink{URL: server.URL}
+	err := sink.Emit(context.Background(), Event{Type: TypeAuthenticationFailed})
+	assert.EqualError(t, err, "posting event: unexpected status code 503")
+}
+
+func TestNewSink(t *testing.T) {
+	sink, err := NewSink(supportlog.DefaultLogger, "", "")
+	require.NoError(t, err)
+	assert.IsType(t, LogSink{}, sink)
+
+	sink, err = NewSink(supportlog.DefaultLogger, "https://siem.example.com/events", "secret")
+	require.NoError(t, err)
+	require.IsType(t, HTTPSink{}, sink)
+	assert.Equal(t, "https://siem.example.com/events", sink.(HTTPSink).URL)
+	assert.Equal(t, "secret", sink.(HTTPSink).Token)
+
+	_, err = NewSink(supportlog.DefaultLogger, "amqp://broker:5672", "")
+	assert.EqualError(t, err, `security event sink url scheme "amqp" is not supported`)
+}
+
+func TestVelocityTracker(t *testing.T) {
+	tracker := VelocityTracker{Window: time.Minute, Limit: 2}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	count, exceeded := tracker.Observe("A", start)
+	assert.Equal(t, 1, count)
+	assert.False(t, exceeded)
+	count, exceeded = tracker.Observe("A", start.Add(10*time.Second))
+	assert.Equal(t, 2, count)
+	assert.False(t, exceeded)
+	count, exceeded = tracker.Observe("A", start.Add(20*time.Second))
+	assert.Equal(t, 3, count)
+	assert.True(t, exceeded)
+
+	// Other keys are counted separately.
+	count, exceeded = tracker.Observe("B", start.Add(20*time.Second))
+	assert.Equal(t, 1, count)
+	assert.False(t, exceeded)
+
+	// Occurrences older than the window are not counted.
+	count, exceeded = tracker.Observe("A", start.Add(75*time.Second))
+	assert.Equal(t, 2, count)
+	assert.False(t, exceeded)
+}
+
+func TestVelocityTracker_noLimit(t *testing.T) {
+	tracker := VelocityTracker{Window: time.Minute}
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		_, exceeded := tracker.Observe("A", now)
+		assert.False(t, exceeded)
+	}
+}
@@ -0,0 +1,110 @@
+package securityevent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	supportlog "github.com/stellar/go/support/log"
+)
+
+// HTTPSink posts each event as a JSON document to a URL, e.g. the event
+// collector of a SIEM or a Kafka REST proxy.
+type HTTPSink struct {
+	URL string
+	// Token is sent as bearer token in the Authorization header if set.
+	Token string
+	HTTP  *http.Client
+}
+
+func (s HTTPSink) Emit(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "encoding event")
+	}
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "building request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "posting event")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("posting event: unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SyslogSink writes each event as a JSON document to syslog.
+type SyslogSink struct {
+	Writer *syslog.Writer
+}
+
+// NewSyslogSink connects to the syslog daemon at the network address, or the
+// local syslog daemon if network and address are empty.
+func NewSyslogSink(network, address string) (SyslogSink, error) {
+	w, err := syslog.Dial(network, address, syslog.LOG_WARNING|syslog.LOG_AUTH, "recoverysigner")
+	if err != nil {
+		return SyslogSink{}, errors.Wrap(err, "connecting to syslog")
+	}
+	return SyslogSink{Writer: w}, nil
+}
+
+func (s SyslogSink) Emit(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "encoding event")
+	}
+	return s.Writer.Warning(string(body))
+}
+
+// NewSink returns the sink described by the URL:
+//
+//   - empty: a LogSink writing to the logger.
+//   - syslog:// or syslog://host:port: a SyslogSink writing to the local
+//     syslog daemon or to the remote one over UDP. syslog+tcp://host:port
+//     uses TCP.
+//   - http://... or https://...: an HTTPSink posting to the URL.
+func NewSink(logger *supportlog.Entry, sinkURL, token string) (Sink, error) {
+	if sinkURL == "" {
+		return LogSink{Logger: logger}, nil
+	}
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing security event sink url")
+	}
+	switch u.Scheme {
+	case "syslog":
+		if u.Host == "" {
+			return NewSyslogSink("", "")
+		}
+		return NewSyslogSink("udp", u.Host)
+	case "syslog+tcp":
+		return NewSyslogSink("tcp", u.Host)
+	case "http", "https":
+		return HTTPSink{
+			URL:   sinkURL,
+			Token: token,
+			HTTP:  &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("security event sink url scheme %q is not supported", u.Scheme)
+	}
+}
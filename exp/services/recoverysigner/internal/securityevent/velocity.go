@@ -0,0 +1,41 @@
+package securityevent
+
+import (
+	"sync"
+	"time"
+)
+
+// VelocityTracker counts the occurrences of something per key, such as
+// signing requests per account, within a sliding window and reports when the
+// count exceeds a limit.
+type VelocityTracker struct {
+	Window time.Duration
+	Limit  int
+
+	mu     sync.Mutex
+	events map[string][]time.Time
+}
+
+// Observe records an occurrence for the key at the time and returns the
+// number of occurrences within the window ending at the time, and whether
+// that number exceeds the limit. A tracker with no limit never reports
+// exceeding it.
+func (t *VelocityTracker) Observe(key string, now time.Time) (count int, exceeded bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.events == nil {
+		t.events = map[string][]time.Time{}
+	}
+
+	cutoff := now.Add(-t.Window)
+	times := t.events[key]
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	times = append(times[i:], now)
+	t.events[key] = times
+
+	count = len(times)
+	return count, t.Limit > 0 && count > t.Limit
+}
@@ -66,8 +66,7 @@ func (am accountPostRequestIdentityAuthMethod) Validate() error {
 	if !account.AuthMethodType(am.Type).Valid() {
 		return errors.Errorf("auth method type %q unrecognized", am.Type)
 	}
-	// TODO: Validate auth method values: Stellar address, phone number and email.
-	return nil
+	return account.AuthMethodType(am.Type).ValidateValue(am.Value)
 }
 
 func (h accountPostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -2,9 +2,14 @@ package serve
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/securityevent"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/signingcap"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/http/httpdecode"
 	supportlog "github.com/stellar/go/support/log"
@@ -18,6 +23,13 @@ type accountSignHandler struct {
 	NetworkPassphrase     string
 	AccountStore          account.Store
 	AllowedSourceAccounts []*keypair.FromAddress
+	SecurityEvents        *securityevent.Emitter
+	// SigningVelocity counts the signing requests per account, security
+	// events are emitted when its limit is exceeded. May be nil.
+	SigningVelocity *securityevent.VelocityTracker
+	// SigningCaps limits the amounts transactions may move depending on the
+	// auth methods the client authenticated with. May be nil.
+	SigningCaps *signingcap.Limiter
 }
 
 type accountSignRequest struct {
@@ -83,16 +95,19 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Authorized if authenticated as the account.
-	authorized := claims.Address == req.Address.Address()
+	authorizedWithSelf := claims.Address == req.Address.Address()
+	authorized := authorizedWithSelf
 	l.Infof("Authorized with self: %v.", authorized)
 
 	// Authorized if authenticated as an identity registered with the account.
+	authMethodTypes := []account.AuthMethodType{}
 	for _, i := range acc.Identities {
 		for _, m := range i.AuthMethods {
 			if m.Value != "" && ((m.Type == account.AuthMethodTypeAddress && m.Value == claims.Address) ||
 				(m.Type == account.AuthMethodTypePhoneNumber && m.Value == claims.PhoneNumber) ||
 				(m.Type == account.AuthMethodTypeEmail && m.Value == claims.Email)) {
 				authorized = true
+				authMethodTypes = appendAuthMethodType(authMethodTypes, m.Type)
 				l.Infof("Authorized with %s.", m.Type)
 				break
 			}
@@ -101,10 +116,27 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	l.Infof("Authorized: %v.", authorized)
 	if !authorized {
+		e := newSecurityEvent(r, securityevent.TypeAuthorizationFailed, req.Address.Address())
+		e.Details = map[string]string{"authenticated_address": claims.Address}
+		h.SecurityEvents.Emit(ctx, e)
 		notFound.Render(w)
 		return
 	}
 
+	if h.SigningVelocity != nil {
+		count, exceeded := h.SigningVelocity.Observe(req.Address.Address(), time.Now())
+		if exceeded {
+			l.WithField("count", count).Info("Signing velocity limit exceeded.")
+			e := newSecurityEvent(r, securityevent.TypeSigningVelocityExceeded, req.Address.Address())
+			e.Details = map[string]string{
+				"count":  strconv.Itoa(count),
+				"limit":  strconv.Itoa(h.SigningVelocity.Limit),
+				"window": h.SigningVelocity.Window.String(),
+			}
+			h.SecurityEvents.Emit(ctx, e)
+		}
+	}
+
 	// Decode the request transaction.
 	parsed, err := txnbuild.TransactionFromXDR(req.Transaction)
 	if err != nil {
@@ -160,6 +192,33 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Check that the transaction does not move more than the identities the
+	// client authenticated as are permitted to. Clients authenticated as the
+	// account itself are not limited.
+	if h.SigningCaps != nil && !authorizedWithSelf {
+		err = h.SigningCaps.Reserve(req.Address.Address(), authMethodTypes, tx, time.Now())
+		if exceeded, ok := err.(*signingcap.ExceededError); ok {
+			l.WithField("auth_method_type", exceeded.Reservation.AuthMethodType).
+				WithField("asset", exceeded.Reservation.Asset).
+				Info("Signing cap exceeded.")
+			e := newSecurityEvent(r, securityevent.TypeSigningCapExceeded, req.Address.Address())
+			e.Details = map[string]string{
+				"auth_method_type": string(exceeded.Reservation.AuthMethodType),
+				"asset":            exceeded.Reservation.Asset,
+				"amount":           amount.StringFromInt64(exceeded.Reservation.Amount),
+				"used":             amount.StringFromInt64(exceeded.Used),
+				"limit":            amount.StringFromInt64(exceeded.Reservation.Limit),
+			}
+			h.SecurityEvents.Emit(ctx, e)
+			signingCapExceeded.Render(w)
+			return
+		} else if err != nil {
+			l.Error("Error reserving signing caps:", err)
+			serverError.Render(w)
+			return
+		}
+	}
+
 	// Sign the transaction.
 	hash, err := tx.Hash(h.NetworkPassphrase)
 	if err != nil {
@@ -182,3 +241,12 @@ func (h accountSignHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	httpjson.Render(w, resp, httpjson.JSON)
 }
+
+func appendAuthMethodType(types []account.AuthMethodType, t account.AuthMethodType) []account.AuthMethodType {
+	for _, existing := range types {
+		if existing == t {
+			return types
+		}
+	}
+	return append(types, t)
+}
@@ -0,0 +1,126 @@
+package serve
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/securityevent"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/signingcap"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that transactions moving more than the cap of the identity the client
+// authenticated as are not signed, and that clients authenticated as the
+// account are not limited.
+func TestAccountSign_signingCaps(t *testing.T) {
+	session := dbtest.Open(t).Open()
+	s := &account.DBStore{DB: session}
+	s.Add(account.Account{
+		Address: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+		Identities: []account.Identity{
+			{
+				Role: "owner",
+				AuthMethods: []account.AuthMethod{
+					{Type: account.AuthMethodTypePhoneNumber, Value: "+10000000000"},
+				},
+			},
+		},
+	})
+	sink := &securityEventRecorder{}
+	securityEvents := &securityevent.Emitter{Logger: supportlog.DefaultLogger, Sink: sink}
+	h := accountSignHandler{
+		Logger:       supportlog.DefaultLogger,
+		AccountStore: s,
+		SigningKeys: []*keypair.Full{
+			keypair.MustParseFull("SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK"), // GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H
+		},
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		SecurityEvents:    securityEvents,
+		SigningCaps: &signingcap.Limiter{
+			Caps: []signingcap.Cap{
+				{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 100000000},
+			},
+			Store: &signingcap.DBStore{DB: session},
+		},
+	}
+
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &txnbuild.SimpleAccount{AccountID: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"},
+			IncrementSequenceNum: true,
+			Operations: []txnbuild.Operation{
+				&txnbuild.Payment{
+					Destination: "GD7CGJSJ5OBOU5KOP2UQDH3MPY75UTEY27HVV5XPSL2X6DJ2VGTOSXEU",
+					Amount:      "6",
+					Asset:       txnbuild.NativeAsset{},
+				},
+			},
+			BaseFee:    txnbuild.MinBaseFee,
+			Timebounds: txnbuild.NewTimebounds(0, 1),
+		},
+	)
+	require.NoError(t, err)
+	txEnc, err := tx.Base64()
+	require.NoError(t, err)
+	t.Log("Tx:", txEnc)
+
+	sign := func(a auth.Auth) *http.Response {
+		ctx := context.Background()
+		ctx = auth.NewContext(ctx, a)
+		req := `{
+	"transaction": "` + txEnc + `"
+}`
+		r := httptest.NewRequest("POST", "/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/sign/GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", strings.NewReader(req))
+		r = r.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		m := chi.NewMux()
+		m.Post("/{address}/sign/{signing-address}", h.ServeHTTP)
+		m.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	resp := sign(auth.Auth{PhoneNumber: "+10000000000"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = sign(auth.Auth{PhoneNumber: "+10000000000"})
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	wantBody := `{
+	"error": "The transaction exceeds the amount the authenticated identity is permitted to sign for."
+}`
+	assert.JSONEq(t, wantBody, string(body))
+
+	resp = sign(auth.Auth{Address: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Only the refused request emitted an event.
+	securityEvents.Close()
+	require.Len(t, sink.Events, 1)
+	assert.Equal(t, securityevent.TypeSigningCapExceeded, sink.Events[0].Type)
+	assert.Equal(t, "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4", sink.Events[0].Account)
+	assert.Equal(t, map[string]string{
+		"auth_method_type": "phone_number",
+		"asset":            "native",
+		"amount":           "6.0000000",
+		"used":             "6.0000000",
+		"limit":            "10.0000000",
+	}, sink.Events[0].Details)
+}
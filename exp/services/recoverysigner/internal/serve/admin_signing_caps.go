@@ -0,0 +1,206 @@
+package serve
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/signingcap"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/http/httpdecode"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+type adminSigningCapsResponse struct {
+	Address     string                        `json:"address"`
+	WindowStart time.Time                     `json:"window_start"`
+	Caps        []adminSigningCapsResponseCap `json:"caps"`
+}
+
+type adminSigningCapsResponseCap struct {
+	AuthMethodType account.AuthMethodType `json:"auth_method_type"`
+	Asset          string                 `json:"asset"`
+	Amount         string                 `json:"amount"`
+	Used           string                 `json:"used"`
+	Override       bool                   `json:"override"`
+}
+
+func renderAdminSigningCaps(w http.ResponseWriter, l *supportlog.Entry, limiter *signingcap.Limiter, address string) {
+	caps, windowStart, err := limiter.AccountCaps(address, time.Now())
+	if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+	resp := adminSigningCapsResponse{
+		Address:     address,
+		WindowStart: windowStart,
+		Caps:        []adminSigningCapsResponseCap{},
+	}
+	for _, c := range caps {
+		resp.Caps = append(resp.Caps, adminSigningCapsResponseCap{
+			AuthMethodType: c.AuthMethodType,
+			Asset:          c.Asset,
+			Amount:         amount.StringFromInt64(c.Amount),
+			Used:           amount.StringFromInt64(c.Used),
+			Override:       c.Override,
+		})
+	}
+	httpjson.Render(w, resp, httpjson.JSON)
+}
+
+type adminSigningCapsGetHandler struct {
+	Logger      *supportlog.Entry
+	SigningCaps *signingcap.Limiter
+}
+
+type adminSigningCapsGetRequest struct {
+	Address *keypair.FromAddress `path:"address"`
+}
+
+func (h adminSigningCapsGetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := adminSigningCapsGetRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(r.Context()).
+		WithField("account", req.Address.Address())
+
+	renderAdminSigningCaps(w, l, h.SigningCaps, req.Address.Address())
+}
+
+type adminSigningCapsPutHandler struct {
+	Logger      *supportlog.Entry
+	SigningCaps *signingcap.Limiter
+}
+
+type adminSigningCapsPutRequest struct {
+	Address        *keypair.FromAddress `path:"address"`
+	AuthMethodType string               `path:"auth-method-type"`
+	Asset          string               `path:"asset"`
+	Amount         string               `json:"amount" form:"amount"`
+}
+
+func (h adminSigningCapsPutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := adminSigningCapsPutRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil {
+		badRequest.Render(w)
+		return
+	}
+	c, err := signingcap.ParseCap(req.AuthMethodType + ":" + req.Amount + ":" + req.Asset)
+	if err != nil {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(r.Context()).
+		WithField("account", req.Address.Address()).
+		WithField("auth_method_type", c.AuthMethodType).
+		WithField("asset", c.Asset).
+		WithField("amount", amount.StringFromInt64(c.Amount))
+
+	l.Info("Request to override signing cap.")
+
+	err = h.SigningCaps.Store.SetOverride(req.Address.Address(), c)
+	if err == account.ErrNotFound {
+		l.Info("Account not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	l.Info("Signing cap overridden.")
+
+	renderAdminSigningCaps(w, l, h.SigningCaps, req.Address.Address())
+}
+
+type adminSigningCapsDeleteHandler struct {
+	Logger      *supportlog.Entry
+	SigningCaps *signingcap.Limiter
+}
+
+type adminSigningCapsDeleteRequest struct {
+	Address        *keypair.FromAddress `path:"address"`
+	AuthMethodType string               `path:"auth-method-type"`
+	Asset          string               `path:"asset"`
+}
+
+func (h adminSigningCapsDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := adminSigningCapsDeleteRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil {
+		badRequest.Render(w)
+		return
+	}
+	authMethodType := account.AuthMethodType(req.AuthMethodType)
+	asset, err := signingcap.ParseAsset(req.Asset)
+	if err != nil || !authMethodType.Valid() {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(r.Context()).
+		WithField("account", req.Address.Address()).
+		WithField("auth_method_type", authMethodType).
+		WithField("asset", asset)
+
+	l.Info("Request to delete signing cap override.")
+
+	err = h.SigningCaps.Store.DeleteOverride(req.Address.Address(), authMethodType, asset)
+	if err == signingcap.ErrNotFound {
+		l.Info("Signing cap override not found.")
+		notFound.Render(w)
+		return
+	} else if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	l.Info("Signing cap override deleted.")
+
+	renderAdminSigningCaps(w, l, h.SigningCaps, req.Address.Address())
+}
+
+type adminSigningCapsUsageDeleteHandler struct {
+	Logger      *supportlog.Entry
+	SigningCaps *signingcap.Limiter
+}
+
+type adminSigningCapsUsageDeleteRequest struct {
+	Address *keypair.FromAddress `path:"address"`
+}
+
+func (h adminSigningCapsUsageDeleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := adminSigningCapsUsageDeleteRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Address == nil {
+		badRequest.Render(w)
+		return
+	}
+
+	l := h.Logger.Ctx(r.Context()).
+		WithField("account", req.Address.Address())
+
+	l.Info("Request to reset signing cap usage.")
+
+	err = h.SigningCaps.Store.ResetUsage(req.Address.Address())
+	if err != nil {
+		l.Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	l.Info("Signing cap usage reset.")
+
+	renderAdminSigningCaps(w, l, h.SigningCaps, req.Address.Address())
+}
@@ -0,0 +1,121 @@
+package serve
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/signingcap"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_signingCaps(t *testing.T) {
+	session := dbtest.Open(t).Open()
+	s := &account.DBStore{DB: session}
+	s.Add(account.Account{
+		Address: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+	})
+
+	limiter := &signingcap.Limiter{
+		Caps: []signingcap.Cap{
+			{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 50000000},
+		},
+		Store: &signingcap.DBStore{DB: session},
+	}
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &txnbuild.SimpleAccount{AccountID: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"},
+			IncrementSequenceNum: true,
+			Operations: []txnbuild.Operation{
+				&txnbuild.Payment{
+					Destination: "GD7CGJSJ5OBOU5KOP2UQDH3MPY75UTEY27HVV5XPSL2X6DJ2VGTOSXEU",
+					Amount:      "2",
+					Asset:       txnbuild.NativeAsset{},
+				},
+			},
+			BaseFee:    txnbuild.MinBaseFee,
+			Timebounds: txnbuild.NewTimebounds(0, 1),
+		},
+	)
+	require.NoError(t, err)
+	err = limiter.Reserve("GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4", []account.AuthMethodType{account.AuthMethodTypeEmail}, tx, time.Now())
+	require.NoError(t, err)
+	windowStart := limiter.WindowStart(time.Now()).Format(time.RFC3339)
+
+	h := adminHandler(adminDeps{
+		Logger:          supportlog.DefaultLogger,
+		MetricsGatherer: prometheus.NewRegistry(),
+		SigningCaps:     limiter,
+	})
+	do := func(method, path, body string) (int, string) {
+		var reqBody io.Reader
+		if body != "" {
+			reqBody = strings.NewReader(body)
+		}
+		r := httptest.NewRequest(method, path, reqBody)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		resp := w.Result()
+		respBody, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(respBody)
+	}
+
+	status, body := do("GET", "/accounts/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/signing-caps", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{
+	"address": "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+	"window_start": "`+windowStart+`",
+	"caps": [
+		{"auth_method_type": "email", "asset": "native", "amount": "5.0000000", "used": "2.0000000", "override": false}
+	]
+}`, body)
+
+	status, body = do("PUT", "/accounts/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/signing-caps/email/native", `{"amount": "20"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{
+	"address": "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+	"window_start": "`+windowStart+`",
+	"caps": [
+		{"auth_method_type": "email", "asset": "native", "amount": "20.0000000", "used": "2.0000000", "override": true}
+	]
+}`, body)
+
+	status, _ = do("PUT", "/accounts/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/signing-caps/sms/native", `{"amount": "20"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do("PUT", "/accounts/GBLOP46WEVXWO5N75TDX7GXLYFQE3XLDT5NQ2VYIBEWWEMSZWR3AUISZ/signing-caps/email/native", `{"amount": "20"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = do("DELETE", "/accounts/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/signing-caps/usage", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{
+	"address": "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+	"window_start": "`+windowStart+`",
+	"caps": [
+		{"auth_method_type": "email", "asset": "native", "amount": "20.0000000", "used": "0.0000000", "override": true}
+	]
+}`, body)
+
+	status, body = do("DELETE", "/accounts/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/signing-caps/email/native", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.JSONEq(t, `{
+	"address": "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4",
+	"window_start": "`+windowStart+`",
+	"caps": [
+		{"auth_method_type": "email", "asset": "native", "amount": "5.0000000", "used": "0.0000000", "override": false}
+	]
+}`, body)
+
+	status, _ = do("DELETE", "/accounts/GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4/signing-caps/email/native", "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
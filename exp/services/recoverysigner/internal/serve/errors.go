@@ -26,6 +26,10 @@ var conflict = errorResponse{
 	Status: http.StatusConflict,
 	Error:  "The request could not be completed because the resource already exists.",
 }
+var signingCapExceeded = errorResponse{
+	Status: http.StatusForbidden,
+	Error:  "The transaction exceeds the amount the authenticated identity is permitted to sign for.",
+}
 var unauthorized = errorResponse{
 	Status: http.StatusUnauthorized,
 	Error:  "The request could not be authenticated.",
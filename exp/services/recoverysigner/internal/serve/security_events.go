@@ -0,0 +1,39 @@
+package serve
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/securityevent"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+)
+
+// newSecurityEvent returns an event of the type populated with the details
+// of the request that caused it.
+func newSecurityEvent(r *http.Request, t securityevent.Type, account string) securityevent.Event {
+	return securityevent.Event{
+		Type:       t,
+		Account:    account,
+		RemoteAddr: r.RemoteAddr,
+		RequestID:  middleware.GetReqID(r.Context()),
+	}
+}
+
+// authFailedMiddleware emits a security event for requests that carry an
+// Authorization header that none of the preceding auth middlewares could
+// verify.
+func authFailedMiddleware(em *securityevent.Emitter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if r.Header.Get("Authorization") != "" {
+				if _, ok := auth.FromContext(ctx); !ok {
+					e := newSecurityEvent(r, securityevent.TypeAuthenticationFailed, "")
+					e.Details = map[string]string{"path": r.URL.Path}
+					em.Emit(ctx, e)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package serve
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/securityevent"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type securityEventRecorder struct {
+	Events []securityevent.Event
+}
+
+func (s *securityEventRecorder) Emit(ctx context.Context, e securityevent.Event) error {
+	s.Events = append(s.Events, e)
+	return nil
+}
+
+func TestAuthFailedMiddleware(t *testing.T) {
+	sink := &securityEventRecorder{}
+	em := &securityevent.Emitter{Logger: supportlog.DefaultLogger, Sink: sink}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := authFailedMiddleware(em)(next)
+
+	// No credentials.
+	r := httptest.NewRequest("GET", "/accounts", nil)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Verified credentials.
+	r = httptest.NewRequest("GET", "/accounts", nil)
+	r.Header.Set("Authorization", "Bearer valid")
+	r = r.WithContext(auth.NewContext(r.Context(), auth.Auth{Address: "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"}))
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Unverified credentials.
+	r = httptest.NewRequest("GET", "/accounts", nil)
+	r.Header.Set("Authorization", "Bearer invalid")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Only the request with unverified credentials emitted an event.
+	em.Close()
+	require.Len(t, sink.Events, 1)
+	assert.Equal(t, securityevent.TypeAuthenticationFailed, sink.Events[0].Type)
+	assert.Equal(t, "/accounts", sink.Events[0].Details["path"])
+	assert.Equal(t, r.RemoteAddr, sink.Events[0].RemoteAddr)
+}
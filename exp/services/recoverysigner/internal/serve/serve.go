@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	firebaseauth "firebase.google.com/go/auth"
 	"github.com/go-chi/chi"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/db"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/securityevent"
 	"github.com/stellar/go/exp/services/recoverysigner/internal/serve/auth"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/signingcap"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/errors"
 	supporthttp "github.com/stellar/go/support/http"
@@ -35,6 +39,13 @@ type Options struct {
 	MetricsNamespace string
 
 	AllowedSourceAccounts string
+
+	SecurityEventSinkURL   string
+	SecurityEventSinkToken string
+	SigningVelocityLimit   int
+	SigningVelocityWindow  time.Duration
+	SigningCaps            string
+	SigningCapWindow       time.Duration
 }
 
 func Serve(opts Options) {
@@ -48,6 +59,7 @@ func Serve(opts Options) {
 		adminDeps := adminDeps{
 			Logger:          opts.Logger,
 			MetricsGatherer: deps.MetricsRegistry,
+			SigningCaps:     deps.SigningCaps,
 		}
 		go serveAdmin(opts, adminDeps)
 	}
@@ -61,6 +73,9 @@ func Serve(opts Options) {
 		OnStarting: func() {
 			deps.Logger.Infof("Starting SEP-30 Recovery Signer server on %s", addr)
 		},
+		OnStopped: func() {
+			deps.SecurityEvents.Close()
+		},
 	})
 }
 
@@ -75,6 +90,9 @@ type handlerDeps struct {
 	FirebaseAuthClient    *firebaseauth.Client
 	MetricsRegistry       *prometheus.Registry
 	AllowedSourceAccounts []*keypair.FromAddress
+	SecurityEvents        *securityevent.Emitter
+	SigningVelocity       *securityevent.VelocityTracker
+	SigningCaps           *signingcap.Limiter
 }
 
 func getHandlerDeps(opts Options) (handlerDeps, error) {
@@ -153,6 +171,19 @@ func getHandlerDeps(opts Options) (handlerDeps, error) {
 		allowedSourceAccounts = append(allowedSourceAccounts, accountAddress)
 	}
 
+	signingCaps, err := signingcap.ParseCaps(opts.SigningCaps)
+	if err != nil {
+		return handlerDeps{}, errors.Wrap(err, "parsing signing caps")
+	}
+	for _, c := range signingCaps {
+		opts.Logger.Infof("Signing cap: %s may move %s of %s per %v", c.AuthMethodType, amount.StringFromInt64(c.Amount), c.Asset, opts.SigningCapWindow)
+	}
+
+	securityEventSink, err := securityevent.NewSink(opts.Logger, opts.SecurityEventSinkURL, opts.SecurityEventSinkToken)
+	if err != nil {
+		return handlerDeps{}, errors.Wrap(err, "setting up security event sink")
+	}
+
 	deps := handlerDeps{
 		Logger:                opts.Logger,
 		NetworkPassphrase:     opts.NetworkPassphrase,
@@ -164,6 +195,19 @@ func getHandlerDeps(opts Options) (handlerDeps, error) {
 		FirebaseAuthClient:    firebaseAuthClient,
 		MetricsRegistry:       metricsRegistry,
 		AllowedSourceAccounts: allowedSourceAccounts,
+		SecurityEvents: &securityevent.Emitter{
+			Logger: opts.Logger,
+			Sink:   securityEventSink,
+		},
+		SigningVelocity: &securityevent.VelocityTracker{
+			Window: opts.SigningVelocityWindow,
+			Limit:  opts.SigningVelocityLimit,
+		},
+		SigningCaps: &signingcap.Limiter{
+			Caps:   signingCaps,
+			Window: opts.SigningCapWindow,
+			Store:  &signingcap.DBStore{DB: db},
+		},
 	}
 
 	return deps, nil
@@ -179,6 +223,7 @@ func handler(deps handlerDeps) http.Handler {
 	mux.Route("/accounts", func(mux chi.Router) {
 		mux.Use(auth.SEP10Middleware(deps.SEP10JWTIssuer, deps.SEP10JWKS))
 		mux.Use(auth.FirebaseMiddleware(auth.FirebaseTokenVerifierLive{AuthClient: deps.FirebaseAuthClient}))
+		mux.Use(authFailedMiddleware(deps.SecurityEvents))
 		mux.Get("/", accountListHandler{
 			Logger:           deps.Logger,
 			SigningAddresses: deps.SigningAddresses,
@@ -211,6 +256,9 @@ func handler(deps handlerDeps) http.Handler {
 				NetworkPassphrase:     deps.NetworkPassphrase,
 				AccountStore:          deps.AccountStore,
 				AllowedSourceAccounts: deps.AllowedSourceAccounts,
+				SecurityEvents:        deps.SecurityEvents,
+				SigningVelocity:       deps.SigningVelocity,
+				SigningCaps:           deps.SigningCaps,
 			}
 			mux.Post("/sign", signHandler.ServeHTTP)
 			mux.Post("/sign/{signing-address}", signHandler.ServeHTTP)
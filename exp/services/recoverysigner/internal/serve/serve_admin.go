@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/go-chi/chi"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/signingcap"
 	supporthttp "github.com/stellar/go/support/http"
 	supportlog "github.com/stellar/go/support/log"
 )
@@ -26,10 +28,31 @@ func serveAdmin(opts Options, deps adminDeps) {
 type adminDeps struct {
 	Logger          *supportlog.Entry
 	MetricsGatherer prometheus.Gatherer
+	SigningCaps     *signingcap.Limiter
 }
 
 func adminHandler(deps adminDeps) http.Handler {
 	mux := supporthttp.NewMux(deps.Logger)
 	mux.Handle("/metrics", promhttp.HandlerFor(deps.MetricsGatherer, promhttp.HandlerOpts{}))
+	if deps.SigningCaps != nil {
+		mux.Route("/accounts/{address}/signing-caps", func(mux chi.Router) {
+			mux.Get("/", adminSigningCapsGetHandler{
+				Logger:      deps.Logger,
+				SigningCaps: deps.SigningCaps,
+			}.ServeHTTP)
+			mux.Delete("/usage", adminSigningCapsUsageDeleteHandler{
+				Logger:      deps.Logger,
+				SigningCaps: deps.SigningCaps,
+			}.ServeHTTP)
+			mux.Put("/{auth-method-type}/{asset}", adminSigningCapsPutHandler{
+				Logger:      deps.Logger,
+				SigningCaps: deps.SigningCaps,
+			}.ServeHTTP)
+			mux.Delete("/{auth-method-type}/{asset}", adminSigningCapsDeleteHandler{
+				Logger:      deps.Logger,
+				SigningCaps: deps.SigningCaps,
+			}.ServeHTTP)
+		})
+	}
 	return mux
 }
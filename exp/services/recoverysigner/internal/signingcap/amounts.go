@@ -0,0 +1,90 @@
+package signingcap
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// Amounts returns the amounts of each asset, keyed by the canonical form of
+// the asset, that the operations of the transaction may move out of its
+// source account. Operations with a different source account are ignored.
+//
+// Sell offers count their full selling amount, buy offers the amount they
+// sell at their price, and path payments their maximum send amount. Account
+// merges move the entire native balance and so count as math.MaxInt64.
+func Amounts(tx *txnbuild.Transaction) map[string]int64 {
+	env := tx.ToXDR()
+	sourceAccount := env.SourceAccount().ToAccountId()
+
+	amounts := map[string]int64{}
+	add := func(asset xdr.Asset, amt xdr.Int64) {
+		key := asset.StringCanonical()
+		amounts[key] = addAmounts(amounts[key], int64(amt))
+	}
+
+	var native xdr.Asset
+	native.SetNative()
+
+	for _, op := range env.Operations() {
+		if op.SourceAccount != nil {
+			opSourceAccount := op.SourceAccount.ToAccountId()
+			if !opSourceAccount.Equals(sourceAccount) {
+				continue
+			}
+		}
+
+		body := op.Body
+		switch body.Type {
+		case xdr.OperationTypeCreateAccount:
+			add(native, body.CreateAccountOp.StartingBalance)
+		case xdr.OperationTypePayment:
+			add(body.PaymentOp.Asset, body.PaymentOp.Amount)
+		case xdr.OperationTypePathPaymentStrictReceive:
+			add(body.PathPaymentStrictReceiveOp.SendAsset, body.PathPaymentStrictReceiveOp.SendMax)
+		case xdr.OperationTypePathPaymentStrictSend:
+			add(body.PathPaymentStrictSendOp.SendAsset, body.PathPaymentStrictSendOp.SendAmount)
+		case xdr.OperationTypeManageSellOffer:
+			add(body.ManageSellOfferOp.Selling, body.ManageSellOfferOp.Amount)
+		case xdr.OperationTypeCreatePassiveSellOffer:
+			add(body.CreatePassiveSellOfferOp.Selling, body.CreatePassiveSellOfferOp.Amount)
+		case xdr.OperationTypeManageBuyOffer:
+			o := body.ManageBuyOfferOp
+			add(o.Selling, xdr.Int64(sellingAmount(int64(o.BuyAmount), o.Price)))
+		case xdr.OperationTypeCreateClaimableBalance:
+			add(body.CreateClaimableBalanceOp.Asset, body.CreateClaimableBalanceOp.Amount)
+		case xdr.OperationTypeAccountMerge:
+			add(native, math.MaxInt64)
+		}
+	}
+	return amounts
+}
+
+// sellingAmount returns the amount sold when buying the amount at the price,
+// the price being the price of the buying asset in terms of the selling
+// asset, rounded up.
+func sellingAmount(buyAmount int64, price xdr.Price) int64 {
+	if price.D == 0 {
+		return math.MaxInt64
+	}
+	n := new(big.Int).Mul(big.NewInt(buyAmount), big.NewInt(int64(price.N)))
+	d := big.NewInt(int64(price.D))
+	q, m := new(big.Int).QuoRem(n, d, new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if !q.IsInt64() {
+		return math.MaxInt64
+	}
+	return q.Int64()
+}
+
+// addAmounts adds the non-negative amounts, saturating at math.MaxInt64.
+func addAmounts(a, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
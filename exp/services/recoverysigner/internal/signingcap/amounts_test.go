@@ -0,0 +1,80 @@
+package signingcap
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAccount = "GA6HNE7O2N2IXIOBZNZ4IPTS2P6DSAJJF5GD5PDLH5GYOZ6WMPSKCXD4"
+	testOther   = "GBLOP46WEVXWO5N75TDX7GXLYFQE3XLDT5NQ2VYIBEWWEMSZWR3AUISZ"
+	testIssuer  = "GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN"
+	testUSDC    = "USDC:" + testIssuer
+)
+
+func newTestTransaction(t *testing.T, ops ...txnbuild.Operation) *txnbuild.Transaction {
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &txnbuild.SimpleAccount{AccountID: testAccount},
+			IncrementSequenceNum: true,
+			Operations:           ops,
+			BaseFee:              txnbuild.MinBaseFee,
+			Timebounds:           txnbuild.NewTimebounds(0, 1),
+		},
+	)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestAmounts(t *testing.T) {
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: testIssuer}
+	tx := newTestTransaction(t,
+		&txnbuild.Payment{Destination: testOther, Amount: "10", Asset: txnbuild.NativeAsset{}},
+		&txnbuild.Payment{Destination: testOther, Amount: "1", Asset: usdc},
+		&txnbuild.CreateAccount{Destination: testOther, Amount: "2"},
+		&txnbuild.PathPaymentStrictReceive{SendAsset: usdc, SendMax: "3", Destination: testOther, DestAsset: txnbuild.NativeAsset{}, DestAmount: "30"},
+		&txnbuild.PathPaymentStrictSend{SendAsset: txnbuild.NativeAsset{}, SendAmount: "4", Destination: testOther, DestAsset: usdc, DestMin: "0.4"},
+		&txnbuild.ManageSellOffer{Selling: usdc, Buying: txnbuild.NativeAsset{}, Amount: "5", Price: "10"},
+		&txnbuild.CreatePassiveSellOffer{Selling: txnbuild.NativeAsset{}, Buying: usdc, Amount: "6", Price: "0.1"},
+		&txnbuild.ManageBuyOffer{Selling: usdc, Buying: txnbuild.NativeAsset{}, Amount: "7", Price: "0.3"},
+		&txnbuild.CreateClaimableBalance{Amount: "8", Asset: txnbuild.NativeAsset{}, Destinations: []txnbuild.Claimant{txnbuild.NewClaimant(testOther, nil)}},
+		// Operations of other source accounts do not move the funds of the
+		// account.
+		&txnbuild.Payment{Destination: testAccount, Amount: "100", Asset: txnbuild.NativeAsset{}, SourceAccount: testOther},
+		// Operations that do not move funds are ignored.
+		&txnbuild.BumpSequence{BumpTo: 100},
+	)
+
+	assert.Equal(t, map[string]int64{
+		"native": 300000000,
+		testUSDC: 111000000,
+	}, Amounts(tx))
+}
+
+func TestAmounts_accountMerge(t *testing.T) {
+	tx := newTestTransaction(t,
+		&txnbuild.Payment{Destination: testOther, Amount: "10", Asset: txnbuild.NativeAsset{}},
+		&txnbuild.AccountMerge{Destination: testOther},
+	)
+	assert.Equal(t, map[string]int64{"native": math.MaxInt64}, Amounts(tx))
+}
+
+func TestAmounts_none(t *testing.T) {
+	tx := newTestTransaction(t,
+		&txnbuild.SetOptions{
+			Signer: &txnbuild.Signer{Address: testOther, Weight: 20},
+		},
+	)
+	assert.Empty(t, Amounts(tx))
+}
+
+func TestSellingAmount(t *testing.T) {
+	assert.Equal(t, int64(21000000), sellingAmount(70000000, xdr.Price{N: 3, D: 10}))
+	assert.Equal(t, int64(1), sellingAmount(1, xdr.Price{N: 1, D: 3}))
+	assert.Equal(t, int64(math.MaxInt64), sellingAmount(math.MaxInt64, xdr.Price{N: 2, D: 1}))
+}
@@ -0,0 +1,9 @@
+package signingcap
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+type DBStore struct {
+	DB *sqlx.DB
+}
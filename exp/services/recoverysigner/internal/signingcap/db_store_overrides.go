@@ -0,0 +1,69 @@
+package signingcap
+
+import (
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+)
+
+func (s *DBStore) Overrides(address string) ([]Cap, error) {
+	caps := []Cap{}
+	err := s.DB.Select(&caps, `
+		SELECT
+			signing_cap_overrides.auth_method_type,
+			signing_cap_overrides.asset,
+			signing_cap_overrides.amount
+		FROM signing_cap_overrides
+		JOIN accounts ON accounts.id = signing_cap_overrides.account_id
+		WHERE accounts.address = $1
+		ORDER BY signing_cap_overrides.auth_method_type, signing_cap_overrides.asset
+	`, address)
+	if err != nil {
+		return nil, err
+	}
+	return caps, nil
+}
+
+func (s *DBStore) SetOverride(address string, c Cap) error {
+	result, err := s.DB.Exec(`
+		INSERT INTO signing_cap_overrides (account_id, auth_method_type, asset, amount)
+		SELECT id, $2::auth_method_type, $3, $4::bigint
+		FROM accounts
+		WHERE address = $1
+		ON CONFLICT (account_id, auth_method_type, asset) DO UPDATE
+		SET amount = EXCLUDED.amount, updated_at = NOW()
+	`, address, c.AuthMethodType, c.Asset, c.Amount)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return account.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *DBStore) DeleteOverride(address string, authMethodType account.AuthMethodType, asset string) error {
+	result, err := s.DB.Exec(`
+		DELETE FROM signing_cap_overrides
+		USING accounts
+		WHERE accounts.id = signing_cap_overrides.account_id
+		AND accounts.address = $1
+		AND signing_cap_overrides.auth_method_type = $2
+		AND signing_cap_overrides.asset = $3
+	`, address, authMethodType, asset)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package signingcap
+
+import (
+	"testing"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrides(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	accountStore := account.DBStore{DB: session}
+	err := accountStore.Add(account.Account{Address: testAccount})
+	require.NoError(t, err)
+
+	store := DBStore{DB: session}
+
+	overrides, err := store.Overrides(testAccount)
+	require.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	err = store.SetOverride(testAccount, Cap{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 10})
+	require.NoError(t, err)
+	err = store.SetOverride(testAccount, Cap{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 20})
+	require.NoError(t, err)
+	err = store.SetOverride(testAccount, Cap{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 30})
+	require.NoError(t, err)
+
+	overrides, err = store.Overrides(testAccount)
+	require.NoError(t, err)
+	assert.Equal(t, []Cap{
+		{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 20},
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 30},
+	}, overrides)
+
+	err = store.DeleteOverride(testAccount, account.AuthMethodTypePhoneNumber, "native")
+	require.NoError(t, err)
+	err = store.DeleteOverride(testAccount, account.AuthMethodTypePhoneNumber, "native")
+	assert.Equal(t, ErrNotFound, err)
+
+	overrides, err = store.Overrides(testAccount)
+	require.NoError(t, err)
+	assert.Equal(t, []Cap{
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 30},
+	}, overrides)
+}
+
+func TestSetOverride_accountNotFound(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	store := DBStore{DB: session}
+
+	err := store.SetOverride(testAccount, Cap{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 10})
+	assert.Equal(t, account.ErrNotFound, err)
+}
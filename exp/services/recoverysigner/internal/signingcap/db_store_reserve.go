@@ -0,0 +1,55 @@
+package signingcap
+
+import (
+	"database/sql"
+	"time"
+)
+
+func (s *DBStore) Reserve(address string, windowStart time.Time, reservations []Reservation) error {
+	tx, err := s.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, r := range reservations {
+		// The usage is only inserted or updated if the new amount stays
+		// within the limit, and the row stays locked until the transaction
+		// ends, so concurrent reservations cannot exceed the limit together.
+		used := int64(0)
+		err = tx.Get(&used, `
+			INSERT INTO signing_cap_usage (account_id, auth_method_type, asset, window_start, amount)
+			SELECT id, $2::auth_method_type, $3, $4::timestamptz, $5::bigint
+			FROM accounts
+			WHERE address = $1 AND $5::bigint <= $6::bigint
+			ON CONFLICT (account_id, auth_method_type, asset, window_start) DO UPDATE
+			SET amount = signing_cap_usage.amount + EXCLUDED.amount, updated_at = NOW()
+			WHERE EXCLUDED.amount <= $6::bigint - signing_cap_usage.amount
+			RETURNING amount
+		`, address, r.AuthMethodType, r.Asset, windowStart, r.Amount, r.Limit)
+		if err == sql.ErrNoRows {
+			err = tx.Get(&used, `
+				SELECT COALESCE(SUM(signing_cap_usage.amount), 0)
+				FROM signing_cap_usage
+				JOIN accounts ON accounts.id = signing_cap_usage.account_id
+				WHERE accounts.address = $1
+				AND signing_cap_usage.auth_method_type = $2
+				AND signing_cap_usage.asset = $3
+				AND signing_cap_usage.window_start = $4
+			`, address, r.AuthMethodType, r.Asset, windowStart)
+			if err != nil {
+				return err
+			}
+			return &ExceededError{Reservation: r, Used: used}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
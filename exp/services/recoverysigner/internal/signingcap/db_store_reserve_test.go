@@ -0,0 +1,65 @@
+package signingcap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserve(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	accountStore := account.DBStore{DB: session}
+	err := accountStore.Add(account.Account{Address: testAccount})
+	require.NoError(t, err)
+
+	store := DBStore{DB: session}
+	windowStart := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	err = store.Reserve(testAccount, windowStart, []Reservation{
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 30, Limit: 50},
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: testUSDC, Amount: 10, Limit: 10},
+	})
+	require.NoError(t, err)
+
+	err = store.Reserve(testAccount, windowStart, []Reservation{
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 20, Limit: 50},
+	})
+	require.NoError(t, err)
+
+	// None of the reservations are made if any of them exceeds its limit.
+	err = store.Reserve(testAccount, windowStart, []Reservation{
+		{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 5, Limit: 50},
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 1, Limit: 50},
+	})
+	assert.Equal(t, &ExceededError{
+		Reservation: Reservation{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 1, Limit: 50},
+		Used:        50,
+	}, err)
+
+	err = store.Reserve(testAccount, windowStart, []Reservation{
+		{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 51, Limit: 50},
+	})
+	assert.Equal(t, &ExceededError{
+		Reservation: Reservation{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 51, Limit: 50},
+		Used:        0,
+	}, err)
+
+	usage, err := store.Usage(testAccount, windowStart)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Usage{
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: testUSDC, Amount: 10},
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 50},
+	}, usage)
+
+	// The usage of another window is counted separately.
+	err = store.Reserve(testAccount, windowStart.Add(24*time.Hour), []Reservation{
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 50, Limit: 50},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,30 @@
+package signingcap
+
+import "time"
+
+func (s *DBStore) Usage(address string, windowStart time.Time) ([]Usage, error) {
+	usage := []Usage{}
+	err := s.DB.Select(&usage, `
+		SELECT
+			signing_cap_usage.auth_method_type,
+			signing_cap_usage.asset,
+			signing_cap_usage.amount
+		FROM signing_cap_usage
+		JOIN accounts ON accounts.id = signing_cap_usage.account_id
+		WHERE accounts.address = $1 AND signing_cap_usage.window_start = $2
+		ORDER BY signing_cap_usage.auth_method_type, signing_cap_usage.asset
+	`, address, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func (s *DBStore) ResetUsage(address string) error {
+	_, err := s.DB.Exec(`
+		DELETE FROM signing_cap_usage
+		USING accounts
+		WHERE accounts.id = signing_cap_usage.account_id AND accounts.address = $1
+	`, address)
+	return err
+}
@@ -0,0 +1,59 @@
+package signingcap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage(t *testing.T) {
+	db := dbtest.Open(t)
+	session := db.Open()
+
+	accountStore := account.DBStore{DB: session}
+	err := accountStore.Add(account.Account{Address: testAccount})
+	require.NoError(t, err)
+	err = accountStore.Add(account.Account{Address: testOther})
+	require.NoError(t, err)
+
+	store := DBStore{DB: session}
+	windowStart := time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	usage, err := store.Usage(testAccount, windowStart)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	for _, address := range []string{testAccount, testOther} {
+		err = store.Reserve(address, windowStart, []Reservation{
+			{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 20, Limit: 50},
+		})
+		require.NoError(t, err)
+	}
+
+	usage, err = store.Usage(testAccount, windowStart)
+	require.NoError(t, err)
+	assert.Equal(t, []Usage{
+		{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 20},
+	}, usage)
+
+	usage, err = store.Usage(testAccount, windowStart.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	err = store.ResetUsage(testAccount)
+	require.NoError(t, err)
+
+	usage, err = store.Usage(testAccount, windowStart)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	usage, err = store.Usage(testOther, windowStart)
+	require.NoError(t, err)
+	assert.Equal(t, []Usage{
+		{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 20},
+	}, usage)
+}
@@ -0,0 +1,141 @@
+package signingcap
+
+import (
+	"sort"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/txnbuild"
+)
+
+// DefaultWindow is the window used by a Limiter without a window.
+const DefaultWindow = 24 * time.Hour
+
+// Limiter enforces caps on the amounts moved by signed transactions within
+// fixed windows, e.g. per day. The caps configured for all accounts are
+// replaced, or added to, by the overrides stored for an account.
+type Limiter struct {
+	Caps   []Cap
+	Window time.Duration
+	Store  Store
+}
+
+// AccountCap is a cap in effect for an account along with its usage in the
+// current window.
+type AccountCap struct {
+	Cap
+	Override bool
+	Used     int64
+}
+
+// WindowStart returns the start of the window containing the time.
+func (l *Limiter) WindowStart(now time.Time) time.Time {
+	window := l.Window
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return now.UTC().Truncate(window)
+}
+
+// AccountCaps returns the caps in effect for the account, sorted by auth
+// method type and asset, and the start of the window the usage is for.
+func (l *Limiter) AccountCaps(address string, now time.Time) ([]AccountCap, time.Time, error) {
+	windowStart := l.WindowStart(now)
+	caps, err := l.caps(address)
+	if err != nil {
+		return nil, windowStart, err
+	}
+	usage, err := l.Store.Usage(address, windowStart)
+	if err != nil {
+		return nil, windowStart, err
+	}
+	for _, u := range usage {
+		k := capKey{u.AuthMethodType, u.Asset}
+		if c, ok := caps[k]; ok {
+			c.Used = u.Amount
+			caps[k] = c
+		}
+	}
+
+	accountCaps := make([]AccountCap, 0, len(caps))
+	for _, c := range caps {
+		accountCaps = append(accountCaps, c)
+	}
+	sort.Slice(accountCaps, func(i, j int) bool {
+		if accountCaps[i].AuthMethodType != accountCaps[j].AuthMethodType {
+			return accountCaps[i].AuthMethodType < accountCaps[j].AuthMethodType
+		}
+		return accountCaps[i].Asset < accountCaps[j].Asset
+	})
+	return accountCaps, windowStart, nil
+}
+
+// Reserve reserves the amounts the transaction moves out of the account
+// against the caps of the auth method types the client authenticated with,
+// and returns an *ExceededError if the transaction would exceed a cap.
+//
+// An asset is uncapped if any of the auth method types has no cap for it,
+// otherwise the amount is reserved against the most permissive cap.
+func (l *Limiter) Reserve(address string, authMethodTypes []account.AuthMethodType, tx *txnbuild.Transaction, now time.Time) error {
+	amounts := Amounts(tx)
+	if len(amounts) == 0 || len(authMethodTypes) == 0 {
+		return nil
+	}
+
+	caps, err := l.caps(address)
+	if err != nil {
+		return err
+	}
+
+	reservations := []Reservation{}
+	for asset, amt := range amounts {
+		if amt == 0 {
+			continue
+		}
+		var reservation *Reservation
+		for _, t := range authMethodTypes {
+			c, ok := caps[capKey{t, asset}]
+			if !ok {
+				reservation = nil
+				break
+			}
+			if reservation == nil || c.Amount > reservation.Limit {
+				reservation = &Reservation{AuthMethodType: t, Asset: asset, Amount: amt, Limit: c.Amount}
+			}
+		}
+		if reservation != nil {
+			reservations = append(reservations, *reservation)
+		}
+	}
+	if len(reservations) == 0 {
+		return nil
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		if reservations[i].AuthMethodType != reservations[j].AuthMethodType {
+			return reservations[i].AuthMethodType < reservations[j].AuthMethodType
+		}
+		return reservations[i].Asset < reservations[j].Asset
+	})
+
+	return l.Store.Reserve(address, l.WindowStart(now), reservations)
+}
+
+type capKey struct {
+	AuthMethodType account.AuthMethodType
+	Asset          string
+}
+
+func (l *Limiter) caps(address string) (map[capKey]AccountCap, error) {
+	caps := map[capKey]AccountCap{}
+	for _, c := range l.Caps {
+		caps[capKey{c.AuthMethodType, c.Asset}] = AccountCap{Cap: c}
+	}
+	overrides, err := l.Store.Overrides(address)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range overrides {
+		caps[capKey{c.AuthMethodType, c.Asset}] = AccountCap{Cap: c, Override: true}
+	}
+	return caps, nil
+}
@@ -0,0 +1,158 @@
+package signingcap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a Store for testing the Limiter without a database.
+type memoryStore struct {
+	usage     map[time.Time]map[capKey]int64
+	overrides []Cap
+}
+
+func (s *memoryStore) Reserve(address string, windowStart time.Time, reservations []Reservation) error {
+	if s.usage == nil {
+		s.usage = map[time.Time]map[capKey]int64{}
+	}
+	if s.usage[windowStart] == nil {
+		s.usage[windowStart] = map[capKey]int64{}
+	}
+	usage := s.usage[windowStart]
+	for _, r := range reservations {
+		used := usage[capKey{r.AuthMethodType, r.Asset}]
+		if r.Amount > r.Limit-used {
+			return &ExceededError{Reservation: r, Used: used}
+		}
+	}
+	for _, r := range reservations {
+		usage[capKey{r.AuthMethodType, r.Asset}] += r.Amount
+	}
+	return nil
+}
+
+func (s *memoryStore) Usage(address string, windowStart time.Time) ([]Usage, error) {
+	usage := []Usage{}
+	for k, amt := range s.usage[windowStart] {
+		usage = append(usage, Usage{AuthMethodType: k.AuthMethodType, Asset: k.Asset, Amount: amt})
+	}
+	return usage, nil
+}
+
+func (s *memoryStore) ResetUsage(address string) error {
+	s.usage = nil
+	return nil
+}
+
+func (s *memoryStore) Overrides(address string) ([]Cap, error) {
+	return s.overrides, nil
+}
+
+func (s *memoryStore) SetOverride(address string, c Cap) error {
+	s.overrides = append(s.overrides, c)
+	return nil
+}
+
+func (s *memoryStore) DeleteOverride(address string, authMethodType account.AuthMethodType, asset string) error {
+	return ErrNotFound
+}
+
+func TestLimiter_reserve(t *testing.T) {
+	store := &memoryStore{}
+	l := &Limiter{
+		Caps: []Cap{
+			{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 100000000},
+			{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 50000000},
+		},
+		Store: store,
+	}
+	now := time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)
+	pay := func(amt string) *txnbuild.Transaction {
+		return newTestTransaction(t, &txnbuild.Payment{Destination: testOther, Amount: amt, Asset: txnbuild.NativeAsset{}})
+	}
+	email := []account.AuthMethodType{account.AuthMethodTypeEmail}
+
+	require.NoError(t, l.Reserve(testAccount, email, pay("3"), now))
+	err := l.Reserve(testAccount, email, pay("3"), now)
+	assert.EqualError(t, err, "signing cap for email of native exceeded: 3.0000000 requested, 3.0000000 of 5.0000000 used")
+	require.NoError(t, l.Reserve(testAccount, email, pay("2"), now))
+
+	// The most permissive cap applies when authenticated with multiple auth
+	// methods.
+	both := []account.AuthMethodType{account.AuthMethodTypeEmail, account.AuthMethodTypePhoneNumber}
+	require.NoError(t, l.Reserve(testAccount, both, pay("6"), now))
+
+	// Auth method types without a cap are not limited.
+	address := []account.AuthMethodType{account.AuthMethodTypeAddress}
+	require.NoError(t, l.Reserve(testAccount, address, pay("1000"), now))
+	require.NoError(t, l.Reserve(testAccount, append(both, account.AuthMethodTypeAddress), pay("1000"), now))
+
+	// Assets without a cap are not limited.
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: testIssuer}
+	require.NoError(t, l.Reserve(testAccount, email, newTestTransaction(t, &txnbuild.Payment{Destination: testOther, Amount: "1000", Asset: usdc}), now))
+
+	// The usage is counted per window.
+	require.NoError(t, l.Reserve(testAccount, email, pay("5"), now.Add(24*time.Hour)))
+
+	caps, windowStart, err := l.AccountCaps(testAccount, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), windowStart)
+	assert.Equal(t, []AccountCap{
+		{Cap: Cap{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 50000000}, Used: 50000000},
+		{Cap: Cap{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 100000000}, Used: 60000000},
+	}, caps)
+}
+
+func TestLimiter_overrides(t *testing.T) {
+	store := &memoryStore{
+		overrides: []Cap{
+			{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 200000000},
+			{AuthMethodType: account.AuthMethodTypeEmail, Asset: testUSDC, Amount: 0},
+		},
+	}
+	l := &Limiter{
+		Caps: []Cap{
+			{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 50000000},
+		},
+		Window: time.Hour,
+		Store:  store,
+	}
+	now := time.Date(2021, 4, 1, 12, 30, 0, 0, time.UTC)
+	email := []account.AuthMethodType{account.AuthMethodTypeEmail}
+
+	require.NoError(t, l.Reserve(testAccount, email, newTestTransaction(t, &txnbuild.Payment{Destination: testOther, Amount: "20", Asset: txnbuild.NativeAsset{}}), now))
+
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: testIssuer}
+	err := l.Reserve(testAccount, email, newTestTransaction(t, &txnbuild.Payment{Destination: testOther, Amount: "0.0000001", Asset: usdc}), now)
+	require.IsType(t, &ExceededError{}, err)
+	assert.Equal(t, testUSDC, err.(*ExceededError).Reservation.Asset)
+
+	caps, windowStart, err := l.AccountCaps(testAccount, now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC), windowStart)
+	assert.Equal(t, []AccountCap{
+		{Cap: Cap{AuthMethodType: account.AuthMethodTypeEmail, Asset: testUSDC, Amount: 0}, Override: true},
+		{Cap: Cap{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 200000000}, Override: true, Used: 200000000},
+	}, caps)
+}
+
+func TestLimiter_accountMerge(t *testing.T) {
+	l := &Limiter{
+		Caps: []Cap{
+			{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 50000000},
+		},
+		Store: &memoryStore{},
+	}
+	now := time.Now()
+
+	tx := newTestTransaction(t, &txnbuild.AccountMerge{Destination: testOther})
+	err := l.Reserve(testAccount, []account.AuthMethodType{account.AuthMethodTypeEmail}, tx, now)
+	assert.IsType(t, &ExceededError{}, err)
+	err = l.Reserve(testAccount, []account.AuthMethodType{account.AuthMethodTypePhoneNumber}, tx, now)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,133 @@
+// Package signingcap limits the amounts that the transactions signed for an
+// account may move out of it, depending on the auth method the client
+// authenticated with, e.g. a client authenticated with a phone number may
+// move up to 1000 XLM per day while one authenticated with an email may move
+// up to 500 XLM per day.
+package signingcap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// Cap is the amount of an asset that may be moved out of an account within a
+// window by transactions signed for a client authenticated with an auth
+// method type.
+type Cap struct {
+	AuthMethodType account.AuthMethodType `db:"auth_method_type"`
+	// Asset is the canonical form of the asset, "native" or "CODE:ISSUER".
+	Asset string `db:"asset"`
+	// Amount is in stroops.
+	Amount int64 `db:"amount"`
+}
+
+// ParseCaps parses a comma separated list of caps, each formatted as
+// <auth-method-type>:<amount>:<asset>, e.g.
+// "phone_number:1000:native,email:500:USDC:GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN".
+func ParseCaps(s string) ([]Cap, error) {
+	caps := []Cap{}
+	seen := map[string]bool{}
+	for _, capStr := range strings.Split(s, ",") {
+		capStr = strings.TrimSpace(capStr)
+		if capStr == "" {
+			continue
+		}
+		c, err := ParseCap(capStr)
+		if err != nil {
+			return nil, err
+		}
+		key := string(c.AuthMethodType) + "/" + c.Asset
+		if seen[key] {
+			return nil, errors.Errorf("signing cap %q is configured more than once for the auth method type and asset", capStr)
+		}
+		seen[key] = true
+		caps = append(caps, c)
+	}
+	return caps, nil
+}
+
+// ParseCap parses a cap formatted as <auth-method-type>:<amount>:<asset>.
+func ParseCap(s string) (Cap, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return Cap{}, errors.Errorf("signing cap %q is not formatted as <auth-method-type>:<amount>:<asset>", s)
+	}
+	authMethodType := account.AuthMethodType(parts[0])
+	if !authMethodType.Valid() {
+		return Cap{}, errors.Errorf("signing cap %q has an invalid auth method type", s)
+	}
+	amt, err := ParseAmount(parts[1])
+	if err != nil {
+		return Cap{}, errors.Wrapf(err, "signing cap %q has an invalid amount", s)
+	}
+	asset, err := ParseAsset(parts[2])
+	if err != nil {
+		return Cap{}, errors.Wrapf(err, "signing cap %q has an invalid asset", s)
+	}
+	return Cap{AuthMethodType: authMethodType, Asset: asset, Amount: amt}, nil
+}
+
+// ParseAmount parses a non-negative decimal amount, e.g. "10.5", into stroops.
+func ParseAmount(s string) (int64, error) {
+	amt, err := amount.ParseInt64(s)
+	if err != nil {
+		return 0, err
+	}
+	if amt < 0 {
+		return 0, errors.New("amount is negative")
+	}
+	return amt, nil
+}
+
+// ParseAsset parses an asset formatted as "native" or "CODE:ISSUER" and
+// returns its canonical form.
+func ParseAsset(s string) (string, error) {
+	assets, err := xdr.BuildAssets(s)
+	if err != nil {
+		return "", err
+	}
+	if len(assets) != 1 {
+		return "", fmt.Errorf("%s is not a valid asset", s)
+	}
+	return assets[0].StringCanonical(), nil
+}
+
+// Reservation is an amount of an asset reserved against the cap of an auth
+// method type.
+type Reservation struct {
+	AuthMethodType account.AuthMethodType
+	Asset          string
+	Amount         int64
+	Limit          int64
+}
+
+// Usage is the amount of an asset moved out of an account within a window
+// by transactions signed for a client authenticated with an auth method type.
+type Usage struct {
+	AuthMethodType account.AuthMethodType `db:"auth_method_type"`
+	Asset          string                 `db:"asset"`
+	Amount         int64                  `db:"amount"`
+}
+
+// ExceededError is returned when reserving an amount would exceed a cap.
+type ExceededError struct {
+	Reservation Reservation
+	// Used is the amount already reserved within the window.
+	Used int64
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf(
+		"signing cap for %s of %s exceeded: %s requested, %s of %s used",
+		e.Reservation.AuthMethodType,
+		e.Reservation.Asset,
+		amount.StringFromInt64(e.Reservation.Amount),
+		amount.StringFromInt64(e.Used),
+		amount.StringFromInt64(e.Reservation.Limit),
+	)
+}
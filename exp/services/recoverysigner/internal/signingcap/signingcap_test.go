@@ -0,0 +1,52 @@
+package signingcap
+
+import (
+	"testing"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCaps(t *testing.T) {
+	caps, err := ParseCaps("")
+	require.NoError(t, err)
+	assert.Empty(t, caps)
+
+	caps, err = ParseCaps("phone_number:1000:native, email:0.5:USDC:GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN,email:500:NATIVE")
+	require.NoError(t, err)
+	assert.Equal(t, []Cap{
+		{AuthMethodType: account.AuthMethodTypePhoneNumber, Asset: "native", Amount: 10000000000},
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "USDC:GA5ZSEJYB37JRC5AVCIA5MOP4RHTM335X2KGX3IHOJAPP5RE34K4KZVN", Amount: 5000000},
+		{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 5000000000},
+	}, caps)
+}
+
+func TestParseCaps_invalid(t *testing.T) {
+	testCases := []struct {
+		caps    string
+		wantErr string
+	}{
+		{"phone_number:1000", `signing cap "phone_number:1000" is not formatted as <auth-method-type>:<amount>:<asset>`},
+		{"sms:1000:native", `signing cap "sms:1000:native" has an invalid auth method type`},
+		{"email:-1:native", `signing cap "email:-1:native" has an invalid amount: amount is negative`},
+		{"email:ten:native", `signing cap "email:ten:native" has an invalid amount: invalid amount format: ten`},
+		{"email:10:USDC", `signing cap "email:10:USDC" has an invalid asset: USDC is not a valid asset`},
+		{"email:10:USDC:GABC", `signing cap "email:10:USDC:GABC" has an invalid asset: USDC:GABC is not a valid asset, it contains an invalid issuer`},
+		{"email:10:native,email:20:native", `signing cap "email:20:native" is configured more than once for the auth method type and asset`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caps, func(t *testing.T) {
+			_, err := ParseCaps(tc.caps)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestExceededError(t *testing.T) {
+	err := &ExceededError{
+		Reservation: Reservation{AuthMethodType: account.AuthMethodTypeEmail, Asset: "native", Amount: 20000000, Limit: 50000000},
+		Used:        40000000,
+	}
+	assert.EqualError(t, err, "signing cap for email of native exceeded: 2.0000000 requested, 4.0000000 of 5.0000000 used")
+}
@@ -0,0 +1,23 @@
+package signingcap
+
+import (
+	"errors"
+	"time"
+
+	"github.com/stellar/go/exp/services/recoverysigner/internal/account"
+)
+
+type Store interface {
+	// Reserve adds the amounts of the reservations to the usage of the
+	// account within the window starting at windowStart. Either all or none
+	// of the reservations are made, and an *ExceededError is returned if any
+	// of them would exceed its limit.
+	Reserve(address string, windowStart time.Time, reservations []Reservation) error
+	Usage(address string, windowStart time.Time) ([]Usage, error)
+	ResetUsage(address string) error
+	Overrides(address string) ([]Cap, error)
+	SetOverride(address string, c Cap) error
+	DeleteOverride(address string, authMethodType account.AuthMethodType, asset string) error
+}
+
+var ErrNotFound = errors.New("signing cap override not found")
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"go/types"
 
 	"github.com/spf13/cobra"
@@ -19,6 +20,7 @@ func (c *ServeCommand) Command() *cobra.Command {
 	opts := serve.Options{
 		Logger: c.Logger,
 	}
+	introspectionSecret := &config.SecretValue{}
 	configOpts := config.ConfigOptions{
 		{
 			Name:        "port",
@@ -50,6 +52,7 @@ func (c *ServeCommand) Command() *cobra.Command {
 			OptType:   types.String,
 			ConfigKey: &opts.SigningKeys,
 			Required:  true,
+			Secret:    true,
 		},
 		{
 			Name:      "domain",
@@ -80,6 +83,7 @@ func (c *ServeCommand) Command() *cobra.Command {
 			OptType:   types.String,
 			ConfigKey: &opts.JWK,
 			Required:  true,
+			Secret:    true,
 		},
 		{
 			Name:      "jwt-issuer",
@@ -104,6 +108,22 @@ func (c *ServeCommand) Command() *cobra.Command {
 			ConfigKey:   &opts.AllowAccountsThatDoNotExist,
 			FlagDefault: false,
 		},
+		{
+			Name:      "db-url",
+			Usage:     "Database URL of the store of revoked tokens (revocations are held in memory if not set)",
+			OptType:   types.String,
+			ConfigKey: &opts.DatabaseURL,
+			Secret:    true,
+		},
+		{
+			Name:      "introspection-secret",
+			Usage:     "Secret resource servers authenticate with as a bearer token to introspect tokens (the introspection endpoint is disabled if not set)",
+			OptType:   types.String,
+			ConfigKey: &opts.IntrospectionSecret,
+			Secret:    true,
+			// The introspection secret is rotated without restarting.
+			OnSecretChange: introspectionSecret.Set,
+		},
 	}
 	cmd := &cobra.Command{
 		Use:   "serve",
@@ -111,6 +131,11 @@ func (c *ServeCommand) Command() *cobra.Command {
 		Run: func(_ *cobra.Command, _ []string) {
 			configOpts.Require()
 			configOpts.SetValues()
+			introspectionSecret.Set(opts.IntrospectionSecret)
+			opts.IntrospectionSecretValue = introspectionSecret
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			configOpts.WatchSecrets(ctx)
 			c.Run(opts)
 		},
 	}
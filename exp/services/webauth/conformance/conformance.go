@@ -0,0 +1,708 @@
+// Package conformance is a conformance suite for SEP-10 Web Authentication
+// servers. It exercises a deployed server against the edge cases of the
+// protocol, e.g. challenges with invalid signatures, expired timebounds or a
+// wrong home domain, so that third parties can verify their deployments.
+//
+// The suite can be run from a Go test with Test, or programmatically with
+// Run:
+//
+//	func TestWebAuth(t *testing.T) {
+//		conformance.Test(t, conformance.Config{
+//			Endpoint:          "https://webauth.example.com/auth",
+//			ServerAccountID:   "GA...",
+//			NetworkPassphrase: network.TestNetworkPassphrase,
+//			WebAuthDomain:     "webauth.example.com",
+//			HomeDomain:        "example.com",
+//		})
+//	}
+//
+// SEP-10: https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0010.md
+package conformance
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// Config configures the conformance suite.
+type Config struct {
+	// Endpoint is the URL of the server, the WEB_AUTH_ENDPOINT of the
+	// stellar.toml of the home domain.
+	Endpoint string
+	// ServerAccountID is the account the server signs challenges with, the
+	// SIGNING_KEY of the stellar.toml of the home domain.
+	ServerAccountID string
+	// NetworkPassphrase is the passphrase of the network challenges are
+	// signed for.
+	NetworkPassphrase string
+	// WebAuthDomain is the domain of the server, expected in the
+	// web_auth_domain operation of challenges.
+	WebAuthDomain string
+	// HomeDomain is a home domain supported by the server.
+	HomeDomain string
+
+	// ClientKey is the master key of the client account authenticating
+	// with the server, which must be an account existing on the network or
+	// an account the server allows although it does not exist. If nil, a
+	// random account which does not exist is used.
+	ClientKey *keypair.Full
+	// ServerSigningKey is the secret key of the server account. It allows
+	// the suite to forge challenges that the server would not issue, like
+	// expired challenges. The cases requiring it are skipped if it is nil, so
+	// it should only be set for test deployments.
+	ServerSigningKey *keypair.Full
+
+	// HTTP is the client used to send requests to the server. If nil,
+	// http.DefaultClient is used.
+	HTTP *http.Client
+}
+
+// Case is a conformance test case.
+type Case struct {
+	Name        string
+	Description string
+	// RequiresServerSigningKey is true if the case forges challenges, and
+	// is skipped if Config.ServerSigningKey is not set.
+	RequiresServerSigningKey bool
+	Run                      func(ctx context.Context, s *Suite) error
+}
+
+// Result is the result of a conformance test case.
+type Result struct {
+	Name    string
+	Skipped bool
+	// Err is the reason the case failed, nil if it passed.
+	Err error
+}
+
+// Passed returns true if the case passed or was skipped.
+func (r Result) Passed() bool {
+	return r.Err == nil
+}
+
+// Suite runs the conformance test cases against a server.
+type Suite struct {
+	config Config
+}
+
+// NewSuite returns a suite testing the server described by config.
+func NewSuite(config Config) (*Suite, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("endpoint is required")
+	}
+	if !strkey.IsValidEd25519PublicKey(config.ServerAccountID) {
+		return nil, errors.Errorf("server account %q is not a valid account id", config.ServerAccountID)
+	}
+	if config.NetworkPassphrase == "" {
+		return nil, errors.New("network passphrase is required")
+	}
+	if config.HomeDomain == "" {
+		return nil, errors.New("home domain is required")
+	}
+	if config.ServerSigningKey != nil && config.ServerSigningKey.Address() != config.ServerAccountID {
+		return nil, errors.New("server signing key does not match server account")
+	}
+	if config.HTTP == nil {
+		config.HTTP = http.DefaultClient
+	}
+	return &Suite{config: config}, nil
+}
+
+// Run runs all the cases against the server, in the order of Cases.
+func (s *Suite) Run(ctx context.Context) []Result {
+	results := make([]Result, 0, len(Cases))
+	for _, c := range Cases {
+		results = append(results, s.RunCase(ctx, c))
+	}
+	return results
+}
+
+// RunCase runs a case against the server.
+func (s *Suite) RunCase(ctx context.Context, c Case) Result {
+	if c.RequiresServerSigningKey && s.config.ServerSigningKey == nil {
+		return Result{Name: c.Name, Skipped: true}
+	}
+	return Result{Name: c.Name, Err: c.Run(ctx, s)}
+}
+
+// Run runs all the cases against the server described by config.
+func Run(ctx context.Context, config Config) ([]Result, error) {
+	s, err := NewSuite(config)
+	if err != nil {
+		return nil, err
+	}
+	return s.Run(ctx), nil
+}
+
+// Test runs all the cases against the server described by config as
+// subtests of t.
+func Test(t *testing.T, config Config) {
+	s, err := NewSuite(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range Cases {
+		c := c
+		t.Run(c.Name, func(t *testing.T) {
+			result := s.RunCase(context.Background(), c)
+			if result.Skipped {
+				t.Skip("requires the server signing key")
+			}
+			if result.Err != nil {
+				t.Fatalf("%s: %v", c.Description, result.Err)
+			}
+		})
+	}
+}
+
+// Cases are the conformance test cases.
+var Cases = []Case{
+	{
+		Name:        "challenge",
+		Description: "the server issues a valid challenge for the client account",
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			tx, err := s.challenge(ctx, url.Values{"account": {client.Address()}})
+			if err != nil {
+				return err
+			}
+			_, clientAccountID, homeDomain, err := txnbuild.ReadChallengeTx(
+				tx, s.config.ServerAccountID, s.config.NetworkPassphrase, s.config.WebAuthDomain, []string{s.config.HomeDomain},
+			)
+			if err != nil {
+				return errors.Wrap(err, "invalid challenge")
+			}
+			if clientAccountID != client.Address() {
+				return errors.Errorf("challenge is for account %s instead of %s", clientAccountID, client.Address())
+			}
+			if homeDomain != s.config.HomeDomain {
+				return errors.Errorf("challenge is for home domain %s instead of %s", homeDomain, s.config.HomeDomain)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "challenge_home_domain",
+		Description: "the server issues a challenge for the requested home domain",
+		Run: func(ctx context.Context, s *Suite) error {
+			tx, err := s.challenge(ctx, url.Values{
+				"account":     {s.clientKey().Address()},
+				"home_domain": {s.config.HomeDomain},
+			})
+			if err != nil {
+				return err
+			}
+			_, _, _, err = txnbuild.ReadChallengeTx(
+				tx, s.config.ServerAccountID, s.config.NetworkPassphrase, s.config.WebAuthDomain, []string{s.config.HomeDomain},
+			)
+			return errors.Wrap(err, "invalid challenge")
+		},
+	},
+	{
+		Name:        "challenge_invalid_account",
+		Description: "the server rejects challenge requests for an invalid account",
+		Run: func(ctx context.Context, s *Suite) error {
+			return s.expectChallengeRejected(ctx, url.Values{"account": {"GINVALID"}})
+		},
+	},
+	{
+		Name:        "challenge_muxed_account",
+		Description: "the server rejects challenge requests for a muxed account, or issues a challenge for the muxed account",
+		Run: func(ctx context.Context, s *Suite) error {
+			account := muxedAddress(s.clientKey(), 1)
+			status, body, err := s.getChallenge(ctx, url.Values{"account": {account}})
+			if err != nil {
+				return err
+			}
+			if status == http.StatusBadRequest {
+				return nil
+			}
+			tx, err := s.decodeChallenge(status, body)
+			if err != nil {
+				return err
+			}
+			_, clientAccountID, _, err := txnbuild.ReadChallengeTx(
+				tx, s.config.ServerAccountID, s.config.NetworkPassphrase, s.config.WebAuthDomain, []string{s.config.HomeDomain},
+			)
+			if err != nil {
+				return errors.Wrap(err, "invalid challenge")
+			}
+			if clientAccountID != account {
+				return errors.Errorf("challenge is for account %s instead of %s", clientAccountID, account)
+			}
+			return nil
+		},
+	},
+	{
+		Name:        "challenge_wrong_home_domain",
+		Description: "the server rejects challenge requests for a home domain it does not support",
+		Run: func(ctx context.Context, s *Suite) error {
+			return s.expectChallengeRejected(ctx, url.Values{
+				"account":     {s.clientKey().Address()},
+				"home_domain": {"unsupported." + s.config.HomeDomain},
+			})
+		},
+	},
+	{
+		Name:        "token",
+		Description: "the server issues a token for a challenge signed by the client",
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			tx, err := s.challenge(ctx, url.Values{"account": {client.Address()}})
+			if err != nil {
+				return err
+			}
+			tx, err = s.sign(tx, s.config.NetworkPassphrase, client)
+			if err != nil {
+				return err
+			}
+			status, body, err := s.token(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if status != http.StatusOK {
+				return errors.Errorf("expected status %d, got %d: %s", http.StatusOK, status, body)
+			}
+			return checkToken(body, client.Address())
+		},
+	},
+	{
+		Name:        "token_memo",
+		Description: "the server rejects challenge requests with a memo, or issues a token whose subject is the account and the memo",
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			status, body, err := s.getChallenge(ctx, url.Values{"account": {client.Address()}, "memo": {"1234"}})
+			if err != nil {
+				return err
+			}
+			if status == http.StatusBadRequest {
+				return nil
+			}
+			tx, err := s.decodeChallenge(status, body)
+			if err != nil {
+				return err
+			}
+			tx, err = s.sign(tx, s.config.NetworkPassphrase, client)
+			if err != nil {
+				return err
+			}
+			status, body, err = s.token(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if status != http.StatusOK {
+				return errors.Errorf("expected status %d, got %d: %s", http.StatusOK, status, body)
+			}
+			return checkToken(body, client.Address()+":1234")
+		},
+	},
+	{
+		Name:        "token_unsigned",
+		Description: "the server rejects challenges not signed by the client",
+		Run: func(ctx context.Context, s *Suite) error {
+			tx, err := s.challenge(ctx, url.Values{"account": {s.clientKey().Address()}})
+			if err != nil {
+				return err
+			}
+			return s.expectTokenRejected(ctx, tx)
+		},
+	},
+	{
+		Name:        "token_bad_signature",
+		Description: "the server rejects challenges signed by a key which is not a signer of the client account",
+		Run: func(ctx context.Context, s *Suite) error {
+			tx, err := s.challenge(ctx, url.Values{"account": {s.clientKey().Address()}})
+			if err != nil {
+				return err
+			}
+			tx, err = s.sign(tx, s.config.NetworkPassphrase, keypair.MustRandom())
+			if err != nil {
+				return err
+			}
+			return s.expectTokenRejected(ctx, tx)
+		},
+	},
+	{
+		Name:        "token_wrong_network",
+		Description: "the server rejects challenges signed by the client for another network",
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			tx, err := s.challenge(ctx, url.Values{"account": {client.Address()}})
+			if err != nil {
+				return err
+			}
+			tx, err = s.sign(tx, s.config.NetworkPassphrase+" (other network)", client)
+			if err != nil {
+				return err
+			}
+			return s.expectTokenRejected(ctx, tx)
+		},
+	},
+	{
+		Name:        "token_tampered",
+		Description: "the server rejects challenges modified after it signed them",
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			tx, err := s.challenge(ctx, url.Values{"account": {client.Address()}})
+			if err != nil {
+				return err
+			}
+			tx, err = tamper(tx)
+			if err != nil {
+				return err
+			}
+			tx, err = s.sign(tx, s.config.NetworkPassphrase, client)
+			if err != nil {
+				return err
+			}
+			return s.expectTokenRejected(ctx, tx)
+		},
+	},
+	{
+		Name:                     "token_expired",
+		Description:              "the server rejects challenges whose timebounds expired",
+		RequiresServerSigningKey: true,
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			now := time.Now()
+			return s.expectForgedRejected(ctx, client, challengeParams{
+				clientAccountID: client.Address(),
+				homeDomain:      s.config.HomeDomain,
+				webAuthDomain:   s.config.WebAuthDomain,
+				minTime:         now.Add(-10 * time.Minute),
+				maxTime:         now.Add(-5 * time.Minute),
+			})
+		},
+	},
+	{
+		Name:                     "token_not_yet_valid",
+		Description:              "the server rejects challenges whose timebounds did not start yet",
+		RequiresServerSigningKey: true,
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			now := time.Now()
+			return s.expectForgedRejected(ctx, client, challengeParams{
+				clientAccountID: client.Address(),
+				homeDomain:      s.config.HomeDomain,
+				webAuthDomain:   s.config.WebAuthDomain,
+				minTime:         now.Add(5 * time.Minute),
+				maxTime:         now.Add(10 * time.Minute),
+			})
+		},
+	},
+	{
+		Name:                     "token_wrong_home_domain",
+		Description:              "the server rejects challenges for a home domain it does not support",
+		RequiresServerSigningKey: true,
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			now := time.Now()
+			return s.expectForgedRejected(ctx, client, challengeParams{
+				clientAccountID: client.Address(),
+				homeDomain:      "unsupported." + s.config.HomeDomain,
+				webAuthDomain:   s.config.WebAuthDomain,
+				minTime:         now,
+				maxTime:         now.Add(5 * time.Minute),
+			})
+		},
+	},
+	{
+		Name:                     "token_wrong_web_auth_domain",
+		Description:              "the server rejects challenges for another web auth domain",
+		RequiresServerSigningKey: true,
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			now := time.Now()
+			return s.expectForgedRejected(ctx, client, challengeParams{
+				clientAccountID: client.Address(),
+				homeDomain:      s.config.HomeDomain,
+				webAuthDomain:   "unsupported." + s.config.WebAuthDomain,
+				minTime:         now,
+				maxTime:         now.Add(5 * time.Minute),
+			})
+		},
+	},
+	{
+		Name:                     "token_muxed_account",
+		Description:              "the server rejects challenges for a muxed client account, or issues a token whose subject is the muxed account",
+		RequiresServerSigningKey: true,
+		Run: func(ctx context.Context, s *Suite) error {
+			client := s.clientKey()
+			account := muxedAddress(client, 1)
+			now := time.Now()
+			tx, err := s.forge(challengeParams{
+				clientAccountID: account,
+				homeDomain:      s.config.HomeDomain,
+				webAuthDomain:   s.config.WebAuthDomain,
+				minTime:         now,
+				maxTime:         now.Add(5 * time.Minute),
+			})
+			if err != nil {
+				return err
+			}
+			tx, err = s.sign(tx, s.config.NetworkPassphrase, client)
+			if err != nil {
+				return err
+			}
+			status, body, err := s.token(ctx, tx)
+			if err != nil {
+				return err
+			}
+			switch status {
+			case http.StatusBadRequest, http.StatusUnauthorized:
+				return nil
+			case http.StatusOK:
+				return checkToken(body, account)
+			default:
+				return errors.Errorf("expected status %d, %d or %d, got %d: %s",
+					http.StatusOK, http.StatusBadRequest, http.StatusUnauthorized, status, body)
+			}
+		},
+	},
+}
+
+func (s *Suite) clientKey() *keypair.Full {
+	if s.config.ClientKey != nil {
+		return s.config.ClientKey
+	}
+	return keypair.MustRandom()
+}
+
+func (s *Suite) do(ctx context.Context, req *http.Request) (int, []byte, error) {
+	resp, err := s.config.HTTP.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "request failed")
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "reading response failed")
+	}
+	return resp.StatusCode, body, nil
+}
+
+func (s *Suite) getChallenge(ctx context.Context, query url.Values) (int, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, s.config.Endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "building request failed")
+	}
+	return s.do(ctx, req)
+}
+
+// challenge requests a challenge and returns its transaction.
+func (s *Suite) challenge(ctx context.Context, query url.Values) (string, error) {
+	status, body, err := s.getChallenge(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	return s.decodeChallenge(status, body)
+}
+
+// decodeChallenge returns the transaction of a challenge response.
+func (s *Suite) decodeChallenge(status int, body []byte) (string, error) {
+	if status != http.StatusOK {
+		return "", errors.Errorf("expected status %d for challenge, got %d: %s", http.StatusOK, status, body)
+	}
+	var resp struct {
+		Transaction       string `json:"transaction"`
+		NetworkPassphrase string `json:"network_passphrase"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", errors.Wrap(err, "decoding challenge response failed")
+	}
+	if resp.NetworkPassphrase != "" && resp.NetworkPassphrase != s.config.NetworkPassphrase {
+		return "", errors.Errorf("challenge is for network %q instead of %q", resp.NetworkPassphrase, s.config.NetworkPassphrase)
+	}
+	return resp.Transaction, nil
+}
+
+func (s *Suite) expectChallengeRejected(ctx context.Context, query url.Values) error {
+	status, body, err := s.getChallenge(ctx, query)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusBadRequest {
+		return errors.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, status, body)
+	}
+	return nil
+}
+
+// token posts the challenge tx and returns the response.
+func (s *Suite) token(ctx context.Context, tx string) (int, []byte, error) {
+	reqBody, err := json.Marshal(map[string]string{"transaction": tx})
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.config.Endpoint, strings.NewReader(string(reqBody)))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "building request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return s.do(ctx, req)
+}
+
+// expectTokenRejected posts the challenge tx and checks that the server
+// rejects it. SEP-10 does not distinguish invalid challenges from
+// challenges with insufficient signatures, so 400 and 401 are accepted.
+func (s *Suite) expectTokenRejected(ctx context.Context, tx string) error {
+	status, body, err := s.token(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusBadRequest && status != http.StatusUnauthorized {
+		return errors.Errorf("expected status %d or %d, got %d: %s",
+			http.StatusBadRequest, http.StatusUnauthorized, status, body)
+	}
+	return nil
+}
+
+func (s *Suite) expectForgedRejected(ctx context.Context, client *keypair.Full, params challengeParams) error {
+	tx, err := s.forge(params)
+	if err != nil {
+		return err
+	}
+	tx, err = s.sign(tx, s.config.NetworkPassphrase, client)
+	if err != nil {
+		return err
+	}
+	return s.expectTokenRejected(ctx, tx)
+}
+
+// sign adds the signature of signer for the network with the given
+// passphrase to the challenge tx.
+func (s *Suite) sign(tx, passphrase string, signer *keypair.Full) (string, error) {
+	// Muxed accounts are enabled so that the accounts of the challenge are
+	// kept as is.
+	parsed, err := txnbuild.TransactionFromXDR(tx, txnbuild.TransactionFromXDROptionEnableMuxedAccounts)
+	if err != nil {
+		return "", errors.Wrap(err, "decoding challenge failed")
+	}
+	challenge, ok := parsed.Transaction()
+	if !ok {
+		return "", errors.New("challenge is a fee bump transaction")
+	}
+	challenge, err = challenge.Sign(passphrase, signer)
+	if err != nil {
+		return "", errors.Wrap(err, "signing challenge failed")
+	}
+	return challenge.Base64()
+}
+
+type challengeParams struct {
+	clientAccountID  string
+	homeDomain       string
+	webAuthDomain    string
+	minTime, maxTime time.Time
+}
+
+// forge builds a challenge signed with the server signing key, which the
+// server would not issue.
+func (s *Suite) forge(params challengeParams) (string, error) {
+	nonce := make([]byte, 48)
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &txnbuild.SimpleAccount{AccountID: s.config.ServerAccountID},
+		Operations: []txnbuild.Operation{
+			&txnbuild.ManageData{
+				SourceAccount: params.clientAccountID,
+				Name:          params.homeDomain + " auth",
+				Value:         []byte(base64.StdEncoding.EncodeToString(nonce)),
+			},
+			&txnbuild.ManageData{
+				SourceAccount: s.config.ServerAccountID,
+				Name:          "web_auth_domain",
+				Value:         []byte(params.webAuthDomain),
+			},
+		},
+		BaseFee:             txnbuild.MinBaseFee,
+		Timebounds:          txnbuild.NewTimebounds(params.minTime.Unix(), params.maxTime.Unix()),
+		EnableMuxedAccounts: true,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "building challenge failed")
+	}
+	tx, err = tx.Sign(s.config.NetworkPassphrase, s.config.ServerSigningKey)
+	if err != nil {
+		return "", errors.Wrap(err, "signing challenge failed")
+	}
+	return tx.Base64()
+}
+
+// tamper replaces the nonce of the challenge tx, keeping the signatures of
+// the original challenge.
+func tamper(tx string) (string, error) {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(tx, &envelope); err != nil {
+		return "", errors.Wrap(err, "decoding challenge failed")
+	}
+	operations := envelope.Operations()
+	if len(operations) == 0 || operations[0].Body.ManageDataOp == nil || operations[0].Body.ManageDataOp.DataValue == nil {
+		return "", errors.New("challenge has no manage data operation")
+	}
+	value := *operations[0].Body.ManageDataOp.DataValue
+	tampered := make(xdr.DataValue, len(value))
+	copy(tampered, value)
+	if tampered[0] == 'A' {
+		tampered[0] = 'B'
+	} else {
+		tampered[0] = 'A'
+	}
+	operations[0].Body.ManageDataOp.DataValue = &tampered
+	return xdr.MarshalBase64(envelope)
+}
+
+// muxedAddress returns the muxed account (M...) with the given id of the
+// account of kp.
+func muxedAddress(kp *keypair.Full, id uint64) string {
+	accountID := xdr.MustAddress(kp.Address())
+	muxed := xdr.MuxedAccount{
+		Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519,
+		Med25519: &xdr.MuxedAccountMed25519{
+			Id:      xdr.Uint64(id),
+			Ed25519: *accountID.Ed25519,
+		},
+	}
+	return muxed.Address()
+}
+
+// checkToken checks that the token response body contains a JWT whose
+// subject is account.
+func checkToken(body []byte, account string) error {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return errors.Wrap(err, "decoding token response failed")
+	}
+	token, err := jwt.ParseSigned(resp.Token)
+	if err != nil {
+		return errors.Wrap(err, "parsing token failed")
+	}
+	var claims jwt.Claims
+	if err = token.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		return errors.Wrap(err, "decoding token claims failed")
+	}
+	if claims.Subject != account {
+		return errors.Errorf("token subject is %q instead of %q", claims.Subject, account)
+	}
+	if claims.Expiry == nil || claims.Expiry.Time().Before(time.Now()) {
+		return errors.New("token expiry is not in the future")
+	}
+	return nil
+}
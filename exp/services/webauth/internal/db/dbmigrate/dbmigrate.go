@@ -0,0 +1,46 @@
+package dbmigrate
+
+import (
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+var migrationSource = &migrate.MemoryMigrationSource{
+	Migrations: []*migrate.Migration{
+		{
+			Id: "2021-12-01.0.revoked-tokens.sql",
+			Up: []string{
+				`CREATE TABLE revoked_tokens (
+					id TEXT PRIMARY KEY,
+					expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+					revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+				)`,
+				`CREATE INDEX revoked_tokens_expires_at_idx ON revoked_tokens (expires_at)`,
+			},
+			Down: []string{
+				`DROP TABLE revoked_tokens`,
+			},
+		},
+	},
+}
+
+// PlanMigration finds the migrations that would be applied if Migrate was to
+// be run now.
+func PlanMigration(db *sqlx.DB, dir migrate.MigrationDirection, count int) ([]string, error) {
+	migrations, _, err := migrate.PlanMigration(db.DB, db.DriverName(), migrationSource, dir, count)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		ids = append(ids, m.Id)
+	}
+	return ids, nil
+}
+
+// Migrate runs all the migrations to get the database to the state described
+// by the migrations in the direction specified. Count is the maximum number
+// of migrations to apply or rollback.
+func Migrate(db *sqlx.DB, dir migrate.MigrationDirection, count int) (int, error) {
+	return migrate.ExecMax(db.DB, db.DriverName(), migrationSource, dir, count)
+}
@@ -2,14 +2,15 @@ package serve
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/stellar/go/keypair"
-	"github.com/stellar/go/strkey"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/httpjson"
 	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
 )
 
 // ChallengeHandler implements the SEP-10 challenge endpoint and handles
@@ -21,6 +22,7 @@ type challengeHandler struct {
 	ChallengeExpiresIn time.Duration
 	Domain             string
 	HomeDomains        []string
+	ClientDomains      *clientDomainSigningKeys
 }
 
 type challengeResponse struct {
@@ -32,11 +34,24 @@ func (h challengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	queryValues := r.URL.Query()
 
+	// The account can be a muxed account (M...), or be given with an ID
+	// memo, to authenticate a user of a custodial account.
 	account := queryValues.Get("account")
-	if !strkey.IsValidEd25519PublicKey(account) {
+	muxedAccount, err := xdr.AddressToMuxedAccount(account)
+	if err != nil {
 		badRequest.Render(w)
 		return
 	}
+	var memo *txnbuild.MemoID
+	if memoStr := queryValues.Get("memo"); memoStr != "" {
+		id, err := strconv.ParseUint(memoStr, 10, 64)
+		if err != nil || muxedAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+			badRequest.Render(w)
+			return
+		}
+		memoID := txnbuild.MemoID(id)
+		memo = &memoID
+	}
 
 	homeDomain := queryValues.Get("home_domain")
 	if homeDomain != "" {
@@ -57,14 +72,60 @@ func (h challengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		homeDomain = h.HomeDomains[0]
 	}
 
-	tx, err := txnbuild.BuildChallengeTx(
-		h.SigningKey.Seed(),
-		account,
-		h.Domain,
-		homeDomain,
-		h.NetworkPassphrase,
-		h.ChallengeExpiresIn,
-	)
+	var tx *txnbuild.Transaction
+	clientDomain := queryValues.Get("client_domain")
+	if clientDomain != "" && memo != nil {
+		// Challenges can only have either a client domain or a memo.
+		badRequest.Render(w)
+		return
+	}
+	if clientDomain != "" {
+		// The challenge must be signed by the SIGNING_KEY of the client
+		// domain's stellar.toml to attribute the client to the domain.
+		clientDomain = strings.TrimSuffix(clientDomain, ".")
+		var clientDomainAccountID string
+		clientDomainAccountID, err = h.ClientDomains.SigningKey(clientDomain)
+		if err != nil {
+			h.Logger.Ctx(ctx).
+				WithField("clientdomain", clientDomain).
+				Infof("Failed to get the signing key of the client domain: %v", err)
+			if err == errClientDomainLookupsLimited {
+				tooManyRequests.Render(w)
+			} else {
+				badRequest.Render(w)
+			}
+			return
+		}
+		tx, err = txnbuild.BuildChallengeTxWithClientDomain(
+			h.SigningKey.Seed(),
+			account,
+			h.Domain,
+			homeDomain,
+			clientDomain,
+			clientDomainAccountID,
+			h.NetworkPassphrase,
+			h.ChallengeExpiresIn,
+		)
+	} else if memo != nil {
+		tx, err = txnbuild.BuildChallengeTxWithMemo(
+			h.SigningKey.Seed(),
+			account,
+			h.Domain,
+			homeDomain,
+			h.NetworkPassphrase,
+			h.ChallengeExpiresIn,
+			*memo,
+		)
+	} else {
+		tx, err = txnbuild.BuildChallengeTx(
+			h.SigningKey.Seed(),
+			account,
+			h.Domain,
+			homeDomain,
+			h.NetworkPassphrase,
+			h.ChallengeExpiresIn,
+		)
+	}
 	if err != nil {
 		h.Logger.Ctx(ctx).WithStack(err).Error(err)
 		serverError.Render(w)
@@ -81,8 +142,10 @@ func (h challengeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	l := h.Logger.Ctx(ctx).
 		WithField("tx", hash).
 		WithField("account", account).
+		WithField("memo", queryValues.Get("memo")).
 		WithField("serversigner", h.SigningKey.Address()).
-		WithField("homedomain", homeDomain)
+		WithField("homedomain", homeDomain).
+		WithField("clientdomain", clientDomain)
 
 	l.Info("Generated challenge transaction for account.")
 
@@ -9,9 +9,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stellar/go/clients/stellartoml"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/errors"
 	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -185,3 +188,149 @@ func TestChallenge_invalidHomeDomain(t *testing.T) {
 	require.NoError(t, err)
 	assert.JSONEq(t, `{"error":"The request was invalid in some way."}`, string(body))
 }
+
+func TestChallenge_clientDomain(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	account := keypair.MustRandom()
+	clientDomainKey := keypair.MustRandom()
+
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: clientDomainKey.Address()}, nil)
+	clientDomains, err := newClientDomainSigningKeys(stellarTOML, defaultClientDomainLookupQuota)
+	require.NoError(t, err)
+
+	h := challengeHandler{
+		Logger:             supportlog.DefaultLogger,
+		NetworkPassphrase:  network.TestNetworkPassphrase,
+		SigningKey:         serverKey,
+		ChallengeExpiresIn: time.Minute,
+		Domain:             "webauthdomain",
+		HomeDomains:        []string{"testdomain"},
+		ClientDomains:      clientDomains,
+	}
+
+	r := httptest.NewRequest("GET", fmt.Sprintf("/?account=%s&client_domain=wallet.example.com", account.Address()), nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	res := struct {
+		Transaction string `json:"transaction"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+
+	var tx xdr.TransactionEnvelope
+	err = xdr.SafeUnmarshalBase64(res.Transaction, &tx)
+	require.NoError(t, err)
+
+	assert.Len(t, tx.Operations(), 3)
+	op2SourceAccount := tx.Operations()[2].SourceAccount.ToAccountId()
+	assert.Equal(t, clientDomainKey.Address(), op2SourceAccount.Address())
+	assert.Equal(t, xdr.OperationTypeManageData, tx.Operations()[2].Body.Type)
+	assert.Equal(t, "client_domain", string(tx.Operations()[2].Body.ManageDataOp.DataName))
+	assert.Equal(t, "wallet.example.com", string(*tx.Operations()[2].Body.ManageDataOp.DataValue))
+	stellarTOML.AssertExpectations(t)
+}
+
+func TestChallenge_clientDomainWithoutSigningKey(t *testing.T) {
+	account := keypair.MustRandom()
+
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{}, nil)
+	stellarTOML.
+		On("GetStellarToml", "unreachable.example.com").
+		Return((*stellartoml.Response)(nil), errors.New("http request errored"))
+	clientDomains, err := newClientDomainSigningKeys(stellarTOML, defaultClientDomainLookupQuota)
+	require.NoError(t, err)
+
+	h := challengeHandler{
+		Logger:        supportlog.DefaultLogger,
+		SigningKey:    keypair.MustRandom(),
+		HomeDomains:   []string{"testdomain"},
+		ClientDomains: clientDomains,
+	}
+
+	domains := []string{
+		"wallet.example.com",
+		"unreachable.example.com",
+		"169.254.169.254",
+		"localhost",
+		"wallet.example.com:8080",
+		"wallet.example.com%2Fpath",
+	}
+	for _, clientDomain := range domains {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?account=%s&client_domain=%s", account.Address(), clientDomain), nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		resp := w.Result()
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"error":"The request was invalid in some way."}`, string(body))
+	}
+}
+
+func TestChallenge_muxedAccountAndMemo(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	account := keypair.MustRandom()
+	muxedAccount := xdr.MuxedAccount{
+		Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519,
+		Med25519: &xdr.MuxedAccountMed25519{
+			Id:      1234,
+			Ed25519: *xdr.MustAddress(account.Address()).Ed25519,
+		},
+	}
+
+	h := challengeHandler{
+		Logger:             supportlog.DefaultLogger,
+		NetworkPassphrase:  network.TestNetworkPassphrase,
+		SigningKey:         serverKey,
+		ChallengeExpiresIn: time.Minute,
+		Domain:             "webauthdomain",
+		HomeDomains:        []string{"testdomain"},
+	}
+
+	challenge := func(query string) (int, *txnbuild.Transaction, string) {
+		r := httptest.NewRequest("GET", "/?"+query, nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		resp := w.Result()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil, ""
+		}
+		res := struct {
+			Transaction string `json:"transaction"`
+		}{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		tx, clientAccountID, _, err := txnbuild.ReadChallengeTx(res.Transaction, serverKey.Address(), network.TestNetworkPassphrase, "webauthdomain", []string{"testdomain"})
+		require.NoError(t, err)
+		return resp.StatusCode, tx, clientAccountID
+	}
+
+	status, tx, clientAccountID := challenge("account=" + muxedAccount.Address())
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, muxedAccount.Address(), clientAccountID)
+	assert.Nil(t, txnbuild.ChallengeTxMemo(tx))
+
+	status, tx, _ = challenge("account=" + account.Address() + "&memo=1234")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, txnbuild.MemoID(1234), *txnbuild.ChallengeTxMemo(tx))
+
+	for _, query := range []string{
+		"account=" + account.Address() + "&memo=text",
+		"account=" + account.Address() + "&memo=-1",
+		"account=" + muxedAccount.Address() + "&memo=1234",
+		"account=" + account.Address() + "&memo=1234&client_domain=wallet.example.com",
+	} {
+		status, _, _ = challenge(query)
+		assert.Equal(t, http.StatusBadRequest, status, query)
+	}
+}
@@ -0,0 +1,154 @@
+package serve
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/throttled"
+)
+
+const (
+	// clientDomainCacheSize is the number of client domains whose signing
+	// key is cached.
+	clientDomainCacheSize = 1000
+	// clientDomainCacheTTL is how long the signing key of a client domain is
+	// cached.
+	clientDomainCacheTTL = 5 * time.Minute
+	// clientDomainFailureCacheTTL is how long a failure to get the signing
+	// key of a client domain is cached, so that requests for a domain that
+	// is down do not all wait for its stellar.toml.
+	clientDomainFailureCacheTTL = time.Minute
+)
+
+// defaultClientDomainLookupQuota is the rate of the lookups of the
+// stellar.toml of client domains that are not cached.
+var defaultClientDomainLookupQuota = throttled.RateQuota{
+	MaxRate:  throttled.PerSec(10),
+	MaxBurst: 20,
+}
+
+// errClientDomainLookupsLimited is returned by the SigningKey of
+// clientDomainSigningKeys when the rate of lookups is exceeded.
+var errClientDomainLookupsLimited = errors.New("too many client domain lookups")
+
+// clientDomainSigningKeys returns the SIGNING_KEY of the stellar.toml of the
+// client domains of challenges. Client domains are requested by anyone, so
+// only DNS hostnames are looked up, the results are cached, and the lookups
+// of domains that are not cached are rate limited.
+type clientDomainSigningKeys struct {
+	StellarTOML stellartoml.ClientInterface
+
+	cache   *lru.Cache
+	limiter *throttled.GCRARateLimiter
+
+	// clock is a Clock returning the current time.
+	clock *clock.Clock
+}
+
+// clientDomainSigningKey is a result cached by clientDomainSigningKeys.
+type clientDomainSigningKey struct {
+	signingKey string
+	err        error
+	expires    time.Time
+}
+
+func newClientDomainSigningKeys(stellarTOML stellartoml.ClientInterface, quota throttled.RateQuota) (*clientDomainSigningKeys, error) {
+	cache, err := lru.New(clientDomainCacheSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating client domain cache")
+	}
+	limiter, err := throttled.NewGCRARateLimiter(1, quota)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating client domain rate limiter")
+	}
+	return &clientDomainSigningKeys{StellarTOML: stellarTOML, cache: cache, limiter: limiter}, nil
+}
+
+// SigningKey returns the SIGNING_KEY of the stellar.toml of clientDomain, which
+// must be a DNS hostname.
+func (k *clientDomainSigningKeys) SigningKey(clientDomain string) (string, error) {
+	if !isHostname(clientDomain) {
+		return "", errors.Errorf("client domain %q is not a hostname", clientDomain)
+	}
+	clientDomain = strings.ToLower(clientDomain)
+
+	now := k.clock.Now()
+	if value, ok := k.cache.Get(clientDomain); ok {
+		entry := value.(clientDomainSigningKey)
+		if now.Before(entry.expires) {
+			return entry.signingKey, entry.err
+		}
+	}
+
+	limited, _, err := k.limiter.RateLimit("", 1)
+	if err != nil {
+		return "", errors.Wrap(err, "rate limiting client domain lookups")
+	}
+	if limited {
+		return "", errClientDomainLookupsLimited
+	}
+
+	entry := clientDomainSigningKey{expires: now.Add(clientDomainCacheTTL)}
+	entry.signingKey, entry.err = k.lookup(clientDomain)
+	if entry.err != nil {
+		entry.expires = now.Add(clientDomainFailureCacheTTL)
+	}
+	k.cache.Add(clientDomain, entry)
+	return entry.signingKey, entry.err
+}
+
+func (k *clientDomainSigningKeys) lookup(clientDomain string) (string, error) {
+	resp, err := k.StellarTOML.GetStellarToml(clientDomain)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching stellar.toml")
+	}
+	if !strkey.IsValidEd25519PublicKey(resp.SigningKey) {
+		return "", errors.Errorf("stellar.toml SIGNING_KEY %q is not a valid account id", resp.SigningKey)
+	}
+	return resp.SigningKey, nil
+}
+
+// isHostname returns true if s is a fully qualified DNS hostname, without a
+// port, a path or a trailing dot, and is not an IP address.
+func isHostname(s string) bool {
+	if len(s) > 253 || net.ParseIP(s) != nil {
+		return false
+	}
+	labels := strings.Split(s, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	// Top-level domains are not numeric, which rules out the shorthand
+	// forms of IPv4 addresses, e.g. 127.1.
+	tld := labels[len(labels)-1]
+	return strings.Trim(tld, "0123456789") != ""
+}
+
+// checkClientDomainRedirect only lets the requests for the stellar.toml of
+// client domains be redirected to other DNS hostnames over HTTPS.
+func checkClientDomainRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if req.URL.Scheme != "https" || req.URL.Port() != "" || !isHostname(req.URL.Hostname()) {
+		return errors.Errorf("redirect to %s is not allowed", req.URL.Redacted())
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package serve
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/clock/clocktest"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/throttled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsHostname(t *testing.T) {
+	for _, s := range []string{
+		"example.com",
+		"wallet.Example.com",
+		"my-wallet.example.co.uk",
+		"1password.com",
+	} {
+		assert.True(t, isHostname(s), s)
+	}
+	for _, s := range []string{
+		"",
+		"localhost",
+		"example.com.",
+		".example.com",
+		"example.com:443",
+		"example.com/path",
+		"example.com?query",
+		"user@example.com",
+		"-wallet.example.com",
+		"wallet_.example.com",
+		"127.0.0.1",
+		"127.1",
+		"[::1]",
+		"::1",
+		"0x7f.1",
+	} {
+		assert.False(t, isHostname(s), s)
+	}
+}
+
+func TestClientDomainSigningKeys_cache(t *testing.T) {
+	signingKey := keypair.MustRandom().Address()
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet.example.com").
+		Return(&stellartoml.Response{SigningKey: signingKey}, nil).
+		Twice()
+	stellarTOML.
+		On("GetStellarToml", "unreachable.example.com").
+		Return((*stellartoml.Response)(nil), errors.New("http request errored")).
+		Twice()
+
+	k, err := newClientDomainSigningKeys(stellarTOML, defaultClientDomainLookupQuota)
+	require.NoError(t, err)
+	now := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+	k.clock = &clock.Clock{Source: clocktest.FixedSource(now)}
+
+	for i := 0; i < 2; i++ {
+		key, err := k.SigningKey("Wallet.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, signingKey, key)
+		_, err = k.SigningKey("unreachable.example.com")
+		assert.EqualError(t, err, "fetching stellar.toml: http request errored")
+	}
+
+	// Failures are looked up again sooner than signing keys.
+	k.clock = &clock.Clock{Source: clocktest.FixedSource(now.Add(clientDomainFailureCacheTTL))}
+	_, err = k.SigningKey("unreachable.example.com")
+	assert.EqualError(t, err, "fetching stellar.toml: http request errored")
+	_, err = k.SigningKey("wallet.example.com")
+	require.NoError(t, err)
+
+	k.clock = &clock.Clock{Source: clocktest.FixedSource(now.Add(clientDomainCacheTTL))}
+	_, err = k.SigningKey("wallet.example.com")
+	require.NoError(t, err)
+
+	stellarTOML.AssertExpectations(t)
+}
+
+func TestClientDomainSigningKeys_rateLimit(t *testing.T) {
+	stellarTOML := &stellartoml.MockClient{}
+	stellarTOML.
+		On("GetStellarToml", "wallet1.example.com").
+		Return(&stellartoml.Response{SigningKey: keypair.MustRandom().Address()}, nil).
+		Once()
+
+	k, err := newClientDomainSigningKeys(stellarTOML, throttled.RateQuota{MaxRate: throttled.PerHour(1)})
+	require.NoError(t, err)
+
+	_, err = k.SigningKey("wallet1.example.com")
+	require.NoError(t, err)
+	_, err = k.SigningKey("wallet2.example.com")
+	assert.Equal(t, errClientDomainLookupsLimited, err)
+	// Cached signing keys are not limited.
+	_, err = k.SigningKey("wallet1.example.com")
+	require.NoError(t, err)
+
+	stellarTOML.AssertExpectations(t)
+}
+
+func TestCheckClientDomainRedirect(t *testing.T) {
+	redirect := func(rawurl string) error {
+		u, err := url.Parse(rawurl)
+		require.NoError(t, err)
+		return checkClientDomainRedirect(&http.Request{URL: u}, []*http.Request{{}})
+	}
+	assert.NoError(t, redirect("https://www.example.com/.well-known/stellar.toml"))
+	assert.EqualError(t, redirect("http://www.example.com/.well-known/stellar.toml"), "redirect to http://www.example.com/.well-known/stellar.toml is not allowed")
+	assert.Error(t, redirect("https://www.example.com:8443/.well-known/stellar.toml"))
+	assert.Error(t, redirect("https://169.254.169.254/latest/meta-data"))
+	assert.Error(t, redirect("https://localhost/.well-known/stellar.toml"))
+}
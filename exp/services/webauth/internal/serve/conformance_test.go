@@ -0,0 +1,112 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/exp/services/webauth/conformance"
+	"github.com/stellar/go/exp/support/jwtkey"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func newConformanceServer(t *testing.T, serverKey *keypair.Full) *httptest.Server {
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type": "https://stellar.org/horizon-errors/not_found", "status": 404}`))
+	}))
+	t.Cleanup(horizon.Close)
+
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk, err := json.Marshal(jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)})
+	require.NoError(t, err)
+
+	h, err := handler(Options{
+		Logger:                      supportlog.DefaultLogger,
+		HorizonURL:                  horizon.URL,
+		NetworkPassphrase:           network.TestNetworkPassphrase,
+		SigningKeys:                 serverKey.Seed(),
+		Domain:                      "webauth.example.com",
+		AuthHomeDomains:             "example.com",
+		ChallengeExpiresIn:          time.Minute,
+		JWK:                         string(jwk),
+		JWTIssuer:                   "https://example.com",
+		JWTExpiresIn:                time.Minute,
+		AllowAccountsThatDoNotExist: true,
+	})
+	require.NoError(t, err)
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConformance(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	server := newConformanceServer(t, serverKey)
+
+	conformance.Test(t, conformance.Config{
+		Endpoint:          server.URL,
+		ServerAccountID:   serverKey.Address(),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		WebAuthDomain:     "webauth.example.com",
+		HomeDomain:        "example.com",
+		ServerSigningKey:  serverKey,
+	})
+}
+
+func TestConformance_failures(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	server := newConformanceServer(t, serverKey)
+
+	// The challenges of the server do not match the expected web auth
+	// domain, and the forged cases are skipped without the signing key.
+	results, err := conformance.Run(context.Background(), conformance.Config{
+		Endpoint:          server.URL,
+		ServerAccountID:   serverKey.Address(),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		WebAuthDomain:     "other.example.com",
+		HomeDomain:        "example.com",
+	})
+	require.NoError(t, err)
+	failed := map[string]bool{}
+	skipped := map[string]bool{}
+	for _, result := range results {
+		if !result.Passed() {
+			failed[result.Name] = true
+		}
+		if result.Skipped {
+			skipped[result.Name] = true
+		}
+	}
+	assert.Equal(t, map[string]bool{
+		"challenge":               true,
+		"challenge_home_domain":   true,
+		"challenge_muxed_account": true,
+	}, failed)
+	assert.Equal(t, map[string]bool{
+		"token_expired":               true,
+		"token_not_yet_valid":         true,
+		"token_wrong_home_domain":     true,
+		"token_wrong_web_auth_domain": true,
+		"token_muxed_account":         true,
+	}, skipped)
+
+	_, err = conformance.Run(context.Background(), conformance.Config{
+		Endpoint:          server.URL,
+		ServerAccountID:   serverKey.Address(),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		HomeDomain:        "example.com",
+		ServerSigningKey:  keypair.MustRandom(),
+	})
+	assert.EqualError(t, err, "server signing key does not match server account")
+}
@@ -22,6 +22,10 @@ var badRequest = errorResponse{
 	Status: http.StatusBadRequest,
 	Error:  "The request was invalid in some way.",
 }
+var tooManyRequests = errorResponse{
+	Status: http.StatusTooManyRequests,
+	Error:  "Too many requests were made, try again later.",
+}
 var unauthorized = errorResponse{
 	Status: http.StatusUnauthorized,
 	Error:  "The request could not be authenticated.",
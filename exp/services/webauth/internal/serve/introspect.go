@@ -0,0 +1,107 @@
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpauthz"
+	"github.com/stellar/go/support/http/httpdecode"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// verifyToken parses the JWT and verifies that it was signed with the JWK and
+// issued by the issuer. It returns the token's claims and whether the token
+// is still active, i.e. it has not expired and has not been revoked.
+func verifyToken(ctx context.Context, tokenStr string, jwk jose.JSONWebKey, issuer string, store TokenStore) (jwt.Claims, bool, error) {
+	claims := jwt.Claims{}
+	token, err := jwt.ParseSigned(tokenStr)
+	if err != nil {
+		return claims, false, nil
+	}
+	err = token.Claims(jwk.Public(), &claims)
+	if err != nil {
+		return claims, false, nil
+	}
+	if claims.ID == "" || claims.Expiry == nil {
+		return claims, false, nil
+	}
+	err = claims.Validate(jwt.Expected{Issuer: issuer, Time: time.Now()})
+	if err != nil {
+		return claims, false, nil
+	}
+	revoked, err := store.IsRevoked(ctx, claims.ID)
+	if err != nil {
+		return claims, false, errors.Wrap(err, "checking token revocation")
+	}
+	return claims, !revoked, nil
+}
+
+// introspectHandler tells resource servers whether tokens are active. Since
+// the response discloses the claims of tokens, resource servers must
+// authenticate with the bearer token Secret, as required by RFC 7662. Secret
+// is read on each request, so that it can be rotated.
+type introspectHandler struct {
+	Logger     *supportlog.Entry
+	JWK        jose.JSONWebKey
+	JWTIssuer  string
+	TokenStore TokenStore
+	Secret     *config.SecretValue
+}
+
+type introspectRequest struct {
+	Token string `json:"token" form:"token"`
+}
+
+type introspectResponse struct {
+	Active    bool   `json:"active"`
+	Issuer    string `json:"iss,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	ID        string `json:"jti,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+func (h introspectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	secret := httpauthz.ParseBearerToken(r.Header.Get("Authorization"))
+	expected := h.Secret.Get()
+	if expected == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+		unauthorized.Render(w)
+		return
+	}
+
+	req := introspectRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Token == "" {
+		badRequest.Render(w)
+		return
+	}
+
+	claims, active, err := verifyToken(ctx, req.Token, h.JWK, h.JWTIssuer, h.TokenStore)
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	// Details of inactive tokens are not disclosed, as described in RFC 7662.
+	res := introspectResponse{Active: active}
+	if active {
+		res.Issuer = claims.Issuer
+		res.Subject = claims.Subject
+		res.ID = claims.ID
+		if claims.IssuedAt != nil {
+			res.IssuedAt = int64(*claims.IssuedAt)
+		}
+		res.ExpiresAt = int64(*claims.Expiry)
+	}
+	httpjson.Render(w, res, httpjson.JSON)
+}
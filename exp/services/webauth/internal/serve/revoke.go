@@ -0,0 +1,58 @@
+package serve
+
+import (
+	"net/http"
+
+	"github.com/stellar/go/support/http/httpdecode"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+	"gopkg.in/square/go-jose.v2"
+)
+
+type revokeHandler struct {
+	Logger     *supportlog.Entry
+	JWK        jose.JSONWebKey
+	JWTIssuer  string
+	TokenStore TokenStore
+}
+
+type revokeRequest struct {
+	Token string `json:"token" form:"token"`
+}
+
+type revokeResponse struct{}
+
+func (h revokeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req := revokeRequest{}
+	err := httpdecode.Decode(r, &req)
+	if err != nil || req.Token == "" {
+		badRequest.Render(w)
+		return
+	}
+
+	claims, active, err := verifyToken(ctx, req.Token, h.JWK, h.JWTIssuer, h.TokenStore)
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+
+	// Revoking a token that is invalid, expired or already revoked is not an
+	// error, as described in RFC 7009, since the token cannot be used anyway.
+	if active {
+		err = h.TokenStore.Revoke(ctx, claims.ID, claims.Expiry.Time())
+		if err != nil {
+			h.Logger.Ctx(ctx).WithStack(err).Error(err)
+			serverError.Render(w)
+			return
+		}
+		h.Logger.Ctx(ctx).
+			WithField("jti", claims.ID).
+			WithField("account", claims.Subject).
+			Info("Revoked token.")
+	}
+
+	httpjson.Render(w, revokeResponse{}, httpjson.JSON)
+}
@@ -0,0 +1,201 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/exp/services/webauth/internal/db/dbmigrate"
+	"github.com/stellar/go/exp/support/jwtkey"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/support/db/dbtest"
+	supportlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+func signTestToken(t *testing.T, jwk jose.JSONWebKey, claims jwt.Claims) string {
+	signerOpts := &jose.SignerOptions{}
+	signerOpts.WithType("JWT")
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.SignatureAlgorithm(jwk.Algorithm), Key: jwk.Key}, signerOpts)
+	require.NoError(t, err)
+	tokenStr, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	require.NoError(t, err)
+	return tokenStr
+}
+
+func postToken(t *testing.T, h http.Handler, path, token string) map[string]interface{} {
+	body := url.Values{}
+	body.Set("token", token)
+	r := httptest.NewRequest("POST", path, strings.NewReader(body.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	res := map[string]interface{}{}
+	err := json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+	return res
+}
+
+func TestIntrospectAndRevoke(t *testing.T) {
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+	account := keypair.MustRandom()
+	store := NewMemoryTokenStore()
+
+	now := time.Now()
+	token := signTestToken(t, jwk, jwt.Claims{
+		ID:       "1234",
+		Issuer:   "https://example.com",
+		Subject:  account.Address(),
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Minute)),
+	})
+
+	introspect := introspectHandler{Logger: supportlog.DefaultLogger, JWK: jwk, JWTIssuer: "https://example.com", TokenStore: store, Secret: config.NewSecretValue("secret")}
+	revoke := revokeHandler{Logger: supportlog.DefaultLogger, JWK: jwk, JWTIssuer: "https://example.com", TokenStore: store}
+
+	res := postToken(t, introspect, "/introspect", token)
+	assert.Equal(t, true, res["active"])
+	assert.Equal(t, "1234", res["jti"])
+	assert.Equal(t, account.Address(), res["sub"])
+	assert.Equal(t, "https://example.com", res["iss"])
+
+	res = postToken(t, revoke, "/revoke", token)
+	assert.Empty(t, res)
+
+	revoked, err := store.IsRevoked(context.Background(), "1234")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	res = postToken(t, introspect, "/introspect", token)
+	assert.Equal(t, map[string]interface{}{"active": false}, res)
+
+	// Revoking again, or revoking garbage, is not an error.
+	res = postToken(t, revoke, "/revoke", token)
+	assert.Empty(t, res)
+	res = postToken(t, revoke, "/revoke", "notatoken")
+	assert.Empty(t, res)
+}
+
+func TestIntrospect_wrongIssuer(t *testing.T) {
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+
+	now := time.Now()
+	token := signTestToken(t, jwk, jwt.Claims{
+		ID:       "1234",
+		Issuer:   "https://other.example.com",
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Minute)),
+	})
+
+	introspect := introspectHandler{Logger: supportlog.DefaultLogger, JWK: jwk, JWTIssuer: "https://example.com", TokenStore: NewMemoryTokenStore(), Secret: config.NewSecretValue("secret")}
+	res := postToken(t, introspect, "/introspect", token)
+	assert.Equal(t, map[string]interface{}{"active": false}, res)
+}
+
+func TestIntrospect_unauthenticated(t *testing.T) {
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+
+	now := time.Now()
+	token := signTestToken(t, jwk, jwt.Claims{
+		ID:       "1234",
+		Issuer:   "https://example.com",
+		IssuedAt: jwt.NewNumericDate(now),
+		Expiry:   jwt.NewNumericDate(now.Add(time.Minute)),
+	})
+	introspect := introspectHandler{Logger: supportlog.DefaultLogger, JWK: jwk, JWTIssuer: "https://example.com", TokenStore: NewMemoryTokenStore(), Secret: config.NewSecretValue("secret")}
+
+	serve := func(authorization string) int {
+		body := url.Values{}
+		body.Set("token", token)
+		r := httptest.NewRequest("POST", "/introspect", strings.NewReader(body.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if authorization != "" {
+			r.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		introspect.ServeHTTP(w, r)
+		return w.Result().StatusCode
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, serve(""))
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer wrong"))
+	// the token introspected is not a credential
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer "+token))
+	assert.Equal(t, http.StatusOK, serve("Bearer secret"))
+
+	// Once the secret is rotated, only the new secret is accepted.
+	introspect.Secret.Set("rotated")
+	assert.Equal(t, http.StatusUnauthorized, serve("Bearer secret"))
+	assert.Equal(t, http.StatusOK, serve("Bearer rotated"))
+}
+
+func TestMemoryTokenStore_prunesExpired(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTokenStore()
+
+	err := store.Revoke(ctx, "expired", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	err = store.Revoke(ctx, "active", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	revoked, err := store.IsRevoked(ctx, "expired")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	revoked, err = store.IsRevoked(ctx, "active")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestDBTokenStore(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	_, err := dbmigrate.Migrate(conn, migrate.Up, 0)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	store := DBTokenStore{DB: conn}
+
+	revoked, err := store.IsRevoked(ctx, "1234")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	err = store.Revoke(ctx, "1234", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	// revoking twice is not an error
+	err = store.Revoke(ctx, "1234", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	revoked, err = store.IsRevoked(ctx, "1234")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// revocations of expired tokens are pruned
+	err = store.Revoke(ctx, "expired", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	err = store.Revoke(ctx, "5678", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	revoked, err = store.IsRevoked(ctx, "expired")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/clients/stellartoml"
+	"github.com/stellar/go/exp/services/webauth/internal/db"
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/config"
 	"github.com/stellar/go/support/errors"
 	supporthttp "github.com/stellar/go/support/http"
 	supportlog "github.com/stellar/go/support/log"
@@ -16,6 +19,10 @@ import (
 	"gopkg.in/square/go-jose.v2"
 )
 
+// stellarTOMLTimeout is the timeout of requests for the stellar.toml of the
+// client domains of challenges.
+const stellarTOMLTimeout = 10 * time.Second
+
 type Options struct {
 	Logger                      *supportlog.Entry
 	HorizonURL                  string
@@ -29,6 +36,12 @@ type Options struct {
 	JWTIssuer                   string
 	JWTExpiresIn                time.Duration
 	AllowAccountsThatDoNotExist bool
+	DatabaseURL                 string
+	IntrospectionSecret         string
+	// IntrospectionSecretValue, if set, holds the introspection secret
+	// instead of IntrospectionSecret, so that it can be rotated while the
+	// server runs.
+	IntrospectionSecretValue *config.SecretValue
 }
 
 func Serve(opts Options) {
@@ -90,11 +103,37 @@ func handler(opts Options) (http.Handler, error) {
 	}
 	horizonClient.SetHorizonTimeout(horizonTimeout)
 
+	var tokenStore TokenStore
+	if opts.DatabaseURL != "" {
+		db, err := db.Open(opts.DatabaseURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing database url")
+		}
+		err = db.Ping()
+		if err != nil {
+			opts.Logger.Warn("Error pinging to Database: ", err)
+		}
+		tokenStore = DBTokenStore{DB: db}
+	} else {
+		opts.Logger.Warn("No database configured, token revocations are held in memory and are lost when the server restarts")
+		tokenStore = NewMemoryTokenStore()
+	}
+
 	mux := supporthttp.NewAPIMux(opts.Logger)
 
 	mux.NotFound(errorHandler{Error: notFound}.ServeHTTP)
 	mux.MethodNotAllowed(errorHandler{Error: methodNotAllowed}.ServeHTTP)
 
+	clientDomains, err := newClientDomainSigningKeys(&stellartoml.Client{
+		HTTP: &http.Client{
+			Timeout:       stellarTOMLTimeout,
+			CheckRedirect: checkClientDomainRedirect,
+		},
+	}, defaultClientDomainLookupQuota)
+	if err != nil {
+		return nil, err
+	}
+
 	mux.Get("/health", health.PassHandler{}.ServeHTTP)
 	mux.Get("/", challengeHandler{
 		Logger:             opts.Logger,
@@ -103,6 +142,7 @@ func handler(opts Options) (http.Handler, error) {
 		ChallengeExpiresIn: opts.ChallengeExpiresIn,
 		Domain:             opts.Domain,
 		HomeDomains:        trimmedHomeDomains,
+		ClientDomains:      clientDomains,
 	}.ServeHTTP)
 	mux.Post("/", tokenHandler{
 		Logger:                      opts.Logger,
@@ -116,6 +156,25 @@ func handler(opts Options) (http.Handler, error) {
 		Domain:                      opts.Domain,
 		HomeDomains:                 trimmedHomeDomains,
 	}.ServeHTTP)
+	introspectionSecret := opts.IntrospectionSecretValue
+	if introspectionSecret == nil {
+		introspectionSecret = config.NewSecretValue(opts.IntrospectionSecret)
+	}
+	if introspectionSecret.Get() != "" {
+		mux.Post("/introspect", introspectHandler{
+			Logger:     opts.Logger,
+			JWK:        jwk,
+			JWTIssuer:  opts.JWTIssuer,
+			TokenStore: tokenStore,
+			Secret:     introspectionSecret,
+		}.ServeHTTP)
+	}
+	mux.Post("/revoke", revokeHandler{
+		Logger:     opts.Logger,
+		JWK:        jwk,
+		JWTIssuer:  opts.JWTIssuer,
+		TokenStore: tokenStore,
+	}.ServeHTTP)
 
 	return mux, nil
 }
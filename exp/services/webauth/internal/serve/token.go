@@ -1,16 +1,21 @@
 package serve
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/http/httpdecode"
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/httpjson"
 	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
 	"gopkg.in/square/go-jose.v2"
 	"gopkg.in/square/go-jose.v2/jwt"
 )
@@ -36,6 +41,10 @@ type tokenResponse struct {
 	Token string `json:"token"`
 }
 
+type clientDomainClaims struct {
+	ClientDomain string `json:"client_domain"`
+}
+
 func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -76,11 +85,33 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	clientDomain, clientDomainAccountID := txnbuild.ChallengeTxClientDomain(tx)
+
+	// The user of a custodial account is identified by a muxed account
+	// (M...) or by the memo of the challenge, and authenticated with the
+	// signers of the underlying account (G...). The user is the subject of
+	// the JWT, e.g. M... or G...:1234.
+	subject := clientAccountID
+	muxedAccount, err := xdr.AddressToMuxedAccount(clientAccountID)
+	if err != nil {
+		h.Logger.Ctx(ctx).WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+	underlyingAccountID := muxedAccount.ToAccountId()
+	clientAccountID = underlyingAccountID.Address()
+	if memo := txnbuild.ChallengeTxMemo(tx); memo != nil {
+		subject = fmt.Sprintf("%s:%d", clientAccountID, *memo)
+	}
+
 	l := h.Logger.Ctx(ctx).
 		WithField("tx", hash).
 		WithField("account", clientAccountID).
+		WithField("subject", subject).
 		WithField("serversigner", signingAddress.Address()).
-		WithField("homedomain", homeDomain)
+		WithField("homedomain", homeDomain).
+		WithField("clientdomain", clientDomain).
+		WithField("clientdomainsigner", clientDomainAccountID)
 
 	l.Info("Start verifying challenge transaction.")
 
@@ -140,14 +171,28 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tokenID, err := newTokenID()
+	if err != nil {
+		l.WithStack(err).Error(err)
+		serverError.Render(w)
+		return
+	}
+
 	now := time.Now().UTC()
 	claims := jwt.Claims{
+		ID:       tokenID,
 		Issuer:   h.JWTIssuer,
-		Subject:  clientAccountID,
+		Subject:  subject,
 		IssuedAt: jwt.NewNumericDate(now),
 		Expiry:   jwt.NewNumericDate(now.Add(h.JWTExpiresIn)),
 	}
-	tokenStr, err := jwt.Signed(jws).Claims(claims).CompactSerialize()
+	builder := jwt.Signed(jws).Claims(claims)
+	if clientDomain != "" {
+		// The client domain verified by its signature on the challenge is
+		// carried by the nonstandard client_domain claim of SEP-10.
+		builder = builder.Claims(clientDomainClaims{ClientDomain: clientDomain})
+	}
+	tokenStr, err := builder.CompactSerialize()
 	if err != nil {
 		l.WithStack(err).Error(err)
 		serverError.Render(w)
@@ -159,3 +204,14 @@ func (h tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	httpjson.Render(w, res, httpjson.JSON)
 }
+
+// newTokenID returns a random identifier for the jti claim of a JWT so that
+// the token can be revoked individually.
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.Wrap(err, "generating token id")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -21,6 +21,7 @@ import (
 	supportlog "github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/problem"
 	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/square/go-jose.v2"
@@ -121,6 +122,7 @@ func TestToken_formInputSuccess(t *testing.T) {
 	assert.Equal(t, "https://example.com", claims["iss"])
 	assert.Equal(t, account.Address(), claims["sub"])
 	assert.Equal(t, account.Address(), claims["sub"])
+	assert.NotEmpty(t, claims["jti"])
 	iat := time.Unix(int64(claims["iat"].(float64)), 0)
 	exp := time.Unix(int64(claims["exp"].(float64)), 0)
 	assert.True(t, iat.Before(time.Now()))
@@ -1213,3 +1215,262 @@ func TestToken_jsonInputInvalidWebAuthDomainFail(t *testing.T) {
 
 	assert.JSONEq(t, `{"error":"The request was invalid in some way."}`, string(respBodyBytes))
 }
+
+func TestToken_jsonInputClientDomainSuccess(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	t.Logf("Server signing key: %s", serverKey.Address())
+
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+
+	account := keypair.MustRandom()
+	t.Logf("Client account: %s", account.Address())
+
+	clientDomainKey := keypair.MustRandom()
+	t.Logf("Client domain signing key: %s", clientDomainKey.Address())
+
+	domain := "webauth.example.com"
+	homeDomain := "example.com"
+	tx, err := txnbuild.BuildChallengeTxWithClientDomain(
+		serverKey.Seed(),
+		account.Address(),
+		domain,
+		homeDomain,
+		"wallet.example.com",
+		clientDomainKey.Address(),
+		network.TestNetworkPassphrase,
+		time.Minute,
+	)
+	require.NoError(t, err)
+
+	horizonClient := &horizonclient.MockClient{}
+	horizonClient.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: account.Address()}).
+		Return(
+			horizon.Account{
+				Thresholds: horizon.AccountThresholds{
+					LowThreshold:  1,
+					MedThreshold:  10,
+					HighThreshold: 100,
+				},
+				Signers: []horizon.Signer{
+					{
+						Key:    account.Address(),
+						Weight: 100,
+					},
+				}},
+			nil,
+		)
+
+	h := tokenHandler{
+		Logger:            supportlog.DefaultLogger,
+		HorizonClient:     horizonClient,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		SigningAddresses:  []*keypair.FromAddress{serverKey.FromAddress()},
+		JWK:               jwk,
+		JWTIssuer:         "https://example.com",
+		JWTExpiresIn:      time.Minute,
+		Domain:            domain,
+		HomeDomains:       []string{homeDomain},
+	}
+
+	post := func(tx *txnbuild.Transaction) *http.Response {
+		txSigned, err := tx.Base64()
+		require.NoError(t, err)
+		bodyBytes, err := json.Marshal(map[string]string{"transaction": txSigned})
+		require.NoError(t, err)
+		r := httptest.NewRequest("POST", "/", bytes.NewReader(bodyBytes))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	// The challenge is rejected without the signature of the client domain.
+	txSignedByClient, err := tx.Sign(network.TestNetworkPassphrase, account)
+	require.NoError(t, err)
+	resp := post(txSignedByClient)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	txSignedByBoth, err := txSignedByClient.Sign(network.TestNetworkPassphrase, clientDomainKey)
+	require.NoError(t, err)
+	resp = post(txSignedByBoth)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	res := struct {
+		Token string `json:"token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(res.Token, func(token *jwt.Token) (interface{}, error) {
+		return &jwtPrivateKey.PublicKey, nil
+	})
+	require.NoError(t, err)
+
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, account.Address(), claims["sub"])
+	assert.Equal(t, "wallet.example.com", claims["client_domain"])
+}
+
+func TestToken_jsonInputMuxedAccountSuccess(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+
+	account := keypair.MustRandom()
+	muxedAccount := xdr.MuxedAccount{
+		Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519,
+		Med25519: &xdr.MuxedAccountMed25519{
+			Id:      1234,
+			Ed25519: *xdr.MustAddress(account.Address()).Ed25519,
+		},
+	}
+	t.Logf("Client account: %s", muxedAccount.Address())
+
+	domain := "webauth.example.com"
+	homeDomain := "example.com"
+	tx, err := txnbuild.BuildChallengeTx(
+		serverKey.Seed(),
+		muxedAccount.Address(),
+		domain,
+		homeDomain,
+		network.TestNetworkPassphrase,
+		time.Minute,
+	)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, account)
+	require.NoError(t, err)
+	txSigned, err := tx.Base64()
+	require.NoError(t, err)
+
+	// The signers of the underlying account authenticate the muxed account.
+	horizonClient := &horizonclient.MockClient{}
+	horizonClient.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: account.Address()}).
+		Return(
+			horizon.Account{
+				Thresholds: horizon.AccountThresholds{HighThreshold: 1},
+				Signers:    []horizon.Signer{{Key: account.Address(), Weight: 1}},
+			},
+			nil,
+		)
+
+	h := tokenHandler{
+		Logger:            supportlog.DefaultLogger,
+		HorizonClient:     horizonClient,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		SigningAddresses:  []*keypair.FromAddress{serverKey.FromAddress()},
+		JWK:               jwk,
+		JWTIssuer:         "https://example.com",
+		JWTExpiresIn:      time.Minute,
+		Domain:            domain,
+		HomeDomains:       []string{homeDomain},
+	}
+
+	body := struct {
+		Transaction string `json:"transaction"`
+	}{txSigned}
+	bodyBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(bodyBytes))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	res := struct {
+		Token string `json:"token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+	token, err := jwt.Parse(res.Token, func(token *jwt.Token) (interface{}, error) {
+		return &jwtPrivateKey.PublicKey, nil
+	})
+	require.NoError(t, err)
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, muxedAccount.Address(), claims["sub"])
+	horizonClient.AssertExpectations(t)
+}
+
+func TestToken_jsonInputMemoAccountNotExistSuccess(t *testing.T) {
+	serverKey := keypair.MustRandom()
+	jwtPrivateKey, err := jwtkey.GenerateKey()
+	require.NoError(t, err)
+	jwk := jose.JSONWebKey{Key: jwtPrivateKey, Algorithm: string(jose.ES256)}
+
+	account := keypair.MustRandom()
+	t.Logf("Client account: %s", account.Address())
+
+	domain := "webauth.example.com"
+	homeDomain := "example.com"
+	tx, err := txnbuild.BuildChallengeTxWithMemo(
+		serverKey.Seed(),
+		account.Address(),
+		domain,
+		homeDomain,
+		network.TestNetworkPassphrase,
+		time.Minute,
+		txnbuild.MemoID(1234),
+	)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, account)
+	require.NoError(t, err)
+	txSigned, err := tx.Base64()
+	require.NoError(t, err)
+
+	horizonClient := &horizonclient.MockClient{}
+	horizonClient.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: account.Address()}).
+		Return(
+			horizon.Account{},
+			&horizonclient.Error{
+				Problem: problem.P{
+					Type:   "https://stellar.org/horizon-errors/not_found",
+					Title:  "Resource Missing",
+					Status: 404,
+				},
+			},
+		)
+
+	h := tokenHandler{
+		Logger:                      supportlog.DefaultLogger,
+		HorizonClient:               horizonClient,
+		NetworkPassphrase:           network.TestNetworkPassphrase,
+		SigningAddresses:            []*keypair.FromAddress{serverKey.FromAddress()},
+		JWK:                         jwk,
+		JWTIssuer:                   "https://example.com",
+		JWTExpiresIn:                time.Minute,
+		AllowAccountsThatDoNotExist: true,
+		Domain:                      domain,
+		HomeDomains:                 []string{homeDomain},
+	}
+
+	body := struct {
+		Transaction string `json:"transaction"`
+	}{txSigned}
+	bodyBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(bodyBytes))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	res := struct {
+		Token string `json:"token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	require.NoError(t, err)
+	token, err := jwt.Parse(res.Token, func(token *jwt.Token) (interface{}, error) {
+		return &jwtPrivateKey.PublicKey, nil
+	})
+	require.NoError(t, err)
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, account.Address()+":1234", claims["sub"])
+	horizonClient.AssertExpectations(t)
+}
@@ -0,0 +1,92 @@
+package serve
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/support/errors"
+)
+
+// TokenStore records the IDs of JWTs that have been revoked before their
+// expiry.
+type TokenStore interface {
+	// Revoke marks the token with the given ID as revoked. The store only
+	// needs to remember the revocation until expiresAt, after which the
+	// token is invalid regardless.
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	// IsRevoked reports whether the token with the given ID was revoked.
+	IsRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// MemoryTokenStore is a TokenStore that keeps revocations in memory.
+// Revocations are lost when the process restarts.
+type MemoryTokenStore struct {
+	mu      sync.Mutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		revoked: map[string]time.Time{},
+	}
+}
+
+func (s *MemoryTokenStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune(time.Now())
+	s.revoked[id] = expiresAt
+	return nil
+}
+
+func (s *MemoryTokenStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.revoked[id]
+	return ok, nil
+}
+
+// prune forgets revocations of tokens that have expired since they cannot
+// be used anymore. The caller must hold mu.
+func (s *MemoryTokenStore) prune(now time.Time) {
+	for id, expiresAt := range s.revoked {
+		if now.After(expiresAt) {
+			delete(s.revoked, id)
+		}
+	}
+}
+
+// DBTokenStore is a TokenStore that keeps revocations in the revoked_tokens
+// table of a Postgres database, so that they survive restarts and are shared
+// by all the instances of the server using the database.
+type DBTokenStore struct {
+	DB *sqlx.DB
+}
+
+func (s DBTokenStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := s.DB.ExecContext(ctx, `DELETE FROM revoked_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return errors.Wrap(err, "pruning expired revoked tokens")
+	}
+	_, err = s.DB.ExecContext(ctx, `
+		INSERT INTO revoked_tokens (id, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (id) DO NOTHING
+	`, id, expiresAt)
+	if err != nil {
+		return errors.Wrap(err, "inserting revoked token")
+	}
+	return nil
+}
+
+func (s DBTokenStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	var revoked bool
+	err := s.DB.GetContext(ctx, &revoked, `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE id = $1)`, id)
+	if err != nil {
+		return false, errors.Wrap(err, "querying revoked token")
+	}
+	return revoked, nil
+}
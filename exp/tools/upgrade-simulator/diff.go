@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/clients/stellarcore"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// diffLedgers returns the human-readable differences between the meta of the
+// same ledger closed by the baseline and the candidate Stellar Core. It
+// returns no differences if the meta are identical.
+func diffLedgers(baseline, candidate xdr.LedgerCloseMeta) ([]string, error) {
+	baselineBytes, err := baseline.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal baseline ledger")
+	}
+	candidateBytes, err := candidate.MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal candidate ledger")
+	}
+	if bytes.Equal(baselineBytes, candidateBytes) {
+		return nil, nil
+	}
+
+	var diffs []string
+	diff := func(format string, baseline, candidate interface{}) {
+		if baseline != candidate {
+			diffs = append(diffs, fmt.Sprintf(format+": %v != %v", baseline, candidate))
+		}
+	}
+
+	b, c := baseline.MustV0(), candidate.MustV0()
+	bh, ch := b.LedgerHeader.Header, c.LedgerHeader.Header
+	diff("protocol version", bh.LedgerVersion, ch.LedgerVersion)
+	diff("base fee", bh.BaseFee, ch.BaseFee)
+	diff("base reserve", bh.BaseReserve, ch.BaseReserve)
+	diff("max tx set size", bh.MaxTxSetSize, ch.MaxTxSetSize)
+	diff("total coins", bh.TotalCoins, ch.TotalCoins)
+	diff("fee pool", bh.FeePool, ch.FeePool)
+	diff("upgrades", describeUpgrades(b.UpgradesProcessing), describeUpgrades(c.UpgradesProcessing))
+	diff("transaction count", len(b.TxProcessing), len(c.TxProcessing))
+
+	candidateResults := map[xdr.Hash]xdr.TransactionResult{}
+	for _, tx := range c.TxProcessing {
+		candidateResults[tx.Result.TransactionHash] = tx.Result.Result
+	}
+	for _, tx := range b.TxProcessing {
+		hash := tx.Result.TransactionHash.HexString()
+		result, ok := candidateResults[tx.Result.TransactionHash]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("transaction %s: not applied by candidate", hash))
+			continue
+		}
+		diff("transaction "+hash+" result", tx.Result.Result.Result.Code, result.Result.Code)
+		diff("transaction "+hash+" fee charged", tx.Result.Result.FeeCharged, result.FeeCharged)
+	}
+
+	diff("ledger hash", b.LedgerHeader.Hash.HexString(), c.LedgerHeader.Hash.HexString())
+	if len(diffs) == 0 {
+		diffs = append(diffs, "ledger entry changes differ")
+	}
+	return diffs, nil
+}
+
+func describeUpgrades(upgrades []xdr.UpgradeEntryMeta) string {
+	var buf bytes.Buffer
+	for i, upgrade := range upgrades {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		switch upgrade.Upgrade.Type {
+		case xdr.LedgerUpgradeTypeLedgerUpgradeVersion:
+			fmt.Fprintf(&buf, "protocol version %d", *upgrade.Upgrade.NewLedgerVersion)
+		case xdr.LedgerUpgradeTypeLedgerUpgradeBaseFee:
+			fmt.Fprintf(&buf, "base fee %d", *upgrade.Upgrade.NewBaseFee)
+		case xdr.LedgerUpgradeTypeLedgerUpgradeMaxTxSetSize:
+			fmt.Fprintf(&buf, "max tx set size %d", *upgrade.Upgrade.NewMaxTxSetSize)
+		case xdr.LedgerUpgradeTypeLedgerUpgradeBaseReserve:
+			fmt.Fprintf(&buf, "base reserve %d", *upgrade.Upgrade.NewBaseReserve)
+		default:
+			buf.WriteString(upgrade.Upgrade.Type.String())
+		}
+	}
+	if buf.Len() == 0 {
+		return "none"
+	}
+	return buf.String()
+}
+
+// checkUpgrades returns an error if the header of a ledger closed by the
+// candidate Stellar Core does not have the values of the upgrades it was set
+// to vote for.
+func checkUpgrades(header xdr.LedgerHeader, upgrades stellarcore.UpgradeParameters) error {
+	var missing []string
+	check := func(name string, expected, actual uint32) {
+		if expected != 0 && expected != actual {
+			missing = append(missing, fmt.Sprintf("%s is %d instead of %d", name, actual, expected))
+		}
+	}
+	check("protocol version", upgrades.ProtocolVersion, uint32(header.LedgerVersion))
+	check("base fee", upgrades.BaseFee, uint32(header.BaseFee))
+	check("base reserve", upgrades.BaseReserve, uint32(header.BaseReserve))
+	check("max tx set size", upgrades.MaxTxSetSize, uint32(header.MaxTxSetSize))
+	if len(missing) > 0 {
+		return errors.Errorf("upgrades were not applied: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/clients/stellarcore"
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/network"
+	proto "github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
+)
+
+// upgrade-simulator replays a range of ledgers with two captive Stellar Core
+// instances, a baseline one replaying them from the history archives and a
+// candidate one closing them standalone after applying a proposed network
+// upgrade, and prints the differences between the meta of the ledgers they
+// close. See README.md.
+func main() {
+	baselineBinaryPath := flag.String("core-binary-path", "stellar-core", "path to the stellar-core binary of the baseline")
+	candidateBinaryPath := flag.String("candidate-core-binary-path", "", "path to the stellar-core binary of the candidate (defaults to -core-binary-path)")
+	configPath := flag.String("captive-core-config-path", "", "path to the captive core toml file used by both instances (optional)")
+	networkPassphrase := flag.String("network-passphrase", network.PublicNetworkPassphrase, "network passphrase")
+	archiveURLs := flag.String("history-archive-urls", "https://history.stellar.org/prd/core-live/core_live_001", "comma-separated list of history archive urls")
+	candidateHTTPPort := flag.Uint("candidate-http-port", 11626, "HTTP port of the candidate, used to submit the transactions and close the ledgers")
+	storagePath := flag.String("storage-path", os.TempDir(), "directory in which the captive core instances store their data")
+	start := flag.Uint("start", 0, "first ledger of the range to replay, must follow a checkpoint ledger")
+	end := flag.Uint("end", 0, "last ledger of the range to replay")
+	maxDiffs := flag.Uint("max-diffs", 0, "stop after this many differing ledgers (0 for no limit)")
+	protocolVersion := flag.Uint("protocol-version", 0, "ledger protocol version to upgrade to")
+	baseFee := flag.Uint("base-fee", 0, "base fee to upgrade to, in stroops")
+	baseReserve := flag.Uint("base-reserve", 0, "base reserve to upgrade to, in stroops")
+	maxTxSetSize := flag.Uint("max-tx-set-size", 0, "maximum transaction set size to upgrade to")
+	flag.Parse()
+
+	log.SetLevel(log.InfoLevel)
+
+	if *start == 0 || *end < *start {
+		flag.Usage()
+		log.Fatal("-start and -end must define a non-empty range of ledgers")
+	}
+	// The candidate replays the ledgers preceding the range from the history
+	// archives, it must stop at the ledger preceding -start.
+	if *start < 64 || !historyarchive.NewCheckpointManager(0).IsCheckpoint(uint32(*start)-1) {
+		flag.Usage()
+		log.Fatal("-start must follow a checkpoint ledger, e.g. 35000064")
+	}
+	upgrades := stellarcore.UpgradeParameters{
+		ProtocolVersion: uint32(*protocolVersion),
+		BaseFee:         uint32(*baseFee),
+		BaseReserve:     uint32(*baseReserve),
+		MaxTxSetSize:    uint32(*maxTxSetSize),
+	}
+	if upgrades == (stellarcore.UpgradeParameters{}) && *candidateBinaryPath == "" {
+		flag.Usage()
+		log.Fatal("at least one upgrade or -candidate-core-binary-path must be given")
+	}
+	if *candidateBinaryPath == "" {
+		*candidateBinaryPath = *baselineBinaryPath
+	}
+
+	params := ledgerbackend.CaptiveCoreTomlParams{
+		NetworkPassphrase:  *networkPassphrase,
+		HistoryArchiveURLs: strings.Split(*archiveURLs, ","),
+	}
+	var baselineToml *ledgerbackend.CaptiveCoreToml
+	var err error
+	if *configPath != "" {
+		baselineToml, err = ledgerbackend.NewCaptiveCoreTomlFromFile(*configPath, params)
+	} else {
+		baselineToml, err = ledgerbackend.NewCaptiveCoreToml(params)
+	}
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "could not create captive core toml"))
+	}
+	candidateToml, err := baselineToml.ManualCloseToml()
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "could not create candidate captive core toml"))
+	}
+	candidateToml.HTTPPort = *candidateHTTPPort
+
+	ctx := context.Background()
+	baseline, err := newBackend(ctx, "baseline", *baselineBinaryPath, *storagePath, params, baselineToml,
+		ledgerbackend.BoundedRange(uint32(*start), uint32(*end)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer baseline.Close()
+	// The candidate replays the ledgers up to the one preceding the range,
+	// then waits for the ledgers to be closed manually.
+	candidate, err := newBackend(ctx, "candidate", *candidateBinaryPath, *storagePath, params, candidateToml,
+		ledgerbackend.UnboundedRange(uint32(*start)-1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer candidate.Close()
+
+	candidateCore := &stellarcore.Client{URL: fmt.Sprintf("http://localhost:%d", *candidateHTTPPort)}
+	if upgrades != (stellarcore.UpgradeParameters{}) {
+		if err = candidateCore.SetUpgrades(ctx, upgrades); err != nil {
+			log.Fatal(errors.Wrap(err, "could not set the upgrades of the candidate"))
+		}
+	}
+
+	differing := uint(0)
+	for sequence := uint32(*start); sequence <= uint32(*end); sequence++ {
+		baselineLedger, err := baseline.GetLedger(ctx, sequence)
+		if err != nil {
+			log.Fatal(errors.Wrapf(err, "could not get ledger %d from baseline", sequence))
+		}
+		rejected, err := closeLedger(ctx, candidateCore, *networkPassphrase, baselineLedger)
+		if err != nil {
+			log.Fatal(errors.Wrapf(err, "could not close ledger %d with candidate", sequence))
+		}
+		candidateLedger, err := candidate.GetLedger(ctx, sequence)
+		if err != nil {
+			log.Fatal(errors.Wrapf(err, "could not get ledger %d from candidate", sequence))
+		}
+		if sequence == uint32(*start) {
+			// The upgrades are applied by the first ledger the candidate
+			// closes.
+			if err = checkUpgrades(candidateLedger.MustV0().LedgerHeader.Header, upgrades); err != nil {
+				log.Fatal(errors.Wrapf(err, "candidate did not upgrade ledger %d", sequence))
+			}
+		}
+
+		diffs, err := diffLedgers(baselineLedger, candidateLedger)
+		if err != nil {
+			log.Fatal(errors.Wrapf(err, "could not diff ledger %d", sequence))
+		}
+		diffs = append(rejected, diffs...)
+		if len(diffs) == 0 {
+			continue
+		}
+
+		differing++
+		fmt.Printf("ledger %d:\n", sequence)
+		for _, diff := range diffs {
+			fmt.Printf("  %s\n", diff)
+		}
+		if *maxDiffs != 0 && differing >= *maxDiffs {
+			log.Infof("Stopping after %d differing ledgers", differing)
+			break
+		}
+	}
+
+	log.Infof("%d ledgers differ between baseline and candidate", differing)
+	if differing > 0 {
+		os.Exit(1)
+	}
+}
+
+// closeLedger submits the transactions of the given ledger closed by the
+// baseline to the candidate Stellar Core, and makes it close the ledger with
+// the same close time. It returns the transactions the candidate rejected.
+func closeLedger(ctx context.Context, core *stellarcore.Client, networkPassphrase string, ledger xdr.LedgerCloseMeta) ([]string, error) {
+	var rejected []string
+	for _, tx := range ledger.MustV0().TxSet.Txs {
+		hash, err := network.HashTransactionInEnvelope(tx, networkPassphrase)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not hash transaction")
+		}
+		blob, err := xdr.MarshalBase64(tx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal transaction")
+		}
+		resp, err := core.SubmitTransaction(ctx, blob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not submit transaction %x", hash)
+		}
+		if resp.Exception != "" {
+			return nil, errors.Errorf("could not submit transaction %x: %s", hash, resp.Exception)
+		}
+		if resp.Status != proto.TXStatusPending {
+			rejected = append(rejected, fmt.Sprintf("transaction %x: rejected by candidate: %s %s", hash, resp.Status, resp.Error))
+		}
+	}
+
+	header := ledger.MustV0().LedgerHeader.Header
+	closeTime := time.Unix(int64(header.ScpValue.CloseTime), 0)
+	if err := core.ManualCloseAt(ctx, uint32(header.LedgerSeq), closeTime); err != nil {
+		return nil, errors.Wrap(err, "could not close ledger")
+	}
+	return rejected, nil
+}
+
+func newBackend(
+	ctx context.Context,
+	name, binaryPath, storagePath string,
+	params ledgerbackend.CaptiveCoreTomlParams,
+	toml *ledgerbackend.CaptiveCoreToml,
+	ledgerRange ledgerbackend.Range,
+) (*ledgerbackend.CaptiveStellarCore, error) {
+	backend, err := ledgerbackend.NewCaptive(ledgerbackend.CaptiveCoreConfig{
+		BinaryPath:         binaryPath,
+		NetworkPassphrase:  params.NetworkPassphrase,
+		HistoryArchiveURLs: params.HistoryArchiveURLs,
+		Toml:               toml,
+		StoragePath:        storagePath,
+		Log:                log.WithField("subservice", name),
+		Context:            ctx,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create %s captive core", name)
+	}
+
+	log.Infof("Preparing range %s with %s captive core", ledgerRange, name)
+	if err := backend.PrepareRange(ctx, ledgerRange); err != nil {
+		backend.Close()
+		return nil, errors.Wrapf(err, "could not prepare range with %s captive core", name)
+	}
+	return backend, nil
+}
@@ -33,6 +33,9 @@ type CommandOptions struct {
 	Force       bool
 	Verify      bool
 	Thorough    bool
+	// MirrorStatePath is the path of a local file recording the checkpoints
+	// copied by Mirror, so that an interrupted mirroring can be resumed.
+	MirrorStatePath string
 }
 
 type ConnectOptions struct {
@@ -407,6 +410,9 @@ func Connect(u string, opts ConnectOptions) (*Archive, error) {
 			pth = pth[1:]
 		}
 		arch.backend, err = makeS3Backend(parsed.Host, pth, opts)
+	} else if parsed.Scheme == "gs" {
+		pth = strings.TrimPrefix(pth, "/")
+		arch.backend = makeGCSBackend(parsed.Host, pth, opts)
 	} else if parsed.Scheme == "file" {
 		pth = path.Join(parsed.Host, pth)
 		arch.backend = makeFsBackend(pth, opts)
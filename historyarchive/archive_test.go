@@ -583,3 +583,249 @@ func TestGetLedgers(t *testing.T) {
 		assertXdrEquals(t, results[i], ledger.TransactionResult)
 	}
 }
+
+func TestConnectGCS(t *testing.T) {
+	archive := MustConnect("gs://history-bucket/stellar/pubnet", ConnectOptions{CheckpointFrequency: 64})
+	backend, ok := archive.backend.(*HttpArchiveBackend)
+	if assert.True(t, ok) {
+		assert.Equal(t, "https://storage.googleapis.com/history-bucket/stellar/pubnet", backend.base.String())
+	}
+}
+
+// addBucket writes a valid bucket with the given entries to the archive.
+func addBucket(t *testing.T, arch *Archive, entries ...xdr.BucketEntry) Hash {
+	var raw bytes.Buffer
+	for _, entry := range entries {
+		assert.NoError(t, xdr.MarshalFramed(&raw, entry))
+	}
+	h := Hash(sha256.Sum256(raw.Bytes()))
+	xdrEntries := make([]xdrEntry, len(entries))
+	for i := range entries {
+		xdrEntries[i] = entries[i]
+	}
+	writeCategoryFile(t, arch.backend, BucketPath(h), xdrEntries)
+	return h
+}
+
+func addCheckpointWithBucket(t *testing.T, arch *Archive, chk uint32, bucket Hash) {
+	opts := &CommandOptions{Force: true}
+	var has HistoryArchiveState
+	has.CurrentLedger = chk
+	has.CurrentBuckets[0].Curr = bucket.String()
+	assert.NoError(t, arch.PutCheckpointHAS(chk, has, opts))
+	assert.NoError(t, arch.PutRootHAS(has, opts))
+	for _, cat := range []string{"ledger", "transactions", "results", "scp"} {
+		assert.NoError(t, arch.AddRandomCheckpointFile(cat, chk))
+	}
+}
+
+func TestMirrorVerify(t *testing.T) {
+	defer cleanup()
+	opts := &CommandOptions{Range: Range{Low: 63, High: 127}, Concurrency: 2, Verify: true}
+	metaEntry := xdr.BucketEntry{
+		Type:      xdr.BucketEntryTypeMetaentry,
+		MetaEntry: &xdr.BucketMetadata{LedgerVersion: 15},
+	}
+
+	src := GetTestArchive()
+	valid := addBucket(t, src, metaEntry)
+	addCheckpointWithBucket(t, src, 63, valid)
+	addCheckpointWithBucket(t, src, 127, valid)
+	dst := GetTestArchive()
+	// A corrupted bucket left by a previous copy is replaced.
+	assert.NoError(t, dst.backend.PutFile(BucketPath(valid), ioutil.NopCloser(strings.NewReader("corrupted"))))
+
+	assert.NoError(t, Mirror(src, dst, opts))
+	assert.Equal(t, 0, countMissing(dst, opts))
+	assert.NoError(t, dst.VerifyBucketEntries(valid))
+
+	// Buckets not matching their hash are reported, and not written.
+	src = GetTestArchive()
+	invalid, err := src.AddRandomBucket()
+	assert.NoError(t, err)
+	addCheckpointWithBucket(t, src, 63, invalid)
+	dst = GetTestArchive()
+	assert.EqualError(t, Mirror(src, dst, opts), "1 errors while mirroring")
+	exists, err := dst.BucketExists(invalid)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	opts.Verify = false
+	assert.NoError(t, Mirror(src, GetTestArchive(), opts))
+}
+
+func TestMirrorResume(t *testing.T) {
+	defer cleanup()
+	dir, err := ioutil.TempDir("", "mirror-state")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	opts := testOptions()
+	opts.MirrorStatePath = dir + "/state.json"
+	src := GetRandomPopulatedArchive()
+	assert.NoError(t, Mirror(src, GetTestArchive(), opts))
+
+	data, err := ioutil.ReadFile(opts.MirrorStatePath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"checkpoint_frequency": 64, "mirrored": [[63, 959]]}`, string(data))
+
+	// The mirrored checkpoints are skipped.
+	dst := GetTestArchive()
+	assert.NoError(t, Mirror(src, dst, opts))
+	assert.NotEqual(t, 0, countMissing(dst, opts))
+
+	opts.Force = true
+	assert.NoError(t, Mirror(src, dst, opts))
+	assert.Equal(t, 0, countMissing(dst, opts))
+
+	assert.NoError(t, ioutil.WriteFile(opts.MirrorStatePath, []byte(`{"checkpoint_frequency": 8}`), 0644))
+	assert.EqualError(
+		t,
+		Mirror(src, dst, opts),
+		"mirror state checkpoint frequency 8 does not match archive checkpoint frequency 64",
+	)
+}
+
+// addVerifiableCheckpoint writes a HAS referencing bucket and a chain of
+// ledger headers for the checkpoint chk, starting from the previous ledger hash
+// prev, with empty transaction sets. It returns the hash of the last ledger.
+func addVerifiableCheckpoint(t *testing.T, arch *Archive, chk uint32, prev, bucket Hash) Hash {
+	opts := &CommandOptions{Force: true}
+	var has HistoryArchiveState
+	has.CurrentLedger = chk
+	has.CurrentBuckets[0].Curr = bucket.String()
+	bucketListHash, err := has.BucketListHash()
+	assert.NoError(t, err)
+
+	var headers []xdrEntry
+	rng := arch.checkpointManager.GetCheckpointRange(chk)
+	for seq := rng.Low; seq <= rng.High; seq++ {
+		header := xdr.LedgerHeader{
+			LedgerSeq:          xdr.Uint32(seq),
+			PreviousLedgerHash: xdr.Hash(prev),
+			ScpValue:           xdr.StellarValue{TxSetHash: xdr.Hash(HashEmptyTxSet(prev))},
+			TxSetResultHash:    xdr.Hash(EmptyXdrArrayHash()),
+		}
+		if seq == chk {
+			header.BucketListHash = bucketListHash
+		}
+		prev, err = HashXdr(&header)
+		assert.NoError(t, err)
+		headers = append(headers, xdr.LedgerHeaderHistoryEntry{Hash: xdr.Hash(prev), Header: header})
+	}
+	writeCategoryFile(t, arch.backend, CategoryCheckpointPath("ledger", chk), headers)
+	writeCategoryFile(t, arch.backend, CategoryCheckpointPath("transactions", chk), nil)
+	writeCategoryFile(t, arch.backend, CategoryCheckpointPath("results", chk), nil)
+	assert.NoError(t, arch.PutCheckpointHAS(chk, has, opts))
+	assert.NoError(t, arch.PutRootHAS(has, opts))
+	return prev
+}
+
+func TestVerify(t *testing.T) {
+	defer cleanup()
+	opts := &CommandOptions{Range: Range{Low: 63, High: 255}, Concurrency: 2, Thorough: true}
+	bucket := xdr.BucketEntry{
+		Type:      xdr.BucketEntryTypeMetaentry,
+		MetaEntry: &xdr.BucketMetadata{LedgerVersion: 15},
+	}
+
+	arch := GetTestArchive()
+	valid := addBucket(t, arch, bucket)
+	prev := addVerifiableCheckpoint(t, arch, 63, Hash{}, valid)
+	addVerifiableCheckpoint(t, arch, 127, prev, valid)
+	report, err := Verify(arch, opts)
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, &VerifyReport{
+		FirstCheckpoint: 63,
+		LastCheckpoint:  127,
+		Checkpoints:     2,
+		Buckets:         1,
+		Gaps:            [][2]uint32{},
+	}, report)
+
+	arch = GetTestArchive()
+	valid = addBucket(t, arch, bucket)
+	addVerifiableCheckpoint(t, arch, 63, Hash{}, valid)
+	// The chain of ledger headers is broken between checkpoints 63 and 127.
+	addVerifiableCheckpoint(t, arch, 127, Hash{1}, valid)
+	writeCategoryFile(t, arch.backend, CategoryCheckpointPath("transactions", 127), []xdrEntry{
+		xdr.TransactionHistoryEntry{LedgerSeq: 100, TxSet: xdr.TransactionSet{PreviousLedgerHash: xdr.Hash{2}}},
+	})
+	// The files of checkpoint 191 are missing, and its HAS references a
+	// missing bucket.
+	missing := Hash{3}
+	var has HistoryArchiveState
+	has.CurrentLedger = 191
+	has.CurrentBuckets[0].Curr = missing.String()
+	assert.NoError(t, arch.PutCheckpointHAS(191, has, &CommandOptions{Force: true}))
+	// The results of checkpoint 255 are corrupted.
+	addVerifiableCheckpoint(t, arch, 255, Hash{}, valid)
+	assert.NoError(t, arch.backend.PutFile(
+		CategoryCheckpointPath("results", 255),
+		ioutil.NopCloser(strings.NewReader("corrupted")),
+	))
+
+	report, err = Verify(arch, opts)
+	assert.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Equal(t, 4, report.Checkpoints)
+	assert.Equal(t, 2, report.Buckets)
+	assert.Equal(t, [][2]uint32{{191, 191}}, report.Gaps)
+	assert.Equal(t, []VerifyProblem{
+		{
+			Kind:   VerifyProblemMissing,
+			Path:   BucketPath(missing),
+			Detail: "bucket is missing",
+		},
+		{
+			Kind:       VerifyProblemInconsistent,
+			Path:       "ledger/00/00/00/ledger-0000007f.xdr.gz",
+			Checkpoint: 127,
+			Ledger:     64,
+			Detail: fmt.Sprintf(
+				"ledger 64 previous ledger hash %s does not match ledger 63 hash %s",
+				Hash{1}, prev,
+			),
+		},
+		{
+			Kind:       VerifyProblemInconsistent,
+			Path:       "transactions/00/00/00/transactions-0000007f.xdr.gz",
+			Checkpoint: 127,
+			Ledger:     100,
+			Detail:     report.Problems[2].Detail,
+		},
+		{
+			Kind:       VerifyProblemMissing,
+			Path:       "ledger/00/00/00/ledger-000000bf.xdr.gz",
+			Checkpoint: 191,
+			Detail:     "ledger file is missing",
+		},
+		{
+			Kind:       VerifyProblemMissing,
+			Path:       "results/00/00/00/results-000000bf.xdr.gz",
+			Checkpoint: 191,
+			Detail:     "results file is missing",
+		},
+		{
+			Kind:       VerifyProblemMissing,
+			Path:       "transactions/00/00/00/transactions-000000bf.xdr.gz",
+			Checkpoint: 191,
+			Detail:     "transactions file is missing",
+		},
+		{
+			Kind:       VerifyProblemCorrupted,
+			Path:       "results/00/00/00/results-000000ff.xdr.gz",
+			Checkpoint: 255,
+			Detail:     "error opening file: unexpected EOF",
+		},
+	}, report.Problems)
+	assert.Contains(t, report.Problems[2].Detail, "ledger 100 transactions hash")
+
+	// Only the requested range is verified.
+	opts.Range = Range{Low: 63, High: 63}
+	report, err = Verify(arch, opts)
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, 1, report.Checkpoints)
+}
@@ -0,0 +1,544 @@
+package historyarchive
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// VerifyProblemKind classifies the problems found by Verify.
+type VerifyProblemKind string
+
+const (
+	// VerifyProblemMissing is reported for files absent from the archive.
+	VerifyProblemMissing VerifyProblemKind = "missing"
+	// VerifyProblemCorrupted is reported for files which cannot be decoded,
+	// or whose content does not match its hash.
+	VerifyProblemCorrupted VerifyProblemKind = "corrupted"
+	// VerifyProblemInconsistent is reported for files which are valid on
+	// their own but contradict other files, e.g. a ledger header whose
+	// previous ledger hash is not the hash of the previous ledger header.
+	VerifyProblemInconsistent VerifyProblemKind = "inconsistent"
+)
+
+// VerifyProblem is a problem found in an archive by Verify.
+type VerifyProblem struct {
+	Kind VerifyProblemKind `json:"kind"`
+	// Path is the path of the file, relative to the root of the archive.
+	Path string `json:"path"`
+	// Checkpoint is the checkpoint of the file, zero for buckets.
+	Checkpoint uint32 `json:"checkpoint,omitempty"`
+	// Ledger is the ledger the problem relates to, if any.
+	Ledger uint32 `json:"ledger,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// VerifyReport is the result of the verification of a range of an archive.
+type VerifyReport struct {
+	FirstCheckpoint uint32 `json:"first_checkpoint"`
+	LastCheckpoint  uint32 `json:"last_checkpoint"`
+	// Checkpoints is the number of checkpoints verified.
+	Checkpoints int `json:"checkpoints"`
+	// Buckets is the number of distinct buckets referenced by the
+	// checkpoints.
+	Buckets int `json:"buckets"`
+	// Gaps are the runs of consecutive checkpoints missing a required file,
+	// as [first, last] pairs.
+	Gaps [][2]uint32 `json:"gaps"`
+	// Problems are sorted by checkpoint, path and ledger.
+	Problems []VerifyProblem `json:"problems"`
+}
+
+// OK returns true if no problem was found.
+func (r *VerifyReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// checkpointBoundary holds the hashes linking the ledger headers of a
+// checkpoint to those of its neighbours.
+type checkpointBoundary struct {
+	first, last        uint32
+	firstPreviousHash  Hash
+	lastHash           Hash
+	lastBucketListHash Hash
+}
+
+type verifier struct {
+	arch *Archive
+	opts *CommandOptions
+
+	mutex      sync.Mutex
+	problems   []VerifyProblem
+	missing    map[uint32]bool
+	buckets    map[Hash]bool
+	boundaries map[uint32]checkpointBoundary
+}
+
+// Verify checks the checkpoints of arch in opts.Range and the buckets they
+// reference, and returns a report of the missing, corrupted and inconsistent
+// files found. It checks that:
+//
+//   - the history archive state (HAS) files of the checkpoints are valid, and
+//     that their bucket list hash matches the header of the checkpoint ledger;
+//   - the ledger headers of each checkpoint match their hash and form an
+//     unbroken chain across checkpoints;
+//   - the transaction sets and results match the hashes of the headers;
+//   - the referenced buckets exist and match their hash. With opts.Thorough
+//     their entries are decoded and re-hashed instead.
+//
+// An error is returned only if the archive cannot be verified, e.g. when its
+// root HAS cannot be read or a file cannot be checked for existence.
+func Verify(arch *Archive, opts *CommandOptions) (*VerifyReport, error) {
+	if opts.Concurrency == 0 {
+		return nil, errors.New("Zero concurrency")
+	}
+	root, err := arch.GetRootHAS()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading root HAS")
+	}
+	rng := opts.Range.clamp(root.Range(), arch.checkpointManager)
+	log.Printf("Verifying checkpoints in range: %s", rng)
+
+	v := &verifier{
+		arch:       arch,
+		opts:       opts,
+		missing:    map[uint32]bool{},
+		buckets:    map[Hash]bool{},
+		boundaries: map[uint32]checkpointBoundary{},
+	}
+
+	req := make(chan uint32)
+	errs := make(chan error, opts.Concurrency)
+	var wg sync.WaitGroup
+	wg.Add(opts.Concurrency)
+	for i := 0; i < opts.Concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for chk := range req {
+				if err := v.verifyCheckpoint(chk); err != nil {
+					errs <- err
+					// Keep draining the requests so that the producer
+					// does not block.
+					for range req {
+					}
+					return
+				}
+			}
+		}()
+	}
+	checkpoints := 0
+	for chk := range rng.GenerateCheckpoints(arch.checkpointManager) {
+		req <- chk
+		checkpoints++
+	}
+	close(req)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	if rng.InRange(root.CurrentLedger) {
+		has, err := arch.GetCheckpointHAS(root.CurrentLedger)
+		if err == nil && has != root {
+			v.addProblem(VerifyProblem{
+				Kind:       VerifyProblemInconsistent,
+				Path:       rootHASPath,
+				Checkpoint: root.CurrentLedger,
+				Detail:     "root HAS does not match the HAS of its checkpoint",
+			})
+		}
+	}
+	v.verifyChain()
+
+	report := &VerifyReport{
+		FirstCheckpoint: rng.Low,
+		LastCheckpoint:  rng.High,
+		Checkpoints:     checkpoints,
+		Buckets:         len(v.buckets),
+		Gaps:            v.gaps(),
+		Problems:        v.problems,
+	}
+	sort.Slice(report.Problems, func(i, j int) bool {
+		a, b := report.Problems[i], report.Problems[j]
+		if a.Checkpoint != b.Checkpoint {
+			return a.Checkpoint < b.Checkpoint
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Ledger < b.Ledger
+	})
+	log.Printf("Verified %d checkpoints and %d buckets, found %d problems",
+		report.Checkpoints, report.Buckets, len(report.Problems))
+	return report, nil
+}
+
+func (v *verifier) addProblem(p VerifyProblem) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.problems = append(v.problems, p)
+	if p.Kind == VerifyProblemMissing && p.Checkpoint != 0 {
+		v.missing[p.Checkpoint] = true
+	}
+}
+
+// checkpointFileExists reports whether the file of category cat exists for
+// checkpoint chk, and reports it missing if it is required.
+func (v *verifier) checkpointFileExists(cat string, chk uint32) (bool, error) {
+	exists, err := v.arch.CategoryCheckpointExists(cat, chk)
+	if err != nil {
+		return false, errors.Wrapf(err, "checking %s file of checkpoint %d", cat, chk)
+	}
+	if !exists && categoryRequired(cat) {
+		v.addProblem(VerifyProblem{
+			Kind:       VerifyProblemMissing,
+			Path:       CategoryCheckpointPath(cat, chk),
+			Checkpoint: chk,
+			Detail:     fmt.Sprintf("%s file is missing", cat),
+		})
+	}
+	return exists, nil
+}
+
+func (v *verifier) verifyCheckpoint(chk uint32) error {
+	headers, err := v.verifyLedgerHeaders(chk)
+	if err != nil {
+		return err
+	}
+	if err = v.verifyHAS(chk, headers); err != nil {
+		return err
+	}
+	if len(headers) == 0 {
+		// The transactions and results cannot be checked without the
+		// headers.
+		_, err = v.checkpointFileExists("transactions", chk)
+		if err == nil {
+			_, err = v.checkpointFileExists("results", chk)
+		}
+		return err
+	}
+	if err = v.verifyTransactions(chk, headers); err != nil {
+		return err
+	}
+	return v.verifyResults(chk, headers)
+}
+
+// verifyLedgerHeaders returns the headers of checkpoint chk by ledger
+// sequence, after checking their hashes and chain. The headers are not
+// returned if they are missing or corrupted.
+func (v *verifier) verifyLedgerHeaders(chk uint32) (map[uint32]xdr.LedgerHeader, error) {
+	exists, err := v.checkpointFileExists("ledger", chk)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	pth := CategoryCheckpointPath("ledger", chk)
+	corrupted := func(ledger uint32, format string, args ...interface{}) (map[uint32]xdr.LedgerHeader, error) {
+		v.addProblem(VerifyProblem{
+			Kind:       VerifyProblemCorrupted,
+			Path:       pth,
+			Checkpoint: chk,
+			Ledger:     ledger,
+			Detail:     fmt.Sprintf(format, args...),
+		})
+		return nil, nil
+	}
+
+	rdr, err := v.arch.GetXdrStream(pth)
+	if err != nil {
+		return corrupted(0, "error opening file: %v", err)
+	}
+	defer rdr.Close()
+
+	expected := v.arch.checkpointManager.GetCheckpointRange(chk)
+	headers := map[uint32]xdr.LedgerHeader{}
+	var boundary checkpointBoundary
+	for seq := expected.Low; seq <= expected.High; seq++ {
+		var entry xdr.LedgerHeaderHistoryEntry
+		if err = rdr.ReadOne(&entry); err == io.EOF {
+			return corrupted(seq, "ledger %d is missing", seq)
+		} else if err != nil {
+			return corrupted(seq, "error reading ledger %d: %v", seq, err)
+		}
+		if uint32(entry.Header.LedgerSeq) != seq {
+			return corrupted(seq, "expected ledger %d, got ledger %d", seq, entry.Header.LedgerSeq)
+		}
+		h, ok, err := ledgerHeaderHash(&entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "hashing ledger %d", seq)
+		}
+		if !ok {
+			return corrupted(seq, "ledger %d expected hash %s, got %s", seq, Hash(entry.Hash), h)
+		}
+		if seq == expected.Low {
+			boundary.firstPreviousHash = Hash(entry.Header.PreviousLedgerHash)
+		} else if Hash(entry.Header.PreviousLedgerHash) != boundary.lastHash {
+			v.addProblem(VerifyProblem{
+				Kind:       VerifyProblemInconsistent,
+				Path:       pth,
+				Checkpoint: chk,
+				Ledger:     seq,
+				Detail: fmt.Sprintf("ledger %d previous ledger hash %s does not match ledger %d hash %s",
+					seq, Hash(entry.Header.PreviousLedgerHash), seq-1, boundary.lastHash),
+			})
+		}
+		boundary.lastHash = h
+		boundary.lastBucketListHash = Hash(entry.Header.BucketListHash)
+		headers[seq] = entry.Header
+	}
+	var extra xdr.LedgerHeaderHistoryEntry
+	if err = rdr.ReadOne(&extra); err != io.EOF {
+		return corrupted(0, "unexpected data after ledger %d", expected.High)
+	}
+
+	boundary.first, boundary.last = expected.Low, expected.High
+	v.mutex.Lock()
+	v.boundaries[chk] = boundary
+	v.mutex.Unlock()
+	return headers, nil
+}
+
+// verifyHAS checks the HAS of checkpoint chk, and the buckets it references.
+func (v *verifier) verifyHAS(chk uint32, headers map[uint32]xdr.LedgerHeader) error {
+	exists, err := v.checkpointFileExists("history", chk)
+	if err != nil || !exists {
+		return err
+	}
+
+	pth := CategoryCheckpointPath("history", chk)
+	problem := func(kind VerifyProblemKind, format string, args ...interface{}) {
+		v.addProblem(VerifyProblem{
+			Kind:       kind,
+			Path:       pth,
+			Checkpoint: chk,
+			Detail:     fmt.Sprintf(format, args...),
+		})
+	}
+
+	has, err := v.arch.GetCheckpointHAS(chk)
+	if err != nil {
+		problem(VerifyProblemCorrupted, "error reading HAS: %v", err)
+		return nil
+	}
+	if has.CurrentLedger != chk {
+		problem(VerifyProblemInconsistent, "HAS current ledger %d does not match checkpoint", has.CurrentLedger)
+	}
+	buckets, err := has.Buckets()
+	if err != nil {
+		problem(VerifyProblemCorrupted, "invalid bucket hash: %v", err)
+		return nil
+	}
+	if header, ok := headers[chk]; ok {
+		bucketListHash, err := has.BucketListHash()
+		if err != nil {
+			problem(VerifyProblemCorrupted, "invalid bucket hash: %v", err)
+		} else if Hash(bucketListHash) != Hash(header.BucketListHash) {
+			problem(VerifyProblemInconsistent, "bucket list hash %s does not match ledger %d bucket list hash %s",
+				Hash(bucketListHash), chk, Hash(header.BucketListHash))
+		}
+	}
+
+	for _, bucket := range buckets {
+		if err := v.verifyBucket(bucket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyBucket checks bucket, unless it was already checked.
+func (v *verifier) verifyBucket(bucket Hash) error {
+	v.mutex.Lock()
+	seen := v.buckets[bucket]
+	v.buckets[bucket] = true
+	v.mutex.Unlock()
+	if seen {
+		return nil
+	}
+
+	exists, err := v.arch.BucketExists(bucket)
+	if err != nil {
+		return errors.Wrapf(err, "checking bucket %s", bucket)
+	}
+	if !exists {
+		v.addProblem(VerifyProblem{
+			Kind:   VerifyProblemMissing,
+			Path:   BucketPath(bucket),
+			Detail: "bucket is missing",
+		})
+		return nil
+	}
+
+	if v.opts.Thorough {
+		err = v.arch.VerifyBucketEntries(bucket)
+	} else {
+		err = v.arch.VerifyBucketHash(bucket)
+	}
+	if err != nil {
+		v.addProblem(VerifyProblem{
+			Kind:   VerifyProblemCorrupted,
+			Path:   BucketPath(bucket),
+			Detail: err.Error(),
+		})
+	}
+	return nil
+}
+
+// verifyEntries reads the entries of the file of category cat for checkpoint
+// chk, and reports a problem for the ledgers whose entry hash, as returned by
+// hashEntry, does not match expectedHash. Ledgers without entry must have
+// the hash emptyHash.
+func (v *verifier) verifyEntries(
+	cat string,
+	chk uint32,
+	headers map[uint32]xdr.LedgerHeader,
+	newEntry func() interface{},
+	hashEntry func(entry interface{}) (uint32, Hash, error),
+	expectedHash func(header xdr.LedgerHeader) Hash,
+	emptyHash func(header xdr.LedgerHeader) Hash,
+) error {
+	exists, err := v.checkpointFileExists(cat, chk)
+	if err != nil || !exists {
+		return err
+	}
+
+	pth := CategoryCheckpointPath(cat, chk)
+	problem := func(kind VerifyProblemKind, ledger uint32, format string, args ...interface{}) {
+		v.addProblem(VerifyProblem{
+			Kind:       kind,
+			Path:       pth,
+			Checkpoint: chk,
+			Ledger:     ledger,
+			Detail:     fmt.Sprintf(format, args...),
+		})
+	}
+
+	rdr, err := v.arch.GetXdrStream(pth)
+	if err != nil {
+		problem(VerifyProblemCorrupted, 0, "error opening file: %v", err)
+		return nil
+	}
+	defer rdr.Close()
+
+	hashes := map[uint32]Hash{}
+	for {
+		entry := newEntry()
+		if err = rdr.ReadOne(entry); err == io.EOF {
+			break
+		} else if err != nil {
+			problem(VerifyProblemCorrupted, 0, "error reading entry: %v", err)
+			return nil
+		}
+		seq, h, err := hashEntry(entry)
+		if err != nil {
+			return errors.Wrapf(err, "hashing %s entry", cat)
+		}
+		if _, ok := headers[seq]; !ok {
+			problem(VerifyProblemCorrupted, seq, "ledger %d is not part of the checkpoint", seq)
+			continue
+		}
+		hashes[seq] = h
+	}
+
+	seqs := make([]uint32, 0, len(headers))
+	for seq := range headers {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	for _, seq := range seqs {
+		header := headers[seq]
+		expected := expectedHash(header)
+		actual, ok := hashes[seq]
+		if !ok {
+			if expected == emptyHash(header) {
+				continue
+			}
+			problem(VerifyProblemMissing, seq, "ledger %d has no %s entry", seq, cat)
+		} else if actual != expected {
+			problem(VerifyProblemInconsistent, seq, "ledger %d %s hash %s does not match header hash %s",
+				seq, cat, actual, expected)
+		}
+	}
+	return nil
+}
+
+func (v *verifier) verifyTransactions(chk uint32, headers map[uint32]xdr.LedgerHeader) error {
+	return v.verifyEntries(
+		"transactions", chk, headers,
+		func() interface{} { return &xdr.TransactionHistoryEntry{} },
+		func(entry interface{}) (uint32, Hash, error) {
+			e := entry.(*xdr.TransactionHistoryEntry)
+			h, err := transactionHistoryEntryHash(e)
+			return uint32(e.LedgerSeq), h, err
+		},
+		func(header xdr.LedgerHeader) Hash { return Hash(header.ScpValue.TxSetHash) },
+		func(header xdr.LedgerHeader) Hash { return HashEmptyTxSet(Hash(header.PreviousLedgerHash)) },
+	)
+}
+
+func (v *verifier) verifyResults(chk uint32, headers map[uint32]xdr.LedgerHeader) error {
+	emptyXdrArrayHash := EmptyXdrArrayHash()
+	return v.verifyEntries(
+		"results", chk, headers,
+		func() interface{} { return &xdr.TransactionHistoryResultEntry{} },
+		func(entry interface{}) (uint32, Hash, error) {
+			e := entry.(*xdr.TransactionHistoryResultEntry)
+			h, err := transactionHistoryResultEntryHash(e)
+			return uint32(e.LedgerSeq), h, err
+		},
+		func(header xdr.LedgerHeader) Hash { return Hash(header.TxSetResultHash) },
+		func(header xdr.LedgerHeader) Hash { return emptyXdrArrayHash },
+	)
+}
+
+// verifyChain checks that the ledger headers of consecutive checkpoints are
+// chained.
+func (v *verifier) verifyChain() {
+	checkpoints := make([]uint32, 0, len(v.boundaries))
+	for chk := range v.boundaries {
+		checkpoints = append(checkpoints, chk)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i] < checkpoints[j] })
+
+	for i := 1; i < len(checkpoints); i++ {
+		prev, next := v.boundaries[checkpoints[i-1]], v.boundaries[checkpoints[i]]
+		if prev.last+1 != next.first || next.firstPreviousHash == prev.lastHash {
+			continue
+		}
+		v.addProblem(VerifyProblem{
+			Kind:       VerifyProblemInconsistent,
+			Path:       CategoryCheckpointPath("ledger", checkpoints[i]),
+			Checkpoint: checkpoints[i],
+			Ledger:     next.first,
+			Detail: fmt.Sprintf("ledger %d previous ledger hash %s does not match ledger %d hash %s",
+				next.first, next.firstPreviousHash, prev.last, prev.lastHash),
+		})
+	}
+}
+
+// gaps returns the runs of consecutive checkpoints with missing files.
+func (v *verifier) gaps() [][2]uint32 {
+	checkpoints := make([]uint32, 0, len(v.missing))
+	for chk := range v.missing {
+		checkpoints = append(checkpoints, chk)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i] < checkpoints[j] })
+
+	frequency := v.arch.checkpointManager.GetCheckpointFrequency()
+	gaps := [][2]uint32{}
+	for _, chk := range checkpoints {
+		last := len(gaps) - 1
+		if last >= 0 && gaps[last][1]+frequency == chk {
+			gaps[last][1] = chk
+		} else {
+			gaps = append(gaps, [2]uint32{chk, chk})
+		}
+	}
+	return gaps
+}
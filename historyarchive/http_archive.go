@@ -127,3 +127,15 @@ func makeHttpBackend(base *url.URL, opts ConnectOptions) ArchiveBackend {
 		base: *base,
 	}
 }
+
+// makeGCSBackend makes a backend reading the objects of a Google Cloud Storage
+// bucket through its public HTTPS endpoint. Private buckets can be read with
+// the S3 backend, using storage.googleapis.com as S3Endpoint and HMAC keys as
+// AWS credentials.
+func makeGCSBackend(bucket string, prefix string, opts ConnectOptions) ArchiveBackend {
+	return makeHttpBackend(&url.URL{
+		Scheme: "https",
+		Host:   "storage.googleapis.com",
+		Path:   path.Join("/", bucket, prefix),
+	}, opts)
+}
@@ -9,27 +9,65 @@ import (
 	"log"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/stellar/go/support/errors"
 )
 
-// Mirror mirrors an archive, it assumes that the source and destination have the same checkpoint ledger frequency
+// mirrorStateSaveInterval is the interval between two saves of the mirror
+// state file.
+const mirrorStateSaveInterval = 10 * time.Second
+
+// bucketCopy is the copy of a bucket by one of the workers of Mirror.
+type bucketCopy struct {
+	done chan struct{}
+	err  error
+}
+
+// Mirror mirrors an archive, it assumes that the source and destination have the same checkpoint ledger frequency.
+//
+// If opts.Verify is set, buckets are verified at the XDR level while they are
+// copied, see copyBucket. If opts.MirrorStatePath is set, the checkpoints whose
+// files were all copied are recorded in that file, and skipped by the next
+// Mirror calls with the same file unless opts.Force is set.
 func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 	rootHAS, e := src.GetRootHAS()
 	if e != nil {
 		return e
 	}
 
+	var state *mirrorState
+	if opts.MirrorStatePath != "" && !opts.DryRun {
+		state, e = loadMirrorState(opts.MirrorStatePath, src.checkpointManager)
+		if e != nil {
+			return e
+		}
+		stopSaving := make(chan struct{})
+		defer close(stopSaving)
+		go func() {
+			ticker := time.NewTicker(mirrorStateSaveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					noteError(state.save())
+				case <-stopSaving:
+					return
+				}
+			}
+		}()
+	}
+
 	opts.Range = opts.Range.clamp(rootHAS.Range(), src.checkpointManager)
 
 	log.Printf("copying range %s\n", opts.Range)
 
 	// Make a bucket-fetch map that shows which buckets are
 	// already-being-fetched
-	bucketFetch := make(map[Hash]bool)
+	bucketFetch := make(map[Hash]*bucketCopy)
 	var bucketFetchMutex sync.Mutex
 
-	var errs uint32
+	var errs, skipped uint32
 	tick := makeTicker(func(ticks uint) {
 		bucketFetchMutex.Lock()
 		sz := opts.Range.SizeInCheckPoints(src.checkpointManager)
@@ -50,6 +88,12 @@ func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 				if !ok {
 					break
 				}
+				if state != nil && !opts.Force && state.isMirrored(ix) {
+					atomic.AddUint32(&skipped, 1)
+					tick <- true
+					continue
+				}
+
 				has, err := src.GetCheckpointHAS(ix)
 				if err != nil {
 					atomic.AddUint32(&errs, noteError(err))
@@ -61,18 +105,26 @@ func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 					panic(errors.Wrap(err, "error getting buckets"))
 				}
 
+				var chkErrs uint32
 				for _, bucket := range buckets {
-					alreadyFetching := false
 					bucketFetchMutex.Lock()
-					_, alreadyFetching = bucketFetch[bucket]
+					fetch, alreadyFetching := bucketFetch[bucket]
 					if !alreadyFetching {
-						bucketFetch[bucket] = true
+						fetch = &bucketCopy{done: make(chan struct{})}
+						bucketFetch[bucket] = fetch
 					}
 					bucketFetchMutex.Unlock()
 					if !alreadyFetching {
-						pth := BucketPath(bucket)
-						err = copyPath(src, dst, pth, opts)
-						atomic.AddUint32(&errs, noteError(err))
+						fetch.err = copyBucket(src, dst, bucket, opts)
+						close(fetch.done)
+						chkErrs += noteError(fetch.err)
+					} else {
+						// The checkpoint is only mirrored once the buckets
+						// copied by other workers are.
+						<-fetch.done
+						if fetch.err != nil {
+							chkErrs++
+						}
 					}
 				}
 
@@ -82,8 +134,12 @@ func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 					if err != nil && !categoryRequired(cat) {
 						continue
 					}
-					atomic.AddUint32(&errs, noteError(err))
+					chkErrs += noteError(err)
 				}
+				if chkErrs == 0 && state != nil {
+					state.markMirrored(ix)
+				}
+				atomic.AddUint32(&errs, chkErrs)
 				tick <- true
 			}
 			wg.Done()
@@ -91,9 +147,12 @@ func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 	}
 
 	wg.Wait()
-	log.Printf("copied %d checkpoints, %d buckets, range %s",
-		opts.Range.SizeInCheckPoints(src.checkpointManager), len(bucketFetch), opts.Range)
+	log.Printf("copied %d checkpoints (%d already mirrored), %d buckets, range %s",
+		opts.Range.SizeInCheckPoints(src.checkpointManager), skipped, len(bucketFetch), opts.Range)
 	close(tick)
+	if state != nil {
+		errs += noteError(state.save())
+	}
 	if rootHAS.CurrentLedger == opts.Range.High {
 		log.Printf("updating destination archive current-ledger pointer to 0x%8.8x",
 			rootHAS.CurrentLedger)
@@ -0,0 +1,119 @@
+package historyarchive
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// mirrorState records the checkpoints mirrored by Mirror in a local file, so
+// that an interrupted mirroring can be resumed without copying them again.
+type mirrorState struct {
+	path      string
+	frequency uint32
+
+	mutex    sync.Mutex
+	mirrored map[uint32]bool
+}
+
+type mirrorStateFile struct {
+	CheckpointFrequency uint32 `json:"checkpoint_frequency"`
+	// Mirrored are the runs of consecutive mirrored checkpoints, as
+	// [first, last] pairs.
+	Mirrored [][2]uint32 `json:"mirrored"`
+}
+
+// loadMirrorState reads the mirror state file at path. The state is empty if
+// the file does not exist yet.
+func loadMirrorState(path string, manager CheckpointManager) (*mirrorState, error) {
+	state := &mirrorState{
+		path:      path,
+		frequency: manager.GetCheckpointFrequency(),
+		mirrored:  map[uint32]bool{},
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "error reading mirror state")
+	}
+
+	var file mirrorStateFile
+	if err = json.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrap(err, "error decoding mirror state")
+	}
+	if file.CheckpointFrequency != state.frequency {
+		return nil, errors.Errorf(
+			"mirror state checkpoint frequency %d does not match archive checkpoint frequency %d",
+			file.CheckpointFrequency,
+			state.frequency,
+		)
+	}
+	for _, run := range file.Mirrored {
+		for chk := run[0]; chk <= run[1]; chk += state.frequency {
+			state.mirrored[chk] = true
+			if chk+state.frequency < chk {
+				break
+			}
+		}
+	}
+	return state, nil
+}
+
+func (s *mirrorState) isMirrored(chk uint32) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.mirrored[chk]
+}
+
+func (s *mirrorState) markMirrored(chk uint32) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.mirrored[chk] = true
+}
+
+// save writes the state to its file. The file is replaced atomically, so that
+// it is not corrupted if the process is interrupted.
+func (s *mirrorState) save() error {
+	s.mutex.Lock()
+	checkpoints := make([]uint32, 0, len(s.mirrored))
+	for chk := range s.mirrored {
+		checkpoints = append(checkpoints, chk)
+	}
+	s.mutex.Unlock()
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i] < checkpoints[j] })
+
+	file := mirrorStateFile{CheckpointFrequency: s.frequency, Mirrored: [][2]uint32{}}
+	for _, chk := range checkpoints {
+		last := len(file.Mirrored) - 1
+		if last >= 0 && file.Mirrored[last][1]+s.frequency == chk {
+			file.Mirrored[last][1] = chk
+		} else {
+			file.Mirrored = append(file.Mirrored, [2]uint32{chk, chk})
+		}
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return errors.Wrap(err, "error encoding mirror state")
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "error creating mirror state file")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "error writing mirror state file")
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "error writing mirror state file")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), s.path), "error replacing mirror state file")
+}
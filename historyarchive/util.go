@@ -8,7 +8,9 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"os"
 	"path"
 )
 
@@ -55,6 +57,65 @@ func copyPath(src *Archive, dst *Archive, pth string, opts *CommandOptions) erro
 	return err
 }
 
+// copyBucket copies a bucket like copyPath. If opts.Verify is set, the bucket
+// is decoded while it is copied and its hash is checked at the XDR level, like
+// VerifyBucketEntries does, and an existing bucket of dst is only skipped if
+// it is valid.
+func copyBucket(src *Archive, dst *Archive, bucket Hash, opts *CommandOptions) error {
+	pth := BucketPath(bucket)
+	if !opts.Verify || opts.DryRun {
+		return copyPath(src, dst, pth, opts)
+	}
+	exists, err := dst.backend.Exists(pth)
+	if err != nil {
+		return err
+	}
+	if exists && !opts.Force {
+		// The bucket may have been partially written by an interrupted copy.
+		if err = dst.VerifyBucketEntries(bucket); err == nil {
+			log.Printf("skipping existing " + pth)
+			return nil
+		}
+		log.Printf("replacing invalid existing %s: %v", pth, err)
+	}
+
+	rdr, err := src.backend.GetFile(pth)
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+
+	// The bucket is spooled to a temporary file and verified before being
+	// written, so that an invalid bucket never reaches dst, whose backend
+	// cannot delete files.
+	tmp, err := ioutil.TempFile("", "bucket-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if _, err = io.Copy(tmp, rdr); err != nil {
+		return err
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	// The stream must not close tmp, which is written to dst below.
+	stream, err := NewXdrGzStream(ioutil.NopCloser(bufio.NewReader(tmp)))
+	if err == nil {
+		err = verifyBucketEntries(stream, bucket)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid bucket %s: %v", pth, err)
+	}
+
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return dst.backend.PutFile(pth, ioutil.NopCloser(bufio.NewReader(tmp)))
+}
+
 func Categories() []string {
 	return []string{"history", "ledger", "transactions", "results", "scp"}
 }
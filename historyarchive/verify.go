@@ -84,12 +84,34 @@ func HashEmptyTxSet(previousLedgerHash Hash) Hash {
 	return Hash(sha256.Sum256(previousLedgerHash[:]))
 }
 
-func (arch *Archive) VerifyLedgerHeaderHistoryEntry(entry *xdr.LedgerHeaderHistoryEntry) error {
+// ledgerHeaderHash returns the hash of the header of entry, and whether it
+// matches the hash recorded in entry.
+func ledgerHeaderHash(entry *xdr.LedgerHeaderHistoryEntry) (Hash, bool, error) {
 	h, err := HashXdr(&entry.Header)
+	if err != nil {
+		return h, false, err
+	}
+	return h, h == Hash(entry.Hash), nil
+}
+
+// transactionHistoryEntryHash returns the hash of the transaction set of
+// entry, which the header of its ledger records.
+func transactionHistoryEntryHash(entry *xdr.TransactionHistoryEntry) (Hash, error) {
+	return HashTxSet(&entry.TxSet)
+}
+
+// transactionHistoryResultEntryHash returns the hash of the transaction
+// result set of entry, which the header of its ledger records.
+func transactionHistoryResultEntryHash(entry *xdr.TransactionHistoryResultEntry) (Hash, error) {
+	return HashXdr(&entry.TxResultSet)
+}
+
+func (arch *Archive) VerifyLedgerHeaderHistoryEntry(entry *xdr.LedgerHeaderHistoryEntry) error {
+	h, ok, err := ledgerHeaderHash(entry)
 	if err != nil {
 		return err
 	}
-	if h != Hash(entry.Hash) {
+	if !ok {
 		return fmt.Errorf("Ledger %d expected hash %s, got %s",
 			entry.Header.LedgerSeq, Hash(entry.Hash), Hash(h))
 	}
@@ -105,7 +127,7 @@ func (arch *Archive) VerifyLedgerHeaderHistoryEntry(entry *xdr.LedgerHeaderHisto
 }
 
 func (arch *Archive) VerifyTransactionHistoryEntry(entry *xdr.TransactionHistoryEntry) error {
-	h, err := HashTxSet(&entry.TxSet)
+	h, err := transactionHistoryEntryHash(entry)
 	if err != nil {
 		return err
 	}
@@ -116,7 +138,7 @@ func (arch *Archive) VerifyTransactionHistoryEntry(entry *xdr.TransactionHistory
 }
 
 func (arch *Archive) VerifyTransactionHistoryResultEntry(entry *xdr.TransactionHistoryResultEntry) error {
-	h, err := HashXdr(&entry.TxResultSet)
+	h, err := transactionHistoryResultEntryHash(entry)
 	if err != nil {
 		return err
 	}
@@ -222,11 +244,17 @@ func (arch *Archive) VerifyBucketEntries(h Hash) error {
 	if err != nil {
 		return err
 	}
+	return verifyBucketEntries(rdr, h)
+}
+
+// verifyBucketEntries decodes the entries of the bucket read from rdr, and
+// checks that the hash of their re-encoding is h. It closes rdr.
+func verifyBucketEntries(rdr *XdrStream, h Hash) error {
 	defer rdr.Close()
 	hsh := sha256.New()
 	for {
 		var entry xdr.BucketEntry
-		err = rdr.ReadOne(&entry)
+		err := rdr.ReadOne(&entry)
 		if err == nil {
 			err2 := xdr.MarshalFramed(hsh, &entry)
 			if err2 != nil {
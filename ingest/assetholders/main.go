@@ -0,0 +1,298 @@
+// Package assetholders maintains an in-memory index of the holders of each
+// asset, i.e. the accounts with a trust line to it, and their balances. The
+// index is built from the ledger entry changes of the ingest package and
+// updated incrementally as ledgers close, so that issuers can compute the
+// distribution of their assets without running Horizon and its database.
+//
+// Native balances are not indexed: every account holds lumens.
+package assetholders
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// Holder is an account holding an asset.
+type Holder struct {
+	AccountID string
+	Balance   xdr.Int64
+	Limit     xdr.Int64
+	Flags     xdr.TrustLineFlags
+}
+
+// Stats are the distribution statistics of an asset.
+type Stats struct {
+	// Holders is the number of accounts with a trust line to the asset.
+	Holders int
+	// FundedHolders is the number of accounts with a positive balance.
+	FundedHolders int
+	// AuthorizedHolders is the number of accounts authorized to hold the
+	// asset.
+	AuthorizedHolders int
+	// TotalBalance is the sum of the balances of the holders. It may not fit
+	// in an int64.
+	TotalBalance *big.Int
+}
+
+type assetHolders struct {
+	asset    xdr.Asset
+	accounts map[string]Holder
+}
+
+// Index maps assets to their holders. It is safe for concurrent use, so it can
+// be queried while changes are applied. Use NewIndex to create a new
+// instance.
+type Index struct {
+	mutex  sync.RWMutex
+	ledger uint32
+	assets map[string]*assetHolders
+}
+
+// NewIndex returns a new empty Index.
+func NewIndex() *Index {
+	return &Index{assets: map[string]*assetHolders{}}
+}
+
+// Ledger returns the sequence of the last ledger applied to the index, or 0 if
+// no ledger was applied.
+func (i *Index) Ledger() uint32 {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return i.ledger
+}
+
+// ProcessChange updates the index with a ledger entry change. Changes to
+// entries other than trust lines are ignored.
+func (i *Index) ProcessChange(ctx context.Context, change ingest.Change) error {
+	if change.Type != xdr.LedgerEntryTypeTrustline {
+		return nil
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if change.Post == nil {
+		i.remove(change.Pre.Data.MustTrustLine())
+	} else {
+		i.set(change.Post.Data.MustTrustLine())
+	}
+	return nil
+}
+
+// ApplyChanges reads all the changes of reader, which are the changes of the
+// ledger with the given sequence, and updates the index with them. The index
+// can be built from the state of a checkpoint, read with a
+// ingest.CheckpointChangeReader, and then kept up to date with the changes of
+// each following ledger, read with ingest.LedgerChangeReaders.
+//
+// Once the index is built, the ledgers must be applied in sequence order. If
+// an error is returned the index is left partially updated and must be
+// rebuilt.
+func (i *Index) ApplyChanges(ctx context.Context, reader ingest.ChangeReader, sequence uint32) error {
+	if ledger := i.Ledger(); ledger != 0 && sequence != ledger+1 {
+		return errors.Errorf("expected ledger %d, got ledger %d", ledger+1, sequence)
+	}
+
+	for {
+		change, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrap(err, "error reading change")
+		}
+		if err = i.ProcessChange(ctx, change); err != nil {
+			return errors.Wrap(err, "error processing change")
+		}
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	i.ledger = sequence
+	return nil
+}
+
+func (i *Index) set(trustLine xdr.TrustLineEntry) {
+	key := trustLine.Asset.String()
+	holders, ok := i.assets[key]
+	if !ok {
+		holders = &assetHolders{asset: trustLine.Asset, accounts: map[string]Holder{}}
+		i.assets[key] = holders
+	}
+
+	accountID := trustLine.AccountId.Address()
+	holders.accounts[accountID] = Holder{
+		AccountID: accountID,
+		Balance:   trustLine.Balance,
+		Limit:     trustLine.Limit,
+		Flags:     xdr.TrustLineFlags(trustLine.Flags),
+	}
+}
+
+func (i *Index) remove(trustLine xdr.TrustLineEntry) {
+	key := trustLine.Asset.String()
+	holders, ok := i.assets[key]
+	if !ok {
+		return
+	}
+
+	delete(holders.accounts, trustLine.AccountId.Address())
+	if len(holders.accounts) == 0 {
+		delete(i.assets, key)
+	}
+}
+
+// Assets returns the assets held by at least one account.
+func (i *Index) Assets() []xdr.Asset {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	keys := i.sortedAssetKeys()
+	assets := make([]xdr.Asset, len(keys))
+	for j, key := range keys {
+		assets[j] = i.assets[key].asset
+	}
+	return assets
+}
+
+// Holders returns the holders of asset, by decreasing balance.
+func (i *Index) Holders(asset xdr.Asset) []Holder {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	holders, ok := i.assets[asset.String()]
+	if !ok {
+		return nil
+	}
+	result := make([]Holder, 0, len(holders.accounts))
+	for _, holder := range holders.accounts {
+		result = append(result, holder)
+	}
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Balance != result[b].Balance {
+			return result[a].Balance > result[b].Balance
+		}
+		return result[a].AccountID < result[b].AccountID
+	})
+	return result
+}
+
+// Holder returns the holder of asset with the given account id, if the
+// account has a trust line to it.
+func (i *Index) Holder(asset xdr.Asset, accountID string) (Holder, bool) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	holders, ok := i.assets[asset.String()]
+	if !ok {
+		return Holder{}, false
+	}
+	holder, ok := holders.accounts[accountID]
+	return holder, ok
+}
+
+// Stats returns the distribution statistics of asset.
+func (i *Index) Stats(asset xdr.Asset) Stats {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	stats := Stats{TotalBalance: big.NewInt(0)}
+	holders, ok := i.assets[asset.String()]
+	if !ok {
+		return stats
+	}
+	for _, holder := range holders.accounts {
+		stats.Holders++
+		if holder.Balance > 0 {
+			stats.FundedHolders++
+		}
+		if holder.Flags.IsAuthorized() {
+			stats.AuthorizedHolders++
+		}
+		stats.TotalBalance.Add(stats.TotalBalance, big.NewInt(int64(holder.Balance)))
+	}
+	return stats
+}
+
+func (i *Index) sortedAssetKeys() []string {
+	keys := make([]string, 0, len(i.assets))
+	for key := range i.assets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WriteSnapshot writes the index to w, so that it can be restored with
+// ReadSnapshot instead of being rebuilt from a checkpoint. The snapshot is a
+// stream of framed XDR values: the ledger sequence of the index, the number
+// of trust lines, and the xdr.TrustLineEntry of each of them.
+func (i *Index) WriteSnapshot(w io.Writer) error {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	buffered := bufio.NewWriter(w)
+	count := 0
+	for _, holders := range i.assets {
+		count += len(holders.accounts)
+	}
+	if err := xdr.MarshalFramed(buffered, xdr.Uint32(i.ledger)); err != nil {
+		return errors.Wrap(err, "error writing ledger sequence")
+	}
+	if err := xdr.MarshalFramed(buffered, xdr.Uint32(count)); err != nil {
+		return errors.Wrap(err, "error writing trust line count")
+	}
+
+	for _, key := range i.sortedAssetKeys() {
+		holders := i.assets[key]
+		accountIDs := make([]string, 0, len(holders.accounts))
+		for accountID := range holders.accounts {
+			accountIDs = append(accountIDs, accountID)
+		}
+		sort.Strings(accountIDs)
+
+		for _, accountID := range accountIDs {
+			holder := holders.accounts[accountID]
+			trustLine := xdr.TrustLineEntry{
+				AccountId: xdr.MustAddress(holder.AccountID),
+				Asset:     holders.asset,
+				Balance:   holder.Balance,
+				Limit:     holder.Limit,
+				Flags:     xdr.Uint32(holder.Flags),
+			}
+			if err := xdr.MarshalFramed(buffered, trustLine); err != nil {
+				return errors.Wrap(err, "error writing trust line")
+			}
+		}
+	}
+	return buffered.Flush()
+}
+
+// ReadSnapshot returns the index written to r by WriteSnapshot.
+func ReadSnapshot(r io.Reader) (*Index, error) {
+	buffered := bufio.NewReader(r)
+	var ledger, count xdr.Uint32
+	if _, err := xdr.UnmarshalFramed(buffered, &ledger); err != nil {
+		return nil, errors.Wrap(err, "error reading ledger sequence")
+	}
+	if _, err := xdr.UnmarshalFramed(buffered, &count); err != nil {
+		return nil, errors.Wrap(err, "error reading trust line count")
+	}
+
+	index := NewIndex()
+	index.ledger = uint32(ledger)
+	for j := uint32(0); j < uint32(count); j++ {
+		var trustLine xdr.TrustLineEntry
+		if _, err := xdr.UnmarshalFramed(buffered, &trustLine); err != nil {
+			return nil, errors.Wrapf(err, "error reading trust line %d", j)
+		}
+		index.set(trustLine)
+	}
+	return index, nil
+}
@@ -0,0 +1,130 @@
+package assetholders
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	issuer   = "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU"
+	account1 = "GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB"
+	account2 = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+)
+
+var (
+	usd = xdr.MustNewCreditAsset("USD", issuer)
+	eur = xdr.MustNewCreditAsset("EUR", issuer)
+)
+
+func trustLine(account string, asset xdr.Asset, balance xdr.Int64, flags xdr.TrustLineFlags) *xdr.LedgerEntry {
+	return &xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTrustline,
+			TrustLine: &xdr.TrustLineEntry{
+				AccountId: xdr.MustAddress(account),
+				Asset:     asset,
+				Balance:   balance,
+				Limit:     math.MaxInt64,
+				Flags:     xdr.Uint32(flags),
+			},
+		},
+	}
+}
+
+func changeReader(changes ...ingest.Change) *ingest.MockChangeReader {
+	reader := &ingest.MockChangeReader{}
+	for _, change := range changes {
+		reader.On("Read").Return(change, nil).Once()
+	}
+	reader.On("Read").Return(ingest.Change{}, io.EOF).Once()
+	return reader
+}
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+	index := NewIndex()
+	authorized := xdr.TrustLineFlagsAuthorizedFlag
+
+	reader := changeReader(
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Post: trustLine(account1, usd, 100, authorized)},
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Post: trustLine(account2, usd, 0, 0)},
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Post: trustLine(account1, eur, math.MaxInt64, authorized)},
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Post: trustLine(account2, eur, math.MaxInt64, authorized)},
+		ingest.Change{Type: xdr.LedgerEntryTypeAccount},
+	)
+	require.NoError(t, index.ApplyChanges(ctx, reader, 63))
+	reader.AssertExpectations(t)
+	assert.Equal(t, uint32(63), index.Ledger())
+	assert.Equal(t, []xdr.Asset{eur, usd}, index.Assets())
+
+	assert.Equal(t, []Holder{
+		{AccountID: account1, Balance: 100, Limit: math.MaxInt64, Flags: authorized},
+		{AccountID: account2, Balance: 0, Limit: math.MaxInt64},
+	}, index.Holders(usd))
+	stats := index.Stats(usd)
+	assert.Equal(t, 2, stats.Holders)
+	assert.Equal(t, 1, stats.FundedHolders)
+	assert.Equal(t, 1, stats.AuthorizedHolders)
+	assert.Equal(t, "100", stats.TotalBalance.String())
+
+	expectedTotal := new(big.Int).Mul(big.NewInt(math.MaxInt64), big.NewInt(2))
+	assert.Equal(t, expectedTotal.String(), index.Stats(eur).TotalBalance.String())
+
+	err := index.ApplyChanges(ctx, changeReader(), 65)
+	assert.EqualError(t, err, "expected ledger 64, got ledger 65")
+
+	reader = changeReader(
+		ingest.Change{
+			Type: xdr.LedgerEntryTypeTrustline,
+			Pre:  trustLine(account2, usd, 0, 0),
+			Post: trustLine(account2, usd, 50, authorized),
+		},
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Pre: trustLine(account1, eur, 0, authorized)},
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Pre: trustLine(account2, eur, 0, authorized)},
+	)
+	require.NoError(t, index.ApplyChanges(ctx, reader, 64))
+	assert.Equal(t, uint32(64), index.Ledger())
+	assert.Equal(t, []xdr.Asset{usd}, index.Assets())
+	assert.Empty(t, index.Holders(eur))
+	assert.Equal(t, Stats{TotalBalance: big.NewInt(0)}, index.Stats(eur))
+
+	holder, ok := index.Holder(usd, account2)
+	assert.True(t, ok)
+	assert.Equal(t, xdr.Int64(50), holder.Balance)
+	_, ok = index.Holder(eur, account2)
+	assert.False(t, ok)
+	assert.Equal(t, "150", index.Stats(usd).TotalBalance.String())
+}
+
+func TestIndexSnapshot(t *testing.T) {
+	ctx := context.Background()
+	index := NewIndex()
+	reader := changeReader(
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Post: trustLine(account1, usd, 100, xdr.TrustLineFlagsAuthorizedFlag)},
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Post: trustLine(account2, usd, 10, 0)},
+		ingest.Change{Type: xdr.LedgerEntryTypeTrustline, Post: trustLine(account2, eur, 5, 0)},
+	)
+	require.NoError(t, index.ApplyChanges(ctx, reader, 127))
+
+	var snapshot bytes.Buffer
+	require.NoError(t, index.WriteSnapshot(&snapshot))
+	restored, err := ReadSnapshot(bytes.NewReader(snapshot.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(127), restored.Ledger())
+	assert.Equal(t, index.Assets(), restored.Assets())
+	assert.Equal(t, index.Holders(usd), restored.Holders(usd))
+	assert.Equal(t, index.Holders(eur), restored.Holders(eur))
+
+	_, err = ReadSnapshot(bytes.NewReader(snapshot.Bytes()[:snapshot.Len()-4]))
+	assert.Error(t, err)
+}
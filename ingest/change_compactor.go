@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"io"
 	"sync"
 
 	"github.com/stellar/go/support/errors"
@@ -82,6 +83,22 @@ func (c *ChangeCompactor) AddChange(change Change) error {
 	}
 }
 
+// AddChanges adds all the changes read from reader, until io.EOF, to
+// ChangeCompactor. It does not close reader.
+func (c *ChangeCompactor) AddChanges(reader ChangeReader) error {
+	for {
+		change, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "error reading change")
+		}
+		if err = c.AddChange(change); err != nil {
+			return err
+		}
+	}
+}
+
 // addCreatedChange adds a change to the cache, but returns an error if create
 // change is unexpected.
 func (c *ChangeCompactor) addCreatedChange(change Change) error {
@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"io"
 	"testing"
 
 	"github.com/stellar/go/xdr"
@@ -396,3 +397,44 @@ func TestChangeCompactorSquashMultiplePayments(t *testing.T) {
 		}
 	}
 }
+
+func TestChangeCompactorAddChanges(t *testing.T) {
+	account := func(seq xdr.Uint32, balance xdr.Int64) *xdr.LedgerEntry {
+		return &xdr.LedgerEntry{
+			LastModifiedLedgerSeq: seq,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeAccount,
+				Account: &xdr.AccountEntry{
+					AccountId: xdr.MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"),
+					Balance:   balance,
+				},
+			},
+		}
+	}
+
+	reader := &MockChangeReader{}
+	reader.On("Read").Return(Change{Type: xdr.LedgerEntryTypeAccount, Pre: account(10, 1), Post: account(11, 2)}, nil).Once()
+	reader.On("Read").Return(Change{Type: xdr.LedgerEntryTypeAccount, Pre: account(11, 2), Post: account(11, 3)}, nil).Once()
+	reader.On("Read").Return(Change{}, io.EOF).Once()
+
+	cache := NewChangeCompactor()
+	assert.NoError(t, cache.AddChanges(reader))
+	reader.AssertExpectations(t)
+	changes := cache.GetChanges()
+	assert.Len(t, changes, 1)
+	assert.Equal(t, account(10, 1), changes[0].Pre)
+	assert.Equal(t, account(11, 3), changes[0].Post)
+
+	reader = &MockChangeReader{}
+	reader.On("Read").Return(Change{Type: xdr.LedgerEntryTypeAccount, Pre: account(11, 3)}, nil).Once()
+	reader.On("Read").Return(Change{Type: xdr.LedgerEntryTypeAccount, Pre: account(11, 3)}, nil).Once()
+	assert.EqualError(
+		t,
+		cache.AddChanges(reader),
+		"can't remove an entry that was previously removed (ledger key = AAAAAAAAAAC2LgFRDBZ3J52nLm30kq2iMgrO7dYzYAN3hvjtf1IHWg==)",
+	)
+
+	reader = &MockChangeReader{}
+	reader.On("Read").Return(Change{}, io.ErrUnexpectedEOF).Once()
+	assert.EqualError(t, cache.AddChanges(reader), "error reading change: unexpected EOF")
+}
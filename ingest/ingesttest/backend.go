@@ -0,0 +1,81 @@
+package ingesttest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// Ensure Backend implements LedgerBackend
+var _ ledgerbackend.LedgerBackend = (*Backend)(nil)
+
+// Backend is a ledgerbackend.LedgerBackend serving the ledgers of a
+// LedgerGenerator, so that code reading a ledger backend can be tested with
+// simulated ledgers. Ledgers are generated when they are first requested and
+// kept in memory. Use NewBackend to create a new instance.
+type Backend struct {
+	mutex     sync.Mutex
+	generator *LedgerGenerator
+	ledgers   []xdr.LedgerCloseMeta
+	prepared  *ledgerbackend.Range
+}
+
+// NewBackend returns a new Backend serving the ledgers of generator. The
+// generator must not be used directly once the backend is created.
+func NewBackend(generator *LedgerGenerator) *Backend {
+	return &Backend{generator: generator}
+}
+
+// GetLatestLedgerSequence returns the sequence of the latest generated
+// ledger.
+func (b *Backend) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.generator.Sequence(), nil
+}
+
+// GetLedger returns the ledger with the given sequence, generating it and the
+// ledgers preceding it if they were not generated yet.
+func (b *Backend) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if sequence < 2 {
+		return xdr.LedgerCloseMeta{}, errors.Errorf("ledger %d cannot be generated, the first generated ledger is 2", sequence)
+	}
+	for b.generator.Sequence() < sequence {
+		if err := ctx.Err(); err != nil {
+			return xdr.LedgerCloseMeta{}, err
+		}
+		ledger, err := b.generator.Next()
+		if err != nil {
+			return xdr.LedgerCloseMeta{}, errors.Wrapf(err, "could not generate ledger %d", b.generator.Sequence()+1)
+		}
+		b.ledgers = append(b.ledgers, ledger)
+	}
+	return b.ledgers[sequence-2], nil
+}
+
+// PrepareRange records the range as prepared. Ledgers are generated as they
+// are requested.
+func (b *Backend) PrepareRange(ctx context.Context, ledgerRange ledgerbackend.Range) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.prepared = &ledgerRange
+	return nil
+}
+
+// IsPrepared returns true if the range is contained in the prepared range.
+func (b *Backend) IsPrepared(ctx context.Context, ledgerRange ledgerbackend.Range) (bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.prepared != nil && b.prepared.Contains(ledgerRange), nil
+}
+
+// Close does nothing: the generated ledgers stay available.
+func (b *Backend) Close() error {
+	return nil
+}
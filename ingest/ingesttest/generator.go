@@ -0,0 +1,490 @@
+package ingesttest
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/stellar/go/hash"
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// account is a simulated account, which signs the transactions it is the
+// source of.
+type account struct {
+	keypair *keypair.Full
+	id      xdr.AccountId
+	// issued is the asset issued by the account, if it is an issuer.
+	issued *xdr.Asset
+	// offers and data are the ids of the offers and the names of the data
+	// entries of the account, in the order they were created.
+	offers []xdr.Int64
+	data   []xdr.String64
+}
+
+// weightedOperation is an operation type of the OperationMix, with the
+// cumulated weight of the operation types preceding it.
+type weightedOperation struct {
+	typ        xdr.OperationType
+	cumulative int
+}
+
+// transactionPlan describes a transaction to close in a ledger. Its
+// operations are drawn when the transaction is applied unless they are set.
+type transactionPlan struct {
+	source     *account
+	operations []xdr.Operation
+	count      int
+	failed     bool
+}
+
+// LedgerGenerator generates simulated ledgers. Use NewLedgerGenerator to
+// create a new instance. It is not safe for concurrent use.
+type LedgerGenerator struct {
+	config     Config
+	rand       *rand.Rand
+	operations []weightedOperation
+	totalOps   int
+
+	root     *account
+	accounts []*account
+	// byAddress indexes all the accounts, including the ones being created.
+	byAddress map[string]*account
+	assets    []xdr.Asset
+	setup     []func() []transactionPlan
+
+	// entries is the state of the ledger, indexed by the XDR of the key of
+	// each ledger entry.
+	entries map[string]xdr.LedgerEntry
+
+	sequence       uint32
+	closeTime      time.Time
+	header         xdr.LedgerHeader
+	previousHash   xdr.Hash
+	bucketListHash xdr.Hash
+	idPool         uint64
+	dataNames      int
+}
+
+// NewLedgerGenerator returns a LedgerGenerator of the given Config, whose
+// first ledger is the one following the genesis ledger.
+func NewLedgerGenerator(config Config) (*LedgerGenerator, error) {
+	config, err := config.withDefaults()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	g := &LedgerGenerator{
+		config:    config,
+		rand:      rand.New(rand.NewSource(config.Seed)),
+		byAddress: map[string]*account{},
+		entries:   map[string]xdr.LedgerEntry{},
+	}
+
+	types := make([]int, 0, len(config.Operations))
+	for typ := range config.Operations {
+		types = append(types, int(typ))
+	}
+	sort.Ints(types)
+	for _, typ := range types {
+		weight := config.Operations[xdr.OperationType(typ)]
+		if weight == 0 {
+			continue
+		}
+		g.totalOps += weight
+		g.operations = append(g.operations, weightedOperation{
+			typ:        xdr.OperationType(typ),
+			cumulative: g.totalOps,
+		})
+	}
+
+	genesis := ingest.GenesisChange(config.NetworkPassphrase).Post
+	g.root = g.newAccount(keypair.Root(config.NetworkPassphrase))
+	g.store(*genesis)
+	g.sequence = 1
+	g.header = xdr.LedgerHeader{
+		LedgerSeq:    1,
+		TotalCoins:   genesis.Data.Account.Balance,
+		BaseFee:      baseFee,
+		BaseReserve:  100000000,
+		MaxTxSetSize: 100,
+	}
+	if g.previousHash, err = hashXDR(g.header); err != nil {
+		return nil, errors.Wrap(err, "could not hash genesis ledger header")
+	}
+	g.closeTime = config.StartTime.Add(-config.CloseInterval)
+
+	issuers := make([]*account, config.Assets)
+	for i := range issuers {
+		issuers[i] = g.newAccount(g.randomKeypair())
+		asset := xdr.MustNewCreditAsset(fmt.Sprintf("SIM%d", i), issuers[i].keypair.Address())
+		issuers[i].issued = &asset
+		g.assets = append(g.assets, asset)
+	}
+	holders := make([]*account, config.Accounts)
+	for i := range holders {
+		holders[i] = g.newAccount(g.randomKeypair())
+	}
+	g.setup = append(g.setup, func() []transactionPlan {
+		var ops []xdr.Operation
+		for _, a := range append(issuers, holders...) {
+			ops = append(ops, operation(xdr.OperationTypeCreateAccount, xdr.CreateAccountOp{
+				Destination:     a.id,
+				StartingBalance: accountBalance,
+			}))
+		}
+		return batch(g.root, ops)
+	})
+	if config.Assets > 0 {
+		g.setup = append(g.setup, func() []transactionPlan {
+			var plans []transactionPlan
+			for _, holder := range holders {
+				var ops []xdr.Operation
+				for _, asset := range g.assets {
+					ops = append(ops, operation(xdr.OperationTypeChangeTrust, xdr.ChangeTrustOp{
+						Line:  asset,
+						Limit: maxLimit,
+					}))
+				}
+				plans = append(plans, batch(holder, ops)...)
+			}
+			return plans
+		}, func() []transactionPlan {
+			var plans []transactionPlan
+			for _, issuer := range issuers {
+				var ops []xdr.Operation
+				for _, holder := range holders {
+					ops = append(ops, operation(xdr.OperationTypePayment, xdr.PaymentOp{
+						Destination: holder.id.ToMuxedAccount(),
+						Asset:       *issuer.issued,
+						Amount:      assetBalance,
+					}))
+				}
+				plans = append(plans, batch(issuer, ops)...)
+			}
+			return plans
+		})
+	}
+
+	return g, nil
+}
+
+// SetupLedgers returns the number of ledgers setting up the simulated network
+// before the ledgers of random transactions.
+func (g *LedgerGenerator) SetupLedgers() int {
+	if g.config.Assets > 0 {
+		return 3
+	}
+	return 1
+}
+
+// NetworkPassphrase returns the passphrase of the simulated network.
+func (g *LedgerGenerator) NetworkPassphrase() string {
+	return g.config.NetworkPassphrase
+}
+
+// Sequence returns the sequence of the last generated ledger, or 1, the
+// sequence of the genesis ledger, if no ledger was generated.
+func (g *LedgerGenerator) Sequence() uint32 {
+	return g.sequence
+}
+
+// Accounts returns the addresses of the accounts of the simulated network,
+// excluding the root account and including the issuers and the accounts
+// created by the generated ledgers.
+func (g *LedgerGenerator) Accounts() []string {
+	addresses := make([]string, len(g.accounts))
+	for i, a := range g.accounts {
+		addresses[i] = a.keypair.Address()
+	}
+	return addresses
+}
+
+// Assets returns the credit assets of the simulated network.
+func (g *LedgerGenerator) Assets() []xdr.Asset {
+	return append([]xdr.Asset{}, g.assets...)
+}
+
+// Entries returns the ledger entries of the state of the simulated network
+// after the last generated ledger, ordered by the XDR of their key. It can be
+// compared to the state built by a processor of the generated ledgers.
+func (g *LedgerGenerator) Entries() []xdr.LedgerEntry {
+	keys := make([]string, 0, len(g.entries))
+	for key := range g.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]xdr.LedgerEntry, len(keys))
+	for i, key := range keys {
+		entries[i] = g.entries[key]
+	}
+	return entries
+}
+
+// Next generates the next ledger.
+func (g *LedgerGenerator) Next() (xdr.LedgerCloseMeta, error) {
+	var plans []transactionPlan
+	if len(g.setup) > 0 {
+		plans = g.setup[0]()
+		g.setup = g.setup[1:]
+	} else {
+		plans = g.randomPlans()
+	}
+	return g.close(plans)
+}
+
+func (g *LedgerGenerator) randomPlans() []transactionPlan {
+	count := g.config.TransactionsPerLedger
+	if count > len(g.accounts) {
+		count = len(g.accounts)
+	}
+
+	plans := make([]transactionPlan, count)
+	for i, j := range g.rand.Perm(len(g.accounts))[:count] {
+		plans[i] = transactionPlan{
+			source: g.accounts[j],
+			count:  1 + g.rand.Intn(g.config.OperationsPerTransaction),
+		}
+		if g.rand.Float64() < g.config.FailedTransactionRate {
+			plans[i].count = 1
+			plans[i].failed = true
+		}
+	}
+	return plans
+}
+
+// close applies the transactions of a ledger the way stellar-core does: the
+// fees of all the transactions are charged before they are applied in order.
+func (g *LedgerGenerator) close(plans []transactionPlan) (xdr.LedgerCloseMeta, error) {
+	g.sequence++
+	g.closeTime = g.closeTime.Add(g.config.CloseInterval)
+
+	fees := make([]xdr.LedgerEntryChanges, len(plans))
+	feePool := g.header.FeePool
+	for i, plan := range plans {
+		fee := xdr.Int64(baseFee * plan.count)
+		fees[i] = g.updateAccount(plan.source, func(entry *xdr.AccountEntry) {
+			entry.Balance -= fee
+		})
+		feePool += fee
+	}
+
+	txSet := xdr.TransactionSet{PreviousLedgerHash: g.previousHash}
+	processing := make([]xdr.TransactionResultMeta, len(plans))
+	results := make([]xdr.TransactionResultPair, len(plans))
+	changeHashes := []byte{}
+	for i, plan := range plans {
+		envelope, resultMeta, err := g.apply(plan)
+		if err != nil {
+			return xdr.LedgerCloseMeta{}, errors.Wrapf(err, "could not apply transaction %d", i+1)
+		}
+		resultMeta.FeeProcessing = fees[i]
+		txSet.Txs = append(txSet.Txs, envelope)
+		processing[i] = resultMeta
+		results[i] = resultMeta.Result
+
+		metaHash, err := hashXDR(resultMeta)
+		if err != nil {
+			return xdr.LedgerCloseMeta{}, err
+		}
+		changeHashes = append(changeHashes, metaHash[:]...)
+	}
+
+	txSetHash, err := hashXDR(txSet)
+	if err != nil {
+		return xdr.LedgerCloseMeta{}, err
+	}
+	resultsHash, err := hashXDR(xdr.TransactionResultSet{Results: results})
+	if err != nil {
+		return xdr.LedgerCloseMeta{}, err
+	}
+	// The state of the simulated network is not kept in buckets: the bucket
+	// list hash chains the hashes of the meta of the transactions instead, so
+	// that it changes with the state.
+	g.bucketListHash = hash.Hash(append(g.bucketListHash[:], changeHashes...))
+
+	g.header = xdr.LedgerHeader{
+		LedgerVersion:      xdr.Uint32(g.config.ProtocolVersion),
+		PreviousLedgerHash: g.previousHash,
+		ScpValue: xdr.StellarValue{
+			TxSetHash: txSetHash,
+			CloseTime: xdr.TimePoint(g.closeTime.Unix()),
+			Ext: xdr.StellarValueExt{
+				V: xdr.StellarValueTypeStellarValueBasic,
+			},
+		},
+		TxSetResultHash: resultsHash,
+		BucketListHash:  g.bucketListHash,
+		LedgerSeq:       xdr.Uint32(g.sequence),
+		TotalCoins:      g.header.TotalCoins,
+		FeePool:         feePool,
+		IdPool:          xdr.Uint64(g.idPool),
+		BaseFee:         baseFee,
+		BaseReserve:     baseReserve,
+		MaxTxSetSize:    1000,
+	}
+	headerHash, err := hashXDR(g.header)
+	if err != nil {
+		return xdr.LedgerCloseMeta{}, err
+	}
+	g.previousHash = headerHash
+
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Hash:   headerHash,
+				Header: g.header,
+			},
+			TxSet:        txSet,
+			TxProcessing: processing,
+		},
+	}, nil
+}
+
+// apply applies a transaction, after its fee was charged, and returns its
+// signed envelope and its result and meta.
+func (g *LedgerGenerator) apply(plan transactionPlan) (xdr.TransactionEnvelope, xdr.TransactionResultMeta, error) {
+	var sequence xdr.SequenceNumber
+	changesBefore := g.updateAccount(plan.source, func(entry *xdr.AccountEntry) {
+		entry.SeqNum++
+		sequence = entry.SeqNum
+	})
+
+	count := plan.count
+	if plan.operations != nil {
+		count = len(plan.operations)
+	}
+	operations := make([]xdr.Operation, count)
+	results := make([]xdr.OperationResult, count)
+	metas := []xdr.OperationMeta{}
+	code := xdr.TransactionResultCodeTxSuccess
+	for i := range operations {
+		if plan.operations != nil {
+			operations[i] = plan.operations[i]
+		} else {
+			operations[i] = g.randomOperation(plan.source)
+		}
+
+		if plan.failed {
+			code = xdr.TransactionResultCodeTxFailed
+			results[i] = failedResult(operations[i].Body.Type)
+			continue
+		}
+		result, changes, err := g.applyOperation(plan.source, operations[i])
+		if err != nil {
+			return xdr.TransactionEnvelope{}, xdr.TransactionResultMeta{}, errors.Wrapf(err, "could not apply operation %d", i)
+		}
+		results[i] = result
+		metas = append(metas, xdr.OperationMeta{Changes: changes})
+	}
+	if plan.failed {
+		// The changes of the operations of failed transactions are rolled
+		// back.
+		metas = []xdr.OperationMeta{}
+	}
+
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: plan.source.id.ToMuxedAccount(),
+				Fee:           xdr.Uint32(baseFee * count),
+				SeqNum:        sequence,
+				Memo:          xdr.Memo{Type: xdr.MemoTypeMemoNone},
+				Operations:    operations,
+			},
+		},
+	}
+	txHash, err := network.HashTransactionInEnvelope(envelope, g.config.NetworkPassphrase)
+	if err != nil {
+		return xdr.TransactionEnvelope{}, xdr.TransactionResultMeta{}, errors.Wrap(err, "could not hash transaction")
+	}
+	signature, err := plan.source.keypair.SignDecorated(txHash[:])
+	if err != nil {
+		return xdr.TransactionEnvelope{}, xdr.TransactionResultMeta{}, errors.Wrap(err, "could not sign transaction")
+	}
+	envelope.V1.Signatures = []xdr.DecoratedSignature{signature}
+
+	return envelope, xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{
+			TransactionHash: txHash,
+			Result: xdr.TransactionResult{
+				FeeCharged: xdr.Int64(baseFee * count),
+				Result: xdr.TransactionResultResult{
+					Code:    code,
+					Results: &results,
+				},
+			},
+		},
+		TxApplyProcessing: xdr.TransactionMeta{
+			V: 2,
+			V2: &xdr.TransactionMetaV2{
+				TxChangesBefore: changesBefore,
+				Operations:      metas,
+				TxChangesAfter:  xdr.LedgerEntryChanges{},
+			},
+		},
+	}, nil
+}
+
+// randomOperation draws an operation of the OperationMix which the source
+// account can submit. It falls back to a BumpSequence operation, which any
+// account can submit, if the drawn operation type has no candidate.
+func (g *LedgerGenerator) randomOperation(source *account) xdr.Operation {
+	weight := g.rand.Intn(g.totalOps)
+	for _, candidate := range g.operations {
+		if weight < candidate.cumulative {
+			if op, ok := operationBuilders[candidate.typ](g, source); ok {
+				return op
+			}
+			break
+		}
+	}
+	op, _ := buildBumpSequence(g, source)
+	return op
+}
+
+func (g *LedgerGenerator) randomKeypair() *keypair.Full {
+	var seed [32]byte
+	g.rand.Read(seed[:])
+	// FromRawSeed cannot fail with a 32 byte seed.
+	kp, _ := keypair.FromRawSeed(seed)
+	return kp
+}
+
+// newAccount indexes an account, which is only added to the accounts of the
+// simulated network once its creation is applied.
+func (g *LedgerGenerator) newAccount(kp *keypair.Full) *account {
+	a := &account{keypair: kp, id: xdr.MustAddress(kp.Address())}
+	g.byAddress[kp.Address()] = a
+	return a
+}
+
+func batch(source *account, ops []xdr.Operation) []transactionPlan {
+	var plans []transactionPlan
+	for len(ops) > 0 {
+		n := len(ops)
+		if n > maxOperations {
+			n = maxOperations
+		}
+		plans = append(plans, transactionPlan{source: source, operations: ops[:n], count: n})
+		ops = ops[n:]
+	}
+	return plans
+}
+
+func hashXDR(v interface{}) (xdr.Hash, error) {
+	var buf bytes.Buffer
+	if _, err := xdr.Marshal(&buf, v); err != nil {
+		return xdr.Hash{}, errors.Wrap(err, "could not marshal xdr")
+	}
+	return hash.Hash(buf.Bytes()), nil
+}
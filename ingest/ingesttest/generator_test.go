@@ -0,0 +1,241 @@
+package ingesttest
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+func generate(t *testing.T, config Config, n int) (*LedgerGenerator, []xdr.LedgerCloseMeta) {
+	generator, err := NewLedgerGenerator(config)
+	require.NoError(t, err)
+	ledgers := make([]xdr.LedgerCloseMeta, n)
+	for i := range ledgers {
+		ledgers[i], err = generator.Next()
+		require.NoError(t, err)
+	}
+	return generator, ledgers
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	s, err := xdr.MarshalBase64(v)
+	require.NoError(t, err)
+	return s
+}
+
+func TestInvalidConfig(t *testing.T) {
+	for _, config := range []Config{
+		{ProtocolVersion: 9},
+		{Accounts: 1},
+		{Assets: -1},
+		{OperationsPerTransaction: 101},
+		{FailedTransactionRate: 2},
+		{Operations: OperationMix{xdr.OperationTypeAccountMerge: 1}},
+		{Operations: OperationMix{xdr.OperationTypePayment: 0}},
+	} {
+		_, err := NewLedgerGenerator(config)
+		assert.Error(t, err)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	config := Config{Seed: 1, Assets: 2, OperationsPerTransaction: 3}
+	_, ledgers := generate(t, config, 20)
+	_, again := generate(t, config, 20)
+	assert.Equal(t, marshal(t, ledgers), marshal(t, again))
+
+	config.Seed = 2
+	_, other := generate(t, config, 20)
+	assert.NotEqual(t, marshal(t, ledgers), marshal(t, other))
+}
+
+func TestLedgersChain(t *testing.T) {
+	config := Config{Assets: 1}
+	generator, ledgers := generate(t, config, 10)
+	assert.Equal(t, uint32(11), generator.Sequence())
+	assert.Equal(t, 3, generator.SetupLedgers())
+
+	for i, ledger := range ledgers {
+		header := ledger.V0.LedgerHeader
+		assert.Equal(t, uint32(i+2), ledger.LedgerSequence())
+		assert.Equal(t, uint32(defaultProtocolVersion), ledger.ProtocolVersion())
+		assert.Equal(t, xdr.Hash(hashOf(t, header.Header)), ledger.LedgerHash())
+		assert.Equal(t, xdr.TimePoint(defaultStartTime.Unix()+5*int64(i)), header.Header.ScpValue.CloseTime)
+		if i > 0 {
+			assert.Equal(t, ledgers[i-1].LedgerHash(), ledger.PreviousLedgerHash())
+			assert.True(t, header.Header.FeePool > ledgers[i-1].V0.LedgerHeader.Header.FeePool)
+		}
+	}
+}
+
+func hashOf(t *testing.T, v interface{}) xdr.Hash {
+	h, err := hashXDR(v)
+	require.NoError(t, err)
+	return h
+}
+
+// TestReplayChanges checks that the changes of the generated ledgers, read
+// with a LedgerChangeReader, apply on the state left by the previous ledgers
+// and build the state of the generator.
+func TestReplayChanges(t *testing.T) {
+	config := Config{
+		Seed:                     3,
+		Assets:                   3,
+		OperationsPerTransaction: 5,
+		FailedTransactionRate:    0.1,
+	}
+	generator, ledgers := generate(t, config, 50)
+
+	genesis := ingest.GenesisChange(network.TestNetworkPassphrase)
+	state := map[string]xdr.LedgerEntry{
+		entryKey(genesis.Post.LedgerKey()): *genesis.Post,
+	}
+	for _, ledger := range ledgers {
+		reader, err := ingest.NewLedgerChangeReaderFromLedgerCloseMeta(network.TestNetworkPassphrase, ledger)
+		require.NoError(t, err)
+		for {
+			change, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			var key xdr.LedgerKey
+			if change.Pre != nil {
+				key = change.Pre.LedgerKey()
+			} else {
+				key = change.Post.LedgerKey()
+			}
+			current, exists := state[entryKey(key)]
+			if change.Pre == nil {
+				assert.False(t, exists)
+			} else {
+				require.True(t, exists)
+				assert.Equal(t, marshal(t, current), marshal(t, *change.Pre))
+			}
+			if change.Post == nil {
+				delete(state, entryKey(key))
+			} else {
+				assert.Equal(t, xdr.Uint32(ledger.LedgerSequence()), change.Post.LastModifiedLedgerSeq)
+				state[entryKey(key)] = *change.Post
+			}
+		}
+	}
+
+	entries := generator.Entries()
+	assert.Len(t, state, len(entries))
+	for _, entry := range entries {
+		assert.Equal(t, marshal(t, entry), marshal(t, state[entryKey(entry.LedgerKey())]))
+	}
+
+	types := map[xdr.LedgerEntryType]int{}
+	for _, entry := range entries {
+		types[entry.Data.Type]++
+	}
+	assert.True(t, types[xdr.LedgerEntryTypeAccount] >= 1+3+defaultAccounts)
+	assert.True(t, types[xdr.LedgerEntryTypeTrustline] >= 3*defaultAccounts)
+	assert.NotZero(t, types[xdr.LedgerEntryTypeOffer])
+	assert.NotZero(t, types[xdr.LedgerEntryTypeData])
+	assert.Len(t, generator.Accounts(), types[xdr.LedgerEntryTypeAccount]-1)
+}
+
+func TestTransactions(t *testing.T) {
+	config := Config{
+		Seed:                  4,
+		Accounts:              5,
+		TransactionsPerLedger: 20,
+		Operations: OperationMix{
+			xdr.OperationTypeManageData:   1,
+			xdr.OperationTypeBumpSequence: 1,
+		},
+		FailedTransactionRate: 1,
+	}
+	generator, ledgers := generate(t, config, 4)
+	assert.Equal(t, 1, generator.SetupLedgers())
+
+	for i, ledger := range ledgers {
+		reader, err := ingest.NewLedgerTransactionReaderFromLedgerCloseMeta(network.TestNetworkPassphrase, ledger)
+		require.NoError(t, err)
+
+		count := 0
+		for {
+			tx, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			count++
+
+			source := tx.Envelope.SourceAccount().ToAccountId()
+			kp := keypair.MustParseAddress(source.Address())
+			require.Len(t, tx.Envelope.Signatures(), 1)
+			assert.NoError(t, kp.Verify(tx.Result.TransactionHash[:], tx.Envelope.Signatures()[0].Signature))
+
+			if i == 0 {
+				// The setup ledger creates the accounts.
+				assert.True(t, tx.Result.Successful())
+				assert.Equal(t, keypair.Root(network.TestNetworkPassphrase).Address(), source.Address())
+				continue
+			}
+			assert.False(t, tx.Result.Successful())
+			require.Len(t, tx.Envelope.Operations(), 1)
+			assert.Contains(t, []xdr.OperationType{
+				xdr.OperationTypeManageData,
+				xdr.OperationTypeBumpSequence,
+			}, tx.Envelope.Operations()[0].Body.Type)
+			assert.Empty(t, tx.UnsafeMeta.V2.Operations)
+			assert.NotEmpty(t, tx.FeeChanges)
+		}
+		if i > 0 {
+			// Transactions are capped to the number of accounts.
+			assert.Equal(t, 5, count)
+		}
+	}
+}
+
+func TestBackend(t *testing.T) {
+	ctx := context.Background()
+	config := Config{Seed: 5, Assets: 1}
+	_, ledgers := generate(t, config, 6)
+
+	generator, err := NewLedgerGenerator(config)
+	require.NoError(t, err)
+	backend := NewBackend(generator)
+
+	prepared, err := backend.IsPrepared(ctx, ledgerbackend.UnboundedRange(2))
+	require.NoError(t, err)
+	assert.False(t, prepared)
+	require.NoError(t, backend.PrepareRange(ctx, ledgerbackend.UnboundedRange(2)))
+	prepared, err = backend.IsPrepared(ctx, ledgerbackend.BoundedRange(3, 10))
+	require.NoError(t, err)
+	assert.True(t, prepared)
+
+	_, err = backend.GetLedger(ctx, 1)
+	assert.EqualError(t, err, "ledger 1 cannot be generated, the first generated ledger is 2")
+
+	ledger, err := backend.GetLedger(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, marshal(t, ledgers[5]), marshal(t, ledger))
+	latest, err := backend.GetLatestLedgerSequence(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(7), latest)
+
+	ledger, err = backend.GetLedger(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, marshal(t, ledgers[1]), marshal(t, ledger))
+
+	reader, err := ingest.NewLedgerChangeReader(ctx, backend, network.TestNetworkPassphrase, 4)
+	require.NoError(t, err)
+	change, err := reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, xdr.LedgerEntryTypeAccount, change.Type)
+}
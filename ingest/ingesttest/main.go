@@ -0,0 +1,184 @@
+// Package ingesttest generates simulated ledgers, so that ingestion processors
+// and downstream indexers can be tested deterministically without captive
+// stellar-core or history archives.
+//
+// A LedgerGenerator starts from the genesis ledger of a network and closes
+// ledgers of random transactions between simulated accounts. The ledgers it
+// returns chain from the genesis ledger, and their transactions are signed and
+// hashed with the network passphrase, so they can be read with the readers of
+// the ingest package: the ledger entry changes of their meta update the state
+// left by the previous ledgers. Generators with the same Config generate the
+// same ledgers.
+//
+// The first ledgers set up the simulated network: the root account creates
+// the accounts and the issuers of the assets, then the accounts trust the
+// assets and the issuers fund them. The following ledgers are made of
+// transactions whose operations are drawn from the OperationMix of the
+// Config.
+//
+// Simulated transactions are simpler than those of stellar-core. Each account
+// is the source of at most one transaction per ledger after the setup ledgers,
+// operations have no source account, offers never cross, and neither reserves
+// nor liabilities are enforced.
+package ingesttest
+
+import (
+	"time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	defaultProtocolVersion       = 15
+	defaultCloseInterval         = 5 * time.Second
+	defaultAccounts              = 10
+	defaultTransactionsPerLedger = 10
+
+	// minProtocolVersion is the first protocol version bumping sequence
+	// numbers in the meta of transactions rather than with their fees.
+	minProtocolVersion = 10
+
+	baseFee     = 100
+	baseReserve = 5000000
+	// maxOperations is the maximum number of operations of a transaction.
+	maxOperations = 100
+)
+
+var (
+	// defaultStartTime is the close time of the first ledger generated with
+	// the default Config.
+	defaultStartTime = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// accountBalance is the native balance of the accounts and issuers
+	// created by the setup ledgers, and assetBalance the balance of each
+	// asset the issuers fund the accounts with.
+	accountBalance = amount.MustParse("10000")
+	assetBalance   = amount.MustParse("1000")
+)
+
+// OperationMix maps operation types to their relative weight in the
+// transactions of the generated ledgers. For example, payments are drawn
+// three times as often as offers in
+//
+//	OperationMix{
+//	    xdr.OperationTypePayment:         3,
+//	    xdr.OperationTypeManageSellOffer: 1,
+//	}
+//
+// The supported operation types are CreateAccount, Payment, ChangeTrust,
+// ManageSellOffer, ManageData and BumpSequence.
+type OperationMix map[xdr.OperationType]int
+
+// DefaultOperationMix is the OperationMix of a Config without one.
+var DefaultOperationMix = OperationMix{
+	xdr.OperationTypeCreateAccount:   1,
+	xdr.OperationTypePayment:         10,
+	xdr.OperationTypeChangeTrust:     1,
+	xdr.OperationTypeManageSellOffer: 4,
+	xdr.OperationTypeManageData:      2,
+	xdr.OperationTypeBumpSequence:    1,
+}
+
+// Config configures a LedgerGenerator. Fields left to their zero value take
+// the default value documented below.
+type Config struct {
+	// NetworkPassphrase is the passphrase of the simulated network. Defaults
+	// to network.TestNetworkPassphrase.
+	NetworkPassphrase string
+	// Seed seeds the random choices of the generator.
+	Seed int64
+	// ProtocolVersion is the protocol version of the ledgers. Defaults to
+	// 15. Versions older than 10 are not supported.
+	ProtocolVersion uint32
+	// StartTime is the close time of the first generated ledger. Defaults to
+	// 2021-01-01 UTC.
+	StartTime time.Time
+	// CloseInterval is the time between two ledgers. Defaults to 5 seconds.
+	CloseInterval time.Duration
+
+	// Accounts is the number of accounts created by the setup ledgers.
+	// Defaults to 10.
+	Accounts int
+	// Assets is the number of credit assets, each issued by its own account.
+	// There are no credit assets by default.
+	Assets int
+
+	// TransactionsPerLedger is the number of transactions of the ledgers
+	// following the setup ledgers, capped to the number of accounts. Defaults
+	// to 10.
+	TransactionsPerLedger int
+	// OperationsPerTransaction is the maximum number of operations of these
+	// transactions, which have between 1 and OperationsPerTransaction
+	// operations. Defaults to 1.
+	OperationsPerTransaction int
+	// Operations is the mix of operations of these transactions. Defaults to
+	// DefaultOperationMix.
+	Operations OperationMix
+	// FailedTransactionRate is the probability that one of these transactions
+	// fails. Failed transactions have a single operation, and only charge
+	// their fee and consume their sequence number.
+	FailedTransactionRate float64
+}
+
+func (c Config) withDefaults() (Config, error) {
+	if c.NetworkPassphrase == "" {
+		c.NetworkPassphrase = network.TestNetworkPassphrase
+	}
+	if c.ProtocolVersion == 0 {
+		c.ProtocolVersion = defaultProtocolVersion
+	}
+	if c.StartTime.IsZero() {
+		c.StartTime = defaultStartTime
+	}
+	if c.CloseInterval == 0 {
+		c.CloseInterval = defaultCloseInterval
+	}
+	if c.Accounts == 0 {
+		c.Accounts = defaultAccounts
+	}
+	if c.TransactionsPerLedger == 0 {
+		c.TransactionsPerLedger = defaultTransactionsPerLedger
+	}
+	if c.OperationsPerTransaction == 0 {
+		c.OperationsPerTransaction = 1
+	}
+	if c.Operations == nil {
+		c.Operations = DefaultOperationMix
+	}
+
+	switch {
+	case c.ProtocolVersion < minProtocolVersion:
+		return c, errors.Errorf("protocol version %d is not supported, the minimum is %d", c.ProtocolVersion, minProtocolVersion)
+	case c.CloseInterval < time.Second:
+		return c, errors.New("close interval must be at least one second")
+	case c.Accounts < 2:
+		return c, errors.New("at least two accounts are required")
+	case c.Assets < 0 || c.Assets > maxOperations:
+		return c, errors.Errorf("number of assets must be between 0 and %d", maxOperations)
+	case c.TransactionsPerLedger < 0:
+		return c, errors.New("number of transactions per ledger cannot be negative")
+	case c.OperationsPerTransaction < 0 || c.OperationsPerTransaction > maxOperations:
+		return c, errors.Errorf("number of operations per transaction must be between 1 and %d", maxOperations)
+	case c.FailedTransactionRate < 0 || c.FailedTransactionRate > 1:
+		return c, errors.New("failed transaction rate must be between 0 and 1")
+	}
+
+	total := 0
+	for typ, weight := range c.Operations {
+		if _, ok := operationBuilders[typ]; !ok {
+			return c, errors.Errorf("operation type %s is not supported", typ)
+		}
+		if weight < 0 {
+			return c, errors.Errorf("weight of operation type %s cannot be negative", typ)
+		}
+		total += weight
+	}
+	if total == 0 {
+		return c, errors.New("operation mix must have a positive weight")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,405 @@
+package ingesttest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+const maxLimit = xdr.Int64(math.MaxInt64)
+
+var (
+	// minBalance is the native balance accounts keep, so that they can pay
+	// the fees of their transactions.
+	minBalance = amount.MustParse("10")
+	// newAccountBalance is the starting balance of the accounts created by
+	// CreateAccount operations.
+	newAccountBalance = amount.MustParse("100")
+	// maxAmount is the maximum amount of payments and offers.
+	maxAmount = amount.MustParse("100")
+	// limitMargin is the margin above their balance of the limits of trust
+	// lines lowered by ChangeTrust operations.
+	limitMargin = amount.MustParse("1000000")
+)
+
+// operationBuilders draw an operation of each supported type which an account
+// can submit, or return false if the account cannot submit any.
+var operationBuilders = map[xdr.OperationType]func(g *LedgerGenerator, source *account) (xdr.Operation, bool){
+	xdr.OperationTypeCreateAccount:   buildCreateAccount,
+	xdr.OperationTypePayment:         buildPayment,
+	xdr.OperationTypeChangeTrust:     buildChangeTrust,
+	xdr.OperationTypeManageSellOffer: buildManageSellOffer,
+	xdr.OperationTypeManageData:      buildManageData,
+	xdr.OperationTypeBumpSequence:    buildBumpSequence,
+}
+
+func operation(typ xdr.OperationType, value interface{}) xdr.Operation {
+	body, err := xdr.NewOperationBody(typ, value)
+	if err != nil {
+		panic(err)
+	}
+	return xdr.Operation{Body: body}
+}
+
+// randomAmount returns a random amount between 1 and max, capped to
+// maxAmount.
+func (g *LedgerGenerator) randomAmount(max xdr.Int64) xdr.Int64 {
+	if max > maxAmount {
+		max = maxAmount
+	}
+	return 1 + xdr.Int64(g.rand.Int63n(int64(max)))
+}
+
+func buildCreateAccount(g *LedgerGenerator, source *account) (xdr.Operation, bool) {
+	if g.sendable(source, xdr.MustNewNativeAsset()) < newAccountBalance {
+		return xdr.Operation{}, false
+	}
+	destination := g.newAccount(g.randomKeypair())
+	return operation(xdr.OperationTypeCreateAccount, xdr.CreateAccountOp{
+		Destination:     destination.id,
+		StartingBalance: newAccountBalance,
+	}), true
+}
+
+func buildPayment(g *LedgerGenerator, source *account) (xdr.Operation, bool) {
+	destination := g.accounts[g.rand.Intn(len(g.accounts))]
+	if destination == source {
+		return xdr.Operation{}, false
+	}
+
+	var assets []xdr.Asset
+	var max []xdr.Int64
+	for _, asset := range append([]xdr.Asset{xdr.MustNewNativeAsset()}, g.assets...) {
+		amount := g.sendable(source, asset)
+		if receivable := g.receivable(destination, asset); receivable < amount {
+			amount = receivable
+		}
+		if amount > 0 {
+			assets = append(assets, asset)
+			max = append(max, amount)
+		}
+	}
+	if len(assets) == 0 {
+		return xdr.Operation{}, false
+	}
+
+	i := g.rand.Intn(len(assets))
+	return operation(xdr.OperationTypePayment, xdr.PaymentOp{
+		Destination: destination.id.ToMuxedAccount(),
+		Asset:       assets[i],
+		Amount:      g.randomAmount(max[i]),
+	}), true
+}
+
+func buildChangeTrust(g *LedgerGenerator, source *account) (xdr.Operation, bool) {
+	if len(g.assets) == 0 {
+		return xdr.Operation{}, false
+	}
+	asset := g.assets[g.rand.Intn(len(g.assets))]
+	if source.issued != nil && source.issued.Equals(asset) {
+		return xdr.Operation{}, false
+	}
+
+	// Limits are never lowered below the balance, nor removed, since
+	// liabilities are not tracked.
+	limit := maxLimit
+	if line, ok := g.trustLine(source, asset); ok && line.Limit == maxLimit {
+		limit = line.Balance + limitMargin
+	}
+	return operation(xdr.OperationTypeChangeTrust, xdr.ChangeTrustOp{
+		Line:  asset,
+		Limit: limit,
+	}), true
+}
+
+func buildManageSellOffer(g *LedgerGenerator, source *account) (xdr.Operation, bool) {
+	if len(source.offers) > 0 && g.rand.Intn(3) == 0 {
+		id := source.offers[g.rand.Intn(len(source.offers))]
+		entry, _ := g.load(offerKey(source, id))
+		offer := entry.Data.Offer
+		return operation(xdr.OperationTypeManageSellOffer, xdr.ManageSellOfferOp{
+			Selling: offer.Selling,
+			Buying:  offer.Buying,
+			Amount:  0,
+			Price:   offer.Price,
+			OfferId: id,
+		}), true
+	}
+
+	assets := g.holdable(source)
+	if len(assets) < 2 {
+		return xdr.Operation{}, false
+	}
+	g.rand.Shuffle(len(assets), func(i, j int) {
+		assets[i], assets[j] = assets[j], assets[i]
+	})
+	selling, buying := assets[0], assets[1]
+	// Offers are only placed on a tenth of the balance, so that the account
+	// can still send the asset.
+	max := g.sendable(source, selling) / 10
+	if max <= 0 {
+		return xdr.Operation{}, false
+	}
+
+	// All the prices are above 1, so offers never cross.
+	d := 1 + g.rand.Int31n(10)
+	return operation(xdr.OperationTypeManageSellOffer, xdr.ManageSellOfferOp{
+		Selling: selling,
+		Buying:  buying,
+		Amount:  g.randomAmount(max),
+		Price:   xdr.Price{N: xdr.Int32(d + 1 + g.rand.Int31n(d)), D: xdr.Int32(d)},
+	}), true
+}
+
+func buildManageData(g *LedgerGenerator, source *account) (xdr.Operation, bool) {
+	var name xdr.String64
+	var value *xdr.DataValue
+	switch {
+	case len(source.data) > 0 && g.rand.Intn(3) == 0:
+		name = source.data[g.rand.Intn(len(source.data))]
+	case len(source.data) > 0 && g.rand.Intn(2) == 0:
+		name = source.data[g.rand.Intn(len(source.data))]
+		value = g.randomDataValue()
+	default:
+		g.dataNames++
+		name = xdr.String64(fmt.Sprintf("sim%d", g.dataNames))
+		value = g.randomDataValue()
+	}
+	return operation(xdr.OperationTypeManageData, xdr.ManageDataOp{
+		DataName:  name,
+		DataValue: value,
+	}), true
+}
+
+func (g *LedgerGenerator) randomDataValue() *xdr.DataValue {
+	value := make(xdr.DataValue, 1+g.rand.Intn(64))
+	g.rand.Read(value)
+	return &value
+}
+
+func buildBumpSequence(g *LedgerGenerator, source *account) (xdr.Operation, bool) {
+	sequence := g.account(source).SeqNum
+	return operation(xdr.OperationTypeBumpSequence, xdr.BumpSequenceOp{
+		BumpTo: sequence + 1 + xdr.SequenceNumber(g.rand.Intn(100)),
+	}), true
+}
+
+// applyOperation applies a successful operation, and returns its result and
+// the changes of its meta.
+func (g *LedgerGenerator) applyOperation(source *account, op xdr.Operation) (xdr.OperationResult, xdr.LedgerEntryChanges, error) {
+	var changes xdr.LedgerEntryChanges
+	var result interface{}
+
+	switch op.Body.Type {
+	case xdr.OperationTypeCreateAccount:
+		body := op.Body.MustCreateAccountOp()
+		created, ok := g.byAddress[body.Destination.Address()]
+		if !ok {
+			return xdr.OperationResult{}, nil, errors.Errorf("unknown account %s", body.Destination.Address())
+		}
+		changes = append(changes, g.updateAccount(source, func(entry *xdr.AccountEntry) {
+			entry.Balance -= body.StartingBalance
+		})...)
+		changes = append(changes, g.create(xdr.LedgerEntry{
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeAccount,
+				Account: &xdr.AccountEntry{
+					AccountId:  body.Destination,
+					Balance:    body.StartingBalance,
+					SeqNum:     xdr.SequenceNumber(uint64(g.sequence) << 32),
+					Thresholds: xdr.Thresholds{1, 0, 0, 0},
+				},
+			},
+		})...)
+		g.accounts = append(g.accounts, created)
+		result = xdr.CreateAccountResult{Code: xdr.CreateAccountResultCodeCreateAccountSuccess}
+
+	case xdr.OperationTypePayment:
+		body := op.Body.MustPaymentOp()
+		destinationID := body.Destination.ToAccountId()
+		destination, ok := g.byAddress[destinationID.Address()]
+		if !ok {
+			return xdr.OperationResult{}, nil, errors.Errorf("unknown account %s", destinationID.Address())
+		}
+		changes = append(changes, g.transfer(source, body.Asset, -body.Amount)...)
+		changes = append(changes, g.transfer(destination, body.Asset, body.Amount)...)
+		result = xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentSuccess}
+
+	case xdr.OperationTypeChangeTrust:
+		body := op.Body.MustChangeTrustOp()
+		if _, ok := g.trustLine(source, body.Line); ok {
+			changes = g.update(trustLineKey(source, body.Line), func(entry *xdr.LedgerEntry) {
+				entry.Data.TrustLine.Limit = body.Limit
+			})
+		} else {
+			changes = append(changes, g.updateAccount(source, func(entry *xdr.AccountEntry) {
+				entry.NumSubEntries++
+			})...)
+			changes = append(changes, g.create(xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{
+					Type: xdr.LedgerEntryTypeTrustline,
+					TrustLine: &xdr.TrustLineEntry{
+						AccountId: source.id,
+						Asset:     body.Line,
+						Limit:     body.Limit,
+						Flags:     xdr.Uint32(xdr.TrustLineFlagsAuthorizedFlag),
+					},
+				},
+			})...)
+		}
+		result = xdr.ChangeTrustResult{Code: xdr.ChangeTrustResultCodeChangeTrustSuccess}
+
+	case xdr.OperationTypeManageSellOffer:
+		body := op.Body.MustManageSellOfferOp()
+		success := xdr.ManageOfferSuccessResult{}
+		if body.OfferId != 0 {
+			changes = append(changes, g.updateAccount(source, func(entry *xdr.AccountEntry) {
+				entry.NumSubEntries--
+			})...)
+			changes = append(changes, g.remove(offerKey(source, body.OfferId))...)
+			source.offers = removeOffer(source.offers, body.OfferId)
+			success.Offer.Effect = xdr.ManageOfferEffectManageOfferDeleted
+		} else {
+			g.idPool++
+			offer := xdr.OfferEntry{
+				SellerId: source.id,
+				OfferId:  xdr.Int64(g.idPool),
+				Selling:  body.Selling,
+				Buying:   body.Buying,
+				Amount:   body.Amount,
+				Price:    body.Price,
+			}
+			changes = append(changes, g.updateAccount(source, func(entry *xdr.AccountEntry) {
+				entry.NumSubEntries++
+			})...)
+			changes = append(changes, g.create(xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{
+					Type:  xdr.LedgerEntryTypeOffer,
+					Offer: &offer,
+				},
+			})...)
+			source.offers = append(source.offers, offer.OfferId)
+			success.Offer.Effect = xdr.ManageOfferEffectManageOfferCreated
+			success.Offer.Offer = &offer
+		}
+		result = xdr.ManageSellOfferResult{
+			Code:    xdr.ManageSellOfferResultCodeManageSellOfferSuccess,
+			Success: &success,
+		}
+
+	case xdr.OperationTypeManageData:
+		body := op.Body.MustManageDataOp()
+		key := dataKey(source, body.DataName)
+		_, exists := g.load(key)
+		switch {
+		case body.DataValue == nil:
+			changes = append(changes, g.updateAccount(source, func(entry *xdr.AccountEntry) {
+				entry.NumSubEntries--
+			})...)
+			changes = append(changes, g.remove(key)...)
+			source.data = removeDataName(source.data, body.DataName)
+		case exists:
+			changes = g.update(key, func(entry *xdr.LedgerEntry) {
+				entry.Data.Data.DataValue = *body.DataValue
+			})
+		default:
+			changes = append(changes, g.updateAccount(source, func(entry *xdr.AccountEntry) {
+				entry.NumSubEntries++
+			})...)
+			changes = append(changes, g.create(xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{
+					Type: xdr.LedgerEntryTypeData,
+					Data: &xdr.DataEntry{
+						AccountId: source.id,
+						DataName:  body.DataName,
+						DataValue: *body.DataValue,
+					},
+				},
+			})...)
+			source.data = append(source.data, body.DataName)
+		}
+		result = xdr.ManageDataResult{Code: xdr.ManageDataResultCodeManageDataSuccess}
+
+	case xdr.OperationTypeBumpSequence:
+		body := op.Body.MustBumpSequenceOp()
+		changes = g.updateAccount(source, func(entry *xdr.AccountEntry) {
+			if body.BumpTo > entry.SeqNum {
+				entry.SeqNum = body.BumpTo
+			}
+		})
+		result = xdr.BumpSequenceResult{Code: xdr.BumpSequenceResultCodeBumpSequenceSuccess}
+
+	default:
+		return xdr.OperationResult{}, nil, errors.Errorf("operation type %s is not supported", op.Body.Type)
+	}
+
+	tr, err := xdr.NewOperationResultTr(op.Body.Type, result)
+	if err != nil {
+		return xdr.OperationResult{}, nil, errors.Wrap(err, "could not build operation result")
+	}
+	return xdr.OperationResult{Code: xdr.OperationResultCodeOpInner, Tr: &tr}, changes, nil
+}
+
+// transfer credits an account with an amount of an asset, or debits it if the
+// amount is negative. Issuers hold an unlimited amount of the asset they
+// issue.
+func (g *LedgerGenerator) transfer(a *account, asset xdr.Asset, amount xdr.Int64) xdr.LedgerEntryChanges {
+	switch {
+	case asset.Type == xdr.AssetTypeAssetTypeNative:
+		return g.updateAccount(a, func(entry *xdr.AccountEntry) {
+			entry.Balance += amount
+		})
+	case a.issued != nil && a.issued.Equals(asset):
+		return nil
+	}
+	return g.update(trustLineKey(a, asset), func(entry *xdr.LedgerEntry) {
+		entry.Data.TrustLine.Balance += amount
+	})
+}
+
+// failedResult returns the result of an operation failing a transaction.
+func failedResult(typ xdr.OperationType) xdr.OperationResult {
+	var result interface{}
+	switch typ {
+	case xdr.OperationTypeCreateAccount:
+		result = xdr.CreateAccountResult{Code: xdr.CreateAccountResultCodeCreateAccountUnderfunded}
+	case xdr.OperationTypePayment:
+		result = xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentUnderfunded}
+	case xdr.OperationTypeChangeTrust:
+		result = xdr.ChangeTrustResult{Code: xdr.ChangeTrustResultCodeChangeTrustLowReserve}
+	case xdr.OperationTypeManageSellOffer:
+		result = xdr.ManageSellOfferResult{Code: xdr.ManageSellOfferResultCodeManageSellOfferUnderfunded}
+	case xdr.OperationTypeManageData:
+		result = xdr.ManageDataResult{Code: xdr.ManageDataResultCodeManageDataLowReserve}
+	case xdr.OperationTypeBumpSequence:
+		result = xdr.BumpSequenceResult{Code: xdr.BumpSequenceResultCodeBumpSequenceBadSeq}
+	default:
+		panic("operation type " + typ.String() + " is not supported")
+	}
+	tr, err := xdr.NewOperationResultTr(typ, result)
+	if err != nil {
+		panic(err)
+	}
+	return xdr.OperationResult{Code: xdr.OperationResultCodeOpInner, Tr: &tr}
+}
+
+func removeOffer(offers []xdr.Int64, id xdr.Int64) []xdr.Int64 {
+	for i := range offers {
+		if offers[i] == id {
+			return append(offers[:i], offers[i+1:]...)
+		}
+	}
+	return offers
+}
+
+func removeDataName(names []xdr.String64, name xdr.String64) []xdr.String64 {
+	for i := range names {
+		if names[i] == name {
+			return append(names[:i], names[i+1:]...)
+		}
+	}
+	return names
+}
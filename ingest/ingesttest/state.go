@@ -0,0 +1,175 @@
+package ingesttest
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// The XDR of the keys and entries of the state is marshalled and unmarshalled
+// without errors unless they are malformed, which would be a bug of the
+// generator: the functions below panic on such errors.
+
+func entryKey(key xdr.LedgerKey) string {
+	s, err := key.MarshalBinaryBase64()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// copyEntry returns a deep copy of a ledger entry, so that the entries of the
+// state are never shared with the returned meta.
+func copyEntry(entry xdr.LedgerEntry) xdr.LedgerEntry {
+	b, err := entry.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	var copied xdr.LedgerEntry
+	if err := copied.UnmarshalBinary(b); err != nil {
+		panic(err)
+	}
+	return copied
+}
+
+func (g *LedgerGenerator) store(entry xdr.LedgerEntry) {
+	g.entries[entryKey(entry.LedgerKey())] = copyEntry(entry)
+}
+
+func (g *LedgerGenerator) load(key xdr.LedgerKey) (xdr.LedgerEntry, bool) {
+	entry, ok := g.entries[entryKey(key)]
+	if !ok {
+		return xdr.LedgerEntry{}, false
+	}
+	return copyEntry(entry), true
+}
+
+// create adds an entry to the state, and returns the change creating it.
+func (g *LedgerGenerator) create(entry xdr.LedgerEntry) xdr.LedgerEntryChanges {
+	entry.LastModifiedLedgerSeq = xdr.Uint32(g.sequence)
+	g.store(entry)
+	return xdr.LedgerEntryChanges{{
+		Type:    xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+		Created: &entry,
+	}}
+}
+
+// update updates an entry of the state, and returns the changes updating it.
+func (g *LedgerGenerator) update(key xdr.LedgerKey, f func(entry *xdr.LedgerEntry)) xdr.LedgerEntryChanges {
+	state, ok := g.load(key)
+	if !ok {
+		panic("updated ledger entry does not exist")
+	}
+	updated := copyEntry(state)
+	f(&updated)
+	updated.LastModifiedLedgerSeq = xdr.Uint32(g.sequence)
+	g.store(updated)
+	return xdr.LedgerEntryChanges{
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &state},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &updated},
+	}
+}
+
+// remove removes an entry from the state, and returns the changes removing
+// it.
+func (g *LedgerGenerator) remove(key xdr.LedgerKey) xdr.LedgerEntryChanges {
+	state, ok := g.load(key)
+	if !ok {
+		panic("removed ledger entry does not exist")
+	}
+	delete(g.entries, entryKey(key))
+	return xdr.LedgerEntryChanges{
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &state},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &key},
+	}
+}
+
+func (g *LedgerGenerator) account(a *account) xdr.AccountEntry {
+	entry, ok := g.load(a.id.LedgerKey())
+	if !ok {
+		panic("account does not exist")
+	}
+	return *entry.Data.Account
+}
+
+func (g *LedgerGenerator) updateAccount(a *account, f func(entry *xdr.AccountEntry)) xdr.LedgerEntryChanges {
+	return g.update(a.id.LedgerKey(), func(entry *xdr.LedgerEntry) {
+		f(entry.Data.Account)
+	})
+}
+
+func trustLineKey(a *account, asset xdr.Asset) xdr.LedgerKey {
+	var key xdr.LedgerKey
+	if err := key.SetTrustline(a.id, asset); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (g *LedgerGenerator) trustLine(a *account, asset xdr.Asset) (xdr.TrustLineEntry, bool) {
+	entry, ok := g.load(trustLineKey(a, asset))
+	if !ok {
+		return xdr.TrustLineEntry{}, false
+	}
+	return *entry.Data.TrustLine, true
+}
+
+func offerKey(a *account, id xdr.Int64) xdr.LedgerKey {
+	var key xdr.LedgerKey
+	if err := key.SetOffer(a.id, uint64(id)); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func dataKey(a *account, name xdr.String64) xdr.LedgerKey {
+	var key xdr.LedgerKey
+	if err := key.SetData(a.id, string(name)); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// sendable returns the amount of an asset an account can send, keeping a
+// minimum native balance.
+func (g *LedgerGenerator) sendable(a *account, asset xdr.Asset) xdr.Int64 {
+	switch {
+	case asset.Type == xdr.AssetTypeAssetTypeNative:
+		balance := g.account(a).Balance - minBalance
+		if balance < 0 {
+			return 0
+		}
+		return balance
+	case a.issued != nil && a.issued.Equals(asset):
+		return maxLimit
+	}
+	line, ok := g.trustLine(a, asset)
+	if !ok {
+		return 0
+	}
+	return line.Balance
+}
+
+// receivable returns the amount of an asset an account can receive.
+func (g *LedgerGenerator) receivable(a *account, asset xdr.Asset) xdr.Int64 {
+	switch {
+	case asset.Type == xdr.AssetTypeAssetTypeNative:
+		return maxLimit - g.account(a).Balance
+	case a.issued != nil && a.issued.Equals(asset):
+		return maxLimit
+	}
+	line, ok := g.trustLine(a, asset)
+	if !ok {
+		return 0
+	}
+	return line.Limit - line.Balance
+}
+
+// holdable returns the assets an account can hold.
+func (g *LedgerGenerator) holdable(a *account) []xdr.Asset {
+	assets := []xdr.Asset{xdr.MustNewNativeAsset()}
+	for _, asset := range g.assets {
+		if _, ok := g.trustLine(a, asset); ok || (a.issued != nil && a.issued.Equals(asset)) {
+			assets = append(assets, asset)
+		}
+	}
+	return assets
+}
@@ -0,0 +1,241 @@
+package ledgerbackend
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/xdr"
+)
+
+// LedgerMetaCategory is the history archive category of the precomputed
+// ledger exports. Its checkpoint files, e.g.
+// ledger-meta/00/00/00/ledger-meta-0000003f.xdr.gz, are gzipped XDR streams
+// of the xdr.LedgerCloseMeta of the ledgers of the checkpoint.
+const LedgerMetaCategory = "ledger-meta"
+
+// Ensure HistoryArchiveBackend implements LedgerBackend
+var _ LedgerBackend = (*HistoryArchiveBackend)(nil)
+
+// HistoryArchiveBackend is a LedgerBackend reading ledgers from a history
+// archive, e.g. one stored in an S3 bucket (s3://) or a Google Cloud Storage
+// bucket (gs://), so that historical ranges can be ingested without running
+// stellar-core. Use NewHistoryArchiveBackend to create a new instance.
+//
+// The ledgers of a checkpoint are read from the precomputed ledger exports of
+// the archive if it has them, see LedgerMetaCategory. Otherwise they are
+// rebuilt from the ledger headers, transaction sets and results of the
+// checkpoint, which do not have the meta of the ledger: the FeeProcessing,
+// TxApplyProcessing and UpgradesProcessing of the xdr.LedgerCloseMeta are
+// empty, so the ledger entry changes of these ledgers are not available.
+type HistoryArchiveBackend struct {
+	archive historyarchive.ArchiveInterface
+	// PollInterval is the time waited between two checks for the publication
+	// of the checkpoint of a ledger, defaults to 10 seconds.
+	PollInterval time.Duration
+
+	mutex             sync.Mutex
+	prepared          *Range
+	cachedCheckpoint  uint32
+	cachedLedgers     map[uint32]xdr.LedgerCloseMeta
+	checkpointManager historyarchive.CheckpointManager
+}
+
+// NewHistoryArchiveBackend returns a new HistoryArchiveBackend reading ledgers
+// from archive.
+func NewHistoryArchiveBackend(archive historyarchive.ArchiveInterface) *HistoryArchiveBackend {
+	return &HistoryArchiveBackend{
+		archive:           archive,
+		PollInterval:      10 * time.Second,
+		checkpointManager: archive.GetCheckpointManager(),
+	}
+}
+
+// NewHistoryArchiveBackendFromURL connects to the history archive at
+// archiveURL, e.g. s3://bucket/path or gs://bucket/path, and returns a new
+// HistoryArchiveBackend reading ledgers from it.
+func NewHistoryArchiveBackendFromURL(archiveURL string, opts historyarchive.ConnectOptions) (*HistoryArchiveBackend, error) {
+	archive, err := historyarchive.Connect(archiveURL, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "error connecting to history archive")
+	}
+	return NewHistoryArchiveBackend(archive), nil
+}
+
+// GetLatestLedgerSequence returns the sequence of the latest ledger published
+// in the archive, which is the last ledger of its latest checkpoint.
+func (b *HistoryArchiveBackend) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	has, err := b.archive.GetRootHAS()
+	if err != nil {
+		return 0, errors.Wrap(err, "error getting root HAS")
+	}
+	return has.CurrentLedger, nil
+}
+
+// PrepareRange prepares the given range to be read, and blocks until the
+// checkpoint of its first ledger is published.
+func (b *HistoryArchiveBackend) PrepareRange(ctx context.Context, ledgerRange Range) error {
+	if ledgerRange.from == 0 {
+		return errors.New("ledger range must start at ledger 1 or later")
+	}
+	if ledgerRange.bounded && ledgerRange.to < ledgerRange.from {
+		return errors.Errorf("invalid ledger range %s", ledgerRange)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.prepared = &ledgerRange
+	_, err := b.getLedger(ctx, ledgerRange.from)
+	if err != nil {
+		b.prepared = nil
+		return errors.Wrapf(err, "error preparing range %s", ledgerRange)
+	}
+	return nil
+}
+
+// IsPrepared returns true if the given range is within the prepared range.
+func (b *HistoryArchiveBackend) IsPrepared(ctx context.Context, ledgerRange Range) (bool, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.prepared != nil && b.prepared.Contains(ledgerRange), nil
+}
+
+// GetLedger returns the ledger with the given sequence, which must be in the
+// prepared range. It blocks until the checkpoint of the ledger is published,
+// or until ctx is done.
+func (b *HistoryArchiveBackend) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.prepared == nil {
+		return xdr.LedgerCloseMeta{}, errors.New("session is not prepared, call PrepareRange first")
+	}
+	if !b.prepared.Contains(SingleLedgerRange(sequence)) {
+		return xdr.LedgerCloseMeta{}, errors.Errorf(
+			"requested ledger %d is outside of the prepared range %s",
+			sequence,
+			b.prepared,
+		)
+	}
+	return b.getLedger(ctx, sequence)
+}
+
+// Close releases the cached ledgers. The backend can be used again after
+// preparing a range.
+func (b *HistoryArchiveBackend) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.prepared = nil
+	b.cachedLedgers = nil
+	return nil
+}
+
+func (b *HistoryArchiveBackend) getLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	checkpoint := b.checkpointManager.GetCheckpoint(sequence)
+	if b.cachedLedgers == nil || b.cachedCheckpoint != checkpoint {
+		if err := b.waitForCheckpoint(ctx, checkpoint); err != nil {
+			return xdr.LedgerCloseMeta{}, err
+		}
+		ledgers, err := b.readCheckpoint(checkpoint)
+		if err != nil {
+			return xdr.LedgerCloseMeta{}, errors.Wrapf(err, "error reading checkpoint %d", checkpoint)
+		}
+		b.cachedCheckpoint, b.cachedLedgers = checkpoint, ledgers
+	}
+
+	ledger, ok := b.cachedLedgers[sequence]
+	if !ok {
+		return xdr.LedgerCloseMeta{}, errors.Errorf("ledger %d is not in checkpoint %d", sequence, checkpoint)
+	}
+	return ledger, nil
+}
+
+// waitForCheckpoint blocks until the checkpoint is published in the archive.
+func (b *HistoryArchiveBackend) waitForCheckpoint(ctx context.Context, checkpoint uint32) error {
+	for {
+		latest, err := b.GetLatestLedgerSequence(ctx)
+		if err != nil {
+			return err
+		}
+		if latest >= checkpoint {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(b.PollInterval):
+		}
+	}
+}
+
+// readCheckpoint returns the ledgers of the checkpoint, by sequence.
+func (b *HistoryArchiveBackend) readCheckpoint(checkpoint uint32) (map[uint32]xdr.LedgerCloseMeta, error) {
+	exported, err := b.archive.CategoryCheckpointExists(LedgerMetaCategory, checkpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking for ledger exports")
+	}
+	if exported {
+		return b.readLedgerExports(checkpoint)
+	}
+
+	archived, err := b.archive.GetLedgers(checkpoint, checkpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting ledgers")
+	}
+	ledgers := make(map[uint32]xdr.LedgerCloseMeta, len(archived))
+	for sequence, ledger := range archived {
+		ledgers[sequence] = ledgerCloseMetaFromArchive(ledger)
+	}
+	return ledgers, nil
+}
+
+func (b *HistoryArchiveBackend) readLedgerExports(checkpoint uint32) (map[uint32]xdr.LedgerCloseMeta, error) {
+	stream, err := b.archive.GetXdrStream(historyarchive.CategoryCheckpointPath(LedgerMetaCategory, checkpoint))
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening ledger exports stream")
+	}
+	defer stream.Close()
+
+	ledgers := map[uint32]xdr.LedgerCloseMeta{}
+	for {
+		var ledger xdr.LedgerCloseMeta
+		if err = stream.ReadOne(&ledger); err == io.EOF {
+			return ledgers, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "error reading ledger exports stream")
+		}
+		ledgers[ledger.LedgerSequence()] = ledger
+	}
+}
+
+// ledgerCloseMetaFromArchive rebuilds the xdr.LedgerCloseMeta of an archived
+// ledger, without the ledger entry changes which are not archived.
+func ledgerCloseMetaFromArchive(ledger *historyarchive.Ledger) xdr.LedgerCloseMeta {
+	results := ledger.TransactionResult.TxResultSet.Results
+	processing := make([]xdr.TransactionResultMeta, len(results))
+	for i, result := range results {
+		processing[i] = xdr.TransactionResultMeta{
+			Result:            result,
+			TxApplyProcessing: xdr.TransactionMeta{V: 2, V2: &xdr.TransactionMetaV2{}},
+		}
+	}
+
+	txSet := ledger.Transaction.TxSet
+	if len(txSet.Txs) == 0 {
+		// Ledgers without transactions may have no archived transaction set.
+		txSet.PreviousLedgerHash = ledger.Header.Header.PreviousLedgerHash
+	}
+	return xdr.LedgerCloseMeta{
+		V: 0,
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: ledger.Header,
+			TxSet:        txSet,
+			TxProcessing: processing,
+		},
+	}
+}
@@ -0,0 +1,115 @@
+package ledgerbackend
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/xdr"
+)
+
+func archivedTestLedger(sequence uint32, results ...xdr.TransactionResultPair) *historyarchive.Ledger {
+	return &historyarchive.Ledger{
+		Header: xdr.LedgerHeaderHistoryEntry{
+			Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(sequence), PreviousLedgerHash: xdr.Hash{byte(sequence)}},
+		},
+		TransactionResult: xdr.TransactionHistoryResultEntry{
+			LedgerSeq:   xdr.Uint32(sequence),
+			TxResultSet: xdr.TransactionResultSet{Results: results},
+		},
+	}
+}
+
+func newHistoryArchiveTestBackend(latest uint32) (*HistoryArchiveBackend, *historyarchive.MockArchive) {
+	archive := &historyarchive.MockArchive{}
+	archive.On("GetCheckpointManager").Return(historyarchive.NewCheckpointManager(64))
+	archive.On("GetRootHAS").Return(historyarchive.HistoryArchiveState{CurrentLedger: latest}, nil)
+	return NewHistoryArchiveBackend(archive), archive
+}
+
+func TestHistoryArchiveBackendArchivedLedgers(t *testing.T) {
+	ctx := context.Background()
+	backend, archive := newHistoryArchiveTestBackend(127)
+
+	result := xdr.TransactionResultPair{TransactionHash: xdr.Hash{1}}
+	archive.On("CategoryCheckpointExists", LedgerMetaCategory, uint32(127)).Return(false, nil).Once()
+	archive.On("GetLedgers", uint32(127), uint32(127)).Return(map[uint32]*historyarchive.Ledger{
+		100: archivedTestLedger(100, result),
+		101: archivedTestLedger(101),
+	}, nil).Once()
+
+	_, err := backend.GetLedger(ctx, 100)
+	assert.EqualError(t, err, "session is not prepared, call PrepareRange first")
+
+	require.NoError(t, backend.PrepareRange(ctx, BoundedRange(100, 101)))
+	prepared, err := backend.IsPrepared(ctx, SingleLedgerRange(101))
+	require.NoError(t, err)
+	assert.True(t, prepared)
+
+	ledger, err := backend.GetLedger(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(100), ledger.LedgerSequence())
+	require.Len(t, ledger.V0.TxProcessing, 1)
+	assert.Equal(t, result, ledger.V0.TxProcessing[0].Result)
+	assert.Equal(t, int32(2), ledger.V0.TxProcessing[0].TxApplyProcessing.V)
+
+	// The checkpoint is cached.
+	ledger, err = backend.GetLedger(ctx, 101)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(101), ledger.LedgerSequence())
+	assert.Empty(t, ledger.V0.TxProcessing)
+	assert.Equal(t, xdr.Hash{101}, ledger.V0.TxSet.PreviousLedgerHash)
+
+	_, err = backend.GetLedger(ctx, 102)
+	assert.EqualError(t, err, "requested ledger 102 is outside of the prepared range [100,101]")
+	archive.AssertExpectations(t)
+}
+
+func TestHistoryArchiveBackendLedgerExports(t *testing.T) {
+	ctx := context.Background()
+	backend, archive := newHistoryArchiveTestBackend(127)
+
+	var buf bytes.Buffer
+	for _, sequence := range []uint32{64, 65} {
+		require.NoError(t, xdr.MarshalFramed(&buf, xdr.LedgerCloseMeta{
+			V0: &xdr.LedgerCloseMetaV0{
+				LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+					Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(sequence), LedgerVersion: 17},
+				},
+			},
+		}))
+	}
+	archive.On("CategoryCheckpointExists", LedgerMetaCategory, uint32(127)).Return(true, nil).Once()
+	archive.On("GetXdrStream", "ledger-meta/00/00/00/ledger-meta-0000007f.xdr.gz").
+		Return(historyarchive.NewXdrStream(ioutil.NopCloser(&buf)), nil).Once()
+
+	require.NoError(t, backend.PrepareRange(ctx, UnboundedRange(65)))
+	ledger, err := backend.GetLedger(ctx, 65)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(65), ledger.LedgerSequence())
+	assert.Equal(t, xdr.Uint32(17), ledger.V0.LedgerHeader.Header.LedgerVersion)
+	archive.AssertExpectations(t)
+}
+
+func TestHistoryArchiveBackendWaitsForCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	archive := &historyarchive.MockArchive{}
+	archive.On("GetCheckpointManager").Return(historyarchive.NewCheckpointManager(64))
+	archive.On("GetRootHAS").Return(historyarchive.HistoryArchiveState{CurrentLedger: 127}, nil).Run(func(mock.Arguments) {
+		cancel()
+	})
+	backend := NewHistoryArchiveBackend(archive)
+	backend.PollInterval = 0
+
+	err := backend.PrepareRange(ctx, UnboundedRange(128))
+	assert.EqualError(t, err, "error preparing range [128,latest): context canceled")
+	prepared, err := backend.IsPrepared(ctx, UnboundedRange(128))
+	require.NoError(t, err)
+	assert.False(t, prepared)
+}
@@ -8,7 +8,6 @@ import (
 	"io/ioutil"
 	"math/rand"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -17,6 +16,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/stellar/go/clients/stellarcore"
 	"github.com/stellar/go/support/log"
 )
 
@@ -57,6 +57,7 @@ type stellarCoreRunner struct {
 	cancel       context.CancelFunc
 	ledgerBuffer *bufferedLedgerMetaReader
 	pipe         pipe
+	process      *stellarcore.Process
 
 	lock             sync.Mutex
 	processExited    bool
@@ -171,7 +172,7 @@ func (r *stellarCoreRunner) getConfFileName() string {
 	return path
 }
 
-func (r *stellarCoreRunner) getLogLineWriter() io.Writer {
+func (r *stellarCoreRunner) getLogLineWriter() *io.PipeWriter {
 	rd, wr := io.Pipe()
 	br := bufio.NewReader(rd)
 
@@ -217,29 +218,6 @@ func (r *stellarCoreRunner) getLogLineWriter() io.Writer {
 	return wr
 }
 
-func (r *stellarCoreRunner) createCmd(params ...string) *exec.Cmd {
-	allParams := append([]string{"--conf", r.getConfFileName()}, params...)
-	cmd := exec.CommandContext(r.ctx, r.executablePath, allParams...)
-	cmd.Dir = r.storagePath
-	cmd.Stdout = r.getLogLineWriter()
-	cmd.Stderr = r.getLogLineWriter()
-	return cmd
-}
-
-func (r *stellarCoreRunner) runCmd(params ...string) error {
-	cmd := r.createCmd(params...)
-	defer r.closeLogLineWriters(cmd)
-
-	if err := cmd.Start(); err != nil {
-		return errors.Wrapf(err, "could not start `stellar-core %v` cmd", params)
-	}
-
-	if err := cmd.Wait(); err != nil {
-		return errors.Wrapf(err, "error waiting for `stellar-core %v` subprocess", params)
-	}
-	return nil
-}
-
 // context returns the context.Context instance associated with the running captive core instance
 func (r *stellarCoreRunner) context() context.Context {
 	return r.ctx
@@ -258,31 +236,14 @@ func (r *stellarCoreRunner) catchup(from, to uint32) error {
 	if r.started {
 		return errors.New("runner already started")
 	}
-	if err := r.runCmd("new-db"); err != nil {
-		return errors.Wrap(err, "error waiting for `stellar-core new-db` subprocess")
-	}
-
-	rangeArg := fmt.Sprintf("%d/%d", to, to-from+1)
-	cmd := r.createCmd(
-		"catchup", rangeArg,
-		"--metadata-output-stream", r.getPipeName(),
-		"--replay-in-memory",
-	)
-
-	var err error
-	r.pipe, err = r.start(cmd)
-	if err != nil {
-		r.closeLogLineWriters(cmd)
-		return errors.Wrap(err, "error starting `stellar-core catchup` subprocess")
-	}
-
-	r.started = true
-	r.ledgerBuffer = newBufferedLedgerMetaReader(r.pipe.Reader)
-	go r.ledgerBuffer.start()
-	r.wg.Add(1)
-	go r.handleExit(cmd)
 
-	return nil
+	err := r.startProcess(func(process *stellarcore.Process) error {
+		return process.Catchup(r.ctx, from, to,
+			"--metadata-output-stream", r.getPipeName(),
+			"--replay-in-memory",
+		)
+	})
+	return errors.Wrap(err, "error starting `stellar-core catchup` subprocess")
 }
 
 // runFrom executes the run command with a starting ledger on the captive core subprocess
@@ -299,34 +260,56 @@ func (r *stellarCoreRunner) runFrom(from uint32, hash string) error {
 		return errors.New("runner already started")
 	}
 
-	cmd := r.createCmd(
-		"run",
-		"--in-memory",
-		"--start-at-ledger", fmt.Sprintf("%d", from),
-		"--start-at-hash", hash,
-		"--metadata-output-stream", r.getPipeName(),
-	)
+	err := r.startProcess(func(process *stellarcore.Process) error {
+		return process.Run(r.ctx,
+			"--in-memory",
+			"--start-at-ledger", fmt.Sprintf("%d", from),
+			"--start-at-hash", hash,
+			"--metadata-output-stream", r.getPipeName(),
+		)
+	})
+	return errors.Wrap(err, "error starting `stellar-core run` subprocess")
+}
+
+// startProcess starts the captive core subprocess with start, which runs
+// either `stellar-core run` or `stellar-core catchup`, and streams the
+// ledgers it emits to the ledger buffer. The subprocess is managed by a
+// stellarcore.Process, without restarts: captive core is restarted by the
+// backend, from the last ledger it ingested.
+func (r *stellarCoreRunner) startProcess(start func(process *stellarcore.Process) error) error {
+	stdout, stderr := r.getLogLineWriter(), r.getLogLineWriter()
+	config := stellarcore.ProcessConfig{
+		BinaryPath: r.executablePath,
+		ConfigPath: r.getConfFileName(),
+		Dir:        r.storagePath,
+		Stdout:     stdout,
+		Stderr:     stderr,
+		Log:        r.log,
+	}
 
 	var err error
-	r.pipe, err = r.start(cmd)
+	r.process, r.pipe, err = r.start(config, start)
 	if err != nil {
-		r.closeLogLineWriters(cmd)
-		return errors.Wrap(err, "error starting `stellar-core run` subprocess")
+		stdout.Close()
+		stderr.Close()
+		return err
 	}
 
 	r.started = true
 	r.ledgerBuffer = newBufferedLedgerMetaReader(r.pipe.Reader)
 	go r.ledgerBuffer.start()
 	r.wg.Add(1)
-	go r.handleExit(cmd)
+	go r.handleExit(stdout, stderr)
 
 	return nil
 }
 
-func (r *stellarCoreRunner) handleExit(cmd *exec.Cmd) {
+func (r *stellarCoreRunner) handleExit(stdout, stderr *io.PipeWriter) {
 	defer r.wg.Done()
-	exitErr := cmd.Wait()
-	r.closeLogLineWriters(cmd)
+	exitErr := r.process.Wait()
+	// Close the go routines created by getLogLineWriter()
+	stdout.Close()
+	stderr.Close()
 
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -342,12 +325,6 @@ func (r *stellarCoreRunner) handleExit(cmd *exec.Cmd) {
 	r.processExitError = exitErr
 }
 
-// closeLogLineWriters closes the go routines created by getLogLineWriter()
-func (r *stellarCoreRunner) closeLogLineWriters(cmd *exec.Cmd) {
-	cmd.Stdout.(*io.PipeWriter).Close()
-	cmd.Stderr.(*io.PipeWriter).Close()
-}
-
 // getMetaPipe returns a channel which contains ledgers streamed from the captive core subprocess
 func (r *stellarCoreRunner) getMetaPipe() <-chan metaResult {
 	return r.ledgerBuffer.getChannel()
@@ -0,0 +1,49 @@
+// +build !windows
+
+package ledgerbackend
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/support/log"
+)
+
+func TestRunnerProcessExit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fake-stellar-core")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	binary := filepath.Join(dir, "stellar-core")
+	script := "#!/bin/sh\ncase \"$3\" in run) exit 3;; esac\n"
+	require.NoError(t, ioutil.WriteFile(binary, []byte(script), 0755))
+
+	captiveCoreToml, err := NewCaptiveCoreTomlFromFile(filepath.Join("testdata", "sample-appendix.cfg"), CaptiveCoreTomlParams{
+		NetworkPassphrase:  "Public Global Stellar Network ; September 2015",
+		HistoryArchiveURLs: []string{"http://localhost:1170"},
+	})
+	require.NoError(t, err)
+	runner, err := newStellarCoreRunner(CaptiveCoreConfig{
+		BinaryPath:         binary,
+		HistoryArchiveURLs: []string{"http://localhost"},
+		Log:                log.New(),
+		Context:            context.Background(),
+		Toml:               captiveCoreToml,
+		StoragePath:        dir,
+	}, stellarCoreRunnerModeOnline)
+	require.NoError(t, err)
+	defer runner.close()
+
+	require.NoError(t, runner.runFrom(2, "hash"))
+	// The meta pipe is closed, with an error, once the process exited.
+	result, ok := <-runner.getMetaPipe()
+	assert.True(t, !ok || result.err != nil)
+	exited, err := runner.getProcessExitError()
+	assert.True(t, exited)
+	assert.EqualError(t, err, "`stellar-core run` failed: exit status 3")
+}
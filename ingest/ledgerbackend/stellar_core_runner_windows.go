@@ -4,9 +4,9 @@ package ledgerbackend
 
 import (
 	"fmt"
-	"os/exec"
 
 	"github.com/Microsoft/go-winio"
+	"github.com/stellar/go/clients/stellarcore"
 )
 
 // Windows-specific methods for the stellarCoreRunner type.
@@ -15,26 +15,43 @@ func (c *stellarCoreRunner) getPipeName() string {
 	return fmt.Sprintf(`\\.\pipe\%s`, c.nonce)
 }
 
-func (c *stellarCoreRunner) start(cmd *exec.Cmd) (pipe, error) {
+func (c *stellarCoreRunner) start(
+	config stellarcore.ProcessConfig,
+	start func(process *stellarcore.Process) error,
+) (*stellarcore.Process, pipe, error) {
 	// First set up the server pipe.
 	listener, err := winio.ListenPipe(c.getPipeName(), nil)
 	if err != nil {
-		return pipe{}, err
+		return nil, pipe{}, err
 	}
 
 	// Then start the process.
-	err = cmd.Start()
+	process, err := stellarcore.NewProcess(config)
+	if err == nil {
+		err = start(process)
+	}
 	if err != nil {
 		listener.Close()
-		return pipe{}, err
+		return nil, pipe{}, err
 	}
 
-	// Then accept on the server end.
+	// Then accept on the server end. The process is started asynchronously,
+	// so the listener is closed if it exits without connecting.
+	accepted := make(chan struct{})
+	defer close(accepted)
+	go func() {
+		select {
+		case <-process.Done():
+			listener.Close()
+		case <-accepted:
+		}
+	}()
 	connection, err := listener.Accept()
 	if err != nil {
 		listener.Close()
-		return pipe{}, err
+		process.Stop()
+		return nil, pipe{}, err
 	}
 
-	return pipe{Reader: connection, File: listener}, nil
+	return process, pipe{Reader: connection, File: listener}, nil
 }
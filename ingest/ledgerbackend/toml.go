@@ -81,6 +81,7 @@ type captiveCoreTomlValues struct {
 	FailureSafety                        int                  `toml:"FAILURE_SAFETY"`
 	UnsafeQuorum                         bool                 `toml:"UNSAFE_QUORUM,omitempty"`
 	RunStandalone                        bool                 `toml:"RUN_STANDALONE,omitempty"`
+	ManualClose                          bool                 `toml:"MANUAL_CLOSE,omitempty"`
 	ArtificiallyAccelerateTimeForTesting bool                 `toml:"ARTIFICIALLY_ACCELERATE_TIME_FOR_TESTING,omitempty"`
 	DisableXDRFsync                      bool                 `toml:"DISABLE_XDR_FSYNC,omitempty"`
 	HomeDomains                          []HomeDomain         `toml:"HOME_DOMAINS,omitempty"`
@@ -377,6 +378,35 @@ func (c *CaptiveCoreToml) CatchupToml() (*CaptiveCoreToml, error) {
 	return offline, nil
 }
 
+// ManualCloseToml returns a new CaptiveCoreToml instance based off the
+// existing instance with some modifications which are suitable for running
+// captive core standalone, closing ledgers only when requested with the
+// manualclose command of its HTTP port, e.g. to close ledgers applying network
+// upgrades set with the upgrades command.
+func (c *CaptiveCoreToml) ManualCloseToml() (*CaptiveCoreToml, error) {
+	standalone, err := c.clone()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not clone toml")
+	}
+
+	standalone.RunStandalone = true
+	standalone.ManualClose = true
+	standalone.UnsafeQuorum = true
+	standalone.FailureSafety = 0
+
+	if !c.QuorumSetIsConfigured() {
+		// Add a fictional quorum -- necessary to convince core to start up;
+		// but not used at all for our purposes. Pubkey here is just random.
+		standalone.QuorumSetEntries = map[string]QuorumSet{
+			"QUORUM_SET": QuorumSet{
+				ThresholdPercent: 100,
+				Validators:       []string{"GCZBOIAY4HLKAJVNJORXZOZRAY2BJDBZHKPBHZCRAIUR5IHC2UHBGCQR"},
+			},
+		}
+	}
+	return standalone, nil
+}
+
 func (c *CaptiveCoreToml) setDefaults(params CaptiveCoreTomlParams) {
 	if !c.tree.Has("NETWORK_PASSPHRASE") {
 		c.NetworkPassphrase = params.NetworkPassphrase
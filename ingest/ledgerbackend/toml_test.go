@@ -312,3 +312,26 @@ func TestGenerateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestManualCloseToml(t *testing.T) {
+	params := CaptiveCoreTomlParams{
+		NetworkPassphrase:  "Public Global Stellar Network ; September 2015",
+		HistoryArchiveURLs: []string{"http://localhost:1170"},
+		HTTPPort:           newUint(6789),
+		PeerPort:           newUint(12345),
+	}
+	captiveCoreToml, err := NewCaptiveCoreToml(params)
+	assert.NoError(t, err)
+
+	standaloneToml, err := captiveCoreToml.ManualCloseToml()
+	assert.NoError(t, err)
+	assert.False(t, captiveCoreToml.ManualClose)
+
+	configBytes, err := generateConfig(standaloneToml, stellarCoreRunnerModeOnline)
+	assert.NoError(t, err)
+
+	expectedByte, err := ioutil.ReadFile(filepath.Join("testdata", "expected-online-with-manual-close.cfg"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, string(configBytes), string(expectedByte))
+}
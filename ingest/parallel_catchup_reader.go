@@ -0,0 +1,193 @@
+package ingest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stellar/go/historyarchive"
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// ProcessLedgerFunc processes a ledger on a worker of a ParallelCatchupReader
+// and returns its result. It is called concurrently by the workers.
+type ProcessLedgerFunc func(ctx context.Context, ledger xdr.LedgerCloseMeta) (interface{}, error)
+
+// MergeLedgerFunc merges the result of processing a ledger. It is called by a
+// single goroutine, in ledger sequence order.
+type MergeLedgerFunc func(sequence uint32, result interface{}) error
+
+// ParallelCatchupReader reads a historical range of ledgers with several
+// workers. The range is split at checkpoint boundaries into segments, each
+// read by a worker with its own ledger backend, so that backends replaying
+// history from checkpoints, like captive stellar-core, never replay the same
+// ledgers twice. The results of the ledgers are merged in order.
+type ParallelCatchupReader struct {
+	// NewBackend creates the ledger backend of a worker. The backends are
+	// closed once the range is read.
+	NewBackend func() (ledgerbackend.LedgerBackend, error)
+	// Workers is the number of segments read concurrently, defaults to 1.
+	Workers uint
+	// CheckpointFrequency is the number of ledgers between checkpoints,
+	// defaults to historyarchive.DefaultCheckpointFrequency.
+	CheckpointFrequency uint32
+	// CheckpointsPerSegment is the number of checkpoints in each segment,
+	// defaults to 1. Larger segments amortize the cost of preparing the
+	// range of a backend.
+	CheckpointsPerSegment uint32
+}
+
+type ledgerSegment struct {
+	from, to uint32
+}
+
+type segmentResult struct {
+	results []interface{}
+	err     error
+}
+
+// segments splits the [from, to] range at checkpoint boundaries.
+func (r *ParallelCatchupReader) segments(from, to uint32) []ledgerSegment {
+	manager := historyarchive.NewCheckpointManager(r.CheckpointFrequency)
+	checkpoints := r.CheckpointsPerSegment
+	if checkpoints == 0 {
+		checkpoints = 1
+	}
+
+	var segments []ledgerSegment
+	for segmentFrom := from; ; {
+		segmentTo := manager.GetCheckpoint(segmentFrom)
+		for i := uint32(1); i < checkpoints && segmentTo < to; i++ {
+			segmentTo = manager.GetCheckpoint(segmentTo + 1)
+		}
+		if segmentTo >= to {
+			return append(segments, ledgerSegment{from: segmentFrom, to: to})
+		}
+		segments = append(segments, ledgerSegment{from: segmentFrom, to: segmentTo})
+		segmentFrom = segmentTo + 1
+	}
+}
+
+// Process reads the ledgers of the [from, to] range, calls process with each
+// of them on the workers, and calls merge with their results in ledger
+// sequence order. It stops at the first error, once the results of the
+// segments before the failed one are merged.
+func (r *ParallelCatchupReader) Process(ctx context.Context, from, to uint32, process ProcessLedgerFunc, merge MergeLedgerFunc) error {
+	if from == 0 || from > to {
+		return errors.Errorf("invalid ledger range [%d,%d]", from, to)
+	}
+	workers := r.Workers
+	if workers == 0 {
+		workers = 1
+	}
+	segments := r.segments(from, to)
+
+	ctx, cancel := context.WithCancel(ctx)
+	results := make([]chan segmentResult, len(segments))
+	for i := range results {
+		results[i] = make(chan segmentResult, 1)
+	}
+	// window bounds the number of segments read ahead of the merge, and so
+	// the memory used by their results.
+	window := make(chan struct{}, 2*workers)
+	jobs := make(chan int)
+
+	go func() {
+		defer close(jobs)
+		for i := range segments {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := uint(0); i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.runWorker(ctx, segments, jobs, results, process)
+		}()
+	}
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	for i, segment := range segments {
+		var result segmentResult
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if result.err != nil {
+			return errors.Wrapf(result.err, "error reading ledgers [%d,%d]", segment.from, segment.to)
+		}
+
+		for j, ledgerResult := range result.results {
+			sequence := segment.from + uint32(j)
+			if err := merge(sequence, ledgerResult); err != nil {
+				return errors.Wrapf(err, "error merging ledger %d", sequence)
+			}
+		}
+		<-window
+	}
+	return nil
+}
+
+func (r *ParallelCatchupReader) runWorker(
+	ctx context.Context,
+	segments []ledgerSegment,
+	jobs <-chan int,
+	results []chan segmentResult,
+	process ProcessLedgerFunc,
+) {
+	var backend ledgerbackend.LedgerBackend
+	defer func() {
+		if backend != nil {
+			backend.Close()
+		}
+	}()
+
+	for i := range jobs {
+		if backend == nil {
+			newBackend, err := r.NewBackend()
+			if err != nil {
+				results[i] <- segmentResult{err: errors.Wrap(err, "error creating ledger backend")}
+				continue
+			}
+			backend = newBackend
+		}
+		results[i] <- readSegment(ctx, backend, segments[i], process)
+	}
+}
+
+func readSegment(ctx context.Context, backend ledgerbackend.LedgerBackend, segment ledgerSegment, process ProcessLedgerFunc) segmentResult {
+	err := backend.PrepareRange(ctx, ledgerbackend.BoundedRange(segment.from, segment.to))
+	if err != nil {
+		return segmentResult{err: errors.Wrap(err, "error preparing range")}
+	}
+
+	results := make([]interface{}, 0, segment.to-segment.from+1)
+	for sequence := segment.from; sequence <= segment.to; sequence++ {
+		ledger, err := backend.GetLedger(ctx, sequence)
+		if err != nil {
+			return segmentResult{err: errors.Wrapf(err, "error getting ledger %d", sequence)}
+		}
+		result, err := process(ctx, ledger)
+		if err != nil {
+			return segmentResult{err: errors.Wrapf(err, "error processing ledger %d", sequence)}
+		}
+		results = append(results, result)
+	}
+	return segmentResult{results: results}
+}
@@ -0,0 +1,140 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go/ingest/ledgerbackend"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catchupTestBackend is a ledger backend recording the ranges it prepared.
+type catchupTestBackend struct {
+	mutex    *sync.Mutex
+	prepared *[]string
+	closed   *int
+}
+
+func (b catchupTestBackend) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	return 0, nil
+}
+
+func (b catchupTestBackend) GetLedger(ctx context.Context, sequence uint32) (xdr.LedgerCloseMeta, error) {
+	return xdr.LedgerCloseMeta{
+		V0: &xdr.LedgerCloseMetaV0{
+			LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+				Header: xdr.LedgerHeader{LedgerSeq: xdr.Uint32(sequence)},
+			},
+		},
+	}, nil
+}
+
+func (b catchupTestBackend) PrepareRange(ctx context.Context, ledgerRange ledgerbackend.Range) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	*b.prepared = append(*b.prepared, ledgerRange.String())
+	return nil
+}
+
+func (b catchupTestBackend) IsPrepared(ctx context.Context, ledgerRange ledgerbackend.Range) (bool, error) {
+	return false, nil
+}
+
+func (b catchupTestBackend) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	*b.closed++
+	return nil
+}
+
+func newCatchupTestReader(workers, checkpointsPerSegment uint32) (*ParallelCatchupReader, *[]string, *int) {
+	backend := catchupTestBackend{mutex: &sync.Mutex{}, prepared: &[]string{}, closed: new(int)}
+	return &ParallelCatchupReader{
+		NewBackend: func() (ledgerbackend.LedgerBackend, error) {
+			return backend, nil
+		},
+		Workers:               uint(workers),
+		CheckpointsPerSegment: checkpointsPerSegment,
+	}, backend.prepared, backend.closed
+}
+
+func processSequence(ctx context.Context, ledger xdr.LedgerCloseMeta) (interface{}, error) {
+	return ledger.LedgerSequence(), nil
+}
+
+func TestParallelCatchupReader(t *testing.T) {
+	for _, tc := range []struct {
+		workers               uint32
+		checkpointsPerSegment uint32
+		prepared              []string
+	}{
+		{1, 0, []string{"[10,63]", "[64,127]", "[128,191]", "[192,255]", "[256,300]"}},
+		{3, 1, []string{"[10,63]", "[64,127]", "[128,191]", "[192,255]", "[256,300]"}},
+		{4, 2, []string{"[10,127]", "[128,255]", "[256,300]"}},
+		{2, 10, []string{"[10,300]"}},
+	} {
+		reader, prepared, closed := newCatchupTestReader(tc.workers, tc.checkpointsPerSegment)
+		var merged []uint32
+		err := reader.Process(context.Background(), 10, 300, processSequence, func(sequence uint32, result interface{}) error {
+			assert.Equal(t, sequence, result)
+			merged = append(merged, sequence)
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, merged, 291)
+		for i, sequence := range merged {
+			assert.Equal(t, uint32(10+i), sequence)
+		}
+		sort.Strings(*prepared)
+		sort.Strings(tc.prepared)
+		assert.Equal(t, tc.prepared, *prepared)
+		assert.LessOrEqual(t, *closed, int(tc.workers))
+		assert.Greater(t, *closed, 0)
+	}
+}
+
+func TestParallelCatchupReaderErrors(t *testing.T) {
+	reader, _, _ := newCatchupTestReader(3, 1)
+	var merged []uint32
+	err := reader.Process(
+		context.Background(),
+		10,
+		300,
+		func(ctx context.Context, ledger xdr.LedgerCloseMeta) (interface{}, error) {
+			if ledger.LedgerSequence() == 100 {
+				return nil, errors.New("process error")
+			}
+			return ledger.LedgerSequence(), nil
+		},
+		func(sequence uint32, result interface{}) error {
+			merged = append(merged, sequence)
+			return nil
+		},
+	)
+	assert.EqualError(t, err, "error reading ledgers [64,127]: error processing ledger 100: process error")
+	// The segments before the failed one are merged.
+	assert.Len(t, merged, 54)
+
+	err = reader.Process(context.Background(), 10, 300, processSequence, func(sequence uint32, result interface{}) error {
+		if sequence == 200 {
+			return errors.New("merge error")
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "error merging ledger 200: merge error")
+
+	reader.NewBackend = func() (ledgerbackend.LedgerBackend, error) {
+		return nil, errors.New("backend error")
+	}
+	err = reader.Process(context.Background(), 10, 300, processSequence, func(uint32, interface{}) error { return nil })
+	assert.EqualError(t, err, "error reading ledgers [10,63]: error creating ledger backend: backend error")
+
+	err = reader.Process(context.Background(), 300, 10, processSequence, func(uint32, interface{}) error { return nil })
+	assert.EqualError(t, err, "invalid ledger range [300,10]")
+}
@@ -0,0 +1,232 @@
+// Package trades extracts the trades executed by a transaction, the same way
+// Horizon does when populating the history_trades table served by the /trades
+// endpoints.
+package trades
+
+import (
+	"time"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// OfferIDType is the type of a synthetic offer id, see SyntheticOfferID.
+type OfferIDType uint64
+
+const (
+	// CoreOfferIDType is the type of offer ids allocated by stellar-core.
+	CoreOfferIDType OfferIDType = 0
+	// OperationIDType is the type of offer ids derived from the id of the
+	// operation which created an immediately filled offer.
+	OperationIDType OfferIDType = 1
+
+	offerIDMask uint64 = 0xC000000000000000
+)
+
+// Trade is a single offer claimed by an operation.
+type Trade struct {
+	// OperationID is the id of the operation which executed the trade, as
+	// used in the `id` and `paging_token` of Horizon's trade resources.
+	OperationID int64
+	// Order is the index of the trade among the offers claimed by the
+	// operation, skipped entries included.
+	Order           int32
+	LedgerCloseTime time.Time
+
+	SellerAccount string
+	BuyerAccount  string
+	// SellOfferID is the stellar-core id of the claimed offer.
+	SellOfferID int64
+	// BuyOfferExists is true when the operation left an offer in the order
+	// book, in which case BuyOfferID is its stellar-core id.
+	BuyOfferExists bool
+	BuyOfferID     int64
+
+	AssetSold    xdr.Asset
+	AmountSold   xdr.Int64
+	AssetBought  xdr.Asset
+	AmountBought xdr.Int64
+
+	// SellPrice is the price of the claimed offer before the trade, which is
+	// the price reported by Horizon.
+	SellPrice xdr.Price
+}
+
+// ClaimOfferAtom returns the trade in the form stellar-core reports it.
+func (t Trade) ClaimOfferAtom() xdr.ClaimOfferAtom {
+	seller := xdr.MustAddress(t.SellerAccount)
+	return xdr.ClaimOfferAtom{
+		SellerId:     seller,
+		OfferId:      xdr.Int64(t.SellOfferID),
+		AssetSold:    t.AssetSold,
+		AmountSold:   t.AmountSold,
+		AssetBought:  t.AssetBought,
+		AmountBought: t.AmountBought,
+	}
+}
+
+// HorizonSellOfferID returns the offer id Horizon reports for the seller.
+func (t Trade) HorizonSellOfferID() int64 {
+	return SyntheticOfferID(uint64(t.SellOfferID), CoreOfferIDType)
+}
+
+// HorizonBuyOfferID returns the offer id Horizon reports for the buyer.
+// stellar-core does not allocate ids for immediately filled offers, in that
+// case the id is derived from the operation id.
+func (t Trade) HorizonBuyOfferID() int64 {
+	if t.BuyOfferExists {
+		return SyntheticOfferID(uint64(t.BuyOfferID), CoreOfferIDType)
+	}
+	return SyntheticOfferID(uint64(t.OperationID), OperationIDType)
+}
+
+// SyntheticOfferID encodes `id` with its type in the second highest bit, the
+// way Horizon builds the `base_offer_id` and `counter_offer_id` of trades. It
+// panics if `id` does not fit in 62 bits.
+func SyntheticOfferID(id uint64, typ OfferIDType) int64 {
+	if id&offerIDMask != 0 {
+		panic("Value too big to encode")
+	}
+	return int64(id | uint64(typ)<<62)
+}
+
+// Extract returns the trades executed by a transaction included in the given
+// ledger. Failed transactions have no trades.
+//
+// Claimed offers with both amounts set to zero are skipped: stellar-core
+// emits them when garbage collecting offers whose owners spent down their
+// balance, and they do not represent trades.
+func Extract(ledger xdr.LedgerHeaderHistoryEntry, transaction ingest.LedgerTransaction) ([]Trade, error) {
+	if !transaction.Result.Successful() {
+		return nil, nil
+	}
+
+	var trades []Trade
+	closeTime := time.Unix(int64(ledger.Header.ScpValue.CloseTime), 0).UTC()
+
+	opResults, ok := transaction.Result.OperationResults()
+	if !ok {
+		return nil, errors.New("transaction has no operation results")
+	}
+	for opidx, op := range transaction.Envelope.Operations() {
+		var claims []xdr.ClaimOfferAtom
+		var buyOfferExists bool
+		var buyOffer xdr.OfferEntry
+
+		switch op.Body.Type {
+		case xdr.OperationTypePathPaymentStrictReceive:
+			claims = opResults[opidx].MustTr().MustPathPaymentStrictReceiveResult().
+				MustSuccess().
+				Offers
+
+		case xdr.OperationTypePathPaymentStrictSend:
+			claims = opResults[opidx].MustTr().
+				MustPathPaymentStrictSendResult().
+				MustSuccess().
+				Offers
+
+		case xdr.OperationTypeManageBuyOffer:
+			manageOfferResult := opResults[opidx].MustTr().MustManageBuyOfferResult().
+				MustSuccess()
+			claims = manageOfferResult.OffersClaimed
+			buyOffer, buyOfferExists = manageOfferResult.Offer.GetOffer()
+
+		case xdr.OperationTypeManageSellOffer:
+			manageOfferResult := opResults[opidx].MustTr().MustManageSellOfferResult().
+				MustSuccess()
+			claims = manageOfferResult.OffersClaimed
+			buyOffer, buyOfferExists = manageOfferResult.Offer.GetOffer()
+
+		case xdr.OperationTypeCreatePassiveSellOffer:
+			result := opResults[opidx].MustTr()
+
+			// KNOWN ISSUE:  stellar-core creates results for CreatePassiveOffer operations
+			// with the wrong result arm set.
+			if result.Type == xdr.OperationTypeManageSellOffer {
+				manageOfferResult := result.MustManageSellOfferResult().MustSuccess()
+				claims = manageOfferResult.OffersClaimed
+				buyOffer, buyOfferExists = manageOfferResult.Offer.GetOffer()
+			} else {
+				passiveOfferResult := result.MustCreatePassiveSellOfferResult().MustSuccess()
+				claims = passiveOfferResult.OffersClaimed
+				buyOffer, buyOfferExists = passiveOfferResult.Offer.GetOffer()
+			}
+		}
+
+		if len(claims) == 0 {
+			continue
+		}
+
+		var buyer xdr.AccountId
+		if op.SourceAccount != nil {
+			buyer = op.SourceAccount.ToAccountId()
+		} else {
+			buyer = transaction.Envelope.SourceAccount().ToAccountId()
+		}
+
+		opID := operationID(ledger.Header.LedgerSeq, transaction.Index, opidx)
+		for order, claim := range claims {
+			if claim.AmountBought == 0 && claim.AmountSold == 0 {
+				continue
+			}
+
+			sellPrice, err := findSellPrice(transaction, opidx, claim)
+			if err != nil {
+				return nil, err
+			}
+
+			trades = append(trades, Trade{
+				OperationID:     opID,
+				Order:           int32(order),
+				LedgerCloseTime: closeTime,
+				SellerAccount:   claim.SellerId.Address(),
+				BuyerAccount:    buyer.Address(),
+				SellOfferID:     int64(claim.OfferId),
+				BuyOfferExists:  buyOfferExists,
+				BuyOfferID:      int64(buyOffer.OfferId),
+				AssetSold:       claim.AssetSold,
+				AmountSold:      claim.AmountSold,
+				AssetBought:     claim.AssetBought,
+				AmountBought:    claim.AmountBought,
+				SellPrice:       sellPrice,
+			})
+		}
+	}
+
+	return trades, nil
+}
+
+// findSellPrice returns the price of the claimed offer before the operation
+// was applied.
+func findSellPrice(
+	transaction ingest.LedgerTransaction,
+	opidx int,
+	claim xdr.ClaimOfferAtom,
+) (xdr.Price, error) {
+	var price xdr.Price
+	key := xdr.LedgerKey{}
+	if err := key.SetOffer(claim.SellerId, uint64(claim.OfferId)); err != nil {
+		return price, errors.Wrap(err, "could not build offer ledger key")
+	}
+
+	changes, err := transaction.GetOperationChanges(uint32(opidx))
+	if err != nil {
+		return price, errors.Wrap(err, "could not determine changes for operation")
+	}
+
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.Pre != nil && key.Equals(change.Pre.LedgerKey()) {
+			return change.Pre.Data.Offer.Price, nil
+		}
+	}
+
+	return price, errors.New("could not find change for trade offer")
+}
+
+// operationID returns the total order id of an operation, see the toid
+// package in Horizon.
+func operationID(ledgerSequence xdr.Uint32, txIndex uint32, opIndex int) int64 {
+	return int64(ledgerSequence)<<32 | int64(txIndex)<<12 | int64(opIndex+1)
+}
@@ -0,0 +1,194 @@
+package trades
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/xdr"
+)
+
+var (
+	source = xdr.MustMuxedAddress("GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY")
+	seller = xdr.MustAddress("GDQWI6FKB72DPOJE4CGYCFQZKRPQQIOYXRMZ5KEVGXMG6UUTGJMBCASH")
+)
+
+func offerChanges(claim xdr.ClaimOfferAtom, price xdr.Price) xdr.LedgerEntryChanges {
+	return xdr.LedgerEntryChanges{
+		{
+			Type: xdr.LedgerEntryChangeTypeLedgerEntryState,
+			State: &xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{
+					Type: xdr.LedgerEntryTypeOffer,
+					Offer: &xdr.OfferEntry{
+						SellerId: claim.SellerId,
+						OfferId:  claim.OfferId,
+						Price:    price,
+					},
+				},
+			},
+		},
+		{
+			Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved,
+			Removed: &xdr.LedgerKey{
+				Type: xdr.LedgerEntryTypeOffer,
+				Offer: &xdr.LedgerKeyOffer{
+					SellerId: claim.SellerId,
+					OfferId:  claim.OfferId,
+				},
+			},
+		},
+	}
+}
+
+func sellOfferTransaction(claims []xdr.ClaimOfferAtom, meta xdr.LedgerEntryChanges, offer *xdr.OfferEntry) ingest.LedgerTransaction {
+	// the offer is deleted when it is fully taken
+	offerResult := xdr.ManageOfferSuccessResultOffer{Effect: xdr.ManageOfferEffectManageOfferDeleted}
+	if offer != nil {
+		offerResult = xdr.ManageOfferSuccessResultOffer{Effect: xdr.ManageOfferEffectManageOfferCreated, Offer: offer}
+	}
+	results := []xdr.OperationResult{
+		{
+			Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypeManageSellOffer,
+				ManageSellOfferResult: &xdr.ManageSellOfferResult{
+					Code: xdr.ManageSellOfferResultCodeManageSellOfferSuccess,
+					Success: &xdr.ManageOfferSuccessResult{
+						OffersClaimed: claims,
+						Offer:         offerResult,
+					},
+				},
+			},
+		},
+	}
+
+	return ingest.LedgerTransaction{
+		Index: 2,
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code:    xdr.TransactionResultCodeTxSuccess,
+					Results: &results,
+				},
+			},
+		},
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: source,
+					Operations: []xdr.Operation{
+						{
+							Body: xdr.OperationBody{
+								Type:              xdr.OperationTypeManageSellOffer,
+								ManageSellOfferOp: &xdr.ManageSellOfferOp{},
+							},
+						},
+					},
+				},
+			},
+		},
+		UnsafeMeta: xdr.TransactionMeta{
+			V: 2,
+			V2: &xdr.TransactionMetaV2{
+				Operations: []xdr.OperationMeta{{Changes: meta}},
+			},
+		},
+	}
+}
+
+func TestExtract(t *testing.T) {
+	ledger := xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq: 100,
+			ScpValue:  xdr.StellarValue{CloseTime: 1600000000},
+		},
+	}
+	claim := xdr.ClaimOfferAtom{
+		SellerId:     seller,
+		OfferId:      42,
+		AssetSold:    xdr.MustNewNativeAsset(),
+		AmountSold:   200,
+		AssetBought:  xdr.MustNewCreditAsset("USD", seller.Address()),
+		AmountBought: 100,
+	}
+	// garbage collected offers are not trades
+	empty := xdr.ClaimOfferAtom{
+		SellerId:    seller,
+		OfferId:     43,
+		AssetSold:   xdr.MustNewNativeAsset(),
+		AssetBought: xdr.MustNewCreditAsset("USD", seller.Address()),
+	}
+	price := xdr.Price{N: 1, D: 2}
+
+	tx := sellOfferTransaction(
+		[]xdr.ClaimOfferAtom{empty, claim},
+		offerChanges(claim, price),
+		nil,
+	)
+	extracted, err := Extract(ledger, tx)
+	require.NoError(t, err)
+	require.Len(t, extracted, 1)
+
+	trade := extracted[0]
+	opID := int64(100)<<32 | int64(2)<<12 | 1
+	assert.Equal(t, opID, trade.OperationID)
+	assert.Equal(t, int32(1), trade.Order)
+	assert.Equal(t, time.Unix(1600000000, 0).UTC(), trade.LedgerCloseTime)
+	assert.Equal(t, seller.Address(), trade.SellerAccount)
+	assert.Equal(t, source.Address(), trade.BuyerAccount)
+	assert.Equal(t, price, trade.SellPrice)
+	assert.Equal(t, claim, trade.ClaimOfferAtom())
+	assert.False(t, trade.BuyOfferExists)
+	assert.Equal(t, int64(42), trade.HorizonSellOfferID())
+	assert.Equal(t, opID|1<<62, trade.HorizonBuyOfferID())
+
+	tx = sellOfferTransaction(
+		[]xdr.ClaimOfferAtom{claim},
+		offerChanges(claim, price),
+		&xdr.OfferEntry{OfferId: 7},
+	)
+	extracted, err = Extract(ledger, tx)
+	require.NoError(t, err)
+	require.Len(t, extracted, 1)
+	assert.True(t, extracted[0].BuyOfferExists)
+	assert.Equal(t, int64(7), extracted[0].HorizonBuyOfferID())
+}
+
+func TestExtractMissingOfferChange(t *testing.T) {
+	claim := xdr.ClaimOfferAtom{
+		SellerId:     seller,
+		OfferId:      42,
+		AssetSold:    xdr.MustNewNativeAsset(),
+		AmountSold:   200,
+		AssetBought:  xdr.MustNewCreditAsset("USD", seller.Address()),
+		AmountBought: 100,
+	}
+	tx := sellOfferTransaction([]xdr.ClaimOfferAtom{claim}, xdr.LedgerEntryChanges{}, nil)
+	_, err := Extract(xdr.LedgerHeaderHistoryEntry{}, tx)
+	assert.EqualError(t, err, "could not find change for trade offer")
+}
+
+func TestExtractFailedTransaction(t *testing.T) {
+	tx := ingest.LedgerTransaction{
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code: xdr.TransactionResultCodeTxFailed,
+				},
+			},
+		},
+	}
+	extracted, err := Extract(xdr.LedgerHeaderHistoryEntry{}, tx)
+	assert.NoError(t, err)
+	assert.Empty(t, extracted)
+}
+
+func TestSyntheticOfferID(t *testing.T) {
+	assert.Equal(t, int64(5), SyntheticOfferID(5, CoreOfferIDType))
+	assert.Equal(t, int64(5|1<<62), SyntheticOfferID(5, OperationIDType))
+	assert.Panics(t, func() { SyntheticOfferID(1<<62, CoreOfferIDType) })
+}
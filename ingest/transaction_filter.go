@@ -0,0 +1,315 @@
+package ingest
+
+import (
+	"context"
+	"io"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionFilter decides whether a transaction is relevant to a consumer,
+// see FilteredLedgerTransactionReader.
+type TransactionFilter interface {
+	FilterTransaction(ctx context.Context, tx LedgerTransaction) (bool, error)
+}
+
+// TransactionFilterFunc is an adapter allowing a function to be used as a
+// TransactionFilter.
+type TransactionFilterFunc func(ctx context.Context, tx LedgerTransaction) (bool, error)
+
+// FilterTransaction calls f(ctx, tx).
+func (f TransactionFilterFunc) FilterTransaction(ctx context.Context, tx LedgerTransaction) (bool, error) {
+	return f(ctx, tx)
+}
+
+// FilteredLedgerTransactionReader reads the transactions of a
+// LedgerTransactionReader which match all of its filters, skipping the
+// others. Use NewFilteredLedgerTransactionReader to create a new instance.
+type FilteredLedgerTransactionReader struct {
+	*LedgerTransactionReader
+	ctx     context.Context
+	filters []TransactionFilter
+}
+
+// NewFilteredLedgerTransactionReader creates a new FilteredLedgerTransactionReader
+// reading the transactions of reader which match all the filters. Use
+// AnyTransactionFilter to read the transactions matching any of several
+// filters. The context is passed to the filters.
+func NewFilteredLedgerTransactionReader(ctx context.Context, reader *LedgerTransactionReader, filters ...TransactionFilter) *FilteredLedgerTransactionReader {
+	return &FilteredLedgerTransactionReader{
+		LedgerTransactionReader: reader,
+		ctx:                     ctx,
+		filters:                 filters,
+	}
+}
+
+// Read returns the next transaction in the ledger matching the filters, ordered
+// by tx number, each time it is called. When there are no more transactions to
+// return, an EOF error is returned.
+func (reader *FilteredLedgerTransactionReader) Read() (LedgerTransaction, error) {
+	for {
+		tx, err := reader.LedgerTransactionReader.Read()
+		if err != nil {
+			return tx, err
+		}
+
+		match, err := allTransactionFilters(reader.filters).FilterTransaction(reader.ctx, tx)
+		if err != nil {
+			return LedgerTransaction{}, errors.Wrapf(err, "error filtering transaction %d", tx.Index)
+		}
+		if match {
+			return tx, nil
+		}
+	}
+}
+
+// FilterTransactions returns the transactions of reader which match all the
+// filters, reading it until EOF.
+func FilterTransactions(ctx context.Context, reader *LedgerTransactionReader, filters ...TransactionFilter) ([]LedgerTransaction, error) {
+	filtered := NewFilteredLedgerTransactionReader(ctx, reader, filters...)
+	var txs []LedgerTransaction
+	for {
+		tx, err := filtered.Read()
+		if err == io.EOF {
+			return txs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+}
+
+type allTransactionFilters []TransactionFilter
+
+func (filters allTransactionFilters) FilterTransaction(ctx context.Context, tx LedgerTransaction) (bool, error) {
+	for _, filter := range filters {
+		match, err := filter.FilterTransaction(ctx, tx)
+		if err != nil || !match {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// AnyTransactionFilter returns a filter matching the transactions which match
+// any of the filters.
+func AnyTransactionFilter(filters ...TransactionFilter) TransactionFilter {
+	return TransactionFilterFunc(func(ctx context.Context, tx LedgerTransaction) (bool, error) {
+		for _, filter := range filters {
+			match, err := filter.FilterTransaction(ctx, tx)
+			if err != nil || match {
+				return match, err
+			}
+		}
+		return false, nil
+	})
+}
+
+// OperationTypeFilter returns a filter matching the transactions with at
+// least one operation of one of the given types.
+func OperationTypeFilter(types ...xdr.OperationType) TransactionFilter {
+	return TransactionFilterFunc(func(ctx context.Context, tx LedgerTransaction) (bool, error) {
+		for _, op := range tx.Envelope.Operations() {
+			for _, typ := range types {
+				if op.Body.Type == typ {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+}
+
+// AccountFilter returns a filter matching the transactions involving one of
+// the given accounts, by their G... address. A transaction involves an
+// account if the account is the source of the transaction, of its fee bump or
+// of one of its operations, if the account is the destination, trustor or
+// clawed back account of one of its operations, or if the transaction changed
+// a ledger entry of the account.
+func AccountFilter(addresses ...string) TransactionFilter {
+	set := map[string]bool{}
+	for _, address := range addresses {
+		set[address] = true
+	}
+	return TransactionFilterFunc(func(ctx context.Context, tx LedgerTransaction) (bool, error) {
+		for _, account := range transactionAccounts(tx) {
+			if set[account.Address()] {
+				return true, nil
+			}
+		}
+
+		changes, err := tx.GetChanges()
+		if err != nil {
+			return false, errors.Wrap(err, "error getting transaction changes")
+		}
+		for _, change := range changes {
+			for _, entry := range []*xdr.LedgerEntry{change.Pre, change.Post} {
+				if entry == nil {
+					continue
+				}
+				for _, account := range ledgerEntryAccounts(*entry) {
+					if set[account.Address()] {
+						return true, nil
+					}
+				}
+			}
+		}
+		return false, nil
+	})
+}
+
+// AssetFilter returns a filter matching the transactions involving one of the
+// given assets. A transaction involves an asset if one of its operations
+// refers to the asset, or if the transaction changed a trust line, offer or
+// claimable balance of the asset.
+func AssetFilter(assets ...xdr.Asset) TransactionFilter {
+	return TransactionFilterFunc(func(ctx context.Context, tx LedgerTransaction) (bool, error) {
+		source := tx.Envelope.SourceAccount().ToAccountId()
+		for _, op := range tx.Envelope.Operations() {
+			opSource := source
+			if op.SourceAccount != nil {
+				opSource = op.SourceAccount.ToAccountId()
+			}
+			if containsAsset(assets, operationAssets(op, opSource)) {
+				return true, nil
+			}
+		}
+
+		changes, err := tx.GetChanges()
+		if err != nil {
+			return false, errors.Wrap(err, "error getting transaction changes")
+		}
+		for _, change := range changes {
+			for _, entry := range []*xdr.LedgerEntry{change.Pre, change.Post} {
+				if entry != nil && containsAsset(assets, ledgerEntryAssets(*entry)) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+}
+
+func containsAsset(assets, candidates []xdr.Asset) bool {
+	for _, candidate := range candidates {
+		for _, asset := range assets {
+			if candidate.Equals(asset) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// transactionAccounts returns the accounts referred to by the envelope of the
+// transaction.
+func transactionAccounts(tx LedgerTransaction) []xdr.AccountId {
+	accounts := []xdr.AccountId{tx.Envelope.SourceAccount().ToAccountId()}
+	if tx.Envelope.IsFeeBump() {
+		accounts = append(accounts, tx.Envelope.FeeBumpAccount().ToAccountId())
+	}
+	for _, op := range tx.Envelope.Operations() {
+		if op.SourceAccount != nil {
+			accounts = append(accounts, op.SourceAccount.ToAccountId())
+		}
+		body := op.Body
+		switch body.Type {
+		case xdr.OperationTypeCreateAccount:
+			accounts = append(accounts, body.MustCreateAccountOp().Destination)
+		case xdr.OperationTypePayment:
+			accounts = append(accounts, body.MustPaymentOp().Destination.ToAccountId())
+		case xdr.OperationTypePathPaymentStrictReceive:
+			accounts = append(accounts, body.MustPathPaymentStrictReceiveOp().Destination.ToAccountId())
+		case xdr.OperationTypePathPaymentStrictSend:
+			accounts = append(accounts, body.MustPathPaymentStrictSendOp().Destination.ToAccountId())
+		case xdr.OperationTypeAccountMerge:
+			accounts = append(accounts, body.MustDestination().ToAccountId())
+		case xdr.OperationTypeAllowTrust:
+			accounts = append(accounts, body.MustAllowTrustOp().Trustor)
+		case xdr.OperationTypeSetTrustLineFlags:
+			accounts = append(accounts, body.MustSetTrustLineFlagsOp().Trustor)
+		case xdr.OperationTypeClawback:
+			accounts = append(accounts, body.MustClawbackOp().From.ToAccountId())
+		}
+	}
+	return accounts
+}
+
+// ledgerEntryAccounts returns the accounts owning the ledger entry, or the
+// claimants of a claimable balance.
+func ledgerEntryAccounts(entry xdr.LedgerEntry) []xdr.AccountId {
+	switch entry.Data.Type {
+	case xdr.LedgerEntryTypeAccount:
+		return []xdr.AccountId{entry.Data.MustAccount().AccountId}
+	case xdr.LedgerEntryTypeTrustline:
+		return []xdr.AccountId{entry.Data.MustTrustLine().AccountId}
+	case xdr.LedgerEntryTypeOffer:
+		return []xdr.AccountId{entry.Data.MustOffer().SellerId}
+	case xdr.LedgerEntryTypeData:
+		return []xdr.AccountId{entry.Data.MustData().AccountId}
+	case xdr.LedgerEntryTypeClaimableBalance:
+		var accounts []xdr.AccountId
+		for _, claimant := range entry.Data.MustClaimableBalance().Claimants {
+			accounts = append(accounts, claimant.MustV0().Destination)
+		}
+		return accounts
+	default:
+		return nil
+	}
+}
+
+// operationAssets returns the assets referred to by the operation, whose
+// source account is source.
+func operationAssets(op xdr.Operation, source xdr.AccountId) []xdr.Asset {
+	body := op.Body
+	switch body.Type {
+	case xdr.OperationTypePayment:
+		return []xdr.Asset{body.MustPaymentOp().Asset}
+	case xdr.OperationTypePathPaymentStrictReceive:
+		payment := body.MustPathPaymentStrictReceiveOp()
+		return append([]xdr.Asset{payment.SendAsset, payment.DestAsset}, payment.Path...)
+	case xdr.OperationTypePathPaymentStrictSend:
+		payment := body.MustPathPaymentStrictSendOp()
+		return append([]xdr.Asset{payment.SendAsset, payment.DestAsset}, payment.Path...)
+	case xdr.OperationTypeManageSellOffer:
+		offer := body.MustManageSellOfferOp()
+		return []xdr.Asset{offer.Selling, offer.Buying}
+	case xdr.OperationTypeCreatePassiveSellOffer:
+		offer := body.MustCreatePassiveSellOfferOp()
+		return []xdr.Asset{offer.Selling, offer.Buying}
+	case xdr.OperationTypeManageBuyOffer:
+		offer := body.MustManageBuyOfferOp()
+		return []xdr.Asset{offer.Selling, offer.Buying}
+	case xdr.OperationTypeChangeTrust:
+		return []xdr.Asset{body.MustChangeTrustOp().Line}
+	case xdr.OperationTypeAllowTrust:
+		// The asset of AllowTrust is issued by the source account.
+		return []xdr.Asset{body.MustAllowTrustOp().Asset.ToAsset(source)}
+	case xdr.OperationTypeCreateClaimableBalance:
+		return []xdr.Asset{body.MustCreateClaimableBalanceOp().Asset}
+	case xdr.OperationTypeClawback:
+		return []xdr.Asset{body.MustClawbackOp().Asset}
+	case xdr.OperationTypeSetTrustLineFlags:
+		return []xdr.Asset{body.MustSetTrustLineFlagsOp().Asset}
+	default:
+		return nil
+	}
+}
+
+// ledgerEntryAssets returns the assets of a trust line, offer or claimable
+// balance.
+func ledgerEntryAssets(entry xdr.LedgerEntry) []xdr.Asset {
+	switch entry.Data.Type {
+	case xdr.LedgerEntryTypeTrustline:
+		return []xdr.Asset{entry.Data.MustTrustLine().Asset}
+	case xdr.LedgerEntryTypeOffer:
+		offer := entry.Data.MustOffer()
+		return []xdr.Asset{offer.Selling, offer.Buying}
+	case xdr.LedgerEntryTypeClaimableBalance:
+		return []xdr.Asset{entry.Data.MustClaimableBalance().Asset}
+	default:
+		return nil
+	}
+}
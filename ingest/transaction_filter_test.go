@@ -0,0 +1,146 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	filterSource    = "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A"
+	filterRecipient = "GCO26ZSBD63TKYX45H2C7D2WOFWOUSG5BMTNC3BG4QMXM3PAYI6WHKVZ"
+	filterIssuer    = "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"
+	filterOther     = "GBFLTCDLOE6YQ74B66RH3S2UW5I2MKZ5VLTM75F4YMIWUIXRIFVNRNIF"
+)
+
+func filterTestTransaction(index uint32, ops []xdr.Operation, changes xdr.LedgerEntryChanges) LedgerTransaction {
+	return LedgerTransaction{
+		Index: index,
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: xdr.MustMuxedAddress(filterSource),
+					Operations:    ops,
+				},
+			},
+		},
+		UnsafeMeta: xdr.TransactionMeta{
+			V: 1,
+			V1: &xdr.TransactionMetaV1{
+				Operations: []xdr.OperationMeta{{Changes: changes}},
+			},
+		},
+	}
+}
+
+func filterTestTransactions() []LedgerTransaction {
+	usd := xdr.MustNewCreditAsset("USD", filterIssuer)
+	return []LedgerTransaction{
+		// A payment of USD to the recipient.
+		filterTestTransaction(1, []xdr.Operation{{
+			Body: xdr.OperationBody{
+				Type: xdr.OperationTypePayment,
+				PaymentOp: &xdr.PaymentOp{
+					Destination: xdr.MustMuxedAddress(filterRecipient),
+					Asset:       usd,
+					Amount:      10,
+				},
+			},
+		}}, nil),
+		// A bump sequence whose meta changes a USD trust line of another
+		// account.
+		filterTestTransaction(2, []xdr.Operation{{
+			Body: xdr.OperationBody{
+				Type:           xdr.OperationTypeBumpSequence,
+				BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 10},
+			},
+		}}, xdr.LedgerEntryChanges{{
+			Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated,
+			Created: &xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{
+					Type: xdr.LedgerEntryTypeTrustline,
+					TrustLine: &xdr.TrustLineEntry{
+						AccountId: xdr.MustAddress(filterOther),
+						Asset:     usd,
+					},
+				},
+			},
+		}}),
+		// An AllowTrust of USD by its issuer.
+		filterTestTransaction(3, []xdr.Operation{{
+			SourceAccount: xdr.MustMuxedAddressPtr(filterIssuer),
+			Body: xdr.OperationBody{
+				Type: xdr.OperationTypeAllowTrust,
+				AllowTrustOp: &xdr.AllowTrustOp{
+					Trustor: xdr.MustAddress(filterRecipient),
+					Asset:   xdr.MustNewAssetCodeFromString("USD"),
+				},
+			},
+		}}, nil),
+	}
+}
+
+func readFiltered(t *testing.T, filters ...TransactionFilter) []uint32 {
+	reader := &LedgerTransactionReader{transactions: filterTestTransactions()}
+	txs, err := FilterTransactions(context.Background(), reader, filters...)
+	require.NoError(t, err)
+	var indexes []uint32
+	for _, tx := range txs {
+		indexes = append(indexes, tx.Index)
+	}
+	return indexes
+}
+
+func TestFilteredLedgerTransactionReader(t *testing.T) {
+	usd := xdr.MustNewCreditAsset("USD", filterIssuer)
+	eur := xdr.MustNewCreditAsset("EUR", filterIssuer)
+
+	assert.Equal(t, []uint32{1, 2, 3}, readFiltered(t))
+	assert.Equal(t, []uint32{1, 2, 3}, readFiltered(t, AssetFilter(usd)))
+	assert.Empty(t, readFiltered(t, AssetFilter(eur)))
+
+	assert.Equal(t, []uint32{1, 2, 3}, readFiltered(t, AccountFilter(filterSource)))
+	assert.Equal(t, []uint32{1, 3}, readFiltered(t, AccountFilter(filterRecipient)))
+	assert.Equal(t, []uint32{2}, readFiltered(t, AccountFilter(filterOther)))
+	assert.Equal(t, []uint32{3}, readFiltered(t, AccountFilter(filterIssuer)))
+
+	assert.Equal(t, []uint32{2}, readFiltered(t, OperationTypeFilter(xdr.OperationTypeBumpSequence)))
+	assert.Equal(t, []uint32{1, 3}, readFiltered(t, OperationTypeFilter(xdr.OperationTypePayment, xdr.OperationTypeAllowTrust)))
+
+	// The filters are combined.
+	assert.Equal(t, []uint32{1}, readFiltered(t, AssetFilter(usd), AccountFilter(filterRecipient), OperationTypeFilter(xdr.OperationTypePayment)))
+	assert.Equal(t, []uint32{1, 2}, readFiltered(t, AnyTransactionFilter(
+		OperationTypeFilter(xdr.OperationTypePayment),
+		AccountFilter(filterOther),
+	)))
+}
+
+func TestFilteredLedgerTransactionReaderError(t *testing.T) {
+	reader := NewFilteredLedgerTransactionReader(
+		context.Background(),
+		&LedgerTransactionReader{transactions: filterTestTransactions()},
+		TransactionFilterFunc(func(ctx context.Context, tx LedgerTransaction) (bool, error) {
+			if tx.Index == 2 {
+				return false, errors.New("filter error")
+			}
+			return true, nil
+		}),
+	)
+
+	tx, err := reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), tx.Index)
+	_, err = reader.Read()
+	assert.EqualError(t, err, "error filtering transaction 2: filter error")
+	tx, err = reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), tx.Index)
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
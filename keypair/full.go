@@ -69,6 +69,22 @@ func (kp *Full) SignDecorated(input []byte) (xdr.DecoratedSignature, error) {
 	}, nil
 }
 
+// SignPayloadDecorated signs payload and returns the decorated signature
+// expected by an ed25519 signed payload signer of this keypair and payload.
+// Its hint is the hint of the keypair XORed with the last 4 bytes of the
+// payload, or fewer if the payload is shorter.
+func (kp *Full) SignPayloadDecorated(payload []byte) (xdr.DecoratedSignature, error) {
+	sig, err := kp.SignDecorated(payload)
+	if err != nil {
+		return xdr.DecoratedSignature{}, err
+	}
+
+	for i := 0; i < len(sig.Hint) && i < len(payload); i++ {
+		sig.Hint[len(sig.Hint)-1-i] ^= payload[len(payload)-1-i]
+	}
+	return sig, nil
+}
+
 func (kp *Full) publicKey() ed25519.PublicKey {
 	pub, _ := kp.keys()
 	return pub
@@ -68,4 +68,18 @@ var _ = Describe("keypair.Full", func() {
 		})
 	})
 
+	Describe("SignPayloadDecorated()", func() {
+		It("xors the hint with the end of the payload", func() {
+			payload := []byte{1, 2, 3, 4, 5}
+			sig, err := subject.(*Full).SignPayloadDecorated(payload)
+			Expect(err).To(BeNil())
+			Expect(sig.Hint).To(BeEquivalentTo([4]byte{hint[0] ^ 2, hint[1] ^ 3, hint[2] ^ 4, hint[3] ^ 5}))
+			Expect(subject.Verify(payload, sig.Signature)).To(Succeed())
+
+			sig, err = subject.(*Full).SignPayloadDecorated([]byte{1})
+			Expect(err).To(BeNil())
+			Expect(sig.Hint).To(BeEquivalentTo([4]byte{hint[0], hint[1], hint[2], hint[3] ^ 1}))
+		})
+	})
+
 })
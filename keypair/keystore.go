@@ -0,0 +1,243 @@
+package keypair
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KeystoreVersion is the version of the keystore format written by
+// SaveEncrypted.
+const KeystoreVersion = 1
+
+// KDF is a key derivation function deriving the encryption key of a
+// keystore from its password.
+type KDF string
+
+const (
+	// KDFScrypt derives keys with scrypt (N=2^18, r=8, p=1).
+	KDFScrypt KDF = "scrypt"
+	// KDFArgon2id derives keys with Argon2id (t=3, m=64MiB, p=4).
+	KDFArgon2id KDF = "argon2id"
+)
+
+// ErrInvalidPassword is returned by LoadEncrypted when the password does not
+// decrypt the keystore, either because it is wrong or because the keystore
+// was tampered with.
+var ErrInvalidPassword = errors.New("invalid keystore password")
+
+// The KDF parameters used for new keystores. They are variables so that
+// tests can lower them.
+var (
+	scryptN        = 1 << 18
+	argon2Time     = uint32(3)
+	argon2MemoryKB = uint32(64 * 1024)
+)
+
+const (
+	keystoreCipher  = "aes-256-gcm"
+	keystoreKeyLen  = 32
+	keystoreSaltLen = 32
+)
+
+// keystore is the JSON representation of an encrypted keystore:
+//
+//	{
+//	  "version": 1,
+//	  "address": "G...",
+//	  "crypto": {
+//	    "cipher": "aes-256-gcm",
+//	    "ciphertext": "...",
+//	    "nonce": "...",
+//	    "kdf": "scrypt",
+//	    "kdfparams": {"salt": "...", "n": 262144, "r": 8, "p": 1, "keylen": 32}
+//	  }
+//	}
+//
+// The address is stored in clear so that keystores can be identified without
+// their password, and is authenticated as the additional data of the cipher.
+type keystore struct {
+	Version int            `json:"version"`
+	Address string         `json:"address"`
+	Crypto  keystoreCrypto `json:"crypto"`
+}
+
+type keystoreCrypto struct {
+	Cipher     string            `json:"cipher"`
+	Ciphertext string            `json:"ciphertext"`
+	Nonce      string            `json:"nonce"`
+	KDF        KDF               `json:"kdf"`
+	KDFParams  keystoreKDFParams `json:"kdfparams"`
+}
+
+type keystoreKDFParams struct {
+	Salt   string `json:"salt"`
+	KeyLen int    `json:"keylen"`
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// SaveEncrypted writes the seed of kp to the file at path, encrypted with a
+// key derived from password by kdf. The file is created with 0600
+// permissions and overwritten if it exists.
+func SaveEncrypted(path string, kp *Full, password string, kdf KDF) error {
+	data, err := EncryptKeystore(kp, password, kdf)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// LoadEncrypted reads the keystore file at path written by SaveEncrypted and
+// decrypts it with password.
+func LoadEncrypted(path string, password string) (*Full, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptKeystore(data, password)
+}
+
+// EncryptKeystore returns the JSON keystore of kp, see SaveEncrypted.
+func EncryptKeystore(kp *Full, password string, kdf KDF) ([]byte, error) {
+	params := keystoreKDFParams{KeyLen: keystoreKeyLen}
+	switch kdf {
+	case KDFScrypt:
+		params.N, params.R, params.P = scryptN, 8, 1
+	case KDFArgon2id:
+		params.Time, params.Memory, params.Threads = argon2Time, argon2MemoryKB, 4
+	default:
+		return nil, fmt.Errorf("unsupported kdf %q", kdf)
+	}
+
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	params.Salt = hex.EncodeToString(salt)
+
+	key, err := deriveKeystoreKey(kdf, params, password)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newKeystoreAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	address := kp.Address()
+	ciphertext := aead.Seal(nil, nonce, kp.rawSeed(), []byte(address))
+	return json.MarshalIndent(keystore{
+		Version: KeystoreVersion,
+		Address: address,
+		Crypto: keystoreCrypto{
+			Cipher:     keystoreCipher,
+			Ciphertext: hex.EncodeToString(ciphertext),
+			Nonce:      hex.EncodeToString(nonce),
+			KDF:        kdf,
+			KDFParams:  params,
+		},
+	}, "", "  ")
+}
+
+// DecryptKeystore decrypts the JSON keystore data with password, see
+// LoadEncrypted.
+func DecryptKeystore(data []byte, password string) (*Full, error) {
+	var ks keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("could not parse keystore: %w", err)
+	}
+	if ks.Version != KeystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+	if ks.Crypto.Cipher != keystoreCipher {
+		return nil, fmt.Errorf("unsupported keystore cipher %q", ks.Crypto.Cipher)
+	}
+	if ks.Crypto.KDFParams.KeyLen != keystoreKeyLen {
+		return nil, fmt.Errorf("unsupported keystore key length %d", ks.Crypto.KDFParams.KeyLen)
+	}
+	ciphertext, err := hex.DecodeString(ks.Crypto.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+	nonce, err := hex.DecodeString(ks.Crypto.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore nonce: %w", err)
+	}
+
+	key, err := deriveKeystoreKey(ks.Crypto.KDF, ks.Crypto.KDFParams, password)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newKeystoreAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid keystore nonce length %d", len(nonce))
+	}
+	rawSeed, err := aead.Open(nil, nonce, ciphertext, []byte(ks.Address))
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	if len(rawSeed) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	var seed [32]byte
+	copy(seed[:], rawSeed)
+	kp, err := FromRawSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	if kp.Address() != ks.Address {
+		return nil, ErrInvalidKey
+	}
+	return kp, nil
+}
+
+func deriveKeystoreKey(kdf KDF, params keystoreKDFParams, password string) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+
+	switch kdf {
+	case KDFScrypt:
+		return scrypt.Key([]byte(password), salt, params.N, params.R, params.P, params.KeyLen)
+	case KDFArgon2id:
+		if params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+			return nil, errors.New("invalid argon2id parameters")
+		}
+		return argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(params.KeyLen)), nil
+	default:
+		return nil, fmt.Errorf("unsupported kdf %q", kdf)
+	}
+}
+
+func newKeystoreAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
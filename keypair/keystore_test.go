@@ -0,0 +1,75 @@
+package keypair
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lowerKeystoreKDFParams lowers the KDF parameters to speed up tests, and
+// returns a function restoring them.
+func lowerKeystoreKDFParams() func() {
+	n, iterations, memory := scryptN, argon2Time, argon2MemoryKB
+	scryptN, argon2Time, argon2MemoryKB = 1<<10, 1, 1024
+	return func() {
+		scryptN, argon2Time, argon2MemoryKB = n, iterations, memory
+	}
+}
+
+func TestSaveLoadEncrypted(t *testing.T) {
+	defer lowerKeystoreKDFParams()()
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	kp := MustRandom()
+	for _, kdf := range []KDF{KDFScrypt, KDFArgon2id} {
+		t.Run(string(kdf), func(t *testing.T) {
+			path := filepath.Join(dir, string(kdf)+".json")
+			require.NoError(t, SaveEncrypted(path, kp, "correct horse", kdf))
+
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+			data, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), kp.Address())
+			assert.NotContains(t, string(data), kp.Seed())
+
+			loaded, err := LoadEncrypted(path, "correct horse")
+			require.NoError(t, err)
+			assert.Equal(t, kp.Seed(), loaded.Seed())
+
+			_, err = LoadEncrypted(path, "wrong horse")
+			assert.Equal(t, ErrInvalidPassword, err)
+		})
+	}
+}
+
+func TestDecryptKeystoreErrors(t *testing.T) {
+	defer lowerKeystoreKDFParams()()
+	kp := MustRandom()
+	data, err := EncryptKeystore(kp, "password", KDFScrypt)
+	require.NoError(t, err)
+
+	// the address is authenticated
+	tampered := strings.Replace(string(data), kp.Address(), MustRandom().Address(), 1)
+	_, err = DecryptKeystore([]byte(tampered), "password")
+	assert.Equal(t, ErrInvalidPassword, err)
+
+	unsupported := strings.Replace(string(data), `"version": 1`, `"version": 2`, 1)
+	_, err = DecryptKeystore([]byte(unsupported), "password")
+	assert.EqualError(t, err, "unsupported keystore version 2")
+
+	_, err = DecryptKeystore([]byte("{"), "password")
+	assert.EqualError(t, err, "could not parse keystore: unexpected end of JSON input")
+
+	_, err = EncryptKeystore(kp, "password", KDF("pbkdf2"))
+	assert.EqualError(t, err, `unsupported kdf "pbkdf2"`)
+}
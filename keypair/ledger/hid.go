@@ -0,0 +1,75 @@
+package ledger
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	hidChannel    = 0x0101
+	hidTagAPDU    = 0x05
+	hidPacketSize = 64
+)
+
+// HIDTransport is a Transport framing APDUs in the HID reports of Ledger
+// devices. Device reads and writes raw HID reports, e.g. a /dev/hidraw*
+// device file on Linux opened with os.OpenFile(path, os.O_RDWR, 0).
+type HIDTransport struct {
+	Device io.ReadWriter
+}
+
+// Exchange sends the APDU command to the device and returns its response.
+func (t HIDTransport) Exchange(command []byte) ([]byte, error) {
+	for _, packet := range hidFrame(command) {
+		// the first byte is the report ID, 0 for devices without numbered
+		// reports such as Ledger devices
+		if _, err := t.Device.Write(append([]byte{0x00}, packet...)); err != nil {
+			return nil, errors.Wrap(err, "could not write to device")
+		}
+	}
+
+	var resp []byte
+	var length int
+	for sequence := uint16(0); sequence == 0 || len(resp) < length; sequence++ {
+		packet := make([]byte, hidPacketSize)
+		if _, err := io.ReadFull(t.Device, packet); err != nil {
+			return nil, errors.Wrap(err, "could not read from device")
+		}
+		if binary.BigEndian.Uint16(packet) != hidChannel || packet[2] != hidTagAPDU {
+			return nil, errors.New("unexpected packet header")
+		}
+		if binary.BigEndian.Uint16(packet[3:]) != sequence {
+			return nil, errors.Errorf("unexpected packet sequence %d", binary.BigEndian.Uint16(packet[3:]))
+		}
+
+		data := packet[5:]
+		if sequence == 0 {
+			length = int(binary.BigEndian.Uint16(data))
+			data = data[2:]
+		}
+		resp = append(resp, data...)
+	}
+	return resp[:length], nil
+}
+
+// hidFrame splits the APDU command in HID packets, the first one starting
+// with the length of the command.
+func hidFrame(command []byte) [][]byte {
+	data := make([]byte, 2+len(command))
+	binary.BigEndian.PutUint16(data, uint16(len(command)))
+	copy(data[2:], command)
+
+	var packets [][]byte
+	for sequence := uint16(0); len(data) > 0; sequence++ {
+		packet := make([]byte, hidPacketSize)
+		binary.BigEndian.PutUint16(packet, hidChannel)
+		packet[2] = hidTagAPDU
+		binary.BigEndian.PutUint16(packet[3:], sequence)
+		n := copy(packet[5:], data)
+		data = data[n:]
+		packets = append(packets, packet)
+	}
+	return packets
+}
@@ -0,0 +1,162 @@
+// Package ledger provides a keypair.Signer keeping its secret key on a Ledger
+// hardware wallet running the Stellar app. The signer signs transaction
+// hashes, which requires hash signing to be enabled in the app's settings.
+package ledger
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// APDU constants of the Stellar app.
+const (
+	cla           = 0xe0
+	insGetPK      = 0x02
+	insSignTxHash = 0x08
+	p1First       = 0x00
+	p2NoConfirm   = 0x00
+
+	statusOK                  = 0x9000
+	statusRejected            = 0x6985
+	statusHashSigningDisabled = 0x6c66
+)
+
+var (
+	// ErrRejected is returned when the user rejects a request on the device.
+	ErrRejected = errors.New("request rejected on the device")
+
+	// ErrHashSigningDisabled is returned when signing a hash while hash
+	// signing is not enabled in the Stellar app's settings.
+	ErrHashSigningDisabled = errors.New("hash signing is not enabled in the Stellar app")
+)
+
+// StatusError is returned when the device responds with an unexpected status
+// word.
+type StatusError struct {
+	Status uint16
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("device responded with status 0x%04x", e.Status)
+}
+
+// Transport exchanges APDUs with a Ledger device, see HIDTransport.
+type Transport interface {
+	// Exchange sends the APDU command to the device and returns its
+	// response, ending with the status word.
+	Exchange(command []byte) ([]byte, error)
+}
+
+// Signer is a keypair.Signer signing with the key derived at the path
+// m/44'/148'/account' on a Ledger device.
+type Signer struct {
+	transport Transport
+	path      []uint32
+	address   string
+}
+
+var _ keypair.Signer = (*Signer)(nil)
+
+// NewSigner returns a Signer for the given account index of the device,
+// reading its public key from the device.
+func NewSigner(transport Transport, account uint32) (*Signer, error) {
+	s := &Signer{
+		transport: transport,
+		path:      []uint32{hardened(44), hardened(148), hardened(account)},
+	}
+
+	resp, err := s.exchange(insGetPK, p2NoConfirm, s.pathData())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get public key")
+	}
+	if len(resp) < 32 {
+		return nil, errors.Errorf("public key response is %d bytes long", len(resp))
+	}
+	s.address, err = strkey.Encode(strkey.VersionByteAccountID, resp[:32])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode public key")
+	}
+	return s, nil
+}
+
+func hardened(i uint32) uint32 {
+	return 0x80000000 | i
+}
+
+// Address returns the address of the key of the signer.
+func (s *Signer) Address() string {
+	return s.address
+}
+
+// Hint returns the signature hint of the key of the signer.
+func (s *Signer) Hint() [4]byte {
+	return keypair.MustParseAddress(s.address).Hint()
+}
+
+// Sign signs the 32-byte hash of a transaction on the device.
+func (s *Signer) Sign(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, errors.Errorf("hash must be 32 bytes long, got %d", len(hash))
+	}
+
+	resp, err := s.exchange(insSignTxHash, p2NoConfirm, append(s.pathData(), hash...))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign hash")
+	}
+	if len(resp) != 64 {
+		return nil, errors.Errorf("signature response is %d bytes long", len(resp))
+	}
+	return resp, nil
+}
+
+// SignDecorated signs the 32-byte hash of a transaction on the device.
+func (s *Signer) SignDecorated(hash []byte) (xdr.DecoratedSignature, error) {
+	sig, err := s.Sign(hash)
+	if err != nil {
+		return xdr.DecoratedSignature{}, err
+	}
+
+	return xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(s.Hint()),
+		Signature: xdr.Signature(sig),
+	}, nil
+}
+
+func (s *Signer) pathData() []byte {
+	data := make([]byte, 1+4*len(s.path))
+	data[0] = byte(len(s.path))
+	for i, component := range s.path {
+		binary.BigEndian.PutUint32(data[1+4*i:], component)
+	}
+	return data
+}
+
+// exchange sends a command of the Stellar app and returns the data of the
+// response without its status word.
+func (s *Signer) exchange(ins, p2 byte, data []byte) ([]byte, error) {
+	command := append([]byte{cla, ins, p1First, p2, byte(len(data))}, data...)
+	resp, err := s.transport.Exchange(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp) < 2 {
+		return nil, errors.New("response has no status word")
+	}
+
+	status := binary.BigEndian.Uint16(resp[len(resp)-2:])
+	switch status {
+	case statusOK:
+		return resp[:len(resp)-2], nil
+	case statusRejected:
+		return nil, ErrRejected
+	case statusHashSigningDisabled:
+		return nil, ErrHashSigningDisabled
+	default:
+		return nil, StatusError{Status: status}
+	}
+}
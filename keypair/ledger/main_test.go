@@ -0,0 +1,121 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDevice is a Transport emulating the Stellar app with a keypair.
+type fakeDevice struct {
+	kp       *keypair.Full
+	status   uint16
+	commands [][]byte
+}
+
+func (d *fakeDevice) Exchange(command []byte) ([]byte, error) {
+	d.commands = append(d.commands, command)
+	if d.status != 0 {
+		return []byte{byte(d.status >> 8), byte(d.status)}, nil
+	}
+
+	var data []byte
+	switch command[1] {
+	case insGetPK:
+		data = strkey.MustDecode(strkey.VersionByteAccountID, d.kp.Address())
+	case insSignTxHash:
+		hash := command[len(command)-32:]
+		data, _ = d.kp.Sign(hash)
+	}
+	return append(data, 0x90, 0x00), nil
+}
+
+func TestSigner(t *testing.T) {
+	kp := keypair.MustRandom()
+	device := &fakeDevice{kp: kp}
+
+	signer, err := NewSigner(device, 1)
+	require.NoError(t, err)
+	assert.Equal(t, kp.Address(), signer.Address())
+	assert.Equal(t, kp.Hint(), signer.Hint())
+	assert.Equal(t, []byte{
+		cla, insGetPK, 0x00, 0x00, 13,
+		3, 0x80, 0, 0, 44, 0x80, 0, 0, 148, 0x80, 0, 0, 1,
+	}, device.commands[0])
+
+	hash := bytes.Repeat([]byte{0xab}, 32)
+	sig, err := signer.SignDecorated(hash)
+	require.NoError(t, err)
+	expected, err := kp.SignDecorated(hash)
+	require.NoError(t, err)
+	assert.Equal(t, expected, sig)
+
+	_, err = signer.Sign(hash[:31])
+	assert.EqualError(t, err, "hash must be 32 bytes long, got 31")
+
+	device.status = statusHashSigningDisabled
+	_, err = signer.Sign(hash)
+	assert.EqualError(t, err, "could not sign hash: hash signing is not enabled in the Stellar app")
+
+	device.status = statusRejected
+	_, err = signer.Sign(hash)
+	assert.EqualError(t, err, "could not sign hash: request rejected on the device")
+
+	device.status = 0x6d00
+	_, err = NewSigner(device, 0)
+	assert.EqualError(t, err, "could not get public key: device responded with status 0x6d00")
+}
+
+// fakeHIDDevice is a HID device answering the commands framed in the reports
+// written to it with a Transport.
+type fakeHIDDevice struct {
+	transport Transport
+	command   []byte
+	length    int
+	responses bytes.Buffer
+}
+
+func (d *fakeHIDDevice) Write(report []byte) (int, error) {
+	packet := report[1:]
+	data := packet[5:]
+	if binary.BigEndian.Uint16(packet[3:]) == 0 {
+		d.command = nil
+		d.length = int(binary.BigEndian.Uint16(data))
+		data = data[2:]
+	}
+	d.command = append(d.command, data...)
+	if len(d.command) >= d.length {
+		resp, err := d.transport.Exchange(d.command[:d.length])
+		if err != nil {
+			return 0, err
+		}
+		for _, packet := range hidFrame(resp) {
+			d.responses.Write(packet)
+		}
+	}
+	return len(report), nil
+}
+
+func (d *fakeHIDDevice) Read(p []byte) (int, error) {
+	return d.responses.Read(p)
+}
+
+func TestHIDTransport(t *testing.T) {
+	kp := keypair.MustRandom()
+	transport := HIDTransport{Device: &fakeHIDDevice{transport: &fakeDevice{kp: kp}}}
+
+	signer, err := NewSigner(transport, 0)
+	require.NoError(t, err)
+	assert.Equal(t, kp.Address(), signer.Address())
+
+	// the signature response spans two packets
+	hash := bytes.Repeat([]byte{0x01}, 32)
+	sig, err := signer.Sign(hash)
+	require.NoError(t, err)
+	assert.NoError(t, kp.Verify(hash, sig))
+}
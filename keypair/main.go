@@ -42,6 +42,16 @@ type KP interface {
 	SignDecorated(input []byte) (xdr.DecoratedSignature, error)
 }
 
+// Signer signs transaction hashes on behalf of an account. It is
+// implemented by Full, and by signers keeping the secret key elsewhere, e.g.
+// on a hardware wallet (see the keypair/ledger package).
+type Signer interface {
+	Address() string
+	SignDecorated(input []byte) (xdr.DecoratedSignature, error)
+}
+
+var _ Signer = (*Full)(nil)
+
 // Random creates a random full keypair
 func Random() (*Full, error) {
 	var rawSeed [32]byte
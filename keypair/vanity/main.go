@@ -0,0 +1,200 @@
+// Package vanity searches for keypairs whose address starts or ends with
+// chosen characters, such as GABC...XYZ, generating random keypairs on all CPU
+// cores until one matches.
+//
+// Addresses are base32 encoded, so each character of the pattern multiplies
+// the expected number of keypairs generated by 32: patterns of more than 6 or
+// 7 characters take hours to days to find on a typical machine.
+package vanity
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// alphabet is the base32 alphabet of the addresses.
+const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+
+// secondCharacters are the characters an address can have after its leading
+// G, which encodes the version byte along with the first bits of the key.
+const secondCharacters = "ABCD"
+
+// batchSize is the number of keypairs a worker generates between checks of
+// the context and updates of the number of attempts.
+const batchSize = 256
+
+// Options configures a Search.
+type Options struct {
+	// Prefix is the characters the address must start with after its
+	// leading G. The first character must be A, B, C or D.
+	Prefix string
+	// Suffix is the characters the address must end with.
+	Suffix string
+	// Workers is the number of goroutines generating keypairs. Defaults to
+	// runtime.NumCPU().
+	Workers int
+	// Progress, if set, is called every ProgressInterval while searching.
+	Progress func(Progress)
+	// ProgressInterval defaults to one second.
+	ProgressInterval time.Duration
+}
+
+// Progress reports the progress of a Search.
+type Progress struct {
+	// Attempts is the number of keypairs generated so far.
+	Attempts uint64
+	// Elapsed is the time elapsed since the search started.
+	Elapsed time.Duration
+	// ExpectedAttempts is the average number of keypairs generated to find
+	// a match, see ExpectedAttempts.
+	ExpectedAttempts float64
+}
+
+// Rate returns the number of keypairs generated per second.
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Attempts) / p.Elapsed.Seconds()
+}
+
+// normalize validates the prefix and suffix and returns them in upper case.
+func normalize(prefix, suffix string) (string, string, error) {
+	prefix = strings.ToUpper(prefix)
+	suffix = strings.ToUpper(suffix)
+	if prefix == "" && suffix == "" {
+		return "", "", errors.New("prefix and suffix cannot both be empty")
+	}
+	// The leading G and the checksum leave 55 characters.
+	if len(prefix)+len(suffix) > 55 {
+		return "", "", errors.New("prefix and suffix are longer than an address")
+	}
+	for _, s := range []string{prefix, suffix} {
+		for _, c := range s {
+			if !strings.ContainsRune(alphabet, c) {
+				return "", "", errors.Errorf("invalid character %q, addresses only contain the letters A to Z and the digits 2 to 7", c)
+			}
+		}
+	}
+	if prefix != "" && !strings.ContainsRune(secondCharacters, rune(prefix[0])) {
+		return "", "", errors.Errorf("prefix cannot start with %q, the character after the leading G of addresses is A, B, C or D", prefix[0])
+	}
+	return prefix, suffix, nil
+}
+
+// ExpectedAttempts returns the average number of keypairs generated to find
+// an address with the given prefix and suffix.
+func ExpectedAttempts(prefix, suffix string) (float64, error) {
+	prefix, suffix, err := normalize(prefix, suffix)
+	if err != nil {
+		return 0, err
+	}
+	attempts := math.Pow(32, float64(len(prefix)+len(suffix)))
+	if prefix != "" {
+		// the second character of addresses has 4 possible values
+		attempts /= 8
+	}
+	return attempts, nil
+}
+
+// Search generates random keypairs until one has an address matching the
+// prefix and suffix of opts, and returns it. It returns the error of ctx if
+// ctx is done first.
+func Search(ctx context.Context, opts Options) (*keypair.Full, error) {
+	prefix, suffix, err := normalize(opts.Prefix, opts.Suffix)
+	if err != nil {
+		return nil, err
+	}
+	expected, _ := ExpectedAttempts(prefix, suffix)
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		attempts uint64
+		found    = make(chan *keypair.Full, 1)
+		errs     = make(chan error, 1)
+		wg       sync.WaitGroup
+	)
+	prefix = "G" + prefix
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				for j := 0; j < batchSize; j++ {
+					kp, err := keypair.Random()
+					if err != nil {
+						select {
+						case errs <- errors.Wrap(err, "generating keypair"):
+						default:
+						}
+						cancel()
+						return
+					}
+					address := kp.Address()
+					if strings.HasPrefix(address, prefix) && strings.HasSuffix(address, suffix) {
+						atomic.AddUint64(&attempts, uint64(j+1))
+						select {
+						case found <- kp:
+						default:
+						}
+						cancel()
+						return
+					}
+				}
+				atomic.AddUint64(&attempts, batchSize)
+			}
+		}()
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case kp := <-found:
+			cancel()
+			wg.Wait()
+			return kp, nil
+		case err := <-errs:
+			wg.Wait()
+			return nil, err
+		case <-ctx.Done():
+			wg.Wait()
+			// a worker may have found a keypair as ctx was cancelled
+			select {
+			case kp := <-found:
+				return kp, nil
+			case err := <-errs:
+				return nil, err
+			default:
+				return nil, ctx.Err()
+			}
+		case <-ticker.C:
+			if opts.Progress != nil {
+				opts.Progress(Progress{
+					Attempts:         atomic.LoadUint64(&attempts),
+					Elapsed:          time.Since(start),
+					ExpectedAttempts: expected,
+				})
+			}
+		}
+	}
+}
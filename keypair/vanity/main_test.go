@@ -0,0 +1,85 @@
+package vanity
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	kp, err := Search(context.Background(), Options{Prefix: "a", Suffix: "2", Workers: 2})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(kp.Address(), "GA"), kp.Address())
+	assert.True(t, strings.HasSuffix(kp.Address(), "2"), kp.Address())
+
+	kp, err = Search(context.Background(), Options{Suffix: "XY"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(kp.Address(), "XY"), kp.Address())
+}
+
+func TestSearchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var progress int32
+	var last Progress
+	_, err := Search(ctx, Options{
+		Prefix:           "ABCDEFGHIJKL",
+		ProgressInterval: time.Millisecond,
+		Progress: func(p Progress) {
+			last = p
+			if atomic.AddInt32(&progress, 1) == 3 {
+				cancel()
+			}
+		},
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, atomic.LoadInt32(&progress) >= 3)
+	assert.Equal(t, float64(1<<57), last.ExpectedAttempts)
+	assert.True(t, last.Elapsed > 0)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = Search(ctx, Options{Suffix: "ABCDEFGHIJKL"})
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestInvalidPatterns(t *testing.T) {
+	for _, tc := range []struct {
+		prefix, suffix string
+		err            string
+	}{
+		{"", "", "prefix and suffix cannot both be empty"},
+		{"A1", "", `invalid character '1', addresses only contain the letters A to Z and the digits 2 to 7`},
+		{"", "a-", `invalid character '-', addresses only contain the letters A to Z and the digits 2 to 7`},
+		{"EFG", "", `prefix cannot start with 'E', the character after the leading G of addresses is A, B, C or D`},
+		{"A", strings.Repeat("A", 55), "prefix and suffix are longer than an address"},
+	} {
+		_, err := Search(context.Background(), Options{Prefix: tc.prefix, Suffix: tc.suffix})
+		assert.EqualError(t, err, tc.err)
+		_, err = ExpectedAttempts(tc.prefix, tc.suffix)
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+func TestExpectedAttempts(t *testing.T) {
+	attempts, err := ExpectedAttempts("", "abc")
+	require.NoError(t, err)
+	assert.Equal(t, float64(32*32*32), attempts)
+
+	attempts, err = ExpectedAttempts("d", "")
+	require.NoError(t, err)
+	assert.Equal(t, float64(4), attempts)
+
+	attempts, err = ExpectedAttempts("bc", "7")
+	require.NoError(t, err)
+	assert.Equal(t, float64(4*32*32), attempts)
+}
+
+func TestProgressRate(t *testing.T) {
+	assert.Equal(t, float64(0), Progress{Attempts: 10}.Rate())
+	assert.Equal(t, float64(5), Progress{Attempts: 10, Elapsed: 2 * time.Second}.Rate())
+}
@@ -0,0 +1,103 @@
+// Package hashid derives the IDs and signature hashes defined by the stellar
+// protocol as hashes of XDR preimages, so that they do not have to be rebuilt
+// by hand wherever they are needed.
+//
+// The hashes of payloads signed for a network, like transactions and overlay
+// authentication certificates, include the network ID derived from its
+// passphrase, so that they cannot be replayed on another network. Operation
+// and claimable balance IDs do not depend on the network: they are derived
+// from the source account and sequence number of a transaction.
+package hashid
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/stellar/go/hash"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// OperationID returns the hash of the operation at index opIndex of the
+// transaction with sequence number seqNum sent by sourceAccount. It is the ID
+// of the claimable balance created by the operation, if any.
+func OperationID(sourceAccount xdr.AccountId, seqNum int64, opIndex uint32) (xdr.Hash, error) {
+	preimage := xdr.OperationId{
+		Type: xdr.EnvelopeTypeEnvelopeTypeOpId,
+		Id: &xdr.OperationIdId{
+			SourceAccount: sourceAccount.ToMuxedAccount(),
+			SeqNum:        xdr.SequenceNumber(seqNum),
+			OpNum:         xdr.Uint32(opIndex),
+		},
+	}
+	return hashXDR(preimage)
+}
+
+// ClaimableBalanceID returns the ID of the claimable balance created by the
+// operation at index opIndex of the transaction with sequence number seqNum
+// sent by sourceAccount.
+func ClaimableBalanceID(sourceAccount xdr.AccountId, seqNum int64, opIndex uint32) (xdr.ClaimableBalanceId, error) {
+	id, err := OperationID(sourceAccount, seqNum, opIndex)
+	if err != nil {
+		return xdr.ClaimableBalanceId{}, err
+	}
+	return xdr.NewClaimableBalanceId(xdr.ClaimableBalanceIdTypeClaimableBalanceIdTypeV0, id)
+}
+
+// ClaimableBalanceIDHex returns the ID of the claimable balance created by the
+// operation at index opIndex of the transaction with sequence number seqNum
+// sent by sourceAccount, as hex encoded XDR. This is the format used by
+// Horizon.
+func ClaimableBalanceIDHex(sourceAccount xdr.AccountId, seqNum int64, opIndex uint32) (string, error) {
+	id, err := ClaimableBalanceID(sourceAccount, seqNum, opIndex)
+	if err != nil {
+		return "", err
+	}
+	return xdr.MarshalHex(id)
+}
+
+// Transaction returns the hash of the transaction in envelope for the network
+// with the given passphrase. This is the hash signed to authorize the
+// transaction, and its ID.
+func Transaction(envelope xdr.TransactionEnvelope, passphrase string) (xdr.Hash, error) {
+	return network.HashTransactionInEnvelope(envelope, passphrase)
+}
+
+// PreAuthTxSigner returns the pre-authorized transaction signer key (T...)
+// authorizing the transaction in envelope on the network with the given
+// passphrase.
+func PreAuthTxSigner(envelope xdr.TransactionEnvelope, passphrase string) (string, error) {
+	h, err := Transaction(envelope, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return strkey.Encode(strkey.VersionByteHashTx, h[:])
+}
+
+// AuthCert returns the hash signed by a node to certify the overlay key
+// pubkey until expiration, in seconds since the epoch, on the network with the
+// given passphrase.
+func AuthCert(pubkey xdr.Curve25519Public, expiration uint64, passphrase string) (xdr.Hash, error) {
+	if strings.TrimSpace(passphrase) == "" {
+		return xdr.Hash{}, errors.New("empty network passphrase")
+	}
+	return hashXDR(
+		xdr.Hash(network.ID(passphrase)),
+		xdr.EnvelopeTypeEnvelopeTypeAuth,
+		xdr.Uint64(expiration),
+		pubkey,
+	)
+}
+
+// hashXDR returns the hash of the concatenated XDR encodings of values.
+func hashXDR(values ...interface{}) (xdr.Hash, error) {
+	var preimage bytes.Buffer
+	for _, value := range values {
+		if _, err := xdr.Marshal(&preimage, value); err != nil {
+			return xdr.Hash{}, errors.Wrap(err, "marshal preimage failed")
+		}
+	}
+	return hash.Hash(preimage.Bytes()), nil
+}
@@ -0,0 +1,67 @@
+package hashid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+func TestClaimableBalanceID(t *testing.T) {
+	source := xdr.MustAddress("GC2BKLYOOYPDEFJKLKY6FNNRQMGFLVHJKQRGNSSRRGSMPGF32LHCQVGF")
+
+	id, err := ClaimableBalanceIDHex(source, 124, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "0000000095001252ab3b4d16adbfa5364ce526dfcda03cb2258b827edbb2e0450087be51", id)
+
+	opID, err := OperationID(source, 124, 0)
+	require.NoError(t, err)
+	balanceID, err := ClaimableBalanceID(source, 124, 0)
+	require.NoError(t, err)
+	assert.Equal(t, opID, balanceID.MustV0())
+
+	other, err := OperationID(source, 124, 1)
+	require.NoError(t, err)
+	assert.NotEqual(t, opID, other)
+}
+
+func TestPreAuthTxSigner(t *testing.T) {
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64("AAAAAGL8HQvQkbK2HA3WVjRrKmjX00fG8sLI7m0ERwJW/AX3AAAACgAAAAAAAAABAAAAAAAAAAAAAAABAAAAAAAAAAAAAAAArqN6LeOagjxMaUP96Bzfs9e0corNZXzBWJkFoK7kvkwAAAAAO5rKAAAAAAAAAAABVvwF9wAAAEAKZ7IPj/46PuWU6ZOtyMosctNAkXRNX9WCAI5RnfRk+AyxDLoDZP/9l3NvsxQtWj9juQOuoBlFLnWu8intgxQA", &envelope)
+	require.NoError(t, err)
+
+	h, err := Transaction(envelope, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	signer, err := PreAuthTxSigner(envelope, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, strkey.MustEncode(strkey.VersionByteHashTx, h[:]), signer)
+
+	public, err := PreAuthTxSigner(envelope, network.PublicNetworkPassphrase)
+	require.NoError(t, err)
+	assert.NotEqual(t, signer, public)
+
+	_, err = PreAuthTxSigner(envelope, "")
+	assert.EqualError(t, err, "empty network passphrase")
+}
+
+func TestAuthCert(t *testing.T) {
+	pubkey := xdr.Curve25519Public{Key: [32]byte{1, 2, 3}}
+	h, err := AuthCert(pubkey, 1600000000, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	networkID := network.ID(network.TestNetworkPassphrase)
+	fields := make([]byte, 12)
+	binary.BigEndian.PutUint32(fields, uint32(xdr.EnvelopeTypeEnvelopeTypeAuth))
+	binary.BigEndian.PutUint64(fields[4:], 1600000000)
+	preimage := append(append(networkID[:], fields...), pubkey.Key[:]...)
+	assert.Equal(t, xdr.Hash(sha256.Sum256(preimage)), h)
+
+	_, err = AuthCert(pubkey, 1600000000, " ")
+	assert.EqualError(t, err, "empty network passphrase")
+}
@@ -0,0 +1,126 @@
+package network
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// FutureNetworkPassphrase is the pass phrase used for every transaction
+// intended for the SDF-run future network
+const FutureNetworkPassphrase = "Test SDF Future Network ; October 2022"
+
+// Profile bundles the values services need to connect to a stellar network.
+// Profiles can be read from TOML files with the network/profiletoml package.
+type Profile struct {
+	// Name identifies the profile, e.g. "testnet".
+	Name              string
+	NetworkPassphrase string
+	HorizonURL        string
+	// FriendbotURL is empty if the network has no friendbot.
+	FriendbotURL       string
+	HistoryArchiveURLs []string
+}
+
+var (
+	// PublicProfile is the profile of the public stellar network.
+	PublicProfile = Profile{
+		Name:              "pubnet",
+		NetworkPassphrase: PublicNetworkPassphrase,
+		HorizonURL:        "https://horizon.stellar.org/",
+		HistoryArchiveURLs: []string{
+			"https://history.stellar.org/prd/core-live/core_live_001/",
+			"https://history.stellar.org/prd/core-live/core_live_002/",
+			"https://history.stellar.org/prd/core-live/core_live_003/",
+		},
+	}
+	// TestProfile is the profile of the SDF-run test network.
+	TestProfile = Profile{
+		Name:              "testnet",
+		NetworkPassphrase: TestNetworkPassphrase,
+		HorizonURL:        "https://horizon-testnet.stellar.org/",
+		FriendbotURL:      "https://friendbot.stellar.org/",
+		HistoryArchiveURLs: []string{
+			"https://history.stellar.org/prd/core-testnet/core_testnet_001/",
+			"https://history.stellar.org/prd/core-testnet/core_testnet_002/",
+			"https://history.stellar.org/prd/core-testnet/core_testnet_003/",
+		},
+	}
+	// FutureProfile is the profile of the SDF-run future network.
+	FutureProfile = Profile{
+		Name:              "futurenet",
+		NetworkPassphrase: FutureNetworkPassphrase,
+		HorizonURL:        "https://horizon-futurenet.stellar.org/",
+		FriendbotURL:      "https://friendbot-futurenet.stellar.org/",
+		HistoryArchiveURLs: []string{
+			"https://history-futurenet.stellar.org/",
+		},
+	}
+)
+
+var builtinProfiles = map[string]Profile{
+	"pubnet":    PublicProfile,
+	"public":    PublicProfile,
+	"testnet":   TestProfile,
+	"test":      TestProfile,
+	"futurenet": FutureProfile,
+	"future":    FutureProfile,
+}
+
+// BuiltinProfile returns the built-in profile with the given name: "pubnet"
+// (or "public"), "testnet" (or "test") or "futurenet" (or "future").
+func BuiltinProfile(name string) (Profile, error) {
+	profile, ok := builtinProfiles[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(builtinProfiles))
+		for name := range builtinProfiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return Profile{}, errors.Errorf("unknown network profile %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	profile.HistoryArchiveURLs = append([]string(nil), profile.HistoryArchiveURLs...)
+	return profile, nil
+}
+
+// Validate returns an error if the profile has no network passphrase, or an
+// invalid URL. The Horizon URL, friendbot URL and history archive URLs are
+// optional, as not every service needs them.
+func (p Profile) Validate() error {
+	if strings.TrimSpace(p.NetworkPassphrase) == "" {
+		return errors.New("network profile has no network passphrase")
+	}
+	if err := validateProfileURL("horizon-url", p.HorizonURL); err != nil {
+		return err
+	}
+	if err := validateProfileURL("friendbot-url", p.FriendbotURL); err != nil {
+		return err
+	}
+	for _, archiveURL := range p.HistoryArchiveURLs {
+		if archiveURL == "" {
+			return errors.New("network profile has an empty history archive url")
+		}
+		if err := validateProfileURL("history-archive-urls", archiveURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ID returns the network ID derived from the passphrase of the profile.
+func (p Profile) ID() [32]byte {
+	return ID(p.NetworkPassphrase)
+}
+
+func validateProfileURL(field, value string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" {
+		return errors.Errorf("network profile %s %q is not a valid url", field, value)
+	}
+	return nil
+}
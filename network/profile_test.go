@@ -0,0 +1,32 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinProfile(t *testing.T) {
+	profile, err := BuiltinProfile("testnet")
+	require.NoError(t, err)
+	assert.Equal(t, TestProfile, profile)
+	assert.Equal(t, ID(TestNetworkPassphrase), profile.ID())
+
+	profile, err = BuiltinProfile("Public")
+	require.NoError(t, err)
+	assert.Equal(t, PublicNetworkPassphrase, profile.NetworkPassphrase)
+	assert.Empty(t, profile.FriendbotURL)
+
+	// The returned profile can be modified without altering the built-in
+	// one.
+	profile.HistoryArchiveURLs[0] = "file:///tmp/archive"
+	assert.Equal(t, "https://history.stellar.org/prd/core-live/core_live_001/", PublicProfile.HistoryArchiveURLs[0])
+
+	for _, profile := range []Profile{PublicProfile, TestProfile, FutureProfile} {
+		assert.NoError(t, profile.Validate(), profile.Name)
+	}
+
+	_, err = BuiltinProfile("devnet")
+	assert.EqualError(t, err, `unknown network profile "devnet", expected one of future, futurenet, public, pubnet, test, testnet`)
+}
@@ -0,0 +1,59 @@
+// Package profiletoml reads network profiles from TOML documents, keeping the
+// network package free of a TOML dependency.
+package profiletoml
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/errors"
+)
+
+// profile is the TOML representation of a network.Profile.
+type profile struct {
+	Name               string   `toml:"name"`
+	NetworkPassphrase  string   `toml:"network-passphrase"`
+	HorizonURL         string   `toml:"horizon-url"`
+	FriendbotURL       string   `toml:"friendbot-url"`
+	HistoryArchiveURLs []string `toml:"history-archive-urls"`
+}
+
+// Parse parses a profile from a TOML document, e.g.:
+//
+//	name = "private"
+//	network-passphrase = "Private Network ; January 2021"
+//	horizon-url = "https://horizon.example.com/"
+//	friendbot-url = "https://friendbot.example.com/"
+//	history-archive-urls = ["https://history.example.com/"]
+func Parse(content string) (network.Profile, error) {
+	var p profile
+	metadata, err := toml.Decode(content, &p)
+	if err != nil {
+		return network.Profile{}, errors.Wrap(err, "decoding network profile failed")
+	}
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		return network.Profile{}, errors.New("unknown network profile fields: " + fmt.Sprintf("%+v", undecoded))
+	}
+	result := network.Profile{
+		Name:               p.Name,
+		NetworkPassphrase:  p.NetworkPassphrase,
+		HorizonURL:         p.HorizonURL,
+		FriendbotURL:       p.FriendbotURL,
+		HistoryArchiveURLs: p.HistoryArchiveURLs,
+	}
+	if err = result.Validate(); err != nil {
+		return network.Profile{}, err
+	}
+	return result, nil
+}
+
+// Load reads the profile from the TOML file at path, see Parse.
+func Load(path string) (network.Profile, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return network.Profile{}, errors.Wrap(err, "reading network profile failed")
+	}
+	return Parse(string(content))
+}
@@ -0,0 +1,58 @@
+package profiletoml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	profile, err := Parse(`
+name = "private"
+network-passphrase = "Private Network ; January 2021"
+horizon-url = "https://horizon.example.com/"
+history-archive-urls = ["https://history.example.com/", "s3://history/archive"]
+`)
+	require.NoError(t, err)
+	assert.Equal(t, network.Profile{
+		Name:               "private",
+		NetworkPassphrase:  "Private Network ; January 2021",
+		HorizonURL:         "https://horizon.example.com/",
+		HistoryArchiveURLs: []string{"https://history.example.com/", "s3://history/archive"},
+	}, profile)
+
+	_, err = Parse(`horizon-url = "https://horizon.example.com/"`)
+	assert.EqualError(t, err, "network profile has no network passphrase")
+
+	_, err = Parse(`
+network-passphrase = "Private Network ; January 2021"
+friendbot-url = "friendbot"
+`)
+	assert.EqualError(t, err, `network profile friendbot-url "friendbot" is not a valid url`)
+
+	_, err = Parse(`
+network-passphrase = "Private Network ; January 2021"
+horizon = "https://horizon.example.com/"
+`)
+	assert.EqualError(t, err, "unknown network profile fields: [horizon]")
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "network-profile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "profile.toml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`network-passphrase = "Private Network ; January 2021"`), 0644))
+
+	profile, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Private Network ; January 2021", profile.NetworkPassphrase)
+
+	_, err = Load(filepath.Join(dir, "missing.toml"))
+	assert.Error(t, err)
+}
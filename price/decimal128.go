@@ -0,0 +1,71 @@
+package price
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/xdr"
+)
+
+// ToDecimal128 returns the decimal128 of price p, or an error if p has no
+// exact decimal128 representation, e.g. 1/3.
+func ToDecimal128(p xdr.Price) (amount.Decimal128, error) {
+	d, err := toDecimal(p)
+	if err != nil {
+		return amount.Decimal128{}, err
+	}
+	return d.Decimal128()
+}
+
+// FromDecimal128 returns the price of d, or an error if d is NaN, infinite,
+// negative or cannot be represented exactly by a fraction of 32-bit signed
+// integers.
+func FromDecimal128(d amount.Decimal128) (xdr.Price, error) {
+	dec, err := amount.NewDecimalFromDecimal128(d)
+	if err != nil {
+		return xdr.Price{}, err
+	}
+	return fromDecimal(dec)
+}
+
+// ToAPD returns the apd.Decimal of price p, or an error if p has no exact
+// decimal representation, e.g. 1/3.
+func ToAPD(p xdr.Price) (*apd.Decimal, error) {
+	d, err := toDecimal(p)
+	if err != nil {
+		return nil, err
+	}
+	return d.APD()
+}
+
+// FromAPD returns the price of d, or an error if d is not finite, negative
+// or cannot be represented exactly by a fraction of 32-bit signed integers.
+func FromAPD(d *apd.Decimal) (xdr.Price, error) {
+	dec, err := amount.NewDecimalFromAPD(d)
+	if err != nil {
+		return xdr.Price{}, err
+	}
+	return fromDecimal(dec)
+}
+
+func toDecimal(p xdr.Price) (amount.Decimal, error) {
+	if p.D == 0 {
+		return amount.Decimal{}, ErrDivisionByZero
+	}
+	return amount.NewDecimalFromRat(big.NewRat(int64(p.N), int64(p.D))), nil
+}
+
+func fromDecimal(d amount.Decimal) (xdr.Price, error) {
+	if d.Sign() < 0 {
+		return xdr.Price{}, errors.New("price cannot be negative")
+	}
+	r := d.Rat()
+	if !r.Num().IsInt64() || r.Num().Int64() > math.MaxInt32 ||
+		!r.Denom().IsInt64() || r.Denom().Int64() > math.MaxInt32 {
+		return xdr.Price{}, ErrOverflow
+	}
+	return xdr.Price{N: xdr.Int32(r.Num().Int64()), D: xdr.Int32(r.Denom().Int64())}, nil
+}
@@ -130,3 +130,46 @@ func TestMulFractionOverflow(t *testing.T) {
 		t.Fatal("expected overflow error")
 	}
 }
+
+func TestDecimal128(t *testing.T) {
+	for _, c := range []struct {
+		p xdr.Price
+		s string
+	}{
+		{xdr.Price{1, 2}, "0.5"},
+		{xdr.Price{4119, 1}, "4119"},
+		{xdr.Price{5333399, 6250000}, "0.85334384"},
+		{xdr.Price{1, 1 << 30}, "9.31322574615478515625E-10"},
+	} {
+		d, err := ToDecimal128(c.p)
+		if assert.NoError(t, err) {
+			assert.Equal(t, c.s, d.String())
+			p, err := FromDecimal128(d)
+			assert.NoError(t, err)
+			assert.Equal(t, c.p, p)
+		}
+
+		a, err := ToAPD(c.p)
+		if assert.NoError(t, err) {
+			p, err := FromAPD(a)
+			assert.NoError(t, err)
+			assert.Equal(t, c.p, p)
+		}
+	}
+
+	_, err := ToDecimal128(xdr.Price{1, 3})
+	assert.EqualError(t, err, "value has no exact decimal representation: 1/3")
+	_, err = ToAPD(xdr.Price{1, 0})
+	assert.Equal(t, ErrDivisionByZero, err)
+
+	d, err := ToDecimal128(xdr.Price{math.MaxInt32, 1})
+	assert.NoError(t, err)
+	d.Low++
+	_, err = FromDecimal128(d)
+	assert.Equal(t, ErrOverflow, err)
+
+	d, err = ToDecimal128(xdr.Price{-1, 2})
+	assert.NoError(t, err)
+	_, err = FromDecimal128(d)
+	assert.EqualError(t, err, "price cannot be negative")
+}
@@ -40,6 +40,8 @@ type Account struct {
 	ID                   string            `json:"id"`
 	AccountID            string            `json:"account_id"`
 	Sequence             string            `json:"sequence"`
+	SequenceLedger       uint32            `json:"sequence_ledger,omitempty"`
+	SequenceTime         int64             `json:"sequence_time,string,omitempty"`
 	SubentryCount        int32             `json:"subentry_count"`
 	InflationDestination string            `json:"inflation_destination,omitempty"`
 	HomeDomain           string            `json:"home_domain,omitempty"`
@@ -337,10 +339,26 @@ type Root struct {
 	HorizonSequence              int32     `json:"history_latest_ledger"`
 	HorizonLatestClosedAt        time.Time `json:"history_latest_ledger_closed_at"`
 	HistoryElderSequence         int32     `json:"history_elder_ledger"`
+	HistoryRetentionCount        uint      `json:"history_retention_count"`
 	CoreSequence                 int32     `json:"core_latest_ledger"`
 	NetworkPassphrase            string    `json:"network_passphrase"`
 	CurrentProtocolVersion       int32     `json:"current_protocol_version"`
 	CoreSupportedProtocolVersion int32     `json:"core_supported_protocol_version"`
+
+	// Operator describes who runs the Horizon server, if configured.
+	Operator *RootOperator `json:"operator,omitempty"`
+}
+
+// RootOperator is the metadata about the operator of a Horizon server
+// advertised in the root resource, letting crawlers discover the
+// capabilities of the services hosted alongside it.
+type RootOperator struct {
+	Name        string `json:"name,omitempty"`
+	Contact     string `json:"contact,omitempty"`
+	NetworkName string `json:"network_name,omitempty"`
+	// SupportedSEPs lists the SEPs implemented by services co-hosted with
+	// the Horizon server, e.g. "SEP-10".
+	SupportedSEPs []string `json:"supported_seps,omitempty"`
 }
 
 // Signer represents one of an account's signers.
@@ -432,6 +450,41 @@ func (res TradeAggregation) PagingToken() string {
 	return strconv.FormatInt(res.Timestamp, 10)
 }
 
+// BalanceHistory represents the balance of an asset held by an account at
+// the end of a period of time, together with the amounts moved in that
+// period.
+type BalanceHistory struct {
+	Timestamp   int64  `json:"timestamp,string"`
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code,omitempty"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	Balance     string `json:"balance"`
+	Credited    string `json:"credited"`
+	Debited     string `json:"debited"`
+}
+
+// PagingToken implementation for hal.Pageable. Not actually used
+func (res BalanceHistory) PagingToken() string {
+	return strconv.FormatInt(res.Timestamp, 10)
+}
+
+// AssetPaymentStat represents the successful payments delivering an asset
+// during a day (UTC), starting at Timestamp: their number and the amount of
+// the asset they delivered.
+type AssetPaymentStat struct {
+	Timestamp   int64  `json:"timestamp,string"`
+	AssetType   string `json:"asset_type"`
+	AssetCode   string `json:"asset_code,omitempty"`
+	AssetIssuer string `json:"asset_issuer,omitempty"`
+	Payments    int64  `json:"payments,string"`
+	Volume      string `json:"volume"`
+}
+
+// PagingToken implementation for hal.Pageable. Not actually used
+func (res AssetPaymentStat) PagingToken() string {
+	return strconv.FormatInt(res.Timestamp, 10)
+}
+
 // Transaction represents a single, successful transaction
 type Transaction struct {
 	Links struct {
@@ -447,34 +500,122 @@ type Transaction struct {
 		// When TransactionSuccess is removed from the SDKs we can remove this HAL link
 		Transaction hal.Link `json:"transaction"`
 	} `json:"_links"`
-	ID                 string              `json:"id"`
-	PT                 string              `json:"paging_token"`
-	Successful         bool                `json:"successful"`
-	Hash               string              `json:"hash"`
-	Ledger             int32               `json:"ledger"`
-	LedgerCloseTime    time.Time           `json:"created_at"`
-	Account            string              `json:"source_account"`
-	AccountMuxed       string              `json:"account_muxed,omitempty"`
-	AccountMuxedID     uint64              `json:"account_muxed_id,omitempty"`
-	AccountSequence    string              `json:"source_account_sequence"`
-	FeeAccount         string              `json:"fee_account"`
-	FeeAccountMuxed    string              `json:"fee_account_muxed,omitempty"`
-	FeeAccountMuxedID  uint64              `json:"fee_account_muxed_id,omitempty"`
-	FeeCharged         int64               `json:"fee_charged,string"`
-	MaxFee             int64               `json:"max_fee,string"`
-	OperationCount     int32               `json:"operation_count"`
-	EnvelopeXdr        string              `json:"envelope_xdr"`
-	ResultXdr          string              `json:"result_xdr"`
-	ResultMetaXdr      string              `json:"result_meta_xdr"`
-	FeeMetaXdr         string              `json:"fee_meta_xdr"`
-	MemoType           string              `json:"memo_type"`
-	MemoBytes          string              `json:"memo_bytes,omitempty"`
-	Memo               string              `json:"memo,omitempty"`
-	Signatures         []string            `json:"signatures"`
-	ValidAfter         string              `json:"valid_after,omitempty"`
-	ValidBefore        string              `json:"valid_before,omitempty"`
-	FeeBumpTransaction *FeeBumpTransaction `json:"fee_bump_transaction,omitempty"`
-	InnerTransaction   *InnerTransaction   `json:"inner_transaction,omitempty"`
+	ID                 string                    `json:"id"`
+	PT                 string                    `json:"paging_token"`
+	Successful         bool                      `json:"successful"`
+	Hash               string                    `json:"hash"`
+	Ledger             int32                     `json:"ledger"`
+	LedgerCloseTime    time.Time                 `json:"created_at"`
+	Account            string                    `json:"source_account"`
+	AccountMuxed       string                    `json:"account_muxed,omitempty"`
+	AccountMuxedID     uint64                    `json:"account_muxed_id,omitempty"`
+	AccountSequence    string                    `json:"source_account_sequence"`
+	FeeAccount         string                    `json:"fee_account"`
+	FeeAccountMuxed    string                    `json:"fee_account_muxed,omitempty"`
+	FeeAccountMuxedID  uint64                    `json:"fee_account_muxed_id,omitempty"`
+	FeeCharged         int64                     `json:"fee_charged,string"`
+	MaxFee             int64                     `json:"max_fee,string"`
+	OperationCount     int32                     `json:"operation_count"`
+	EnvelopeXdr        string                    `json:"envelope_xdr"`
+	ResultXdr          string                    `json:"result_xdr"`
+	ResultMetaXdr      string                    `json:"result_meta_xdr"`
+	FeeMetaXdr         string                    `json:"fee_meta_xdr"`
+	MemoType           string                    `json:"memo_type"`
+	MemoBytes          string                    `json:"memo_bytes,omitempty"`
+	Memo               string                    `json:"memo,omitempty"`
+	Signatures         []string                  `json:"signatures"`
+	ValidAfter         string                    `json:"valid_after,omitempty"`
+	ValidBefore        string                    `json:"valid_before,omitempty"`
+	Preconditions      *TransactionPreconditions `json:"preconditions,omitempty"`
+	FeeBumpTransaction *FeeBumpTransaction       `json:"fee_bump_transaction,omitempty"`
+	InnerTransaction   *InnerTransaction         `json:"inner_transaction,omitempty"`
+}
+
+// TransactionPreconditions are the conditions a transaction must meet to be
+// valid. Besides time bounds, they are only set on the transactions of
+// protocol 19 and later.
+type TransactionPreconditions struct {
+	TimeBounds   *TransactionPreconditionsTimebounds   `json:"timebounds,omitempty"`
+	LedgerBounds *TransactionPreconditionsLedgerbounds `json:"ledgerbounds,omitempty"`
+	// MinAccountSequence is the minimum sequence number of the source
+	// account, the transaction is valid only if it is followed by the
+	// sequence number of the transaction.
+	MinAccountSequence string `json:"min_account_sequence,omitempty"`
+	// MinAccountSequenceAge is the number of seconds that must have elapsed
+	// since the sequence number of the source account was last bumped.
+	MinAccountSequenceAge uint64 `json:"min_account_sequence_age,string,omitempty"`
+	// MinAccountSequenceLedgerGap is the number of ledgers that must have
+	// closed since the sequence number of the source account was last
+	// bumped.
+	MinAccountSequenceLedgerGap uint32 `json:"min_account_sequence_ledger_gap,omitempty"`
+	// ExtraSigners are the signer keys, in strkey format, which must have
+	// signed the transaction in addition to the signers of the source
+	// accounts.
+	ExtraSigners []string `json:"extra_signers,omitempty"`
+}
+
+// UnmarshalJSON decodes the minimum sequence age of preconditions from
+// either a string or a number.
+func (p *TransactionPreconditions) UnmarshalJSON(data []byte) error {
+	type Alias TransactionPreconditions // we define Alias to avoid infinite recursion when calling UnmarshalJSON()
+	v := &struct {
+		MinAccountSequenceAge json.Number `json:"min_account_sequence_age"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if v.MinAccountSequenceAge != "" {
+		age, err := strconv.ParseUint(string(v.MinAccountSequenceAge), 10, 64)
+		if err != nil {
+			return err
+		}
+		p.MinAccountSequenceAge = age
+	}
+	return nil
+}
+
+// TransactionPreconditionsTimebounds are the UNIX timestamps, in seconds,
+// between which a transaction is valid. A zero MaxTime means no upper bound.
+type TransactionPreconditionsTimebounds struct {
+	MinTime int64 `json:"min_time,string"`
+	MaxTime int64 `json:"max_time,string,omitempty"`
+}
+
+// UnmarshalJSON decodes the time bounds from either strings or numbers.
+func (b *TransactionPreconditionsTimebounds) UnmarshalJSON(data []byte) error {
+	var v struct {
+		MinTime json.Number `json:"min_time"`
+		MaxTime json.Number `json:"max_time"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var err error
+	*b = TransactionPreconditionsTimebounds{}
+	if v.MinTime != "" {
+		if b.MinTime, err = v.MinTime.Int64(); err != nil {
+			return err
+		}
+	}
+	if v.MaxTime != "" {
+		if b.MaxTime, err = v.MaxTime.Int64(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TransactionPreconditionsLedgerbounds are the ledger sequence numbers
+// between which a transaction is valid, MaxLedger being exclusive. A zero
+// MaxLedger means no upper bound.
+type TransactionPreconditionsLedgerbounds struct {
+	MinLedger uint32 `json:"min_ledger"`
+	MaxLedger uint32 `json:"max_ledger,omitempty"`
 }
 
 // FeeBumpTransaction contains information about a fee bump transaction
@@ -557,6 +698,47 @@ type TransactionResultCodes struct {
 	OperationCodes  []string `json:"operations,omitempty"`
 }
 
+// AsyncTransactionStatus is the status of a transaction submitted to
+// stellar-core by the /transactions_async endpoint.
+type AsyncTransactionStatus string
+
+// The statuses of transactions submitted to the /transactions_async endpoint.
+const (
+	// TxStatusPending means the transaction was accepted by stellar-core and
+	// will be included in an upcoming ledger if it is valid.
+	TxStatusPending AsyncTransactionStatus = "PENDING"
+	// TxStatusDuplicate means the transaction was already submitted.
+	TxStatusDuplicate AsyncTransactionStatus = "DUPLICATE"
+	// TxStatusTryAgainLater means stellar-core could not accept the
+	// transaction, e.g. because its queue is full, and it should be
+	// submitted again later.
+	TxStatusTryAgainLater AsyncTransactionStatus = "TRY_AGAIN_LATER"
+	// TxStatusError means stellar-core rejected the transaction. The reason
+	// is in the ErrorResultXDR of the response.
+	TxStatusError AsyncTransactionStatus = "ERROR"
+)
+
+// AsyncTransactionSubmissionResponse is the response of the
+// /transactions_async endpoint.
+type AsyncTransactionSubmissionResponse struct {
+	// ErrorResultXDR is the base64 encoded TransactionResult explaining why
+	// stellar-core rejected the transaction. It is only set when TxStatus is
+	// TxStatusError.
+	ErrorResultXDR string                 `json:"errorResultXdr,omitempty"`
+	TxStatus       AsyncTransactionStatus `json:"tx_status"`
+	Hash           string                 `json:"hash"`
+}
+
+// ErrorResult decodes the ErrorResultXDR of the response.
+func (r AsyncTransactionSubmissionResponse) ErrorResult() (xdr.TransactionResult, error) {
+	var result xdr.TransactionResult
+	if r.ErrorResultXDR == "" {
+		return result, errors.New("response has no error result")
+	}
+	err := xdr.SafeUnmarshalBase64(r.ErrorResultXDR, &result)
+	return result, err
+}
+
 // KeyTypeFromAddress converts the version byte of the provided strkey encoded
 // value (for example an account id or a signer key) and returns the appropriate
 // horizon-specific type name.
@@ -721,6 +903,73 @@ func (res ClaimableBalance) PagingToken() string {
 	return res.PT
 }
 
+// Sponsorship represents a ledger entry, or subentry, of an account whose
+// reserve is paid by a sponsor.
+type Sponsorship struct {
+	Links struct {
+		Account hal.Link `json:"account"`
+		Sponsor hal.Link `json:"sponsor"`
+	} `json:"_links"`
+
+	// Type is one of account, data, offer, signer and trustline.
+	Type      string `json:"type"`
+	AccountID string `json:"account_id"`
+	Sponsor   string `json:"sponsor"`
+	Signer    string `json:"signer,omitempty"`
+	Asset     string `json:"asset,omitempty"`
+	DataName  string `json:"data_name,omitempty"`
+	OfferID   string `json:"offer_id,omitempty"`
+	PT        string `json:"paging_token"`
+}
+
+// PagingToken implementation for hal.Pageable
+func (res Sponsorship) PagingToken() string {
+	return res.PT
+}
+
+// SponsorshipsPage returns a list of sponsorship records
+type SponsorshipsPage struct {
+	Links    hal.Links `json:"_links"`
+	Embedded struct {
+		Records []Sponsorship `json:"records"`
+	} `json:"_embedded"`
+}
+
+// SponsorshipCounts is the number of sponsored entries of each type.
+type SponsorshipCounts struct {
+	Total             uint32 `json:"total"`
+	Accounts          uint32 `json:"accounts"`
+	Data              uint32 `json:"data"`
+	Offers            uint32 `json:"offers"`
+	Signers           uint32 `json:"signers"`
+	Trustlines        uint32 `json:"trustlines"`
+	ClaimableBalances uint32 `json:"claimable_balances"`
+}
+
+// SponsorCount is the number of entries of an account paid by a sponsor.
+type SponsorCount struct {
+	Sponsor string `json:"sponsor"`
+	Count   uint32 `json:"count"`
+}
+
+// AccountSponsorships summarizes the entries an account sponsors, which
+// count against its reserve, and the sponsors of its own entries.
+type AccountSponsorships struct {
+	Links struct {
+		Self       hal.Link `json:"self"`
+		Sponsoring hal.Link `json:"sponsoring"`
+		Sponsored  hal.Link `json:"sponsored"`
+	} `json:"_links"`
+
+	AccountID string `json:"account_id"`
+	// Sponsoring counts the entries sponsored by the account, including
+	// claimable balances.
+	Sponsoring SponsorshipCounts `json:"sponsoring"`
+	// Sponsored counts the entries of the account paid by sponsors.
+	Sponsored SponsorshipCounts `json:"sponsored"`
+	Sponsors  []SponsorCount    `json:"sponsors"`
+}
+
 // Claimant represents a claimable balance claimant
 type Claimant struct {
 	Destination string             `json:"destination"`
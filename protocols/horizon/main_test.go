@@ -234,6 +234,41 @@ func TestTransactionUnmarshalJSON(t *testing.T) {
 	assert.Equal(t, int64(3000000000), parsedFeesAsInts.FeeCharged)
 }
 
+func TestTransactionPreconditionsJSON(t *testing.T) {
+	var asStrings, asNumbers TransactionPreconditions
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"timebounds": {"min_time": "1000", "max_time": "2000"},
+		"ledgerbounds": {"min_ledger": 10},
+		"min_account_sequence_age": "60",
+		"extra_signers": ["GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"]
+	}`), &asStrings))
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"timebounds": {"min_time": 1000, "max_time": 2000},
+		"ledgerbounds": {"min_ledger": 10},
+		"min_account_sequence_age": 60,
+		"extra_signers": ["GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"]
+	}`), &asNumbers))
+	assert.Equal(t, asStrings, asNumbers)
+	assert.Equal(t, TransactionPreconditions{
+		TimeBounds:            &TransactionPreconditionsTimebounds{MinTime: 1000, MaxTime: 2000},
+		LedgerBounds:          &TransactionPreconditionsLedgerbounds{MinLedger: 10},
+		MinAccountSequenceAge: 60,
+		ExtraSigners:          []string{"GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"},
+	}, asStrings)
+
+	marshaled, err := json.Marshal(TransactionPreconditions{
+		TimeBounds:            &TransactionPreconditionsTimebounds{},
+		MinAccountSequenceAge: 60,
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"timebounds": {"min_time": "0"}, "min_account_sequence_age": "60"}`, string(marshaled))
+
+	var transaction Transaction
+	assert.NoError(t, json.Unmarshal([]byte(`{"id": "1"}`), &transaction))
+	assert.Nil(t, transaction.Preconditions)
+	assert.Error(t, json.Unmarshal([]byte(`{"min_account_sequence_age": "-1"}`), &asStrings))
+}
+
 func TestTradeAggregation_PagingToken(t *testing.T) {
 	ta := TradeAggregation{Timestamp: 64}
 	assert.Equal(t, "64", ta.PagingToken())
@@ -0,0 +1,343 @@
+// Package schema generates JSON Schema and OpenAPI definitions of the
+// resources of protocols/horizon, and validates responses against them.
+//
+// Definitions are generated from the Go structs following the rules of
+// encoding/json: json tags name the properties, fields without omitempty are
+// required, pointers, slices and maps which are not omitted can be null and
+// fields with the string option are strings. Custom marshalers are assumed to
+// keep the shape of their struct, types for which they don't must be defined
+// with Registry.Override.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Schema is a JSON Schema, limited to the keywords needed to describe Go
+// types. It marshals to the OpenAPI 3.0 flavor of JSON Schema.
+type Schema struct {
+	// Ref is the name of the definition this schema refers to, all other
+	// fields but Nullable are empty when it is set.
+	Ref                  string
+	Type                 string
+	Format               string
+	Pattern              string
+	Nullable             bool
+	Properties           map[string]*Schema
+	Required             []string
+	Items                *Schema
+	AdditionalProperties *Schema
+}
+
+// MarshalJSON marshals the schema as an OpenAPI schema object.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.toMap(openAPIRefPrefix, true))
+}
+
+const (
+	jsonSchemaRefPrefix = "#/definitions/"
+	openAPIRefPrefix    = "#/components/schemas/"
+)
+
+// toMap converts the schema to a JSON object. OpenAPI 3.0 marks nullable
+// schemas with a nullable keyword, JSON Schema adds null to their types.
+func (s *Schema) toMap(refPrefix string, openAPI bool) map[string]interface{} {
+	m := map[string]interface{}{}
+	if s.Ref != "" {
+		m["$ref"] = refPrefix + s.Ref
+	}
+	if s.Type != "" {
+		m["type"] = s.Type
+	}
+	if s.Format != "" {
+		m["format"] = s.Format
+	}
+	if s.Pattern != "" {
+		m["pattern"] = s.Pattern
+	}
+	if s.Properties != nil {
+		properties := map[string]interface{}{}
+		for name, property := range s.Properties {
+			properties[name] = property.toMap(refPrefix, openAPI)
+		}
+		m["properties"] = properties
+	}
+	if len(s.Required) > 0 {
+		m["required"] = s.Required
+	}
+	if s.Items != nil {
+		m["items"] = s.Items.toMap(refPrefix, openAPI)
+	}
+	if s.AdditionalProperties != nil {
+		m["additionalProperties"] = s.AdditionalProperties.toMap(refPrefix, openAPI)
+	}
+
+	if !s.Nullable || (s.Ref == "" && s.Type == "") {
+		return m
+	}
+	switch {
+	case openAPI && s.Ref != "":
+		// the siblings of $ref are ignored
+		return map[string]interface{}{"allOf": []interface{}{m}, "nullable": true}
+	case openAPI:
+		m["nullable"] = true
+	case s.Ref != "":
+		return map[string]interface{}{"anyOf": []interface{}{m, map[string]interface{}{"type": "null"}}}
+	default:
+		m["type"] = []string{s.Type, "null"}
+	}
+	return m
+}
+
+// nullable returns a copy of s which can be null, or not.
+func (s *Schema) nullable(nullable bool) *Schema {
+	c := *s
+	c.Nullable = nullable
+	return &c
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Registry holds the definitions of named struct types. Definitions are
+// named after the package and name of their type, e.g. "horizon.Account".
+type Registry struct {
+	Definitions map[string]*Schema
+	names       map[reflect.Type]string
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		Definitions: map[string]*Schema{},
+		names:       map[reflect.Type]string{},
+	}
+}
+
+// Define adds the definition of the type of v, and of all the named struct
+// types it refers to, to the registry. It returns a reference to the
+// definition when v is a named struct, and its schema otherwise.
+func (r *Registry) Define(v interface{}) *Schema {
+	return r.schemaOf(reflect.TypeOf(v))
+}
+
+// DefineAs adds s to the registry as the definition name, and returns a
+// reference to it. It is used for resources which are not described by a
+// single Go type.
+func (r *Registry) DefineAs(name string, s *Schema) *Schema {
+	r.Definitions[name] = s
+	return &Schema{Ref: name}
+}
+
+// Override adds s to the registry as the definition of the type of v, to be
+// used instead of the generated one. It is used for types whose custom
+// marshalers change their shape.
+func (r *Registry) Override(v interface{}, s *Schema) *Schema {
+	t := reflect.TypeOf(v)
+	name := definitionName(t)
+	r.names[t] = name
+	return r.DefineAs(name, s)
+}
+
+// Ref returns a reference to the definition name, or an error if it is not
+// in the registry.
+func (r *Registry) Ref(name string) (*Schema, error) {
+	if _, ok := r.Definitions[name]; !ok {
+		return nil, errors.Errorf("unknown definition: %s", name)
+	}
+	return &Schema{Ref: name}, nil
+}
+
+// JSONSchema returns a JSON Schema (draft 7) document with all the
+// definitions of the registry.
+func (r *Registry) JSONSchema() ([]byte, error) {
+	definitions := map[string]interface{}{}
+	for name, s := range r.Definitions {
+		definitions[name] = s.toMap(jsonSchemaRefPrefix, false)
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"definitions": definitions,
+	}, "", "  ")
+}
+
+// OpenAPI returns an OpenAPI 3.0 document with all the definitions of the
+// registry as component schemas. It has no paths.
+func (r *Registry) OpenAPI(title, version string) ([]byte, error) {
+	return json.MarshalIndent(map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"paths": map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": r.Definitions,
+		},
+	}, "", "  ")
+}
+
+func definitionName(t reflect.Type) string {
+	return path.Base(t.PkgPath()) + "." + t.Name()
+}
+
+func (r *Registry) schemaOf(t reflect.Type) *Schema {
+	if name, ok := r.names[t]; ok {
+		return &Schema{Ref: name}
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	if !t.Implements(jsonMarshalerType) && t.Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: true}
+		}
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: r.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaOf(t.Elem()), Nullable: true}
+	case reflect.Ptr:
+		return r.schemaOf(t.Elem()).nullable(true)
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		name := definitionName(t)
+		// the name is registered first as the type can refer to itself
+		r.names[t] = name
+		r.Definitions[name] = r.structSchema(t)
+		return &Schema{Ref: name}
+	default:
+		// interfaces can hold any value
+		return &Schema{}
+	}
+}
+
+func (r *Registry) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds the properties of the fields of t to s. The fields of
+// embedded structs are added after the other fields, and do not replace
+// them, as encoding/json gives precedence to the shallower fields.
+func (r *Registry) addFields(s *Schema, t reflect.Type) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := parseTag(tag)
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := s.Properties[name]; ok {
+			continue
+		}
+
+		property := r.schemaOf(f.Type)
+		if options.contains("string") {
+			property = stringSchema(f.Type, property)
+		}
+		if options.contains("omitempty") {
+			// nil values are omitted
+			property = property.nullable(false)
+		} else {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = property
+	}
+
+	for _, ft := range embedded {
+		r.addFields(s, ft)
+	}
+}
+
+// stringSchema returns the schema of fields with the string option, which
+// encodes booleans and numbers as strings.
+func stringSchema(t reflect.Type, s *Schema) *Schema {
+	nullable := false
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "string", Pattern: "^(true|false)$", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "string", Pattern: "^-?[0-9]+$", Nullable: nullable}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "string", Pattern: "^[0-9]+$", Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "string", Nullable: nullable}
+	default:
+		// the option only applies to booleans and numbers
+		return s
+	}
+}
+
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+func (o tagOptions) contains(option string) bool {
+	for _, s := range strings.Split(string(o), ",") {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/xdr"
+)
+
+type testEmbedded struct {
+	Name  string `json:"name"`
+	Extra string `json:"extra,omitempty"`
+}
+
+type testResource struct {
+	testEmbedded
+	Name       int64             `json:"name,string"`
+	Optional   *uint32           `json:"optional,omitempty"`
+	Nullable   *bool             `json:"nullable"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Data       []byte            `json:"data"`
+	Labels     map[string]string `json:"labels"`
+	Children   []testResource    `json:"children,omitempty"`
+	Untagged   float64
+	Ignored    string `json:"-"`
+	unexported string
+}
+
+func TestDefine(t *testing.T) {
+	r := NewRegistry()
+	ref := r.Define(testResource{})
+	assert.Equal(t, &Schema{Ref: "schema.testResource"}, ref)
+	assert.Equal(t, &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name":       {Type: "string", Pattern: "^-?[0-9]+$"},
+			"optional":   {Type: "integer", Format: "int32"},
+			"nullable":   {Type: "boolean", Nullable: true},
+			"created_at": {Type: "string", Format: "date-time"},
+			"data":       {Type: "string", Format: "byte", Nullable: true},
+			"labels":     {Type: "object", AdditionalProperties: &Schema{Type: "string"}, Nullable: true},
+			"children":   {Type: "array", Items: &Schema{Ref: "schema.testResource"}},
+			"Untagged":   {Type: "number", Format: "double"},
+			"extra":      {Type: "string"},
+		},
+		Required: []string{"name", "nullable", "created_at", "data", "labels", "Untagged"},
+	}, r.Definitions["schema.testResource"])
+	assert.Len(t, r.Definitions, 1)
+
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}, Nullable: true}, r.Define([]string{}))
+}
+
+func TestSchemaDocuments(t *testing.T) {
+	r := NewRegistry()
+	r.DefineAs("test.Resource", &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"child": {Ref: "test.Resource", Nullable: true},
+			"name":  {Type: "string", Nullable: true},
+		},
+	})
+
+	var document map[string]interface{}
+	b, err := r.JSONSchema()
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, &document))
+	assert.Equal(t, map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"definitions": map[string]interface{}{
+			"test.Resource": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"child": map[string]interface{}{
+						"anyOf": []interface{}{
+							map[string]interface{}{"$ref": "#/definitions/test.Resource"},
+							map[string]interface{}{"type": "null"},
+						},
+					},
+					"name": map[string]interface{}{"type": []interface{}{"string", "null"}},
+				},
+			},
+		},
+	}, document)
+
+	b, err = r.OpenAPI("Horizon", "2.0.0")
+	require.NoError(t, err)
+	document = nil
+	require.NoError(t, json.Unmarshal(b, &document))
+	assert.Equal(t, map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]interface{}{"title": "Horizon", "version": "2.0.0"},
+		"paths":   map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"test.Resource": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"child": map[string]interface{}{
+							"allOf":    []interface{}{map[string]interface{}{"$ref": "#/components/schemas/test.Resource"}},
+							"nullable": true,
+						},
+						"name": map[string]interface{}{"type": "string", "nullable": true},
+					},
+				},
+			},
+		},
+	}, document)
+}
+
+func TestValidate(t *testing.T) {
+	r := NewRegistry()
+	r.Define(testResource{})
+
+	valid := `{
+		"name": "42",
+		"nullable": null,
+		"created_at": "2021-05-04T12:00:00Z",
+		"data": "AQI=",
+		"labels": {"a": "b"},
+		"children": [{"name": "1", "nullable": true, "created_at": "2021-05-04T12:00:00Z", "data": null, "labels": null, "Untagged": 1}],
+		"Untagged": 1.5,
+		"new_property": 1
+	}`
+	assert.NoError(t, r.Validate("schema.testResource", []byte(valid)))
+
+	err := r.ValidateStrict("schema.testResource", []byte(valid))
+	assert.EqualError(t, err, "$.new_property: unknown property")
+
+	err = r.Validate("schema.testResource", []byte(`{
+		"name": 42,
+		"optional": 1.5,
+		"nullable": "true",
+		"created_at": "yesterday",
+		"labels": {"a": 1},
+		"children": [{}],
+		"Untagged": null
+	}`))
+	require.IsType(t, &ValidationError{}, err)
+	assert.Equal(t, []string{
+		"$: missing property data",
+		"$.Untagged: must not be null",
+		`$.children[0]: missing property name`,
+		`$.children[0]: missing property nullable`,
+		`$.children[0]: missing property created_at`,
+		`$.children[0]: missing property data`,
+		`$.children[0]: missing property labels`,
+		`$.children[0]: missing property Untagged`,
+		`$.created_at: "yesterday" is not a date-time`,
+		"$.labels.a: expected string, got number",
+		"$.name: expected string, got number",
+		"$.nullable: expected boolean, got string",
+		"$.optional: expected integer, got 1.5",
+	}, err.(*ValidationError).Errors)
+
+	err = r.Validate("schema.testResource", []byte(`{"name": "4.2"`))
+	assert.EqualError(t, err, "could not decode document: unexpected EOF")
+
+	err = r.Validate("schema.unknown", []byte(`{}`))
+	assert.EqualError(t, err, "unknown definition: schema.unknown")
+}
+
+func TestHorizonResources(t *testing.T) {
+	r := Horizon()
+	for _, resource := range resources {
+		name := definitionName(reflect.TypeOf(resource))
+		t.Run(name, func(t *testing.T) {
+			b, err := json.Marshal(resource)
+			require.NoError(t, err)
+			assert.NoError(t, r.ValidateStrict(name, b))
+		})
+	}
+
+	relBefore := xdr.Int64(100)
+	predicate := xdr.ClaimPredicate{
+		Type: xdr.ClaimPredicateTypeClaimPredicateOr,
+		OrPredicates: &[]xdr.ClaimPredicate{
+			{Type: xdr.ClaimPredicateTypeClaimPredicateUnconditional},
+			{Type: xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime, RelBefore: &relBefore},
+		},
+	}
+	balance := horizon.ClaimableBalance{
+		BalanceID: "00000000",
+		Claimants: []horizon.Claimant{{Destination: "GA", Predicate: predicate}},
+	}
+	b, err := json.Marshal(balance)
+	require.NoError(t, err)
+	assert.NoError(t, r.ValidateStrict("horizon.ClaimableBalance", b))
+
+	page := `{
+		"_links": {"self": {"href": "/accounts"}, "next": {"href": "/accounts?cursor=1"}, "prev": {"href": "/accounts?cursor=0"}},
+		"_embedded": {"records": [{"id": "GA", "balances": [{"balance": 1}]}]}
+	}`
+	err = r.Validate("horizon.AccountsPage", []byte(page))
+	require.IsType(t, &ValidationError{}, err)
+	assert.Contains(t, err.(*ValidationError).Errors, "$._embedded.records[0].balances[0].balance: expected string, got number")
+	assert.Contains(t, err.(*ValidationError).Errors, "$._embedded.records[0]: missing property account_id")
+}
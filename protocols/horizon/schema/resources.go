@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/effects"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/xdr"
+)
+
+// Horizon returns a registry with the definitions of the resources of
+// protocols/horizon, including all operations and effects. The pages of
+// resources are defined as "horizon.AccountsPage", "operations.OperationsPage"
+// etc., whose records are validated against their common fields for
+// operations and effects.
+func Horizon() *Registry {
+	r := NewRegistry()
+	r.Override(xdr.ClaimPredicate{}, claimPredicateSchema())
+	for _, resource := range resources {
+		r.Define(resource)
+	}
+
+	// The page structs name their records differently, and the records of
+	// operations and effects are interfaces, so pages are defined by hand.
+	links := r.Define(hal.Links{})
+	for name, record := range map[string]interface{}{
+		"horizon.AccountsPage":          horizon.Account{},
+		"horizon.AssetsPage":            horizon.AssetStat{},
+		"horizon.ClaimableBalances":     horizon.ClaimableBalance{},
+		"horizon.LedgersPage":           horizon.Ledger{},
+		"horizon.OffersPage":            horizon.Offer{},
+		"horizon.PathsPage":             horizon.Path{},
+		"horizon.SponsorshipsPage":      horizon.Sponsorship{},
+		"horizon.TradeAggregationsPage": horizon.TradeAggregation{},
+		"horizon.TradesPage":            horizon.Trade{},
+		"horizon.TransactionsPage":      horizon.Transaction{},
+		"operations.OperationsPage":     operations.Base{},
+		"effects.EffectsPage":           effects.Base{},
+	} {
+		r.DefineAs(name, pageSchema(links, r.Define(record)))
+	}
+
+	return r
+}
+
+// resources are the resources defined by Horizon, other than pages.
+var resources = []interface{}{
+	horizon.Account{},
+	horizon.AccountData{},
+	horizon.AccountSponsorships{},
+	horizon.AssetStat{},
+	horizon.AsyncTransactionSubmissionResponse{},
+	horizon.ClaimableBalance{},
+	horizon.FeeStats{},
+	horizon.Ledger{},
+	horizon.Offer{},
+	horizon.OrderBookSummary{},
+	horizon.Path{},
+	horizon.Root{},
+	horizon.Sponsorship{},
+	horizon.Trade{},
+	horizon.TradeAggregation{},
+	horizon.Transaction{},
+
+	operations.AccountMerge{},
+	operations.AllowTrust{},
+	operations.BeginSponsoringFutureReserves{},
+	operations.BumpSequence{},
+	operations.ChangeTrust{},
+	operations.ClaimClaimableBalance{},
+	operations.Clawback{},
+	operations.ClawbackClaimableBalance{},
+	operations.CreateAccount{},
+	operations.CreateClaimableBalance{},
+	operations.CreatePassiveSellOffer{},
+	operations.EndSponsoringFutureReserves{},
+	operations.Inflation{},
+	operations.ManageBuyOffer{},
+	operations.ManageData{},
+	operations.ManageSellOffer{},
+	operations.PathPayment{},
+	operations.PathPaymentStrictSend{},
+	operations.Payment{},
+	operations.RevokeSponsorship{},
+	operations.SetOptions{},
+	operations.SetTrustLineFlags{},
+
+	effects.AccountCreated{},
+	effects.AccountCredited{},
+	effects.AccountDebited{},
+	effects.AccountFlagsUpdated{},
+	effects.AccountHomeDomainUpdated{},
+	effects.AccountSponsorshipCreated{},
+	effects.AccountSponsorshipRemoved{},
+	effects.AccountSponsorshipUpdated{},
+	effects.AccountThresholdsUpdated{},
+	effects.ClaimableBalanceClaimantCreated{},
+	effects.ClaimableBalanceClaimed{},
+	effects.ClaimableBalanceClawedBack{},
+	effects.ClaimableBalanceCreated{},
+	effects.ClaimableBalanceSponsorshipCreated{},
+	effects.ClaimableBalanceSponsorshipRemoved{},
+	effects.ClaimableBalanceSponsorshipUpdated{},
+	effects.DataCreated{},
+	effects.DataRemoved{},
+	effects.DataSponsorshipCreated{},
+	effects.DataSponsorshipRemoved{},
+	effects.DataSponsorshipUpdated{},
+	effects.DataUpdated{},
+	effects.SequenceBumped{},
+	effects.SignerCreated{},
+	effects.SignerRemoved{},
+	effects.SignerSponsorshipCreated{},
+	effects.SignerSponsorshipRemoved{},
+	effects.SignerSponsorshipUpdated{},
+	effects.SignerUpdated{},
+	effects.Trade{},
+	effects.TrustlineAuthorized{},
+	effects.TrustlineAuthorizedToMaintainLiabilities{},
+	effects.TrustlineCreated{},
+	effects.TrustlineDeauthorized{},
+	effects.TrustlineFlagsUpdated{},
+	effects.TrustlineRemoved{},
+	effects.TrustlineSponsorshipCreated{},
+	effects.TrustlineSponsorshipRemoved{},
+	effects.TrustlineSponsorshipUpdated{},
+	effects.TrustlineUpdated{},
+}
+
+func pageSchema(links, record *Schema) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"_links": links,
+			"_embedded": {
+				Type: "object",
+				Properties: map[string]*Schema{
+					"records": {Type: "array", Items: record},
+				},
+				Required: []string{"records"},
+			},
+		},
+		Required: []string{"_links", "_embedded"},
+	}
+}
+
+// claimPredicateSchema returns the schema of the JSON encoding of claim
+// predicates, which has one of the properties.
+func claimPredicateSchema() *Schema {
+	predicate := &Schema{Ref: "xdr.ClaimPredicate"}
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"and":           {Type: "array", Items: predicate},
+			"or":            {Type: "array", Items: predicate},
+			"not":           predicate,
+			"unconditional": {Type: "boolean"},
+			// years after 9999 are prefixed with a sign, which RFC 3339
+			// date-times do not allow
+			"abs_before": {Type: "string"},
+			"rel_before": {Type: "string", Pattern: "^-?[0-9]+$"},
+		},
+	}
+}
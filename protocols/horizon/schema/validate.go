@@ -0,0 +1,209 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// ValidationError lists the mismatches between a document and the
+// definition it was validated against.
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Errors, "; ")
+}
+
+// Validate checks that the JSON document data conforms to the definition
+// name. Properties which are not in the definition are allowed, as new
+// properties are added to resources without notice. A *ValidationError is
+// returned if the document does not conform.
+func (r *Registry) Validate(name string, data []byte) error {
+	return r.validate(name, data, false)
+}
+
+// ValidateStrict is Validate, but also rejects properties which are not in
+// the definition. It is meant for tests of the definitions, which would miss
+// new properties otherwise.
+func (r *Registry) ValidateStrict(name string, data []byte) error {
+	return r.validate(name, data, true)
+}
+
+func (r *Registry) validate(name string, data []byte, strict bool) error {
+	s, err := r.Ref(name)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err = decoder.Decode(&document); err != nil {
+		return errors.Wrap(err, "could not decode document")
+	}
+
+	v := validator{registry: r, strict: strict}
+	v.check(s, document, "$")
+	if len(v.errors) > 0 {
+		return &ValidationError{Errors: v.errors}
+	}
+	return nil
+}
+
+type validator struct {
+	registry *Registry
+	strict   bool
+	errors   []string
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) check(s *Schema, value interface{}, path string) {
+	if value == nil {
+		if !s.Nullable && (s.Ref != "" || s.Type != "") {
+			v.errorf(path, "must not be null")
+		}
+		return
+	}
+	if s.Ref != "" {
+		definition, ok := v.registry.Definitions[s.Ref]
+		if !ok {
+			v.errorf(path, "unknown definition %s", s.Ref)
+			return
+		}
+		s = definition
+	}
+
+	switch s.Type {
+	case "":
+		// any value
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.errorf(path, "expected boolean, got %s", typeName(value))
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			v.errorf(path, "expected integer, got %s", typeName(value))
+		} else if strings.ContainsAny(n.String(), ".eE") {
+			v.errorf(path, "expected integer, got %s", n)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			v.errorf(path, "expected number, got %s", typeName(value))
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.errorf(path, "expected string, got %s", typeName(value))
+			return
+		}
+		v.checkString(s, str, path)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			v.errorf(path, "expected array, got %s", typeName(value))
+			return
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				v.check(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.errorf(path, "expected object, got %s", typeName(value))
+			return
+		}
+		v.checkObject(s, object, path)
+	default:
+		v.errorf(path, "unknown schema type %s", s.Type)
+	}
+}
+
+func (v *validator) checkString(s *Schema, str, path string) {
+	if s.Pattern != "" {
+		if rx, err := compilePattern(s.Pattern); err != nil {
+			v.errorf(path, "invalid pattern %s: %s", s.Pattern, err)
+		} else if !rx.MatchString(str) {
+			v.errorf(path, "%q does not match %s", str, s.Pattern)
+		}
+	}
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			v.errorf(path, "%q is not a date-time", str)
+		}
+	}
+}
+
+func (v *validator) checkObject(s *Schema, object map[string]interface{}, path string) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			v.errorf(path, "missing property %s", name)
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propertyPath := path + "." + name
+		if property, ok := s.Properties[name]; ok {
+			v.check(property, object[name], propertyPath)
+		} else if s.AdditionalProperties != nil {
+			v.check(s.AdditionalProperties, object[name], propertyPath)
+		} else if v.strict && s.Properties != nil {
+			v.errorf(propertyPath, "unknown property")
+		}
+	}
+}
+
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+var (
+	patternsLock sync.Mutex
+	patterns     = map[string]*regexp.Regexp{}
+)
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	patternsLock.Lock()
+	defer patternsLock.Unlock()
+	if rx, ok := patterns[pattern]; ok {
+		return rx, nil
+	}
+	rx, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns[pattern] = rx
+	return rx, nil
+}
@@ -0,0 +1,74 @@
+package sep9
+
+// NaturalPersonFields are the SEP-9 fields of natural persons.
+var NaturalPersonFields = []Field{
+	{Name: "last_name", Type: TypeString, Description: "Family or last name"},
+	{Name: "first_name", Type: TypeString, Description: "Given or first name"},
+	{Name: "additional_name", Type: TypeString, Description: "Middle name or other additional name"},
+	{Name: "address_country_code", Type: TypeCountry, Description: "Country code for current address"},
+	{Name: "state_or_province", Type: TypeString, Description: "Name of state/province/region/prefecture"},
+	{Name: "city", Type: TypeString, Description: "Name of city/town"},
+	{Name: "postal_code", Type: TypeString, Description: "Postal or other code identifying user's locale"},
+	{Name: "address", Type: TypeString, Description: "Entire address (country, state, postal code, street address, etc...) as a multi-line string"},
+	{Name: "mobile_number", Type: TypePhone, Description: "Mobile phone number with country code, in E.164 format"},
+	{Name: "email_address", Type: TypeEmail, Description: "Email address"},
+	{Name: "birth_date", Type: TypeDate, Description: "Date of birth, e.g. 1976-07-04"},
+	{Name: "birth_place", Type: TypeString, Description: "Place of birth (city, state, country; as on passport)"},
+	{Name: "birth_country_code", Type: TypeCountry, Description: "ISO Code of country of birth"},
+	{Name: "bank_account_number", Type: TypeString, Description: "Number identifying bank account"},
+	{Name: "bank_account_type", Type: TypeString, Description: "Type of bank account", Values: []string{"checking", "savings"}},
+	{Name: "bank_number", Type: TypeString, Description: "Number identifying bank in national banking system (routing number in US)"},
+	{Name: "bank_phone_number", Type: TypePhone, Description: "Phone number with country code for bank"},
+	{Name: "bank_branch_number", Type: TypeString, Description: "Number identifying bank branch"},
+	{Name: "tax_id", Type: TypeString, Description: "Tax identifier of user in their country (social security number in US)"},
+	{Name: "tax_id_name", Type: TypeString, Description: "Name of the tax ID (SSN or ITIN in the US)"},
+	{Name: "occupation", Type: TypeNumber, Description: "Occupation ISCO code"},
+	{Name: "employer_name", Type: TypeString, Description: "Name of employer"},
+	{Name: "employer_address", Type: TypeString, Description: "Address of employer"},
+	{Name: "language_code", Type: TypeLanguage, Description: "Primary language"},
+	{Name: "id_type", Type: TypeString, Description: "Type of identification", Values: []string{"passport", "drivers_license", "id_card"}},
+	{Name: "id_country_code", Type: TypeCountry, Description: "Country issuing passport or photo ID as ISO 3166-1 alpha-3 code"},
+	{Name: "id_issue_date", Type: TypeDate, Description: "ID issue date"},
+	{Name: "id_expiration_date", Type: TypeDate, Description: "ID expiration date"},
+	{Name: "id_number", Type: TypeString, Description: "Passport or ID number"},
+	{Name: "photo_id_front", Type: TypeBinary, Description: "Image of front of user's photo ID or passport"},
+	{Name: "photo_id_back", Type: TypeBinary, Description: "Image of back of user's photo ID or passport"},
+	{Name: "notary_approval_of_photo_id", Type: TypeBinary, Description: "Image of notary's approval of photo ID or passport"},
+	{Name: "ip_address", Type: TypeIP, Description: "IP address of customer's computer"},
+	{Name: "photo_proof_residence", Type: TypeBinary, Description: "Image of a utility bill, bank statement or similar with the user's name and address"},
+	{Name: "sex", Type: TypeString, Description: "Sex of the user", Values: []string{"male", "female", "other"}},
+	{Name: "proof_of_income", Type: TypeBinary, Description: "Image of user's proof of income document"},
+	{Name: "proof_of_liveness", Type: TypeBinary, Description: "Video or image file of user as a liveness proof"},
+	{Name: "referral_id", Type: TypeString, Description: "User's origin (such as an id in another application) or a referral code"},
+}
+
+// OrganizationFields are the SEP-9 fields of organizations.
+var OrganizationFields = []Field{
+	{Name: "organization.name", Type: TypeString, Description: "Full organization name as on the incorporation papers"},
+	{Name: "organization.VAT_number", Type: TypeString, Description: "Organization VAT number"},
+	{Name: "organization.registration_number", Type: TypeString, Description: "Organization registration number"},
+	{Name: "organization.registration_date", Type: TypeDate, Description: "Date the organization was registered"},
+	{Name: "organization.registered_address", Type: TypeString, Description: "Organization registered address"},
+	{Name: "organization.number_of_shareholders", Type: TypeNumber, Description: "Organization shareholder number"},
+	{Name: "organization.shareholder_name", Type: TypeString, Description: "Can be an organization or a person and should be queried recursively up to the ultimate beneficial owners"},
+	{Name: "organization.photo_incorporation_doc", Type: TypeBinary, Description: "Image of incorporation documents"},
+	{Name: "organization.photo_proof_address", Type: TypeBinary, Description: "Image of a utility bill, bank statement with the organization's name and address"},
+	{Name: "organization.address_country_code", Type: TypeCountry, Description: "Country code for current address"},
+	{Name: "organization.state_or_province", Type: TypeString, Description: "Name of state/province/region/prefecture"},
+	{Name: "organization.city", Type: TypeString, Description: "Name of city/town"},
+	{Name: "organization.postal_code", Type: TypeString, Description: "Postal or other code identifying organization's locale"},
+	{Name: "organization.director_name", Type: TypeString, Description: "Organization registered managing director (the rest of the information should be queried as an individual using the natural person fields)"},
+	{Name: "organization.website", Type: TypeURL, Description: "Organization website"},
+	{Name: "organization.email", Type: TypeEmail, Description: "Organization contact email"},
+	{Name: "organization.phone", Type: TypePhone, Description: "Organization contact phone"},
+}
+
+var fieldsByName = map[string]Field{}
+
+func init() {
+	for _, fields := range [][]Field{NaturalPersonFields, OrganizationFields} {
+		for _, f := range fields {
+			fieldsByName[f.Name] = f
+		}
+	}
+}
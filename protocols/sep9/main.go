@@ -0,0 +1,199 @@
+// Package sep9 provides the registry of the standard KYC/AML fields defined
+// in SEP-9, with their types and validators, and helpers to validate and
+// encode sets of field values such as the ones sent to SEP-12 servers.
+//
+// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0009.md
+package sep9
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// Type is the type of the value of a field.
+type Type string
+
+const (
+	// TypeString is a free-form string.
+	TypeString Type = "string"
+	// TypeEmail is an email address.
+	TypeEmail Type = "email"
+	// TypePhone is a phone number in E.164 format, e.g. +14155552671.
+	TypePhone Type = "phone"
+	// TypeDate is an ISO 8601 date, e.g. 1990-01-31.
+	TypeDate Type = "date"
+	// TypeCountry is an ISO 3166-1 alpha-3 country code, e.g. USA.
+	TypeCountry Type = "country_code"
+	// TypeLanguage is an ISO 639-1 language code, e.g. en.
+	TypeLanguage Type = "language_code"
+	// TypeNumber is a non-negative integer.
+	TypeNumber Type = "number"
+	// TypeIP is an IPv4 or IPv6 address.
+	TypeIP Type = "ip_address"
+	// TypeURL is an http or https URL.
+	TypeURL Type = "url"
+	// TypeBinary is the content of a file, e.g. an image or a PDF.
+	TypeBinary Type = "binary"
+)
+
+// DateLayout is the layout of TypeDate values for time.Parse.
+const DateLayout = "2006-01-02"
+
+var (
+	// RxEmail is the regular expression validating TypeEmail values, according
+	// with the reference
+	// https://www.alexedwards.net/blog/validation-snippets-for-go#email-validation.
+	// It's free to use under the [MIT Licence](https://opensource.org/licenses/MIT)
+	RxEmail    = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+	rxPhone    = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	rxCountry  = regexp.MustCompile(`^[A-Z]{3}$`)
+	rxLanguage = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// Field is a SEP-9 field.
+type Field struct {
+	Name        string
+	Type        Type
+	Description string
+	// Values, if not empty, are the only values the field accepts.
+	Values []string
+}
+
+// Validate returns an error if value is not a valid value of the field.
+func (f Field) Validate(value string) error {
+	if value == "" {
+		return errors.Errorf("%s cannot be empty", f.Name)
+	}
+
+	if len(f.Values) > 0 {
+		for _, v := range f.Values {
+			if value == v {
+				return nil
+			}
+		}
+		return errors.Errorf("%s must be one of %s", f.Name, strings.Join(f.Values, ", "))
+	}
+
+	var valid bool
+	switch f.Type {
+	case TypeString, TypeBinary:
+		valid = true
+	case TypeEmail:
+		valid = RxEmail.MatchString(value)
+	case TypePhone:
+		valid = rxPhone.MatchString(value)
+	case TypeDate:
+		_, err := time.Parse(DateLayout, value)
+		valid = err == nil
+	case TypeCountry:
+		valid = rxCountry.MatchString(value)
+	case TypeLanguage:
+		valid = rxLanguage.MatchString(value)
+	case TypeNumber:
+		_, err := strconv.ParseUint(value, 10, 64)
+		valid = err == nil
+	case TypeIP:
+		valid = net.ParseIP(value) != nil
+	case TypeURL:
+		u, err := url.Parse(value)
+		valid = err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	default:
+		return errors.Errorf("%s has unknown type %s", f.Name, f.Type)
+	}
+	if !valid {
+		return errors.Errorf("%s is not a valid %s", f.Name, f.Type)
+	}
+	return nil
+}
+
+// Lookup returns the SEP-9 field with the given name.
+func Lookup(name string) (Field, bool) {
+	f, ok := fieldsByName[name]
+	return f, ok
+}
+
+// Validate returns an error if name is not a SEP-9 field or value is not a
+// valid value of the field.
+func Validate(name, value string) error {
+	f, ok := Lookup(name)
+	if !ok {
+		return errors.Errorf("%s is not a SEP-9 field", name)
+	}
+	return f.Validate(value)
+}
+
+// Values are the values of SEP-9 fields keyed by field name, e.g. the
+// fields of a SEP-12 PUT /customer request.
+type Values map[string]string
+
+// ValuesFromForm returns the values of the SEP-9 fields of form, ignoring
+// its other fields. Only the first value of each field is kept.
+func ValuesFromForm(form url.Values) Values {
+	values := Values{}
+	for name := range form {
+		if _, ok := Lookup(name); ok {
+			values[name] = form.Get(name)
+		}
+	}
+	return values
+}
+
+// Validate returns an error listing the values which are not valid, or the
+// names which are not SEP-9 fields, in the order of their names.
+func (v Values) Validate() error {
+	names := make([]string, 0, len(v))
+	for name := range v {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		if err := Validate(name, v[name]); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Form returns the values as a form, e.g. to be sent with
+// application/x-www-form-urlencoded.
+func (v Values) Form() url.Values {
+	form := url.Values{}
+	for name, value := range v {
+		form.Set(name, value)
+	}
+	return form
+}
+
+// Date returns the value of the TypeDate field name, and false if it is not
+// set.
+func (v Values) Date(name string) (time.Time, bool, error) {
+	value, ok := v[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	if f, ok := Lookup(name); !ok || f.Type != TypeDate {
+		return time.Time{}, true, errors.Errorf("%s is not a SEP-9 date field", name)
+	}
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, true, errors.Errorf("%s is not a valid %s", name, TypeDate)
+	}
+	return t, true, nil
+}
+
+// SetDate sets the TypeDate field name to the date of t.
+func (v Values) SetDate(name string, t time.Time) {
+	v[name] = t.Format(DateLayout)
+}
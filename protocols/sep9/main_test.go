@@ -0,0 +1,123 @@
+package sep9
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldsAreUnique(t *testing.T) {
+	assert.Len(t, fieldsByName, len(NaturalPersonFields)+len(OrganizationFields))
+}
+
+func TestLookup(t *testing.T) {
+	f, ok := Lookup("mobile_number")
+	require.True(t, ok)
+	assert.Equal(t, TypePhone, f.Type)
+
+	f, ok = Lookup("organization.website")
+	require.True(t, ok)
+	assert.Equal(t, TypeURL, f.Type)
+
+	_, ok = Lookup("favorite_color")
+	assert.False(t, ok)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"first_name", "Jane", true},
+		{"first_name", "", false},
+		{"email_address", "t@email.com", true},
+		{"email_address", "email.com", false},
+		{"email_address", "@email.com", false},
+		{"mobile_number", "+14155552671", true},
+		{"mobile_number", "14155552671", false},
+		{"mobile_number", "+0123", false},
+		{"mobile_number", "+1234567890123456", false},
+		{"birth_date", "1976-07-04", true},
+		{"birth_date", "1976-13-04", false},
+		{"birth_date", "04/07/1976", false},
+		{"address_country_code", "USA", true},
+		{"address_country_code", "US", false},
+		{"address_country_code", "usa", false},
+		{"language_code", "en", true},
+		{"language_code", "EN", false},
+		{"occupation", "2512", true},
+		{"occupation", "-1", false},
+		{"ip_address", "192.168.0.1", true},
+		{"ip_address", "2001:db8::1", true},
+		{"ip_address", "192.168.0", false},
+		{"organization.website", "https://example.com", true},
+		{"organization.website", "example.com", false},
+		{"organization.website", "ftp://example.com", false},
+		{"sex", "female", true},
+		{"sex", "F", false},
+		{"id_type", "passport", true},
+		{"bank_account_type", "savings", true},
+		{"bank_account_type", "current", false},
+		{"photo_id_front", "\x89PNG", true},
+		{"favorite_color", "blue", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name+"/"+tc.value, func(t *testing.T) {
+			err := Validate(tc.name, tc.value)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestValuesValidate(t *testing.T) {
+	values := Values{
+		"first_name":    "Jane",
+		"email_address": "jane@example.com",
+		"birth_date":    "1976-07-04",
+	}
+	assert.NoError(t, values.Validate())
+
+	values["mobile_number"] = "5552671"
+	values["favorite_color"] = "blue"
+	assert.EqualError(t, values.Validate(), "favorite_color is not a SEP-9 field; mobile_number is not a valid phone")
+}
+
+func TestValuesFromForm(t *testing.T) {
+	form := url.Values{
+		"first_name": {"Jane", "Janet"},
+		"account":    {"GABC"},
+	}
+	values := ValuesFromForm(form)
+	assert.Equal(t, Values{"first_name": "Jane"}, values)
+	assert.Equal(t, url.Values{"first_name": {"Jane"}}, values.Form())
+}
+
+func TestValuesDate(t *testing.T) {
+	values := Values{}
+	_, ok, err := values.Date("birth_date")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	values.SetDate("birth_date", time.Date(1976, 7, 4, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, "1976-07-04", values["birth_date"])
+	date, ok, err := values.Date("birth_date")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(1976, 7, 4, 0, 0, 0, 0, time.UTC), date)
+
+	values["first_name"] = "Jane"
+	_, _, err = values.Date("first_name")
+	assert.EqualError(t, err, "first_name is not a SEP-9 date field")
+
+	values["birth_date"] = "1976-07-32"
+	_, _, err = values.Date("birth_date")
+	assert.EqualError(t, err, "birth_date is not a valid date")
+}
@@ -0,0 +1,46 @@
+package stellarcore
+
+// GetSurveyResultResponse is the json response returned from stellar-core's
+// /getsurveyresult endpoint.
+type GetSurveyResultResponse struct {
+	// Backlog is the list of nodes which were not surveyed yet.
+	Backlog []string `json:"backlog"`
+	// BadResponseNodes is the list of nodes which responded with invalid data.
+	BadResponseNodes []string `json:"badResponseNodes"`
+	SurveyInProgress bool     `json:"surveyInProgress"`
+	// Topology maps the ids of the surveyed nodes to their responses. Nodes
+	// which did not respond yet have a nil value.
+	Topology map[string]*SurveyTopology `json:"topology"`
+}
+
+// SurveyTopology is the response of a surveyed node, describing its
+// connections to its peers.
+type SurveyTopology struct {
+	InboundPeers          []SurveyPeer `json:"inboundPeers"`
+	OutboundPeers         []SurveyPeer `json:"outboundPeers"`
+	NumTotalInboundPeers  int          `json:"numTotalInboundPeers"`
+	NumTotalOutboundPeers int          `json:"numTotalOutboundPeers"`
+}
+
+// SurveyPeer are the statistics a surveyed node reports about the connection
+// to one of its peers.
+type SurveyPeer struct {
+	NodeID                    string `json:"nodeId"`
+	Version                   string `json:"version"`
+	MessagesRead              uint64 `json:"messagesRead"`
+	MessagesWritten           uint64 `json:"messagesWritten"`
+	BytesRead                 uint64 `json:"bytesRead"`
+	BytesWritten              uint64 `json:"bytesWritten"`
+	SecondsConnected          uint64 `json:"secondsConnected"`
+	UniqueFloodBytesRecv      uint64 `json:"uniqueFloodBytesRecv"`
+	DuplicateFloodBytesRecv   uint64 `json:"duplicateFloodBytesRecv"`
+	UniqueFetchBytesRecv      uint64 `json:"uniqueFetchBytesRecv"`
+	DuplicateFetchBytesRecv   uint64 `json:"duplicateFetchBytesRecv"`
+	UniqueFloodMessageRecv    uint64 `json:"uniqueFloodMessageRecv"`
+	DuplicateFloodMessageRecv uint64 `json:"duplicateFloodMessageRecv"`
+	UniqueFetchMessageRecv    uint64 `json:"uniqueFetchMessageRecv"`
+	DuplicateFetchMessageRecv uint64 `json:"duplicateFetchMessageRecv"`
+	// AverageLatencyMs is only reported by stellar-core versions measuring
+	// the latency of their peers, it is zero otherwise.
+	AverageLatencyMs float64 `json:"averageLatencyMs,omitempty"`
+}
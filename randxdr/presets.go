@@ -57,6 +57,12 @@ var IsNestedInnerSet Selector = func(name string, xdrType goxdr.XdrType) bool {
 	return false
 }
 
+// IsString is a Selector which matches on all XDR string fields
+var IsString Selector = func(name string, xdrType goxdr.XdrType) bool {
+	_, ok := goxdr.XdrBaseType(xdrType).(goxdr.XdrString)
+	return ok
+}
+
 // SetPtrToPresent is a Setter which ensures that a given XDR pointer field is not nil
 var SetPtrToPresent Setter = func(m *randMarshaller, name string, xdrType goxdr.XdrType) {
 	p := goxdr.XdrBaseType(xdrType).(goxdr.XdrPtr)
@@ -97,3 +103,26 @@ func SetPositiveNum32() Setter {
 		f.SetU32(uint32(x.rand.Int31n(math.MaxInt32)))
 	}
 }
+
+const alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// SetAlphanumeric is a Setter which fills a string or opaque XDR field with
+// random alphanumeric characters. Variable length fields are given a random
+// length.
+var SetAlphanumeric Setter = func(x *randMarshaller, field string, xdrType goxdr.XdrType) {
+	var bs []byte
+	switch t := goxdr.XdrBaseType(xdrType).(type) {
+	case goxdr.XdrVarBytes:
+		bound := t.XdrBound()
+		if bound > x.maxBytesSize {
+			bound = x.maxBytesSize
+		}
+		bs = make([]byte, x.rand.Uint32()%(bound+1))
+		defer t.SetByteSlice(bs)
+	case goxdr.XdrBytes:
+		bs = t.GetByteSlice()
+	}
+	for i := range bs {
+		bs[i] = alphanumeric[x.rand.Intn(len(alphanumeric))]
+	}
+}
@@ -20,7 +20,7 @@ type Config struct {
 	Port     int `valid:"required"`
 	Database struct {
 		Type string `valid:"matches(^sqlite3|postgres$)"`
-		DSN  string `valid:"required"`
+		DSN  string `valid:"required" secret:"true"`
 	} `valid:"required"`
 	Queries struct {
 		Federation        string `valid:"required"`
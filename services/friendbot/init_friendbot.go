@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/services/friendbot/internal"
@@ -18,15 +20,27 @@ func initFriendbot(
 	networkPassphrase string,
 	horizonURL string,
 	startingBalance string,
+	maxStartingBalance string,
+	assets []internal.Asset,
 	numMinions int,
+	maxMinions int,
+	minionIdleTimeout time.Duration,
 	baseFee int64,
 	minionBatchSize int,
 	submitTxRetriesAllowed int,
+	metrics *internal.Metrics,
 ) (*internal.Bot, error) {
 	if friendbotSecret == "" || networkPassphrase == "" || horizonURL == "" || startingBalance == "" || numMinions < 0 {
 		return nil, errors.New("invalid input param(s)")
 	}
 
+	if maxStartingBalance != "" {
+		max, err := amount.ParseInt64(maxStartingBalance)
+		if err != nil || max <= 0 {
+			return nil, errors.Errorf("invalid max starting balance %q", maxStartingBalance)
+		}
+	}
+
 	// Guarantee that friendbotSecret is a seed, if not blank.
 	strkey.MustDecode(strkey.VersionByteSeed, friendbotSecret)
 
@@ -44,12 +58,15 @@ func initFriendbot(
 	// Casting from the interface type will work, since we
 	// already confirmed that friendbotSecret is a seed.
 	botKeypair := botKP.(*keypair.Full)
-	botAccount := internal.Account{AccountID: botKeypair.Address()}
+	botAccount := &internal.Account{AccountID: botKeypair.Address()}
 	// set default values
 	minionBalance := "101.00"
 	if numMinions == 0 {
 		numMinions = 1000
 	}
+	if maxMinions == 0 {
+		maxMinions = 2 * numMinions
+	}
 	if minionBatchSize == 0 {
 		minionBatchSize = 50
 	}
@@ -62,20 +79,31 @@ func initFriendbot(
 		return nil, errors.Wrap(err, "creating minion accounts")
 	}
 	log.Printf("Adding %d minions to friendbot", len(minions))
-	return &internal.Bot{Minions: minions}, nil
+	pool := internal.NewMinionPool(minions, maxMinions)
+	pool.CreateMinions = func(n int) ([]*internal.Minion, error) {
+		return createMinionAccounts(botAccount, botKeypair, networkPassphrase, startingBalance, minionBalance, n, minionBatchSize, submitTxRetriesAllowed, baseFee, hclient)
+	}
+	pool.BatchSize = minionBatchSize
+	pool.IdleTimeout = minionIdleTimeout
+	pool.Metrics = metrics
+	return &internal.Bot{
+		Minions:            pool,
+		MaxStartingBalance: maxStartingBalance,
+		Assets:             assets,
+	}, nil
 }
 
-func createMinionAccounts(botAccount internal.Account, botKeypair *keypair.Full, networkPassphrase, newAccountBalance, minionBalance string,
-	numMinions, minionBatchSize, submitTxRetriesAllowed int, baseFee int64, hclient horizonclient.ClientInterface) ([]internal.Minion, error) {
+func createMinionAccounts(botAccount *internal.Account, botKeypair *keypair.Full, networkPassphrase, newAccountBalance, minionBalance string,
+	numMinions, minionBatchSize, submitTxRetriesAllowed int, baseFee int64, hclient horizonclient.ClientInterface) ([]*internal.Minion, error) {
 
-	var minions []internal.Minion
+	var minions []*internal.Minion
 	numRemainingMinions := numMinions
 	// Allow retries to account for testnet congestion
 	currentSubmitTxRetry := 0
 
 	for numRemainingMinions > 0 {
 		var (
-			newMinions []internal.Minion
+			newMinions []*internal.Minion
 			ops        []txnbuild.Operation
 		)
 		// Refresh the sequence number before submitting a new transaction.
@@ -94,7 +122,7 @@ func createMinionAccounts(botAccount internal.Account, botKeypair *keypair.Full,
 			if err != nil {
 				return minions, errors.Wrap(err, "making keypair")
 			}
-			newMinions = append(newMinions, internal.Minion{
+			newMinions = append(newMinions, &internal.Minion{
 				Account:              internal.Account{AccountID: minionKeypair.Address()},
 				Keypair:              minionKeypair,
 				BotAccount:           botAccount,
@@ -2,6 +2,7 @@ package main
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stellar/go/clients/horizonclient"
@@ -25,7 +26,7 @@ func TestInitFriendbot_createMinionAccounts_success(t *testing.T) {
 		AccountID: botAccountID,
 		Sequence:  "1",
 	}
-	botAccount := internal.Account{AccountID: botAccountID, Sequence: 1}
+	botAccount := &internal.Account{AccountID: botAccountID, Sequence: 1}
 
 	horizonClientMock := horizonclient.MockClient{}
 	horizonClientMock.
@@ -57,7 +58,7 @@ func TestInitFriendbot_createMinionAccounts_timeoutError(t *testing.T) {
 		AccountID: botAccountID,
 		Sequence:  "1",
 	}
-	botAccount := internal.Account{AccountID: botAccountID, Sequence: 1}
+	botAccount := &internal.Account{AccountID: botAccountID, Sequence: 1}
 
 	horizonClientMock := horizonclient.MockClient{}
 	horizonClientMock.
@@ -89,3 +90,16 @@ func TestInitFriendbot_createMinionAccounts_timeoutError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "after retrying 5 times: submitting create accounts tx:")
 }
+
+func TestInitRouter_keypairPostOnly(t *testing.T) {
+	router := initRouter(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/keypair", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// Friendbot is disabled, but the route exists.
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/keypair", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -20,7 +20,7 @@ func (a Account) GetAccountID() string {
 
 // IncrementSequenceNumber increments the internal record of the
 // account's sequence number by 1.
-func (a Account) IncrementSequenceNumber() (int64, error) {
+func (a *Account) IncrementSequenceNumber() (int64, error) {
 	a.Sequence++
 	return a.Sequence, nil
 }
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Asset is a non-native asset that friendbot funds new accounts with. The
+// payments of the asset are made from the balance of the friendbot account,
+// which must hold it, so that friendbot does not need the key of the issuer.
+type Asset struct {
+	Code   string
+	Issuer string
+	Amount string
+}
+
+// NewAsset returns the asset with the code issued by the issuer account,
+// funding new accounts with the amount.
+func NewAsset(code, issuer, assetAmount string) (Asset, error) {
+	if !strkey.IsValidEd25519PublicKey(issuer) {
+		return Asset{}, errors.Errorf("invalid issuer %q of asset %s", issuer, code)
+	}
+	a := Asset{Code: code, Issuer: issuer, Amount: assetAmount}
+	if _, err := a.creditAsset().GetType(); err != nil {
+		return Asset{}, errors.Wrapf(err, "invalid asset code %q", code)
+	}
+	if amt, err := amount.ParseInt64(assetAmount); err != nil || amt <= 0 {
+		return Asset{}, errors.Errorf("invalid amount %q of asset %s", assetAmount, code)
+	}
+	return a, nil
+}
+
+func (a Asset) creditAsset() txnbuild.CreditAsset {
+	return txnbuild.CreditAsset{Code: a.Code, Issuer: a.Issuer}
+}
@@ -1,18 +1,36 @@
 package internal
 
 import (
-	"log"
-	"sync"
+	"context"
 
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/keypair"
 	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
 )
 
 // Bot represents the friendbot subsystem and primarily delegates work
-// to its Minions.
+// to the Minions of its pool.
 type Bot struct {
-	Minions         []Minion
-	nextMinionIndex int
-	indexMux        sync.Mutex
+	Minions *MinionPool
+	// MaxStartingBalance is the largest starting balance that can be
+	// requested. Requests for a starting balance are rejected if empty.
+	MaxStartingBalance string
+	// Assets are the non-native assets accounts created with CreateAccount
+	// are funded with. Accounts funded with Pay or PayAmount only receive
+	// XLM, as adding the trustlines of the assets must be signed for by the
+	// account, whose key friendbot does not have.
+	Assets []Asset
+}
+
+// InvalidAmountError is returned by PayAmount and CreateAccount when the
+// starting balance can't be requested, see CheckAmount.
+type InvalidAmountError struct {
+	Err error
+}
+
+func (e *InvalidAmountError) Error() string {
+	return e.Err.Error()
 }
 
 // SubmitResult is the result from the asynchronous tx submission.
@@ -22,15 +40,81 @@ type SubmitResult struct {
 }
 
 // Pay funds the account at `destAddress`.
-func (bot *Bot) Pay(destAddress string) (*hProtocol.Transaction, error) {
-	bot.indexMux.Lock()
-	log.Printf("Selecting minion at index %d of max length %d", bot.nextMinionIndex, len(bot.Minions))
-	minion := bot.Minions[bot.nextMinionIndex]
-	bot.nextMinionIndex = (bot.nextMinionIndex + 1) % len(bot.Minions)
-	bot.indexMux.Unlock()
+func (bot *Bot) Pay(ctx context.Context, destAddress string) (*hProtocol.Transaction, error) {
+	return bot.submit(ctx, Request{Destination: destAddress})
+}
+
+// PayAmount funds the account at `destAddress` with a starting balance of
+// `startingBalance`, or the default starting balance if empty. The account is
+// not funded with the configured assets, see Assets.
+func (bot *Bot) PayAmount(ctx context.Context, destAddress, startingBalance string) (*hProtocol.Transaction, error) {
+	err := bot.CheckAmount(startingBalance)
+	if err != nil {
+		return nil, &InvalidAmountError{Err: err}
+	}
+	return bot.submit(ctx, Request{Destination: destAddress, Amount: startingBalance})
+}
+
+// CreateAccount creates an account with a random keypair, funded with a
+// starting balance of `startingBalance`, or the default starting balance if
+// empty, and with the configured assets.
+func (bot *Bot) CreateAccount(ctx context.Context, startingBalance string) (*keypair.Full, *hProtocol.Transaction, error) {
+	err := bot.CheckAmount(startingBalance)
+	if err != nil {
+		return nil, nil, &InvalidAmountError{Err: err}
+	}
+	kp, err := keypair.Random()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "making keypair")
+	}
+	tx, err := bot.submit(ctx, Request{
+		Destination: kp.Address(),
+		Amount:      startingBalance,
+		Assets:      bot.Assets,
+		Keypair:     kp,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return kp, tx, nil
+}
+
+// CheckAmount returns an error if `startingBalance` can't be requested. An
+// empty starting balance is always allowed and means the default starting
+// balance.
+func (bot *Bot) CheckAmount(startingBalance string) error {
+	if startingBalance == "" {
+		return nil
+	}
+	if bot.MaxStartingBalance == "" {
+		return errors.New("requesting a starting balance is not enabled")
+	}
+	max, err := amount.ParseInt64(bot.MaxStartingBalance)
+	if err != nil {
+		return errors.Wrap(err, "parsing max starting balance")
+	}
+	amt, err := amount.ParseInt64(startingBalance)
+	if err != nil {
+		return errors.New("must be a valid amount")
+	}
+	if amt <= 0 || amt > max {
+		return errors.Errorf("must be greater than 0 and at most %s", amount.StringFromInt64(max))
+	}
+	return nil
+}
+
+func (bot *Bot) submit(ctx context.Context, req Request) (*hProtocol.Transaction, error) {
+	minion, err := bot.Minions.Acquire(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "acquiring minion")
+	}
+	defer bot.Minions.Release(minion)
+
 	resultChan := make(chan SubmitResult)
-	go minion.Run(destAddress, resultChan)
+	go minion.Run(req, resultChan)
 	maybeSubmitResult := <-resultChan
 	close(resultChan)
+	failed := maybeSubmitResult.maybeErr != nil && errors.Cause(maybeSubmitResult.maybeErr) != ErrAccountExists
+	bot.Minions.Metrics.recordSubmission(failed)
 	return maybeSubmitResult.maybeTransactionSuccess, maybeSubmitResult.maybeErr
 }
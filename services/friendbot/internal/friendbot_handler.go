@@ -15,6 +15,14 @@ type FriendbotHandler struct {
 	Friendbot *Bot
 }
 
+// KeypairResponse is the response of HandleKeypair, containing the keypair
+// of the account created and the transaction that created it.
+type KeypairResponse struct {
+	AccountID   string              `json:"account_id"`
+	Secret      string              `json:"secret"`
+	Transaction horizon.Transaction `json:"transaction"`
+}
+
 // Handle is a method that implements http.HandlerFunc
 func (handler *FriendbotHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	accountExistsProblem := problem.BadRequest
@@ -48,7 +56,51 @@ func (handler *FriendbotHandler) doHandle(r *http.Request) (*horizon.Transaction
 	if err != nil {
 		return nil, problem.MakeInvalidFieldProblem("addr", err)
 	}
-	return handler.Friendbot.Pay(address)
+	tx, err := handler.Friendbot.PayAmount(r.Context(), address, r.Form.Get("amount"))
+	if amountErr, ok := err.(*InvalidAmountError); ok {
+		return nil, problem.MakeInvalidFieldProblem("amount", amountErr.Err)
+	}
+	return tx, err
+}
+
+// HandleKeypair is a method that implements http.HandlerFunc. It creates an
+// account with a new keypair, funded with the configured assets, and responds
+// with the keypair. It must only be routed for POST requests, so that the
+// secret of the account is not cached or logged along with a GET URL.
+func (handler *FriendbotHandler) HandleKeypair(w http.ResponseWriter, r *http.Request) {
+	result, err := handler.doHandleKeypair(r)
+	if err != nil {
+		problem.Render(r.Context(), w, err)
+		return
+	}
+
+	hal.Render(w, *result)
+}
+
+func (handler *FriendbotHandler) doHandleKeypair(r *http.Request) (*KeypairResponse, error) {
+	err := handler.checkEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		p := problem.BadRequest
+		p.Detail = "Request parameters are not escaped or incorrectly formatted."
+		return nil, &p
+	}
+
+	kp, tx, err := handler.Friendbot.CreateAccount(r.Context(), r.Form.Get("amount"))
+	if amountErr, ok := err.(*InvalidAmountError); ok {
+		return nil, problem.MakeInvalidFieldProblem("amount", amountErr.Err)
+	} else if err != nil {
+		return nil, err
+	}
+	return &KeypairResponse{
+		AccountID:   kp.Address(),
+		Secret:      kp.Seed(),
+		Transaction: *tx,
+	}, nil
 }
 
 func (handler *FriendbotHandler) checkEnabled() error {
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"sync"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/stellar/go/keypair"
 	hProtocol "github.com/stellar/go/protocols/horizon"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFriendbot_Pay(t *testing.T) {
@@ -40,7 +42,7 @@ func TestFriendbot_Pay(t *testing.T) {
 			Sequence:  1,
 		},
 		Keypair:              minionKeypair.(*keypair.Full),
-		BotAccount:           botAccount,
+		BotAccount:           &botAccount,
 		BotKeypair:           botKeypair.(*keypair.Full),
 		Network:              "Test SDF Network ; September 2015",
 		StartingBalance:      "10000.00",
@@ -48,10 +50,10 @@ func TestFriendbot_Pay(t *testing.T) {
 		CheckSequenceRefresh: CheckSequenceRefresh,
 		BaseFee:              txnbuild.MinBaseFee,
 	}
-	fb := &Bot{Minions: []Minion{minion}}
+	fb := &Bot{Minions: NewMinionPool([]*Minion{&minion}, 1)}
 
 	recipientAddress := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
-	txSuccess, err := fb.Pay(recipientAddress)
+	txSuccess, err := fb.Pay(context.Background(), recipientAddress)
 	if !assert.NoError(t, err) {
 		return
 	}
@@ -62,14 +64,107 @@ func TestFriendbot_Pay(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
-		_, err := fb.Pay(recipientAddress)
+		_, err := fb.Pay(context.Background(), recipientAddress)
 		assert.NoError(t, err)
 		wg.Done()
 	}()
 	go func() {
-		_, err := fb.Pay(recipientAddress)
+		_, err := fb.Pay(context.Background(), recipientAddress)
 		assert.NoError(t, err)
 		wg.Done()
 	}()
 	wg.Wait()
 }
+
+func newTestBot() *Bot {
+	mockSubmitTransaction := func(minion *Minion, hclient horizonclient.ClientInterface, tx string) (*hProtocol.Transaction, error) {
+		txSuccess := hProtocol.Transaction{EnvelopeXdr: tx, Successful: true}
+		return &txSuccess, nil
+	}
+	botKeypair := keypair.MustParseFull("SCWNLYELENPBXN46FHYXETT5LJCYBZD5VUQQVW4KZPHFO2YTQJUWT4D5")
+	minionKeypair := keypair.MustParseFull("SDTNSEERJPJFUE2LSDNYBFHYGVTPIWY7TU2IOJZQQGLWO2THTGB7NU5A")
+	minion := Minion{
+		Account: Account{
+			AccountID: minionKeypair.Address(),
+			Sequence:  1,
+		},
+		Keypair:              minionKeypair,
+		BotAccount:           &Account{AccountID: botKeypair.Address()},
+		BotKeypair:           botKeypair,
+		Network:              "Test SDF Network ; September 2015",
+		StartingBalance:      "10000.00",
+		SubmitTransaction:    mockSubmitTransaction,
+		CheckSequenceRefresh: CheckSequenceRefresh,
+		BaseFee:              txnbuild.MinBaseFee,
+	}
+	return &Bot{Minions: NewMinionPool([]*Minion{&minion}, 1)}
+}
+
+func TestFriendbot_PayAmount(t *testing.T) {
+	fb := newTestBot()
+	recipientAddress := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
+
+	_, err := fb.PayAmount(context.Background(), recipientAddress, "50")
+	assert.EqualError(t, err, "requesting a starting balance is not enabled")
+
+	fb.MaxStartingBalance = "100"
+	_, err = fb.PayAmount(context.Background(), recipientAddress, "100.0000001")
+	assert.EqualError(t, err, "must be greater than 0 and at most 100.0000000")
+	_, err = fb.PayAmount(context.Background(), recipientAddress, "0")
+	assert.EqualError(t, err, "must be greater than 0 and at most 100.0000000")
+	_, err = fb.PayAmount(context.Background(), recipientAddress, "ten")
+	assert.EqualError(t, err, "must be a valid amount")
+
+	txSuccess, err := fb.PayAmount(context.Background(), recipientAddress, "50")
+	require.NoError(t, err)
+	tx, err := txnbuild.TransactionFromXDR(txSuccess.EnvelopeXdr)
+	require.NoError(t, err)
+	simpleTx, ok := tx.Transaction()
+	require.True(t, ok)
+	require.Len(t, simpleTx.Operations(), 1)
+	op := simpleTx.Operations()[0].(*txnbuild.CreateAccount)
+	assert.Equal(t, recipientAddress, op.Destination)
+	assert.Equal(t, "50.0000000", op.Amount)
+}
+
+func TestFriendbot_CreateAccount(t *testing.T) {
+	fb := newTestBot()
+	issuer := "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H"
+	usdc, err := NewAsset("USDC", issuer, "100")
+	require.NoError(t, err)
+	eurt, err := NewAsset("EURT", issuer, "50")
+	require.NoError(t, err)
+	fb.Assets = []Asset{usdc, eurt}
+
+	kp, txSuccess, err := fb.CreateAccount(context.Background(), "")
+	require.NoError(t, err)
+	tx, err := txnbuild.TransactionFromXDR(txSuccess.EnvelopeXdr)
+	require.NoError(t, err)
+	simpleTx, ok := tx.Transaction()
+	require.True(t, ok)
+
+	// The minion, the bot and the new account sign, the assets are paid
+	// from the balance of the bot.
+	assert.Len(t, simpleTx.Signatures(), 3)
+
+	botAddress := "GD25B4QI6KWVDWXDW25CIM7EKR6A6PBSWE2RCNSAC4NJQDQJXZJYMMKR"
+	assert.Equal(t, []txnbuild.Operation{
+		&txnbuild.BeginSponsoringFutureReserves{SponsoredID: kp.Address(), SourceAccount: botAddress},
+		&txnbuild.CreateAccount{Destination: kp.Address(), Amount: "10000.0000000", SourceAccount: botAddress},
+		&txnbuild.ChangeTrust{Line: txnbuild.CreditAsset{Code: "USDC", Issuer: issuer}, Limit: txnbuild.MaxTrustlineLimit, SourceAccount: kp.Address()},
+		&txnbuild.ChangeTrust{Line: txnbuild.CreditAsset{Code: "EURT", Issuer: issuer}, Limit: txnbuild.MaxTrustlineLimit, SourceAccount: kp.Address()},
+		&txnbuild.EndSponsoringFutureReserves{SourceAccount: kp.Address()},
+		&txnbuild.Payment{Destination: kp.Address(), Amount: "100.0000000", Asset: txnbuild.CreditAsset{Code: "USDC", Issuer: issuer}, SourceAccount: botAddress},
+		&txnbuild.Payment{Destination: kp.Address(), Amount: "50.0000000", Asset: txnbuild.CreditAsset{Code: "EURT", Issuer: issuer}, SourceAccount: botAddress},
+	}, simpleTx.Operations())
+}
+
+func TestNewAsset(t *testing.T) {
+	// The secret seed of the issuer is not accepted.
+	_, err := NewAsset("USDC", "SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK", "100")
+	assert.EqualError(t, err, `invalid issuer "SBIB72S6JMTGJRC6LMKLC5XMHZ2IOHZSZH4SASTN47LECEEJ7QEB6EYK" of asset USDC`)
+	_, err = NewAsset("TOOLONGASSETCODE", "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", "100")
+	assert.Error(t, err)
+	_, err = NewAsset("USDC", "GBOG4KF66M4AFRBUHOTJQJRO7BGGFCSGIICTI5BHXHKXCWV2C67QRN5H", "-1")
+	assert.EqualError(t, err, `invalid amount "-1" of asset USDC`)
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the Prometheus collectors of friendbot. A nil *Metrics is
+// valid and records nothing.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	poolSize          prometheus.Gauge
+	idleMinions       prometheus.Gauge
+	queueDepth        prometheus.Gauge
+	minionSubmissions prometheus.Counter
+	minionErrors      prometheus.Counter
+}
+
+// NewMetrics returns Metrics registered with a new registry.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		poolSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "friendbot", Subsystem: "minion_pool", Name: "size",
+			Help: "Number of minions in the pool, including minions being created.",
+		}),
+		idleMinions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "friendbot", Subsystem: "minion_pool", Name: "idle",
+			Help: "Number of minions waiting for a request.",
+		}),
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "friendbot", Subsystem: "minion_pool", Name: "queue_depth",
+			Help: "Number of requests waiting for a minion.",
+		}),
+		// The minions are not used as a label, as they are created and
+		// merged as the load changes.
+		minionSubmissions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "friendbot", Subsystem: "minion", Name: "submissions_total",
+			Help: "Number of transactions submitted by the minions.",
+		}),
+		minionErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "friendbot", Subsystem: "minion", Name: "errors_total",
+			Help: "Number of transactions submitted by the minions that failed other than because the account exists.",
+		}),
+	}
+
+	collectors := []prometheus.Collector{
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		prometheus.NewGoCollector(),
+		m.poolSize,
+		m.idleMinions,
+		m.queueDepth,
+		m.minionSubmissions,
+		m.minionErrors,
+	}
+	for _, c := range collectors {
+		err := m.Registry.Register(c)
+		if err != nil {
+			log.Printf("Error registering metric: %v", err)
+		}
+	}
+	return m
+}
+
+func (m *Metrics) setPool(size, idle, waiting int) {
+	if m == nil {
+		return
+	}
+	m.poolSize.Set(float64(size))
+	m.idleMinions.Set(float64(idle))
+	m.queueDepth.Set(float64(waiting))
+}
+
+func (m *Metrics) recordSubmission(failed bool) {
+	if m == nil {
+		return
+	}
+	m.minionSubmissions.Inc()
+	if failed {
+		m.minionErrors.Inc()
+	}
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
@@ -31,11 +32,25 @@ type Minion struct {
 
 	// Uninitialized.
 	forceRefreshSequence bool
+	idleSince            time.Time
 }
 
-// Run reads a payment destination address and an output channel. It attempts
-// to pay that address and submits the result to the channel.
-func (minion *Minion) Run(destAddress string, resultChan chan SubmitResult) {
+// Request describes an account for a minion to create and fund.
+type Request struct {
+	Destination string
+	// Amount is the starting balance of the account. The minion's
+	// StartingBalance is used if empty.
+	Amount string
+	// Assets the account is funded with, in addition to XLM. The trustlines
+	// of the assets are sponsored by friendbot, and signed for by Keypair,
+	// which must be set if Assets is not empty.
+	Assets  []Asset
+	Keypair *keypair.Full
+}
+
+// Run reads a payment request and an output channel. It attempts to create
+// and fund the requested account and submits the result to the channel.
+func (minion *Minion) Run(req Request, resultChan chan SubmitResult) {
 	err := minion.CheckSequenceRefresh(minion, minion.Horizon)
 	if err != nil {
 		resultChan <- SubmitResult{
@@ -44,7 +59,7 @@ func (minion *Minion) Run(destAddress string, resultChan chan SubmitResult) {
 		}
 		return
 	}
-	txStr, err := minion.makeTx(destAddress)
+	txStr, err := minion.makeTx(req)
 	if err != nil {
 		resultChan <- SubmitResult{
 			maybeTransactionSuccess: nil,
@@ -59,6 +74,39 @@ func (minion *Minion) Run(destAddress string, resultChan chan SubmitResult) {
 	}
 }
 
+// Merge merges the minion's account into the bot account, returning its
+// balance to the bot. The minion must not be used afterwards.
+func (minion *Minion) Merge() error {
+	err := minion.CheckSequenceRefresh(minion, minion.Horizon)
+	if err != nil {
+		return errors.Wrap(err, "checking minion seq")
+	}
+	tx, err := txnbuild.NewTransaction(
+		txnbuild.TransactionParams{
+			SourceAccount:        &minion.Account,
+			IncrementSequenceNum: true,
+			Operations: []txnbuild.Operation{
+				&txnbuild.AccountMerge{Destination: minion.BotAccount.GetAccountID()},
+			},
+			BaseFee:    minion.BaseFee,
+			Timebounds: txnbuild.NewInfiniteTimeout(),
+		},
+	)
+	if err != nil {
+		return errors.Wrap(err, "unable to build tx")
+	}
+	tx, err = tx.Sign(minion.Network, minion.Keypair)
+	if err != nil {
+		return errors.Wrap(err, "unable to sign tx")
+	}
+	txe, err := tx.Base64()
+	if err != nil {
+		return errors.Wrap(err, "unable to serialize")
+	}
+	_, err = minion.SubmitTransaction(minion, minion.Horizon, txe)
+	return errors.Wrap(err, "submitting merge tx")
+}
+
 // SubmitTransaction should be passed to the Minion.
 func SubmitTransaction(minion *Minion, hclient horizonclient.ClientInterface, tx string) (*hProtocol.Transaction, error) {
 	result, err := hclient.SubmitTransactionXDR(tx)
@@ -105,17 +153,53 @@ func (minion *Minion) checkHandleBadSequence(err *horizonclient.Error) {
 	minion.forceRefreshSequence = true
 }
 
-func (minion *Minion) makeTx(destAddress string) (string, error) {
+func (minion *Minion) makeTx(req Request) (string, error) {
+	startingBalance := req.Amount
+	if startingBalance == "" {
+		startingBalance = minion.StartingBalance
+	}
+	botAddress := minion.BotAccount.GetAccountID()
 	createAccountOp := txnbuild.CreateAccount{
-		Destination:   destAddress,
-		SourceAccount: minion.BotAccount.GetAccountID(),
-		Amount:        minion.StartingBalance,
+		Destination:   req.Destination,
+		SourceAccount: botAddress,
+		Amount:        startingBalance,
 	}
+	ops := []txnbuild.Operation{&createAccountOp}
+	signers := []keypair.Signer{minion.Keypair, minion.BotKeypair}
+
+	if len(req.Assets) > 0 {
+		if req.Keypair == nil || req.Keypair.Address() != req.Destination {
+			return "", errors.New("the keypair of the destination is required to add trustlines")
+		}
+		// The bot sponsors the reserves of the account and its trustlines, so
+		// that the XLM starting balance is fully spendable.
+		ops = []txnbuild.Operation{
+			&txnbuild.BeginSponsoringFutureReserves{SponsoredID: req.Destination, SourceAccount: botAddress},
+			&createAccountOp,
+		}
+		for _, asset := range req.Assets {
+			ops = append(ops, &txnbuild.ChangeTrust{Line: asset.creditAsset(), SourceAccount: req.Destination})
+		}
+		ops = append(ops, &txnbuild.EndSponsoringFutureReserves{SourceAccount: req.Destination})
+		signers = append(signers, req.Keypair)
+
+		for _, asset := range req.Assets {
+			ops = append(ops, &txnbuild.Payment{
+				Destination:   req.Destination,
+				Amount:        asset.Amount,
+				Asset:         asset.creditAsset(),
+				SourceAccount: botAddress,
+			})
+		}
+	}
+
+	// Building the tx increments the in-memory sequence number, since the tx
+	// will be submitted.
 	tx, err := txnbuild.NewTransaction(
 		txnbuild.TransactionParams{
-			SourceAccount:        minion.Account,
+			SourceAccount:        &minion.Account,
 			IncrementSequenceNum: true,
-			Operations:           []txnbuild.Operation{&createAccountOp},
+			Operations:           ops,
 			BaseFee:              minion.BaseFee,
 			Timebounds:           txnbuild.NewInfiniteTimeout(),
 		},
@@ -124,7 +208,7 @@ func (minion *Minion) makeTx(destAddress string) (string, error) {
 		return "", errors.Wrap(err, "unable to build tx")
 	}
 
-	tx, err = tx.Sign(minion.Network, minion.Keypair, minion.BotKeypair)
+	tx, err = tx.Sign(minion.Network, signers...)
 	if err != nil {
 		return "", errors.Wrap(err, "unable to sign tx")
 	}
@@ -133,11 +217,5 @@ func (minion *Minion) makeTx(destAddress string) (string, error) {
 	if err != nil {
 		return "", errors.Wrap(err, "unable to serialize")
 	}
-
-	// Increment the in-memory sequence number, since the tx will be submitted.
-	_, err = minion.Account.IncrementSequenceNumber()
-	if err != nil {
-		return "", errors.Wrap(err, "incrementing minion seq")
-	}
-	return txe, err
+	return txe, nil
 }
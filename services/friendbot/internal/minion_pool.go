@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// MinionPool lends minions to requests, so that each minion submits one
+// transaction at a time. When all minions are busy, minions are created in
+// batches of BatchSize, up to the pool's max minions. Minions idle for longer
+// than IdleTimeout are merged back into the bot account, down to MinMinions.
+type MinionPool struct {
+	// CreateMinions creates and funds up to n new minion accounts. It may
+	// return the minions created before an error.
+	CreateMinions func(n int) ([]*Minion, error)
+	MinMinions    int
+	BatchSize     int
+	IdleTimeout   time.Duration
+	Metrics       *Metrics
+
+	maxMinions int
+	idle       chan *Minion
+
+	mu      sync.Mutex
+	size    int
+	waiting int
+	// batch is the batch of minions being created, nil if none.
+	batch *minionBatch
+}
+
+// minionBatch is a batch of minions being created.
+type minionBatch struct {
+	// failed is closed if the creation of the batch failed, waking the
+	// requests waiting for a minion, after err is set.
+	failed chan struct{}
+	err    error
+}
+
+// NewMinionPool returns a pool of the given minions, growing up to
+// maxMinions. The pool's other options should be set before it is used.
+func NewMinionPool(minions []*Minion, maxMinions int) *MinionPool {
+	if maxMinions < len(minions) {
+		maxMinions = len(minions)
+	}
+	p := &MinionPool{
+		MinMinions: len(minions),
+		BatchSize:  1,
+		maxMinions: maxMinions,
+		idle:       make(chan *Minion, maxMinions),
+		size:       len(minions),
+	}
+	for _, minion := range minions {
+		p.release(minion)
+	}
+	return p
+}
+
+// Acquire returns an idle minion, waiting for one to be released or
+// created if there is none. The minion must be returned with Release.
+func (p *MinionPool) Acquire(ctx context.Context) (*Minion, error) {
+	select {
+	case minion := <-p.idle:
+		p.updateMetrics()
+		return minion, nil
+	default:
+	}
+
+	p.grow()
+
+	p.mu.Lock()
+	p.waiting++
+	// A nil channel never fails: without a batch being created, requests
+	// wait for a minion to be released.
+	var failed chan struct{}
+	batch := p.batch
+	if batch != nil {
+		failed = batch.failed
+	}
+	p.mu.Unlock()
+	p.updateMetrics()
+	defer func() {
+		p.mu.Lock()
+		p.waiting--
+		p.mu.Unlock()
+		p.updateMetrics()
+	}()
+
+	select {
+	case minion := <-p.idle:
+		return minion, nil
+	case <-failed:
+		// The batch may have created some minions before failing.
+		select {
+		case minion := <-p.idle:
+			return minion, nil
+		default:
+		}
+		return nil, errors.Wrap(batch.err, "creating minions")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Release returns a minion acquired with Acquire to the pool.
+func (p *MinionPool) Release(minion *Minion) {
+	p.release(minion)
+	p.updateMetrics()
+}
+
+func (p *MinionPool) release(minion *Minion) {
+	minion.idleSince = time.Now()
+	p.idle <- minion
+}
+
+// grow creates a batch of minions in the background, unless a batch is
+// already being created or the pool is full.
+func (p *MinionPool) grow() {
+	p.mu.Lock()
+	if p.batch != nil || p.CreateMinions == nil || p.size >= p.maxMinions {
+		p.mu.Unlock()
+		return
+	}
+	n := p.BatchSize
+	if n > p.maxMinions-p.size {
+		n = p.maxMinions - p.size
+	}
+	busy := p.size
+	batch := &minionBatch{failed: make(chan struct{})}
+	p.batch = batch
+	p.size += n
+	p.mu.Unlock()
+	p.updateMetrics()
+
+	go func() {
+		log.Printf("Creating %d minions, all %d minions are busy", n, busy)
+		minions, err := p.CreateMinions(n)
+		if err != nil {
+			log.Printf("Error creating minions: %v", err)
+		}
+
+		p.mu.Lock()
+		p.batch = nil
+		p.size -= n - len(minions)
+		p.mu.Unlock()
+
+		for _, minion := range minions {
+			p.release(minion)
+		}
+		if err != nil {
+			batch.err = err
+			close(batch.failed)
+		}
+		p.updateMetrics()
+	}()
+}
+
+// Run merges minions idle for longer than IdleTimeout back into the bot
+// account until ctx is done.
+func (p *MinionPool) Run(ctx context.Context) {
+	if p.IdleTimeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(p.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.shrink()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// shrink merges the minions idle for longer than IdleTimeout, while the pool
+// has more than MinMinions and no request is waiting for a minion.
+func (p *MinionPool) shrink() {
+	now := time.Now()
+	for i, n := 0, len(p.idle); i < n; i++ {
+		var minion *Minion
+		select {
+		case minion = <-p.idle:
+		default:
+			p.updateMetrics()
+			return
+		}
+
+		p.mu.Lock()
+		merge := p.size > p.MinMinions && p.waiting == 0 && now.Sub(minion.idleSince) >= p.IdleTimeout
+		if merge {
+			p.size--
+		}
+		p.mu.Unlock()
+
+		if !merge {
+			p.idle <- minion
+			continue
+		}
+
+		err := minion.Merge()
+		if err != nil {
+			log.Printf("Error merging minion %s: %v", minion.Keypair.Address(), err)
+			p.mu.Lock()
+			p.size++
+			p.mu.Unlock()
+			p.release(minion)
+			continue
+		}
+		log.Printf("Merged idle minion %s", minion.Keypair.Address())
+	}
+	p.updateMetrics()
+}
+
+func (p *MinionPool) updateMetrics() {
+	p.mu.Lock()
+	size, waiting := p.size, p.waiting
+	p.mu.Unlock()
+	p.Metrics.setPool(size, len(p.idle), waiting)
+}
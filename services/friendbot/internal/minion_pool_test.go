@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMinion(submitted chan<- string) *Minion {
+	kp := keypair.MustRandom()
+	return &Minion{
+		Account:         Account{AccountID: kp.Address(), Sequence: 1},
+		Keypair:         kp,
+		BotAccount:      &Account{AccountID: "GD25B4QI6KWVDWXDW25CIM7EKR6A6PBSWE2RCNSAC4NJQDQJXZJYMMKR"},
+		BotKeypair:      keypair.MustParseFull("SCWNLYELENPBXN46FHYXETT5LJCYBZD5VUQQVW4KZPHFO2YTQJUWT4D5"),
+		Network:         "Test SDF Network ; September 2015",
+		StartingBalance: "10000.00",
+		SubmitTransaction: func(minion *Minion, hclient horizonclient.ClientInterface, tx string) (*hProtocol.Transaction, error) {
+			submitted <- tx
+			return &hProtocol.Transaction{EnvelopeXdr: tx, Successful: true}, nil
+		},
+		CheckSequenceRefresh: CheckSequenceRefresh,
+		BaseFee:              txnbuild.MinBaseFee,
+	}
+}
+
+func TestMinionPool_grow(t *testing.T) {
+	submitted := make(chan string, 10)
+	initial := newTestMinion(submitted)
+	p := NewMinionPool([]*Minion{initial}, 3)
+	p.BatchSize = 2
+	created := make(chan int, 10)
+	p.CreateMinions = func(n int) ([]*Minion, error) {
+		created <- n
+		var minions []*Minion
+		for i := 0; i < n; i++ {
+			minions = append(minions, newTestMinion(submitted))
+		}
+		return minions, nil
+	}
+	ctx := context.Background()
+
+	m1, err := p.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, initial, m1)
+
+	// All minions are busy, so a batch is created.
+	m2, err := p.Acquire(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, <-created)
+	m3, err := p.Acquire(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, m2, m3)
+
+	// The pool is full, so requests wait for a minion to be released.
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = p.Acquire(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Len(t, created, 0)
+
+	p.Release(m2)
+	m4, err := p.Acquire(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, m2, m4)
+	assert.Equal(t, 3, p.size)
+}
+
+func TestMinionPool_growFailed(t *testing.T) {
+	submitted := make(chan string, 10)
+	p := NewMinionPool([]*Minion{newTestMinion(submitted)}, 3)
+	p.BatchSize = 2
+	proceed := make(chan struct{})
+	p.CreateMinions = func(n int) ([]*Minion, error) {
+		<-proceed
+		return []*Minion{newTestMinion(submitted)}, errors.New("horizon is down")
+	}
+	ctx := context.Background()
+
+	_, err := p.Acquire(ctx)
+	require.NoError(t, err)
+
+	// The batch creates a single minion before failing, the first waiting
+	// request gets it and the second is woken up with the error.
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := p.Acquire(ctx)
+			results <- err
+		}()
+	}
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.waiting == 2
+	}, time.Second, time.Millisecond)
+	close(proceed)
+	errs := []error{<-results, <-results}
+	assert.ElementsMatch(t, []string{"<nil>", "creating minions: horizon is down"},
+		[]string{fmt.Sprint(errs[0]), fmt.Sprint(errs[1])})
+	assert.Equal(t, 2, p.size)
+}
+
+func TestMinionPool_shrink(t *testing.T) {
+	submitted := make(chan string, 10)
+	minions := []*Minion{
+		newTestMinion(submitted),
+		newTestMinion(submitted),
+		newTestMinion(submitted),
+	}
+	p := NewMinionPool(minions, 3)
+	p.MinMinions = 1
+	p.IdleTimeout = time.Minute
+
+	// Minions idle for less than the timeout are kept.
+	p.shrink()
+	assert.Equal(t, 3, p.size)
+	assert.Len(t, submitted, 0)
+
+	for i := 0; i < 3; i++ {
+		m, err := p.Acquire(context.Background())
+		require.NoError(t, err)
+		m.idleSince = time.Now().Add(-2 * time.Minute)
+		p.idle <- m
+	}
+
+	// Idle minions are merged into the bot account, down to MinMinions.
+	p.shrink()
+	assert.Equal(t, 1, p.size)
+	assert.Len(t, p.idle, 1)
+	require.Len(t, submitted, 2)
+
+	tx, err := txnbuild.TransactionFromXDR(<-submitted)
+	require.NoError(t, err)
+	simpleTx, ok := tx.Transaction()
+	require.True(t, ok)
+	assert.Equal(t, []txnbuild.Operation{
+		&txnbuild.AccountMerge{Destination: "GD25B4QI6KWVDWXDW25CIM7EKR6A6PBSWE2RCNSAC4NJQDQJXZJYMMKR"},
+	}, simpleTx.Operations())
+	assert.Equal(t, int64(2), simpleTx.SourceAccount().Sequence)
+}
+
+func TestMinionPool_sequence(t *testing.T) {
+	submitted := make(chan string, 10)
+	minion := newTestMinion(submitted)
+	fb := &Bot{Minions: NewMinionPool([]*Minion{minion}, 1)}
+
+	// Each transaction of a minion uses the next sequence number, without
+	// refreshing it.
+	for _, seq := range []int64{2, 3, 4} {
+		_, err := fb.Pay(context.Background(), "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z")
+		require.NoError(t, err)
+		tx, err := txnbuild.TransactionFromXDR(<-submitted)
+		require.NoError(t, err)
+		simpleTx, ok := tx.Transaction()
+		require.True(t, ok)
+		assert.Equal(t, seq, simpleTx.SourceAccount().Sequence)
+	}
+}
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"sync"
 	"testing"
 
@@ -45,7 +46,7 @@ func TestMinion_NoChannelErrors(t *testing.T) {
 			Sequence:  1,
 		},
 		Keypair:              minionKeypair.(*keypair.Full),
-		BotAccount:           botAccount,
+		BotAccount:           &botAccount,
 		BotKeypair:           botKeypair.(*keypair.Full),
 		Network:              "Test SDF Network ; September 2015",
 		StartingBalance:      "10000.00",
@@ -53,7 +54,7 @@ func TestMinion_NoChannelErrors(t *testing.T) {
 		CheckSequenceRefresh: mockCheckSequenceRefresh,
 		BaseFee:              txnbuild.MinBaseFee,
 	}
-	fb := &Bot{Minions: []Minion{minion}}
+	fb := &Bot{Minions: NewMinionPool([]*Minion{&minion}, 1)}
 
 	recipientAddress := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
 
@@ -65,7 +66,7 @@ func TestMinion_NoChannelErrors(t *testing.T) {
 
 	for i := 0; i < numTests; i++ {
 		go func() {
-			fb.Pay(recipientAddress)
+			fb.Pay(context.Background(), recipientAddress)
 			wg.Done()
 		}()
 	}
@@ -110,7 +111,7 @@ func TestMinion_CorrectNumberOfTxSubmissions(t *testing.T) {
 			Sequence:  1,
 		},
 		Keypair:              minionKeypair.(*keypair.Full),
-		BotAccount:           botAccount,
+		BotAccount:           &botAccount,
 		BotKeypair:           botKeypair.(*keypair.Full),
 		Network:              "Test SDF Network ; September 2015",
 		StartingBalance:      "10000.00",
@@ -118,7 +119,7 @@ func TestMinion_CorrectNumberOfTxSubmissions(t *testing.T) {
 		CheckSequenceRefresh: mockCheckSequenceRefresh,
 		BaseFee:              txnbuild.MinBaseFee,
 	}
-	fb := &Bot{Minions: []Minion{minion}}
+	fb := &Bot{Minions: NewMinionPool([]*Minion{&minion}, 1)}
 
 	recipientAddress := "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
 
@@ -128,7 +129,7 @@ func TestMinion_CorrectNumberOfTxSubmissions(t *testing.T) {
 
 	for i := 0; i < numTests; i++ {
 		go func() {
-			fb.Pay(recipientAddress)
+			fb.Pay(context.Background(), recipientAddress)
 			wg.Done()
 		}()
 	}
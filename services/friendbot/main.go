@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	stdhttp "net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/services/friendbot/internal"
 	"github.com/stellar/go/support/app"
@@ -20,15 +23,28 @@ import (
 // Config represents the configuration of a friendbot server
 type Config struct {
 	Port                   int         `toml:"port" valid:"required"`
-	FriendbotSecret        string      `toml:"friendbot_secret" valid:"required"`
+	FriendbotSecret        string      `toml:"friendbot_secret" valid:"required" secret:"true"`
 	NetworkPassphrase      string      `toml:"network_passphrase" valid:"required"`
 	HorizonURL             string      `toml:"horizon_url" valid:"required"`
 	StartingBalance        string      `toml:"starting_balance" valid:"required"`
 	TLS                    *config.TLS `valid:"optional"`
 	NumMinions             int         `toml:"num_minions" valid:"optional"`
+	MaxMinions             int         `toml:"max_minions" valid:"optional"`
+	MinionIdleTimeout      int         `toml:"minion_idle_timeout" valid:"optional"`
 	BaseFee                int64       `toml:"base_fee" valid:"optional"`
 	MinionBatchSize        int         `toml:"minion_batch_size" valid:"optional"`
 	SubmitTxRetriesAllowed int         `toml:"submit_tx_retries_allowed" valid:"optional"`
+	MaxStartingBalance     string      `toml:"max_starting_balance" valid:"optional"`
+	AdminPort              int         `toml:"admin_port" valid:"optional"`
+	Assets                 []Asset     `toml:"assets" valid:"optional"`
+}
+
+// Asset represents the configuration of a non-native asset that new accounts
+// created with a keypair are funded with
+type Asset struct {
+	Code   string `toml:"code" valid:"required"`
+	Issuer string `toml:"issuer" valid:"required"`
+	Amount string `toml:"amount" valid:"required"`
 }
 
 func main() {
@@ -62,12 +78,34 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	var assets []internal.Asset
+	for _, a := range cfg.Assets {
+		asset, assetErr := internal.NewAsset(a.Code, a.Issuer, a.Amount)
+		if assetErr != nil {
+			log.Error("config file: ", assetErr)
+			os.Exit(1)
+		}
+		assets = append(assets, asset)
+	}
+
+	minionIdleTimeout := 10 * time.Minute
+	if cfg.MinionIdleTimeout != 0 {
+		minionIdleTimeout = time.Duration(cfg.MinionIdleTimeout) * time.Second
+	}
+	metrics := internal.NewMetrics()
+
 	fb, err := initFriendbot(cfg.FriendbotSecret, cfg.NetworkPassphrase, cfg.HorizonURL, cfg.StartingBalance,
-		cfg.NumMinions, cfg.BaseFee, cfg.MinionBatchSize, cfg.SubmitTxRetriesAllowed)
+		cfg.MaxStartingBalance, assets, cfg.NumMinions, cfg.MaxMinions, minionIdleTimeout, cfg.BaseFee,
+		cfg.MinionBatchSize, cfg.SubmitTxRetriesAllowed, metrics)
 	if err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
+	go fb.Minions.Run(context.Background())
+
+	if cfg.AdminPort != 0 {
+		go serveAdmin(cfg.AdminPort, metrics)
+	}
 	router := initRouter(fb)
 	registerProblems()
 
@@ -90,6 +128,7 @@ func initRouter(fb *internal.Bot) *chi.Mux {
 	handler := &internal.FriendbotHandler{Friendbot: fb}
 	mux.Get("/", handler.Handle)
 	mux.Post("/", handler.Handle)
+	mux.Post("/keypair", handler.HandleKeypair)
 	mux.NotFound(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 		problem.Render(r.Context(), w, problem.NotFound)
 	}))
@@ -97,6 +136,20 @@ func initRouter(fb *internal.Bot) *chi.Mux {
 	return mux
 }
 
+func serveAdmin(port int, metrics *internal.Metrics) {
+	mux := http.NewAPIMux(log.DefaultLogger)
+	mux.Handle("/metrics", promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+
+	addr := fmt.Sprintf("0.0.0.0:%d", port)
+	http.Run(http.Config{
+		ListenAddr: addr,
+		Handler:    mux,
+		OnStarting: func() {
+			log.Infof("starting admin server, listening on %s", addr)
+		},
+	})
+}
+
 func registerProblems() {
 	problem.RegisterError(sql.ErrNoRows, problem.NotFound)
 }
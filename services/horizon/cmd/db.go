@@ -257,6 +257,7 @@ func runDBReingestRange(from, to uint32, reingestForce bool, parallelWorkers uin
 		CaptiveCoreStoragePath:      config.CaptiveCoreStoragePath,
 		StellarCoreCursor:           config.CursorName,
 		StellarCoreURL:              config.StellarCoreURL,
+		MarketFilter:                config.IngestMarketFilter,
 	}
 
 	if !ingestConfig.EnableCaptiveCore {
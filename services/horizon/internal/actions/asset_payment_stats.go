@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"fmt"
+	"net/http"
+	gTime "time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/support/time"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	dayMillis = int64(24 * gTime.Hour / gTime.Millisecond)
+	// defaultAssetPaymentStatsDays is the number of days returned when no
+	// start time is given.
+	defaultAssetPaymentStatsDays = 30
+	// maxAssetPaymentStatsDays is the largest number of days returned by a
+	// single request.
+	maxAssetPaymentStatsDays = 366
+)
+
+// AssetPaymentStatsQuery query struct for the assets/{asset}/stats end-point
+type AssetPaymentStatsQuery struct {
+	Asset           string      `schema:"asset" valid:"asset"`
+	StartTimeFilter time.Millis `schema:"start_time" valid:"-"`
+	EndTimeFilter   time.Millis `schema:"end_time" valid:"-"`
+}
+
+// Validate runs validations on AssetPaymentStatsQuery
+func (q AssetPaymentStatsQuery) Validate() error {
+	if !q.StartTimeFilter.IsNil() && !q.EndTimeFilter.IsNil() &&
+		q.StartTimeFilter.ToInt64() >= q.EndTimeFilter.ToInt64() {
+		return problem.MakeInvalidFieldProblem(
+			"end_time",
+			errors.New("illegal end time. end time must be greater than the provided start time"),
+		)
+	}
+
+	start, end := q.days(time.Now())
+	if end.ToInt64()-start.ToInt64() > maxAssetPaymentStatsDays*dayMillis {
+		return problem.MakeInvalidFieldProblem(
+			"start_time",
+			fmt.Errorf("illegal time range. at most %d days can be requested", maxAssetPaymentStatsDays),
+		)
+	}
+
+	return nil
+}
+
+// days returns the start of the first day (inclusive) and the start of the
+// last day (exclusive) overlapping the requested time range. The range ends
+// now by default and starts defaultAssetPaymentStatsDays before its end.
+func (q AssetPaymentStatsQuery) days(now time.Millis) (time.Millis, time.Millis) {
+	end := q.EndTimeFilter
+	if end.IsNil() {
+		end = now
+	}
+	end = end.RoundUp(dayMillis)
+
+	start := q.StartTimeFilter.RoundDown(dayMillis)
+	if q.StartTimeFilter.IsNil() {
+		start = time.MillisFromInt64(end.ToInt64() - defaultAssetPaymentStatsDays*dayMillis)
+	}
+	return start, end
+}
+
+// GetAssetPaymentStatsHandler is the action handler for the
+// /assets/{asset}/stats endpoint. It returns, for every day (UTC) in which
+// the asset was delivered, the number of successful payments, strict receive
+// and strict send path payments delivering it and the amount delivered.
+type GetAssetPaymentStatsHandler struct{}
+
+// GetResource returns the daily payment stats of an asset.
+func (handler GetAssetPaymentStatsHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	qp := AssetPaymentStatsQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	assets, err := xdr.BuildAssets(qp.Asset)
+	if err != nil {
+		return nil, problem.MakeInvalidFieldProblem("asset", err)
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := qp.days(time.Now())
+	stats, err := historyQ.GetAssetPaymentStats(ctx, assets[0], start.ToTime(), end.ToTime())
+	if err != nil {
+		return nil, err
+	}
+
+	page := hal.Page{}
+	page.Init()
+	for _, stat := range stats {
+		record, err := newAssetPaymentStat(stat)
+		if err != nil {
+			return nil, err
+		}
+		page.Add(record)
+	}
+	page.Links.Self = hal.NewLink(FullURL(ctx).String())
+	return page, nil
+}
+
+func newAssetPaymentStat(stat history.AssetPaymentStat) (horizon.AssetPaymentStat, error) {
+	volume, err := amount.IntStringToAmount(stat.Volume)
+	if err != nil {
+		return horizon.AssetPaymentStat{}, errors.Wrap(err, "invalid volume")
+	}
+
+	return horizon.AssetPaymentStat{
+		Timestamp:   stat.Day.UnixNano() / int64(gTime.Millisecond),
+		AssetType:   xdr.AssetTypeToString[stat.AssetType],
+		AssetCode:   stat.AssetCode,
+		AssetIssuer: stat.AssetIssuer,
+		Payments:    stat.Payments,
+		Volume:      volume,
+	}, nil
+}
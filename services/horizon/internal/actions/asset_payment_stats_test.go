@@ -0,0 +1,66 @@
+package actions
+
+import (
+	"testing"
+	gTime "time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/time"
+	"github.com/stellar/go/xdr"
+)
+
+func TestAssetPaymentStatsQueryValidate(t *testing.T) {
+	q := AssetPaymentStatsQuery{Asset: "native"}
+	assert.NoError(t, q.Validate())
+
+	q.StartTimeFilter = 2000
+	q.EndTimeFilter = 1000
+	assert.Error(t, q.Validate())
+
+	q.StartTimeFilter = 1
+	q.EndTimeFilter = time.MillisFromInt64(maxAssetPaymentStatsDays * dayMillis)
+	assert.NoError(t, q.Validate())
+
+	q.EndTimeFilter++
+	assert.Error(t, q.Validate())
+}
+
+func TestAssetPaymentStatsQueryDays(t *testing.T) {
+	day := time.MillisFromInt64(gTime.Date(2021, 6, 15, 0, 0, 0, 0, gTime.UTC).Unix() * 1000)
+	now := day + time.MillisFromInt64(10*gTime.Hour.Milliseconds())
+
+	start, end := AssetPaymentStatsQuery{}.days(now)
+	assert.Equal(t, day+time.MillisFromInt64(dayMillis), end)
+	assert.Equal(t, end-time.MillisFromInt64(defaultAssetPaymentStatsDays*dayMillis), start)
+
+	start, end = AssetPaymentStatsQuery{StartTimeFilter: day + 1, EndTimeFilter: day + 2}.days(now)
+	assert.Equal(t, day, start)
+	assert.Equal(t, day+time.MillisFromInt64(dayMillis), end)
+
+	start, end = AssetPaymentStatsQuery{StartTimeFilter: day - 1, EndTimeFilter: day}.days(now)
+	assert.Equal(t, day-time.MillisFromInt64(dayMillis), start)
+	assert.Equal(t, day, end)
+}
+
+func TestNewAssetPaymentStat(t *testing.T) {
+	day := gTime.Date(2021, 6, 15, 0, 0, 0, 0, gTime.UTC)
+	record, err := newAssetPaymentStat(history.AssetPaymentStat{
+		AssetType:   xdr.AssetTypeAssetTypeCreditAlphanum12,
+		AssetCode:   "EURT",
+		AssetIssuer: "GD4FLXKATOO2Z4DME5BHLJDYF6UHUJS624CGA2FWTEVGUM4UVRF5YBGS",
+		Day:         day,
+		Payments:    3,
+		Volume:      "92233720368547758070",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, day.Unix()*1000, record.Timestamp)
+	assert.Equal(t, "credit_alphanum12", record.AssetType)
+	assert.Equal(t, "EURT", record.AssetCode)
+	assert.Equal(t, int64(3), record.Payments)
+	assert.Equal(t, "9223372036854.7758070", record.Volume)
+
+	_, err = newAssetPaymentStat(history.AssetPaymentStat{Volume: "1.5"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,482 @@
+package actions
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	gTime "time"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/support/time"
+	"github.com/stellar/go/xdr"
+)
+
+// BalanceHistoryQuery query struct for the accounts/{account_id}/balance_history end-point
+type BalanceHistoryQuery struct {
+	AccountID        string      `schema:"account_id" valid:"accountID"`
+	AssetFilter      string      `schema:"asset" valid:"asset,optional"`
+	StartTimeFilter  time.Millis `schema:"start_time" valid:"-"`
+	EndTimeFilter    time.Millis `schema:"end_time" valid:"-"`
+	ResolutionFilter uint64      `schema:"resolution" valid:"-"`
+}
+
+// Validate runs validations on BalanceHistoryQuery
+func (q BalanceHistoryQuery) Validate() error {
+	resolutionDuration := gTime.Duration(q.ResolutionFilter) * gTime.Millisecond
+	if _, ok := history.AllowedResolutions[resolutionDuration]; !ok {
+		return problem.MakeInvalidFieldProblem(
+			"resolution",
+			errors.New("illegal or missing resolution. "+
+				"allowed resolutions are: 1 minute (60000), 5 minutes (300000), 15 minutes (900000), 1 hour (3600000), "+
+				"1 day (86400000) and 1 week (604800000)"),
+		)
+	}
+
+	if !q.StartTimeFilter.IsNil() && !q.EndTimeFilter.IsNil() &&
+		q.StartTimeFilter.ToInt64() >= q.EndTimeFilter.ToInt64() {
+		return problem.MakeInvalidFieldProblem(
+			"end_time",
+			errors.New("illegal end time. end time must be greater than the provided start time"),
+		)
+	}
+
+	return nil
+}
+
+// asset returns the canonical form of the requested asset, defaulting to
+// native.
+func (q BalanceHistoryQuery) asset() string {
+	if q.AssetFilter == "" {
+		return "native"
+	}
+	return q.AssetFilter
+}
+
+// balanceHistoryBatchSize is the number of effects and fee charges loaded at
+// once while walking back the history of an account.
+const balanceHistoryBatchSize = 200
+
+// GetBalanceHistoryHandler is the action handler for the
+// /accounts/{account_id}/balance_history endpoint. Balances are reconstructed
+// by walking the account's effects and fee charges back in time from its
+// current balance, so only buckets in which the balance changed are returned.
+// Only the ledgers still in the history database can be walked: time ranges
+// reaching into reaped ledgers are rejected and, without a start time, the
+// history starts with the first bucket after the oldest ledger.
+type GetBalanceHistoryHandler struct {
+	LedgerState *ledger.State
+}
+
+// GetResourcePage returns a page of the balance history of an account.
+func (handler GetBalanceHistoryHandler) GetResourcePage(w HeaderWriter, r *http.Request) ([]hal.Pageable, error) {
+	ctx := r.Context()
+	qp := BalanceHistoryQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	pq, err := GetPageQuery(handler.LedgerState, r, DisableCursorValidation)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := pq.CursorInt64()
+	if err != nil {
+		return nil, problem.MakeInvalidFieldProblem(
+			"cursor",
+			errors.New("The cursor should be the timestamp of a balance history record"),
+		)
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	status := handler.LedgerState.CurrentStatus()
+	var elder history.Ledger
+	if err = historyQ.LedgerBySequence(ctx, &elder, status.HistoryElder); historyQ.NoRows(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	window := balanceHistoryWindow{
+		resolution: int64(qp.ResolutionFilter),
+		upper:      math.MaxInt64,
+		order:      pq.Order,
+		limit:      int(pq.Limit),
+	}
+	elderMillis := time.MillisFromSeconds(elder.ClosedAt.Unix()).ToInt64()
+	if qp.StartTimeFilter.IsNil() {
+		window.lower = time.MillisFromInt64(elderMillis).RoundUp(window.resolution).ToInt64()
+	} else {
+		window.lower = qp.StartTimeFilter.RoundDown(window.resolution).ToInt64()
+		if window.lower < elderMillis {
+			return nil, hProblem.MakeBeforeHistoryProblem(status.HistoryElder, status.HistoryLatest)
+		}
+	}
+	if !qp.EndTimeFilter.IsNil() {
+		window.upper = qp.EndTimeFilter.ToInt64()
+	}
+	if pq.Order == db2.OrderAscending && cursor >= window.lower {
+		window.lower = cursor + 1
+	} else if pq.Order == db2.OrderDescending && cursor < window.upper {
+		window.upper = cursor
+	}
+
+	var account history.Account
+	if err = historyQ.AccountByAddress(ctx, &account, qp.AccountID); historyQ.NoRows(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	balance, err := currentBalance(ctx, historyQ, qp.AccountID, qp.asset())
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &balanceChangeWalker{
+		ctx:                 ctx,
+		source:              historyQ,
+		accountID:           account.ID,
+		address:             qp.AccountID,
+		asset:               qp.asset(),
+		minLedger:           status.HistoryElder,
+		beforeOperationID:   math.MaxInt64,
+		beforeOrder:         math.MaxInt32,
+		beforeTransactionID: math.MaxInt64,
+	}
+	records, err := balanceHistory(changes, balance, window)
+	if err != nil {
+		return nil, err
+	}
+
+	var page []hal.Pageable
+	for _, record := range records {
+		page = append(page, record)
+	}
+	return page, nil
+}
+
+// currentBalance returns the balance of `asset` held by `address` in the
+// ledger state, or 0 if the account or the trust line does not exist anymore.
+func currentBalance(ctx context.Context, historyQ *history.Q, address, asset string) (int64, error) {
+	if asset == "native" {
+		account, err := historyQ.GetAccountByID(ctx, address)
+		if historyQ.NoRows(err) {
+			return 0, nil
+		} else if err != nil {
+			return 0, err
+		}
+		return account.Balance, nil
+	}
+
+	trustLines, err := historyQ.GetSortedTrustLinesByAccountID(ctx, address)
+	if err != nil {
+		return 0, err
+	}
+	for _, trustLine := range trustLines {
+		if trustLine.AssetCode+":"+trustLine.AssetIssuer == asset {
+			return trustLine.Balance, nil
+		}
+	}
+	return 0, nil
+}
+
+// balanceChange is a change, in stroops, to the balance of an asset held by
+// an account.
+type balanceChange struct {
+	at     gTime.Time
+	amount int64
+}
+
+// balanceHistorySource loads the effects and fee charges of an account in
+// reverse chronological order, it is implemented by *history.Q.
+type balanceHistorySource interface {
+	BalanceEffectsForAccount(
+		ctx context.Context,
+		accountID int64,
+		beforeOperationID int64,
+		beforeOrder int32,
+		minLedger int32,
+		limit uint64,
+	) ([]history.BalanceEffect, error)
+	FeeChargesForAccount(
+		ctx context.Context,
+		accountID int64,
+		address string,
+		beforeTransactionID int64,
+		minLedger int32,
+		limit uint64,
+	) ([]history.FeeCharge, error)
+}
+
+// balanceChangeWalker walks the changes to the balance of an asset held by
+// an account back in time, loading its effects and fee charges in batches.
+type balanceChangeWalker struct {
+	ctx       context.Context
+	source    balanceHistorySource
+	accountID int64
+	address   string
+	asset     string
+	minLedger int32
+
+	effects             []history.BalanceEffect
+	fees                []history.FeeCharge
+	effectsDone         bool
+	feesDone            bool
+	beforeOperationID   int64
+	beforeOrder         int32
+	beforeTransactionID int64
+}
+
+func (w *balanceChangeWalker) load() error {
+	if len(w.effects) == 0 && !w.effectsDone {
+		effects, err := w.source.BalanceEffectsForAccount(
+			w.ctx, w.accountID, w.beforeOperationID, w.beforeOrder, w.minLedger, balanceHistoryBatchSize,
+		)
+		if err != nil {
+			return err
+		}
+		w.effects = effects
+		w.effectsDone = len(effects) < balanceHistoryBatchSize
+		if len(effects) > 0 {
+			last := effects[len(effects)-1]
+			w.beforeOperationID, w.beforeOrder = last.HistoryOperationID, last.Order
+		}
+	}
+
+	// Only lumens pay for fees.
+	if w.asset != "native" {
+		w.feesDone = true
+	}
+	if len(w.fees) == 0 && !w.feesDone {
+		fees, err := w.source.FeeChargesForAccount(
+			w.ctx, w.accountID, w.address, w.beforeTransactionID, w.minLedger, balanceHistoryBatchSize,
+		)
+		if err != nil {
+			return err
+		}
+		w.fees = fees
+		w.feesDone = len(fees) < balanceHistoryBatchSize
+		if len(fees) > 0 {
+			w.beforeTransactionID = fees[len(fees)-1].TransactionID
+		}
+	}
+	return nil
+}
+
+// next returns the latest change preceding the changes already returned,
+// ok is false once all the changes since the ledger minLedger were returned.
+func (w *balanceChangeWalker) next() (change balanceChange, ok bool, err error) {
+	for {
+		if err = w.load(); err != nil {
+			return balanceChange{}, false, err
+		}
+
+		switch {
+		case len(w.effects) == 0 && len(w.fees) == 0:
+			return balanceChange{}, false, nil
+		// Fees are charged before any operation of the ledger is applied, so
+		// going back in time the effects of a ledger precede its fees.
+		case len(w.fees) == 0 ||
+			(len(w.effects) > 0 && w.effects[0].HistoryOperationID>>32 >= w.fees[0].TransactionID>>32):
+			effect := w.effects[0]
+			w.effects = w.effects[1:]
+			delta, err := effectBalanceChange(w.address, w.asset, effect)
+			if err != nil {
+				return balanceChange{}, false, err
+			}
+			if delta != 0 {
+				return balanceChange{effect.LedgerCloseTime, delta}, true, nil
+			}
+		default:
+			fee := w.fees[0]
+			w.fees = w.fees[1:]
+			return balanceChange{fee.LedgerCloseTime, -fee.FeeCharged}, true, nil
+		}
+	}
+}
+
+// effectBalanceChange returns the change to the balance of `asset` held by
+// `account` made by `effect`.
+func effectBalanceChange(account, asset string, effect history.BalanceEffect) (int64, error) {
+	var details balanceEffectDetails
+	if err := effect.UnmarshalDetails(&details); err != nil {
+		return 0, err
+	}
+
+	switch effect.Type {
+	case history.EffectAccountCreated:
+		if asset != "native" {
+			return 0, nil
+		}
+		delta, err := amount.ParseInt64(details.StartingBalance)
+		if err != nil {
+			return 0, errors.Wrap(err, "invalid starting_balance")
+		}
+		return delta, nil
+	case history.EffectAccountCredited, history.EffectAccountDebited:
+		if canonicalAsset(details.AssetType, details.AssetCode, details.AssetIssuer) != asset {
+			return 0, nil
+		}
+		delta, err := amount.ParseInt64(details.Amount)
+		if err != nil {
+			return 0, errors.Wrap(err, "invalid amount")
+		}
+		if effect.Type == history.EffectAccountDebited {
+			delta = -delta
+		}
+		return delta, nil
+	case history.EffectTrade:
+		// The source account of a path payment is already credited and
+		// debited for the whole payment, the trades crossed along the path
+		// would count the same amounts twice.
+		if isPathPayment(effect.OperationType) && effect.OperationSourceAccount == account {
+			return 0, nil
+		}
+		var delta int64
+		if canonicalAsset(details.BoughtAssetType, details.BoughtAssetCode, details.BoughtAssetIssuer) == asset {
+			bought, err := amount.ParseInt64(details.BoughtAmount)
+			if err != nil {
+				return 0, errors.Wrap(err, "invalid bought_amount")
+			}
+			delta += bought
+		}
+		if canonicalAsset(details.SoldAssetType, details.SoldAssetCode, details.SoldAssetIssuer) == asset {
+			sold, err := amount.ParseInt64(details.SoldAmount)
+			if err != nil {
+				return 0, errors.Wrap(err, "invalid sold_amount")
+			}
+			delta -= sold
+		}
+		return delta, nil
+	}
+	return 0, nil
+}
+
+// balanceHistoryWindow selects the buckets of a page of balance history.
+// Timestamps are in milliseconds.
+type balanceHistoryWindow struct {
+	resolution int64
+	// lower is the start of the first bucket (inclusive), upper the bound of
+	// the starts of the buckets (exclusive).
+	lower int64
+	upper int64
+	order string
+	limit int
+}
+
+// balanceHistory walks `changes` back in time from `balance`, the current
+// balance, and returns the closing balance of the buckets of `window` in
+// which the balance changed, along with the amounts credited and debited in
+// these buckets.
+func balanceHistory(changes *balanceChangeWalker, balance int64, window balanceHistoryWindow) ([]horizon.BalanceHistory, error) {
+	var assetType, assetCode, assetIssuer string
+	if changes.asset == "native" {
+		assetType = "native"
+	} else {
+		parts := strings.SplitN(changes.asset, ":", 2)
+		assetCode, assetIssuer = parts[0], parts[1]
+		assetType = "credit_alphanum4"
+		if len(assetCode) > 4 {
+			assetType = "credit_alphanum12"
+		}
+	}
+
+	// records are collected from the latest bucket to the oldest.
+	records := []horizon.BalanceHistory{}
+	var credited, debited, closing int64
+	var current int64 = -1
+	flush := func() {
+		if current >= window.lower && current < window.upper {
+			records = append(records, horizon.BalanceHistory{
+				Timestamp:   current,
+				AssetType:   assetType,
+				AssetCode:   assetCode,
+				AssetIssuer: assetIssuer,
+				Balance:     amount.StringFromInt64(closing),
+				Credited:    amount.StringFromInt64(credited),
+				Debited:     amount.StringFromInt64(debited),
+			})
+		}
+		current = -1
+	}
+
+	for {
+		change, ok, err := changes.next()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		ms := time.MillisFromSeconds(change.at.Unix()).ToInt64()
+		bucket := ms - ms%window.resolution
+		if bucket < window.lower {
+			break
+		}
+		if bucket != current {
+			flush()
+			if window.order == db2.OrderDescending && len(records) >= window.limit {
+				break
+			}
+			current = bucket
+			closing = balance
+			credited, debited = 0, 0
+		}
+
+		balance -= change.amount
+		if change.amount > 0 {
+			credited += change.amount
+		} else {
+			debited -= change.amount
+		}
+	}
+	flush()
+
+	if window.order == db2.OrderAscending {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+	if len(records) > window.limit {
+		records = records[:window.limit]
+	}
+	return records, nil
+}
+
+// balanceEffectDetails holds the details of all the effect types loaded by
+// history.Q.BalanceEffectsForAccount.
+type balanceEffectDetails struct {
+	StartingBalance string `json:"starting_balance"`
+	Amount          string `json:"amount"`
+	AssetType       string `json:"asset_type"`
+	AssetCode       string `json:"asset_code"`
+	AssetIssuer     string `json:"asset_issuer"`
+	history.TradeEffectDetails
+}
+
+func canonicalAsset(assetType, code, issuer string) string {
+	if assetType == "native" {
+		return "native"
+	}
+	return code + ":" + issuer
+}
+
+func isPathPayment(opType xdr.OperationType) bool {
+	return opType == xdr.OperationTypePathPaymentStrictReceive ||
+		opType == xdr.OperationTypePathPaymentStrictSend
+}
@@ -0,0 +1,213 @@
+package actions
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/toid"
+	"github.com/stellar/go/xdr"
+)
+
+func TestBalanceHistoryQueryValidate(t *testing.T) {
+	q := BalanceHistoryQuery{ResolutionFilter: 12345}
+	assert.Error(t, q.Validate())
+
+	q = BalanceHistoryQuery{ResolutionFilter: 3600000}
+	assert.NoError(t, q.Validate())
+
+	q.StartTimeFilter = 2000
+	q.EndTimeFilter = 1000
+	assert.Error(t, q.Validate())
+}
+
+// balanceHistorySourceMock serves effects and fee charges, given in
+// chronological order, as *history.Q does.
+type balanceHistorySourceMock struct {
+	effects []history.BalanceEffect
+	fees    []history.FeeCharge
+}
+
+func (m balanceHistorySourceMock) BalanceEffectsForAccount(
+	ctx context.Context,
+	accountID int64,
+	beforeOperationID int64,
+	beforeOrder int32,
+	minLedger int32,
+	limit uint64,
+) ([]history.BalanceEffect, error) {
+	var effects []history.BalanceEffect
+	for i := len(m.effects) - 1; i >= 0 && uint64(len(effects)) < limit; i-- {
+		effect := m.effects[i]
+		if effect.HistoryOperationID > beforeOperationID ||
+			(effect.HistoryOperationID == beforeOperationID && effect.Order >= beforeOrder) ||
+			int32(effect.HistoryOperationID>>32) < minLedger {
+			continue
+		}
+		effects = append(effects, effect)
+	}
+	return effects, nil
+}
+
+func (m balanceHistorySourceMock) FeeChargesForAccount(
+	ctx context.Context,
+	accountID int64,
+	address string,
+	beforeTransactionID int64,
+	minLedger int32,
+	limit uint64,
+) ([]history.FeeCharge, error) {
+	var fees []history.FeeCharge
+	for i := len(m.fees) - 1; i >= 0 && uint64(len(fees)) < limit; i-- {
+		fee := m.fees[i]
+		if fee.TransactionID >= beforeTransactionID || int32(fee.TransactionID>>32) < minLedger {
+			continue
+		}
+		fees = append(fees, fee)
+	}
+	return fees, nil
+}
+
+func TestBalanceHistoryReconstruction(t *testing.T) {
+	account := "GAXMF43TGZHW3QN3REOUA2U5PW5BTARXGGYJ3JIFHW3YT6QRKRL3CPPU"
+	other := "GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU"
+	issuer := "GD4FLXKATOO2Z4DME5BHLJDYF6UHUJS624CGA2FWTEVGUM4UVRF5YBGS"
+	hour := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	hourMillis := hour.Unix() * 1000
+	hourStep := int64(time.Hour / time.Millisecond)
+
+	source := balanceHistorySourceMock{
+		effects: []history.BalanceEffect{
+			{
+				HistoryOperationID: toid.New(10, 1, 1).ToInt64(),
+				Order:              1,
+				Type:               history.EffectAccountCreated,
+				DetailsString:      null.StringFrom(`{"starting_balance":"100.0000000"}`),
+				OperationType:      xdr.OperationTypeCreateAccount,
+				LedgerCloseTime:    hour.Add(5 * time.Minute),
+			},
+			{
+				HistoryOperationID:     toid.New(20, 1, 1).ToInt64(),
+				Order:                  1,
+				Type:                   history.EffectAccountDebited,
+				DetailsString:          null.StringFrom(`{"asset_type":"native","amount":"10.0000000"}`),
+				OperationType:          xdr.OperationTypePayment,
+				OperationSourceAccount: account,
+				LedgerCloseTime:        hour.Add(time.Hour),
+			},
+			{
+				HistoryOperationID:     toid.New(20, 2, 1).ToInt64(),
+				Order:                  1,
+				Type:                   history.EffectAccountCredited,
+				DetailsString:          null.StringFrom(`{"asset_type":"credit_alphanum4","asset_code":"USD","asset_issuer":"` + issuer + `","amount":"5.0000000"}`),
+				OperationType:          xdr.OperationTypePayment,
+				OperationSourceAccount: other,
+				LedgerCloseTime:        hour.Add(time.Hour),
+			},
+			{
+				HistoryOperationID:     toid.New(30, 1, 1).ToInt64(),
+				Order:                  1,
+				Type:                   history.EffectTrade,
+				DetailsString:          null.StringFrom(`{"offer_id":1,"sold_asset_type":"native","sold_amount":"20.0000000","bought_asset_type":"credit_alphanum4","bought_asset_code":"USD","bought_asset_issuer":"` + issuer + `","bought_amount":"2.0000000"}`),
+				OperationType:          xdr.OperationTypeManageSellOffer,
+				OperationSourceAccount: account,
+				LedgerCloseTime:        hour.Add(2 * time.Hour),
+			},
+			{
+				// Already accounted for by the account_debited effect of the
+				// path payment.
+				HistoryOperationID:     toid.New(30, 2, 1).ToInt64(),
+				Order:                  1,
+				Type:                   history.EffectTrade,
+				DetailsString:          null.StringFrom(`{"offer_id":2,"sold_asset_type":"native","sold_amount":"1.0000000","bought_asset_type":"credit_alphanum4","bought_asset_code":"USD","bought_asset_issuer":"` + issuer + `","bought_amount":"0.1000000"}`),
+				OperationType:          xdr.OperationTypePathPaymentStrictSend,
+				OperationSourceAccount: account,
+				LedgerCloseTime:        hour.Add(2 * time.Hour),
+			},
+		},
+		fees: []history.FeeCharge{
+			{TransactionID: toid.New(20, 1, 0).ToInt64(), FeeCharged: 100, LedgerCloseTime: hour.Add(time.Hour)},
+		},
+	}
+	walker := func(asset string, minLedger int32) *balanceChangeWalker {
+		return &balanceChangeWalker{
+			ctx:                 context.Background(),
+			source:              source,
+			address:             account,
+			asset:               asset,
+			minLedger:           minLedger,
+			beforeOperationID:   math.MaxInt64,
+			beforeOrder:         math.MaxInt32,
+			beforeTransactionID: math.MaxInt64,
+		}
+	}
+	window := balanceHistoryWindow{
+		resolution: hourStep,
+		upper:      math.MaxInt64,
+		order:      db2.OrderAscending,
+		limit:      10,
+	}
+
+	records, err := balanceHistory(walker("native", 1), 699999900, window)
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, hourMillis, records[0].Timestamp)
+	assert.Equal(t, "native", records[0].AssetType)
+	assert.Equal(t, "100.0000000", records[0].Balance)
+	assert.Equal(t, "100.0000000", records[0].Credited)
+	assert.Equal(t, "0.0000000", records[0].Debited)
+
+	assert.Equal(t, "89.9999900", records[1].Balance)
+	assert.Equal(t, "10.0000100", records[1].Debited)
+
+	assert.Equal(t, "69.9999900", records[2].Balance)
+	assert.Equal(t, "20.0000000", records[2].Debited)
+
+	// pages are cut after the walk, descending pages stop it early
+	window.limit = 2
+	records, err = balanceHistory(walker("native", 1), 699999900, window)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, hourMillis, records[0].Timestamp)
+	assert.Equal(t, hourMillis+hourStep, records[1].Timestamp)
+
+	window.order = db2.OrderDescending
+	window.upper = hourMillis + 2*hourStep
+	records, err = balanceHistory(walker("native", 1), 699999900, window)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, hourMillis+hourStep, records[0].Timestamp)
+	assert.Equal(t, "89.9999900", records[0].Balance)
+	assert.Equal(t, hourMillis, records[1].Timestamp)
+
+	// the balances after the reaped ledgers are anchored on the current one
+	window = balanceHistoryWindow{
+		resolution: hourStep,
+		lower:      hourMillis + hourStep,
+		upper:      math.MaxInt64,
+		order:      db2.OrderAscending,
+		limit:      10,
+	}
+	records, err = balanceHistory(walker("native", 20), 699999900, window)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "89.9999900", records[0].Balance)
+	assert.Equal(t, "69.9999900", records[1].Balance)
+
+	window.lower = hourMillis + 2*hourStep
+	records, err = balanceHistory(walker("USD:"+issuer, 1), 70000000, window)
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "credit_alphanum4", records[0].AssetType)
+	assert.Equal(t, "USD", records[0].AssetCode)
+	assert.Equal(t, issuer, records[0].AssetIssuer)
+	assert.Equal(t, "7.0000000", records[0].Balance)
+	assert.Equal(t, "2.0000000", records[0].Credited)
+}
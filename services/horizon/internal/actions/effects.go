@@ -9,6 +9,7 @@ import (
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/services/horizon/internal/toid"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/support/render/problem"
@@ -65,6 +66,16 @@ func (handler GetEffectsHandler) GetResourcePage(w HeaderWriter, r *http.Request
 		return nil, err
 	}
 
+	switch {
+	case qp.LedgerID > 0:
+		err = validateLedgerWithinHistory(handler.LedgerState, int32(qp.LedgerID))
+	case qp.OperationID > 0:
+		err = validateLedgerWithinHistory(handler.LedgerState, toid.Parse(int64(qp.OperationID)).LedgerSequence)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	historyQ, err := horizonContext.HistoryQFromRequest(r)
 	if err != nil {
 		return nil, err
@@ -560,15 +560,28 @@ func validateCursorWithinHistory(ledgerState *ledger.State, pq db2.PageQuery) er
 		return problem.MakeInvalidFieldProblem("cursor", errors.New("invalid value"))
 	}
 
-	elder := toid.New(ledgerState.CurrentStatus().HistoryElder, 0, 0)
+	status := ledgerState.CurrentStatus()
+	elder := toid.New(status.HistoryElder, 0, 0)
 
 	if cursor <= elder.ToInt64() {
-		return &hProblem.BeforeHistory
+		return hProblem.MakeBeforeHistoryProblem(status.HistoryElder, status.HistoryLatest)
 	}
 
 	return nil
 }
 
+// validateLedgerWithinHistory returns a 410 GONE http response if the ledger
+// with the given sequence is prior to the history of the history database,
+// e.g. because it has been reaped. Ledgers after the latest ingested ledger
+// are not rejected: they are not found (404) until they are ingested.
+func validateLedgerWithinHistory(ledgerState *ledger.State, sequence int32) error {
+	status := ledgerState.CurrentStatus()
+	if sequence < status.HistoryElder {
+		return hProblem.MakeBeforeHistoryProblem(status.HistoryElder, status.HistoryLatest)
+	}
+	return nil
+}
+
 func countNonEmpty(params ...interface{}) (int, error) {
 	count := 0
 
@@ -147,6 +147,29 @@ func TestValidateCursorWithinHistory(t *testing.T) {
 	}
 }
 
+func TestValidateLedgerWithinHistory(t *testing.T) {
+	ledgerState := &ledger.State{}
+	ledgerState.SetStatus(ledger.Status{HistoryElder: 100, HistoryLatest: 200})
+
+	assert.NoError(t, validateLedgerWithinHistory(ledgerState, 100))
+	// ledgers after the latest ledger are not found, not gone
+	assert.NoError(t, validateLedgerWithinHistory(ledgerState, 201))
+
+	err := validateLedgerWithinHistory(ledgerState, 99)
+	assert.IsType(t, problem.P{}, err)
+	p := err.(problem.P)
+	assert.Equal(t, "before_history", p.Type)
+	assert.Equal(t, http.StatusGone, p.Status)
+	assert.Equal(t, map[string]interface{}{
+		"history_elder_ledger":  int32(100),
+		"history_latest_ledger": int32(200),
+	}, p.Extras)
+
+	pq, err := db2.NewPageQuery(toid.New(99, 0, 0).String(), false, "desc", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, p, validateCursorWithinHistory(ledgerState, pq))
+}
+
 func TestActionGetLimit(t *testing.T) {
 	tt := test.Start(t)
 	defer tt.Finish()
@@ -7,7 +7,6 @@ import (
 	"github.com/stellar/go/services/horizon/internal/context"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/ledger"
-	"github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
 	"github.com/stellar/go/support/render/hal"
 )
@@ -65,8 +64,8 @@ func (handler GetLedgerByIDHandler) GetResource(w HeaderWriter, r *http.Request)
 	if err != nil {
 		return nil, err
 	}
-	if int32(qp.LedgerID) < handler.LedgerState.CurrentStatus().HistoryElder {
-		return nil, problem.BeforeHistory
+	if err = validateLedgerWithinHistory(handler.LedgerState, int32(qp.LedgerID)); err != nil {
+		return nil, err
 	}
 	historyQ, err := context.HistoryQFromRequest(r)
 	if err != nil {
@@ -8,7 +8,6 @@ import (
 	horizonContext "github.com/stellar/go/services/horizon/internal/context"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/ledger"
-	"github.com/stellar/go/services/horizon/internal/render/problem"
 	"github.com/stellar/go/services/horizon/internal/resourceadapter"
 	"github.com/stellar/go/services/horizon/internal/toid"
 	"github.com/stellar/go/support/errors"
@@ -85,6 +84,13 @@ func (handler GetOperationsHandler) GetResourcePage(w HeaderWriter, r *http.Requ
 		return nil, err
 	}
 
+	if qp.LedgerID > 0 {
+		err = validateLedgerWithinHistory(handler.LedgerState, int32(qp.LedgerID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	historyQ, err := horizonContext.HistoryQFromRequest(r)
 	if err != nil {
 		return nil, err
@@ -144,10 +150,7 @@ type OperationQuery struct {
 // Validate runs extra validations on query parameters
 func (qp OperationQuery) Validate() error {
 	parsed := toid.Parse(int64(qp.ID))
-	if parsed.LedgerSequence < qp.LedgerState.CurrentStatus().HistoryElder {
-		return problem.BeforeHistory
-	}
-	return nil
+	return validateLedgerWithinHistory(qp.LedgerState, parsed.LedgerSequence)
 }
 
 // GetResource returns an operation page.
@@ -210,7 +210,7 @@ func TestGetOperationsIncludeFailed(t *testing.T) {
 	tt.Scenario("failed_transactions")
 
 	q := &history.Q{tt.HorizonSession()}
-	handler := GetOperationsHandler{}
+	handler := GetOperationsHandler{LedgerState: &ledger.State{}}
 
 	records, err := handler.GetResourcePage(
 		httptest.NewRecorder(),
@@ -340,7 +340,7 @@ func TestGetOperationsFilterByLedgerID(t *testing.T) {
 	tt.Scenario("base")
 
 	q := &history.Q{tt.HorizonSession()}
-	handler := GetOperationsHandler{}
+	handler := GetOperationsHandler{LedgerState: &ledger.State{}}
 
 	testCases := []struct {
 		ledgerID    string
@@ -491,7 +491,7 @@ func TestOperation_CreatedAt(t *testing.T) {
 	tt.Scenario("base")
 
 	q := &history.Q{tt.HorizonSession()}
-	handler := GetOperationsHandler{}
+	handler := GetOperationsHandler{LedgerState: &ledger.State{}}
 
 	records, err := handler.GetResourcePage(
 		httptest.NewRecorder(),
@@ -659,7 +659,10 @@ func TestGetOperation(t *testing.T) {
 			t, map[string]string{}, map[string]string{"id": "0"}, tt.HorizonSession(),
 		),
 	)
-	tt.Assert.Equal(err, problem.BeforeHistory)
+	tt.Assert.Equal(err, problem.MakeBeforeHistoryProblem(
+		handler.LedgerState.CurrentStatus().HistoryElder,
+		handler.LedgerState.CurrentStatus().HistoryLatest,
+	))
 }
 
 func TestOperation_IncludeTransaction(t *testing.T) {
@@ -26,6 +26,12 @@ type GetRootHandler struct {
 	NetworkPassphrase string
 	FriendbotURL      *url.URL
 	HorizonVersion    string
+	// Operator is the operator metadata included in the root resource, if
+	// any.
+	Operator *horizon.RootOperator
+	// HistoryRetentionCount is the number of ledgers kept in history, zero
+	// if all history is kept.
+	HistoryRetentionCount uint
 }
 
 func (handler GetRootHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
@@ -50,5 +56,7 @@ func (handler GetRootHandler) GetResource(w HeaderWriter, r *http.Request) (inte
 		handler.FriendbotURL,
 		templates,
 	)
+	res.Operator = handler.Operator
+	res.HistoryRetentionCount = handler.HistoryRetentionCount
 	return res, nil
 }
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+)
+
+type staticCoreSettings CoreSettings
+
+func (s staticCoreSettings) GetCoreSettings() CoreSettings {
+	return CoreSettings(s)
+}
+
+func TestGetRootHandlerOperator(t *testing.T) {
+	handler := GetRootHandler{
+		LedgerState:        &ledger.State{},
+		CoreSettingsGetter: staticCoreSettings{CoreVersion: "test-core"},
+		NetworkPassphrase:  "test",
+		HorizonVersion:     "devel",
+	}
+
+	response, err := handler.GetResource(nil, makeRequest(t, nil, nil, nil))
+	assert.NoError(t, err)
+	root := response.(horizon.Root)
+	assert.Equal(t, "test-core", root.StellarCoreVersion)
+	assert.Nil(t, root.Operator)
+
+	handler.Operator = &horizon.RootOperator{
+		Name:          "Example",
+		Contact:       "ops@example.com",
+		NetworkName:   "pubnet",
+		SupportedSEPs: []string{"SEP-1", "SEP-10"},
+	}
+	response, err = handler.GetResource(nil, makeRequest(t, nil, nil, nil))
+	assert.NoError(t, err)
+	root = response.(horizon.Root)
+	assert.Equal(t, handler.Operator, root.Operator)
+}
+
+func TestGetRootHandlerHistoryRetention(t *testing.T) {
+	ledgerState := &ledger.State{}
+	ledgerState.SetStatus(ledger.Status{HistoryElder: 100, HistoryLatest: 200})
+	handler := GetRootHandler{
+		LedgerState:           ledgerState,
+		CoreSettingsGetter:    staticCoreSettings{},
+		HistoryRetentionCount: 101,
+	}
+
+	response, err := handler.GetResource(nil, makeRequest(t, nil, nil, nil))
+	assert.NoError(t, err)
+	root := response.(horizon.Root)
+	assert.Equal(t, int32(100), root.HistoryElderSequence)
+	assert.Equal(t, int32(200), root.HorizonSequence)
+	assert.Equal(t, uint(101), root.HistoryRetentionCount)
+}
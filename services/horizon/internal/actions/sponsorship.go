@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"net/http"
+
+	"github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/resourceadapter"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// AccountSponsorshipsQuery query struct for the
+// /accounts/{account_id}/sponsorships end-points
+type AccountSponsorshipsQuery struct {
+	AccountID string `schema:"account_id" valid:"accountID,required"`
+}
+
+// GetAccountSponsorshipsHandler is the action handler for the
+// /accounts/{account_id}/sponsorships endpoint, which summarizes the entries
+// sponsored by an account and the sponsors of its entries.
+type GetAccountSponsorshipsHandler struct{}
+
+// GetResource returns the sponsorships summary of an account.
+func (handler GetAccountSponsorshipsHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
+	ctx := r.Context()
+	qp := AccountSponsorshipsQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sponsoring, err := historyQ.SponsorshipCounts(ctx, history.SponsorshipsQuery{Sponsor: qp.AccountID})
+	if err != nil {
+		return nil, err
+	}
+	sponsored, err := historyQ.SponsorshipCounts(ctx, history.SponsorshipsQuery{AccountID: qp.AccountID})
+	if err != nil {
+		return nil, err
+	}
+	sponsors, err := historyQ.SponsorsForAccount(ctx, qp.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := horizon.AccountSponsorships{
+		AccountID: qp.AccountID,
+		Sponsors:  make([]horizon.SponsorCount, 0, len(sponsors)),
+	}
+	resourceadapter.PopulateSponsorshipCounts(&response.Sponsoring, sponsoring)
+	resourceadapter.PopulateSponsorshipCounts(&response.Sponsored, sponsored)
+	for _, sponsor := range sponsors {
+		response.Sponsors = append(response.Sponsors, horizon.SponsorCount{
+			Sponsor: sponsor.Sponsor,
+			Count:   sponsor.Count,
+		})
+	}
+
+	lb := hal.LinkBuilder{Base: horizonContext.BaseURL(ctx)}
+	response.Links.Self = lb.Link("/accounts", qp.AccountID, "sponsorships")
+	response.Links.Sponsoring = lb.PagedLink("/accounts", qp.AccountID, "sponsorships", "sponsoring")
+	response.Links.Sponsored = lb.PagedLink("/accounts", qp.AccountID, "sponsorships", "sponsored")
+	return response, nil
+}
+
+// GetSponsorshipsHandler is the action handler for the
+// /accounts/{account_id}/sponsorships/sponsoring and
+// /accounts/{account_id}/sponsorships/sponsored endpoints.
+type GetSponsorshipsHandler struct {
+	LedgerState *ledger.State
+	// Sponsoring lists the entries sponsored by the account instead of the
+	// sponsored entries of the account.
+	Sponsoring bool
+}
+
+// GetResourcePage returns a page of sponsorships of an account.
+func (handler GetSponsorshipsHandler) GetResourcePage(
+	w HeaderWriter,
+	r *http.Request,
+) ([]hal.Pageable, error) {
+	ctx := r.Context()
+	qp := AccountSponsorshipsQuery{}
+	if err := getParams(&qp, r); err != nil {
+		return nil, err
+	}
+
+	pq, err := GetPageQuery(handler.LedgerState, r, DisableCursorValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	query := history.SponsorshipsQuery{PageQuery: pq}
+	if handler.Sponsoring {
+		query.Sponsor = qp.AccountID
+	} else {
+		query.AccountID = qp.AccountID
+	}
+
+	if _, _, _, err = query.Cursor(); err != nil {
+		return nil, problem.MakeInvalidFieldProblem(
+			"cursor",
+			errors.New("The cursor should be the paging token of a sponsorship"),
+		)
+	}
+
+	historyQ, err := horizonContext.HistoryQFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := historyQ.GetSponsorships(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var sponsorships []hal.Pageable
+	for _, record := range records {
+		var response horizon.Sponsorship
+		resourceadapter.PopulateSponsorship(ctx, &response, record)
+		sponsorships = append(sponsorships, response)
+	}
+
+	return sponsorships, nil
+}
@@ -0,0 +1,88 @@
+package actions
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/support/render/problem"
+)
+
+func TestGetSponsorshipsHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &history.Q{tt.HorizonSession()}
+
+	batch := q.NewOffersBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, eurOffer))
+	tt.Assert.NoError(batch.Add(tt.Ctx, twoEurOffer))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	for _, c := range []struct {
+		sponsoring bool
+		accountID  string
+	}{
+		{true, sponsor.Address()},
+		{false, seller.Address()},
+	} {
+		records, err := GetSponsorshipsHandler{Sponsoring: c.sponsoring}.GetResourcePage(
+			httptest.NewRecorder(),
+			makeRequest(t, map[string]string{}, map[string]string{"account_id": c.accountID}, q),
+		)
+		tt.Assert.NoError(err)
+		tt.Assert.Len(records, 1)
+
+		sponsorship := records[0].(horizon.Sponsorship)
+		tt.Assert.Equal(history.SponsoredOffer, sponsorship.Type)
+		tt.Assert.Equal(seller.Address(), sponsorship.AccountID)
+		tt.Assert.Equal(sponsor.Address(), sponsorship.Sponsor)
+		tt.Assert.Equal("5", sponsorship.OfferID)
+		tt.Assert.Equal("offer-"+seller.Address()+"-5", sponsorship.PagingToken())
+	}
+
+	_, err := GetSponsorshipsHandler{Sponsoring: true}.GetResourcePage(
+		httptest.NewRecorder(),
+		makeRequest(
+			t,
+			map[string]string{"cursor": "5"},
+			map[string]string{"account_id": sponsor.Address()},
+			q,
+		),
+	)
+	tt.Assert.IsType(&problem.P{}, err)
+	tt.Assert.Equal("cursor", err.(*problem.P).Extras["invalid_field"])
+}
+
+func TestGetAccountSponsorshipsHandler(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &history.Q{tt.HorizonSession()}
+
+	batch := q.NewOffersBatchInsertBuilder(0)
+	tt.Assert.NoError(batch.Add(tt.Ctx, twoEurOffer))
+	tt.Assert.NoError(batch.Exec(tt.Ctx))
+
+	resource, err := GetAccountSponsorshipsHandler{}.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": sponsor.Address()}, q),
+	)
+	tt.Assert.NoError(err)
+	summary := resource.(horizon.AccountSponsorships)
+	tt.Assert.Equal(horizon.SponsorshipCounts{Total: 1, Offers: 1}, summary.Sponsoring)
+	tt.Assert.Equal(horizon.SponsorshipCounts{}, summary.Sponsored)
+	tt.Assert.Empty(summary.Sponsors)
+
+	resource, err = GetAccountSponsorshipsHandler{}.GetResource(
+		httptest.NewRecorder(),
+		makeRequest(t, map[string]string{}, map[string]string{"account_id": seller.Address()}, q),
+	)
+	tt.Assert.NoError(err)
+	summary = resource.(horizon.AccountSponsorships)
+	tt.Assert.Equal(horizon.SponsorshipCounts{}, summary.Sponsoring)
+	tt.Assert.Equal(horizon.SponsorshipCounts{Total: 1, Offers: 1}, summary.Sponsored)
+	tt.Assert.Equal([]horizon.SponsorCount{{Sponsor: sponsor.Address(), Count: 1}}, summary.Sponsors)
+}
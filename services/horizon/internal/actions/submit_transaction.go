@@ -1,6 +1,7 @@
 package actions
 
 import (
+	"context"
 	"encoding/hex"
 	"mime"
 	"net/http"
@@ -16,9 +17,19 @@ import (
 	"github.com/stellar/go/xdr"
 )
 
+const (
+	// idempotencyKeyHeader is the header clients use to send a token
+	// identifying a submission, so that retries return the original result.
+	idempotencyKeyHeader    = "Idempotency-Key"
+	maxIdempotencyKeyLength = 255
+)
+
 type SubmitTransactionHandler struct {
 	Submitter         *txsub.System
 	NetworkPassphrase string
+	// IdempotencyStore, if set, enables idempotent submissions using the
+	// Idempotency-Key header.
+	IdempotencyStore *txsub.IdempotencyStore
 }
 
 type envelopeInfo struct {
@@ -109,6 +120,34 @@ func (handler SubmitTransactionHandler) response(r *http.Request, info envelopeI
 	return nil, result.Err
 }
 
+func (handler SubmitTransactionHandler) submit(r *http.Request, info envelopeInfo) (<-chan txsub.Result, error) {
+	submit := func(ctx context.Context) <-chan txsub.Result {
+		return handler.Submitter.Submit(
+			ctx,
+			info.raw,
+			info.parsed,
+			info.hash,
+		)
+	}
+
+	token := r.Header.Get(idempotencyKeyHeader)
+	if token == "" || handler.IdempotencyStore == nil {
+		return submit(r.Context()), nil
+	}
+	if len(token) > maxIdempotencyKeyLength {
+		return nil, problem.MakeInvalidFieldProblem(
+			idempotencyKeyHeader,
+			errors.Errorf("must be at most %d characters long", maxIdempotencyKeyLength),
+		)
+	}
+
+	submission, err := handler.IdempotencyStore.Submit(r.Context(), token, info.hash, submit)
+	if err == txsub.ErrIdempotencyTokenConflict {
+		return nil, &hProblem.IdempotencyKeyConflict
+	}
+	return submission, err
+}
+
 func (handler SubmitTransactionHandler) GetResource(w HeaderWriter, r *http.Request) (interface{}, error) {
 	if err := handler.validateBodyType(r); err != nil {
 		return nil, err
@@ -136,12 +175,10 @@ func (handler SubmitTransactionHandler) GetResource(w HeaderWriter, r *http.Requ
 		}
 	}
 
-	submission := handler.Submitter.Submit(
-		r.Context(),
-		info.raw,
-		info.parsed,
-		info.hash,
-	)
+	submission, err := handler.submit(r, info)
+	if err != nil {
+		return nil, err
+	}
 
 	select {
 	case result := <-submission:
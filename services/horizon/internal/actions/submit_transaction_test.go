@@ -0,0 +1,131 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/network"
+	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/services/horizon/internal/txsub"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+func submitTestEnvelope(t *testing.T) string {
+	tx := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxV0,
+		V0: &xdr.TransactionV0Envelope{
+			Tx: xdr.TransactionV0{
+				SourceAccountEd25519: *xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H").Ed25519,
+				Fee:                  100,
+				SeqNum:               1,
+				Operations: []xdr.Operation{
+					{
+						Body: xdr.OperationBody{
+							Type: xdr.OperationTypeCreateAccount,
+							CreateAccountOp: &xdr.CreateAccountOp{
+								Destination:     xdr.MustAddress("GCXKG6RN4ONIEPCMNFB732A436Z5PNDSRLGWK7GBLCMQLIFO4S7EYWVU"),
+								StartingBalance: 1000000000,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	txStr, err := xdr.MarshalBase64(tx)
+	require.NoError(t, err)
+	return txStr
+}
+
+func makeSubmitRequest(t *testing.T, txStr, idempotencyKey string) *http.Request {
+	form := url.Values{"tx": []string{txStr}}
+	r, err := http.NewRequest("POST", "/transactions", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		r.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chi.NewRouteContext()))
+}
+
+// recordedResult returns a submit function emitting result, failing the test
+// if it is called more than once.
+func recordedResult(t *testing.T, result txsub.Result) func(context.Context) <-chan txsub.Result {
+	calls := 0
+	return func(context.Context) <-chan txsub.Result {
+		calls++
+		require.Equal(t, 1, calls)
+		ch := make(chan txsub.Result, 1)
+		ch <- result
+		return ch
+	}
+}
+
+func TestSubmitTransactionIdempotencyKey(t *testing.T) {
+	store := txsub.NewIdempotencyStore(time.Minute, time.Minute)
+	// Submitter is nil, so any submission which is not answered from the
+	// idempotency store panics.
+	handler := SubmitTransactionHandler{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		IdempotencyStore:  store,
+	}
+	txStr := submitTestEnvelope(t)
+	info, err := extractEnvelopeInfo(txStr, handler.NetworkPassphrase)
+	require.NoError(t, err)
+
+	maxLengthKey := strings.Repeat("k", maxIdempotencyKeyLength)
+	for _, key := range []string{"key", maxLengthKey} {
+		submission, err := store.Submit(context.Background(), key, info.hash, recordedResult(t, txsub.Result{Err: txsub.ErrNoAccount}))
+		require.NoError(t, err)
+		<-submission
+
+		// a retry with the same key gets the result of the original submission
+		_, err = handler.GetResource(httptest.NewRecorder(), makeSubmitRequest(t, txStr, key))
+		p, ok := err.(*problem.P)
+		require.True(t, ok)
+		assert.Equal(t, "transaction_failed", p.Type)
+		assert.Equal(t, txsub.ErrNoAccount.ResultXDR, p.Extras["result_xdr"])
+	}
+}
+
+func TestSubmitTransactionIdempotencyKeyConflict(t *testing.T) {
+	store := txsub.NewIdempotencyStore(time.Minute, time.Minute)
+	handler := SubmitTransactionHandler{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		IdempotencyStore:  store,
+	}
+
+	otherHash := strings.Repeat("0", 64)
+	submission, err := store.Submit(context.Background(), "key", otherHash, recordedResult(t, txsub.Result{Err: txsub.ErrNoAccount}))
+	require.NoError(t, err)
+	<-submission
+
+	_, err = handler.GetResource(httptest.NewRecorder(), makeSubmitRequest(t, submitTestEnvelope(t), "key"))
+	assert.Equal(t, &hProblem.IdempotencyKeyConflict, err)
+	assert.Equal(t, http.StatusConflict, hProblem.IdempotencyKeyConflict.Status)
+}
+
+func TestSubmitTransactionIdempotencyKeyTooLong(t *testing.T) {
+	handler := SubmitTransactionHandler{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		IdempotencyStore:  txsub.NewIdempotencyStore(time.Minute, time.Minute),
+	}
+
+	key := strings.Repeat("k", maxIdempotencyKeyLength+1)
+	_, err := handler.GetResource(httptest.NewRecorder(), makeSubmitRequest(t, submitTestEnvelope(t), key))
+	require.Error(t, err)
+	p, ok := err.(*problem.P)
+	require.True(t, ok)
+	assert.Equal(t, "bad_request", p.Type)
+	assert.Equal(t, idempotencyKeyHeader, p.Extras["invalid_field"])
+}
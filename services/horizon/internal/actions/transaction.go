@@ -110,6 +110,13 @@ func (handler GetTransactionsHandler) GetResourcePage(w HeaderWriter, r *http.Re
 		return nil, err
 	}
 
+	if qp.LedgerID > 0 {
+		err = validateLedgerWithinHistory(handler.LedgerState, int32(qp.LedgerID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	historyQ, err := horizonContext.HistoryQFromRequest(r)
 	if err != nil {
 		return nil, err
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/test"
 	supportProblem "github.com/stellar/go/support/render/problem"
 )
@@ -16,7 +17,7 @@ func TestGetTransactionsHandler(t *testing.T) {
 	defer tt.Finish()
 
 	q := &history.Q{tt.HorizonSession()}
-	handler := GetTransactionsHandler{}
+	handler := GetTransactionsHandler{LedgerState: &ledger.State{}}
 
 	// filter by account
 	records, err := handler.GetResourcePage(
@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/stellar/go/clients/stellarcore"
+	hProtocol "github.com/stellar/go/protocols/horizon"
 	proto "github.com/stellar/go/protocols/stellarcore"
 	"github.com/stellar/go/services/horizon/internal/actions"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
@@ -68,6 +69,7 @@ type App struct {
 	reaper          *reap.System
 	ticks           *time.Ticker
 	ledgerState     *ledger.State
+	drain           drainState
 
 	// metrics
 	prometheusRegistry                *prometheus.Registry
@@ -107,7 +109,7 @@ func NewApp(config Config) (*App, error) {
 // the shutdown signals.
 func (a *App) Serve() {
 
-	log.Infof("Starting horizon on :%d (ingest: %v)", a.config.Port, a.config.Ingest)
+	log.Infof("Starting horizon on :%d (ingest: %v, read-only gateway: %v)", a.config.Port, a.config.Ingest, a.config.ReadOnlyGateway)
 
 	if a.config.AdminPort != 0 {
 		log.Infof("Starting internal server on :%d", a.config.AdminPort)
@@ -162,8 +164,27 @@ func (a *App) Close() {
 	close(a.done)
 }
 
+// Drain puts the app in drain mode: it keeps serving requests, but fails
+// health checks and closes connections once their requests are served, so
+// that load balancers send new requests to other instances.
+func (a *App) Drain() {
+	if a.drain.isDraining() {
+		return
+	}
+	log.Info("draining")
+	a.drain.drain()
+	if a.webServer != nil {
+		a.webServer.DisableKeepAlives()
+	}
+}
+
 func (a *App) waitForDone() {
 	<-a.done
+	a.Drain()
+	if a.config.DrainPeriod > 0 {
+		log.Infof("waiting %s for load balancers to notice draining", a.config.DrainPeriod)
+		time.Sleep(a.config.DrainPeriod)
+	}
 	webShutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	a.webServer.Shutdown(webShutdownCtx)
@@ -207,18 +228,23 @@ func (a *App) UpdateLedgerState(ctx context.Context) {
 		log.WithStack(err).WithField("err", err.Error()).Error(msg)
 	}
 
-	coreClient := &stellarcore.Client{
-		HTTP: http.DefaultClient,
-		URL:  a.config.StellarCoreURL,
-	}
+	// read-only gateways do not connect to stellar-core, CoreLatest is left
+	// at zero.
+	if !a.config.ReadOnlyGateway {
+		coreClient := &stellarcore.Client{
+			HTTP: http.DefaultClient,
+			URL:  a.config.StellarCoreURL,
+		}
 
-	coreInfo, err := coreClient.Info(a.ctx)
-	if err != nil {
-		logErr(err, "failed to load the stellar-core info")
-		return
+		coreInfo, err := coreClient.Info(a.ctx)
+		if err != nil {
+			logErr(err, "failed to load the stellar-core info")
+			return
+		}
+		next.CoreLatest = int32(coreInfo.Info.Ledger.Num)
 	}
-	next.CoreLatest = int32(coreInfo.Info.Ledger.Num)
 
+	var err error
 	next.HistoryLatest, next.HistoryLatestClosedAt, err =
 		a.HistoryQ().LatestLedgerSequenceClosedAt(ctx)
 	if err != nil {
@@ -365,7 +391,7 @@ func (a *App) UpdateFeeStatsState(ctx context.Context) {
 // CurrentProtocolVersion, and CoreSupportedProtocolVersion from the Stellar
 // core API.
 func (a *App) UpdateStellarCoreInfo(ctx context.Context) {
-	if a.config.StellarCoreURL == "" {
+	if a.config.StellarCoreURL == "" || a.config.ReadOnlyGateway {
 		return
 	}
 
@@ -412,15 +438,37 @@ func (a *App) Tick(ctx context.Context) error {
 	go func() { a.UpdateStellarCoreInfo(ctx); wg.Done() }()
 	wg.Wait()
 
-	wg.Add(2)
-	go func() { a.reaper.Tick(ctx); wg.Done() }()
-	go func() { a.submitter.Tick(ctx); wg.Done() }()
+	// read-only gateways leave reaping to the instances maintaining the
+	// database
+	if !a.config.ReadOnlyGateway {
+		wg.Add(1)
+		go func() { a.reaper.Tick(ctx); wg.Done() }()
+	}
+	if a.submitter != nil {
+		wg.Add(1)
+		go func() { a.submitter.Tick(ctx); wg.Done() }()
+	}
 	wg.Wait()
 
 	log.Debug("finished ticking app")
 	return ctx.Err()
 }
 
+// rootOperator returns the operator metadata advertised in the root
+// resource, or nil if none is configured.
+func (a *App) rootOperator() *hProtocol.RootOperator {
+	if a.config.OperatorName == "" && a.config.OperatorContact == "" &&
+		a.config.NetworkName == "" && len(a.config.SupportedSEPs) == 0 {
+		return nil
+	}
+	return &hProtocol.RootOperator{
+		Name:          a.config.OperatorName,
+		Contact:       a.config.OperatorContact,
+		NetworkName:   a.config.NetworkName,
+		SupportedSEPs: a.config.SupportedSEPs,
+	}
+}
+
 // Init initializes app, using the config to populate db connections and
 // whatnot.
 func (a *App) init() error {
@@ -456,7 +504,9 @@ func (a *App) init() error {
 	initPathFinder(a)
 
 	// txsub
-	initSubmissionSystem(a)
+	if !a.config.ReadOnlyGateway || a.config.StellarCoreURL != "" {
+		initSubmissionSystem(a)
+	}
 
 	// reaper
 	a.reaper = reap.New(a.config.HistoryRetentionCount, a.HorizonSession(), a.ledgerState)
@@ -477,31 +527,55 @@ func (a *App) init() error {
 	initTxSubMetrics(a)
 
 	routerConfig := httpx.RouterConfig{
-		DBSession:             a.historyQ.SessionInterface,
-		TxSubmitter:           a.submitter,
-		RateQuota:             a.config.RateQuota,
-		BehindCloudflare:      a.config.BehindCloudflare,
-		BehindAWSLoadBalancer: a.config.BehindAWSLoadBalancer,
-		SSEUpdateFrequency:    a.config.SSEUpdateFrequency,
-		StaleThreshold:        a.config.StaleThreshold,
-		ConnectionTimeout:     a.config.ConnectionTimeout,
-		NetworkPassphrase:     a.config.NetworkPassphrase,
-		MaxPathLength:         a.config.MaxPathLength,
-		PathFinder:            a.paths,
-		PrometheusRegistry:    a.prometheusRegistry,
-		CoreGetter:            a,
-		HorizonVersion:        a.horizonVersion,
-		FriendbotURL:          a.config.FriendbotURL,
-		HealthCheck: healthCheck{
-			session: a.historyQ.SessionInterface,
-			ctx:     a.ctx,
-			core: &stellarcore.Client{
-				HTTP: &http.Client{Timeout: infoRequestTimeout},
-				URL:  a.config.StellarCoreURL,
-			},
-			cache: newHealthCache(healthCacheTTL),
+		DBSession:                   a.historyQ.SessionInterface,
+		TxSubmitter:                 a.submitter,
+		RateQuota:                   a.config.RateQuota,
+		BehindCloudflare:            a.config.BehindCloudflare,
+		BehindAWSLoadBalancer:       a.config.BehindAWSLoadBalancer,
+		SSEUpdateFrequency:          a.config.SSEUpdateFrequency,
+		StaleThreshold:              a.config.StaleThreshold,
+		ConnectionTimeout:           a.config.ConnectionTimeout,
+		NetworkPassphrase:           a.config.NetworkPassphrase,
+		MaxPathLength:               a.config.MaxPathLength,
+		PathFinder:                  a.paths,
+		PrometheusRegistry:          a.prometheusRegistry,
+		CoreGetter:                  a,
+		HorizonVersion:              a.horizonVersion,
+		FriendbotURL:                a.config.FriendbotURL,
+		SubmissionIdempotencyWindow: a.config.SubmissionIdempotencyWindow,
+		ResponseCacheSize:           a.config.ResponseCacheSize,
+		Operator:                    a.rootOperator(),
+		HistoryRetentionCount:       a.config.HistoryRetentionCount,
+		LatencyBudgets: httpx.LatencyBudgetConfig{
+			Budgets: a.config.LatencyBudgets,
+			Target:  a.config.LatencyBudgetTarget,
+			Window:  a.config.LatencyBudgetWindow,
 		},
+		ReadOnlyGateway: a.config.ReadOnlyGateway,
+	}
+
+	health := healthCheck{
+		session: a.historyQ.SessionInterface,
+		ctx:     a.ctx,
+		cache:   newHealthCache(healthCacheTTL),
+
+		ledgerState:           a.ledgerState,
+		ingestionLagThreshold: a.config.HealthIngestionLagThreshold,
+		drain:                 &a.drain,
+	}
+	if a.primaryHistoryQ != nil {
+		health.primary = a.primaryHistoryQ
+		health.replica = a.historyQ
+		health.replicaLagThreshold = uint32(a.config.HealthReplicaLagThreshold)
+	}
+	if !a.config.ReadOnlyGateway {
+		health.core = &stellarcore.Client{
+			HTTP: &http.Client{Timeout: infoRequestTimeout},
+			URL:  a.config.StellarCoreURL,
+		}
 	}
+	routerConfig.HealthCheck = health
+	routerConfig.Drain = drainHandler{app: a}
 
 	if a.primaryHistoryQ != nil {
 		routerConfig.PrimaryDBSession = a.primaryHistoryQ.SessionInterface
@@ -6,14 +6,21 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/services/horizon/internal/httpx"
+	"github.com/stellar/go/services/horizon/internal/ingest/processors"
 	"github.com/stellar/throttled"
 )
 
 // Config is the configuration for horizon.  It gets populated by the
 // app's main function and is provided to NewApp.
 type Config struct {
-	DatabaseURL        string
-	RoDatabaseURL      string
+	DatabaseURL   string
+	RoDatabaseURL string
+	// RoDatabaseMaxLag, if non-zero, makes the reads of the API run against
+	// the replica of RoDatabaseURL, or against the primary while the replica
+	// lags more than RoDatabaseMaxLag, instead of failing with stale history
+	// errors when the replica is behind the primary.
+	RoDatabaseMaxLag   time.Duration
 	HistoryArchiveURLs []string
 	Port               uint
 	AdminPort          uint
@@ -33,13 +40,43 @@ type Config struct {
 	MaxDBConnections            int
 	HorizonDBMaxOpenConnections int
 	HorizonDBMaxIdleConnections int
+	// SlowQueryThreshold is the duration above which DB queries are logged
+	// as slow. Zero disables the slow query log.
+	SlowQueryThreshold time.Duration
 
 	SSEUpdateFrequency time.Duration
 	ConnectionTimeout  time.Duration
-	RateQuota          *throttled.RateQuota
-	FriendbotURL       *url.URL
-	LogLevel           logrus.Level
-	LogFile            string
+	// SubmissionIdempotencyWindow is how long the results of transaction
+	// submissions made with an idempotency key are kept.
+	SubmissionIdempotencyWindow time.Duration
+	// ResponseCacheSize is the number of immutable resources whose responses
+	// are cached in memory, zero disables the cache.
+	ResponseCacheSize int
+	RateQuota         *throttled.RateQuota
+	FriendbotURL      *url.URL
+	// LatencyBudgets, LatencyBudgetTarget and LatencyBudgetWindow configure
+	// the latency budget alarms of routes, reported on the admin port.
+	LatencyBudgets      []httpx.LatencyBudget
+	LatencyBudgetTarget float64
+	LatencyBudgetWindow time.Duration
+	// HealthIngestionLagThreshold is the age of the latest ingested ledger
+	// above which /health reports ingestion as lagging, and
+	// HealthReplicaLagThreshold the number of ledgers the replica database
+	// may be behind the primary before /health reports it as stale. Zero
+	// disables the checks.
+	HealthIngestionLagThreshold time.Duration
+	HealthReplicaLagThreshold   uint
+	// DrainPeriod is how long Horizon keeps serving requests, while failing
+	// /health, when shutting down.
+	DrainPeriod time.Duration
+	// OperatorName, OperatorContact, NetworkName and SupportedSEPs describe
+	// the operator of this instance in the root resource.
+	OperatorName    string
+	OperatorContact string
+	NetworkName     string
+	SupportedSEPs   []string
+	LogLevel        logrus.Level
+	LogFile         string
 	// MaxPathLength is the maximum length of the path returned by `/paths` endpoint.
 	MaxPathLength     uint
 	NetworkPassphrase string
@@ -52,6 +89,11 @@ type Config struct {
 	TLSKey string
 	// Ingest toggles whether this horizon instance should run the data ingestion subsystem.
 	Ingest bool
+	// ReadOnlyGateway makes this horizon instance serve the API from a
+	// database maintained by other, ingesting, instances without connecting
+	// to stellar-core. Transactions are only submitted if StellarCoreURL is
+	// set. Responses include headers reporting how stale the database is.
+	ReadOnlyGateway bool
 	// CursorName is the cursor used for ingesting from stellar-core.
 	// Setting multiple cursors in different Horizon instances allows multiple
 	// Horizons to ingest from the same stellar-core instance without cursor
@@ -72,6 +114,9 @@ type Config struct {
 	// IngestDisableStateVerification disables state verification
 	// `System.verifyState()` when set to `true`.
 	IngestDisableStateVerification bool
+	// IngestMarketFilter selects the markets of which offers and trades are
+	// ingested. All markets are ingested if nil.
+	IngestMarketFilter *processors.MarketFilter
 	// ApplyMigrations will apply pending migrations to the horizon database
 	// before starting the horizon service
 	ApplyMigrations bool
@@ -0,0 +1,192 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
+
+	"github.com/stellar/go/services/horizon/internal/toid"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// AssetPaymentStatsOperationTypes are the operations counted in the payment
+// stats of the asset they deliver.
+var AssetPaymentStatsOperationTypes = []xdr.OperationType{
+	xdr.OperationTypePayment,
+	xdr.OperationTypePathPaymentStrictReceive,
+	xdr.OperationTypePathPaymentStrictSend,
+}
+
+// AssetPaymentStat is a row of the history_asset_payment_stats table: the
+// number of successful payments delivering an asset during a day (UTC), and
+// the sum of the amounts delivered in stroops.
+type AssetPaymentStat struct {
+	AssetType   xdr.AssetType `db:"asset_type"`
+	AssetCode   string        `db:"asset_code"`
+	AssetIssuer string        `db:"asset_issuer"`
+	Day         time.Time     `db:"day"`
+	Payments    int64         `db:"payments"`
+	Volume      string        `db:"volume"`
+}
+
+// QAssetPaymentStats defines the asset payment stats queries used by
+// ingestion.
+type QAssetPaymentStats interface {
+	AddAssetPaymentStats(ctx context.Context, stats []AssetPaymentStat) error
+	RebuildAssetPaymentStats(ctx context.Context, fromLedger, toLedger uint32) error
+}
+
+// dayFormat formats days in the history_asset_payment_stats table. Days are
+// sent as strings so they do not depend on the time zone of the session.
+const dayFormat = "2006-01-02"
+
+// AddAssetPaymentStats adds the payments and volumes of the given stats to the
+// ones already recorded for their asset and day. There must be at most one
+// stat per asset and day.
+func (q *Q) AddAssetPaymentStats(ctx context.Context, stats []AssetPaymentStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	sql := sq.Insert("history_asset_payment_stats").
+		Columns("asset_type", "asset_code", "asset_issuer", "day", "payments", "volume")
+	for _, stat := range stats {
+		sql = sql.Values(
+			stat.AssetType,
+			stat.AssetCode,
+			stat.AssetIssuer,
+			stat.Day.UTC().Format(dayFormat),
+			stat.Payments,
+			stat.Volume,
+		)
+	}
+	sql = sql.Suffix(`ON CONFLICT (asset_type, asset_code, asset_issuer, day) DO UPDATE SET
+		payments = history_asset_payment_stats.payments + excluded.payments,
+		volume = history_asset_payment_stats.volume + excluded.volume`)
+
+	if _, err := q.Exec(context.WithValue(ctx, &db.QueryTypeContextKey, db.UpsertQueryType), sql); err != nil {
+		return errors.Wrap(err, "could not add asset payment stats")
+	}
+	return nil
+}
+
+// RebuildAssetPaymentStats recomputes from history_operations the payment
+// stats of the days in which the ledgers from fromLedger to toLedger
+// (inclusive) closed. It is used after reingesting the ledgers, whose
+// payments were added again to the stats of their days.
+//
+// The stats of a day are only complete if all the ledgers of the day are in
+// the history tables: days partially removed by the reaper lose the payments
+// of the removed ledgers.
+func (q *Q) RebuildAssetPaymentStats(ctx context.Context, fromLedger, toLedger uint32) error {
+	var days struct {
+		From null.String `db:"from_day"`
+		To   null.String `db:"to_day"`
+	}
+	sql := sq.Select(
+		"to_char(min(closed_at), 'YYYY-MM-DD') AS from_day",
+		"to_char(max(closed_at) + interval '1 day', 'YYYY-MM-DD') AS to_day",
+	).
+		From("history_ledgers").
+		Where("sequence BETWEEN ? AND ?", fromLedger, toLedger)
+	if err := q.Get(ctx, &days, sql); err != nil {
+		return errors.Wrap(err, "could not load days of ledgers")
+	}
+	if !days.From.Valid {
+		return nil
+	}
+
+	var ledgers struct {
+		First int32 `db:"first"`
+		Last  int32 `db:"last"`
+	}
+	sql = sq.Select("min(sequence) AS first", "max(sequence) AS last").
+		From("history_ledgers").
+		Where("closed_at >= ?::date AND closed_at < ?::date", days.From.String, days.To.String)
+	if err := q.Get(ctx, &ledgers, sql); err != nil {
+		return errors.Wrap(err, "could not load ledgers of days")
+	}
+	start, end, err := toid.LedgerRangeInclusive(ledgers.First, ledgers.Last)
+	if err != nil {
+		return errors.Wrap(err, "invalid ledger range")
+	}
+
+	_, err = q.Exec(ctx, sq.Delete("history_asset_payment_stats").
+		Where("day >= ?::date AND day < ?::date", days.From.String, days.To.String))
+	if err != nil {
+		return errors.Wrap(err, "could not delete asset payment stats")
+	}
+
+	// Concurrent rebuilds of the same days, by parallel reingestion workers,
+	// overwrite each other's rows instead of failing on the primary key.
+	_, err = q.ExecRaw(context.WithValue(ctx, &db.QueryTypeContextKey, db.UpsertQueryType), `
+		INSERT INTO history_asset_payment_stats (asset_type, asset_code, asset_issuer, day, payments, volume)
+		SELECT
+			CASE hop.details->>'asset_type'
+				WHEN 'native' THEN 0
+				WHEN 'credit_alphanum4' THEN 1
+				ELSE 2
+			END,
+			COALESCE(hop.details->>'asset_code', ''),
+			COALESCE(hop.details->>'asset_issuer', ''),
+			hl.closed_at::date,
+			count(*),
+			sum(((hop.details->>'amount')::numeric * 10000000)::bigint)
+		FROM history_operations hop
+		JOIN history_transactions ht ON ht.id = hop.transaction_id
+		JOIN history_ledgers hl ON hl.sequence = (hop.id >> 32)
+		WHERE hop.id >= $1 AND hop.id < $2
+		AND hop.type IN ($3, $4, $5)
+		AND (ht.successful = true OR ht.successful IS NULL)
+		GROUP BY 1, 2, 3, 4
+		ON CONFLICT (asset_type, asset_code, asset_issuer, day) DO UPDATE SET
+			payments = excluded.payments,
+			volume = excluded.volume`,
+		start, end,
+		AssetPaymentStatsOperationTypes[0],
+		AssetPaymentStatsOperationTypes[1],
+		AssetPaymentStatsOperationTypes[2],
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not rebuild asset payment stats")
+	}
+	return nil
+}
+
+// GetAssetPaymentStats returns, in chronological order, the payment stats of
+// an asset for the days from `from` (inclusive) to `to` (exclusive), UTC.
+// Days without payments are omitted.
+func (q *Q) GetAssetPaymentStats(ctx context.Context, asset xdr.Asset, from, to time.Time) ([]AssetPaymentStat, error) {
+	var assetType xdr.AssetType
+	var code, issuer string
+	if err := asset.Extract(&assetType, &code, &issuer); err != nil {
+		return nil, errors.Wrap(err, "could not extract asset")
+	}
+
+	sql := sq.Select(
+		"asset_type",
+		"asset_code",
+		"asset_issuer",
+		"day",
+		"payments",
+		"volume",
+	).
+		From("history_asset_payment_stats").
+		Where(map[string]interface{}{
+			"asset_type":   assetType,
+			"asset_code":   code,
+			"asset_issuer": issuer,
+		}).
+		Where("day >= ?::date AND day < ?::date", from.UTC().Format(dayFormat), to.UTC().Format(dayFormat)).
+		OrderBy("day asc")
+
+	var stats []AssetPaymentStat
+	if err := q.Select(ctx, &stats, sql); err != nil {
+		return nil, errors.Wrap(err, "could not load asset payment stats")
+	}
+	return stats, nil
+}
@@ -0,0 +1,59 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/xdr"
+)
+
+func TestAddAssetPaymentStats(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	issuer := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	usd := xdr.MustNewCreditAsset("USD", issuer)
+	day := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, q.AddAssetPaymentStats(tt.Ctx, []AssetPaymentStat{
+		{AssetType: xdr.AssetTypeAssetTypeCreditAlphanum4, AssetCode: "USD", AssetIssuer: issuer, Day: day, Payments: 2, Volume: "300"},
+		{AssetType: xdr.AssetTypeAssetTypeNative, Day: day, Payments: 1, Volume: "10"},
+	}))
+	require.NoError(t, q.AddAssetPaymentStats(tt.Ctx, []AssetPaymentStat{
+		{AssetType: xdr.AssetTypeAssetTypeCreditAlphanum4, AssetCode: "USD", AssetIssuer: issuer, Day: day, Payments: 1, Volume: "92233720368547758070"},
+		{AssetType: xdr.AssetTypeAssetTypeCreditAlphanum4, AssetCode: "USD", AssetIssuer: issuer, Day: day.AddDate(0, 0, 1), Payments: 4, Volume: "40"},
+	}))
+
+	stats, err := q.GetAssetPaymentStats(tt.Ctx, usd, day, day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.True(t, day.Equal(stats[0].Day))
+	assert.Equal(t, int64(3), stats[0].Payments)
+	assert.Equal(t, "92233720368547758370", stats[0].Volume)
+	assert.True(t, day.AddDate(0, 0, 1).Equal(stats[1].Day))
+	assert.Equal(t, int64(4), stats[1].Payments)
+	assert.Equal(t, "40", stats[1].Volume)
+
+	// the end of the range is exclusive
+	stats, err = q.GetAssetPaymentStats(tt.Ctx, usd, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(3), stats[0].Payments)
+
+	stats, err = q.GetAssetPaymentStats(tt.Ctx, xdr.MustNewNativeAsset(), day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "10", stats[0].Volume)
+
+	// rebuilding the stats of ledgers missing from history_ledgers is a no-op
+	require.NoError(t, q.RebuildAssetPaymentStats(tt.Ctx, 100, 200))
+	stats, err = q.GetAssetPaymentStats(tt.Ctx, usd, day, day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	assert.Len(t, stats, 2)
+}
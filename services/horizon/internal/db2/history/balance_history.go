@@ -0,0 +1,124 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
+
+	"github.com/stellar/go/services/horizon/internal/toid"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// BalanceEffectTypes are the effect types which change the balance of the
+// account they are attached to.
+var BalanceEffectTypes = []EffectType{
+	EffectAccountCreated,
+	EffectAccountCredited,
+	EffectAccountDebited,
+	EffectTrade,
+}
+
+// BalanceEffect is an effect which changes the balance of an account, along
+// with the operation and ledger data required to place it in time.
+type BalanceEffect struct {
+	HistoryOperationID     int64             `db:"history_operation_id"`
+	Order                  int32             `db:"order"`
+	Type                   EffectType        `db:"type"`
+	DetailsString          null.String       `db:"details"`
+	OperationType          xdr.OperationType `db:"operation_type"`
+	OperationSourceAccount string            `db:"operation_source_account"`
+	LedgerCloseTime        time.Time         `db:"ledger_close_time"`
+}
+
+// UnmarshalDetails unmarshals the details of this effect into `dest`
+func (r *BalanceEffect) UnmarshalDetails(dest interface{}) error {
+	e := Effect{DetailsString: r.DetailsString}
+	return e.UnmarshalDetails(dest)
+}
+
+// FeeCharge is a fee paid by an account for a transaction.
+type FeeCharge struct {
+	TransactionID   int64     `db:"transaction_id"`
+	FeeCharged      int64     `db:"fee_charged"`
+	LedgerCloseTime time.Time `db:"ledger_close_time"`
+}
+
+// BalanceEffectsForAccount loads, in reverse chronological order, at most
+// `limit` effects which changed the balances of the given history account,
+// starting before the effect (`beforeOperationID`, `beforeOrder`) and ending
+// at ledger `minLedger`. The query walks the history_account_id index of
+// history_effects.
+func (q *Q) BalanceEffectsForAccount(
+	ctx context.Context,
+	accountID int64,
+	beforeOperationID int64,
+	beforeOrder int32,
+	minLedger int32,
+	limit uint64,
+) ([]BalanceEffect, error) {
+	sql := sq.Select(
+		"heff.history_operation_id",
+		"heff.order",
+		"heff.type",
+		"heff.details",
+		"hop.type AS operation_type",
+		"hop.source_account AS operation_source_account",
+		"hl.closed_at AS ledger_close_time",
+	).
+		From("history_effects heff").
+		Join("history_operations hop ON hop.id = heff.history_operation_id").
+		Join("history_ledgers hl ON hl.sequence = (heff.history_operation_id >> 32)").
+		Where("heff.history_account_id = ?", accountID).
+		Where(`(heff.history_operation_id, heff."order") < (?, ?)`, beforeOperationID, beforeOrder).
+		Where("heff.history_operation_id >= ?", toid.New(minLedger, 0, 0).ToInt64()).
+		Where(sq.Eq{"heff.type": BalanceEffectTypes}).
+		OrderBy(`heff.history_operation_id desc, heff."order" desc`).
+		Limit(limit)
+
+	var effects []BalanceEffect
+	if err := q.Select(ctx, &effects, sql); err != nil {
+		return nil, errors.Wrap(err, "could not load balance effects")
+	}
+	return effects, nil
+}
+
+// FeeChargesForAccount loads, in reverse chronological order, at most
+// `limit` fees paid by `address`, the address of the history account
+// `accountID`, for the transactions before `beforeTransactionID` and
+// starting at ledger `minLedger`. The transactions are found through the
+// history_account_id index of history_transaction_participants, fee bump
+// fee accounts being participants of their transactions.
+func (q *Q) FeeChargesForAccount(
+	ctx context.Context,
+	accountID int64,
+	address string,
+	beforeTransactionID int64,
+	minLedger int32,
+	limit uint64,
+) ([]FeeCharge, error) {
+	sql := sq.Select(
+		"ht.id AS transaction_id",
+		"COALESCE(ht.fee_charged, ht.max_fee) AS fee_charged",
+		"hl.closed_at AS ledger_close_time",
+	).
+		From("history_transaction_participants htp").
+		Join("history_transactions ht ON ht.id = htp.history_transaction_id").
+		Join("history_ledgers hl ON hl.sequence = ht.ledger_sequence").
+		Where("htp.history_account_id = ?", accountID).
+		Where("htp.history_transaction_id < ?", beforeTransactionID).
+		Where("htp.history_transaction_id >= ?", toid.New(minLedger, 0, 0).ToInt64()).
+		// The fee account of a transaction is either its fee bump account or
+		// its source account.
+		Where("(ht.fee_account = ? OR (ht.fee_account IS NULL AND ht.account = ?))", address, address).
+		OrderBy("htp.history_transaction_id desc").
+		Limit(limit)
+
+	var fees []FeeCharge
+	if err := q.Select(ctx, &fees, sql); err != nil {
+		return nil, errors.Wrap(err, "could not load fee charges")
+	}
+	return fees, nil
+}
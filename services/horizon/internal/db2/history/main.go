@@ -221,6 +221,7 @@ type AccountsBatchInsertBuilder interface {
 
 type IngestionQ interface {
 	QAccounts
+	QAssetPaymentStats
 	QAssetStats
 	QClaimableBalances
 	QHistoryClaimableBalances
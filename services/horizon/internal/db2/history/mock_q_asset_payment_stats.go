@@ -0,0 +1,23 @@
+package history
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQAssetPaymentStats is a mock implementation of the QAssetPaymentStats
+// interface
+type MockQAssetPaymentStats struct {
+	mock.Mock
+}
+
+func (m *MockQAssetPaymentStats) AddAssetPaymentStats(ctx context.Context, stats []AssetPaymentStat) error {
+	a := m.Called(ctx, stats)
+	return a.Error(0)
+}
+
+func (m *MockQAssetPaymentStats) RebuildAssetPaymentStats(ctx context.Context, fromLedger, toLedger uint32) error {
+	a := m.Called(ctx, fromLedger, toLedger)
+	return a.Error(0)
+}
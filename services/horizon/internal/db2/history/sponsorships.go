@@ -0,0 +1,295 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/support/errors"
+)
+
+// The types of the ledger entries, and subentries, which can be sponsored by
+// an account on behalf of another one.
+const (
+	SponsoredAccount   = "account"
+	SponsoredData      = "data"
+	SponsoredOffer     = "offer"
+	SponsoredSigner    = "signer"
+	SponsoredTrustline = "trustline"
+	// SponsoredClaimableBalance entries are only counted by
+	// SponsorshipCounts, as they are not owned by an account. They are listed
+	// by GetClaimableBalances.
+	SponsoredClaimableBalance = "claimable_balance"
+)
+
+// sponsorshipTable describes how the sponsored entries of a state table are
+// selected as sponsorships.
+type sponsorshipTable struct {
+	entryType string
+	table     string
+	// accountColumn is the column of the account owning the entry.
+	accountColumn string
+	// idColumn is the expression identifying the entry among the entries of
+	// its type owned by the account.
+	idColumn string
+}
+
+// sponsorshipTables are sorted by entry type, which is the first column of
+// the order of sponsorships.
+var sponsorshipTables = []sponsorshipTable{
+	{SponsoredAccount, "accounts", "account_id", "account_id"},
+	{SponsoredData, "accounts_data", "account_id", "name"},
+	{SponsoredOffer, "offers", "seller_id", "offer_id::text"},
+	{SponsoredSigner, "accounts_signers", "account_id", "signer"},
+	{SponsoredTrustline, "trust_lines", "account_id", "asset_code || ':' || asset_issuer"},
+}
+
+// Sponsorship is a ledger entry, or subentry, of an account whose reserve is
+// paid by a sponsor.
+type Sponsorship struct {
+	EntryType string `db:"entry_type"`
+	AccountID string `db:"account_id"`
+	// EntryID identifies the entry among the entries of its type owned by
+	// the account: the signer key of signers, the `code:issuer` asset of
+	// trustlines, the name of data entries and the id of offers.
+	EntryID string `db:"entry_id"`
+	Sponsor string `db:"sponsor"`
+}
+
+// PagingToken returns a cursor for this sponsorship.
+func (s Sponsorship) PagingToken() string {
+	return fmt.Sprintf("%s-%s-%s", s.EntryType, s.AccountID, s.EntryID)
+}
+
+// SponsorshipsQuery is a helper struct to configure queries to sponsorships.
+// Exactly one of Sponsor and AccountID must be set.
+type SponsorshipsQuery struct {
+	PageQuery db2.PageQuery
+	// Sponsor restricts the sponsorships to the entries sponsored by the
+	// account.
+	Sponsor string
+	// AccountID restricts the sponsorships to the entries owned by the
+	// account.
+	AccountID string
+}
+
+// Cursor validates and returns the query page cursor, made of the entry type,
+// account and entry id of a sponsorship.
+func (q SponsorshipsQuery) Cursor() (entryType, accountID, entryID string, err error) {
+	if q.PageQuery.Cursor == "" {
+		return "", "", "", nil
+	}
+
+	parts := strings.SplitN(q.PageQuery.Cursor, "-", 3)
+	if len(parts) != 3 {
+		return "", "", "", errors.New("Invalid cursor")
+	}
+	for _, table := range sponsorshipTables {
+		if table.entryType == parts[0] {
+			return parts[0], parts[1], parts[2], nil
+		}
+	}
+	return "", "", "", errors.Errorf("Invalid cursor - unknown entry type %s", parts[0])
+}
+
+func (q SponsorshipsQuery) filter(table sponsorshipTable) (sq.Eq, error) {
+	switch {
+	case q.Sponsor != "" && q.AccountID == "":
+		return sq.Eq{"sponsor": q.Sponsor}, nil
+	case q.AccountID != "" && q.Sponsor == "":
+		return sq.Eq{table.accountColumn: q.AccountID}, nil
+	default:
+		return nil, errors.New("exactly one of sponsor and account id must be set")
+	}
+}
+
+// GetSponsorships returns a page of sponsorships, ordered by entry type,
+// account and entry id. The cursor and limit are applied to the query of
+// each table so that sponsors of many entries can be paged efficiently.
+func (q *Q) GetSponsorships(ctx context.Context, query SponsorshipsQuery) ([]Sponsorship, error) {
+	cursorType, cursorAccount, cursorID, err := query.Cursor()
+	if err != nil {
+		return nil, err
+	}
+
+	var op string
+	switch query.PageQuery.Order {
+	case db2.OrderAscending:
+		op = ">"
+	case db2.OrderDescending:
+		op = "<"
+	default:
+		return nil, errors.Errorf("invalid order: %s", query.PageQuery.Order)
+	}
+
+	var selectSponsorships sq.SelectBuilder
+	empty := true
+	for _, table := range sponsorshipTables {
+		if cursorType != "" {
+			// skip the tables before the cursor in the requested order
+			if (op == ">" && table.entryType < cursorType) || (op == "<" && table.entryType > cursorType) {
+				continue
+			}
+		}
+
+		filter, err := query.filter(table)
+		if err != nil {
+			return nil, err
+		}
+		sql := sq.
+			Select(
+				fmt.Sprintf("'%s' AS entry_type", table.entryType),
+				table.accountColumn+" AS account_id",
+				table.idColumn+" AS entry_id",
+				"sponsor",
+			).
+			From(table.table).
+			Where(filter).
+			Where("sponsor IS NOT NULL")
+		if table.entryType == cursorType {
+			sql = sql.Where(
+				fmt.Sprintf("(%s, %s) %s (?, ?)", table.accountColumn, table.idColumn, op),
+				cursorAccount, cursorID,
+			)
+		}
+		sql = sql.
+			OrderBy(
+				table.accountColumn+" "+query.PageQuery.Order,
+				table.idColumn+" "+query.PageQuery.Order,
+			).
+			Limit(query.PageQuery.Limit).
+			Prefix("(").
+			Suffix(")")
+
+		if empty {
+			selectSponsorships = sql
+			empty = false
+			continue
+		}
+		sqlStr, args, err := sql.ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not construct sponsorships query")
+		}
+		selectSponsorships = selectSponsorships.Suffix("UNION ALL "+sqlStr, args...)
+	}
+
+	var results []Sponsorship
+	if empty {
+		return results, nil
+	}
+
+	order := query.PageQuery.Order
+	sql := sq.
+		Select("*").
+		FromSelect(selectSponsorships, "sponsorships").
+		OrderBy(
+			"sponsorships.entry_type "+order,
+			"sponsorships.account_id "+order,
+			"sponsorships.entry_id "+order,
+		).
+		Limit(query.PageQuery.Limit)
+	if err := q.Select(ctx, &results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	return results, nil
+}
+
+// SponsorshipCounts returns the number of sponsored entries of each type
+// matching the query, ignoring its page. Claimable balances are counted when
+// the query is restricted to a sponsor.
+func (q *Q) SponsorshipCounts(ctx context.Context, query SponsorshipsQuery) (map[string]uint32, error) {
+	var selectCounts sq.SelectBuilder
+	for i, table := range sponsorshipTables {
+		filter, err := query.filter(table)
+		if err != nil {
+			return nil, err
+		}
+		sql := sq.
+			Select(fmt.Sprintf("'%s' AS entry_type", table.entryType), "count(*) AS count").
+			From(table.table).
+			Where(filter).
+			Where("sponsor IS NOT NULL")
+
+		if i == 0 {
+			selectCounts = sql
+			continue
+		}
+		sqlStr, args, err := sql.ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not construct sponsorship counts query")
+		}
+		selectCounts = selectCounts.Suffix("UNION ALL "+sqlStr, args...)
+	}
+
+	if query.Sponsor != "" {
+		sqlStr, args, err := sq.
+			Select(fmt.Sprintf("'%s' AS entry_type", SponsoredClaimableBalance), "count(*) AS count").
+			From("claimable_balances").
+			Where(sq.Eq{"sponsor": query.Sponsor}).
+			ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not construct sponsorship counts query")
+		}
+		selectCounts = selectCounts.Suffix("UNION ALL "+sqlStr, args...)
+	}
+
+	var rows []struct {
+		EntryType string `db:"entry_type"`
+		Count     uint32 `db:"count"`
+	}
+	if err := q.Select(ctx, &rows, selectCounts); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+
+	counts := map[string]uint32{}
+	for _, row := range rows {
+		counts[row.EntryType] = row.Count
+	}
+	return counts, nil
+}
+
+// SponsorCount is the number of entries of an account sponsored by a sponsor.
+type SponsorCount struct {
+	Sponsor string `db:"sponsor"`
+	Count   uint32 `db:"count"`
+}
+
+// SponsorsForAccount returns the sponsors of the entries owned by the
+// account, with the number of entries each sponsors, ordered by decreasing
+// count. As an account has at most 1000 subentries the result is not paged.
+func (q *Q) SponsorsForAccount(ctx context.Context, accountID string) ([]SponsorCount, error) {
+	var selectSponsors sq.SelectBuilder
+	for i, table := range sponsorshipTables {
+		sql := sq.
+			Select("sponsor").
+			From(table.table).
+			Where(sq.Eq{table.accountColumn: accountID}).
+			Where("sponsor IS NOT NULL")
+
+		if i == 0 {
+			selectSponsors = sql
+			continue
+		}
+		sqlStr, args, err := sql.ToSql()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not construct sponsors query")
+		}
+		selectSponsors = selectSponsors.Suffix("UNION ALL "+sqlStr, args...)
+	}
+
+	sql := sq.
+		Select("sponsors.sponsor", "count(*) AS count").
+		FromSelect(selectSponsors, "sponsors").
+		GroupBy("sponsors.sponsor").
+		OrderBy("count DESC", "sponsors.sponsor ASC")
+
+	var results []SponsorCount
+	if err := q.Select(ctx, &results, sql); err != nil {
+		return nil, errors.Wrap(err, "could not run select query")
+	}
+	return results, nil
+}
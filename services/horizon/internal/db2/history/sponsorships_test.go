@@ -0,0 +1,86 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stellar/go/services/horizon/internal/db2"
+	"github.com/stellar/go/services/horizon/internal/test"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetSponsorships(t *testing.T) {
+	tt := test.Start(t)
+	defer tt.Finish()
+	test.ResetHorizonDB(t, tt.HorizonDB)
+	q := &Q{tt.HorizonSession()}
+
+	address1 := account1.Data.Account.AccountId.Address()
+	address2 := account2.Data.Account.AccountId.Address()
+	sponsorAddress := sponsor.Address()
+	eurTrustLine.Data.TrustLine.AccountId = account1.Data.Account.AccountId
+
+	tt.Assert.NoError(q.UpsertAccounts(tt.Ctx, []xdr.LedgerEntry{account1, account2}))
+	_, err := q.InsertTrustLine(tt.Ctx, eurTrustLine)
+	tt.Assert.NoError(err)
+	_, err = q.CreateAccountSigner(tt.Ctx, address1, address2, 1, &sponsorAddress)
+	tt.Assert.NoError(err)
+	_, err = q.CreateAccountSigner(tt.Ctx, address2, address1, 1, nil)
+	tt.Assert.NoError(err)
+
+	accountSponsorship := Sponsorship{SponsoredAccount, address2, address2, sponsorAddress}
+	signerSponsorship := Sponsorship{SponsoredSigner, address1, address2, sponsorAddress}
+	trustlineSponsorship := Sponsorship{
+		SponsoredTrustline, address1, "EUR:" + trustLineIssuer.Address(), sponsorAddress,
+	}
+
+	query := SponsorshipsQuery{
+		PageQuery: db2.PageQuery{Order: db2.OrderAscending, Limit: 2},
+		Sponsor:   sponsorAddress,
+	}
+	sponsorships, err := q.GetSponsorships(tt.Ctx, query)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]Sponsorship{accountSponsorship, signerSponsorship}, sponsorships)
+
+	query.PageQuery.Cursor = sponsorships[1].PagingToken()
+	sponsorships, err = q.GetSponsorships(tt.Ctx, query)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]Sponsorship{trustlineSponsorship}, sponsorships)
+
+	query.PageQuery.Order = db2.OrderDescending
+	sponsorships, err = q.GetSponsorships(tt.Ctx, query)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]Sponsorship{accountSponsorship}, sponsorships)
+
+	query = SponsorshipsQuery{
+		PageQuery: db2.PageQuery{Order: db2.OrderAscending, Limit: 10},
+		AccountID: address1,
+	}
+	sponsorships, err = q.GetSponsorships(tt.Ctx, query)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]Sponsorship{signerSponsorship, trustlineSponsorship}, sponsorships)
+
+	counts, err := q.SponsorshipCounts(tt.Ctx, SponsorshipsQuery{Sponsor: sponsorAddress})
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(map[string]uint32{
+		SponsoredAccount:          1,
+		SponsoredData:             0,
+		SponsoredOffer:            0,
+		SponsoredSigner:           1,
+		SponsoredTrustline:        1,
+		SponsoredClaimableBalance: 0,
+	}, counts)
+
+	sponsors, err := q.SponsorsForAccount(tt.Ctx, address1)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]SponsorCount{{sponsorAddress, 2}}, sponsors)
+
+	sponsors, err = q.SponsorsForAccount(tt.Ctx, address2)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal([]SponsorCount{{sponsorAddress, 1}}, sponsors)
+
+	_, err = q.GetSponsorships(tt.Ctx, SponsorshipsQuery{
+		PageQuery: db2.PageQuery{Order: db2.OrderAscending, Limit: 10, Cursor: "ledger-a-b"},
+		Sponsor:   sponsorAddress,
+	})
+	tt.Assert.EqualError(err, "Invalid cursor - unknown entry type ledger")
+}
@@ -41,6 +41,7 @@
 // migrations/44_asset_stat_accounts_and_balances.sql (439B)
 // migrations/45_add_claimable_balances_history.sql (2.163kB)
 // migrations/46_add_muxed_accounts.sql (465B)
+// migrations/47_add_asset_payment_stats.sql (513B)
 // migrations/4_add_protocol_version.sql (188B)
 // migrations/5_create_trades_table.sql (1.1kB)
 // migrations/6_create_assets_table.sql (366B)
@@ -936,6 +937,26 @@ func migrations46_add_muxed_accountsSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations47_add_asset_payment_statsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x91\xcf\x6b\xc2\x30\x14\xc7\xef\xf9\x2b\xde\x31\x65\xed\xc1\xc9\x06\xc3\x53\x6c\x83\x0b\xab\xa9\x64\xed\xd0\x53\x88\x1a\xb6\x82\xb5\xa5\x89\x1b\xf9\xef\xd7\x6c\xb3\xab\x22\xfa\x6e\xe1\x7d\xc8\xf7\xc7\x8b\x22\xb8\xab\xca\xf7\x56\x59\x0d\x45\x83\x50\x2c\x28\xc9\x29\xe4\x64\x9a\x52\xf8\x28\x8d\xad\x5b\x27\x95\x31\xda\xca\x46\xb9\x4a\xef\xad\x34\x56\x59\x03\x18\x41\x37\xbf\x1b\xeb\x1a\xdd\x3d\x18\xcf\xe1\x38\x3c\xcb\x81\x17\x69\x1a\x0e\xb0\x4d\xbd\xf5\xd8\x1b\x11\xf1\x33\x11\x78\x74\x1f\x5c\xc4\x4a\x63\x0e\xba\xed\xb1\x87\xc7\x73\x6c\xab\x5c\xaf\x03\x89\xb7\x7b\x51\xf4\xcf\xaf\xf9\x59\x4d\xd9\xac\xb7\x77\x8a\x7d\xd6\xbb\x43\xa5\x8f\x3f\x14\x73\x2a\x58\x8c\xc7\x4f\xe7\xa2\x0b\xc1\xe6\x44\xac\xe0\x85\xae\xf0\x7f\xea\x70\x10\x2d\x3c\xf1\x1f\x7a\x9b\x01\x0a\x26\x7d\xa7\x8c\x27\x74\x79\xad\x53\xb9\x76\xd2\x67\xcb\xf8\xd5\xe6\x8b\x57\xc6\x67\xb0\xb6\xad\xd6\x80\xbd\x48\x27\x11\x0d\xce\x98\xd4\x5f\x7b\x84\x12\x91\x2d\x6e\x9f\x71\x82\xbe\x01\x3c\x2f\xf9\xa0\x01\x02\x00\x00")
+
+func migrations47_add_asset_payment_statsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations47_add_asset_payment_statsSql,
+		"migrations/47_add_asset_payment_stats.sql",
+	)
+}
+
+func migrations47_add_asset_payment_statsSql() (*asset, error) {
+	bytes, err := migrations47_add_asset_payment_statsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/47_add_asset_payment_stats.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa9, 0x67, 0xe, 0xed, 0x3, 0xda, 0x15, 0xab, 0x14, 0x26, 0x48, 0x66, 0x1, 0x69, 0x89, 0x5e, 0xc2, 0x8, 0xad, 0xe4, 0x28, 0x92, 0x6d, 0x58, 0xa, 0x55, 0x9f, 0xe8, 0x8a, 0x47, 0xeb, 0xcb}}
+	return a, nil
+}
+
 var _migrations4_add_protocol_versionSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xcd\xb1\x0a\xc2\x30\x10\x06\xe0\x3d\x4f\xf1\xef\x52\x70\xef\x14\x4d\x9d\xce\x44\x4a\x32\x38\x15\xd1\xa3\x06\x6a\xae\x5c\x82\xe2\xdb\xbb\xba\x88\x4f\xf0\x75\x1d\x36\x8f\x3c\xeb\xa5\x31\xd2\x6a\x2c\xc5\x61\x44\xb4\x3b\x1a\x10\x3c\x9d\x71\xcf\xb5\x89\xbe\xa7\x85\x6f\x33\x6b\x85\x01\xac\x73\xd8\x07\x4a\x47\x8f\x55\xa5\xc9\x55\x96\xe9\xc9\x5a\xb3\x14\xe4\xd2\x78\x66\x85\x1b\x0e\x36\x51\xc4\x16\x3e\x44\xf8\x44\xd4\x1b\xf3\x6d\x39\x79\x95\xff\x9a\x1b\xc3\xe9\x97\xd5\x9b\x4f\x00\x00\x00\xff\xff\x83\xbb\x30\x2e\xbc\x00\x00\x00")
 
 func migrations4_add_protocol_versionSqlBytes() ([]byte, error) {
@@ -1208,6 +1229,7 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/44_asset_stat_accounts_and_balances.sql":                 migrations44_asset_stat_accounts_and_balancesSql,
 	"migrations/45_add_claimable_balances_history.sql":                   migrations45_add_claimable_balances_historySql,
 	"migrations/46_add_muxed_accounts.sql":                               migrations46_add_muxed_accountsSql,
+	"migrations/47_add_asset_payment_stats.sql":                          migrations47_add_asset_payment_statsSql,
 	"migrations/4_add_protocol_version.sql":                              migrations4_add_protocol_versionSql,
 	"migrations/5_create_trades_table.sql":                               migrations5_create_trades_tableSql,
 	"migrations/6_create_assets_table.sql":                               migrations6_create_assets_tableSql,
@@ -1300,6 +1322,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"44_asset_stat_accounts_and_balances.sql":                 &bintree{migrations44_asset_stat_accounts_and_balancesSql, map[string]*bintree{}},
 		"45_add_claimable_balances_history.sql":                   &bintree{migrations45_add_claimable_balances_historySql, map[string]*bintree{}},
 		"46_add_muxed_accounts.sql":                               &bintree{migrations46_add_muxed_accountsSql, map[string]*bintree{}},
+		"47_add_asset_payment_stats.sql":                          &bintree{migrations47_add_asset_payment_statsSql, map[string]*bintree{}},
 		"4_add_protocol_version.sql":                              &bintree{migrations4_add_protocol_versionSql, map[string]*bintree{}},
 		"5_create_trades_table.sql":                               &bintree{migrations5_create_trades_tableSql, map[string]*bintree{}},
 		"6_create_assets_table.sql":                               &bintree{migrations6_create_assets_tableSql, map[string]*bintree{}},
@@ -0,0 +1,17 @@
+-- +migrate Up
+
+CREATE TABLE history_asset_payment_stats (
+    asset_type   INT         NOT NULL,
+    asset_code   VARCHAR(12) NOT NULL,
+    asset_issuer VARCHAR(56) NOT NULL,
+    day          DATE        NOT NULL,
+    payments     BIGINT      NOT NULL,
+    volume       NUMERIC(39) NOT NULL,
+    PRIMARY KEY(asset_type, asset_code, asset_issuer, day)
+);
+
+CREATE INDEX history_asset_payment_stats_by_day ON history_asset_payment_stats USING btree (day);
+
+-- +migrate Down
+
+DROP TABLE history_asset_payment_stats;
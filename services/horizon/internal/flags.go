@@ -7,11 +7,14 @@ import (
 	stdLog "log"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/stellar/go/services/horizon/internal/db2/schema"
+	"github.com/stellar/go/services/horizon/internal/httpx"
+	"github.com/stellar/go/services/horizon/internal/ingest/processors"
 	apkg "github.com/stellar/go/support/app"
 	support "github.com/stellar/go/support/config"
 	"github.com/stellar/go/support/db"
@@ -96,6 +99,7 @@ func Flags() (*Config, support.ConfigOptions) {
 			OptType:   types.String,
 			Required:  true,
 			Usage:     "horizon postgres database to connect with",
+			Secret:    true,
 		},
 		&support.ConfigOption{
 			Name:      "ro-database-url",
@@ -103,6 +107,15 @@ func Flags() (*Config, support.ConfigOptions) {
 			OptType:   types.String,
 			Required:  false,
 			Usage:     "horizon postgres read-replica to connect with, when set it will return stale history error when replica is behind primary",
+			Secret:    true,
+		},
+		&support.ConfigOption{
+			Name:           "ro-database-max-lag",
+			ConfigKey:      &config.RoDatabaseMaxLag,
+			OptType:        types.Int,
+			FlagDefault:    0,
+			CustomSetValue: support.SetDuration,
+			Usage:          "replication lag (in seconds) of the read-replica (see --ro-database-url) above which reads are routed to the primary database, instead of returning stale history errors when the replica is behind the primary, 0 disables the routing",
 		},
 		&support.ConfigOption{
 			Name:        StellarCoreBinaryPathName,
@@ -206,6 +219,7 @@ func Flags() (*Config, support.ConfigOptions) {
 			OptType:   types.String,
 			Required:  false,
 			Usage:     "stellar-core postgres database to connect with",
+			Secret:    true,
 		},
 		&support.ConfigOption{
 			Name:      StellarCoreURLFlagName,
@@ -278,6 +292,88 @@ func Flags() (*Config, support.ConfigOptions) {
 			CustomSetValue: support.SetDuration,
 			Usage:          "defines the timeout of connection after which 504 response will be sent or stream will be closed, if Horizon is behind a load balancer with idle connection timeout, this should be set to a few seconds less that idle timeout, does not apply to POST /transactions",
 		},
+		&support.ConfigOption{
+			Name:           "slow-query-threshold",
+			ConfigKey:      &config.SlowQueryThreshold,
+			OptType:        types.Int,
+			FlagDefault:    0,
+			CustomSetValue: support.SetDuration,
+			Usage:          "defines the duration (in seconds) above which DB queries are logged as slow (with their parameters redacted), 0 disables the slow query log",
+		},
+		&support.ConfigOption{
+			Name:           "submission-idempotency-window",
+			ConfigKey:      &config.SubmissionIdempotencyWindow,
+			OptType:        types.Int,
+			FlagDefault:    300,
+			CustomSetValue: support.SetDuration,
+			Usage:          "defines how long (in seconds) the result of a transaction submitted with an Idempotency-Key header is returned to retried submissions using the same key, 0 disables idempotent submissions",
+		},
+		&support.ConfigOption{
+			Name:        "response-cache-size",
+			ConfigKey:   &config.ResponseCacheSize,
+			OptType:     types.Int,
+			FlagDefault: 0,
+			Usage:       "number of ledger, transaction and operation responses cached in memory, these resources never change once ingested, 0 disables the cache",
+		},
+		&support.ConfigOption{
+			Name:        "latency-budgets",
+			ConfigKey:   &config.LatencyBudgets,
+			OptType:     types.String,
+			FlagDefault: "",
+			CustomSetValue: func(co *support.ConfigOption) {
+				budgets, err := httpx.ParseLatencyBudgets(viper.GetString(co.Name))
+				if err != nil {
+					stdLog.Fatalf("Invalid --%s: %v", co.Name, err)
+				}
+				*(co.ConfigKey.(*[]httpx.LatencyBudget)) = budgets
+			},
+			Usage: "comma-separated list of route=latency latency budgets (e.g. /accounts/{account_id}=500ms,/paths/strict-send=2s), the state of which is reported by the /latency_budgets admin endpoint and the horizon_http_latency_budget_alarm metric",
+		},
+		&support.ConfigOption{
+			Name:        "latency-budget-target",
+			ConfigKey:   &config.LatencyBudgetTarget,
+			OptType:     types.String,
+			FlagDefault: "99",
+			CustomSetValue: func(co *support.ConfigOption) {
+				target, err := strconv.ParseFloat(viper.GetString(co.Name), 64)
+				if err != nil || target <= 0 || target > 100 {
+					stdLog.Fatalf("Invalid --%s: must be a percentage between 0 and 100", co.Name)
+				}
+				*(co.ConfigKey.(*float64)) = target
+			},
+			Usage: "percentage of the requests to a route which must be served within its latency budget, below which the route's latency budget alarm is raised",
+		},
+		&support.ConfigOption{
+			Name:           "latency-budget-window",
+			ConfigKey:      &config.LatencyBudgetWindow,
+			OptType:        types.Int,
+			FlagDefault:    600,
+			CustomSetValue: support.SetDuration,
+			Usage:          "sliding window (in seconds) over which requests are counted against latency budgets",
+		},
+		&support.ConfigOption{
+			Name:           "health-ingestion-lag-threshold",
+			ConfigKey:      &config.HealthIngestionLagThreshold,
+			OptType:        types.Int,
+			FlagDefault:    60,
+			CustomSetValue: support.SetDuration,
+			Usage:          "age (in seconds) of the latest ingested ledger above which /health reports Horizon as degraded because ingestion is lagging, 0 disables the check",
+		},
+		&support.ConfigOption{
+			Name:        "health-replica-lag-threshold",
+			ConfigKey:   &config.HealthReplicaLagThreshold,
+			OptType:     types.Uint,
+			FlagDefault: uint(5),
+			Usage:       "number of ledgers the replica database may be behind the primary database (see --ro-database-url) before /health reports Horizon as degraded, 0 disables the check",
+		},
+		&support.ConfigOption{
+			Name:           "drain-period",
+			ConfigKey:      &config.DrainPeriod,
+			OptType:        types.Int,
+			FlagDefault:    0,
+			CustomSetValue: support.SetDuration,
+			Usage:          "time (in seconds) during which Horizon fails /health but keeps serving requests when shutting down, so that load balancers stop sending it requests before it stops",
+		},
 		&support.ConfigOption{
 			Name:        "per-hour-rate-limit",
 			ConfigKey:   &config.RateQuota,
@@ -303,6 +399,43 @@ func Flags() (*Config, support.ConfigOptions) {
 			CustomSetValue: support.SetURL,
 			Usage:          "friendbot service to redirect to",
 		},
+		&support.ConfigOption{
+			Name:        "operator-name",
+			ConfigKey:   &config.OperatorName,
+			OptType:     types.String,
+			FlagDefault: "",
+			Usage:       "name of the organization operating this instance, advertised in the root resource",
+		},
+		&support.ConfigOption{
+			Name:        "operator-contact",
+			ConfigKey:   &config.OperatorContact,
+			OptType:     types.String,
+			FlagDefault: "",
+			Usage:       "contact (e.g. email or URL) of the operator of this instance, advertised in the root resource",
+		},
+		&support.ConfigOption{
+			Name:        "network-name",
+			ConfigKey:   &config.NetworkName,
+			OptType:     types.String,
+			FlagDefault: "",
+			Usage:       "human readable name of the network served by this instance (e.g. pubnet), advertised in the root resource",
+		},
+		&support.ConfigOption{
+			Name:        "supported-seps",
+			ConfigKey:   &config.SupportedSEPs,
+			OptType:     types.String,
+			FlagDefault: "",
+			CustomSetValue: func(co *support.ConfigOption) {
+				var seps []string
+				for _, sep := range strings.Split(viper.GetString(co.Name), ",") {
+					if sep = strings.TrimSpace(sep); sep != "" {
+						seps = append(seps, sep)
+					}
+				}
+				*(co.ConfigKey.(*[]string)) = seps
+			},
+			Usage: "comma-separated list of SEPs supported by services co-hosted with this instance (e.g. SEP-1,SEP-10), advertised in the root resource",
+		},
 		&support.ConfigOption{
 			Name:        "log-level",
 			ConfigKey:   &config.LogLevel,
@@ -350,12 +483,14 @@ func Flags() (*Config, support.ConfigOptions) {
 			ConfigKey: &config.SentryDSN,
 			OptType:   types.String,
 			Usage:     "Sentry URL to which panics and errors should be reported",
+			Secret:    true,
 		},
 		&support.ConfigOption{
 			Name:      "loggly-token",
 			ConfigKey: &config.LogglyToken,
 			OptType:   types.String,
 			Usage:     "Loggly token, used to configure log forwarding to loggly",
+			Secret:    true,
 		},
 		&support.ConfigOption{
 			Name:        "loggly-tag",
@@ -383,6 +518,14 @@ func Flags() (*Config, support.ConfigOptions) {
 			FlagDefault: false,
 			Usage:       "causes this horizon process to ingest data from stellar-core into horizon's db",
 		},
+		&support.ConfigOption{
+			Name:        "read-only-gateway",
+			ConfigKey:   &config.ReadOnlyGateway,
+			OptType:     types.Bool,
+			FlagDefault: false,
+			Required:    false,
+			Usage:       "serves the API from a horizon db maintained by other instances, without connecting to stellar-core (cannot be used with --ingest, transactions are only submitted if --stellar-core-url is set)",
+		},
 		&support.ConfigOption{
 			Name:        "cursor-name",
 			EnvVar:      "CURSOR_NAME",
@@ -419,6 +562,21 @@ func Flags() (*Config, support.ConfigOptions) {
 			FlagDefault: false,
 			Usage:       "ingestion system runs a verification routing to compare state in local database with history buckets, this can be disabled however it's not recommended",
 		},
+		&support.ConfigOption{
+			Name:        "ingest-markets",
+			ConfigKey:   &config.IngestMarketFilter,
+			OptType:     types.String,
+			FlagDefault: "",
+			Required:    false,
+			CustomSetValue: func(opt *support.ConfigOption) {
+				filter, err := processors.ParseMarketFilter(viper.GetString(opt.Name))
+				if err != nil {
+					stdLog.Fatalf("Invalid --%s: %s", opt.Name, err)
+				}
+				*(opt.ConfigKey.(**processors.MarketFilter)) = filter
+			},
+			Usage: "comma-separated list of asset pairs (e.g. native/USD:G...), only offers and trades in the markets of which are ingested. All markets are ingested if empty. Run `horizon ingest trigger-state-rebuild` after changing it",
+		},
 		&support.ConfigOption{
 			Name:        "apply-migrations",
 			ConfigKey:   &config.ApplyMigrations,
@@ -460,7 +618,13 @@ func Flags() (*Config, support.ConfigOptions) {
 func NewAppFromFlags(config *Config, flags support.ConfigOptions) *App {
 	ApplyFlags(config, flags, ApplyOptions{RequireCaptiveCoreConfig: true, AlwaysIngest: false})
 	// Validate app-specific arguments
-	if config.StellarCoreURL == "" {
+	if config.ReadOnlyGateway && config.Ingest {
+		log.Fatal("flags --read-only-gateway and --ingest cannot be used together")
+	}
+	if config.RoDatabaseMaxLag > 0 && config.RoDatabaseURL == "" {
+		log.Fatal("flag --ro-database-max-lag requires --ro-database-url")
+	}
+	if config.StellarCoreURL == "" && !config.ReadOnlyGateway {
 		log.Fatalf("flag --%s cannot be empty", StellarCoreURLFlagName)
 	}
 	if config.Ingest && !config.EnableCaptiveCoreIngestion && config.StellarCoreDatabaseURL == "" {
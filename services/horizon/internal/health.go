@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/support/clock"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/log"
@@ -19,12 +21,35 @@ const (
 	healthCacheTTL     = 500 * time.Millisecond
 )
 
+// Statuses reported by /health. Horizon fails the health check when it is
+// unhealthy or draining, but keeps receiving traffic when it is degraded.
+const (
+	healthStatusHealthy   = "healthy"
+	healthStatusDegraded  = "degraded"
+	healthStatusUnhealthy = "unhealthy"
+	healthStatusDraining  = "draining"
+)
+
+// Reasons reported by /health when Horizon is not healthy.
+const (
+	healthReasonDatabaseUnreachable = "database_unreachable"
+	healthReasonCoreUnreachable     = "core_unreachable"
+	healthReasonCoreUnsynced        = "core_unsynced"
+	healthReasonIngestionLagging    = "ingestion_lagging"
+	healthReasonReplicaStale        = "replica_stale"
+	healthReasonDraining            = "draining"
+)
+
 var healthLogger = log.WithField("service", "healthCheck")
 
 type stellarCoreClient interface {
 	Info(ctx context.Context) (*stellarcore.InfoResponse, error)
 }
 
+type ingestLedgerGetter interface {
+	GetLastLedgerIngestNonBlocking(ctx context.Context) (uint32, error)
+}
+
 type healthCache struct {
 	response   healthResponse
 	lastUpdate time.Time
@@ -49,17 +74,50 @@ func newHealthCache(ttl time.Duration) *healthCache {
 	return &healthCache{ttl: ttl}
 }
 
+// drainState records whether Horizon is draining: it keeps serving requests,
+// but fails health checks so that load balancers stop sending it new ones
+// before it shuts down.
+type drainState struct {
+	draining int32
+}
+
+func (d *drainState) drain() {
+	atomic.StoreInt32(&d.draining, 1)
+}
+
+func (d *drainState) isDraining() bool {
+	return atomic.LoadInt32(&d.draining) == 1
+}
+
 type healthCheck struct {
 	session db.SessionInterface
 	ctx     context.Context
-	core    stellarCoreClient
-	cache   *healthCache
+	// core is nil on read-only gateways, which only check the database.
+	core  stellarCoreClient
+	cache *healthCache
+	clock clock.Clock
+
+	// ledgerState, if set, reports ingestion as lagging when the latest
+	// ingested ledger closed more than ingestionLagThreshold ago.
+	ledgerState           *ledger.State
+	ingestionLagThreshold time.Duration
+	// primary and replica, if set, report the replica database as stale
+	// when it is more than replicaLagThreshold ledgers behind the primary.
+	primary             ingestLedgerGetter
+	replica             ingestLedgerGetter
+	replicaLagThreshold uint32
+	// drain, if set, fails the health check once Horizon is draining.
+	drain *drainState
 }
 
 type healthResponse struct {
 	DatabaseConnected bool `json:"database_connected"`
 	CoreUp            bool `json:"core_up"`
 	CoreSynced        bool `json:"core_synced"`
+	// Status is healthy, degraded, unhealthy or draining, and Reasons the
+	// machine-readable reasons Horizon is not healthy.
+	Status  string   `json:"status"`
+	Reasons []string `json:"reasons,omitempty"`
 }
 
 func (h healthCheck) runCheck() healthResponse {
@@ -71,22 +129,90 @@ func (h healthCheck) runCheck() healthResponse {
 	if err := h.session.Ping(h.ctx, dbPingTimeout); err != nil {
 		healthLogger.Warnf("could not ping db: %s", err)
 		response.DatabaseConnected = false
+		response.Reasons = append(response.Reasons, healthReasonDatabaseUnreachable)
 	}
-	if resp, err := h.core.Info(h.ctx); err != nil {
+	if h.core == nil {
+		response.CoreUp = false
+		response.CoreSynced = false
+	} else if resp, err := h.core.Info(h.ctx); err != nil {
 		healthLogger.Warnf("request to stellar core failed: %s", err)
 		response.CoreUp = false
 		response.CoreSynced = false
+		response.Reasons = append(response.Reasons, healthReasonCoreUnreachable)
 	} else {
 		response.CoreSynced = resp.IsSynced()
+		if !response.CoreSynced {
+			response.Reasons = append(response.Reasons, healthReasonCoreUnsynced)
+		}
+	}
+
+	if h.ledgerState != nil && h.ingestionLagThreshold > 0 {
+		closedAt := h.ledgerState.CurrentStatus().HistoryLatestClosedAt
+		if h.clock.Now().Sub(closedAt) > h.ingestionLagThreshold {
+			response.Reasons = append(response.Reasons, healthReasonIngestionLagging)
+		}
+	}
+
+	if h.primary != nil && h.replica != nil && h.replicaLagThreshold > 0 {
+		if h.replicaLag() > h.replicaLagThreshold {
+			response.Reasons = append(response.Reasons, healthReasonReplicaStale)
+		}
+	}
+
+	switch {
+	case !h.healthy(response):
+		response.Status = healthStatusUnhealthy
+	case len(response.Reasons) > 0:
+		response.Status = healthStatusDegraded
+	default:
+		response.Status = healthStatusHealthy
 	}
 
 	return response
 }
 
+// replicaLag returns the number of ledgers the replica database is behind
+// the primary. The replica is considered stale if either database cannot be
+// queried.
+func (h healthCheck) replicaLag() uint32 {
+	primaryLedger, err := h.primary.GetLastLedgerIngestNonBlocking(h.ctx)
+	if err != nil {
+		healthLogger.Warnf("could not get last ingested ledger of primary db: %s", err)
+		return h.replicaLagThreshold + 1
+	}
+	replicaLedger, err := h.replica.GetLastLedgerIngestNonBlocking(h.ctx)
+	if err != nil {
+		healthLogger.Warnf("could not get last ingested ledger of replica db: %s", err)
+		return h.replicaLagThreshold + 1
+	}
+	if replicaLedger >= primaryLedger {
+		return 0
+	}
+	return primaryLedger - replicaLedger
+}
+
+// healthy returns false if Horizon cannot serve requests: its database or,
+// unless it is a read-only gateway, stellar-core is down or unsynced.
+func (h healthCheck) healthy(response healthResponse) bool {
+	healthy := response.DatabaseConnected
+	if h.core != nil {
+		healthy = healthy && response.CoreUp && response.CoreSynced
+	}
+	return healthy
+}
+
 func (h healthCheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	response := h.cache.get(h.runCheck)
 
-	if !response.DatabaseConnected || !response.CoreSynced || !response.CoreUp {
+	healthy := h.healthy(response)
+	if h.drain != nil && h.drain.isDraining() {
+		// Draining is not cached, so that load balancers notice it as soon
+		// as possible.
+		healthy = false
+		response.Status = healthStatusDraining
+		response.Reasons = append(append([]string{}, response.Reasons...), healthReasonDraining)
+	}
+	if !healthy {
 		w.WriteHeader(http.StatusServiceUnavailable)
 	}
 
@@ -94,3 +220,19 @@ func (h healthCheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		healthLogger.Warnf("could not write response: %s", err)
 	}
 }
+
+// drainHandler puts Horizon in drain mode, served on the admin port so that
+// operators can drain an instance before stopping it.
+type drainHandler struct {
+	app *App
+}
+
+func (h drainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.app.Drain()
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(struct {
+		Draining bool `json:"draining"`
+	}{true}); err != nil {
+		healthLogger.Warnf("could not write response: %s", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stellar/go/protocols/stellarcore"
+	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/support/clock"
 	"github.com/stellar/go/support/clock/clocktest"
 	"github.com/stellar/go/support/db"
@@ -54,6 +55,7 @@ func TestHealthCheck(t *testing.T) {
 				DatabaseConnected: true,
 				CoreUp:            true,
 				CoreSynced:        true,
+				Status:            healthStatusHealthy,
 			},
 		},
 		{
@@ -66,6 +68,8 @@ func TestHealthCheck(t *testing.T) {
 				DatabaseConnected: false,
 				CoreUp:            true,
 				CoreSynced:        true,
+				Status:            healthStatusUnhealthy,
+				Reasons:           []string{healthReasonDatabaseUnreachable},
 			},
 		},
 		{
@@ -78,6 +82,8 @@ func TestHealthCheck(t *testing.T) {
 				DatabaseConnected: true,
 				CoreUp:            true,
 				CoreSynced:        false,
+				Status:            healthStatusUnhealthy,
+				Reasons:           []string{healthReasonCoreUnsynced},
 			},
 		},
 		{
@@ -90,6 +96,8 @@ func TestHealthCheck(t *testing.T) {
 				DatabaseConnected: true,
 				CoreUp:            false,
 				CoreSynced:        false,
+				Status:            healthStatusUnhealthy,
+				Reasons:           []string{healthReasonCoreUnreachable},
 			},
 		},
 		{
@@ -102,6 +110,8 @@ func TestHealthCheck(t *testing.T) {
 				DatabaseConnected: false,
 				CoreUp:            false,
 				CoreSynced:        false,
+				Status:            healthStatusUnhealthy,
+				Reasons:           []string{healthReasonDatabaseUnreachable, healthReasonCoreUnreachable},
 			},
 		},
 		{
@@ -114,6 +124,8 @@ func TestHealthCheck(t *testing.T) {
 				DatabaseConnected: false,
 				CoreUp:            true,
 				CoreSynced:        false,
+				Status:            healthStatusUnhealthy,
+				Reasons:           []string{healthReasonDatabaseUnreachable, healthReasonCoreUnsynced},
 			},
 		},
 	} {
@@ -146,6 +158,47 @@ func TestHealthCheck(t *testing.T) {
 	}
 }
 
+func TestHealthCheckWithoutCore(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		pingErr        error
+		expectedStatus int
+	}{
+		{"healthy", nil, http.StatusOK},
+		{"db down", fmt.Errorf("database is down"), http.StatusServiceUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			session := &db.MockSession{}
+			session.On("Ping", ctx, dbPingTimeout).Return(tc.pingErr).Once()
+
+			h := healthCheck{
+				session: session,
+				ctx:     ctx,
+				cache:   newHealthCache(healthCacheTTL),
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, nil)
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var response healthResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			expected := healthResponse{DatabaseConnected: true, Status: healthStatusHealthy}
+			if tc.pingErr != nil {
+				expected = healthResponse{
+					Status:  healthStatusUnhealthy,
+					Reasons: []string{healthReasonDatabaseUnreachable},
+				}
+			}
+			assert.Equal(t, expected, response)
+
+			session.AssertExpectations(t)
+		})
+	}
+}
+
 func TestHealthCheckCache(t *testing.T) {
 	cachedResponse := healthResponse{
 		DatabaseConnected: false,
@@ -191,6 +244,8 @@ func TestHealthCheckCache(t *testing.T) {
 		DatabaseConnected: true,
 		CoreUp:            false,
 		CoreSynced:        false,
+		Status:            healthStatusUnhealthy,
+		Reasons:           []string{healthReasonCoreUnreachable},
 	}
 	for _, timestamp := range []time.Time{time.Unix(6, 0), time.Unix(7, 0)} {
 		h.cache.clock = clock.Clock{
@@ -211,3 +266,110 @@ func TestHealthCheckCache(t *testing.T) {
 	session.AssertExpectations(t)
 	core.AssertExpectations(t)
 }
+
+type mockIngestLedgerGetter struct {
+	mock.Mock
+}
+
+func (m *mockIngestLedgerGetter) GetLastLedgerIngestNonBlocking(ctx context.Context) (uint32, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(uint32), args.Error(1)
+}
+
+func TestHealthCheckDegraded(t *testing.T) {
+	now := time.Unix(1000, 0)
+	for _, tc := range []struct {
+		name            string
+		closedAt        time.Time
+		primaryLedger   uint32
+		replicaLedger   uint32
+		replicaErr      error
+		expectedReasons []string
+	}{
+		{"healthy", now.Add(-5 * time.Second), 100, 98, nil, nil},
+		{"ingestion lagging", now.Add(-2 * time.Minute), 100, 100, nil, []string{healthReasonIngestionLagging}},
+		{"replica stale", now.Add(-5 * time.Second), 100, 90, nil, []string{healthReasonReplicaStale}},
+		{"replica down", now.Add(-5 * time.Second), 100, 0, fmt.Errorf("replica is down"), []string{healthReasonReplicaStale}},
+		{"ingestion lagging and replica stale", now.Add(-2 * time.Minute), 100, 90, nil, []string{healthReasonIngestionLagging, healthReasonReplicaStale}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			session := &db.MockSession{}
+			session.On("Ping", ctx, dbPingTimeout).Return(nil).Once()
+			primary := &mockIngestLedgerGetter{}
+			primary.On("GetLastLedgerIngestNonBlocking", ctx).Return(tc.primaryLedger, nil).Once()
+			replica := &mockIngestLedgerGetter{}
+			replica.On("GetLastLedgerIngestNonBlocking", ctx).Return(tc.replicaLedger, tc.replicaErr).Once()
+			ledgerState := &ledger.State{}
+			ledgerState.SetStatus(ledger.Status{HistoryLatestClosedAt: tc.closedAt})
+
+			h := healthCheck{
+				session:               session,
+				ctx:                   ctx,
+				cache:                 newHealthCache(healthCacheTTL),
+				clock:                 clock.Clock{Source: clocktest.FixedSource(now)},
+				ledgerState:           ledgerState,
+				ingestionLagThreshold: time.Minute,
+				primary:               primary,
+				replica:               replica,
+				replicaLagThreshold:   5,
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, nil)
+			// Horizon keeps receiving traffic when degraded.
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response healthResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			expectedStatus := healthStatusHealthy
+			if len(tc.expectedReasons) > 0 {
+				expectedStatus = healthStatusDegraded
+			}
+			assert.Equal(t, expectedStatus, response.Status)
+			assert.Equal(t, tc.expectedReasons, response.Reasons)
+
+			session.AssertExpectations(t)
+			primary.AssertExpectations(t)
+			replica.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHealthCheckDraining(t *testing.T) {
+	ctx := context.Background()
+	session := &db.MockSession{}
+	session.On("Ping", ctx, dbPingTimeout).Return(nil).Once()
+
+	drain := &drainState{}
+	h := healthCheck{
+		session: session,
+		ctx:     ctx,
+		cache:   newHealthCache(time.Hour),
+		drain:   drain,
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Draining fails the health check right away, although the response is
+	// cached.
+	drain.drain()
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, nil)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var response healthResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, healthResponse{
+		DatabaseConnected: true,
+		Status:            healthStatusDraining,
+		Reasons:           []string{healthReasonDraining},
+	}, response)
+	assert.Empty(t, h.cache.response.Reasons)
+
+	session.AssertExpectations(t)
+}
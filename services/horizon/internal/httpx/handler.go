@@ -13,6 +13,7 @@ import (
 	"github.com/stellar/go/services/horizon/internal/render/sse"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/support/render/httpjson"
 	"github.com/stellar/go/support/render/problem"
@@ -209,11 +210,10 @@ func (handler pageActionHandler) renderPage(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	httpjson.Render(
-		w,
-		page,
-		httpjson.HALJSON,
-	)
+	// Records are written in batches as they are encoded, pages can be large.
+	if err := hal.RenderPage(w, page); err != nil {
+		log.Ctx(r.Context()).WithStack(err).Warn("Error rendering page: ", err)
+	}
 }
 
 func (handler pageActionHandler) renderStream(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,241 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stellar/go/support/log"
+)
+
+const (
+	// latencyBudgetBuckets is the number of buckets the latency budget window
+	// is divided into.
+	latencyBudgetBuckets = 10
+	// latencyBudgetMinRequests is the number of requests to a route in the
+	// window below which its latency budget alarm is not raised, so that a
+	// few slow requests to a quiet route do not raise it.
+	latencyBudgetMinRequests = 20
+)
+
+// LatencyBudget is the latency objective of a route.
+type LatencyBudget struct {
+	// Route is the route template, e.g. /accounts/{account_id}.
+	Route string
+	// Latency is the duration within which requests to the route must be
+	// served.
+	Latency time.Duration
+}
+
+// ParseLatencyBudgets parses a comma-separated list of route=latency pairs,
+// e.g. "/accounts/{account_id}=500ms,/paths/strict-send=2s".
+func ParseLatencyBudgets(s string) ([]LatencyBudget, error) {
+	var budgets []LatencyBudget
+	seen := map[string]bool{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		i := strings.LastIndex(pair, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid latency budget %q, expected route=latency", pair)
+		}
+		route := strings.TrimSpace(pair[:i])
+		latency, err := time.ParseDuration(strings.TrimSpace(pair[i+1:]))
+		if err != nil || latency <= 0 {
+			return nil, fmt.Errorf("invalid latency in latency budget %q", pair)
+		}
+		if seen[route] {
+			return nil, fmt.Errorf("duplicate latency budget for route %s", route)
+		}
+		seen[route] = true
+		budgets = append(budgets, LatencyBudget{Route: route, Latency: latency})
+	}
+	return budgets, nil
+}
+
+// LatencyBudgetConfig configures the latency budget alarms of routes.
+type LatencyBudgetConfig struct {
+	Budgets []LatencyBudget
+	// Target is the percentage of the requests to a route which must be
+	// served within its latency budget, e.g. 99.
+	Target float64
+	// Window is the sliding window over which requests are counted.
+	Window time.Duration
+}
+
+// LatencyBudgetStatus is the state of the latency budget of a route over the
+// window, returned by the /latency_budgets admin endpoint.
+type LatencyBudgetStatus struct {
+	Route         string  `json:"route"`
+	LatencyBudget string  `json:"latency_budget"`
+	Target        float64 `json:"target"`
+	Requests      uint64  `json:"requests"`
+	OverBudget    uint64  `json:"over_budget"`
+	Errors        uint64  `json:"errors"`
+	// WithinBudget is the percentage of the requests served within the
+	// latency budget.
+	WithinBudget float64 `json:"within_budget"`
+	Alarm        bool    `json:"alarm"`
+}
+
+type latencyBudgetBucket struct {
+	id         int64
+	requests   uint64
+	overBudget uint64
+	errors     uint64
+}
+
+type routeLatencyBudget struct {
+	latency time.Duration
+	buckets [latencyBudgetBuckets]latencyBudgetBucket
+	alarm   bool
+}
+
+// latencyBudgetTracker counts the requests to the routes with a latency
+// budget, and those over budget or failing, in a sliding window.
+type latencyBudgetTracker struct {
+	target         float64
+	bucketDuration time.Duration
+	now            func() time.Time
+
+	lock   sync.Mutex
+	routes map[string]*routeLatencyBudget
+}
+
+func newLatencyBudgetTracker(config LatencyBudgetConfig) *latencyBudgetTracker {
+	window := config.Window
+	if window <= 0 {
+		window = 10 * time.Minute
+	}
+	t := &latencyBudgetTracker{
+		target:         config.Target,
+		bucketDuration: window / latencyBudgetBuckets,
+		now:            time.Now,
+		routes:         map[string]*routeLatencyBudget{},
+	}
+	for _, budget := range config.Budgets {
+		t.routes[budget.Route] = &routeLatencyBudget{latency: budget.Latency}
+	}
+	return t
+}
+
+// register registers a gauge reporting the alarm of each route, 1 when the
+// route is over its latency budget.
+func (t *latencyBudgetTracker) register(registry *prometheus.Registry) error {
+	for route := range t.routes {
+		route := route
+		gauge := prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: "horizon", Subsystem: "http", Name: "latency_budget_alarm",
+				Help:        "1 if the route is over its latency budget, 0 otherwise",
+				ConstLabels: prometheus.Labels{"route": route},
+			},
+			func() float64 {
+				if t.status(route).Alarm {
+					return 1
+				}
+				return 0
+			},
+		)
+		if err := registry.Register(gauge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observe records a request to route, and returns true if it was over the
+// route's latency budget.
+func (t *latencyBudgetTracker) observe(route string, duration time.Duration, status int) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	budget, ok := t.routes[route]
+	if !ok {
+		return false
+	}
+
+	bucket := t.bucket(budget)
+	bucket.requests++
+	overBudget := duration > budget.latency
+	if overBudget {
+		bucket.overBudget++
+	}
+	if status >= http.StatusInternalServerError {
+		bucket.errors++
+	}
+
+	s := t.statusLocked(route, budget)
+	if s.Alarm && !budget.alarm {
+		log.WithFields(log.F{
+			"route":         route,
+			"within_budget": s.WithinBudget,
+			"target":        s.Target,
+		}).Warn("Route is over its latency budget")
+	}
+	budget.alarm = s.Alarm
+	return overBudget
+}
+
+// bucket returns the current bucket of budget, resetting it if it is stale.
+func (t *latencyBudgetTracker) bucket(budget *routeLatencyBudget) *latencyBudgetBucket {
+	id := t.now().UnixNano() / int64(t.bucketDuration)
+	bucket := &budget.buckets[id%latencyBudgetBuckets]
+	if bucket.id != id {
+		*bucket = latencyBudgetBucket{id: id}
+	}
+	return bucket
+}
+
+func (t *latencyBudgetTracker) status(route string) LatencyBudgetStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.statusLocked(route, t.routes[route])
+}
+
+func (t *latencyBudgetTracker) statusLocked(route string, budget *routeLatencyBudget) LatencyBudgetStatus {
+	s := LatencyBudgetStatus{
+		Route:         route,
+		LatencyBudget: budget.latency.String(),
+		Target:        t.target,
+		WithinBudget:  100,
+	}
+	oldest := t.now().UnixNano()/int64(t.bucketDuration) - latencyBudgetBuckets
+	for _, bucket := range budget.buckets {
+		if bucket.id > oldest {
+			s.Requests += bucket.requests
+			s.OverBudget += bucket.overBudget
+			s.Errors += bucket.errors
+		}
+	}
+	if s.Requests > 0 {
+		s.WithinBudget = 100 * float64(s.Requests-s.OverBudget) / float64(s.Requests)
+	}
+	s.Alarm = s.Requests >= latencyBudgetMinRequests && s.WithinBudget < t.target
+	return s
+}
+
+// ServeHTTP returns the status of the latency budgets of all routes.
+func (t *latencyBudgetTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.lock.Lock()
+	statuses := make([]LatencyBudgetStatus, 0, len(t.routes))
+	for route, budget := range t.routes {
+		statuses = append(statuses, t.statusLocked(route, budget))
+	}
+	t.lock.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Route < statuses[j].Route
+	})
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(statuses); err != nil {
+		log.Ctx(r.Context()).Warnf("could not write response: %s", err)
+	}
+}
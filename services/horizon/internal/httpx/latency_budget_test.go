@@ -0,0 +1,83 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatencyBudgets(t *testing.T) {
+	budgets, err := ParseLatencyBudgets("/accounts/{account_id}=500ms, /paths/strict-send=2s")
+	require.NoError(t, err)
+	assert.Equal(t, []LatencyBudget{
+		{Route: "/accounts/{account_id}", Latency: 500 * time.Millisecond},
+		{Route: "/paths/strict-send", Latency: 2 * time.Second},
+	}, budgets)
+
+	budgets, err = ParseLatencyBudgets("")
+	require.NoError(t, err)
+	assert.Empty(t, budgets)
+
+	_, err = ParseLatencyBudgets("/ledgers")
+	assert.EqualError(t, err, `invalid latency budget "/ledgers", expected route=latency`)
+
+	_, err = ParseLatencyBudgets("/ledgers=fast")
+	assert.EqualError(t, err, `invalid latency in latency budget "/ledgers=fast"`)
+
+	_, err = ParseLatencyBudgets("/ledgers=1s,/ledgers=2s")
+	assert.EqualError(t, err, "duplicate latency budget for route /ledgers")
+}
+
+func TestLatencyBudgetTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tracker := newLatencyBudgetTracker(LatencyBudgetConfig{
+		Budgets: []LatencyBudget{{Route: "/ledgers", Latency: time.Second}},
+		Target:  90,
+		Window:  time.Minute,
+	})
+	tracker.now = func() time.Time { return now }
+
+	assert.False(t, tracker.observe("/unknown", time.Hour, http.StatusOK))
+
+	// below the minimum number of requests the alarm is not raised
+	for i := 0; i < 5; i++ {
+		assert.True(t, tracker.observe("/ledgers", 2*time.Second, http.StatusOK))
+	}
+	status := tracker.status("/ledgers")
+	assert.Equal(t, uint64(5), status.Requests)
+	assert.Equal(t, uint64(5), status.OverBudget)
+	assert.False(t, status.Alarm)
+
+	for i := 0; i < 15; i++ {
+		assert.False(t, tracker.observe("/ledgers", 100*time.Millisecond, http.StatusInternalServerError))
+	}
+	status = tracker.status("/ledgers")
+	assert.Equal(t, LatencyBudgetStatus{
+		Route:         "/ledgers",
+		LatencyBudget: "1s",
+		Target:        90,
+		Requests:      20,
+		OverBudget:    5,
+		Errors:        15,
+		WithinBudget:  75,
+		Alarm:         true,
+	}, status)
+
+	// requests older than the window are not counted
+	now = now.Add(time.Minute)
+	status = tracker.status("/ledgers")
+	assert.Equal(t, uint64(0), status.Requests)
+	assert.False(t, status.Alarm)
+
+	w := httptest.NewRecorder()
+	tracker.ServeHTTP(w, httptest.NewRequest("GET", "/latency_budgets", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var statuses []LatencyBudgetStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &statuses))
+	assert.Equal(t, []LatencyBudgetStatus{status}, statuses)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/render"
 	hProblem "github.com/stellar/go/services/horizon/internal/render/problem"
+	"github.com/stellar/go/support/clock"
 	"github.com/stellar/go/support/db"
 	supportErrors "github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
@@ -66,7 +67,8 @@ func newWrapResponseWriter(w http.ResponseWriter, r *http.Request) middleware.Wr
 }
 
 // loggerMiddleware logs http requests and resposnes to the logging subsytem of horizon.
-func loggerMiddleware(serverMetrics *ServerMetrics) func(next http.Handler) http.Handler {
+// It also records the requests in latencyBudgets when it is not nil.
+func loggerMiddleware(serverMetrics *ServerMetrics, latencyBudgets *latencyBudgetTracker) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
@@ -83,7 +85,7 @@ func loggerMiddleware(serverMetrics *ServerMetrics) func(next http.Handler) http
 			then := time.Now()
 			next.ServeHTTP(mw, r.WithContext(ctx))
 			duration := time.Since(then)
-			logEndOfRequest(ctx, r, serverMetrics.RequestDurationSummary, duration, mw, streaming)
+			logEndOfRequest(ctx, r, serverMetrics, latencyBudgets, duration, mw, streaming)
 		})
 	}
 }
@@ -149,7 +151,7 @@ func sanitizeMetricRoute(routePattern string) string {
 	return route
 }
 
-func logEndOfRequest(ctx context.Context, r *http.Request, requestDurationSummary *prometheus.SummaryVec, duration time.Duration, mw middleware.WrapResponseWriter, streaming bool) {
+func logEndOfRequest(ctx context.Context, r *http.Request, serverMetrics *ServerMetrics, latencyBudgets *latencyBudgetTracker, duration time.Duration, mw middleware.WrapResponseWriter, streaming bool) {
 	route := sanitizeMetricRoute(chi.RouteContext(r.Context()).RoutePattern())
 
 	referer := r.Referer()
@@ -179,12 +181,63 @@ func logEndOfRequest(ctx context.Context, r *http.Request, requestDurationSummar
 		"referer":         referer,
 	}).Info("Finished request")
 
-	requestDurationSummary.With(prometheus.Labels{
+	serverMetrics.RequestDurationSummary.With(prometheus.Labels{
 		"status":    strconv.FormatInt(int64(mw.Status()), 10),
 		"route":     route,
 		"streaming": strconv.FormatBool(streaming),
 		"method":    r.Method,
 	}).Observe(float64(duration.Seconds()))
+
+	if mw.Status() >= http.StatusInternalServerError {
+		serverMetrics.RequestErrorsCounter.With(prometheus.Labels{
+			"route":  route,
+			"method": r.Method,
+		}).Inc()
+	}
+
+	// the duration of streaming requests is the duration of the stream
+	if streaming {
+		return
+	}
+	serverMetrics.RequestDurationHistogram.With(prometheus.Labels{
+		"route":  route,
+		"method": r.Method,
+	}).Observe(duration.Seconds())
+	if latencyBudgets != nil && latencyBudgets.observe(route, duration, mw.Status()) {
+		serverMetrics.LatencyBudgetExceededCounter.With(prometheus.Labels{
+			"route": route,
+		}).Inc()
+	}
+}
+
+const (
+	// LatestLedgerClosedAtHeaderName is the header set by read-only gateways
+	// to the close time of the latest ledger in the database.
+	LatestLedgerClosedAtHeaderName = "Latest-Ledger-Closed-At"
+	// LatestLedgerAgeHeaderName is the header set by read-only gateways to
+	// the number of seconds elapsed since the latest ledger in the database
+	// was closed.
+	LatestLedgerAgeHeaderName = "Latest-Ledger-Age"
+)
+
+// stalenessHeadersMiddleware adds headers reporting the close time and age of
+// the latest ledger in the database to each response. They are omitted until
+// the ledger state has been loaded.
+func stalenessHeadersMiddleware(ledgerState *ledger.State, c *clock.Clock) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			closedAt := ledgerState.CurrentStatus().HistoryLatestClosedAt
+			if !closedAt.IsZero() {
+				age := c.Now().Sub(closedAt)
+				if age < 0 {
+					age = 0
+				}
+				w.Header().Set(LatestLedgerClosedAtHeaderName, closedAt.UTC().Format(time.RFC3339))
+				w.Header().Set(LatestLedgerAgeHeaderName, strconv.FormatInt(int64(age/time.Second), 10))
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
 }
 
 // recoverMiddleware helps the server recover from panics. It ensures that
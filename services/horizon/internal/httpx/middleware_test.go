@@ -1,9 +1,16 @@
 package httpx
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/support/clock"
+	"github.com/stellar/go/support/clock/clocktest"
 )
 
 func TestMiddlewareSanitizesRoutesForPrometheus(t *testing.T) {
@@ -45,3 +52,27 @@ func TestMiddlewareSanitizesRoutesForPrometheus(t *testing.T) {
 	}
 
 }
+
+func TestStalenessHeadersMiddleware(t *testing.T) {
+	ledgerState := &ledger.State{}
+	c := &clock.Clock{Source: clocktest.FixedSource(time.Date(2021, 5, 4, 12, 0, 30, 0, time.UTC))}
+	handler := stalenessHeadersMiddleware(ledgerState, c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// the ledger state has not been loaded yet
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(LatestLedgerClosedAtHeaderName))
+	assert.Empty(t, w.Header().Get(LatestLedgerAgeHeaderName))
+
+	ledgerState.SetStatus(ledger.Status{
+		HistoryLatest:         10,
+		HistoryLatestClosedAt: time.Date(2021, 5, 4, 12, 0, 0, 0, time.UTC),
+	})
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, "2021-05-04T12:00:00Z", w.Header().Get(LatestLedgerClosedAtHeaderName))
+	assert.Equal(t, "30", w.Header().Get(LatestLedgerAgeHeaderName))
+}
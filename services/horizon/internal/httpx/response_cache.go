@@ -0,0 +1,143 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/stellar/go/services/horizon/internal/ledger"
+	"github.com/stellar/go/services/horizon/internal/toid"
+)
+
+// responseCache keeps the responses of immutable resources (e.g. ledgers,
+// transactions and operations) in memory, so that repeated requests for them
+// do not hit the database. Resources are immutable only as long as they are
+// in the history: responses for ledgers older than the history elder, which
+// were reaped, are evicted instead of being served.
+type responseCache struct {
+	cache       *lru.Cache
+	ledgerState *ledger.State
+}
+
+// cachedResponse is a successful response stored in a responseCache.
+type cachedResponse struct {
+	contentType string
+	body        []byte
+	// ledger is the sequence of the ledger the resource belongs to.
+	ledger int32
+}
+
+// newResponseCache returns a cache holding up to size responses.
+func newResponseCache(size int, ledgerState *ledger.State) (*responseCache, error) {
+	cache, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &responseCache{cache: cache, ledgerState: ledgerState}, nil
+}
+
+// responseLedger returns the sequence of the ledger of the resource in body,
+// which is given by its paging token.
+func responseLedger(body []byte) (int32, bool) {
+	var resource struct {
+		PagingToken string `json:"paging_token"`
+	}
+	if err := json.Unmarshal(body, &resource); err != nil {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(resource.PagingToken, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return toid.Parse(id).LedgerSequence, true
+}
+
+// cacheKey returns the key of the response to r. Responses contain links
+// built from the request scheme and host, and depend on the Accept header,
+// so they are part of the key along with the path and query.
+func cacheKey(r *http.Request) string {
+	scheme := "http"
+	switch {
+	case r.Header.Get("X-Forwarded-Proto") != "":
+		scheme = r.Header.Get("X-Forwarded-Proto")
+	case r.TLS != nil:
+		scheme = "https"
+	}
+
+	return strings.Join([]string{
+		scheme,
+		r.Host,
+		r.URL.RequestURI(),
+		r.Header.Get("Accept"),
+	}, "\n")
+}
+
+// Wrap returns a handler serving GET requests from the cache, and caching the
+// successful responses of next. Error responses are never cached, as the
+// resource could exist later (e.g. a ledger which is not ingested yet), and
+// neither are responses without a paging token, as they could not be evicted
+// once reaped.
+func (c *responseCache) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := cacheKey(r)
+		if value, ok := c.cache.Get(key); ok {
+			response := value.(cachedResponse)
+			if response.ledger >= c.ledgerState.CurrentStatus().HistoryElder {
+				w.Header().Set("Content-Type", response.contentType)
+				w.Header().Set("X-Horizon-Cache", "hit")
+				w.WriteHeader(http.StatusOK)
+				w.Write(response.body)
+				return
+			}
+			// the resource was reaped, let next respond that it is out of
+			// the history
+			c.cache.Remove(key)
+		}
+
+		w.Header().Set("X-Horizon-Cache", "miss")
+		recorder := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+		if recorder.status != http.StatusOK {
+			return
+		}
+		if sequence, ok := responseLedger(recorder.body.Bytes()); ok {
+			c.cache.Add(key, cachedResponse{
+				contentType: w.Header().Get("Content-Type"),
+				body:        recorder.body.Bytes(),
+				ledger:      sequence,
+			})
+		}
+	})
+}
+
+// responseRecorder writes a response while keeping a copy of its status code
+// and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,91 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stellar/go/services/horizon/internal/ledger"
+)
+
+func TestResponseCache(t *testing.T) {
+	ledgerState := &ledger.State{}
+	ledgerState.SetStatus(ledger.Status{HistoryElder: 1})
+	cache, err := newResponseCache(10, ledgerState)
+	require.NoError(t, err)
+
+	calls := 0
+	handler := cache.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case "/fee_stats":
+			w.Write([]byte(`{"last_ledger":"1"}`))
+			return
+		}
+		if ledgerState.CurrentStatus().HistoryElder > 1 {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.Header().Set("Content-Type", "application/hal+json")
+		fmt.Fprintf(w, `{"host":%q,"paging_token":"4294967296"}`, r.Host)
+	}))
+
+	serve := func(method, host, path, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		r.Host = host
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("GET", "a.example", "/ledgers/1", "")
+	assert.Equal(t, "miss", w.Header().Get("X-Horizon-Cache"))
+	assert.Equal(t, 1, calls)
+
+	w = serve("GET", "a.example", "/ledgers/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hit", w.Header().Get("X-Horizon-Cache"))
+	assert.Equal(t, "application/hal+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"host":"a.example","paging_token":"4294967296"}`, w.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// links depend on the host and the format on the Accept header
+	w = serve("GET", "b.example", "/ledgers/1", "")
+	assert.Equal(t, "miss", w.Header().Get("X-Horizon-Cache"))
+	assert.Equal(t, `{"host":"b.example","paging_token":"4294967296"}`, w.Body.String())
+	serve("GET", "a.example", "/ledgers/1", "application/json")
+	assert.Equal(t, 3, calls)
+
+	// errors are not cached
+	w = serve("GET", "a.example", "/missing", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve("GET", "a.example", "/missing", "")
+	assert.Equal(t, "miss", w.Header().Get("X-Horizon-Cache"))
+	assert.Equal(t, 5, calls)
+
+	// only GET requests are served from the cache
+	w = serve("HEAD", "a.example", "/ledgers/1", "")
+	assert.Empty(t, w.Header().Get("X-Horizon-Cache"))
+	assert.Equal(t, 6, calls)
+
+	// responses without a paging token are not cached
+	serve("GET", "a.example", "/fee_stats", "")
+	w = serve("GET", "a.example", "/fee_stats", "")
+	assert.Equal(t, "miss", w.Header().Get("X-Horizon-Cache"))
+	assert.Equal(t, 8, calls)
+
+	// reaped resources are not served from the cache
+	ledgerState.SetStatus(ledger.Status{HistoryElder: 2})
+	w = serve("GET", "a.example", "/ledgers/1", "")
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Equal(t, "miss", w.Header().Get("X-Horizon-Cache"))
+	assert.Equal(t, 9, calls)
+}
@@ -15,12 +15,14 @@ import (
 	"github.com/rs/cors"
 	"github.com/stellar/throttled"
 
+	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/horizon/internal/actions"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
 	"github.com/stellar/go/services/horizon/internal/ledger"
 	"github.com/stellar/go/services/horizon/internal/paths"
 	"github.com/stellar/go/services/horizon/internal/render/sse"
 	"github.com/stellar/go/services/horizon/internal/txsub"
+	"github.com/stellar/go/support/clock"
 	"github.com/stellar/go/support/db"
 	supporthttp "github.com/stellar/go/support/http"
 	"github.com/stellar/go/support/render/problem"
@@ -47,6 +49,29 @@ type RouterConfig struct {
 	HorizonVersion        string
 	FriendbotURL          *url.URL
 	HealthCheck           http.Handler
+	// Drain, if set, is served on the admin port to put Horizon in drain
+	// mode.
+	Drain http.Handler
+	// SubmissionIdempotencyWindow is how long the results of submissions
+	// made with an idempotency key are kept. Zero disables idempotent
+	// submissions.
+	SubmissionIdempotencyWindow time.Duration
+	// ResponseCacheSize is the number of immutable resources (ledgers,
+	// transactions and operations) whose responses are cached in memory.
+	// Zero disables the cache.
+	ResponseCacheSize int
+	// Operator is the operator metadata advertised in the root resource.
+	Operator *horizon.RootOperator
+	// HistoryRetentionCount is the number of ledgers kept in history,
+	// advertised in the root resource. Zero means that all history is kept.
+	HistoryRetentionCount uint
+	// LatencyBudgets configures the latency budget alarms of routes,
+	// reported on the admin port.
+	LatencyBudgets LatencyBudgetConfig
+	// ReadOnlyGateway adds headers reporting the staleness of the database
+	// to responses, as read-only gateways do not ingest it themselves.
+	// Transactions are only submitted if TxSubmitter is set.
+	ReadOnlyGateway bool
 }
 
 type Router struct {
@@ -67,14 +92,33 @@ func NewRouter(config *RouterConfig, serverMetrics *ServerMetrics, ledgerState *
 			return nil, fmt.Errorf("unable to create RateLimiter: %v", err)
 		}
 	}
-	result.addMiddleware(config, rateLimiter, serverMetrics)
-	result.addRoutes(config, rateLimiter, ledgerState)
+	var cache *responseCache
+	if config.ResponseCacheSize > 0 {
+		var err error
+		cache, err = newResponseCache(config.ResponseCacheSize, ledgerState)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create response cache: %v", err)
+		}
+	}
+	var latencyBudgets *latencyBudgetTracker
+	if len(config.LatencyBudgets.Budgets) > 0 {
+		latencyBudgets = newLatencyBudgetTracker(config.LatencyBudgets)
+		if config.PrometheusRegistry != nil {
+			if err := latencyBudgets.register(config.PrometheusRegistry); err != nil {
+				return nil, fmt.Errorf("unable to register latency budget metrics: %v", err)
+			}
+		}
+	}
+	result.addMiddleware(config, rateLimiter, serverMetrics, latencyBudgets, ledgerState)
+	result.addRoutes(config, rateLimiter, ledgerState, cache, latencyBudgets)
 	return &result, nil
 }
 
 func (r *Router) addMiddleware(config *RouterConfig,
 	rateLimitter *throttled.HTTPRateLimiter,
-	serverMetrics *ServerMetrics) {
+	serverMetrics *ServerMetrics,
+	latencyBudgets *latencyBudgetTracker,
+	ledgerState *ledger.State) {
 
 	r.Use(chimiddleware.StripSlashes)
 
@@ -85,18 +129,26 @@ func (r *Router) addMiddleware(config *RouterConfig,
 		BehindCloudflare:      config.BehindCloudflare,
 		BehindAWSLoadBalancer: config.BehindAWSLoadBalancer,
 	}))
-	r.Use(loggerMiddleware(serverMetrics))
+	r.Use(loggerMiddleware(serverMetrics, latencyBudgets))
 	r.Use(timeoutMiddleware(config.ConnectionTimeout))
 	r.Use(recoverMiddleware)
 	r.Use(chimiddleware.Compress(flate.DefaultCompression, "application/hal+json"))
 
+	exposedHeaders := []string{"Date"}
+	if config.ReadOnlyGateway {
+		exposedHeaders = append(exposedHeaders, LatestLedgerClosedAtHeaderName, LatestLedgerAgeHeaderName)
+	}
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
 		AllowedHeaders: []string{"*"},
-		ExposedHeaders: []string{"Date"},
+		ExposedHeaders: exposedHeaders,
 	})
 	r.Use(c.Handler)
 
+	if config.ReadOnlyGateway {
+		r.Use(stalenessHeadersMiddleware(ledgerState, &clock.Clock{}))
+	}
+
 	if rateLimitter != nil {
 		r.Use(rateLimitter.RateLimit)
 	}
@@ -113,22 +165,32 @@ func (r *Router) addMiddleware(config *RouterConfig,
 	// Internal middlewares
 	r.Internal.Use(chimiddleware.StripSlashes)
 	r.Internal.Use(chimiddleware.RequestID)
-	r.Internal.Use(loggerMiddleware(serverMetrics))
+	r.Internal.Use(loggerMiddleware(serverMetrics, nil))
 }
 
-func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRateLimiter, ledgerState *ledger.State) {
+func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRateLimiter, ledgerState *ledger.State, cache *responseCache, latencyBudgets *latencyBudgetTracker) {
 	stateMiddleware := StateMiddleware{
 		HorizonSession: config.DBSession,
 	}
+	// immutable wraps the handlers of resources which never change once
+	// ingested with the response cache, if enabled.
+	immutable := func(handler http.Handler) http.Handler {
+		if cache == nil {
+			return handler
+		}
+		return cache.Wrap(handler)
+	}
 
 	r.Method(http.MethodGet, "/health", config.HealthCheck)
 
 	r.Method(http.MethodGet, "/", ObjectActionHandler{Action: actions.GetRootHandler{
-		LedgerState:        ledgerState,
-		CoreSettingsGetter: config.CoreGetter,
-		NetworkPassphrase:  config.NetworkPassphrase,
-		FriendbotURL:       config.FriendbotURL,
-		HorizonVersion:     config.HorizonVersion,
+		LedgerState:           ledgerState,
+		CoreSettingsGetter:    config.CoreGetter,
+		NetworkPassphrase:     config.NetworkPassphrase,
+		FriendbotURL:          config.FriendbotURL,
+		HorizonVersion:        config.HorizonVersion,
+		Operator:              config.Operator,
+		HistoryRetentionCount: config.HistoryRetentionCount,
 	}})
 
 	streamHandler := sse.StreamHandler{
@@ -156,6 +218,9 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 					accountData,
 				))
 				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/offers", streamableStatePageHandler(ledgerState, actions.GetAccountOffersHandler{LedgerState: ledgerState}, streamHandler))
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/sponsorships", ObjectActionHandler{actions.GetAccountSponsorshipsHandler{}})
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/sponsorships/sponsoring", restPageHandler(ledgerState, actions.GetSponsorshipsHandler{LedgerState: ledgerState, Sponsoring: true}))
+				r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/sponsorships/sponsored", restPageHandler(ledgerState, actions.GetSponsorshipsHandler{LedgerState: ledgerState}))
 			})
 		})
 
@@ -170,6 +235,7 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 		})
 
 		r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/assets", restPageHandler(ledgerState, actions.AssetStatsHandler{LedgerState: ledgerState}))
+		r.With(historyMiddleware).Method(http.MethodGet, "/assets/{asset}/stats", ObjectActionHandler{actions.GetAssetPaymentStatsHandler{}})
 
 		findPaths := ObjectActionHandler{actions.FindPathsHandler{
 			StaleThreshold:       config.StaleThreshold,
@@ -203,6 +269,7 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 	// need to use absolute routes here. Make sure we use regexp check here for
 	// emptiness. Without it, requesting `/accounts//payments` return all payments!
 	r.Group(func(r chi.Router) {
+		r.With(stateMiddleware.Wrap).Method(http.MethodGet, "/accounts/{account_id:\\w+}/balance_history", restPageHandler(ledgerState, actions.GetBalanceHistoryHandler{LedgerState: ledgerState}))
 		r.With(historyMiddleware).Method(http.MethodGet, "/accounts/{account_id:\\w+}/effects", streamableHistoryPageHandler(ledgerState, actions.GetEffectsHandler{LedgerState: ledgerState}, streamHandler))
 		r.With(historyMiddleware).Method(http.MethodGet, "/accounts/{account_id:\\w+}/operations", streamableHistoryPageHandler(ledgerState, actions.GetOperationsHandler{
 			LedgerState:  ledgerState,
@@ -219,7 +286,7 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 	r.Route("/ledgers", func(r chi.Router) {
 		r.With(historyMiddleware).Method(http.MethodGet, "/", streamableHistoryPageHandler(ledgerState, actions.GetLedgersHandler{LedgerState: ledgerState}, streamHandler))
 		r.Route("/{ledger_id}", func(r chi.Router) {
-			r.With(historyMiddleware).Method(http.MethodGet, "/", ObjectActionHandler{actions.GetLedgerByIDHandler{LedgerState: ledgerState}})
+			r.With(historyMiddleware).Method(http.MethodGet, "/", immutable(ObjectActionHandler{actions.GetLedgerByIDHandler{LedgerState: ledgerState}}))
 			r.With(historyMiddleware).Method(http.MethodGet, "/transactions", streamableHistoryPageHandler(ledgerState, actions.GetTransactionsHandler{LedgerState: ledgerState}, streamHandler))
 			r.Group(func(r chi.Router) {
 				r.With(historyMiddleware).Method(http.MethodGet, "/effects", streamableHistoryPageHandler(ledgerState, actions.GetEffectsHandler{LedgerState: ledgerState}, streamHandler))
@@ -247,7 +314,7 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 	r.Route("/transactions", func(r chi.Router) {
 		r.With(historyMiddleware).Method(http.MethodGet, "/", streamableHistoryPageHandler(ledgerState, actions.GetTransactionsHandler{LedgerState: ledgerState}, streamHandler))
 		r.Route("/{tx_id}", func(r chi.Router) {
-			r.With(historyMiddleware).Method(http.MethodGet, "/", ObjectActionHandler{actions.GetTransactionByHashHandler{}})
+			r.With(historyMiddleware).Method(http.MethodGet, "/", immutable(ObjectActionHandler{actions.GetTransactionByHashHandler{}}))
 			r.With(historyMiddleware).Method(http.MethodGet, "/effects", streamableHistoryPageHandler(ledgerState, actions.GetEffectsHandler{LedgerState: ledgerState}, streamHandler))
 			r.With(historyMiddleware).Method(http.MethodGet, "/operations", streamableHistoryPageHandler(ledgerState, actions.GetOperationsHandler{
 				LedgerState:  ledgerState,
@@ -266,7 +333,7 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 			LedgerState:  ledgerState,
 			OnlyPayments: false,
 		}, streamHandler))
-		r.With(historyMiddleware).Method(http.MethodGet, "/{id}", ObjectActionHandler{actions.GetOperationByIDHandler{LedgerState: ledgerState}})
+		r.With(historyMiddleware).Method(http.MethodGet, "/{id}", immutable(ObjectActionHandler{actions.GetOperationByIDHandler{LedgerState: ledgerState}}))
 		r.With(historyMiddleware).Method(http.MethodGet, "/{op_id}/effects", streamableHistoryPageHandler(ledgerState, actions.GetEffectsHandler{LedgerState: ledgerState}, streamHandler))
 	})
 
@@ -289,10 +356,20 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 	})
 
 	// Transaction submission API
-	r.Method(http.MethodPost, "/transactions", ObjectActionHandler{actions.SubmitTransactionHandler{
-		Submitter:         config.TxSubmitter,
-		NetworkPassphrase: config.NetworkPassphrase,
-	}})
+	var idempotencyStore *txsub.IdempotencyStore
+	if config.SubmissionIdempotencyWindow > 0 && config.TxSubmitter != nil {
+		idempotencyStore = txsub.NewIdempotencyStore(
+			config.SubmissionIdempotencyWindow,
+			config.TxSubmitter.SubmissionTimeout,
+		)
+	}
+	if config.TxSubmitter != nil {
+		r.Method(http.MethodPost, "/transactions", ObjectActionHandler{actions.SubmitTransactionHandler{
+			Submitter:         config.TxSubmitter,
+			NetworkPassphrase: config.NetworkPassphrase,
+			IdempotencyStore:  idempotencyStore,
+		}})
+	}
 
 	// Network state related endpoints
 	r.Method(http.MethodGet, "/fee_stats", ObjectActionHandler{actions.FeeStatsHandler{}})
@@ -315,4 +392,10 @@ func (r *Router) addRoutes(config *RouterConfig, rateLimiter *throttled.HTTPRate
 	r.Internal.Get("/metrics", promhttp.HandlerFor(config.PrometheusRegistry, promhttp.HandlerOpts{}).ServeHTTP)
 	r.Internal.Get("/debug/pprof/heap", pprof.Index)
 	r.Internal.Get("/debug/pprof/profile", pprof.Profile)
+	if config.Drain != nil {
+		r.Internal.Method(http.MethodPost, "/drain", config.Drain)
+	}
+	if latencyBudgets != nil {
+		r.Internal.Get("/latency_budgets", latencyBudgets.ServeHTTP)
+	}
 }
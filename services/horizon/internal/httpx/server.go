@@ -24,6 +24,12 @@ import (
 type ServerMetrics struct {
 	RequestDurationSummary  *prometheus.SummaryVec
 	ReplicaLagErrorsCounter prometheus.Counter
+	// RequestDurationHistogram, RequestErrorsCounter and
+	// LatencyBudgetExceededCounter are the per-route SLIs. Unlike the
+	// summary, the histogram can be aggregated across instances.
+	RequestDurationHistogram     *prometheus.HistogramVec
+	RequestErrorsCounter         *prometheus.CounterVec
+	LatencyBudgetExceededCounter *prometheus.CounterVec
 }
 
 type TLSConfig struct {
@@ -74,6 +80,28 @@ func NewServer(serverConfig ServerConfig, routerConfig RouterConfig, ledgerState
 				Help: "Count of HTTP errors returned due to replica lag",
 			},
 		),
+		RequestDurationHistogram: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "horizon", Subsystem: "http", Name: "requests_duration_histogram_seconds",
+				Help:    "HTTP requests durations of non-streaming requests",
+				Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			},
+			[]string{"route", "method"},
+		),
+		RequestErrorsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "horizon", Subsystem: "http", Name: "requests_errors_count",
+				Help: "Count of HTTP requests which failed with a 5xx status",
+			},
+			[]string{"route", "method"},
+		),
+		LatencyBudgetExceededCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "horizon", Subsystem: "http", Name: "latency_budget_exceeded_count",
+				Help: "Count of HTTP requests served over the latency budget of their route",
+			},
+			[]string{"route"},
+		),
 	}
 	router, err := NewRouter(&routerConfig, sm, ledgerState)
 	if err != nil {
@@ -120,6 +148,15 @@ func (s *Server) Serve() error {
 	return err
 }
 
+// DisableKeepAlives closes the connections of the server once their
+// in-flight requests are served, so that clients reconnect, e.g. to other
+// instances when Horizon is draining.
+func (s *Server) DisableKeepAlives() {
+	if s.server != nil {
+		s.server.SetKeepAlivesEnabled(false)
+	}
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
 	var wg sync.WaitGroup
 	defer wg.Wait()
@@ -715,6 +715,10 @@ func (h reingestHistoryRangeState) run(s *system) (transition, error) {
 			return stop(), err
 		}
 
+		if err := s.historyQ.RebuildAssetPaymentStats(s.ctx, h.fromLedger, h.toLedger); err != nil {
+			return stop(), errors.Wrap(err, rebuildAssetPaymentStatsErrMsg)
+		}
+
 		if err := s.historyQ.Commit(s.ctx); err != nil {
 			return stop(), errors.Wrap(err, commitErrMsg)
 		}
@@ -751,6 +755,22 @@ func (h reingestHistoryRangeState) run(s *system) (transition, error) {
 				return stop(), err
 			}
 		}
+
+		// The payments of the reingested ledgers were added again to the
+		// asset payment stats of their days, which are rebuilt once all the
+		// ledgers are ingested.
+		if err := s.historyQ.Begin(s.ctx); err != nil {
+			return stop(), errors.Wrap(err, "Error starting a transaction")
+		}
+		defer s.historyQ.Rollback(s.ctx)
+
+		if err := s.historyQ.RebuildAssetPaymentStats(s.ctx, h.fromLedger, h.toLedger); err != nil {
+			return stop(), errors.Wrap(err, rebuildAssetPaymentStatsErrMsg)
+		}
+
+		if err := s.historyQ.Commit(s.ctx); err != nil {
+			return stop(), errors.Wrap(err, commitErrMsg)
+		}
 	}
 
 	log.WithFields(logpkg.F{
@@ -302,6 +302,7 @@ func (s *ReingestHistoryRangeStateTestSuite) SetupTest() {
 func (s *ReingestHistoryRangeStateTestSuite) TearDownTest() {
 	t := s.T()
 	s.historyQ.AssertExpectations(t)
+	s.historyQ.MockQAssetPaymentStats.AssertExpectations(t)
 	s.historyAdapter.AssertExpectations(t)
 	s.runner.AssertExpectations(t)
 }
@@ -494,10 +495,60 @@ func (s *ReingestHistoryRangeStateTestSuite) TestSuccess() {
 		s.historyQ.On("Rollback", s.ctx).Return(nil).Once()
 	}
 
+	s.historyQ.On("Begin", s.ctx).Return(nil).Once()
+	s.historyQ.MockQAssetPaymentStats.On("RebuildAssetPaymentStats", s.ctx, uint32(100), uint32(200)).Return(nil).Once()
+	s.historyQ.On("Commit", s.ctx).Return(nil).Once()
+	s.historyQ.On("Rollback", s.ctx).Return(nil).Once()
+
 	err := s.system.ReingestRange(100, 200, false)
 	s.Assert().NoError(err)
 }
 
+func (s *ReingestHistoryRangeStateTestSuite) TestRebuildAssetPaymentStatsFails() {
+	*s.historyQ = mockDBQ{}
+	s.historyQ.On("GetTx").Return(nil).Once()
+	s.historyQ.On("GetLastLedgerIngestNonBlocking", s.ctx).Return(uint32(0), nil).Once()
+
+	for i := uint32(100); i <= uint32(200); i++ {
+		s.historyQ.On("Begin", s.ctx).Return(nil).Once()
+		s.historyQ.On("GetTx").Return(&sqlx.Tx{}).Once()
+
+		toidFrom := toid.New(int32(i), 0, 0)
+		toidTo := toid.New(int32(i+1), 0, 0)
+		s.historyQ.On(
+			"DeleteRangeAll", s.ctx, toidFrom.ToInt64(), toidTo.ToInt64(),
+		).Return(nil).Once()
+
+		meta := xdr.LedgerCloseMeta{
+			V0: &xdr.LedgerCloseMetaV0{
+				LedgerHeader: xdr.LedgerHeaderHistoryEntry{
+					Header: xdr.LedgerHeader{
+						LedgerSeq: xdr.Uint32(i),
+					},
+				},
+			},
+		}
+		s.ledgerBackend.On("GetLedger", s.ctx, uint32(i)).Return(meta, nil).Once()
+
+		s.runner.On("RunTransactionProcessorsOnLedger", meta).Return(
+			processors.StatsLedgerTransactionProcessorResults{},
+			processorsRunDurations{},
+			nil,
+		).Once()
+
+		s.historyQ.On("Commit", s.ctx).Return(nil).Once()
+		s.historyQ.On("Rollback", s.ctx).Return(nil).Once()
+	}
+
+	s.historyQ.On("Begin", s.ctx).Return(nil).Once()
+	s.historyQ.MockQAssetPaymentStats.On("RebuildAssetPaymentStats", s.ctx, uint32(100), uint32(200)).
+		Return(errors.New("my error")).Once()
+	s.historyQ.On("Rollback", s.ctx).Return(nil).Once()
+
+	err := s.system.ReingestRange(100, 200, false)
+	s.Assert().EqualError(err, "Error rebuilding asset payment stats: my error")
+}
+
 func (s *ReingestHistoryRangeStateTestSuite) TestSuccessOneLedger() {
 	s.historyQ.On("GetLastLedgerIngestNonBlocking", s.ctx).Return(uint32(0), nil).Once()
 	s.historyQ.On("GetTx").Return(&sqlx.Tx{}).Once()
@@ -525,6 +576,11 @@ func (s *ReingestHistoryRangeStateTestSuite) TestSuccessOneLedger() {
 	).Once()
 	s.historyQ.On("Commit", s.ctx).Return(nil).Once()
 
+	s.historyQ.On("Begin", s.ctx).Return(nil).Once()
+	s.historyQ.MockQAssetPaymentStats.On("RebuildAssetPaymentStats", s.ctx, uint32(100), uint32(100)).Return(nil).Once()
+	s.historyQ.On("Commit", s.ctx).Return(nil).Once()
+	s.historyQ.On("Rollback", s.ctx).Return(nil).Once()
+
 	// Recreate mock in this single test to remove previous assertion.
 	*s.ledgerBackend = mockLedgerBackend{}
 	s.ledgerBackend.On("PrepareRange", s.ctx, ledgerbackend.BoundedRange(100, 100)).Return(nil).Once()
@@ -571,6 +627,7 @@ func (s *ReingestHistoryRangeStateTestSuite) TestReingestRangeForce() {
 		).Once()
 	}
 
+	s.historyQ.MockQAssetPaymentStats.On("RebuildAssetPaymentStats", s.ctx, uint32(100), uint32(200)).Return(nil).Once()
 	s.historyQ.On("Commit", s.ctx).Return(nil).Once()
 
 	err := s.system.ReingestRange(100, 200, true)
@@ -17,6 +17,7 @@ import (
 	"github.com/stellar/go/ingest"
 	"github.com/stellar/go/ingest/ledgerbackend"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/services/horizon/internal/ingest/processors"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
 	logpkg "github.com/stellar/go/support/log"
@@ -80,6 +81,10 @@ type Config struct {
 	HistoryArchiveURL        string
 	DisableStateVerification bool
 
+	// MarketFilter selects the markets of which offers and trades are
+	// ingested. All markets are ingested if nil.
+	MarketFilter *processors.MarketFilter
+
 	MaxReingestRetries          int
 	ReingestRetryBackoffSeconds int
 
@@ -88,11 +93,12 @@ type Config struct {
 }
 
 const (
-	getLastIngestedErrMsg        string = "Error getting last ingested ledger"
-	getIngestVersionErrMsg       string = "Error getting ingestion version"
-	updateLastLedgerIngestErrMsg string = "Error updating last ingested ledger"
-	commitErrMsg                 string = "Error committing db transaction"
-	updateExpStateInvalidErrMsg  string = "Error updating state invalid value"
+	getLastIngestedErrMsg          string = "Error getting last ingested ledger"
+	getIngestVersionErrMsg         string = "Error getting ingestion version"
+	updateLastLedgerIngestErrMsg   string = "Error updating last ingested ledger"
+	commitErrMsg                   string = "Error committing db transaction"
+	updateExpStateInvalidErrMsg    string = "Error updating state invalid value"
+	rebuildAssetPaymentStatsErrMsg string = "Error rebuilding asset payment stats"
 )
 
 type stellarCoreClient interface {
@@ -228,6 +228,7 @@ type mockDBQ struct {
 	mock.Mock
 
 	history.MockQAccounts
+	history.MockQAssetPaymentStats
 	history.MockQClaimableBalances
 	history.MockQHistoryClaimableBalances
 	history.MockQAssetStats
@@ -108,7 +108,7 @@ func (s *ProcessorRunner) buildChangeProcessor(
 		statsChangeProcessor,
 		processors.NewAccountDataProcessor(s.historyQ),
 		processors.NewAccountsProcessor(s.historyQ),
-		processors.NewOffersProcessor(s.historyQ, ledgerSequence),
+		processors.NewOffersProcessor(s.historyQ, ledgerSequence, s.config.MarketFilter),
 		processors.NewAssetStatsProcessor(s.historyQ, useLedgerCache),
 		processors.NewSignersProcessor(s.historyQ, useLedgerCache),
 		processors.NewTrustLinesProcessor(s.historyQ),
@@ -130,10 +130,11 @@ func (s *ProcessorRunner) buildTransactionProcessor(
 		processors.NewEffectProcessor(s.historyQ, sequence),
 		processors.NewLedgerProcessor(s.historyQ, ledger, CurrentVersion),
 		processors.NewOperationProcessor(s.historyQ, sequence),
-		processors.NewTradeProcessor(s.historyQ, ledger),
+		processors.NewTradeProcessor(s.historyQ, ledger, s.config.MarketFilter),
 		processors.NewParticipantsProcessor(s.historyQ, sequence),
 		processors.NewTransactionProcessor(s.historyQ, sequence),
 		processors.NewClaimableBalancesTransactionProcessor(s.historyQ, sequence),
+		processors.NewAssetPaymentStatsProcessor(s.historyQ, ledger),
 	})
 }
 
@@ -0,0 +1,119 @@
+package processors
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+type assetPayments struct {
+	stat   history.AssetPaymentStat
+	volume big.Int
+}
+
+// AssetPaymentStatsProcessor counts the successful payments, strict receive
+// and strict send path payments of a ledger by the asset they deliver, and
+// adds them to the payment stats of the day the ledger closed.
+type AssetPaymentStatsProcessor struct {
+	qStats   history.QAssetPaymentStats
+	day      time.Time
+	payments map[string]*assetPayments
+}
+
+// NewAssetPaymentStatsProcessor returns a processor adding the payments of
+// ledger to the stats of its day.
+func NewAssetPaymentStatsProcessor(qStats history.QAssetPaymentStats, ledger xdr.LedgerHeaderHistoryEntry) *AssetPaymentStatsProcessor {
+	closeTime := time.Unix(int64(ledger.Header.ScpValue.CloseTime), 0).UTC()
+	return &AssetPaymentStatsProcessor{
+		qStats:   qStats,
+		day:      time.Date(closeTime.Year(), closeTime.Month(), closeTime.Day(), 0, 0, 0, 0, time.UTC),
+		payments: map[string]*assetPayments{},
+	}
+}
+
+// ProcessTransaction counts the payments of the transaction if it succeeded.
+func (p *AssetPaymentStatsProcessor) ProcessTransaction(ctx context.Context, transaction ingest.LedgerTransaction) error {
+	if !transaction.Result.Successful() {
+		return nil
+	}
+
+	for i, op := range transaction.Envelope.Operations() {
+		var (
+			asset  xdr.Asset
+			amount xdr.Int64
+		)
+		switch op.Body.Type {
+		case xdr.OperationTypePayment:
+			payment := op.Body.MustPaymentOp()
+			asset, amount = payment.Asset, payment.Amount
+		case xdr.OperationTypePathPaymentStrictReceive:
+			payment := op.Body.MustPathPaymentStrictReceiveOp()
+			asset, amount = payment.DestAsset, payment.DestAmount
+		case xdr.OperationTypePathPaymentStrictSend:
+			operation := transactionOperationWrapper{
+				index:       uint32(i),
+				transaction: transaction,
+				operation:   op,
+			}
+			result := operation.OperationResult().MustPathPaymentStrictSendResult()
+			asset, amount = op.Body.MustPathPaymentStrictSendOp().DestAsset, result.DestAmount()
+		default:
+			continue
+		}
+
+		if err := p.add(asset, amount); err != nil {
+			return errors.Wrapf(err, "Error counting payment of operation %d of transaction %d", i, transaction.Index)
+		}
+	}
+
+	return nil
+}
+
+func (p *AssetPaymentStatsProcessor) add(asset xdr.Asset, amount xdr.Int64) error {
+	key := asset.String()
+	payments, ok := p.payments[key]
+	if !ok {
+		payments = &assetPayments{stat: history.AssetPaymentStat{Day: p.day}}
+		err := asset.Extract(&payments.stat.AssetType, &payments.stat.AssetCode, &payments.stat.AssetIssuer)
+		if err != nil {
+			return err
+		}
+		p.payments[key] = payments
+	}
+	payments.stat.Payments++
+	payments.volume.Add(&payments.volume, big.NewInt(int64(amount)))
+	return nil
+}
+
+// Commit adds the payments counted to the stats of the day.
+func (p *AssetPaymentStatsProcessor) Commit(ctx context.Context) error {
+	if len(p.payments) == 0 {
+		return nil
+	}
+
+	// The stats are sorted so that concurrent ingestion transactions lock
+	// their rows in the same order.
+	keys := make([]string, 0, len(p.payments))
+	for key := range p.payments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	stats := make([]history.AssetPaymentStat, 0, len(keys))
+	for _, key := range keys {
+		payments := p.payments[key]
+		payments.stat.Volume = payments.volume.String()
+		stats = append(stats, payments.stat)
+	}
+
+	if err := p.qStats.AddAssetPaymentStats(ctx, stats); err != nil {
+		return errors.Wrap(err, "Error adding asset payment stats")
+	}
+	return nil
+}
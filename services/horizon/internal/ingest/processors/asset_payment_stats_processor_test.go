@@ -0,0 +1,238 @@
+//lint:file-ignore U1001 Ignore all unused code, staticcheck doesn't understand testify/suite
+
+package processors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/stellar/go/ingest"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+type AssetPaymentStatsProcessorTestSuiteLedger struct {
+	suite.Suite
+	ctx       context.Context
+	processor *AssetPaymentStatsProcessor
+	mockQ     *history.MockQAssetPaymentStats
+
+	usd         xdr.Asset
+	eur         xdr.Asset
+	destination xdr.MuxedAccount
+}
+
+func TestAssetPaymentStatsProcessorTestSuiteLedger(t *testing.T) {
+	suite.Run(t, new(AssetPaymentStatsProcessorTestSuiteLedger))
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) SetupTest() {
+	s.ctx = context.Background()
+	s.mockQ = &history.MockQAssetPaymentStats{}
+	s.usd = xdr.MustNewCreditAsset("USD", "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML")
+	s.eur = xdr.MustNewCreditAsset("EUR", "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML")
+	s.destination = xdr.MustMuxedAddress("GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY")
+
+	s.processor = NewAssetPaymentStatsProcessor(s.mockQ, xdr.LedgerHeaderHistoryEntry{
+		Header: xdr.LedgerHeader{
+			LedgerSeq: 20,
+			ScpValue: xdr.StellarValue{
+				CloseTime: xdr.TimePoint(time.Date(2021, 6, 15, 23, 59, 59, 0, time.UTC).Unix()),
+			},
+		},
+	})
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) TearDownTest() {
+	s.mockQ.AssertExpectations(s.T())
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) transaction(successful bool, ops []xdr.Operation, results []xdr.OperationResult) ingest.LedgerTransaction {
+	code := xdr.TransactionResultCodeTxSuccess
+	if !successful {
+		code = xdr.TransactionResultCodeTxFailed
+	}
+	source := xdr.MustAddress("GAUJETIZVEP2NRYLUESJ3LS66NVCEGMON4UDCBCSBEVPIID773P2W6AY")
+	return ingest.LedgerTransaction{
+		Index: 1,
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{
+					Code:    code,
+					Results: &results,
+				},
+			},
+		},
+		Envelope: xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+			V1: &xdr.TransactionV1Envelope{
+				Tx: xdr.Transaction{
+					SourceAccount: source.ToMuxedAccount(),
+					Operations:    ops,
+				},
+			},
+		},
+	}
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) payment(asset xdr.Asset, amount xdr.Int64) (xdr.Operation, xdr.OperationResult) {
+	return xdr.Operation{
+		Body: xdr.OperationBody{
+			Type: xdr.OperationTypePayment,
+			PaymentOp: &xdr.PaymentOp{
+				Destination: s.destination,
+				Asset:       asset,
+				Amount:      amount,
+			},
+		},
+	}, xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type:          xdr.OperationTypePayment,
+			PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentSuccess},
+		},
+	}
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) pathPaymentStrictReceive(asset xdr.Asset, amount xdr.Int64) (xdr.Operation, xdr.OperationResult) {
+	return xdr.Operation{
+		Body: xdr.OperationBody{
+			Type: xdr.OperationTypePathPaymentStrictReceive,
+			PathPaymentStrictReceiveOp: &xdr.PathPaymentStrictReceiveOp{
+				SendAsset:   xdr.MustNewNativeAsset(),
+				SendMax:     amount * 2,
+				Destination: s.destination,
+				DestAsset:   asset,
+				DestAmount:  amount,
+			},
+		},
+	}, xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type: xdr.OperationTypePathPaymentStrictReceive,
+			PathPaymentStrictReceiveResult: &xdr.PathPaymentStrictReceiveResult{
+				Code: xdr.PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveSuccess,
+				Success: &xdr.PathPaymentStrictReceiveResultSuccess{
+					Last: xdr.SimplePaymentResult{Destination: s.destination.ToAccountId(), Asset: asset, Amount: amount},
+				},
+			},
+		},
+	}
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) pathPaymentStrictSend(asset xdr.Asset, destMin, amount xdr.Int64) (xdr.Operation, xdr.OperationResult) {
+	return xdr.Operation{
+		Body: xdr.OperationBody{
+			Type: xdr.OperationTypePathPaymentStrictSend,
+			PathPaymentStrictSendOp: &xdr.PathPaymentStrictSendOp{
+				SendAsset:   xdr.MustNewNativeAsset(),
+				SendAmount:  amount * 2,
+				Destination: s.destination,
+				DestAsset:   asset,
+				DestMin:     destMin,
+			},
+		},
+	}, xdr.OperationResult{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type: xdr.OperationTypePathPaymentStrictSend,
+			PathPaymentStrictSendResult: &xdr.PathPaymentStrictSendResult{
+				Code: xdr.PathPaymentStrictSendResultCodePathPaymentStrictSendSuccess,
+				Success: &xdr.PathPaymentStrictSendResultSuccess{
+					Last: xdr.SimplePaymentResult{Destination: s.destination.ToAccountId(), Asset: asset, Amount: amount},
+				},
+			},
+		},
+	}
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) TestNoPayments() {
+	bumpSequence := xdr.Operation{
+		Body: xdr.OperationBody{
+			Type:           xdr.OperationTypeBumpSequence,
+			BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 30000},
+		},
+	}
+	tx := s.transaction(true, []xdr.Operation{bumpSequence}, []xdr.OperationResult{{}})
+	s.Assert().NoError(s.processor.ProcessTransaction(s.ctx, tx))
+	s.Assert().NoError(s.processor.Commit(s.ctx))
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) TestPayments() {
+	payment, paymentResult := s.payment(s.usd, 100)
+	strictReceive, strictReceiveResult := s.pathPaymentStrictReceive(s.usd, 250)
+	// the volume is the amount delivered, not the minimum
+	strictSend, strictSendResult := s.pathPaymentStrictSend(s.eur, 10, 75)
+	native, nativeResult := s.payment(xdr.MustNewNativeAsset(), 1000)
+
+	s.Assert().NoError(s.processor.ProcessTransaction(s.ctx, s.transaction(
+		true,
+		[]xdr.Operation{payment, strictReceive, strictSend},
+		[]xdr.OperationResult{paymentResult, strictReceiveResult, strictSendResult},
+	)))
+	s.Assert().NoError(s.processor.ProcessTransaction(s.ctx, s.transaction(
+		true,
+		[]xdr.Operation{native, payment},
+		[]xdr.OperationResult{nativeResult, paymentResult},
+	)))
+	// failed transactions are not counted
+	s.Assert().NoError(s.processor.ProcessTransaction(s.ctx, s.transaction(
+		false,
+		[]xdr.Operation{payment},
+		[]xdr.OperationResult{paymentResult},
+	)))
+
+	day := time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC)
+	s.mockQ.On("AddAssetPaymentStats", s.ctx, []history.AssetPaymentStat{
+		{
+			AssetType:   xdr.AssetTypeAssetTypeCreditAlphanum4,
+			AssetCode:   "EUR",
+			AssetIssuer: "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+			Day:         day,
+			Payments:    1,
+			Volume:      "75",
+		},
+		{
+			AssetType:   xdr.AssetTypeAssetTypeCreditAlphanum4,
+			AssetCode:   "USD",
+			AssetIssuer: "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+			Day:         day,
+			Payments:    3,
+			Volume:      "450",
+		},
+		{
+			AssetType: xdr.AssetTypeAssetTypeNative,
+			Day:       day,
+			Payments:  1,
+			Volume:    "1000",
+		},
+	}).Return(nil).Once()
+
+	s.Assert().NoError(s.processor.Commit(s.ctx))
+}
+
+func (s *AssetPaymentStatsProcessorTestSuiteLedger) TestAddAssetPaymentStatsFails() {
+	payment, paymentResult := s.payment(s.usd, 100)
+	s.Assert().NoError(s.processor.ProcessTransaction(s.ctx, s.transaction(
+		true,
+		[]xdr.Operation{payment},
+		[]xdr.OperationResult{paymentResult},
+	)))
+
+	s.mockQ.On("AddAssetPaymentStats", s.ctx, []history.AssetPaymentStat{
+		{
+			AssetType:   xdr.AssetTypeAssetTypeCreditAlphanum4,
+			AssetCode:   "USD",
+			AssetIssuer: "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+			Day:         time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC),
+			Payments:    1,
+			Volume:      "100",
+		},
+	}).Return(errors.New("transient error")).Once()
+
+	s.Assert().EqualError(s.processor.Commit(s.ctx), "Error adding asset payment stats: transient error")
+}
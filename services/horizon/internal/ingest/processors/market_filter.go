@@ -0,0 +1,77 @@
+package processors
+
+import (
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// MarketFilter selects the markets, unordered pairs of assets, of which
+// offers and trades are ingested. A nil MarketFilter selects all markets.
+type MarketFilter struct {
+	markets map[string]bool
+}
+
+// NewMarketFilter returns a MarketFilter selecting the markets of the given
+// asset pairs, in either direction.
+func NewMarketFilter(pairs [][2]xdr.Asset) *MarketFilter {
+	f := &MarketFilter{markets: map[string]bool{}}
+	for _, pair := range pairs {
+		f.markets[marketKey(pair[0], pair[1])] = true
+	}
+	return f
+}
+
+// ParseMarketFilter parses a comma separated list of asset pairs, each
+// formatted as two assets (Code:Issuer or "native") separated by a slash,
+// for example "native/USD:GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ".
+// A nil MarketFilter, selecting all markets, is returned for an empty string.
+func ParseMarketFilter(s string) (*MarketFilter, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var pairs [][2]xdr.Asset
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.Split(pair, "/")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("%s is not a valid asset pair", pair)
+		}
+		assets, err := xdr.BuildAssets(parts[0] + "," + parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s is not a valid asset pair", pair)
+		}
+		if assets[0].Equals(assets[1]) {
+			return nil, errors.Errorf("%s is not a valid asset pair, the assets are equal", pair)
+		}
+		pairs = append(pairs, [2]xdr.Asset{assets[0], assets[1]})
+	}
+	return NewMarketFilter(pairs), nil
+}
+
+// Includes returns true if the market of assets a and b is selected.
+func (f *MarketFilter) Includes(a, b xdr.Asset) bool {
+	if f == nil {
+		return true
+	}
+	return f.markets[marketKey(a, b)]
+}
+
+// IncludesEntry returns true if the ledger entry is not an offer, or is an
+// offer in a selected market.
+func (f *MarketFilter) IncludesEntry(entry xdr.LedgerEntry) bool {
+	offer, ok := entry.Data.GetOffer()
+	if !ok {
+		return true
+	}
+	return f.Includes(offer.Selling, offer.Buying)
+}
+
+func marketKey(a, b xdr.Asset) string {
+	x, y := a.StringCanonical(), b.StringCanonical()
+	if y < x {
+		x, y = y, x
+	}
+	return x + "/" + y
+}
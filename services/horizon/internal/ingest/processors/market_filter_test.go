@@ -0,0 +1,69 @@
+package processors
+
+import (
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMarketFilter(t *testing.T) {
+	usd := xdr.MustNewCreditAsset("USD", "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ")
+	eur := xdr.MustNewCreditAsset("EUR", "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ")
+	native := xdr.MustNewNativeAsset()
+
+	filter, err := ParseMarketFilter("")
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+	assert.True(t, filter.Includes(usd, eur))
+
+	filter, err = ParseMarketFilter(
+		"native/USD:GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ",
+	)
+	require.NoError(t, err)
+	assert.True(t, filter.Includes(native, usd))
+	assert.True(t, filter.Includes(usd, native))
+	assert.False(t, filter.Includes(native, eur))
+	assert.False(t, filter.Includes(usd, eur))
+
+	for _, s := range []string{
+		"native",
+		"native/USD",
+		"native/USD:GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ/EUR:GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ",
+		"native/native",
+		"native/USD:GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ,",
+	} {
+		_, err = ParseMarketFilter(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestMarketFilterIncludesEntry(t *testing.T) {
+	usd := xdr.MustNewCreditAsset("USD", "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ")
+	eur := xdr.MustNewCreditAsset("EUR", "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ")
+	filter := NewMarketFilter([][2]xdr.Asset{{usd, xdr.MustNewNativeAsset()}})
+
+	offer := func(selling, buying xdr.Asset) xdr.LedgerEntry {
+		return xdr.LedgerEntry{
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeOffer,
+				Offer: &xdr.OfferEntry{
+					SellerId: xdr.MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"),
+					Selling:  selling,
+					Buying:   buying,
+				},
+			},
+		}
+	}
+	assert.True(t, filter.IncludesEntry(offer(xdr.MustNewNativeAsset(), usd)))
+	assert.False(t, filter.IncludesEntry(offer(eur, usd)))
+	assert.True(t, filter.IncludesEntry(xdr.LedgerEntry{
+		Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeAccount,
+			Account: &xdr.AccountEntry{
+				AccountId: xdr.MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"),
+			},
+		},
+	}))
+}
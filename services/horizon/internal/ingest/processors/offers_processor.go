@@ -14,16 +14,19 @@ import (
 const offerCompactionWindow = uint32(100)
 
 type OffersProcessor struct {
-	offersQ  history.QOffers
-	sequence uint32
+	offersQ      history.QOffers
+	sequence     uint32
+	marketFilter *MarketFilter
 
 	cache       *ingest.ChangeCompactor
 	insertBatch history.OffersBatchInsertBuilder
 	removeBatch []int64
 }
 
-func NewOffersProcessor(offersQ history.QOffers, sequence uint32) *OffersProcessor {
-	p := &OffersProcessor{offersQ: offersQ, sequence: sequence}
+// NewOffersProcessor returns a processor ingesting the offers of the markets
+// selected by marketFilter, or of all markets if it is nil.
+func NewOffersProcessor(offersQ history.QOffers, sequence uint32, marketFilter *MarketFilter) *OffersProcessor {
+	p := &OffersProcessor{offersQ: offersQ, sequence: sequence, marketFilter: marketFilter}
 	p.reset()
 	return p
 }
@@ -78,14 +81,21 @@ func (p *OffersProcessor) flushCache(ctx context.Context) error {
 		var action string
 		var offerID xdr.Int64
 
+		// Offers outside the selected markets are not stored, so changes
+		// moving an offer into or out of them are inserts or removals.
+		preIncluded := change.Pre != nil && p.marketFilter.IncludesEntry(*change.Pre)
+		postIncluded := change.Post != nil && p.marketFilter.IncludesEntry(*change.Post)
+
 		switch {
-		case change.Pre == nil && change.Post != nil:
+		case !preIncluded && !postIncluded:
+			continue
+		case !preIncluded && postIncluded:
 			// Created
 			action = "inserting"
 			row := p.ledgerEntryToRow(change.Post)
 			err = p.insertBatch.Add(ctx, row)
 			rowsAffected = 1 // We don't track this when batch inserting
-		case change.Pre != nil && change.Post == nil:
+		case preIncluded && !postIncluded:
 			// Removed
 			action = "removing"
 			offer := change.Pre.Data.MustOffer()
@@ -15,6 +15,7 @@ import (
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -23,7 +24,7 @@ func TestFuzzOffers(t *testing.T) {
 	tt := test.Start(t)
 	test.ResetHorizonDB(t, tt.HorizonDB)
 	q := &history.Q{&db.Session{DB: tt.HorizonDB}}
-	pp := NewOffersProcessor(q, 10)
+	pp := NewOffersProcessor(q, 10, nil)
 	gen := randxdr.NewGenerator()
 
 	var changes []xdr.LedgerEntryChange
@@ -76,7 +77,7 @@ func (s *OffersProcessorTestSuiteState) SetupTest() {
 		Return(s.mockBatchInsertBuilder).Once()
 
 	s.sequence = 456
-	s.processor = NewOffersProcessor(s.mockQ, s.sequence)
+	s.processor = NewOffersProcessor(s.mockQ, s.sequence, nil)
 }
 
 func (s *OffersProcessorTestSuiteState) TearDownTest() {
@@ -143,7 +144,7 @@ func (s *OffersProcessorTestSuiteLedger) SetupTest() {
 		Return(s.mockBatchInsertBuilder).Once()
 
 	s.sequence = 456
-	s.processor = NewOffersProcessor(s.mockQ, s.sequence)
+	s.processor = NewOffersProcessor(s.mockQ, s.sequence, nil)
 }
 
 func (s *OffersProcessorTestSuiteLedger) TearDownTest() {
@@ -435,3 +436,67 @@ func (s *OffersProcessorTestSuiteLedger) TestRemoveMultipleOffers() {
 	err = s.processor.Commit(s.ctx)
 	s.Assert().NoError(err)
 }
+
+func TestOffersProcessorMarketFilter(t *testing.T) {
+	ctx := context.Background()
+	mockQ := &history.MockQOffers{}
+	mockBatchInsertBuilder := &history.MockOffersBatchInsertBuilder{}
+	mockQ.
+		On("NewOffersBatchInsertBuilder", maxBatchSize).
+		Return(mockBatchInsertBuilder).Once()
+
+	usd := xdr.MustNewCreditAsset("USD", "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ")
+	eur := xdr.MustNewCreditAsset("EUR", "GCEZWKCA5VLDNRLN3RPRJMRZOX3Z6G5CHCGSNFHEYVXM3XOJMDS674JZ")
+	filter := NewMarketFilter([][2]xdr.Asset{{xdr.MustNewNativeAsset(), usd}})
+	processor := NewOffersProcessor(mockQ, 456, filter)
+
+	entry := func(id xdr.Int64, selling, buying xdr.Asset) *xdr.LedgerEntry {
+		return &xdr.LedgerEntry{
+			LastModifiedLedgerSeq: 123,
+			Data: xdr.LedgerEntryData{
+				Type: xdr.LedgerEntryTypeOffer,
+				Offer: &xdr.OfferEntry{
+					SellerId: xdr.MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML"),
+					OfferId:  id,
+					Selling:  selling,
+					Buying:   buying,
+					Price:    xdr.Price{N: 1, D: 2},
+				},
+			},
+		}
+	}
+
+	changes := []ingest.Change{
+		// Created in a selected market.
+		{Type: xdr.LedgerEntryTypeOffer, Post: entry(1, usd, xdr.MustNewNativeAsset())},
+		// Created outside the selected markets.
+		{Type: xdr.LedgerEntryTypeOffer, Post: entry(2, usd, eur)},
+		// Updated outside the selected markets.
+		{Type: xdr.LedgerEntryTypeOffer, Pre: entry(3, eur, usd), Post: entry(3, eur, usd)},
+		// Removed outside the selected markets.
+		{Type: xdr.LedgerEntryTypeOffer, Pre: entry(4, eur, usd)},
+		// Removed in a selected market.
+		{Type: xdr.LedgerEntryTypeOffer, Pre: entry(5, xdr.MustNewNativeAsset(), usd)},
+	}
+	for _, change := range changes {
+		assert.NoError(t, processor.ProcessChange(ctx, change))
+	}
+
+	mockBatchInsertBuilder.On("Add", ctx, history.Offer{
+		SellerID:           "GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML",
+		OfferID:            1,
+		SellingAsset:       usd,
+		BuyingAsset:        xdr.MustNewNativeAsset(),
+		Pricen:             1,
+		Priced:             2,
+		Price:              0.5,
+		LastModifiedLedger: 123,
+	}).Return(nil).Once()
+	mockBatchInsertBuilder.On("Exec", ctx).Return(nil).Once()
+	mockQ.On("RemoveOffers", ctx, []int64{5}, uint32(456)).Return(int64(1), nil).Once()
+	mockQ.On("CompactOffers", ctx, uint32(356)).Return(int64(0), nil).Once()
+	assert.NoError(t, processor.Commit(ctx))
+
+	mockQ.AssertExpectations(t)
+	mockBatchInsertBuilder.AssertExpectations(t)
+}
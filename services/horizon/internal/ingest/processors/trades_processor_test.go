@@ -148,6 +148,7 @@ func (s *TradeProcessorTestSuiteLedger) SetupTest() {
 				LedgerSeq: 100,
 			},
 		},
+		nil,
 	)
 }
 
@@ -531,6 +532,45 @@ func (s *TradeProcessorTestSuiteLedger) TestIngestTradesSucceeds() {
 	s.Assert().NoError(err)
 }
 
+func (s *TradeProcessorTestSuiteLedger) TestIngestTradesMarketFilter() {
+	ctx := context.Background()
+	inserts := s.mockReadTradeTransactions(s.processor.ledger)
+	s.processor.marketFilter = NewMarketFilter([][2]xdr.Asset{
+		{s.strictReceiveTrade.AssetBought, s.strictReceiveTrade.AssetSold},
+	})
+
+	s.mockQ.On("CreateAccounts", ctx, mock.AnythingOfType("[]string"), maxBatchSize).
+		Return(s.unmuxedAccountToID, nil).Once()
+
+	s.mockQ.On("CreateAssets", ctx, mock.AnythingOfType("[]xdr.Asset"), maxBatchSize).
+		Run(func(args mock.Arguments) {
+			arg := args.Get(1).([]xdr.Asset)
+			s.Assert().ElementsMatch(
+				[]xdr.Asset{s.strictReceiveTrade.AssetSold, s.strictReceiveTrade.AssetBought},
+				arg,
+			)
+		}).Return(s.assetToID, nil).Once()
+
+	for _, insert := range inserts {
+		if insert.Trade.OfferId != s.strictReceiveTrade.OfferId {
+			continue
+		}
+		s.mockBatchInsertBuilder.On("Add", ctx, []history.InsertTrade{
+			insert,
+		}).Return(nil).Once()
+	}
+
+	s.mockBatchInsertBuilder.On("Exec", ctx).Return(nil).Once()
+
+	for _, tx := range s.txs {
+		err := s.processor.ProcessTransaction(ctx, tx)
+		s.Assert().NoError(err)
+	}
+
+	err := s.processor.Commit(ctx)
+	s.Assert().NoError(err)
+}
+
 func (s *TradeProcessorTestSuiteLedger) TestCreateAccountsError() {
 	ctx := context.Background()
 	s.mockReadTradeTransactions(s.processor.ledger)
@@ -147,6 +147,11 @@ func (s *system) verifyState(verifyAgainstLatestCheckpoint bool) error {
 	verifier := &verify.StateVerifier{
 		StateReader: stateReader,
 	}
+	if s.config.MarketFilter != nil {
+		verifier.TransformFunction = func(entry xdr.LedgerEntry) (bool, xdr.LedgerEntry) {
+			return !s.config.MarketFilter.IncludesEntry(entry), entry
+		}
+	}
 
 	assetStats := processors.AssetStatSet{}
 	total := 0
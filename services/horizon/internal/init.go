@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/getsentry/raven-go"
+	"github.com/jmoiron/sqlx"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stellar/go/exp/orderbook"
 	"github.com/stellar/go/services/horizon/internal/db2/history"
@@ -18,17 +19,35 @@ import (
 	"github.com/stellar/go/support/log"
 )
 
-func mustNewDBSession(subservice db.Subservice, databaseURL string, maxIdle, maxOpen int, registry *prometheus.Registry) db.SessionInterface {
+func mustNewDBSession(subservice db.Subservice, databaseURL string, maxIdle, maxOpen int, slowQueryThreshold time.Duration, registry *prometheus.Registry) db.SessionInterface {
 	session, err := db.Open("postgres", databaseURL)
 	if err != nil {
 		log.Fatalf("cannot open Horizon DB: %v", err)
 	}
+	session.SlowQueryThreshold = slowQueryThreshold
 
 	session.DB.SetMaxIdleConns(maxIdle)
 	session.DB.SetMaxOpenConns(maxOpen)
 	return db.RegisterMetrics(session, "horizon", subservice, registry)
 }
 
+// mustNewDBSessionWithReplica returns a session routing its reads to the
+// replica of replicaURL, and to the primary while the replica lags more than
+// maxLag.
+func mustNewDBSessionWithReplica(subservice db.Subservice, databaseURL, replicaURL string, maxLag time.Duration, maxIdle, maxOpen int, slowQueryThreshold time.Duration, registry *prometheus.Registry) db.SessionInterface {
+	session, err := db.OpenWithReplica("postgres", databaseURL, replicaURL, maxLag)
+	if err != nil {
+		log.Fatalf("cannot open Horizon DB: %v", err)
+	}
+	session.SlowQueryThreshold = slowQueryThreshold
+
+	for _, conn := range []*sqlx.DB{session.DB, session.Replica.DB} {
+		conn.SetMaxIdleConns(maxIdle)
+		conn.SetMaxOpenConns(maxOpen)
+	}
+	return db.RegisterMetrics(session, "horizon", subservice, registry)
+}
+
 func mustInitHorizonDB(app *App) {
 	maxIdle := app.config.HorizonDBMaxIdleConnections
 	maxOpen := app.config.HorizonDBMaxOpenConnections
@@ -49,6 +68,21 @@ func mustInitHorizonDB(app *App) {
 			app.config.DatabaseURL,
 			maxIdle,
 			maxOpen,
+			app.config.SlowQueryThreshold,
+			app.prometheusRegistry,
+		)}
+	} else if app.config.RoDatabaseMaxLag > 0 {
+		// Reads are routed to the replica, or to the primary while the
+		// replica is lagging, so that they do not need to be checked for
+		// staleness.
+		app.historyQ = &history.Q{mustNewDBSessionWithReplica(
+			db.HistorySubservice,
+			app.config.DatabaseURL,
+			app.config.RoDatabaseURL,
+			app.config.RoDatabaseMaxLag,
+			maxIdle,
+			maxOpen,
+			app.config.SlowQueryThreshold,
 			app.prometheusRegistry,
 		)}
 	} else {
@@ -58,6 +92,7 @@ func mustInitHorizonDB(app *App) {
 			app.config.RoDatabaseURL,
 			maxIdle,
 			maxOpen,
+			app.config.SlowQueryThreshold,
 			app.prometheusRegistry,
 		)}
 
@@ -66,6 +101,7 @@ func mustInitHorizonDB(app *App) {
 			app.config.DatabaseURL,
 			maxIdle,
 			maxOpen,
+			app.config.SlowQueryThreshold,
 			app.prometheusRegistry,
 		)}
 	}
@@ -76,12 +112,12 @@ func initIngester(app *App) {
 	var coreSession db.SessionInterface
 	if !app.config.EnableCaptiveCoreIngestion {
 		coreSession = mustNewDBSession(
-			db.CoreSubservice, app.config.StellarCoreDatabaseURL, ingest.MaxDBConnections, ingest.MaxDBConnections, app.prometheusRegistry)
+			db.CoreSubservice, app.config.StellarCoreDatabaseURL, ingest.MaxDBConnections, ingest.MaxDBConnections, app.config.SlowQueryThreshold, app.prometheusRegistry)
 	}
 	app.ingester, err = ingest.NewSystem(ingest.Config{
 		CoreSession: coreSession,
 		HistorySession: mustNewDBSession(
-			db.IngestSubservice, app.config.DatabaseURL, ingest.MaxDBConnections, ingest.MaxDBConnections, app.prometheusRegistry,
+			db.IngestSubservice, app.config.DatabaseURL, ingest.MaxDBConnections, ingest.MaxDBConnections, app.config.SlowQueryThreshold, app.prometheusRegistry,
 		),
 		NetworkPassphrase: app.config.NetworkPassphrase,
 		// TODO:
@@ -97,6 +133,7 @@ func initIngester(app *App) {
 		RemoteCaptiveCoreURL:     app.config.RemoteCaptiveCoreURL,
 		EnableCaptiveCore:        app.config.EnableCaptiveCoreIngestion,
 		DisableStateVerification: app.config.IngestDisableStateVerification,
+		MarketFilter:             app.config.IngestMarketFilter,
 	})
 
 	if err != nil {
@@ -253,6 +290,9 @@ func initIngestMetrics(app *App) {
 }
 
 func initTxSubMetrics(app *App) {
+	if app.submitter == nil {
+		return
+	}
 	app.submitter.Init()
 	app.prometheusRegistry.MustRegister(app.submitter.Metrics.SubmissionDuration)
 	app.prometheusRegistry.MustRegister(app.submitter.Metrics.BufferedSubmissionsGauge)
@@ -267,6 +307,9 @@ func initTxSubMetrics(app *App) {
 func initWebMetrics(app *App) {
 	app.prometheusRegistry.MustRegister(app.webServer.Metrics.RequestDurationSummary)
 	app.prometheusRegistry.MustRegister(app.webServer.Metrics.ReplicaLagErrorsCounter)
+	app.prometheusRegistry.MustRegister(app.webServer.Metrics.RequestDurationHistogram)
+	app.prometheusRegistry.MustRegister(app.webServer.Metrics.RequestErrorsCounter)
+	app.prometheusRegistry.MustRegister(app.webServer.Metrics.LatencyBudgetExceededCounter)
 }
 
 func initSubmissionSystem(app *App) {
@@ -89,7 +89,8 @@ var (
 		Detail: "This horizon instance is configured to only track a " +
 			"portion of the stellar network's latest history. This request " +
 			"is asking for results prior to the recorded history known to " +
-			"this horizon instance.",
+			"this horizon instance. The earliest ledger available is reported " +
+			"in the `history_elder_ledger` extra.",
 	}
 
 	// StaleHistory is a well-known problem type.  Use it as a shortcut
@@ -104,6 +105,18 @@ var (
 			"try again later.",
 	}
 
+	// IdempotencyKeyConflict is a well-known problem type.  Use it as a shortcut
+	// in your actions.
+	IdempotencyKeyConflict = problem.P{
+		Type:   "idempotency_key_conflict",
+		Title:  "Idempotency Key Conflict",
+		Status: http.StatusConflict,
+		Detail: "The idempotency key sent in the `Idempotency-Key` header was " +
+			"already used to submit a different transaction. Use a new key for " +
+			"every distinct transaction and reuse it only when retrying the " +
+			"submission of the same transaction.",
+	}
+
 	// StillIngesting is a well-known problem type.  Use it as a shortcut
 	// in your actions.
 	StillIngesting = problem.P{
@@ -114,3 +127,15 @@ var (
 			"wait for several minutes before trying your request again.",
 	}
 )
+
+// MakeBeforeHistoryProblem returns a BeforeHistory problem whose extras report
+// the range of ledgers available in the history of this horizon instance, so
+// that clients can resume from the earliest ledger available.
+func MakeBeforeHistoryProblem(historyElder, historyLatest int32) problem.P {
+	p := BeforeHistory
+	p.Extras = map[string]interface{}{
+		"history_elder_ledger":  historyElder,
+		"history_latest_ledger": historyLatest,
+	}
+	return p
+}
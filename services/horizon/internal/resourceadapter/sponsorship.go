@@ -0,0 +1,45 @@
+package resourceadapter
+
+import (
+	"context"
+
+	protocol "github.com/stellar/go/protocols/horizon"
+	horizonContext "github.com/stellar/go/services/horizon/internal/context"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	"github.com/stellar/go/support/render/hal"
+)
+
+// PopulateSponsorship fills out the resource's fields
+func PopulateSponsorship(ctx context.Context, dest *protocol.Sponsorship, row history.Sponsorship) {
+	dest.Type = row.EntryType
+	dest.AccountID = row.AccountID
+	dest.Sponsor = row.Sponsor
+	switch row.EntryType {
+	case history.SponsoredData:
+		dest.DataName = row.EntryID
+	case history.SponsoredOffer:
+		dest.OfferID = row.EntryID
+	case history.SponsoredSigner:
+		dest.Signer = row.EntryID
+	case history.SponsoredTrustline:
+		dest.Asset = row.EntryID
+	}
+	dest.PT = row.PagingToken()
+
+	lb := hal.LinkBuilder{Base: horizonContext.BaseURL(ctx)}
+	dest.Links.Account = lb.Link("/accounts", row.AccountID)
+	dest.Links.Sponsor = lb.Link("/accounts", row.Sponsor)
+}
+
+// PopulateSponsorshipCounts fills out the counts from the counts of each
+// entry type returned by history.Q.SponsorshipCounts.
+func PopulateSponsorshipCounts(dest *protocol.SponsorshipCounts, counts map[string]uint32) {
+	dest.Accounts = counts[history.SponsoredAccount]
+	dest.Data = counts[history.SponsoredData]
+	dest.Offers = counts[history.SponsoredOffer]
+	dest.Signers = counts[history.SponsoredSigner]
+	dest.Trustlines = counts[history.SponsoredTrustline]
+	dest.ClaimableBalances = counts[history.SponsoredClaimableBalance]
+	dest.Total = dest.Accounts + dest.Data + dest.Offers + dest.Signers +
+		dest.Trustlines + dest.ClaimableBalances
+}
@@ -0,0 +1,128 @@
+package txsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/support/contextutil"
+)
+
+// ErrIdempotencyTokenConflict is returned when an idempotency token is reused
+// for a different transaction.
+var ErrIdempotencyTokenConflict = errors.New("idempotency token was already used for a different transaction")
+
+// IdempotencyStore remembers the results of transaction submissions made with
+// a client supplied idempotency token, so that clients retrying a submission
+// after an ambiguous network failure get the original result back instead of
+// a new submission attempt.
+type IdempotencyStore struct {
+	window  time.Duration
+	timeout time.Duration
+	now     func() time.Time
+	mutex   sync.Mutex
+	entries map[string]*idempotencyEntry // token => `*idempotencyEntry`
+	// recorded holds the recorded entries in the order they expire in, which
+	// is the order they were recorded in as the window is the same for all.
+	recorded []recordedEntry
+}
+
+type recordedEntry struct {
+	token string
+	entry *idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	hash   string
+	done   chan struct{}
+	result Result
+	// expiresAt is zero while the submission is in progress.
+	expiresAt time.Time
+}
+
+// NewIdempotencyStore returns a store keeping submission results for the
+// given window after they are known. Submissions are given up to timeout to
+// complete.
+func NewIdempotencyStore(window, timeout time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		window:  window,
+		timeout: timeout,
+		now:     time.Now,
+		entries: map[string]*idempotencyEntry{},
+	}
+}
+
+// Submit returns a channel emitting the result of the submission identified by
+// token. The first call for a token calls submit and records its result; later
+// calls made with the same token and transaction hash within the window emit
+// the recorded result, waiting for it if the submission is still in progress.
+// Submissions which time out or are canceled are not recorded, so that they
+// can be retried.
+//
+// submit is called with a context carrying the values of ctx but not its
+// cancelation: the submission is shared by every request using the token, so
+// it must not be aborted when the client which started it disconnects. The
+// context is instead canceled after the timeout of the store, so that a
+// submission which never completes does not hold on to the token forever.
+func (s *IdempotencyStore) Submit(ctx context.Context, token, hash string, submit func(context.Context) <-chan Result) (<-chan Result, error) {
+	s.mutex.Lock()
+	s.expire()
+	entry, ok := s.entries[token]
+	if ok && entry.hash != hash {
+		s.mutex.Unlock()
+		return nil, ErrIdempotencyTokenConflict
+	}
+	if !ok {
+		entry = &idempotencyEntry{hash: hash, done: make(chan struct{})}
+		s.entries[token] = entry
+	}
+	s.mutex.Unlock()
+
+	if !ok {
+		submitCtx, cancel := context.WithTimeout(contextutil.Detach(ctx), s.timeout)
+		results := submit(submitCtx)
+		go func() {
+			defer cancel()
+			s.record(token, entry, results)
+		}()
+	}
+
+	response := make(chan Result, 1)
+	go func() {
+		<-entry.done
+		response <- entry.result
+	}()
+	return response, nil
+}
+
+func (s *IdempotencyStore) record(token string, entry *idempotencyEntry, results <-chan Result) {
+	result := <-results
+
+	s.mutex.Lock()
+	entry.result = result
+	if result.Err == ErrTimeout || result.Err == ErrCanceled {
+		delete(s.entries, token)
+	} else {
+		entry.expiresAt = s.now().Add(s.window)
+		s.recorded = append(s.recorded, recordedEntry{token: token, entry: entry})
+	}
+	s.mutex.Unlock()
+
+	close(entry.done)
+}
+
+// expire removes the entries whose window has passed. It must be called with
+// the mutex held.
+func (s *IdempotencyStore) expire() {
+	now := s.now()
+	for len(s.recorded) > 0 && now.After(s.recorded[0].entry.expiresAt) {
+		recorded := s.recorded[0]
+		if s.entries[recorded.token] == recorded.entry {
+			delete(s.entries, recorded.token)
+		}
+		// let the entry be garbage collected
+		s.recorded[0] = recordedEntry{}
+		s.recorded = s.recorded[1:]
+	}
+}
@@ -0,0 +1,188 @@
+package txsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	idempotencyHash0 = "0000000000000000000000000000000000000000000000000000000000000000"
+	idempotencyHash1 = "0000000000000000000000000000000000000000000000000000000000000001"
+)
+
+// countingSubmit returns a submit function emitting result and a pointer to
+// the number of times it was called.
+func countingSubmit(result Result) (func(context.Context) <-chan Result, *int) {
+	calls := 0
+	return func(context.Context) <-chan Result {
+		calls++
+		ch := make(chan Result, 1)
+		ch <- result
+		return ch
+	}, &calls
+}
+
+func TestIdempotencyStoreReturnsOriginalResult(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, time.Minute)
+	submit, calls := countingSubmit(Result{Err: ErrBadSequence})
+
+	ch, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	assert.Equal(t, ErrBadSequence, (<-ch).Err)
+
+	ch, err = store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	assert.Equal(t, ErrBadSequence, (<-ch).Err)
+	assert.Equal(t, 1, *calls)
+
+	// a different token submits again
+	ch, err = store.Submit(context.Background(), "other-token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	<-ch
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencyStoreWaitsForPendingSubmission(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, time.Minute)
+	results := make(chan Result, 1)
+	calls := 0
+	submit := func(context.Context) <-chan Result {
+		calls++
+		return results
+	}
+
+	first, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	second, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+
+	results <- Result{Err: ErrNoAccount}
+	assert.Equal(t, ErrNoAccount, (<-first).Err)
+	assert.Equal(t, ErrNoAccount, (<-second).Err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyStoreConflict(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, time.Minute)
+	submit, calls := countingSubmit(Result{})
+
+	ch, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	<-ch
+
+	_, err = store.Submit(context.Background(), "token", idempotencyHash1, submit)
+	assert.Equal(t, ErrIdempotencyTokenConflict, err)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestIdempotencyStoreDoesNotRecordTimeouts(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, time.Minute)
+	submit, calls := countingSubmit(Result{Err: ErrTimeout})
+
+	ch, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	assert.Equal(t, ErrTimeout, (<-ch).Err)
+
+	ch, err = store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	<-ch
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencyStoreExpiry(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, time.Minute)
+	now := time.Unix(1000, 0)
+	store.now = func() time.Time { return now }
+	submit, calls := countingSubmit(Result{})
+
+	ch, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	<-ch
+
+	now = now.Add(2 * time.Minute)
+	// once expired the token can be used for another transaction
+	ch, err = store.Submit(context.Background(), "token", idempotencyHash1, submit)
+	require.NoError(t, err)
+	<-ch
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencyStoreDetachesSubmissionFromRequest(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, time.Minute)
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+
+	results := make(chan Result, 1)
+	var submissionCtx context.Context
+	submit := func(ctx context.Context) <-chan Result {
+		submissionCtx = ctx
+		return results
+	}
+
+	first, err := store.Submit(ctx, "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	// the client which started the submission goes away
+	cancel()
+
+	second, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+
+	require.NotNil(t, submissionCtx)
+	assert.NoError(t, submissionCtx.Err())
+	deadline, ok := submissionCtx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+	assert.Equal(t, "value", submissionCtx.Value(key{}))
+
+	results <- Result{Err: ErrNoAccount}
+	assert.Equal(t, ErrNoAccount, (<-first).Err)
+	assert.Equal(t, ErrNoAccount, (<-second).Err)
+}
+
+func TestIdempotencyStoreTimesOutSubmission(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, 10*time.Millisecond)
+	// the submission never completes on its own
+	submit := func(ctx context.Context) <-chan Result {
+		results := make(chan Result, 1)
+		go func() {
+			<-ctx.Done()
+			results <- Result{Err: ErrCanceled}
+		}()
+		return results
+	}
+
+	ch, err := store.Submit(context.Background(), "token", idempotencyHash0, submit)
+	require.NoError(t, err)
+	assert.Equal(t, ErrCanceled, (<-ch).Err)
+
+	// the token can be used for another transaction
+	ch, err = store.Submit(context.Background(), "token", idempotencyHash1, submit)
+	require.NoError(t, err)
+	assert.Equal(t, ErrCanceled, (<-ch).Err)
+}
+
+func TestIdempotencyStoreExpiresInRecordOrder(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute, time.Minute)
+	now := time.Unix(1000, 0)
+	store.now = func() time.Time { return now }
+	submit, _ := countingSubmit(Result{})
+
+	ch, err := store.Submit(context.Background(), "first", idempotencyHash0, submit)
+	require.NoError(t, err)
+	<-ch
+	now = now.Add(30 * time.Second)
+	ch, err = store.Submit(context.Background(), "second", idempotencyHash0, submit)
+	require.NoError(t, err)
+	<-ch
+
+	now = now.Add(45 * time.Second)
+	// only the first token has expired
+	_, err = store.Submit(context.Background(), "first", idempotencyHash1, submit)
+	assert.NoError(t, err)
+	_, err = store.Submit(context.Background(), "second", idempotencyHash1, submit)
+	assert.Equal(t, ErrIdempotencyTokenConflict, err)
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"go/types"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db"
+	kycstatus "github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/kyc-status"
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/support/log"
+)
+
+type KYCCommand struct {
+	DatabaseURL string
+}
+
+func (c *KYCCommand) Command() *cobra.Command {
+	configOpts := config.ConfigOptions{
+		{
+			Name:        "database-url",
+			Usage:       "Database URL",
+			OptType:     types.String,
+			ConfigKey:   &c.DatabaseURL,
+			FlagDefault: "postgres://localhost:5432/?sslmode=disable",
+			Required:    true,
+			Secret:      true,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "kyc",
+		Short: "Review the KYC statuses of accounts",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	configOpts.Init(cmd)
+
+	var (
+		status, createdAfter, createdBefore, cursor string
+		limit                                       int
+	)
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the KYC statuses of accounts, ordered by stellar address",
+		Run: func(cmd *cobra.Command, args []string) {
+			c.List(status, createdAfter, createdBefore, cursor, limit)
+		},
+	}
+	listCmd.Flags().StringVar(&status, "status", "", "Only list the accounts with this status: approved, rejected, pending or not_submitted")
+	listCmd.Flags().StringVar(&createdAfter, "created-after", "", "Only list the accounts which required KYC at or after this RFC 3339 time")
+	listCmd.Flags().StringVar(&createdBefore, "created-before", "", "Only list the accounts which required KYC before this RFC 3339 time")
+	listCmd.Flags().StringVar(&cursor, "cursor", "", "Stellar address after which the accounts are listed")
+	listCmd.Flags().IntVar(&limit, "limit", 0, "Number of accounts listed, 10 if 0")
+	cmd.AddCommand(listCmd)
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "approve <stellar-address>",
+		Short: "Approve the KYC of an account, overriding the decision of the KYC provider",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				cmd.Help()
+				return
+			}
+			c.Decide(args[0], kycstatus.CaseStatusApproved)
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "reject <stellar-address>",
+		Short: "Reject the KYC of an account, overriding the decision of the KYC provider",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				cmd.Help()
+				return
+			}
+			c.Decide(args[0], kycstatus.CaseStatusRejected)
+		},
+	})
+
+	return cmd
+}
+
+func (c *KYCCommand) List(status, createdAfter, createdBefore, cursor string, limit int) {
+	db, err := db.Open(c.DatabaseURL)
+	if err != nil {
+		log.Errorf("Error opening database: %s", err.Error())
+		return
+	}
+	defer db.Close()
+
+	commands := kycstatus.AdminCommands{DB: db, Out: os.Stdout}
+	err = commands.List(context.Background(), status, createdAfter, createdBefore, cursor, limit)
+	if err != nil {
+		log.Errorf("Error listing KYC statuses: %s", err.Error())
+	}
+}
+
+func (c *KYCCommand) Decide(stellarAddress string, status kycstatus.CaseStatus) {
+	db, err := db.Open(c.DatabaseURL)
+	if err != nil {
+		log.Errorf("Error opening database: %s", err.Error())
+		return
+	}
+	defer db.Close()
+
+	commands := kycstatus.AdminCommands{DB: db, Out: os.Stdout, Cases: &cases.Store{DB: db}}
+	err = commands.Decide(context.Background(), stellarAddress, status)
+	if err != nil {
+		log.Errorf("Error setting KYC of %s to %s: %s", stellarAddress, status, err.Error())
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"go/types"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	migrate "github.com/rubenv/sql-migrate"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db"
@@ -15,6 +17,7 @@ import (
 
 type MigrateCommand struct {
 	DatabaseURL string
+	DryRun      bool
 }
 
 func (c *MigrateCommand) Command() *cobra.Command {
@@ -26,10 +29,19 @@ func (c *MigrateCommand) Command() *cobra.Command {
 			ConfigKey:   &c.DatabaseURL,
 			FlagDefault: "postgres://localhost:5432/?sslmode=disable",
 			Required:    true,
+			Secret:      true,
+		},
+		{
+			Name:        "dry-run",
+			Usage:       "List the migrations that would be run without running them",
+			OptType:     types.Bool,
+			ConfigKey:   &c.DryRun,
+			FlagDefault: false,
+			Required:    false,
 		},
 	}
 	cmd := &cobra.Command{
-		Use:   "migrate [up|down] [count]",
+		Use:   "migrate [up|down|status] [count]",
 		Short: "Run migrations on the database",
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
 			configOpts.Require()
@@ -56,6 +68,10 @@ func (c *MigrateCommand) Migrate(cmd *cobra.Command, args []string) {
 		return
 	}
 	dirStr := args[0]
+	if dirStr == "status" {
+		c.status(db)
+		return
+	}
 
 	var dir migrate.MigrationDirection
 	switch dirStr {
@@ -64,7 +80,7 @@ func (c *MigrateCommand) Migrate(cmd *cobra.Command, args []string) {
 	case "up":
 		dir = migrate.Up
 	default:
-		log.Errorf("Invalid migration direction, must be 'up' or 'down'.")
+		log.Errorf("Invalid migration direction, must be 'up', 'down' or 'status'.")
 		return
 	}
 
@@ -89,6 +105,10 @@ func (c *MigrateCommand) Migrate(cmd *cobra.Command, args []string) {
 	if len(migrations) > 0 {
 		log.Infof("Migrations to apply %s: %s", dirStr, strings.Join(migrations, ", "))
 	}
+	if c.DryRun {
+		log.Infof("Dry run, no migrations applied %s.", dirStr)
+		return
+	}
 
 	n, err := dbmigrate.Migrate(db, dir, count)
 	if err != nil {
@@ -101,3 +121,18 @@ func (c *MigrateCommand) Migrate(cmd *cobra.Command, args []string) {
 		log.Infof("No migrations applied %s.", dirStr)
 	}
 }
+
+func (c *MigrateCommand) status(db *sqlx.DB) {
+	statuses, err := dbmigrate.Status(db)
+	if err != nil {
+		log.Errorf("Error getting migration status: %s", err.Error())
+		return
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			log.Infof("%s applied at %s", status.ID, status.AppliedAt.Format(time.RFC3339))
+		} else {
+			log.Infof("%s not applied", status.ID)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve"
+	"github.com/stellar/go/support/log"
+)
+
+type ReplayCommand struct{}
+
+func (c *ReplayCommand) Command() *cobra.Command {
+	opts := serve.Options{}
+	configOpts := serveConfigOptions(&opts)
+	cmd := &cobra.Command{
+		Use:   "replay <audit-log-id>",
+		Short: "Replay a tx-approve decision recorded in the audit log against its recorded state, without changing the database",
+		Long: "Take again the tx-approve decision recorded in the audit log entry with the current configuration, against the state of the account recorded with it, " +
+			"and print it along with the recorded decision. It is configured like the serve command.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				cmd.Help()
+				return
+			}
+			configOpts.Require()
+			configOpts.SetValues()
+			c.Run(opts, args[0])
+		},
+	}
+	configOpts.Init(cmd)
+	return cmd
+}
+
+func (c *ReplayCommand) Run(opts serve.Options, idStr string) {
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Errorf("Invalid audit log id, must be a number.")
+		return
+	}
+
+	result, err := serve.Replay(context.Background(), opts, id)
+	if err != nil {
+		log.Errorf("Error replaying audit log entry %d: %s", id, err.Error())
+		return
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(result); err != nil {
+		log.Errorf("Error writing replay result: %s", err.Error())
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"go/types"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/keyrotation"
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/support/log"
+)
+
+type RotateIssuerKeyCommand struct {
+	HorizonURL           string
+	IssuerAccountAddress string
+	IssuerAccountSecret  string
+	NetworkPassphrase    string
+	NewSignerAddress     string
+	OverlapWindow        int
+}
+
+func (c *RotateIssuerKeyCommand) Command() *cobra.Command {
+	configOpts := config.ConfigOptions{
+		{
+			Name:      "issuer-account-secret",
+			Usage:     "Secret key currently signing the revised transactions, which signs the transactions changing the signers of the issuer account",
+			OptType:   types.String,
+			ConfigKey: &c.IssuerAccountSecret,
+			Required:  true,
+			Secret:    true,
+		},
+		{
+			Name:      "issuer-account-address",
+			Usage:     "Address of the asset issuer's stellar account, defaults to the address of issuer-account-secret",
+			OptType:   types.String,
+			ConfigKey: &c.IssuerAccountAddress,
+			Required:  false,
+		},
+		{
+			Name:      "new-signer-address",
+			Usage:     "Address of the new key signing the revised transactions",
+			OptType:   types.String,
+			ConfigKey: &c.NewSignerAddress,
+			Required:  true,
+		},
+		{
+			Name:        "overlap-window",
+			Usage:       "Number of seconds both keys remain signers of the issuer account after the last transaction signed by the current key, should be longer than the time bounds of the revised transactions",
+			OptType:     types.Int,
+			ConfigKey:   &c.OverlapWindow,
+			FlagDefault: 300,
+			Required:    true,
+		},
+		{
+			Name:        "horizon-url",
+			Usage:       "Horizon URL the transactions changing the signers are submitted to",
+			OptType:     types.String,
+			ConfigKey:   &c.HorizonURL,
+			FlagDefault: horizonclient.DefaultTestNetClient.HorizonURL,
+			Required:    true,
+		},
+		{
+			Name:        "network-passphrase",
+			Usage:       "Network passphrase of the Stellar network transactions should be signed for",
+			OptType:     types.String,
+			ConfigKey:   &c.NetworkPassphrase,
+			FlagDefault: network.TestNetworkPassphrase,
+			Required:    true,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "rotate-issuer-key [add|remove]",
+		Short: "Rotate the key signing the revised transactions of the issuer account",
+		Long: "Add the new signer to the issuer account with the weight of the current one, wait until the current signer did not sign any transaction of the issuer account for the overlap window and remove it. " +
+			"The server should be restarted with the new key as soon as it was added. " +
+			"With add or remove, only the given step is run.",
+		Run: func(cmd *cobra.Command, args []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+			c.Run(cmd, args)
+		},
+	}
+	configOpts.Init(cmd)
+	return cmd
+}
+
+func (c *RotateIssuerKeyCommand) Run(cmd *cobra.Command, args []string) {
+	oldSigner, err := keypair.ParseFull(c.IssuerAccountSecret)
+	if err != nil {
+		log.Errorf("Error parsing issuer account secret: %s", err.Error())
+		return
+	}
+	r := keyrotation.Rotation{
+		HorizonClient: &horizonclient.Client{
+			HorizonURL: c.HorizonURL,
+			HTTP:       &http.Client{Timeout: 30 * time.Second},
+		},
+		NetworkPassphrase: c.NetworkPassphrase,
+		IssuerAddress:     c.IssuerAccountAddress,
+		OldSigner:         oldSigner,
+		NewSignerAddress:  c.NewSignerAddress,
+		OverlapWindow:     time.Duration(c.OverlapWindow) * time.Second,
+	}
+
+	ctx := context.Background()
+	step := ""
+	if len(args) > 0 {
+		step = args[0]
+	}
+	switch step {
+	case "":
+		err = r.Run(ctx)
+	case "add":
+		err = r.AddNewSigner(ctx)
+	case "remove":
+		err = r.RemoveOldSigner(ctx)
+	default:
+		cmd.Help()
+		return
+	}
+	if err != nil {
+		log.Errorf("Error rotating issuer key: %s", err.Error())
+		return
+	}
+	log.Info("Issuer key rotation completed.")
+}
@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"go/types"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/clients/horizonclient"
@@ -13,14 +16,47 @@ import (
 type ServeCommand struct{}
 
 func (c *ServeCommand) Command() *cobra.Command {
-	opts := serve.Options{}
-	configOpts := config.ConfigOptions{
+	opts := serve.Options{KYCProviderWebhookSecretValue: &config.SecretValue{}}
+	configOpts := serveConfigOptions(&opts)
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the SEP-8 Approval Server",
+		Run: func(_ *cobra.Command, _ []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+			opts.KYCProviderWebhookSecretValue.Set(opts.KYCProviderWebhookSecret)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			configOpts.WatchSecrets(ctx)
+			c.Run(opts)
+		},
+	}
+	configOpts.Init(cmd)
+	return cmd
+}
+
+func (c *ServeCommand) Run(opts serve.Options) {
+	serve.Serve(opts)
+}
+
+// serveConfigOptions returns the configuration options of the server, shared
+// by the commands which need the same configuration as serve.
+func serveConfigOptions(opts *serve.Options) config.ConfigOptions {
+	return config.ConfigOptions{
 		{
 			Name:      "issuer-account-secret",
 			Usage:     "Secret key of the asset issuer's stellar account.",
 			OptType:   types.String,
 			ConfigKey: &opts.IssuerAccountSecret,
 			Required:  true,
+			Secret:    true,
+		},
+		{
+			Name:      "issuer-account-address",
+			Usage:     "Address of the asset issuer's stellar account, if issuer-account-secret is one of its signers other than its master key",
+			OptType:   types.String,
+			ConfigKey: &opts.IssuerAccountAddress,
+			Required:  false,
 		},
 		{
 			Name:      "asset-code",
@@ -29,6 +65,52 @@ func (c *ServeCommand) Command() *cobra.Command {
 			ConfigKey: &opts.AssetCode,
 			Required:  true,
 		},
+		{
+			Name:      "additional-regulated-assets",
+			Usage:     "Comma separated list of additional regulated assets in the CODE:ISSUER_ADDRESS:KYC_THRESHOLD[:SIGNER_ADDRESS] format, SIGNER_ADDRESS being the signer of the issuer account signing the revised transactions if not its master key. The secret keys of the signers are read from issuer-secrets-file",
+			OptType:   types.String,
+			ConfigKey: &opts.AdditionalRegulatedAssets,
+			Required:  false,
+		},
+		{
+			Name:      "issuer-secrets-file",
+			Usage:     "Path of a file holding the secret keys of the issuers of additional-regulated-assets, one per line",
+			OptType:   types.String,
+			ConfigKey: &opts.IssuerSecretsFile,
+			Required:  false,
+		},
+		{
+			Name:      "admin-api-key",
+			Usage:     "API key shared by the admins calling the admin endpoints as bearer token, the admin API is disabled if both admin-api-key and admin-api-keys are empty",
+			OptType:   types.String,
+			ConfigKey: &opts.AdminAPIKey,
+			Required:  false,
+			Secret:    true,
+		},
+		{
+			Name:      "admin-api-keys",
+			Usage:     "API keys of the admins comma separated, each in the NAME:KEY format, identifying the admins calling the admin endpoints by their names",
+			OptType:   types.String,
+			ConfigKey: &opts.AdminAPIKeys,
+			Required:  false,
+			Secret:    true,
+		},
+		{
+			Name:        "audit-log-retention-days",
+			Usage:       "Number of days the tx-approve decisions are kept in the audit log, forever if 0",
+			OptType:     types.Int,
+			ConfigKey:   &opts.AuditLogRetentionDays,
+			FlagDefault: 0,
+			Required:    false,
+		},
+		{
+			Name:        "clawback-approval-required",
+			Usage:       "Require the clawbacks requested through the admin API to be approved by a second admin before being submitted",
+			OptType:     types.Bool,
+			ConfigKey:   &opts.ClawbackApprovalRequired,
+			FlagDefault: false,
+			Required:    false,
+		},
 		{
 			Name:        "database-url",
 			Usage:       "Database URL",
@@ -36,6 +118,7 @@ func (c *ServeCommand) Command() *cobra.Command {
 			ConfigKey:   &opts.DatabaseURL,
 			FlagDefault: "postgres://localhost:5432/?sslmode=disable",
 			Required:    true,
+			Secret:      true,
 		},
 		{
 			Name:        "friendbot-payment-amount",
@@ -45,6 +128,30 @@ func (c *ServeCommand) Command() *cobra.Command {
 			FlagDefault: 10000,
 			Required:    true,
 		},
+		{
+			Name:        "grpc-port",
+			Usage:       "Port to serve the gRPC interface on, disabled if 0",
+			OptType:     types.Int,
+			ConfigKey:   &opts.GRPCPort,
+			FlagDefault: 0,
+			Required:    false,
+		},
+		{
+			Name:        "grpc-host",
+			Usage:       "Host the gRPC interface listens on",
+			OptType:     types.String,
+			ConfigKey:   &opts.GRPCHost,
+			FlagDefault: "localhost",
+			Required:    false,
+		},
+		{
+			Name:      "grpc-auth-token",
+			Usage:     "Token gRPC callers must send as bearer token in the authorization metadata, required if grpc-port is set",
+			OptType:   types.String,
+			ConfigKey: &opts.GRPCAuthToken,
+			Required:  false,
+			Secret:    true,
+		},
 		{
 			Name:        "horizon-url",
 			Usage:       "Horizon URL used for looking up account details",
@@ -53,6 +160,44 @@ func (c *ServeCommand) Command() *cobra.Command {
 			FlagDefault: horizonclient.DefaultTestNetClient.HorizonURL,
 			Required:    true,
 		},
+		{
+			Name:      "kyc-provider-url",
+			Usage:     "Base URL of the REST API of the KYC provider reviewing the submitted KYC information, if empty emails starting with \"x\" are rejected and all others approved",
+			OptType:   types.String,
+			ConfigKey: &opts.KYCProviderURL,
+			Required:  false,
+		},
+		{
+			Name:      "kyc-provider-api-key",
+			Usage:     "API key sent as bearer token to the KYC provider",
+			OptType:   types.String,
+			ConfigKey: &opts.KYCProviderAPIKey,
+			Required:  false,
+			Secret:    true,
+		},
+		{
+			Name:      "kyc-provider-webhook-secret",
+			Usage:     "Secret the KYC provider must send as bearer token to the kyc-provider webhook, the webhook is disabled if empty",
+			OptType:   types.String,
+			ConfigKey: &opts.KYCProviderWebhookSecret,
+			Required:  false,
+			Secret:    true,
+			// The webhook secret is rotated without restarting when
+			// serving.
+			OnSecretChange: func(value string) {
+				if opts.KYCProviderWebhookSecretValue != nil {
+					opts.KYCProviderWebhookSecretValue.Set(value)
+				}
+			},
+		},
+		{
+			Name:        "kyc-provider-poll-interval",
+			Usage:       "Number of seconds between polls of the KYC provider for the status of pending cases, disabled if 0",
+			OptType:     types.Int,
+			ConfigKey:   &opts.KYCProviderPollInterval,
+			FlagDefault: 0,
+			Required:    false,
+		},
 		{
 			Name:        "network-passphrase",
 			Usage:       "Network passphrase of the Stellar network transactions should be signed for",
@@ -61,6 +206,45 @@ func (c *ServeCommand) Command() *cobra.Command {
 			FlagDefault: network.TestNetworkPassphrase,
 			Required:    true,
 		},
+		{
+			Name:        "rate-limit-per-minute",
+			Usage:       "Number of tx-approve requests allowed per minute for each client IP, disabled if 0",
+			OptType:     types.Int,
+			ConfigKey:   &opts.RateLimitPerMinute,
+			FlagDefault: 0,
+			Required:    false,
+		},
+		{
+			Name:        "rate-limit-burst",
+			Usage:       "Number of tx-approve requests allowed in a burst above the per minute rate limit",
+			OptType:     types.Int,
+			ConfigKey:   &opts.RateLimitBurst,
+			FlagDefault: 10,
+			Required:    false,
+		},
+		{
+			Name:      "rate-limit-redis-url",
+			Usage:     "URL of the Redis server keeping the rate limiting state, shared by all the instances of the server, e.g. redis://localhost:6379/0. The state is kept in memory if not set",
+			OptType:   types.String,
+			ConfigKey: &opts.RateLimitRedisURL,
+			Required:  false,
+			Secret:    true,
+		},
+		{
+			Name:      "trusted-proxies",
+			Usage:     "Comma separated list of the IP addresses and CIDR networks of the proxies in front of the server. The X-Forwarded-For header is ignored if not set",
+			OptType:   types.String,
+			ConfigKey: &opts.TrustedProxies,
+			Required:  false,
+		},
+		{
+			Name:        "revision-strategy",
+			Usage:       "Name of the strategy building the revised transactions, one of: " + strings.Join(revisionStrategyNames(), ", "),
+			OptType:     types.String,
+			ConfigKey:   &opts.RevisionStrategyName,
+			FlagDefault: "set-trust-line-flags-sandwich",
+			Required:    false,
+		},
 		{
 			Name:        "port",
 			Usage:       "Port to listen and serve on",
@@ -85,19 +269,15 @@ func (c *ServeCommand) Command() *cobra.Command {
 			Required:    true,
 		},
 	}
-	cmd := &cobra.Command{
-		Use:   "serve",
-		Short: "Serve the SEP-8 Approval Server",
-		Run: func(_ *cobra.Command, _ []string) {
-			configOpts.Require()
-			configOpts.SetValues()
-			c.Run(opts)
-		},
-	}
-	configOpts.Init(cmd)
-	return cmd
 }
 
-func (c *ServeCommand) Run(opts serve.Options) {
-	serve.Serve(opts)
+// revisionStrategyNames returns the sorted names of the revision strategies
+// which can be selected.
+func revisionStrategyNames() []string {
+	names := make([]string, 0, len(serve.RevisionStrategies))
+	for name := range serve.RevisionStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve"
+	"github.com/stellar/go/support/log"
+)
+
+type ValidateConfigCommand struct{}
+
+func (c *ValidateConfigCommand) Command() *cobra.Command {
+	opts := serve.Options{}
+	configOpts := serveConfigOptions(&opts)
+	cmd := &cobra.Command{
+		Use:   "validate-config",
+		Short: "Validate the configuration of the serve command without serving",
+		Long: "Validate the options of the serve command, check that the database is reachable and fully migrated, " +
+			"and that the signing key of each regulated asset can sign for its issuer account. Exits with status 1 if a problem is found.",
+		Run: func(_ *cobra.Command, _ []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+			c.Run(opts)
+		},
+	}
+	configOpts.Init(cmd)
+	return cmd
+}
+
+func (c *ValidateConfigCommand) Run(opts serve.Options) {
+	errs := serve.ValidateOptions(opts)
+	for _, err := range errs {
+		log.Error(err.Error())
+	}
+	if len(errs) > 0 {
+		os.Exit(1)
+	}
+	log.Info("Configuration is valid.")
+}
@@ -1,7 +1,9 @@
 package db
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stellar/go/support/db/dbtest"
 	"github.com/stretchr/testify/assert"
@@ -28,3 +30,29 @@ func TestOpen_openAndPingFails(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "dial tcp 127.0.0.1:0: connect:")
 }
+
+func TestOpenInstrumented(t *testing.T) {
+	db := dbtest.Postgres(t)
+
+	queries := []string{}
+	sqlxDB, err := OpenInstrumented(db.DSN, func(query string, duration time.Duration) {
+		queries = append(queries, query)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", sqlxDB.DriverName())
+
+	ctx := context.Background()
+	_, err = sqlxDB.ExecContext(ctx, "CREATE TABLE t (n INTEGER)")
+	require.NoError(t, err)
+	tx, err := sqlxDB.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "INSERT INTO t VALUES ($1)", 1)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	var n int
+	err = sqlxDB.GetContext(ctx, &n, "SELECT n FROM t")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, []string{"CREATE TABLE t (n INTEGER)", "INSERT INTO t VALUES ($1)", "SELECT n FROM t"}, queries)
+}
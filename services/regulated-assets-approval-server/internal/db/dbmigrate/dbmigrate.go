@@ -1,35 +1,59 @@
 package dbmigrate
 
 import (
+	"context"
+
 	"github.com/jmoiron/sqlx"
 	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/exp/compliance/cases"
+	supportmigrate "github.com/stellar/go/support/db/migrate"
+	"github.com/stellar/go/support/errors"
 )
 
 //go:generate go-bindata -nometadata -ignore .+\.(go|swp)$ -pkg dbmigrate -o dbmigrate_generated.go ./migrations
 
-var migrationSource = &migrate.AssetMigrationSource{
-	Asset:    Asset,
-	AssetDir: AssetDir,
-	Dir:      "migrations",
+// migrationSource is the source of the migrations of the server, which
+// include the migrations of the compliance cases tables.
+var migrationSource = combinedSource{
+	supportmigrate.BindataSource(Asset, AssetDir, "migrations"),
+	cases.Migrations(),
+}
+
+// combinedSource finds the migrations of several sources, which sql-migrate
+// sorts by ID.
+type combinedSource []migrate.MigrationSource
+
+func (s combinedSource) FindMigrations() ([]*migrate.Migration, error) {
+	var migrations []*migrate.Migration
+	for _, source := range s {
+		found, err := source.FindMigrations()
+		if err != nil {
+			return nil, errors.Wrap(err, "finding migrations")
+		}
+		migrations = append(migrations, found...)
+	}
+	return migrations, nil
 }
 
 // PlanMigration finds the migrations that would be applied if Migrate was to
 // be run now.
 func PlanMigration(db *sqlx.DB, dir migrate.MigrationDirection, count int) ([]string, error) {
-	migrations, _, err := migrate.PlanMigration(db.DB, db.DriverName(), migrationSource, dir, count)
-	if err != nil {
-		return nil, err
-	}
-	ids := make([]string, 0, len(migrations))
-	for _, m := range migrations {
-		ids = append(ids, m.Id)
-	}
-	return ids, nil
+	migrator := &supportmigrate.Migrator{DB: db, Source: migrationSource, DryRun: true}
+	return migrator.Migrate(context.Background(), dir, count)
 }
 
 // Migrate runs all the migrations to get the database to the state described
 // by the migration files in the direction specified. Count is the maximum
-// number of migrations to apply or rollback.
+// number of migrations to apply or rollback. Concurrent calls from several
+// instances of the server apply each migration once.
 func Migrate(db *sqlx.DB, dir migrate.MigrationDirection, count int) (int, error) {
-	return migrate.ExecMax(db.DB, db.DriverName(), migrationSource, dir, count)
+	migrator := &supportmigrate.Migrator{DB: db, Source: migrationSource}
+	ids, err := migrator.Migrate(context.Background(), dir, count)
+	return len(ids), err
+}
+
+// Status returns whether each migration was applied.
+func Status(db *sqlx.DB) ([]supportmigrate.Status, error) {
+	migrator := &supportmigrate.Migrator{DB: db, Source: migrationSource}
+	return migrator.Status()
 }
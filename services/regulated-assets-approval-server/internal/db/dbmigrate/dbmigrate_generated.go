@@ -2,6 +2,12 @@
 // sources:
 // migrations/2021-05-05.0.initial.sql (162B)
 // migrations/2021-05-18.0.accounts-kyc-status.sql (414B)
+// migrations/2021-06-01.0.kyc-case-id.sql (261B)
+// migrations/2021-06-15.0.revised-transactions.sql (375B)
+// migrations/2021-10-25.0.tx-approve-audit-log.sql (291B)
+// migrations/2021-11-08.0.clawback-requests.sql (506B)
+// migrations/2021-12-06.0.revised-transactions-original-tx.sql (319B)
+// migrations/2021-12-07.0.tx-approve-audit-log-state.sql (488B)
 
 package dbmigrate
 
@@ -110,6 +116,126 @@ func migrations202105180AccountsKycStatusSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations202106010KycCaseIdSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x28\x4d\xca\xc9\x4c\xd6\x4b\x4c\x4e\xce\x2f\xcd\x2b\x29\x8e\xcf\xae\x4c\x8e\x2f\x2e\x49\x2c\x29\x2d\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x00\x89\x26\x27\x16\xa7\xc6\x67\xa6\x28\x94\xa4\x56\x94\x58\x73\x39\x07\xb9\x3a\x86\xb8\x2a\x78\xfa\xb9\xb8\x46\x28\x60\xd1\x1d\x8f\xa4\x05\x88\x2a\x14\xfc\xfd\xf0\xd9\xa5\x81\xa4\x5c\xd3\x9a\x8b\x4b\x17\xc9\xc9\x2e\xf9\xe5\x79\x44\x3b\xda\x25\xc8\x3f\x00\x8b\xab\xad\xb9\x00\xbd\xd6\x9c\x78\x05\x01\x00\x00")
+
+func migrations202106010KycCaseIdSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations202106010KycCaseIdSql,
+		"migrations/2021-06-01.0.kyc-case-id.sql",
+	)
+}
+
+func migrations202106010KycCaseIdSql() (*asset, error) {
+	bytes, err := migrations202106010KycCaseIdSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/2021-06-01.0.kyc-case-id.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x98, 0xf1, 0xbf, 0x0, 0xa8, 0x6e, 0x71, 0x58, 0x79, 0x47, 0x1f, 0xe1, 0xe4, 0x73, 0xd0, 0xc5, 0x28, 0xa6, 0x9a, 0x5d, 0x28, 0x9b, 0x24, 0xb5, 0xab, 0xe, 0x6d, 0x45, 0x5c, 0xd6, 0xc9, 0x90}}
+	return a, nil
+}
+
+var _migrations202106150RevisedTransactionsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xd0\x51\x4b\xc3\x30\x14\x05\xe0\xf7\xfc\x8a\xf3\xd8\xe2\xe6\x1f\xd8\x53\xb5\x15\xc4\xda\x8e\xd2\x22\x7b\x0a\x69\x7a\x59\x03\x6d\x52\x93\x9b\x6d\xf8\xeb\x05\x0b\xa2\x43\xd9\xe3\x85\xef\x5c\x38\x67\xbb\xc5\xdd\x6c\x8e\x5e\x31\xa1\x5b\x84\x78\x6c\x8a\xac\x2d\xd0\x66\x0f\x65\x81\x25\xf6\x93\xd1\xf7\x9e\x4e\x26\xd0\x20\xd9\x2b\x1b\x94\x66\xe3\x6c\x40\x22\x00\x20\xb8\xe8\x35\x49\xa5\xb5\x8b\x96\xc1\x74\x61\x54\x75\x8b\xaa\x2b\xcb\xcd\x2a\xe8\x3d\x92\xd5\x24\x6d\x9c\x7b\xf2\xe8\xcd\xd1\xd8\x6b\xc4\x17\x39\xaa\x30\xfe\x95\x3f\xa9\xc9\x0c\x32\x5a\x36\x13\xd8\xcc\x14\x58\xcd\x0b\xce\x86\xc7\xaf\x13\x1f\xce\xd2\x2a\xb5\x27\xc5\x34\x48\xc5\xff\xc2\xef\xdf\xc8\x8b\xa7\xac\x2b\x5b\x54\xf5\x5b\x92\xae\xf9\x7d\xf3\xfc\x9a\x35\x07\xbc\x14\x07\x24\xbf\x8b\x6d\xae\x6b\xa4\x22\xdd\x09\xf1\x73\xbc\xdc\x9d\xad\x10\x79\x53\xef\x6f\x8f\xb7\x13\x9f\x03\x00\x53\xfe\x39\x04\x77\x01\x00\x00")
+
+func migrations202106150RevisedTransactionsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations202106150RevisedTransactionsSql,
+		"migrations/2021-06-15.0.revised-transactions.sql",
+	)
+}
+
+func migrations202106150RevisedTransactionsSql() (*asset, error) {
+	bytes, err := migrations202106150RevisedTransactionsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/2021-06-15.0.revised-transactions.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa6, 0xe9, 0xaf, 0x40, 0x56, 0x4d, 0xd9, 0x3, 0x13, 0x9, 0x24, 0xce, 0x41, 0xb9, 0x55, 0x27, 0xcd, 0xac, 0xd, 0xa5, 0x5a, 0x1d, 0x35, 0xc5, 0xf3, 0x4f, 0x3b, 0x2c, 0x6d, 0xae, 0x4d, 0xb4}}
+	return a, nil
+}
+
+var _migrations202110250TxApproveAuditLogSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x8f\x41\x6b\x83\x40\x14\x84\xef\xef\x57\xcc\x31\xa1\xb5\x7f\x20\x27\x1b\xb7\x10\x6a\x55\x44\x09\x39\xc9\x1a\x1f\x76\x8b\xba\xcb\xee\xb3\x91\xfe\xfa\x4a\x02\xa1\x94\x42\xdf\x6d\xde\xc7\x0c\x33\x51\x84\x87\xd1\xf4\x5e\x0b\xa3\x76\x44\xfb\x52\xc5\x95\x42\x15\x3f\xa7\x0a\x6e\x6e\x07\x73\x7e\x92\xa5\xd1\xce\x79\xfb\xc9\x8d\x9e\x3b\x23\xcd\x60\x7b\x6c\x08\xeb\x99\x0e\xad\xe9\x03\x7b\xa3\x07\x14\xe5\xe1\x2d\x2e\x4f\x78\x55\xa7\xc7\x2b\x95\x05\xc2\x8b\x20\xcb\x2b\x64\x75\x9a\xde\xbe\x41\xb4\xcc\xe1\x2f\xe2\x39\x38\x3b\x05\xc6\x47\xb0\x53\xfb\x0b\x9e\x3d\xaf\x25\xbb\x46\x0b\xc4\x8c\xbc\xa6\x8c\x0e\x17\x23\xef\x57\x89\x2f\x3b\xf1\xdd\x81\x44\xbd\xc4\x75\xba\x8a\xfc\xb8\xd9\xd2\x76\x47\x14\xfd\x18\x9a\xd8\xcb\x44\x94\x94\x79\xf1\xff\xd0\x1d\x7d\x03\xc9\xa5\xce\x75\x23\x01\x00\x00")
+
+func migrations202110250TxApproveAuditLogSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations202110250TxApproveAuditLogSql,
+		"migrations/2021-10-25.0.tx-approve-audit-log.sql",
+	)
+}
+
+func migrations202110250TxApproveAuditLogSql() (*asset, error) {
+	bytes, err := migrations202110250TxApproveAuditLogSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/2021-10-25.0.tx-approve-audit-log.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x17, 0x8b, 0x10, 0xa7, 0xba, 0x46, 0x3, 0xee, 0x2b, 0x59, 0x35, 0x8f, 0x1a, 0x67, 0x59, 0x4d, 0xb9, 0x88, 0x72, 0x33, 0xbc, 0xe6, 0xf1, 0xbb, 0x6d, 0xdc, 0x4, 0xec, 0xd7, 0xdb, 0x46, 0x6d}}
+	return a, nil
+}
+
+var _migrations202111080ClawbackRequestsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x85\x91\xc1\x4e\x84\x40\x0c\x86\xef\xf3\x14\xbd\xed\x6e\x14\x5f\x60\x4f\x28\x98\x18\x11\x36\x04\x62\xf6\x44\x66\x98\x66\x19\x05\x06\xa7\x25\xa0\x4f\x2f\x59\x34\xb2\xba\x59\xe7\xd4\xf9\xbf\xf6\x6f\xda\x7a\x1e\x5c\x35\xe6\xe0\x24\x23\xe4\x9d\x10\x77\x69\xe8\x67\x21\x64\xfe\x6d\x14\x42\xd7\xab\xda\x94\x37\x65\x2d\x07\x25\xcb\xd7\xc2\xe1\x5b\x8f\xc4\x04\x6b\x01\xd3\x33\x1a\x94\x39\x10\x3a\x23\x6b\xd8\xa5\x0f\x4f\x7e\xba\x87\xc7\x70\x7f\x7d\xa4\x92\x08\xb9\x28\xad\x46\x60\x1c\x19\xe2\x24\x83\x38\x8f\xa2\x25\x35\x44\x3d\xba\x73\xfc\xbb\x27\xc1\x0b\xd9\x56\xfd\xa2\x0e\xe5\xa4\x9e\xd6\x41\x10\xde\xfb\x79\x94\xc1\x6a\x35\x27\x11\x4b\xee\xe9\x9c\xf9\xd7\x1c\xa8\x0b\xf5\x7e\x99\x4b\x06\x36\xcd\x14\xcb\xa6\x83\xc1\x70\x75\xfc\xc2\x87\x6d\xf1\x6f\xe3\x38\x79\x5e\x6f\x66\x07\x8d\xa5\xd1\x3f\xfe\xa7\xe2\x05\xd3\x39\x91\xc7\x45\x15\x8f\x45\x25\xa9\x5a\x28\xe8\x9c\x9d\x97\x26\x36\x5b\x21\xbc\xc5\x09\x03\x3b\xb4\x42\x04\x69\xb2\xfb\xe7\x84\x5b\xf1\x09\xc1\xb6\xcb\xc3\xfa\x01\x00\x00")
+
+func migrations202111080ClawbackRequestsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations202111080ClawbackRequestsSql,
+		"migrations/2021-11-08.0.clawback-requests.sql",
+	)
+}
+
+func migrations202111080ClawbackRequestsSql() (*asset, error) {
+	bytes, err := migrations202111080ClawbackRequestsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/2021-11-08.0.clawback-requests.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2, 0x27, 0xa1, 0xce, 0x9a, 0xd8, 0xa4, 0x68, 0xf2, 0x1d, 0x1d, 0x26, 0xd5, 0xd1, 0xa1, 0x5, 0x66, 0xa3, 0x63, 0xe7, 0xd8, 0x57, 0xdb, 0xff, 0x2c, 0x43, 0xb3, 0xa1, 0x74, 0x16, 0x74, 0xab}}
+	return a, nil
+}
+
+var _migrations202112060RevisedTransactionsOriginalTxSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x8e\xb1\x0e\x82\x30\x14\x45\xf7\x7e\xc5\xdb\xa5\xfe\x00\x53\xb5\x6c\x55\x0c\x81\xb9\xa9\xd8\xc0\x4b\xa0\x25\xed\x53\xfb\xf9\x12\x07\x25\x1a\x4c\xbc\xeb\x3d\x39\x39\x9c\xc3\x66\xc4\x2e\x18\xb2\xd0\x4c\x8c\x09\x55\x17\x15\xd4\x62\xa7\x0a\x98\xae\xe7\x01\xdb\x6d\xb0\x37\x8c\xf6\xa2\x29\x18\x17\x4d\x4b\xe8\x5d\x64\x30\x4f\x48\x09\xfb\x52\x35\x87\x23\xf8\x80\x1d\x3a\x33\x68\x4a\xba\x37\xb1\x07\xb2\x89\xb2\x4f\xea\x65\x4a\x3f\xfe\x59\xaf\x47\x1b\xa3\xe9\xec\x93\xca\x19\xe3\x8b\x48\xe9\xef\xee\xbf\x4c\x59\x95\xa7\xb5\xce\xec\x8b\x78\x37\xae\x7c\x8b\xbe\x9c\x3d\x00\xf3\xc9\xb4\x4f\x3f\x01\x00\x00")
+
+func migrations202112060RevisedTransactionsOriginalTxSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations202112060RevisedTransactionsOriginalTxSql,
+		"migrations/2021-12-06.0.revised-transactions-original-tx.sql",
+	)
+}
+
+func migrations202112060RevisedTransactionsOriginalTxSql() (*asset, error) {
+	bytes, err := migrations202112060RevisedTransactionsOriginalTxSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/2021-12-06.0.revised-transactions-original-tx.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x38, 0xd7, 0xd8, 0x87, 0x18, 0xc6, 0xf7, 0x14, 0xcf, 0x52, 0xf3, 0x32, 0x60, 0x28, 0xfd, 0x2e, 0x56, 0xbe, 0x83, 0x7e, 0x19, 0xf4, 0xfd, 0xdc, 0x88, 0xf9, 0xd7, 0x3e, 0xcb, 0xb1, 0x19, 0xee}}
+	return a, nil
+}
+
+var _migrations202112070TxApproveAuditLogStateSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x91\xcb\x0e\x82\x30\x10\x45\xf7\xf3\x15\xb3\xd4\x28\xfe\x40\x57\x95\x76\x61\x82\x60\x08\x24\xee\x9a\xf2\x88\xa9\x41\xda\x40\x51\x3e\x5f\x50\x13\xd1\x10\x02\xb3\x9e\x73\xcf\x64\xae\xe3\xe0\xe6\xa6\x2e\x95\xb4\x39\xc6\x06\x80\x7a\x11\x0f\x31\xa2\x7b\x8f\xa3\x69\x92\x42\xa5\x3b\xdb\x0a\x69\x4c\xa5\xef\xb9\x90\x4d\xa6\xac\x28\xf4\x05\xb0\x1b\xca\x18\xba\x81\x17\x1f\x7d\xac\x6d\x1f\x70\xad\x75\x99\x10\x00\x37\xe4\x34\xe2\x78\xf0\x19\x3f\xe3\x18\x2e\xd2\x2a\xef\x80\x4c\x48\x2b\x54\xd6\x62\xe0\x4f\xc9\x70\xf5\x5d\x5f\x93\x19\xe9\xfd\x35\x4d\xbd\x54\xf2\xa6\xb6\xf8\x23\x03\x67\xf0\x20\xa6\x1f\x25\x00\x0b\x83\xd3\xc7\x3e\x91\x37\x7e\x04\x99\x4b\xff\x63\x8b\x8a\x79\x39\x86\xcd\x10\x78\x02\xb9\x7e\xeb\xf1\xe8\x01\x00\x00")
+
+func migrations202112070TxApproveAuditLogStateSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations202112070TxApproveAuditLogStateSql,
+		"migrations/2021-12-07.0.tx-approve-audit-log-state.sql",
+	)
+}
+
+func migrations202112070TxApproveAuditLogStateSql() (*asset, error) {
+	bytes, err := migrations202112070TxApproveAuditLogStateSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/2021-12-07.0.tx-approve-audit-log-state.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf3, 0xad, 0xed, 0xa1, 0x16, 0x36, 0x2d, 0xe1, 0x65, 0x5, 0xd9, 0xa5, 0x17, 0xd1, 0x7a, 0x83, 0x83, 0xee, 0xcc, 0x98, 0x96, 0xa9, 0x7f, 0x30, 0x3c, 0xfc, 0xb9, 0x1d, 0xaa, 0x22, 0x47, 0x10}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -201,19 +327,27 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"migrations/2021-05-05.0.initial.sql":             migrations202105050InitialSql,
-	"migrations/2021-05-18.0.accounts-kyc-status.sql": migrations202105180AccountsKycStatusSql,
+	"migrations/2021-05-05.0.initial.sql":                          migrations202105050InitialSql,
+	"migrations/2021-05-18.0.accounts-kyc-status.sql":              migrations202105180AccountsKycStatusSql,
+	"migrations/2021-06-01.0.kyc-case-id.sql":                      migrations202106010KycCaseIdSql,
+	"migrations/2021-06-15.0.revised-transactions.sql":             migrations202106150RevisedTransactionsSql,
+	"migrations/2021-10-25.0.tx-approve-audit-log.sql":             migrations202110250TxApproveAuditLogSql,
+	"migrations/2021-11-08.0.clawback-requests.sql":                migrations202111080ClawbackRequestsSql,
+	"migrations/2021-12-06.0.revised-transactions-original-tx.sql": migrations202112060RevisedTransactionsOriginalTxSql,
+	"migrations/2021-12-07.0.tx-approve-audit-log-state.sql":       migrations202112070TxApproveAuditLogStateSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -247,8 +381,14 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"migrations": &bintree{nil, map[string]*bintree{
-		"2021-05-05.0.initial.sql":             &bintree{migrations202105050InitialSql, map[string]*bintree{}},
-		"2021-05-18.0.accounts-kyc-status.sql": &bintree{migrations202105180AccountsKycStatusSql, map[string]*bintree{}},
+		"2021-05-05.0.initial.sql":                          &bintree{migrations202105050InitialSql, map[string]*bintree{}},
+		"2021-05-18.0.accounts-kyc-status.sql":              &bintree{migrations202105180AccountsKycStatusSql, map[string]*bintree{}},
+		"2021-06-01.0.kyc-case-id.sql":                      &bintree{migrations202106010KycCaseIdSql, map[string]*bintree{}},
+		"2021-06-15.0.revised-transactions.sql":             &bintree{migrations202106150RevisedTransactionsSql, map[string]*bintree{}},
+		"2021-10-25.0.tx-approve-audit-log.sql":             &bintree{migrations202110250TxApproveAuditLogSql, map[string]*bintree{}},
+		"2021-11-08.0.clawback-requests.sql":                &bintree{migrations202111080ClawbackRequestsSql, map[string]*bintree{}},
+		"2021-12-06.0.revised-transactions-original-tx.sql": &bintree{migrations202112060RevisedTransactionsOriginalTxSql, map[string]*bintree{}},
+		"2021-12-07.0.tx-approve-audit-log-state.sql":       &bintree{migrations202112070TxApproveAuditLogStateSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -151,6 +151,13 @@ func TestMigrate_upApplyAll(t *testing.T) {
 	wantIDs := []string{
 		"2021-05-05.0.initial.sql",
 		"2021-05-18.0.accounts-kyc-status.sql",
+		"2021-06-01.0.kyc-case-id.sql",
+		"2021-06-15.0.revised-transactions.sql",
+		"2021-10-25.0.tx-approve-audit-log.sql",
+		"2021-11-08.0.clawback-requests.sql",
+		"2021-12-06.0.revised-transactions-original-tx.sql",
+		"2021-12-07.0.tx-approve-audit-log-state.sql",
+		"cases-2021-06-15.0.initial.sql",
 	}
 	assert.Equal(t, wantIDs, ids)
 }
@@ -174,6 +181,13 @@ func TestMigrate_upApplyNone(t *testing.T) {
 	wantIDs := []string{
 		"2021-05-05.0.initial.sql",
 		"2021-05-18.0.accounts-kyc-status.sql",
+		"2021-06-01.0.kyc-case-id.sql",
+		"2021-06-15.0.revised-transactions.sql",
+		"2021-10-25.0.tx-approve-audit-log.sql",
+		"2021-11-08.0.clawback-requests.sql",
+		"2021-12-06.0.revised-transactions-original-tx.sql",
+		"2021-12-07.0.tx-approve-audit-log-state.sql",
+		"cases-2021-06-15.0.initial.sql",
 	}
 	assert.Equal(t, wantIDs, ids)
 }
@@ -0,0 +1,8 @@
+-- +migrate Up
+
+ALTER TABLE public.accounts_kyc_status ADD COLUMN kyc_case_id text;
+CREATE INDEX accounts_kyc_status_kyc_case_id_idx ON public.accounts_kyc_status (kyc_case_id);
+
+-- +migrate Down
+
+ALTER TABLE public.accounts_kyc_status DROP COLUMN kyc_case_id;
@@ -0,0 +1,14 @@
+-- +migrate Up
+
+CREATE TABLE public.revised_transactions (
+    source_account text NOT NULL,
+    sequence_number bigint NOT NULL,
+    tx_hash text NOT NULL,
+    valid_until timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source_account, sequence_number)
+);
+
+-- +migrate Down
+
+DROP TABLE public.revised_transactions;
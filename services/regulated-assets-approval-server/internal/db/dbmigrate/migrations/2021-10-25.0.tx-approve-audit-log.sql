@@ -0,0 +1,13 @@
+-- +migrate Up
+
+CREATE TABLE public.tx_approve_audit_log (
+    id bigserial PRIMARY KEY,
+    tx text NOT NULL,
+    status text NOT NULL,
+    response jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+-- +migrate Down
+
+DROP TABLE public.tx_approve_audit_log;
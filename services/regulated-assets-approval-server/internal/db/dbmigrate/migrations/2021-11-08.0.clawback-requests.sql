@@ -0,0 +1,21 @@
+-- +migrate Up
+
+CREATE TABLE public.clawback_requests (
+    id bigserial PRIMARY KEY,
+    asset_code text NOT NULL,
+    asset_issuer text NOT NULL,
+    clawbacks jsonb NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    status text NOT NULL,
+    requested_by text NOT NULL,
+    requested_at timestamp with time zone NOT NULL DEFAULT NOW(),
+    decided_by text,
+    decided_at timestamp with time zone,
+    tx text,
+    tx_hash text,
+    error text
+);
+
+-- +migrate Down
+
+DROP TABLE public.clawback_requests;
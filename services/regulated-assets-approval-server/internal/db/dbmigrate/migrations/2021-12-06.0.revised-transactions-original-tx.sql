@@ -0,0 +1,13 @@
+-- +migrate Up
+
+ALTER TABLE public.revised_transactions
+    ADD COLUMN original_tx_hash text,
+    ADD COLUMN revised_tx text,
+    ADD COLUMN revision_message text;
+
+-- +migrate Down
+
+ALTER TABLE public.revised_transactions
+    DROP COLUMN original_tx_hash,
+    DROP COLUMN revised_tx,
+    DROP COLUMN revision_message;
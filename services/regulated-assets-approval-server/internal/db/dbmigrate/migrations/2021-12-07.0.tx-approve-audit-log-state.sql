@@ -0,0 +1,15 @@
+-- +migrate Up
+
+ALTER TABLE public.tx_approve_audit_log
+    ADD COLUMN state jsonb;
+
+CREATE INDEX tx_approve_audit_log_created_at_idx ON public.tx_approve_audit_log (created_at);
+CREATE INDEX tx_approve_audit_log_status_created_at_idx ON public.tx_approve_audit_log (status, created_at);
+
+-- +migrate Down
+
+DROP INDEX public.tx_approve_audit_log_status_created_at_idx;
+DROP INDEX public.tx_approve_audit_log_created_at_idx;
+
+ALTER TABLE public.tx_approve_audit_log
+    DROP COLUMN state;
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/exp/compliance/cases"
 	"github.com/stellar/go/support/db/dbtest"
 )
 
@@ -35,5 +36,9 @@ func Open(t *testing.T) *dbtest.DB {
 	if err != nil {
 		t.Fatal(err)
 	}
+	_, err = migrate.Exec(conn.DB, "postgres", cases.Migrations(), migrate.Up)
+	if err != nil {
+		t.Fatal(err)
+	}
 	return db
 }
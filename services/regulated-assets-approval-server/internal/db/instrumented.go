@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stellar/go/support/errors"
+)
+
+// QueryObserver is called with the duration of every query run on a
+// database opened with OpenInstrumented.
+type QueryObserver func(query string, duration time.Duration)
+
+// OpenInstrumented opens the database like Open, calling observe with the
+// duration of every query and statement executed on it, within transactions
+// or not.
+func OpenInstrumented(dataSourceName string, observe QueryObserver) (*sqlx.DB, error) {
+	connector, err := pq.NewConnector(dataSourceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing database url")
+	}
+	db := sql.OpenDB(instrumentedConnector{Connector: connector, observe: observe})
+	return sqlx.NewDb(db, "postgres"), nil
+}
+
+type instrumentedConnector struct {
+	driver.Connector
+	observe QueryObserver
+}
+
+func (c instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return instrumentedConn{Conn: conn, observe: c.observe}, nil
+}
+
+// instrumentedConn times the queries run by database/sql with the
+// QueryerContext and ExecerContext interfaces of the wrapped connection,
+// which is how lib/pq runs all the queries that are not explicitly prepared.
+type instrumentedConn struct {
+	driver.Conn
+	observe QueryObserver
+}
+
+func (c instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.observe(query, time.Since(start))
+	return rows, err
+}
+
+func (c instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.observe(query, time.Since(start))
+	return result, err
+}
+
+func (c instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package keyrotation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/txnbuild"
+)
+
+// Rotation replaces the key signing the revised transactions of an issuer
+// account with a new one. The new key is added as a signer of the issuer
+// account with the weight of the old key, which is only removed once no
+// transaction signed by it was submitted for the overlap window, so that the
+// transactions signed by the old key before the server switched to the new
+// one can still be submitted.
+type Rotation struct {
+	HorizonClient     horizonclient.ClientInterface
+	NetworkPassphrase string
+	// IssuerAddress is the issuer account, it defaults to the address of
+	// OldSigner.
+	IssuerAddress string
+	// OldSigner is the key currently signing the revised transactions. It
+	// signs the transactions changing the signers of the issuer account.
+	OldSigner        *keypair.Full
+	NewSignerAddress string
+	// OverlapWindow is the time both keys remain signers of the issuer
+	// account after the last transaction signed by the old key. It should be
+	// longer than the time bounds of the revised transactions.
+	OverlapWindow time.Duration
+	// PollInterval is the time between the lookups of the transactions of
+	// the issuer account signed by the old key, 10 seconds if 0.
+	PollInterval time.Duration
+}
+
+func (r Rotation) validate() error {
+	if r.HorizonClient == nil {
+		return errors.New("horizon client cannot be nil")
+	}
+	if r.NetworkPassphrase == "" {
+		return errors.New("network passphrase cannot be empty")
+	}
+	if r.OldSigner == nil {
+		return errors.New("old signer cannot be nil")
+	}
+	if _, err := keypair.ParseAddress(r.NewSignerAddress); err != nil {
+		return errors.Errorf("new signer %q is not a valid Stellar address", r.NewSignerAddress)
+	}
+	if r.NewSignerAddress == r.OldSigner.Address() {
+		return errors.New("new signer cannot be the old signer")
+	}
+	if r.OverlapWindow < 0 {
+		return errors.New("overlap window cannot be negative")
+	}
+	return nil
+}
+
+func (r Rotation) issuerAddress() string {
+	if r.IssuerAddress != "" {
+		return r.IssuerAddress
+	}
+	return r.OldSigner.Address()
+}
+
+func (r Rotation) pollInterval() time.Duration {
+	if r.PollInterval > 0 {
+		return r.PollInterval
+	}
+	return 10 * time.Second
+}
+
+// Run adds the new signer, waits until no transaction of the issuer account
+// signed by the old signer was seen on the network for the overlap window,
+// and removes the old signer. The server should be restarted with the new key
+// as soon as it was added: the old signer is not removed while the server
+// keeps signing revised transactions with it, which are submitted by their
+// senders.
+func (r Rotation) Run(ctx context.Context) error {
+	if err := r.AddNewSigner(ctx); err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Infof("Restart the server with the new signer %s, the old signer %s will be removed once it did not sign any transaction for %s", r.NewSignerAddress, r.OldSigner.Address(), r.OverlapWindow)
+	lastUsed := time.Now()
+	cursor := "now"
+	for time.Since(lastUsed) < r.OverlapWindow {
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "waiting for the overlap window, the old signer was not removed")
+		case <-time.After(r.pollInterval()):
+		}
+
+		var used bool
+		var err error
+		used, cursor, err = r.oldSignerUsed(cursor)
+		if err != nil {
+			return errors.Wrap(err, "looking up the transactions signed by the old signer, it was not removed")
+		}
+		if used {
+			lastUsed = time.Now()
+			log.Ctx(ctx).Infof("%s still signs transactions of issuer account %s, it will be removed once it did not sign any for %s", r.OldSigner.Address(), r.issuerAddress(), r.OverlapWindow)
+		}
+	}
+
+	return r.RemoveOldSigner(ctx)
+}
+
+// oldSignerUsed returns true if one of the transactions of the issuer account
+// after cursor is signed by the old signer, along with the cursor of the last
+// transaction.
+func (r Rotation) oldSignerUsed(cursor string) (bool, string, error) {
+	used := false
+	for {
+		page, err := r.HorizonClient.Transactions(horizonclient.TransactionRequest{
+			ForAccount: r.issuerAddress(),
+			Cursor:     cursor,
+			Order:      horizonclient.OrderAsc,
+			Limit:      200,
+		})
+		if err != nil {
+			return false, cursor, errors.Wrapf(err, "getting transactions of issuer account %s", r.issuerAddress())
+		}
+		for _, tx := range page.Embedded.Records {
+			cursor = tx.PT
+			if r.signedByOldSigner(tx) {
+				used = true
+			}
+		}
+		if len(page.Embedded.Records) < 200 {
+			return used, cursor, nil
+		}
+	}
+}
+
+// signedByOldSigner returns true if the transaction, or the inner transaction
+// of a fee bump transaction, has a signature of the old signer.
+func (r Rotation) signedByOldSigner(tx hProtocol.Transaction) bool {
+	hashHex, signatures := tx.Hash, tx.Signatures
+	if tx.InnerTransaction != nil {
+		hashHex, signatures = tx.InnerTransaction.Hash, tx.InnerTransaction.Signatures
+	}
+	hash, err := hex.DecodeString(hashHex)
+	if err != nil {
+		return false
+	}
+	for _, s := range signatures {
+		signature, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			continue
+		}
+		if r.OldSigner.Verify(hash, signature) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// AddNewSigner adds the new signer to the issuer account with the weight of
+// the old signer.
+func (r Rotation) AddNewSigner(ctx context.Context) error {
+	if err := r.validate(); err != nil {
+		return errors.Wrap(err, "validating key rotation")
+	}
+
+	account, err := r.HorizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: r.issuerAddress()})
+	if err != nil {
+		return errors.Wrapf(err, "getting detail for issuer account %s", r.issuerAddress())
+	}
+	oldWeight := signerWeight(account, r.OldSigner.Address())
+	if oldWeight == 0 {
+		return errors.Errorf("%s is not a signer of issuer account %s", r.OldSigner.Address(), r.issuerAddress())
+	}
+	if oldWeight < int32(account.Thresholds.HighThreshold) {
+		return errors.Errorf("the weight %d of %s is below the high threshold %d required to change the signers of issuer account %s", oldWeight, r.OldSigner.Address(), account.Thresholds.HighThreshold, r.issuerAddress())
+	}
+	if signerWeight(account, r.NewSignerAddress) == oldWeight {
+		log.Ctx(ctx).Infof("%s is already a signer of issuer account %s", r.NewSignerAddress, r.issuerAddress())
+		return nil
+	}
+
+	err = r.submitSetOptions(&account, &txnbuild.SetOptions{
+		Signer: &txnbuild.Signer{Address: r.NewSignerAddress, Weight: txnbuild.Threshold(oldWeight)},
+	})
+	if err != nil {
+		return errors.Wrap(err, "adding new signer")
+	}
+	log.Ctx(ctx).Infof("Added %s as signer of issuer account %s with weight %d", r.NewSignerAddress, r.issuerAddress(), oldWeight)
+	return nil
+}
+
+// RemoveOldSigner removes the old signer from the issuer account, or sets
+// the weight of its master key to zero if the old signer is the master key.
+// It fails if the new signer was not added first.
+func (r Rotation) RemoveOldSigner(ctx context.Context) error {
+	if err := r.validate(); err != nil {
+		return errors.Wrap(err, "validating key rotation")
+	}
+
+	account, err := r.HorizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: r.issuerAddress()})
+	if err != nil {
+		return errors.Wrapf(err, "getting detail for issuer account %s", r.issuerAddress())
+	}
+	if signerWeight(account, r.NewSignerAddress) == 0 {
+		return errors.Errorf("refusing to remove %s, the new signer %s is not a signer of issuer account %s", r.OldSigner.Address(), r.NewSignerAddress, r.issuerAddress())
+	}
+	if signerWeight(account, r.OldSigner.Address()) == 0 {
+		log.Ctx(ctx).Infof("%s is not a signer of issuer account %s anymore", r.OldSigner.Address(), r.issuerAddress())
+		return nil
+	}
+
+	op := &txnbuild.SetOptions{}
+	if r.OldSigner.Address() == r.issuerAddress() {
+		op.MasterWeight = txnbuild.NewThreshold(0)
+	} else {
+		op.Signer = &txnbuild.Signer{Address: r.OldSigner.Address(), Weight: 0}
+	}
+	err = r.submitSetOptions(&account, op)
+	if err != nil {
+		return errors.Wrap(err, "removing old signer")
+	}
+	log.Ctx(ctx).Infof("Removed %s from the signers of issuer account %s", r.OldSigner.Address(), r.issuerAddress())
+	return nil
+}
+
+func (r Rotation) submitSetOptions(account *hProtocol.Account, op *txnbuild.SetOptions) error {
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        account,
+		IncrementSequenceNum: true,
+		Operations:           []txnbuild.Operation{op},
+		BaseFee:              txnbuild.MinBaseFee,
+		Timebounds:           txnbuild.NewTimeout(300),
+	})
+	if err != nil {
+		return errors.Wrap(err, "building transaction")
+	}
+	tx, err = tx.Sign(r.NetworkPassphrase, r.OldSigner)
+	if err != nil {
+		return errors.Wrap(err, "signing transaction")
+	}
+	_, err = r.HorizonClient.SubmitTransaction(tx)
+	if err != nil {
+		return httperror.ParseHorizonError(err)
+	}
+	return nil
+}
+
+// signerWeight returns the weight of a signer of the account, or zero if it
+// is not one of its signers.
+func signerWeight(account hProtocol.Account, address string) int32 {
+	for _, s := range account.Signers {
+		if s.Key == address {
+			return s.Weight
+		}
+	}
+	return 0
+}
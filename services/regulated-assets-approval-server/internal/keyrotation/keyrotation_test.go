@@ -0,0 +1,306 @@
+package keyrotation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func issuerAccount(address string, signers ...hProtocol.Signer) hProtocol.Account {
+	return hProtocol.Account{
+		AccountID:  address,
+		Sequence:   "10",
+		Signers:    signers,
+		Thresholds: hProtocol.AccountThresholds{LowThreshold: 1, MedThreshold: 1, HighThreshold: 1},
+	}
+}
+
+// submittedSetOptions returns the SetOptions operation of the transaction
+// submitted to the mock, after checking that it is signed by signer.
+func submittedSetOptions(t *testing.T, horizonMock *horizonclient.MockClient, signer *keypair.Full) *txnbuild.SetOptions {
+	var submitted *txnbuild.Transaction
+	for _, call := range horizonMock.Calls {
+		if call.Method == "SubmitTransaction" {
+			submitted = call.Arguments.Get(0).(*txnbuild.Transaction)
+		}
+	}
+	require.NotNil(t, submitted)
+	assert.Equal(t, int64(11), submitted.SourceAccount().Sequence)
+	require.Len(t, submitted.Signatures(), 1)
+	hash, err := submitted.Hash(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.NoError(t, signer.Verify(hash[:], submitted.Signatures()[0].Signature))
+
+	require.Len(t, submitted.Operations(), 1)
+	op, ok := submitted.Operations()[0].(*txnbuild.SetOptions)
+	require.True(t, ok)
+	return op
+}
+
+func TestRotation_validate(t *testing.T) {
+	oldKP := keypair.MustRandom()
+	r := Rotation{}
+	assert.EqualError(t, r.validate(), "horizon client cannot be nil")
+	r.HorizonClient = &horizonclient.MockClient{}
+	assert.EqualError(t, r.validate(), "network passphrase cannot be empty")
+	r.NetworkPassphrase = network.TestNetworkPassphrase
+	assert.EqualError(t, r.validate(), "old signer cannot be nil")
+	r.OldSigner = oldKP
+	r.NewSignerAddress = "foo"
+	assert.EqualError(t, r.validate(), `new signer "foo" is not a valid Stellar address`)
+	r.NewSignerAddress = oldKP.Address()
+	assert.EqualError(t, r.validate(), "new signer cannot be the old signer")
+	r.NewSignerAddress = keypair.MustRandom().Address()
+	assert.NoError(t, r.validate())
+}
+
+func TestRotation_AddNewSigner(t *testing.T) {
+	ctx := context.Background()
+	oldKP := keypair.MustRandom()
+	newAddress := keypair.MustRandom().Address()
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: oldKP.Address()}).
+		Return(issuerAccount(oldKP.Address(), hProtocol.Signer{Key: oldKP.Address(), Weight: 2}), nil)
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(hProtocol.Transaction{}, nil)
+
+	r := Rotation{
+		HorizonClient:     &horizonMock,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		OldSigner:         oldKP,
+		NewSignerAddress:  newAddress,
+	}
+	err := r.AddNewSigner(ctx)
+	require.NoError(t, err)
+
+	op := submittedSetOptions(t, &horizonMock, oldKP)
+	assert.Equal(t, &txnbuild.Signer{Address: newAddress, Weight: 2}, op.Signer)
+	assert.Nil(t, op.MasterWeight)
+}
+
+func TestRotation_AddNewSigner_oldSignerCannotChangeSigners(t *testing.T) {
+	ctx := context.Background()
+	issuerAddress := keypair.MustRandom().Address()
+	oldKP := keypair.MustRandom()
+	newAddress := keypair.MustRandom().Address()
+
+	account := issuerAccount(issuerAddress, hProtocol.Signer{Key: oldKP.Address(), Weight: 1})
+	account.Thresholds.HighThreshold = 2
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: issuerAddress}).
+		Return(account, nil)
+
+	r := Rotation{
+		HorizonClient:     &horizonMock,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		IssuerAddress:     issuerAddress,
+		OldSigner:         oldKP,
+		NewSignerAddress:  newAddress,
+	}
+	err := r.AddNewSigner(ctx)
+	assert.EqualError(t, err, "the weight 1 of "+oldKP.Address()+" is below the high threshold 2 required to change the signers of issuer account "+issuerAddress)
+
+	r.OldSigner = keypair.MustRandom()
+	err = r.AddNewSigner(ctx)
+	assert.EqualError(t, err, r.OldSigner.Address()+" is not a signer of issuer account "+issuerAddress)
+	horizonMock.AssertNotCalled(t, "SubmitTransaction", mock.Anything)
+}
+
+func TestRotation_RemoveOldSigner_masterKey(t *testing.T) {
+	ctx := context.Background()
+	oldKP := keypair.MustRandom()
+	newAddress := keypair.MustRandom().Address()
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: oldKP.Address()}).
+		Return(issuerAccount(
+			oldKP.Address(),
+			hProtocol.Signer{Key: newAddress, Weight: 1},
+			hProtocol.Signer{Key: oldKP.Address(), Weight: 1},
+		), nil)
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(hProtocol.Transaction{}, nil)
+
+	r := Rotation{
+		HorizonClient:     &horizonMock,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		OldSigner:         oldKP,
+		NewSignerAddress:  newAddress,
+	}
+	err := r.RemoveOldSigner(ctx)
+	require.NoError(t, err)
+
+	op := submittedSetOptions(t, &horizonMock, oldKP)
+	assert.Equal(t, txnbuild.NewThreshold(0), op.MasterWeight)
+	assert.Nil(t, op.Signer)
+}
+
+func TestRotation_RemoveOldSigner_otherSigner(t *testing.T) {
+	ctx := context.Background()
+	issuerAddress := keypair.MustRandom().Address()
+	oldKP := keypair.MustRandom()
+	newAddress := keypair.MustRandom().Address()
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: issuerAddress}).
+		Return(issuerAccount(
+			issuerAddress,
+			hProtocol.Signer{Key: newAddress, Weight: 1},
+			hProtocol.Signer{Key: oldKP.Address(), Weight: 1},
+		), nil)
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(hProtocol.Transaction{}, nil)
+
+	r := Rotation{
+		HorizonClient:     &horizonMock,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		IssuerAddress:     issuerAddress,
+		OldSigner:         oldKP,
+		NewSignerAddress:  newAddress,
+	}
+	err := r.RemoveOldSigner(ctx)
+	require.NoError(t, err)
+
+	op := submittedSetOptions(t, &horizonMock, oldKP)
+	assert.Equal(t, &txnbuild.Signer{Address: oldKP.Address(), Weight: 0}, op.Signer)
+	assert.Nil(t, op.MasterWeight)
+}
+
+func TestRotation_RemoveOldSigner_newSignerMissing(t *testing.T) {
+	ctx := context.Background()
+	oldKP := keypair.MustRandom()
+	newAddress := keypair.MustRandom().Address()
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: oldKP.Address()}).
+		Return(issuerAccount(oldKP.Address(), hProtocol.Signer{Key: oldKP.Address(), Weight: 1}), nil)
+
+	r := Rotation{
+		HorizonClient:     &horizonMock,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		OldSigner:         oldKP,
+		NewSignerAddress:  newAddress,
+	}
+	err := r.RemoveOldSigner(ctx)
+	assert.EqualError(t, err, "refusing to remove "+oldKP.Address()+", the new signer "+newAddress+" is not a signer of issuer account "+oldKP.Address())
+	horizonMock.AssertNotCalled(t, "SubmitTransaction", mock.Anything)
+}
+
+func TestRotation_Run(t *testing.T) {
+	ctx := context.Background()
+	oldKP := keypair.MustRandom()
+	newAddress := keypair.MustRandom().Address()
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: oldKP.Address()}).
+		Return(issuerAccount(oldKP.Address(), hProtocol.Signer{Key: oldKP.Address(), Weight: 1}), nil).
+		Once()
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: oldKP.Address()}).
+		Return(issuerAccount(
+			oldKP.Address(),
+			hProtocol.Signer{Key: newAddress, Weight: 1},
+			hProtocol.Signer{Key: oldKP.Address(), Weight: 1},
+		), nil).
+		Once()
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(hProtocol.Transaction{}, nil).
+		Twice()
+
+	r := Rotation{
+		HorizonClient:     &horizonMock,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		OldSigner:         oldKP,
+		NewSignerAddress:  newAddress,
+	}
+	err := r.Run(ctx)
+	require.NoError(t, err)
+	horizonMock.AssertExpectations(t)
+
+	op := submittedSetOptions(t, &horizonMock, oldKP)
+	assert.Equal(t, txnbuild.NewThreshold(0), op.MasterWeight)
+}
+
+func TestRotation_Run_waitsForOldSignerTransactions(t *testing.T) {
+	ctx := context.Background()
+	oldKP := keypair.MustRandom()
+	newAddress := keypair.MustRandom().Address()
+
+	hash := make([]byte, 32)
+	signature, err := oldKP.Sign(hash)
+	require.NoError(t, err)
+	signedTx := hProtocol.Transaction{
+		PT:         "5",
+		Hash:       hex.EncodeToString(hash),
+		Signatures: []string{base64.StdEncoding.EncodeToString(signature)},
+	}
+	page := hProtocol.TransactionsPage{}
+	page.Embedded.Records = []hProtocol.Transaction{signedTx}
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: oldKP.Address()}).
+		Return(issuerAccount(oldKP.Address(), hProtocol.Signer{Key: oldKP.Address(), Weight: 1}), nil).
+		Once()
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: oldKP.Address()}).
+		Return(issuerAccount(
+			oldKP.Address(),
+			hProtocol.Signer{Key: newAddress, Weight: 1},
+			hProtocol.Signer{Key: oldKP.Address(), Weight: 1},
+		), nil).
+		Once()
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(hProtocol.Transaction{}, nil).
+		Twice()
+	// The old signer still signs a transaction once the new signer is
+	// added, and none after it.
+	horizonMock.
+		On("Transactions", horizonclient.TransactionRequest{ForAccount: oldKP.Address(), Cursor: "now", Order: horizonclient.OrderAsc, Limit: 200}).
+		Return(page, nil).
+		Once()
+	horizonMock.
+		On("Transactions", horizonclient.TransactionRequest{ForAccount: oldKP.Address(), Cursor: "5", Order: horizonclient.OrderAsc, Limit: 200}).
+		Return(hProtocol.TransactionsPage{}, nil)
+
+	r := Rotation{
+		HorizonClient:     &horizonMock,
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		OldSigner:         oldKP,
+		NewSignerAddress:  newAddress,
+		OverlapWindow:     100 * time.Millisecond,
+		PollInterval:      10 * time.Millisecond,
+	}
+	start := time.Now()
+	err = r.Run(ctx)
+	require.NoError(t, err)
+	horizonMock.AssertExpectations(t)
+	// The overlap window restarted when the signed transaction was seen.
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(110*time.Millisecond))
+
+	op := submittedSetOptions(t, &horizonMock, oldKP)
+	assert.Equal(t, txnbuild.NewThreshold(0), op.MasterWeight)
+}
@@ -0,0 +1,19 @@
+// Package adminauth carries the identity of the admins calling the admin API,
+// who authenticate with their own API key.
+package adminauth
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns a context carrying the name of the authenticated admin.
+func NewContext(ctx context.Context, admin string) context.Context {
+	return context.WithValue(ctx, contextKey{}, admin)
+}
+
+// FromContext returns the name of the admin authenticated for the request of
+// ctx, or an empty string if there is none.
+func FromContext(ctx context.Context) string {
+	admin, _ := ctx.Value(contextKey{}).(string)
+	return admin
+}
@@ -344,7 +344,7 @@ func TestAPI_RejectedIntegration(t *testing.T) {
 
 	// TEST "rejected" response if more than one operation in transaction.
 	wantBody = `{
-		"status":"rejected", "error":"Please submit a transaction with exactly one operation of type payment."
+		"status":"rejected", "error":"Please submit a transaction with exactly one operation of type payment, path_payment_strict_send or path_payment_strict_receive."
 	}`
 	require.JSONEq(t, wantBody, string(body))
 
@@ -505,36 +505,36 @@ func TestAPI_RevisedIntegration(t *testing.T) {
 
 	// Check if revised transaction only has 5 operations.
 	require.Len(t, tx.Operations(), 5)
-	// Check Operation 1: AllowTrust op where issuer fully authorizes account A, asset X.
-	op1, ok := tx.Operations()[0].(*txnbuild.AllowTrust)
+	// Check Operation 1: SetTrustLineFlags op where issuer fully authorizes account A, asset X.
+	op1, ok := tx.Operations()[0].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op1.Trustor, senderAccKP.Address())
-	assert.Equal(t, op1.Type.GetCode(), assetGOAT.GetCode())
-	require.True(t, op1.Authorize)
-	// Check  Operation 2: AllowTrust op where issuer fully authorizes account B, asset X.
-	op2, ok := tx.Operations()[1].(*txnbuild.AllowTrust)
+	assert.Equal(t, op1.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op1.SetFlags)
+	// Check  Operation 2: SetTrustLineFlags op where issuer fully authorizes account B, asset X.
+	op2, ok := tx.Operations()[1].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op2.Trustor, receiverAccKP.Address())
-	assert.Equal(t, op2.Type.GetCode(), assetGOAT.GetCode())
-	require.True(t, op2.Authorize)
+	assert.Equal(t, op2.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op2.SetFlags)
 	// Check Operation 3: Payment from A to B.
 	op3, ok := tx.Operations()[2].(*txnbuild.Payment)
 	require.True(t, ok)
 	assert.Equal(t, op3.SourceAccount, senderAccKP.Address())
 	assert.Equal(t, op3.Destination, receiverAccKP.Address())
 	assert.Equal(t, op3.Asset, assetGOAT)
-	// Check Operation 4: AllowTrust op where issuer fully deauthorizes account B, asset X.
-	op4, ok := tx.Operations()[3].(*txnbuild.AllowTrust)
+	// Check Operation 4: SetTrustLineFlags op where issuer fully deauthorizes account B, asset X.
+	op4, ok := tx.Operations()[3].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op4.Trustor, receiverAccKP.Address())
-	assert.Equal(t, op4.Type.GetCode(), assetGOAT.GetCode())
-	require.False(t, op4.Authorize)
-	// Check Operation 5: AllowTrust op where issuer fully deauthorizes account A, asset X.
-	op5, ok := tx.Operations()[4].(*txnbuild.AllowTrust)
+	assert.Equal(t, op4.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op4.ClearFlags)
+	// Check Operation 5: SetTrustLineFlags op where issuer fully deauthorizes account A, asset X.
+	op5, ok := tx.Operations()[4].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op5.Trustor, senderAccKP.Address())
-	assert.Equal(t, op5.Type.GetCode(), assetGOAT.GetCode())
-	require.False(t, op5.Authorize)
+	assert.Equal(t, op5.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op5.ClearFlags)
 }
 
 func TestAPI_KYCIntegration(t *testing.T) {
@@ -718,36 +718,36 @@ func TestAPI_KYCIntegration(t *testing.T) {
 
 	// Check if revised transaction only has 5 operations.
 	require.Len(t, tx.Operations(), 5)
-	// Check Operation 1: AllowTrust op where issuer fully authorizes account A, asset X.
-	op1, ok := tx.Operations()[0].(*txnbuild.AllowTrust)
+	// Check Operation 1: SetTrustLineFlags op where issuer fully authorizes account A, asset X.
+	op1, ok := tx.Operations()[0].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op1.Trustor, senderAccKP.Address())
-	assert.Equal(t, op1.Type.GetCode(), assetGOAT.GetCode())
-	require.True(t, op1.Authorize)
-	// Check  Operation 2: AllowTrust op where issuer fully authorizes account B, asset X.
-	op2, ok := tx.Operations()[1].(*txnbuild.AllowTrust)
+	assert.Equal(t, op1.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op1.SetFlags)
+	// Check  Operation 2: SetTrustLineFlags op where issuer fully authorizes account B, asset X.
+	op2, ok := tx.Operations()[1].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op2.Trustor, receiverAccKP.Address())
-	assert.Equal(t, op2.Type.GetCode(), assetGOAT.GetCode())
-	require.True(t, op2.Authorize)
+	assert.Equal(t, op2.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op2.SetFlags)
 	// Check Operation 3: Payment from A to B.
 	op3, ok := tx.Operations()[2].(*txnbuild.Payment)
 	require.True(t, ok)
 	assert.Equal(t, op3.SourceAccount, senderAccKP.Address())
 	assert.Equal(t, op3.Destination, receiverAccKP.Address())
 	assert.Equal(t, op3.Asset, assetGOAT)
-	// Check Operation 4: AllowTrust op where issuer fully deauthorizes account B, asset X.
-	op4, ok := tx.Operations()[3].(*txnbuild.AllowTrust)
+	// Check Operation 4: SetTrustLineFlags op where issuer fully deauthorizes account B, asset X.
+	op4, ok := tx.Operations()[3].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op4.Trustor, receiverAccKP.Address())
-	assert.Equal(t, op4.Type.GetCode(), assetGOAT.GetCode())
-	require.False(t, op4.Authorize)
-	// Check Operation 5: AllowTrust op where issuer fully deauthorizes account A, asset X.
-	op5, ok := tx.Operations()[4].(*txnbuild.AllowTrust)
+	assert.Equal(t, op4.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op4.ClearFlags)
+	// Check Operation 5: SetTrustLineFlags op where issuer fully deauthorizes account A, asset X.
+	op5, ok := tx.Operations()[4].(*txnbuild.SetTrustLineFlags)
 	require.True(t, ok)
 	assert.Equal(t, op5.Trustor, senderAccKP.Address())
-	assert.Equal(t, op5.Type.GetCode(), assetGOAT.GetCode())
-	require.False(t, op5.Authorize)
+	assert.Equal(t, op5.Asset.GetCode(), assetGOAT.GetCode())
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, op5.ClearFlags)
 
 	// Prepare and send /kyc-status/{callback_id} POST request; with an email_address that starts with "x".
 	req = `{
@@ -0,0 +1,681 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: approval.proto
+
+package approvalpb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type TxApproveRequest struct {
+	// Base64 encoded transaction envelope.
+	Tx                   string   `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxApproveRequest) Reset()         { *m = TxApproveRequest{} }
+func (m *TxApproveRequest) String() string { return proto.CompactTextString(m) }
+func (*TxApproveRequest) ProtoMessage()    {}
+func (*TxApproveRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{0}
+}
+
+func (m *TxApproveRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxApproveRequest.Unmarshal(m, b)
+}
+func (m *TxApproveRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxApproveRequest.Marshal(b, m, deterministic)
+}
+func (m *TxApproveRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxApproveRequest.Merge(m, src)
+}
+func (m *TxApproveRequest) XXX_Size() int {
+	return xxx_messageInfo_TxApproveRequest.Size(m)
+}
+func (m *TxApproveRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxApproveRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxApproveRequest proto.InternalMessageInfo
+
+func (m *TxApproveRequest) GetTx() string {
+	if m != nil {
+		return m.Tx
+	}
+	return ""
+}
+
+type TxApproveResponse struct {
+	// One of "revised", "pending", "action_required" or "rejected".
+	Status       string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Error        string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Message      string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Tx           string   `protobuf:"bytes,4,opt,name=tx,proto3" json:"tx,omitempty"`
+	ActionUrl    string   `protobuf:"bytes,5,opt,name=action_url,json=actionUrl,proto3" json:"action_url,omitempty"`
+	ActionMethod string   `protobuf:"bytes,6,opt,name=action_method,json=actionMethod,proto3" json:"action_method,omitempty"`
+	ActionFields []string `protobuf:"bytes,7,rep,name=action_fields,json=actionFields,proto3" json:"action_fields,omitempty"`
+	// Number of milliseconds to wait before submitting the transaction again
+	// when the status is "pending", 0 if it cannot be determined.
+	Timeout              int64    `protobuf:"varint,8,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxApproveResponse) Reset()         { *m = TxApproveResponse{} }
+func (m *TxApproveResponse) String() string { return proto.CompactTextString(m) }
+func (*TxApproveResponse) ProtoMessage()    {}
+func (*TxApproveResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{1}
+}
+
+func (m *TxApproveResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxApproveResponse.Unmarshal(m, b)
+}
+func (m *TxApproveResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxApproveResponse.Marshal(b, m, deterministic)
+}
+func (m *TxApproveResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxApproveResponse.Merge(m, src)
+}
+func (m *TxApproveResponse) XXX_Size() int {
+	return xxx_messageInfo_TxApproveResponse.Size(m)
+}
+func (m *TxApproveResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxApproveResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxApproveResponse proto.InternalMessageInfo
+
+func (m *TxApproveResponse) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *TxApproveResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *TxApproveResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *TxApproveResponse) GetTx() string {
+	if m != nil {
+		return m.Tx
+	}
+	return ""
+}
+
+func (m *TxApproveResponse) GetActionUrl() string {
+	if m != nil {
+		return m.ActionUrl
+	}
+	return ""
+}
+
+func (m *TxApproveResponse) GetActionMethod() string {
+	if m != nil {
+		return m.ActionMethod
+	}
+	return ""
+}
+
+func (m *TxApproveResponse) GetActionFields() []string {
+	if m != nil {
+		return m.ActionFields
+	}
+	return nil
+}
+
+func (m *TxApproveResponse) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+type PostKYCStatusRequest struct {
+	CallbackId           string   `protobuf:"bytes,1,opt,name=callback_id,json=callbackId,proto3" json:"callback_id,omitempty"`
+	EmailAddress         string   `protobuf:"bytes,2,opt,name=email_address,json=emailAddress,proto3" json:"email_address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PostKYCStatusRequest) Reset()         { *m = PostKYCStatusRequest{} }
+func (m *PostKYCStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*PostKYCStatusRequest) ProtoMessage()    {}
+func (*PostKYCStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{2}
+}
+
+func (m *PostKYCStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PostKYCStatusRequest.Unmarshal(m, b)
+}
+func (m *PostKYCStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PostKYCStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *PostKYCStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PostKYCStatusRequest.Merge(m, src)
+}
+func (m *PostKYCStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_PostKYCStatusRequest.Size(m)
+}
+func (m *PostKYCStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PostKYCStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PostKYCStatusRequest proto.InternalMessageInfo
+
+func (m *PostKYCStatusRequest) GetCallbackId() string {
+	if m != nil {
+		return m.CallbackId
+	}
+	return ""
+}
+
+func (m *PostKYCStatusRequest) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+type PostKYCStatusResponse struct {
+	Result               string   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PostKYCStatusResponse) Reset()         { *m = PostKYCStatusResponse{} }
+func (m *PostKYCStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*PostKYCStatusResponse) ProtoMessage()    {}
+func (*PostKYCStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{3}
+}
+
+func (m *PostKYCStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PostKYCStatusResponse.Unmarshal(m, b)
+}
+func (m *PostKYCStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PostKYCStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *PostKYCStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PostKYCStatusResponse.Merge(m, src)
+}
+func (m *PostKYCStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_PostKYCStatusResponse.Size(m)
+}
+func (m *PostKYCStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PostKYCStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PostKYCStatusResponse proto.InternalMessageInfo
+
+func (m *PostKYCStatusResponse) GetResult() string {
+	if m != nil {
+		return m.Result
+	}
+	return ""
+}
+
+type GetKYCStatusRequest struct {
+	StellarAddressOrCallbackId string   `protobuf:"bytes,1,opt,name=stellar_address_or_callback_id,json=stellarAddressOrCallbackId,proto3" json:"stellar_address_or_callback_id,omitempty"`
+	XXX_NoUnkeyedLiteral       struct{} `json:"-"`
+	XXX_unrecognized           []byte   `json:"-"`
+	XXX_sizecache              int32    `json:"-"`
+}
+
+func (m *GetKYCStatusRequest) Reset()         { *m = GetKYCStatusRequest{} }
+func (m *GetKYCStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetKYCStatusRequest) ProtoMessage()    {}
+func (*GetKYCStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{4}
+}
+
+func (m *GetKYCStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetKYCStatusRequest.Unmarshal(m, b)
+}
+func (m *GetKYCStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetKYCStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetKYCStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetKYCStatusRequest.Merge(m, src)
+}
+func (m *GetKYCStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetKYCStatusRequest.Size(m)
+}
+func (m *GetKYCStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetKYCStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetKYCStatusRequest proto.InternalMessageInfo
+
+func (m *GetKYCStatusRequest) GetStellarAddressOrCallbackId() string {
+	if m != nil {
+		return m.StellarAddressOrCallbackId
+	}
+	return ""
+}
+
+// KYCStatus timestamps are unix timestamps in seconds, zero when unset.
+type KYCStatus struct {
+	StellarAddress       string   `protobuf:"bytes,1,opt,name=stellar_address,json=stellarAddress,proto3" json:"stellar_address,omitempty"`
+	CallbackId           string   `protobuf:"bytes,2,opt,name=callback_id,json=callbackId,proto3" json:"callback_id,omitempty"`
+	EmailAddress         string   `protobuf:"bytes,3,opt,name=email_address,json=emailAddress,proto3" json:"email_address,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	KycSubmittedAt       int64    `protobuf:"varint,5,opt,name=kyc_submitted_at,json=kycSubmittedAt,proto3" json:"kyc_submitted_at,omitempty"`
+	ApprovedAt           int64    `protobuf:"varint,6,opt,name=approved_at,json=approvedAt,proto3" json:"approved_at,omitempty"`
+	RejectedAt           int64    `protobuf:"varint,7,opt,name=rejected_at,json=rejectedAt,proto3" json:"rejected_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KYCStatus) Reset()         { *m = KYCStatus{} }
+func (m *KYCStatus) String() string { return proto.CompactTextString(m) }
+func (*KYCStatus) ProtoMessage()    {}
+func (*KYCStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{5}
+}
+
+func (m *KYCStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KYCStatus.Unmarshal(m, b)
+}
+func (m *KYCStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KYCStatus.Marshal(b, m, deterministic)
+}
+func (m *KYCStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KYCStatus.Merge(m, src)
+}
+func (m *KYCStatus) XXX_Size() int {
+	return xxx_messageInfo_KYCStatus.Size(m)
+}
+func (m *KYCStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_KYCStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KYCStatus proto.InternalMessageInfo
+
+func (m *KYCStatus) GetStellarAddress() string {
+	if m != nil {
+		return m.StellarAddress
+	}
+	return ""
+}
+
+func (m *KYCStatus) GetCallbackId() string {
+	if m != nil {
+		return m.CallbackId
+	}
+	return ""
+}
+
+func (m *KYCStatus) GetEmailAddress() string {
+	if m != nil {
+		return m.EmailAddress
+	}
+	return ""
+}
+
+func (m *KYCStatus) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *KYCStatus) GetKycSubmittedAt() int64 {
+	if m != nil {
+		return m.KycSubmittedAt
+	}
+	return 0
+}
+
+func (m *KYCStatus) GetApprovedAt() int64 {
+	if m != nil {
+		return m.ApprovedAt
+	}
+	return 0
+}
+
+func (m *KYCStatus) GetRejectedAt() int64 {
+	if m != nil {
+		return m.RejectedAt
+	}
+	return 0
+}
+
+type DeleteKYCStatusRequest struct {
+	StellarAddress       string   `protobuf:"bytes,1,opt,name=stellar_address,json=stellarAddress,proto3" json:"stellar_address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteKYCStatusRequest) Reset()         { *m = DeleteKYCStatusRequest{} }
+func (m *DeleteKYCStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteKYCStatusRequest) ProtoMessage()    {}
+func (*DeleteKYCStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{6}
+}
+
+func (m *DeleteKYCStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteKYCStatusRequest.Unmarshal(m, b)
+}
+func (m *DeleteKYCStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteKYCStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteKYCStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteKYCStatusRequest.Merge(m, src)
+}
+func (m *DeleteKYCStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteKYCStatusRequest.Size(m)
+}
+func (m *DeleteKYCStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteKYCStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteKYCStatusRequest proto.InternalMessageInfo
+
+func (m *DeleteKYCStatusRequest) GetStellarAddress() string {
+	if m != nil {
+		return m.StellarAddress
+	}
+	return ""
+}
+
+type DeleteKYCStatusResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteKYCStatusResponse) Reset()         { *m = DeleteKYCStatusResponse{} }
+func (m *DeleteKYCStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteKYCStatusResponse) ProtoMessage()    {}
+func (*DeleteKYCStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_317f9b72348dd733, []int{7}
+}
+
+func (m *DeleteKYCStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteKYCStatusResponse.Unmarshal(m, b)
+}
+func (m *DeleteKYCStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteKYCStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteKYCStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteKYCStatusResponse.Merge(m, src)
+}
+func (m *DeleteKYCStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteKYCStatusResponse.Size(m)
+}
+func (m *DeleteKYCStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteKYCStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteKYCStatusResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*TxApproveRequest)(nil), "approval.TxApproveRequest")
+	proto.RegisterType((*TxApproveResponse)(nil), "approval.TxApproveResponse")
+	proto.RegisterType((*PostKYCStatusRequest)(nil), "approval.PostKYCStatusRequest")
+	proto.RegisterType((*PostKYCStatusResponse)(nil), "approval.PostKYCStatusResponse")
+	proto.RegisterType((*GetKYCStatusRequest)(nil), "approval.GetKYCStatusRequest")
+	proto.RegisterType((*KYCStatus)(nil), "approval.KYCStatus")
+	proto.RegisterType((*DeleteKYCStatusRequest)(nil), "approval.DeleteKYCStatusRequest")
+	proto.RegisterType((*DeleteKYCStatusResponse)(nil), "approval.DeleteKYCStatusResponse")
+}
+
+func init() { proto.RegisterFile("approval.proto", fileDescriptor_317f9b72348dd733) }
+
+var fileDescriptor_317f9b72348dd733 = []byte{
+	// 516 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdb, 0x6e, 0xd3, 0x40,
+	0x10, 0x86, 0x15, 0x87, 0x9c, 0x86, 0x34, 0x2d, 0xdb, 0x52, 0x8c, 0x51, 0xdb, 0x60, 0x2e, 0xc8,
+	0x55, 0x91, 0xe0, 0x09, 0x9c, 0x56, 0x20, 0x84, 0x10, 0x55, 0x0a, 0x48, 0x45, 0x48, 0xd6, 0xc6,
+	0x1e, 0xc0, 0x64, 0xdd, 0x35, 0xbb, 0x6b, 0x94, 0x3e, 0x00, 0xaf, 0xc5, 0x23, 0xf1, 0x0c, 0xa8,
+	0x7b, 0x88, 0x53, 0x3b, 0x1c, 0x2e, 0xe7, 0xdf, 0xcf, 0x33, 0xb3, 0xff, 0xce, 0x18, 0x46, 0xb4,
+	0x28, 0x04, 0xff, 0x4e, 0xd9, 0x71, 0x21, 0xb8, 0xe2, 0xa4, 0xef, 0xe2, 0x30, 0x84, 0x9d, 0xb7,
+	0xcb, 0x48, 0x47, 0x38, 0xc3, 0x6f, 0x25, 0x4a, 0x45, 0x46, 0xe0, 0xa9, 0xa5, 0xdf, 0x1a, 0xb7,
+	0x26, 0x83, 0x99, 0xa7, 0x96, 0xe1, 0xaf, 0x16, 0xdc, 0x59, 0x83, 0x64, 0xc1, 0x2f, 0x25, 0x92,
+	0x7d, 0xe8, 0x4a, 0x45, 0x55, 0x29, 0x2d, 0x69, 0x23, 0xb2, 0x07, 0x1d, 0x14, 0x82, 0x0b, 0xdf,
+	0xd3, 0xb2, 0x09, 0x88, 0x0f, 0xbd, 0x1c, 0xa5, 0xa4, 0x9f, 0xd1, 0x6f, 0x6b, 0xdd, 0x85, 0xb6,
+	0xda, 0x2d, 0x57, 0x8d, 0x1c, 0x00, 0xd0, 0x44, 0x65, 0xfc, 0x32, 0x2e, 0x05, 0xf3, 0x3b, 0x5a,
+	0x1f, 0x18, 0xe5, 0x9d, 0x60, 0xe4, 0x11, 0x6c, 0xd9, 0xe3, 0x1c, 0xd5, 0x17, 0x9e, 0xfa, 0x5d,
+	0x4d, 0x0c, 0x8d, 0xf8, 0x5a, 0x6b, 0x6b, 0xd0, 0xa7, 0x0c, 0x59, 0x2a, 0xfd, 0xde, 0xb8, 0x5d,
+	0x41, 0xcf, 0xb5, 0x76, 0xdd, 0x92, 0xca, 0x72, 0xe4, 0xa5, 0xf2, 0xfb, 0xe3, 0xd6, 0xa4, 0x3d,
+	0x73, 0x61, 0xf8, 0x11, 0xf6, 0xce, 0xb8, 0x54, 0xaf, 0x2e, 0x4e, 0xce, 0xf5, 0x9d, 0x9c, 0x31,
+	0x47, 0x70, 0x3b, 0xa1, 0x8c, 0xcd, 0x69, 0xb2, 0x88, 0xb3, 0xd4, 0xde, 0x1b, 0x9c, 0xf4, 0x52,
+	0xd7, 0xc5, 0x9c, 0x66, 0x2c, 0xa6, 0x69, 0x2a, 0x50, 0x4a, 0xeb, 0xc1, 0x50, 0x8b, 0x91, 0xd1,
+	0xc2, 0x27, 0x70, 0xb7, 0x96, 0xbd, 0x72, 0x54, 0xa0, 0x2c, 0x99, 0x72, 0x8e, 0x9a, 0x28, 0xbc,
+	0x80, 0xdd, 0x17, 0xd8, 0xec, 0x66, 0x0a, 0x87, 0x52, 0x21, 0x63, 0x54, 0xb8, 0x72, 0x31, 0x17,
+	0x71, 0xb3, 0xc1, 0xc0, 0x52, 0xb6, 0xfe, 0x1b, 0x71, 0xb2, 0x6a, 0x38, 0xfc, 0xe1, 0xc1, 0x60,
+	0x95, 0x98, 0x3c, 0x86, 0xed, 0x5a, 0x46, 0x9b, 0x62, 0x74, 0x33, 0x45, 0xdd, 0x08, 0xef, 0xdf,
+	0x46, 0xb4, 0x9b, 0x46, 0x5c, 0xbf, 0x74, 0x22, 0x90, 0x2a, 0x4c, 0x63, 0xaa, 0xf4, 0x04, 0xb4,
+	0x67, 0x03, 0xab, 0x44, 0x8a, 0x4c, 0x60, 0x67, 0x71, 0x95, 0xc4, 0xb2, 0x9c, 0xe7, 0x99, 0xb2,
+	0x50, 0x47, 0x43, 0xa3, 0xc5, 0x55, 0x72, 0xee, 0xe4, 0x48, 0xbf, 0x8b, 0x19, 0x68, 0x03, 0x75,
+	0x35, 0x04, 0x4e, 0x32, 0x80, 0xc0, 0xaf, 0x98, 0xd8, 0x2c, 0x3d, 0x03, 0x38, 0x29, 0x52, 0x61,
+	0x04, 0xfb, 0xa7, 0xc8, 0x50, 0x61, 0xc3, 0xe5, 0xff, 0xf5, 0x24, 0xbc, 0x0f, 0xf7, 0x1a, 0x29,
+	0xcc, 0xc3, 0x3e, 0xfd, 0xe9, 0x41, 0x3f, 0xb2, 0x1b, 0x47, 0x4e, 0x61, 0xb0, 0x5a, 0x26, 0x12,
+	0x1c, 0xaf, 0x36, 0xb3, 0xbe, 0x86, 0xc1, 0x83, 0x8d, 0x67, 0x76, 0x56, 0xce, 0x60, 0xeb, 0xc6,
+	0x10, 0x91, 0xc3, 0x8a, 0xde, 0x34, 0xbb, 0xc1, 0xd1, 0x1f, 0xcf, 0x6d, 0xc6, 0x29, 0x0c, 0xd7,
+	0xa7, 0x8c, 0x1c, 0x54, 0x1f, 0x6c, 0x98, 0xbe, 0x60, 0xb7, 0x3a, 0xae, 0xbe, 0x79, 0x0f, 0xdb,
+	0x35, 0x0f, 0xc8, 0xb8, 0xe2, 0x36, 0x3b, 0x1c, 0x3c, 0xfc, 0x0b, 0x61, 0x7a, 0x9b, 0x0e, 0x3f,
+	0x80, 0x63, 0x8a, 0xf9, 0xbc, 0xab, 0x7f, 0x62, 0xcf, 0x7e, 0x0f, 0x00, 0x1d, 0x0f, 0xb6, 0x49,
+	0xd6, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ApprovalClient is the client API for Approval service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ApprovalClient interface {
+	// TxApprove validates and, if possible, revises a transaction containing a
+	// payment of the regulated asset, as done by POST /tx-approve.
+	TxApprove(ctx context.Context, in *TxApproveRequest, opts ...grpc.CallOption) (*TxApproveResponse, error)
+	// PostKYCStatus submits the KYC information of the account identified by
+	// callback_id, as done by POST /kyc-status/{callback_id}.
+	PostKYCStatus(ctx context.Context, in *PostKYCStatusRequest, opts ...grpc.CallOption) (*PostKYCStatusResponse, error)
+	// GetKYCStatus returns the KYC status of an account, as done by
+	// GET /kyc-status/{stellar_address_or_callback_id}.
+	GetKYCStatus(ctx context.Context, in *GetKYCStatusRequest, opts ...grpc.CallOption) (*KYCStatus, error)
+	// DeleteKYCStatus deletes the KYC status of an account, as done by
+	// DELETE /kyc-status/{stellar_address}.
+	DeleteKYCStatus(ctx context.Context, in *DeleteKYCStatusRequest, opts ...grpc.CallOption) (*DeleteKYCStatusResponse, error)
+}
+
+type approvalClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewApprovalClient(cc *grpc.ClientConn) ApprovalClient {
+	return &approvalClient{cc}
+}
+
+func (c *approvalClient) TxApprove(ctx context.Context, in *TxApproveRequest, opts ...grpc.CallOption) (*TxApproveResponse, error) {
+	out := new(TxApproveResponse)
+	err := c.cc.Invoke(ctx, "/approval.Approval/TxApprove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *approvalClient) PostKYCStatus(ctx context.Context, in *PostKYCStatusRequest, opts ...grpc.CallOption) (*PostKYCStatusResponse, error) {
+	out := new(PostKYCStatusResponse)
+	err := c.cc.Invoke(ctx, "/approval.Approval/PostKYCStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *approvalClient) GetKYCStatus(ctx context.Context, in *GetKYCStatusRequest, opts ...grpc.CallOption) (*KYCStatus, error) {
+	out := new(KYCStatus)
+	err := c.cc.Invoke(ctx, "/approval.Approval/GetKYCStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *approvalClient) DeleteKYCStatus(ctx context.Context, in *DeleteKYCStatusRequest, opts ...grpc.CallOption) (*DeleteKYCStatusResponse, error) {
+	out := new(DeleteKYCStatusResponse)
+	err := c.cc.Invoke(ctx, "/approval.Approval/DeleteKYCStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ApprovalServer is the server API for Approval service.
+type ApprovalServer interface {
+	// TxApprove validates and, if possible, revises a transaction containing a
+	// payment of the regulated asset, as done by POST /tx-approve.
+	TxApprove(context.Context, *TxApproveRequest) (*TxApproveResponse, error)
+	// PostKYCStatus submits the KYC information of the account identified by
+	// callback_id, as done by POST /kyc-status/{callback_id}.
+	PostKYCStatus(context.Context, *PostKYCStatusRequest) (*PostKYCStatusResponse, error)
+	// GetKYCStatus returns the KYC status of an account, as done by
+	// GET /kyc-status/{stellar_address_or_callback_id}.
+	GetKYCStatus(context.Context, *GetKYCStatusRequest) (*KYCStatus, error)
+	// DeleteKYCStatus deletes the KYC status of an account, as done by
+	// DELETE /kyc-status/{stellar_address}.
+	DeleteKYCStatus(context.Context, *DeleteKYCStatusRequest) (*DeleteKYCStatusResponse, error)
+}
+
+func RegisterApprovalServer(s *grpc.Server, srv ApprovalServer) {
+	s.RegisterService(&_Approval_serviceDesc, srv)
+}
+
+func _Approval_TxApprove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxApproveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApprovalServer).TxApprove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/approval.Approval/TxApprove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApprovalServer).TxApprove(ctx, req.(*TxApproveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Approval_PostKYCStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PostKYCStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApprovalServer).PostKYCStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/approval.Approval/PostKYCStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApprovalServer).PostKYCStatus(ctx, req.(*PostKYCStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Approval_GetKYCStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKYCStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApprovalServer).GetKYCStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/approval.Approval/GetKYCStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApprovalServer).GetKYCStatus(ctx, req.(*GetKYCStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Approval_DeleteKYCStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKYCStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApprovalServer).DeleteKYCStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/approval.Approval/DeleteKYCStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApprovalServer).DeleteKYCStatus(ctx, req.(*DeleteKYCStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Approval_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "approval.Approval",
+	HandlerType: (*ApprovalServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TxApprove",
+			Handler:    _Approval_TxApprove_Handler,
+		},
+		{
+			MethodName: "PostKYCStatus",
+			Handler:    _Approval_PostKYCStatus_Handler,
+		},
+		{
+			MethodName: "GetKYCStatus",
+			Handler:    _Approval_GetKYCStatus_Handler,
+		},
+		{
+			MethodName: "DeleteKYCStatus",
+			Handler:    _Approval_DeleteKYCStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "approval.proto",
+}
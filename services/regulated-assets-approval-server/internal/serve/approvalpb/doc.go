@@ -0,0 +1,8 @@
+// Package approvalpb contains the Go bindings of approval.proto, the gRPC
+// interface of the approval server.
+//
+// approval.pb.go is generated with protoc and the protoc-gen-go plugin of
+// github.com/golang/protobuf v1.3.1, the version used by this module.
+package approvalpb
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. approval.proto
@@ -0,0 +1,57 @@
+package serve
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// auditLogPruner periodically deletes the tx-approve decisions recorded in
+// the audit log for longer than the retention period.
+type auditLogPruner struct {
+	db        dbConn
+	retention time.Duration
+	interval  time.Duration
+}
+
+// Run prunes the audit log right away and then every interval until ctx is
+// done.
+func (p auditLogPruner) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.prune(ctx)
+		if err != nil {
+			log.Ctx(ctx).Error(errors.Wrap(err, "pruning the tx-approve audit log"))
+		} else if n > 0 {
+			log.Ctx(ctx).Infof("Deleted %d entries older than %s from the tx-approve audit log", n, p.retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune deletes the entries of the audit log older than the retention
+// period and returns the number of deleted entries.
+func (p auditLogPruner) prune(ctx context.Context) (int64, error) {
+	const q = `
+		DELETE FROM tx_approve_audit_log
+		WHERE created_at < $1
+	`
+	result, err := p.db.ExecContext(ctx, q, time.Now().Add(-p.retention))
+	if err != nil {
+		return 0, errors.Wrap(err, "deleting expired entries")
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "counting deleted entries")
+	}
+	return n, nil
+}
@@ -0,0 +1,38 @@
+package serve
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogPruner_prune(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+
+	const q = `
+		INSERT INTO tx_approve_audit_log (tx, status, response, created_at)
+		VALUES ('AAAA', 'rejected', '{}', $1)
+	`
+	_, err := conn.ExecContext(ctx, q, time.Now().Add(-48*time.Hour))
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, q, time.Now())
+	require.NoError(t, err)
+
+	p := auditLogPruner{db: conn, retention: 24 * time.Hour}
+	n, err := p.prune(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	var count int
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM tx_approve_audit_log").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,574 @@
+package serve
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/adminauth"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpdecode"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+	"github.com/stellar/go/txnbuild"
+)
+
+// clawbackStatus is the status of a clawback request.
+type clawbackStatus string
+
+const (
+	// clawbackStatusPending requests wait for the approval of a second admin.
+	clawbackStatusPending clawbackStatus = "pending"
+	// clawbackStatusApproved requests are being submitted, or their
+	// transaction was submitted without a known outcome, which is reconciled
+	// with its hash.
+	clawbackStatusApproved  clawbackStatus = "approved"
+	clawbackStatusSubmitted clawbackStatus = "submitted"
+	clawbackStatusFailed    clawbackStatus = "failed"
+	clawbackStatusRejected  clawbackStatus = "rejected"
+)
+
+// errUnauthenticatedAdmin is returned when a clawback request is handled
+// without an admin authenticated by adminAuthHandler.
+var errUnauthenticatedAdmin = httperror.NewHTTPError(http.StatusUnauthorized, "Unauthorized.")
+
+// maxClawbacks is the maximum number of clawbacks of a request, which are
+// submitted in a single transaction.
+const maxClawbacks = 100
+
+// transactionIngestionDelay is how long after its max time a transaction
+// which is not found on Horizon is considered to have expired, giving Horizon
+// the time to ingest the ledger it could have been included in.
+const transactionIngestionDelay = time.Minute
+
+const clawbackRequestColumns = "id, asset_code, asset_issuer, clawbacks, reason, status, requested_by, requested_at, decided_by, decided_at, tx, tx_hash, error"
+
+// clawbackHandler claws back regulated assets from their holders. Every
+// clawback request is recorded in the clawback_requests table along with the
+// admins who requested and approved it and the submitted transaction.
+type clawbackHandler struct {
+	assets            []regulatedAsset
+	horizonClient     horizonclient.ClientInterface
+	networkPassphrase string
+	db                *sqlx.DB
+	// approvalRequired requires the clawback requests to be approved by an
+	// admin other than the one who requested them before being submitted.
+	approvalRequired bool
+	// metrics records the Horizon errors, it may be nil.
+	metrics *metrics
+}
+
+type clawback struct {
+	From   string `json:"from"`
+	Amount string `json:"amount"`
+}
+
+type clawbackCreateRequest struct {
+	AssetCode string `json:"asset_code"`
+	// AssetIssuer is only required when several regulated assets have
+	// AssetCode.
+	AssetIssuer string     `json:"asset_issuer"`
+	Clawbacks   []clawback `json:"clawbacks"`
+	Reason      string     `json:"reason"`
+}
+
+type clawbackGetRequest struct {
+	ID string `path:"id" json:"-"`
+}
+
+type clawbackDecisionRequest struct {
+	ID string `path:"id" json:"-"`
+}
+
+type clawbackResponse struct {
+	ID          int64          `json:"id"`
+	AssetCode   string         `json:"asset_code"`
+	AssetIssuer string         `json:"asset_issuer"`
+	Clawbacks   []clawback     `json:"clawbacks"`
+	Reason      string         `json:"reason,omitempty"`
+	Status      clawbackStatus `json:"status"`
+	RequestedBy string         `json:"requested_by"`
+	RequestedAt time.Time      `json:"requested_at"`
+	DecidedBy   string         `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time     `json:"decided_at,omitempty"`
+	Tx          string         `json:"tx,omitempty"`
+	TxHash      string         `json:"tx_hash,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+func (c *clawbackResponse) Render(w http.ResponseWriter) {
+	httpjson.Render(w, c, httpjson.JSON)
+}
+
+func scanClawbackRequest(row *sql.Row) (*clawbackResponse, error) {
+	var (
+		resp                             clawbackResponse
+		clawbacks                        []byte
+		decidedBy, tx, txHash, errString sql.NullString
+	)
+	err := row.Scan(&resp.ID, &resp.AssetCode, &resp.AssetIssuer, &clawbacks, &resp.Reason, &resp.Status, &resp.RequestedBy, &resp.RequestedAt, &decidedBy, &resp.DecidedAt, &tx, &txHash, &errString)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(clawbacks, &resp.Clawbacks)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding clawbacks")
+	}
+	resp.DecidedBy = decidedBy.String
+	resp.Tx = tx.String
+	resp.TxHash = txHash.String
+	resp.Error = errString.String
+	return &resp, nil
+}
+
+func (h clawbackHandler) validate() error {
+	if len(h.assets) == 0 {
+		return errors.New("assets cannot be empty")
+	}
+	if h.horizonClient == nil {
+		return errors.New("horizon client cannot be nil")
+	}
+	if h.networkPassphrase == "" {
+		return errors.New("network passphrase cannot be empty")
+	}
+	if h.db == nil {
+		return errors.New("database cannot be nil")
+	}
+	return nil
+}
+
+func (h clawbackHandler) serveCreate(w http.ResponseWriter, r *http.Request) {
+	in := clawbackCreateRequest{}
+	h.serve(w, r, &in, func(ctx context.Context) (*clawbackResponse, error) {
+		return h.create(ctx, adminauth.FromContext(ctx), in)
+	})
+}
+
+func (h clawbackHandler) serveGet(w http.ResponseWriter, r *http.Request) {
+	in := clawbackGetRequest{}
+	h.serve(w, r, &in, func(ctx context.Context) (*clawbackResponse, error) {
+		id, err := parseClawbackRequestID(in.ID)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := h.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return h.reconcile(ctx, resp)
+	})
+}
+
+func (h clawbackHandler) serveApprove(w http.ResponseWriter, r *http.Request) {
+	in := clawbackDecisionRequest{}
+	h.serve(w, r, &in, func(ctx context.Context) (*clawbackResponse, error) {
+		return h.approve(ctx, adminauth.FromContext(ctx), in)
+	})
+}
+
+func (h clawbackHandler) serveReject(w http.ResponseWriter, r *http.Request) {
+	in := clawbackDecisionRequest{}
+	h.serve(w, r, &in, func(ctx context.Context) (*clawbackResponse, error) {
+		return h.reject(ctx, adminauth.FromContext(ctx), in)
+	})
+}
+
+// serve decodes the request into in, and renders the clawback request
+// returned by handle.
+func (h clawbackHandler) serve(w http.ResponseWriter, r *http.Request, in interface{}, handle func(ctx context.Context) (*clawbackResponse, error)) {
+	ctx := r.Context()
+	err := h.validate()
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating clawbackHandler"))
+		httperror.InternalServer.Render(w)
+		return
+	}
+
+	err = httpdecode.Decode(r, in)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "decoding clawback request"))
+		httperror.BadRequest.Render(w)
+		return
+	}
+
+	resp, err := handle(ctx)
+	if err != nil {
+		httpErr, ok := err.(*httperror.Error)
+		if !ok {
+			log.Ctx(ctx).Error(errors.Wrap(err, "handling clawback request"))
+			httpErr = httperror.InternalServer
+		}
+		httpErr.Render(w)
+		return
+	}
+
+	resp.Render(w)
+}
+
+func parseClawbackRequestID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, httperror.NewHTTPError(http.StatusBadRequest, "Invalid clawback request id.")
+	}
+	return id, nil
+}
+
+// findAsset returns the regulated asset with the given code, and with the
+// given issuer if it is not empty.
+func (h clawbackHandler) findAsset(code, issuer string) (regulatedAsset, error) {
+	if code == "" {
+		return regulatedAsset{}, httperror.NewHTTPError(http.StatusBadRequest, "Missing asset code.")
+	}
+	var found []regulatedAsset
+	for _, asset := range h.assets {
+		if asset.code == code && (issuer == "" || asset.issuer() == issuer) {
+			found = append(found, asset)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return regulatedAsset{}, httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s is not a regulated asset.", code))
+	case 1:
+		return found[0], nil
+	default:
+		return regulatedAsset{}, httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Several regulated assets have the code %s, the asset issuer must be set.", code))
+	}
+}
+
+// parseClawbacks validates the requested clawbacks and returns them with
+// their amounts formatted like Horizon amounts.
+func parseClawbacks(clawbacks []clawback) ([]clawback, error) {
+	if len(clawbacks) == 0 {
+		return nil, httperror.NewHTTPError(http.StatusBadRequest, "Missing clawbacks.")
+	}
+	if len(clawbacks) > maxClawbacks {
+		return nil, httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("At most %d clawbacks can be requested at once.", maxClawbacks))
+	}
+	parsed := make([]clawback, len(clawbacks))
+	for i, c := range clawbacks {
+		if !strkey.IsValidEd25519PublicKey(c.From) {
+			return nil, httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%q is not a valid Stellar address.", c.From))
+		}
+		amt, err := amount.ParseInt64(c.Amount)
+		if err != nil || amt <= 0 {
+			return nil, httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%q is not a valid amount.", c.Amount))
+		}
+		parsed[i] = clawback{From: c.From, Amount: amount.StringFromInt64(amt)}
+	}
+	return parsed, nil
+}
+
+// create records a clawback request of admin. Unless approvalRequired is
+// set, the request is approved by the admin who created it and submitted
+// right away.
+func (h clawbackHandler) create(ctx context.Context, admin string, in clawbackCreateRequest) (*clawbackResponse, error) {
+	if admin == "" {
+		return nil, errUnauthenticatedAdmin
+	}
+	asset, err := h.findAsset(in.AssetCode, in.AssetIssuer)
+	if err != nil {
+		return nil, err
+	}
+	clawbacks, err := parseClawbacks(in.Clawbacks)
+	if err != nil {
+		return nil, err
+	}
+	clawbacksJSON, err := json.Marshal(clawbacks)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding clawbacks")
+	}
+
+	query := `
+		INSERT INTO clawback_requests (asset_code, asset_issuer, clawbacks, reason, status, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + clawbackRequestColumns
+	resp, err := scanClawbackRequest(h.db.QueryRowContext(ctx, query, asset.code, asset.issuer(), string(clawbacksJSON), in.Reason, clawbackStatusPending, admin))
+	if err != nil {
+		return nil, errors.Wrap(err, "inserting clawback request")
+	}
+	log.Ctx(ctx).Infof("Clawback request %d of %d %s clawbacks requested by %s", resp.ID, len(clawbacks), asset.code, admin)
+
+	if h.approvalRequired {
+		return resp, nil
+	}
+	resp, err = h.decide(ctx, resp.ID, admin, clawbackStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	return h.submit(ctx, resp)
+}
+
+func (h clawbackHandler) get(ctx context.Context, id int64) (*clawbackResponse, error) {
+	query := `SELECT ` + clawbackRequestColumns + ` FROM clawback_requests WHERE id = $1`
+	resp, err := scanClawbackRequest(h.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, httperror.NewHTTPError(http.StatusNotFound, "Not found.")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "querying the database")
+	}
+	return resp, nil
+}
+
+// approve approves a pending clawback request on behalf of admin and submits
+// its clawbacks.
+func (h clawbackHandler) approve(ctx context.Context, admin string, in clawbackDecisionRequest) (*clawbackResponse, error) {
+	id, err := parseClawbackRequestID(in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == "" {
+		return nil, errUnauthenticatedAdmin
+	}
+	resp, err := h.decide(ctx, id, admin, clawbackStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	return h.submit(ctx, resp)
+}
+
+// reject rejects a pending clawback request on behalf of admin, which can also
+// be the admin who requested it.
+func (h clawbackHandler) reject(ctx context.Context, admin string, in clawbackDecisionRequest) (*clawbackResponse, error) {
+	id, err := parseClawbackRequestID(in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if admin == "" {
+		return nil, errUnauthenticatedAdmin
+	}
+	return h.decide(ctx, id, admin, clawbackStatusRejected)
+}
+
+// decide sets the status of a pending clawback request to approved or
+// rejected. The status is updated only if the request is still pending, so
+// that concurrent decisions cannot submit the clawbacks twice.
+func (h clawbackHandler) decide(ctx context.Context, id int64, admin string, status clawbackStatus) (*clawbackResponse, error) {
+	query := `
+		UPDATE clawback_requests
+		SET status = $3, decided_by = $2, decided_at = NOW()
+		WHERE id = $1 AND status = 'pending'`
+	if status == clawbackStatusApproved && h.approvalRequired {
+		query += ` AND requested_by <> $2`
+	}
+	query += ` RETURNING ` + clawbackRequestColumns
+	resp, err := scanClawbackRequest(h.db.QueryRowContext(ctx, query, id, admin, status))
+	if err == sql.ErrNoRows {
+		current, err := h.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if current.Status != clawbackStatusPending {
+			return nil, httperror.NewHTTPError(http.StatusConflict, fmt.Sprintf("The clawback request is %s.", current.Status))
+		}
+		return nil, httperror.NewHTTPError(http.StatusForbidden, "The clawback request must be approved by an admin other than the one who requested it.")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "updating clawback request")
+	}
+
+	log.Ctx(ctx).Infof("Clawback request %d %s by %s", id, status, admin)
+	return resp, nil
+}
+
+// submit submits the clawbacks of an approved request. The transaction is
+// recorded before being submitted, so that when the outcome of the submission
+// is unknown, e.g. after a timeout, the request stays approved and is
+// reconciled with the transaction hash by reconcile.
+func (h clawbackHandler) submit(ctx context.Context, req *clawbackResponse) (*clawbackResponse, error) {
+	var tx *txnbuild.Transaction
+	asset, err := h.findAsset(req.AssetCode, req.AssetIssuer)
+	if err == nil {
+		tx, err = h.buildTransaction(asset, req.Clawbacks)
+	}
+	if err != nil {
+		return h.setResult(ctx, req.ID, clawbackStatusFailed, err)
+	}
+
+	txe, err := tx.Base64()
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding transaction")
+	}
+	txHash, err := tx.HashHex(h.networkPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing transaction")
+	}
+	_, err = h.db.ExecContext(ctx, `UPDATE clawback_requests SET tx = $2, tx_hash = $3 WHERE id = $1`, req.ID, txe, txHash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "recording transaction of clawback request %d", req.ID)
+	}
+
+	_, err = h.horizonClient.SubmitTransaction(tx)
+	if err == nil {
+		return h.setResult(ctx, req.ID, clawbackStatusSubmitted, nil)
+	}
+	h.metrics.incHorizonError("submit_transaction")
+	if !isTransactionRejected(err) {
+		return h.setResult(ctx, req.ID, clawbackStatusApproved, httperror.ParseHorizonError(err))
+	}
+	return h.setResult(ctx, req.ID, clawbackStatusFailed, httperror.ParseHorizonError(err))
+}
+
+// isTransactionRejected returns true if Horizon rejected a submitted
+// transaction, e.g. because it failed or was malformed, in which case it will
+// not be included in a ledger. Other errors, e.g. timeouts, leave the outcome
+// of the submission unknown.
+func isTransactionRejected(err error) bool {
+	hErr := horizonclient.GetError(err)
+	return hErr != nil && hErr.Problem.Status == http.StatusBadRequest
+}
+
+// reconcile updates the status of an approved request whose transaction was
+// submitted without a known outcome, from the transaction with its hash on
+// Horizon. The transaction failed if it was not found once expired. The
+// request is returned unchanged if its outcome is still unknown.
+func (h clawbackHandler) reconcile(ctx context.Context, req *clawbackResponse) (*clawbackResponse, error) {
+	if req.Status != clawbackStatusApproved || req.TxHash == "" {
+		return req, nil
+	}
+
+	tx, err := h.horizonClient.TransactionDetail(req.TxHash)
+	switch {
+	case err == nil && tx.Successful:
+		return h.setResult(ctx, req.ID, clawbackStatusSubmitted, nil)
+	case err == nil:
+		return h.setResult(ctx, req.ID, clawbackStatusFailed, errors.Errorf("transaction failed with result %s", tx.ResultXdr))
+	case horizonclient.IsNotFoundError(err):
+		maxTime, err := transactionMaxTime(req.Tx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing transaction of clawback request %d", req.ID)
+		}
+		if time.Now().Before(maxTime.Add(transactionIngestionDelay)) {
+			return req, nil
+		}
+		return h.setResult(ctx, req.ID, clawbackStatusFailed, errors.New("transaction expired without being included in a ledger"))
+	default:
+		h.metrics.incHorizonError("transaction_detail")
+		log.Ctx(ctx).Error(errors.Wrapf(err, "getting transaction %s of clawback request %d", req.TxHash, req.ID))
+		return req, nil
+	}
+}
+
+// transactionMaxTime returns the max time of the timebounds of a transaction
+// envelope.
+func transactionMaxTime(txe string) (time.Time, error) {
+	genericTx, err := txnbuild.TransactionFromXDR(txe)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tx, ok := genericTx.Transaction()
+	if !ok {
+		return time.Time{}, errors.New("transaction is a fee bump transaction")
+	}
+	return time.Unix(tx.Timebounds().MaxTime, 0), nil
+}
+
+// setResult sets the status of an approved request and the error that
+// occurred while submitting it, if any. When the request is not approved
+// anymore, e.g. because it was reconciled concurrently, it is returned
+// unchanged.
+func (h clawbackHandler) setResult(ctx context.Context, id int64, status clawbackStatus, result error) (*clawbackResponse, error) {
+	var errString *string
+	if result != nil {
+		log.Ctx(ctx).Error(errors.Wrapf(result, "submitting clawback request %d", id))
+		s := result.Error()
+		errString = &s
+	}
+
+	query := `
+		UPDATE clawback_requests
+		SET status = $2, error = $3
+		WHERE id = $1 AND status = 'approved'
+		RETURNING ` + clawbackRequestColumns
+	resp, err := scanClawbackRequest(h.db.QueryRowContext(ctx, query, id, status, errString))
+	if err == sql.ErrNoRows {
+		return h.get(ctx, id)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "updating clawback request %d", id)
+	}
+
+	log.Ctx(ctx).Infof("Clawback request %d %s", id, status)
+	return resp, nil
+}
+
+// buildTransaction builds the transaction of the clawbacks, signed by the
+// issuer of the asset, after checking that clawback is enabled on the issuer
+// account and on the trustlines of the holders.
+func (h clawbackHandler) buildTransaction(asset regulatedAsset, clawbacks []clawback) (*txnbuild.Transaction, error) {
+	issuerAcc, err := h.horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: asset.issuer()})
+	if err != nil {
+		h.metrics.incHorizonError("account_detail")
+		return nil, errors.Wrapf(err, "getting detail for issuer account %s", asset.issuer())
+	}
+
+	if !issuerAcc.Flags.AuthClawbackEnabled {
+		return nil, errors.Errorf("clawback is not enabled on issuer account %s", asset.issuer())
+	}
+	checked := map[string]bool{}
+	for _, c := range clawbacks {
+		if checked[c.From] {
+			continue
+		}
+		checked[c.From] = true
+		err = h.checkTrustlineClawbackEnabled(asset, c.From)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	creditAsset := txnbuild.CreditAsset{Code: asset.code, Issuer: asset.issuer()}
+	ops := make([]txnbuild.Operation, len(clawbacks))
+	for i, c := range clawbacks {
+		ops[i] = &txnbuild.Clawback{
+			From:   c.From,
+			Amount: c.Amount,
+			Asset:  creditAsset,
+		}
+	}
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &issuerAcc,
+		IncrementSequenceNum: true,
+		Operations:           ops,
+		BaseFee:              txnbuild.MinBaseFee,
+		Timebounds:           txnbuild.NewTimeout(300),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "building transaction")
+	}
+	tx, err = tx.Sign(h.networkPassphrase, asset.issuerKP)
+	if err != nil {
+		return nil, errors.Wrap(err, "signing transaction")
+	}
+	return tx, nil
+}
+
+// checkTrustlineClawbackEnabled checks that an account has a trustline to the
+// asset with clawback enabled, which is only the case of the trustlines
+// created after clawback was enabled on the issuer account.
+func (h clawbackHandler) checkTrustlineClawbackEnabled(asset regulatedAsset, accountID string) error {
+	acc, err := h.horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: accountID})
+	if err != nil {
+		h.metrics.incHorizonError("account_detail")
+		return errors.Wrapf(err, "getting detail for account %s", accountID)
+	}
+	for _, b := range acc.Balances {
+		if b.Code != asset.code || b.Issuer != asset.issuer() {
+			continue
+		}
+		if b.IsClawbackEnabled == nil || !*b.IsClawbackEnabled {
+			return errors.Errorf("clawback is not enabled on the %s trustline of account %s", asset.code, accountID)
+		}
+		return nil
+	}
+	return errors.Errorf("account %s has no %s trustline", accountID, asset.code)
+}
@@ -0,0 +1,437 @@
+package serve
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClawbackHandler_validate(t *testing.T) {
+	h := clawbackHandler{}
+	require.EqualError(t, h.validate(), "assets cannot be empty")
+
+	h.assets = []regulatedAsset{{code: "FOO", issuerKP: keypair.MustRandom()}}
+	require.EqualError(t, h.validate(), "horizon client cannot be nil")
+
+	h.horizonClient = &horizonclient.MockClient{}
+	require.EqualError(t, h.validate(), "network passphrase cannot be empty")
+
+	h.networkPassphrase = network.TestNetworkPassphrase
+	require.EqualError(t, h.validate(), "database cannot be nil")
+}
+
+func TestClawbackHandler_findAsset(t *testing.T) {
+	fooKP := keypair.MustRandom()
+	otherFooKP := keypair.MustRandom()
+	barKP := keypair.MustRandom()
+	h := clawbackHandler{assets: []regulatedAsset{
+		{code: "FOO", issuerKP: fooKP},
+		{code: "BAR", issuerKP: barKP},
+		{code: "FOO", issuerKP: otherFooKP},
+	}}
+
+	asset, err := h.findAsset("BAR", "")
+	require.NoError(t, err)
+	assert.Equal(t, barKP.Address(), asset.issuer())
+
+	asset, err = h.findAsset("FOO", otherFooKP.Address())
+	require.NoError(t, err)
+	assert.Equal(t, otherFooKP.Address(), asset.issuer())
+
+	_, err = h.findAsset("FOO", "")
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Several regulated assets have the code FOO, the asset issuer must be set."), err)
+
+	_, err = h.findAsset("BAR", fooKP.Address())
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "BAR is not a regulated asset."), err)
+
+	_, err = h.findAsset("", "")
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Missing asset code."), err)
+}
+
+func TestParseClawbacks(t *testing.T) {
+	holder := keypair.MustRandom().Address()
+	clawbacks, err := parseClawbacks([]clawback{{From: holder, Amount: "10.5"}})
+	require.NoError(t, err)
+	assert.Equal(t, []clawback{{From: holder, Amount: "10.5000000"}}, clawbacks)
+
+	_, err = parseClawbacks(nil)
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Missing clawbacks."), err)
+
+	_, err = parseClawbacks(make([]clawback, maxClawbacks+1))
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "At most 100 clawbacks can be requested at once."), err)
+
+	_, err = parseClawbacks([]clawback{{From: "foo", Amount: "1"}})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, `"foo" is not a valid Stellar address.`), err)
+
+	_, err = parseClawbacks([]clawback{{From: holder, Amount: "0"}})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, `"0" is not a valid amount.`), err)
+}
+
+func newClawbackTestHandler(t *testing.T, issuerKP *keypair.Full) (clawbackHandler, *horizonclient.MockClient) {
+	db := dbtest.Open(t)
+	t.Cleanup(func() { db.Close() })
+	conn := db.Open()
+	t.Cleanup(func() { conn.Close() })
+
+	horizonMock := &horizonclient.MockClient{}
+	issuerAcc := horizon.Account{AccountID: issuerKP.Address(), Sequence: "1"}
+	issuerAcc.Flags.AuthClawbackEnabled = true
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: issuerKP.Address()}).
+		Return(issuerAcc, nil)
+
+	return clawbackHandler{
+		assets:            []regulatedAsset{{code: "FOO", issuerKP: issuerKP}},
+		horizonClient:     horizonMock,
+		networkPassphrase: network.TestNetworkPassphrase,
+		db:                conn,
+	}, horizonMock
+}
+
+// newClawbackTestHolder returns a holder of FOO whose trustline has clawback
+// enabled or not.
+func newClawbackTestHolder(horizonMock *horizonclient.MockClient, issuerKP *keypair.Full, clawbackEnabled bool) string {
+	holder := keypair.MustRandom().Address()
+	balance := horizon.Balance{IsClawbackEnabled: &clawbackEnabled}
+	balance.Code = "FOO"
+	balance.Issuer = issuerKP.Address()
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: holder}).
+		Return(horizon.Account{AccountID: holder, Balances: []horizon.Balance{balance}}, nil)
+	return holder
+}
+
+func TestClawbackHandler_create(t *testing.T) {
+	ctx := context.Background()
+	issuerKP := keypair.MustRandom()
+	h, horizonMock := newClawbackTestHandler(t, issuerKP)
+	holder := newClawbackTestHolder(horizonMock, issuerKP, true)
+
+	var submitted *txnbuild.Transaction
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Run(func(args mock.Arguments) { submitted = args.Get(0).(*txnbuild.Transaction) }).
+		Return(horizon.Transaction{}, nil)
+
+	_, err := h.create(ctx, "", clawbackCreateRequest{AssetCode: "FOO"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusUnauthorized, "Unauthorized."), err)
+
+	resp, err := h.create(ctx, "alice", clawbackCreateRequest{
+		AssetCode: "FOO",
+		Clawbacks: []clawback{{From: holder, Amount: "25"}},
+		Reason:    "Court order",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusSubmitted, resp.Status)
+	assert.Equal(t, issuerKP.Address(), resp.AssetIssuer)
+	assert.Equal(t, []clawback{{From: holder, Amount: "25.0000000"}}, resp.Clawbacks)
+	assert.Equal(t, "Court order", resp.Reason)
+	assert.Equal(t, "alice", resp.RequestedBy)
+	assert.Equal(t, "alice", resp.DecidedBy)
+	assert.NotNil(t, resp.DecidedAt)
+	assert.Empty(t, resp.Error)
+
+	require.NotNil(t, submitted)
+	txHash, err := submitted.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, txHash, resp.TxHash)
+	require.Len(t, submitted.Operations(), 1)
+	op, ok := submitted.Operations()[0].(*txnbuild.Clawback)
+	require.True(t, ok)
+	assert.Equal(t, holder, op.From)
+	assert.Equal(t, "25.0000000", op.Amount)
+	assert.Equal(t, txnbuild.CreditAsset{Code: "FOO", Issuer: issuerKP.Address()}, op.Asset)
+	assert.Equal(t, int64(2), submitted.SourceAccount().Sequence)
+
+	got, err := h.get(ctx, resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, resp, got)
+
+	// approved requests cannot be decided again
+	_, err = h.reject(ctx, "bob", clawbackDecisionRequest{ID: "1"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusConflict, "The clawback request is submitted."), err)
+}
+
+func TestClawbackHandler_createClawbackNotEnabled(t *testing.T) {
+	ctx := context.Background()
+	issuerKP := keypair.MustRandom()
+	h, horizonMock := newClawbackTestHandler(t, issuerKP)
+	enabledHolder := newClawbackTestHolder(horizonMock, issuerKP, true)
+	disabledHolder := newClawbackTestHolder(horizonMock, issuerKP, false)
+	otherAsset := keypair.MustRandom().Address()
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: otherAsset}).
+		Return(horizon.Account{AccountID: otherAsset}, nil)
+
+	resp, err := h.create(ctx, "alice", clawbackCreateRequest{
+		AssetCode: "FOO",
+		Clawbacks: []clawback{{From: enabledHolder, Amount: "1"}, {From: disabledHolder, Amount: "1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusFailed, resp.Status)
+	assert.Equal(t, "clawback is not enabled on the FOO trustline of account "+disabledHolder, resp.Error)
+	assert.Empty(t, resp.TxHash)
+
+	resp, err = h.create(ctx, "alice", clawbackCreateRequest{
+		AssetCode: "FOO",
+		Clawbacks: []clawback{{From: otherAsset, Amount: "1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusFailed, resp.Status)
+	assert.Equal(t, "account "+otherAsset+" has no FOO trustline", resp.Error)
+
+	// clawback is not enabled on the issuer account
+	otherIssuerKP := keypair.MustRandom()
+	h.assets = append(h.assets, regulatedAsset{code: "BAR", issuerKP: otherIssuerKP})
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: otherIssuerKP.Address()}).
+		Return(horizon.Account{AccountID: otherIssuerKP.Address(), Sequence: "1"}, nil)
+	resp, err = h.create(ctx, "alice", clawbackCreateRequest{
+		AssetCode: "BAR",
+		Clawbacks: []clawback{{From: enabledHolder, Amount: "1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusFailed, resp.Status)
+	assert.Equal(t, "clawback is not enabled on issuer account "+otherIssuerKP.Address(), resp.Error)
+
+	horizonMock.AssertNotCalled(t, "SubmitTransaction", mock.Anything)
+}
+
+func TestClawbackHandler_createSubmissionFailed(t *testing.T) {
+	ctx := context.Background()
+	issuerKP := keypair.MustRandom()
+	h, horizonMock := newClawbackTestHandler(t, issuerKP)
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(horizon.Transaction{}, horizonclient.Error{Problem: problem.P{
+			Title:  "Transaction Failed",
+			Status: http.StatusBadRequest,
+			Extras: map[string]interface{}{
+				"result_codes": horizon.TransactionResultCodes{TransactionCode: "tx_failed"},
+			},
+		}})
+
+	resp, err := h.create(ctx, "alice", clawbackCreateRequest{
+		AssetCode: "FOO",
+		Clawbacks: []clawback{{From: newClawbackTestHolder(horizonMock, issuerKP, true), Amount: "1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusFailed, resp.Status)
+	assert.Contains(t, resp.Error, "tx_failed")
+	assert.NotEmpty(t, resp.TxHash)
+
+	// failed requests are not reconciled
+	resp, err = h.reconcile(ctx, resp)
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusFailed, resp.Status)
+	horizonMock.AssertNotCalled(t, "TransactionDetail", mock.Anything)
+}
+
+func TestClawbackHandler_reconcile(t *testing.T) {
+	ctx := context.Background()
+	issuerKP := keypair.MustRandom()
+	h, horizonMock := newClawbackTestHandler(t, issuerKP)
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(horizon.Transaction{}, errors.New("context deadline exceeded"))
+
+	create := func() *clawbackResponse {
+		resp, err := h.create(ctx, "alice", clawbackCreateRequest{
+			AssetCode: "FOO",
+			Clawbacks: []clawback{{From: newClawbackTestHolder(horizonMock, issuerKP, true), Amount: "1"}},
+		})
+		require.NoError(t, err)
+		// the outcome of the submission is unknown
+		require.Equal(t, clawbackStatusApproved, resp.Status)
+		require.Contains(t, resp.Error, "context deadline exceeded")
+		require.NotEmpty(t, resp.Tx)
+		require.NotEmpty(t, resp.TxHash)
+		return resp
+	}
+	notFound := horizonclient.Error{Problem: problem.P{
+		Type:   "https://stellar.org/horizon-errors/not_found",
+		Status: http.StatusNotFound,
+	}}
+
+	// the transaction was included in a ledger
+	resp := create()
+	horizonMock.On("TransactionDetail", resp.TxHash).Return(horizon.Transaction{Successful: true}, nil).Once()
+	resp, err := h.reconcile(ctx, resp)
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusSubmitted, resp.Status)
+	assert.Empty(t, resp.Error)
+
+	// the transaction failed
+	resp = create()
+	horizonMock.On("TransactionDetail", resp.TxHash).Return(horizon.Transaction{Successful: false, ResultXdr: "AAAAAAAAAGT////6AAAAAA=="}, nil).Once()
+	resp, err = h.reconcile(ctx, resp)
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusFailed, resp.Status)
+	assert.Equal(t, "transaction failed with result AAAAAAAAAGT////6AAAAAA==", resp.Error)
+
+	// the transaction is not found before it expired
+	resp = create()
+	horizonMock.On("TransactionDetail", resp.TxHash).Return(horizon.Transaction{}, notFound).Once()
+	resp, err = h.reconcile(ctx, resp)
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusApproved, resp.Status)
+
+	// Horizon is unavailable
+	horizonMock.On("TransactionDetail", resp.TxHash).Return(horizon.Transaction{}, errors.New("connection refused")).Once()
+	resp, err = h.reconcile(ctx, resp)
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusApproved, resp.Status)
+
+	// the transaction is not found after it expired
+	expiredTx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &txnbuild.SimpleAccount{AccountID: issuerKP.Address(), Sequence: 1},
+		Operations:    []txnbuild.Operation{&txnbuild.BumpSequence{}},
+		BaseFee:       txnbuild.MinBaseFee,
+		Timebounds:    txnbuild.NewTimebounds(0, time.Now().Add(-transactionIngestionDelay).Unix()-1),
+	})
+	require.NoError(t, err)
+	expiredTxe, err := expiredTx.Base64()
+	require.NoError(t, err)
+	_, err = h.db.ExecContext(ctx, "UPDATE clawback_requests SET tx = $2 WHERE id = $1", resp.ID, expiredTxe)
+	require.NoError(t, err)
+	resp, err = h.get(ctx, resp.ID)
+	require.NoError(t, err)
+	horizonMock.On("TransactionDetail", resp.TxHash).Return(horizon.Transaction{}, notFound).Once()
+	resp, err = h.reconcile(ctx, resp)
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusFailed, resp.Status)
+	assert.Equal(t, "transaction expired without being included in a ledger", resp.Error)
+}
+
+func TestClawbackHandler_approvalRequired(t *testing.T) {
+	ctx := context.Background()
+	issuerKP := keypair.MustRandom()
+	h, horizonMock := newClawbackTestHandler(t, issuerKP)
+	h.approvalRequired = true
+	horizonMock.
+		On("SubmitTransaction", mock.AnythingOfType("*txnbuild.Transaction")).
+		Return(horizon.Transaction{}, nil)
+
+	in := clawbackCreateRequest{
+		AssetCode: "FOO",
+		Clawbacks: []clawback{{From: newClawbackTestHolder(horizonMock, issuerKP, true), Amount: "1"}},
+	}
+	resp, err := h.create(ctx, "alice", in)
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusPending, resp.Status)
+	assert.Empty(t, resp.DecidedBy)
+	assert.Empty(t, resp.TxHash)
+	horizonMock.AssertNotCalled(t, "SubmitTransaction", mock.Anything)
+	id := resp.ID
+
+	_, err = h.approve(ctx, "bob", clawbackDecisionRequest{ID: "foo"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Invalid clawback request id."), err)
+	_, err = h.approve(ctx, "bob", clawbackDecisionRequest{ID: "1000"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusNotFound, "Not found."), err)
+	_, err = h.approve(ctx, "", clawbackDecisionRequest{ID: "1"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusUnauthorized, "Unauthorized."), err)
+
+	// the requesting admin cannot approve the request
+	_, err = h.approve(ctx, "alice", clawbackDecisionRequest{ID: "1"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusForbidden, "The clawback request must be approved by an admin other than the one who requested it."), err)
+
+	resp, err = h.approve(ctx, "bob", clawbackDecisionRequest{ID: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, id, resp.ID)
+	assert.Equal(t, clawbackStatusSubmitted, resp.Status)
+	assert.Equal(t, "alice", resp.RequestedBy)
+	assert.Equal(t, "bob", resp.DecidedBy)
+	assert.NotEmpty(t, resp.TxHash)
+	horizonMock.AssertNumberOfCalls(t, "SubmitTransaction", 1)
+
+	_, err = h.approve(ctx, "carol", clawbackDecisionRequest{ID: "1"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusConflict, "The clawback request is submitted."), err)
+	horizonMock.AssertNumberOfCalls(t, "SubmitTransaction", 1)
+
+	// the requesting admin can reject the request
+	resp, err = h.create(ctx, "alice", in)
+	require.NoError(t, err)
+	resp, err = h.reject(ctx, "alice", clawbackDecisionRequest{ID: "2"})
+	require.NoError(t, err)
+	assert.Equal(t, clawbackStatusRejected, resp.Status)
+	assert.Equal(t, "alice", resp.DecidedBy)
+	assert.Empty(t, resp.TxHash)
+	_, err = h.approve(ctx, "bob", clawbackDecisionRequest{ID: "2"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusConflict, "The clawback request is rejected."), err)
+	horizonMock.AssertNumberOfCalls(t, "SubmitTransaction", 1)
+}
+
+func TestClawbackHandler_serveHTTP(t *testing.T) {
+	issuerKP := keypair.MustRandom()
+	holder := keypair.MustRandom().Address()
+	h, _ := newClawbackTestHandler(t, issuerKP)
+	h.approvalRequired = true
+
+	m := chi.NewMux()
+	m.Use(adminAuthHandler(map[string]string{"alice": "alice-key", "bob": "bob-key"}))
+	m.Post("/admin/clawback", h.serveCreate)
+	m.Get("/admin/clawback/{id}", h.serveGet)
+	m.Post("/admin/clawback/{id}/approve", h.serveApprove)
+	m.Post("/admin/clawback/{id}/reject", h.serveReject)
+
+	serve := func(apiKey, method, url, body string) (int, string) {
+		w := httptest.NewRecorder()
+		var r *http.Request
+		if body == "" {
+			r = httptest.NewRequest(method, url, nil)
+		} else {
+			r = httptest.NewRequest(method, url, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+		}
+		r.Header.Set("Authorization", "Bearer "+apiKey)
+		m.ServeHTTP(w, r)
+		resp := w.Result()
+		b, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(b)
+	}
+
+	status, body := serve("alice-key", "POST", "/admin/clawback", `{"asset_code": "FOO", "clawbacks": [{"from": "`+holder+`", "amount": "3"}]}`)
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Contains(t, body, `"status":"pending"`)
+	assert.Contains(t, body, `"clawbacks":[{"from":"`+holder+`","amount":"3.0000000"}]`)
+	assert.Contains(t, body, `"requested_by":"alice"`)
+
+	// the admin is identified by their API key, not by the request body
+	status, body = serve("alice-key", "POST", "/admin/clawback/1/approve", `{"admin": "bob"}`)
+	assert.Equal(t, http.StatusForbidden, status, body)
+
+	status, body = serve("bob-key", "POST", "/admin/clawback/1/reject", "")
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Contains(t, body, `"status":"rejected"`)
+	assert.Contains(t, body, `"decided_by":"bob"`)
+
+	status, body = serve("alice-key", "GET", "/admin/clawback/1", "")
+	require.Equal(t, http.StatusOK, status, body)
+	assert.Contains(t, body, `"status":"rejected"`)
+
+	status, body = serve("alice-key", "POST", "/admin/clawback", `{"asset_code": "BAR", "clawbacks": []}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"error": "BAR is not a regulated asset."}`, body)
+
+	status, _ = serve("other-key", "GET", "/admin/clawback/1", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
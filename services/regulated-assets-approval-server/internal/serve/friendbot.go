@@ -18,11 +18,15 @@ import (
 
 type friendbotHandler struct {
 	issuerAccountSecret string
-	assetCode           string
-	horizonClient       horizonclient.ClientInterface
-	horizonURL          string
-	networkPassphrase   string
-	paymentAmount       int
+	// issuerAddress is the issuer account, if issuerAccountSecret is one of
+	// its signers other than its master key.
+	issuerAddress     string
+	assetCode         string
+	horizonClient     horizonclient.ClientInterface
+	horizonURL        string
+	networkPassphrase string
+	paymentAmount     int
+	metrics           *metrics
 }
 
 func (h friendbotHandler) validate() error {
@@ -104,6 +108,7 @@ func (h friendbotHandler) topUpAccountWithRegulatedAsset(ctx context.Context, in
 
 	account, err := h.horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: in.Address})
 	if err != nil {
+		h.metrics.incHorizonError("account_detail")
 		log.Ctx(ctx).Error(errors.Wrapf(err, "getting detail for account %s", in.Address))
 		return httperror.NewHTTPError(http.StatusBadRequest, `Please make sure the provided account address already exists in the network.`)
 	}
@@ -115,9 +120,13 @@ func (h friendbotHandler) topUpAccountWithRegulatedAsset(ctx context.Context, in
 		return err
 	}
 
+	issuerAddress := h.issuerAddress
+	if issuerAddress == "" {
+		issuerAddress = kp.Address()
+	}
 	asset := txnbuild.CreditAsset{
 		Code:   h.assetCode,
-		Issuer: kp.Address(),
+		Issuer: issuerAddress,
 	}
 
 	var accountHasTrustline bool
@@ -131,9 +140,10 @@ func (h friendbotHandler) topUpAccountWithRegulatedAsset(ctx context.Context, in
 		return httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Account with address %s doesn't have a trustline for %s:%s", in.Address, asset.Code, asset.Issuer))
 	}
 
-	issuerAcc, err := h.horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: kp.Address()})
+	issuerAcc, err := h.horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: issuerAddress})
 	if err != nil {
-		log.Ctx(ctx).Error(errors.Wrapf(err, "getting detail for issuer account %s", kp.Address()))
+		h.metrics.incHorizonError("account_detail")
+		log.Ctx(ctx).Error(errors.Wrapf(err, "getting detail for issuer account %s", issuerAddress))
 		return httperror.InternalServer
 	}
 
@@ -175,6 +185,7 @@ func (h friendbotHandler) topUpAccountWithRegulatedAsset(ctx context.Context, in
 
 	_, err = h.horizonClient.SubmitTransaction(tx)
 	if err != nil {
+		h.metrics.incHorizonError("submit_transaction")
 		err = httperror.ParseHorizonError(err)
 		log.Ctx(ctx).Error(err)
 		return err
@@ -0,0 +1,100 @@
+package serve
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"strconv"
+
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/approvalpb"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	kycstatus "github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/kyc-status"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer implements approvalpb.ApprovalServer, exposing the same
+// functionality as the SEP-8 HTTP API to internal callers.
+type grpcServer struct {
+	kycstatus.GRPCServer
+	txApproveHandler txApproveHandler
+}
+
+func (s grpcServer) TxApprove(ctx context.Context, in *approvalpb.TxApproveRequest) (*approvalpb.TxApproveResponse, error) {
+	err := s.txApproveHandler.validate()
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating txApproveHandler"))
+		return nil, httperror.GRPCError(err)
+	}
+
+	resp, err := s.txApproveHandler.txApprove(ctx, txApproveRequest{Tx: in.Tx})
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating the input transaction for approval"))
+		return nil, httperror.GRPCError(err)
+	}
+
+	out := &approvalpb.TxApproveResponse{
+		Status:       string(resp.Status),
+		Error:        resp.Error,
+		Message:      resp.Message,
+		Tx:           resp.Tx,
+		ActionUrl:    resp.ActionURL,
+		ActionMethod: resp.ActionMethod,
+		ActionFields: resp.ActionFields,
+	}
+	if resp.Timeout != nil {
+		out.Timeout = *resp.Timeout
+	}
+	return out, nil
+}
+
+// grpcAuthInterceptor rejects the calls which do not have the
+// `authorization: Bearer {token}` metadata.
+func grpcAuthInterceptor(token string) grpc.UnaryServerInterceptor {
+	want := []byte("Bearer " + token)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var auth []byte
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("authorization"); len(values) == 1 {
+			auth = []byte(values[0])
+		}
+		if subtle.ConstantTimeCompare(auth, want) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "Unauthorized.")
+		}
+		return handler(ctx, req)
+	}
+}
+
+func newGRPCServer(opts Options, deps dependencies) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcAuthInterceptor(opts.GRPCAuthToken)))
+	approvalpb.RegisterApprovalServer(server, grpcServer{
+		GRPCServer:       kycstatus.GRPCServer{DB: deps.db, Provider: deps.kycProvider, Cases: deps.cases},
+		txApproveHandler: opts.txApproveHandler(deps),
+	})
+	return server
+}
+
+func serveGRPC(opts Options, deps dependencies) {
+	if opts.GRPCAuthToken == "" {
+		log.Fatal("grpc-auth-token must be set to serve gRPC")
+	}
+	host := opts.GRPCHost
+	if host == "" {
+		host = "localhost"
+	}
+	listenAddr := net.JoinHostPort(host, strconv.Itoa(opts.GRPCPort))
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		log.Fatal(errors.Wrapf(err, "listening on %s", listenAddr))
+	}
+
+	log.Infof("Serving gRPC on %s", listenAddr)
+	err = newGRPCServer(opts, deps).Serve(listener)
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "serving gRPC"))
+	}
+}
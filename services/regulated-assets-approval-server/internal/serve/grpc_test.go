@@ -0,0 +1,137 @@
+package serve
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/approvalpb"
+	kycstatus "github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/kyc-status"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPC(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+
+	kycThreshold, err := amount.ParseInt64("500")
+	require.NoError(t, err)
+	opts := Options{
+		AssetCode:         "FOO",
+		BaseURL:           "https://sep8-server.test",
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	}
+	deps := dependencies{
+		issuerKP:     keypair.MustRandom(),
+		kycThreshold: kycThreshold,
+		db:           conn,
+	}
+	// Use a mocked horizon client rather than the one built from the options.
+	h := opts.txApproveHandler(deps)
+	h.horizonClient = &horizonclient.MockClient{}
+	server := grpc.NewServer()
+	approvalpb.RegisterApprovalServer(server, grpcServer{
+		GRPCServer:       kycstatus.GRPCServer{DB: conn},
+		txApproveHandler: h,
+	})
+	defer server.Stop()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+
+	clientConn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer clientConn.Close()
+	client := approvalpb.NewApprovalClient(clientConn)
+
+	// Rejected transactions are not errors, the rejection is in the response.
+	txResp, err := client.TxApprove(ctx, &approvalpb.TxApproveRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", txResp.Status)
+	assert.Equal(t, `Missing parameter "tx".`, txResp.Error)
+
+	txResp, err = client.TxApprove(ctx, &approvalpb.TxApproveRequest{Tx: "BADXDR"})
+	require.NoError(t, err)
+	assert.Equal(t, "rejected", txResp.Status)
+	assert.Equal(t, `Invalid parameter "tx".`, txResp.Error)
+
+	// KYC status errors are mapped to gRPC status codes.
+	accountKP := keypair.MustRandom()
+	_, err = client.GetKYCStatus(ctx, &approvalpb.GetKYCStatusRequest{StellarAddressOrCallbackId: accountKP.Address()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.PostKYCStatus(ctx, &approvalpb.PostKYCStatusRequest{CallbackId: "1234", EmailAddress: "invalid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	callbackID := uuid.New().String()
+	_, err = conn.ExecContext(ctx, `INSERT INTO accounts_kyc_status (stellar_address, callback_id) VALUES ($1, $2)`, accountKP.Address(), callbackID)
+	require.NoError(t, err)
+
+	postResp, err := client.PostKYCStatus(ctx, &approvalpb.PostKYCStatusRequest{CallbackId: callbackID, EmailAddress: "test@email.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "no_further_action_required", postResp.Result)
+
+	kycStatus, err := client.GetKYCStatus(ctx, &approvalpb.GetKYCStatusRequest{StellarAddressOrCallbackId: callbackID})
+	require.NoError(t, err)
+	assert.Equal(t, accountKP.Address(), kycStatus.StellarAddress)
+	assert.Equal(t, callbackID, kycStatus.CallbackId)
+	assert.Equal(t, "test@email.com", kycStatus.EmailAddress)
+	assert.NotZero(t, kycStatus.CreatedAt)
+	assert.NotZero(t, kycStatus.ApprovedAt)
+	assert.Zero(t, kycStatus.RejectedAt)
+
+	_, err = client.DeleteKYCStatus(ctx, &approvalpb.DeleteKYCStatusRequest{StellarAddress: accountKP.Address()})
+	require.NoError(t, err)
+	_, err = client.DeleteKYCStatus(ctx, &approvalpb.DeleteKYCStatusRequest{StellarAddress: accountKP.Address()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+type stubApprovalServer struct {
+	approvalpb.ApprovalServer
+}
+
+func (stubApprovalServer) GetKYCStatus(ctx context.Context, in *approvalpb.GetKYCStatusRequest) (*approvalpb.KYCStatus, error) {
+	return &approvalpb.KYCStatus{StellarAddress: in.StellarAddressOrCallbackId}, nil
+}
+
+func TestGRPCAuth(t *testing.T) {
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpcAuthInterceptor("secret")))
+	approvalpb.RegisterApprovalServer(server, stubApprovalServer{})
+	defer server.Stop()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+
+	clientConn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	require.NoError(t, err)
+	defer clientConn.Close()
+	client := approvalpb.NewApprovalClient(clientConn)
+
+	ctx := context.Background()
+	req := &approvalpb.GetKYCStatusRequest{StellarAddressOrCallbackId: "GABC"}
+	_, err = client.GetKYCStatus(ctx, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.GetKYCStatus(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong"), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	kycStatus, err := client.GetKYCStatus(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer secret"), req)
+	require.NoError(t, err)
+	assert.Equal(t, "GABC", kycStatus.StellarAddress)
+}
@@ -0,0 +1,55 @@
+package httperror
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCStatus returns the gRPC status equivalent to the HTTP error, allowing
+// the error to be returned as is by gRPC handlers.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(grpcCode(e.Status), e.ErrorMessage)
+}
+
+// GRPCError converts an error returned by a handler into an error suitable
+// to be returned by a gRPC handler. Errors which are not an *Error are
+// replaced by InternalServer so that their details are not leaked.
+func GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	httpErr, ok := err.(*Error)
+	if !ok {
+		httpErr = InternalServer
+	}
+	return httpErr.GRPCStatus().Err()
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusOK:
+		return codes.OK
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
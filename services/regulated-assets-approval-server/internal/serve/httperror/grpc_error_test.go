@@ -0,0 +1,33 @@
+package httperror
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCError(t *testing.T) {
+	require.NoError(t, GRPCError(nil))
+
+	err := GRPCError(NewHTTPError(http.StatusNotFound, "Not found."))
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.NotFound, s.Code())
+	require.Equal(t, "Not found.", s.Message())
+
+	err = GRPCError(NewHTTPError(http.StatusBadRequest, "Missing stellar address."))
+	s, ok = status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.InvalidArgument, s.Code())
+
+	// Unknown errors don't leak their details.
+	err = GRPCError(errors.New("querying the database: connection refused"))
+	s, ok = status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Internal, s.Code())
+	require.Equal(t, InternalServer.ErrorMessage, s.Message())
+}
@@ -0,0 +1,69 @@
+package kycstatus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/support/errors"
+)
+
+// AdminCommands implements the kyc commands of the command line interface,
+// sharing their logic with the admin HTTP handlers. The results are written
+// to Out as indented JSON.
+type AdminCommands struct {
+	DB  *sqlx.DB
+	Out io.Writer
+	// Cases, if set, records the decisions in compliance cases.
+	Cases *cases.Store
+}
+
+// List writes the page of KYC statuses matching the filters, as listed by
+// the GET /admin/kyc-status endpoint. Empty filters are ignored and a limit
+// of 0 selects the default page size.
+func (c AdminCommands) List(ctx context.Context, status, createdAfter, createdBefore, cursor string, limit int) error {
+	h := AdminListHandler{DB: c.DB}
+	if err := h.validate(); err != nil {
+		return errors.Wrap(err, "validating kyc-status AdminListHandler")
+	}
+
+	in := adminListRequest{
+		Status:        status,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		Cursor:        cursor,
+	}
+	if limit != 0 {
+		in.Limit = strconv.Itoa(limit)
+	}
+	resp, err := h.handle(ctx, in)
+	if err != nil {
+		return err
+	}
+	return c.write(resp)
+}
+
+// Decide approves or rejects the KYC of an account, depending on status,
+// overriding the decision of the KYC provider, and writes the updated KYC
+// status.
+func (c AdminCommands) Decide(ctx context.Context, stellarAddress string, status CaseStatus) error {
+	h := AdminDecisionHandler{DB: c.DB, Status: status, Cases: c.Cases}
+	if err := h.validate(); err != nil {
+		return errors.Wrap(err, "validating kyc-status AdminDecisionHandler")
+	}
+
+	resp, err := h.handle(ctx, adminDecisionRequest{StellarAddress: stellarAddress})
+	if err != nil {
+		return err
+	}
+	return c.write(resp)
+}
+
+func (c AdminCommands) write(v interface{}) error {
+	enc := json.NewEncoder(c.Out)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(v), "writing result")
+}
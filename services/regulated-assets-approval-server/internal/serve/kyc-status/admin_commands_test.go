@@ -0,0 +1,56 @@
+package kycstatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminCommands(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+
+	accountAddress := keypair.MustRandom().Address()
+	_, err := conn.ExecContext(ctx, "INSERT INTO accounts_kyc_status (stellar_address, callback_id, kyc_submitted_at) VALUES ($1, 'callback-1', NOW())", accountAddress)
+	require.NoError(t, err)
+
+	out := bytes.Buffer{}
+	c := AdminCommands{DB: conn, Out: &out}
+
+	// The pending account is listed.
+	err = c.List(ctx, "pending", "", "", "", 0)
+	require.NoError(t, err)
+	list := adminListResponse{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &list))
+	require.Len(t, list.Records, 1)
+	assert.Equal(t, accountAddress, list.Records[0].StellarAddress)
+
+	err = c.List(ctx, "unknown", "", "", "", 0)
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Invalid status, must be one of approved, rejected, pending or not_submitted."), err)
+
+	// The account is approved.
+	out.Reset()
+	err = c.Decide(ctx, accountAddress, CaseStatusApproved)
+	require.NoError(t, err)
+	record := kycGetResponse{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, accountAddress, record.StellarAddress)
+	assert.NotNil(t, record.ApprovedAt)
+
+	err = c.Decide(ctx, keypair.MustRandom().Address(), CaseStatusRejected)
+	assert.Equal(t, httperror.NewHTTPError(http.StatusNotFound, "Not found."), err)
+
+	err = c.Decide(ctx, accountAddress, CaseStatusPending)
+	assert.EqualError(t, err, `validating kyc-status AdminDecisionHandler: invalid status "pending"`)
+}
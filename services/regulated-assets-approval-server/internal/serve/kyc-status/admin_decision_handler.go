@@ -0,0 +1,102 @@
+package kycstatus
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/adminauth"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpdecode"
+	"github.com/stellar/go/support/log"
+)
+
+// AdminDecisionHandler manually approves or rejects the KYC of an account,
+// overriding the decision of the KYC provider.
+type AdminDecisionHandler struct {
+	DB *sqlx.DB
+	// Status is the decision applied, either CaseStatusApproved or
+	// CaseStatusRejected.
+	Status CaseStatus
+	// Cases, if set, records the decisions in compliance cases.
+	Cases *cases.Store
+}
+
+type adminDecisionRequest struct {
+	StellarAddress string `path:"stellar_address"`
+}
+
+func (h AdminDecisionHandler) validate() error {
+	if h.DB == nil {
+		return errors.New("database cannot be nil")
+	}
+	if h.Status != CaseStatusApproved && h.Status != CaseStatusRejected {
+		return errors.Errorf("invalid status %q", h.Status)
+	}
+	return nil
+}
+
+func (h AdminDecisionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	err := h.validate()
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating kyc-status AdminDecisionHandler"))
+		httperror.InternalServer.Render(w)
+		return
+	}
+
+	in := adminDecisionRequest{}
+	err = httpdecode.Decode(r, &in)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "decoding kyc-status admin decision Request"))
+		httperror.BadRequest.Render(w)
+		return
+	}
+
+	resp, err := h.handle(ctx, in)
+	if err != nil {
+		httpErr, ok := err.(*httperror.Error)
+		if !ok {
+			log.Ctx(ctx).Error(errors.Wrap(err, "applying kyc decision"))
+			httpErr = httperror.InternalServer
+		}
+		httpErr.Render(w)
+		return
+	}
+
+	resp.Render(w)
+}
+
+func (h AdminDecisionHandler) handle(ctx context.Context, in adminDecisionRequest) (*kycGetResponse, error) {
+	if in.StellarAddress == "" {
+		return nil, httperror.NewHTTPError(http.StatusBadRequest, "Missing stellar address.")
+	}
+
+	query := `
+		UPDATE accounts_kyc_status
+		SET ` + caseStatusAssignments(h.Status) + `
+		WHERE stellar_address = $1
+		RETURNING ` + kycStatusColumns
+	resp, err := scanKYCStatus(h.DB.QueryRowContext(ctx, query, in.StellarAddress))
+	if err == sql.ErrNoRows {
+		return nil, httperror.NewHTTPError(http.StatusNotFound, "Not found.")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "querying the database")
+	}
+
+	reviewer := adminauth.FromContext(ctx)
+	if reviewer == "" {
+		reviewer = adminReviewer
+	}
+	err = recordComplianceCase(ctx, h.Cases, in.StellarAddress, h.Status, reviewer)
+	if err != nil {
+		return nil, errors.Wrap(err, "recording compliance case")
+	}
+
+	log.Ctx(ctx).Infof("KYC of %s manually set to %s by %s", in.StellarAddress, h.Status, reviewer)
+	return resp, nil
+}
@@ -0,0 +1,68 @@
+package kycstatus
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/adminauth"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminDecisionHandlerValidate(t *testing.T) {
+	h := AdminDecisionHandler{}
+	err := h.validate()
+	require.EqualError(t, err, "database cannot be nil")
+
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	h = AdminDecisionHandler{DB: conn, Status: CaseStatusPending}
+	err = h.validate()
+	require.EqualError(t, err, `invalid status "pending"`)
+
+	h = AdminDecisionHandler{DB: conn, Status: CaseStatusApproved}
+	err = h.validate()
+	require.NoError(t, err)
+}
+
+func TestAdminDecisionHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+
+	accountAddress := keypair.MustRandom().Address()
+	_, err := conn.ExecContext(ctx, "INSERT INTO accounts_kyc_status (stellar_address, callback_id, rejected_at) VALUES ($1, 'callback-1', NOW())", accountAddress)
+	require.NoError(t, err)
+
+	h := AdminDecisionHandler{DB: conn, Status: CaseStatusApproved}
+	_, err = h.handle(ctx, adminDecisionRequest{StellarAddress: keypair.MustRandom().Address()})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusNotFound, "Not found."), err)
+
+	resp, err := h.handle(ctx, adminDecisionRequest{StellarAddress: accountAddress})
+	require.NoError(t, err)
+	assert.Equal(t, accountAddress, resp.StellarAddress)
+	assert.NotNil(t, resp.ApprovedAt)
+	assert.Nil(t, resp.RejectedAt)
+
+	// The decisions are reviewed by the authenticated admin.
+	store := &cases.Store{DB: conn}
+	h = AdminDecisionHandler{DB: conn, Cases: store, Status: CaseStatusRejected}
+	resp, err = h.handle(adminauth.NewContext(ctx, "alice"), adminDecisionRequest{StellarAddress: accountAddress})
+	require.NoError(t, err)
+	assert.Nil(t, resp.ApprovedAt)
+	assert.NotNil(t, resp.RejectedAt)
+	records, err := store.List(ctx, cases.ListFilter{Kind: ComplianceCaseKind, Entity: accountAddress})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, cases.StatusRejected, records[0].Status)
+	assert.Equal(t, "alice", records[0].Reviewer)
+}
@@ -0,0 +1,179 @@
+package kycstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpdecode"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/httpjson"
+)
+
+const (
+	defaultAdminListLimit = 10
+	maxAdminListLimit     = 200
+)
+
+// AdminListHandler lists the KYC statuses of accounts, ordered by stellar
+// address.
+type AdminListHandler struct {
+	DB *sqlx.DB
+}
+
+type adminListRequest struct {
+	// Status is one of approved, rejected, pending (submitted but not decided)
+	// or not_submitted.
+	Status string `query:"status"`
+	// CreatedAfter and CreatedBefore filter the accounts by the time they
+	// first required KYC, in the RFC 3339 format.
+	CreatedAfter  string `query:"created_after"`
+	CreatedBefore string `query:"created_before"`
+	// Cursor is the stellar address after which the accounts are listed.
+	Cursor string `query:"cursor"`
+	Limit  string `query:"limit"`
+}
+
+type adminListResponse struct {
+	Records []*kycGetResponse `json:"records"`
+	// NextCursor is the cursor of the next page, empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func (h AdminListHandler) validate() error {
+	if h.DB == nil {
+		return errors.New("database cannot be nil")
+	}
+	return nil
+}
+
+func (h AdminListHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	err := h.validate()
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating kyc-status AdminListHandler"))
+		httperror.InternalServer.Render(w)
+		return
+	}
+
+	in := adminListRequest{}
+	err = httpdecode.Decode(r, &in)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "decoding kyc-status admin list Request"))
+		httperror.BadRequest.Render(w)
+		return
+	}
+
+	resp, err := h.handle(ctx, in)
+	if err != nil {
+		httpErr, ok := err.(*httperror.Error)
+		if !ok {
+			log.Ctx(ctx).Error(errors.Wrap(err, "listing kyc statuses"))
+			httpErr = httperror.InternalServer
+		}
+		httpErr.Render(w)
+		return
+	}
+
+	httpjson.Render(w, resp, httpjson.JSON)
+}
+
+func (h AdminListHandler) handle(ctx context.Context, in adminListRequest) (*adminListResponse, error) {
+	query, args, limit, err := in.buildListQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := h.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "querying the database")
+	}
+	defer rows.Close()
+
+	resp := &adminListResponse{Records: []*kycGetResponse{}}
+	for rows.Next() {
+		record, err := scanKYCStatus(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "scanning kyc status")
+		}
+		resp.Records = append(resp.Records, record)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "iterating kyc statuses")
+	}
+
+	if len(resp.Records) == limit {
+		resp.NextCursor = resp.Records[limit-1].StellarAddress
+	}
+	return resp, nil
+}
+
+// buildListQuery builds the query selecting a page of kyc statuses matching
+// the request filters, and returns it along with the page size.
+func (in adminListRequest) buildListQuery() (string, []interface{}, int, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	switch in.Status {
+	case "":
+	case "approved":
+		conditions = append(conditions, "approved_at IS NOT NULL")
+	case "rejected":
+		conditions = append(conditions, "rejected_at IS NOT NULL")
+	case "pending":
+		conditions = append(conditions, "kyc_submitted_at IS NOT NULL AND approved_at IS NULL AND rejected_at IS NULL")
+	case "not_submitted":
+		conditions = append(conditions, "kyc_submitted_at IS NULL")
+	default:
+		return "", nil, 0, httperror.NewHTTPError(http.StatusBadRequest, "Invalid status, must be one of approved, rejected, pending or not_submitted.")
+	}
+
+	for _, filter := range []struct {
+		value, name, operator string
+	}{
+		{in.CreatedAfter, "created_after", ">="},
+		{in.CreatedBefore, "created_before", "<"},
+	} {
+		if filter.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, filter.value)
+		if err != nil {
+			return "", nil, 0, httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid %s, must be in the RFC 3339 format.", filter.name))
+		}
+		args = append(args, t)
+		conditions = append(conditions, fmt.Sprintf("created_at %s $%d", filter.operator, len(args)))
+	}
+
+	if in.Cursor != "" {
+		args = append(args, in.Cursor)
+		conditions = append(conditions, fmt.Sprintf("stellar_address > $%d", len(args)))
+	}
+
+	limit := defaultAdminListLimit
+	if in.Limit != "" {
+		var err error
+		limit, err = strconv.Atoi(in.Limit)
+		if err != nil || limit < 1 || limit > maxAdminListLimit {
+			return "", nil, 0, httperror.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid limit, must be between 1 and %d.", maxAdminListLimit))
+		}
+	}
+
+	var query strings.Builder
+	query.WriteString("SELECT " + kycStatusColumns + " FROM accounts_kyc_status ")
+	if len(conditions) > 0 {
+		query.WriteString("WHERE " + strings.Join(conditions, " AND ") + " ")
+	}
+	args = append(args, limit)
+	query.WriteString(fmt.Sprintf("ORDER BY stellar_address ASC LIMIT $%d", len(args)))
+
+	return query.String(), args, limit, nil
+}
@@ -0,0 +1,91 @@
+package kycstatus
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminListRequestBuildListQuery(t *testing.T) {
+	// Test default query.
+	query, args, limit, err := adminListRequest{}.buildListQuery()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT "+kycStatusColumns+" FROM accounts_kyc_status ORDER BY stellar_address ASC LIMIT $1", query)
+	assert.Equal(t, []interface{}{10}, args)
+	assert.Equal(t, 10, limit)
+
+	// Test all filters.
+	createdAfter := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	createdBefore := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	query, args, limit, err = adminListRequest{
+		Status:        "pending",
+		CreatedAfter:  "2021-06-01T00:00:00Z",
+		CreatedBefore: "2021-07-01T00:00:00Z",
+		Cursor:        "GA",
+		Limit:         "50",
+	}.buildListQuery()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT "+kycStatusColumns+" FROM accounts_kyc_status WHERE kyc_submitted_at IS NOT NULL AND approved_at IS NULL AND rejected_at IS NULL AND created_at >= $1 AND created_at < $2 AND stellar_address > $3 ORDER BY stellar_address ASC LIMIT $4", query)
+	assert.Equal(t, []interface{}{createdAfter, createdBefore, "GA", 50}, args)
+	assert.Equal(t, 50, limit)
+
+	// Test invalid filters.
+	_, _, _, err = adminListRequest{Status: "unknown"}.buildListQuery()
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Invalid status, must be one of approved, rejected, pending or not_submitted."), err)
+	_, _, _, err = adminListRequest{CreatedAfter: "2021-06-01"}.buildListQuery()
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Invalid created_after, must be in the RFC 3339 format."), err)
+	_, _, _, err = adminListRequest{Limit: "201"}.buildListQuery()
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Invalid limit, must be between 1 and 200."), err)
+}
+
+func TestAdminListHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	h := AdminListHandler{DB: conn}
+
+	const q = `
+		INSERT INTO accounts_kyc_status (stellar_address, callback_id, kyc_submitted_at, approved_at, rejected_at)
+		VALUES ($1, $2, NOW(), $3, $4)
+	`
+	now := time.Now()
+	approved := []string{keypair.MustRandom().Address(), keypair.MustRandom().Address(), keypair.MustRandom().Address()}
+	for i, address := range approved {
+		_, err := conn.ExecContext(ctx, q, address, string(rune('a'+i)), now, nil)
+		require.NoError(t, err)
+	}
+	rejected := keypair.MustRandom().Address()
+	_, err := conn.ExecContext(ctx, q, rejected, "z", nil, now)
+	require.NoError(t, err)
+
+	resp, err := h.handle(ctx, adminListRequest{Status: "rejected"})
+	require.NoError(t, err)
+	require.Len(t, resp.Records, 1)
+	assert.Equal(t, rejected, resp.Records[0].StellarAddress)
+	assert.Empty(t, resp.NextCursor)
+
+	// Test pagination over the approved accounts.
+	var listed []string
+	in := adminListRequest{Status: "approved", Limit: "2"}
+	for {
+		resp, err = h.handle(ctx, in)
+		require.NoError(t, err)
+		for _, record := range resp.Records {
+			listed = append(listed, record.StellarAddress)
+		}
+		if resp.NextCursor == "" {
+			break
+		}
+		in.Cursor = resp.NextCursor
+	}
+	assert.ElementsMatch(t, approved, listed)
+}
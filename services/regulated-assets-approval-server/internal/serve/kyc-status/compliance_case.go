@@ -0,0 +1,59 @@
+package kycstatus
+
+import (
+	"context"
+
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// ComplianceCaseKind is the kind of the compliance cases tracking the
+	// KYC reviews of accounts, whose entity is the Stellar address.
+	ComplianceCaseKind = "kyc"
+	// providerReviewer is the reviewer of the compliance cases decided by the
+	// KYC provider.
+	providerReviewer = "kyc-provider"
+	// adminReviewer is the reviewer of the compliance cases decided through
+	// the admin commands, the cases decided through the admin API being
+	// reviewed by the authenticated admin.
+	adminReviewer = "admin"
+)
+
+// recordComplianceCase records the KYC status of an account in its latest
+// compliance case. A new case is opened when the account has none, or when
+// its KYC information is submitted again after the latest case was decided.
+// It does nothing if store is nil.
+func recordComplianceCase(ctx context.Context, store *cases.Store, stellarAddress string, status CaseStatus, reviewer string) error {
+	if store == nil {
+		return nil
+	}
+
+	existing, err := store.List(ctx, cases.ListFilter{Kind: ComplianceCaseKind, Entity: stellarAddress})
+	if err != nil {
+		return errors.Wrap(err, "listing compliance cases")
+	}
+	var c *cases.Case
+	if len(existing) > 0 {
+		c = &existing[len(existing)-1]
+	}
+	if c == nil || (c.Status.Decided() && status == CaseStatusPending) {
+		c, err = store.Create(ctx, ComplianceCaseKind, stellarAddress)
+		if err != nil {
+			return errors.Wrap(err, "opening compliance case")
+		}
+	}
+
+	caseStatus := cases.StatusInReview
+	switch status {
+	case CaseStatusApproved:
+		caseStatus = cases.StatusApproved
+	case CaseStatusRejected:
+		caseStatus = cases.StatusRejected
+	}
+	_, err = store.UpdateStatus(ctx, c.ID, caseStatus, reviewer)
+	if err != nil {
+		return errors.Wrap(err, "updating compliance case status")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package kycstatus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordComplianceCase(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	store := &cases.Store{DB: conn}
+
+	address := keypair.MustRandom().Address()
+	list := func() []cases.Case {
+		records, err := store.List(ctx, cases.ListFilter{Kind: ComplianceCaseKind, Entity: address})
+		require.NoError(t, err)
+		return records
+	}
+
+	// Submitting KYC information opens a case.
+	err := recordComplianceCase(ctx, store, address, CaseStatusPending, providerReviewer)
+	require.NoError(t, err)
+	records := list()
+	require.Len(t, records, 1)
+	assert.Equal(t, cases.StatusInReview, records[0].Status)
+	assert.Equal(t, providerReviewer, records[0].Reviewer)
+
+	// Decisions update the case.
+	err = recordComplianceCase(ctx, store, address, CaseStatusApproved, adminReviewer)
+	require.NoError(t, err)
+	records = list()
+	require.Len(t, records, 1)
+	assert.Equal(t, cases.StatusApproved, records[0].Status)
+	assert.Equal(t, adminReviewer, records[0].Reviewer)
+
+	// Submitting KYC information again opens a new case.
+	err = recordComplianceCase(ctx, store, address, CaseStatusPending, providerReviewer)
+	require.NoError(t, err)
+	records = list()
+	require.Len(t, records, 2)
+	assert.Equal(t, cases.StatusApproved, records[0].Status)
+	assert.Equal(t, cases.StatusInReview, records[1].Status)
+
+	// Without a store nothing is recorded.
+	err = recordComplianceCase(ctx, nil, keypair.MustRandom().Address(), CaseStatusApproved, adminReviewer)
+	require.NoError(t, err)
+}
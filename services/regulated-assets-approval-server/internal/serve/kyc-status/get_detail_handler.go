@@ -18,6 +18,7 @@ type kycGetResponse struct {
 	StellarAddress string     `json:"stellar_address"`
 	CallbackID     string     `json:"callback_id"`
 	EmailAddress   string     `json:"email_address,omitempty"`
+	KYCCaseID      string     `json:"kyc_case_id,omitempty"`
 	CreatedAt      *time.Time `json:"created_at"`
 	KYCSubmittedAt *time.Time `json:"kyc_submitted_at,omitempty"`
 	ApprovedAt     *time.Time `json:"approved_at,omitempty"`
@@ -87,19 +88,12 @@ func (h GetDetailHandler) handle(ctx context.Context, in getDetailRequest) (resp
 		return nil, httperror.NewHTTPError(http.StatusBadRequest, "Missing stellar address or callbackID")
 	}
 
-	// Prepare SELECT query return values.
-	var (
-		stellarAddress, callbackID             string
-		emailAddress                           sql.NullString
-		createdAt                              time.Time
-		kycSubmittedAt, approvedAt, rejectedAt sql.NullTime
-	)
 	const q = `
-		SELECT stellar_address, email_address, created_at, kyc_submitted_at, approved_at, rejected_at, callback_id
+		SELECT ` + kycStatusColumns + `
 		FROM accounts_kyc_status
 		WHERE stellar_address = $1 OR callback_id = $1
 	`
-	err = h.DB.QueryRowContext(ctx, q, in.StellarAddressOrCallbackID).Scan(&stellarAddress, &emailAddress, &createdAt, &kycSubmittedAt, &approvedAt, &rejectedAt, &callbackID)
+	resp, err = scanKYCStatus(h.DB.QueryRowContext(ctx, q, in.StellarAddressOrCallbackID))
 	if err == sql.ErrNoRows {
 		return nil, httperror.NewHTTPError(http.StatusNotFound, "Not found.")
 	}
@@ -107,10 +101,36 @@ func (h GetDetailHandler) handle(ctx context.Context, in getDetailRequest) (resp
 		return nil, errors.Wrap(err, "querying the database")
 	}
 
+	return resp, nil
+}
+
+// kycStatusColumns are the columns of accounts_kyc_status scanned by
+// scanKYCStatus.
+const kycStatusColumns = "stellar_address, email_address, created_at, kyc_submitted_at, approved_at, rejected_at, callback_id, kyc_case_id"
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanKYCStatus scans a row of the kycStatusColumns.
+func scanKYCStatus(row rowScanner) (*kycGetResponse, error) {
+	var (
+		stellarAddress, callbackID             string
+		emailAddress, kycCaseID                sql.NullString
+		createdAt                              time.Time
+		kycSubmittedAt, approvedAt, rejectedAt sql.NullTime
+	)
+	err := row.Scan(&stellarAddress, &emailAddress, &createdAt, &kycSubmittedAt, &approvedAt, &rejectedAt, &callbackID, &kycCaseID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &kycGetResponse{
 		StellarAddress: stellarAddress,
 		CallbackID:     callbackID,
 		EmailAddress:   emailAddress.String,
+		KYCCaseID:      kycCaseID.String,
 		CreatedAt:      &createdAt,
 		KYCSubmittedAt: timePointerIfValid(kycSubmittedAt),
 		ApprovedAt:     timePointerIfValid(approvedAt),
@@ -0,0 +1,87 @@
+package kycstatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/approvalpb"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// GRPCServer implements the kyc-status methods of the approvalpb.ApprovalServer
+// interface, sharing its logic with the HTTP handlers.
+type GRPCServer struct {
+	DB       *sqlx.DB
+	Provider Provider
+	// Cases, if set, records the reviews in compliance cases.
+	Cases *cases.Store
+}
+
+func (s GRPCServer) PostKYCStatus(ctx context.Context, in *approvalpb.PostKYCStatusRequest) (*approvalpb.PostKYCStatusResponse, error) {
+	h := PostHandler{DB: s.DB, Provider: s.Provider, Cases: s.Cases}
+	if err := h.validate(); err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating kyc-status PostHandler"))
+		return nil, httperror.GRPCError(err)
+	}
+
+	resp, err := h.handle(ctx, kycPostRequest{
+		CallbackID:   in.CallbackId,
+		EmailAddress: in.EmailAddress,
+	})
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating the input gRPC request for kyc-status"))
+		return nil, httperror.GRPCError(err)
+	}
+
+	return &approvalpb.PostKYCStatusResponse{Result: resp.Result}, nil
+}
+
+func (s GRPCServer) GetKYCStatus(ctx context.Context, in *approvalpb.GetKYCStatusRequest) (*approvalpb.KYCStatus, error) {
+	h := GetDetailHandler{DB: s.DB}
+	if err := h.validate(); err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating kyc-status GetDetailHandler"))
+		return nil, httperror.GRPCError(err)
+	}
+
+	resp, err := h.handle(ctx, getDetailRequest{StellarAddressOrCallbackID: in.StellarAddressOrCallbackId})
+	if err != nil {
+		return nil, httperror.GRPCError(err)
+	}
+
+	return &approvalpb.KYCStatus{
+		StellarAddress: resp.StellarAddress,
+		CallbackId:     resp.CallbackID,
+		EmailAddress:   resp.EmailAddress,
+		CreatedAt:      unixOrZero(resp.CreatedAt),
+		KycSubmittedAt: unixOrZero(resp.KYCSubmittedAt),
+		ApprovedAt:     unixOrZero(resp.ApprovedAt),
+		RejectedAt:     unixOrZero(resp.RejectedAt),
+	}, nil
+}
+
+func (s GRPCServer) DeleteKYCStatus(ctx context.Context, in *approvalpb.DeleteKYCStatusRequest) (*approvalpb.DeleteKYCStatusResponse, error) {
+	h := DeleteHandler{DB: s.DB}
+	if err := h.validate(); err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating kyc-status DeleteHandler"))
+		return nil, httperror.GRPCError(err)
+	}
+
+	err := h.handle(ctx, deleteRequest{StellarAddress: in.StellarAddress})
+	if err != nil {
+		return nil, httperror.GRPCError(err)
+	}
+
+	return &approvalpb.DeleteKYCStatusResponse{}, nil
+}
+
+// unixOrZero returns the unix timestamp of t, or zero if t is nil.
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
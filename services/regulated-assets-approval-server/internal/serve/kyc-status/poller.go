@@ -0,0 +1,76 @@
+package kycstatus
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// Poller periodically asks a Provider for the status of the pending cases,
+// for providers which do not notify the webhook of their decisions.
+type Poller struct {
+	DB       *sqlx.DB
+	Provider Provider
+	Interval time.Duration
+	// Cases, if set, records the decisions in compliance cases.
+	Cases *cases.Store
+}
+
+// Run polls the pending cases every Interval until ctx is done.
+func (p Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := p.poll(ctx)
+			if err != nil {
+				log.Ctx(ctx).Error(errors.Wrap(err, "polling pending KYC cases"))
+			}
+		}
+	}
+}
+
+func (p Poller) poll(ctx context.Context) error {
+	const q = `
+		SELECT kyc_case_id
+		FROM accounts_kyc_status
+		WHERE kyc_case_id IS NOT NULL AND approved_at IS NULL AND rejected_at IS NULL
+	`
+	var caseIDs []string
+	err := p.DB.SelectContext(ctx, &caseIDs, q)
+	if err != nil {
+		return errors.Wrap(err, "querying pending cases")
+	}
+
+	for _, caseID := range caseIDs {
+		status, err := p.Provider.CaseStatus(ctx, caseID)
+		if err != nil {
+			log.Ctx(ctx).Error(errors.Wrapf(err, "getting status of KYC case %s", caseID))
+			continue
+		}
+		if status == CaseStatusPending {
+			continue
+		}
+		stellarAddress, err := updateCaseStatus(ctx, p.DB, caseID, status)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "updating status of KYC case %s", caseID)
+		}
+		err = recordComplianceCase(ctx, p.Cases, stellarAddress, status, providerReviewer)
+		if err != nil {
+			return errors.Wrapf(err, "recording compliance case of KYC case %s", caseID)
+		}
+	}
+	return nil
+}
@@ -2,12 +2,14 @@ package kycstatus
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
-	"regexp"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/protocols/sep9"
 	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/http/httpdecode"
@@ -38,6 +40,11 @@ func NewKYCStatusPostResponse() *kycPostResponse {
 
 type PostHandler struct {
 	DB *sqlx.DB
+	// Provider reviews the submitted KYC information. Defaults to
+	// RuleProvider.
+	Provider Provider
+	// Cases, if set, records the reviews in compliance cases.
+	Cases *cases.Store
 }
 
 func (h PostHandler) validate() error {
@@ -47,6 +54,13 @@ func (h PostHandler) validate() error {
 	return nil
 }
 
+func (h PostHandler) provider() Provider {
+	if h.Provider == nil {
+		return RuleProvider{}
+	}
+	return h.Provider
+}
+
 func (h PostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -99,8 +113,26 @@ func (h PostHandler) handle(ctx context.Context, in kycPostRequest) (resp *kycPo
 		return nil, httperror.NewHTTPError(http.StatusBadRequest, "The provided email_address is invalid.")
 	}
 
+	var stellarAddress string
+	err = h.DB.QueryRowContext(ctx, "SELECT stellar_address FROM accounts_kyc_status WHERE callback_id = $1", in.CallbackID).Scan(&stellarAddress)
+	if err == sql.ErrNoRows {
+		return nil, httperror.NewHTTPError(http.StatusNotFound, "Not found.")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "querying the database")
+	}
+
+	kycCase, err := h.provider().Submit(ctx, Submission{
+		StellarAddress: stellarAddress,
+		CallbackID:     in.CallbackID,
+		EmailAddress:   in.EmailAddress,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "submitting KYC information to the provider")
+	}
+
 	var exists bool
-	query, args := in.buildUpdateKYCQuery()
+	query, args := in.buildUpdateKYCQuery(kycCase)
 	err = h.DB.QueryRowContext(ctx, query, args...).Scan(&exists)
 	if err != nil {
 		return nil, errors.Wrap(err, "querying the database")
@@ -109,18 +141,17 @@ func (h PostHandler) handle(ctx context.Context, in kycPostRequest) (resp *kycPo
 		return nil, httperror.NewHTTPError(http.StatusNotFound, "Not found.")
 	}
 
-	return NewKYCStatusPostResponse(), nil
-}
+	err = recordComplianceCase(ctx, h.Cases, stellarAddress, kycCase.Status, providerReviewer)
+	if err != nil {
+		return nil, errors.Wrap(err, "recording compliance case")
+	}
 
-// isKYCRuleRespected validates if KYC data is approved or rejected.
-// Current rule(s) emails starting "x" are rejected and other emails are automatically approved.
-func (in kycPostRequest) isKYCRuleRespected() bool {
-	return !strings.HasPrefix(strings.ToLower(in.EmailAddress), "x")
+	return NewKYCStatusPostResponse(), nil
 }
 
-// buildUpdateKYCQuery builds a query that will approve or reject stellar account from accounts_kyc_status table.
-// Afterwards the query should return an exists boolean if present.
-func (in kycPostRequest) buildUpdateKYCQuery() (string, []interface{}) {
+// buildUpdateKYCQuery builds a query that will store the KYC case of a stellar account in the accounts_kyc_status table,
+// approving or rejecting the account if the case is decided. Afterwards the query should return an exists boolean if present.
+func (in kycPostRequest) buildUpdateKYCQuery(kycCase Case) (string, []interface{}) {
 	var (
 		query strings.Builder
 		args  []interface{}
@@ -133,12 +164,12 @@ func (in kycPostRequest) buildUpdateKYCQuery() (string, []interface{}) {
 	args = append(args, in.EmailAddress)
 	query.WriteString(fmt.Sprintf("email_address = $%d, ", len(args)))
 
-	// Check if KYC info is approved or rejected
-	if in.isKYCRuleRespected() {
-		query.WriteString("approved_at = NOW(), rejected_at = NULL ")
-	} else {
-		query.WriteString("rejected_at = NOW(), approved_at = NULL ")
-	}
+	// Append the provider's case ID, if any, for query built.
+	args = append(args, sql.NullString{String: kycCase.ID, Valid: kycCase.ID != ""})
+	query.WriteString(fmt.Sprintf("kyc_case_id = $%d, ", len(args)))
+
+	// Check if KYC info is approved, rejected or pending.
+	query.WriteString(caseStatusAssignments(kycCase.Status))
 
 	// Append CallbackID for query built.
 	args = append(args, in.CallbackID)
@@ -156,6 +187,20 @@ func (in kycPostRequest) buildUpdateKYCQuery() (string, []interface{}) {
 	return query.String(), args
 }
 
+// caseStatusAssignments returns the SET assignments of the approved_at and
+// rejected_at columns matching a case status.
+func caseStatusAssignments(status CaseStatus) string {
+	switch status {
+	case CaseStatusApproved:
+		return "approved_at = NOW(), rejected_at = NULL "
+	case CaseStatusRejected:
+		return "rejected_at = NOW(), approved_at = NULL "
+	default:
+		return "approved_at = NULL, rejected_at = NULL "
+	}
+}
+
 // RxEmail is a regex used to validate e-mail addresses, according with the reference https://www.alexedwards.net/blog/validation-snippets-for-go#email-validation.
 // It's free to use under the [MIT Licence](https://opensource.org/licenses/MIT)
-var RxEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+// It is the validator of the SEP-9 email fields.
+var RxEmail = sep9.RxEmail
@@ -1,6 +1,7 @@
 package kycstatus
 
 import (
+	"database/sql"
 	"testing"
 
 	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
@@ -25,41 +26,33 @@ func TestPostHandlerValidate(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestIsKYCRuleRespected(t *testing.T) {
-	// Test if email approved.
-	in := kycPostRequest{
-		EmailAddress: "test@email.com",
-	}
-	approved := in.isKYCRuleRespected()
-	assert.True(t, approved)
-	// Test if email rejected.
-	in = kycPostRequest{
-		EmailAddress: "xtest@email.com",
-	}
-	approved = in.isKYCRuleRespected()
-	assert.False(t, approved)
-}
-
 func TestBuildUpdateKYCQuery(t *testing.T) {
-	// Test query returned if email approved.
+	// Test query returned if KYC approved.
 	in := kycPostRequest{
 		CallbackID:   "1234567890-12345",
 		EmailAddress: "test@email.com",
 	}
-	query, args := in.buildUpdateKYCQuery()
-	expectedQuery := "WITH updated_row AS (UPDATE accounts_kyc_status SET kyc_submitted_at = NOW(), email_address = $1, approved_at = NOW(), rejected_at = NULL WHERE callback_id = $2 RETURNING * )\n\t\tSELECT EXISTS(\n\t\t\tSELECT * FROM updated_row\n\t\t)\n\t"
-	expectedArgs := []interface{}{in.EmailAddress, in.CallbackID}
+	query, args := in.buildUpdateKYCQuery(Case{Status: CaseStatusApproved})
+	expectedQuery := "WITH updated_row AS (UPDATE accounts_kyc_status SET kyc_submitted_at = NOW(), email_address = $1, kyc_case_id = $2, approved_at = NOW(), rejected_at = NULL WHERE callback_id = $3 RETURNING * )\n\t\tSELECT EXISTS(\n\t\t\tSELECT * FROM updated_row\n\t\t)\n\t"
+	expectedArgs := []interface{}{in.EmailAddress, sql.NullString{}, in.CallbackID}
 	require.Equal(t, expectedQuery, query)
 	require.Equal(t, expectedArgs, args)
 
-	// Test query returned if email rejected.
+	// Test query returned if KYC rejected.
 	in = kycPostRequest{
 		CallbackID:   "9999999999-9999",
 		EmailAddress: "xtest@email.com",
 	}
-	query, args = in.buildUpdateKYCQuery()
-	expectedQuery = "WITH updated_row AS (UPDATE accounts_kyc_status SET kyc_submitted_at = NOW(), email_address = $1, rejected_at = NOW(), approved_at = NULL WHERE callback_id = $2 RETURNING * )\n\t\tSELECT EXISTS(\n\t\t\tSELECT * FROM updated_row\n\t\t)\n\t"
-	expectedArgs = []interface{}{in.EmailAddress, in.CallbackID}
+	query, args = in.buildUpdateKYCQuery(Case{Status: CaseStatusRejected})
+	expectedQuery = "WITH updated_row AS (UPDATE accounts_kyc_status SET kyc_submitted_at = NOW(), email_address = $1, kyc_case_id = $2, rejected_at = NOW(), approved_at = NULL WHERE callback_id = $3 RETURNING * )\n\t\tSELECT EXISTS(\n\t\t\tSELECT * FROM updated_row\n\t\t)\n\t"
+	expectedArgs = []interface{}{in.EmailAddress, sql.NullString{}, in.CallbackID}
+	require.Equal(t, expectedQuery, query)
+	require.Equal(t, expectedArgs, args)
+
+	// Test query returned if KYC pending in the provider.
+	query, args = in.buildUpdateKYCQuery(Case{ID: "case-1", Status: CaseStatusPending})
+	expectedQuery = "WITH updated_row AS (UPDATE accounts_kyc_status SET kyc_submitted_at = NOW(), email_address = $1, kyc_case_id = $2, approved_at = NULL, rejected_at = NULL WHERE callback_id = $3 RETURNING * )\n\t\tSELECT EXISTS(\n\t\t\tSELECT * FROM updated_row\n\t\t)\n\t"
+	expectedArgs = []interface{}{in.EmailAddress, sql.NullString{String: "case-1", Valid: true}, in.CallbackID}
 	require.Equal(t, expectedQuery, query)
 	require.Equal(t, expectedArgs, args)
 }
@@ -0,0 +1,59 @@
+package kycstatus
+
+import (
+	"context"
+	"strings"
+)
+
+// CaseStatus is the status of a KYC case.
+type CaseStatus string
+
+const (
+	CaseStatusPending  CaseStatus = "pending"
+	CaseStatusApproved CaseStatus = "approved"
+	CaseStatusRejected CaseStatus = "rejected"
+)
+
+// Submission is the KYC information submitted for an account.
+type Submission struct {
+	StellarAddress string
+	CallbackID     string
+	EmailAddress   string
+}
+
+// Case is the review of a Submission by a Provider.
+type Case struct {
+	// ID is the identifier of the case in the provider, empty if the provider
+	// decided immediately and does not keep track of cases.
+	ID     string
+	Status CaseStatus
+}
+
+// Provider reviews the KYC information of accounts, usually by forwarding it
+// to an external KYC vendor.
+//
+// Providers may decide on a Submission immediately, or return a pending Case
+// which is decided later. In that case the server learns about the decision
+// either from the provider notifying the kyc-provider webhook, or by polling
+// CaseStatus.
+type Provider interface {
+	Submit(ctx context.Context, submission Submission) (Case, error)
+	CaseStatus(ctx context.Context, caseID string) (CaseStatus, error)
+}
+
+// RuleProvider is a Provider deciding immediately with an arbitrary rule:
+// email addresses starting with "x" are rejected and all others are approved.
+type RuleProvider struct{}
+
+func (RuleProvider) Submit(ctx context.Context, submission Submission) (Case, error) {
+	if strings.HasPrefix(strings.ToLower(submission.EmailAddress), "x") {
+		return Case{Status: CaseStatusRejected}, nil
+	}
+	return Case{Status: CaseStatusApproved}, nil
+}
+
+// CaseStatus always returns CaseStatusPending, as RuleProvider never returns
+// pending cases.
+func (RuleProvider) CaseStatus(ctx context.Context, caseID string) (CaseStatus, error) {
+	return CaseStatusPending, nil
+}
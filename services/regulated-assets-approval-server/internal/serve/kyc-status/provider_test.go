@@ -0,0 +1,23 @@
+package kycstatus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleProvider(t *testing.T) {
+	ctx := context.Background()
+
+	// Test if email approved.
+	kycCase, err := RuleProvider{}.Submit(ctx, Submission{EmailAddress: "test@email.com"})
+	require.NoError(t, err)
+	assert.Equal(t, Case{Status: CaseStatusApproved}, kycCase)
+
+	// Test if email rejected.
+	kycCase, err = RuleProvider{}.Submit(ctx, Submission{EmailAddress: "Xtest@email.com"})
+	require.NoError(t, err)
+	assert.Equal(t, Case{Status: CaseStatusRejected}, kycCase)
+}
@@ -0,0 +1,119 @@
+package kycstatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// RESTProvider is a Provider forwarding submissions to an external KYC
+// vendor through a REST API:
+//
+//   - `POST {URL}/cases` creates a case from a JSON object with the
+//     stellar_address, callback_id, email_address and webhook_url fields.
+//   - `GET {URL}/cases/{id}` returns a case.
+//
+// Both endpoints respond with a JSON object with the id and status (pending,
+// approved or rejected) of the case. If WebhookURL is set, the vendor is
+// expected to POST the case there once it is decided.
+type RESTProvider struct {
+	URL        string
+	APIKey     string
+	WebhookURL string
+	HTTP       *http.Client
+}
+
+type restCase struct {
+	ID     string     `json:"id"`
+	Status CaseStatus `json:"status"`
+}
+
+type restSubmission struct {
+	StellarAddress string `json:"stellar_address"`
+	CallbackID     string `json:"callback_id"`
+	EmailAddress   string `json:"email_address"`
+	WebhookURL     string `json:"webhook_url,omitempty"`
+}
+
+func (p RESTProvider) Submit(ctx context.Context, submission Submission) (Case, error) {
+	body, err := json.Marshal(restSubmission{
+		StellarAddress: submission.StellarAddress,
+		CallbackID:     submission.CallbackID,
+		EmailAddress:   submission.EmailAddress,
+		WebhookURL:     p.WebhookURL,
+	})
+	if err != nil {
+		return Case{}, errors.Wrap(err, "encoding submission")
+	}
+
+	c, err := p.do(ctx, http.MethodPost, "cases", bytes.NewReader(body))
+	if err != nil {
+		return Case{}, errors.Wrap(err, "creating case")
+	}
+	if c.ID == "" {
+		return Case{}, errors.New("KYC provider returned a case without id")
+	}
+	return Case{ID: c.ID, Status: c.Status}, nil
+}
+
+func (p RESTProvider) CaseStatus(ctx context.Context, caseID string) (CaseStatus, error) {
+	c, err := p.do(ctx, http.MethodGet, path.Join("cases", url.PathEscape(caseID)), nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "getting case %s", caseID)
+	}
+	return c.Status, nil
+}
+
+func (p RESTProvider) do(ctx context.Context, method, endpoint string, body io.Reader) (restCase, error) {
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return restCase{}, errors.Wrap(err, "parsing KYC provider URL")
+	}
+	u.Path = path.Join(u.Path, endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return restCase{}, errors.Wrap(err, "building request")
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	client := p.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return restCase{}, errors.Wrap(err, "sending request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return restCase{}, errors.Errorf("KYC provider responded with status %d: %s", resp.StatusCode, respBody)
+	}
+
+	c := restCase{}
+	err = json.NewDecoder(resp.Body).Decode(&c)
+	if err != nil {
+		return restCase{}, errors.Wrap(err, "decoding response")
+	}
+	switch c.Status {
+	case CaseStatusPending, CaseStatusApproved, CaseStatusRejected:
+	default:
+		return restCase{}, errors.Errorf("KYC provider returned unknown case status %q", c.Status)
+	}
+	return c, nil
+}
@@ -0,0 +1,63 @@
+package kycstatus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTProvider(t *testing.T) {
+	var submitted restSubmission
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer api-key", r.Header.Get("Authorization"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/cases":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+			w.Write([]byte(`{"id":"case-1","status":"pending"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/cases/case-1":
+			w.Write([]byte(`{"id":"case-1","status":"approved"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/cases/case-2":
+			w.Write([]byte(`{"id":"case-2","status":"unknown"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`not found`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	provider := RESTProvider{
+		URL:        server.URL + "/v1",
+		APIKey:     "api-key",
+		WebhookURL: "https://example.com/kyc-provider/webhook",
+	}
+
+	kycCase, err := provider.Submit(ctx, Submission{
+		StellarAddress: "GA2ILZPZAQ4R5PRKZ2X2AFAYHPZIJA2LSFLHPIQTSS2AWBXOYXGQ2OIE",
+		CallbackID:     "callback-1",
+		EmailAddress:   "test@email.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Case{ID: "case-1", Status: CaseStatusPending}, kycCase)
+	assert.Equal(t, restSubmission{
+		StellarAddress: "GA2ILZPZAQ4R5PRKZ2X2AFAYHPZIJA2LSFLHPIQTSS2AWBXOYXGQ2OIE",
+		CallbackID:     "callback-1",
+		EmailAddress:   "test@email.com",
+		WebhookURL:     "https://example.com/kyc-provider/webhook",
+	}, submitted)
+
+	status, err := provider.CaseStatus(ctx, "case-1")
+	require.NoError(t, err)
+	assert.Equal(t, CaseStatusApproved, status)
+
+	_, err = provider.CaseStatus(ctx, "case-2")
+	assert.EqualError(t, err, `getting case case-2: KYC provider returned unknown case status "unknown"`)
+
+	_, err = provider.CaseStatus(ctx, "case-3")
+	assert.EqualError(t, err, "getting case case-3: KYC provider responded with status 404: not found")
+}
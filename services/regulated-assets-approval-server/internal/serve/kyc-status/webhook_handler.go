@@ -0,0 +1,123 @@
+package kycstatus
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/exp/compliance/cases"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/support/config"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http/httpdecode"
+	"github.com/stellar/go/support/log"
+)
+
+// WebhookHandler receives the decisions of a Provider on pending cases.
+// Requests must be authenticated with the `Authorization: Bearer {Secret}`
+// header. Secret is read on each request, so that it can be rotated.
+type WebhookHandler struct {
+	DB     *sqlx.DB
+	Secret *config.SecretValue
+	// Cases, if set, records the decisions in compliance cases.
+	Cases *cases.Store
+}
+
+type webhookRequest struct {
+	CaseID string     `json:"id"`
+	Status CaseStatus `json:"status"`
+}
+
+func (h WebhookHandler) validate() error {
+	if h.DB == nil {
+		return errors.New("database cannot be nil")
+	}
+	if h.Secret == nil || h.Secret.Get() == "" {
+		return errors.New("secret cannot be empty")
+	}
+	return nil
+}
+
+func (h WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	err := h.validate()
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "validating kyc-provider WebhookHandler"))
+		httperror.InternalServer.Render(w)
+		return
+	}
+
+	auth := []byte(r.Header.Get("Authorization"))
+	if subtle.ConstantTimeCompare(auth, []byte("Bearer "+h.Secret.Get())) != 1 {
+		httperror.NewHTTPError(http.StatusUnauthorized, "Unauthorized.").Render(w)
+		return
+	}
+
+	in := webhookRequest{}
+	err = httpdecode.Decode(r, &in)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "decoding kyc-provider webhook Request"))
+		httperror.BadRequest.Render(w)
+		return
+	}
+
+	err = h.handle(ctx, in)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "handling kyc-provider webhook Request"))
+		httpErr, ok := err.(*httperror.Error)
+		if !ok {
+			httpErr = httperror.InternalServer
+		}
+		httpErr.Render(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h WebhookHandler) handle(ctx context.Context, in webhookRequest) error {
+	if in.CaseID == "" {
+		return httperror.NewHTTPError(http.StatusBadRequest, "Missing id.")
+	}
+	switch in.Status {
+	case CaseStatusPending, CaseStatusApproved, CaseStatusRejected:
+	default:
+		return httperror.NewHTTPError(http.StatusBadRequest, "Invalid status.")
+	}
+
+	stellarAddress, err := updateCaseStatus(ctx, h.DB, in.CaseID, in.Status)
+	if err == sql.ErrNoRows {
+		return httperror.NewHTTPError(http.StatusNotFound, "Not found.")
+	}
+	if err != nil {
+		return errors.Wrap(err, "updating case status")
+	}
+	err = recordComplianceCase(ctx, h.Cases, stellarAddress, in.Status, providerReviewer)
+	if err != nil {
+		return errors.Wrap(err, "recording compliance case")
+	}
+	return nil
+}
+
+// updateCaseStatus approves or rejects the account whose KYC information is
+// reviewed in the given case, and returns its address. It returns
+// sql.ErrNoRows if no account has that case.
+func updateCaseStatus(ctx context.Context, db *sqlx.DB, caseID string, status CaseStatus) (string, error) {
+	query := `
+		UPDATE accounts_kyc_status
+		SET ` + caseStatusAssignments(status) + `
+		WHERE kyc_case_id = $1
+		RETURNING stellar_address
+	`
+	var stellarAddress string
+	err := db.QueryRowContext(ctx, query, caseID).Scan(&stellarAddress)
+	if err == sql.ErrNoRows {
+		return "", err
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "querying the database")
+	}
+	return stellarAddress, nil
+}
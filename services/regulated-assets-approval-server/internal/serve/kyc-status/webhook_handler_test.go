@@ -0,0 +1,82 @@
+package kycstatus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
+	"github.com/stellar/go/support/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookHandlerValidate(t *testing.T) {
+	h := WebhookHandler{}
+	err := h.validate()
+	require.EqualError(t, err, "database cannot be nil")
+
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	h = WebhookHandler{DB: conn}
+	err = h.validate()
+	require.EqualError(t, err, "secret cannot be empty")
+
+	h = WebhookHandler{DB: conn, Secret: config.NewSecretValue("secret")}
+	err = h.validate()
+	require.NoError(t, err)
+}
+
+func TestWebhookHandlerUnauthorized(t *testing.T) {
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	h := WebhookHandler{DB: conn, Secret: config.NewSecretValue("secret")}
+
+	r := httptest.NewRequest(http.MethodPost, "/kyc-provider/webhook", strings.NewReader(`{"id":"case-1","status":"approved"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Authorization", "Bearer wrong")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"Unauthorized."}`, w.Body.String())
+}
+
+func TestWebhookHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	h := WebhookHandler{DB: conn, Secret: config.NewSecretValue("secret")}
+
+	accountKP := keypair.MustRandom()
+	const q = `
+		INSERT INTO accounts_kyc_status (stellar_address, callback_id, email_address, kyc_submitted_at, kyc_case_id)
+		VALUES ($1, 'callback-1', 'test@email.com', NOW(), 'case-1')
+	`
+	_, err := conn.ExecContext(ctx, q, accountKP.Address())
+	require.NoError(t, err)
+
+	err = h.handle(ctx, webhookRequest{CaseID: "case-1", Status: "unknown"})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusBadRequest, "Invalid status."), err)
+
+	err = h.handle(ctx, webhookRequest{CaseID: "case-2", Status: CaseStatusApproved})
+	assert.Equal(t, httperror.NewHTTPError(http.StatusNotFound, "Not found."), err)
+
+	err = h.handle(ctx, webhookRequest{CaseID: "case-1", Status: CaseStatusRejected})
+	require.NoError(t, err)
+
+	var approved, rejected bool
+	err = conn.QueryRowContext(ctx, "SELECT approved_at IS NOT NULL, rejected_at IS NOT NULL FROM accounts_kyc_status WHERE stellar_address = $1", accountKP.Address()).Scan(&approved, &rejected)
+	require.NoError(t, err)
+	assert.False(t, approved)
+	assert.True(t, rejected)
+}
@@ -0,0 +1,136 @@
+package serve
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/support/log"
+)
+
+// metrics holds the Prometheus collectors of the approval server. A nil
+// *metrics is valid and records nothing.
+type metrics struct {
+	registry *prometheus.Registry
+
+	txApproveCount      *prometheus.CounterVec
+	kycCallbackDuration *prometheus.HistogramVec
+	horizonErrorCount   *prometheus.CounterVec
+	dbQueryDuration     *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		txApproveCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "approval_server", Subsystem: "tx_approve", Name: "requests_total",
+			Help: "Number of tx-approve requests, partitioned by the SEP-8 status of the response or error if the request failed.",
+		}, []string{"status"}),
+		kycCallbackDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "approval_server", Subsystem: "kyc_status", Name: "callback_duration_seconds",
+			Help: "Duration of the requests to the kyc-status callback, partitioned by response status code.",
+		}, []string{"status_code"}),
+		horizonErrorCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "approval_server", Subsystem: "horizon", Name: "errors_total",
+			Help: "Number of failed requests to Horizon, partitioned by request.",
+		}, []string{"request"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "approval_server", Subsystem: "db", Name: "query_duration_seconds",
+			Help: "Duration of database queries, partitioned by kind of statement and table.",
+		}, []string{"query"}),
+	}
+
+	collectors := []prometheus.Collector{
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		prometheus.NewGoCollector(),
+		m.txApproveCount,
+		m.kycCallbackDuration,
+		m.horizonErrorCount,
+		m.dbQueryDuration,
+	}
+	for _, c := range collectors {
+		err := m.registry.Register(c)
+		if err != nil {
+			log.Warn("Error registering metric: ", err)
+		}
+	}
+	return m
+}
+
+func (m *metrics) observeTxApprove(resp *txApprovalResponse, err error) {
+	if m == nil {
+		return
+	}
+	status := "error"
+	if err == nil && resp != nil {
+		status = string(resp.Status)
+	}
+	m.txApproveCount.WithLabelValues(status).Inc()
+}
+
+func (m *metrics) incHorizonError(request string) {
+	if m == nil {
+		return
+	}
+	m.horizonErrorCount.WithLabelValues(request).Inc()
+}
+
+// queryTable matches the first table a statement reads or writes, along with
+// the kind of statement.
+var queryTable = regexp.MustCompile(`(?i)\b(INSERT\s+INTO|UPDATE|DELETE\s+FROM|FROM)\s+([a-z_][a-z0-9_]*)`)
+
+// dbQueryLabel returns the label of a query in the db query duration metric,
+// the kind of statement and the first table it touches, e.g.
+// insert_accounts_kyc_status, so that the number of labels is bounded by the
+// schema rather than by the queries.
+func dbQueryLabel(query string) string {
+	match := queryTable.FindStringSubmatch(query)
+	if match == nil {
+		return "other"
+	}
+	kind := strings.ToLower(strings.Fields(match[1])[0])
+	if kind == "from" {
+		kind = "select"
+	}
+	return kind + "_" + strings.ToLower(match[2])
+}
+
+// observeDBQuery records the duration of a query. It is the QueryObserver of
+// the database of the server, so that all queries are timed.
+func (m *metrics) observeDBQuery(query string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.dbQueryDuration.WithLabelValues(dbQueryLabel(query)).Observe(duration.Seconds())
+}
+
+// kycCallbackMiddleware records the duration of the requests handled by next
+// as kyc-status callback latencies.
+func (m *metrics) kycCallbackMiddleware(next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		m.kycCallbackDuration.
+			WithLabelValues(strconv.Itoa(sw.status)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// statusResponseWriter remembers the status code written to the wrapped
+// http.ResponseWriter.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
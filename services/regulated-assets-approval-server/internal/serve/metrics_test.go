@@ -0,0 +1,109 @@
+package serve
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getMetricValue(metric prometheus.Metric) *dto.Metric {
+	value := &dto.Metric{}
+	err := metric.Write(value)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+func TestMetrics_nilIsNoop(t *testing.T) {
+	var m *metrics
+	m.observeTxApprove(NewRejectedTxApprovalResponse("rejected"), nil)
+	m.incHorizonError("account_detail")
+	m.observeDBQuery("SELECT 1", time.Second)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	assert.NotNil(t, m.kycCallbackMiddleware(next))
+}
+
+func TestMetrics_observeTxApprove(t *testing.T) {
+	m := newMetrics()
+
+	m.observeTxApprove(NewRejectedTxApprovalResponse("rejected"), nil)
+	m.observeTxApprove(NewRejectedTxApprovalResponse("rejected"), nil)
+	m.observeTxApprove(NewRevisedTxApprovalResponse("AAAA", "revised"), nil)
+	m.observeTxApprove(nil, errors.New("horizon is down"))
+
+	assert.Equal(t, float64(2), getMetricValue(m.txApproveCount.WithLabelValues("rejected")).GetCounter().GetValue())
+	assert.Equal(t, float64(1), getMetricValue(m.txApproveCount.WithLabelValues("revised")).GetCounter().GetValue())
+	assert.Equal(t, float64(1), getMetricValue(m.txApproveCount.WithLabelValues("error")).GetCounter().GetValue())
+	assert.Equal(t, float64(0), getMetricValue(m.txApproveCount.WithLabelValues("success")).GetCounter().GetValue())
+}
+
+func TestMetrics_kycCallbackMiddleware(t *testing.T) {
+	m := newMetrics()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	r := httptest.NewRequest("POST", "/kyc-status/1234", nil)
+	w := httptest.NewRecorder()
+	m.kycCallbackMiddleware(next).ServeHTTP(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	histogram := getMetricValue(m.kycCallbackDuration.WithLabelValues("400").(prometheus.Metric)).GetHistogram()
+	assert.Equal(t, uint64(1), histogram.GetSampleCount())
+}
+
+func TestMetrics_observeDBQuery(t *testing.T) {
+	m := newMetrics()
+
+	m.observeDBQuery(`
+		WITH new_row AS (
+			INSERT INTO accounts_kyc_status (stellar_address, callback_id)
+			VALUES ($1, $2)
+			ON CONFLICT(stellar_address) DO NOTHING
+			RETURNING *
+		)
+		SELECT callback_id FROM new_row
+	`, time.Millisecond)
+	m.observeDBQuery(`SELECT COUNT(*) FROM revised_transactions WHERE source_account = $1`, time.Millisecond)
+	m.observeDBQuery(`select count(*) from Revised_Transactions`, time.Millisecond)
+	m.observeDBQuery(`UPDATE clawback_requests SET status = $2 WHERE id = $1`, time.Millisecond)
+	m.observeDBQuery(`DELETE FROM accounts_kyc_status WHERE stellar_address = $1`, time.Millisecond)
+	m.observeDBQuery(`SELECT 1`, time.Millisecond)
+
+	for label, count := range map[string]uint64{
+		"insert_accounts_kyc_status":  1,
+		"select_revised_transactions": 2,
+		"update_clawback_requests":    1,
+		"delete_accounts_kyc_status":  1,
+		"other":                       1,
+	} {
+		histogram := getMetricValue(m.dbQueryDuration.WithLabelValues(label).(prometheus.Metric)).GetHistogram()
+		assert.Equal(t, count, histogram.GetSampleCount(), label)
+	}
+}
+
+func TestMetrics_handler(t *testing.T) {
+	m := newMetrics()
+	m.incHorizonError("submit_transaction")
+
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `approval_server_horizon_errors_total{request="submit_transaction"} 1`)
+}
@@ -1,9 +1,12 @@
 package serve
 
 import (
+	"crypto/subtle"
 	"net/http"
 
 	"github.com/rs/cors"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/adminauth"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
 )
 
 func corsHandler(next http.Handler) http.Handler {
@@ -14,3 +17,30 @@ func corsHandler(next http.Handler) http.Handler {
 	})
 	return cors.Handler(next)
 }
+
+// adminAuthHandler rejects the requests which do not have the
+// `Authorization: Bearer {apiKey}` header with the API key of one of the
+// admins, keyed by their names. The name of the admin is added to the context
+// of the requests, see adminauth.FromContext.
+func adminAuthHandler(apiKeys map[string]string) func(http.Handler) http.Handler {
+	want := make(map[string][]byte, len(apiKeys))
+	for admin, apiKey := range apiKeys {
+		want[admin] = []byte("Bearer " + apiKey)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := []byte(r.Header.Get("Authorization"))
+			authenticated := ""
+			for admin, key := range want {
+				if subtle.ConstantTimeCompare(auth, key) == 1 {
+					authenticated = admin
+				}
+			}
+			if authenticated == "" {
+				httperror.NewHTTPError(http.StatusUnauthorized, "Unauthorized.").Render(w)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(adminauth.NewContext(r.Context(), authenticated)))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package serve
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/adminauth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminAuthHandler(t *testing.T) {
+	handler := adminAuthHandler(map[string]string{
+		"alice": "alice-key",
+		"bob":   "bob-key",
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(adminauth.FromContext(r.Context())))
+	}))
+
+	for _, tc := range []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantAdmin     string
+	}{
+		{"missing", "", http.StatusUnauthorized, ""},
+		{"wrong key", "Bearer other-key", http.StatusUnauthorized, ""},
+		{"alice", "Bearer alice-key", http.StatusOK, "alice"},
+		{"bob", "Bearer bob-key", http.StatusOK, "bob"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/admin/kyc-status", nil)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			assert.Equal(t, tc.wantStatus, w.Code)
+			if tc.wantAdmin != "" {
+				assert.Equal(t, tc.wantAdmin, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package serve
+
+import (
+	"github.com/stellar/go/txnbuild"
+)
+
+// paymentOperation is an operation moving a regulated asset, either a
+// payment or a path payment.
+type paymentOperation struct {
+	operation   txnbuild.Operation
+	source      string
+	destination string
+	asset       txnbuild.Asset
+	// amount is the amount of the regulated asset moved by the operation. For
+	// path payments where it is not fixed, it is the maximum sent or the
+	// minimum received.
+	amount string
+	// trustors are the accounts which hold the regulated asset during the
+	// operation, in the order they need to be authorized.
+	trustors []string
+}
+
+// newPaymentOperation returns the paymentOperation of op if it is a payment
+// or path payment of one of the handler's regulated assets. txSource is used
+// as the source of operations without an explicit one.
+func (h txApproveHandler) newPaymentOperation(op txnbuild.Operation, txSource string) (*paymentOperation, bool) {
+	source := op.GetSourceAccount()
+	if source == "" {
+		source = txSource
+	}
+
+	switch op := op.(type) {
+	case *txnbuild.Payment:
+		if !h.isRegulatedAsset(op.Asset) {
+			return nil, false
+		}
+		return &paymentOperation{
+			operation:   op,
+			source:      source,
+			destination: op.Destination,
+			asset:       op.Asset,
+			amount:      op.Amount,
+			trustors:    []string{source, op.Destination},
+		}, true
+	case *txnbuild.PathPaymentStrictSend:
+		return h.newPathPaymentOperation(op, source, op.Destination, op.SendAsset, op.SendAmount, op.DestAsset, op.DestMin, op.Path)
+	case *txnbuild.PathPaymentStrictReceive:
+		return h.newPathPaymentOperation(op, source, op.Destination, op.SendAsset, op.SendMax, op.DestAsset, op.DestAmount, op.Path)
+	default:
+		return nil, false
+	}
+}
+
+// newPathPaymentOperation returns the paymentOperation of a path payment
+// sending or receiving (but not both) a regulated asset. Regulated assets
+// cannot be used in the path, as the offers crossed would need to be
+// authorized too.
+func (h txApproveHandler) newPathPaymentOperation(
+	op txnbuild.Operation,
+	source, destination string,
+	sendAsset txnbuild.Asset, sendAmount string,
+	destAsset txnbuild.Asset, destAmount string,
+	path []txnbuild.Asset,
+) (*paymentOperation, bool) {
+	for _, asset := range path {
+		if h.isRegulatedAsset(asset) {
+			return nil, false
+		}
+	}
+
+	sendRegulated := h.isRegulatedAsset(sendAsset)
+	destRegulated := h.isRegulatedAsset(destAsset)
+	switch {
+	case sendRegulated && destRegulated:
+		if sendAsset != destAsset {
+			return nil, false
+		}
+		return &paymentOperation{
+			operation:   op,
+			source:      source,
+			destination: destination,
+			asset:       sendAsset,
+			amount:      sendAmount,
+			trustors:    []string{source, destination},
+		}, true
+	case sendRegulated:
+		return &paymentOperation{
+			operation:   op,
+			source:      source,
+			destination: destination,
+			asset:       sendAsset,
+			amount:      sendAmount,
+			trustors:    []string{source},
+		}, true
+	case destRegulated:
+		return &paymentOperation{
+			operation:   op,
+			source:      source,
+			destination: destination,
+			asset:       destAsset,
+			amount:      destAmount,
+			trustors:    []string{destination},
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// isRegulatedAsset returns true if asset is one of the handler's regulated
+// assets.
+func (h txApproveHandler) isRegulatedAsset(asset txnbuild.Asset) bool {
+	if asset == nil || asset.IsNative() {
+		return false
+	}
+	_, ok := h.findAsset(asset.GetCode(), asset.GetIssuer())
+	return ok
+}
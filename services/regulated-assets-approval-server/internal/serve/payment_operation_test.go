@@ -0,0 +1,170 @@
+package serve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxApproveHandler_newPaymentOperation(t *testing.T) {
+	issuerKP := keypair.MustRandom()
+	sourceKP := keypair.MustRandom()
+	destinationKP := keypair.MustRandom()
+	goat := txnbuild.CreditAsset{Code: "GOAT", Issuer: issuerKP.Address()}
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: keypair.MustRandom().Address()}
+	h := txApproveHandler{issuerKP: issuerKP, assetCode: "GOAT"}
+
+	// payment
+	payment := &txnbuild.Payment{Destination: destinationKP.Address(), Amount: "10", Asset: goat}
+	op, ok := h.newPaymentOperation(payment, sourceKP.Address())
+	require.True(t, ok)
+	assert.Equal(t, &paymentOperation{
+		operation:   payment,
+		source:      sourceKP.Address(),
+		destination: destinationKP.Address(),
+		asset:       goat,
+		amount:      "10",
+		trustors:    []string{sourceKP.Address(), destinationKP.Address()},
+	}, op)
+
+	_, ok = h.newPaymentOperation(&txnbuild.Payment{Destination: destinationKP.Address(), Amount: "10", Asset: usd}, sourceKP.Address())
+	assert.False(t, ok)
+
+	// path payment sending the regulated asset
+	strictSend := &txnbuild.PathPaymentStrictSend{
+		SendAsset:   goat,
+		SendAmount:  "10",
+		Destination: destinationKP.Address(),
+		DestAsset:   txnbuild.NativeAsset{},
+		DestMin:     "1",
+	}
+	op, ok = h.newPaymentOperation(strictSend, sourceKP.Address())
+	require.True(t, ok)
+	assert.Equal(t, goat, op.asset)
+	assert.Equal(t, "10", op.amount)
+	assert.Equal(t, []string{sourceKP.Address()}, op.trustors)
+
+	// path payment receiving the regulated asset
+	strictReceive := &txnbuild.PathPaymentStrictReceive{
+		SendAsset:     usd,
+		SendMax:       "20",
+		Destination:   destinationKP.Address(),
+		DestAsset:     goat,
+		DestAmount:    "5",
+		SourceAccount: sourceKP.Address(),
+	}
+	op, ok = h.newPaymentOperation(strictReceive, keypair.MustRandom().Address())
+	require.True(t, ok)
+	assert.Equal(t, sourceKP.Address(), op.source)
+	assert.Equal(t, goat, op.asset)
+	assert.Equal(t, "5", op.amount)
+	assert.Equal(t, []string{destinationKP.Address()}, op.trustors)
+
+	// path payment sending and receiving the regulated asset
+	strictReceive = &txnbuild.PathPaymentStrictReceive{
+		SendAsset:   goat,
+		SendMax:     "20",
+		Destination: destinationKP.Address(),
+		DestAsset:   goat,
+		DestAmount:  "5",
+		Path:        []txnbuild.Asset{usd},
+	}
+	op, ok = h.newPaymentOperation(strictReceive, sourceKP.Address())
+	require.True(t, ok)
+	assert.Equal(t, "20", op.amount)
+	assert.Equal(t, []string{sourceKP.Address(), destinationKP.Address()}, op.trustors)
+
+	// regulated asset in the path
+	strictSend = &txnbuild.PathPaymentStrictSend{
+		SendAsset:   usd,
+		SendAmount:  "10",
+		Destination: destinationKP.Address(),
+		DestAsset:   txnbuild.NativeAsset{},
+		DestMin:     "1",
+		Path:        []txnbuild.Asset{goat},
+	}
+	_, ok = h.newPaymentOperation(strictSend, sourceKP.Address())
+	assert.False(t, ok)
+
+	// no regulated asset
+	strictSend.Path = nil
+	_, ok = h.newPaymentOperation(strictSend, sourceKP.Address())
+	assert.False(t, ok)
+}
+
+func TestTxApproveHandler_pathPayment(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	issuerKP := keypair.MustRandom()
+	senderKP := keypair.MustRandom()
+	receiverKP := keypair.MustRandom()
+	goat := txnbuild.CreditAsset{Code: "GOAT", Issuer: issuerKP.Address()}
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: senderKP.Address()}).
+		Return(horizon.Account{AccountID: senderKP.Address(), Sequence: "5"}, nil)
+
+	kycThreshold, err := amount.ParseInt64("500")
+	require.NoError(t, err)
+	handler := txApproveHandler{
+		issuerKP:          issuerKP,
+		assetCode:         goat.GetCode(),
+		horizonClient:     &horizonMock,
+		networkPassphrase: network.TestNetworkPassphrase,
+		db:                conn,
+		kycThreshold:      kycThreshold,
+		baseURL:           "https://sep8-server.test",
+	}
+
+	pathPayment := &txnbuild.PathPaymentStrictSend{
+		SendAsset:   goat,
+		SendAmount:  "10",
+		Destination: receiverKP.Address(),
+		DestAsset:   txnbuild.NativeAsset{},
+		DestMin:     "1",
+	}
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: senderKP.Address(), Sequence: 5},
+		IncrementSequenceNum: true,
+		Operations:           []txnbuild.Operation{pathPayment},
+		BaseFee:              txnbuild.MinBaseFee,
+		Timebounds:           txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	txe, err := tx.Base64()
+	require.NoError(t, err)
+
+	resp, err := handler.txApprove(ctx, txApproveRequest{Tx: txe})
+	require.NoError(t, err)
+	assert.Equal(t, sep8StatusRevised, resp.Status)
+
+	parsed, err := txnbuild.TransactionFromXDR(resp.Tx)
+	require.NoError(t, err)
+	revisedTx, ok := parsed.Transaction()
+	require.True(t, ok)
+	require.Len(t, revisedTx.Operations(), 3)
+
+	authorize, ok := revisedTx.Operations()[0].(*txnbuild.SetTrustLineFlags)
+	require.True(t, ok)
+	assert.Equal(t, senderKP.Address(), authorize.Trustor)
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, authorize.SetFlags)
+	_, ok = revisedTx.Operations()[1].(*txnbuild.PathPaymentStrictSend)
+	assert.True(t, ok)
+	deauthorize, ok := revisedTx.Operations()[2].(*txnbuild.SetTrustLineFlags)
+	require.True(t, ok)
+	assert.Equal(t, senderKP.Address(), deauthorize.Trustor)
+	assert.Equal(t, []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}, deauthorize.ClearFlags)
+}
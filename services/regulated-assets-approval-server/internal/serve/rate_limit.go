@@ -0,0 +1,104 @@
+package serve
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/throttled"
+)
+
+// rateLimitLRUSize is the number of keys tracked by the in-memory rate
+// limiter.
+const rateLimitLRUSize = 50000
+
+// txApproveRequestMaxSize is the maximum size of the body of tx-approve
+// requests, which is read by the rate limiter before the request is handled.
+const txApproveRequestMaxSize = 100 * 1024
+
+// newMemoryRateLimiter returns a rate limiter that keeps its state in memory,
+// allowing perMinute requests per key with bursts of up to burst requests.
+func newMemoryRateLimiter(perMinute, burst int) (throttled.RateLimiter, error) {
+	limiter, err := throttled.NewGCRARateLimiter(rateLimitLRUSize, throttled.RateQuota{
+		MaxRate:  throttled.PerMin(perMinute),
+		MaxBurst: burst,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating rate limiter")
+	}
+	return limiter, nil
+}
+
+// rateLimitHandler rejects tx-approve requests once the client IP exceeds the
+// rate allowed by the limiter. Rejected requests receive a SEP-8 rejected
+// response with the 429 status code. Requests are not limited per source
+// account of the submitted transaction: it is not authenticated, so anyone
+// could exhaust the limit of an account by submitting its transactions.
+func rateLimitHandler(limiter throttled.RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			r.Body = http.MaxBytesReader(w, r.Body, txApproveRequestMaxSize)
+			err := bufferBody(r)
+			if err != nil {
+				log.Ctx(ctx).Info(errors.Wrap(err, "reading tx-approve request"))
+				resp := NewRejectedTxApprovalResponse("The request body is too large or could not be read.")
+				resp.StatusCode = http.StatusRequestEntityTooLarge
+				resp.Render(w)
+				return
+			}
+
+			key := "ip:" + clientIP(r)
+			limited, result, err := limiter.RateLimit(key, 1)
+			if err != nil {
+				// Fail open, an unavailable rate limiter backend must not take
+				// the approval server down with it.
+				log.Ctx(ctx).Error(errors.Wrap(err, "rate limiting request"))
+			} else if limited {
+				log.Ctx(ctx).Infof("Rate limit exceeded for %s.", key)
+				if result.RetryAfter >= 0 {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+				}
+				resp := NewRejectedTxApprovalResponse("Rate limit exceeded, please try again later.")
+				resp.StatusCode = http.StatusTooManyRequests
+				resp.Render(w)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the IP of the client without the port. The remote address
+// is only replaced by the X-Forwarded-For header of requests coming from
+// trusted proxies, see Options.TrustedProxies, so that clients cannot evade
+// the rate limit by spoofing the header.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// bufferBody reads the body of the request, so that requests exceeding the
+// size limit of the http.MaxBytesReader wrapping it are rejected before being
+// handled. The request body is left intact for the next handler.
+func bufferBody(r *http.Request) error {
+	if r.Body == nil {
+		return nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return nil
+}
@@ -0,0 +1,117 @@
+package serve
+
+import (
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/throttled"
+)
+
+const (
+	// redisRateLimitKeyPrefix namespaces the keys of the rate limiter in the
+	// Redis database.
+	redisRateLimitKeyPrefix = "regulated-assets-approval-server:rate-limit:"
+	// redisTimeout bounds the duration of the connections to Redis and of
+	// the commands sent to it.
+	redisTimeout = time.Second
+)
+
+// redisGCRAScript implements the same generic cell rate algorithm as
+// throttled.GCRARateLimiter, atomically and with the clock of the Redis
+// server so that all the instances of the approval server share the same
+// state. Durations are in milliseconds.
+//
+// KEYS[1] is the key being limited, ARGV[1] the emission interval, ARGV[2]
+// the delay variation tolerance and ARGV[3] the quantity. It returns whether
+// the request is limited and the theoretical arrival time relative to now.
+var redisGCRAScript = redis.NewScript(1, `
+redis.replicate_commands()
+local emission = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+local increment = emission * tonumber(ARGV[3])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local newTat = tat + increment
+if newTat - tolerance > now then
+	return {1, tat - now}
+end
+redis.call("SET", KEYS[1], newTat, "PX", newTat - now)
+return {0, newTat - now}
+`)
+
+// redisRateLimiter is a throttled.RateLimiter keeping its state in Redis.
+type redisRateLimiter struct {
+	pool      *redis.Pool
+	emission  time.Duration
+	tolerance time.Duration
+	limit     int
+}
+
+// newRedisRateLimiter returns a rate limiter that keeps its state in the
+// Redis database at redisURL, allowing perMinute requests per key with bursts
+// of up to burst requests.
+func newRedisRateLimiter(redisURL string, perMinute, burst int) (throttled.RateLimiter, error) {
+	if perMinute <= 0 {
+		return nil, errors.New("creating rate limiter: the rate must be greater than zero")
+	}
+	if burst < 0 {
+		return nil, errors.New("creating rate limiter: the burst cannot be negative")
+	}
+	pool := &redis.Pool{
+		MaxIdle:     10,
+		IdleTimeout: time.Minute,
+		Dial: func() (redis.Conn, error) {
+			return redis.DialURL(redisURL,
+				redis.DialConnectTimeout(redisTimeout),
+				redis.DialReadTimeout(redisTimeout),
+				redis.DialWriteTimeout(redisTimeout),
+			)
+		},
+	}
+	emission := time.Minute / time.Duration(perMinute)
+	return &redisRateLimiter{
+		pool:      pool,
+		emission:  emission,
+		tolerance: emission * time.Duration(burst+1),
+		limit:     burst + 1,
+	}, nil
+}
+
+func (l *redisRateLimiter) RateLimit(key string, quantity int) (bool, throttled.RateLimitResult, error) {
+	result := throttled.RateLimitResult{Limit: l.limit, Remaining: 0, ResetAfter: -1, RetryAfter: -1}
+
+	conn := l.pool.Get()
+	defer conn.Close()
+	reply, err := redis.Int64s(redisGCRAScript.Do(conn,
+		redisRateLimitKeyPrefix+key,
+		l.emission.Milliseconds(),
+		l.tolerance.Milliseconds(),
+		quantity,
+	))
+	if err != nil {
+		return false, result, errors.Wrap(err, "running rate limit script")
+	}
+	if len(reply) != 2 {
+		return false, result, errors.Errorf("rate limit script returned %d values, expected 2", len(reply))
+	}
+
+	limited := reply[0] == 1
+	ttl := time.Duration(reply[1]) * time.Millisecond
+	result.ResetAfter = ttl
+	if limited {
+		increment := l.emission * time.Duration(quantity)
+		if increment <= l.tolerance {
+			result.RetryAfter = ttl + increment - l.tolerance
+		}
+		return true, result, nil
+	}
+	if next := l.tolerance - ttl; next > -l.emission {
+		result.Remaining = int(next / l.emission)
+	}
+	return false, result, nil
+}
@@ -0,0 +1,52 @@
+package serve
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisRateLimiter(t *testing.T) {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		t.Skip("REDIS_URL is not set")
+	}
+
+	limiter, err := newRedisRateLimiter(redisURL, 1, 1)
+	require.NoError(t, err)
+
+	key := "account:" + keypair.MustRandom().Address()
+
+	// The burst allows two requests.
+	limited, result, err := limiter.RateLimit(key, 1)
+	require.NoError(t, err)
+	assert.False(t, limited)
+	assert.Equal(t, 2, result.Limit)
+	assert.Equal(t, 1, result.Remaining)
+
+	limited, result, err = limiter.RateLimit(key, 1)
+	require.NoError(t, err)
+	assert.False(t, limited)
+	assert.Equal(t, 0, result.Remaining)
+
+	limited, result, err = limiter.RateLimit(key, 1)
+	require.NoError(t, err)
+	assert.True(t, limited)
+	assert.True(t, result.RetryAfter > 0)
+
+	// Other keys are not limited.
+	limited, _, err = limiter.RateLimit("account:"+keypair.MustRandom().Address(), 1)
+	require.NoError(t, err)
+	assert.False(t, limited)
+}
+
+func TestRedisRateLimiter_unavailable(t *testing.T) {
+	limiter, err := newRedisRateLimiter("redis://127.0.0.1:1/0", 1, 1)
+	require.NoError(t, err)
+
+	_, _, err = limiter.RateLimit("ip:1.1.1.1", 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferBody(t *testing.T) {
+	body := `{"tx":"AAAA"}`
+	r := httptest.NewRequest("POST", "/tx-approve", strings.NewReader(body))
+	require.NoError(t, bufferBody(r))
+
+	// The body can still be read by the next handler.
+	remaining, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(remaining))
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	limiter, err := newMemoryRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := rateLimitHandler(limiter)(next)
+
+	serve := func(remoteAddr, txe string) *http.Response {
+		body, err := json.Marshal(map[string]string{"tx": txe})
+		require.NoError(t, err)
+		r := httptest.NewRequest("POST", "/tx-approve", bytes.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	// The burst allows two requests from the same IP.
+	require.Equal(t, http.StatusOK, serve("1.1.1.1:1234", "txA").StatusCode)
+	require.Equal(t, http.StatusOK, serve("1.1.1.1:5678", "txA").StatusCode)
+
+	resp := serve("1.1.1.1:1234", "txB")
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"rejected","error":"Rate limit exceeded, please try again later."}`, string(respBody))
+
+	// Other IPs are not limited, even when submitting the same transaction,
+	// as its source account is not authenticated.
+	resp = serve("2.2.2.2:1234", "txA")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestRateLimitHandler_bodyTooLarge(t *testing.T) {
+	limiter, err := newMemoryRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request should not reach the next handler")
+	})
+	h := rateLimitHandler(limiter)(next)
+
+	body := `{"tx":"` + strings.Repeat("A", txApproveRequestMaxSize) + `"}`
+	r := httptest.NewRequest("POST", "/tx-approve", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"rejected","error":"The request body is too large or could not be read."}`, string(respBody))
+}
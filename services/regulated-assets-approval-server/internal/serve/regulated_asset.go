@@ -0,0 +1,122 @@
+package serve
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/support/errors"
+)
+
+// regulatedAsset is an asset whose payments are approved by this server.
+type regulatedAsset struct {
+	code string
+	// issuerKP signs the revised transactions. It is the master key of the
+	// issuer account unless issuerAddress is set.
+	issuerKP *keypair.Full
+	// issuerAddress is the issuer account, if issuerKP is one of its other
+	// signers.
+	issuerAddress string
+	kycThreshold  int64
+}
+
+// issuer returns the address of the issuer account of the asset.
+func (a regulatedAsset) issuer() string {
+	if a.issuerAddress != "" {
+		return a.issuerAddress
+	}
+	return a.issuerKP.Address()
+}
+
+// readIssuerSecrets reads the signing keys of the issuers of regulated
+// assets from the file at `path`, one secret key per line. Empty lines and
+// lines starting with # are ignored. An empty path reads no keys.
+func readIssuerSecrets(path string) ([]*keypair.Full, error) {
+	if path == "" {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading issuer secrets file")
+	}
+
+	var kps []*keypair.Full
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kp, err := keypair.ParseFull(line)
+		if err != nil {
+			// The error is not wrapped, it could contain the secret.
+			return nil, errors.Errorf("line %d of the issuer secrets file is not a valid Stellar secret key", i+1)
+		}
+		kps = append(kps, kp)
+	}
+	return kps, nil
+}
+
+// parseRegulatedAssets parses a comma separated list of assets in the
+// CODE:ISSUER_ADDRESS:KYC_THRESHOLD[:SIGNER_ADDRESS] format. The revised
+// transactions of an asset are signed with the key of SIGNER_ADDRESS, one of
+// the signers of the issuer account, or with the master key of the issuer if
+// it is omitted. The signing keys are found in `issuerKPs`.
+func parseRegulatedAssets(s string, issuerKPs []*keypair.Full) ([]regulatedAsset, error) {
+	var assets []regulatedAsset
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 && len(parts) != 4 {
+			return nil, errors.Errorf("regulated asset %q must have the CODE:ISSUER_ADDRESS:KYC_THRESHOLD[:SIGNER_ADDRESS] format", entry)
+		}
+		if parts[0] == "" {
+			return nil, errors.New("regulated asset code cannot be empty")
+		}
+		if _, err := keypair.ParseAddress(parts[1]); err != nil {
+			return nil, errors.Errorf("issuer of regulated asset %s is not a valid Stellar address", parts[0])
+		}
+		signerAddress := parts[1]
+		issuerAddress := ""
+		if len(parts) == 4 {
+			if _, err := keypair.ParseAddress(parts[3]); err != nil {
+				return nil, errors.Errorf("signer of regulated asset %s is not a valid Stellar address", parts[0])
+			}
+			signerAddress = parts[3]
+			if signerAddress != parts[1] {
+				issuerAddress = parts[1]
+			}
+		}
+		var issuerKP *keypair.Full
+		for _, kp := range issuerKPs {
+			if kp.Address() == signerAddress {
+				issuerKP = kp
+			}
+		}
+		if issuerKP == nil && issuerAddress != "" {
+			return nil, errors.Errorf("the secret key of signer %s of regulated asset %s is missing", signerAddress, parts[0])
+		}
+		if issuerKP == nil {
+			return nil, errors.Errorf("the secret key of issuer %s of regulated asset %s is missing", signerAddress, parts[0])
+		}
+		kycThreshold, err := amount.ParseInt64(parts[2])
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s cannot be parsed as a Stellar amount", parts[2])
+		}
+		if kycThreshold <= 0 {
+			return nil, errors.Errorf("kyc threshold of regulated asset %s must be greater than zero", parts[0])
+		}
+
+		assets = append(assets, regulatedAsset{
+			code:          parts[0],
+			issuerKP:      issuerKP,
+			issuerAddress: issuerAddress,
+			kycThreshold:  kycThreshold,
+		})
+	}
+	return assets, nil
+}
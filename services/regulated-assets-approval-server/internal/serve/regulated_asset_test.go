@@ -0,0 +1,144 @@
+package serve
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadIssuerSecrets(t *testing.T) {
+	kps, err := readIssuerSecrets("")
+	require.NoError(t, err)
+	assert.Empty(t, kps)
+
+	fooKP := keypair.MustRandom()
+	barKP := keypair.MustRandom()
+	f, err := ioutil.TempFile("", "issuer-secrets")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("# FOO\n" + fooKP.Seed() + "\n\n  " + barKP.Seed() + "  \n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	kps, err = readIssuerSecrets(f.Name())
+	require.NoError(t, err)
+	require.Len(t, kps, 2)
+	assert.Equal(t, fooKP.Address(), kps[0].Address())
+	assert.Equal(t, barKP.Address(), kps[1].Address())
+
+	require.NoError(t, ioutil.WriteFile(f.Name(), []byte(fooKP.Seed()+"\n"+barKP.Address()+"\n"), 0600))
+	_, err = readIssuerSecrets(f.Name())
+	assert.EqualError(t, err, "line 2 of the issuer secrets file is not a valid Stellar secret key")
+}
+
+func TestParseRegulatedAssets(t *testing.T) {
+	fooKP := keypair.MustRandom()
+	barKP := keypair.MustRandom()
+	kps := []*keypair.Full{fooKP, barKP}
+
+	assets, err := parseRegulatedAssets("", kps)
+	require.NoError(t, err)
+	assert.Empty(t, assets)
+
+	assets, err = parseRegulatedAssets("FOO:"+fooKP.Address()+":500, BAR:"+barKP.Address()+":10.5", kps)
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	assert.Equal(t, "FOO", assets[0].code)
+	assert.Equal(t, fooKP.Address(), assets[0].issuerKP.Address())
+	assert.Equal(t, int64(5000000000), assets[0].kycThreshold)
+	assert.Equal(t, "BAR", assets[1].code)
+	assert.Equal(t, barKP.Address(), assets[1].issuerKP.Address())
+	assert.Equal(t, int64(105000000), assets[1].kycThreshold)
+
+	// The revised transactions of BAZ are signed by barKP, a signer of its
+	// issuer account.
+	bazIssuer := keypair.MustRandom().Address()
+	assets, err = parseRegulatedAssets("BAZ:"+bazIssuer+":500:"+barKP.Address(), kps)
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, bazIssuer, assets[0].issuer())
+	assert.Equal(t, barKP.Address(), assets[0].issuerKP.Address())
+
+	_, err = parseRegulatedAssets("FOO:"+fooKP.Address(), kps)
+	assert.EqualError(t, err, `regulated asset "FOO:`+fooKP.Address()+`" must have the CODE:ISSUER_ADDRESS:KYC_THRESHOLD[:SIGNER_ADDRESS] format`)
+
+	_, err = parseRegulatedAssets("BAZ:"+bazIssuer+":500:GABC", kps)
+	assert.EqualError(t, err, "signer of regulated asset BAZ is not a valid Stellar address")
+
+	_, err = parseRegulatedAssets("FOO:"+fooKP.Seed()+":500", kps)
+	assert.EqualError(t, err, "issuer of regulated asset FOO is not a valid Stellar address")
+
+	otherKP := keypair.MustRandom()
+	_, err = parseRegulatedAssets("FOO:"+otherKP.Address()+":500", kps)
+	assert.EqualError(t, err, "the secret key of issuer "+otherKP.Address()+" of regulated asset FOO is missing")
+	_, err = parseRegulatedAssets("BAZ:"+bazIssuer+":500:"+otherKP.Address(), kps)
+	assert.EqualError(t, err, "the secret key of signer "+otherKP.Address()+" of regulated asset BAZ is missing")
+
+	_, err = parseRegulatedAssets("FOO:"+fooKP.Address()+":0", kps)
+	assert.EqualError(t, err, "kyc threshold of regulated asset FOO must be greater than zero")
+}
+
+func TestTxApproveHandler_additionalAssets(t *testing.T) {
+	fooKP := keypair.MustRandom()
+	barKP := keypair.MustRandom()
+	h := txApproveHandler{
+		issuerKP:     fooKP,
+		assetCode:    "FOO",
+		kycThreshold: 5000000000,
+		additionalAssets: []regulatedAsset{
+			{code: "BAR", issuerKP: barKP, kycThreshold: 1000000000},
+		},
+	}
+
+	asset, ok := h.findAsset("BAR", barKP.Address())
+	require.True(t, ok)
+	assert.Equal(t, "BAR", asset.code)
+	_, ok = h.findAsset("BAR", fooKP.Address())
+	assert.False(t, ok)
+
+	assert.True(t, h.isIssuer(fooKP.Address()))
+	assert.True(t, h.isIssuer(barKP.Address()))
+	assert.False(t, h.isIssuer(keypair.MustRandom().Address()))
+
+	// the threshold of the payment asset is used
+	msg, err := h.kycRequiredMessageIfNeeded(&paymentOperation{
+		asset:  txnbuild.CreditAsset{Code: "BAR", Issuer: barKP.Address()},
+		amount: "101",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Payments exceeding 100.00 BAR requires KYC approval. Please provide an email address.", msg)
+
+	msg, err = h.kycRequiredMessageIfNeeded(&paymentOperation{
+		asset:  txnbuild.CreditAsset{Code: "FOO", Issuer: fooKP.Address()},
+		amount: "101",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, msg)
+}
+
+func TestTxApproveHandler_issuerAddress(t *testing.T) {
+	issuerKP := keypair.MustRandom()
+	signerKP := keypair.MustRandom()
+	h := txApproveHandler{
+		issuerKP:      signerKP,
+		issuerAddress: issuerKP.Address(),
+		assetCode:     "FOO",
+		kycThreshold:  5000000000,
+	}
+
+	// the asset is issued by the issuer account, not by its signing key
+	asset, ok := h.findAsset("FOO", issuerKP.Address())
+	require.True(t, ok)
+	assert.Equal(t, issuerKP.Address(), asset.issuer())
+	assert.Equal(t, signerKP.Address(), asset.issuerKP.Address())
+	_, ok = h.findAsset("FOO", signerKP.Address())
+	assert.False(t, ok)
+
+	assert.True(t, h.isIssuer(issuerKP.Address()))
+	assert.False(t, h.isIssuer(signerKP.Address()))
+}
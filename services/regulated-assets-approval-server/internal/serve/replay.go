@@ -0,0 +1,105 @@
+package serve
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// decisionState is the state of the payment source account a tx-approve
+// decision depends on. It is recorded in the audit log along with the
+// decision, so that the decision can be replayed against it.
+type decisionState struct {
+	// Time is the time of the decision.
+	Time time.Time `json:"time"`
+	// Sequence is the sequence number of the payment source account, empty
+	// if the decision was taken without looking it up.
+	Sequence string `json:"sequence,omitempty"`
+	// KYC is the KYC status of the payment source account, nil if the
+	// decision was taken without looking it up.
+	KYC *decisionKYCStatus `json:"kyc,omitempty"`
+
+	// replayed is true if the decision is replayed against the recorded
+	// state, see replay.
+	replayed bool
+}
+
+// decisionKYCStatus is the KYC status of an account when a decision was
+// taken.
+type decisionKYCStatus struct {
+	CallbackID string `json:"callback_id"`
+	Approved   bool   `json:"approved,omitempty"`
+	Rejected   bool   `json:"rejected,omitempty"`
+	InReview   bool   `json:"in_review,omitempty"`
+}
+
+// ReplayResult compares a decision recorded in the tx-approve audit log with
+// the decision taken again on the same transaction.
+type ReplayResult struct {
+	ID        int64           `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Tx        string          `json:"tx"`
+	Recorded  json.RawMessage `json:"recorded"`
+	Replayed  json.RawMessage `json:"replayed"`
+	// StatusChanged is true if the replayed decision does not have the
+	// status of the recorded one.
+	StatusChanged bool `json:"status_changed"`
+}
+
+// Replay takes again the decision recorded in the tx-approve audit log entry
+// with the given id, with the current configuration of the server against
+// the state of the payment source account recorded with the decision: its
+// sequence number and its KYC status. The replayed revised transaction is
+// neither signed nor recorded, and the database is left unchanged.
+func Replay(ctx context.Context, opts Options, id int64) (*ReplayResult, error) {
+	deps := opts.dependencies()
+	defer deps.db.Close()
+	h := opts.txApproveHandler(deps)
+	h.metrics = nil
+	return h.replay(ctx, id)
+}
+
+func (h txApproveHandler) replay(ctx context.Context, id int64) (*ReplayResult, error) {
+	result := ReplayResult{ID: id}
+	var (
+		recordedStatus string
+		recorded       []byte
+		stateJSON      sql.NullString
+	)
+	const q = `
+		SELECT tx, status, response, state, created_at
+		FROM tx_approve_audit_log
+		WHERE id = $1
+	`
+	err := h.db.QueryRowContext(ctx, q, id).Scan(&result.Tx, &recordedStatus, &recorded, &stateJSON, &result.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, errors.Errorf("audit log entry %d not found", id)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "querying the audit log")
+	}
+	result.Recorded = json.RawMessage(recorded)
+	if !stateJSON.Valid {
+		return nil, errors.Errorf("audit log entry %d was recorded without the state of the decision and cannot be replayed", id)
+	}
+	state := decisionState{}
+	err = json.Unmarshal([]byte(stateJSON.String), &state)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding the recorded decision state")
+	}
+	state.replayed = true
+
+	resp, err := h.decide(ctx, txApproveRequest{Tx: result.Tx}, &state)
+	if err != nil {
+		return nil, errors.Wrap(err, "replaying tx-approve")
+	}
+	result.Replayed, err = json.Marshal(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding the replayed response")
+	}
+	result.StatusChanged = string(resp.Status) != recordedStatus
+	return &result, nil
+}
@@ -0,0 +1,124 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxApproveHandlerReplay(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+
+	issuerKP := keypair.MustRandom()
+	senderKP := keypair.MustRandom()
+	receiverKP := keypair.MustRandom()
+	assetGOAT := txnbuild.CreditAsset{Code: "GOAT", Issuer: issuerKP.Address()}
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: senderKP.Address()}).
+		Return(horizon.Account{AccountID: senderKP.Address(), Sequence: "2"}, nil)
+
+	kycThreshold, err := amount.ParseInt64("500")
+	require.NoError(t, err)
+	h := txApproveHandler{
+		issuerKP:          issuerKP,
+		assetCode:         assetGOAT.GetCode(),
+		horizonClient:     &horizonMock,
+		networkPassphrase: network.TestNetworkPassphrase,
+		db:                conn,
+		kycThreshold:      kycThreshold,
+		baseURL:           "https://sep8-server.test",
+	}
+
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &horizon.Account{AccountID: senderKP.Address(), Sequence: "2"},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: receiverKP.Address(), Amount: "501", Asset: assetGOAT},
+		},
+		BaseFee:    txnbuild.MinBaseFee,
+		Timebounds: txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	txEnc, err := tx.Base64()
+	require.NoError(t, err)
+
+	// The decision requiring KYC is recorded in the audit log.
+	resp, err := h.txApprove(ctx, txApproveRequest{Tx: txEnc})
+	require.NoError(t, err)
+	require.Equal(t, sep8StatusActionRequired, resp.Status)
+
+	var (
+		id     int64
+		status string
+	)
+	err = conn.QueryRowContext(ctx, "SELECT id, status FROM tx_approve_audit_log").Scan(&id, &status)
+	require.NoError(t, err)
+	assert.Equal(t, "action_required", status)
+
+	_, err = h.replay(ctx, id+1)
+	assert.EqualError(t, err, fmt.Sprintf("audit log entry %d not found", id+1))
+
+	// The decision is replayed against the recorded KYC status, the KYC
+	// approved since is ignored.
+	_, err = conn.ExecContext(ctx, "UPDATE accounts_kyc_status SET approved_at = NOW() WHERE stellar_address = $1", senderKP.Address())
+	require.NoError(t, err)
+	result, err := h.replay(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, id, result.ID)
+	assert.Equal(t, txEnc, result.Tx)
+	assert.False(t, result.StatusChanged)
+	recorded := txApprovalResponse{}
+	require.NoError(t, json.Unmarshal(result.Recorded, &recorded))
+	assert.Equal(t, sep8StatusActionRequired, recorded.Status)
+	replayed := txApprovalResponse{}
+	require.NoError(t, json.Unmarshal(result.Replayed, &replayed))
+	assert.Equal(t, recorded, replayed)
+
+	// With a higher KYC threshold, the replayed decision revises the
+	// transaction, without signing it.
+	h.kycThreshold, err = amount.ParseInt64("1000")
+	require.NoError(t, err)
+	result, err = h.replay(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, result.StatusChanged)
+	replayed = txApprovalResponse{}
+	require.NoError(t, json.Unmarshal(result.Replayed, &replayed))
+	assert.Equal(t, sep8StatusRevised, replayed.Status)
+	revisedTx, err := txnbuild.TransactionFromXDR(replayed.Tx)
+	require.NoError(t, err)
+	revised, ok := revisedTx.Transaction()
+	require.True(t, ok)
+	assert.Empty(t, revised.Signatures())
+
+	// Replaying leaves the database unchanged.
+	var count int
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM tx_approve_audit_log").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM revised_transactions").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	horizonMock.AssertNumberOfCalls(t, "AccountDetail", 1)
+
+	// The decisions recorded without their state cannot be replayed.
+	_, err = conn.ExecContext(ctx, "UPDATE tx_approve_audit_log SET state = NULL")
+	require.NoError(t, err)
+	_, err = h.replay(ctx, id)
+	assert.EqualError(t, err, fmt.Sprintf("audit log entry %d was recorded without the state of the decision and cannot be replayed", id))
+}
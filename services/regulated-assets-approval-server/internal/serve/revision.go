@@ -0,0 +1,100 @@
+package serve
+
+import (
+	"context"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/txnbuild"
+)
+
+// RevisionRequest contains the data of an approved payment which needs to be
+// revised.
+type RevisionRequest struct {
+	// Tx is the transaction submitted for approval.
+	Tx *txnbuild.Transaction
+	// Operation is the payment or path payment of the regulated asset
+	// contained in Tx.
+	Operation txnbuild.Operation
+	// Payment is the payment of the regulated asset contained in Tx, nil if
+	// Operation is a path payment.
+	Payment *txnbuild.Payment
+	// Asset is the regulated asset moved by Operation.
+	Asset txnbuild.Asset
+	// Trustors are the accounts holding Asset during Operation, which need to
+	// be authorized.
+	Trustors []string
+	// PaymentSource is the account sending the payment.
+	PaymentSource string
+	// SourceAccount is the source account of Tx, with its current sequence
+	// number as loaded from Horizon.
+	SourceAccount txnbuild.Account
+	// IssuerAddress is the address of the regulated asset issuer, which will
+	// sign the revised transaction.
+	IssuerAddress string
+}
+
+// Revision is the result of a RevisionStrategy.
+type Revision struct {
+	// Params are used to build the revised transaction, which is then signed
+	// by the issuer.
+	Params txnbuild.TransactionParams
+	// Message is returned to the client along with the revised transaction.
+	Message string
+}
+
+// RevisionStrategy builds the revised transaction for an approved payment,
+// allowing issuers to supply custom revision logic, e.g. routing payments
+// through a compliance escrow account or adding a memo tag.
+type RevisionStrategy interface {
+	Revise(ctx context.Context, req RevisionRequest) (*Revision, error)
+}
+
+// RevisionStrategies are the revision strategies which can be selected by
+// name with the revision-strategy option. Issuers building the server with a
+// custom RevisionStrategy register it here, in an init function.
+var RevisionStrategies = map[string]RevisionStrategy{
+	"set-trust-line-flags-sandwich": SetTrustLineFlagsSandwich{},
+	"allow-trust-sandwich":          AllowTrustSandwich{},
+}
+
+// revisionStrategy returns the strategy registered in RevisionStrategies
+// under name.
+func revisionStrategy(name string) (RevisionStrategy, error) {
+	strategy, ok := RevisionStrategies[name]
+	if !ok {
+		return nil, errors.Errorf("unknown revision strategy %q", name)
+	}
+	return strategy, nil
+}
+
+// SetTrustLineFlagsSandwich is the default RevisionStrategy. It wraps the
+// payment with SetTrustLineFlags operations authorizing the accounts holding
+// the regulated asset before the payment and deauthorizing them after it.
+type SetTrustLineFlagsSandwich struct{}
+
+func (SetTrustLineFlagsSandwich) Revise(ctx context.Context, req RevisionRequest) (*Revision, error) {
+	return authorizationSandwich(req, true), nil
+}
+
+// AllowTrustSandwich wraps the payment with the AllowTrust operations
+// deprecated by protocol 17, for networks which did not upgrade to it yet.
+type AllowTrustSandwich struct{}
+
+func (AllowTrustSandwich) Revise(ctx context.Context, req RevisionRequest) (*Revision, error) {
+	return authorizationSandwich(req, false), nil
+}
+
+func authorizationSandwich(req RevisionRequest, useSetTrustLineFlags bool) *Revision {
+	operations := txnbuild.SEP8AuthorizationSandwich(req.Operation, req.IssuerAddress, req.Asset, req.Trustors, useSetTrustLineFlags)
+
+	return &Revision{
+		Params: txnbuild.TransactionParams{
+			SourceAccount:        req.SourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           operations,
+			BaseFee:              300,
+			Timebounds:           txnbuild.NewTimeout(300),
+		},
+		Message: "Authorization and deauthorization operations were added.",
+	}
+}
@@ -0,0 +1,201 @@
+package serve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbtest"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTrustLineFlagsSandwich(t *testing.T) {
+	issuerKP := keypair.MustRandom()
+	senderKP := keypair.MustRandom()
+	receiverKP := keypair.MustRandom()
+	asset := txnbuild.CreditAsset{Code: "GOAT", Issuer: issuerKP.Address()}
+	payment := &txnbuild.Payment{Destination: receiverKP.Address(), Amount: "1", Asset: asset}
+	sourceAccount := &txnbuild.SimpleAccount{AccountID: senderKP.Address(), Sequence: 5}
+
+	revision, err := SetTrustLineFlagsSandwich{}.Revise(context.Background(), RevisionRequest{
+		Operation:     payment,
+		Payment:       payment,
+		Asset:         payment.Asset,
+		Trustors:      []string{senderKP.Address(), payment.Destination},
+		PaymentSource: senderKP.Address(),
+		SourceAccount: sourceAccount,
+		IssuerAddress: issuerKP.Address(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Authorization and deauthorization operations were added.", revision.Message)
+	assert.Equal(t, sourceAccount, revision.Params.SourceAccount)
+	assert.True(t, revision.Params.IncrementSequenceNum)
+
+	authorized := []txnbuild.TrustLineFlag{txnbuild.TrustLineAuthorized}
+	wantOperations := []txnbuild.Operation{
+		&txnbuild.SetTrustLineFlags{Trustor: senderKP.Address(), Asset: asset, SetFlags: authorized, SourceAccount: issuerKP.Address()},
+		&txnbuild.SetTrustLineFlags{Trustor: receiverKP.Address(), Asset: asset, SetFlags: authorized, SourceAccount: issuerKP.Address()},
+		payment,
+		&txnbuild.SetTrustLineFlags{Trustor: receiverKP.Address(), Asset: asset, ClearFlags: authorized, SourceAccount: issuerKP.Address()},
+		&txnbuild.SetTrustLineFlags{Trustor: senderKP.Address(), Asset: asset, ClearFlags: authorized, SourceAccount: issuerKP.Address()},
+	}
+	assert.Equal(t, wantOperations, revision.Params.Operations)
+}
+
+func TestAllowTrustSandwich(t *testing.T) {
+	issuerKP := keypair.MustRandom()
+	senderKP := keypair.MustRandom()
+	receiverKP := keypair.MustRandom()
+	asset := txnbuild.CreditAsset{Code: "GOAT", Issuer: issuerKP.Address()}
+	payment := &txnbuild.Payment{Destination: receiverKP.Address(), Amount: "1", Asset: asset}
+	sourceAccount := &txnbuild.SimpleAccount{AccountID: senderKP.Address(), Sequence: 5}
+
+	revision, err := AllowTrustSandwich{}.Revise(context.Background(), RevisionRequest{
+		Operation:     payment,
+		Payment:       payment,
+		Asset:         payment.Asset,
+		Trustors:      []string{senderKP.Address(), payment.Destination},
+		PaymentSource: senderKP.Address(),
+		SourceAccount: sourceAccount,
+		IssuerAddress: issuerKP.Address(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Authorization and deauthorization operations were added.", revision.Message)
+	assert.Equal(t, sourceAccount, revision.Params.SourceAccount)
+	assert.True(t, revision.Params.IncrementSequenceNum)
+
+	wantOperations := []txnbuild.Operation{
+		&txnbuild.AllowTrust{Trustor: senderKP.Address(), Type: asset, Authorize: true, SourceAccount: issuerKP.Address()},
+		&txnbuild.AllowTrust{Trustor: receiverKP.Address(), Type: asset, Authorize: true, SourceAccount: issuerKP.Address()},
+		payment,
+		&txnbuild.AllowTrust{Trustor: receiverKP.Address(), Type: asset, Authorize: false, SourceAccount: issuerKP.Address()},
+		&txnbuild.AllowTrust{Trustor: senderKP.Address(), Type: asset, Authorize: false, SourceAccount: issuerKP.Address()},
+	}
+	assert.Equal(t, wantOperations, revision.Params.Operations)
+}
+
+func TestOptionsRevisionStrategy(t *testing.T) {
+	strategy, err := Options{}.revisionStrategy()
+	require.NoError(t, err)
+	assert.Equal(t, SetTrustLineFlagsSandwich{}, strategy)
+
+	strategy, err = Options{RevisionStrategyName: "allow-trust-sandwich"}.revisionStrategy()
+	require.NoError(t, err)
+	assert.Equal(t, AllowTrustSandwich{}, strategy)
+
+	RevisionStrategies["memo-tag"] = memoTagStrategy{memo: "compliance-123"}
+	defer delete(RevisionStrategies, "memo-tag")
+	strategy, err = Options{RevisionStrategyName: "memo-tag"}.revisionStrategy()
+	require.NoError(t, err)
+	assert.Equal(t, memoTagStrategy{memo: "compliance-123"}, strategy)
+
+	// a strategy set programmatically takes precedence over the name
+	strategy, err = Options{RevisionStrategy: memoTagStrategy{}, RevisionStrategyName: "allow-trust-sandwich"}.revisionStrategy()
+	require.NoError(t, err)
+	assert.Equal(t, memoTagStrategy{}, strategy)
+
+	_, err = Options{RevisionStrategyName: "escrow"}.revisionStrategy()
+	assert.EqualError(t, err, `unknown revision strategy "escrow"`)
+}
+
+// memoTagStrategy is a custom RevisionStrategy adding a memo to the default
+// revision.
+type memoTagStrategy struct {
+	memo string
+}
+
+func (s memoTagStrategy) Revise(ctx context.Context, req RevisionRequest) (*Revision, error) {
+	revision, err := AllowTrustSandwich{}.Revise(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	revision.Params.Memo = txnbuild.MemoText(s.memo)
+	revision.Message = "Authorization operations and a memo tag were added."
+	return revision, nil
+}
+
+func TestTxApproveHandler_customRevisionStrategy(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	issuerKP := keypair.MustRandom()
+	senderKP := keypair.MustRandom()
+	receiverKP := keypair.MustRandom()
+	asset := txnbuild.CreditAsset{Code: "GOAT", Issuer: issuerKP.Address()}
+
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: senderKP.Address()}).
+		Return(horizon.Account{AccountID: senderKP.Address(), Sequence: "5"}, nil)
+
+	kycThreshold, err := amount.ParseInt64("500")
+	require.NoError(t, err)
+	handler := txApproveHandler{
+		issuerKP:          issuerKP,
+		assetCode:         asset.GetCode(),
+		horizonClient:     &horizonMock,
+		networkPassphrase: network.TestNetworkPassphrase,
+		db:                conn,
+		kycThreshold:      kycThreshold,
+		baseURL:           "https://sep8-server.test",
+		revisionStrategy:  memoTagStrategy{memo: "compliance-123"},
+	}
+
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: senderKP.Address(), Sequence: 5},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: receiverKP.Address(), Amount: "1", Asset: asset},
+		},
+		BaseFee:    txnbuild.MinBaseFee,
+		Timebounds: txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	txe, err := tx.Base64()
+	require.NoError(t, err)
+
+	resp, err := handler.txApprove(ctx, txApproveRequest{Tx: txe})
+	require.NoError(t, err)
+	assert.Equal(t, sep8StatusRevised, resp.Status)
+	assert.Equal(t, "Authorization operations and a memo tag were added.", resp.Message)
+
+	parsed, err := txnbuild.TransactionFromXDR(resp.Tx)
+	require.NoError(t, err)
+	revisedTx, ok := parsed.Transaction()
+	require.True(t, ok)
+	assert.Equal(t, txnbuild.MemoText("compliance-123"), revisedTx.Memo())
+	assert.Len(t, revisedTx.Operations(), 5)
+	assert.Len(t, revisedTx.Signatures(), 1)
+
+	// The same transaction gets the recorded revision, even if the strategy
+	// would now revise it differently.
+	handler.revisionStrategy = memoTagStrategy{memo: "compliance-456"}
+	again, err := handler.txApprove(ctx, txApproveRequest{Tx: txe})
+	require.NoError(t, err)
+	assert.Equal(t, resp, again)
+
+	// A different transaction with the same sequence number is rejected.
+	tx, err = txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: senderKP.Address(), Sequence: 5},
+		IncrementSequenceNum: true,
+		Operations: []txnbuild.Operation{
+			&txnbuild.Payment{Destination: receiverKP.Address(), Amount: "2", Asset: asset},
+		},
+		BaseFee:    txnbuild.MinBaseFee,
+		Timebounds: txnbuild.NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	txe, err = tx.Base64()
+	require.NoError(t, err)
+	resp, err = handler.txApprove(ctx, txApproveRequest{Tx: txe})
+	require.NoError(t, err)
+	assert.Equal(t, NewRejectedTxApprovalResponse("A different transaction with the same source account and sequence number was already approved."), resp)
+}
@@ -1,42 +1,152 @@
 package serve
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/exp/compliance/cases"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db"
 	kycstatus "github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/kyc-status"
+	"github.com/stellar/go/support/config"
 	"github.com/stellar/go/support/errors"
 	supporthttp "github.com/stellar/go/support/http"
 	"github.com/stellar/go/support/log"
 	"github.com/stellar/go/support/render/health"
+	"github.com/stellar/throttled"
 )
 
 type Options struct {
-	AssetCode                         string
-	BaseURL                           string
-	DatabaseURL                       string
-	FriendbotPaymentAmount            int
-	HorizonURL                        string
-	IssuerAccountSecret               string
+	// AdditionalRegulatedAssets is a comma separated list of
+	// CODE:ISSUER_ADDRESS:KYC_THRESHOLD[:SIGNER_ADDRESS] assets approved
+	// along with AssetCode. The secret keys of their issuers, or of the
+	// signers of the issuer accounts, are read from IssuerSecretsFile.
+	AdditionalRegulatedAssets string
+	// AdminAPIKey enables the admin API, which must be called with this key
+	// as bearer token. The admin calling it with this key is named "admin".
+	AdminAPIKey string
+	// AdminAPIKeys is a comma separated list of NAME:KEY pairs giving each
+	// admin their own API key, which enables the admin API. The admins are
+	// identified by their name in the clawback requests and the compliance
+	// cases they decide.
+	AdminAPIKeys string
+	AssetCode    string
+	// AuditLogRetentionDays is the number of days the tx-approve decisions
+	// are kept in the audit log, forever if 0.
+	AuditLogRetentionDays int
+	BaseURL               string
+	// ClawbackApprovalRequired requires the clawbacks requested through the
+	// admin API to be approved by a second admin before being submitted.
+	ClawbackApprovalRequired bool
+	DatabaseURL              string
+	FriendbotPaymentAmount   int
+	GRPCPort                 int
+	// GRPCHost is the host the gRPC interface listens on. Defaults to
+	// localhost.
+	GRPCHost string
+	// GRPCAuthToken must be sent as bearer token in the authorization
+	// metadata of every gRPC call.
+	GRPCAuthToken string
+	HorizonURL    string
+	// IssuerAccountAddress is the issuer account of AssetCode. It defaults
+	// to the address of IssuerAccountSecret, and must be set when
+	// IssuerAccountSecret is another signer of the issuer account, e.g.
+	// after the signing key was rotated.
+	IssuerAccountAddress string
+	IssuerAccountSecret  string
+	// IssuerSecretsFile is the path of a file holding the secret keys of the
+	// issuers of AdditionalRegulatedAssets, one per line.
+	IssuerSecretsFile                 string
 	KYCRequiredPaymentAmountThreshold string
-	NetworkPassphrase                 string
-	Port                              int
+	// KYCProviderURL is the base URL of the REST API of an external KYC
+	// provider, see kycstatus.RESTProvider. If empty, KYC information is
+	// reviewed by kycstatus.RuleProvider.
+	KYCProviderURL    string
+	KYCProviderAPIKey string
+	// KYCProviderWebhookSecret enables the kyc-provider webhook, which must
+	// be called with this secret as bearer token.
+	KYCProviderWebhookSecret string
+	// KYCProviderWebhookSecretValue, if set, holds the webhook secret
+	// instead of KYCProviderWebhookSecret, so that it can be rotated while
+	// the server runs.
+	KYCProviderWebhookSecretValue *config.SecretValue
+	// KYCProviderPollInterval is the number of seconds between polls of the
+	// pending KYC cases, disabled if 0.
+	KYCProviderPollInterval int
+	NetworkPassphrase       string
+	Port                    int
+	// RateLimitPerMinute is the number of tx-approve requests allowed per
+	// minute for each client IP, disabled if 0.
+	RateLimitPerMinute int
+	RateLimitBurst     int
+	// RateLimiter keeps the rate limiting state. Defaults to a limiter backed
+	// by the Redis server at RateLimitRedisURL if set, which must be used when
+	// running multiple instances, or to an in-memory limiter otherwise.
+	RateLimiter       throttled.RateLimiter
+	RateLimitRedisURL string
+	// RevisionStrategy builds the revised transactions returned by
+	// tx-approve. Defaults to the strategy registered in RevisionStrategies
+	// under RevisionStrategyName.
+	RevisionStrategy RevisionStrategy
+	// RevisionStrategyName is the name of the strategy used when
+	// RevisionStrategy is nil. Defaults to set-trust-line-flags-sandwich.
+	RevisionStrategyName string
+	// TrustedProxies is a comma separated list of the IP addresses and CIDR
+	// networks of the proxies in front of the server. The client IP of
+	// requests coming from them is taken from the X-Forwarded-For header,
+	// which is ignored otherwise.
+	TrustedProxies string
+}
+
+// dependencies are the values shared by the HTTP and gRPC servers.
+type dependencies struct {
+	issuerKP         *keypair.Full
+	issuerAddress    string
+	kycThreshold     int64
+	additionalAssets []regulatedAsset
+	db               *sqlx.DB
+	cases            *cases.Store
+	kycProvider      kycstatus.Provider
+	revisionStrategy RevisionStrategy
+	metrics          *metrics
 }
 
 func Serve(opts Options) {
+	deps := opts.dependencies()
+	if opts.GRPCPort != 0 {
+		go serveGRPC(opts, deps)
+	}
+	if opts.KYCProviderPollInterval > 0 {
+		go kycstatus.Poller{
+			DB:       deps.db,
+			Provider: deps.kycProvider,
+			Cases:    deps.cases,
+			Interval: time.Duration(opts.KYCProviderPollInterval) * time.Second,
+		}.Run(context.Background())
+	}
+	if opts.AuditLogRetentionDays > 0 {
+		go auditLogPruner{
+			db:        deps.db,
+			retention: time.Duration(opts.AuditLogRetentionDays) * 24 * time.Hour,
+			interval:  time.Hour,
+		}.Run(context.Background())
+	}
+
 	listenAddr := fmt.Sprintf(":%d", opts.Port)
 	serverConfig := supporthttp.Config{
 		ListenAddr:          listenAddr,
-		Handler:             handleHTTP(opts),
+		Handler:             handleHTTP(opts, deps),
 		TCPKeepAlive:        time.Minute * 3,
 		ShutdownGracePeriod: time.Second * 50,
 		ReadTimeout:         time.Second * 5,
@@ -53,16 +163,33 @@ func Serve(opts Options) {
 	supporthttp.Run(serverConfig)
 }
 
-func handleHTTP(opts Options) http.Handler {
+func (opts Options) dependencies() dependencies {
 	issuerKP, err := keypair.ParseFull(opts.IssuerAccountSecret)
 	if err != nil {
 		log.Fatal(errors.Wrap(err, "parsing secret"))
 	}
+	issuerAddress := opts.IssuerAccountAddress
+	if issuerAddress == "" {
+		issuerAddress = issuerKP.Address()
+	}
 	parsedKYCRequiredPaymentThreshold, err := amount.ParseInt64(opts.KYCRequiredPaymentAmountThreshold)
 	if err != nil {
 		log.Fatal(errors.Wrapf(err, "%s cannot be parsed as a Stellar amount", opts.KYCRequiredPaymentAmountThreshold))
 	}
-	db, err := db.Open(opts.DatabaseURL)
+	issuerKPs, err := readIssuerSecrets(opts.IssuerSecretsFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	additionalAssets, err := parseRegulatedAssets(opts.AdditionalRegulatedAssets, issuerKPs)
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "parsing additional regulated assets"))
+	}
+	strategy, err := opts.revisionStrategy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	metrics := newMetrics()
+	db, err := db.OpenInstrumented(opts.DatabaseURL, metrics.observeDBQuery)
 	if err != nil {
 		log.Fatal(errors.Wrap(err, "error parsing database url"))
 	}
@@ -71,53 +198,228 @@ func handleHTTP(opts Options) http.Handler {
 	if err != nil {
 		log.Warn("Error pinging to Database: ", err)
 	}
+	return dependencies{
+		issuerKP:         issuerKP,
+		issuerAddress:    issuerAddress,
+		kycThreshold:     parsedKYCRequiredPaymentThreshold,
+		additionalAssets: additionalAssets,
+		db:               db,
+		cases:            &cases.Store{DB: db},
+		kycProvider:      opts.kycProvider(),
+		revisionStrategy: strategy,
+		metrics:          metrics,
+	}
+}
+
+func (opts Options) kycProvider() kycstatus.Provider {
+	if opts.KYCProviderURL == "" {
+		return kycstatus.RuleProvider{}
+	}
+	provider := kycstatus.RESTProvider{
+		URL:    opts.KYCProviderURL,
+		APIKey: opts.KYCProviderAPIKey,
+		HTTP:   &http.Client{Timeout: 30 * time.Second},
+	}
+	if opts.KYCProviderWebhookSecret != "" {
+		provider.WebhookURL = buildURLString(opts.BaseURL, "kyc-provider/webhook")
+	}
+	return provider
+}
+
+func handleHTTP(opts Options, deps dependencies) http.Handler {
 	mux := chi.NewMux()
 
 	mux.Use(middleware.RequestID)
-	mux.Use(middleware.RealIP)
+	if opts.TrustedProxies != "" {
+		trustedProxies, err := supporthttp.ParseTrustedProxies(opts.TrustedProxies)
+		if err != nil {
+			log.Fatal(errors.Wrap(err, "parsing trusted proxies"))
+		}
+		mux.Use(supporthttp.XFFMiddleware(supporthttp.XFFMiddlewareConfig{TrustedProxies: trustedProxies}))
+	}
 	mux.Use(supporthttp.LoggingMiddleware)
 	mux.Use(corsHandler)
 
 	mux.Get("/health", health.PassHandler{}.ServeHTTP)
+	if deps.metrics != nil {
+		mux.Handle("/metrics", promhttp.HandlerFor(deps.metrics.registry, promhttp.HandlerOpts{}))
+	}
 	mux.Get("/.well-known/stellar.toml", stellarTOMLHandler{
 		assetCode:         opts.AssetCode,
-		issuerAddress:     issuerKP.Address(),
+		issuerAddress:     deps.issuerAddress,
 		networkPassphrase: opts.NetworkPassphrase,
 		approvalServer:    buildURLString(opts.BaseURL, "tx-approve"),
-		kycThreshold:      parsedKYCRequiredPaymentThreshold,
+		kycThreshold:      deps.kycThreshold,
+		additionalAssets:  deps.additionalAssets,
 	}.ServeHTTP)
 	mux.Get("/friendbot", friendbotHandler{
 		assetCode:           opts.AssetCode,
 		issuerAccountSecret: opts.IssuerAccountSecret,
+		issuerAddress:       opts.IssuerAccountAddress,
 		horizonClient:       opts.horizonClient(),
 		horizonURL:          opts.HorizonURL,
 		networkPassphrase:   opts.NetworkPassphrase,
 		paymentAmount:       opts.FriendbotPaymentAmount,
+		metrics:             deps.metrics,
 	}.ServeHTTP)
-	mux.Post("/tx-approve", txApproveHandler{
-		assetCode:         opts.AssetCode,
-		issuerKP:          issuerKP,
-		horizonClient:     opts.horizonClient(),
-		networkPassphrase: opts.NetworkPassphrase,
-		db:                db,
-		kycThreshold:      parsedKYCRequiredPaymentThreshold,
-		baseURL:           opts.BaseURL,
-	}.ServeHTTP)
+	mux.With(opts.rateLimitHandler()).Post("/tx-approve", opts.txApproveHandler(deps).ServeHTTP)
 	mux.Route("/kyc-status", func(mux chi.Router) {
-		mux.Post("/{callback_id}", kycstatus.PostHandler{
-			DB: db,
+		mux.With(deps.metrics.kycCallbackMiddleware).Post("/{callback_id}", kycstatus.PostHandler{
+			DB:       deps.db,
+			Provider: deps.kycProvider,
+			Cases:    deps.cases,
 		}.ServeHTTP)
 		mux.Get("/{stellar_address_or_callback_id}", kycstatus.GetDetailHandler{
-			DB: db,
+			DB: deps.db,
 		}.ServeHTTP)
 		mux.Delete("/{stellar_address}", kycstatus.DeleteHandler{
-			DB: db,
+			DB: deps.db,
 		}.ServeHTTP)
 	})
+	if opts.KYCProviderWebhookSecret != "" {
+		webhookSecret := opts.KYCProviderWebhookSecretValue
+		if webhookSecret == nil {
+			webhookSecret = config.NewSecretValue(opts.KYCProviderWebhookSecret)
+		}
+		mux.Post("/kyc-provider/webhook", kycstatus.WebhookHandler{
+			DB:     deps.db,
+			Cases:  deps.cases,
+			Secret: webhookSecret,
+		}.ServeHTTP)
+	}
+	adminAPIKeys, err := opts.adminAPIKeys()
+	if err != nil {
+		log.Fatal(errors.Wrap(err, "parsing admin API keys"))
+	}
+	if len(adminAPIKeys) > 0 {
+		mux.Route("/admin/kyc-status", func(mux chi.Router) {
+			mux.Use(adminAuthHandler(adminAPIKeys))
+			mux.Get("/", kycstatus.AdminListHandler{
+				DB: deps.db,
+			}.ServeHTTP)
+			mux.Post("/{stellar_address}/approve", kycstatus.AdminDecisionHandler{
+				DB:     deps.db,
+				Cases:  deps.cases,
+				Status: kycstatus.CaseStatusApproved,
+			}.ServeHTTP)
+			mux.Post("/{stellar_address}/reject", kycstatus.AdminDecisionHandler{
+				DB:     deps.db,
+				Cases:  deps.cases,
+				Status: kycstatus.CaseStatusRejected,
+			}.ServeHTTP)
+			mux.Delete("/{stellar_address}", kycstatus.DeleteHandler{
+				DB: deps.db,
+			}.ServeHTTP)
+		})
+		mux.Route("/admin/cases", func(mux chi.Router) {
+			mux.Use(adminAuthHandler(adminAPIKeys))
+			cases.Routes(deps.cases)(mux)
+		})
+		mux.Route("/admin/clawback", func(mux chi.Router) {
+			mux.Use(adminAuthHandler(adminAPIKeys))
+			h := opts.clawbackHandler(deps)
+			mux.Post("/", h.serveCreate)
+			mux.Get("/{id}", h.serveGet)
+			mux.Post("/{id}/approve", h.serveApprove)
+			mux.Post("/{id}/reject", h.serveReject)
+		})
+	}
 
 	return mux
 }
 
+// defaultAdminName is the name of the admin calling the admin API with
+// AdminAPIKey.
+const defaultAdminName = "admin"
+
+// adminAPIKeys returns the API keys of the admins keyed by their names, from
+// AdminAPIKey and AdminAPIKeys.
+func (opts Options) adminAPIKeys() (map[string]string, error) {
+	keys := map[string]string{}
+	if opts.AdminAPIKey != "" {
+		keys[defaultAdminName] = opts.AdminAPIKey
+	}
+	if opts.AdminAPIKeys == "" {
+		return keys, nil
+	}
+	for _, pair := range strings.Split(opts.AdminAPIKeys, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.New("admin API keys must be NAME:KEY pairs")
+		}
+		name, key := parts[0], parts[1]
+		if _, ok := keys[name]; ok {
+			return nil, errors.Errorf("admin %s has several API keys", name)
+		}
+		for other, otherKey := range keys {
+			if key == otherKey {
+				return nil, errors.Errorf("admins %s and %s have the same API key", other, name)
+			}
+		}
+		keys[name] = key
+	}
+	return keys, nil
+}
+
+func (opts Options) rateLimitHandler() func(http.Handler) http.Handler {
+	if opts.RateLimitPerMinute <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	limiter := opts.RateLimiter
+	if limiter == nil {
+		var err error
+		if opts.RateLimitRedisURL != "" {
+			limiter, err = newRedisRateLimiter(opts.RateLimitRedisURL, opts.RateLimitPerMinute, opts.RateLimitBurst)
+		} else {
+			limiter, err = newMemoryRateLimiter(opts.RateLimitPerMinute, opts.RateLimitBurst)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	return rateLimitHandler(limiter)
+}
+
+// revisionStrategy returns the RevisionStrategy of the options, or the one
+// selected by RevisionStrategyName.
+func (opts Options) revisionStrategy() (RevisionStrategy, error) {
+	if opts.RevisionStrategy != nil {
+		return opts.RevisionStrategy, nil
+	}
+	if opts.RevisionStrategyName == "" {
+		return SetTrustLineFlagsSandwich{}, nil
+	}
+	return revisionStrategy(opts.RevisionStrategyName)
+}
+
+func (opts Options) txApproveHandler(deps dependencies) txApproveHandler {
+	return txApproveHandler{
+		assetCode:         opts.AssetCode,
+		issuerKP:          deps.issuerKP,
+		issuerAddress:     deps.issuerAddress,
+		horizonClient:     opts.horizonClient(),
+		networkPassphrase: opts.NetworkPassphrase,
+		db:                deps.db,
+		kycThreshold:      deps.kycThreshold,
+		baseURL:           opts.BaseURL,
+		revisionStrategy:  deps.revisionStrategy,
+		additionalAssets:  deps.additionalAssets,
+		kycPendingTimeout: time.Duration(opts.KYCProviderPollInterval) * time.Second,
+		metrics:           deps.metrics,
+	}
+}
+
+func (opts Options) clawbackHandler(deps dependencies) clawbackHandler {
+	return clawbackHandler{
+		assets:            opts.txApproveHandler(deps).assets(),
+		horizonClient:     opts.horizonClient(),
+		networkPassphrase: opts.NetworkPassphrase,
+		db:                deps.db,
+		approvalRequired:  opts.ClawbackApprovalRequired,
+		metrics:           deps.metrics,
+	}
+}
+
 func (opts Options) horizonClient() horizonclient.ClientInterface {
 	return &horizonclient.Client{
 		HorizonURL: opts.HorizonURL,
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,3 +22,22 @@ func TestHorizonClient(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, http.Client{Timeout: 30 * time.Second}, *httpClient)
 }
+
+func TestOptionsAdminAPIKeys(t *testing.T) {
+	keys, err := Options{}.adminAPIKeys()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	keys, err = Options{AdminAPIKey: "shared-key", AdminAPIKeys: "alice:alice-key, bob:bob:key"}.adminAPIKeys()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"admin": "shared-key", "alice": "alice-key", "bob": "bob:key"}, keys)
+
+	_, err = Options{AdminAPIKeys: "alice"}.adminAPIKeys()
+	assert.EqualError(t, err, "admin API keys must be NAME:KEY pairs")
+	_, err = Options{AdminAPIKeys: "alice:"}.adminAPIKeys()
+	assert.EqualError(t, err, "admin API keys must be NAME:KEY pairs")
+	_, err = Options{AdminAPIKeys: "alice:key1,alice:key2"}.adminAPIKeys()
+	assert.EqualError(t, err, "admin alice has several API keys")
+	_, err = Options{AdminAPIKey: "key", AdminAPIKeys: "alice:key"}.adminAPIKeys()
+	assert.EqualError(t, err, "admins admin and alice have the same API key")
+}
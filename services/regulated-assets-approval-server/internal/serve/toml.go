@@ -2,6 +2,7 @@ package serve
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
@@ -16,6 +17,7 @@ type stellarTOMLHandler struct {
 	issuerAddress     string
 	networkPassphrase string
 	kycThreshold      int64
+	additionalAssets  []regulatedAsset
 }
 
 func (h stellarTOMLHandler) validate() error {
@@ -63,13 +65,30 @@ func (h stellarTOMLHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		httperror.InternalServer.Render(rw)
 		return
 	}
+	additionalThresholds := make([]string, len(h.additionalAssets))
+	for i, asset := range h.additionalAssets {
+		additionalThresholds[i], err = convertThresholdToReadableString(asset.kycThreshold)
+		if err != nil {
+			log.Ctx(ctx).Error(errors.Wrap(err, "converting kycThreshold value to human readable string"))
+			httperror.InternalServer.Render(rw)
+			return
+		}
+	}
 
 	// Generate toml content.
 	fmt.Fprintf(rw, "NETWORK_PASSPHRASE=%q\n", h.networkPassphrase)
-	fmt.Fprintf(rw, "[[CURRENCIES]]\n")
-	fmt.Fprintf(rw, "code=%q\n", h.assetCode)
-	fmt.Fprintf(rw, "issuer=%q\n", h.issuerAddress)
-	fmt.Fprintf(rw, "regulated=true\n")
-	fmt.Fprintf(rw, "approval_server=%q\n", h.approvalServer)
-	fmt.Fprintf(rw, "approval_criteria=\"The approval server currently only accepts payments. The transaction must have exactly one operation of type payment. If the payment amount exceeds %s %s it will need KYC approval.\"", kycThreshold, h.assetCode)
+	h.writeCurrency(rw, h.assetCode, h.issuerAddress, kycThreshold)
+	for i, asset := range h.additionalAssets {
+		fmt.Fprintf(rw, "\n")
+		h.writeCurrency(rw, asset.code, asset.issuer(), additionalThresholds[i])
+	}
+}
+
+func (h stellarTOMLHandler) writeCurrency(w io.Writer, code, issuer, kycThreshold string) {
+	fmt.Fprintf(w, "[[CURRENCIES]]\n")
+	fmt.Fprintf(w, "code=%q\n", code)
+	fmt.Fprintf(w, "issuer=%q\n", issuer)
+	fmt.Fprintf(w, "regulated=true\n")
+	fmt.Fprintf(w, "approval_server=%q\n", h.approvalServer)
+	fmt.Fprintf(w, "approval_criteria=\"The approval server currently only accepts payments. The transaction must have exactly one operation of type payment. If the payment amount exceeds %s %s it will need KYC approval.\"", kycThreshold, code)
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi"
+	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -117,3 +118,43 @@ approval_server="localhost:8000/tx-approve"
 approval_criteria="The approval server currently only accepts payments. The transaction must have exactly one operation of type payment. If the payment amount exceeds 500.00 FOO it will need KYC approval."`
 	require.Equal(t, wantBody, string(body))
 }
+
+func TestTomlHandler_ServeHTTP_additionalAssets(t *testing.T) {
+	barIssuerKP := keypair.MustRandom()
+	mux := chi.NewMux()
+	mux.Get("/.well-known/stellar.toml", stellarTOMLHandler{
+		networkPassphrase: network.TestNetworkPassphrase,
+		assetCode:         "FOO",
+		issuerAddress:     "GCVDOU4YHHXGM3QYVSDHPQIFMZKXTFSIYO4HJOJZOTR7GURVQO6IQ5HM",
+		approvalServer:    "localhost:8000/tx-approve",
+		kycThreshold:      5000000000,
+		additionalAssets: []regulatedAsset{
+			{code: "BAR", issuerKP: barIssuerKP, kycThreshold: 1000000000},
+		},
+	}.ServeHTTP)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/.well-known/stellar.toml", nil)
+	mux.ServeHTTP(w, r)
+
+	resp := w.Result()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	wantBody := `NETWORK_PASSPHRASE="` + network.TestNetworkPassphrase + `"
+[[CURRENCIES]]
+code="FOO"
+issuer="GCVDOU4YHHXGM3QYVSDHPQIFMZKXTFSIYO4HJOJZOTR7GURVQO6IQ5HM"
+regulated=true
+approval_server="localhost:8000/tx-approve"
+approval_criteria="The approval server currently only accepts payments. The transaction must have exactly one operation of type payment. If the payment amount exceeds 500.00 FOO it will need KYC approval."
+[[CURRENCIES]]
+code="BAR"
+issuer="` + barIssuerKP.Address() + `"
+regulated=true
+approval_server="localhost:8000/tx-approve"
+approval_criteria="The approval server currently only accepts payments. The transaction must have exactly one operation of type payment. If the payment amount exceeds 100.00 BAR it will need KYC approval."`
+	require.Equal(t, wantBody, string(body))
+}
@@ -3,15 +3,18 @@ package serve
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizonclient"
 	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon"
 	"github.com/stellar/go/services/regulated-assets-approval-server/internal/serve/httperror"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/http/httpdecode"
@@ -19,14 +22,35 @@ import (
 	"github.com/stellar/go/txnbuild"
 )
 
+// dbConn is the part of *sqlx.DB and *sqlx.Tx used by txApproveHandler.
+type dbConn interface {
+	sqlx.ExecerContext
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type txApproveHandler struct {
-	issuerKP          *keypair.Full
+	issuerKP *keypair.Full
+	// issuerAddress is the issuer account of assetCode, if issuerKP is one
+	// of its signers other than its master key.
+	issuerAddress     string
 	assetCode         string
 	horizonClient     horizonclient.ClientInterface
 	networkPassphrase string
-	db                *sqlx.DB
+	db                dbConn
 	kycThreshold      int64
 	baseURL           string
+	// revisionStrategy builds the revised transaction, defaults to
+	// SetTrustLineFlagsSandwich.
+	revisionStrategy RevisionStrategy
+	// additionalAssets are approved along with the assetCode asset issued by
+	// issuerKP.
+	additionalAssets []regulatedAsset
+	// kycPendingTimeout is the time after which the wallet should submit a
+	// transaction again while the KYC of its sender is being reviewed, 0 if
+	// unknown.
+	kycPendingTimeout time.Duration
+	// metrics records the outcomes of tx-approve, it may be nil.
+	metrics *metrics
 }
 
 type txApproveRequest struct {
@@ -56,16 +80,58 @@ func (h txApproveHandler) validate() error {
 	if h.baseURL == "" {
 		return errors.New("base url cannot be empty")
 	}
+	for _, asset := range h.additionalAssets {
+		if asset.code == "" {
+			return errors.New("additional asset code cannot be empty")
+		}
+		if asset.issuerKP == nil {
+			return errors.Errorf("issuer keypair of additional asset %s cannot be nil", asset.code)
+		}
+		if asset.kycThreshold <= 0 {
+			return errors.Errorf("kyc threshold of additional asset %s cannot be less than or equal to zero", asset.code)
+		}
+	}
 	return nil
 }
 
-func convertThresholdToReadableString(threshold int64) (string, error) {
-	thresholdStr := amount.StringFromInt64(threshold)
-	res, err := strconv.ParseFloat(thresholdStr, 1)
-	if err != nil {
-		return "", errors.Wrap(err, "converting threshold amount from string to float")
+// assets returns all the regulated assets approved by the handler.
+func (h txApproveHandler) assets() []regulatedAsset {
+	assets := []regulatedAsset{{
+		code:          h.assetCode,
+		issuerKP:      h.issuerKP,
+		issuerAddress: h.issuerAddress,
+		kycThreshold:  h.kycThreshold,
+	}}
+	return append(assets, h.additionalAssets...)
+}
+
+// findAsset returns the regulated asset with the given code and issuer.
+func (h txApproveHandler) findAsset(code, issuer string) (regulatedAsset, bool) {
+	for _, asset := range h.assets() {
+		if asset.code == code && asset.issuer() == issuer {
+			return asset, true
+		}
+	}
+	return regulatedAsset{}, false
+}
+
+// isIssuer returns true if the address is the issuer of one of the regulated
+// assets.
+func (h txApproveHandler) isIssuer(address string) bool {
+	for _, asset := range h.assets() {
+		if asset.issuer() == address {
+			return true
+		}
 	}
-	return fmt.Sprintf("%.2f", res), nil
+	return false
+}
+
+// thresholdFormat is the format of the KYC thresholds shown to users, e.g.
+// 500.00.
+var thresholdFormat = amount.FormatOptions{DecimalPlaces: 2, Rounding: amount.RoundHalfEven}
+
+func convertThresholdToReadableString(threshold int64) (string, error) {
+	return amount.Format(threshold, thresholdFormat)
 }
 
 func (h txApproveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -116,18 +182,18 @@ func (h txApproveHandler) validateInput(ctx context.Context, in txApproveRequest
 		return NewRejectedTxApprovalResponse(`Invalid parameter "tx".`), nil
 	}
 
-	if tx.SourceAccount().AccountID == h.issuerKP.Address() {
+	if h.isIssuer(tx.SourceAccount().AccountID) {
 		log.Ctx(ctx).Errorf("transaction %s sourceAccount is the same as the server issuer account %s",
 			in.Tx,
-			h.issuerKP.Address())
+			tx.SourceAccount().AccountID)
 		return NewRejectedTxApprovalResponse("The source account is invalid."), nil
 	}
 
 	if len(tx.Operations()) != 1 {
-		return NewRejectedTxApprovalResponse("Please submit a transaction with exactly one operation of type payment."), nil
+		return NewRejectedTxApprovalResponse("Please submit a transaction with exactly one operation of type payment, path_payment_strict_send or path_payment_strict_receive."), nil
 	}
 
-	if tx.Operations()[0].GetSourceAccount() == h.issuerKP.Address() {
+	if h.isIssuer(tx.Operations()[0].GetSourceAccount()) {
 		log.Ctx(ctx).Error(`transaction contains one or more operations where sourceAccount is issuer account.`)
 		return NewRejectedTxApprovalResponse("There is one or more unauthorized operations in the provided transaction."), nil
 	}
@@ -137,38 +203,62 @@ func (h txApproveHandler) validateInput(ctx context.Context, in txApproveRequest
 
 // txApprove is called to validate the input transaction.
 func (h txApproveHandler) txApprove(ctx context.Context, in txApproveRequest) (resp *txApprovalResponse, err error) {
+	state := &decisionState{Time: time.Now().UTC()}
 	defer func() {
 		log.Ctx(ctx).Debug("==== will log responses ====")
 		log.Ctx(ctx).Debugf("req: %+v", in)
 		log.Ctx(ctx).Debugf("resp: %+v", resp)
 		log.Ctx(ctx).Debugf("err: %+v", err)
 		log.Ctx(ctx).Debug("====  did log responses ====")
+		h.metrics.observeTxApprove(resp, err)
+		if err == nil {
+			h.recordAuditLog(ctx, in, resp, state)
+		}
 	}()
 
+	return h.decide(ctx, in, state)
+}
+
+// decide takes the decision on the input transaction. The state of the
+// payment source account the decision depends on is read from Horizon and
+// from the database and kept in state. When replaying a decision of the
+// audit log, it is read from state instead, and the revised transaction is
+// neither signed nor recorded.
+func (h txApproveHandler) decide(ctx context.Context, in txApproveRequest, state *decisionState) (*txApprovalResponse, error) {
 	txRejectedResp, tx := h.validateInput(ctx, in)
 	if txRejectedResp != nil {
 		return txRejectedResp, nil
 	}
 
-	paymentOp, ok := tx.Operations()[0].(*txnbuild.Payment)
-	if !ok {
+	switch tx.Operations()[0].(type) {
+	case *txnbuild.Payment, *txnbuild.PathPaymentStrictSend, *txnbuild.PathPaymentStrictReceive:
+	default:
 		log.Ctx(ctx).Error(`transaction contains one or more operations is not of type payment`)
 		return NewRejectedTxApprovalResponse("There is one or more unauthorized operations in the provided transaction."), nil
 	}
-	paymentSource := paymentOp.SourceAccount
-	if paymentSource == "" {
-		paymentSource = tx.SourceAccount().AccountID
-	}
 
-	issuerAddress := h.issuerKP.Address()
-	if paymentOp.Asset.GetCode() != h.assetCode || paymentOp.Asset.GetIssuer() != issuerAddress {
+	paymentOp, ok := h.newPaymentOperation(tx.Operations()[0], tx.SourceAccount().AccountID)
+	if !ok {
 		log.Ctx(ctx).Error(`the payment asset is not supported by this issuer`)
 		return NewRejectedTxApprovalResponse("The payment asset is not supported by this issuer."), nil
 	}
+	paymentSource := paymentOp.source
+	asset, _ := h.findAsset(paymentOp.asset.GetCode(), paymentOp.asset.GetIssuer())
+	issuerAddress := asset.issuer()
 
-	acc, err := h.horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: paymentSource})
-	if err != nil {
-		return nil, errors.Wrapf(err, "getting detail for payment source account %s", issuerAddress)
+	acc := horizon.Account{AccountID: paymentSource, Sequence: state.Sequence}
+	if state.replayed {
+		if state.Sequence == "" {
+			return nil, errors.Errorf("the replayed decision depends on the sequence number of account %s, which was not recorded", paymentSource)
+		}
+	} else {
+		var err error
+		acc, err = h.horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: paymentSource})
+		if err != nil {
+			h.metrics.incHorizonError("account_detail")
+			return nil, errors.Wrapf(err, "getting detail for payment source account %s", issuerAddress)
+		}
+		state.Sequence = acc.Sequence
 	}
 	// validate the sequence number
 	accountSequence, err := strconv.ParseInt(acc.Sequence, 10, 64)
@@ -179,9 +269,26 @@ func (h txApproveHandler) txApprove(ctx context.Context, in txApproveRequest) (r
 		log.Ctx(ctx).Errorf(`invalid transaction sequence number tx.SourceAccount().Sequence: %d, accountSequence+1:%d`, tx.SourceAccount().Sequence, accountSequence+1)
 		return NewRejectedTxApprovalResponse("Invalid transaction sequence number."), nil
 	}
+	// Return the revision already signed for the transaction, if any, so
+	// that the sender cannot obtain several signed revisions of it.
+	txHash, err := tx.HashHex(h.networkPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing transaction")
+	}
+	// A replayed decision is taken again rather than returning the
+	// revision recorded since.
+	if !state.replayed {
+		recorded, err := h.findRevisedTransaction(ctx, tx.SourceAccount().AccountID, tx.SourceAccount().Sequence)
+		if err != nil {
+			return nil, errors.Wrap(err, "finding revised transaction")
+		}
+		if recorded != nil {
+			return h.recordedRevisionResponse(ctx, tx, txHash, recorded), nil
+		}
+	}
 	// Validate if payment operation requires KYC.
 	var kycRequiredResponse *txApprovalResponse
-	kycRequiredResponse, err = h.handleKYCRequiredOperationIfNeeded(ctx, paymentSource, paymentOp)
+	kycRequiredResponse, err = h.handleKYCRequiredOperationIfNeeded(ctx, paymentSource, paymentOp, state)
 	if err != nil {
 		return nil, errors.Wrap(err, "handling KYC required payment")
 	}
@@ -189,59 +296,180 @@ func (h txApproveHandler) txApprove(ctx context.Context, in txApproveRequest) (r
 		return kycRequiredResponse, nil
 	}
 	// build the transaction
-	revisedOperations := []txnbuild.Operation{
-		&txnbuild.AllowTrust{
-			Trustor:       paymentSource,
-			Type:          paymentOp.Asset,
-			Authorize:     true,
-			SourceAccount: issuerAddress,
-		},
-		&txnbuild.AllowTrust{
-			Trustor:       paymentOp.Destination,
-			Type:          paymentOp.Asset,
-			Authorize:     true,
-			SourceAccount: issuerAddress,
-		},
-		paymentOp,
-		&txnbuild.AllowTrust{
-			Trustor:       paymentOp.Destination,
-			Type:          paymentOp.Asset,
-			Authorize:     false,
-			SourceAccount: issuerAddress,
-		},
-		&txnbuild.AllowTrust{
-			Trustor:       paymentSource,
-			Type:          paymentOp.Asset,
-			Authorize:     false,
-			SourceAccount: issuerAddress,
-		},
-	}
-	revisedTx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
-		SourceAccount:        &acc,
-		IncrementSequenceNum: true,
-		Operations:           revisedOperations,
-		BaseFee:              300,
-		Timebounds:           txnbuild.NewTimeout(300),
+	strategy := h.revisionStrategy
+	if strategy == nil {
+		strategy = SetTrustLineFlagsSandwich{}
+	}
+	payment, _ := paymentOp.operation.(*txnbuild.Payment)
+	revision, err := strategy.Revise(ctx, RevisionRequest{
+		Tx:            tx,
+		Operation:     paymentOp.operation,
+		Payment:       payment,
+		Asset:         paymentOp.asset,
+		Trustors:      paymentOp.trustors,
+		PaymentSource: paymentSource,
+		SourceAccount: &acc,
+		IssuerAddress: issuerAddress,
 	})
+	if err != nil {
+		return nil, errors.Wrap(err, "revising transaction")
+	}
+	revisedTx, err := txnbuild.NewTransaction(revision.Params)
 	if err != nil {
 		return nil, errors.Wrap(err, "building transaction")
 	}
+	if state.replayed {
+		revisedTxe, err := revisedTx.Base64()
+		if err != nil {
+			return nil, errors.Wrap(err, "encoding revised transaction")
+		}
+		return NewRevisedTxApprovalResponse(revisedTxe, revision.Message), nil
+	}
 
-	revisedTx, err = revisedTx.Sign(h.networkPassphrase, h.issuerKP)
+	revisedTx, err = revisedTx.Sign(h.networkPassphrase, asset.issuerKP)
 	if err != nil {
 		return nil, errors.Wrap(err, "signing transaction")
 	}
 
-	txe, err := revisedTx.Base64()
+	recorded, err := h.recordRevisedTransaction(ctx, txHash, revisedTx, revision.Message)
+	if err != nil {
+		return nil, errors.Wrap(err, "recording revised transaction")
+	}
+	return h.recordedRevisionResponse(ctx, tx, txHash, recorded), nil
+}
+
+// recordedRevision is the revised transaction recorded for a source account
+// and sequence number.
+type recordedRevision struct {
+	// OriginalTxHash is the hash of the transaction submitted to tx-approve.
+	OriginalTxHash string
+	// RevisedTx is the signed revised transaction envelope, in base64.
+	RevisedTx string
+	// Message is the message of the revised tx-approve response.
+	Message string
+}
+
+// recordedRevisionResponse returns the revised response of the recorded
+// revision if it is the revision of tx, whose hash is txHash, or a rejected
+// response if a different transaction was revised for the same source
+// account and sequence number.
+func (h txApproveHandler) recordedRevisionResponse(ctx context.Context, tx *txnbuild.Transaction, txHash string, recorded *recordedRevision) *txApprovalResponse {
+	if recorded.OriginalTxHash != txHash || recorded.RevisedTx == "" {
+		log.Ctx(ctx).Errorf("a different revised transaction was already approved for source account %s and sequence number %d", tx.SourceAccount().AccountID, tx.SourceAccount().Sequence)
+		return NewRejectedTxApprovalResponse("A different transaction with the same source account and sequence number was already approved.")
+	}
+	return NewRevisedTxApprovalResponse(recorded.RevisedTx, recorded.Message)
+}
+
+// findRevisedTransaction returns the revision recorded for the source account
+// and sequence number whose time bounds have not expired, or nil if there is
+// none.
+func (h txApproveHandler) findRevisedTransaction(ctx context.Context, sourceAccount string, sequenceNumber int64) (*recordedRevision, error) {
+	const q = `
+		SELECT COALESCE(original_tx_hash, ''), COALESCE(revised_tx, ''), COALESCE(revision_message, '')
+		FROM revised_transactions
+		WHERE source_account = $1
+		AND sequence_number = $2
+		AND (valid_until IS NULL OR valid_until >= NOW())
+	`
+	recorded := recordedRevision{}
+	err := h.db.QueryRowContext(ctx, q, sourceAccount, sequenceNumber).Scan(&recorded.OriginalTxHash, &recorded.RevisedTx, &recorded.Message)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "querying revised_transactions")
+	}
+	return &recorded, nil
+}
+
+// recordRevisedTransaction records the signed revision of the transaction
+// whose hash is originalTxHash for its source account and sequence number,
+// so that the server does not sign different revisions of transactions which
+// could all be submitted with the same sequence number, letting the sender
+// choose among them. It returns the revision recorded for the source account
+// and sequence number, which is not revisedTx if a revision was already
+// recorded and its time bounds have not expired.
+func (h txApproveHandler) recordRevisedTransaction(ctx context.Context, originalTxHash string, revisedTx *txnbuild.Transaction, message string) (*recordedRevision, error) {
+	revisedTxHash, err := revisedTx.HashHex(h.networkPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing revised transaction")
+	}
+	revisedTxe, err := revisedTx.Base64()
 	if err != nil {
 		return nil, errors.Wrap(err, "encoding revised transaction")
 	}
+	var validUntil *time.Time
+	if maxTime := revisedTx.Timebounds().MaxTime; maxTime != 0 {
+		t := time.Unix(maxTime, 0).UTC()
+		validUntil = &t
+	}
 
-	return NewRevisedTxApprovalResponse(txe), nil
+	// A revision already recorded for the source account and sequence number
+	// is only replaced once expired, even by a revision of the same
+	// transaction, so that concurrent requests return the same revision.
+	const q = `
+		WITH upserted AS (
+			INSERT INTO revised_transactions (source_account, sequence_number, tx_hash, valid_until, original_tx_hash, revised_tx, revision_message)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (source_account, sequence_number) DO UPDATE
+			SET tx_hash = EXCLUDED.tx_hash, valid_until = EXCLUDED.valid_until, created_at = NOW(),
+				original_tx_hash = EXCLUDED.original_tx_hash, revised_tx = EXCLUDED.revised_tx,
+				revision_message = EXCLUDED.revision_message
+			WHERE revised_transactions.valid_until < NOW()
+			RETURNING original_tx_hash, revised_tx, revision_message
+		)
+		SELECT original_tx_hash, revised_tx, revision_message FROM upserted
+	`
+	recorded := recordedRevision{}
+	err = h.db.QueryRowContext(ctx, q,
+		revisedTx.SourceAccount().AccountID, revisedTx.SourceAccount().Sequence, revisedTxHash, validUntil,
+		originalTxHash, revisedTxe, message,
+	).Scan(&recorded.OriginalTxHash, &recorded.RevisedTx, &recorded.Message)
+	if err == sql.ErrNoRows {
+		existing, err := h.findRevisedTransaction(ctx, revisedTx.SourceAccount().AccountID, revisedTx.SourceAccount().Sequence)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, errors.New("the recorded revised transaction expired while recording a new one")
+		}
+		return existing, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "inserting revised transaction")
+	}
+	return &recorded, nil
+}
+
+// recordAuditLog records the decision taken on the submitted transaction in
+// the tx_approve_audit_log table, along with the state it depends on, so
+// that it can be reviewed and replayed later. Failing to record it is logged
+// but does not fail the request.
+func (h txApproveHandler) recordAuditLog(ctx context.Context, in txApproveRequest, resp *txApprovalResponse, state *decisionState) {
+	response, err := json.Marshal(resp)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "encoding the tx-approve response for the audit log"))
+		return
+	}
+	stateJSON, err := json.Marshal(state)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "encoding the tx-approve decision state for the audit log"))
+		return
+	}
+
+	const q = `
+		INSERT INTO tx_approve_audit_log (tx, status, response, state, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err = h.db.ExecContext(ctx, q, in.Tx, string(resp.Status), string(response), string(stateJSON), state.Time)
+	if err != nil {
+		log.Ctx(ctx).Error(errors.Wrap(err, "inserting row into tx_approve_audit_log table"))
+	}
 }
 
 // handleKYCRequiredOperationIfNeeded validates and returns an action_required response if the payment requires KYC.
-func (h txApproveHandler) handleKYCRequiredOperationIfNeeded(ctx context.Context, stellarAddress string, paymentOp *txnbuild.Payment) (*txApprovalResponse, error) {
+func (h txApproveHandler) handleKYCRequiredOperationIfNeeded(ctx context.Context, stellarAddress string, paymentOp *paymentOperation, state *decisionState) (*txApprovalResponse, error) {
 	// validate payment operation against KYC condition(s).
 	KYCRequiredMessage, err := h.kycRequiredMessageIfNeeded(paymentOp)
 	if err != nil {
@@ -251,6 +479,47 @@ func (h txApproveHandler) handleKYCRequiredOperationIfNeeded(ctx context.Context
 		return nil, nil
 	}
 
+	kyc, err := h.kycStatus(ctx, stellarAddress, state)
+	if err != nil {
+		return nil, err
+	}
+
+	if kyc.Approved {
+		return nil, nil
+	}
+	if kyc.Rejected {
+		asset, ok := h.findAsset(paymentOp.asset.GetCode(), paymentOp.asset.GetIssuer())
+		if !ok {
+			return nil, errors.Errorf("asset %s is not regulated by this server", paymentOp.asset.GetCode())
+		}
+		kycThreshold, err := convertThresholdToReadableString(asset.kycThreshold)
+		if err != nil {
+			return nil, errors.Wrap(err, "converting kycThreshold to human readable string")
+		}
+		return NewRejectedTxApprovalResponse(fmt.Sprintf("Your KYC was rejected and you're not authorized for operations above %s %s.", kycThreshold, asset.code)), nil
+	}
+	if kyc.InReview {
+		return NewPendingTxApprovalResponse("Your KYC is being reviewed, please try again later.", h.kycPendingTimeout), nil
+	}
+
+	return NewActionRequiredTxApprovalResponse(
+		KYCRequiredMessage,
+		fmt.Sprintf("%s/kyc-status/%s", h.baseURL, kyc.CallbackID),
+		[]string{"email_address"},
+	), nil
+}
+
+// kycStatus returns the KYC status of the account, which is created if it
+// has none, and keeps it in state. When replaying a decision, it returns the
+// status recorded in state.
+func (h txApproveHandler) kycStatus(ctx context.Context, stellarAddress string, state *decisionState) (*decisionKYCStatus, error) {
+	if state.replayed {
+		if state.KYC == nil {
+			return nil, errors.Errorf("the replayed decision depends on the KYC status of account %s, which was not recorded", stellarAddress)
+		}
+		return state.KYC, nil
+	}
+
 	intendedCallbackID := uuid.New().String()
 	const q = `
 		WITH new_row AS (
@@ -259,52 +528,47 @@ func (h txApproveHandler) handleKYCRequiredOperationIfNeeded(ctx context.Context
 			ON CONFLICT(stellar_address) DO NOTHING
 			RETURNING *
 		)
-		SELECT callback_id, approved_at, rejected_at FROM new_row
+		SELECT callback_id, approved_at, rejected_at, kyc_case_id FROM new_row
 		UNION
-		SELECT callback_id, approved_at, rejected_at
+		SELECT callback_id, approved_at, rejected_at, kyc_case_id
 		FROM accounts_kyc_status
 		WHERE stellar_address = $1
 	`
 	var (
 		callbackID             string
 		approvedAt, rejectedAt sql.NullTime
+		kycCaseID              sql.NullString
 	)
-	err = h.db.QueryRowContext(ctx, q, stellarAddress, intendedCallbackID).Scan(&callbackID, &approvedAt, &rejectedAt)
+	err := h.db.QueryRowContext(ctx, q, stellarAddress, intendedCallbackID).Scan(&callbackID, &approvedAt, &rejectedAt, &kycCaseID)
 	if err != nil {
 		return nil, errors.Wrap(err, "inserting new row into accounts_kyc_status table")
 	}
-
-	if approvedAt.Valid {
-		return nil, nil
-	}
-	if rejectedAt.Valid {
-		kycThreshold, err := convertThresholdToReadableString(h.kycThreshold)
-		if err != nil {
-			return nil, errors.Wrap(err, "converting kycThreshold to human readable string")
-		}
-		return NewRejectedTxApprovalResponse(fmt.Sprintf("Your KYC was rejected and you're not authorized for operations above %s %s.", kycThreshold, h.assetCode)), nil
+	state.KYC = &decisionKYCStatus{
+		CallbackID: callbackID,
+		Approved:   approvedAt.Valid,
+		Rejected:   rejectedAt.Valid,
+		InReview:   kycCaseID.Valid,
 	}
-
-	return NewActionRequiredTxApprovalResponse(
-		KYCRequiredMessage,
-		fmt.Sprintf("%s/kyc-status/%s", h.baseURL, callbackID),
-		[]string{"email_address"},
-	), nil
+	return state.KYC, nil
 }
 
 // kycRequiredMessageIfNeeded returns a "action_required" message for the NewActionRequiredTxApprovalResponse if the payment operation meets KYC conditions.
-// Currently rule(s) are, checking if payment amount is > KYCThreshold amount.
-func (h txApproveHandler) kycRequiredMessageIfNeeded(paymentOp *txnbuild.Payment) (string, error) {
-	paymentAmount, err := amount.ParseInt64(paymentOp.Amount)
+// Currently rule(s) are, checking if payment amount is > the KYC threshold of the payment asset.
+func (h txApproveHandler) kycRequiredMessageIfNeeded(paymentOp *paymentOperation) (string, error) {
+	asset, ok := h.findAsset(paymentOp.asset.GetCode(), paymentOp.asset.GetIssuer())
+	if !ok {
+		return "", errors.Errorf("asset %s is not regulated by this server", paymentOp.asset.GetCode())
+	}
+	paymentAmount, err := amount.ParseInt64(paymentOp.amount)
 	if err != nil {
 		return "", errors.Wrap(err, "parsing account payment amount from string to Int64")
 	}
-	if paymentAmount > h.kycThreshold {
-		kycThreshold, err := convertThresholdToReadableString(h.kycThreshold)
+	if paymentAmount > asset.kycThreshold {
+		kycThreshold, err := convertThresholdToReadableString(asset.kycThreshold)
 		if err != nil {
 			return "", errors.Wrap(err, "converting kycThreshold to human readable string")
 		}
-		return fmt.Sprintf(`Payments exceeding %s %s requires KYC approval. Please provide an email address.`, kycThreshold, h.assetCode), nil
+		return fmt.Sprintf(`Payments exceeding %s %s requires KYC approval. Please provide an email address.`, kycThreshold, asset.code), nil
 	}
 	return "", nil
 }
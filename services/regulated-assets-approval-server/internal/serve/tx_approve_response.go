@@ -2,6 +2,7 @@ package serve
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/stellar/go/support/render/httpjson"
 )
@@ -15,6 +16,9 @@ type txApprovalResponse struct {
 	ActionURL    string     `json:"action_url,omitempty"`
 	ActionMethod string     `json:"action_method,omitempty"`
 	ActionFields []string   `json:"action_fields,omitempty"`
+	// Timeout is the number of milliseconds to wait before submitting the
+	// transaction again, set for pending responses only.
+	Timeout *int64 `json:"timeout,omitempty"`
 }
 
 func (t *txApprovalResponse) Render(w http.ResponseWriter) {
@@ -29,12 +33,12 @@ func NewRejectedTxApprovalResponse(errMessage string) *txApprovalResponse {
 	}
 }
 
-func NewRevisedTxApprovalResponse(tx string) *txApprovalResponse {
+func NewRevisedTxApprovalResponse(tx, message string) *txApprovalResponse {
 	return &txApprovalResponse{
 		Status:     sep8StatusRevised,
 		Tx:         tx,
 		StatusCode: http.StatusOK,
-		Message:    "Authorization and deauthorization operations were added.",
+		Message:    message,
 	}
 }
 
@@ -49,10 +53,24 @@ func NewActionRequiredTxApprovalResponse(message, actionURL string, actionFields
 	}
 }
 
+// NewPendingTxApprovalResponse returns a pending response, telling the
+// wallet to submit the transaction again after timeout, or at its discretion
+// if timeout is 0.
+func NewPendingTxApprovalResponse(message string, timeout time.Duration) *txApprovalResponse {
+	timeoutMillis := int64(timeout / time.Millisecond)
+	return &txApprovalResponse{
+		Status:     sep8StatusPending,
+		Message:    message,
+		StatusCode: http.StatusOK,
+		Timeout:    &timeoutMillis,
+	}
+}
+
 type sep8Status string
 
 const (
 	sep8StatusRejected       sep8Status = "rejected"
 	sep8StatusRevised        sep8Status = "revised"
 	sep8StatusActionRequired sep8Status = "action_required"
+	sep8StatusPending        sep8Status = "pending"
 )
@@ -2,8 +2,10 @@ package serve
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizonclient"
@@ -171,7 +173,7 @@ func TestTxApproveHandlerKYCRequiredMessageIfNeeded(t *testing.T) {
 	}
 
 	// TEST No KYC needed response. actionRequiredMessage should be "".
-	actionRequiredMessage, err := h.kycRequiredMessageIfNeeded(&paymentOP)
+	actionRequiredMessage, err := h.kycRequiredMessageIfNeeded(&paymentOperation{asset: paymentOP.Asset, amount: paymentOP.Amount})
 	require.NoError(t, err)
 	require.Empty(t, actionRequiredMessage)
 
@@ -183,7 +185,7 @@ func TestTxApproveHandlerKYCRequiredMessageIfNeeded(t *testing.T) {
 	}
 
 	// TEST kycRequiredMessageIfNeeded returns error.
-	_, err = h.kycRequiredMessageIfNeeded(&paymentOP)
+	_, err = h.kycRequiredMessageIfNeeded(&paymentOperation{asset: paymentOP.Asset, amount: paymentOP.Amount})
 	assert.Contains(t,
 		err.Error(),
 		`parsing account payment amount from string to Int64: invalid amount format: ten`,
@@ -198,7 +200,7 @@ func TestTxApproveHandlerKYCRequiredMessageIfNeeded(t *testing.T) {
 
 	// TEST Successful KYC required response.
 	// actionRequiredMessage should return "Payments exceeding [kycThreshold] [assetCode] requires KYC approval..." message.
-	actionRequiredMessage, err = h.kycRequiredMessageIfNeeded(&paymentOP)
+	actionRequiredMessage, err = h.kycRequiredMessageIfNeeded(&paymentOperation{asset: paymentOP.Asset, amount: paymentOP.Amount})
 	require.NoError(t, err)
 	assert.Equal(t, `Payments exceeding 500.00 GOAT requires KYC approval. Please provide an email address.`, actionRequiredMessage)
 }
@@ -244,7 +246,7 @@ func TestTxApproveHandlerHandleKYCRequiredOperationIfNeeded(t *testing.T) {
 	}
 
 	// TEST successful "action_required" response.
-	actionRequiredTxApprovalResponse, err := h.handleKYCRequiredOperationIfNeeded(ctx, sourceKP.Address(), &paymentOP)
+	actionRequiredTxApprovalResponse, err := h.handleKYCRequiredOperationIfNeeded(ctx, sourceKP.Address(), &paymentOperation{asset: paymentOP.Asset, amount: paymentOP.Amount}, &decisionState{Time: time.Now()})
 	require.NoError(t, err)
 	wantTXApprovalResponse := txApprovalResponse{
 		Status:       sep8Status("action_required"),
@@ -266,6 +268,36 @@ func TestTxApproveHandlerHandleKYCRequiredOperationIfNeeded(t *testing.T) {
 	err = h.db.QueryRowContext(ctx, q, sourceKP.Address()).Scan(&stellarAddress)
 	require.NoError(t, err)
 	assert.Equal(t, sourceKP.Address(), stellarAddress)
+
+	// TEST "pending" response while the KYC provider reviews the case.
+	h.kycPendingTimeout = time.Minute
+	_, err = h.db.ExecContext(ctx, "UPDATE accounts_kyc_status SET kyc_submitted_at = NOW(), kyc_case_id = 'case-1' WHERE stellar_address = $1", sourceKP.Address())
+	require.NoError(t, err)
+	pendingTimeout := int64(60000)
+	pendingTxApprovalResponse, err := h.handleKYCRequiredOperationIfNeeded(ctx, sourceKP.Address(), &paymentOperation{asset: paymentOP.Asset, amount: paymentOP.Amount}, &decisionState{Time: time.Now()})
+	require.NoError(t, err)
+	wantTXApprovalResponse = txApprovalResponse{
+		Status:     sep8Status("pending"),
+		Message:    "Your KYC is being reviewed, please try again later.",
+		StatusCode: http.StatusOK,
+		Timeout:    &pendingTimeout,
+	}
+	assert.Equal(t, &wantTXApprovalResponse, pendingTxApprovalResponse)
+}
+
+func TestNewPendingTxApprovalResponse(t *testing.T) {
+	body, err := json.Marshal(NewPendingTxApprovalResponse("Please try again later.", 90*time.Second))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"pending","message":"Please try again later.","timeout":90000}`, string(body))
+
+	// the timeout is required, 0 when unknown
+	body, err = json.Marshal(NewPendingTxApprovalResponse("Please try again later.", 0))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"pending","message":"Please try again later.","timeout":0}`, string(body))
+
+	body, err = json.Marshal(NewRejectedTxApprovalResponse("Invalid transaction."))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"rejected","error":"Invalid transaction."}`, string(body))
 }
 
 func TestTxApproveHandlerTxApprove(t *testing.T) {
@@ -522,7 +554,7 @@ func TestTxApproveHandlerTxApprove(t *testing.T) {
 	require.NoError(t, err)
 	wantRejectedResponse = txApprovalResponse{
 		Status:     "rejected",
-		Error:      "Please submit a transaction with exactly one operation of type payment.",
+		Error:      "Please submit a transaction with exactly one operation of type payment, path_payment_strict_send or path_payment_strict_receive.",
 		StatusCode: http.StatusBadRequest,
 	}
 	assert.Equal(t, &wantRejectedResponse, rejectedResponse)
@@ -564,3 +596,74 @@ func TestTxApproveHandlerTxApprove(t *testing.T) {
 	}
 	assert.Equal(t, &wantRejectedResponse, rejectedResponse)
 }
+
+func TestTxApproveHandlerRecordRevisedTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Open(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	h := txApproveHandler{
+		networkPassphrase: network.TestNetworkPassphrase,
+		db:                conn,
+	}
+
+	senderKP := keypair.MustRandom()
+	receiverKP := keypair.MustRandom()
+	newTx := func(amount string, timebounds txnbuild.Timebounds) *txnbuild.Transaction {
+		tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+			SourceAccount: &txnbuild.SimpleAccount{AccountID: senderKP.Address(), Sequence: 6},
+			Operations: []txnbuild.Operation{
+				&txnbuild.Payment{Destination: receiverKP.Address(), Amount: amount, Asset: txnbuild.NativeAsset{}},
+			},
+			BaseFee:    txnbuild.MinBaseFee,
+			Timebounds: timebounds,
+		})
+		require.NoError(t, err)
+		return tx
+	}
+
+	base64 := func(tx *txnbuild.Transaction) string {
+		txe, err := tx.Base64()
+		require.NoError(t, err)
+		return txe
+	}
+
+	recorded, err := h.findRevisedTransaction(ctx, senderKP.Address(), 6)
+	require.NoError(t, err)
+	assert.Nil(t, recorded)
+
+	// The first revision is recorded.
+	revision1 := newTx("1", txnbuild.NewInfiniteTimeout())
+	recorded, err = h.recordRevisedTransaction(ctx, "original-a", revision1, "message")
+	require.NoError(t, err)
+	assert.Equal(t, recordedRevision{OriginalTxHash: "original-a", RevisedTx: base64(revision1), Message: "message"}, *recorded)
+	recorded, err = h.findRevisedTransaction(ctx, senderKP.Address(), 6)
+	require.NoError(t, err)
+	assert.Equal(t, recordedRevision{OriginalTxHash: "original-a", RevisedTx: base64(revision1), Message: "message"}, *recorded)
+
+	// Another revision of the same transaction returns the recorded one.
+	recorded, err = h.recordRevisedTransaction(ctx, "original-a", newTx("1", txnbuild.NewTimeout(300)), "message")
+	require.NoError(t, err)
+	assert.Equal(t, base64(revision1), recorded.RevisedTx)
+
+	// The revision of a different transaction is not recorded while the
+	// first one is valid.
+	recorded, err = h.recordRevisedTransaction(ctx, "original-b", newTx("2", txnbuild.NewInfiniteTimeout()), "")
+	require.NoError(t, err)
+	assert.Equal(t, "original-a", recorded.OriginalTxHash)
+
+	// A different revision is recorded once the recorded one expired.
+	_, err = conn.Exec("UPDATE revised_transactions SET valid_until = NOW() - INTERVAL '1 minute'")
+	require.NoError(t, err)
+	recorded, err = h.findRevisedTransaction(ctx, senderKP.Address(), 6)
+	require.NoError(t, err)
+	assert.Nil(t, recorded)
+	revision2 := newTx("2", txnbuild.NewTimeout(300))
+	recorded, err = h.recordRevisedTransaction(ctx, "original-b", revision2, "")
+	require.NoError(t, err)
+	assert.Equal(t, recordedRevision{OriginalTxHash: "original-b", RevisedTx: base64(revision2)}, *recorded)
+	recorded, err = h.recordRevisedTransaction(ctx, "original-c", newTx("3", txnbuild.NewTimeout(300)), "")
+	require.NoError(t, err)
+	assert.Equal(t, "original-b", recorded.OriginalTxHash)
+}
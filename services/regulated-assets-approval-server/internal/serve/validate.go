@@ -0,0 +1,172 @@
+package serve
+
+import (
+	"net/url"
+	"strings"
+
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db"
+	"github.com/stellar/go/services/regulated-assets-approval-server/internal/db/dbmigrate"
+	"github.com/stellar/go/support/errors"
+	supporthttp "github.com/stellar/go/support/http"
+)
+
+// ValidateOptions checks the options the server would be started with, and
+// returns the problems found. Besides the values of the options, it checks
+// that the database is reachable and fully migrated, and that the signing key
+// of each regulated asset can sign the revised transactions of its issuer
+// account.
+func ValidateOptions(opts Options) []error {
+	assets, errs := opts.validate()
+	if len(errs) > 0 {
+		return errs
+	}
+	errs = append(errs, validateDatabase(opts.DatabaseURL)...)
+	errs = append(errs, validateIssuerSigners(opts.horizonClient(), assets)...)
+	return errs
+}
+
+// validate checks the values of the options and returns the regulated assets
+// they configure.
+func (opts Options) validate() ([]regulatedAsset, []error) {
+	var errs []error
+	asset := regulatedAsset{code: opts.AssetCode, issuerAddress: opts.IssuerAccountAddress}
+
+	if opts.AssetCode == "" {
+		errs = append(errs, errors.New("asset-code cannot be empty"))
+	}
+	var err error
+	asset.issuerKP, err = keypair.ParseFull(opts.IssuerAccountSecret)
+	if err != nil {
+		errs = append(errs, errors.New("issuer-account-secret is not a valid Stellar secret key"))
+	}
+	if opts.IssuerAccountAddress != "" {
+		if _, err = keypair.ParseAddress(opts.IssuerAccountAddress); err != nil {
+			errs = append(errs, errors.New("issuer-account-address is not a valid Stellar address"))
+		}
+	}
+	asset.kycThreshold, err = amount.ParseInt64(opts.KYCRequiredPaymentAmountThreshold)
+	if err != nil || asset.kycThreshold <= 0 {
+		errs = append(errs, errors.New("kyc-required-payment-amount-threshold must be an amount greater than zero"))
+	}
+	var additionalAssets []regulatedAsset
+	issuerKPs, err := readIssuerSecrets(opts.IssuerSecretsFile)
+	if err != nil {
+		errs = append(errs, errors.Wrap(err, "issuer-secrets-file is invalid"))
+	} else if additionalAssets, err = parseRegulatedAssets(opts.AdditionalRegulatedAssets, issuerKPs); err != nil {
+		errs = append(errs, errors.Wrap(err, "additional-regulated-assets is invalid"))
+	}
+
+	for _, u := range []struct {
+		name, value string
+		optional    bool
+	}{
+		{"base-url", opts.BaseURL, false},
+		{"horizon-url", opts.HorizonURL, false},
+		{"kyc-provider-url", opts.KYCProviderURL, true},
+	} {
+		if u.value == "" && u.optional {
+			continue
+		}
+		parsed, err := url.Parse(u.value)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			errs = append(errs, errors.Errorf("%s must be an absolute URL", u.name))
+		}
+	}
+	if opts.NetworkPassphrase == "" {
+		errs = append(errs, errors.New("network-passphrase cannot be empty"))
+	}
+	if opts.Port <= 0 || opts.Port > 65535 {
+		errs = append(errs, errors.New("port must be between 1 and 65535"))
+	}
+	if opts.GRPCPort < 0 || opts.GRPCPort > 65535 || opts.GRPCPort == opts.Port {
+		errs = append(errs, errors.New("grpc-port must be 0 or between 1 and 65535, and different from port"))
+	}
+	if opts.GRPCPort != 0 && opts.GRPCAuthToken == "" {
+		errs = append(errs, errors.New("grpc-auth-token cannot be empty when grpc-port is set"))
+	}
+	if opts.FriendbotPaymentAmount <= 0 {
+		errs = append(errs, errors.New("friendbot-payment-amount must be greater than zero"))
+	}
+	if opts.KYCProviderPollInterval < 0 {
+		errs = append(errs, errors.New("kyc-provider-poll-interval cannot be negative"))
+	}
+	if opts.AuditLogRetentionDays < 0 {
+		errs = append(errs, errors.New("audit-log-retention-days cannot be negative"))
+	}
+	if opts.RateLimitPerMinute < 0 || opts.RateLimitBurst < 0 {
+		errs = append(errs, errors.New("rate-limit-per-minute and rate-limit-burst cannot be negative"))
+	}
+	if opts.RateLimitRedisURL != "" {
+		if u, err := url.Parse(opts.RateLimitRedisURL); err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") {
+			errs = append(errs, errors.New("rate-limit-redis-url must be a redis:// or rediss:// URL"))
+		}
+	}
+	if _, err = supporthttp.ParseTrustedProxies(opts.TrustedProxies); err != nil {
+		errs = append(errs, errors.Wrap(err, "trusted-proxies is invalid"))
+	}
+	adminAPIKeys, err := opts.adminAPIKeys()
+	if err != nil {
+		errs = append(errs, errors.Wrap(err, "admin-api-keys is invalid"))
+	} else if opts.ClawbackApprovalRequired && (opts.AdminAPIKey != "" || len(adminAPIKeys) < 2) {
+		errs = append(errs, errors.New("clawback-approval-required requires admin-api-keys with at least two admins, and admin-api-key to be empty"))
+	}
+	if _, err = opts.revisionStrategy(); err != nil {
+		errs = append(errs, errors.Wrap(err, "revision-strategy is invalid"))
+	}
+
+	return append([]regulatedAsset{asset}, additionalAssets...), errs
+}
+
+// validateDatabase checks that the database is reachable and that all the
+// migrations were applied.
+func validateDatabase(databaseURL string) []error {
+	conn, err := db.Open(databaseURL)
+	if err != nil {
+		return []error{errors.Wrap(err, "opening database")}
+	}
+	defer conn.Close()
+	if err = conn.Ping(); err != nil {
+		return []error{errors.Wrap(err, "connecting to database")}
+	}
+
+	migrations, err := dbmigrate.PlanMigration(conn, migrate.Up, 0)
+	if err != nil {
+		return []error{errors.Wrap(err, "planning database migrations")}
+	}
+	if len(migrations) > 0 {
+		return []error{errors.Errorf("database migrations not applied: %s", strings.Join(migrations, ", "))}
+	}
+	return nil
+}
+
+// validateIssuerSigners checks that the signing key of each asset is a
+// signer of its issuer account, with a weight reaching the low threshold
+// required by the authorization operations of the revised transactions.
+func validateIssuerSigners(horizonClient horizonclient.ClientInterface, assets []regulatedAsset) []error {
+	var errs []error
+	for _, asset := range assets {
+		account, err := horizonClient.AccountDetail(horizonclient.AccountRequest{AccountID: asset.issuer()})
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "getting detail for issuer account %s of %s", asset.issuer(), asset.code))
+			continue
+		}
+
+		var weight int32
+		for _, s := range account.Signers {
+			if s.Key == asset.issuerKP.Address() {
+				weight = s.Weight
+			}
+		}
+		if weight == 0 || weight < int32(account.Thresholds.LowThreshold) {
+			errs = append(errs, errors.Errorf(
+				"the signing key %s of %s does not have enough weight on issuer account %s: %d, the low threshold is %d",
+				asset.issuerKP.Address(), asset.code, asset.issuer(), weight, account.Thresholds.LowThreshold,
+			))
+		}
+	}
+	return errs
+}
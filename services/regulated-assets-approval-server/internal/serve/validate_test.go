@@ -0,0 +1,122 @@
+package serve
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	issuerKP := keypair.MustRandom()
+	barKP := keypair.MustRandom()
+	secretsFile, err := ioutil.TempFile("", "issuer-secrets")
+	require.NoError(t, err)
+	defer os.Remove(secretsFile.Name())
+	_, err = secretsFile.WriteString(barKP.Seed() + "\n")
+	require.NoError(t, err)
+	require.NoError(t, secretsFile.Close())
+
+	opts := Options{
+		AdditionalRegulatedAssets:         "BAR:" + barKP.Address() + ":10",
+		AssetCode:                         "FOO",
+		BaseURL:                           "https://sep8-server.test",
+		FriendbotPaymentAmount:            10000,
+		HorizonURL:                        "https://horizon-testnet.stellar.org/",
+		IssuerAccountSecret:               issuerKP.Seed(),
+		IssuerSecretsFile:                 secretsFile.Name(),
+		KYCRequiredPaymentAmountThreshold: "500",
+		NetworkPassphrase:                 network.TestNetworkPassphrase,
+		Port:                              8000,
+	}
+
+	assets, errs := opts.validate()
+	require.Empty(t, errs)
+	require.Len(t, assets, 2)
+	assert.Equal(t, "FOO", assets[0].code)
+	assert.Equal(t, issuerKP.Address(), assets[0].issuer())
+	assert.Equal(t, int64(5000000000), assets[0].kycThreshold)
+	assert.Equal(t, "BAR", assets[1].code)
+
+	opts.IssuerAccountAddress = barKP.Address()
+	assets, errs = opts.validate()
+	require.Empty(t, errs)
+	assert.Equal(t, barKP.Address(), assets[0].issuer())
+	assert.Equal(t, issuerKP.Address(), assets[0].issuerKP.Address())
+
+	_, errs = Options{
+		AdditionalRegulatedAssets:         "BAR",
+		IssuerAccountSecret:               issuerKP.Address(),
+		IssuerAccountAddress:              "GABC",
+		KYCRequiredPaymentAmountThreshold: "0",
+		BaseURL:                           "sep8-server.test",
+		KYCProviderURL:                    "/kyc",
+		FriendbotPaymentAmount:            -1,
+		Port:                              8000,
+		GRPCPort:                          8000,
+		KYCProviderPollInterval:           -1,
+		AuditLogRetentionDays:             -1,
+		RateLimitBurst:                    -1,
+		AdminAPIKey:                       "admin-key",
+		ClawbackApprovalRequired:          true,
+		RevisionStrategyName:              "escrow",
+	}.validate()
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	assert.Equal(t, []string{
+		"asset-code cannot be empty",
+		"issuer-account-secret is not a valid Stellar secret key",
+		"issuer-account-address is not a valid Stellar address",
+		"kyc-required-payment-amount-threshold must be an amount greater than zero",
+		`additional-regulated-assets is invalid: regulated asset "BAR" must have the CODE:ISSUER_ADDRESS:KYC_THRESHOLD[:SIGNER_ADDRESS] format`,
+		"base-url must be an absolute URL",
+		"horizon-url must be an absolute URL",
+		"kyc-provider-url must be an absolute URL",
+		"network-passphrase cannot be empty",
+		"grpc-port must be 0 or between 1 and 65535, and different from port",
+		"grpc-auth-token cannot be empty when grpc-port is set",
+		"friendbot-payment-amount must be greater than zero",
+		"kyc-provider-poll-interval cannot be negative",
+		"audit-log-retention-days cannot be negative",
+		"rate-limit-per-minute and rate-limit-burst cannot be negative",
+		"clawback-approval-required requires admin-api-keys with at least two admins, and admin-api-key to be empty",
+		`revision-strategy is invalid: unknown revision strategy "escrow"`,
+	}, msgs)
+}
+
+func TestValidateIssuerSigners(t *testing.T) {
+	issuerKP := keypair.MustRandom()
+	signerKP := keypair.MustRandom()
+	horizonMock := horizonclient.MockClient{}
+	horizonMock.
+		On("AccountDetail", horizonclient.AccountRequest{AccountID: issuerKP.Address()}).
+		Return(horizon.Account{
+			AccountID:  issuerKP.Address(),
+			Thresholds: horizon.AccountThresholds{LowThreshold: 2},
+			Signers: []horizon.Signer{
+				{Key: issuerKP.Address(), Weight: 0},
+				{Key: signerKP.Address(), Weight: 2},
+			},
+		}, nil)
+
+	// The signing key has enough weight.
+	errs := validateIssuerSigners(&horizonMock, []regulatedAsset{
+		{code: "FOO", issuerKP: signerKP, issuerAddress: issuerKP.Address()},
+	})
+	assert.Empty(t, errs)
+
+	// The master key of the issuer account was disabled.
+	errs = validateIssuerSigners(&horizonMock, []regulatedAsset{
+		{code: "FOO", issuerKP: issuerKP},
+	})
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "the signing key "+issuerKP.Address()+" of FOO does not have enough weight on issuer account "+issuerKP.Address()+": 0, the low threshold is 2")
+}
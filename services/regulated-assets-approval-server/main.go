@@ -21,6 +21,10 @@ func main() {
 
 	rootCmd.AddCommand((&cmd.MigrateCommand{}).Command())
 	rootCmd.AddCommand((&cmd.ServeCommand{}).Command())
+	rootCmd.AddCommand((&cmd.RotateIssuerKeyCommand{}).Command())
+	rootCmd.AddCommand((&cmd.KYCCommand{}).Command())
+	rootCmd.AddCommand((&cmd.ReplayCommand{}).Command())
+	rootCmd.AddCommand((&cmd.ValidateConfigCommand{}).Command())
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -4,16 +4,18 @@ import (
 	"context"
 	"time"
 
-	"github.com/lib/pq"
 	"github.com/spf13/cobra"
+	ticker "github.com/stellar/go/services/ticker/internal"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
 )
 
 var DaysToKeep int
+var CandleRetention string
 
 func init() {
 	rootCmd.AddCommand(cmdClean)
 	cmdClean.AddCommand(cmdCleanTrades)
+	cmdClean.AddCommand(cmdCleanCandles)
 
 	cmdCleanTrades.Flags().IntVarP(
 		&DaysToKeep,
@@ -22,6 +24,13 @@ func init() {
 		7,
 		"Trade entries older than keep-days will be deleted",
 	)
+
+	cmdCleanCandles.Flags().StringVar(
+		&CandleRetention,
+		"retention",
+		ticker.DefaultCandleRetention,
+		"Comma-separated <resolution>=<days> list of the number of days candles are kept for; candles of resolutions not listed are kept forever",
+	)
 }
 
 var cmdClean = &cobra.Command{
@@ -33,15 +42,11 @@ var cmdCleanTrades = &cobra.Command{
 	Use:   "trades",
 	Short: "Cleans up old trades from the database",
 	Run: func(cmd *cobra.Command, args []string) {
-		dbInfo, err := pq.ParseURL(DatabaseURL)
-		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
-		}
-
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
+		defer session.Close()
 
 		now := time.Now()
 		minDate := now.AddDate(0, 0, -DaysToKeep)
@@ -52,3 +57,25 @@ var cmdCleanTrades = &cobra.Command{
 		}
 	},
 }
+
+var cmdCleanCandles = &cobra.Command{
+	Use:   "candles",
+	Short: "Cleans up old candles from the database",
+	Run: func(cmd *cobra.Command, args []string) {
+		retention, err := ticker.ParseCandleRetention(CandleRetention)
+		if err != nil {
+			Logger.Fatal("could not parse retention:", err)
+		}
+
+		session, err := tickerdb.Open(DatabaseURL)
+		if err != nil {
+			Logger.Fatal("could not connect to db:", err)
+		}
+		defer session.Close()
+
+		err = ticker.CleanCandles(context.Background(), session, Logger, retention)
+		if err != nil {
+			Logger.Fatal("could not delete candles:", err)
+		}
+	},
+}
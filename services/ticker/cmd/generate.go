@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"github.com/lib/pq"
 	"github.com/spf13/cobra"
 	ticker "github.com/stellar/go/services/ticker/internal"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
@@ -42,18 +41,14 @@ var cmdGenerateMarketData = &cobra.Command{
 	Use:   "market-data",
 	Short: "Generate the aggregated market data (for 24h and 7d) and outputs to a file.",
 	Run: func(cmd *cobra.Command, args []string) {
-		dbInfo, err := pq.ParseURL(DatabaseURL)
-		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
-		}
-
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
+		defer session.Close()
 
 		Logger.Infof("Starting market data generation, outputting to: %s\n", MarketsOutFile)
-		err = ticker.GenerateMarketSummaryFile(&session, Logger, MarketsOutFile)
+		err = ticker.GenerateMarketSummaryFile(session, Logger, MarketsOutFile)
 		if err != nil {
 			Logger.Fatal("could not generate market data:", err)
 		}
@@ -64,18 +59,14 @@ var cmdGenerateAssetData = &cobra.Command{
 	Use:   "asset-data",
 	Short: "Generate the aggregated asset data and outputs to a file.",
 	Run: func(cmd *cobra.Command, args []string) {
-		dbInfo, err := pq.ParseURL(DatabaseURL)
-		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
-		}
-
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
+		defer session.Close()
 
 		Logger.Infof("Starting asset data generation, outputting to: %s\n", AssetsOutFile)
-		err = ticker.GenerateAssetsFile(context.Background(), &session, Logger, AssetsOutFile)
+		err = ticker.GenerateAssetsFile(context.Background(), session, Logger, AssetsOutFile)
 		if err != nil {
 			Logger.Fatal("could not generate asset data:", err)
 		}
@@ -3,7 +3,6 @@ package cmd
 import (
 	"context"
 
-	"github.com/lib/pq"
 	"github.com/spf13/cobra"
 	ticker "github.com/stellar/go/services/ticker/internal"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
@@ -11,12 +10,14 @@ import (
 
 var ShouldStream bool
 var BackfillHours int
+var CandleHours int
 
 func init() {
 	rootCmd.AddCommand(cmdIngest)
 	cmdIngest.AddCommand(cmdIngestAssets)
 	cmdIngest.AddCommand(cmdIngestTrades)
 	cmdIngest.AddCommand(cmdIngestOrderbooks)
+	cmdIngest.AddCommand(cmdIngestCandles)
 
 	cmdIngestTrades.Flags().BoolVar(
 		&ShouldStream,
@@ -31,6 +32,13 @@ func init() {
 		7*24,
 		"Number of past hours to backfill trade data",
 	)
+
+	cmdIngestCandles.Flags().IntVar(
+		&CandleHours,
+		"num-hours",
+		2,
+		"Number of past hours of trades to compute candles from",
+	)
 }
 
 var cmdIngest = &cobra.Command{
@@ -43,19 +51,14 @@ var cmdIngestAssets = &cobra.Command{
 	Short: "Refreshes the asset database with new data retrieved from Horizon.",
 	Run: func(cmd *cobra.Command, args []string) {
 		Logger.Info("Refreshing the asset database")
-		dbInfo, err := pq.ParseURL(DatabaseURL)
-		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
-		}
-
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
-		defer session.DB.Close()
+		defer session.Close()
 
 		ctx := context.Background()
-		err = ticker.RefreshAssets(ctx, &session, Client, Logger)
+		err = ticker.RefreshAssets(ctx, session, Client, Logger)
 		if err != nil {
 			Logger.Fatal("could not refresh asset database:", err)
 		}
@@ -66,16 +69,11 @@ var cmdIngestTrades = &cobra.Command{
 	Use:   "trades",
 	Short: "Fills the trade database with data retrieved form Horizon.",
 	Run: func(cmd *cobra.Command, args []string) {
-		dbInfo, err := pq.ParseURL(DatabaseURL)
-		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
-		}
-
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
-		defer session.DB.Close()
+		defer session.Close()
 
 		ctx := context.Background()
 		numDays := float32(BackfillHours) / 24.0
@@ -84,14 +82,14 @@ var cmdIngestTrades = &cobra.Command{
 			BackfillHours,
 			numDays,
 		)
-		err = ticker.BackfillTrades(ctx, &session, Client, Logger, BackfillHours, 0)
+		err = ticker.BackfillTrades(ctx, session, Client, Logger, BackfillHours, 0)
 		if err != nil {
 			Logger.Fatal("could not refresh trade database:", err)
 		}
 
 		if ShouldStream {
 			Logger.Info("Streaming new data (this is a continuous process)")
-			err = ticker.StreamTrades(ctx, &session, Client, Logger)
+			err = ticker.StreamTrades(ctx, session, Client, Logger)
 			if err != nil {
 				Logger.Fatal("could not refresh trade database:", err)
 			}
@@ -104,20 +102,34 @@ var cmdIngestOrderbooks = &cobra.Command{
 	Short: "Refreshes the orderbook stats database with new data retrieved from Horizon.",
 	Run: func(cmd *cobra.Command, args []string) {
 		Logger.Info("Refreshing the asset database")
-		dbInfo, err := pq.ParseURL(DatabaseURL)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
+			Logger.Fatal("could not connect to db:", err)
 		}
+		defer session.Close()
 
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		err = ticker.RefreshOrderbookEntries(session, Client, Logger)
+		if err != nil {
+			Logger.Fatal("could not refresh orderbook database:", err)
+		}
+	},
+}
+
+var cmdIngestCandles = &cobra.Command{
+	Use:   "candles",
+	Short: "Computes the OHLCV candles of every market from the trades in the database.",
+	Run: func(cmd *cobra.Command, args []string) {
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
-		defer session.DB.Close()
+		defer session.Close()
 
-		err = ticker.RefreshOrderbookEntries(&session, Client, Logger)
+		ctx := context.Background()
+		Logger.Infof("Computing candles for the past %d hour(s)\n", CandleHours)
+		err = ticker.RefreshCandles(ctx, session, Logger, CandleHours)
 		if err != nil {
-			Logger.Fatal("could not refresh orderbook database:", err)
+			Logger.Fatal("could not compute candles:", err)
 		}
 	},
 }
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"github.com/lib/pq"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
 )
@@ -15,19 +14,14 @@ var cmdMigrate = &cobra.Command{
 	Short: "Updates the database to the latest schema version.",
 	Run: func(cmd *cobra.Command, args []string) {
 		Logger.Info("Refreshing the asset database")
-		dbInfo, err := pq.ParseURL(DatabaseURL)
-		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
-		}
-
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
-		defer session.DB.Close()
+		defer session.Close()
 
 		Logger.Infoln("Upgrading the database")
-		n, err := tickerdb.MigrateDB(&session)
+		n, err := session.Migrate()
 		if err != nil {
 			Logger.Fatal("could not upgrade the database:", err)
 		}
@@ -1,13 +1,16 @@
 package cmd
 
 import (
-	"github.com/lib/pq"
+	"time"
+
 	"github.com/spf13/cobra"
 	ticker "github.com/stellar/go/services/ticker/internal"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
 )
 
 var ServerAddr string
+var StreamInterval int
+var PollInterval int
 
 func init() {
 	rootCmd.AddCommand(cmdServe)
@@ -18,6 +21,18 @@ func init() {
 		"0.0.0.0:3000",
 		"Server address and port",
 	)
+	cmdServe.Flags().IntVar(
+		&StreamInterval,
+		"stream-interval",
+		60,
+		"Interval (in seconds) between market updates pushed to websocket subscribers; 0 disables streaming",
+	)
+	cmdServe.Flags().IntVar(
+		&PollInterval,
+		"poll-interval",
+		5,
+		"Interval (in seconds) between checks for new trades and orderbook stats to push to GraphQL subscribers; 0 disables subscriptions",
+	)
 }
 
 var cmdServe = &cobra.Command{
@@ -25,17 +40,12 @@ var cmdServe = &cobra.Command{
 	Short: "Runs a GraphQL interface to get Ticker data",
 	Run: func(cmd *cobra.Command, args []string) {
 		Logger.Info("Starting GraphQL Server")
-		dbInfo, err := pq.ParseURL(DatabaseURL)
-		if err != nil {
-			Logger.Fatal("could not parse db-url:", err)
-		}
-
-		session, err := tickerdb.CreateSession("postgres", dbInfo)
+		session, err := tickerdb.Open(DatabaseURL)
 		if err != nil {
 			Logger.Fatal("could not connect to db:", err)
 		}
-		defer session.DB.Close()
+		defer session.Close()
 
-		ticker.StartGraphQLServer(&session, Logger, ServerAddr)
+		ticker.StartGraphQLServer(session, Logger, ServerAddr, time.Duration(StreamInterval)*time.Second, time.Duration(PollInterval)*time.Second)
 	},
 }
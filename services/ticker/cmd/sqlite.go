@@ -0,0 +1,9 @@
+// +build sqlite
+
+package cmd
+
+import (
+	// Registers the driver of SQLite databases, which needs cgo, so that it
+	// is only built in with -tags sqlite.
+	_ "github.com/mattn/go-sqlite3"
+)
@@ -14,7 +14,7 @@ import (
 )
 
 // RefreshAssets scrapes the most recent asset list and ingests then into the db.
-func RefreshAssets(ctx context.Context, s *tickerdb.TickerSession, c *horizonclient.Client, l *hlog.Entry) (err error) {
+func RefreshAssets(ctx context.Context, s tickerdb.Store, c *horizonclient.Client, l *hlog.Entry) (err error) {
 	sc := scraper.ScraperConfig{
 		Client: c,
 		Logger: l,
@@ -46,7 +46,7 @@ func RefreshAssets(ctx context.Context, s *tickerdb.TickerSession, c *horizoncli
 }
 
 // GenerateAssetsFile generates a file with the info about all valid scraped Assets
-func GenerateAssetsFile(ctx context.Context, s *tickerdb.TickerSession, l *hlog.Entry, filename string) error {
+func GenerateAssetsFile(ctx context.Context, s tickerdb.Store, l *hlog.Entry, filename string) error {
 	l.Infoln("Retrieving asset data from db...")
 	var assets []Asset
 	validAssets, err := s.GetAssetsWithNestedIssuer(ctx)
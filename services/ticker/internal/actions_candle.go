@@ -0,0 +1,275 @@
+package ticker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/services/ticker/internal/utils"
+	hlog "github.com/stellar/go/support/log"
+)
+
+// CandleResolutions are the resolutions, by name, of the candles computed
+// for every market.
+var CandleResolutions = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// DefaultCandleRetention is the default number of days the candles of each
+// resolution are kept for. The candles of resolutions which are not listed
+// are kept forever.
+const DefaultCandleRetention = "1m=7,5m=30,15m=90,1h=365"
+
+const (
+	defaultCandlesLimit = 100
+	maxCandlesLimit     = 1000
+)
+
+// RefreshCandles computes the candles of every resolution from the trades
+// of the last <numHours>, replacing the ones previously computed. The
+// trades of the first candle of each resolution are included even if they
+// are older than <numHours>, so the trades must be kept for longer than
+// <numHours> plus the longest resolution.
+func RefreshCandles(ctx context.Context, s tickerdb.Store, l *hlog.Entry, numHours int) error {
+	now := time.Now()
+	for _, name := range sortedCandleResolutions() {
+		resolution := CandleResolutions[name]
+		since := now.Add(-time.Duration(numHours) * time.Hour).Truncate(resolution)
+		l.Infof("Computing %s candles since %s\n", name, utils.TimeToRFC3339(since))
+		if err := s.UpsertCandles(ctx, resolution, since); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CleanCandles deletes the candles older than the number of days their
+// resolution is kept for in <retention>.
+func CleanCandles(ctx context.Context, s tickerdb.Store, l *hlog.Entry, retention map[string]int) error {
+	now := time.Now()
+	for name, days := range retention {
+		l.Infof("Deleting %s candles older than %d days\n", name, days)
+		err := s.DeleteOldCandles(ctx, CandleResolutions[name], now.AddDate(0, 0, -days))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseCandleRetention parses a comma-separated list of <resolution>=<days>
+// pairs (e.g. "1m=7,1h=365") into the number of days the candles of each
+// resolution are kept for.
+func ParseCandleRetention(s string) (map[string]int, error) {
+	retention := map[string]int{}
+	if s == "" {
+		return retention, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid retention %q, expected <resolution>=<days>", pair)
+		}
+		if _, ok := CandleResolutions[parts[0]]; !ok {
+			return nil, fmt.Errorf("unknown candle resolution %q", parts[0])
+		}
+		days, err := strconv.Atoi(parts[1])
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid number of days %q for resolution %s", parts[1], parts[0])
+		}
+		retention[parts[0]] = days
+	}
+	return retention, nil
+}
+
+// candlesRequest represents the parameters of a request to the candles
+// endpoint.
+type candlesRequest struct {
+	baseAssetCode      string
+	baseAssetIssuer    string
+	counterAssetCode   string
+	counterAssetIssuer string
+	resolution         string
+	cursor             *time.Time
+	desc               bool
+	limit              int
+}
+
+// CandlesHandler returns a handler serving the candles of a market, paginated
+// by their open time.
+func CandlesHandler(s tickerdb.Store, l *hlog.Entry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.Infof("%s %s %s\n", r.RemoteAddr, r.Method, r.URL)
+		if r.Method != http.MethodGet {
+			writeCandlesError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		req, err := parseCandlesRequest(r.URL.Query())
+		if err != nil {
+			writeCandlesError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx := r.Context()
+		found, baseID, err := s.GetAssetByCodeAndIssuerAccount(ctx, req.baseAssetCode, req.baseAssetIssuer)
+		if err != nil {
+			l.Errorln("could not retrieve base asset:", err)
+			writeCandlesError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if !found {
+			writeCandlesError(w, http.StatusNotFound, "base asset not found")
+			return
+		}
+		found, counterID, err := s.GetAssetByCodeAndIssuerAccount(ctx, req.counterAssetCode, req.counterAssetIssuer)
+		if err != nil {
+			l.Errorln("could not retrieve counter asset:", err)
+			writeCandlesError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		if !found {
+			writeCandlesError(w, http.StatusNotFound, "counter asset not found")
+			return
+		}
+
+		candles, err := s.GetCandles(
+			ctx,
+			baseID,
+			counterID,
+			CandleResolutions[req.resolution],
+			req.cursor,
+			req.desc,
+			req.limit,
+		)
+		if err != nil {
+			l.Errorln("could not retrieve candles:", err)
+			writeCandlesError(w, http.StatusInternalServerError, "internal server error")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(newCandlesPage(r.URL, req, candles))
+	})
+}
+
+// parseCandlesRequest validates the query parameters of a request to the
+// candles endpoint.
+func parseCandlesRequest(q url.Values) (req candlesRequest, err error) {
+	req.baseAssetCode, req.baseAssetIssuer, err = parseCandlesAsset(q.Get("base_asset"))
+	if err != nil {
+		return req, fmt.Errorf("invalid base_asset: %s", err)
+	}
+	req.counterAssetCode, req.counterAssetIssuer, err = parseCandlesAsset(q.Get("counter_asset"))
+	if err != nil {
+		return req, fmt.Errorf("invalid counter_asset: %s", err)
+	}
+
+	req.resolution = q.Get("resolution")
+	if _, ok := CandleResolutions[req.resolution]; !ok {
+		return req, fmt.Errorf(
+			"invalid resolution %q, expected one of %s",
+			req.resolution,
+			strings.Join(sortedCandleResolutions(), ", "),
+		)
+	}
+
+	switch order := q.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		req.desc = true
+	default:
+		return req, fmt.Errorf("invalid order %q, expected asc or desc", order)
+	}
+
+	if cursor := q.Get("cursor"); cursor != "" {
+		ms, parseErr := strconv.ParseInt(cursor, 10, 64)
+		if parseErr != nil {
+			return req, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		t := time.Unix(0, ms*int64(time.Millisecond))
+		req.cursor = &t
+	}
+
+	req.limit = defaultCandlesLimit
+	if limit := q.Get("limit"); limit != "" {
+		req.limit, err = strconv.Atoi(limit)
+		if err != nil || req.limit <= 0 || req.limit > maxCandlesLimit {
+			return req, fmt.Errorf("invalid limit %q, expected a number between 1 and %d", limit, maxCandlesLimit)
+		}
+	}
+	return req, nil
+}
+
+// parseCandlesAsset parses an asset in the format of the assets.json file,
+// "native" or <code>:<issuer>, into the code and issuer it is stored with.
+func parseCandlesAsset(asset string) (code string, issuer string, err error) {
+	if asset == "native" {
+		return "XLM", "native", nil
+	}
+	parts := strings.SplitN(asset, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("%q is neither native nor <code>:<issuer>", asset)
+	}
+	return parts[0], parts[1], nil
+}
+
+func newCandlesPage(u *url.URL, req candlesRequest, dbCandles []tickerdb.Candle) CandlesPage {
+	page := CandlesPage{
+		Resolution: req.resolution,
+		Candles:    make([]Candle, 0, len(dbCandles)),
+	}
+	for _, c := range dbCandles {
+		page.Candles = append(page.Candles, Candle{
+			OpenTime:        utils.TimeToUnixEpoch(c.OpenTime),
+			OpenTimeRFC3339: utils.TimeToRFC3339(c.OpenTime),
+			Open:            c.Open,
+			High:            c.High,
+			Low:             c.Low,
+			Close:           c.Close,
+			BaseVolume:      c.BaseVolume,
+			CounterVolume:   c.CounterVolume,
+			TradeCount:      c.TradeCount,
+		})
+	}
+
+	// A full page may be followed by more candles.
+	if len(page.Candles) == req.limit {
+		q := u.Query()
+		q.Set("cursor", strconv.FormatInt(page.Candles[len(page.Candles)-1].OpenTime, 10))
+		next := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		page.Next = next.String()
+	}
+	return page
+}
+
+func writeCandlesError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// sortedCandleResolutions returns the names of the candle resolutions, from
+// the shortest to the longest.
+func sortedCandleResolutions() []string {
+	names := make([]string, 0, len(CandleResolutions))
+	for name := range CandleResolutions {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return CandleResolutions[names[i]] < CandleResolutions[names[j]]
+	})
+	return names
+}
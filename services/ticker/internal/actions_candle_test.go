@@ -0,0 +1,117 @@
+package ticker
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCandleRetention(t *testing.T) {
+	retention, err := ParseCandleRetention(DefaultCandleRetention)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"1m": 7, "5m": 30, "15m": 90, "1h": 365}, retention)
+
+	retention, err = ParseCandleRetention("")
+	require.NoError(t, err)
+	assert.Empty(t, retention)
+
+	_, err = ParseCandleRetention("1m")
+	assert.EqualError(t, err, `invalid retention "1m", expected <resolution>=<days>`)
+	_, err = ParseCandleRetention("1m=7,2h=1")
+	assert.EqualError(t, err, `unknown candle resolution "2h"`)
+	_, err = ParseCandleRetention("1m=0")
+	assert.EqualError(t, err, `invalid number of days "0" for resolution 1m`)
+}
+
+func TestParseCandlesRequest(t *testing.T) {
+	issuer := "GATEMHCCKCY67ZUCKTROYN24ZYT5GK4EQZ65JJLDHKHRUZI3EUEKMTCH"
+	q := url.Values{
+		"base_asset":    {"native"},
+		"counter_asset": {"BTC:" + issuer},
+		"resolution":    {"1h"},
+	}
+
+	req, err := parseCandlesRequest(q)
+	require.NoError(t, err)
+	assert.Equal(t, candlesRequest{
+		baseAssetCode:      "XLM",
+		baseAssetIssuer:    "native",
+		counterAssetCode:   "BTC",
+		counterAssetIssuer: issuer,
+		resolution:         "1h",
+		limit:              defaultCandlesLimit,
+	}, req)
+
+	q.Set("order", "desc")
+	q.Set("cursor", "1556828400000")
+	q.Set("limit", "10")
+	req, err = parseCandlesRequest(q)
+	require.NoError(t, err)
+	assert.True(t, req.desc)
+	assert.Equal(t, 10, req.limit)
+	require.NotNil(t, req.cursor)
+	assert.Equal(t, int64(1556828400), req.cursor.Unix())
+
+	for param, expected := range map[string]string{
+		"base_asset":    `invalid base_asset: "BTC" is neither native nor <code>:<issuer>`,
+		"counter_asset": `invalid counter_asset: "BTC" is neither native nor <code>:<issuer>`,
+		"resolution":    `invalid resolution "BTC", expected one of 1m, 5m, 15m, 1h, 1d`,
+		"order":         `invalid order "BTC", expected asc or desc`,
+		"cursor":        `invalid cursor "BTC"`,
+		"limit":         `invalid limit "BTC", expected a number between 1 and 1000`,
+	} {
+		invalid := url.Values{}
+		for k, v := range q {
+			invalid[k] = v
+		}
+		invalid.Set(param, "BTC")
+		_, err = parseCandlesRequest(invalid)
+		assert.EqualError(t, err, expected)
+	}
+
+	q.Set("limit", "1001")
+	_, err = parseCandlesRequest(q)
+	assert.Error(t, err)
+}
+
+func TestNewCandlesPage(t *testing.T) {
+	u, err := url.Parse("/candles?base_asset=native&counter_asset=BTC%3AGATEMHCCKCY67ZUCKTROYN24ZYT5GK4EQZ65JJLDHKHRUZI3EUEKMTCH&resolution=1h&limit=2")
+	require.NoError(t, err)
+	req, err := parseCandlesRequest(u.Query())
+	require.NoError(t, err)
+
+	start := time.Unix(1556827200, 0)
+	candles := []tickerdb.Candle{
+		{OpenTime: start, Open: 1, High: 2, Low: 0.5, Close: 1.5, BaseVolume: 10, CounterVolume: 15, TradeCount: 3},
+		{OpenTime: start.Add(time.Hour), Open: 1.5, High: 1.5, Low: 1.5, Close: 1.5, BaseVolume: 1, CounterVolume: 1.5, TradeCount: 1},
+	}
+
+	page := newCandlesPage(u, req, candles)
+	assert.Equal(t, "1h", page.Resolution)
+	require.Len(t, page.Candles, 2)
+	assert.Equal(t, Candle{
+		OpenTime:        1556827200000,
+		OpenTimeRFC3339: start.Format(time.RFC3339),
+		Open:            1,
+		High:            2,
+		Low:             0.5,
+		Close:           1.5,
+		BaseVolume:      10,
+		CounterVolume:   15,
+		TradeCount:      3,
+	}, page.Candles[0])
+
+	next, err := url.Parse(page.Next)
+	require.NoError(t, err)
+	assert.Equal(t, "/candles", next.Path)
+	assert.Equal(t, "1556830800000", next.Query().Get("cursor"))
+	assert.Equal(t, "1h", next.Query().Get("resolution"))
+
+	// there is no next page after a partial one
+	page = newCandlesPage(u, req, candles[:1])
+	assert.Empty(t, page.Next)
+}
@@ -1,13 +1,34 @@
 package ticker
 
 import (
+	"context"
+	"time"
+
 	"github.com/stellar/go/services/ticker/internal/gql"
 	"github.com/stellar/go/services/ticker/internal/tickerdb"
 	hlog "github.com/stellar/go/support/log"
 )
 
-func StartGraphQLServer(s *tickerdb.TickerSession, l *hlog.Entry, port string) {
+// StartGraphQLServer serves the GraphQL interface and the candles of every
+// market (on /candles) on <port>. If <streamInterval> is positive, market
+// updates are also pushed to websocket subscribers on /markets/stream,
+// recomputed every <streamInterval>. If <pollInterval> is positive, new
+// trades and orderbook stats are pushed to the subscribers of the GraphQL
+// subscriptions, checked for every <pollInterval>.
+func StartGraphQLServer(s tickerdb.Store, l *hlog.Entry, port string, streamInterval, pollInterval time.Duration) {
 	graphql := gql.New(s, l)
+	graphql.Handle("/candles", CandlesHandler(s, l))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if streamInterval > 0 {
+		stream := NewMarketStream(s, l, streamInterval)
+		go stream.Run(ctx)
+		graphql.Handle("/markets/stream", stream.Handler())
+	}
+	if pollInterval > 0 {
+		go graphql.PollUpdates(ctx, pollInterval)
+	}
 
 	graphql.Serve(port)
 }
@@ -12,7 +12,7 @@ import (
 
 // GenerateMarketSummaryFile generates a MarketSummary with the statistics for all
 // valid markets within the database and outputs it to <filename>.
-func GenerateMarketSummaryFile(s *tickerdb.TickerSession, l *hlog.Entry, filename string) error {
+func GenerateMarketSummaryFile(s tickerdb.Store, l *hlog.Entry, filename string) error {
 	l.Infoln("Generating market data...")
 	marketSummary, err := GenerateMarketSummary(s)
 	if err != nil {
@@ -36,7 +36,7 @@ func GenerateMarketSummaryFile(s *tickerdb.TickerSession, l *hlog.Entry, filenam
 
 // GenerateMarketSummary outputs a MarketSummary with the statistics for all
 // valid markets within the database.
-func GenerateMarketSummary(s *tickerdb.TickerSession) (ms MarketSummary, err error) {
+func GenerateMarketSummary(s tickerdb.Store) (ms MarketSummary, err error) {
 	var marketStatsSlice []MarketStats
 	now := time.Now()
 	nowMillis := utils.TimeToUnixEpoch(now)
@@ -0,0 +1,200 @@
+package ticker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/services/ticker/internal/utils"
+	hlog "github.com/stellar/go/support/log"
+	"golang.org/x/net/websocket"
+)
+
+// subscriberBufferSize is the number of pending updates a subscriber may
+// have queued before it is considered too slow and disconnected.
+const subscriberBufferSize = 16
+
+// MarketUpdate represents a change in the statistics of a specific market,
+// as pushed to websocket subscribers.
+type MarketUpdate struct {
+	MarketStats
+
+	PriceDelta         float64 `json:"price_delta"`
+	BaseVolumeDelta    float64 `json:"base_volume_delta"`
+	CounterVolumeDelta float64 `json:"counter_volume_delta"`
+	TradeCountDelta    int64   `json:"trade_count_delta"`
+	UpdatedAt          int64   `json:"updated_at"`
+	UpdatedAtRFC3339   string  `json:"updated_at_rfc3339"`
+}
+
+// MarketStream periodically recomputes the market summary and pushes the
+// markets that changed to all connected websocket subscribers.
+type MarketStream struct {
+	session  tickerdb.Store
+	logger   *hlog.Entry
+	interval time.Duration
+
+	mutex       sync.Mutex
+	markets     map[string]MarketStats
+	subscribers map[chan []MarketUpdate]struct{}
+}
+
+// NewMarketStream creates a MarketStream which recomputes the market summary
+// every <interval>.
+func NewMarketStream(s tickerdb.Store, l *hlog.Entry, interval time.Duration) *MarketStream {
+	return &MarketStream{
+		session:     s,
+		logger:      l,
+		interval:    interval,
+		markets:     map[string]MarketStats{},
+		subscribers: map[chan []MarketUpdate]struct{}{},
+	}
+}
+
+// Run recomputes the market summary every interval until ctx is cancelled.
+func (ms *MarketStream) Run(ctx context.Context) {
+	ticker := time.NewTicker(ms.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ms.refresh(); err != nil {
+			ms.logger.Errorln("could not refresh market stream:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ms *MarketStream) refresh() error {
+	summary, err := GenerateMarketSummary(ms.session)
+	if err != nil {
+		return err
+	}
+	ms.publish(summary.Pairs, time.Now())
+	return nil
+}
+
+// publish stores the latest market stats and sends the markets which changed
+// since the previous call to every subscriber.
+func (ms *MarketStream) publish(pairs []MarketStats, now time.Time) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	updates := diffMarkets(ms.markets, pairs, now)
+	for _, pair := range pairs {
+		ms.markets[pair.TradePairName] = pair
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	for sub := range ms.subscribers {
+		select {
+		case sub <- updates:
+		default:
+			// The subscriber is not keeping up, drop it rather than
+			// blocking every other subscriber.
+			delete(ms.subscribers, sub)
+			close(sub)
+		}
+	}
+}
+
+// subscribe registers a new subscriber and returns it along with the current
+// state of every market.
+func (ms *MarketStream) subscribe() (chan []MarketUpdate, []MarketUpdate) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := time.Now()
+	snapshot := make([]MarketUpdate, 0, len(ms.markets))
+	for _, market := range ms.markets {
+		snapshot = append(snapshot, newMarketUpdate(market, MarketStats{}, now))
+	}
+
+	sub := make(chan []MarketUpdate, subscriberBufferSize)
+	ms.subscribers[sub] = struct{}{}
+	return sub, snapshot
+}
+
+func (ms *MarketStream) unsubscribe(sub chan []MarketUpdate) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	if _, ok := ms.subscribers[sub]; ok {
+		delete(ms.subscribers, sub)
+		close(sub)
+	}
+}
+
+// Handler returns a websocket handler which sends a snapshot of all markets
+// upon connection, followed by every subsequent market update.
+func (ms *MarketStream) Handler() websocket.Handler {
+	return func(conn *websocket.Conn) {
+		defer conn.Close()
+		// The server's read timeout must not apply to long-lived streams.
+		conn.SetDeadline(time.Time{})
+
+		sub, snapshot := ms.subscribe()
+		defer ms.unsubscribe(sub)
+
+		// Subscribers are not expected to send anything, reading is only
+		// used to detect when the connection is closed.
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var discard []byte
+			for websocket.Message.Receive(conn, &discard) == nil {
+			}
+		}()
+
+		if err := websocket.JSON.Send(conn, snapshot); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case <-closed:
+				return
+			case updates, ok := <-sub:
+				if !ok {
+					return
+				}
+				if err := websocket.JSON.Send(conn, updates); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// diffMarkets returns an update for every market in <next> which is new or
+// whose statistics differ from the ones in <prev>.
+func diffMarkets(prev map[string]MarketStats, next []MarketStats, now time.Time) []MarketUpdate {
+	var updates []MarketUpdate
+	for _, market := range next {
+		old, ok := prev[market.TradePairName]
+		if ok && old == market {
+			continue
+		}
+		updates = append(updates, newMarketUpdate(market, old, now))
+	}
+	return updates
+}
+
+func newMarketUpdate(market, old MarketStats, now time.Time) MarketUpdate {
+	return MarketUpdate{
+		MarketStats:        market,
+		PriceDelta:         market.Price - old.Price,
+		BaseVolumeDelta:    market.BaseVolume24h - old.BaseVolume24h,
+		CounterVolumeDelta: market.CounterVolume24h - old.CounterVolume24h,
+		TradeCountDelta:    market.TradeCount24h - old.TradeCount24h,
+		UpdatedAt:          utils.TimeToUnixEpoch(now),
+		UpdatedAtRFC3339:   utils.TimeToRFC3339(now),
+	}
+}
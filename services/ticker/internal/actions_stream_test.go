@@ -0,0 +1,71 @@
+package ticker
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func TestDiffMarkets(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	prev := map[string]MarketStats{
+		"XLM_BTC": {TradePairName: "XLM_BTC", Price: 1, BaseVolume24h: 10, CounterVolume24h: 10, TradeCount24h: 2},
+		"XLM_ETH": {TradePairName: "XLM_ETH", Price: 2},
+	}
+	next := []MarketStats{
+		{TradePairName: "XLM_BTC", Price: 1.5, BaseVolume24h: 15, CounterVolume24h: 22.5, TradeCount24h: 3},
+		{TradePairName: "XLM_ETH", Price: 2},
+		{TradePairName: "XLM_USD", Price: 0.1, BaseVolume24h: 100},
+	}
+
+	updates := diffMarkets(prev, next, now)
+	require.Len(t, updates, 2)
+
+	assert.Equal(t, "XLM_BTC", updates[0].TradePairName)
+	assert.Equal(t, 0.5, updates[0].PriceDelta)
+	assert.Equal(t, 5.0, updates[0].BaseVolumeDelta)
+	assert.Equal(t, 12.5, updates[0].CounterVolumeDelta)
+	assert.Equal(t, int64(1), updates[0].TradeCountDelta)
+	assert.Equal(t, int64(1600000000000), updates[0].UpdatedAt)
+
+	assert.Equal(t, "XLM_USD", updates[1].TradePairName)
+	assert.Equal(t, 0.1, updates[1].PriceDelta)
+	assert.Equal(t, 100.0, updates[1].BaseVolumeDelta)
+}
+
+func TestMarketStreamHandler(t *testing.T) {
+	stream := NewMarketStream(nil, hlog.New(), time.Minute)
+	stream.publish([]MarketStats{{TradePairName: "XLM_BTC", Price: 1}}, time.Now())
+
+	server := httptest.NewServer(stream.Handler())
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, err := websocket.Dial(url, "", server.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var snapshot []MarketUpdate
+	require.NoError(t, websocket.JSON.Receive(conn, &snapshot))
+	require.Len(t, snapshot, 1)
+	assert.Equal(t, "XLM_BTC", snapshot[0].TradePairName)
+	assert.Equal(t, 1.0, snapshot[0].Price)
+
+	// Unchanged markets are not pushed again.
+	stream.publish([]MarketStats{
+		{TradePairName: "XLM_BTC", Price: 1},
+		{TradePairName: "XLM_ETH", Price: 3},
+	}, time.Now())
+
+	var updates []MarketUpdate
+	require.NoError(t, websocket.JSON.Receive(conn, &updates))
+	require.Len(t, updates, 1)
+	assert.Equal(t, "XLM_ETH", updates[0].TradePairName)
+	assert.Equal(t, 3.0, updates[0].PriceDelta)
+}
@@ -17,7 +17,7 @@ import (
 // StreamTrades constantly streams and ingests new trades directly from horizon.
 func StreamTrades(
 	ctx context.Context,
-	s *tickerdb.TickerSession,
+	s tickerdb.Store,
 	c *horizonclient.Client,
 	l *hlog.Entry,
 ) error {
@@ -60,7 +60,7 @@ func StreamTrades(
 // into the database.
 func BackfillTrades(
 	ctx context.Context,
-	s *tickerdb.TickerSession,
+	s tickerdb.Store,
 	c *horizonclient.Client,
 	l *hlog.Entry,
 	numHours int,
@@ -106,7 +106,7 @@ func BackfillTrades(
 
 // findBaseAndCounter tries to find the Base and Counter assets IDs in the database,
 // and returns an error if it doesn't find any.
-func findBaseAndCounter(ctx context.Context, s *tickerdb.TickerSession, trade hProtocol.Trade) (bID int32, cID int32, err error) {
+func findBaseAndCounter(ctx context.Context, s tickerdb.Store, trade hProtocol.Trade) (bID int32, cID int32, err error) {
 	bFound, bID, err := s.GetAssetByCodeAndIssuerAccount(
 		ctx,
 		trade.BaseAssetCode,
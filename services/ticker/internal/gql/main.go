@@ -2,6 +2,7 @@ package gql
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/graph-gophers/graphql-go"
@@ -78,27 +79,45 @@ type orderbookStats struct {
 }
 
 type resolver struct {
-	db     *tickerdb.TickerSession
-	logger *hlog.Entry
+	db       tickerdb.Store
+	logger   *hlog.Entry
+	handlers map[string]http.Handler
+	hub      *updateHub
 }
 
 // New creates a new GraphQL resolver
-func New(s *tickerdb.TickerSession, l *hlog.Entry) *resolver {
+func New(s tickerdb.Store, l *hlog.Entry) *resolver {
 	if s == nil {
 		panic("A valid database session must be provided for the GraphQL server")
 	}
-	return &resolver{db: s, logger: l}
+	return &resolver{db: s, logger: l, handlers: map[string]http.Handler{}, hub: newUpdateHub()}
 }
 
-// Serve creates a GraphQL interface on <address>/graphql and a GraphiQL explorer on /graphiql
+// Handle registers an additional handler to be served alongside the GraphQL
+// interface. It must be called before Serve.
+func (r *resolver) Handle(pattern string, handler http.Handler) {
+	r.handlers[pattern] = handler
+}
+
+// Serve creates a GraphQL interface on <address>/graphql and a GraphiQL explorer on /graphiql.
+// Websocket connections to /graphql are served with the graphql-ws protocol, which supports
+// subscriptions (see PollUpdates).
 func (r *resolver) Serve(address string) {
 	relayHandler := r.NewRelayHandler()
+	subscriptionHandler := r.NewSubscriptionHandler(relayHandler.Schema)
 	mux := http.NewServeMux()
 	mux.Handle("/graphql", http.HandlerFunc(func(wr http.ResponseWriter, re *http.Request) {
 		r.logger.Infof("%s %s %s\n", re.RemoteAddr, re.Method, re.URL)
+		if strings.EqualFold(re.Header.Get("Upgrade"), "websocket") {
+			subscriptionHandler.ServeHTTP(wr, re)
+			return
+		}
 		relayHandler.ServeHTTP(wr, re)
 	}))
 	mux.Handle("/graphiql", GraphiQL{})
+	for pattern, handler := range r.handlers {
+		mux.Handle(pattern, handler)
+	}
 
 	server := &http.Server{
 		Addr:        address,
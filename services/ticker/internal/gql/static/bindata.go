@@ -1,7 +1,7 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
 // graphiql.html (1.182kB)
-// schema.gql (2.514kB)
+// schema.gql (3.447kB)
 
 package static
 
@@ -90,7 +90,7 @@ func graphiqlHtml() (*asset, error) {
 	return a, nil
 }
 
-var _schemaGql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x54\x51\x6f\xe3\x36\x0c\x7e\xb6\x7f\x05\xdb\x7b\x69\x81\x21\x0f\xc3\x9e\x8c\x6d\x40\xda\x6e\x58\xb1\xcb\xed\x76\x69\x87\x01\x41\x31\x30\x16\xe3\x10\x91\x25\x1f\x25\xa5\x0d\x0e\xfd\xef\x83\xe4\x24\x95\xe3\xb6\xc0\x9e\xf7\x64\xf3\x23\x3f\x8a\xfa\x44\xd2\xd5\x6b\x6a\x11\xbe\x95\xc5\xd7\x40\xb2\xab\xa0\xf8\x33\x7e\xcb\xe7\xb2\xf4\xbb\x8e\x20\x59\xd1\xfd\x01\x84\xbc\x30\x6d\x09\x50\x6b\xd8\xa2\x66\x85\x9e\x14\xa0\x73\xe4\x1d\x58\x03\x7e\x4d\x30\xf7\xa4\x35\x0a\x18\xf2\x8f\x56\x36\x93\xb2\xe8\xfd\x15\x2c\xa6\xf1\xe7\xec\xe1\xac\x7c\x27\x19\x3b\x17\x48\xde\xc9\xb6\x0f\xa8\x60\x71\x9b\xfe\x46\xf9\xbc\xa0\x22\x70\x1e\xbd\x83\x95\xd8\x36\xe5\xd1\xe8\x3c\xfc\x68\x42\xfb\x9b\x0d\xe2\xa6\x8d\xfd\x19\xd6\xf1\x2f\x32\x2f\x14\xad\x30\x68\x0f\x3f\xc1\xf7\x3f\xf4\xf0\xe5\x04\x6c\xe7\xd9\x1a\xd4\x7a\x07\x9d\xd8\x2d\x2b\x82\xda\x06\xe3\x49\x00\x8d\x8a\xbc\x25\x3a\xea\x2f\x0f\x6c\x56\x16\x56\x56\x60\xc5\xda\x93\xb0\x69\x26\x65\xd1\xa2\x6c\xc8\xbb\x8b\xb2\x28\x62\x68\xba\xfd\xb5\x55\x54\xc1\xdc\xc7\x90\x1c\xef\xef\x92\x79\xf6\x67\xbd\x46\xca\x5d\x23\x5e\x76\xc5\x0a\x6e\x8d\x2f\x8b\xcb\x0a\x16\xb3\x54\xca\x48\xf9\xa6\x11\x6a\x92\xec\x03\xd1\xac\xbc\xa1\x59\x64\x27\x7d\x5e\x95\x07\x41\xb3\xf3\x60\x57\xd0\x21\xcb\x27\x6c\xc9\xc1\x05\x4d\xa2\x14\x1f\x60\x71\xfe\xf7\xc7\xd9\x3f\x57\x77\xd7\xe7\xdf\x41\xfa\xbd\x9f\xdf\x9c\x3f\x5c\x82\x15\x40\x70\x6c\x1a\x4d\x50\x07\x11\x32\xf5\xae\x67\xa5\xb0\xf3\xcb\x48\x1e\x28\x0b\x42\x2e\x68\xef\x26\x65\xe1\xb9\xde\x90\x5c\x24\x4d\x06\x12\x1d\x0b\xa8\x60\xd1\x83\x0f\x6f\x6a\x33\x3d\xaa\x70\x54\xe9\xb9\x2c\x5d\x8d\xb1\xef\xae\xb8\x89\x81\x7b\xeb\x8e\x5b\xda\x8f\x45\x52\x3f\x8e\x45\x7e\xf2\xd9\xa1\x3d\xa7\x75\x7a\xa4\x0c\x8f\xa4\xcc\x34\xa1\xdd\xc7\xb8\x54\xca\x59\x59\x60\xf0\xeb\x2f\xf4\x35\xb0\x90\xaa\xe0\xca\x5a\x4d\x68\x8e\xf8\xd6\xd6\xb8\xd4\x34\x70\xb4\xfd\x19\xbf\x6a\x8b\x29\x41\xdf\x2b\xc6\x8b\xd5\x9a\xd4\xd5\xee\xc6\xb6\xc8\x66\x40\x31\xf5\xda\x8e\x9b\x6a\xe8\xb9\x1b\x96\xca\x2e\xa1\xd3\x14\x30\x2c\x4d\xb1\xeb\x34\xee\x6e\xa8\xe6\x16\xb5\xab\xf6\x72\xc5\xfb\x61\x9b\xe7\x50\xe4\xea\xcc\xac\xad\x51\x1c\xfb\xc7\x65\xe0\x8a\x9f\x48\x7d\x0a\xed\x32\xf6\xf3\x31\x51\x8b\x4f\x23\x8c\xdd\xbd\xd1\xdc\xb2\x1f\x56\x23\xa4\xa8\x4d\x6d\x79\x6b\x9c\x97\x50\x9f\x9e\x50\x5b\xad\xd1\x93\xa0\x9e\x2a\x25\xe4\x1c\xbd\xeb\x9d\x73\x63\xd0\x07\x39\x89\x0a\x26\x8e\x4f\x8e\xc5\xb1\x09\x6e\xd4\x04\xb7\x37\xfb\xa7\x3d\xac\xd2\xbe\xbf\x62\xd3\xa4\x71\xfb\x8c\x2c\x19\xe9\xd5\x1d\x91\xe3\xc3\x59\x3f\xd4\xf2\xca\x8e\x38\x71\x8d\x78\x31\xe3\x5f\x56\x87\xf8\x44\x87\xe6\xd9\x13\x4e\xe1\x54\xe8\x75\xdf\x67\xbd\xf8\xb6\x23\xf3\xe2\xd7\xf6\xf1\xc5\x58\x73\xb3\xce\x32\xae\xd1\x34\xf9\x09\xda\xba\xcc\xe4\x78\xdc\x16\xf5\xdc\xa3\xf8\x2a\x8d\x56\x6a\x02\x71\xfe\x23\xa9\x86\xe4\x3a\xc6\x47\xf8\xe8\x8c\x0b\xe9\x2d\x9f\x15\x45\xb2\xb4\x76\x33\x8f\x3b\xac\x82\x3f\x06\xf6\xcb\x1b\x9c\x4e\xfb\x7f\x7d\x8d\x77\x34\xff\x3f\xcb\x3a\xc4\xe1\x5b\x09\xc5\x92\xd5\xfe\x86\xc7\xc1\x5d\xb2\x3a\x55\x62\xc9\x6a\x86\x4f\xf9\x12\xdb\x9c\xb2\xd0\x6d\x4e\x59\xe8\x36\x33\xce\xf4\x72\x9d\x10\xaa\x53\x7b\xc6\xea\xb3\xe5\x6c\x45\x1e\xaa\xed\x27\x22\x3e\x7d\x17\x96\x9a\xeb\xdf\x69\x97\xef\xe6\xe1\xee\x0a\xa2\xf3\x3d\x6e\x5b\x7d\xff\xe5\x63\xbe\xb7\x48\x91\x60\xdc\x35\x73\x92\xed\x60\xd0\xe2\xea\x1e\x81\x5e\xd0\xb8\x15\xc9\xc8\xf1\x48\xcb\x69\xf0\xeb\x5f\x8c\xea\xfa\xaa\xb3\xf5\xd9\x59\xc7\x7e\xc4\xb0\xd2\xdc\x3d\xb2\xf7\x39\xf8\x5c\xfe\x1b\x00\x00\xff\xff\x0b\x65\xd4\x2d\xd2\x09\x00\x00")
+var _schemaGql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xe4\x55\xcd\x6e\xe3\x36\x10\x3e\x4b\x4f\x31\xce\x5e\x12\xa0\xc8\xa1\xe8\x49\x68\x0b\x38\x49\x8b\x1a\xdd\x6c\xb7\xeb\xa4\x28\x10\x04\xc5\x58\x1c\x4b\x84\x29\x52\x3b\x24\xed\xb8\x8b\x7d\xf7\x82\x94\x6c\x53\xb2\x9d\x6d\x4f\x7b\xe8\x49\x9a\x9f\x8f\x9c\xf9\xe6\x87\xb6\xac\xa9\x41\xf8\x94\x67\x1f\x3d\xf1\xb6\x80\xec\xf7\xf0\xcd\x33\xeb\x17\xb6\x64\xd9\x3a\x69\x74\x01\xf3\x44\xca\x3f\xe7\xb9\xdb\xb6\x04\xd1\x33\x40\xdf\x00\x93\x63\x49\x6b\x02\x54\x0a\xd6\xa8\xa4\x40\x47\x02\xd0\x5a\x72\x16\x8c\x06\x57\x13\xcc\x1d\x29\x85\x0c\x9a\xdc\xc6\xf0\xea\x3a\xcf\x3a\x7b\x01\x4f\xd3\xf0\x33\x79\x9e\xe4\xaf\x1c\x26\xad\xf5\xc4\xaf\x9c\xd6\x3b\x14\xf0\x34\x8b\x7f\x47\xe7\x39\x46\x41\x60\x1d\x3a\x0b\x4b\x36\x4d\x3c\x47\xa1\x75\xf0\xbd\xf6\xcd\x2f\xc6\xb3\x9d\x56\xe6\x47\xa8\xc3\x5f\x40\x5e\x0a\x5a\xa2\x57\x0e\x7e\x80\x6f\xbf\xeb\xd4\x57\xd7\x60\x22\x0d\xa8\xd4\x16\x5a\x36\x6b\x29\x08\x4a\xe3\xb5\x23\x06\xd4\x22\xe0\x16\x68\xa9\x4b\x1e\xa4\x5e\x1a\x58\x1a\x86\xa5\x54\x8e\x58\xea\xea\x3a\xcf\x1a\xe4\x15\x39\x7b\x99\x67\x59\x70\x8d\xd9\xdf\x1a\x41\x05\xcc\x5d\x70\x49\xf5\x5d\x2e\x89\xa5\xbf\xeb\x14\x28\x35\x1d\xe1\x92\x14\x0b\x98\x69\x97\x67\x57\x05\x3c\xdd\xc7\x50\x8e\x98\xaf\x2a\xa6\x2a\xd2\x3e\x20\xcd\xf0\x19\xce\x42\xd6\x91\x9f\x93\xf4\x20\x28\x69\x1d\x98\x25\xb4\x28\xf9\x1d\x36\x64\xe1\x92\xae\x03\x15\x6f\xe0\xe9\xe2\xcf\xb7\xf7\x7f\xdd\x3c\xdc\x5e\x7c\x03\xf1\xf7\x71\x7e\x77\xf1\x7c\x05\x86\x01\xc1\x4a\x5d\x29\x82\xd2\x33\x93\x2e\xb7\x1d\x2a\xba\x5d\x5c\x05\xf0\x80\x59\x60\xb2\x5e\x39\x7b\x9d\x67\x4e\x96\x2b\xe2\x40\x70\x39\xa4\x68\x1f\x40\x01\x4f\x1d\x35\xcf\x67\xb9\x99\xee\x59\xd8\xb3\xb4\xeb\xfd\x74\x20\x76\x23\x50\x92\x0c\x4d\x56\x53\xc7\x99\x05\xa9\x2b\xb2\x81\xc3\xd8\x6c\xda\x6c\xc0\xe8\x53\xfc\x04\xf8\x59\x8a\xce\xf2\xb3\x34\x1c\x80\x27\xb3\x8f\xf7\x5f\x1e\xe7\x3a\x79\xbe\x2a\xe0\x21\x58\x27\xf9\x38\x68\xc3\x82\x78\x61\xcc\xaa\x2f\xb6\x59\x42\xdf\xa7\xb0\xa9\x49\xd3\x9a\x62\xf1\xb7\x80\x4c\x01\xeb\x5b\x81\xff\x22\xb9\x53\x99\x05\xf8\x17\x93\x3b\x99\xd9\x3e\xc8\x73\xc9\x75\x95\xfa\x6d\xe7\x36\x09\xdb\xca\x96\x18\xf6\xce\x8d\xac\x42\x69\x7b\xe9\x41\x36\xd4\x17\x33\x8e\x52\xa8\x62\xda\x2b\x93\xdd\x42\x99\x96\x71\xac\x12\x7d\x00\x25\xa2\xf6\x4d\xef\x63\x63\xf3\x4c\xf2\x0c\xbd\xab\x3f\xd0\x47\x2f\x99\x44\x01\x37\xc6\x28\x42\xbd\xd7\xaf\x4d\x89\x0b\x45\x03\x43\xd3\xdd\xf1\xb3\x32\xe8\x26\xfd\x66\xbc\x35\xda\xb1\x51\x8a\xc4\xcd\xf6\xce\x34\x28\xf5\x00\xa2\xcb\xda\x1c\xaf\x81\xa1\xe5\x61\x18\xaa\xb4\xd1\x7f\x1a\x1d\x86\xa1\x09\x69\x5b\x85\xdb\x3b\x2a\x65\x83\xca\x16\x3d\x5d\x21\x3f\x6c\xd2\x33\x04\xd9\x32\x11\x4b\xa3\x85\x0c\x1d\x6d\x13\xe5\x52\xbe\x90\x78\xe7\x9b\x05\x71\x72\x50\x83\x2f\x47\x3a\x69\x1f\xb5\x92\x8d\x74\xc3\x68\x98\x04\x35\xb1\x97\x66\xda\x3a\xf6\xe5\xf8\x86\xd2\x28\x85\x8e\x18\xd5\x54\x08\x26\x6b\xe9\x55\xeb\x5c\x56\x1a\x9d\xe7\x91\x97\xd7\xe1\x69\x48\x75\xa1\xf7\x7d\xaa\xe8\x9a\x60\x76\xd7\x97\x76\xb7\x00\xba\x3e\x0b\x4d\x13\x87\xfd\x3d\xca\xfd\xae\x9d\xe4\xa7\xb7\xfa\x24\x3f\xb7\xd5\x27\xf9\x60\x75\x8f\x40\xe7\xb7\x7a\x7f\xe2\x1f\x46\xf9\x86\x0e\xcd\xd3\x03\xc6\xea\x18\xe8\x6d\xb0\xed\xda\xd4\xb4\xa4\x0f\x76\x65\x36\x07\xa1\x96\x55\x7d\x90\xca\x1a\x75\x95\xde\xa0\x8c\x4d\x44\x19\x42\x5f\xa3\x9a\x3b\x64\x57\xc4\xd1\x8a\x4d\xc0\xd6\xbd\x25\x51\x11\xdf\x06\xff\xa0\xde\x1b\x15\x9e\xb7\xed\x87\x7c\x1e\x16\x51\x01\xfb\x69\x8e\xf2\xa1\x06\xe3\xfd\xfc\x5f\xab\x91\x12\x3b\x32\xfd\x9f\x69\x8d\xef\x43\xe0\xb2\x36\x2c\xff\x36\x7a\x76\x97\x10\xf3\xf5\xbb\x7d\x3a\x5a\x95\x3b\xc0\x48\xdd\xb2\x2c\x13\x2e\x03\x72\x66\xe7\xa4\x14\x71\xba\x68\xd4\x69\xb2\x76\x5c\x8c\x9e\x93\xaf\x3f\xef\x5f\x2a\x62\xd6\x3f\xcb\x53\x37\x4e\x65\xe8\x09\x9f\x72\xc8\x16\x52\xf4\x8d\xbb\xdf\xc7\x0b\x29\xc6\x0d\xbe\x90\xe2\x1e\x5f\x0e\x32\xda\xd5\x18\x85\x76\x35\x46\xa1\x5d\xdd\xcb\x64\x0c\x6c\xcb\x84\x62\x2c\xdf\x4b\xf1\xde\xc8\xa4\x6e\xbb\x68\xbb\x45\x17\xf8\x6e\xfd\x42\xc9\xf2\x57\xda\x26\x2c\x8c\x9e\x24\xcf\x2a\x91\x9c\x69\xd4\xe3\x87\xb7\x89\x66\x49\x82\x18\xc3\x13\x32\x27\x5e\x0f\xf8\x0c\x2f\xf5\x91\xd2\x31\x6a\xbb\x24\x3e\x32\x6c\x68\x31\xf5\xae\xfe\x49\x8b\xb6\x8b\x7a\x6f\x11\xd4\x1a\x2b\xdd\x11\xc2\x70\xf5\xb0\x91\xce\xa5\xca\xcf\xf9\x3f\x03\x00\x54\x3c\x09\x95\x77\x0d\x00\x00")
 
 func schemaGqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -0,0 +1,253 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+)
+
+// subscriberBufferSize is the number of pending updates a subscriber may
+// have queued before it is considered too slow and unsubscribed.
+const subscriberBufferSize = 64
+
+// pollTradesLimit is the maximum number of trades published per poll.
+const pollTradesLimit = 1000
+
+// trade represents a trade pushed to subscribers of the trades()
+// subscription.
+type trade struct {
+	HorizonID          string
+	TradePair          string
+	BaseAssetCode      string
+	BaseAssetIssuer    string
+	CounterAssetCode   string
+	CounterAssetIssuer string
+	BaseAmount         float64
+	CounterAmount      float64
+	Price              float64
+	BaseIsSeller       bool
+	LedgerCloseTime    graphql.Time
+}
+
+// marketOrderbook represents the orderbook stats of a specific pair of
+// assets pushed to subscribers of the orderbook() subscription.
+type marketOrderbook struct {
+	TradePair          string
+	BaseAssetCode      string
+	BaseAssetIssuer    string
+	CounterAssetCode   string
+	CounterAssetIssuer string
+	OrderbookStats     orderbookStats
+	UpdatedAt          graphql.Time
+}
+
+// subscription is a subscriber to the updates of some markets.
+type subscription struct {
+	// pairs are the names of the markets subscribed to, all markets if
+	// empty.
+	pairs   map[string]bool
+	updates chan interface{}
+}
+
+// updateHub pushes market updates to the subscribers of the trades() and
+// orderbook() subscriptions.
+type updateHub struct {
+	mutex      sync.Mutex
+	trades     map[*subscription]struct{}
+	orderbooks map[*subscription]struct{}
+}
+
+func newUpdateHub() *updateHub {
+	return &updateHub{
+		trades:     map[*subscription]struct{}{},
+		orderbooks: map[*subscription]struct{}{},
+	}
+}
+
+func (h *updateHub) subscribe(subs map[*subscription]struct{}, pairNames *[]string) *subscription {
+	sub := &subscription{updates: make(chan interface{}, subscriberBufferSize)}
+	if pairNames != nil && len(*pairNames) > 0 {
+		sub.pairs = map[string]bool{}
+		for _, pairName := range *pairNames {
+			sub.pairs[pairName] = true
+		}
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	subs[sub] = struct{}{}
+	return sub
+}
+
+func (h *updateHub) unsubscribe(subs map[*subscription]struct{}, sub *subscription) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if _, ok := subs[sub]; ok {
+		delete(subs, sub)
+		close(sub.updates)
+	}
+}
+
+// publish sends an update of the market <pairName> to the subscribers of the
+// market.
+func (h *updateHub) publish(subs map[*subscription]struct{}, pairName string, update interface{}) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for sub := range subs {
+		if sub.pairs != nil && !sub.pairs[pairName] {
+			continue
+		}
+		select {
+		case sub.updates <- update:
+		default:
+			// The subscriber is not keeping up, drop it rather than
+			// blocking every other subscriber.
+			delete(subs, sub)
+			close(sub.updates)
+		}
+	}
+}
+
+// Trades resolves the trades() GraphQL subscription.
+func (r *resolver) Trades(ctx context.Context, args struct {
+	PairNames *[]string
+}) <-chan *trade {
+	sub := r.hub.subscribe(r.hub.trades, args.PairNames)
+	c := make(chan *trade)
+	go func() {
+		defer close(c)
+		defer r.hub.unsubscribe(r.hub.trades, sub)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-sub.updates:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case c <- update.(*trade):
+				}
+			}
+		}
+	}()
+	return c
+}
+
+// Orderbook resolves the orderbook() GraphQL subscription.
+func (r *resolver) Orderbook(ctx context.Context, args struct {
+	PairNames *[]string
+}) <-chan *marketOrderbook {
+	sub := r.hub.subscribe(r.hub.orderbooks, args.PairNames)
+	c := make(chan *marketOrderbook)
+	go func() {
+		defer close(c)
+		defer r.hub.unsubscribe(r.hub.orderbooks, sub)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case update, ok := <-sub.updates:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case c <- update.(*marketOrderbook):
+				}
+			}
+		}
+	}()
+	return c
+}
+
+// PollUpdates publishes the trades and orderbook stats written to the
+// database by the ingestion jobs to the GraphQL subscribers, checking for
+// new ones every <interval> until ctx is cancelled. Only the updates written
+// after PollUpdates is called are published.
+func (r *resolver) PollUpdates(ctx context.Context, interval time.Duration) {
+	lastTradeID, err := r.db.GetLastTradeID(ctx)
+	if err != nil {
+		r.logger.Errorln("could not retrieve the last trade:", err)
+	}
+	// The orderbook stats are timestamped by the ingestion jobs, whose clock
+	// may differ from ours, so start from the latest update in the database.
+	lastOrderbookUpdate, err := r.db.GetLastOrderbookStatsUpdate(ctx)
+	if err != nil {
+		r.logger.Errorln("could not retrieve the last orderbook stats update:", err)
+		lastOrderbookUpdate = time.Now()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		trades, err := r.db.GetTradesAfterID(ctx, lastTradeID, pollTradesLimit)
+		if err != nil {
+			r.logger.Errorln("could not retrieve new trades:", err)
+		}
+		for _, dbTrade := range trades {
+			r.hub.publish(r.hub.trades, dbTrade.TradePairName, dbTradeToTrade(dbTrade))
+			lastTradeID = dbTrade.ID
+		}
+
+		stats, err := r.db.GetOrderbookStatsUpdatedAfter(ctx, lastOrderbookUpdate)
+		if err != nil {
+			r.logger.Errorln("could not retrieve updated orderbook stats:", err)
+		}
+		for _, dbStats := range stats {
+			r.hub.publish(r.hub.orderbooks, dbStats.TradePairName, dbOrderbookStatsToMarketOrderbook(dbStats))
+			lastOrderbookUpdate = dbStats.UpdatedAt
+		}
+	}
+}
+
+func dbTradeToTrade(dbTrade tickerdb.MarketTrade) *trade {
+	return &trade{
+		HorizonID:          dbTrade.HorizonID,
+		TradePair:          dbTrade.TradePairName,
+		BaseAssetCode:      dbTrade.BaseAssetCode,
+		BaseAssetIssuer:    dbTrade.BaseAssetIssuer,
+		CounterAssetCode:   dbTrade.CounterAssetCode,
+		CounterAssetIssuer: dbTrade.CounterAssetIssuer,
+		BaseAmount:         dbTrade.BaseAmount,
+		CounterAmount:      dbTrade.CounterAmount,
+		Price:              dbTrade.Price,
+		BaseIsSeller:       dbTrade.BaseIsSeller,
+		LedgerCloseTime:    graphql.Time{Time: dbTrade.LedgerCloseTime},
+	}
+}
+
+func dbOrderbookStatsToMarketOrderbook(dbStats tickerdb.MarketOrderbookStats) *marketOrderbook {
+	return &marketOrderbook{
+		TradePair:          dbStats.TradePairName,
+		BaseAssetCode:      dbStats.BaseAssetCode,
+		BaseAssetIssuer:    dbStats.BaseAssetIssuer,
+		CounterAssetCode:   dbStats.CounterAssetCode,
+		CounterAssetIssuer: dbStats.CounterAssetIssuer,
+		OrderbookStats: orderbookStats{
+			BidCount:       BigInt(dbStats.NumBids),
+			BidVolume:      dbStats.BidVolume,
+			BidMax:         dbStats.HighestBid,
+			AskCount:       BigInt(dbStats.NumAsks),
+			AskVolume:      dbStats.AskVolume,
+			AskMin:         dbStats.LowestAsk,
+			Spread:         dbStats.Spread,
+			SpreadMidPoint: dbStats.SpreadMidPoint,
+		},
+		UpdatedAt: graphql.Time{Time: dbStats.UpdatedAt},
+	}
+}
@@ -0,0 +1,193 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"golang.org/x/net/websocket"
+)
+
+// keepAliveInterval is the interval at which keep-alive messages are sent to
+// websocket clients, so that idle connections are not closed by proxies.
+const keepAliveInterval = 30 * time.Second
+
+// Message types of the graphql-ws protocol, see
+// https://github.com/apollographql/subscriptions-transport-ws/blob/master/PROTOCOL.md
+const (
+	wsProtocol = "graphql-ws"
+
+	wsConnectionInit      = "connection_init"
+	wsConnectionAck       = "connection_ack"
+	wsConnectionKeepAlive = "ka"
+	wsConnectionTerminate = "connection_terminate"
+	wsStart               = "start"
+	wsStop                = "stop"
+	wsData                = "data"
+	wsError               = "error"
+	wsComplete            = "complete"
+)
+
+// wsMessage is a message of the graphql-ws protocol.
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// wsStartPayload is the payload of a start message, i.e. a GraphQL operation.
+type wsStartPayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// NewSubscriptionHandler returns a websocket handler executing GraphQL
+// operations, including subscriptions, with the graphql-ws protocol used by
+// Apollo and GraphiQL clients.
+func (r *resolver) NewSubscriptionHandler(schema *graphql.Schema) http.Handler {
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, req *http.Request) error {
+			config.Protocol = []string{wsProtocol}
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			newWSConnection(schema, conn).serve()
+		},
+	}
+}
+
+// wsConnection is a websocket connection to a GraphQL client.
+type wsConnection struct {
+	schema *graphql.Schema
+	conn   *websocket.Conn
+
+	mutex      sync.Mutex
+	operations map[string]context.CancelFunc
+}
+
+func newWSConnection(schema *graphql.Schema, conn *websocket.Conn) *wsConnection {
+	return &wsConnection{
+		schema:     schema,
+		conn:       conn,
+		operations: map[string]context.CancelFunc{},
+	}
+}
+
+// serve executes the operations started by the client until the connection
+// is closed or terminated.
+func (c *wsConnection) serve() {
+	defer c.conn.Close()
+	// The server's read timeout must not apply to long-lived subscriptions.
+	c.conn.SetDeadline(time.Time{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.keepAlive(ctx)
+
+	for {
+		var msg wsMessage
+		if err := websocket.JSON.Receive(c.conn, &msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case wsConnectionInit:
+			c.send(wsMessage{Type: wsConnectionAck})
+		case wsStart:
+			var payload wsStartPayload
+			if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+				c.sendError(msg.ID, "invalid start payload")
+				continue
+			}
+			c.start(ctx, msg.ID, payload)
+		case wsStop:
+			c.stop(msg.ID)
+		case wsConnectionTerminate:
+			return
+		default:
+			c.sendError(msg.ID, "unknown message type "+msg.Type)
+		}
+	}
+}
+
+// start executes an operation, sending its results until it completes or is
+// stopped.
+func (c *wsConnection) start(ctx context.Context, id string, payload wsStartPayload) {
+	c.mutex.Lock()
+	if _, ok := c.operations[id]; ok {
+		c.mutex.Unlock()
+		c.sendError(id, "operation id "+id+" is already in use")
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	c.operations[id] = cancel
+	c.mutex.Unlock()
+
+	responses, err := c.schema.Subscribe(ctx, payload.Query, payload.OperationName, payload.Variables)
+	if err != nil {
+		c.stop(id)
+		c.sendError(id, err.Error())
+		return
+	}
+
+	go func() {
+		// The responses must be read until the channel is closed, which
+		// happens once ctx is cancelled for subscriptions.
+		for response := range responses {
+			data, err := json.Marshal(response)
+			if err != nil {
+				continue
+			}
+			c.send(wsMessage{ID: id, Type: wsData, Payload: data})
+		}
+
+		c.mutex.Lock()
+		_, running := c.operations[id]
+		delete(c.operations, id)
+		c.mutex.Unlock()
+		// Operations stopped by the client are not completed.
+		if running {
+			cancel()
+			c.send(wsMessage{ID: id, Type: wsComplete})
+		}
+	}()
+}
+
+// stop cancels an operation.
+func (c *wsConnection) stop(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if cancel, ok := c.operations[id]; ok {
+		delete(c.operations, id)
+		cancel()
+	}
+}
+
+func (c *wsConnection) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.send(wsMessage{Type: wsConnectionKeepAlive})
+		}
+	}
+}
+
+// send sends a message to the client. Errors are ignored, since a broken
+// connection is detected when receiving from it.
+func (c *wsConnection) send(msg wsMessage) {
+	websocket.JSON.Send(c.conn, msg)
+}
+
+func (c *wsConnection) sendError(id, message string) {
+	payload, _ := json.Marshal(map[string]string{"message": message})
+	c.send(wsMessage{ID: id, Type: wsError, Payload: payload})
+}
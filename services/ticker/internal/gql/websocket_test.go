@@ -0,0 +1,150 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stellar/go/services/ticker/internal/gql/static"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	hlog "github.com/stellar/go/support/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func receiveWSMessage(t *testing.T, conn *websocket.Conn) wsMessage {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg wsMessage
+	require.NoError(t, websocket.JSON.Receive(conn, &msg))
+	return msg
+}
+
+func waitForSubscribers(t *testing.T, r *resolver, n int) {
+	for i := 0; i < 500; i++ {
+		r.hub.mutex.Lock()
+		count := len(r.hub.trades)
+		r.hub.mutex.Unlock()
+		if count == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d subscribers", n)
+}
+
+func TestSubscriptionHandler(t *testing.T) {
+	r := &resolver{logger: hlog.DefaultLogger, hub: newUpdateHub()}
+	schema := graphql.MustParseSchema(static.Schema(), r, graphql.UseFieldResolvers())
+	server := httptest.NewServer(r.NewSubscriptionHandler(schema))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	config, err := websocket.NewConfig(url, server.URL)
+	require.NoError(t, err)
+	config.Protocol = []string{wsProtocol}
+	conn, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, websocket.JSON.Send(conn, wsMessage{Type: wsConnectionInit}))
+	assert.Equal(t, wsMessage{Type: wsConnectionAck}, receiveWSMessage(t, conn))
+
+	start := func(id, query string) {
+		payload, err := json.Marshal(wsStartPayload{Query: query})
+		require.NoError(t, err)
+		require.NoError(t, websocket.JSON.Send(conn, wsMessage{ID: id, Type: wsStart, Payload: payload}))
+	}
+	start("1", `subscription { trades(pairNames: ["XLM_BTC"]) { horizonID tradePair price } }`)
+	waitForSubscribers(t, r, 1)
+
+	// Only the trades of the markets subscribed to are sent.
+	r.hub.publish(r.hub.trades, "XLM_USD", &trade{HorizonID: "1", TradePair: "XLM_USD"})
+	r.hub.publish(r.hub.trades, "XLM_BTC", &trade{HorizonID: "2", TradePair: "XLM_BTC", Price: 0.5})
+	msg := receiveWSMessage(t, conn)
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, wsData, msg.Type)
+	assert.JSONEq(t, `{"data":{"trades":{"horizonID":"2","tradePair":"XLM_BTC","price":0.5}}}`, string(msg.Payload))
+
+	// Queries are completed after their result.
+	start("2", `{ __typename }`)
+	msg = receiveWSMessage(t, conn)
+	assert.Equal(t, "2", msg.ID)
+	assert.JSONEq(t, `{"data":{"__typename":"Query"}}`, string(msg.Payload))
+	assert.Equal(t, wsMessage{ID: "2", Type: wsComplete}, receiveWSMessage(t, conn))
+
+	start("1", `subscription { trades { horizonID } }`)
+	msg = receiveWSMessage(t, conn)
+	assert.Equal(t, wsError, msg.Type)
+	assert.JSONEq(t, `{"message":"operation id 1 is already in use"}`, string(msg.Payload))
+
+	// Stopping a subscription unsubscribes it.
+	require.NoError(t, websocket.JSON.Send(conn, wsMessage{ID: "1", Type: wsStop}))
+	waitForSubscribers(t, r, 0)
+}
+
+func TestUpdateHubDropsSlowSubscribers(t *testing.T) {
+	hub := newUpdateHub()
+	sub := hub.subscribe(hub.orderbooks, nil)
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		hub.publish(hub.orderbooks, "XLM_BTC", &marketOrderbook{TradePair: "XLM_BTC"})
+	}
+
+	assert.Len(t, hub.orderbooks, 0)
+	received := 0
+	for range sub.updates {
+		received++
+	}
+	assert.Equal(t, subscriberBufferSize, received)
+}
+
+func TestPollUpdates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := tickerdb.NewMemoryStore()
+	_, err := store.Migrate()
+	require.NoError(t, err)
+
+	issuerPK := "GCF3TQXKZJNFJK7HCMNE2O2CUNKCJH2Y2ROISTBPLC7C5EIA5NNG2XZB"
+	issuerID, err := store.InsertOrUpdateIssuer(ctx, &tickerdb.Issuer{PublicKey: issuerPK}, nil)
+	require.NoError(t, err)
+	require.NoError(t, store.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+		Code:          "BTC",
+		IssuerAccount: issuerPK,
+		IssuerID:      issuerID,
+		IsValid:       true,
+	}, nil))
+	_, xlmID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "XLM", "native")
+	require.NoError(t, err)
+	_, btcID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "BTC", issuerPK)
+	require.NoError(t, err)
+
+	// The clock of the ingestion jobs is an hour behind ours.
+	updatedAt := time.Now().Add(-time.Hour)
+	stats := &tickerdb.OrderbookStats{BaseAssetID: xlmID, CounterAssetID: btcID, NumBids: 1, UpdatedAt: updatedAt}
+	require.NoError(t, store.InsertOrUpdateOrderbookStats(ctx, stats, nil))
+
+	r := New(store, hlog.DefaultLogger)
+	sub := r.hub.subscribe(r.hub.orderbooks, nil)
+	go r.PollUpdates(ctx, 10*time.Millisecond)
+
+	// The stats updated before polling started are not published, but the
+	// later ones are, even though they are older than our clock.
+	time.Sleep(50 * time.Millisecond)
+	stats.NumBids = 2
+	stats.UpdatedAt = updatedAt.Add(time.Minute)
+	require.NoError(t, store.InsertOrUpdateOrderbookStats(ctx, stats, nil))
+
+	select {
+	case update := <-sub.updates:
+		orderbook := update.(*marketOrderbook)
+		assert.Equal(t, "XLM_BTC", orderbook.TradePair)
+		assert.Equal(t, BigInt(2), orderbook.OrderbookStats.BidCount)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected an orderbook update")
+	}
+}
@@ -43,6 +43,27 @@ type MarketStats struct {
 	SpreadMidPoint   float64 `json:"spread_mid_point"`
 }
 
+// CandlesPage represents a page of the candles of a market.
+type CandlesPage struct {
+	Resolution string   `json:"resolution"`
+	Candles    []Candle `json:"candles"`
+	Next       string   `json:"next,omitempty"`
+}
+
+// Candle represents the OHLCV data of a market during the period of a
+// candle's resolution starting at its open time.
+type Candle struct {
+	OpenTime        int64   `json:"open_time"`
+	OpenTimeRFC3339 string  `json:"open_time_rfc3339"`
+	Open            float64 `json:"open"`
+	High            float64 `json:"high"`
+	Low             float64 `json:"low"`
+	Close           float64 `json:"close"`
+	BaseVolume      float64 `json:"base_volume"`
+	CounterVolume   float64 `json:"counter_volume"`
+	TradeCount      int32   `json:"trade_count"`
+}
+
 // Asset Sumary represents the collection of valid assets.
 type AssetSummary struct {
 	GeneratedAt        int64   `json:"generated_at"`
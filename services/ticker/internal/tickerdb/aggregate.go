@@ -0,0 +1,557 @@
+package tickerdb
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// snapshot holds the rows from which SQLiteStore and MemoryStore compute the
+// aggregated market data, with the same results as the SQL queries of
+// TickerSession. The Store tests run against every backend to keep them in
+// line: changes to the queries of queries_market.go must be made here too.
+type snapshot struct {
+	assets         map[int32]Asset
+	trades         []Trade
+	orderbookStats []OrderbookStats
+}
+
+func newSnapshot(assets []Asset, trades []Trade, orderbookStats []OrderbookStats) snapshot {
+	s := snapshot{
+		assets:         make(map[int32]Asset, len(assets)),
+		trades:         append([]Trade(nil), trades...),
+		orderbookStats: orderbookStats,
+	}
+	for _, a := range assets {
+		s.assets[a.ID] = a
+	}
+	// Trades closed at the same time are ordered by id to pick the open and
+	// close prices.
+	sort.SliceStable(s.trades, func(i, j int) bool {
+		ti, tj := s.trades[i], s.trades[j]
+		if !ti.LedgerCloseTime.Equal(tj.LedgerCloseTime) {
+			return ti.LedgerCloseTime.Before(tj.LedgerCloseTime)
+		}
+		return ti.ID < tj.ID
+	})
+	return s
+}
+
+// assetFilter selects the markets of a pair of assets.
+type assetFilter func(base, counter Asset) bool
+
+// tradeStats aggregates the trades of a market, which must be added in
+// order.
+type tradeStats struct {
+	baseVolume    float64
+	counterVolume float64
+	count         int64
+	open          float64
+	high          float64
+	low           float64
+	close         float64
+	first         time.Time
+	last          time.Time
+}
+
+func (ts *tradeStats) add(t Trade) {
+	if ts.count == 0 {
+		ts.open, ts.high, ts.low = t.Price, t.Price, t.Price
+		ts.first = t.LedgerCloseTime
+	}
+	ts.baseVolume += t.BaseAmount
+	ts.counterVolume += t.CounterAmount
+	ts.count++
+	if t.Price > ts.high {
+		ts.high = t.Price
+	}
+	if t.Price < ts.low {
+		ts.low = t.Price
+	}
+	ts.close = t.Price
+	ts.last = t.LedgerCloseTime
+}
+
+// aggOrderbook is a row of the aggregated_orderbook view.
+type aggOrderbook struct {
+	baseAssetCode    string
+	counterAssetCode string
+	numBids          int
+	bidVolume        float64
+	highestBid       float64
+	numAsks          int
+	askVolume        float64
+	lowestAsk        float64
+}
+
+// tradePairName returns the name of the market of a pair of assets, in which
+// anchored assets are named after the asset they are anchored to.
+func tradePairName(base, counter Asset) string {
+	return anchorOrCode(base) + "_" + anchorOrCode(counter)
+}
+
+func anchorOrCode(a Asset) string {
+	if a.AnchorAssetCode != "" {
+		return a.AnchorAssetCode
+	}
+	return a.Code
+}
+
+// forEachTrade calls fn with the trades closed after <since>, in order,
+// along with their assets.
+func (s snapshot) forEachTrade(since time.Time, fn func(t Trade, base, counter Asset)) {
+	for _, t := range s.trades {
+		if !t.LedgerCloseTime.After(since) {
+			continue
+		}
+		base, ok := s.assets[t.BaseAssetID]
+		if !ok {
+			continue
+		}
+		counter, ok := s.assets[t.CounterAssetID]
+		if !ok {
+			continue
+		}
+		fn(t, base, counter)
+	}
+}
+
+// aggregatedOrderbooks returns the rows of the aggregated_orderbook view by
+// trade pair name.
+func (s snapshot) aggregatedOrderbooks() map[string]*aggOrderbook {
+	books := map[string]*aggOrderbook{}
+	for _, os := range s.orderbookStats {
+		base, ok := s.assets[os.BaseAssetID]
+		if !ok {
+			continue
+		}
+		counter, ok := s.assets[os.CounterAssetID]
+		if !ok {
+			continue
+		}
+
+		name := base.Code + "_" + counter.Code
+		book, ok := books[name]
+		if !ok {
+			book = &aggOrderbook{
+				baseAssetCode:    base.Code,
+				counterAssetCode: counter.Code,
+				highestBid:       os.HighestBid,
+				lowestAsk:        os.LowestAsk,
+			}
+			books[name] = book
+		}
+		book.numBids += os.NumBids
+		book.bidVolume += os.BidVolume
+		book.numAsks += os.NumAsks
+		book.askVolume += os.AskVolume
+		if os.HighestBid > book.highestBid {
+			book.highestBid = os.HighestBid
+		}
+		if os.LowestAsk < book.lowestAsk {
+			book.lowestAsk = os.LowestAsk
+		}
+	}
+	return books
+}
+
+// marketData returns the result of RetrieveMarketData.
+func (s snapshot) marketData(now time.Time) []Market {
+	day := map[string]*tradeStats{}
+	week := map[string]*tradeStats{}
+	s.forEachTrade(now.Add(-7*24*time.Hour), func(t Trade, base, counter Asset) {
+		if !base.IsValid || !counter.IsValid {
+			return
+		}
+		name := tradePairName(base, counter)
+		if week[name] == nil {
+			week[name] = &tradeStats{}
+		}
+		week[name].add(t)
+		if t.LedgerCloseTime.After(now.Add(-24 * time.Hour)) {
+			if day[name] == nil {
+				day[name] = &tradeStats{}
+			}
+			day[name].add(t)
+		}
+	})
+
+	books := s.aggregatedOrderbooks()
+	markets := []Market{}
+	for _, name := range sortedKeys(week) {
+		w := week[name]
+		m := Market{
+			TradePair:          name,
+			OpenPrice24h:       w.close,
+			LowestPrice24h:     w.close,
+			HighestPrice24h:    w.close,
+			BaseVolume7d:       w.baseVolume,
+			CounterVolume7d:    w.counterVolume,
+			TradeCount7d:       w.count,
+			OpenPrice7d:        w.open,
+			LowestPrice7d:      w.low,
+			HighestPrice7d:     w.high,
+			PriceChange7d:      w.close - w.open,
+			LastPriceCloseTime: w.last,
+			LastPrice:          w.close,
+		}
+		if d, ok := day[name]; ok {
+			m.BaseVolume24h = d.baseVolume
+			m.CounterVolume24h = d.counterVolume
+			m.TradeCount24h = d.count
+			m.OpenPrice24h = d.open
+			m.LowestPrice24h = d.low
+			m.HighestPrice24h = d.high
+			m.PriceChange24h = d.close - d.open
+			m.LastPriceCloseTime = d.last
+			m.LastPrice = d.close
+		}
+		if book, ok := books[name]; ok {
+			m.NumBids = book.numBids
+			m.BidVolume = book.bidVolume
+			m.HighestBid = book.highestBid
+			m.NumAsks = book.numAsks
+			m.AskVolume = book.askVolume
+			m.LowestAsk = book.lowestAsk
+		}
+		markets = append(markets, m)
+	}
+	return markets
+}
+
+// partialMarkets returns the result of RetrievePartialMarkets, i.e. the
+// markets of each pair of assets selected by filter.
+func (s snapshot) partialMarkets(now time.Time, numHoursAgo int, filter assetFilter) []PartialMarket {
+	type assetPair struct {
+		base, counter int32
+	}
+	stats := map[assetPair]*tradeStats{}
+	pairs := []assetPair{}
+	intervalStart := now.Add(-time.Duration(numHoursAgo) * time.Hour)
+	s.forEachTrade(intervalStart, func(t Trade, base, counter Asset) {
+		if !filter(base, counter) {
+			return
+		}
+		pair := assetPair{base.ID, counter.ID}
+		if stats[pair] == nil {
+			stats[pair] = &tradeStats{}
+			pairs = append(pairs, pair)
+		}
+		stats[pair].add(t)
+	})
+
+	orderbooks := map[assetPair]OrderbookStats{}
+	for _, os := range s.orderbookStats {
+		orderbooks[assetPair{os.BaseAssetID, os.CounterAssetID}] = os
+	}
+
+	markets := []PartialMarket{}
+	for _, pair := range pairs {
+		base, counter, ts := s.assets[pair.base], s.assets[pair.counter], stats[pair]
+		os := orderbooks[pair]
+		ios := orderbooks[assetPair{pair.counter, pair.base}]
+		markets = append(markets, PartialMarket{
+			TradePairName: fmt.Sprintf(
+				"%s:%s / %s:%s",
+				base.Code, base.IssuerAccount, counter.Code, counter.IssuerAccount,
+			),
+			BaseAssetID:          base.ID,
+			BaseAssetCode:        base.Code,
+			BaseAssetIssuer:      base.IssuerAccount,
+			BaseAssetType:        base.Type,
+			CounterAssetID:       counter.ID,
+			CounterAssetCode:     counter.Code,
+			CounterAssetIssuer:   counter.IssuerAccount,
+			CounterAssetType:     counter.Type,
+			BaseVolume:           ts.baseVolume,
+			CounterVolume:        ts.counterVolume,
+			TradeCount:           int32(ts.count),
+			Open:                 ts.open,
+			Low:                  ts.low,
+			High:                 ts.high,
+			Change:               ts.close - ts.open,
+			Close:                ts.close,
+			NumBids:              os.NumBids,
+			BidVolume:            os.BidVolume,
+			HighestBid:           os.HighestBid,
+			NumAsks:              os.NumAsks,
+			AskVolume:            os.AskVolume,
+			LowestAsk:            os.LowestAsk,
+			NumBidsReverse:       ios.NumBids,
+			BidVolumeReverse:     ios.BidVolume,
+			HighestBidReverse:    ios.HighestBid,
+			NumAsksReverse:       ios.NumAsks,
+			AskVolumeReverse:     ios.AskVolume,
+			LowestAskReverse:     ios.LowestAsk,
+			IntervalStart:        intervalStart,
+			FirstLedgerCloseTime: ts.first,
+			LastLedgerCloseTime:  ts.last,
+		})
+	}
+	sort.SliceStable(markets, func(i, j int) bool {
+		return markets[i].TradePairName < markets[j].TradePairName
+	})
+	return markets
+}
+
+// partialAggMarkets returns the result of RetrievePartialAggMarkets, i.e.
+// the markets of each trade pair name selected by filter.
+func (s snapshot) partialAggMarkets(now time.Time, numHoursAgo int, filter assetFilter) []PartialMarket {
+	stats := map[string]*tradeStats{}
+	reverseNames := map[string]string{}
+	intervalStart := now.Add(-time.Duration(numHoursAgo) * time.Hour)
+	s.forEachTrade(intervalStart, func(t Trade, base, counter Asset) {
+		if !filter(base, counter) {
+			return
+		}
+		name := tradePairName(base, counter)
+		if stats[name] == nil {
+			stats[name] = &tradeStats{}
+			reverseNames[name] = tradePairName(counter, base)
+		}
+		stats[name].add(t)
+	})
+
+	books := s.aggregatedOrderbooks()
+	markets := []PartialMarket{}
+	for _, name := range sortedKeys(stats) {
+		ts := stats[name]
+		m := PartialMarket{
+			TradePairName:        name,
+			BaseVolume:           ts.baseVolume,
+			CounterVolume:        ts.counterVolume,
+			TradeCount:           int32(ts.count),
+			Open:                 ts.open,
+			Low:                  ts.low,
+			High:                 ts.high,
+			Change:               ts.close - ts.open,
+			Close:                ts.close,
+			IntervalStart:        intervalStart,
+			FirstLedgerCloseTime: ts.first,
+			LastLedgerCloseTime:  ts.last,
+		}
+		if book, ok := books[name]; ok {
+			m.BaseAssetCode = book.baseAssetCode
+			m.CounterAssetCode = book.counterAssetCode
+			m.NumBids = book.numBids
+			m.BidVolume = book.bidVolume
+			m.HighestBid = book.highestBid
+			m.NumAsks = book.numAsks
+			m.AskVolume = book.askVolume
+			m.LowestAsk = book.lowestAsk
+		}
+		if book, ok := books[reverseNames[name]]; ok {
+			m.NumBidsReverse = book.numBids
+			m.BidVolumeReverse = book.bidVolume
+			m.HighestBidReverse = book.highestBid
+			m.NumAsksReverse = book.numAsks
+			m.AskVolumeReverse = book.askVolume
+			m.LowestAskReverse = book.lowestAsk
+		}
+		markets = append(markets, m)
+	}
+	return markets
+}
+
+// relevantMarkets7D returns the result of Retrieve7DRelevantMarkets.
+func (s snapshot) relevantMarkets7D(now time.Time) []PartialMarket {
+	seen := map[[2]int32]bool{}
+	markets := []PartialMarket{}
+	s.forEachTrade(now.Add(-7*24*time.Hour), func(t Trade, base, counter Asset) {
+		if !base.IsValid || !counter.IsValid || seen[[2]int32{base.ID, counter.ID}] {
+			return
+		}
+		seen[[2]int32{base.ID, counter.ID}] = true
+		markets = append(markets, PartialMarket{
+			BaseAssetID:        base.ID,
+			BaseAssetType:      base.Type,
+			BaseAssetCode:      base.Code,
+			BaseAssetIssuer:    base.IssuerAccount,
+			CounterAssetID:     counter.ID,
+			CounterAssetType:   counter.Type,
+			CounterAssetCode:   counter.Code,
+			CounterAssetIssuer: counter.IssuerAccount,
+		})
+	})
+	return markets
+}
+
+// candles returns the candles of <resolution> computed from the trades
+// closed since <since>, as upserted by UpsertCandles.
+func (s snapshot) candles(now time.Time, resolution time.Duration, since time.Time) []Candle {
+	type candleKey struct {
+		base, counter int32
+		openTime      int64
+	}
+	seconds := int64(resolution / time.Second)
+	stats := map[candleKey]*tradeStats{}
+	keys := []candleKey{}
+	for _, t := range s.trades {
+		if t.LedgerCloseTime.Before(since) {
+			continue
+		}
+		key := candleKey{t.BaseAssetID, t.CounterAssetID, t.LedgerCloseTime.Unix() / seconds * seconds}
+		if stats[key] == nil {
+			stats[key] = &tradeStats{}
+			keys = append(keys, key)
+		}
+		stats[key].add(t)
+	}
+
+	candles := make([]Candle, 0, len(keys))
+	for _, key := range keys {
+		ts := stats[key]
+		candles = append(candles, Candle{
+			BaseAssetID:    key.base,
+			CounterAssetID: key.counter,
+			Resolution:     int32(seconds),
+			OpenTime:       time.Unix(key.openTime, 0).UTC(),
+			Open:           ts.open,
+			High:           ts.high,
+			Low:            ts.low,
+			Close:          ts.close,
+			BaseVolume:     ts.baseVolume,
+			CounterVolume:  ts.counterVolume,
+			TradeCount:     int32(ts.count),
+			UpdatedAt:      now,
+		})
+	}
+	return candles
+}
+
+// marketTrade joins a trade with its assets, and returns false if one of
+// them is unknown.
+func (s snapshot) marketTrade(t Trade) (MarketTrade, bool) {
+	base, ok := s.assets[t.BaseAssetID]
+	if !ok {
+		return MarketTrade{}, false
+	}
+	counter, ok := s.assets[t.CounterAssetID]
+	if !ok {
+		return MarketTrade{}, false
+	}
+	return MarketTrade{
+		ID:                 t.ID,
+		HorizonID:          t.HorizonID,
+		LedgerCloseTime:    t.LedgerCloseTime,
+		TradePairName:      tradePairName(base, counter),
+		BaseAssetCode:      base.Code,
+		BaseAssetIssuer:    base.IssuerAccount,
+		CounterAssetCode:   counter.Code,
+		CounterAssetIssuer: counter.IssuerAccount,
+		BaseAmount:         t.BaseAmount,
+		CounterAmount:      t.CounterAmount,
+		BaseIsSeller:       t.BaseIsSeller,
+		Price:              t.Price,
+	}, true
+}
+
+// marketOrderbookStats returns the result of GetOrderbookStatsUpdatedAfter.
+func (s snapshot) marketOrderbookStats(after time.Time) []MarketOrderbookStats {
+	stats := []MarketOrderbookStats{}
+	for _, os := range s.orderbookStats {
+		if !os.UpdatedAt.After(after) {
+			continue
+		}
+		base, ok := s.assets[os.BaseAssetID]
+		if !ok {
+			continue
+		}
+		counter, ok := s.assets[os.CounterAssetID]
+		if !ok {
+			continue
+		}
+		stats = append(stats, MarketOrderbookStats{
+			TradePairName:      tradePairName(base, counter),
+			BaseAssetCode:      base.Code,
+			BaseAssetIssuer:    base.IssuerAccount,
+			CounterAssetCode:   counter.Code,
+			CounterAssetIssuer: counter.IssuerAccount,
+			NumBids:            os.NumBids,
+			BidVolume:          os.BidVolume,
+			HighestBid:         os.HighestBid,
+			NumAsks:            os.NumAsks,
+			AskVolume:          os.AskVolume,
+			LowestAsk:          os.LowestAsk,
+			Spread:             os.Spread,
+			SpreadMidPoint:     os.SpreadMidPoint,
+			UpdatedAt:          os.UpdatedAt,
+		})
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].UpdatedAt.Before(stats[j].UpdatedAt)
+	})
+	return stats
+}
+
+// partialMarketsFilter returns the filter of RetrievePartialMarkets.
+func partialMarketsFilter(
+	baseAssetCode *string,
+	baseAssetIssuer *string,
+	counterAssetCode *string,
+	counterAssetIssuer *string,
+) assetFilter {
+	baseAssetCode, baseAssetIssuer, counterAssetCode, counterAssetIssuer = orderBaseAndCounter(
+		baseAssetCode,
+		baseAssetIssuer,
+		counterAssetCode,
+		counterAssetIssuer,
+	)
+	matches := func(v string, filter *string) bool {
+		return filter == nil || v == *filter
+	}
+	return func(base, counter Asset) bool {
+		return base.IsValid && counter.IsValid &&
+			matches(base.Code, baseAssetCode) &&
+			matches(base.IssuerAccount, baseAssetIssuer) &&
+			matches(counter.Code, counterAssetCode) &&
+			matches(counter.IssuerAccount, counterAssetIssuer)
+	}
+}
+
+// partialAggMarketsFilter returns the filter of RetrievePartialAggMarkets.
+func partialAggMarketsFilter(code *string, pairNames *[]*string) (assetFilter, error) {
+	if code != nil && pairNames != nil {
+		return nil, errors.New("code and pairNames cannot both be non-nil")
+	}
+
+	if pairNames != nil {
+		type codePair struct {
+			base, counter string
+		}
+		pairs := map[codePair]bool{}
+		for _, pairName := range *pairNames {
+			if pairName == nil {
+				continue
+			}
+			bCode, cCode, err := getBaseAndCounterCodes(*pairName)
+			if err != nil {
+				return nil, err
+			}
+			pairs[codePair{bCode, cCode}] = true
+		}
+		return func(base, counter Asset) bool {
+			return base.IsValid && counter.IsValid && pairs[codePair{base.Code, counter.Code}]
+		}, nil
+	}
+
+	if code != nil {
+		return func(base, counter Asset) bool {
+			return (base.IsValid && base.Code == *code) || (counter.IsValid && counter.Code == *code)
+		}, nil
+	}
+
+	return func(base, counter Asset) bool {
+		return base.IsValid && counter.IsValid
+	}, nil
+}
+
+func sortedKeys(m map[string]*tradeStats) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
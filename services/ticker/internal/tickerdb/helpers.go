@@ -187,3 +187,21 @@ func (s *TickerSession) performUpsertQuery(ctx context.Context, dbStruct interfa
 	_, err := s.ExecRaw(ctx, qs, dbValues...)
 	return err
 }
+
+// copyDBFields copies the "db"-tagged fields of the struct pointed to by src
+// to the struct pointed to by dst, except the "id" and the fields listed in
+// preserveFields, emulating the update of an upsert query.
+func copyDBFields(dst interface{}, src interface{}, preserveFields []string) {
+	preserved := map[string]bool{"id": true, "-": true}
+	for _, field := range preserveFields {
+		preserved[field] = true
+	}
+
+	d := reflect.ValueOf(dst).Elem()
+	s := reflect.ValueOf(src).Elem()
+	for i := 0; i < s.Type().NumField(); i++ {
+		if !preserved[s.Type().Field(i).Tag.Get("db")] {
+			d.Field(i).Set(s.Field(i))
+		}
+	}
+}
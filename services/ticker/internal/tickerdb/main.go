@@ -1,12 +1,14 @@
 package tickerdb
 
 import (
+	"context"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	migrate "github.com/rubenv/sql-migrate"
 	bdata "github.com/stellar/go/services/ticker/internal/tickerdb/migrations"
 	"github.com/stellar/go/support/db"
+	supportmigrate "github.com/stellar/go/support/db/migrate"
 )
 
 //go:generate go-bindata -nometadata -ignore .+\.go$ -pkg bdata -o migrations/bindata.go ./...
@@ -99,6 +101,25 @@ type OrderbookStats struct {
 	UpdatedAt      time.Time `db:"updated_at"`
 }
 
+// Candle represents an entry on the candles table, i.e. the OHLCV data of
+// the trades of a pair of assets during the <Resolution> seconds starting
+// at <OpenTime>.
+type Candle struct {
+	ID             int32     `db:"id"`
+	BaseAssetID    int32     `db:"base_asset_id"`
+	CounterAssetID int32     `db:"counter_asset_id"`
+	Resolution     int32     `db:"resolution"`
+	OpenTime       time.Time `db:"open_time"`
+	Open           float64   `db:"open"`
+	High           float64   `db:"high"`
+	Low            float64   `db:"low"`
+	Close          float64   `db:"close"`
+	BaseVolume     float64   `db:"base_volume"`
+	CounterVolume  float64   `db:"counter_volume"`
+	TradeCount     int32     `db:"trade_count"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
 // Market represent the aggregated market data retrieved from the database.
 // Note: this struct does *not* directly map to a db entity.
 type Market struct {
@@ -166,6 +187,44 @@ type PartialMarket struct {
 	LastLedgerCloseTime  time.Time `db:"last_ledger_close_time"`
 }
 
+// MarketTrade represents a trade along with the pair name, codes and
+// issuers of its assets.
+// Note: this struct does *not* directly map to a db entity.
+type MarketTrade struct {
+	ID                 int32     `db:"id"`
+	HorizonID          string    `db:"horizon_id"`
+	LedgerCloseTime    time.Time `db:"ledger_close_time"`
+	TradePairName      string    `db:"trade_pair_name"`
+	BaseAssetCode      string    `db:"base_asset_code"`
+	BaseAssetIssuer    string    `db:"base_asset_issuer"`
+	CounterAssetCode   string    `db:"counter_asset_code"`
+	CounterAssetIssuer string    `db:"counter_asset_issuer"`
+	BaseAmount         float64   `db:"base_amount"`
+	CounterAmount      float64   `db:"counter_amount"`
+	BaseIsSeller       bool      `db:"base_is_seller"`
+	Price              float64   `db:"price"`
+}
+
+// MarketOrderbookStats represents the orderbook stats of a pair of assets
+// along with the pair name, codes and issuers of the assets.
+// Note: this struct does *not* directly map to a db entity.
+type MarketOrderbookStats struct {
+	TradePairName      string    `db:"trade_pair_name"`
+	BaseAssetCode      string    `db:"base_asset_code"`
+	BaseAssetIssuer    string    `db:"base_asset_issuer"`
+	CounterAssetCode   string    `db:"counter_asset_code"`
+	CounterAssetIssuer string    `db:"counter_asset_issuer"`
+	NumBids            int       `db:"num_bids"`
+	BidVolume          float64   `db:"bid_volume"`
+	HighestBid         float64   `db:"highest_bid"`
+	NumAsks            int       `db:"num_asks"`
+	AskVolume          float64   `db:"ask_volume"`
+	LowestAsk          float64   `db:"lowest_ask"`
+	Spread             float64   `db:"spread"`
+	SpreadMidPoint     float64   `db:"spread_mid_point"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
+
 // CreateSession returns a new TickerSession that connects to the given db settings
 func CreateSession(driverName, dataSourceName string) (session TickerSession, err error) {
 	dbconn, err := sqlx.Connect(driverName, dataSourceName)
@@ -177,12 +236,18 @@ func CreateSession(driverName, dataSourceName string) (session TickerSession, er
 	return
 }
 
+// Migrate updates the database to the latest schema version, and returns the
+// number of migrations applied.
+func (s *TickerSession) Migrate() (int, error) {
+	return MigrateDB(s)
+}
+
 func MigrateDB(s *TickerSession) (int, error) {
-	migrations := &migrate.AssetMigrationSource{
-		Asset:    bdata.Asset,
-		AssetDir: bdata.AssetDir,
-		Dir:      "migrations",
+	migrator := &supportmigrate.Migrator{
+		DB:     s.DB,
+		Source: supportmigrate.BindataSource(bdata.Asset, bdata.AssetDir, "migrations"),
 	}
 	migrate.SetTable("migrations")
-	return migrate.Exec(s.DB.DB, "postgres", migrations, migrate.Up)
+	ids, err := migrator.Migrate(context.Background(), supportmigrate.Up, 0)
+	return len(ids), err
 }
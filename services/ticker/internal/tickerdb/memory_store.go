@@ -0,0 +1,436 @@
+package tickerdb
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store keeping its data in memory, to be used in tests.
+// Its data is lost when the process exits, and cannot be shared with other
+// processes.
+type MemoryStore struct {
+	mutex          sync.RWMutex
+	migrated       bool
+	issuers        []Issuer
+	assets         []Asset
+	trades         []Trade
+	orderbookStats []OrderbookStats
+	candles        []Candle
+	lastTradeID    int32
+	lastCandleID   int32
+}
+
+// NewMemoryStore returns an empty MemoryStore. Like the other stores, it
+// must be migrated before use to add the native asset.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Migrate adds the native asset and its issuer to the store, as the
+// migrations of the other stores do.
+func (s *MemoryStore) Migrate() (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.migrated {
+		return 0, nil
+	}
+	s.migrated = true
+
+	now := time.Now()
+	s.issuers = append(s.issuers, Issuer{
+		ID:         int32(len(s.issuers) + 1),
+		PublicKey:  "native",
+		Name:       "Stellar Development Foundation",
+		URL:        "http://stellar.org",
+		OrgTwitter: "https://twitter.com/stellarorg",
+	})
+	s.assets = append(s.assets, Asset{
+		ID:                      int32(len(s.assets) + 1),
+		Code:                    "XLM",
+		IssuerAccount:           "native",
+		Type:                    "native",
+		AssetControlledByDomain: true,
+		IsValid:                 true,
+		LastValid:               now,
+		LastChecked:             now,
+		DisplayDecimals:         7,
+		Name:                    "Stellar Lumens",
+		IssuerID:                s.issuers[len(s.issuers)-1].ID,
+	})
+	return 1, nil
+}
+
+// Close does nothing, the data of the store is kept until it is garbage
+// collected.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// InsertOrUpdateAsset inserts an Asset on the store (if new), or updates an
+// existing one, preserving the fields in preserveFields.
+func (s *MemoryStore) InsertOrUpdateAsset(ctx context.Context, a *Asset, preserveFields []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.assets {
+		if s.assets[i].Code == a.Code && s.assets[i].IssuerAccount == a.IssuerAccount {
+			copyDBFields(&s.assets[i], a, preserveFields)
+			return nil
+		}
+	}
+	asset := *a
+	asset.ID = int32(len(s.assets) + 1)
+	asset.Issuer = Issuer{}
+	s.assets = append(s.assets, asset)
+	return nil
+}
+
+// GetAssetByCodeAndIssuerAccount searches for an Asset with the given code
+// and public key, and returns its ID in case it is found.
+func (s *MemoryStore) GetAssetByCodeAndIssuerAccount(ctx context.Context,
+	code string,
+	issuerAccount string,
+) (found bool, id int32, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, a := range s.assets {
+		if a.Code == code && a.IssuerAccount == issuerAccount {
+			return true, a.ID, nil
+		}
+	}
+	return false, 0, nil
+}
+
+// GetAllValidAssets returns a slice with all valid assets in the store.
+func (s *MemoryStore) GetAllValidAssets(ctx context.Context) (assets []Asset, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, a := range s.assets {
+		if a.IsValid {
+			assets = append(assets, a)
+		}
+	}
+	return
+}
+
+// GetAssetsWithNestedIssuer returns a slice with all valid assets in the
+// store, also adding the nested Issuer attribute.
+func (s *MemoryStore) GetAssetsWithNestedIssuer(ctx context.Context) (assets []Asset, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	issuers := map[int32]Issuer{}
+	for _, i := range s.issuers {
+		issuers[i.ID] = i
+	}
+	for _, a := range s.assets {
+		issuer, ok := issuers[a.IssuerID]
+		if !a.IsValid || !ok {
+			continue
+		}
+		a.Issuer = issuer
+		assets = append(assets, a)
+	}
+	return
+}
+
+// InsertOrUpdateIssuer inserts an Issuer on the store (if new), or updates
+// an existing one, preserving the fields in preserveFields.
+func (s *MemoryStore) InsertOrUpdateIssuer(ctx context.Context, issuer *Issuer, preserveFields []string) (id int32, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.issuers {
+		if s.issuers[i].PublicKey == issuer.PublicKey {
+			copyDBFields(&s.issuers[i], issuer, preserveFields)
+			return s.issuers[i].ID, nil
+		}
+	}
+	inserted := *issuer
+	inserted.ID = int32(len(s.issuers) + 1)
+	s.issuers = append(s.issuers, inserted)
+	return inserted.ID, nil
+}
+
+// GetAllIssuers returns a slice with all issuers in the store.
+func (s *MemoryStore) GetAllIssuers(ctx context.Context) (issuers []Issuer, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append(issuers, s.issuers...), nil
+}
+
+// BulkInsertTrades inserts a slice of trades in the store. Trades that are
+// already in the store (i.e. horizon_id already exists) are ignored.
+func (s *MemoryStore) BulkInsertTrades(ctx context.Context, trades []Trade) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	existing := make(map[string]bool, len(s.trades))
+	for _, t := range s.trades {
+		existing[t.HorizonID] = true
+	}
+	for _, t := range trades {
+		if existing[t.HorizonID] {
+			continue
+		}
+		existing[t.HorizonID] = true
+		s.lastTradeID++
+		t.ID = s.lastTradeID
+		s.trades = append(s.trades, t)
+	}
+	return nil
+}
+
+// GetLastTrade returns the newest Trade object in the store, or
+// sql.ErrNoRows if there is none.
+func (s *MemoryStore) GetLastTrade(ctx context.Context) (trade Trade, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.trades) == 0 {
+		return Trade{}, sql.ErrNoRows
+	}
+	trade = s.trades[0]
+	for _, t := range s.trades[1:] {
+		if t.LedgerCloseTime.After(trade.LedgerCloseTime) {
+			trade = t
+		}
+	}
+	return trade, nil
+}
+
+// GetLastTradeID returns the id of the newest trade inserted in the store,
+// or 0 if there is none.
+func (s *MemoryStore) GetLastTradeID(ctx context.Context) (id int32, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if len(s.trades) == 0 {
+		return 0, nil
+	}
+	return s.trades[len(s.trades)-1].ID, nil
+}
+
+// GetTradesAfterID returns up to <limit> trades inserted after the trade with
+// id <id>, along with their assets, ordered by id.
+func (s *MemoryStore) GetTradesAfterID(ctx context.Context, id int32, limit int) (trades []MarketTrade, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	snap := newSnapshot(s.assets, nil, nil)
+	for _, t := range s.trades {
+		if len(trades) == limit {
+			break
+		}
+		if t.ID <= id {
+			continue
+		}
+		if trade, ok := snap.marketTrade(t); ok {
+			trades = append(trades, trade)
+		}
+	}
+	return
+}
+
+// DeleteOldTrades deletes trades in the store older than minDate.
+func (s *MemoryStore) DeleteOldTrades(ctx context.Context, minDate time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	trades := s.trades[:0]
+	for _, t := range s.trades {
+		if !t.LedgerCloseTime.Before(minDate) {
+			trades = append(trades, t)
+		}
+	}
+	s.trades = trades
+	return nil
+}
+
+// InsertOrUpdateOrderbookStats inserts an OrdebookStats entry on the store
+// (if new), or updates an existing one, preserving the fields in
+// preserveFields.
+func (s *MemoryStore) InsertOrUpdateOrderbookStats(ctx context.Context, o *OrderbookStats, preserveFields []string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := range s.orderbookStats {
+		if s.orderbookStats[i].BaseAssetID == o.BaseAssetID && s.orderbookStats[i].CounterAssetID == o.CounterAssetID {
+			copyDBFields(&s.orderbookStats[i], o, preserveFields)
+			return nil
+		}
+	}
+	stats := *o
+	stats.ID = int32(len(s.orderbookStats) + 1)
+	s.orderbookStats = append(s.orderbookStats, stats)
+	return nil
+}
+
+// GetOrderbookStatsUpdatedAfter returns the orderbook stats updated after
+// <after>, along with their assets, ordered by update time.
+func (s *MemoryStore) GetOrderbookStatsUpdatedAfter(ctx context.Context, after time.Time) ([]MarketOrderbookStats, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.snapshot().marketOrderbookStats(after), nil
+}
+
+// GetLastOrderbookStatsUpdate returns the time of the latest update of the
+// orderbook stats, or the zero time if there is none.
+func (s *MemoryStore) GetLastOrderbookStatsUpdate(ctx context.Context) (updatedAt time.Time, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, stats := range s.orderbookStats {
+		if stats.UpdatedAt.After(updatedAt) {
+			updatedAt = stats.UpdatedAt
+		}
+	}
+	return updatedAt, nil
+}
+
+// UpsertCandles computes the candles of <resolution> of every pair of assets
+// from the trades closed since <since>, replacing the candles previously
+// computed for the same periods.
+func (s *MemoryStore) UpsertCandles(ctx context.Context, resolution time.Duration, since time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, c := range s.snapshot().candles(time.Now(), resolution, since) {
+		s.upsertCandle(c)
+	}
+	return nil
+}
+
+func (s *MemoryStore) upsertCandle(c Candle) {
+	for i := range s.candles {
+		existing := &s.candles[i]
+		if existing.BaseAssetID == c.BaseAssetID &&
+			existing.CounterAssetID == c.CounterAssetID &&
+			existing.Resolution == c.Resolution &&
+			existing.OpenTime.Equal(c.OpenTime) {
+			c.ID = existing.ID
+			*existing = c
+			return
+		}
+	}
+	s.lastCandleID++
+	c.ID = s.lastCandleID
+	s.candles = append(s.candles, c)
+}
+
+// GetCandles returns up to <limit> candles of <resolution> of a pair of assets,
+// ordered by open time. If <cursor> is not nil, only the candles opened after
+// it (or before it if <desc> is true) are returned.
+func (s *MemoryStore) GetCandles(ctx context.Context,
+	baseAssetID int32,
+	counterAssetID int32,
+	resolution time.Duration,
+	cursor *time.Time,
+	desc bool,
+	limit int,
+) (candles []Candle, err error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	seconds := int32(resolution / time.Second)
+	for _, c := range s.candles {
+		if c.BaseAssetID != baseAssetID || c.CounterAssetID != counterAssetID || c.Resolution != seconds {
+			continue
+		}
+		if cursor != nil && ((!desc && !c.OpenTime.After(*cursor)) || (desc && !c.OpenTime.Before(*cursor))) {
+			continue
+		}
+		candles = append(candles, c)
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		if desc {
+			return candles[i].OpenTime.After(candles[j].OpenTime)
+		}
+		return candles[i].OpenTime.Before(candles[j].OpenTime)
+	})
+	if len(candles) > limit {
+		candles = candles[:limit]
+	}
+	return
+}
+
+// DeleteOldCandles deletes the candles of <resolution> opened before minDate.
+func (s *MemoryStore) DeleteOldCandles(ctx context.Context, resolution time.Duration, minDate time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	seconds := int32(resolution / time.Second)
+	candles := s.candles[:0]
+	for _, c := range s.candles {
+		if c.Resolution != seconds || !c.OpenTime.Before(minDate) {
+			candles = append(candles, c)
+		}
+	}
+	s.candles = candles
+	return nil
+}
+
+// RetrieveMarketData retrieves the 24h- and 7d aggregated market data for all
+// markets that were active during this period.
+func (s *MemoryStore) RetrieveMarketData(ctx context.Context) ([]Market, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.snapshot().marketData(time.Now()), nil
+}
+
+// RetrievePartialAggMarkets retrieves the aggregated market data for all
+// markets (or for a specific one if PairNames != nil) for a given period.
+func (s *MemoryStore) RetrievePartialAggMarkets(ctx context.Context,
+	code *string,
+	pairNames *[]*string,
+	numHoursAgo int,
+) ([]PartialMarket, error) {
+	filter, err := partialAggMarketsFilter(code, pairNames)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.snapshot().partialAggMarkets(time.Now(), numHoursAgo, filter), nil
+}
+
+// RetrievePartialMarkets retrieves data in the PartialMarket format from the
+// store, optionally filtered by base and counter asset.
+func (s *MemoryStore) RetrievePartialMarkets(ctx context.Context,
+	baseAssetCode *string,
+	baseAssetIssuer *string,
+	counterAssetCode *string,
+	counterAssetIssuer *string,
+	numHoursAgo int,
+) ([]PartialMarket, error) {
+	filter := partialMarketsFilter(baseAssetCode, baseAssetIssuer, counterAssetCode, counterAssetIssuer)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.snapshot().partialMarkets(time.Now(), numHoursAgo, filter), nil
+}
+
+// Retrieve7DRelevantMarkets retrieves the base and counter asset data of the markets
+// that were relevant in the last 7-day period.
+func (s *MemoryStore) Retrieve7DRelevantMarkets(ctx context.Context) ([]PartialMarket, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.snapshot().relevantMarkets7D(time.Now()), nil
+}
+
+// snapshot must be called with the mutex held.
+func (s *MemoryStore) snapshot() snapshot {
+	return newSnapshot(s.assets, s.trades, s.orderbookStats)
+}
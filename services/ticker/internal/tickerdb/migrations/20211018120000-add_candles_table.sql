@@ -0,0 +1,28 @@
+
+-- +migrate Up
+CREATE TABLE candles (
+    id serial NOT NULL PRIMARY KEY,
+
+    base_asset_id integer REFERENCES assets (id) NOT NULL,
+    counter_asset_id integer REFERENCES assets (id) NOT NULL,
+
+    resolution integer NOT NULL,
+    open_time timestamptz NOT NULL,
+
+    open double precision NOT NULL,
+    high double precision NOT NULL,
+    low double precision NOT NULL,
+    close double precision NOT NULL,
+
+    base_volume double precision NOT NULL,
+    counter_volume double precision NOT NULL,
+    trade_count integer NOT NULL,
+
+    updated_at timestamptz NOT NULL
+);
+ALTER TABLE ONLY public.candles
+    ADD CONSTRAINT candles_base_counter_resolution_open_time_key UNIQUE (base_asset_id, counter_asset_id, resolution, open_time);
+CREATE INDEX candles_resolution_open_time_idx ON public.candles USING btree (resolution, open_time);
+
+-- +migrate Down
+DROP TABLE candles;
@@ -10,6 +10,7 @@
 // migrations/20190411165735-data_seed_and_indices.sql (1.522kB)
 // migrations/20190425110313-add_orderbook_stats.sql (749B)
 // migrations/20190426092321-add_aggregated_orderbook_view.sql (831B)
+// migrations/20211018120000-add_candles_table.sql (877B)
 
 package bdata
 
@@ -278,6 +279,26 @@ func migrations20190426092321Add_aggregated_orderbook_viewSql() (*asset, error)
 	return a, nil
 }
 
+var _migrations20211018120000Add_candles_tableSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x93\xcb\x4e\xc3\x30\x10\x45\xf7\xfe\x8a\x59\xb6\x22\xe5\x07\xba\x0a\x8d\x41\x11\xc1\x29\x69\x22\xd1\x95\xe5\x26\xa3\xd6\x22\x2f\xd9\x0e\x05\xbe\x1e\xd7\x4a\x1f\x29\x85\x22\xb2\xf0\x26\xe7\xde\xf1\xcc\x5c\x93\xc9\x04\x6e\x2a\xb9\x56\xc2\x20\x64\x2d\x99\x25\xd4\x4f\x29\xa4\xfe\x5d\x44\x21\x17\x75\x51\xa2\x86\x11\x01\xfb\xc9\x02\x34\x2a\x29\x4a\x60\x71\x0a\x2c\x8b\x22\x98\x27\xe1\x93\x9f\x2c\xe1\x91\x2e\x3d\xe2\xa0\x95\xd0\xc8\x85\xd6\x68\xb8\xe5\x65\x6d\x70\x8d\x0a\x12\x7a\x4f\x13\xca\x66\x74\x01\xee\x9f\xb5\x94\xc5\xf8\xe0\xe3\x39\x69\xde\x74\x16\x57\xff\x50\x3b\xb9\x42\xdd\x94\x9d\x91\x4d\x7d\x10\x0e\xfd\x9b\x16\x6b\x6e\x64\x85\xb0\x3b\xb4\x11\x55\x6b\x3e\xcf\x5d\x76\x10\x14\x4d\xb7\x2a\x11\x5a\x85\xb9\xd4\x3b\xc3\xa1\xd1\x46\xae\x37\xd7\x98\xb2\xd9\x5e\x43\xf2\xb2\xd1\xf8\x1b\x74\x1c\xe8\x9b\xed\xac\xc2\xab\x86\xfd\x00\xff\x46\x1b\x25\x0a\xe4\x4e\x73\x61\x60\x0e\xe9\xda\xc2\xc6\xa2\xe0\xc2\x5c\x1c\x19\x19\x4f\x89\x1f\xa5\x34\xe9\xe3\x12\xb3\x68\x09\xad\x2d\x2a\xf3\xdb\x3e\x3a\xce\xc6\x0f\x02\x98\xc5\x6c\x91\x26\x7e\xc8\xd2\x7d\xaa\xb8\xeb\x6c\x7f\xe7\xe3\xf6\xf8\x61\x4f\xfc\x15\x3f\x20\x63\xe1\x73\x46\x61\x34\x08\x96\xf7\x2d\x2c\xde\xc9\xfe\xbd\xe3\xaa\xed\x0d\xfb\x44\x87\x2c\xa0\x2f\x87\xda\x17\xcb\xc9\xe2\xdd\xf6\x70\xd6\x01\x64\x8b\x90\x3d\xc0\xca\x28\x44\x18\xfd\x54\x84\x9c\x3e\xa3\xa0\xd9\xd6\x24\x48\xe2\xf9\xf0\x19\x4d\xc9\x17\x4d\xfb\x8a\x3b\x6d\x03\x00\x00")
+
+func migrations20211018120000Add_candles_tableSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations20211018120000Add_candles_tableSql,
+		"migrations/20211018120000-add_candles_table.sql",
+	)
+}
+
+func migrations20211018120000Add_candles_tableSql() (*asset, error) {
+	bytes, err := migrations20211018120000Add_candles_tableSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/20211018120000-add_candles_table.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf1, 0x71, 0x55, 0x51, 0x55, 0x5, 0x3, 0x14, 0x72, 0xb7, 0xd, 0xdd, 0xf9, 0x69, 0x5d, 0xc9, 0x96, 0x19, 0xfc, 0xda, 0xac, 0x59, 0x8a, 0xb8, 0x29, 0xdd, 0x48, 0xff, 0x7b, 0xf, 0x25, 0xfb}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -379,17 +400,20 @@ var _bindata = map[string]func() (*asset, error){
 	"migrations/20190411165735-data_seed_and_indices.sql":           migrations20190411165735Data_seed_and_indicesSql,
 	"migrations/20190425110313-add_orderbook_stats.sql":             migrations20190425110313Add_orderbook_statsSql,
 	"migrations/20190426092321-add_aggregated_orderbook_view.sql":   migrations20190426092321Add_aggregated_orderbook_viewSql,
+	"migrations/20211018120000-add_candles_table.sql":               migrations20211018120000Add_candles_tableSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -433,6 +457,7 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		"20190411165735-data_seed_and_indices.sql":           &bintree{migrations20190411165735Data_seed_and_indicesSql, map[string]*bintree{}},
 		"20190425110313-add_orderbook_stats.sql":             &bintree{migrations20190425110313Add_orderbook_statsSql, map[string]*bintree{}},
 		"20190426092321-add_aggregated_orderbook_view.sql":   &bintree{migrations20190426092321Add_aggregated_orderbook_viewSql, map[string]*bintree{}},
+		"20211018120000-add_candles_table.sql":               &bintree{migrations20211018120000Add_candles_tableSql, map[string]*bintree{}},
 	}},
 }}
 
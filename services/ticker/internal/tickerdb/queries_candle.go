@@ -0,0 +1,93 @@
+package tickerdb
+
+import (
+	"context"
+	"time"
+)
+
+// UpsertCandles computes the candles of <resolution> of every pair of assets
+// from the trades closed since <since>, replacing the candles previously
+// computed for the same periods. <since> should be the open time of a candle,
+// otherwise the first candle is computed from part of its trades only.
+func (s *TickerSession) UpsertCandles(ctx context.Context, resolution time.Duration, since time.Time) error {
+	seconds := int32(resolution / time.Second)
+	_, err := s.ExecRaw(ctx, upsertCandlesQuery, seconds, seconds, seconds, since)
+	return err
+}
+
+// GetCandles returns up to <limit> candles of <resolution> of a pair of assets,
+// ordered by open time. If <cursor> is not nil, only the candles opened after
+// it (or before it if <desc> is true) are returned.
+func (s *TickerSession) GetCandles(ctx context.Context,
+	baseAssetID int32,
+	counterAssetID int32,
+	resolution time.Duration,
+	cursor *time.Time,
+	desc bool,
+	limit int,
+) (candles []Candle, err error) {
+	q := "SELECT * FROM candles WHERE base_asset_id = ? AND counter_asset_id = ? AND resolution = ?"
+	args := []interface{}{baseAssetID, counterAssetID, int32(resolution / time.Second)}
+
+	order := "ASC"
+	comparison := ">"
+	if desc {
+		order = "DESC"
+		comparison = "<"
+	}
+	if cursor != nil {
+		q += " AND open_time " + comparison + " ?"
+		args = append(args, *cursor)
+	}
+	q += " ORDER BY open_time " + order + " LIMIT ?"
+	args = append(args, limit)
+
+	err = s.SelectRaw(ctx, &candles, q, args...)
+	return
+}
+
+// DeleteOldCandles deletes the candles of <resolution> opened before minDate.
+func (s *TickerSession) DeleteOldCandles(ctx context.Context, resolution time.Duration, minDate time.Time) error {
+	_, err := s.ExecRaw(ctx,
+		"DELETE FROM candles WHERE resolution = ? AND open_time < ?",
+		int32(resolution/time.Second),
+		minDate,
+	)
+	return err
+}
+
+// upsertCandlesQuery groups the trades of each pair of assets by the period of
+// the resolution they were closed in. Trades closed at the same time are
+// ordered by id to pick the open and close prices.
+var upsertCandlesQuery = `
+INSERT INTO candles (
+	base_asset_id, counter_asset_id, resolution, open_time,
+	open, high, low, close,
+	base_volume, counter_volume, trade_count, updated_at
+)
+SELECT
+	t.base_asset_id,
+	t.counter_asset_id,
+	?::integer,
+	to_timestamp(floor(extract(epoch from t.ledger_close_time) / ?::integer) * ?::integer) AS open_time,
+	(array_agg(t.price ORDER BY t.ledger_close_time ASC, t.id ASC))[1],
+	max(t.price),
+	min(t.price),
+	(array_agg(t.price ORDER BY t.ledger_close_time DESC, t.id DESC))[1],
+	sum(t.base_amount),
+	sum(t.counter_amount),
+	count(*),
+	now()
+FROM trades AS t
+WHERE t.ledger_close_time >= ?
+GROUP BY t.base_asset_id, t.counter_asset_id, open_time
+ON CONFLICT ON CONSTRAINT candles_base_counter_resolution_open_time_key DO UPDATE SET
+	open = EXCLUDED.open,
+	high = EXCLUDED.high,
+	low = EXCLUDED.low,
+	close = EXCLUDED.close,
+	base_volume = EXCLUDED.base_volume,
+	counter_volume = EXCLUDED.counter_volume,
+	trade_count = EXCLUDED.trade_count,
+	updated_at = EXCLUDED.updated_at;
+`
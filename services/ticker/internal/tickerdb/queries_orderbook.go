@@ -2,6 +2,7 @@ package tickerdb
 
 import (
 	"context"
+	"time"
 )
 
 // InsertOrUpdateOrderbookStats inserts an OrdebookStats entry on the database (if new),
@@ -9,3 +10,46 @@ import (
 func (s *TickerSession) InsertOrUpdateOrderbookStats(ctx context.Context, o *OrderbookStats, preserveFields []string) (err error) {
 	return s.performUpsertQuery(ctx, *o, "orderbook_stats", "orderbook_stats_base_counter_asset_key", preserveFields)
 }
+
+// GetOrderbookStatsUpdatedAfter returns the orderbook stats updated after
+// <after>, along with their assets, ordered by update time.
+func (s *TickerSession) GetOrderbookStatsUpdatedAfter(ctx context.Context, after time.Time) (stats []MarketOrderbookStats, err error) {
+	err = s.SelectRaw(ctx, &stats, orderbookStatsUpdatedAfterQuery, after)
+	return
+}
+
+// GetLastOrderbookStatsUpdate returns the time of the latest update of the
+// orderbook stats, or the zero time if there is none.
+func (s *TickerSession) GetLastOrderbookStatsUpdate(ctx context.Context) (updatedAt time.Time, err error) {
+	err = s.GetRaw(ctx, &updatedAt, "SELECT updated_at FROM orderbook_stats ORDER BY updated_at DESC LIMIT 1")
+	if s.NoRows(err) {
+		err = nil
+	}
+	return
+}
+
+var orderbookStatsUpdatedAfterQuery = `
+SELECT
+	concat(
+		COALESCE(NULLIF(bAsset.anchor_asset_code, ''), bAsset.code),
+		'_',
+		COALESCE(NULLIF(cAsset.anchor_asset_code, ''), cAsset.code)
+	) AS trade_pair_name,
+	bAsset.code AS base_asset_code,
+	bAsset.issuer_account AS base_asset_issuer,
+	cAsset.code AS counter_asset_code,
+	cAsset.issuer_account AS counter_asset_issuer,
+	os.num_bids,
+	os.bid_volume,
+	os.highest_bid,
+	os.num_asks,
+	os.ask_volume,
+	os.lowest_ask,
+	os.spread,
+	os.spread_mid_point,
+	os.updated_at
+FROM orderbook_stats AS os
+	JOIN assets AS bAsset ON os.base_asset_id = bAsset.id
+	JOIN assets AS cAsset ON os.counter_asset_id = cAsset.id
+WHERE os.updated_at > ?
+ORDER BY os.updated_at ASC;`
@@ -32,6 +32,20 @@ func (s *TickerSession) GetLastTrade(ctx context.Context) (trade Trade, err erro
 	return
 }
 
+// GetLastTradeID returns the id of the newest trade inserted in the
+// database, or 0 if there is none.
+func (s *TickerSession) GetLastTradeID(ctx context.Context) (id int32, err error) {
+	err = s.GetRaw(ctx, &id, "SELECT COALESCE(MAX(id), 0) FROM trades")
+	return
+}
+
+// GetTradesAfterID returns up to <limit> trades inserted after the trade with
+// id <id>, along with their assets, ordered by id.
+func (s *TickerSession) GetTradesAfterID(ctx context.Context, id int32, limit int) (trades []MarketTrade, err error) {
+	err = s.SelectRaw(ctx, &trades, tradesAfterIDQuery, id, limit)
+	return
+}
+
 // DeleteOldTrades deletes trades in the database older than minDate.
 func (s *TickerSession) DeleteOldTrades(ctx context.Context, minDate time.Time) error {
 	_, err := s.ExecRaw(ctx, "DELETE FROM trades WHERE ledger_close_time < ?", minDate)
@@ -87,3 +101,28 @@ func performInsertTrades(ctx context.Context, s *TickerSession, trades []Trade)
 	_, err = s.ExecRaw(ctx, qs, dbValues...)
 	return
 }
+
+var tradesAfterIDQuery = `
+SELECT
+	t.id,
+	t.horizon_id,
+	t.ledger_close_time,
+	concat(
+		COALESCE(NULLIF(bAsset.anchor_asset_code, ''), bAsset.code),
+		'_',
+		COALESCE(NULLIF(cAsset.anchor_asset_code, ''), cAsset.code)
+	) AS trade_pair_name,
+	bAsset.code AS base_asset_code,
+	bAsset.issuer_account AS base_asset_issuer,
+	cAsset.code AS counter_asset_code,
+	cAsset.issuer_account AS counter_asset_issuer,
+	t.base_amount,
+	t.counter_amount,
+	t.base_is_seller,
+	t.price
+FROM trades AS t
+	JOIN assets AS bAsset ON t.base_asset_id = bAsset.id
+	JOIN assets AS cAsset ON t.counter_asset_id = cAsset.id
+WHERE t.id > ?
+ORDER BY t.id ASC
+LIMIT ?;`
@@ -0,0 +1,548 @@
+package tickerdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/services/ticker/internal/utils"
+	"github.com/stellar/go/support/db"
+)
+
+// SQLiteStore is a Store on a SQLite database, for deployments that do not
+// want to operate PostgreSQL. The aggregated market data is computed in Go
+// from the trades of the requested period rather than by the database, which
+// is fast enough for the trading volumes such deployments have.
+//
+// The "sqlite3" database driver, e.g. github.com/mattn/go-sqlite3, must be
+// registered by the caller.
+type SQLiteStore struct {
+	db.Session
+}
+
+// NewSQLiteStore returns a SQLiteStore connected to the SQLite database
+// <dataSourceName>.
+func NewSQLiteStore(dataSourceName string) (*SQLiteStore, error) {
+	dbconn, err := sqlx.Connect("sqlite3", dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLiteStore{Session: db.Session{DB: dbconn}}, nil
+}
+
+// Migrate updates the database to the latest schema version, and returns the
+// number of migrations applied.
+func (s *SQLiteStore) Migrate() (int, error) {
+	migrate.SetTable("migrations")
+	return migrate.Exec(s.DB.DB, "sqlite3", sqliteMigrations, migrate.Up)
+}
+
+// InsertOrUpdateAsset inserts an Asset on the database (if new),
+// or updates an existing one
+func (s *SQLiteStore) InsertOrUpdateAsset(ctx context.Context, a *Asset, preserveFields []string) error {
+	return s.performUpsertQuery(ctx, *a, "assets", "code, issuer_account", preserveFields)
+}
+
+// GetAssetByCodeAndIssuerAccount searches for an Asset with the given code
+// and public key, and returns its ID in case it is found.
+func (s *SQLiteStore) GetAssetByCodeAndIssuerAccount(ctx context.Context,
+	code string,
+	issuerAccount string,
+) (found bool, id int32, err error) {
+	var ids []int32
+	err = s.SelectRaw(ctx, &ids, "SELECT id FROM assets WHERE code = ? AND issuer_account = ?", code, issuerAccount)
+	if err != nil || len(ids) == 0 {
+		return
+	}
+	return true, ids[0], nil
+}
+
+// GetAllValidAssets returns a slice with all assets in the database
+// with is_valid = true
+func (s *SQLiteStore) GetAllValidAssets(ctx context.Context) (assets []Asset, err error) {
+	err = s.SelectRaw(ctx, &assets, "SELECT * FROM assets WHERE is_valid = TRUE")
+	return
+}
+
+// GetAssetsWithNestedIssuer returns a slice with all assets in the database
+// with is_valid = true, also adding the nested Issuer attribute
+func (s *SQLiteStore) GetAssetsWithNestedIssuer(ctx context.Context) (assets []Asset, err error) {
+	var issuers []Issuer
+	if err = s.SelectRaw(ctx, &issuers, "SELECT * FROM issuers"); err != nil {
+		return
+	}
+	var validAssets []Asset
+	if validAssets, err = s.GetAllValidAssets(ctx); err != nil {
+		return
+	}
+
+	issuersByID := map[int32]Issuer{}
+	for _, i := range issuers {
+		issuersByID[i.ID] = i
+	}
+	for _, a := range validAssets {
+		if issuer, ok := issuersByID[a.IssuerID]; ok {
+			a.Issuer = issuer
+			assets = append(assets, a)
+		}
+	}
+	return
+}
+
+// InsertOrUpdateIssuer inserts an Issuer on the database (if new),
+// or updates an existing one
+func (s *SQLiteStore) InsertOrUpdateIssuer(ctx context.Context, issuer *Issuer, preserveFields []string) (id int32, err error) {
+	err = s.performUpsertQuery(ctx, *issuer, "issuers", "public_key", preserveFields)
+	if err != nil {
+		return
+	}
+	err = s.GetRaw(ctx, &id, "SELECT id FROM issuers WHERE public_key = ?", issuer.PublicKey)
+	return
+}
+
+// GetAllIssuers returns a slice with all issuers in the database
+func (s *SQLiteStore) GetAllIssuers(ctx context.Context) (issuers []Issuer, err error) {
+	err = s.SelectRaw(ctx, &issuers, "SELECT * FROM issuers")
+	return
+}
+
+// BulkInsertTrades inserts a slice of trades in the database. Trades
+// that are already in the database (i.e. horizon_id already exists)
+// are ignored.
+func (s *SQLiteStore) BulkInsertTrades(ctx context.Context, trades []Trade) error {
+	dbFields := strings.Join(getDBFieldTags(Trade{}, true), ", ")
+	for _, chunk := range chunkifyDBTrades(trades, 50) {
+		var placeholders []string
+		var dbValues []interface{}
+		for _, trade := range chunk {
+			v := getDBFieldValues(trade, true)
+			placeholders = append(placeholders, "("+generatePlaceholders(v)+")")
+			dbValues = append(dbValues, v...)
+		}
+
+		qs := "INSERT INTO trades (" + dbFields + ")"
+		qs += " VALUES " + strings.Join(placeholders, ", ")
+		qs += " ON CONFLICT (horizon_id) DO NOTHING;"
+		if _, err := s.ExecRaw(ctx, qs, utcTimes(dbValues)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLastTrade returns the newest Trade object in the database.
+func (s *SQLiteStore) GetLastTrade(ctx context.Context) (trade Trade, err error) {
+	err = s.GetRaw(ctx, &trade, "SELECT * FROM trades ORDER BY ledger_close_time DESC LIMIT 1")
+	return
+}
+
+// GetLastTradeID returns the id of the newest trade inserted in the
+// database, or 0 if there is none.
+func (s *SQLiteStore) GetLastTradeID(ctx context.Context) (id int32, err error) {
+	err = s.GetRaw(ctx, &id, "SELECT COALESCE(MAX(id), 0) FROM trades")
+	return
+}
+
+// GetTradesAfterID returns up to <limit> trades inserted after the trade with
+// id <id>, along with their assets, ordered by id.
+func (s *SQLiteStore) GetTradesAfterID(ctx context.Context, id int32, limit int) (trades []MarketTrade, err error) {
+	err = s.SelectRaw(ctx, &trades, sqliteTradesAfterIDQuery, id, limit)
+	return
+}
+
+// DeleteOldTrades deletes trades in the database older than minDate.
+func (s *SQLiteStore) DeleteOldTrades(ctx context.Context, minDate time.Time) error {
+	_, err := s.ExecRaw(ctx, "DELETE FROM trades WHERE ledger_close_time < ?", minDate.UTC())
+	return err
+}
+
+// InsertOrUpdateOrderbookStats inserts an OrdebookStats entry on the database (if new),
+// or updates an existing one
+func (s *SQLiteStore) InsertOrUpdateOrderbookStats(ctx context.Context, o *OrderbookStats, preserveFields []string) error {
+	return s.performUpsertQuery(ctx, *o, "orderbook_stats", "base_asset_id, counter_asset_id", preserveFields)
+}
+
+// GetOrderbookStatsUpdatedAfter returns the orderbook stats updated after
+// <after>, along with their assets, ordered by update time.
+func (s *SQLiteStore) GetOrderbookStatsUpdatedAfter(ctx context.Context, after time.Time) (stats []MarketOrderbookStats, err error) {
+	err = s.SelectRaw(ctx, &stats, sqliteOrderbookStatsUpdatedAfterQuery, after.UTC())
+	return
+}
+
+// GetLastOrderbookStatsUpdate returns the time of the latest update of the
+// orderbook stats, or the zero time if there is none.
+func (s *SQLiteStore) GetLastOrderbookStatsUpdate(ctx context.Context) (updatedAt time.Time, err error) {
+	err = s.GetRaw(ctx, &updatedAt, "SELECT updated_at FROM orderbook_stats ORDER BY updated_at DESC LIMIT 1")
+	if s.NoRows(err) {
+		err = nil
+	}
+	return
+}
+
+// UpsertCandles computes the candles of <resolution> of every pair of assets
+// from the trades closed since <since>, replacing the candles previously
+// computed for the same periods.
+func (s *SQLiteStore) UpsertCandles(ctx context.Context, resolution time.Duration, since time.Time) error {
+	var trades []Trade
+	err := s.SelectRaw(ctx, &trades, "SELECT * FROM trades WHERE ledger_close_time >= ?", since.UTC())
+	if err != nil {
+		return err
+	}
+	candles := newSnapshot(nil, trades, nil).candles(time.Now(), resolution, since)
+
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	dbFields := getDBFieldTags(Candle{}, true)
+	toUpdateFields := utils.SliceDiff(dbFields, sanitizeFieldNames([]string{
+		"base_asset_id", "counter_asset_id", "resolution", "open_time",
+	}))
+	qs := "INSERT INTO candles (" + strings.Join(dbFields, ", ") + ")"
+	qs += " VALUES (" + generatePlaceholders(getDBFieldValues(Candle{}, true)) + ")"
+	qs += " " + createSQLiteOnConflictFragment("base_asset_id, counter_asset_id, resolution, open_time", toUpdateFields)
+	for _, c := range candles {
+		if _, err = tx.ExecContext(ctx, qs, utcTimes(getDBFieldValues(c, true))...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCandles returns up to <limit> candles of <resolution> of a pair of assets,
+// ordered by open time. If <cursor> is not nil, only the candles opened after
+// it (or before it if <desc> is true) are returned.
+func (s *SQLiteStore) GetCandles(ctx context.Context,
+	baseAssetID int32,
+	counterAssetID int32,
+	resolution time.Duration,
+	cursor *time.Time,
+	desc bool,
+	limit int,
+) (candles []Candle, err error) {
+	q := "SELECT * FROM candles WHERE base_asset_id = ? AND counter_asset_id = ? AND resolution = ?"
+	args := []interface{}{baseAssetID, counterAssetID, int32(resolution / time.Second)}
+
+	order := "ASC"
+	comparison := ">"
+	if desc {
+		order = "DESC"
+		comparison = "<"
+	}
+	if cursor != nil {
+		q += " AND open_time " + comparison + " ?"
+		args = append(args, cursor.UTC())
+	}
+	q += " ORDER BY open_time " + order + " LIMIT ?"
+	args = append(args, limit)
+
+	err = s.SelectRaw(ctx, &candles, q, args...)
+	return
+}
+
+// DeleteOldCandles deletes the candles of <resolution> opened before minDate.
+func (s *SQLiteStore) DeleteOldCandles(ctx context.Context, resolution time.Duration, minDate time.Time) error {
+	_, err := s.ExecRaw(ctx,
+		"DELETE FROM candles WHERE resolution = ? AND open_time < ?",
+		int32(resolution/time.Second),
+		minDate.UTC(),
+	)
+	return err
+}
+
+// RetrieveMarketData retrieves the 24h- and 7d aggregated market data for all
+// markets that were active during this period.
+func (s *SQLiteStore) RetrieveMarketData(ctx context.Context) ([]Market, error) {
+	now := time.Now()
+	snap, err := s.loadSnapshot(ctx, now.Add(-7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	return snap.marketData(now), nil
+}
+
+// RetrievePartialAggMarkets retrieves the aggregated market data for all
+// markets (or for a specific one if PairNames != nil) for a given period.
+func (s *SQLiteStore) RetrievePartialAggMarkets(ctx context.Context,
+	code *string,
+	pairNames *[]*string,
+	numHoursAgo int,
+) ([]PartialMarket, error) {
+	filter, err := partialAggMarketsFilter(code, pairNames)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	snap, err := s.loadSnapshot(ctx, now.Add(-time.Duration(numHoursAgo)*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	return snap.partialAggMarkets(now, numHoursAgo, filter), nil
+}
+
+// RetrievePartialMarkets retrieves data in the PartialMarket format from the database.
+// It optionally filters the data according to the provided base and counter asset params
+// provided, as well as the numHoursAgo time offset.
+func (s *SQLiteStore) RetrievePartialMarkets(ctx context.Context,
+	baseAssetCode *string,
+	baseAssetIssuer *string,
+	counterAssetCode *string,
+	counterAssetIssuer *string,
+	numHoursAgo int,
+) ([]PartialMarket, error) {
+	filter := partialMarketsFilter(baseAssetCode, baseAssetIssuer, counterAssetCode, counterAssetIssuer)
+
+	now := time.Now()
+	snap, err := s.loadSnapshot(ctx, now.Add(-time.Duration(numHoursAgo)*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	return snap.partialMarkets(now, numHoursAgo, filter), nil
+}
+
+// Retrieve7DRelevantMarkets retrieves the base and counter asset data of the markets
+// that were relevant in the last 7-day period.
+func (s *SQLiteStore) Retrieve7DRelevantMarkets(ctx context.Context) ([]PartialMarket, error) {
+	now := time.Now()
+	snap, err := s.loadSnapshot(ctx, now.Add(-7*24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	return snap.relevantMarkets7D(now), nil
+}
+
+// loadSnapshot loads the assets, the orderbook stats and the trades closed
+// since <since>.
+func (s *SQLiteStore) loadSnapshot(ctx context.Context, since time.Time) (snapshot, error) {
+	var (
+		assets []Asset
+		trades []Trade
+		stats  []OrderbookStats
+	)
+	if err := s.SelectRaw(ctx, &assets, "SELECT * FROM assets"); err != nil {
+		return snapshot{}, err
+	}
+	if err := s.SelectRaw(ctx, &trades, "SELECT * FROM trades WHERE ledger_close_time >= ?", since.UTC()); err != nil {
+		return snapshot{}, err
+	}
+	if err := s.SelectRaw(ctx, &stats, "SELECT * FROM orderbook_stats"); err != nil {
+		return snapshot{}, err
+	}
+	return newSnapshot(assets, trades, stats), nil
+}
+
+// performUpsertQuery introspects a dbStruct interface{} and performs an insert query
+// (if no row has the same conflictColumns), otherwise it updates the row,
+// preserving the old values for the fields in preserveFields
+func (s *SQLiteStore) performUpsertQuery(ctx context.Context, dbStruct interface{}, tableName string, conflictColumns string, preserveFields []string) error {
+	dbFields := getDBFieldTags(dbStruct, true)
+	dbValues := getDBFieldValues(dbStruct, true)
+	toUpdateFields := utils.SliceDiff(dbFields, sanitizeFieldNames(preserveFields))
+
+	qs := fmt.Sprintf("INSERT INTO %s (", tableName) + strings.Join(dbFields, ", ") + ")"
+	qs += " VALUES (" + generatePlaceholders(dbValues) + ")"
+	qs += " " + createSQLiteOnConflictFragment(conflictColumns, toUpdateFields) + ";"
+	_, err := s.ExecRaw(ctx, qs, utcTimes(dbValues)...)
+	return err
+}
+
+// createSQLiteOnConflictFragment generates an ON CONFLICT sql clause for the
+// unique conflictColumns, updating the fields listed in the fields param.
+func createSQLiteOnConflictFragment(conflictColumns string, fields []string) string {
+	if len(fields) == 0 {
+		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", conflictColumns)
+	}
+
+	updates := make([]string, len(fields))
+	for i, field := range fields {
+		updates[i] = fmt.Sprintf("%s = excluded.%s", field, field)
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET ", conflictColumns) + strings.Join(updates, ", ")
+}
+
+// utcTimes converts the times of values to UTC, since SQLite stores times
+// as text that is compared as such.
+func utcTimes(values []interface{}) []interface{} {
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			values[i] = t.UTC()
+		}
+	}
+	return values
+}
+
+var sqliteTradesAfterIDQuery = `
+SELECT
+	t.id,
+	t.horizon_id,
+	t.ledger_close_time,
+	COALESCE(NULLIF(bAsset.anchor_asset_code, ''), bAsset.code) || '_' ||
+		COALESCE(NULLIF(cAsset.anchor_asset_code, ''), cAsset.code) AS trade_pair_name,
+	bAsset.code AS base_asset_code,
+	bAsset.issuer_account AS base_asset_issuer,
+	cAsset.code AS counter_asset_code,
+	cAsset.issuer_account AS counter_asset_issuer,
+	t.base_amount,
+	t.counter_amount,
+	t.base_is_seller,
+	t.price
+FROM trades AS t
+	JOIN assets AS bAsset ON t.base_asset_id = bAsset.id
+	JOIN assets AS cAsset ON t.counter_asset_id = cAsset.id
+WHERE t.id > ?
+ORDER BY t.id ASC
+LIMIT ?;`
+
+var sqliteOrderbookStatsUpdatedAfterQuery = `
+SELECT
+	COALESCE(NULLIF(bAsset.anchor_asset_code, ''), bAsset.code) || '_' ||
+		COALESCE(NULLIF(cAsset.anchor_asset_code, ''), cAsset.code) AS trade_pair_name,
+	bAsset.code AS base_asset_code,
+	bAsset.issuer_account AS base_asset_issuer,
+	cAsset.code AS counter_asset_code,
+	cAsset.issuer_account AS counter_asset_issuer,
+	os.num_bids,
+	os.bid_volume,
+	os.highest_bid,
+	os.num_asks,
+	os.ask_volume,
+	os.lowest_ask,
+	os.spread,
+	os.spread_mid_point,
+	os.updated_at
+FROM orderbook_stats AS os
+	JOIN assets AS bAsset ON os.base_asset_id = bAsset.id
+	JOIN assets AS cAsset ON os.counter_asset_id = cAsset.id
+WHERE os.updated_at > ?
+ORDER BY os.updated_at ASC;`
+
+// sqliteMigrations create the schema of the PostgreSQL migrations, in a
+// single migration since SQLite deployments start from it. New PostgreSQL
+// migrations need a matching SQLite one, which TestSQLiteSchema checks.
+var sqliteMigrations = &migrate.MemoryMigrationSource{
+	Migrations: []*migrate.Migration{
+		{
+			Id: "20211101120000-sqlite-initial.sql",
+			Up: []string{
+				`CREATE TABLE issuers (
+					id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+					public_key text NOT NULL UNIQUE,
+					name text NOT NULL,
+					url text NOT NULL,
+					toml_url text NOT NULL,
+					federation_server text NOT NULL,
+					auth_server text NOT NULL,
+					transfer_server text NOT NULL,
+					web_auth_endpoint text NOT NULL,
+					deposit_server text NOT NULL,
+					org_twitter text NOT NULL
+				);`,
+				`CREATE TABLE assets (
+					id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+					code text NOT NULL,
+					issuer_account text NOT NULL,
+					type text NOT NULL,
+					num_accounts integer NOT NULL,
+					auth_required boolean NOT NULL,
+					auth_revocable boolean NOT NULL,
+					amount real NOT NULL,
+					asset_controlled_by_domain boolean NOT NULL,
+					anchor_asset_code text NOT NULL,
+					anchor_asset_type text NOT NULL,
+					is_valid boolean NOT NULL,
+					validation_error text NOT NULL,
+					last_valid timestamp NOT NULL,
+					last_checked timestamp NOT NULL,
+					display_decimals integer NOT NULL DEFAULT 7,
+					name text NOT NULL DEFAULT '',
+					description text NOT NULL DEFAULT '',
+					conditions text NOT NULL DEFAULT '',
+					is_asset_anchored boolean NOT NULL DEFAULT FALSE,
+					fixed_number integer NOT NULL DEFAULT 0,
+					max_number integer NOT NULL DEFAULT 0,
+					is_unlimited boolean NOT NULL DEFAULT TRUE,
+					redemption_instructions text NOT NULL DEFAULT '',
+					collateral_addresses text NOT NULL DEFAULT '',
+					collateral_address_signatures text NOT NULL DEFAULT '',
+					countries text NOT NULL DEFAULT '',
+					status text NOT NULL DEFAULT '',
+					issuer_id integer NOT NULL REFERENCES issuers (id),
+					UNIQUE (code, issuer_account)
+				);`,
+				`CREATE TABLE trades (
+					id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+					horizon_id text NOT NULL UNIQUE,
+					ledger_close_time timestamp NOT NULL,
+					offer_id text NOT NULL,
+					base_offer_id text NOT NULL,
+					base_account text NOT NULL,
+					base_amount real NOT NULL,
+					base_asset_id integer REFERENCES assets (id),
+					counter_offer_id text NOT NULL,
+					counter_account text NOT NULL,
+					counter_amount real NOT NULL,
+					counter_asset_id integer REFERENCES assets (id),
+					base_is_seller boolean NOT NULL,
+					price real NOT NULL
+				);`,
+				`CREATE INDEX trades_ledger_close_time_idx ON trades (ledger_close_time);`,
+				`CREATE TABLE orderbook_stats (
+					id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+					base_asset_id integer NOT NULL REFERENCES assets (id),
+					counter_asset_id integer NOT NULL REFERENCES assets (id),
+					num_bids integer NOT NULL,
+					bid_volume real NOT NULL,
+					highest_bid real NOT NULL,
+					num_asks integer NOT NULL,
+					ask_volume real NOT NULL,
+					lowest_ask real NOT NULL,
+					spread real NOT NULL,
+					spread_mid_point real NOT NULL,
+					updated_at timestamp NOT NULL,
+					UNIQUE (base_asset_id, counter_asset_id)
+				);`,
+				`CREATE TABLE candles (
+					id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+					base_asset_id integer NOT NULL REFERENCES assets (id),
+					counter_asset_id integer NOT NULL REFERENCES assets (id),
+					resolution integer NOT NULL,
+					open_time timestamp NOT NULL,
+					open real NOT NULL,
+					high real NOT NULL,
+					low real NOT NULL,
+					close real NOT NULL,
+					base_volume real NOT NULL,
+					counter_volume real NOT NULL,
+					trade_count integer NOT NULL,
+					updated_at timestamp NOT NULL,
+					UNIQUE (base_asset_id, counter_asset_id, resolution, open_time)
+				);`,
+				`CREATE INDEX candles_resolution_open_time_idx ON candles (resolution, open_time);`,
+				`INSERT INTO issuers (
+					public_key, name, url, toml_url, federation_server, auth_server,
+					transfer_server, web_auth_endpoint, deposit_server, org_twitter
+				) VALUES (
+					'native', 'Stellar Development Foundation', 'http://stellar.org', '', '', '',
+					'', '', '', 'https://twitter.com/stellarorg'
+				);`,
+				`INSERT INTO assets (
+					code, type, num_accounts, auth_required, auth_revocable, amount,
+					asset_controlled_by_domain, anchor_asset_code, anchor_asset_type,
+					is_valid, validation_error, last_valid, last_checked, display_decimals,
+					name, is_unlimited, issuer_id, issuer_account
+				) VALUES (
+					'XLM', 'native', 0, FALSE, FALSE, 0.0,
+					TRUE, '', '',
+					TRUE, '', datetime('now'), datetime('now'), 7,
+					'Stellar Lumens', FALSE, (SELECT id FROM issuers WHERE public_key = 'native'), 'native'
+				);`,
+			},
+		},
+	},
+}
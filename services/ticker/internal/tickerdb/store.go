@@ -0,0 +1,124 @@
+package tickerdb
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stellar/go/support/errors"
+)
+
+// Store is the persistence layer of the ticker. It is implemented on
+// PostgreSQL by TickerSession, on SQLite by SQLiteStore for lightweight
+// deployments, and in memory by MemoryStore for tests.
+type Store interface {
+	// Migrate updates the schema of the store to its latest version, and
+	// returns the number of migrations applied.
+	Migrate() (int, error)
+	// Close releases the resources of the store.
+	Close() error
+
+	InsertOrUpdateAsset(ctx context.Context, a *Asset, preserveFields []string) error
+	GetAssetByCodeAndIssuerAccount(ctx context.Context, code string, issuerAccount string) (found bool, id int32, err error)
+	GetAllValidAssets(ctx context.Context) ([]Asset, error)
+	GetAssetsWithNestedIssuer(ctx context.Context) ([]Asset, error)
+
+	InsertOrUpdateIssuer(ctx context.Context, issuer *Issuer, preserveFields []string) (id int32, err error)
+	GetAllIssuers(ctx context.Context) ([]Issuer, error)
+
+	BulkInsertTrades(ctx context.Context, trades []Trade) error
+	GetLastTrade(ctx context.Context) (Trade, error)
+	GetLastTradeID(ctx context.Context) (int32, error)
+	GetTradesAfterID(ctx context.Context, id int32, limit int) ([]MarketTrade, error)
+	DeleteOldTrades(ctx context.Context, minDate time.Time) error
+
+	InsertOrUpdateOrderbookStats(ctx context.Context, o *OrderbookStats, preserveFields []string) error
+	GetOrderbookStatsUpdatedAfter(ctx context.Context, after time.Time) ([]MarketOrderbookStats, error)
+	GetLastOrderbookStatsUpdate(ctx context.Context) (time.Time, error)
+
+	UpsertCandles(ctx context.Context, resolution time.Duration, since time.Time) error
+	GetCandles(ctx context.Context, baseAssetID int32, counterAssetID int32, resolution time.Duration, cursor *time.Time, desc bool, limit int) ([]Candle, error)
+	DeleteOldCandles(ctx context.Context, resolution time.Duration, minDate time.Time) error
+
+	RetrieveMarketData(ctx context.Context) ([]Market, error)
+	RetrievePartialAggMarkets(ctx context.Context, code *string, pairNames *[]*string, numHoursAgo int) ([]PartialMarket, error)
+	RetrievePartialMarkets(ctx context.Context, baseAssetCode *string, baseAssetIssuer *string, counterAssetCode *string, counterAssetIssuer *string, numHoursAgo int) ([]PartialMarket, error)
+	Retrieve7DRelevantMarkets(ctx context.Context) ([]PartialMarket, error)
+}
+
+var (
+	_ Store = (*TickerSession)(nil)
+	_ Store = (*SQLiteStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
+
+// sqliteDefaultParams are the parameters of the SQLite driver that Open sets
+// unless they are in the database URL: the ingestion and the server of the
+// ticker run in different processes, and write to the database concurrently.
+var sqliteDefaultParams = map[string]string{
+	"_busy_timeout": "5000",
+	"_journal_mode": "WAL",
+	"_foreign_keys": "1",
+}
+
+// Open returns the Store of a database URL. postgres:// URLs are opened
+// with TickerSession, and sqlite:// URLs, e.g. sqlite:///var/lib/ticker.db,
+// with SQLiteStore. The query of sqlite:// URLs is passed to the SQLite
+// driver, which must be registered by the caller: the ticker only registers
+// it when built with -tags sqlite.
+func Open(databaseURL string) (Store, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse database URL")
+	}
+
+	switch u.Scheme {
+	case "postgres", "postgresql":
+		dbInfo, err := pq.ParseURL(databaseURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse database URL")
+		}
+		session, err := CreateSession("postgres", dbInfo)
+		if err != nil {
+			return nil, err
+		}
+		return &session, nil
+	case "sqlite", "sqlite3":
+		// sqlite://ticker.db is relative to the working directory, while
+		// sqlite:///var/lib/ticker.db is absolute.
+		path := u.Host + u.Path
+		if u.Opaque != "" {
+			path = u.Opaque
+		}
+		if path == "" {
+			return nil, errors.New("missing path of the SQLite database")
+		}
+		if !sqliteDriverRegistered() {
+			return nil, errors.New("the sqlite3 database driver is not registered, build the ticker with -tags sqlite")
+		}
+		params := u.Query()
+		for name, value := range sqliteDefaultParams {
+			if params.Get(name) == "" {
+				params.Set(name, value)
+			}
+		}
+		store, err := NewSQLiteStore(path + "?" + params.Encode())
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, errors.Errorf("unsupported database URL scheme %q", u.Scheme)
+	}
+}
+
+func sqliteDriverRegistered() bool {
+	for _, driver := range sql.Drivers() {
+		if driver == "sqlite3" {
+			return true
+		}
+	}
+	return false
+}
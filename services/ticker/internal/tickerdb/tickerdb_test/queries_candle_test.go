@@ -0,0 +1,153 @@
+package tickerdb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	migrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandles(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+
+	var session tickerdb.TickerSession
+	session.DB = db.Open()
+	defer session.DB.Close()
+	ctx := context.Background()
+
+	// Run migrations to make sure the tests are run
+	// on the most updated schema version
+	migrations := &migrate.FileMigrationSource{
+		Dir: "../migrations",
+	}
+	_, err := migrate.Exec(session.DB.DB, "postgres", migrations, migrate.Up)
+	require.NoError(t, err)
+
+	// Adding a seed issuer to be used later:
+	tbl := session.GetTable("issuers")
+	_, err = tbl.Insert(tickerdb.Issuer{
+		PublicKey: "GCF3TQXKZJNFJK7HCMNE2O2CUNKCJH2Y2ROISTBPLC7C5EIA5NNG2XZB",
+		Name:      "FOO BAR",
+	}).IgnoreCols("id").Exec(ctx)
+	require.NoError(t, err)
+	var issuer tickerdb.Issuer
+	err = session.GetRaw(ctx, &issuer, `
+		SELECT *
+		FROM issuers
+		ORDER BY id DESC
+		LIMIT 1`,
+	)
+	require.NoError(t, err)
+
+	// Adding the assets of the market:
+	var assets [2]tickerdb.Asset
+	for i, code := range []string{"XLM", "BTC"} {
+		err = session.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+			Code:     code,
+			IssuerID: issuer.ID,
+		}, []string{"code", "issuer_id"})
+		require.NoError(t, err)
+		err = session.GetRaw(ctx, &assets[i], `
+			SELECT *
+			FROM assets
+			ORDER BY id DESC
+			LIMIT 1`,
+		)
+		require.NoError(t, err)
+	}
+
+	// Trades in two different hours:
+	start := time.Date(2021, 10, 18, 10, 0, 0, 0, time.UTC)
+	trade := func(id string, offset time.Duration, price, amount float64) tickerdb.Trade {
+		return tickerdb.Trade{
+			HorizonID:       id,
+			BaseAssetID:     assets[0].ID,
+			CounterAssetID:  assets[1].ID,
+			LedgerCloseTime: start.Add(offset),
+			BaseAmount:      amount,
+			CounterAmount:   amount * price,
+			Price:           price,
+		}
+	}
+	err = session.BulkInsertTrades(ctx, []tickerdb.Trade{
+		trade("hrzid1", 5*time.Minute, 2, 10),
+		trade("hrzid2", 20*time.Minute, 4, 10),
+		trade("hrzid3", 40*time.Minute, 1, 20),
+		trade("hrzid4", 50*time.Minute, 3, 10),
+		trade("hrzid5", 70*time.Minute, 5, 1),
+	})
+	require.NoError(t, err)
+
+	err = session.UpsertCandles(ctx, time.Hour, start)
+	require.NoError(t, err)
+
+	candles, err := session.GetCandles(ctx, assets[0].ID, assets[1].ID, time.Hour, nil, false, 10)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+
+	assert.True(t, start.Equal(candles[0].OpenTime))
+	assert.Equal(t, int32(3600), candles[0].Resolution)
+	assert.Equal(t, 2.0, candles[0].Open)
+	assert.Equal(t, 4.0, candles[0].High)
+	assert.Equal(t, 1.0, candles[0].Low)
+	assert.Equal(t, 3.0, candles[0].Close)
+	assert.Equal(t, 50.0, candles[0].BaseVolume)
+	assert.Equal(t, 110.0, candles[0].CounterVolume)
+	assert.Equal(t, int32(4), candles[0].TradeCount)
+
+	assert.True(t, start.Add(time.Hour).Equal(candles[1].OpenTime))
+	assert.Equal(t, 5.0, candles[1].Open)
+	assert.Equal(t, 5.0, candles[1].Close)
+	assert.Equal(t, int32(1), candles[1].TradeCount)
+
+	// Upserting again updates the existing candles:
+	err = session.BulkInsertTrades(ctx, []tickerdb.Trade{
+		trade("hrzid6", 80*time.Minute, 6, 1),
+	})
+	require.NoError(t, err)
+	err = session.UpsertCandles(ctx, time.Hour, start.Add(time.Hour))
+	require.NoError(t, err)
+
+	candles, err = session.GetCandles(ctx, assets[0].ID, assets[1].ID, time.Hour, nil, true, 10)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	assert.Equal(t, 6.0, candles[0].Close)
+	assert.Equal(t, int32(2), candles[0].TradeCount)
+	assert.Equal(t, int32(4), candles[1].TradeCount)
+
+	// Paging through the candles:
+	candles, err = session.GetCandles(ctx, assets[0].ID, assets[1].ID, time.Hour, nil, false, 1)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	candles, err = session.GetCandles(ctx, assets[0].ID, assets[1].ID, time.Hour, &candles[0].OpenTime, false, 1)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.True(t, start.Add(time.Hour).Equal(candles[0].OpenTime))
+
+	// Other resolutions are stored separately:
+	err = session.UpsertCandles(ctx, 24*time.Hour, start.Truncate(24*time.Hour))
+	require.NoError(t, err)
+	candles, err = session.GetCandles(ctx, assets[0].ID, assets[1].ID, 24*time.Hour, nil, false, 10)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, int32(6), candles[0].TradeCount)
+
+	// Deleting the old candles of a resolution:
+	err = session.DeleteOldCandles(ctx, time.Hour, start.Add(time.Hour))
+	require.NoError(t, err)
+	candles, err = session.GetCandles(ctx, assets[0].ID, assets[1].ID, time.Hour, nil, false, 10)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.True(t, start.Add(time.Hour).Equal(candles[0].OpenTime))
+
+	candles, err = session.GetCandles(ctx, assets[0].ID, assets[1].ID, 24*time.Hour, nil, false, 10)
+	require.NoError(t, err)
+	assert.Len(t, candles, 1)
+}
@@ -352,3 +352,115 @@ func TestDeleteOldTrades(t *testing.T) {
 	assert.WithinDuration(t, now.Local(), trade1.LedgerCloseTime.Local(), 10*time.Millisecond)
 	assert.WithinDuration(t, oneDayAgo.Local(), trade2.LedgerCloseTime.Local(), 10*time.Millisecond)
 }
+
+func TestGetTradesAfterID(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+
+	var session tickerdb.TickerSession
+	session.DB = db.Open()
+	defer session.DB.Close()
+	ctx := context.Background()
+
+	// Run migrations to make sure the tests are run
+	// on the most updated schema version
+	migrations := &migrate.FileMigrationSource{
+		Dir: "../migrations",
+	}
+	_, err := migrate.Exec(session.DB.DB, "postgres", migrations, migrate.Up)
+	require.NoError(t, err)
+
+	// Sanity Check (there are no trades in the database)
+	lastID, err := session.GetLastTradeID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), lastID)
+
+	// Adding a seed issuer to be used later:
+	tbl := session.GetTable("issuers")
+	_, err = tbl.Insert(tickerdb.Issuer{
+		PublicKey: "GCF3TQXKZJNFJK7HCMNE2O2CUNKCJH2Y2ROISTBPLC7C5EIA5NNG2XZB",
+		Name:      "FOO BAR",
+	}).IgnoreCols("id").Exec(ctx)
+	require.NoError(t, err)
+	var issuer tickerdb.Issuer
+	err = session.GetRaw(ctx, &issuer, `
+		SELECT *
+		FROM issuers
+		ORDER BY id DESC
+		LIMIT 1`,
+	)
+	require.NoError(t, err)
+
+	// Adding two assets to be used later:
+	err = session.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+		Code:          "XLM",
+		IssuerAccount: "native",
+		IssuerID:      issuer.ID,
+	}, []string{"code", "issuer_account", "issuer_id"})
+	require.NoError(t, err)
+	var asset1 tickerdb.Asset
+	err = session.GetRaw(ctx, &asset1, `
+		SELECT *
+		FROM assets
+		ORDER BY id DESC
+		LIMIT 1`,
+	)
+	require.NoError(t, err)
+
+	err = session.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+		Code:          "BTC",
+		IssuerAccount: issuer.PublicKey,
+		IssuerID:      issuer.ID,
+	}, []string{"code", "issuer_account", "issuer_id"})
+	require.NoError(t, err)
+	var asset2 tickerdb.Asset
+	err = session.GetRaw(ctx, &asset2, `
+		SELECT *
+		FROM assets
+		ORDER BY id DESC
+		LIMIT 1`,
+	)
+	require.NoError(t, err)
+
+	now := time.Now()
+	err = session.BulkInsertTrades(ctx, []tickerdb.Trade{
+		{
+			HorizonID:       "hrzid1",
+			BaseAssetID:     asset1.ID,
+			CounterAssetID:  asset2.ID,
+			BaseAmount:      100.0,
+			CounterAmount:   10.0,
+			Price:           0.1,
+			LedgerCloseTime: now,
+		},
+		{
+			HorizonID:       "hrzid2",
+			BaseAssetID:     asset1.ID,
+			CounterAssetID:  asset2.ID,
+			BaseAmount:      50.0,
+			CounterAmount:   10.0,
+			Price:           0.2,
+			LedgerCloseTime: now,
+		},
+	})
+	require.NoError(t, err)
+
+	trades, err := session.GetTradesAfterID(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+	assert.Equal(t, "hrzid1", trades[0].HorizonID)
+	assert.Equal(t, "XLM_BTC", trades[0].TradePairName)
+	assert.Equal(t, "native", trades[0].BaseAssetIssuer)
+	assert.Equal(t, issuer.PublicKey, trades[0].CounterAssetIssuer)
+	assert.Equal(t, 0.1, trades[0].Price)
+	assert.Equal(t, "hrzid2", trades[1].HorizonID)
+
+	lastID, err = session.GetLastTradeID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, trades[1].ID, lastID)
+
+	trades, err = session.GetTradesAfterID(ctx, trades[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	assert.Equal(t, "hrzid2", trades[0].HorizonID)
+}
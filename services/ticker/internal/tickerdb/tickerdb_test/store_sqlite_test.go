@@ -0,0 +1,74 @@
+// +build sqlite
+
+package tickerdb_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	storeBackends = append(storeBackends, storeBackend{
+		name:       "sqlite",
+		open:       openSQLiteStore,
+		migrations: 1,
+	})
+}
+
+func openSQLiteStore(t *testing.T) (tickerdb.Store, func()) {
+	dir, err := ioutil.TempDir("", "ticker")
+	require.NoError(t, err)
+
+	store, err := tickerdb.Open("sqlite://" + filepath.Join(dir, "ticker.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		require.NoError(t, err)
+	}
+	return store, func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// TestSQLiteSchema checks that the schema of the SQLite migration has the
+// columns of the PostgreSQL migrations.
+func TestSQLiteSchema(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.Postgres(t)
+	defer db.Close()
+	session := &tickerdb.TickerSession{}
+	session.DB = db.Open()
+	defer session.Close()
+	_, err := session.Migrate()
+	require.NoError(t, err)
+
+	store, closeStore := openSQLiteStore(t)
+	defer closeStore()
+	_, err = store.Migrate()
+	require.NoError(t, err)
+	sqliteStore := store.(*tickerdb.SQLiteStore)
+
+	for _, table := range []string{"issuers", "assets", "trades", "orderbook_stats", "candles"} {
+		var want, got []string
+		err = session.SelectRaw(ctx, &want, `
+			SELECT column_name
+			FROM information_schema.columns
+			WHERE table_schema = 'public' AND table_name = ?`,
+			table,
+		)
+		require.NoError(t, err)
+		require.NotEmpty(t, want, table)
+		err = sqliteStore.SelectRaw(ctx, &got, "SELECT name FROM pragma_table_info(?)", table)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, want, got, table)
+	}
+}
@@ -0,0 +1,339 @@
+package tickerdb_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stellar/go/services/ticker/internal/tickerdb"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeBackend opens a migrated store, and returns the number of migrations
+// of its schema.
+type storeBackend struct {
+	name       string
+	open       func(t *testing.T) (tickerdb.Store, func())
+	migrations int
+}
+
+// storeBackends are the backends testStores runs the tests against. The
+// SQLite one is only added with -tags sqlite, since its driver needs cgo.
+var storeBackends = []storeBackend{
+	{
+		name: "memory",
+		open: func(t *testing.T) (tickerdb.Store, func()) {
+			return tickerdb.NewMemoryStore(), func() {}
+		},
+		migrations: 1,
+	},
+	{
+		name: "postgres",
+		open: func(t *testing.T) (tickerdb.Store, func()) {
+			db := dbtest.Postgres(t)
+			session := &tickerdb.TickerSession{}
+			session.DB = db.Open()
+			return session, func() {
+				session.Close()
+				db.Close()
+			}
+		},
+		migrations: 11,
+	},
+}
+
+// testStores runs test against a migrated store of each backend, so that
+// the SQLite and in-memory stores are checked to behave like the PostgreSQL
+// one.
+func testStores(t *testing.T, test func(t *testing.T, store tickerdb.Store)) {
+	for _, backend := range storeBackends {
+		backend := backend
+		t.Run(backend.name, func(t *testing.T) {
+			store, closeStore := backend.open(t)
+			defer closeStore()
+			n, err := store.Migrate()
+			require.NoError(t, err)
+			assert.Equal(t, backend.migrations, n)
+			n, err = store.Migrate()
+			require.NoError(t, err)
+			assert.Equal(t, 0, n)
+			test(t, store)
+		})
+	}
+}
+
+func TestOpenUnsupportedScheme(t *testing.T) {
+	_, err := tickerdb.Open("mysql://localhost/ticker")
+	assert.EqualError(t, err, `unsupported database URL scheme "mysql"`)
+}
+
+func TestStoreAssetsAndIssuers(t *testing.T) {
+	testStores(t, func(t *testing.T, store tickerdb.Store) {
+		ctx := context.Background()
+
+		found, xlmID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "XLM", "native")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.NotZero(t, xlmID)
+
+		issuerPK := "GCF3TQXKZJNFJK7HCMNE2O2CUNKCJH2Y2ROISTBPLC7C5EIA5NNG2XZB"
+		issuerID, err := store.InsertOrUpdateIssuer(ctx, &tickerdb.Issuer{
+			PublicKey: issuerPK,
+			Name:      "FOO BAR",
+		}, nil)
+		require.NoError(t, err)
+		updatedID, err := store.InsertOrUpdateIssuer(ctx, &tickerdb.Issuer{
+			PublicKey: issuerPK,
+			Name:      "FOO",
+			URL:       "https://foo.bar",
+		}, []string{"name"})
+		require.NoError(t, err)
+		assert.Equal(t, issuerID, updatedID)
+
+		issuers, err := store.GetAllIssuers(ctx)
+		require.NoError(t, err)
+		require.Len(t, issuers, 2)
+		assert.Equal(t, "FOO BAR", issuers[1].Name)
+		assert.Equal(t, "https://foo.bar", issuers[1].URL)
+
+		lastValid := time.Date(2021, 11, 1, 12, 0, 0, 0, time.UTC)
+		err = store.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+			Code:          "BTC",
+			IssuerAccount: issuerPK,
+			IssuerID:      issuerID,
+			IsValid:       true,
+			LastValid:     lastValid,
+			NumAccounts:   10,
+		}, []string{"code", "issuer_id"})
+		require.NoError(t, err)
+		err = store.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+			Code:          "BTC",
+			IssuerAccount: issuerPK,
+			IssuerID:      issuerID,
+			IsValid:       true,
+			NumAccounts:   20,
+		}, []string{"code", "issuer_id", "last_valid"})
+		require.NoError(t, err)
+		err = store.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+			Code:          "ETH",
+			IssuerAccount: issuerPK,
+			IssuerID:      issuerID,
+		}, nil)
+		require.NoError(t, err)
+
+		found, btcID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "BTC", issuerPK)
+		require.NoError(t, err)
+		assert.True(t, found)
+		found, _, err = store.GetAssetByCodeAndIssuerAccount(ctx, "BTC", "native")
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		assets, err := store.GetAllValidAssets(ctx)
+		require.NoError(t, err)
+		require.Len(t, assets, 2)
+		assert.Equal(t, btcID, assets[1].ID)
+		assert.Equal(t, int32(20), assets[1].NumAccounts)
+		assert.True(t, lastValid.Equal(assets[1].LastValid))
+
+		assets, err = store.GetAssetsWithNestedIssuer(ctx)
+		require.NoError(t, err)
+		require.Len(t, assets, 2)
+		assert.Equal(t, "native", assets[0].Issuer.PublicKey)
+		assert.Equal(t, issuerPK, assets[1].Issuer.PublicKey)
+	})
+}
+
+func TestStoreTradesAndMarkets(t *testing.T) {
+	testStores(t, func(t *testing.T, store tickerdb.Store) {
+		ctx := context.Background()
+
+		_, err := store.GetLastTrade(ctx)
+		assert.Equal(t, sql.ErrNoRows, err)
+
+		issuerPK := "GCF3TQXKZJNFJK7HCMNE2O2CUNKCJH2Y2ROISTBPLC7C5EIA5NNG2XZB"
+		issuerID, err := store.InsertOrUpdateIssuer(ctx, &tickerdb.Issuer{PublicKey: issuerPK}, nil)
+		require.NoError(t, err)
+		require.NoError(t, store.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+			Code:          "BTC",
+			IssuerAccount: issuerPK,
+			IssuerID:      issuerID,
+			IsValid:       true,
+		}, nil))
+		_, xlmID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "XLM", "native")
+		require.NoError(t, err)
+		_, btcID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "BTC", issuerPK)
+		require.NoError(t, err)
+
+		now := time.Now()
+		trade := func(id string, price float64, closeTime time.Time) tickerdb.Trade {
+			return tickerdb.Trade{
+				HorizonID:       id,
+				BaseAssetID:     xlmID,
+				BaseAmount:      100,
+				CounterAssetID:  btcID,
+				CounterAmount:   100 * price,
+				Price:           price,
+				LedgerCloseTime: closeTime,
+			}
+		}
+		require.NoError(t, store.BulkInsertTrades(ctx, []tickerdb.Trade{
+			trade("1", 0.1, now.Add(-72*time.Hour)),
+			trade("2", 0.3, now.Add(-2*time.Hour)),
+			trade("3", 0.2, now.Add(-1*time.Hour)),
+			trade("4", 0.5, now.AddDate(0, -1, 0)),
+		}))
+		// Trades already inserted are ignored.
+		require.NoError(t, store.BulkInsertTrades(ctx, []tickerdb.Trade{
+			trade("3", 1, now),
+		}))
+
+		lastTrade, err := store.GetLastTrade(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "3", lastTrade.HorizonID)
+		lastID, err := store.GetLastTradeID(ctx)
+		require.NoError(t, err)
+
+		trades, err := store.GetTradesAfterID(ctx, lastID-2, 10)
+		require.NoError(t, err)
+		require.Len(t, trades, 2)
+		assert.Equal(t, "3", trades[0].HorizonID)
+		assert.Equal(t, "4", trades[1].HorizonID)
+		assert.Equal(t, "XLM_BTC", trades[0].TradePairName)
+		assert.Equal(t, issuerPK, trades[0].CounterAssetIssuer)
+
+		lastUpdate, err := store.GetLastOrderbookStatsUpdate(ctx)
+		require.NoError(t, err)
+		assert.True(t, lastUpdate.IsZero())
+
+		require.NoError(t, store.InsertOrUpdateOrderbookStats(ctx, &tickerdb.OrderbookStats{
+			BaseAssetID:    xlmID,
+			CounterAssetID: btcID,
+			NumBids:        3,
+			HighestBid:     0.19,
+			NumAsks:        2,
+			LowestAsk:      0.21,
+			UpdatedAt:      now,
+		}, nil))
+		lastUpdate, err = store.GetLastOrderbookStatsUpdate(ctx)
+		require.NoError(t, err)
+		assert.WithinDuration(t, now, lastUpdate, time.Millisecond)
+		stats, err := store.GetOrderbookStatsUpdatedAfter(ctx, now.Add(-time.Minute))
+		require.NoError(t, err)
+		require.Len(t, stats, 1)
+		assert.Equal(t, "XLM_BTC", stats[0].TradePairName)
+		assert.Equal(t, 3, stats[0].NumBids)
+		stats, err = store.GetOrderbookStatsUpdatedAfter(ctx, now)
+		require.NoError(t, err)
+		assert.Len(t, stats, 0)
+
+		markets, err := store.RetrieveMarketData(ctx)
+		require.NoError(t, err)
+		require.Len(t, markets, 1)
+		assert.Equal(t, "XLM_BTC", markets[0].TradePair)
+		assert.Equal(t, int64(2), markets[0].TradeCount24h)
+		assert.Equal(t, int64(3), markets[0].TradeCount7d)
+		assert.Equal(t, 0.3, markets[0].OpenPrice24h)
+		assert.Equal(t, 0.2, markets[0].LastPrice)
+		assert.Equal(t, 0.1, markets[0].LowestPrice7d)
+		assert.Equal(t, 0.3, markets[0].HighestPrice7d)
+		assert.InDelta(t, 0.1, markets[0].PriceChange7d, 1e-9)
+		assert.Equal(t, 3, markets[0].NumBids)
+		assert.Equal(t, 0.21, markets[0].LowestAsk)
+
+		btc := "BTC"
+		partialMarkets, err := store.RetrievePartialMarkets(ctx, nil, nil, &btc, nil, 24)
+		require.NoError(t, err)
+		require.Len(t, partialMarkets, 1)
+		assert.Equal(t, "XLM:native / BTC:"+issuerPK, partialMarkets[0].TradePairName)
+		assert.Equal(t, int32(2), partialMarkets[0].TradeCount)
+		assert.Equal(t, 3, partialMarkets[0].NumBids)
+		assert.Equal(t, 0, partialMarkets[0].NumBidsReverse)
+
+		pairName := "BTC_XLM"
+		partialMarkets, err = store.RetrievePartialAggMarkets(ctx, nil, &[]*string{&pairName}, 168)
+		require.NoError(t, err)
+		require.Len(t, partialMarkets, 1)
+		assert.Equal(t, "XLM_BTC", partialMarkets[0].TradePairName)
+		assert.Equal(t, int32(3), partialMarkets[0].TradeCount)
+		assert.Equal(t, 0.1, partialMarkets[0].Open)
+		assert.Equal(t, 0.2, partialMarkets[0].Close)
+		_, err = store.RetrievePartialAggMarkets(ctx, &btc, &[]*string{&pairName}, 168)
+		assert.EqualError(t, err, "code and pairNames cannot both be non-nil")
+
+		partialMarkets, err = store.Retrieve7DRelevantMarkets(ctx)
+		require.NoError(t, err)
+		require.Len(t, partialMarkets, 1)
+		assert.Equal(t, xlmID, partialMarkets[0].BaseAssetID)
+		assert.Equal(t, btcID, partialMarkets[0].CounterAssetID)
+
+		require.NoError(t, store.DeleteOldTrades(ctx, now.AddDate(0, 0, -7)))
+		trades, err = store.GetTradesAfterID(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, trades, 3)
+	})
+}
+
+func TestStoreCandles(t *testing.T) {
+	testStores(t, func(t *testing.T, store tickerdb.Store) {
+		ctx := context.Background()
+
+		_, xlmID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "XLM", "native")
+		require.NoError(t, err)
+		issuerID, err := store.InsertOrUpdateIssuer(ctx, &tickerdb.Issuer{PublicKey: "issuer"}, nil)
+		require.NoError(t, err)
+		require.NoError(t, store.InsertOrUpdateAsset(ctx, &tickerdb.Asset{
+			Code:          "BTC",
+			IssuerAccount: "issuer",
+			IssuerID:      issuerID,
+		}, nil))
+		_, btcID, err := store.GetAssetByCodeAndIssuerAccount(ctx, "BTC", "issuer")
+		require.NoError(t, err)
+
+		hour := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+		var trades []tickerdb.Trade
+		for i, price := range []float64{2, 1, 4, 3} {
+			trades = append(trades, tickerdb.Trade{
+				HorizonID:       string(rune('a' + i)),
+				BaseAssetID:     xlmID,
+				BaseAmount:      1,
+				CounterAssetID:  btcID,
+				CounterAmount:   price,
+				Price:           price,
+				LedgerCloseTime: hour.Add(time.Duration(i*20) * time.Minute),
+			})
+		}
+		require.NoError(t, store.BulkInsertTrades(ctx, trades))
+
+		require.NoError(t, store.UpsertCandles(ctx, time.Hour, hour))
+		// Upserting again replaces the candles.
+		require.NoError(t, store.UpsertCandles(ctx, time.Hour, hour))
+
+		candles, err := store.GetCandles(ctx, xlmID, btcID, time.Hour, nil, false, 10)
+		require.NoError(t, err)
+		require.Len(t, candles, 2)
+		assert.True(t, hour.Equal(candles[0].OpenTime))
+		assert.Equal(t, 2.0, candles[0].Open)
+		assert.Equal(t, 4.0, candles[0].High)
+		assert.Equal(t, 1.0, candles[0].Low)
+		assert.Equal(t, 4.0, candles[0].Close)
+		assert.Equal(t, int32(3), candles[0].TradeCount)
+		assert.Equal(t, 7.0, candles[0].CounterVolume)
+		assert.Equal(t, int32(1), candles[1].TradeCount)
+
+		cursor := hour.Add(time.Hour)
+		candles, err = store.GetCandles(ctx, xlmID, btcID, time.Hour, &cursor, true, 10)
+		require.NoError(t, err)
+		require.Len(t, candles, 1)
+		assert.True(t, hour.Equal(candles[0].OpenTime))
+
+		require.NoError(t, store.DeleteOldCandles(ctx, time.Hour, cursor))
+		candles, err = store.GetCandles(ctx, xlmID, btcID, time.Hour, nil, true, 10)
+		require.NoError(t, err)
+		require.Len(t, candles, 1)
+		assert.True(t, cursor.Equal(candles[0].OpenTime))
+	})
+}
@@ -0,0 +1,77 @@
+package strkey
+
+import (
+	"github.com/stellar/go/crc16"
+)
+
+// Classification is the result of classifying a candidate strkey.
+type Classification struct {
+	// Version is the version byte encoded in the strkey. It is zero if the
+	// string could not be base32 decoded.
+	Version VersionByte
+	// Err is nil if the strkey is valid. Otherwise it is the reason it is
+	// not, e.g. ErrInvalidVersionByte or crc16.ErrInvalidChecksum.
+	Err error
+}
+
+// Valid returns true if the classified string is a valid strkey.
+func (c Classification) Valid() bool {
+	return c.Err == nil
+}
+
+// InvalidChecksum returns true if the classified string is a well formed
+// strkey whose checksum does not match its content.
+func (c Classification) InvalidChecksum() bool {
+	return c.Err == crc16.ErrInvalidChecksum
+}
+
+// Classify validates every string of srcs, reporting its version byte and, if
+// invalid, the reason. It is equivalent to calling DecodeAny on each string
+// but reuses a single decoding buffer, which makes it suitable for sanitizing
+// large amounts of addresses.
+func Classify(srcs []string) []Classification {
+	return ClassifyInto(make([]Classification, 0, len(srcs)), srcs)
+}
+
+// ClassifyInto is like Classify, but appends the classifications to dst and
+// returns the extended slice, so that callers processing data in batches can
+// reuse it.
+func ClassifyInto(dst []Classification, srcs []string) []Classification {
+	var buf []byte
+	for _, src := range srcs {
+		var c Classification
+		buf, c = classify(buf, src)
+		dst = append(dst, c)
+	}
+	return dst
+}
+
+// Validate returns, for every string of srcs, whether it is a valid strkey
+// with the expected version byte.
+func Validate(expected VersionByte, srcs []string) []bool {
+	valid := make([]bool, len(srcs))
+	var buf []byte
+	for i, src := range srcs {
+		var c Classification
+		buf, c = classify(buf, src)
+		valid[i] = c.Err == nil && c.Version == expected
+	}
+	return valid
+}
+
+// classify decodes src into buf, returning the (possibly grown) buffer so it
+// can be reused for the next string.
+func classify(buf []byte, src string) ([]byte, Classification) {
+	raw, err := decodeStringInto(buf, src)
+	if err != nil {
+		return buf, Classification{Err: err}
+	}
+
+	c := Classification{Version: VersionByte(raw[0])}
+	if err := checkValidVersionByte(c.Version); err != nil {
+		c.Err = err
+	} else if err := crc16.Validate(raw[:len(raw)-2], raw[len(raw)-2:]); err != nil {
+		c.Err = err
+	}
+	return raw, c
+}
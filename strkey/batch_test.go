@@ -0,0 +1,88 @@
+package strkey
+
+import (
+	"encoding/base32"
+	"testing"
+
+	"github.com/stellar/go/crc16"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	// a well formed strkey using an unknown version byte
+	raw := append([]byte{1 << 3}, make([]byte, 32)...)
+	raw = append(raw, crc16.Checksum(raw)...)
+	unknownVersion := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+
+	srcs := []string{
+		"GA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQHES5",
+		"SBU2RRGLXH3E5CQHTD3ODLDF2BWDCYUSSBLLZ5GNW7JXHDIYKXZWHOKR",
+		"MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK",
+		// one character changed
+		"GA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQHES4",
+		"GA3D",
+		"GA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQHES!",
+		unknownVersion,
+	}
+
+	classifications := Classify(srcs)
+	require.Len(t, classifications, len(srcs))
+
+	assert.True(t, classifications[0].Valid())
+	assert.Equal(t, VersionByteAccountID, classifications[0].Version)
+	assert.True(t, classifications[1].Valid())
+	assert.Equal(t, VersionByte(VersionByteSeed), classifications[1].Version)
+	assert.True(t, classifications[2].Valid())
+	assert.Equal(t, VersionByte(VersionByteMuxedAccount), classifications[2].Version)
+
+	assert.False(t, classifications[3].Valid())
+	assert.True(t, classifications[3].InvalidChecksum())
+	assert.Equal(t, VersionByteAccountID, classifications[3].Version)
+
+	assert.EqualError(t, classifications[4].Err, "strkey is 4 bytes long; minimum valid length is 5")
+	assert.Equal(t, VersionByte(0), classifications[4].Version)
+	assert.False(t, classifications[5].Valid())
+	assert.False(t, classifications[5].InvalidChecksum())
+
+	assert.Equal(t, ErrInvalidVersionByte, classifications[6].Err)
+	assert.Equal(t, VersionByte(1<<3), classifications[6].Version)
+
+	// results match DecodeAny
+	for i, src := range srcs {
+		version, _, err := DecodeAny(src)
+		if err == nil {
+			assert.True(t, classifications[i].Valid(), src)
+			assert.Equal(t, version, classifications[i].Version, src)
+		} else {
+			assert.False(t, classifications[i].Valid(), src)
+		}
+	}
+
+	dst := ClassifyInto(classifications[:0], srcs[:1])
+	assert.Len(t, dst, 1)
+	assert.True(t, dst[0].Valid())
+}
+
+func TestValidate(t *testing.T) {
+	valid := Validate(VersionByteAccountID, []string{
+		"GA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQHES5",
+		"SBU2RRGLXH3E5CQHTD3ODLDF2BWDCYUSSBLLZ5GNW7JXHDIYKXZWHOKR",
+		"GA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQHES4",
+		"",
+	})
+	assert.Equal(t, []bool{true, false, false, false}, valid)
+}
+
+func BenchmarkClassify(b *testing.B) {
+	srcs := make([]string, 1000)
+	for i := range srcs {
+		srcs[i] = "GA3D5KRYM6CB7OWQ6TWYRR3Z4T7GNZLKERYNZGGA5SOAOPIFY6YQHES5"
+	}
+	dst := make([]Classification, 0, len(srcs))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = ClassifyInto(dst[:0], srcs)
+	}
+}
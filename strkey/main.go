@@ -34,6 +34,10 @@ const (
 	//VersionByteHashX is the version byte used for encoded stellar hashX
 	//signer keys.
 	VersionByteHashX = 23 << 3 // Base32-encodes to 'X...'
+
+	//VersionByteSignedPayload is the version byte used for encoded stellar
+	//ed25519 signed payload signer keys.
+	VersionByteSignedPayload = 15 << 3 // Base32-encodes to 'P...'
 )
 
 // DecodeAny decodes the provided StrKey into a raw value, checking the checksum
@@ -164,7 +168,7 @@ func Version(src string) (VersionByte, error) {
 // is not one of the defined valid version byte constants.
 func checkValidVersionByte(version VersionByte) error {
 	switch version {
-	case VersionByteAccountID, VersionByteMuxedAccount, VersionByteSeed, VersionByteHashTx, VersionByteHashX:
+	case VersionByteAccountID, VersionByteMuxedAccount, VersionByteSeed, VersionByteHashTx, VersionByteHashX, VersionByteSignedPayload:
 		return nil
 	default:
 		return ErrInvalidVersionByte
@@ -188,9 +192,15 @@ func initDecodingTable() [256]byte {
 // potentially be strkey encoded (i.e. it has both a version byte and a
 // checksum, neither of which are explicitly checked by this func)
 func decodeString(src string) ([]byte, error) {
+	return decodeStringInto(nil, src)
+}
+
+// decodeStringInto is like decodeString, but decodes into buf (reusing its
+// capacity) instead of allocating a new slice.
+func decodeStringInto(buf []byte, src string) ([]byte, error) {
 	// operations on strings are expensive since it involves unicode parsing
 	// so, we use bytes from the beginning
-	srcBytes := []byte(src)
+	srcBytes := append(buf[:0], src...)
 	// The minimal binary decoded length is 3 bytes (version byte and 2-byte CRC) which,
 	// in unpadded base32 (since each character provides 5 bits) corresponds to ceiling(8*3/5) = 5
 	if len(srcBytes) < 5 {
@@ -0,0 +1,98 @@
+package strkey
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// MaxSignedPayloadLength is the maximum length of the payload of an ed25519
+// signed payload signer.
+const MaxSignedPayloadLength = 64
+
+// SignedPayload is an ed25519 signed payload signer key: the signer is
+// satisfied by a signature of the payload by the ed25519 public key.
+type SignedPayload struct {
+	signer  string
+	payload []byte
+}
+
+// NewSignedPayload returns the signed payload of the ed25519 public key
+// signerPublicKey (a G... address) and payload, which is at most
+// MaxSignedPayloadLength bytes long.
+func NewSignedPayload(signerPublicKey string, payload []byte) (*SignedPayload, error) {
+	if len(payload) > MaxSignedPayloadLength {
+		return nil, errors.Errorf("payload length %d exceeds max %d", len(payload), MaxSignedPayloadLength)
+	}
+	if _, err := Decode(VersionByteAccountID, signerPublicKey); err != nil {
+		return nil, errors.Wrap(err, "invalid signer public key")
+	}
+	return &SignedPayload{signer: signerPublicKey, payload: payload}, nil
+}
+
+// Signer returns the ed25519 public key (a G... address) signing the payload.
+func (sp *SignedPayload) Signer() string {
+	return sp.signer
+}
+
+// Payload returns the payload signed by the signer.
+func (sp *SignedPayload) Payload() []byte {
+	return sp.payload
+}
+
+// Encode returns the strkey (a P... address) of the signed payload. Its raw
+// form is the XDR encoding of the ed25519 public key followed by the
+// variable length opaque payload.
+func (sp *SignedPayload) Encode() (string, error) {
+	signer, err := Decode(VersionByteAccountID, sp.signer)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid signer public key")
+	}
+
+	var raw bytes.Buffer
+	raw.Write(signer)
+	if err := binary.Write(&raw, binary.BigEndian, uint32(len(sp.payload))); err != nil {
+		return "", err
+	}
+	raw.Write(sp.payload)
+	// XDR pads opaque data with zeros to a multiple of 4 bytes.
+	raw.Write(make([]byte, (4-len(sp.payload)%4)%4))
+
+	return Encode(VersionByteSignedPayload, raw.Bytes())
+}
+
+// DecodeSignedPayload decodes the signed payload strkey (a P... address)
+// address.
+func DecodeSignedPayload(address string) (*SignedPayload, error) {
+	raw, err := Decode(VersionByteSignedPayload, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// 32 bytes for the public key, 4 bytes for the length of the payload.
+	if len(raw) < 36 {
+		return nil, errors.New("invalid signed payload length")
+	}
+	length := binary.BigEndian.Uint32(raw[32:36])
+	if length > MaxSignedPayloadLength {
+		return nil, errors.Errorf("payload length %d exceeds max %d", length, MaxSignedPayloadLength)
+	}
+	padded := (length + 3) / 4 * 4
+	if uint32(len(raw)-36) != padded {
+		return nil, errors.New("invalid signed payload length")
+	}
+	for _, b := range raw[36+length:] {
+		if b != 0 {
+			return nil, errors.New("invalid signed payload padding")
+		}
+	}
+
+	signer, err := Encode(VersionByteAccountID, raw[:32])
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, length)
+	copy(payload, raw[36:36+length])
+	return &SignedPayload{signer: signer, payload: payload}, nil
+}
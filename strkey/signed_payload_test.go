@@ -0,0 +1,67 @@
+package strkey
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedPayload(t *testing.T) {
+	signer := "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"
+	payload := make([]byte, 32)
+	for i := range payload {
+		payload[i] = byte(i + 1)
+	}
+	address := "PA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAQACAQDAQCQMBYIBEFAWDANBYHRAEISCMKBKFQXDAMRUGY4DUPB6IBZGM"
+
+	sp, err := NewSignedPayload(signer, payload)
+	require.NoError(t, err)
+	encoded, err := sp.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, address, encoded)
+
+	version, err := Version(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, VersionByte(VersionByteSignedPayload), version)
+
+	decoded, err := DecodeSignedPayload(address)
+	require.NoError(t, err)
+	assert.Equal(t, signer, decoded.Signer())
+	assert.Equal(t, payload, decoded.Payload())
+
+	// Payloads whose length is not a multiple of 4 are padded.
+	for _, length := range []int{0, 1, 29, 64} {
+		sp, err = NewSignedPayload(signer, payload[:0])
+		require.NoError(t, err)
+		sp.payload = make([]byte, length)
+		encoded, err = sp.Encode()
+		require.NoError(t, err)
+		decoded, err = DecodeSignedPayload(encoded)
+		require.NoError(t, err)
+		assert.Len(t, decoded.Payload(), length)
+	}
+}
+
+func TestSignedPayloadErrors(t *testing.T) {
+	signer := "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"
+	_, err := NewSignedPayload(signer, make([]byte, 65))
+	assert.EqualError(t, err, "payload length 65 exceeds max 64")
+	_, err = NewSignedPayload("XBU2RRGLXH3E5CQHTD3ODLDF2BWDCYUSSBLLZ5GNW7JXHDIYKXZWGTOG", nil)
+	assert.EqualError(t, err, "invalid signer public key: invalid version byte")
+
+	_, err = DecodeSignedPayload(signer)
+	assert.Equal(t, ErrInvalidVersionByte, err)
+
+	raw := append(MustDecode(VersionByteAccountID, signer), 0, 0, 0, 1, 1, 0, 0, 1)
+	_, err = DecodeSignedPayload(MustEncode(VersionByteSignedPayload, raw))
+	assert.EqualError(t, err, "invalid signed payload padding")
+
+	raw = append(MustDecode(VersionByteAccountID, signer), 0, 0, 0, 4, 1, 2)
+	_, err = DecodeSignedPayload(MustEncode(VersionByteSignedPayload, raw))
+	assert.EqualError(t, err, "invalid signed payload length")
+
+	raw = append(MustDecode(VersionByteAccountID, signer), 0, 0, 0, 65)
+	_, err = DecodeSignedPayload(MustEncode(VersionByteSignedPayload, raw))
+	assert.EqualError(t, err, "payload length 65 exceeds max 64")
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"go/types"
 	stdLog "log"
@@ -43,6 +44,13 @@ func (cos ConfigOptions) SetValues() {
 	}
 }
 
+// WatchSecrets calls WatchSecret on each ConfigOption.
+func (cos ConfigOptions) WatchSecrets(ctx context.Context) {
+	for _, co := range cos {
+		co.WatchSecret(ctx)
+	}
+}
+
 // ConfigOption is a complete description of the configuration of a command line option
 type ConfigOption struct {
 	Name           string              // e.g. "db-url"
@@ -53,7 +61,10 @@ type ConfigOption struct {
 	Usage          string              // Help text
 	CustomSetValue func(*ConfigOption) // Optional function for custom validation/transformation
 	ConfigKey      interface{}         // Pointer to the final key in the linked Config struct
+	Secret         bool                // Whether the value may be a reference to a secret, e.g. "vault://secret/data/horizon#db-url", see DefaultSecretResolver
+	OnSecretChange func(string)        // Optional function called with the new value when the lease of the secret expires and it changed, see WatchSecret
 	flag           *pflag.Flag         // The persistent flag that the config option is attached to
+	secretRef      string              // The secret reference the value was resolved from, if any
 }
 
 // Init handles initialisation steps, including configuring and binding the env variable name.
@@ -85,6 +96,10 @@ func (co *ConfigOption) Require() {
 func (co *ConfigOption) SetValue() {
 	co.Bind()
 
+	if co.Secret {
+		co.resolveSecret()
+	}
+
 	// Use a custom setting function, if one is provided
 	if co.CustomSetValue != nil {
 		co.CustomSetValue(co)
@@ -101,6 +116,41 @@ func (co *ConfigOption) UsageText() string {
 	return fmt.Sprintf("%s (%s)", co.Usage, co.EnvVar)
 }
 
+// WatchSecret resolves the secret of the ConfigOption again each time its
+// lease expires and calls OnSecretChange with the new value when it changed,
+// until ctx is done. It must be called after SetValue, and does nothing if
+// OnSecretChange is nil or the value was not resolved from a secret
+// reference. The secret is watched in a new goroutine.
+func (co *ConfigOption) WatchSecret(ctx context.Context) {
+	if co.OnSecretChange == nil || co.secretRef == "" {
+		return
+	}
+	go DefaultSecretResolver.Watch(ctx, co.secretRef, co.OnSecretChange)
+}
+
+// resolveSecret replaces the value of a secret ConfigOption holding a secret
+// reference with the value of the secret, so that it is set as any other value.
+func (co *ConfigOption) resolveSecret() {
+	ref := viper.GetString(co.Name)
+	if !DefaultSecretResolver.IsSecretRef(ref) {
+		return
+	}
+	co.secretRef = ref
+	value, err := DefaultSecretResolver.Resolve(context.Background(), ref)
+	if err != nil {
+		stdLog.Fatalf("Invalid config: unable to resolve the secret of %s: %v", co.Name, err)
+	}
+	// A flag set on the command line takes precedence over the values set
+	// in viper, so it is replaced too.
+	if co.flag != nil && co.flag.Changed {
+		if err = co.flag.Value.Set(value); err != nil {
+			stdLog.Fatalf("Invalid config: unable to set the secret of %s: %v", co.Name, err)
+		}
+	} else {
+		viper.Set(co.Name, value)
+	}
+}
+
 // setSimpleValue sets the value of a ConfigOption's configKey, based on the ConfigOption's default type.
 func (co *ConfigOption) setSimpleValue() {
 	if co.ConfigKey != nil {
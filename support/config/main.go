@@ -3,6 +3,7 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 
@@ -48,6 +49,13 @@ func decode(content string, dest interface{}) error {
 		return errors.New("Unknown fields: " + fmt.Sprintf("%+v", undecoded))
 	}
 
+	// Fields tagged with `secret:"true"` may hold a reference to a secret,
+	// which is resolved before validating the config.
+	err = DefaultSecretResolver.ResolveSecrets(context.Background(), dest)
+	if err != nil {
+		return errors.Wrap(err, "resolving secrets failed")
+	}
+
 	valid, err := govalidator.ValidateStruct(dest)
 
 	if valid {
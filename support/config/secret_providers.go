@@ -0,0 +1,240 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/stellar/go/support/errors"
+)
+
+var secretsHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// VaultProvider gets secrets from the HTTP API of HashiCorp Vault. The path
+// of a secret is its API path without the /v1/ prefix, e.g.
+// "secret/data/horizon" for the horizon secret of the KV version 2 engine
+// mounted at secret/. Leases of dynamic secrets are honored.
+type VaultProvider struct {
+	// Address is the address of the Vault server, defaults to the VAULT_ADDR
+	// environment variable.
+	Address string
+	// Token is the Vault token, defaults to the VAULT_TOKEN environment
+	// variable.
+	Token string
+	// HTTP defaults to a client with a 30 seconds timeout.
+	HTTP *http.Client
+}
+
+type vaultResponse struct {
+	LeaseDuration int                    `json:"lease_duration"`
+	Data          map[string]interface{} `json:"data"`
+}
+
+// GetSecret implements SecretProvider.
+func (p *VaultProvider) GetSecret(ctx context.Context, path, key string) (Secret, error) {
+	address, token := p.Address, p.Token
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" {
+		return Secret{}, errors.New("vault address is not set, set VAULT_ADDR")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return Secret{}, errors.Wrap(err, "building vault request")
+	}
+	req.Header.Set("X-Vault-Token", token)
+	var resp vaultResponse
+	if err = getSecretJSON(ctx, p.HTTP, req, &resp); err != nil {
+		return Secret{}, err
+	}
+
+	// The KV version 2 engine nests the secret in a data field, along with
+	// its metadata.
+	data := resp.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok && data["metadata"] != nil {
+		data = nested
+	}
+	if key == "" {
+		if len(data) != 1 {
+			return Secret{}, errors.Errorf("vault secret %s has %d fields, a key is required", path, len(data))
+		}
+		for k := range data {
+			key = k
+		}
+	}
+	value, err := objectField(data, key)
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{Value: value, LeaseDuration: time.Duration(resp.LeaseDuration) * time.Second}, nil
+}
+
+// AWSSecretsManagerProvider gets secrets from AWS Secrets Manager. The path
+// of a secret is its name or ARN. A key selects a field of a secret stored as
+// a JSON object.
+type AWSSecretsManagerProvider struct {
+	// Client defaults to a client configured from the environment, see
+	// session.NewSession.
+	Client secretsmanageriface.SecretsManagerAPI
+
+	once      sync.Once
+	clientErr error
+}
+
+// GetSecret implements SecretProvider.
+func (p *AWSSecretsManagerProvider) GetSecret(ctx context.Context, path, key string) (Secret, error) {
+	p.once.Do(func() {
+		if p.Client != nil {
+			return
+		}
+		sess, err := session.NewSession()
+		if err != nil {
+			p.clientErr = errors.Wrap(err, "creating aws session")
+			return
+		}
+		p.Client = secretsmanager.New(sess)
+	})
+	if p.clientErr != nil {
+		return Secret{}, p.clientErr
+	}
+
+	out, err := p.Client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(path),
+	})
+	if err != nil {
+		return Secret{}, errors.Wrap(err, "getting secret value")
+	}
+	secret := string(out.SecretBinary)
+	if out.SecretString != nil {
+		secret = *out.SecretString
+	}
+	value, err := secretField(secret, key)
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{Value: value}, nil
+}
+
+// GCPSecretManagerProvider gets secrets from Google Cloud Secret Manager. The
+// path of a secret is its resource name, e.g.
+// "projects/my-project/secrets/horizon", and the latest version is used if
+// the path has no version. A key selects a field of a secret stored as a
+// JSON object.
+type GCPSecretManagerProvider struct {
+	// Endpoint defaults to "https://secretmanager.googleapis.com/v1/".
+	Endpoint string
+	// Token returns the OAuth 2.0 access token sent to the API. It defaults
+	// to the GOOGLE_OAUTH_ACCESS_TOKEN environment variable if set, or to
+	// the token of the default service account, from the metadata server of
+	// the instance.
+	Token func(ctx context.Context) (string, error)
+	// HTTP defaults to a client with a 30 seconds timeout.
+	HTTP *http.Client
+}
+
+type gcpAccessResponse struct {
+	Payload struct {
+		Data string `json:"data"`
+	} `json:"payload"`
+}
+
+// GetSecret implements SecretProvider.
+func (p *GCPSecretManagerProvider) GetSecret(ctx context.Context, path, key string) (Secret, error) {
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = "https://secretmanager.googleapis.com/v1/"
+	}
+	tokenFunc := p.Token
+	if tokenFunc == nil {
+		tokenFunc = p.defaultToken
+	}
+	token, err := tokenFunc(ctx)
+	if err != nil {
+		return Secret{}, errors.Wrap(err, "getting gcp access token")
+	}
+
+	path = strings.Trim(path, "/")
+	if !strings.Contains(path, "/versions/") {
+		path += "/versions/latest"
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/"+path+":access", nil)
+	if err != nil {
+		return Secret{}, errors.Wrap(err, "building gcp secret manager request")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	var resp gcpAccessResponse
+	if err = getSecretJSON(ctx, p.HTTP, req, &resp); err != nil {
+		return Secret{}, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return Secret{}, errors.Wrap(err, "decoding secret payload")
+	}
+	value, err := secretField(string(data), key)
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{Value: value}, nil
+}
+
+func (p *GCPSecretManagerProvider) defaultToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		"http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token",
+		nil,
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "building metadata server request")
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	var resp struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err = getSecretJSON(ctx, p.HTTP, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.AccessToken, nil
+}
+
+// getSecretJSON sends the request and decodes the JSON response into dest.
+func getSecretJSON(ctx context.Context, client *http.Client, req *http.Request, dest interface{}) error {
+	if client == nil {
+		client = secretsHTTPClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrapf(err, "requesting %s", req.URL.Host)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "reading response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		// The body is not included, as it may contain part of the secret.
+		return fmt.Errorf("%s responded with status %d", req.URL.Host, resp.StatusCode)
+	}
+	return errors.Wrap(json.Unmarshal(body, dest), "decoding response")
+}
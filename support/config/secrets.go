@@ -0,0 +1,296 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// DefaultSecretTTL is the time the secrets without a lease are cached for
+// before being resolved again.
+const DefaultSecretTTL = 5 * time.Minute
+
+// minSecretRefresh is the minimum time between two resolutions of a watched
+// secret, so that secrets with very short leases don't flood the provider.
+const minSecretRefresh = 10 * time.Second
+
+// Secret is a value resolved by a SecretProvider.
+type Secret struct {
+	Value string
+	// LeaseDuration is the time the value is valid for, after which it is
+	// resolved again. Zero means the value has no lease, in which case the
+	// TTL of the SecretResolver is used.
+	LeaseDuration time.Duration
+}
+
+// SecretProvider gets secrets from a secrets manager.
+type SecretProvider interface {
+	// GetSecret returns the secret stored at path. If key is not empty, the
+	// secret is an object and the value of its key field is returned.
+	GetSecret(ctx context.Context, path, key string) (Secret, error)
+}
+
+// SecretRef is a reference to a secret, in the <scheme>://<path>[#<key>]
+// format, e.g. "vault://secret/data/horizon#database-url". The scheme is the
+// name the provider of the secret is registered with in a SecretResolver.
+type SecretRef struct {
+	Scheme string
+	Path   string
+	Key    string
+}
+
+// String returns the reference in the <scheme>://<path>[#<key>] format.
+func (ref SecretRef) String() string {
+	s := ref.Scheme + "://" + ref.Path
+	if ref.Key != "" {
+		s += "#" + ref.Key
+	}
+	return s
+}
+
+// ParseSecretRef parses a reference in the <scheme>://<path>[#<key>] format.
+func ParseSecretRef(s string) (SecretRef, error) {
+	parts := strings.SplitN(s, "://", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return SecretRef{}, errors.Errorf("invalid secret reference %q, expected <scheme>://<path>[#<key>]", s)
+	}
+	ref := SecretRef{Scheme: parts[0], Path: parts[1]}
+	if i := strings.LastIndex(ref.Path, "#"); i >= 0 {
+		ref.Path, ref.Key = ref.Path[:i], ref.Path[i+1:]
+	}
+	return ref, nil
+}
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+// SecretResolver resolves secret references with the provider registered for
+// their scheme, and caches the values until their lease expires. Use
+// NewSecretResolver to create a new instance. It is safe for concurrent use.
+type SecretResolver struct {
+	// TTL is the time the secrets without a lease are cached for, defaults to
+	// DefaultSecretTTL.
+	TTL time.Duration
+
+	providers  map[string]SecretProvider
+	mutex      sync.Mutex
+	cache      map[string]cachedSecret
+	now        func() time.Time
+	minRefresh time.Duration
+}
+
+// NewSecretResolver creates a SecretResolver with the providers, by scheme.
+func NewSecretResolver(providers map[string]SecretProvider) *SecretResolver {
+	return &SecretResolver{
+		providers:  providers,
+		cache:      map[string]cachedSecret{},
+		now:        time.Now,
+		minRefresh: minSecretRefresh,
+	}
+}
+
+// DefaultSecretResolver is the resolver of the secret config options and of
+// the fields tagged as secret of the config files. It resolves the "vault",
+// "aws-sm" and "gcp-sm" schemes, see VaultProvider, AWSSecretsManagerProvider
+// and GCPSecretManagerProvider for their paths and credentials.
+var DefaultSecretResolver = NewSecretResolver(map[string]SecretProvider{
+	"vault":  &VaultProvider{},
+	"aws-sm": &AWSSecretsManagerProvider{},
+	"gcp-sm": &GCPSecretManagerProvider{},
+})
+
+// IsSecretRef returns true if s is a reference to a secret of one of the
+// registered schemes. Other values, e.g. a URL whose scheme is not a
+// registered one, are used as is.
+func (r *SecretResolver) IsSecretRef(s string) bool {
+	ref, err := ParseSecretRef(s)
+	if err != nil {
+		return false
+	}
+	_, ok := r.providers[ref.Scheme]
+	return ok
+}
+
+// Resolve returns the value of the referenced secret, from the cache if its
+// lease has not expired.
+func (r *SecretResolver) Resolve(ctx context.Context, s string) (string, error) {
+	value, _, err := r.resolve(ctx, s)
+	return value, err
+}
+
+func (r *SecretResolver) resolve(ctx context.Context, s string) (string, time.Time, error) {
+	r.mutex.Lock()
+	cached, ok := r.cache[s]
+	r.mutex.Unlock()
+	if ok && r.now().Before(cached.expiresAt) {
+		return cached.value, cached.expiresAt, nil
+	}
+
+	ref, err := ParseSecretRef(s)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	provider, ok := r.providers[ref.Scheme]
+	if !ok {
+		return "", time.Time{}, errors.Errorf("no secret provider for scheme %q", ref.Scheme)
+	}
+	secret, err := provider.GetSecret(ctx, ref.Path, ref.Key)
+	if err != nil {
+		return "", time.Time{}, errors.Wrapf(err, "getting secret %s", s)
+	}
+
+	ttl := secret.LeaseDuration
+	if ttl <= 0 {
+		ttl = r.TTL
+	}
+	if ttl <= 0 {
+		ttl = DefaultSecretTTL
+	}
+	cached = cachedSecret{value: secret.Value, expiresAt: r.now().Add(ttl)}
+	r.mutex.Lock()
+	r.cache[s] = cached
+	r.mutex.Unlock()
+	return cached.value, cached.expiresAt, nil
+}
+
+// Watch resolves the referenced secret again each time its lease expires,
+// and calls onChange with the new value when it changed, until ctx is done.
+// Failed resolutions are logged and retried.
+func (r *SecretResolver) Watch(ctx context.Context, s string, onChange func(value string)) {
+	value, expiresAt, err := r.resolve(ctx, s)
+	if err != nil {
+		log.Ctx(ctx).WithField("secret", s).Error(err)
+		expiresAt = r.now()
+	}
+
+	for {
+		wait := expiresAt.Sub(r.now())
+		if wait < r.minRefresh {
+			wait = r.minRefresh
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		var newValue string
+		newValue, expiresAt, err = r.resolve(ctx, s)
+		if err != nil {
+			log.Ctx(ctx).WithField("secret", s).Error(err)
+			expiresAt = r.now()
+			continue
+		}
+		if newValue != value {
+			value = newValue
+			onChange(value)
+		}
+	}
+}
+
+// SecretValue holds the value of a secret config option which is updated
+// while the program runs, e.g. by passing its Set method as OnSecretChange.
+// It is safe for concurrent use.
+type SecretValue struct {
+	value atomic.Value
+}
+
+// NewSecretValue returns a SecretValue holding value.
+func NewSecretValue(value string) *SecretValue {
+	v := &SecretValue{}
+	v.Set(value)
+	return v
+}
+
+// Get returns the current value of the secret.
+func (v *SecretValue) Get() string {
+	value, _ := v.value.Load().(string)
+	return value
+}
+
+// Set replaces the value of the secret.
+func (v *SecretValue) Set(value string) {
+	v.value.Store(value)
+}
+
+// ResolveSecrets replaces the string fields of the struct pointed to by dest
+// which are tagged with `secret:"true"` and hold a secret reference with the
+// value of the secret. Nested structs and slices of structs are resolved too.
+func (r *SecretResolver) ResolveSecrets(ctx context.Context, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("dest must be a pointer to a struct")
+	}
+	return r.resolveStructSecrets(ctx, v.Elem())
+}
+
+func (r *SecretResolver) resolveStructSecrets(ctx context.Context, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, fieldValue := t.Field(i), v.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		switch {
+		case fieldValue.Kind() == reflect.Struct:
+			if err := r.resolveStructSecrets(ctx, fieldValue); err != nil {
+				return err
+			}
+		case fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct && !fieldValue.IsNil():
+			if err := r.resolveStructSecrets(ctx, fieldValue.Elem()); err != nil {
+				return err
+			}
+		case fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.Struct:
+			for j := 0; j < fieldValue.Len(); j++ {
+				if err := r.resolveStructSecrets(ctx, fieldValue.Index(j)); err != nil {
+					return errors.Wrapf(err, "resolving %s[%d]", field.Name, j)
+				}
+			}
+		case fieldValue.Kind() == reflect.String && field.Tag.Get("secret") == "true":
+			if !r.IsSecretRef(fieldValue.String()) {
+				continue
+			}
+			value, err := r.Resolve(ctx, fieldValue.String())
+			if err != nil {
+				return errors.Wrapf(err, "resolving field %s", field.Name)
+			}
+			fieldValue.SetString(value)
+		}
+	}
+	return nil
+}
+
+// secretField returns the value of the key field of a secret stored as a JSON
+// object, or the secret as is if key is empty.
+func secretField(secret string, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", errors.Wrap(err, "decoding secret as a JSON object")
+	}
+	return objectField(fields, key)
+}
+
+func objectField(fields map[string]interface{}, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok {
+		return "", errors.Errorf("secret has no %q field", key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
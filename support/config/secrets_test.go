@@ -0,0 +1,357 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"go/types"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSecretProvider struct {
+	values map[string]Secret
+	calls  int
+}
+
+func (p *testSecretProvider) GetSecret(ctx context.Context, path, key string) (Secret, error) {
+	p.calls++
+	secret, ok := p.values[path+"#"+key]
+	if !ok {
+		return Secret{}, assert.AnError
+	}
+	return secret, nil
+}
+
+func TestParseSecretRef(t *testing.T) {
+	ref, err := ParseSecretRef("vault://secret/data/horizon#db-url")
+	require.NoError(t, err)
+	assert.Equal(t, SecretRef{Scheme: "vault", Path: "secret/data/horizon", Key: "db-url"}, ref)
+	assert.Equal(t, "vault://secret/data/horizon#db-url", ref.String())
+
+	ref, err = ParseSecretRef("aws-sm://horizon-seed")
+	require.NoError(t, err)
+	assert.Equal(t, SecretRef{Scheme: "aws-sm", Path: "horizon-seed"}, ref)
+	assert.Equal(t, "aws-sm://horizon-seed", ref.String())
+
+	for _, s := range []string{"", "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY", "vault://", "://path"} {
+		_, err = ParseSecretRef(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSecretResolverResolve(t *testing.T) {
+	provider := &testSecretProvider{values: map[string]Secret{
+		"db#url":  {Value: "postgres://localhost/db", LeaseDuration: time.Hour},
+		"seed#":   {Value: "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY"},
+		"other#x": {Value: "x"},
+	}}
+	resolver := NewSecretResolver(map[string]SecretProvider{"test": provider})
+	now := time.Unix(0, 0)
+	resolver.now = func() time.Time { return now }
+
+	assert.True(t, resolver.IsSecretRef("test://db#url"))
+	assert.False(t, resolver.IsSecretRef("postgres://localhost/db"))
+	assert.False(t, resolver.IsSecretRef("plain value"))
+
+	value, err := resolver.Resolve(context.Background(), "test://db#url")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://localhost/db", value)
+	value, err = resolver.Resolve(context.Background(), "test://seed")
+	require.NoError(t, err)
+	assert.Equal(t, "SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY", value)
+	assert.Equal(t, 2, provider.calls)
+
+	// The values are cached until their lease, or the default TTL, expires.
+	now = now.Add(DefaultSecretTTL - time.Second)
+	_, err = resolver.Resolve(context.Background(), "test://db#url")
+	require.NoError(t, err)
+	_, err = resolver.Resolve(context.Background(), "test://seed")
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+
+	now = now.Add(time.Second)
+	_, err = resolver.Resolve(context.Background(), "test://db#url")
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.calls)
+	_, err = resolver.Resolve(context.Background(), "test://seed")
+	require.NoError(t, err)
+	assert.Equal(t, 3, provider.calls)
+
+	now = now.Add(time.Hour)
+	_, err = resolver.Resolve(context.Background(), "test://db#url")
+	require.NoError(t, err)
+	assert.Equal(t, 4, provider.calls)
+
+	_, err = resolver.Resolve(context.Background(), "test://missing")
+	assert.EqualError(t, err, "getting secret test://missing: "+assert.AnError.Error())
+	_, err = resolver.Resolve(context.Background(), "unknown://db#url")
+	assert.EqualError(t, err, `no secret provider for scheme "unknown"`)
+}
+
+type secretsTestConfig struct {
+	DatabaseURL string `secret:"true"`
+	Name        string
+	Nested      struct {
+		Seed string `secret:"true"`
+	}
+	Pointer *struct {
+		Token string `secret:"true"`
+	}
+	Slice []struct {
+		Seed string `secret:"true"`
+	}
+}
+
+func TestSecretResolverResolveSecrets(t *testing.T) {
+	resolver := NewSecretResolver(map[string]SecretProvider{"test": &testSecretProvider{values: map[string]Secret{
+		"db#url": {Value: "postgres://localhost/db"},
+		"seed#":  {Value: "seed"},
+		"token#": {Value: "token"},
+	}}})
+
+	config := secretsTestConfig{DatabaseURL: "test://db#url", Name: "test://seed"}
+	config.Nested.Seed = "test://seed"
+	config.Pointer = &struct {
+		Token string `secret:"true"`
+	}{Token: "test://token"}
+	config.Slice = []struct {
+		Seed string `secret:"true"`
+	}{{Seed: "plain"}, {Seed: "test://seed"}}
+	require.NoError(t, resolver.ResolveSecrets(context.Background(), &config))
+	assert.Equal(t, "postgres://localhost/db", config.DatabaseURL)
+	assert.Equal(t, "test://seed", config.Name, "fields not tagged as secret are not resolved")
+	assert.Equal(t, "seed", config.Nested.Seed)
+	assert.Equal(t, "token", config.Pointer.Token)
+	assert.Equal(t, "plain", config.Slice[0].Seed)
+	assert.Equal(t, "seed", config.Slice[1].Seed)
+
+	// Values which are not secret references are used as is.
+	config = secretsTestConfig{DatabaseURL: "postgres://localhost/other"}
+	require.NoError(t, resolver.ResolveSecrets(context.Background(), &config))
+	assert.Equal(t, "postgres://localhost/other", config.DatabaseURL)
+
+	config = secretsTestConfig{DatabaseURL: "test://missing"}
+	assert.EqualError(
+		t,
+		resolver.ResolveSecrets(context.Background(), &config),
+		"resolving field DatabaseURL: getting secret test://missing: "+assert.AnError.Error(),
+	)
+	assert.EqualError(t, resolver.ResolveSecrets(context.Background(), config), "dest must be a pointer to a struct")
+}
+
+func TestConfigOption_secret(t *testing.T) {
+	defaultResolver := DefaultSecretResolver
+	defer func() { DefaultSecretResolver = defaultResolver }()
+	DefaultSecretResolver = NewSecretResolver(map[string]SecretProvider{"test": &testSecretProvider{values: map[string]Secret{
+		"db#url": {Value: "postgres://localhost/db"},
+	}}})
+
+	var dbURL, name string
+	configOpts := ConfigOptions{
+		{Name: "secret-db-url", OptType: types.String, ConfigKey: &dbURL, Secret: true},
+		{Name: "secret-name", OptType: types.String, ConfigKey: &name},
+	}
+	cmd := &cobra.Command{
+		Use: "doathing",
+		Run: func(_ *cobra.Command, _ []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+		},
+	}
+	require.NoError(t, configOpts.Init(cmd))
+	cmd.SetArgs([]string{"--secret-db-url", "test://db#url", "--secret-name", "test://db#url"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "postgres://localhost/db", dbURL)
+	assert.Equal(t, "test://db#url", name)
+}
+
+// rotatingSecretProvider returns the last value sent to it, with a very short
+// lease.
+type rotatingSecretProvider struct {
+	values chan string
+	last   string
+}
+
+func (p *rotatingSecretProvider) GetSecret(ctx context.Context, path, key string) (Secret, error) {
+	select {
+	case p.last = <-p.values:
+	default:
+	}
+	return Secret{Value: p.last, LeaseDuration: time.Millisecond}, nil
+}
+
+func TestConfigOption_watchSecret(t *testing.T) {
+	defaultResolver := DefaultSecretResolver
+	defer func() { DefaultSecretResolver = defaultResolver }()
+	provider := &rotatingSecretProvider{values: make(chan string, 3)}
+	provider.values <- "secret1"
+	DefaultSecretResolver = NewSecretResolver(map[string]SecretProvider{"test": provider})
+	DefaultSecretResolver.minRefresh = time.Millisecond
+
+	var value string
+	changes := make(chan string)
+	configOpts := ConfigOptions{
+		{
+			Name:      "watched-secret",
+			OptType:   types.String,
+			ConfigKey: &value,
+			Secret:    true,
+			OnSecretChange: func(v string) {
+				changes <- v
+			},
+		},
+	}
+	cmd := &cobra.Command{
+		Use: "doathing",
+		Run: func(_ *cobra.Command, _ []string) {
+			configOpts.Require()
+			configOpts.SetValues()
+		},
+	}
+	require.NoError(t, configOpts.Init(cmd))
+	cmd.SetArgs([]string{"--watched-secret", "test://rotated"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "secret1", value)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	configOpts.WatchSecrets(ctx)
+	provider.values <- "secret2"
+	assert.Equal(t, "secret2", <-changes)
+
+	// The secret is not watched anymore once ctx is done.
+	cancel()
+	provider.values <- "secret3"
+	select {
+	case v := <-changes:
+		t.Fatalf("unexpected change to %q after the context was canceled", v)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSecretValue(t *testing.T) {
+	v := NewSecretValue("secret1")
+	assert.Equal(t, "secret1", v.Get())
+	v.Set("secret2")
+	assert.Equal(t, "secret2", v.Get())
+	assert.Equal(t, "", (&SecretValue{}).Get())
+}
+
+func TestVaultProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/horizon":
+			w.Write([]byte(`{"lease_duration":0,"data":{"data":{"db-url":"postgres://localhost/db","seed":"seed"},"metadata":{"version":1}}}`))
+		case "/v1/database/creds/horizon":
+			w.Write([]byte(`{"lease_duration":3600,"data":{"password":"password"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	provider := &VaultProvider{Address: server.URL, Token: "token"}
+
+	secret, err := provider.GetSecret(context.Background(), "secret/data/horizon", "db-url")
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Value: "postgres://localhost/db"}, secret)
+
+	secret, err = provider.GetSecret(context.Background(), "database/creds/horizon", "")
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Value: "password", LeaseDuration: time.Hour}, secret)
+
+	_, err = provider.GetSecret(context.Background(), "secret/data/horizon", "")
+	assert.EqualError(t, err, "vault secret secret/data/horizon has 2 fields, a key is required")
+	_, err = provider.GetSecret(context.Background(), "secret/data/horizon", "missing")
+	assert.EqualError(t, err, `secret has no "missing" field`)
+	_, err = provider.GetSecret(context.Background(), "secret/data/missing", "")
+	assert.Error(t, err)
+
+	provider.Token = "other"
+	_, err = provider.GetSecret(context.Background(), "secret/data/horizon", "db-url")
+	assert.Contains(t, err.Error(), "responded with status 403")
+}
+
+type mockSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+}
+
+func (m *mockSecretsManager) GetSecretValueWithContext(ctx aws.Context, input *secretsmanager.GetSecretValueInput, opts ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	switch aws.StringValue(input.SecretId) {
+	case "horizon":
+		return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(`{"db-url":"postgres://localhost/db"}`)}, nil
+	case "seed":
+		return &secretsmanager.GetSecretValueOutput{SecretString: aws.String("seed")}, nil
+	default:
+		return nil, assert.AnError
+	}
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	provider := &AWSSecretsManagerProvider{Client: &mockSecretsManager{}}
+
+	secret, err := provider.GetSecret(context.Background(), "horizon", "db-url")
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Value: "postgres://localhost/db"}, secret)
+
+	secret, err = provider.GetSecret(context.Background(), "seed", "")
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Value: "seed"}, secret)
+
+	_, err = provider.GetSecret(context.Background(), "seed", "db-url")
+	assert.Contains(t, err.Error(), "decoding secret as a JSON object")
+	_, err = provider.GetSecret(context.Background(), "missing", "")
+	assert.EqualError(t, err, "getting secret value: "+assert.AnError.Error())
+}
+
+func TestGCPSecretManagerProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload string
+		switch r.URL.Path {
+		case "/v1/projects/p/secrets/horizon/versions/latest:access":
+			payload = `{"db-url":"postgres://localhost/db"}`
+		case "/v1/projects/p/secrets/seed/versions/2:access":
+			payload = "seed"
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"payload":{"data":"` + base64.StdEncoding.EncodeToString([]byte(payload)) + `"}}`))
+	}))
+	defer server.Close()
+	provider := &GCPSecretManagerProvider{
+		Endpoint: server.URL + "/v1/",
+		Token:    func(context.Context) (string, error) { return "token", nil },
+	}
+
+	secret, err := provider.GetSecret(context.Background(), "projects/p/secrets/horizon", "db-url")
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Value: "postgres://localhost/db"}, secret)
+
+	secret, err = provider.GetSecret(context.Background(), "projects/p/secrets/seed/versions/2", "")
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Value: "seed"}, secret)
+
+	_, err = provider.GetSecret(context.Background(), "projects/p/secrets/missing", "")
+	assert.Contains(t, err.Error(), "responded with status 404")
+
+	provider.Token = func(context.Context) (string, error) { return "", assert.AnError }
+	_, err = provider.GetSecret(context.Background(), "projects/p/secrets/seed", "")
+	assert.EqualError(t, err, "getting gcp access token: "+assert.AnError.Error())
+}
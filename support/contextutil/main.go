@@ -0,0 +1,27 @@
+// Package contextutil provides helpers to work with contexts.
+package contextutil
+
+import (
+	"context"
+	"time"
+)
+
+// Detach returns a context carrying the values of ctx but none of its
+// deadline or cancelation. It is used for work which is shared by several
+// callers, or which must complete after the caller which started it is gone.
+// Such work should still be bounded, e.g. with context.WithTimeout.
+func Detach(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
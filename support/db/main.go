@@ -99,8 +99,24 @@ type Session struct {
 	// DB is the database connection that queries should be executed against.
 	DB *sqlx.DB
 
+	// SlowQueryThreshold, if non-zero, is the duration above which queries
+	// are logged at the warning level. Bound parameters of slow queries are
+	// redacted as they can contain sensitive data.
+	SlowQueryThreshold time.Duration
+
+	// Replica, if set, is a read replica of DB. SELECT statements run outside
+	// of a transaction are routed to the replica, unless it is lagging, the
+	// context was made with WithPrimary or the session already wrote to DB.
+	// All other statements, and all the statements of transactions, run
+	// against DB.
+	Replica *Replica
+
 	tx        *sqlx.Tx
 	txOptions *sql.TxOptions
+	// wrote is set once a statement other than a read ran on the session,
+	// whose reads are then routed to DB to see its writes. It is not copied
+	// by Clone.
+	wrote bool
 }
 
 type SessionInterface interface {
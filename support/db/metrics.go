@@ -41,18 +41,10 @@ func contextRoute(ctx context.Context) string {
 
 type SessionWithMetrics struct {
 	SessionInterface
-	registry                 *prometheus.Registry
-	queryCounter             *prometheus.CounterVec
-	queryDurationSummary     *prometheus.SummaryVec
-	maxOpenConnectionsGauge  prometheus.GaugeFunc
-	openConnectionsGauge     prometheus.GaugeFunc
-	inUseConnectionsGauge    prometheus.GaugeFunc
-	idleConnectionsGauge     prometheus.GaugeFunc
-	waitCountCounter         prometheus.CounterFunc
-	waitDurationCounter      prometheus.CounterFunc
-	maxIdleClosedCounter     prometheus.CounterFunc
-	maxIdleTimeClosedCounter prometheus.CounterFunc
-	maxLifetimeClosedCounter prometheus.CounterFunc
+	registry             *prometheus.Registry
+	queryCounter         *prometheus.CounterVec
+	queryDurationSummary *prometheus.SummaryVec
+	poolStatsCollector   *PoolStatsCollector
 }
 
 func RegisterMetrics(base *Session, namespace string, sub Subservice, registry *prometheus.Registry) SessionInterface {
@@ -95,130 +87,12 @@ func RegisterMetrics(base *Session, namespace string, sub Subservice, registry *
 	// ),
 	// registry.MustRegister(s.txnDuration)
 
-	s.maxOpenConnectionsGauge = prometheus.NewGaugeFunc(
-		prometheus.GaugeOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "max_open_connections",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			// Right now MaxOpenConnections in Horizon is static however it's possible that
-			// it will change one day. In such case, using GaugeFunc is very cheap and will
-			// prevent issues with this metric in the future.
-			return float64(base.DB.Stats().MaxOpenConnections)
-		},
-	)
-	registry.MustRegister(s.maxOpenConnectionsGauge)
-
-	s.openConnectionsGauge = prometheus.NewGaugeFunc(
-		prometheus.GaugeOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "open_connections",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return float64(base.DB.Stats().OpenConnections)
-		},
-	)
-	registry.MustRegister(s.openConnectionsGauge)
-
-	s.inUseConnectionsGauge = prometheus.NewGaugeFunc(
-		prometheus.GaugeOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "in_use_connections",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return float64(base.DB.Stats().InUse)
-		},
-	)
-	registry.MustRegister(s.inUseConnectionsGauge)
-
-	s.idleConnectionsGauge = prometheus.NewGaugeFunc(
-		prometheus.GaugeOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "idle_connections",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return float64(base.DB.Stats().Idle)
-		},
-	)
-	registry.MustRegister(s.idleConnectionsGauge)
-
-	s.waitCountCounter = prometheus.NewCounterFunc(
-		prometheus.CounterOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "wait_count_total",
-			Help:        "total number of number of connections waited for",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return float64(base.DB.Stats().WaitCount)
-		},
-	)
-	registry.MustRegister(s.waitCountCounter)
-
-	s.waitDurationCounter = prometheus.NewCounterFunc(
-		prometheus.CounterOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "wait_duration_seconds_total",
-			Help:        "total time blocked waiting for a new connection",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return base.DB.Stats().WaitDuration.Seconds()
-		},
-	)
-	registry.MustRegister(s.waitDurationCounter)
-
-	s.maxIdleClosedCounter = prometheus.NewCounterFunc(
-		prometheus.CounterOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "max_idle_closed_total",
-			Help:        "total number of number of connections closed due to SetMaxIdleConns",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return float64(base.DB.Stats().MaxIdleClosed)
-		},
-	)
-	registry.MustRegister(s.maxIdleClosedCounter)
-
-	s.maxIdleTimeClosedCounter = prometheus.NewCounterFunc(
-		prometheus.CounterOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "max_idle_time_closed_total",
-			Help:        "total number of number of connections closed due to SetConnMaxIdleTime",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return float64(base.DB.Stats().MaxIdleTimeClosed)
-		},
-	)
-	registry.MustRegister(s.maxIdleTimeClosedCounter)
-
-	s.maxLifetimeClosedCounter = prometheus.NewCounterFunc(
-		prometheus.CounterOpts{
-			Namespace:   namespace,
-			Subsystem:   "db",
-			Name:        "max_lifetime_closed_total",
-			Help:        "total number of number of connections closed due to SetConnMaxLifetime",
-			ConstLabels: prometheus.Labels{"subservice": string(sub)},
-		},
-		func() float64 {
-			return float64(base.DB.Stats().MaxLifetimeClosed)
-		},
+	s.poolStatsCollector = NewPoolStatsCollector(
+		base.DB.DB,
+		namespace,
+		prometheus.Labels{"subservice": string(sub)},
 	)
-	registry.MustRegister(s.maxLifetimeClosedCounter)
+	registry.MustRegister(s.poolStatsCollector)
 
 	return s
 }
@@ -228,15 +102,7 @@ func (s *SessionWithMetrics) Close() error {
 	s.registry.Unregister(s.queryDurationSummary)
 	// s.registry.Unregister(s.txnCounter)
 	// s.registry.Unregister(s.txnDurationSummary)
-	s.registry.Unregister(s.maxOpenConnectionsGauge)
-	s.registry.Unregister(s.openConnectionsGauge)
-	s.registry.Unregister(s.inUseConnectionsGauge)
-	s.registry.Unregister(s.idleConnectionsGauge)
-	s.registry.Unregister(s.waitCountCounter)
-	s.registry.Unregister(s.waitDurationCounter)
-	s.registry.Unregister(s.maxIdleClosedCounter)
-	s.registry.Unregister(s.maxIdleTimeClosedCounter)
-	s.registry.Unregister(s.maxLifetimeClosedCounter)
+	s.registry.Unregister(s.poolStatsCollector)
 	return s.SessionInterface.Close()
 }
 
@@ -275,15 +141,7 @@ func (s *SessionWithMetrics) Clone() SessionInterface {
 		queryDurationSummary: s.queryDurationSummary,
 		// txnCounter:               s.txnCounter,
 		// txnDurationSummary:       s.txnDurationSummary,
-		maxOpenConnectionsGauge:  s.maxOpenConnectionsGauge,
-		openConnectionsGauge:     s.openConnectionsGauge,
-		inUseConnectionsGauge:    s.inUseConnectionsGauge,
-		idleConnectionsGauge:     s.idleConnectionsGauge,
-		waitCountCounter:         s.waitCountCounter,
-		waitDurationCounter:      s.waitDurationCounter,
-		maxIdleClosedCounter:     s.maxIdleClosedCounter,
-		maxIdleTimeClosedCounter: s.maxIdleTimeClosedCounter,
-		maxLifetimeClosedCounter: s.maxLifetimeClosedCounter,
+		poolStatsCollector: s.poolStatsCollector,
 	}
 }
 
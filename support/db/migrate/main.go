@@ -0,0 +1,247 @@
+// Package migrate applies and rolls back the SQL migrations of a service on
+// its Postgres database. It wraps github.com/rubenv/sql-migrate, adding a dry
+// run mode and an advisory lock so that the instances of a service migrating
+// the database as they start do not apply the same migrations concurrently.
+//
+// Migrations are read from a directory of .sql files in the sql-migrate
+// format, either embedded in the binary or on disk:
+//
+//	//go:embed migrations/*.sql
+//	var migrationsFS embed.FS
+//
+//	migrator := &migrate.Migrator{
+//	    DB:     db,
+//	    Source: migrate.FileSystemSource(http.FS(migrationsFS), "migrations"),
+//	}
+//	applied, err := migrator.Migrate(ctx, migrate.Up, 0)
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	sqlmigrate "github.com/rubenv/sql-migrate"
+	"github.com/stellar/go/support/errors"
+)
+
+// Direction is the direction in which migrations are run.
+type Direction = sqlmigrate.MigrationDirection
+
+const (
+	// Up applies the migrations not applied yet, oldest first.
+	Up = sqlmigrate.Up
+	// Down rolls back the applied migrations, newest first.
+	Down = sqlmigrate.Down
+)
+
+// DefaultLockKey is the key of the advisory lock held while migrating when
+// Migrator.LockKey is not set.
+const DefaultLockKey int64 = 0x6d696772617465
+
+// Source finds the migrations to run.
+type Source = sqlmigrate.MigrationSource
+
+// FileSystemSource returns a Source reading the migrations from the .sql files
+// of dir in fs. With Go 1.16 or later, an embed.FS is read with
+// FileSystemSource(http.FS(fsys), dir).
+func FileSystemSource(fs http.FileSystem, dir string) Source {
+	return fileSystemSource{fs: fs, dir: path.Join("/", dir)}
+}
+
+type fileSystemSource struct {
+	fs  http.FileSystem
+	dir string
+}
+
+func (s fileSystemSource) FindMigrations() ([]*sqlmigrate.Migration, error) {
+	dir, err := s.fs.Open(s.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", s.dir)
+	}
+	defer dir.Close()
+	files, err := dir.Readdir(0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", s.dir)
+	}
+
+	var migrations []*sqlmigrate.Migration
+	for _, info := range files {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".sql") {
+			continue
+		}
+		migration, err := s.parse(info.Name())
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Less(migrations[j])
+	})
+	return migrations, nil
+}
+
+func (s fileSystemSource) parse(name string) (*sqlmigrate.Migration, error) {
+	file, err := s.fs.Open(path.Join(s.dir, name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", name)
+	}
+	defer file.Close()
+	migration, err := sqlmigrate.ParseMigration(name, file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", name)
+	}
+	return migration, nil
+}
+
+// BindataSource returns a Source reading the migrations from the .sql files of
+// dir in assets generated by go-bindata.
+func BindataSource(asset func(path string) ([]byte, error), assetDir func(path string) ([]string, error), dir string) Source {
+	return &sqlmigrate.AssetMigrationSource{Asset: asset, AssetDir: assetDir, Dir: dir}
+}
+
+// MemorySource returns a Source of the given migrations, parsed from their
+// SQL by id.
+func MemorySource(migrations map[string]string) (Source, error) {
+	source := &sqlmigrate.MemoryMigrationSource{}
+	for id, sql := range migrations {
+		migration, err := sqlmigrate.ParseMigration(id, bytes.NewReader([]byte(sql)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", id)
+		}
+		source.Migrations = append(source.Migrations, migration)
+	}
+	return source, nil
+}
+
+// Status is the state of a migration in the database.
+type Status struct {
+	ID string
+	// Applied is true if the migration was applied.
+	Applied bool
+	// AppliedAt is the time the migration was applied, zero if it was not.
+	AppliedAt time.Time
+}
+
+// Migrator runs the migrations of Source on a Postgres database, recording
+// the migrations applied in the sql-migrate table, gorp_migrations unless
+// changed with sqlmigrate.SetTable. As the advisory lock holds a connection
+// while migrating, DB must allow at least two open connections.
+type Migrator struct {
+	DB     *sqlx.DB
+	Source Source
+	// LockKey is the key of the Postgres advisory lock held while migrating.
+	// Services sharing a database should use the same key. Defaults to
+	// DefaultLockKey.
+	LockKey int64
+	// DryRun makes Migrate return the migrations it would run without
+	// running them.
+	DryRun bool
+}
+
+// Migrate runs the migrations in the given direction, at most count of them
+// or all of them if count is zero, and returns the ids of the migrations run.
+// If a migration fails, the ids of the migrations run before it are returned
+// with the error.
+//
+// Migrate waits for the advisory lock held by the other migrators of the
+// database, or for ctx to be done, before planning the migrations. In dry run
+// mode the migrations are planned without taking the lock.
+func (m *Migrator) Migrate(ctx context.Context, dir Direction, count int) ([]string, error) {
+	if count < 0 {
+		return nil, errors.Errorf("invalid migration count %d", count)
+	}
+	if m.DryRun {
+		return m.plan(dir, count)
+	}
+
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	ids, err := m.plan(dir, count)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	n, err := sqlmigrate.ExecMax(m.DB.DB, "postgres", m.Source, dir, count)
+	if n > len(ids) {
+		// the plan and the execution only differ if migrations were run
+		// without the lock
+		n = len(ids)
+	}
+	if err != nil {
+		return ids[:n], errors.Wrap(err, "running migrations")
+	}
+	return ids[:n], nil
+}
+
+// Status returns the status of the migrations of Source, sorted by id.
+func (m *Migrator) Status() ([]Status, error) {
+	migrations, err := m.Source.FindMigrations()
+	if err != nil {
+		return nil, errors.Wrap(err, "finding migrations")
+	}
+	records, err := sqlmigrate.GetMigrationRecords(m.DB.DB, "postgres")
+	if err != nil {
+		return nil, errors.Wrap(err, "getting applied migrations")
+	}
+	appliedAt := map[string]time.Time{}
+	for _, record := range records {
+		appliedAt[record.Id] = record.AppliedAt
+	}
+
+	statuses := make([]Status, 0, len(migrations))
+	for _, migration := range migrations {
+		at, ok := appliedAt[migration.Id]
+		statuses = append(statuses, Status{ID: migration.Id, Applied: ok, AppliedAt: at})
+		delete(appliedAt, migration.Id)
+	}
+	for id := range appliedAt {
+		return nil, errors.Errorf("unknown migration %s in database", id)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) plan(dir Direction, count int) ([]string, error) {
+	migrations, _, err := sqlmigrate.PlanMigration(m.DB.DB, "postgres", m.Source, dir, count)
+	if err != nil {
+		return nil, errors.Wrap(err, "planning migrations")
+	}
+	ids := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		ids = append(ids, migration.Id)
+	}
+	return ids, nil
+}
+
+// lock takes the advisory lock in a transaction of its own, which holds it
+// while sql-migrate runs the migrations on the other connections of the pool
+// and releases it when rolled back.
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	key := m.LockKey
+	if key == 0 {
+		key = DefaultLockKey
+	}
+	// the transaction is not bound to ctx, which would roll it back and
+	// release the lock if ctx was done while migrating
+	tx, err := m.DB.BeginTx(context.Background(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "beginning lock transaction")
+	}
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", key); err != nil {
+		tx.Rollback()
+		return nil, errors.Wrap(err, "taking migration lock")
+	}
+	return func() { tx.Rollback() }, nil
+}
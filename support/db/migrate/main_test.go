@@ -0,0 +1,185 @@
+package migrate
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSource = FileSystemSource(http.Dir("testdata"), "migrations")
+
+func migrationIDs(t *testing.T, source Source) []string {
+	migrations, err := source.FindMigrations()
+	require.NoError(t, err)
+	ids := []string{}
+	for _, migration := range migrations {
+		ids = append(ids, migration.Id)
+	}
+	return ids
+}
+
+func TestFileSystemSource(t *testing.T) {
+	migrations, err := testSource.FindMigrations()
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, "1_accounts.sql", migrations[0].Id)
+	require.Len(t, migrations[0].Up, 1)
+	assert.Contains(t, migrations[0].Up[0], "CREATE TABLE accounts")
+	require.Len(t, migrations[0].Down, 1)
+	assert.Contains(t, migrations[0].Down[0], "DROP TABLE accounts")
+	assert.Equal(t, "2_balances.sql", migrations[1].Id)
+
+	// the directory can be absolute
+	assert.Equal(t, []string{"1_accounts.sql", "2_balances.sql"}, migrationIDs(t, FileSystemSource(http.Dir("testdata"), "/migrations/")))
+
+	_, err = FileSystemSource(http.Dir("testdata"), "missing").FindMigrations()
+	assert.Error(t, err)
+}
+
+func TestMemorySource(t *testing.T) {
+	source, err := MemorySource(map[string]string{
+		"2_b.sql":  "-- +migrate Up\nSELECT 2;\n",
+		"10_c.sql": "-- +migrate Up\nSELECT 10;\n",
+		"1_a.sql":  "-- +migrate Up\nSELECT 1;\n",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1_a.sql", "2_b.sql", "10_c.sql"}, migrationIDs(t, source))
+
+	_, err = MemorySource(map[string]string{"1_a.sql": "SELECT 1;\n"})
+	assert.Error(t, err)
+}
+
+func TestMigrate(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	migrator := &Migrator{DB: conn, Source: testSource}
+
+	ids, err := migrator.Migrate(context.Background(), Up, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1_accounts.sql"}, ids)
+	_, err = conn.Exec("INSERT INTO accounts (id) VALUES ('GA')")
+	require.NoError(t, err)
+
+	ids, err = migrator.Migrate(context.Background(), Up, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2_balances.sql"}, ids)
+	var balance int64
+	require.NoError(t, conn.Get(&balance, "SELECT balance FROM accounts"))
+
+	ids, err = migrator.Migrate(context.Background(), Up, 0)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	ids, err = migrator.Migrate(context.Background(), Down, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2_balances.sql", "1_accounts.sql"}, ids)
+	_, err = conn.Exec("SELECT 1 FROM accounts")
+	assert.Error(t, err)
+
+	_, err = migrator.Migrate(context.Background(), Up, -1)
+	assert.EqualError(t, err, "invalid migration count -1")
+}
+
+func TestMigrate_dryRun(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	migrator := &Migrator{DB: conn, Source: testSource, DryRun: true}
+
+	ids, err := migrator.Migrate(context.Background(), Up, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1_accounts.sql", "2_balances.sql"}, ids)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.False(t, status.Applied)
+	}
+	_, err = conn.Exec("SELECT 1 FROM accounts")
+	assert.Error(t, err)
+}
+
+func TestMigrate_concurrent(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+
+	var wg sync.WaitGroup
+	applied := make(chan []string, 4)
+	for i := 0; i < cap(applied); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn := db.Open()
+			defer conn.Close()
+			ids, err := (&Migrator{DB: conn, Source: testSource}).Migrate(context.Background(), Up, 0)
+			assert.NoError(t, err)
+			applied <- ids
+		}()
+	}
+	wg.Wait()
+	close(applied)
+
+	// a single migrator applied the migrations
+	all := []string{}
+	for ids := range applied {
+		all = append(all, ids...)
+	}
+	assert.Equal(t, []string{"1_accounts.sql", "2_balances.sql"}, all)
+}
+
+func TestMigrate_lockCancelled(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+
+	locker := &Migrator{DB: conn, Source: testSource}
+	unlock, err := locker.lock(context.Background())
+	require.NoError(t, err)
+	defer unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = (&Migrator{DB: conn, Source: testSource}).Migrate(ctx, Up, 0)
+	assert.Error(t, err)
+
+	statuses, err := locker.Status()
+	require.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.False(t, status.Applied)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	db := dbtest.Postgres(t)
+	defer db.Close()
+	conn := db.Open()
+	defer conn.Close()
+	migrator := &Migrator{DB: conn, Source: testSource}
+
+	_, err := migrator.Migrate(context.Background(), Up, 1)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "1_accounts.sql", statuses[0].ID)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[0].AppliedAt.IsZero())
+	assert.Equal(t, Status{ID: "2_balances.sql"}, statuses[1])
+
+	// migrations applied from another source are reported
+	source, err := MemorySource(map[string]string{"2_balances.sql": "-- +migrate Up\nSELECT 1;\n"})
+	require.NoError(t, err)
+	_, err = (&Migrator{DB: conn, Source: source}).Status()
+	assert.EqualError(t, err, "unknown migration 1_accounts.sql in database")
+}
@@ -0,0 +1,9 @@
+-- +migrate Up
+
+CREATE TABLE accounts (
+    id text PRIMARY KEY
+);
+
+-- +migrate Down
+
+DROP TABLE accounts;
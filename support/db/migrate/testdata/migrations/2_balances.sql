@@ -0,0 +1,7 @@
+-- +migrate Up
+
+ALTER TABLE accounts ADD COLUMN balance bigint NOT NULL DEFAULT 0;
+
+-- +migrate Down
+
+ALTER TABLE accounts DROP COLUMN balance;
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PoolStatsCollector is a prometheus.Collector exposing the connection pool
+// statistics (open, in use and idle connections, waits, closed connections)
+// of a database handle. It can be registered by any service using a *sql.DB,
+// including the ones not using Session.
+type PoolStatsCollector struct {
+	db *sql.DB
+
+	maxOpenConnections *prometheus.Desc
+	openConnections    *prometheus.Desc
+	inUseConnections   *prometheus.Desc
+	idleConnections    *prometheus.Desc
+	waitCount          *prometheus.Desc
+	waitDuration       *prometheus.Desc
+	maxIdleClosed      *prometheus.Desc
+	maxIdleTimeClosed  *prometheus.Desc
+	maxLifetimeClosed  *prometheus.Desc
+}
+
+// NewPoolStatsCollector returns a collector for the pool statistics of db.
+// Metrics are created in the `db` subsystem of namespace with the given
+// constant labels.
+func NewPoolStatsCollector(db *sql.DB, namespace string, constLabels prometheus.Labels) *PoolStatsCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "db", name),
+			help,
+			nil,
+			constLabels,
+		)
+	}
+
+	return &PoolStatsCollector{
+		db:                 db,
+		maxOpenConnections: desc("max_open_connections", "maximum number of open connections to the database"),
+		openConnections:    desc("open_connections", "number of established connections both in use and idle"),
+		inUseConnections:   desc("in_use_connections", "number of connections currently in use"),
+		idleConnections:    desc("idle_connections", "number of idle connections"),
+		waitCount:          desc("wait_count_total", "total number of number of connections waited for"),
+		waitDuration:       desc("wait_duration_seconds_total", "total time blocked waiting for a new connection"),
+		maxIdleClosed:      desc("max_idle_closed_total", "total number of number of connections closed due to SetMaxIdleConns"),
+		maxIdleTimeClosed:  desc("max_idle_time_closed_total", "total number of number of connections closed due to SetConnMaxIdleTime"),
+		maxLifetimeClosed:  desc("max_lifetime_closed_total", "total number of number of connections closed due to SetConnMaxLifetime"),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *PoolStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpenConnections
+	ch <- c.openConnections
+	ch <- c.inUseConnections
+	ch <- c.idleConnections
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxIdleTimeClosed
+	ch <- c.maxLifetimeClosed
+}
+
+// Collect implements prometheus.Collector.
+func (c *PoolStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.maxOpenConnections, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.openConnections, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUseConnections, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idleConnections, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxIdleTimeClosed, prometheus.CounterValue, float64(stats.MaxIdleTimeClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolStatsCollector(t *testing.T) {
+	// sql.Open does not connect to the database so no server is needed.
+	conn, err := sql.Open("postgres", "postgres://localhost/pool_stats_test")
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetMaxOpenConns(7)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewPoolStatsCollector(conn, "test", prometheus.Labels{"subservice": "history"}))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		require.Len(t, family.GetMetric(), 1)
+		metric := family.GetMetric()[0]
+		assert.Equal(t, "subservice", metric.GetLabel()[0].GetName())
+		assert.Equal(t, "history", metric.GetLabel()[0].GetValue())
+		if metric.GetGauge() != nil {
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		} else {
+			values[family.GetName()] = metric.GetCounter().GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"test_db_max_open_connections":        7,
+		"test_db_open_connections":            0,
+		"test_db_in_use_connections":          0,
+		"test_db_idle_connections":            0,
+		"test_db_wait_count_total":            0,
+		"test_db_wait_duration_seconds_total": 0,
+		"test_db_max_idle_closed_total":       0,
+		"test_db_max_idle_time_closed_total":  0,
+		"test_db_max_lifetime_closed_total":   0,
+	}, values)
+}
@@ -0,0 +1,241 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+const (
+	// DefaultReplicaLagCheckInterval is the interval at which the lag of a
+	// replica is checked when Replica.LagCheckInterval is not set.
+	DefaultReplicaLagCheckInterval = time.Second
+	// replicaLagCheckTimeout bounds the duration of a lag check, reads are
+	// routed to the primary if the replica does not answer in time.
+	replicaLagCheckTimeout = time.Second
+)
+
+// primaryLSNQuery returns the current write-ahead log location of the
+// primary.
+const primaryLSNQuery = `SELECT pg_current_wal_lsn()::text`
+
+// replicaLagQuery returns whether a Postgres hot standby did not replay the
+// write-ahead log up to the location of the primary given as $1, and the
+// number of seconds since it replayed its last transaction. A standby that
+// replayed all the WAL of the primary is not lagging, even if the primary did
+// not commit any transaction for a while, whereas a standby whose WAL
+// receiver is disconnected is lagging as soon as the primary writes.
+const replicaLagQuery = `SELECT
+	pg_is_in_recovery() AND COALESCE(pg_last_wal_replay_lsn() < $1::pg_lsn, true) AS behind,
+	EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) AS seconds`
+
+// lockingClause matches the locking clauses of SELECT statements, which
+// cannot run on a hot standby. Statements merely mentioning them, in a string
+// literal for instance, are routed to the primary as well.
+var lockingClause = regexp.MustCompile(`(?i)\bFOR\s+(UPDATE|SHARE|NO\s+KEY\s+UPDATE|KEY\s+SHARE)\b`)
+
+// Replica is a read replica of the database of a Session. A Replica is safe
+// for concurrent use and is shared by the clones of its Session.
+type Replica struct {
+	// DB is the connection to the replica.
+	DB *sqlx.DB
+
+	// MaxLag, if non-zero, is the replication lag above which reads are
+	// routed to the primary until the replica catches up. Reads are also
+	// routed to the primary while the lag of the replica cannot be checked,
+	// including until it is first checked.
+	MaxLag time.Duration
+
+	// LagCheckInterval is the interval at which the lag is checked in the
+	// background when MaxLag is set. Defaults to
+	// DefaultReplicaLagCheckInterval.
+	LagCheckInterval time.Duration
+
+	// state is the replicaState of the replica, it is read and written
+	// atomically.
+	state     int32
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	// lag returns the lag of the replica, it is replaced in tests.
+	lag func(ctx context.Context, primary *sqlx.DB) (time.Duration, error)
+}
+
+// replicaState is the result of the latest lag check of a Replica.
+type replicaState = int32
+
+const (
+	replicaUnchecked replicaState = iota
+	replicaUsable
+	replicaLagging
+)
+
+// Lag returns the replication lag of the replica behind primary. The lag is
+// zero if the replica replayed all the write-ahead log of primary, otherwise
+// it is the time since the replica replayed its last transaction.
+func (r *Replica) Lag(ctx context.Context, primary *sqlx.DB) (time.Duration, error) {
+	if r.lag != nil {
+		return r.lag(ctx, primary)
+	}
+
+	var lsn string
+	if err := primary.GetContext(ctx, &lsn, primaryLSNQuery); err != nil {
+		return 0, errors.Wrap(err, "getting primary wal location failed")
+	}
+	var result struct {
+		Behind  bool            `db:"behind"`
+		Seconds sql.NullFloat64 `db:"seconds"`
+	}
+	if err := r.DB.GetContext(ctx, &result, replicaLagQuery, lsn); err != nil {
+		return 0, errors.Wrap(err, "checking replica lag failed")
+	}
+	if !result.Behind {
+		return 0, nil
+	}
+	if !result.Seconds.Valid {
+		return 0, errors.New("replica did not replay any transaction")
+	}
+	return time.Duration(result.Seconds.Float64 * float64(time.Second)), nil
+}
+
+// usable returns true if reads can be routed to the replica of primary. The
+// first call starts checking the lag in the background, the calls only read
+// the result of the latest check.
+func (r *Replica) usable(primary *sqlx.DB) bool {
+	if r.MaxLag <= 0 {
+		return true
+	}
+	r.startOnce.Do(func() {
+		r.stop = make(chan struct{})
+		go r.run(primary, r.stop)
+	})
+	return atomic.LoadInt32(&r.state) == replicaUsable
+}
+
+// run checks the lag of the replica every LagCheckInterval until stop is
+// closed.
+func (r *Replica) run(primary *sqlx.DB, stop <-chan struct{}) {
+	interval := r.LagCheckInterval
+	if interval <= 0 {
+		interval = DefaultReplicaLagCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.check(primary)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check checks the lag of the replica and records whether reads can be
+// routed to it.
+func (r *Replica) check(primary *sqlx.DB) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaLagCheckTimeout)
+	defer cancel()
+	lag, err := r.Lag(ctx, primary)
+	lagging := err != nil || lag > r.MaxLag
+
+	state := replicaUsable
+	if lagging {
+		state = replicaLagging
+	}
+	previous := atomic.SwapInt32(&r.state, state)
+	if err != nil {
+		log.WithField("err", err).Warn("sql: replica lag unknown, routing reads to primary")
+	} else if lagging && previous != replicaLagging {
+		log.WithField("lag", lag.String()).Warn("sql: replica lagging, routing reads to primary")
+	} else if !lagging && previous == replicaLagging {
+		log.WithField("lag", lag.String()).Info("sql: replica caught up, routing reads to replica")
+	}
+}
+
+// Close stops checking the lag of the replica and closes its connection.
+func (r *Replica) Close() error {
+	// Once Close was called, usable does not start checking the lag.
+	r.startOnce.Do(func() {})
+	r.stopOnce.Do(func() {
+		if r.stop != nil {
+			close(r.stop)
+		}
+	})
+	return r.DB.Close()
+}
+
+// OpenWithReplica opens the database at `dsn` and its read replica at
+// `replicaDSN`, and returns a new *Session routing reads to the replica, see
+// Session.Replica.
+func OpenWithReplica(dialect, dsn, replicaDSN string, maxLag time.Duration) (*Session, error) {
+	session, err := Open(dialect, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	replica, err := sqlx.Open(dialect, replicaDSN)
+	if err != nil {
+		session.Close()
+		return nil, errors.Wrap(err, "open replica failed")
+	}
+	if err = pingDB(replica); err != nil {
+		session.Close()
+		replica.Close()
+		return nil, errors.Wrap(err, "ping replica failed")
+	}
+
+	session.Replica = &Replica{DB: replica, MaxLag: maxLag}
+	return session, nil
+}
+
+type primaryContextKey struct{}
+
+// WithPrimary returns a context routing the queries of Sessions made with it
+// to the primary database, even if they are reads. It is used to read data
+// just written by another session, which may not have been replicated yet, or to call functions
+// with side effects, such as nextval() or pg_advisory_lock(), in SELECT
+// statements.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// isRead returns true if `query` is a SELECT statement which can run on a
+// replica.
+func isRead(query string) bool {
+	query = strings.TrimSpace(query)
+	if len(query) < len("select") || !strings.EqualFold(query[:len("select")], "select") {
+		return false
+	}
+	return !lockingClause.MatchString(query)
+}
+
+// readConn returns the connection `query` should be run against: the replica
+// if `query` is a read routed to it, the primary or the transaction of the
+// session otherwise. Once the session wrote to the primary, its reads are
+// routed to the primary so that they see the writes.
+func (s *Session) readConn(ctx context.Context, query string) Conn {
+	read := isRead(query)
+	if !read {
+		s.wrote = true
+	}
+	if s.tx != nil || s.Replica == nil {
+		return s.conn()
+	}
+	if primary, _ := ctx.Value(primaryContextKey{}).(bool); primary || s.wrote {
+		return s.DB
+	}
+	if !read || !s.Replica.usable(s.DB) {
+		return s.DB
+	}
+	return s.Replica.DB
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRead(t *testing.T) {
+	assert.True(t, isRead("SELECT * FROM people"))
+	assert.True(t, isRead("\n\tselect name from people where name = $1"))
+	assert.False(t, isRead("SELECT * FROM people FOR UPDATE"))
+	assert.False(t, isRead("SELECT * FROM people FOR NO KEY UPDATE SKIP LOCKED"))
+	assert.False(t, isRead("select * from people for\n share"))
+	assert.False(t, isRead("INSERT INTO people (name) VALUES ('bob') RETURNING name"))
+	assert.False(t, isRead("WITH d AS (DELETE FROM people RETURNING *) SELECT * FROM d"))
+	assert.False(t, isRead("UPDATE people SET hunger_level = 1"))
+	assert.False(t, isRead("sel"))
+}
+
+func TestReplicaCheck(t *testing.T) {
+	var (
+		lag time.Duration
+		err error
+	)
+	replica := &Replica{
+		MaxLag: time.Second,
+		lag: func(ctx context.Context, primary *sqlx.DB) (time.Duration, error) {
+			return lag, err
+		},
+	}
+	usable := func() bool {
+		return atomic.LoadInt32(&replica.state) == replicaUsable
+	}
+
+	// reads are routed to the primary until the lag is checked
+	assert.False(t, usable())
+
+	lag = 500 * time.Millisecond
+	replica.check(nil)
+	assert.True(t, usable())
+
+	lag = time.Minute
+	replica.check(nil)
+	assert.False(t, usable())
+
+	lag, err = 0, errors.New("connection refused")
+	replica.check(nil)
+	assert.False(t, usable())
+
+	err = nil
+	replica.check(nil)
+	assert.True(t, usable())
+}
+
+func TestReplicaUsable(t *testing.T) {
+	checks := make(chan struct{}, 1)
+	replica := &Replica{
+		lag: func(ctx context.Context, primary *sqlx.DB) (time.Duration, error) {
+			select {
+			case checks <- struct{}{}:
+			default:
+			}
+			return time.Minute, nil
+		},
+	}
+
+	// lag detection is disabled without MaxLag
+	assert.True(t, replica.usable(nil))
+	assert.Len(t, checks, 0)
+
+	// the lag is checked in the background, the replica is not usable until
+	// it is checked and while it is lagging
+	replica.MaxLag = time.Second
+	replica.LagCheckInterval = time.Millisecond
+	assert.False(t, replica.usable(nil))
+	<-checks
+	<-checks
+	assert.False(t, replica.usable(nil))
+	replica.stopOnce.Do(func() { close(replica.stop) })
+}
+
+func TestSessionReplica(t *testing.T) {
+	primaryDB := dbtest.Postgres(t).Load(testSchema)
+	defer primaryDB.Close()
+	replicaDB := dbtest.Postgres(t).Load(testSchema)
+	defer replicaDB.Close()
+
+	ctx := context.Background()
+	sess := &Session{
+		DB:      primaryDB.Open(),
+		Replica: &Replica{DB: replicaDB.Open()},
+	}
+	defer sess.Close()
+
+	// the replica has fewer rows than the primary, so that the database
+	// queried can be told apart
+	_, err := sess.Replica.DB.Exec("DELETE FROM people WHERE name = 'scott'")
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, sess.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 2, count)
+
+	var names []string
+	require.NoError(t, sess.SelectRaw(ctx, &names, "SELECT name FROM people"))
+	assert.Len(t, names, 2)
+
+	require.NoError(t, sess.GetRaw(WithPrimary(ctx), &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 3, count)
+
+	// clones share the replica
+	clone := sess.Clone()
+	require.NoError(t, clone.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 2, count)
+
+	// writes and transactions run against the primary, and the reads of a
+	// session that wrote are routed to the primary to see its writes
+	_, err = sess.ExecRaw(ctx, "INSERT INTO people (name, hunger_level) VALUES ('bob', 5)")
+	require.NoError(t, err)
+	require.NoError(t, sess.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 4, count)
+	require.NoError(t, sess.Begin(ctx))
+	require.NoError(t, sess.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 4, count)
+	require.NoError(t, sess.Rollback(ctx))
+
+	clone = sess.Clone()
+	require.NoError(t, clone.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 2, count)
+	var name string
+	require.NoError(t, clone.GetRaw(ctx, &name, "SELECT name FROM people WHERE name = 'scott' FOR UPDATE"))
+	assert.Equal(t, "scott", name)
+	require.NoError(t, clone.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 4, count)
+
+	// a replica that is not a standby does not lag
+	lag, err := sess.Replica.Lag(ctx, sess.DB)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), lag)
+
+	// reads are routed to the primary while the replica is lagging, the lag
+	// is checked by hand rather than in the background
+	sess.Replica.MaxLag = time.Second
+	sess.Replica.startOnce.Do(func() {})
+	sess.Replica.lag = func(ctx context.Context, primary *sqlx.DB) (time.Duration, error) {
+		return time.Minute, nil
+	}
+	sess.Replica.check(sess.DB)
+	clone = sess.Clone()
+	require.NoError(t, clone.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 4, count)
+
+	sess.Replica.lag = func(ctx context.Context, primary *sqlx.DB) (time.Duration, error) {
+		return 0, nil
+	}
+	sess.Replica.check(sess.DB)
+	require.NoError(t, clone.GetRaw(ctx, &count, "SELECT COUNT(*) FROM people"))
+	assert.Equal(t, 2, count)
+}
@@ -70,14 +70,23 @@ func (s *Session) GetTxOptions() *sql.TxOptions {
 // source is currently within.
 func (s *Session) Clone() SessionInterface {
 	return &Session{
-		DB: s.DB,
+		DB:                 s.DB,
+		SlowQueryThreshold: s.SlowQueryThreshold,
+		Replica:            s.Replica,
 	}
 }
 
 // Close delegates to the underlying database Close method, closing the database
 // and releasing any resources. It is rare to Close a DB, as the DB handle is meant
-// to be long-lived and shared between many goroutines.
+// to be long-lived and shared between many goroutines. The replica, if any, is
+// closed as well.
 func (s *Session) Close() error {
+	if s.Replica != nil {
+		if err := s.Replica.Close(); err != nil {
+			s.DB.Close()
+			return err
+		}
+	}
 	return s.DB.Close()
 }
 
@@ -135,7 +144,7 @@ func (s *Session) GetRaw(ctx context.Context, dest interface{}, query string, ar
 	}
 
 	start := time.Now()
-	err = s.conn().GetContext(ctx, dest, query, args...)
+	err = s.readConn(ctx, query).GetContext(ctx, dest, query, args...)
 	s.log(ctx, "get", start, query, args)
 
 	if err == nil {
@@ -203,6 +212,7 @@ func (s *Session) ExecRaw(ctx context.Context, query string, args ...interface{}
 		return nil, errors.Wrap(err, "replace placeholders failed")
 	}
 
+	s.wrote = true
 	start := time.Now()
 	result, err := s.conn().ExecContext(ctx, query, args...)
 	s.log(ctx, "exec", start, query, args)
@@ -250,7 +260,7 @@ func (s *Session) QueryRaw(ctx context.Context, query string, args ...interface{
 	}
 
 	start := time.Now()
-	result, err := s.conn().QueryxContext(ctx, query, args...)
+	result, err := s.readConn(ctx, query).QueryxContext(ctx, query, args...)
 	s.log(ctx, "query", start, query, args)
 
 	if err == nil {
@@ -324,7 +334,7 @@ func (s *Session) SelectRaw(
 	}
 
 	start := time.Now()
-	err = s.conn().SelectContext(ctx, dest, query, args...)
+	err = s.readConn(ctx, query).SelectContext(ctx, dest, query, args...)
 	s.log(ctx, "select", start, query, args)
 
 	if err == nil {
@@ -383,9 +393,28 @@ func (s *Session) conn() Conn {
 }
 
 func (s *Session) log(ctx context.Context, typ string, start time.Time, query string, args []interface{}) {
+	dur := time.Since(start)
+	if s.SlowQueryThreshold > 0 && dur >= s.SlowQueryThreshold {
+		log.Ctx(ctx).
+			WithField("args", redactArgs(args)).
+			WithField("sql", query).
+			WithField("dur", dur.String()).
+			Warnf("sql: slow %s", typ)
+	}
+
 	log.
 		WithField("args", args).
 		WithField("sql", query).
-		WithField("dur", time.Since(start).String()).
+		WithField("dur", dur.String()).
 		Debugf("sql: %s", typ)
 }
+
+// redactArgs replaces the values of query arguments by their types, so that
+// queries can be logged without leaking sensitive data.
+func redactArgs(args []interface{}) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		redacted[i] = fmt.Sprintf("[redacted %T]", arg)
+	}
+	return redacted
+}
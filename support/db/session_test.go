@@ -91,3 +91,12 @@ func TestSession(t *testing.T) {
 		assert.Equal("$1 = $2 = $3 = ?", out)
 	}
 }
+
+func TestRedactArgs(t *testing.T) {
+	assert.Equal(
+		t,
+		[]string{"[redacted string]", "[redacted int64]", "[redacted <nil>]"},
+		redactArgs([]interface{}{"secret", int64(1), nil}),
+	)
+	assert.Empty(t, redactArgs(nil))
+}
@@ -0,0 +1,126 @@
+// Package horizonmetrics records Prometheus metrics of the requests a
+// horizonclient.Client sends to Horizon.
+package horizonmetrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stellar/go/clients/horizonclient"
+)
+
+// Ensure Instrumentation implements horizonclient.Instrumentation and
+// prometheus.Collector.
+var (
+	_ horizonclient.Instrumentation = (*Instrumentation)(nil)
+	_ prometheus.Collector          = (*Instrumentation)(nil)
+)
+
+// Instrumentation is a horizonclient.Instrumentation recording the number,
+// latency and outcome of the requests per endpoint, the requests in flight,
+// the retries and the reuse of pooled connections. It is a
+// prometheus.Collector which must be registered to expose the metrics, and
+// can be shared by several clients:
+//
+//	instrumentation := horizonmetrics.New("myservice", nil)
+//	registry.MustRegister(instrumentation)
+//	client := &horizonclient.Client{
+//	    HorizonURL:      "https://horizon.stellar.org/",
+//	    Instrumentation: instrumentation,
+//	}
+type Instrumentation struct {
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	requestsInFlight *prometheus.GaugeVec
+	retries          *prometheus.CounterVec
+	connections      *prometheus.CounterVec
+}
+
+// New returns an Instrumentation whose metrics are created in the
+// `horizon_client` subsystem of namespace with the given constant labels.
+func New(namespace string, constLabels prometheus.Labels) *Instrumentation {
+	return &Instrumentation{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace, Subsystem: "horizon_client", Name: "requests_total",
+				Help:        "number of requests sent to horizon, status is the HTTP status code or error if the request failed",
+				ConstLabels: constLabels,
+			},
+			[]string{"method", "endpoint", "status"},
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace, Subsystem: "horizon_client", Name: "request_duration_seconds",
+				Help:        "time until the response headers of the requests sent to horizon were received",
+				ConstLabels: constLabels,
+				Buckets:     prometheus.DefBuckets,
+			},
+			[]string{"method", "endpoint"},
+		),
+		requestsInFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace, Subsystem: "horizon_client", Name: "requests_in_flight",
+				Help:        "number of requests sent to horizon waiting for their response headers",
+				ConstLabels: constLabels,
+			},
+			[]string{"method", "endpoint"},
+		),
+		retries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace, Subsystem: "horizon_client", Name: "retries_total",
+				Help:        "number of stream reconnections and transaction submissions retried",
+				ConstLabels: constLabels,
+			},
+			[]string{"method", "endpoint"},
+		),
+		connections: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace, Subsystem: "horizon_client", Name: "connections_total",
+				Help:        "number of requests sent to horizon on a new connection (reused=false) or on a pooled one (reused=true)",
+				ConstLabels: constLabels,
+			},
+			[]string{"reused"},
+		),
+	}
+}
+
+// OnRequest implements horizonclient.Instrumentation.
+func (i *Instrumentation) OnRequest(info horizonclient.RequestInfo) {
+	i.requestsInFlight.WithLabelValues(info.Method, info.Endpoint).Inc()
+}
+
+// OnResponse implements horizonclient.Instrumentation.
+func (i *Instrumentation) OnResponse(info horizonclient.ResponseInfo) {
+	i.requestsInFlight.WithLabelValues(info.Method, info.Endpoint).Dec()
+	i.requestDuration.WithLabelValues(info.Method, info.Endpoint).Observe(info.Duration.Seconds())
+
+	status := "error"
+	if info.Err == nil {
+		status = strconv.Itoa(info.StatusCode)
+		i.connections.WithLabelValues(strconv.FormatBool(info.ConnReused)).Inc()
+	}
+	i.requests.WithLabelValues(info.Method, info.Endpoint, status).Inc()
+}
+
+// OnRetry implements horizonclient.Instrumentation.
+func (i *Instrumentation) OnRetry(info horizonclient.RetryInfo) {
+	i.retries.WithLabelValues(info.Method, info.Endpoint).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (i *Instrumentation) Describe(ch chan<- *prometheus.Desc) {
+	i.requests.Describe(ch)
+	i.requestDuration.Describe(ch)
+	i.requestsInFlight.Describe(ch)
+	i.retries.Describe(ch)
+	i.connections.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (i *Instrumentation) Collect(ch chan<- prometheus.Metric) {
+	i.requests.Collect(ch)
+	i.requestDuration.Collect(ch)
+	i.requestsInFlight.Collect(ch)
+	i.retries.Collect(ch)
+	i.connections.Collect(ch)
+}
@@ -0,0 +1,90 @@
+package horizonmetrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stellar/go/clients/horizonclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func labels(metric *dto.Metric) map[string]string {
+	values := map[string]string{}
+	for _, label := range metric.GetLabel() {
+		values[label.GetName()] = label.GetValue()
+	}
+	return values
+}
+
+func TestInstrumentation(t *testing.T) {
+	instrumentation := New("test", prometheus.Labels{"service": "ticker"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(instrumentation)
+
+	request := horizonclient.RequestInfo{
+		Method:   "GET",
+		URL:      "https://localhost/ledgers/1",
+		Endpoint: "/ledgers/{id}",
+		Class:    horizonclient.EndpointClassDetail,
+	}
+	instrumentation.OnRequest(request)
+	instrumentation.OnRequest(request)
+	instrumentation.OnResponse(horizonclient.ResponseInfo{RequestInfo: request, StatusCode: 200, Duration: time.Second})
+	instrumentation.OnRequest(request)
+	instrumentation.OnResponse(horizonclient.ResponseInfo{RequestInfo: request, StatusCode: 404, Duration: time.Second, ConnReused: true})
+	instrumentation.OnRetry(horizonclient.RetryInfo{RequestInfo: request, Attempt: 1})
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	byName := map[string]*dto.MetricFamily{}
+	for _, family := range families {
+		byName[family.GetName()] = family
+		for _, metric := range family.GetMetric() {
+			assert.Equal(t, "ticker", labels(metric)["service"])
+		}
+	}
+
+	requests := byName["test_horizon_client_requests_total"].GetMetric()
+	require.Len(t, requests, 2)
+	for _, metric := range requests {
+		assert.Equal(t, "/ledgers/{id}", labels(metric)["endpoint"])
+		assert.Equal(t, "GET", labels(metric)["method"])
+		assert.Contains(t, []string{"200", "404"}, labels(metric)["status"])
+		assert.Equal(t, float64(1), metric.GetCounter().GetValue())
+	}
+
+	duration := byName["test_horizon_client_request_duration_seconds"].GetMetric()
+	require.Len(t, duration, 1)
+	assert.Equal(t, uint64(2), duration[0].GetHistogram().GetSampleCount())
+	assert.Equal(t, float64(2), duration[0].GetHistogram().GetSampleSum())
+
+	inFlight := byName["test_horizon_client_requests_in_flight"].GetMetric()
+	require.Len(t, inFlight, 1)
+	assert.Equal(t, float64(1), inFlight[0].GetGauge().GetValue())
+
+	retries := byName["test_horizon_client_retries_total"].GetMetric()
+	require.Len(t, retries, 1)
+	assert.Equal(t, float64(1), retries[0].GetCounter().GetValue())
+
+	connections := byName["test_horizon_client_connections_total"].GetMetric()
+	require.Len(t, connections, 2)
+	for _, metric := range connections {
+		assert.Equal(t, float64(1), metric.GetCounter().GetValue())
+	}
+
+	// failed requests are counted with the error status
+	instrumentation.OnResponse(horizonclient.ResponseInfo{RequestInfo: request, Err: errors.New("connection refused")})
+	families, err = registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "test_horizon_client_requests_total" {
+			continue
+		}
+		require.Len(t, family.GetMetric(), 3)
+		assert.Equal(t, "error", labels(family.GetMetric()[2])["status"])
+	}
+}
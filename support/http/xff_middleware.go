@@ -1,6 +1,7 @@
 package http
 
 import (
+	"net"
 	stdhttp "net/http"
 	"strings"
 )
@@ -9,6 +10,60 @@ import (
 type XFFMiddlewareConfig struct {
 	BehindCloudflare      bool
 	BehindAWSLoadBalancer bool
+	// TrustedProxies are the networks of the proxies in front of the server.
+	// If set, X-Forwarded-For is only used for requests coming from one of
+	// them, and the visitor is the right-most address of the header that is
+	// not one of them, so that visitors cannot spoof their address.
+	TrustedProxies []*net.IPNet
+}
+
+// ParseTrustedProxies parses a comma separated list of IP addresses and CIDR
+// networks for XFFMiddlewareConfig.TrustedProxies.
+func ParseTrustedProxies(s string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, str := range strings.Split(s, ",") {
+		str = strings.TrimSpace(str)
+		if str == "" {
+			continue
+		}
+		if !strings.Contains(str, "/") {
+			ip := net.ParseIP(str)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: str}
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(str)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// isTrustedProxy returns true if addr, an IP address with or without port,
+// is in one of the networks.
+func isTrustedProxy(networks []*net.IPNet, addr string) bool {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(strings.TrimSpace(addr))
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
 }
 
 // XFFMiddleware is a middleware that replaces http.Request.RemoteAddr with a
@@ -17,6 +72,9 @@ type XFFMiddlewareConfig struct {
 //   * If BehindCloudflare is true CF-Connecting-IP header is used.
 //   * If BehindAWSLoadBalancer is true the last value of X-Forwarded-For header
 //     is used.
+//   * If TrustedProxies is set the right-most value of X-Forwarded-For header
+//     that is not a trusted proxy is used, provided the request comes from a
+//     trusted proxy.
 //   * If none of above is set the first value of X-Forwarded-For header is
 //     used. Note: it's easy to spoof the real IP address if the application is
 //     not behind a proxy that maintains a X-Forwarded-For header.
@@ -33,6 +91,16 @@ func XFFMiddleware(config XFFMiddlewareConfig) func(next stdhttp.Handler) stdhtt
 
 			if config.BehindCloudflare {
 				newRemoteAddr = r.Header.Get("CF-Connecting-IP")
+			} else if len(config.TrustedProxies) > 0 {
+				if isTrustedProxy(config.TrustedProxies, r.RemoteAddr) {
+					ips := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+					for i := len(ips) - 1; i >= 0; i-- {
+						newRemoteAddr = ips[i]
+						if !isTrustedProxy(config.TrustedProxies, ips[i]) {
+							break
+						}
+					}
+				}
 			} else {
 				ips := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
 				if len(ips) > 0 {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockHandler struct {
@@ -77,6 +78,54 @@ func TestXFFMiddlewareNormalProxy(t *testing.T) {
 	})
 }
 
+func TestXFFMiddlewareTrustedProxies(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	require.NoError(t, err)
+	xff := XFFMiddleware(XFFMiddlewareConfig{TrustedProxies: trustedProxies})
+
+	remoteAddr := func(remoteAddr, forwardedFor string) string {
+		var got string
+		handler := xff(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.RemoteAddr
+		}))
+		handler.ServeHTTP(nil, &http.Request{
+			RemoteAddr: remoteAddr,
+			Header:     xffHeaders("X-Forwarded-For", forwardedFor),
+		})
+		return got
+	}
+
+	// The visitor is the address the trusted proxies received the request
+	// from, whatever the visitor put in the header.
+	assert.Equal(t, "2.2.2.2", remoteAddr("10.0.0.1:1234", "1.1.1.1, 2.2.2.2"))
+	assert.Equal(t, "2.2.2.2", remoteAddr("10.0.0.1:1234", "1.1.1.1, 2.2.2.2, 192.168.1.1, 10.1.2.3"))
+	assert.Equal(t, "10.0.0.2", remoteAddr("10.0.0.1:1234", "10.0.0.2"))
+	assert.Equal(t, "10.0.0.1:1234", remoteAddr("10.0.0.1:1234", ""))
+
+	// The header of requests not coming from a trusted proxy is ignored.
+	assert.Equal(t, "3.3.3.3:1234", remoteAddr("3.3.3.3:1234", "1.1.1.1"))
+	assert.Equal(t, "192.168.1.2:1234", remoteAddr("192.168.1.2:1234", "1.1.1.1"))
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	networks, err := ParseTrustedProxies("")
+	require.NoError(t, err)
+	assert.Empty(t, networks)
+
+	networks, err = ParseTrustedProxies("10.0.0.0/8,192.168.1.1,::1,fd00::/8")
+	require.NoError(t, err)
+	require.Len(t, networks, 4)
+	assert.Equal(t, "10.0.0.0/8", networks[0].String())
+	assert.Equal(t, "192.168.1.1/32", networks[1].String())
+	assert.Equal(t, "::1/128", networks[2].String())
+	assert.Equal(t, "fd00::/8", networks[3].String())
+
+	_, err = ParseTrustedProxies("10.0.0.0/8,example.com")
+	assert.EqualError(t, err, "invalid IP address: example.com")
+	_, err = ParseTrustedProxies("10.0.0.0/33")
+	assert.EqualError(t, err, "invalid CIDR address: 10.0.0.0/33")
+}
+
 func xffHeaders(name, value string) http.Header {
 	headers := http.Header{}
 	headers.Add(name, value)
@@ -0,0 +1,112 @@
+package hal
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// renderPageBatchSize is the size of the encoded records RenderPage buffers
+// before writing them to the client. Flushing each record would defeat the
+// compression of the response.
+const renderPageBatchSize = 32 * 1024
+
+// renderPageErrorRecord is the last record of the pages whose records could
+// not all be encoded after the status code was sent.
+const renderPageErrorRecord = `{
+        "type": "https://stellar.org/horizon-errors/server_error",
+        "title": "Internal Server Error",
+        "status": 500,
+        "detail": "The page could not be rendered entirely, the records following this one were dropped."
+      }`
+
+// RenderPage writes the page to w as HAL JSON, like Render, but encodes its
+// records one at a time and writes them in batches instead of buffering the
+// whole encoded page. This lowers the memory used and the time to first byte
+// of large pages. The output is identical to the output of Render.
+//
+// The status code is only sent with the first batch, so an error encoding a
+// record of the first batch, which holds the whole page for most pages,
+// results in a 500 response like Render. An error encoding a later record
+// cannot change the status code, the page is then terminated with an error
+// record, see renderPageErrorRecord. The error is returned for the caller to
+// log.
+func RenderPage(w http.ResponseWriter, page Page) error {
+	page.Init()
+
+	links, err := json.MarshalIndent(page.Links, "  ", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return errors.Wrap(err, "encoding links")
+	}
+
+	pw := pageWriter{w: w}
+	pw.buf.WriteString("{\n  \"_links\": ")
+	pw.buf.Write(links)
+	pw.buf.WriteString(",\n  \"_embedded\": {\n    \"records\": [")
+	var encodeErr error
+	for i, record := range page.Embedded.Records {
+		js, err := json.MarshalIndent(record, "      ", "  ")
+		if err != nil {
+			encodeErr = errors.Wrapf(err, "encoding record %d", i)
+			if !pw.headerWritten {
+				http.Error(w, encodeErr.Error(), http.StatusInternalServerError)
+				return encodeErr
+			}
+			js = []byte(renderPageErrorRecord)
+		}
+		if i > 0 {
+			pw.buf.WriteString(",")
+		}
+		pw.buf.WriteString("\n      ")
+		pw.buf.Write(js)
+		if encodeErr != nil {
+			break
+		}
+		if pw.buf.Len() >= renderPageBatchSize {
+			pw.flush()
+		}
+	}
+	if len(page.Embedded.Records) > 0 {
+		pw.buf.WriteString("\n    ")
+	}
+	pw.buf.WriteString("]\n  }\n}")
+	pw.write()
+	if encodeErr != nil {
+		return encodeErr
+	}
+	return errors.Wrap(pw.err, "writing page")
+}
+
+// pageWriter buffers the encoded page and writes it to w, sending the
+// headers with the first write, until the first error, which it keeps.
+type pageWriter struct {
+	w             http.ResponseWriter
+	buf           bytes.Buffer
+	headerWritten bool
+	err           error
+}
+
+// write writes the buffered bytes to w.
+func (pw *pageWriter) write() {
+	if !pw.headerWritten {
+		pw.w.Header().Set("Content-Disposition", "inline")
+		pw.w.Header().Set("Content-Type", "application/hal+json; charset=utf-8")
+		pw.w.WriteHeader(http.StatusOK)
+		pw.headerWritten = true
+	}
+	if pw.err == nil {
+		_, pw.err = pw.w.Write(pw.buf.Bytes())
+	}
+	pw.buf.Reset()
+}
+
+// flush writes the buffered bytes to w and flushes them to the client.
+func (pw *pageWriter) flush() {
+	pw.write()
+	if flusher, ok := pw.w.(http.Flusher); ok && pw.err == nil {
+		flusher.Flush()
+	}
+}
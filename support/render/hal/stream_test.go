@@ -0,0 +1,127 @@
+package hal
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRecord struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r testRecord) PagingToken() string {
+	return r.ID
+}
+
+func testPage(records ...Pageable) Page {
+	page := Page{Order: "asc", Limit: 10, Cursor: "0"}
+	page.FullURL = &url.URL{Scheme: "https", Host: "example.com", Path: "/records"}
+	page.Init()
+	for _, r := range records {
+		page.Add(r)
+	}
+	page.PopulateLinks()
+	return page
+}
+
+func TestRenderPageMatchesRender(t *testing.T) {
+	pages := []Page{
+		testPage(),
+		testPage(testRecord{ID: "1", Name: "one"}),
+		testPage(testRecord{ID: "1", Name: "one"}, testRecord{ID: "2", Name: "<two>"}),
+	}
+	for _, page := range pages {
+		want := httptest.NewRecorder()
+		Render(want, page)
+
+		got := httptest.NewRecorder()
+		err := RenderPage(got, page)
+		require.NoError(t, err)
+
+		assert.Equal(t, want.Code, got.Code)
+		assert.Equal(t, want.Header(), got.Header())
+		assert.Equal(t, want.Body.String(), got.Body.String())
+	}
+}
+
+type failingRecord struct {
+	ID string
+}
+
+func (r failingRecord) PagingToken() string {
+	return r.ID
+}
+
+func (r failingRecord) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("cannot encode record")
+}
+
+func TestRenderPageFlushesBatches(t *testing.T) {
+	// Small pages are written at once.
+	w := httptest.NewRecorder()
+	err := RenderPage(w, testPage(testRecord{ID: "1"}, testRecord{ID: "2"}))
+	require.NoError(t, err)
+	assert.False(t, w.Flushed)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Large pages are flushed in batches.
+	name := strings.Repeat("a", renderPageBatchSize/2)
+	page := testPage(testRecord{ID: "1", Name: name}, testRecord{ID: "2", Name: name}, testRecord{ID: "3", Name: name})
+	w = httptest.NewRecorder()
+	err = RenderPage(w, page)
+	require.NoError(t, err)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var decoded struct {
+		Embedded struct {
+			Records []testRecord `json:"records"`
+		} `json:"_embedded"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &decoded)
+	require.NoError(t, err)
+	assert.Len(t, decoded.Embedded.Records, 3)
+}
+
+func TestRenderPageErrorBeforeFirstBatch(t *testing.T) {
+	page := testPage(testRecord{ID: "1"}, failingRecord{ID: "2"}, testRecord{ID: "3"})
+
+	w := httptest.NewRecorder()
+	err := RenderPage(w, page)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "encoding record 1: ")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "_embedded")
+}
+
+func TestRenderPageErrorAfterFirstBatch(t *testing.T) {
+	name := strings.Repeat("a", renderPageBatchSize)
+	page := testPage(testRecord{ID: "1", Name: name}, failingRecord{ID: "2"}, testRecord{ID: "3"})
+
+	w := httptest.NewRecorder()
+	err := RenderPage(w, page)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "encoding record 1: ")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// The page is terminated with an error record.
+	var decoded struct {
+		Embedded struct {
+			Records []map[string]interface{} `json:"records"`
+		} `json:"_embedded"`
+	}
+	err = json.Unmarshal(w.Body.Bytes(), &decoded)
+	require.NoError(t, err)
+	require.Len(t, decoded.Embedded.Records, 2)
+	assert.Equal(t, "1", decoded.Embedded.Records[0]["id"])
+	assert.Equal(t, float64(http.StatusInternalServerError), decoded.Embedded.Records[1]["status"])
+}
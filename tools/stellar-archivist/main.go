@@ -5,6 +5,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -122,6 +123,23 @@ func scan(a string, opts *Options) {
 	}
 }
 
+func verify(a string, opts *Options) {
+	arch := historyarchive.MustConnect(a, opts.ConnectOpts)
+	opts.SetRange(arch, nil)
+	report, err := historyarchive.Verify(arch, &opts.CommandOpts)
+	if err != nil {
+		log.Fatal(err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		log.Fatal(err)
+	}
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
 func mirror(src string, dst string, opts *Options) {
 	srcArch := historyarchive.MustConnect(src, opts.ConnectOpts)
 	dstArch := historyarchive.MustConnect(dst, opts.ConnectOpts)
@@ -270,13 +288,28 @@ func main() {
 	})
 
 	rootCmd.AddCommand(&cobra.Command{
+		Use: "verify",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.MaybeProfile()
+			verify(firstArg(args), &opts)
+		},
+	})
+
+	mirrorCmd := &cobra.Command{
 		Use: "mirror",
 		Run: func(cmd *cobra.Command, args []string) {
 			opts.MaybeProfile()
 			src, dst := srcDst(args)
 			mirror(src, dst, &opts)
 		},
-	})
+	}
+	mirrorCmd.Flags().StringVar(
+		&opts.CommandOpts.MirrorStatePath,
+		"state-file",
+		"",
+		"local file recording the mirrored checkpoints, to resume an interrupted mirror",
+	)
+	rootCmd.AddCommand(mirrorCmd)
 
 	rootCmd.AddCommand(&cobra.Command{
 		Use: "repair",
@@ -23,7 +23,7 @@ func TestAccountMergeValidate(t *testing.T) {
 		},
 	)
 	if assert.Error(t, err) {
-		expected := "invalid address"
+		expected := "validation failed for *txnbuild.AccountMerge operation: Field: Destination"
 		assert.Contains(t, err.Error(), expected)
 	}
 }
@@ -0,0 +1,269 @@
+package txnbuild
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// maxClaimPredicateDepth is the maximum nesting depth of a claim predicate
+// accepted by stellar-core, the root predicate being at depth 1.
+const maxClaimPredicateDepth = 4
+
+// ClaimPredicate is a fluent builder of the claim predicates of claimable
+// balance claimants, e.g.
+//
+//	predicate, err := ClaimPredicateBeforeRelativeTime(24 * time.Hour).
+//		Or(ClaimPredicateBeforeAbsoluteTime(deadline).Not()).
+//		Build()
+//
+// Errors, such as negative times, are kept until Build is called.
+type ClaimPredicate struct {
+	predicate xdr.ClaimPredicate
+	err       error
+}
+
+// ClaimPredicateFromXDR returns a ClaimPredicate for an existing XDR claim
+// predicate, e.g. to describe or evaluate it.
+func ClaimPredicateFromXDR(predicate xdr.ClaimPredicate) ClaimPredicate {
+	return ClaimPredicate{predicate: predicate}
+}
+
+// ClaimPredicateUnconditional returns a predicate which is always fulfilled.
+func ClaimPredicateUnconditional() ClaimPredicate {
+	return ClaimPredicate{predicate: UnconditionalPredicate}
+}
+
+// ClaimPredicateBeforeAbsoluteTime returns a predicate fulfilled if the
+// balance is claimed in a ledger closing before t.
+func ClaimPredicateBeforeAbsoluteTime(t time.Time) ClaimPredicate {
+	if t.Unix() < 0 {
+		return ClaimPredicate{err: errors.Errorf("absolute time %s is before the unix epoch", t.UTC().Format(time.RFC3339))}
+	}
+	return ClaimPredicate{predicate: BeforeAbsoluteTimePredicate(t.Unix())}
+}
+
+// ClaimPredicateBeforeRelativeTime returns a predicate fulfilled if the
+// balance is claimed in a ledger closing less than d after the ledger which
+// created the balance. d must be a whole number of seconds.
+func ClaimPredicateBeforeRelativeTime(d time.Duration) ClaimPredicate {
+	if d < 0 {
+		return ClaimPredicate{err: errors.Errorf("relative time %s is negative", d)}
+	}
+	if d%time.Second != 0 {
+		return ClaimPredicate{err: errors.Errorf("relative time %s is not a whole number of seconds", d)}
+	}
+	return ClaimPredicate{predicate: BeforeRelativeTimePredicate(int64(d / time.Second))}
+}
+
+// And returns a predicate fulfilled if both p and other are fulfilled.
+func (p ClaimPredicate) And(other ClaimPredicate) ClaimPredicate {
+	if err := firstError(p.err, other.err); err != nil {
+		return ClaimPredicate{err: err}
+	}
+	return ClaimPredicate{predicate: AndPredicate(p.predicate, other.predicate)}
+}
+
+// Or returns a predicate fulfilled if p or other is fulfilled.
+func (p ClaimPredicate) Or(other ClaimPredicate) ClaimPredicate {
+	if err := firstError(p.err, other.err); err != nil {
+		return ClaimPredicate{err: err}
+	}
+	return ClaimPredicate{predicate: OrPredicate(p.predicate, other.predicate)}
+}
+
+// Not returns a predicate fulfilled if p is not fulfilled.
+func (p ClaimPredicate) Not() ClaimPredicate {
+	if p.err != nil {
+		return p
+	}
+	return ClaimPredicate{predicate: NotPredicate(p.predicate)}
+}
+
+// Build validates the predicate and returns its XDR.
+func (p ClaimPredicate) Build() (xdr.ClaimPredicate, error) {
+	if p.err != nil {
+		return xdr.ClaimPredicate{}, p.err
+	}
+	if err := validateClaimPredicate(p.predicate, 1); err != nil {
+		return xdr.ClaimPredicate{}, err
+	}
+	return p.predicate, nil
+}
+
+// MustBuild is like Build but panics if the predicate is not valid.
+func (p ClaimPredicate) MustBuild() xdr.ClaimPredicate {
+	predicate, err := p.Build()
+	if err != nil {
+		panic(err)
+	}
+	return predicate
+}
+
+// Claimant returns a Claimant for destination with the predicate.
+func (p ClaimPredicate) Claimant(destination string) (Claimant, error) {
+	predicate, err := p.Build()
+	if err != nil {
+		return Claimant{}, err
+	}
+	return NewClaimant(destination, &predicate), nil
+}
+
+// ClaimableAt returns true if a balance created by a ledger closing at
+// createdAt can be claimed by a ledger closing at t. It returns an error if
+// the predicate is not valid.
+func (p ClaimPredicate) ClaimableAt(createdAt, t time.Time) (bool, error) {
+	predicate, err := p.Build()
+	if err != nil {
+		return false, err
+	}
+	return evaluateClaimPredicate(predicate, createdAt.Unix(), t.Unix()), nil
+}
+
+// String returns a human-readable description of the predicate, e.g.
+// "before 2021-06-01T00:00:00Z or not (within 1h0m0s of creation)".
+func (p ClaimPredicate) String() string {
+	if p.err != nil {
+		return "invalid predicate: " + p.err.Error()
+	}
+	return describeClaimPredicate(p.predicate, true)
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateClaimPredicate validates the predicate at the given depth with the
+// same rules as stellar-core.
+func validateClaimPredicate(predicate xdr.ClaimPredicate, depth int) error {
+	if depth > maxClaimPredicateDepth {
+		return errors.Errorf("predicate nesting exceeds the maximum depth of %d", maxClaimPredicateDepth)
+	}
+
+	switch predicate.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateUnconditional:
+		return nil
+	case xdr.ClaimPredicateTypeClaimPredicateAnd, xdr.ClaimPredicateTypeClaimPredicateOr:
+		predicates := predicate.AndPredicates
+		if predicate.Type == xdr.ClaimPredicateTypeClaimPredicateOr {
+			predicates = predicate.OrPredicates
+		}
+		if predicates == nil || len(*predicates) != 2 {
+			return errors.Errorf("%s predicate must have exactly 2 predicates", claimPredicateTypeName(predicate.Type))
+		}
+		for _, p := range *predicates {
+			if err := validateClaimPredicate(p, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		if predicate.NotPredicate == nil || *predicate.NotPredicate == nil {
+			return errors.New("not predicate must have a predicate")
+		}
+		return validateClaimPredicate(**predicate.NotPredicate, depth+1)
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		if predicate.AbsBefore == nil || *predicate.AbsBefore < 0 {
+			return errors.New("absolute time predicate must have a non-negative time")
+		}
+		return nil
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime:
+		if predicate.RelBefore == nil || *predicate.RelBefore < 0 {
+			return errors.New("relative time predicate must have a non-negative time")
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown predicate type %d", predicate.Type)
+	}
+}
+
+// evaluateClaimPredicate evaluates a valid predicate for a balance created at
+// createdAt and claimed at closeTime, both unix timestamps.
+func evaluateClaimPredicate(predicate xdr.ClaimPredicate, createdAt, closeTime int64) bool {
+	switch predicate.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateAnd:
+		predicates := *predicate.AndPredicates
+		return evaluateClaimPredicate(predicates[0], createdAt, closeTime) &&
+			evaluateClaimPredicate(predicates[1], createdAt, closeTime)
+	case xdr.ClaimPredicateTypeClaimPredicateOr:
+		predicates := *predicate.OrPredicates
+		return evaluateClaimPredicate(predicates[0], createdAt, closeTime) ||
+			evaluateClaimPredicate(predicates[1], createdAt, closeTime)
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		return !evaluateClaimPredicate(**predicate.NotPredicate, createdAt, closeTime)
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		return closeTime < int64(*predicate.AbsBefore)
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime:
+		// stellar-core saturates the absolute time computed when the balance
+		// is created.
+		relBefore := int64(*predicate.RelBefore)
+		if createdAt > math.MaxInt64-relBefore {
+			return true
+		}
+		return closeTime < createdAt+relBefore
+	default:
+		return true
+	}
+}
+
+func describeClaimPredicate(predicate xdr.ClaimPredicate, root bool) string {
+	var description string
+	switch predicate.Type {
+	case xdr.ClaimPredicateTypeClaimPredicateUnconditional:
+		return "unconditional"
+	case xdr.ClaimPredicateTypeClaimPredicateAnd, xdr.ClaimPredicateTypeClaimPredicateOr:
+		predicates := predicate.AndPredicates
+		if predicate.Type == xdr.ClaimPredicateTypeClaimPredicateOr {
+			predicates = predicate.OrPredicates
+		}
+		if predicates == nil || len(*predicates) != 2 {
+			return "invalid " + claimPredicateTypeName(predicate.Type) + " predicate"
+		}
+		description = fmt.Sprintf(
+			"%s %s %s",
+			describeClaimPredicate((*predicates)[0], false),
+			claimPredicateTypeName(predicate.Type),
+			describeClaimPredicate((*predicates)[1], false),
+		)
+	case xdr.ClaimPredicateTypeClaimPredicateNot:
+		if predicate.NotPredicate == nil || *predicate.NotPredicate == nil {
+			return "invalid not predicate"
+		}
+		return "not (" + describeClaimPredicate(**predicate.NotPredicate, true) + ")"
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime:
+		if predicate.AbsBefore == nil {
+			return "invalid absolute time predicate"
+		}
+		return "before " + time.Unix(int64(*predicate.AbsBefore), 0).UTC().Format(time.RFC3339)
+	case xdr.ClaimPredicateTypeClaimPredicateBeforeRelativeTime:
+		if predicate.RelBefore == nil {
+			return "invalid relative time predicate"
+		}
+		return fmt.Sprintf("within %s of creation", time.Duration(*predicate.RelBefore)*time.Second)
+	default:
+		return fmt.Sprintf("unknown predicate type %d", predicate.Type)
+	}
+	if root {
+		return description
+	}
+	return "(" + description + ")"
+}
+
+func claimPredicateTypeName(t xdr.ClaimPredicateType) string {
+	switch t {
+	case xdr.ClaimPredicateTypeClaimPredicateAnd:
+		return "and"
+	case xdr.ClaimPredicateTypeClaimPredicateOr:
+		return "or"
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,119 @@
+package txnbuild
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimPredicateBuild(t *testing.T) {
+	deadline := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	predicate, err := ClaimPredicateBeforeRelativeTime(time.Hour).
+		Or(ClaimPredicateBeforeAbsoluteTime(deadline).Not()).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, OrPredicate(
+		BeforeRelativeTimePredicate(3600),
+		NotPredicate(BeforeAbsoluteTimePredicate(deadline.Unix())),
+	), predicate)
+
+	assert.Equal(t, UnconditionalPredicate, ClaimPredicateUnconditional().MustBuild())
+
+	claimant, err := ClaimPredicateUnconditional().Claimant(newKeypair1().Address())
+	require.NoError(t, err)
+	assert.Equal(t, NewClaimant(newKeypair1().Address(), nil), claimant)
+}
+
+func TestClaimPredicateBuildErrors(t *testing.T) {
+	_, err := ClaimPredicateBeforeRelativeTime(-time.Second).Build()
+	assert.EqualError(t, err, "relative time -1s is negative")
+
+	_, err = ClaimPredicateBeforeRelativeTime(1500 * time.Millisecond).Build()
+	assert.EqualError(t, err, "relative time 1.5s is not a whole number of seconds")
+
+	_, err = ClaimPredicateBeforeAbsoluteTime(time.Unix(-1, 0)).
+		And(ClaimPredicateUnconditional()).
+		Build()
+	assert.EqualError(t, err, "absolute time 1969-12-31T23:59:59Z is before the unix epoch")
+
+	p := ClaimPredicateUnconditional()
+	_, err = p.Not().Not().Not().Build()
+	assert.NoError(t, err)
+	_, err = p.Not().Not().Not().Not().Build()
+	assert.EqualError(t, err, "predicate nesting exceeds the maximum depth of 4")
+
+	_, err = ClaimPredicateFromXDR(xdr.ClaimPredicate{
+		Type:          xdr.ClaimPredicateTypeClaimPredicateAnd,
+		AndPredicates: &[]xdr.ClaimPredicate{UnconditionalPredicate},
+	}).Build()
+	assert.EqualError(t, err, "and predicate must have exactly 2 predicates")
+
+	_, err = ClaimPredicateFromXDR(xdr.ClaimPredicate{
+		Type: xdr.ClaimPredicateTypeClaimPredicateNot,
+	}).Build()
+	assert.EqualError(t, err, "not predicate must have a predicate")
+
+	assert.Panics(t, func() {
+		ClaimPredicateBeforeRelativeTime(-time.Second).MustBuild()
+	})
+}
+
+func TestClaimPredicateString(t *testing.T) {
+	deadline := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "unconditional", ClaimPredicateUnconditional().String())
+	assert.Equal(t, "before 2021-06-01T00:00:00Z", ClaimPredicateBeforeAbsoluteTime(deadline).String())
+	assert.Equal(t, "within 1h0m0s of creation", ClaimPredicateBeforeRelativeTime(time.Hour).String())
+	assert.Equal(t,
+		"before 2021-06-01T00:00:00Z or (not (within 1h0m0s of creation) and unconditional)",
+		ClaimPredicateBeforeAbsoluteTime(deadline).
+			Or(ClaimPredicateBeforeRelativeTime(time.Hour).Not().And(ClaimPredicateUnconditional())).
+			String(),
+	)
+	assert.Equal(t, "invalid predicate: relative time -1s is negative", ClaimPredicateBeforeRelativeTime(-time.Second).String())
+}
+
+func TestClaimPredicateClaimableAt(t *testing.T) {
+	createdAt := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	deadline := createdAt.Add(24 * time.Hour)
+
+	testCases := []struct {
+		name      string
+		predicate ClaimPredicate
+		at        time.Time
+		claimable bool
+	}{
+		{"unconditional", ClaimPredicateUnconditional(), deadline, true},
+		{"before absolute time", ClaimPredicateBeforeAbsoluteTime(deadline), deadline.Add(-time.Second), true},
+		{"at absolute time", ClaimPredicateBeforeAbsoluteTime(deadline), deadline, false},
+		{"before relative time", ClaimPredicateBeforeRelativeTime(time.Hour), createdAt.Add(59 * time.Minute), true},
+		{"at relative time", ClaimPredicateBeforeRelativeTime(time.Hour), createdAt.Add(time.Hour), false},
+		{"not before absolute time", ClaimPredicateBeforeAbsoluteTime(deadline).Not(), deadline, true},
+		{
+			"window and",
+			ClaimPredicateBeforeAbsoluteTime(deadline).And(ClaimPredicateBeforeRelativeTime(time.Hour).Not()),
+			createdAt.Add(2 * time.Hour),
+			true,
+		},
+		{
+			"window or",
+			ClaimPredicateBeforeAbsoluteTime(createdAt).Or(ClaimPredicateBeforeRelativeTime(time.Hour)),
+			createdAt.Add(2 * time.Hour),
+			false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claimable, err := tc.predicate.ClaimableAt(createdAt, tc.at)
+			require.NoError(t, err)
+			assert.Equal(t, tc.claimable, claimable)
+		})
+	}
+
+	_, err := ClaimPredicateBeforeRelativeTime(-time.Second).ClaimableAt(createdAt, deadline)
+	assert.EqualError(t, err, "relative time -1s is negative")
+}
@@ -282,6 +282,49 @@ func TestFeeBumpSignHashX(t *testing.T) {
 	assert.Equal(t, xdr.SignatureHint(expectedHint), signatures[0].Hint)
 }
 
+func TestFeeBumpSignPayload(t *testing.T) {
+	kp0, kp1 := newKeypair0(), newKeypair1()
+	payment := Payment{
+		Destination: "GCCOBXW2XQNUSL467IEILE6MMCNRR66SSVL4YQADUNYYNUVREF3FIV2Z",
+		Amount:      "10",
+		Asset:       NativeAsset{},
+	}
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(4353383146192899))
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&payment},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.NoError(t, err)
+
+	feeBumpTx, err := NewFeeBumpTransaction(
+		FeeBumpTransactionParams{
+			FeeAccount: kp1.Address(),
+			BaseFee:    2 * MinBaseFee,
+			Inner:      tx,
+		},
+	)
+	assert.NoError(t, err)
+	payload := []byte{1, 2}
+	feeBumpTx, err = feeBumpTx.SignPayload(payload, kp1)
+	assert.NoError(t, err)
+
+	signatures := feeBumpTx.Signatures()
+	assert.Len(t, signatures, 1)
+	expectedHint := kp1.Hint()
+	expectedHint[2] ^= 1
+	expectedHint[3] ^= 2
+	assert.Equal(t, xdr.SignatureHint(expectedHint), signatures[0].Hint)
+	assert.NoError(t, kp1.Verify(payload, signatures[0].Signature))
+}
+
 func TestFeeBumpAddSignatureBase64(t *testing.T) {
 	kp0 := newKeypair0()
 	kp1 := newKeypair1()
@@ -0,0 +1,80 @@
+package txnbuild
+
+import (
+	"github.com/stellar/go/support/errors"
+)
+
+// FeePercentile is a percentile of the fees recently offered by transactions
+// on the network.
+type FeePercentile int
+
+// The percentiles of the fees reported by Horizon's fee stats.
+const (
+	FeePercentile10 FeePercentile = 10
+	FeePercentile20 FeePercentile = 20
+	FeePercentile30 FeePercentile = 30
+	FeePercentile40 FeePercentile = 40
+	FeePercentile50 FeePercentile = 50
+	FeePercentile60 FeePercentile = 60
+	FeePercentile70 FeePercentile = 70
+	FeePercentile80 FeePercentile = 80
+	FeePercentile90 FeePercentile = 90
+	FeePercentile95 FeePercentile = 95
+	FeePercentile99 FeePercentile = 99
+)
+
+// Valid returns true if p is one of the FeePercentile constants.
+func (p FeePercentile) Valid() bool {
+	switch p {
+	case FeePercentile10, FeePercentile20, FeePercentile30, FeePercentile40,
+		FeePercentile50, FeePercentile60, FeePercentile70, FeePercentile80,
+		FeePercentile90, FeePercentile95, FeePercentile99:
+		return true
+	}
+	return false
+}
+
+// FeeSource returns the base fee per operation offered by the given
+// percentile of recent transactions, e.g. from Horizon's fee stats (see
+// horizonclient.FeeStatsSource). It resolves the dynamic fees of transactions
+// when they are built, see DynamicFee.
+type FeeSource interface {
+	BaseFee(percentile FeePercentile) (int64, error)
+}
+
+// DynamicFee returns a base fee for TransactionParams and
+// FeeBumpTransactionParams which is resolved with their FeeSource when the
+// transaction is built, to the base fee offered by the given percentile of
+// recent transactions, e.g.
+//
+//	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+//		...
+//		BaseFee:   txnbuild.DynamicFee(txnbuild.FeePercentile90),
+//		FeeSource: horizonclient.FeeStatsSource{Client: client},
+//	})
+//
+// The resolved base fee is never lower than MinBaseFee.
+func DynamicFee(percentile FeePercentile) int64 {
+	return -int64(percentile)
+}
+
+// resolveBaseFee returns the base fee resolved with source if baseFee was
+// returned by DynamicFee, or baseFee otherwise.
+func resolveBaseFee(baseFee int64, source FeeSource) (int64, error) {
+	percentile := FeePercentile(-baseFee)
+	if baseFee >= 0 || !percentile.Valid() {
+		return baseFee, nil
+	}
+	if source == nil {
+		return 0, errors.New("dynamic base fee requires a fee source")
+	}
+
+	resolved, err := source.BaseFee(percentile)
+	if err != nil {
+		return 0, errors.Wrap(err, "could not resolve dynamic base fee")
+	}
+	if resolved < MinBaseFee {
+		resolved = MinBaseFee
+	}
+	return resolved, nil
+}
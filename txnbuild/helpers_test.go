@@ -59,7 +59,7 @@ func unmarshalBase64(txeB64 string) (xdr.TransactionEnvelope, error) {
 func newSignedTransaction(
 	params TransactionParams,
 	network string,
-	keypairs ...*keypair.Full,
+	keypairs ...keypair.Signer,
 ) (string, error) {
 	tx, err := NewTransaction(params)
 	if err != nil {
@@ -82,7 +82,7 @@ func newSignedTransaction(
 func newSignedFeeBumpTransaction(
 	params FeeBumpTransactionParams,
 	network string,
-	keypairs ...*keypair.Full,
+	keypairs ...keypair.Signer,
 ) (string, error) {
 	tx, err := NewFeeBumpTransaction(params)
 	if err != nil {
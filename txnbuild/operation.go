@@ -34,6 +34,90 @@ func SetOpSourceMuxedAccount(op *xdr.Operation, sourceAccount string) {
 	op.SourceAccount = &opSourceAccountID
 }
 
+// buildOperationXDR builds the XDR of op. If preserveMuxedSourceAccount is
+// true the source account of the operation is encoded as a muxed account even
+// when muxed accounts are not enabled.
+func buildOperationXDR(op Operation, withMuxedAccounts, preserveMuxedSourceAccount bool) (xdr.Operation, error) {
+	xdrOp, err := op.BuildXDR(withMuxedAccounts)
+	if err != nil {
+		return xdr.Operation{}, err
+	}
+	if preserveMuxedSourceAccount && !withMuxedAccounts {
+		SetOpSourceMuxedAccount(&xdrOp, op.GetSourceAccount())
+	}
+	return xdrOp, nil
+}
+
+// validateOperationSourceAccount validates the source account of op, which
+// can be an M-address if withMuxedAccounts is true.
+func validateOperationSourceAccount(op Operation, withMuxedAccounts bool) error {
+	sourceAccount := op.GetSourceAccount()
+	if sourceAccount == "" {
+		return nil
+	}
+	var err error
+	if withMuxedAccounts {
+		_, err = xdr.AddressToMuxedAccount(sourceAccount)
+	} else {
+		_, err = xdr.AddressToAccountId(sourceAccount)
+	}
+	if err != nil {
+		return NewValidationError("SourceAccount", err.Error())
+	}
+	return nil
+}
+
+// setOperationSourceAccount sets the SourceAccount field of the operations
+// returned by operationFromXDR.
+func setOperationSourceAccount(op Operation, sourceAccount string) {
+	switch o := op.(type) {
+	case *CreateAccount:
+		o.SourceAccount = sourceAccount
+	case *Payment:
+		o.SourceAccount = sourceAccount
+	case *PathPayment:
+		o.SourceAccount = sourceAccount
+	case *ManageSellOffer:
+		o.SourceAccount = sourceAccount
+	case *CreatePassiveSellOffer:
+		o.SourceAccount = sourceAccount
+	case *SetOptions:
+		o.SourceAccount = sourceAccount
+	case *ChangeTrust:
+		o.SourceAccount = sourceAccount
+	case *AllowTrust:
+		o.SourceAccount = sourceAccount
+	case *AccountMerge:
+		o.SourceAccount = sourceAccount
+	case *Inflation:
+		o.SourceAccount = sourceAccount
+	case *ManageData:
+		o.SourceAccount = sourceAccount
+	case *BumpSequence:
+		o.SourceAccount = sourceAccount
+	case *ManageBuyOffer:
+		o.SourceAccount = sourceAccount
+	case *PathPaymentStrictSend:
+		o.SourceAccount = sourceAccount
+	case *BeginSponsoringFutureReserves:
+		o.SourceAccount = sourceAccount
+	case *EndSponsoringFutureReserves:
+		o.SourceAccount = sourceAccount
+	case *CreateClaimableBalance:
+		o.SourceAccount = sourceAccount
+	case *ClaimClaimableBalance:
+		o.SourceAccount = sourceAccount
+	case *RevokeSponsorship:
+		o.SourceAccount = sourceAccount
+	case *Clawback:
+		o.SourceAccount = sourceAccount
+	case *ClawbackClaimableBalance:
+		o.SourceAccount = sourceAccount
+	case *SetTrustLineFlags:
+		o.SourceAccount = sourceAccount
+	}
+}
+
 // operationFromXDR returns a txnbuild Operation from its corresponding XDR operation
 func operationFromXDR(xdrOp xdr.Operation, withMuxedAccounts bool) (Operation, error) {
 	var newOp Operation
@@ -492,3 +492,35 @@ func testOperationsMarshallingRoundtrip(t *testing.T, operations []Operation, wi
 		assert.Equal(t, operations[i], tx.Operations()[i])
 	}
 }
+
+func TestSetOperationSourceAccount(t *testing.T) {
+	address := "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"
+	ops := []Operation{
+		&CreateAccount{},
+		&Payment{},
+		&PathPayment{},
+		&ManageSellOffer{},
+		&CreatePassiveSellOffer{},
+		&SetOptions{},
+		&ChangeTrust{},
+		&AllowTrust{},
+		&AccountMerge{},
+		&Inflation{},
+		&ManageData{},
+		&BumpSequence{},
+		&ManageBuyOffer{},
+		&PathPaymentStrictSend{},
+		&BeginSponsoringFutureReserves{},
+		&EndSponsoringFutureReserves{},
+		&CreateClaimableBalance{},
+		&ClaimClaimableBalance{},
+		&RevokeSponsorship{},
+		&Clawback{},
+		&ClawbackClaimableBalance{},
+		&SetTrustLineFlags{},
+	}
+	for _, op := range ops {
+		setOperationSourceAccount(op, address)
+		assert.Equal(t, address, op.GetSourceAccount(), "%T", op)
+	}
+}
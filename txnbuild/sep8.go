@@ -0,0 +1,109 @@
+package txnbuild
+
+import (
+	"github.com/stellar/go/support/errors"
+)
+
+// SEP8RevisionParams are the parameters of a transaction revised by a SEP-8
+// approval server, see BuildSEP8RevisedTransaction.
+type SEP8RevisionParams struct {
+	// SourceAccount is the source account of the transaction submitted for
+	// approval, with its current sequence number.
+	SourceAccount Account
+	// Operation is the payment, or path payment, of the regulated asset.
+	Operation Operation
+	// Issuer is the address of the regulated asset issuer, which is the
+	// source of the authorization operations and must sign the revised
+	// transaction.
+	Issuer string
+	// Asset is the regulated asset moved by Operation.
+	Asset Asset
+	// Trustors are the accounts holding Asset during Operation, in the order
+	// they are authorized. If empty and Operation is a *Payment, its source
+	// and destination are used.
+	Trustors []string
+	// UseSetTrustLineFlags uses SetTrustLineFlags operations, available since
+	// protocol 17, instead of the deprecated AllowTrust operations.
+	UseSetTrustLineFlags bool
+	BaseFee              int64
+	Timebounds           Timebounds
+}
+
+// SEP8AuthorizationSandwich wraps operation with operations authorizing the
+// trustors to hold asset before it, and deauthorizing them after it, as
+// described in the SEP-8 Authorization flags section:
+// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0008.md#authorization-flags
+func SEP8AuthorizationSandwich(operation Operation, issuer string, asset Asset, trustors []string, useSetTrustLineFlags bool) []Operation {
+	authorization := func(trustor string, authorize bool) Operation {
+		if !useSetTrustLineFlags {
+			return &AllowTrust{
+				Trustor:       trustor,
+				Type:          asset,
+				Authorize:     authorize,
+				SourceAccount: issuer,
+			}
+		}
+		op := &SetTrustLineFlags{
+			Trustor:       trustor,
+			Asset:         asset,
+			SourceAccount: issuer,
+		}
+		if authorize {
+			op.SetFlags = []TrustLineFlag{TrustLineAuthorized}
+		} else {
+			op.ClearFlags = []TrustLineFlag{TrustLineAuthorized}
+		}
+		return op
+	}
+
+	operations := make([]Operation, 0, 2*len(trustors)+1)
+	for _, trustor := range trustors {
+		operations = append(operations, authorization(trustor, true))
+	}
+	operations = append(operations, operation)
+	for i := len(trustors) - 1; i >= 0; i-- {
+		operations = append(operations, authorization(trustors[i], false))
+	}
+	return operations
+}
+
+// BuildSEP8RevisedTransaction returns the transaction a SEP-8 approval server
+// sends back to the client for an approved payment: the payment wrapped with
+// the operations authorizing the trustors. For a payment between two accounts
+// it has five operations. The sequence number of the source account is
+// incremented, and the transaction still needs to be signed by the issuer.
+func BuildSEP8RevisedTransaction(params SEP8RevisionParams) (*Transaction, error) {
+	if params.SourceAccount == nil {
+		return nil, errors.New("source account cannot be nil")
+	}
+	if params.Operation == nil {
+		return nil, errors.New("operation cannot be nil")
+	}
+	if params.Asset == nil || params.Asset.IsNative() {
+		return nil, errors.New("asset must be a credit asset")
+	}
+	if params.Asset.GetIssuer() != params.Issuer {
+		return nil, errors.Errorf("asset issuer %s does not match issuer %s", params.Asset.GetIssuer(), params.Issuer)
+	}
+
+	trustors := params.Trustors
+	if len(trustors) == 0 {
+		payment, ok := params.Operation.(*Payment)
+		if !ok {
+			return nil, errors.New("trustors must be provided for operations other than payments")
+		}
+		source := payment.SourceAccount
+		if source == "" {
+			source = params.SourceAccount.GetAccountID()
+		}
+		trustors = []string{source, payment.Destination}
+	}
+
+	return NewTransaction(TransactionParams{
+		SourceAccount:        params.SourceAccount,
+		IncrementSequenceNum: true,
+		Operations:           SEP8AuthorizationSandwich(params.Operation, params.Issuer, params.Asset, trustors, params.UseSetTrustLineFlags),
+		BaseFee:              params.BaseFee,
+		Timebounds:           params.Timebounds,
+	})
+}
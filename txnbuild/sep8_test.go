@@ -0,0 +1,105 @@
+package txnbuild
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSEP8RevisedTransaction(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	kp2 := newKeypair2()
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(40385577484366))
+	asset := CreditAsset{"GOAT", kp2.Address()}
+	payment := &Payment{
+		Destination: kp1.Address(),
+		Amount:      "10",
+		Asset:       asset,
+	}
+
+	tx, err := BuildSEP8RevisedTransaction(SEP8RevisionParams{
+		SourceAccount: &sourceAccount,
+		Operation:     payment,
+		Issuer:        kp2.Address(),
+		Asset:         asset,
+		BaseFee:       MinBaseFee,
+		Timebounds:    NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(40385577484367), tx.SequenceNumber())
+	assert.Equal(t, []Operation{
+		&AllowTrust{Trustor: kp0.Address(), Type: asset, Authorize: true, SourceAccount: kp2.Address()},
+		&AllowTrust{Trustor: kp1.Address(), Type: asset, Authorize: true, SourceAccount: kp2.Address()},
+		payment,
+		&AllowTrust{Trustor: kp1.Address(), Type: asset, Authorize: false, SourceAccount: kp2.Address()},
+		&AllowTrust{Trustor: kp0.Address(), Type: asset, Authorize: false, SourceAccount: kp2.Address()},
+	}, tx.Operations())
+}
+
+func TestBuildSEP8RevisedTransactionSetTrustLineFlags(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	kp2 := newKeypair2()
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(40385577484366))
+	asset := CreditAsset{"GOAT", kp2.Address()}
+	pathPayment := &PathPaymentStrictSend{
+		SendAsset:   NativeAsset{},
+		SendAmount:  "10",
+		Destination: kp1.Address(),
+		DestAsset:   asset,
+		DestMin:     "1",
+	}
+
+	// Trustors are required for operations other than payments.
+	_, err := BuildSEP8RevisedTransaction(SEP8RevisionParams{
+		SourceAccount: &sourceAccount,
+		Operation:     pathPayment,
+		Issuer:        kp2.Address(),
+		Asset:         asset,
+		BaseFee:       MinBaseFee,
+		Timebounds:    NewInfiniteTimeout(),
+	})
+	assert.EqualError(t, err, "trustors must be provided for operations other than payments")
+
+	tx, err := BuildSEP8RevisedTransaction(SEP8RevisionParams{
+		SourceAccount:        &sourceAccount,
+		Operation:            pathPayment,
+		Issuer:               kp2.Address(),
+		Asset:                asset,
+		Trustors:             []string{kp1.Address()},
+		UseSetTrustLineFlags: true,
+		BaseFee:              MinBaseFee,
+		Timebounds:           NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Operation{
+		&SetTrustLineFlags{Trustor: kp1.Address(), Asset: asset, SetFlags: []TrustLineFlag{TrustLineAuthorized}, SourceAccount: kp2.Address()},
+		pathPayment,
+		&SetTrustLineFlags{Trustor: kp1.Address(), Asset: asset, ClearFlags: []TrustLineFlag{TrustLineAuthorized}, SourceAccount: kp2.Address()},
+	}, tx.Operations())
+}
+
+func TestBuildSEP8RevisedTransactionInvalidAsset(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(40385577484366))
+	payment := &Payment{Destination: kp1.Address(), Amount: "10", Asset: NativeAsset{}}
+
+	_, err := BuildSEP8RevisedTransaction(SEP8RevisionParams{
+		SourceAccount: &sourceAccount,
+		Operation:     payment,
+		Issuer:        kp1.Address(),
+		Asset:         NativeAsset{},
+	})
+	assert.EqualError(t, err, "asset must be a credit asset")
+
+	_, err = BuildSEP8RevisedTransaction(SEP8RevisionParams{
+		SourceAccount: &sourceAccount,
+		Operation:     payment,
+		Issuer:        kp1.Address(),
+		Asset:         CreditAsset{"GOAT", kp0.Address()},
+	})
+	assert.EqualError(t, err, "asset issuer "+kp0.Address()+" does not match issuer "+kp1.Address())
+}
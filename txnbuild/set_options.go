@@ -28,6 +28,8 @@ const AuthClawbackEnabled = AccountFlag(xdr.AccountFlagsAuthClawbackEnabledFlag)
 type Threshold uint8
 
 // Signer represents the Signer in a SetOptions operation.
+// Address is an ed25519 public key (G...), a pre-authorized transaction
+// hash (T...), a hash(x) (X...) or an ed25519 signed payload (P...).
 // If the signer already exists, it is updated.
 // If the weight is 0, the signer is deleted.
 type Signer struct {
@@ -11,6 +11,7 @@ package txnbuild
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
+	"github.com/stellar/go/network/hashid"
 	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
@@ -51,7 +53,7 @@ func concatSignatures(
 	e xdr.TransactionEnvelope,
 	networkStr string,
 	signatures []xdr.DecoratedSignature,
-	kps ...*keypair.Full,
+	signers ...keypair.Signer,
 ) ([]xdr.DecoratedSignature, error) {
 	// Hash the transaction
 	h, err := network.HashTransactionInEnvelope(e, networkStr)
@@ -62,12 +64,12 @@ func concatSignatures(
 	extended := make(
 		[]xdr.DecoratedSignature,
 		len(signatures),
-		len(signatures)+len(kps),
+		len(signatures)+len(signers),
 	)
 	copy(extended, signatures)
 	// Sign the hash
-	for _, kp := range kps {
-		sig, err := kp.SignDecorated(h[:])
+	for _, signer := range signers {
+		sig, err := signer.SignDecorated(h[:])
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to sign transaction")
 		}
@@ -83,6 +85,36 @@ func concatSignatureDecorated(e xdr.TransactionEnvelope, signatures []xdr.Decora
 	return extended, nil
 }
 
+func concatSignatureRaw(e xdr.TransactionEnvelope, signatures []xdr.DecoratedSignature, networkStr, publicKey string, signature []byte) ([]xdr.DecoratedSignature, error) {
+	kp, err := keypair.ParseAddress(publicKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the public key %s", publicKey)
+	}
+
+	if len(signature) != ed25519.SignatureSize {
+		return nil, errors.Errorf("signature must be %d bytes long, got %d", ed25519.SignatureSize, len(signature))
+	}
+
+	h, err := network.HashTransactionInEnvelope(e, networkStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash transaction")
+	}
+
+	err = kp.Verify(h[:], signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify the signature")
+	}
+
+	extended := make([]xdr.DecoratedSignature, len(signatures), len(signatures)+1)
+	copy(extended, signatures)
+	extended = append(extended, xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(kp.Hint()),
+		Signature: xdr.Signature(signature),
+	})
+
+	return extended, nil
+}
+
 func concatSignatureBase64(e xdr.TransactionEnvelope, signatures []xdr.DecoratedSignature, networkStr, publicKey, signature string) ([]xdr.DecoratedSignature, error) {
 	if signature == "" {
 		return nil, errors.New("signature not presented")
@@ -118,8 +150,8 @@ func concatSignatureBase64(e xdr.TransactionEnvelope, signatures []xdr.Decorated
 	return extended, nil
 }
 
-func stringsToKP(keys ...string) ([]*keypair.Full, error) {
-	var signers []*keypair.Full
+func stringsToKP(keys ...string) ([]keypair.Signer, error) {
+	var signers []keypair.Signer
 	for _, k := range keys {
 		kp, err := keypair.Parse(k)
 		if err != nil {
@@ -160,6 +192,29 @@ func concatHashX(signatures []xdr.DecoratedSignature, preimage []byte) ([]xdr.De
 	return append(extended, sig), nil
 }
 
+func concatSignedPayloads(signatures []xdr.DecoratedSignature, payload []byte, kps ...*keypair.Full) ([]xdr.DecoratedSignature, error) {
+	if len(payload) > strkey.MaxSignedPayloadLength {
+		return nil, errors.Errorf(
+			"payload cannot be more than %d bytes", strkey.MaxSignedPayloadLength,
+		)
+	}
+	extended := make(
+		[]xdr.DecoratedSignature,
+		len(signatures),
+		len(signatures)+len(kps),
+	)
+	copy(extended, signatures)
+
+	for _, kp := range kps {
+		sig, err := kp.SignPayloadDecorated(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to sign payload")
+		}
+		extended = append(extended, sig)
+	}
+	return extended, nil
+}
+
 func marshallBinary(e xdr.TransactionEnvelope, signatures []xdr.DecoratedSignature) ([]byte, error) {
 	switch e.Type {
 	case xdr.EnvelopeTypeEnvelopeTypeTx:
@@ -202,6 +257,9 @@ type Transaction struct {
 	operations    []Operation
 	memo          Memo
 	timebounds    Timebounds
+
+	enableMuxedAccounts           bool
+	preserveMuxedOpSourceAccounts bool
 }
 
 // BaseFee returns the per operation fee for this transaction.
@@ -246,6 +304,33 @@ func (t *Transaction) Signatures() []xdr.DecoratedSignature {
 	return t.envelope.Signatures()
 }
 
+// MuxedAccountsEnabled returns true if the transaction was built with
+// TransactionParams.EnableMuxedAccounts, or parsed with
+// TransactionFromXDROptionEnableMuxedAccounts, in which case its accounts
+// are M-addresses when they are muxed.
+func (t *Transaction) MuxedAccountsEnabled() bool {
+	return t.enableMuxedAccounts
+}
+
+// PreservesMuxedOpSourceAccounts returns true if the muxed source accounts
+// of the operations of this transaction are kept as M-addresses even when
+// muxed accounts are not enabled. See
+// TransactionFromXDROptionPreserveMuxedOpSourceAccounts.
+func (t *Transaction) PreservesMuxedOpSourceAccounts() bool {
+	return t.preserveMuxedOpSourceAccounts
+}
+
+// OperationSourceAccount returns the source account of the operation at the
+// given index exactly as it is encoded in the transaction envelope, and false
+// if the operation does not have a source account.
+func (t *Transaction) OperationSourceAccount(index int) (xdr.MuxedAccount, bool) {
+	operations := t.envelope.Operations()
+	if index < 0 || index >= len(operations) || operations[index].SourceAccount == nil {
+		return xdr.MuxedAccount{}, false
+	}
+	return *operations[index].SourceAccount, true
+}
+
 // Hash returns the network specific hash of this transaction
 // encoded as a byte array.
 func (t *Transaction) Hash(networkStr string) ([32]byte, error) {
@@ -280,9 +365,10 @@ func (t *Transaction) clone(signatures []xdr.DecoratedSignature) *Transaction {
 }
 
 // Sign returns a new Transaction instance which extends the current instance
-// with additional signatures derived from the given list of keypair instances.
-func (t *Transaction) Sign(network string, kps ...*keypair.Full) (*Transaction, error) {
-	extendedSignatures, err := concatSignatures(t.envelope, network, t.Signatures(), kps...)
+// with additional signatures derived from the given list of signers, e.g.
+// keypair instances or hardware wallets.
+func (t *Transaction) Sign(network string, signers ...keypair.Signer) (*Transaction, error) {
+	extendedSignatures, err := concatSignatures(t.envelope, network, t.Signatures(), signers...)
 	if err != nil {
 		return nil, err
 	}
@@ -312,6 +398,18 @@ func (t *Transaction) SignHashX(preimage []byte) (*Transaction, error) {
 	return t.clone(extendedSignatures), nil
 }
 
+// SignPayload returns a new Transaction instance which extends the current instance
+// with signatures of the given payload by the given keypairs, as expected by
+// ed25519 signed payload signers (see strkey.SignedPayload) of these keypairs and payload.
+func (t *Transaction) SignPayload(payload []byte, kps ...*keypair.Full) (*Transaction, error) {
+	extendedSignatures, err := concatSignedPayloads(t.Signatures(), payload, kps...)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.clone(extendedSignatures), nil
+}
+
 // AddSignatureDecorated returns a new Transaction instance which extends the current instance
 // with an additional decorated signature(s).
 func (t *Transaction) AddSignatureDecorated(signature ...xdr.DecoratedSignature) (*Transaction, error) {
@@ -323,6 +421,27 @@ func (t *Transaction) AddSignatureDecorated(signature ...xdr.DecoratedSignature)
 	return t.clone(extendedSignatures), nil
 }
 
+// SignaturePayload returns the network specific hash of this transaction which
+// signers sign. It can be shipped to an air-gapped device to be signed there
+// without the transaction, and the resulting signature attached with
+// AttachSignature.
+func (t *Transaction) SignaturePayload(passphrase string) ([32]byte, error) {
+	return t.Hash(passphrase)
+}
+
+// AttachSignature returns a new Transaction instance which extends the current instance
+// with the raw ed25519 signature of the SignaturePayload made by publicKey. An
+// error is returned if the signature is not the signature of the payload for
+// the given network passphrase by publicKey.
+func (t *Transaction) AttachSignature(passphrase, publicKey string, signature []byte) (*Transaction, error) {
+	extendedSignatures, err := concatSignatureRaw(t.envelope, t.Signatures(), passphrase, publicKey, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.clone(extendedSignatures), nil
+}
+
 // AddSignatureBase64 returns a new Transaction instance which extends the current instance
 // with an additional signature derived from the given base64-encoded signature.
 func (t *Transaction) AddSignatureBase64(network, publicKey, signature string) (*Transaction, error) {
@@ -362,34 +481,10 @@ func (t *Transaction) ClaimableBalanceID(operationIndex int) (string, error) {
 		return "", errors.New("operation is not CreateClaimableBalance")
 	}
 
-	// We mimic the relevant code from Stellar Core
-	// https://github.com/stellar/stellar-core/blob/9f3cc04e6ec02c38974c42545a86cdc79809252b/src/test/TestAccount.cpp#L285
-	operationId := xdr.OperationId{
-		Type: xdr.EnvelopeTypeEnvelopeTypeOpId,
-		Id: &xdr.OperationIdId{
-			SourceAccount: xdr.MustMuxedAddress(t.sourceAccount.AccountID),
-			SeqNum:        xdr.SequenceNumber(t.sourceAccount.Sequence),
-			OpNum:         xdr.Uint32(operationIndex),
-		},
-	}
-
-	binaryDump, err := operationId.MarshalBinary()
+	source := xdr.MustMuxedAddress(t.sourceAccount.AccountID).ToAccountId()
+	balanceIdHex, err := hashid.ClaimableBalanceIDHex(source, t.sourceAccount.Sequence, uint32(operationIndex))
 	if err != nil {
-		return "", errors.Wrap(err, "invalid claimable balance operation")
-	}
-
-	hash := sha256.Sum256(binaryDump)
-	balanceIdXdr, err := xdr.NewClaimableBalanceId(
-		// TODO: look into whether this be determined programmatically from the operation structure.
-		xdr.ClaimableBalanceIdTypeClaimableBalanceIdTypeV0,
-		xdr.Hash(hash))
-	if err != nil {
-		return "", errors.Wrap(err, "unable to parse balance ID as XDR")
-	}
-
-	balanceIdHex, err := xdr.MarshalHex(balanceIdXdr)
-	if err != nil {
-		return "", errors.Wrap(err, "unable to encode balance ID as hex")
+		return "", errors.Wrap(err, "unable to compute balance ID")
 	}
 
 	return balanceIdHex, nil
@@ -448,9 +543,10 @@ func (t *FeeBumpTransaction) clone(signatures []xdr.DecoratedSignature) *FeeBump
 }
 
 // Sign returns a new FeeBumpTransaction instance which extends the current instance
-// with additional signatures derived from the given list of keypair instances.
-func (t *FeeBumpTransaction) Sign(network string, kps ...*keypair.Full) (*FeeBumpTransaction, error) {
-	extendedSignatures, err := concatSignatures(t.envelope, network, t.Signatures(), kps...)
+// with additional signatures derived from the given list of signers, e.g.
+// keypair instances or hardware wallets.
+func (t *FeeBumpTransaction) Sign(network string, signers ...keypair.Signer) (*FeeBumpTransaction, error) {
+	extendedSignatures, err := concatSignatures(t.envelope, network, t.Signatures(), signers...)
 	if err != nil {
 		return nil, err
 	}
@@ -480,6 +576,18 @@ func (t *FeeBumpTransaction) SignHashX(preimage []byte) (*FeeBumpTransaction, er
 	return t.clone(extendedSignatures), nil
 }
 
+// SignPayload returns a new FeeBumpTransaction instance which extends the current instance
+// with signatures of the given payload by the given keypairs, as expected by
+// ed25519 signed payload signers (see strkey.SignedPayload) of these keypairs and payload.
+func (t *FeeBumpTransaction) SignPayload(payload []byte, kps ...*keypair.Full) (*FeeBumpTransaction, error) {
+	extendedSignatures, err := concatSignedPayloads(t.Signatures(), payload, kps...)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.clone(extendedSignatures), nil
+}
+
 // AddSignatureBase64 returns a new FeeBumpTransaction instance which extends the current instance
 // with an additional signature derived from the given base64-encoded signature.
 func (t *FeeBumpTransaction) AddSignatureBase64(network, publicKey, signature string) (*FeeBumpTransaction, error) {
@@ -541,17 +649,35 @@ type TransactionFromXDROption int
 
 const (
 	TransactionFromXDROptionEnableMuxedAccounts TransactionFromXDROption = iota
+	// TransactionFromXDROptionStrict makes TransactionFromXDR reject envelopes
+	// which would not be reproduced exactly when rebuilt from the parsed
+	// transaction, e.g. because they contain muxed accounts while muxed
+	// accounts are not enabled or operation fields which txnbuild cannot
+	// represent. Unknown extensions and operation types are always rejected
+	// when unmarshaling the envelope.
+	TransactionFromXDROptionStrict
+	// TransactionFromXDROptionPreserveMuxedOpSourceAccounts keeps the muxed
+	// source accounts of operations as M-addresses when muxed accounts are not
+	// enabled, instead of normalizing them to the underlying G-addresses. The
+	// parsed transaction reports it in PreservesMuxedOpSourceAccounts, which
+	// should be passed on as TransactionParams.PreserveMuxedOpSourceAccounts
+	// when rebuilding it so that the operations are encoded identically.
+	TransactionFromXDROptionPreserveMuxedOpSourceAccounts
 )
 
-func areMuxedAccountsEnabled(options []TransactionFromXDROption) bool {
+func hasTransactionFromXDROption(options []TransactionFromXDROption, option TransactionFromXDROption) bool {
 	for _, opt := range options {
-		if opt == TransactionFromXDROptionEnableMuxedAccounts {
+		if opt == option {
 			return true
 		}
 	}
 	return false
 }
 
+func areMuxedAccountsEnabled(options []TransactionFromXDROption) bool {
+	return hasTransactionFromXDROption(options, TransactionFromXDROptionEnableMuxedAccounts)
+}
+
 // TransactionFromXDR parses the supplied transaction envelope in base64 XDR
 // and returns a GenericTransaction instance.
 func TransactionFromXDR(txeB64 string, options ...TransactionFromXDROption) (*GenericTransaction, error) {
@@ -560,10 +686,205 @@ func TransactionFromXDR(txeB64 string, options ...TransactionFromXDROption) (*Ge
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to unmarshal transaction envelope")
 	}
-	return transactionFromParsedXDR(xdrEnv, areMuxedAccountsEnabled(options))
+	withMuxedAccounts := areMuxedAccountsEnabled(options)
+	preserveMuxedOpSourceAccounts := hasTransactionFromXDROption(options, TransactionFromXDROptionPreserveMuxedOpSourceAccounts)
+	tx, err := transactionFromParsedXDR(xdrEnv, withMuxedAccounts, preserveMuxedOpSourceAccounts)
+	if err != nil {
+		return nil, err
+	}
+	if hasTransactionFromXDROption(options, TransactionFromXDROptionStrict) {
+		if err = checkStrictTransaction(xdrEnv, tx, withMuxedAccounts, preserveMuxedOpSourceAccounts); err != nil {
+			return nil, errors.Wrap(err, "transaction envelope cannot be parsed strictly")
+		}
+	}
+	return tx, nil
+}
+
+// checkStrictTransaction returns an error if rebuilding the parsed
+// transaction does not produce exactly the original envelope.
+func checkStrictTransaction(xdrEnv xdr.TransactionEnvelope, tx *GenericTransaction, withMuxedAccounts, preserveMuxedOpSourceAccounts bool) error {
+	if err := checkMuxedAccounts(xdrEnv, withMuxedAccounts, preserveMuxedOpSourceAccounts); err != nil {
+		return err
+	}
+
+	var rebuilt xdr.TransactionEnvelope
+	var err error
+	if feeBump, ok := tx.FeeBump(); ok {
+		rebuilt, err = rebuildFeeBumpEnvelope(feeBump, withMuxedAccounts)
+	} else {
+		rebuilt, err = rebuildTransactionEnvelope(tx.simple, xdrEnv.Type, withMuxedAccounts)
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to rebuild transaction envelope")
+	}
+
+	original, err := xdrEnv.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal transaction envelope")
+	}
+	rebuiltBytes, err := rebuilt.MarshalBinary()
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal rebuilt transaction envelope")
+	}
+	if bytes.Equal(original, rebuiltBytes) {
+		return nil
+	}
+
+	// Point at the offending operation, if any, to ease debugging.
+	rebuiltOps := rebuilt.Operations()
+	for i, xdrOp := range xdrEnv.Operations() {
+		originalOp, err := xdrOp.MarshalBinary()
+		if err != nil {
+			return errors.Wrapf(err, "unable to marshal operation at index %d", i)
+		}
+		rebuiltOp, err := rebuiltOps[i].MarshalBinary()
+		if err != nil {
+			return errors.Wrapf(err, "unable to marshal rebuilt operation at index %d", i)
+		}
+		if !bytes.Equal(originalOp, rebuiltOp) {
+			return fmt.Errorf(
+				"%s operation at index %d contains fields which are not supported",
+				xdrOp.Body.Type, i,
+			)
+		}
+	}
+	return errors.New("transaction contains fields which are not supported")
+}
+
+// checkMuxedAccounts returns an error if the envelope contains muxed accounts
+// which would be converted into G-addresses because muxed accounts are not
+// enabled. Operation source accounts are allowed to be muxed if they are
+// preserved.
+func checkMuxedAccounts(xdrEnv xdr.TransactionEnvelope, withMuxedAccounts, preserveMuxedOpSourceAccounts bool) error {
+	if withMuxedAccounts {
+		return nil
+	}
+	if xdrEnv.IsFeeBump() && xdrEnv.FeeBumpAccount().Type != xdr.CryptoKeyTypeKeyTypeEd25519 {
+		return errors.New("fee bump account is a muxed account but muxed accounts are not enabled")
+	}
+	if xdrEnv.SourceAccount().Type != xdr.CryptoKeyTypeKeyTypeEd25519 {
+		return errors.New("source account is a muxed account but muxed accounts are not enabled")
+	}
+	if preserveMuxedOpSourceAccounts {
+		return nil
+	}
+	for i, op := range xdrEnv.Operations() {
+		if op.SourceAccount != nil && op.SourceAccount.Type != xdr.CryptoKeyTypeKeyTypeEd25519 {
+			return fmt.Errorf(
+				"source account of %s operation at index %d is a muxed account but muxed accounts are not enabled",
+				op.Body.Type, i,
+			)
+		}
+	}
+	return nil
+}
+
+// rebuildTransactionEnvelope builds an envelope of the given type from the
+// fields of tx, keeping its signatures.
+func rebuildTransactionEnvelope(tx *Transaction, envelopeType xdr.EnvelopeType, withMuxedAccounts bool) (xdr.TransactionEnvelope, error) {
+	var sourceAccount xdr.MuxedAccount
+	if withMuxedAccounts {
+		if err := sourceAccount.SetAddress(tx.sourceAccount.AccountID); err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "account id is not valid")
+		}
+	} else {
+		accountID, err := xdr.AddressToAccountId(tx.sourceAccount.AccountID)
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "account id is not valid")
+		}
+		sourceAccount = accountID.ToMuxedAccount()
+	}
+
+	xdrTx := xdr.Transaction{
+		SourceAccount: sourceAccount,
+		Fee:           xdr.Uint32(tx.maxFee),
+		SeqNum:        xdr.SequenceNumber(tx.sourceAccount.Sequence),
+	}
+	if tx.timebounds.wasBuilt {
+		xdrTx.TimeBounds = &xdr.TimeBounds{
+			MinTime: xdr.TimePoint(tx.timebounds.MinTime),
+			MaxTime: xdr.TimePoint(tx.timebounds.MaxTime),
+		}
+	}
+	if tx.memo != nil {
+		xdrMemo, err := tx.memo.ToXDR()
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "couldn't build memo XDR")
+		}
+		xdrTx.Memo = xdrMemo
+	}
+	for _, op := range tx.operations {
+		xdrOperation, err := buildOperationXDR(op, withMuxedAccounts, tx.preserveMuxedOpSourceAccounts)
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, fmt.Sprintf("failed to build operation %T", op))
+		}
+		xdrTx.Operations = append(xdrTx.Operations, xdrOperation)
+	}
+
+	signatures := tx.Signatures()
+	if envelopeType == xdr.EnvelopeTypeEnvelopeTypeTxV0 {
+		return xdr.TransactionEnvelope{
+			Type: xdr.EnvelopeTypeEnvelopeTypeTxV0,
+			V0: &xdr.TransactionV0Envelope{
+				Tx: xdr.TransactionV0{
+					SourceAccountEd25519: *sourceAccount.Ed25519,
+					Fee:                  xdrTx.Fee,
+					SeqNum:               xdrTx.SeqNum,
+					TimeBounds:           xdrTx.TimeBounds,
+					Memo:                 xdrTx.Memo,
+					Operations:           xdrTx.Operations,
+				},
+				Signatures: signatures,
+			},
+		}, nil
+	}
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx:         xdrTx,
+			Signatures: signatures,
+		},
+	}, nil
+}
+
+// rebuildFeeBumpEnvelope builds a fee bump envelope from the fields of tx,
+// keeping its signatures and the signatures of its inner transaction.
+func rebuildFeeBumpEnvelope(tx *FeeBumpTransaction, withMuxedAccounts bool) (xdr.TransactionEnvelope, error) {
+	innerEnv, err := rebuildTransactionEnvelope(tx.inner, xdr.EnvelopeTypeEnvelopeTypeTx, withMuxedAccounts)
+	if err != nil {
+		return xdr.TransactionEnvelope{}, errors.Wrap(err, "could not rebuild inner transaction")
+	}
+
+	var feeSource xdr.MuxedAccount
+	if withMuxedAccounts {
+		if err = feeSource.SetAddress(tx.feeAccount); err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "fee account is not a valid address")
+		}
+	} else {
+		accountID, err := xdr.AddressToAccountId(tx.feeAccount)
+		if err != nil {
+			return xdr.TransactionEnvelope{}, errors.Wrap(err, "fee account is not a valid address")
+		}
+		feeSource = accountID.ToMuxedAccount()
+	}
+
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTxFeeBump,
+		FeeBump: &xdr.FeeBumpTransactionEnvelope{
+			Tx: xdr.FeeBumpTransaction{
+				FeeSource: feeSource,
+				Fee:       xdr.Int64(tx.maxFee),
+				InnerTx: xdr.FeeBumpTransactionInnerTx{
+					Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+					V1:   innerEnv.V1,
+				},
+			},
+			Signatures: tx.Signatures(),
+		},
+	}, nil
 }
 
-func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope, withMuxedAccounts bool) (*GenericTransaction, error) {
+func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope, withMuxedAccounts, preserveMuxedOpSourceAccounts bool) (*GenericTransaction, error) {
 	var err error
 	newTx := &GenericTransaction{}
 
@@ -572,7 +893,7 @@ func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope, withMuxedAccounts
 		innerTx, err = transactionFromParsedXDR(xdr.TransactionEnvelope{
 			Type: xdr.EnvelopeTypeEnvelopeTypeTx,
 			V1:   xdrEnv.FeeBump.Tx.InnerTx.V1,
-		}, withMuxedAccounts)
+		}, withMuxedAccounts, preserveMuxedOpSourceAccounts)
 		if err != nil {
 			return newTx, errors.New("could not parse inner transaction")
 		}
@@ -625,6 +946,9 @@ func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope, withMuxedAccounts
 		operations: nil,
 		memo:       nil,
 		timebounds: Timebounds{},
+
+		enableMuxedAccounts:           withMuxedAccounts,
+		preserveMuxedOpSourceAccounts: preserveMuxedOpSourceAccounts,
 	}
 
 	if timeBounds := xdrEnv.TimeBounds(); timeBounds != nil {
@@ -642,6 +966,9 @@ func transactionFromParsedXDR(xdrEnv xdr.TransactionEnvelope, withMuxedAccounts
 		if err != nil {
 			return nil, err
 		}
+		if preserveMuxedOpSourceAccounts && op.SourceAccount != nil {
+			setOperationSourceAccount(newOp, op.SourceAccount.Address())
+		}
 		newTx.simple.operations = append(newTx.simple.operations, newOp)
 	}
 
@@ -658,10 +985,41 @@ type TransactionParams struct {
 	Memo                 Memo
 	Timebounds           Timebounds
 	EnableMuxedAccounts  bool
+	// PreserveMuxedOpSourceAccounts allows operation source accounts to be
+	// M-addresses, encoded as muxed accounts, even when EnableMuxedAccounts
+	// is false.
+	PreserveMuxedOpSourceAccounts bool
+	// FeeSource resolves BaseFee when it is a DynamicFee.
+	FeeSource FeeSource
 }
 
 // NewTransaction returns a new Transaction instance
 func NewTransaction(params TransactionParams) (*Transaction, error) {
+	return newTransaction(params, func(i int, op Operation) (xdr.Operation, error) {
+		return validateAndBuildOperationXDR(op, params)
+	})
+}
+
+// validateAndBuildOperationXDR validates op and builds its XDR according to
+// the muxed accounts settings of params.
+func validateAndBuildOperationXDR(op Operation, params TransactionParams) (xdr.Operation, error) {
+	if verr := op.Validate(params.EnableMuxedAccounts); verr != nil {
+		return xdr.Operation{}, errors.Wrap(verr, fmt.Sprintf("validation failed for %T operation", op))
+	}
+	withMuxedSourceAccount := params.EnableMuxedAccounts || params.PreserveMuxedOpSourceAccounts
+	if verr := validateOperationSourceAccount(op, withMuxedSourceAccount); verr != nil {
+		return xdr.Operation{}, errors.Wrap(verr, fmt.Sprintf("validation failed for %T operation", op))
+	}
+	xdrOperation, err := buildOperationXDR(op, params.EnableMuxedAccounts, params.PreserveMuxedOpSourceAccounts)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, fmt.Sprintf("failed to build operation %T", op))
+	}
+	return xdrOperation, nil
+}
+
+// newTransaction returns a new Transaction instance whose operations are
+// validated and built by buildOperation.
+func newTransaction(params TransactionParams, buildOperation func(i int, op Operation) (xdr.Operation, error)) (*Transaction, error) {
 	var sequence int64
 	var err error
 
@@ -669,6 +1027,11 @@ func NewTransaction(params TransactionParams) (*Transaction, error) {
 		return nil, errors.New("transaction has no source account")
 	}
 
+	params.BaseFee, err = resolveBaseFee(params.BaseFee, params.FeeSource)
+	if err != nil {
+		return nil, err
+	}
+
 	if params.IncrementSequenceNum {
 		sequence, err = params.SourceAccount.IncrementSequenceNumber()
 	} else {
@@ -687,6 +1050,9 @@ func NewTransaction(params TransactionParams) (*Transaction, error) {
 		operations: params.Operations,
 		memo:       params.Memo,
 		timebounds: params.Timebounds,
+
+		enableMuxedAccounts:           params.EnableMuxedAccounts,
+		preserveMuxedOpSourceAccounts: params.PreserveMuxedOpSourceAccounts,
 	}
 	var sourceAccount xdr.MuxedAccount
 	if params.EnableMuxedAccounts {
@@ -749,13 +1115,10 @@ func NewTransaction(params TransactionParams) (*Transaction, error) {
 		envelope.V1.Tx.Memo = xdrMemo
 	}
 
-	for _, op := range tx.operations {
-		if verr := op.Validate(params.EnableMuxedAccounts); verr != nil {
-			return nil, errors.Wrap(verr, fmt.Sprintf("validation failed for %T operation", op))
-		}
-		xdrOperation, err2 := op.BuildXDR(params.EnableMuxedAccounts)
+	for i, op := range tx.operations {
+		xdrOperation, err2 := buildOperation(i, op)
 		if err2 != nil {
-			return nil, errors.Wrap(err2, fmt.Sprintf("failed to build operation %T", op))
+			return nil, err2
 		}
 		envelope.V1.Tx.Operations = append(envelope.V1.Tx.Operations, xdrOperation)
 	}
@@ -771,6 +1134,8 @@ type FeeBumpTransactionParams struct {
 	FeeAccount          string
 	BaseFee             int64
 	EnableMuxedAccounts bool
+	// FeeSource resolves BaseFee when it is a DynamicFee.
+	FeeSource FeeSource
 }
 
 func convertToV1(tx *Transaction) (*Transaction, error) {
@@ -783,6 +1148,9 @@ func convertToV1(tx *Transaction) (*Transaction, error) {
 		BaseFee:              tx.BaseFee(),
 		Memo:                 tx.Memo(),
 		Timebounds:           tx.Timebounds(),
+		EnableMuxedAccounts:  tx.MuxedAccountsEnabled(),
+
+		PreserveMuxedOpSourceAccounts: tx.PreservesMuxedOpSourceAccounts(),
 	})
 	if err != nil {
 		return tx, err
@@ -797,6 +1165,13 @@ func NewFeeBumpTransaction(params FeeBumpTransactionParams) (*FeeBumpTransaction
 	if inner == nil {
 		return nil, errors.New("inner transaction is missing")
 	}
+
+	var err error
+	params.BaseFee, err = resolveBaseFee(params.BaseFee, params.FeeSource)
+	if err != nil {
+		return nil, err
+	}
+
 	switch inner.envelope.Type {
 	case xdr.EnvelopeTypeEnvelopeTypeTx, xdr.EnvelopeTypeEnvelopeTypeTxV0:
 	default:
@@ -805,7 +1180,6 @@ func NewFeeBumpTransaction(params FeeBumpTransactionParams) (*FeeBumpTransaction
 
 	innerEnv := inner.ToXDR()
 	if innerEnv.Type == xdr.EnvelopeTypeEnvelopeTypeTxV0 {
-		var err error
 		inner, err = convertToV1(inner)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not upgrade transaction from v0 to v1")
@@ -872,8 +1246,38 @@ func NewFeeBumpTransaction(params FeeBumpTransactionParams) (*FeeBumpTransaction
 
 // BuildChallengeTx is a factory method that creates a valid SEP 10 challenge, for use in web authentication.
 // "timebound" is the time duration the transaction should be valid for, and must be greater than 1s (300s is recommended).
+// "clientAccountID" can be a muxed account (M...), to authenticate a user of a custodial account.
 // More details on SEP 10: https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0010.md
 func BuildChallengeTx(serverSignerSecret, clientAccountID, webAuthDomain, homeDomain, network string, timebound time.Duration) (*Transaction, error) {
+	return buildChallengeTx(serverSignerSecret, clientAccountID, webAuthDomain, homeDomain, "", "", network, timebound, nil)
+}
+
+// BuildChallengeTxWithMemo creates a SEP 10 challenge like BuildChallengeTx,
+// whose ID memo identifies the user of a custodial account among the users
+// sharing "clientAccountID". The memo can be retrieved from the challenge with
+// ChallengeTxMemo. "clientAccountID" cannot be a muxed account, which already
+// identifies the user.
+func BuildChallengeTxWithMemo(serverSignerSecret, clientAccountID, webAuthDomain, homeDomain, network string, timebound time.Duration, memo MemoID) (*Transaction, error) {
+	return buildChallengeTx(serverSignerSecret, clientAccountID, webAuthDomain, homeDomain, "", "", network, timebound, &memo)
+}
+
+// BuildChallengeTxWithClientDomain creates a SEP 10 challenge like
+// BuildChallengeTx, which also attributes the client to "clientDomain" with a
+// client_domain Manage Data operation. The source account of the operation is
+// "clientDomainAccountID", which must be the SIGNING_KEY of the stellar.toml
+// hosted on clientDomain, so that the challenge must also be signed by the
+// client domain to be verified.
+func BuildChallengeTxWithClientDomain(serverSignerSecret, clientAccountID, webAuthDomain, homeDomain, clientDomain, clientDomainAccountID, network string, timebound time.Duration) (*Transaction, error) {
+	if clientDomain == "" {
+		return nil, errors.New("client domain cannot be empty")
+	}
+	if _, err := xdr.AddressToAccountId(clientDomainAccountID); err != nil {
+		return nil, errors.Wrapf(err, "%s is not a valid account id", clientDomainAccountID)
+	}
+	return buildChallengeTx(serverSignerSecret, clientAccountID, webAuthDomain, homeDomain, clientDomain, clientDomainAccountID, network, timebound, nil)
+}
+
+func buildChallengeTx(serverSignerSecret, clientAccountID, webAuthDomain, homeDomain, clientDomain, clientDomainAccountID, network string, timebound time.Duration, memo *MemoID) (*Transaction, error) {
 	if timebound < time.Second {
 		return nil, errors.New("provided timebound must be at least 1s (300s is recommended)")
 	}
@@ -894,9 +1298,14 @@ func BuildChallengeTx(serverSignerSecret, clientAccountID, webAuthDomain, homeDo
 		return nil, errors.New("64 byte long random nonce required")
 	}
 
-	if _, err = xdr.AddressToAccountId(clientAccountID); err != nil {
+	clientAccount, err := xdr.AddressToMuxedAccount(clientAccountID)
+	if err != nil {
 		return nil, errors.Wrapf(err, "%s is not a valid account id", clientAccountID)
 	}
+	isMuxedClient := clientAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519
+	if isMuxedClient && memo != nil {
+		return nil, errors.New("memos are not valid for challenge transactions with a muxed client account")
+	}
 
 	// represent server signing account as SimpleAccount
 	sa := SimpleAccount{
@@ -907,29 +1316,41 @@ func BuildChallengeTx(serverSignerSecret, clientAccountID, webAuthDomain, homeDo
 	currentTime := time.Now().UTC()
 	maxTime := currentTime.Add(timebound)
 
+	operations := []Operation{
+		&ManageData{
+			SourceAccount: clientAccountID,
+			Name:          homeDomain + " auth",
+			Value:         []byte(randomNonceToString),
+		},
+		&ManageData{
+			SourceAccount: serverKP.Address(),
+			Name:          "web_auth_domain",
+			Value:         []byte(webAuthDomain),
+		},
+	}
+	if clientDomain != "" {
+		operations = append(operations, &ManageData{
+			SourceAccount: clientDomainAccountID,
+			Name:          "client_domain",
+			Value:         []byte(clientDomain),
+		})
+	}
+
 	// Create a SEP 10 compatible response. See
 	// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0010.md#response
-	tx, err := NewTransaction(
-		TransactionParams{
-			SourceAccount:        &sa,
-			IncrementSequenceNum: false,
-			Operations: []Operation{
-				&ManageData{
-					SourceAccount: clientAccountID,
-					Name:          homeDomain + " auth",
-					Value:         []byte(randomNonceToString),
-				},
-				&ManageData{
-					SourceAccount: serverKP.Address(),
-					Name:          "web_auth_domain",
-					Value:         []byte(webAuthDomain),
-				},
-			},
-			BaseFee:    MinBaseFee,
-			Memo:       nil,
-			Timebounds: NewTimebounds(currentTime.Unix(), maxTime.Unix()),
-		},
-	)
+	params := TransactionParams{
+		SourceAccount:        &sa,
+		IncrementSequenceNum: false,
+		Operations:           operations,
+		BaseFee:              MinBaseFee,
+		Memo:                 nil,
+		Timebounds:           NewTimebounds(currentTime.Unix(), maxTime.Unix()),
+		EnableMuxedAccounts:  isMuxedClient,
+	}
+	if memo != nil {
+		params.Memo = *memo
+	}
+	tx, err := NewTransaction(params)
 	if err != nil {
 		return nil, err
 	}
@@ -972,6 +1393,17 @@ func generateRandomNonce(n int) ([]byte, error) {
 // web_auth_domain the value will be checked to match the webAuthDomain
 // provided. If it does not match the function will return an error.
 //
+// If the challenge contains a subsequent Manage Data operation with key
+// client_domain its source account is expected to be the signing key of the
+// client domain in its value, which can be retrieved with
+// ChallengeTxClientDomain.
+//
+// The client account ID returned is a muxed account (M...) if the challenge
+// authenticates a user of a custodial account with a muxed account. The
+// challenge can otherwise have an ID memo identifying the user, which can be
+// retrieved with ChallengeTxMemo. Memos of other types, and memos of
+// challenges with a muxed client account, are invalid.
+//
 // It does not verify that the transaction has been signed by the client or
 // that any signatures other than the servers on the transaction are valid. Use
 // one of the following functions to completely verify the transaction:
@@ -1039,9 +1471,25 @@ func ReadChallengeTx(challengeTx, serverAccountID, network, webAuthDomain string
 
 	clientAccountID = op.SourceAccount
 	rawOperations := tx.envelope.Operations()
-	if len(rawOperations) > 0 && rawOperations[0].SourceAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
-		err = errors.New("invalid operation source account: only valid Ed25519 accounts are allowed in challenge transactions")
-		return tx, clientAccountID, matchedHomeDomain, err
+	// The source account of the operation is parsed as the underlying G...
+	// address of a muxed account.
+	isMuxedClient := rawOperations[0].SourceAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519
+	if isMuxedClient {
+		clientAccountID, err = rawOperations[0].SourceAccount.GetAddress()
+		if err != nil {
+			return tx, clientAccountID, matchedHomeDomain, errors.Wrap(err, "invalid operation source account")
+		}
+	}
+
+	// verify memo
+	switch tx.Memo().(type) {
+	case nil:
+	case MemoID:
+		if isMuxedClient {
+			return tx, clientAccountID, matchedHomeDomain, errors.New("memos are not valid for challenge transactions with a muxed client account")
+		}
+	default:
+		return tx, clientAccountID, matchedHomeDomain, errors.New("invalid memo, only ID memos are permitted")
 	}
 
 	// verify manage data value
@@ -1058,7 +1506,8 @@ func ReadChallengeTx(challengeTx, serverAccountID, network, webAuthDomain string
 	}
 
 	// verify subsequent operations are manage data ops and known, or unknown with source account set to server account
-	for _, op := range operations[1:] {
+	clientDomainFound := false
+	for i, op := range operations[1:] {
 		op, ok := op.(*ManageData)
 		if !ok {
 			return tx, clientAccountID, matchedHomeDomain, errors.New("operation type should be manage_data")
@@ -1067,6 +1516,18 @@ func ReadChallengeTx(challengeTx, serverAccountID, network, webAuthDomain string
 			return tx, clientAccountID, matchedHomeDomain, errors.New("operation should have a source account")
 		}
 		switch op.Name {
+		case "client_domain":
+			if clientDomainFound {
+				return tx, clientAccountID, matchedHomeDomain, errors.New("transaction has more than one client domain operation")
+			}
+			clientDomainFound = true
+			if rawOperations[i+1].SourceAccount.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+				err = errors.New("invalid client domain operation source account: only valid Ed25519 accounts are allowed in challenge transactions")
+				return tx, clientAccountID, matchedHomeDomain, err
+			}
+			if len(op.Value) == 0 {
+				return tx, clientAccountID, matchedHomeDomain, errors.New("client domain operation value cannot be empty")
+			}
 		case "web_auth_domain":
 			if op.SourceAccount != serverAccountID {
 				return tx, clientAccountID, matchedHomeDomain, errors.New("web auth domain operation must have server source account")
@@ -1090,6 +1551,38 @@ func ReadChallengeTx(challengeTx, serverAccountID, network, webAuthDomain string
 	return tx, clientAccountID, matchedHomeDomain, nil
 }
 
+// ChallengeTxClientDomain returns the client domain of a SEP 10 challenge
+// transaction, and the account ID of the client domain's signing key, from
+// its client_domain Manage Data operation. The strings returned are empty if
+// the challenge has no client_domain operation.
+//
+// The challenge should be read with ReadChallengeTx beforehand, to verify
+// that it is valid and signed by the server.
+func ChallengeTxClientDomain(tx *Transaction) (clientDomain string, clientDomainAccountID string) {
+	for _, op := range tx.Operations() {
+		op, ok := op.(*ManageData)
+		if !ok || op.Name != "client_domain" {
+			continue
+		}
+		return string(op.Value), op.SourceAccount
+	}
+	return "", ""
+}
+
+// ChallengeTxMemo returns the ID memo of a SEP 10 challenge transaction,
+// which identifies the user of a custodial account among the users sharing
+// the client account, or nil if the challenge has no memo.
+//
+// The challenge should be read with ReadChallengeTx beforehand, to verify
+// that its memo is valid.
+func ChallengeTxMemo(tx *Transaction) *MemoID {
+	memo, ok := tx.Memo().(MemoID)
+	if !ok {
+		return nil
+	}
+	return &memo
+}
+
 // VerifyChallengeTxThreshold verifies that for a SEP 10 challenge transaction
 // all signatures on the transaction are accounted for and that the signatures
 // meet a threshold on an account. A transaction is verified if it is signed by
@@ -1106,6 +1599,11 @@ func ReadChallengeTx(challengeTx, serverAccountID, network, webAuthDomain string
 // web_auth_domain the value will be checked to match the webAuthDomain
 // provided. If it does not match the function will return an error.
 //
+// If the challenge contains a subsequent Manage Data operation with key
+// client_domain the transaction must also be signed by the signing key of
+// the client domain, as verified by VerifyChallengeTxSigners. Its signature
+// does not count towards the threshold.
+//
 // Errors will be raised if:
 //  - The transaction is invalid according to ReadChallengeTx.
 //  - No client signatures are found on the transaction.
@@ -1152,11 +1650,20 @@ func VerifyChallengeTxThreshold(challengeTx, serverAccountID, network, webAuthDo
 // web_auth_domain the value will be checked to match the webAuthDomain
 // provided. If it does not match the function will return an error.
 //
+// If the challenge contains a subsequent Manage Data operation with key
+// client_domain the transaction must also be signed by its source account,
+// the signing key of the client domain. That signature attributes the
+// client to the client domain, and is not returned in the list of signers
+// found.
+//
 // Errors will be raised if:
 //  - The transaction is invalid according to ReadChallengeTx.
 //  - No client signatures are found on the transaction.
+//  - The transaction has a client_domain operation but is not signed by its
+//    source account.
 //  - One or more signatures in the transaction are not identifiable as the
-//    server account or one of the signers provided in the arguments.
+//    server account, the client domain signing key or one of the signers
+//    provided in the arguments.
 func VerifyChallengeTxSigners(challengeTx, serverAccountID, network, webAuthDomain string, homeDomains []string, signers ...string) ([]string, error) {
 	// Read the transaction which validates its structure.
 	tx, _, _, err := ReadChallengeTx(challengeTx, serverAccountID, network, webAuthDomain, homeDomains)
@@ -1170,6 +1677,10 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network, webAuthDoma
 		return nil, err
 	}
 
+	// The signing key of the client domain must have signed the transaction
+	// if the client is attributed to a client domain.
+	_, clientDomainAccountID := ChallengeTxClientDomain(tx)
+
 	// Deduplicate the client signers and ensure the server is not included
 	// anywhere we check or output the list of signers.
 	clientSigners := []string{}
@@ -1183,6 +1694,12 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network, webAuthDoma
 		if signer == serverKP.Address() {
 			continue
 		}
+		// Ignore the client domain signer for the same reason, its signature
+		// attributes the client to the client domain but does not
+		// authenticate the client.
+		if signer == clientDomainAccountID {
+			continue
+		}
 		// Deduplicate.
 		if _, seen := clientSignersSeen[signer]; seen {
 			continue
@@ -1208,20 +1725,30 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network, webAuthDoma
 	// hit. We do this in one hit here even though the server signature was
 	// checked in the ReadChallengeTx to ensure that every signature and signer
 	// are consumed only once on the transaction.
-	allSigners := append([]string{serverKP.Address()}, clientSigners...)
+	allSigners := []string{serverKP.Address()}
+	if clientDomainAccountID != "" {
+		allSigners = append(allSigners, clientDomainAccountID)
+	}
+	allSigners = append(allSigners, clientSigners...)
 	allSignersFound, err := verifyTxSignatures(tx, network, allSigners...)
 	if err != nil {
 		return nil, err
 	}
 
-	// Confirm the server is in the list of signers found and remove it.
+	// Confirm the server and the client domain are in the list of signers
+	// found and remove them.
 	serverSignerFound := false
-	signersFound := make([]string, 0, len(allSignersFound)-1)
+	clientDomainSignerFound := false
+	signersFound := make([]string, 0, len(allSignersFound))
 	for _, signer := range allSignersFound {
 		if signer == serverKP.Address() {
 			serverSignerFound = true
 			continue
 		}
+		if signer == clientDomainAccountID {
+			clientDomainSignerFound = true
+			continue
+		}
 		signersFound = append(signersFound, signer)
 	}
 
@@ -1230,6 +1757,11 @@ func VerifyChallengeTxSigners(challengeTx, serverAccountID, network, webAuthDoma
 		return nil, errors.Errorf("transaction not signed by %s", serverKP.Address())
 	}
 
+	// Confirm we matched a signature to the client domain signer.
+	if clientDomainAccountID != "" && !clientDomainSignerFound {
+		return nil, errors.Errorf("transaction not signed by client domain signing key %s", clientDomainAccountID)
+	}
+
 	// Confirm we matched signatures to the client signers.
 	if len(signersFound) == 0 {
 		return nil, errors.Errorf("transaction not signed by %s", strings.Join(clientSigners, ", "))
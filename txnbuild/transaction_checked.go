@@ -0,0 +1,115 @@
+package txnbuild
+
+import (
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionChecks disables the checks of NewTransactionChecked. The zero
+// value enables all checks.
+type TransactionChecks struct {
+	// AllowInfiniteTimeout allows transactions without an upper time bound,
+	// whose outcome remains unknown until they are included in a ledger,
+	// which can take indefinitely long if their fee is too low.
+	AllowInfiniteTimeout bool
+	// AllowLowBaseFee allows base fees lower than MinBaseFee, which are only
+	// valid if the transaction is wrapped in a fee bump transaction.
+	AllowLowBaseFee bool
+	// AllowMissingMemo allows payments to exchange accounts without a memo.
+	AllowMissingMemo bool
+	// ExchangeAccounts are accounts which require a memo for payments to.
+	// Exchanges and custodians usually identify the owner of a deposit by its
+	// memo, and funds sent without one can be lost. Applications should set
+	// it from a list they maintain, an empty non-nil list if they know of no
+	// exchange account. When it is nil, the check fails closed: every payment
+	// without a memo is rejected, as it could be to an exchange. Accounts
+	// which flag themselves with the config.memo_required data entry of
+	// SEP-29 are also checked by horizonclient on submission.
+	ExchangeAccounts []string
+}
+
+// NewTransactionChecked is NewTransaction, but also rejects transactions which
+// are valid but usually built by mistake:
+//
+//  - transactions without an upper time bound,
+//  - transactions whose base fee is lower than MinBaseFee,
+//  - transactions without a memo paying, with a Payment, path payment or
+//    AccountMerge operation, an exchange account of checks.ExchangeAccounts,
+//    or any account if checks.ExchangeAccounts is nil. Payments to muxed
+//    accounts have a memo encoded in the destination and are allowed.
+//
+// Each check can be disabled with checks. The checks run before the sequence
+// number of the source account is incremented, which is left unchanged if
+// they fail.
+func NewTransactionChecked(params TransactionParams, checks TransactionChecks) (*Transaction, error) {
+	// resolve a dynamic base fee once, for the check and the transaction
+	baseFee, err := resolveBaseFee(params.BaseFee, params.FeeSource)
+	if err != nil {
+		return nil, err
+	}
+	params.BaseFee = baseFee
+
+	// time bounds which were not built are rejected by NewTransaction
+	if !checks.AllowInfiniteTimeout && params.Timebounds.wasBuilt && params.Timebounds.MaxTime == TimeoutInfinite {
+		return nil, errors.New("transaction has no upper time bound, set TransactionChecks.AllowInfiniteTimeout to allow it")
+	}
+	if !checks.AllowLowBaseFee && params.BaseFee < MinBaseFee {
+		return nil, errors.Errorf(
+			"base fee %d is lower than the minimum base fee %d, set TransactionChecks.AllowLowBaseFee to allow it",
+			params.BaseFee, MinBaseFee,
+		)
+	}
+	if !checks.AllowMissingMemo && params.Memo == nil {
+		if err := checkExchangeMemos(params.Operations, checks.ExchangeAccounts); err != nil {
+			return nil, err
+		}
+	}
+	return NewTransaction(params)
+}
+
+// checkExchangeMemos returns an error if one of operations pays an exchange
+// account, or any account if exchangeAccounts is nil.
+func checkExchangeMemos(operations []Operation, exchangeAccounts []string) error {
+	exchanges := map[string]bool{}
+	for _, account := range exchangeAccounts {
+		exchanges[account] = true
+	}
+	if exchangeAccounts != nil && len(exchanges) == 0 {
+		return nil
+	}
+
+	for i, op := range operations {
+		var destination string
+		switch op := op.(type) {
+		case *Payment:
+			destination = op.Destination
+		case *PathPaymentStrictReceive:
+			destination = op.Destination
+		case *PathPaymentStrictSend:
+			destination = op.Destination
+		case *AccountMerge:
+			destination = op.Destination
+		default:
+			continue
+		}
+
+		// muxed accounts have a memo id encoded within them
+		muxed, err := xdr.AddressToMuxedAccount(destination)
+		if err == nil && muxed.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+			continue
+		}
+		if exchangeAccounts == nil {
+			return errors.Errorf(
+				"operation %d pays account %s without a memo and no exchange accounts are set, set TransactionChecks.ExchangeAccounts or TransactionChecks.AllowMissingMemo to allow it",
+				i, destination,
+			)
+		}
+		if exchanges[destination] {
+			return errors.Errorf(
+				"operation %d pays exchange account %s without a memo, set TransactionChecks.AllowMissingMemo to allow it",
+				i, destination,
+			)
+		}
+	}
+	return nil
+}
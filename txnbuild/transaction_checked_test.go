@@ -0,0 +1,120 @@
+package txnbuild
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransactionChecked(t *testing.T) {
+	kp0 := newKeypair0()
+	payment := &Payment{
+		Destination: newKeypair1().Address(),
+		Amount:      "10",
+		Asset:       NativeAsset{},
+	}
+	params := func() TransactionParams {
+		return TransactionParams{
+			SourceAccount: &SimpleAccount{kp0.Address(), 1},
+			Operations:    []Operation{payment},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewTimebounds(0, 1000),
+			Memo:          MemoID(1),
+		}
+	}
+
+	tx, err := NewTransactionChecked(params(), TransactionChecks{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), tx.Timebounds().MaxTime)
+
+	// errors of NewTransaction are returned as is
+	p := params()
+	p.Operations = nil
+	_, err = NewTransactionChecked(p, TransactionChecks{})
+	assert.EqualError(t, err, "transaction has no operations")
+
+	p = params()
+	p.Timebounds = NewInfiniteTimeout()
+	_, err = NewTransactionChecked(p, TransactionChecks{})
+	assert.EqualError(t, err, "transaction has no upper time bound, set TransactionChecks.AllowInfiniteTimeout to allow it")
+	_, err = NewTransactionChecked(p, TransactionChecks{AllowInfiniteTimeout: true})
+	assert.NoError(t, err)
+
+	p = params()
+	p.Timebounds = Timebounds{}
+	_, err = NewTransactionChecked(p, TransactionChecks{})
+	assert.EqualError(t, err, "invalid time bounds: timebounds must be constructed using NewTimebounds(), NewTimeout(), or NewInfiniteTimeout()")
+
+	p = params()
+	p.BaseFee = 0
+	_, err = NewTransactionChecked(p, TransactionChecks{})
+	assert.EqualError(t, err, "base fee 0 is lower than the minimum base fee 100, set TransactionChecks.AllowLowBaseFee to allow it")
+	_, err = NewTransactionChecked(p, TransactionChecks{AllowLowBaseFee: true})
+	assert.NoError(t, err)
+}
+
+func TestNewTransactionCheckedExchangeMemo(t *testing.T) {
+	exchange := "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"
+	muxedExchange := "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"
+	params := func(destination string, memo Memo) TransactionParams {
+		return TransactionParams{
+			SourceAccount: &SimpleAccount{newKeypair0().Address(), 1},
+			Operations: []Operation{
+				&BumpSequence{BumpTo: 2},
+				&PathPaymentStrictSend{
+					SendAsset:   NativeAsset{},
+					SendAmount:  "10",
+					Destination: destination,
+					DestAsset:   NativeAsset{},
+					DestMin:     "10",
+				},
+			},
+			BaseFee:             MinBaseFee,
+			Timebounds:          NewTimebounds(0, 1000),
+			Memo:                memo,
+			EnableMuxedAccounts: true,
+		}
+	}
+
+	// payments without a memo are rejected when no exchange accounts are set
+	_, err := NewTransactionChecked(params(exchange, nil), TransactionChecks{})
+	assert.EqualError(t, err, "operation 1 pays account "+exchange+" without a memo and no exchange accounts are set, set TransactionChecks.ExchangeAccounts or TransactionChecks.AllowMissingMemo to allow it")
+	_, err = NewTransactionChecked(params(muxedExchange, nil), TransactionChecks{})
+	assert.NoError(t, err)
+	_, err = NewTransactionChecked(params(exchange, nil), TransactionChecks{ExchangeAccounts: []string{}})
+	assert.NoError(t, err)
+
+	checks := TransactionChecks{ExchangeAccounts: []string{exchange}}
+	_, err = NewTransactionChecked(params(exchange, nil), checks)
+	assert.EqualError(t, err, "operation 1 pays exchange account "+exchange+" without a memo, set TransactionChecks.AllowMissingMemo to allow it")
+
+	_, err = NewTransactionChecked(params(exchange, MemoID(1)), checks)
+	assert.NoError(t, err)
+	_, err = NewTransactionChecked(params(muxedExchange, nil), checks)
+	assert.NoError(t, err)
+	checks.AllowMissingMemo = true
+	_, err = NewTransactionChecked(params(exchange, nil), checks)
+	assert.NoError(t, err)
+}
+
+func TestNewTransactionCheckedSequenceNumber(t *testing.T) {
+	source := &SimpleAccount{newKeypair0().Address(), 1}
+	params := TransactionParams{
+		SourceAccount:        source,
+		IncrementSequenceNum: true,
+		Operations:           []Operation{&BumpSequence{BumpTo: 2}},
+		BaseFee:              MinBaseFee,
+		Timebounds:           NewInfiniteTimeout(),
+	}
+
+	// the sequence number is left unchanged when a check fails
+	_, err := NewTransactionChecked(params, TransactionChecks{})
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), source.Sequence)
+
+	tx, err := NewTransactionChecked(params, TransactionChecks{AllowInfiniteTimeout: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), tx.SequenceNumber())
+	assert.Equal(t, int64(2), source.Sequence)
+}
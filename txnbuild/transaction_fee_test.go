@@ -1,6 +1,7 @@
 package txnbuild
 
 import (
+	"errors"
 	"math"
 	"testing"
 
@@ -151,3 +152,82 @@ func TestFeeBumpFeeGreaterThanOrEqualInner(t *testing.T) {
 	)
 	assert.EqualError(t, err, "base fee cannot be lower than provided inner transaction fee")
 }
+
+type feeSourceFunc func(percentile FeePercentile) (int64, error)
+
+func (f feeSourceFunc) BaseFee(percentile FeePercentile) (int64, error) {
+	return f(percentile)
+}
+
+func TestDynamicFee(t *testing.T) {
+	source := feeSourceFunc(func(percentile FeePercentile) (int64, error) {
+		switch percentile {
+		case FeePercentile90:
+			return 1000, nil
+		case FeePercentile10:
+			return 50, nil
+		}
+		return 0, errors.New("horizon is down")
+	})
+	sourceAccount := &SimpleAccount{keypair.MustRandom().Address(), 1}
+
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        sourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&Inflation{}, &Inflation{}},
+			BaseFee:              DynamicFee(FeePercentile90),
+			FeeSource:            source,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), tx.BaseFee())
+	assert.Equal(t, int64(2000), tx.MaxFee())
+
+	feeBumpTx, err := NewFeeBumpTransaction(
+		FeeBumpTransactionParams{
+			FeeAccount: newKeypair1().Address(),
+			BaseFee:    DynamicFee(FeePercentile90),
+			FeeSource:  source,
+			Inner:      tx,
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), feeBumpTx.BaseFee())
+
+	tx, err = NewTransaction(
+		TransactionParams{
+			SourceAccount: sourceAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       DynamicFee(FeePercentile10),
+			FeeSource:     source,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(MinBaseFee), tx.BaseFee())
+
+	_, err = NewTransaction(
+		TransactionParams{
+			SourceAccount:        sourceAccount,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&Inflation{}},
+			BaseFee:              DynamicFee(FeePercentile50),
+			FeeSource:            source,
+			Timebounds:           NewInfiniteTimeout(),
+		},
+	)
+	assert.EqualError(t, err, "could not resolve dynamic base fee: horizon is down")
+	assert.Equal(t, int64(2), sourceAccount.Sequence)
+
+	_, err = NewTransaction(
+		TransactionParams{
+			SourceAccount: sourceAccount,
+			Operations:    []Operation{&Inflation{}},
+			BaseFee:       DynamicFee(FeePercentile90),
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	assert.EqualError(t, err, "dynamic base fee requires a fee source")
+}
@@ -0,0 +1,306 @@
+package txnbuild
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
+)
+
+// TransactionIntentVersion is the version of the TransactionIntent format
+// implemented by this package.
+const TransactionIntentVersion = 1
+
+// TransactionIntent is a JSON document describing an unsigned transaction to
+// be approved by multiple parties before it is signed and submitted, e.g. by
+// an approval workflow passing it through a ticketing system.
+//
+// Approvals are signatures of the SignaturePayload of the intent, which is
+// derived from the canonical JSON encoding of its fields other than
+// Approvals, so an intent can be reformatted in transit and still be
+// verified. Approvals are not transaction signatures: the transaction is
+// signed once Verify reports the intent fully approved.
+type TransactionIntent struct {
+	Version           int    `json:"version"`
+	NetworkPassphrase string `json:"network_passphrase"`
+	// Transaction is the base64 encoded XDR envelope of the transaction,
+	// without signatures.
+	Transaction string `json:"transaction"`
+	// TransactionHash is the hex encoded hash of the transaction.
+	TransactionHash string `json:"transaction_hash"`
+	// RequiredSigners are the sorted addresses of the accounts which must
+	// approve the intent.
+	RequiredSigners []string `json:"required_signers"`
+	// ExpiresAt is the time, in UTC and in whole seconds, after which the
+	// intent must not be approved or acted on.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Annotations are human readable notes, e.g. the reason for the
+	// transaction or the ticket tracking its approval.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Approvals are sorted by signer.
+	Approvals []TransactionIntentApproval `json:"approvals,omitempty"`
+}
+
+// TransactionIntentApproval is the approval of a TransactionIntent by one of
+// its required signers.
+type TransactionIntentApproval struct {
+	Signer string `json:"signer"`
+	// Signature is the base64 encoded ed25519 signature of the
+	// SignaturePayload of the intent by Signer.
+	Signature string `json:"signature"`
+}
+
+// TransactionIntentParams are the parameters of a TransactionIntent, see
+// NewTransactionIntent.
+type TransactionIntentParams struct {
+	NetworkPassphrase string
+	RequiredSigners   []string
+	ExpiresAt         time.Time
+	Annotations       map[string]string
+}
+
+// NewTransactionIntent returns an intent to sign tx, which must not be signed
+// yet, once approved by params.RequiredSigners. The required signers are
+// sorted and deduplicated and ExpiresAt is truncated to whole seconds in UTC,
+// so that the same parameters always produce the same intent.
+func NewTransactionIntent(tx *Transaction, params TransactionIntentParams) (*TransactionIntent, error) {
+	if len(tx.Signatures()) > 0 {
+		return nil, errors.New("transaction must not be signed")
+	}
+	txe, err := tx.Base64()
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding transaction")
+	}
+	hash, err := tx.HashHex(params.NetworkPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "hashing transaction")
+	}
+
+	signers := map[string]bool{}
+	requiredSigners := []string{}
+	for _, signer := range params.RequiredSigners {
+		if !signers[signer] {
+			signers[signer] = true
+			requiredSigners = append(requiredSigners, signer)
+		}
+	}
+	sort.Strings(requiredSigners)
+
+	intent := &TransactionIntent{
+		Version:           TransactionIntentVersion,
+		NetworkPassphrase: params.NetworkPassphrase,
+		Transaction:       txe,
+		TransactionHash:   hash,
+		RequiredSigners:   requiredSigners,
+		ExpiresAt:         params.ExpiresAt.UTC().Truncate(time.Second),
+		Annotations:       params.Annotations,
+	}
+	err = intent.validate()
+	if err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// ParseTransactionIntent parses a TransactionIntent from its JSON encoding.
+// Documents with unknown fields are rejected, since those fields would not
+// be covered by the approvals. Approvals are not verified, see Verify.
+func ParseTransactionIntent(data []byte) (*TransactionIntent, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	intent := &TransactionIntent{}
+	err := dec.Decode(intent)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding transaction intent")
+	}
+	err = intent.validate()
+	if err != nil {
+		return nil, err
+	}
+	return intent, nil
+}
+
+// CanonicalJSON returns the canonical JSON encoding of the intent: compact,
+// with fields in the order they are declared in, annotations sorted by key,
+// and without HTML escaping.
+func (i *TransactionIntent) CanonicalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(i)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding transaction intent")
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// SignaturePayload returns the hash signed by the approvals of the intent,
+// the SHA-256 hash of the canonical JSON encoding of the intent without
+// approvals.
+func (i *TransactionIntent) SignaturePayload() ([32]byte, error) {
+	unapproved := *i
+	unapproved.Approvals = nil
+	data, err := unapproved.CanonicalJSON()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// ParseTransaction returns the transaction of the intent.
+func (i *TransactionIntent) ParseTransaction() (*Transaction, error) {
+	genericTx, err := TransactionFromXDR(i.Transaction)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding transaction")
+	}
+	tx, ok := genericTx.Transaction()
+	if !ok {
+		return nil, errors.New("transaction must not be a fee bump transaction")
+	}
+	return tx, nil
+}
+
+// Sign adds the approval of signer, which must be one of the required
+// signers, replacing its previous approval if any.
+func (i *TransactionIntent) Sign(signer keypair.Signer) error {
+	address := signer.Address()
+	if !i.isRequiredSigner(address) {
+		return errors.Errorf("%s is not a required signer", address)
+	}
+	payload, err := i.SignaturePayload()
+	if err != nil {
+		return err
+	}
+	sig, err := signer.SignDecorated(payload[:])
+	if err != nil {
+		return errors.Wrap(err, "signing transaction intent")
+	}
+
+	approval := TransactionIntentApproval{
+		Signer:    address,
+		Signature: base64.StdEncoding.EncodeToString(sig.Signature),
+	}
+	n := sort.Search(len(i.Approvals), func(j int) bool {
+		return i.Approvals[j].Signer >= address
+	})
+	if n < len(i.Approvals) && i.Approvals[n].Signer == address {
+		i.Approvals[n] = approval
+		return nil
+	}
+	i.Approvals = append(i.Approvals, TransactionIntentApproval{})
+	copy(i.Approvals[n+1:], i.Approvals[n:])
+	i.Approvals[n] = approval
+	return nil
+}
+
+// PendingSigners returns the required signers which have not approved the
+// intent. The approvals are not verified, see Verify.
+func (i *TransactionIntent) PendingSigners() []string {
+	approved := map[string]bool{}
+	for _, approval := range i.Approvals {
+		approved[approval.Signer] = true
+	}
+	pending := []string{}
+	for _, signer := range i.RequiredSigners {
+		if !approved[signer] {
+			pending = append(pending, signer)
+		}
+	}
+	return pending
+}
+
+// Verify returns an error if the intent is invalid, has expired at now, has
+// an approval which is invalid or not by a required signer, or has not been
+// approved by all of its required signers yet.
+func (i *TransactionIntent) Verify(now time.Time) error {
+	err := i.validate()
+	if err != nil {
+		return err
+	}
+	if !now.Before(i.ExpiresAt) {
+		return errors.Errorf("transaction intent expired at %s", i.ExpiresAt.Format(time.RFC3339))
+	}
+
+	payload, err := i.SignaturePayload()
+	if err != nil {
+		return err
+	}
+	for j, approval := range i.Approvals {
+		if j > 0 && i.Approvals[j-1].Signer >= approval.Signer {
+			return errors.New("approvals must be sorted by signer and unique")
+		}
+		if !i.isRequiredSigner(approval.Signer) {
+			return errors.Errorf("approval of %s, which is not a required signer", approval.Signer)
+		}
+		sig, err := base64.StdEncoding.DecodeString(approval.Signature)
+		if err != nil {
+			return errors.Errorf("approval of %s has an invalid signature encoding", approval.Signer)
+		}
+		err = keypair.MustParseAddress(approval.Signer).Verify(payload[:], sig)
+		if err != nil {
+			return errors.Errorf("approval of %s has an invalid signature", approval.Signer)
+		}
+	}
+
+	pending := i.PendingSigners()
+	if len(pending) > 0 {
+		return errors.Errorf("transaction intent is not approved by %s", strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+func (i *TransactionIntent) isRequiredSigner(address string) bool {
+	n := sort.SearchStrings(i.RequiredSigners, address)
+	return n < len(i.RequiredSigners) && i.RequiredSigners[n] == address
+}
+
+// validate returns an error if the intent is not in its canonical form or
+// its transaction does not match it.
+func (i *TransactionIntent) validate() error {
+	if i.Version != TransactionIntentVersion {
+		return errors.Errorf("unsupported transaction intent version %d", i.Version)
+	}
+	if i.NetworkPassphrase == "" {
+		return errors.New("network passphrase must be set")
+	}
+	if len(i.RequiredSigners) == 0 {
+		return errors.New("at least one required signer must be set")
+	}
+	for j, signer := range i.RequiredSigners {
+		if !strkey.IsValidEd25519PublicKey(signer) {
+			return errors.Errorf("required signer %q is not a valid account address", signer)
+		}
+		if j > 0 && i.RequiredSigners[j-1] >= signer {
+			return errors.New("required signers must be sorted and unique")
+		}
+	}
+	if i.ExpiresAt.IsZero() {
+		return errors.New("expiry must be set")
+	}
+	if _, offset := i.ExpiresAt.Zone(); offset != 0 || i.ExpiresAt.Nanosecond() != 0 {
+		return errors.New("expiry must be in UTC and in whole seconds")
+	}
+
+	tx, err := i.ParseTransaction()
+	if err != nil {
+		return err
+	}
+	if len(tx.Signatures()) > 0 {
+		return errors.New("transaction must not be signed")
+	}
+	hash, err := tx.HashHex(i.NetworkPassphrase)
+	if err != nil {
+		return errors.Wrap(err, "hashing transaction")
+	}
+	if hash != i.TransactionHash {
+		return errors.New("transaction hash does not match the transaction")
+	}
+	return nil
+}
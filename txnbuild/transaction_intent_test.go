@@ -0,0 +1,171 @@
+package txnbuild
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransactionIntent(t *testing.T) (*Transaction, *TransactionIntent) {
+	kp0 := newKeypair0()
+	sourceAccount := NewSimpleAccount(kp0.Address(), 1)
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations: []Operation{
+				&Payment{Destination: newKeypair2().Address(), Amount: "10", Asset: NativeAsset{}},
+			},
+			BaseFee:    MinBaseFee,
+			Timebounds: NewInfiniteTimeout(),
+		},
+	)
+	require.NoError(t, err)
+
+	intent, err := NewTransactionIntent(tx, TransactionIntentParams{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		RequiredSigners:   []string{newKeypair1().Address(), kp0.Address(), kp0.Address()},
+		ExpiresAt:         time.Date(2021, 3, 1, 13, 0, 0, 500, time.FixedZone("CET", 3600)),
+		Annotations:       map[string]string{"ticket": "OPS-42", "reason": "rebalance <hot> & cold"},
+	})
+	require.NoError(t, err)
+	return tx, intent
+}
+
+func TestNewTransactionIntent(t *testing.T) {
+	tx, intent := newTestTransactionIntent(t)
+
+	txe, err := tx.Base64()
+	require.NoError(t, err)
+	hash, err := tx.HashHex(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, txe, intent.Transaction)
+	assert.Equal(t, hash, intent.TransactionHash)
+	assert.Equal(t, []string{
+		"GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP",
+		"GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3",
+	}, intent.RequiredSigners)
+	assert.Equal(t, time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC), intent.ExpiresAt)
+
+	data, err := intent.CanonicalJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"version":1,`+
+		`"network_passphrase":"Test SDF Network ; September 2015",`+
+		`"transaction":"`+txe+`",`+
+		`"transaction_hash":"`+hash+`",`+
+		`"required_signers":["GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP","GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3"],`+
+		`"expires_at":"2021-03-01T12:00:00Z",`+
+		`"annotations":{"reason":"rebalance <hot> & cold","ticket":"OPS-42"}}`, string(data))
+
+	signedTx, err := tx.Sign(network.TestNetworkPassphrase, newKeypair0())
+	require.NoError(t, err)
+	_, err = NewTransactionIntent(signedTx, TransactionIntentParams{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		RequiredSigners:   []string{newKeypair0().Address()},
+		ExpiresAt:         time.Now().Add(time.Hour),
+	})
+	assert.EqualError(t, err, "transaction must not be signed")
+
+	_, err = NewTransactionIntent(tx, TransactionIntentParams{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		ExpiresAt:         time.Now().Add(time.Hour),
+	})
+	assert.EqualError(t, err, "at least one required signer must be set")
+
+	_, err = NewTransactionIntent(tx, TransactionIntentParams{
+		NetworkPassphrase: network.TestNetworkPassphrase,
+		RequiredSigners:   []string{"GABC"},
+		ExpiresAt:         time.Now().Add(time.Hour),
+	})
+	assert.EqualError(t, err, `required signer "GABC" is not a valid account address`)
+}
+
+func TestTransactionIntentSignAndVerify(t *testing.T) {
+	_, intent := newTestTransactionIntent(t)
+	now := time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	assert.EqualError(t, intent.Sign(newKeypair2()), "GB7BDSZU2Y27LYNLALKKALB52WS2IZWYBDGY6EQBLEED3TJOCVMZRH7H is not a required signer")
+
+	require.NoError(t, intent.Sign(newKeypair0()))
+	assert.Equal(t, []string{"GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP"}, intent.PendingSigners())
+	assert.EqualError(t, intent.Verify(now), "transaction intent is not approved by GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP")
+
+	// Approvals are kept sorted by signer regardless of the signing order.
+	require.NoError(t, intent.Sign(newKeypair1()))
+	assert.Empty(t, intent.PendingSigners())
+	require.Len(t, intent.Approvals, 2)
+	assert.Equal(t, newKeypair1().Address(), intent.Approvals[0].Signer)
+	assert.Equal(t, newKeypair0().Address(), intent.Approvals[1].Signer)
+	assert.NoError(t, intent.Verify(now))
+
+	// Signing again replaces the previous approval.
+	require.NoError(t, intent.Sign(newKeypair1()))
+	assert.Len(t, intent.Approvals, 2)
+
+	assert.EqualError(t, intent.Verify(intent.ExpiresAt), "transaction intent expired at 2021-03-01T12:00:00Z")
+}
+
+func TestTransactionIntentRoundTrip(t *testing.T) {
+	_, intent := newTestTransactionIntent(t)
+	now := time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC)
+	require.NoError(t, intent.Sign(newKeypair0()))
+
+	// Indentation and field order do not affect approvals.
+	data := []byte(`{
+		"approvals": [{"signer": "` + intent.Approvals[0].Signer + `", "signature": "` + intent.Approvals[0].Signature + `"}],
+		"annotations": {"ticket": "OPS-42", "reason": "rebalance <hot> & cold"},
+		"expires_at": "2021-03-01T12:00:00Z",
+		"required_signers": ["GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP", "GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3"],
+		"transaction_hash": "` + intent.TransactionHash + `",
+		"transaction": "` + intent.Transaction + `",
+		"network_passphrase": "Test SDF Network ; September 2015",
+		"version": 1
+	}`)
+	parsed, err := ParseTransactionIntent(data)
+	require.NoError(t, err)
+	assert.Equal(t, intent, parsed)
+	require.NoError(t, parsed.Sign(newKeypair1()))
+	assert.NoError(t, parsed.Verify(now))
+
+	parsedTx, err := parsed.ParseTransaction()
+	require.NoError(t, err)
+	assert.Empty(t, parsedTx.Signatures())
+	assert.Equal(t, int64(2), parsedTx.SourceAccount().Sequence)
+
+	_, err = ParseTransactionIntent([]byte(`{"version":1,"unknown":true}`))
+	assert.EqualError(t, err, `decoding transaction intent: json: unknown field "unknown"`)
+}
+
+func TestTransactionIntentTampered(t *testing.T) {
+	now := time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	_, intent := newTestTransactionIntent(t)
+	require.NoError(t, intent.Sign(newKeypair0()))
+	require.NoError(t, intent.Sign(newKeypair1()))
+	intent.Annotations["ticket"] = "OPS-43"
+	assert.EqualError(t, intent.Verify(now), "approval of GAS4V4O2B7DW5T7IQRPEEVCRXMDZESKISR7DVIGKZQYYV3OSQ5SH5LVP has an invalid signature")
+
+	_, intent = newTestTransactionIntent(t)
+	require.NoError(t, intent.Sign(newKeypair0()))
+	intent.ExpiresAt = intent.ExpiresAt.Add(time.Hour)
+	assert.EqualError(t, intent.Verify(now), "approval of GDQNY3PBOJOKYZSRMK2S7LHHGWZIUISD4QORETLMXEWXBI7KFZZMKTL3 has an invalid signature")
+
+	_, intent = newTestTransactionIntent(t)
+	intent.TransactionHash = "00" + intent.TransactionHash[2:]
+	assert.EqualError(t, intent.Verify(now), "transaction hash does not match the transaction")
+
+	_, intent = newTestTransactionIntent(t)
+	intent.NetworkPassphrase = network.PublicNetworkPassphrase
+	assert.EqualError(t, intent.Verify(now), "transaction hash does not match the transaction")
+
+	_, intent = newTestTransactionIntent(t)
+	intent.Approvals = []TransactionIntentApproval{{Signer: newKeypair2().Address(), Signature: ""}}
+	assert.EqualError(t, intent.Verify(now), "approval of GB7BDSZU2Y27LYNLALKKALB52WS2IZWYBDGY6EQBLEED3TJOCVMZRH7H, which is not a required signer")
+
+	_, intent = newTestTransactionIntent(t)
+	intent.RequiredSigners[0], intent.RequiredSigners[1] = intent.RequiredSigners[1], intent.RequiredSigners[0]
+	assert.EqualError(t, intent.Verify(now), "required signers must be sorted and unique")
+}
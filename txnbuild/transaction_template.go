@@ -0,0 +1,169 @@
+package txnbuild
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+var placeholderRegexp = regexp.MustCompile(`^\{\{([A-Za-z0-9_.-]+)\}\}$`)
+
+// Placeholder returns the placeholder for the value named name, to be used
+// in the string fields of the operations of a TransactionTemplate, e.g. as
+// the amount or destination of a Payment, or as a MemoText.
+func Placeholder(name string) string {
+	return "{{" + name + "}}"
+}
+
+// TransactionTemplate is a transaction with named placeholders from which
+// many concrete transactions can be instantiated. The operations without
+// placeholders are validated and built once when the template is created,
+// so that instantiating a template is cheaper than building the transaction
+// with NewTransaction.
+type TransactionTemplate struct {
+	params       TransactionParams
+	operations   []templateOperation
+	memo         string
+	placeholders map[string]bool
+}
+
+// templateOperation is an operation of a TransactionTemplate. Operations
+// without placeholders are built once, and their XDR is reused.
+type templateOperation struct {
+	op     Operation
+	xdrOp  xdr.Operation
+	fields []templateField
+}
+
+// templateField is a string field of an operation holding a placeholder.
+type templateField struct {
+	index       int
+	placeholder string
+}
+
+// NewTransactionTemplate returns a new TransactionTemplate. The string fields
+// of the operations, and the memo if it is a MemoText, can be placeholders
+// returned by Placeholder. The source account of the transactions
+// instantiated with Instantiate is params.SourceAccount.
+func NewTransactionTemplate(params TransactionParams) (*TransactionTemplate, error) {
+	if len(params.Operations) == 0 {
+		return nil, errors.New("transaction has no operations")
+	}
+
+	t := &TransactionTemplate{
+		params:       params,
+		placeholders: map[string]bool{},
+	}
+
+	if memo, ok := params.Memo.(MemoText); ok {
+		if m := placeholderRegexp.FindStringSubmatch(string(memo)); m != nil {
+			t.memo = m[1]
+			t.placeholders[m[1]] = true
+		}
+	}
+
+	for _, op := range params.Operations {
+		v := reflect.ValueOf(op)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			return nil, errors.Errorf("operation %T is not a pointer to a struct", op)
+		}
+
+		templateOp := templateOperation{op: op}
+		elem := v.Elem()
+		for i := 0; i < elem.NumField(); i++ {
+			field := elem.Field(i)
+			if field.Kind() != reflect.String || !field.CanSet() {
+				continue
+			}
+			if m := placeholderRegexp.FindStringSubmatch(field.String()); m != nil {
+				templateOp.fields = append(templateOp.fields, templateField{index: i, placeholder: m[1]})
+				t.placeholders[m[1]] = true
+			}
+		}
+
+		if len(templateOp.fields) == 0 {
+			xdrOp, err := validateAndBuildOperationXDR(op, params)
+			if err != nil {
+				return nil, err
+			}
+			templateOp.xdrOp = xdrOp
+		}
+		t.operations = append(t.operations, templateOp)
+	}
+
+	return t, nil
+}
+
+// Placeholders returns the sorted names of the placeholders of the template.
+func (t *TransactionTemplate) Placeholders() []string {
+	names := make([]string, 0, len(t.placeholders))
+	for name := range t.placeholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Instantiate returns a new Transaction with the placeholders of the template
+// replaced by values, which must contain a value for each placeholder and
+// nothing else. The sequence number of the template's source account is
+// incremented if IncrementSequenceNum is set, as with NewTransaction.
+func (t *TransactionTemplate) Instantiate(values map[string]string) (*Transaction, error) {
+	return t.InstantiateWithSourceAccount(t.params.SourceAccount, values)
+}
+
+// InstantiateWithSourceAccount is like Instantiate but uses sourceAccount as
+// the source account of the transaction, e.g. to spread transactions over
+// channel accounts.
+func (t *TransactionTemplate) InstantiateWithSourceAccount(sourceAccount Account, values map[string]string) (*Transaction, error) {
+	var missing, unknown []string
+	for name := range t.placeholders {
+		if _, ok := values[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	for name := range values {
+		if !t.placeholders[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, errors.Errorf("missing values for placeholders: %s", strings.Join(missing, ", "))
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.Errorf("unknown placeholders: %s", strings.Join(unknown, ", "))
+	}
+
+	params := t.params
+	params.SourceAccount = sourceAccount
+	if t.memo != "" {
+		params.Memo = MemoText(values[t.memo])
+	}
+	params.Operations = make([]Operation, len(t.operations))
+	for i, templateOp := range t.operations {
+		if len(templateOp.fields) == 0 {
+			params.Operations[i] = templateOp.op
+			continue
+		}
+		v := reflect.New(reflect.TypeOf(templateOp.op).Elem())
+		v.Elem().Set(reflect.ValueOf(templateOp.op).Elem())
+		for _, field := range templateOp.fields {
+			v.Elem().Field(field.index).SetString(values[field.placeholder])
+		}
+		params.Operations[i] = v.Interface().(Operation)
+	}
+
+	return newTransaction(params, func(i int, op Operation) (xdr.Operation, error) {
+		templateOp := t.operations[i]
+		if len(templateOp.fields) == 0 {
+			return templateOp.xdrOp, nil
+		}
+		return validateAndBuildOperationXDR(op, params)
+	})
+}
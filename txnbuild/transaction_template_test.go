@@ -0,0 +1,107 @@
+package txnbuild
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionTemplate(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	kp2 := newKeypair2()
+	source := &SimpleAccount{AccountID: kp0.Address(), Sequence: 100}
+	bumpSequence := &BumpSequence{BumpTo: 200}
+
+	template, err := NewTransactionTemplate(TransactionParams{
+		SourceAccount:        source,
+		IncrementSequenceNum: true,
+		Operations: []Operation{
+			&Payment{
+				Destination: Placeholder("destination"),
+				Amount:      Placeholder("amount"),
+				Asset:       NativeAsset{},
+			},
+			bumpSequence,
+		},
+		Memo:       MemoText(Placeholder("memo")),
+		BaseFee:    MinBaseFee,
+		Timebounds: NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"amount", "destination", "memo"}, template.Placeholders())
+
+	for i, destination := range []string{kp1.Address(), kp2.Address()} {
+		tx, err := template.Instantiate(map[string]string{
+			"destination": destination,
+			"amount":      "10",
+			"memo":        "payout",
+		})
+		require.NoError(t, err)
+
+		expected, err := NewTransaction(TransactionParams{
+			SourceAccount: &SimpleAccount{AccountID: kp0.Address(), Sequence: int64(101 + i)},
+			Operations: []Operation{
+				&Payment{Destination: destination, Amount: "10", Asset: NativeAsset{}},
+				bumpSequence,
+			},
+			Memo:       MemoText("payout"),
+			BaseFee:    MinBaseFee,
+			Timebounds: NewInfiniteTimeout(),
+		})
+		require.NoError(t, err)
+
+		expectedB64, err := expected.Base64()
+		require.NoError(t, err)
+		b64, err := tx.Base64()
+		require.NoError(t, err)
+		assert.Equal(t, expectedB64, b64)
+	}
+	assert.Equal(t, int64(102), source.Sequence)
+
+	channel := &SimpleAccount{AccountID: kp2.Address(), Sequence: 10}
+	tx, err := template.InstantiateWithSourceAccount(channel, map[string]string{
+		"destination": kp1.Address(),
+		"amount":      "10",
+		"memo":        "payout",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, kp2.Address(), tx.SourceAccount().AccountID)
+	assert.Equal(t, int64(11), tx.SequenceNumber())
+	assert.Equal(t, int64(102), source.Sequence)
+}
+
+func TestTransactionTemplateErrors(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+
+	_, err := NewTransactionTemplate(TransactionParams{
+		SourceAccount: &SimpleAccount{AccountID: kp0.Address()},
+		Operations: []Operation{
+			&Payment{Destination: kp1.Address(), Amount: "-1", Asset: NativeAsset{}},
+		},
+		BaseFee:    MinBaseFee,
+		Timebounds: NewInfiniteTimeout(),
+	})
+	assert.Contains(t, err.Error(), "validation failed for *txnbuild.Payment operation")
+
+	template, err := NewTransactionTemplate(TransactionParams{
+		SourceAccount: &SimpleAccount{AccountID: kp0.Address()},
+		Operations: []Operation{
+			&Payment{Destination: kp1.Address(), Amount: Placeholder("amount"), Asset: NativeAsset{}},
+		},
+		BaseFee:    MinBaseFee,
+		Timebounds: NewInfiniteTimeout(),
+	})
+	require.NoError(t, err)
+
+	_, err = template.Instantiate(map[string]string{})
+	assert.EqualError(t, err, "missing values for placeholders: amount")
+
+	_, err = template.Instantiate(map[string]string{"amount": "1", "memo": "payout"})
+	assert.EqualError(t, err, "unknown placeholders: memo")
+
+	_, err = template.Instantiate(map[string]string{"amount": "-1"})
+	assert.Contains(t, err.Error(), "validation failed for *txnbuild.Payment operation")
+}
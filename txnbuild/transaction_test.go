@@ -1128,6 +1128,73 @@ func TestBuildChallengeTx(t *testing.T) {
 	}
 }
 
+func TestBuildChallengeTxWithClientDomain(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+
+	tx, err := BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	ops := tx.Operations()
+	require.Len(t, ops, 3)
+	clientDomainOp := ops[2].(*ManageData)
+	assert.Equal(t, clientDomainKP.Address(), clientDomainOp.SourceAccount)
+	assert.Equal(t, "client_domain", clientDomainOp.Name)
+	assert.Equal(t, []byte("testwallet.stellar.org"), clientDomainOp.Value)
+
+	clientDomain, clientDomainAccountID := ChallengeTxClientDomain(tx)
+	assert.Equal(t, "testwallet.stellar.org", clientDomain)
+	assert.Equal(t, clientDomainKP.Address(), clientDomainAccountID)
+
+	_, err = BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	assert.EqualError(t, err, "client domain cannot be empty")
+
+	_, err = BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "testwallet.stellar.org", "GREATACCOUNT", network.TestNetworkPassphrase, time.Minute)
+	assert.Error(t, err)
+}
+
+func muxedAddress(address string, id uint64) string {
+	aid := xdr.MustAddress(address)
+	muxed := xdr.MuxedAccount{
+		Type: xdr.CryptoKeyTypeKeyTypeMuxedEd25519,
+		Med25519: &xdr.MuxedAccountMed25519{
+			Id:      xdr.Uint64(id),
+			Ed25519: *aid.Ed25519,
+		},
+	}
+	return muxed.Address()
+}
+
+func TestBuildChallengeTxWithMemo(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+
+	tx, err := BuildChallengeTxWithMemo(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", network.TestNetworkPassphrase, time.Minute, MemoID(1234))
+	require.NoError(t, err)
+	assert.Equal(t, MemoID(1234), tx.Memo())
+	memo := ChallengeTxMemo(tx)
+	require.NotNil(t, memo)
+	assert.Equal(t, MemoID(1234), *memo)
+
+	muxedClient := muxedAddress(clientKP.Address(), 1234)
+	_, err = BuildChallengeTxWithMemo(serverKP.Seed(), muxedClient, "testwebauth.stellar.org", "testanchor.stellar.org", network.TestNetworkPassphrase, time.Minute, MemoID(1234))
+	assert.EqualError(t, err, "memos are not valid for challenge transactions with a muxed client account")
+}
+
+func TestBuildChallengeTx_muxedClientAccount(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	muxedClient := muxedAddress(clientKP.Address(), 1234)
+
+	tx, err := BuildChallengeTx(serverKP.Seed(), muxedClient, "testwebauth.stellar.org", "testanchor.stellar.org", network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, muxedClient, tx.Operations()[0].(*ManageData).SourceAccount)
+	assert.Nil(t, ChallengeTxMemo(tx))
+	sourceAccount := tx.ToXDR().Operations()[0].SourceAccount
+	assert.Equal(t, xdr.CryptoKeyTypeKeyTypeMuxedEd25519, sourceAccount.Type)
+	assert.Equal(t, xdr.Uint64(1234), sourceAccount.Med25519.Id)
+}
+
 func TestHashHex(t *testing.T) {
 	kp0 := newKeypair0()
 	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
@@ -1360,6 +1427,59 @@ func TestHashXTransaction(t *testing.T) {
 
 }
 
+func TestSignedPayloadTransaction(t *testing.T) {
+	kp0, kp1 := newKeypair0(), newKeypair1()
+	payload := []byte("a payload signed by kp1")
+	sp, err := strkey.NewSignedPayload(kp1.Address(), payload)
+	assert.NoError(t, err)
+	signedPayload, err := sp.Encode()
+	assert.NoError(t, err)
+
+	sourceAccount := NewSimpleAccount(kp0.Address(), int64(4353383146192899))
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &sourceAccount,
+			IncrementSequenceNum: true,
+			Operations: []Operation{&SetOptions{
+				Signer: &Signer{Address: signedPayload, Weight: Threshold(1)},
+			}},
+			BaseFee:    MinBaseFee,
+			Timebounds: NewInfiniteTimeout(),
+		},
+	)
+	assert.NoError(t, err)
+
+	op := tx.ToXDR().Operations()[0].Body.MustSetOptionsOp()
+	assert.Equal(t, xdr.SignerKeyTypeSignerKeyTypeEd25519SignedPayload, op.Signer.Key.Type)
+	assert.Equal(t, payload, op.Signer.Key.MustEd25519SignedPayload().Payload)
+
+	txeB64, err := tx.Base64()
+	assert.NoError(t, err)
+	parsed, err := TransactionFromXDR(txeB64)
+	assert.NoError(t, err)
+	parsedTx, ok := parsed.Transaction()
+	assert.True(t, ok)
+	assert.Equal(t, signedPayload, parsedTx.Operations()[0].(*SetOptions).Signer.Address)
+
+	tx, err = tx.Sign(network.TestNetworkPassphrase, kp0)
+	assert.NoError(t, err)
+	signed, err := tx.SignPayload(payload, kp1)
+	assert.NoError(t, err)
+	assert.Len(t, tx.Signatures(), 1, "the original transaction is not modified")
+
+	signatures := signed.Signatures()
+	assert.Len(t, signatures, 2)
+	hint := kp1.Hint()
+	for i := 0; i < 4; i++ {
+		hint[i] ^= payload[len(payload)-4+i]
+	}
+	assert.Equal(t, xdr.SignatureHint(hint), signatures[1].Hint)
+	assert.NoError(t, kp1.Verify(payload, signatures[1].Signature))
+
+	_, err = tx.SignPayload(make([]byte, 65), kp1)
+	assert.EqualError(t, err, "payload cannot be more than 64 bytes")
+}
+
 func TestFromXDR(t *testing.T) {
 	txeB64 := "AAAAACYWIvM98KlTMs0IlQBZ06WkYpZ+gILsQN6ega0++I/sAAAAZAAXeEkAAAABAAAAAAAAAAEAAAAQMkExVjZKNTcwM0c0N1hIWQAAAAEAAAABAAAAACYWIvM98KlTMs0IlQBZ06WkYpZ+gILsQN6ega0++I/sAAAAAQAAAADMSEvcRKXsaUNna++Hy7gWm/CfqTjEA7xoGypfrFGUHAAAAAAAAAACCPHRAAAAAAAAAAABPviP7AAAAEBu6BCKf4WZHPum5+29Nxf6SsJNN8bgjp1+e1uNBaHjRg3rdFZYgUqEqbHxVEs7eze3IeRbjMZxS3zPf/xwJCEI"
 
@@ -1447,6 +1567,265 @@ func TestFromXDR(t *testing.T) {
 
 }
 
+func TestFromXDRStrict(t *testing.T) {
+	txeB64 := "AAAAAGigiN2q4qBXAERImNEncpaADylyBRtzdqpEsku6CN0xAAABkAAADXYAAAABAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAABAAAABm5ldyB0eAAAAAAAAgAAAAEAAAAA+Q2efEMLNGF4i+aYfutUXGMSlf8tNevKeS1Jl/oCVGkAAAAGAAAAAVVTRAAAAAAAaKCI3arioFcAREiY0SdyloAPKXIFG3N2qkSyS7oI3TF//////////wAAAAAAAAAKAAAABHRlc3QAAAABAAAABXZhbHVlAAAAAAAAAAAAAAA="
+
+	tx, err := TransactionFromXDR(txeB64, TransactionFromXDROptionStrict)
+	assert.NoError(t, err)
+	newTx, ok := tx.Transaction()
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(newTx.Operations()), "Operations length should match")
+
+	txB64WithMuxedAccounts := "AAAAAgAAAQAAAAAAyv66vuDcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAiII0AAAAbAAAAAQAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAAAAEAAAEAAAAAAMr+ur7g3G3hclysZlFitS+s5zWyiiJD5B0STWy5LXCj6i5yxQAAAAEAAAEAgAAAAAAAAAA/DDS/k60NmXHQTMyQ9wVRHIOKrZc0pKL7DXoD/H/omgAAAAAAAAAABfXhAAAAAAAAAAAB6i5yxQAAAED4Wkvwf/BJV+fqa6Kvi+T/7ZL82pOinN68GlvEi9qK4klH+qITyvN3jRj5Nfz0+VrE2xBJPVc8sS/qN9LlznoC"
+
+	// Muxed accounts would be silently dropped, so strict parsing rejects them
+	_, err = TransactionFromXDR(txB64WithMuxedAccounts, TransactionFromXDROptionStrict)
+	assert.EqualError(t, err, "transaction envelope cannot be parsed strictly: source account is a muxed account but muxed accounts are not enabled")
+
+	tx, err = TransactionFromXDR(
+		txB64WithMuxedAccounts,
+		TransactionFromXDROptionStrict,
+		TransactionFromXDROptionEnableMuxedAccounts,
+	)
+	assert.NoError(t, err)
+	newTx, ok = tx.Transaction()
+	assert.True(t, ok)
+	op, ok := newTx.Operations()[0].(*Payment)
+	assert.True(t, ok)
+	assert.Equal(t, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK", op.Destination)
+}
+
+func TestFromXDRRejectsUnknownExtensionsAndOperations(t *testing.T) {
+	kp0 := newKeypair0()
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+			Operations:    []Operation{&BumpSequence{BumpTo: 5}},
+			BaseFee:       MinBaseFee,
+			Timebounds:    NewInfiniteTimeout(),
+		},
+	)
+	require.NoError(t, err)
+	txeBytes, err := tx.MarshalBinary()
+	require.NoError(t, err)
+
+	// The envelope ends with the operation body (type and bump to), the
+	// transaction extension and the signature count.
+	withExtension := append([]byte{}, txeBytes...)
+	withExtension[len(withExtension)-5] = 1
+	_, err = TransactionFromXDR(base64.StdEncoding.EncodeToString(withExtension))
+	assert.EqualError(t, err, "unable to unmarshal transaction envelope: xdr:decode: switch '1' is not valid for union")
+
+	withUnknownOp := append([]byte{}, txeBytes...)
+	withUnknownOp[len(withUnknownOp)-17] = 99
+	_, err = TransactionFromXDR(base64.StdEncoding.EncodeToString(withUnknownOp))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to unmarshal transaction envelope")
+}
+
+func TestFromXDRStrictRejectsUnsupportedFields(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	newInner := func(op Operation) *Transaction {
+		tx, err := NewTransaction(
+			TransactionParams{
+				SourceAccount:       &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+				Operations:          []Operation{op},
+				BaseFee:             MinBaseFee,
+				Timebounds:          NewInfiniteTimeout(),
+				EnableMuxedAccounts: true,
+			},
+		)
+		require.NoError(t, err)
+		return tx
+	}
+
+	// An unknown account flag is dropped by SetOptions
+	inner := newInner(&SetOptions{SetFlags: []AccountFlag{AuthRequired}})
+	*inner.ToXDR().V1.Tx.Operations[0].Body.SetOptionsOp.SetFlags |= 0x100
+	txeB64, err := inner.Base64()
+	require.NoError(t, err)
+	_, err = TransactionFromXDR(txeB64)
+	assert.NoError(t, err)
+	_, err = TransactionFromXDR(txeB64, TransactionFromXDROptionStrict)
+	assert.EqualError(t, err, "transaction envelope cannot be parsed strictly: OperationTypeSetOptions operation at index 0 contains fields which are not supported")
+
+	// The inner transaction of a fee bump transaction is checked too
+	feeBump, err := NewFeeBumpTransaction(FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: kp1.Address(),
+		BaseFee:    MinBaseFee,
+	})
+	require.NoError(t, err)
+	txeB64, err = feeBump.Base64()
+	require.NoError(t, err)
+	_, err = TransactionFromXDR(txeB64, TransactionFromXDROptionStrict)
+	assert.EqualError(t, err, "transaction envelope cannot be parsed strictly: OperationTypeSetOptions operation at index 0 contains fields which are not supported")
+
+	// Muxed fee bump accounts require muxed accounts to be enabled
+	feeBump, err = NewFeeBumpTransaction(FeeBumpTransactionParams{
+		Inner:               newInner(&BumpSequence{BumpTo: 5}),
+		FeeAccount:          "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK",
+		BaseFee:             MinBaseFee,
+		EnableMuxedAccounts: true,
+	})
+	require.NoError(t, err)
+	txeB64, err = feeBump.Base64()
+	require.NoError(t, err)
+	_, err = TransactionFromXDR(txeB64, TransactionFromXDROptionStrict)
+	assert.EqualError(t, err, "transaction envelope cannot be parsed strictly: fee bump account is a muxed account but muxed accounts are not enabled")
+	parsed, err := TransactionFromXDR(txeB64, TransactionFromXDROptionStrict, TransactionFromXDROptionEnableMuxedAccounts)
+	assert.NoError(t, err)
+	parsedFeeBump, ok := parsed.FeeBump()
+	assert.True(t, ok)
+	assert.Equal(t, "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK", parsedFeeBump.FeeAccount())
+
+	// Muxed operation source accounts require muxed accounts to be enabled
+	inner = newInner(&BumpSequence{
+		BumpTo:        5,
+		SourceAccount: "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK",
+	})
+	txeB64, err = inner.Base64()
+	require.NoError(t, err)
+	_, err = TransactionFromXDR(txeB64, TransactionFromXDROptionStrict)
+	assert.EqualError(t, err, "transaction envelope cannot be parsed strictly: source account of OperationTypeBumpSequence operation at index 0 is a muxed account but muxed accounts are not enabled")
+}
+
+func TestFromXDRPreserveMuxedOpSourceAccounts(t *testing.T) {
+	kp0 := newKeypair0()
+	muxed := "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount: &SimpleAccount{AccountID: kp0.Address(), Sequence: 1},
+			Operations: []Operation{
+				&BumpSequence{BumpTo: 5, SourceAccount: muxed},
+				&Payment{Destination: muxed, Amount: "10", Asset: NativeAsset{}},
+			},
+			BaseFee:             MinBaseFee,
+			Timebounds:          NewInfiniteTimeout(),
+			EnableMuxedAccounts: true,
+		},
+	)
+	require.NoError(t, err)
+	opSource, ok := tx.OperationSourceAccount(0)
+	require.True(t, ok)
+	assert.Equal(t, muxed, opSource.Address())
+	_, ok = tx.OperationSourceAccount(1)
+	assert.False(t, ok)
+	txeB64, err := tx.Base64()
+	require.NoError(t, err)
+
+	// By default the muxed operation source account is normalized
+	parsed, err := TransactionFromXDR(txeB64)
+	require.NoError(t, err)
+	simple, ok := parsed.Transaction()
+	require.True(t, ok)
+	assert.False(t, simple.PreservesMuxedOpSourceAccounts())
+	assert.Equal(t, "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ", simple.Operations()[0].GetSourceAccount())
+
+	parsed, err = TransactionFromXDR(txeB64, TransactionFromXDROptionPreserveMuxedOpSourceAccounts)
+	require.NoError(t, err)
+	simple, ok = parsed.Transaction()
+	require.True(t, ok)
+	assert.True(t, simple.PreservesMuxedOpSourceAccounts())
+	assert.Equal(t, muxed, simple.Operations()[0].GetSourceAccount())
+	// Only operation source accounts are preserved
+	assert.Equal(t, "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ", simple.Operations()[1].(*Payment).Destination)
+
+	sourceAccount := simple.SourceAccount()
+	rebuilt, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:                 &sourceAccount,
+			Operations:                    simple.Operations(),
+			BaseFee:                       simple.BaseFee(),
+			Timebounds:                    simple.Timebounds(),
+			PreserveMuxedOpSourceAccounts: simple.PreservesMuxedOpSourceAccounts(),
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, rebuilt.PreservesMuxedOpSourceAccounts())
+	opSource, ok = rebuilt.OperationSourceAccount(0)
+	require.True(t, ok)
+	assert.Equal(t, muxed, opSource.Address())
+}
+
+func TestMuxedAccountsRoundTrip(t *testing.T) {
+	kp1 := newKeypair1()
+	muxed := "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"
+	unmuxed := "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"
+	usd := CreditAsset{"USD", kp1.Address()}
+	operations := []Operation{
+		&Payment{Destination: muxed, Amount: "10", Asset: NativeAsset{}, SourceAccount: muxed},
+		&PathPaymentStrictReceive{SendAsset: NativeAsset{}, SendMax: "10", Destination: muxed, DestAsset: usd, DestAmount: "1", SourceAccount: muxed},
+		&PathPaymentStrictSend{SendAsset: NativeAsset{}, SendAmount: "10", Destination: muxed, DestAsset: usd, DestMin: "1", SourceAccount: muxed},
+		&Clawback{From: muxed, Amount: "1", Asset: usd, SourceAccount: muxed},
+		&BumpSequence{BumpTo: 5, SourceAccount: muxed},
+		&AccountMerge{Destination: muxed, SourceAccount: muxed},
+	}
+
+	// Muxed accounts must be enabled to use M-addresses.
+	_, err := NewTransaction(TransactionParams{
+		SourceAccount: &SimpleAccount{AccountID: kp1.Address(), Sequence: 1},
+		Operations:    []Operation{&BumpSequence{BumpTo: 5, SourceAccount: muxed}},
+		BaseFee:       MinBaseFee,
+		Timebounds:    NewInfiniteTimeout(),
+	})
+	assert.Contains(t, err.Error(), "validation failed for *txnbuild.BumpSequence operation: Field: SourceAccount")
+
+	tx, err := NewTransaction(TransactionParams{
+		SourceAccount:       &SimpleAccount{AccountID: muxed, Sequence: 1},
+		Operations:          operations,
+		BaseFee:             MinBaseFee,
+		Timebounds:          NewInfiniteTimeout(),
+		EnableMuxedAccounts: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, tx.MuxedAccountsEnabled())
+	txeB64, err := tx.Base64()
+	require.NoError(t, err)
+
+	parsed, err := TransactionFromXDR(txeB64, TransactionFromXDROptionEnableMuxedAccounts)
+	require.NoError(t, err)
+	simple, ok := parsed.Transaction()
+	require.True(t, ok)
+	assert.True(t, simple.MuxedAccountsEnabled())
+	assert.Equal(t, muxed, simple.SourceAccount().AccountID)
+	for _, op := range simple.Operations() {
+		assert.Equal(t, muxed, op.GetSourceAccount())
+	}
+	assert.Equal(t, muxed, simple.Operations()[0].(*Payment).Destination)
+	assert.Equal(t, muxed, simple.Operations()[1].(*PathPaymentStrictReceive).Destination)
+	assert.Equal(t, muxed, simple.Operations()[2].(*PathPaymentStrictSend).Destination)
+	assert.Equal(t, muxed, simple.Operations()[3].(*Clawback).From)
+	assert.Equal(t, muxed, simple.Operations()[5].(*AccountMerge).Destination)
+
+	sourceAccount := simple.SourceAccount()
+	rebuilt, err := NewTransaction(TransactionParams{
+		SourceAccount:       &sourceAccount,
+		Operations:          simple.Operations(),
+		BaseFee:             simple.BaseFee(),
+		Timebounds:          simple.Timebounds(),
+		EnableMuxedAccounts: simple.MuxedAccountsEnabled(),
+	})
+	require.NoError(t, err)
+	rebuiltB64, err := rebuilt.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, txeB64, rebuiltB64)
+
+	// Without muxed accounts the addresses are normalized.
+	parsed, err = TransactionFromXDR(txeB64)
+	require.NoError(t, err)
+	simple, ok = parsed.Transaction()
+	require.True(t, ok)
+	assert.False(t, simple.MuxedAccountsEnabled())
+	assert.Equal(t, unmuxed, simple.SourceAccount().AccountID)
+	for _, op := range simple.Operations() {
+		assert.Equal(t, unmuxed, op.GetSourceAccount())
+	}
+	assert.Equal(t, unmuxed, simple.Operations()[0].(*Payment).Destination)
+	assert.Equal(t, unmuxed, simple.Operations()[5].(*AccountMerge).Destination)
+}
+
 func TestBuild(t *testing.T) {
 	kp0 := newKeypair0()
 	sourceAccount := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
@@ -1737,6 +2116,61 @@ func TestAddSignatureBase64(t *testing.T) {
 	assert.Equal(t, expected, actual, "base64 xdr should match")
 }
 
+func TestSignaturePayloadAndAttachSignature(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	txSource := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
+	tx1Source := NewSimpleAccount(kp0.Address(), int64(9605939170639897))
+	params := func(source *SimpleAccount) TransactionParams {
+		return TransactionParams{
+			SourceAccount:        source,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&BumpSequence{BumpTo: 0}},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewInfiniteTimeout(),
+		}
+	}
+
+	expected, err := newSignedTransaction(params(&txSource), network.TestNetworkPassphrase, kp0, kp1)
+	require.NoError(t, err)
+
+	tx1, err := NewTransaction(params(&tx1Source))
+	require.NoError(t, err)
+
+	payload, err := tx1.SignaturePayload(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	hash, err := tx1.Hash(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, hash, payload)
+
+	// sign the payload offline, without the transaction
+	for _, kp := range []*keypair.Full{kp0, kp1} {
+		signature, err := kp.Sign(payload[:])
+		require.NoError(t, err)
+		require.NoError(t, kp.Verify(payload[:], signature))
+
+		tx1, err = tx1.AttachSignature(network.TestNetworkPassphrase, kp.Address(), signature)
+		require.NoError(t, err)
+	}
+
+	actual, err := tx1.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual, "base64 xdr should match")
+
+	_, err = tx1.AttachSignature(network.TestNetworkPassphrase, "GABC", make([]byte, 64))
+	assert.Contains(t, err.Error(), "failed to parse the public key GABC")
+	_, err = tx1.AttachSignature(network.TestNetworkPassphrase, kp0.Address(), make([]byte, 63))
+	assert.EqualError(t, err, "signature must be 64 bytes long, got 63")
+
+	// The signature must be made by the signer on the payload of the network.
+	signature, err := kp0.Sign(payload[:])
+	require.NoError(t, err)
+	_, err = tx1.AttachSignature(network.TestNetworkPassphrase, kp1.Address(), signature)
+	assert.EqualError(t, err, "failed to verify the signature: signature verification failed")
+	_, err = tx1.AttachSignature(network.PublicNetworkPassphrase, kp0.Address(), signature)
+	assert.EqualError(t, err, "failed to verify the signature: signature verification failed")
+}
+
 func TestReadChallengeTx_validSignedByServerAndClient(t *testing.T) {
 	serverKP := newKeypair0()
 	clientKP := newKeypair1()
@@ -2282,7 +2716,7 @@ func TestReadChallengeTx_forbidsMuxedAccounts(t *testing.T) {
 			Ed25519: *aid.Ed25519,
 		},
 	}
-	*env.V1.Tx.Operations[0].SourceAccount = muxedAccount
+	env.V1.Tx.SourceAccount = muxedAccount
 
 	challenge, err := marshallBase64(env, env.Signatures())
 	assert.NoError(t, err)
@@ -2638,6 +3072,189 @@ func TestReadChallengeTx_invalidWebAuthDomain(t *testing.T) {
 	assert.EqualError(t, err, `web auth domain operation value is "testwebauth.example.org" but expect "testwebauth.stellar.org"`)
 }
 
+func TestReadChallengeTx_validClientDomain(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	readTx, readClientAccountID, _, err := ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, clientKP.Address(), readClientAccountID)
+	clientDomain, clientDomainAccountID := ChallengeTxClientDomain(readTx)
+	assert.Equal(t, "testwallet.stellar.org", clientDomain)
+	assert.Equal(t, clientDomainKP.Address(), clientDomainAccountID)
+}
+
+func TestReadChallengeTx_invalidMultipleClientDomains(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	txSource := NewSimpleAccount(serverKP.Address(), -1)
+	op := ManageData{
+		SourceAccount: clientKP.Address(),
+		Name:          "testanchor.stellar.org auth",
+		Value:         []byte(base64.StdEncoding.EncodeToString(make([]byte, 48))),
+	}
+	clientDomainOp1 := ManageData{
+		SourceAccount: clientDomainKP.Address(),
+		Name:          "client_domain",
+		Value:         []byte("testwallet.stellar.org"),
+	}
+	clientDomainOp2 := ManageData{
+		SourceAccount: serverKP.Address(),
+		Name:          "client_domain",
+		Value:         []byte("evilwallet.stellar.org"),
+	}
+	tx64, err := newSignedTransaction(
+		TransactionParams{
+			SourceAccount:        &txSource,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&op, &clientDomainOp1, &clientDomainOp2},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewTimeout(1000),
+		},
+		network.TestNetworkPassphrase,
+		serverKP,
+	)
+	require.NoError(t, err)
+
+	_, _, _, err = ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"})
+	assert.EqualError(t, err, "transaction has more than one client domain operation")
+}
+
+func TestReadChallengeTx_invalidEmptyClientDomain(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	txSource := NewSimpleAccount(serverKP.Address(), -1)
+	op := ManageData{
+		SourceAccount: clientKP.Address(),
+		Name:          "testanchor.stellar.org auth",
+		Value:         []byte(base64.StdEncoding.EncodeToString(make([]byte, 48))),
+	}
+	clientDomainOp := ManageData{
+		SourceAccount: clientDomainKP.Address(),
+		Name:          "client_domain",
+		Value:         []byte{},
+	}
+	tx64, err := newSignedTransaction(
+		TransactionParams{
+			SourceAccount:        &txSource,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&op, &clientDomainOp},
+			BaseFee:              MinBaseFee,
+			Timebounds:           NewTimeout(1000),
+		},
+		network.TestNetworkPassphrase,
+		serverKP,
+	)
+	require.NoError(t, err)
+
+	_, _, _, err = ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"})
+	assert.EqualError(t, err, "client domain operation value cannot be empty")
+}
+
+func TestReadChallengeTx_validMemo(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	tx, err := BuildChallengeTxWithMemo(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", network.TestNetworkPassphrase, time.Minute, MemoID(1234))
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	readTx, clientAccountID, _, err := ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, clientKP.Address(), clientAccountID)
+	memo := ChallengeTxMemo(readTx)
+	require.NotNil(t, memo)
+	assert.Equal(t, MemoID(1234), *memo)
+}
+
+func TestReadChallengeTx_validMuxedClientAccount(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	muxedClient := muxedAddress(clientKP.Address(), 1234)
+	tx, err := BuildChallengeTx(serverKP.Seed(), muxedClient, "testwebauth.stellar.org", "testanchor.stellar.org", network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, clientKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	readTx, clientAccountID, _, err := ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"})
+	require.NoError(t, err)
+	assert.Equal(t, muxedClient, clientAccountID)
+	assert.Nil(t, ChallengeTxMemo(readTx))
+
+	// The challenge is signed by the signers of the underlying account.
+	signersFound, err := VerifyChallengeTxSigners(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"}, clientKP.Address())
+	require.NoError(t, err)
+	assert.Equal(t, []string{clientKP.Address()}, signersFound)
+}
+
+func TestReadChallengeTx_invalidMemoWithMuxedClientAccount(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	txSource := NewSimpleAccount(serverKP.Address(), -1)
+	op := ManageData{
+		SourceAccount: muxedAddress(clientKP.Address(), 1234),
+		Name:          "testanchor.stellar.org auth",
+		Value:         []byte(base64.StdEncoding.EncodeToString(make([]byte, 48))),
+	}
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &txSource,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&op},
+			BaseFee:              MinBaseFee,
+			Memo:                 MemoID(1234),
+			Timebounds:           NewTimeout(300),
+			EnableMuxedAccounts:  true,
+		},
+	)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, serverKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	_, _, _, err = ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"})
+	assert.EqualError(t, err, "memos are not valid for challenge transactions with a muxed client account")
+}
+
+func TestReadChallengeTx_invalidMemoType(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	txSource := NewSimpleAccount(serverKP.Address(), -1)
+	op := ManageData{
+		SourceAccount: clientKP.Address(),
+		Name:          "testanchor.stellar.org auth",
+		Value:         []byte(base64.StdEncoding.EncodeToString(make([]byte, 48))),
+	}
+	tx, err := NewTransaction(
+		TransactionParams{
+			SourceAccount:        &txSource,
+			IncrementSequenceNum: true,
+			Operations:           []Operation{&op},
+			BaseFee:              MinBaseFee,
+			Memo:                 MemoText("1234"),
+			Timebounds:           NewTimeout(300),
+		},
+	)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, serverKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	_, _, _, err = ReadChallengeTx(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"})
+	assert.EqualError(t, err, "invalid memo, only ID memos are permitted")
+}
+
 func TestVerifyChallengeTxThreshold_invalidServer(t *testing.T) {
 	serverKP := newKeypair0()
 	clientKP := newKeypair1()
@@ -3407,6 +4024,32 @@ func TestVerifyChallengeTxThreshold_invalidWebAuthDomain(t *testing.T) {
 	assert.EqualError(t, err, `web auth domain operation value is "testwebauth.example.org" but expect "testwebauth.stellar.org"`)
 }
 
+func TestVerifyChallengeTxThreshold_clientDomainSignerDoesNotMeetThreshold(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, clientKP, clientDomainKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	// The client domain signing key is also a signer of the account, but its
+	// signature only attributes the client to the client domain.
+	signerSummary := SignerSummary{
+		clientKP.Address():       1,
+		clientDomainKP.Address(): 1,
+	}
+
+	signersFound, err := VerifyChallengeTxThreshold(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"}, Threshold(1), signerSummary)
+	require.NoError(t, err)
+	assert.Equal(t, []string{clientKP.Address()}, signersFound)
+
+	_, err = VerifyChallengeTxThreshold(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"}, Threshold(2), signerSummary)
+	assert.EqualError(t, err, "signers with weight 1 do not meet threshold 2")
+}
+
 func TestVerifyChallengeTxSigners_invalidServer(t *testing.T) {
 	serverKP := newKeypair0()
 	clientKP := newKeypair1()
@@ -4260,6 +4903,52 @@ func TestVerifyChallengeTxSigners_invalidWebAuthDomain(t *testing.T) {
 	assert.EqualError(t, err, `web auth domain operation value is "testwebauth.example.org" but expect "testwebauth.stellar.org"`)
 }
 
+func TestVerifyChallengeTxSigners_validClientDomain(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, clientDomainKP, clientKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	signersFound, err := VerifyChallengeTxSigners(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"}, clientKP.Address())
+	require.NoError(t, err)
+	assert.Equal(t, []string{clientKP.Address()}, signersFound)
+}
+
+func TestVerifyChallengeTxSigners_invalidNotSignedByClientDomain(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, clientKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	_, err = VerifyChallengeTxSigners(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"}, clientKP.Address())
+	assert.EqualError(t, err, "transaction not signed by client domain signing key "+clientDomainKP.Address())
+}
+
+func TestVerifyChallengeTxSigners_invalidOnlySignedByClientDomain(t *testing.T) {
+	serverKP := newKeypair0()
+	clientKP := newKeypair1()
+	clientDomainKP := newKeypair2()
+	tx, err := BuildChallengeTxWithClientDomain(serverKP.Seed(), clientKP.Address(), "testwebauth.stellar.org", "testanchor.stellar.org", "testwallet.stellar.org", clientDomainKP.Address(), network.TestNetworkPassphrase, time.Minute)
+	require.NoError(t, err)
+	tx, err = tx.Sign(network.TestNetworkPassphrase, clientDomainKP)
+	require.NoError(t, err)
+	tx64, err := tx.Base64()
+	require.NoError(t, err)
+
+	_, err = VerifyChallengeTxSigners(tx64, serverKP.Address(), network.TestNetworkPassphrase, "testwebauth.stellar.org", []string{"testanchor.stellar.org"}, clientKP.Address(), clientDomainKP.Address())
+	assert.EqualError(t, err, "transaction not signed by "+clientKP.Address())
+}
+
 func TestVerifyTxSignatureUnsignedTx(t *testing.T) {
 	kp0 := newKeypair0()
 	kp1 := newKeypair1()
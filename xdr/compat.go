@@ -0,0 +1,155 @@
+package xdr
+
+import "fmt"
+
+// The functions of this file upgrade XDR structures written by older protocol
+// versions, which are still found in history archives and databases, to the
+// latest version of the structures. Older versions are decoded by the current
+// structures, as new versions are added as union arms or extensions, but code
+// reading them would otherwise have to handle every version. Upgrading does
+// not change the meaning of the structures, e.g. the hash and signatures of an
+// upgraded transaction envelope are the same as the original ones.
+
+// SafeUnmarshalBase64Compat is SafeUnmarshalBase64 followed by Upgrade.
+func SafeUnmarshalBase64Compat(data string, dest interface{}) error {
+	if err := SafeUnmarshalBase64(data, dest); err != nil {
+		return err
+	}
+	return Upgrade(dest)
+}
+
+// SafeUnmarshalCompat is SafeUnmarshal followed by Upgrade.
+func SafeUnmarshalCompat(data []byte, dest interface{}) error {
+	if err := SafeUnmarshal(data, dest); err != nil {
+		return err
+	}
+	return Upgrade(dest)
+}
+
+// Upgrade upgrades in place the structure pointed to by v, which can be a
+// *TransactionEnvelope, *TransactionMeta, *LedgerEntry or *AccountEntry, to
+// its latest version. Other structures are left unchanged.
+func Upgrade(v interface{}) error {
+	switch v := v.(type) {
+	case *TransactionEnvelope:
+		upgraded, err := UpgradeTransactionEnvelope(*v)
+		if err != nil {
+			return err
+		}
+		*v = upgraded
+	case *TransactionMeta:
+		upgraded, err := UpgradeTransactionMeta(*v)
+		if err != nil {
+			return err
+		}
+		*v = upgraded
+	case *LedgerEntry:
+		*v = UpgradeLedgerEntry(*v)
+	case *AccountEntry:
+		*v = UpgradeAccountEntry(*v)
+	}
+	return nil
+}
+
+// UpgradeTransactionEnvelope converts ENVELOPE_TYPE_TX_V0 envelopes, the
+// format of transactions before protocol 13, to ENVELOPE_TYPE_TX envelopes.
+// The signatures remain valid as the hash of a V0 transaction is the hash of
+// its conversion. Other envelopes are returned unchanged.
+func UpgradeTransactionEnvelope(e TransactionEnvelope) (TransactionEnvelope, error) {
+	switch e.Type {
+	case EnvelopeTypeEnvelopeTypeTxV0:
+		v0 := e.MustV0()
+		sourceAccount := v0.Tx.SourceAccountEd25519
+		return TransactionEnvelope{
+			Type: EnvelopeTypeEnvelopeTypeTx,
+			V1: &TransactionV1Envelope{
+				Tx: Transaction{
+					SourceAccount: MuxedAccount{
+						Type:    CryptoKeyTypeKeyTypeEd25519,
+						Ed25519: &sourceAccount,
+					},
+					Fee:        v0.Tx.Fee,
+					SeqNum:     v0.Tx.SeqNum,
+					TimeBounds: v0.Tx.TimeBounds,
+					Memo:       v0.Tx.Memo,
+					Operations: v0.Tx.Operations,
+				},
+				Signatures: v0.Signatures,
+			},
+		}, nil
+	case EnvelopeTypeEnvelopeTypeTx, EnvelopeTypeEnvelopeTypeTxFeeBump:
+		return e, nil
+	default:
+		return e, fmt.Errorf("unknown envelope type: %v", e.Type)
+	}
+}
+
+// UpgradeTransactionMeta converts transaction meta of version 0, written
+// before protocol 10, and version 1, written before protocol 13, to version
+// 2. The changes of version 1 happen before the operations are applied, as
+// the changes of the fee bump transactions of version 2 which can happen
+// after them did not exist.
+func UpgradeTransactionMeta(meta TransactionMeta) (TransactionMeta, error) {
+	switch meta.V {
+	case 0:
+		return TransactionMeta{
+			V: 2,
+			V2: &TransactionMetaV2{
+				Operations: meta.MustOperations(),
+			},
+		}, nil
+	case 1:
+		v1 := meta.MustV1()
+		return TransactionMeta{
+			V: 2,
+			V2: &TransactionMetaV2{
+				TxChangesBefore: v1.TxChanges,
+				Operations:      v1.Operations,
+			},
+		}, nil
+	case 2:
+		return meta, nil
+	default:
+		return meta, fmt.Errorf("unknown transaction meta version: %d", meta.V)
+	}
+}
+
+// UpgradeLedgerEntry adds the version 1 extension of ledger entries,
+// introduced in protocol 14, to entries written before, with no sponsor. The
+// account entries are upgraded with UpgradeAccountEntry.
+func UpgradeLedgerEntry(entry LedgerEntry) LedgerEntry {
+	if entry.Ext.V == 0 {
+		entry.Ext = LedgerEntryExt{
+			V:  1,
+			V1: &LedgerEntryExtensionV1{},
+		}
+	}
+	if entry.Data.Type == LedgerEntryTypeAccount {
+		account := UpgradeAccountEntry(entry.Data.MustAccount())
+		entry.Data.Account = &account
+	}
+	return entry
+}
+
+// UpgradeAccountEntry adds the version 1 extension of account entries,
+// introduced in protocol 10, with no liabilities, and its version 2 extension,
+// introduced in protocol 14, with no sponsorships, to entries written before.
+func UpgradeAccountEntry(account AccountEntry) AccountEntry {
+	if account.Ext.V == 0 {
+		account.Ext = AccountEntryExt{
+			V:  1,
+			V1: &AccountEntryExtensionV1{},
+		}
+	}
+	if account.Ext.V1.Ext.V == 0 {
+		v1 := *account.Ext.V1
+		v1.Ext = AccountEntryExtensionV1Ext{
+			V: 2,
+			V2: &AccountEntryExtensionV2{
+				SignerSponsoringIDs: make([]SponsorshipDescriptor, len(account.Signers)),
+			},
+		}
+		account.Ext.V1 = &v1
+	}
+	return account
+}
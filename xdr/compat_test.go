@@ -0,0 +1,143 @@
+package xdr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpgradeTransactionEnvelope(t *testing.T) {
+	source := Uint256{1, 2, 3}
+	v0 := TransactionEnvelope{
+		Type: EnvelopeTypeEnvelopeTypeTxV0,
+		V0: &TransactionV0Envelope{
+			Tx: TransactionV0{
+				SourceAccountEd25519: source,
+				Fee:                  100,
+				SeqNum:               42,
+				TimeBounds:           &TimeBounds{MinTime: 1, MaxTime: 2},
+				Memo:                 Memo{Type: MemoTypeMemoNone},
+				Operations: []Operation{{
+					Body: OperationBody{
+						Type:           OperationTypeBumpSequence,
+						BumpSequenceOp: &BumpSequenceOp{BumpTo: 43},
+					},
+				}},
+			},
+			Signatures: []DecoratedSignature{{Hint: SignatureHint{1, 2, 3, 4}, Signature: Signature{5}}},
+		},
+	}
+	b64, err := MarshalBase64(v0)
+	require.NoError(t, err)
+
+	var upgraded TransactionEnvelope
+	require.NoError(t, SafeUnmarshalBase64Compat(b64, &upgraded))
+	assert.Equal(t, EnvelopeTypeEnvelopeTypeTx, upgraded.Type)
+	assert.Equal(t, v0.SourceAccount(), upgraded.SourceAccount())
+	assert.Equal(t, v0.Fee(), upgraded.Fee())
+	assert.Equal(t, v0.SeqNum(), upgraded.SeqNum())
+	assert.Equal(t, v0.TimeBounds(), upgraded.TimeBounds())
+	assert.Equal(t, v0.Memo(), upgraded.Memo())
+	assert.Equal(t, v0.Operations(), upgraded.Operations())
+	assert.Equal(t, v0.Signatures(), upgraded.Signatures())
+
+	same, err := UpgradeTransactionEnvelope(upgraded)
+	require.NoError(t, err)
+	assert.Equal(t, upgraded, same)
+
+	_, err = UpgradeTransactionEnvelope(TransactionEnvelope{Type: EnvelopeTypeEnvelopeTypeScp})
+	assert.EqualError(t, err, "unknown envelope type: EnvelopeTypeEnvelopeTypeScp")
+}
+
+func TestUpgradeTransactionMeta(t *testing.T) {
+	changes := LedgerEntryChanges{{
+		Type:    LedgerEntryChangeTypeLedgerEntryRemoved,
+		Removed: &LedgerKey{Type: LedgerEntryTypeAccount, Account: &LedgerKeyAccount{AccountId: MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB")}},
+	}}
+	operations := []OperationMeta{{Changes: changes}}
+
+	v0 := TransactionMeta{V: 0, Operations: &operations}
+	b, err := v0.MarshalBinary()
+	require.NoError(t, err)
+	var upgraded TransactionMeta
+	require.NoError(t, SafeUnmarshalCompat(b, &upgraded))
+	assert.Equal(t, int32(2), upgraded.V)
+	assert.Equal(t, operations, upgraded.MustV2().Operations)
+	assert.Empty(t, upgraded.MustV2().TxChangesBefore)
+	assert.Empty(t, upgraded.MustV2().TxChangesAfter)
+
+	upgraded, err = UpgradeTransactionMeta(TransactionMeta{
+		V:  1,
+		V1: &TransactionMetaV1{TxChanges: changes, Operations: operations},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TransactionMeta{
+		V: 2,
+		V2: &TransactionMetaV2{
+			TxChangesBefore: changes,
+			Operations:      operations,
+		},
+	}, upgraded)
+
+	v2 := TransactionMeta{V: 2, V2: &TransactionMetaV2{TxChangesAfter: changes}}
+	upgraded, err = UpgradeTransactionMeta(v2)
+	require.NoError(t, err)
+	assert.Equal(t, v2, upgraded)
+
+	_, err = UpgradeTransactionMeta(TransactionMeta{V: 3})
+	assert.EqualError(t, err, "unknown transaction meta version: 3")
+}
+
+func TestUpgradeLedgerEntry(t *testing.T) {
+	account := AccountEntry{
+		AccountId: MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB"),
+		Balance:   100,
+		Signers: []Signer{
+			{Key: MustSigner("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB"), Weight: 1},
+		},
+	}
+	entry := LedgerEntry{
+		LastModifiedLedgerSeq: 10,
+		Data: LedgerEntryData{
+			Type:    LedgerEntryTypeAccount,
+			Account: &account,
+		},
+	}
+	b64, err := MarshalBase64(entry)
+	require.NoError(t, err)
+
+	var upgraded LedgerEntry
+	require.NoError(t, SafeUnmarshalBase64Compat(b64, &upgraded))
+	assert.Equal(t, int32(1), upgraded.Ext.V)
+	assert.Nil(t, upgraded.Ext.V1.SponsoringId)
+
+	upgradedAccount := upgraded.Data.MustAccount()
+	assert.Equal(t, account.Balance, upgradedAccount.Balance)
+	assert.Equal(t, Liabilities{}, upgradedAccount.Liabilities())
+	assert.Equal(t, Uint32(0), upgradedAccount.NumSponsored())
+	assert.Equal(t, Uint32(0), upgradedAccount.NumSponsoring())
+	assert.Equal(t, []SponsorshipDescriptor{nil}, upgradedAccount.Ext.V1.Ext.V2.SignerSponsoringIDs)
+
+	// the original entry is not modified
+	assert.Equal(t, int32(0), entry.Ext.V)
+	assert.Equal(t, int32(0), account.Ext.V)
+
+	// upgraded entries are unchanged
+	assert.Equal(t, upgraded, UpgradeLedgerEntry(upgraded))
+}
+
+func TestUpgradeAccountEntryKeepsLiabilities(t *testing.T) {
+	account := AccountEntry{
+		Ext: AccountEntryExt{
+			V: 1,
+			V1: &AccountEntryExtensionV1{
+				Liabilities: Liabilities{Buying: 1, Selling: 2},
+			},
+		},
+	}
+	upgraded := UpgradeAccountEntry(account)
+	assert.Equal(t, Liabilities{Buying: 1, Selling: 2}, upgraded.Liabilities())
+	assert.Equal(t, int32(2), upgraded.Ext.V1.Ext.V)
+	assert.Equal(t, int32(0), account.Ext.V1.Ext.V)
+}
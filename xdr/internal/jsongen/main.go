@@ -0,0 +1,339 @@
+// jsongen generates the JSON marshaling methods of the types of the xdr
+// package from the XDR definitions quoted in the doc comments of
+// xdr_generated.go, so that the JSON of every type uses the field names and
+// enum constant names of the .x files.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// skipped are the types which keep the JSON encoding of their Go struct when
+// they are marshaled on their own, because it is part of Horizon's API. They
+// are still encoded with their XDR field names when nested in other types.
+var skipped = map[string]bool{
+	"Price": true,
+}
+
+var reDefinition = regexp.MustCompile(`^(\w+) is an XDR (\w+) defines as:$`)
+
+var reBlockComment = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+type xdrType struct {
+	name       string
+	kind       string
+	definition string
+	spec       ast.Expr
+}
+
+func main() {
+	input := flag.String("i", "xdr_generated.go", "file generated by xdrgen")
+	output := flag.String("o", "xdr_json_generated.go", "output file")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *input, nil, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handwritten, err := handwrittenMarshalers(fset, filepath.Dir(*input), *input, *output)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	types, consts := collectTypes(file)
+	kinds := map[string]string{}
+	for _, t := range types {
+		kinds[t.name] = t.kind
+	}
+
+	var fieldNames, enumNames, typedefs, methods bytes.Buffer
+	for _, t := range types {
+		switch t.kind {
+		case "Enum":
+			names, err := enumConstantNames(t, consts[t.name])
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Fprintf(&enumNames, "\treflect.TypeOf(%s(0)): {\n", t.name)
+			for i, c := range consts[t.name] {
+				fmt.Fprintf(&enumNames, "\t\tint32(%s): %q,\n", c, names[i])
+			}
+			fmt.Fprintf(&enumNames, "\t},\n")
+		case "Struct", "NestedStruct", "Union", "NestedUnion":
+			names, err := structFieldNames(t)
+			if err != nil {
+				log.Fatal(err)
+			}
+			fmt.Fprintf(&fieldNames, "\treflect.TypeOf(%s{}): {%s},\n", t.name, quoteAll(names))
+		case "Typedef":
+			if ident, ok := t.spec.(*ast.Ident); ok && isComposite(kinds[ident.Name]) {
+				fmt.Fprintf(&typedefs, "\treflect.TypeOf(%s{}): reflect.TypeOf(%s{}),\n", t.name, ident.Name)
+			}
+		default:
+			continue
+		}
+
+		if skipped[t.name] || handwritten[t.name] || !hasJSONMethods(t, kinds) {
+			continue
+		}
+		fmt.Fprintf(&methods, `
+// MarshalJSON implements json.Marshaler.
+func (s %[1]s) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *%[1]s) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+`, t.name)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by jsongen from %s. DO NOT EDIT.\n\n", filepath.Base(*input))
+	fmt.Fprintf(&out, "package xdr\n\nimport \"reflect\"\n\n")
+	fmt.Fprintf(&out, "// jsonFieldNames are the XDR names of the fields of the structs and unions.\n")
+	fmt.Fprintf(&out, "var jsonFieldNames = map[reflect.Type][]string{\n%s}\n\n", fieldNames.String())
+	fmt.Fprintf(&out, "// jsonEnumNames are the XDR names of the constants of the enums.\n")
+	fmt.Fprintf(&out, "var jsonEnumNames = map[reflect.Type]map[int32]string{\n%s}\n\n", enumNames.String())
+	fmt.Fprintf(&out, "// jsonTypedefs are the types the typedefs of structs and unions are\n")
+	fmt.Fprintf(&out, "// converted to to be encoded.\n")
+	fmt.Fprintf(&out, "var jsonTypedefs = map[reflect.Type]reflect.Type{\n%s}\n", typedefs.String())
+	out.Write(methods.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// collectTypes returns the types defined in file with an XDR definition, in
+// order, and the constants of each enum.
+func collectTypes(file *ast.File) ([]xdrType, map[string][]string) {
+	var types []xdrType
+	consts := map[string][]string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				if gen.Doc == nil {
+					continue
+				}
+				// The definition may follow other comments, like a
+				// deprecation notice.
+				lines := strings.Split(gen.Doc.Text(), "\n")
+				for i, line := range lines {
+					m := reDefinition.FindStringSubmatch(line)
+					if m == nil || m[1] != spec.Name.Name {
+						continue
+					}
+					types = append(types, xdrType{
+						name:       spec.Name.Name,
+						kind:       m[2],
+						definition: strings.Join(lines[i+1:], "\n"),
+						spec:       spec.Type,
+					})
+					break
+				}
+			case *ast.ValueSpec:
+				if ident, ok := spec.Type.(*ast.Ident); ok && gen.Tok == token.CONST {
+					for _, name := range spec.Names {
+						consts[ident.Name] = append(consts[ident.Name], name.Name)
+					}
+				}
+			}
+		}
+	}
+	return types, consts
+}
+
+// handwrittenMarshalers returns the types of the package which already have a
+// MarshalJSON method outside of the generated files.
+func handwrittenMarshalers(fset *token.FileSet, dir string, generated ...string) (map[string]bool, error) {
+	skip := map[string]bool{}
+	for _, name := range generated {
+		skip[filepath.Base(name)] = true
+	}
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !skip[fi.Name()] && !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	types := map[string]bool{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || fn.Name.Name != "MarshalJSON" {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				if ident, ok := recv.(*ast.Ident); ok {
+					types[ident.Name] = true
+				}
+			}
+		}
+	}
+	return types, nil
+}
+
+func isComposite(kind string) bool {
+	switch kind {
+	case "Struct", "NestedStruct", "Union", "NestedUnion":
+		return true
+	}
+	return false
+}
+
+// hasJSONMethods returns whether MarshalJSON and UnmarshalJSON are generated
+// for t. Typedefs of numbers and strings keep their default encoding, as
+// their values are used as is in JSON documents such as Horizon's operation
+// details.
+func hasJSONMethods(t xdrType, kinds map[string]string) bool {
+	if t.kind != "Typedef" {
+		return true
+	}
+	switch spec := t.spec.(type) {
+	case *ast.Ident:
+		return kinds[spec.Name] != "" && kinds[spec.Name] != "Typedef"
+	case *ast.ArrayType:
+		return true
+	}
+	return false
+}
+
+// xdrToken is a token of an XDR definition.
+type xdrToken struct {
+	text  string
+	ident bool
+}
+
+// tokenize splits an XDR definition into identifiers, numbers and
+// punctuation, dropping its comments.
+func tokenize(definition string) []xdrToken {
+	var tokens []xdrToken
+	definition = reBlockComment.ReplaceAllString(definition, " ")
+	for _, line := range strings.Split(definition, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		runes := []rune(line)
+		for i := 0; i < len(runes); {
+			r := runes[i]
+			switch {
+			case unicode.IsSpace(r):
+				i++
+			case unicode.IsLetter(r) || r == '_' || unicode.IsDigit(r):
+				j := i
+				for j < len(runes) && (unicode.IsLetter(runes[j]) || runes[j] == '_' || unicode.IsDigit(runes[j])) {
+					j++
+				}
+				text := string(runes[i:j])
+				tokens = append(tokens, xdrToken{text: text, ident: !unicode.IsDigit(r)})
+				i = j
+			default:
+				tokens = append(tokens, xdrToken{text: string(r)})
+				i++
+			}
+		}
+	}
+	return tokens
+}
+
+// structFieldNames returns the XDR names of the fields of a struct or union,
+// matching the Go field names which xdrgen derives from them.
+func structFieldNames(t xdrType) ([]string, error) {
+	st, ok := t.spec.(*ast.StructType)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a Go struct", t.name)
+	}
+
+	// declarators are the identifiers followed by the end of a declaration,
+	// an array size or the end of a union's discriminant.
+	tokens := tokenize(t.definition)
+	var declarators []string
+	for i := 0; i+1 < len(tokens); i++ {
+		switch tokens[i+1].text {
+		case ";", "<", "[", ")":
+			if tokens[i].ident && tokens[i].text != "void" {
+				declarators = append(declarators, tokens[i].text)
+			}
+		}
+	}
+
+	var names []string
+	for _, field := range st.Fields.List {
+		for _, fieldName := range field.Names {
+			var found []string
+			for _, d := range declarators {
+				if strings.EqualFold(strings.Replace(d, "_", "", -1), fieldName.Name) && !contains(found, d) {
+					found = append(found, d)
+				}
+			}
+			if len(found) != 1 {
+				return nil, fmt.Errorf("%s.%s matches XDR fields %v", t.name, fieldName.Name, found)
+			}
+			names = append(names, found[0])
+		}
+	}
+	return names, nil
+}
+
+// enumConstantNames returns the XDR names of the constants of an enum, in
+// the order of consts.
+func enumConstantNames(t xdrType, consts []string) ([]string, error) {
+	tokens := tokenize(t.definition)
+	var names []string
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].ident && tokens[i+1].text == "=" {
+			names = append(names, tokens[i].text)
+		}
+	}
+	if len(names) != len(consts) {
+		return nil, fmt.Errorf("enum %s has %d constants but %d XDR names", t.name, len(consts), len(names))
+	}
+	return names, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,197 @@
+package xdr
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// LedgerEntryFieldChange is a field of a ledger entry whose value differs
+// between two versions of the entry.
+type LedgerEntryFieldChange struct {
+	// Field is the name of the field in the XDR definition of the entry, e.g.
+	// "balance" or "flags". The fields of the extensions are named after the
+	// extension, e.g. "liabilities.buying", and the entries of lists after the
+	// list and the key of the entry, e.g. "signers[GABC...]".
+	Field string
+	// Before and After are the values of the field, or nil if the field did
+	// not exist, e.g. for a signer which was added or removed. Accounts are
+	// given by their address.
+	Before interface{}
+	After  interface{}
+}
+
+// DiffLedgerEntry returns the fields of the ledger entry which changed
+// between before and after, in the order of the XDR definition of the entry.
+// The entries must have the same ledger key. Extensions which are not present
+// are treated as present with their default values, so that upgrading an
+// entry to a new extension version is not reported as a change.
+func DiffLedgerEntry(before, after LedgerEntry) ([]LedgerEntryFieldChange, error) {
+	beforeKey, err := before.LedgerKey().MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling the ledger key of before")
+	}
+	afterKey, err := after.LedgerKey().MarshalBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling the ledger key of after")
+	}
+	if !bytes.Equal(beforeKey, afterKey) {
+		return nil, errors.New("the ledger entries have different ledger keys")
+	}
+
+	var d ledgerEntryDiff
+	d.add("lastModifiedLedgerSeq", before.LastModifiedLedgerSeq, after.LastModifiedLedgerSeq)
+
+	switch before.Data.Type {
+	case LedgerEntryTypeAccount:
+		d.diffAccount(before.Data.MustAccount(), after.Data.MustAccount())
+	case LedgerEntryTypeTrustline:
+		d.diffTrustLine(before.Data.MustTrustLine(), after.Data.MustTrustLine())
+	case LedgerEntryTypeOffer:
+		d.diffOffer(before.Data.MustOffer(), after.Data.MustOffer())
+	case LedgerEntryTypeData:
+		d.diffData(before.Data.MustData(), after.Data.MustData())
+	case LedgerEntryTypeClaimableBalance:
+		if err := d.diffClaimableBalance(before.Data.MustClaimableBalance(), after.Data.MustClaimableBalance()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Unknown entry type: %v", before.Data.Type)
+	}
+	d.add("sponsoringID", addressOrNil(before.SponsoringID()), addressOrNil(after.SponsoringID()))
+	return d.changes, nil
+}
+
+type ledgerEntryDiff struct {
+	changes []LedgerEntryFieldChange
+}
+
+// add records the field if its values differ. The values must be
+// comparable.
+func (d *ledgerEntryDiff) add(field string, before, after interface{}) {
+	if before != after {
+		d.changes = append(d.changes, LedgerEntryFieldChange{Field: field, Before: before, After: after})
+	}
+}
+
+func (d *ledgerEntryDiff) diffAccount(before, after AccountEntry) {
+	d.add("balance", before.Balance, after.Balance)
+	d.add("seqNum", before.SeqNum, after.SeqNum)
+	d.add("numSubEntries", before.NumSubEntries, after.NumSubEntries)
+	d.add("inflationDest", addressOrNil(before.InflationDest), addressOrNil(after.InflationDest))
+	d.add("flags", AccountFlags(before.Flags), AccountFlags(after.Flags))
+	d.add("homeDomain", before.HomeDomain, after.HomeDomain)
+	d.add("thresholds", before.Thresholds, after.Thresholds)
+
+	beforeSigners, beforeSponsors := accountSigners(before)
+	afterSigners, afterSponsors := accountSigners(after)
+	for _, key := range sortedKeys(beforeSigners, afterSigners) {
+		d.add("signers["+key+"]", beforeSigners[key], afterSigners[key])
+	}
+
+	beforeLiabilities, afterLiabilities := before.Liabilities(), after.Liabilities()
+	d.add("liabilities.buying", beforeLiabilities.Buying, afterLiabilities.Buying)
+	d.add("liabilities.selling", beforeLiabilities.Selling, afterLiabilities.Selling)
+	d.add("numSponsored", before.NumSponsored(), after.NumSponsored())
+	d.add("numSponsoring", before.NumSponsoring(), after.NumSponsoring())
+	for _, key := range sortedKeys(beforeSponsors, afterSponsors) {
+		d.add("signerSponsoringIDs["+key+"]", beforeSponsors[key], afterSponsors[key])
+	}
+}
+
+func (d *ledgerEntryDiff) diffTrustLine(before, after TrustLineEntry) {
+	d.add("balance", before.Balance, after.Balance)
+	d.add("limit", before.Limit, after.Limit)
+	d.add("flags", TrustLineFlags(before.Flags), TrustLineFlags(after.Flags))
+
+	beforeLiabilities, afterLiabilities := before.Liabilities(), after.Liabilities()
+	d.add("liabilities.buying", beforeLiabilities.Buying, afterLiabilities.Buying)
+	d.add("liabilities.selling", beforeLiabilities.Selling, afterLiabilities.Selling)
+}
+
+func (d *ledgerEntryDiff) diffOffer(before, after OfferEntry) {
+	d.add("amount", before.Amount, after.Amount)
+	d.add("price", before.Price, after.Price)
+	d.add("flags", OfferEntryFlags(before.Flags), OfferEntryFlags(after.Flags))
+}
+
+func (d *ledgerEntryDiff) diffData(before, after DataEntry) {
+	if !bytes.Equal(before.DataValue, after.DataValue) {
+		d.changes = append(d.changes, LedgerEntryFieldChange{
+			Field:  "dataValue",
+			Before: before.DataValue,
+			After:  after.DataValue,
+		})
+	}
+}
+
+func (d *ledgerEntryDiff) diffClaimableBalance(before, after ClaimableBalanceEntry) error {
+	d.add("amount", before.Amount, after.Amount)
+
+	// The claimants of a claimable balance can't change, but are compared
+	// for completeness.
+	beforeClaimants, err := MarshalBase64(before.Claimants)
+	if err != nil {
+		return errors.Wrap(err, "marshaling the claimants of before")
+	}
+	afterClaimants, err := MarshalBase64(after.Claimants)
+	if err != nil {
+		return errors.Wrap(err, "marshaling the claimants of after")
+	}
+	if beforeClaimants != afterClaimants {
+		d.changes = append(d.changes, LedgerEntryFieldChange{
+			Field:  "claimants",
+			Before: before.Claimants,
+			After:  after.Claimants,
+		})
+	}
+
+	d.add("flags", before.Flags(), after.Flags())
+	return nil
+}
+
+// accountSigners returns the weights and the sponsors of the signers of the
+// account, by signer address. The signers without a sponsor are omitted from
+// the sponsors.
+func accountSigners(account AccountEntry) (map[string]interface{}, map[string]interface{}) {
+	weights := map[string]interface{}{}
+	sponsors := map[string]interface{}{}
+	var sponsoringIDs []SponsorshipDescriptor
+	if account.Ext.V1 != nil && account.Ext.V1.Ext.V2 != nil {
+		sponsoringIDs = account.Ext.V1.Ext.V2.SignerSponsoringIDs
+	}
+	for i, signer := range account.Signers {
+		address := signer.Key.Address()
+		weights[address] = signer.Weight
+		if i < len(sponsoringIDs) && sponsoringIDs[i] != nil {
+			sponsors[address] = addressOrNil(sponsoringIDs[i])
+		}
+	}
+	return weights, sponsors
+}
+
+// addressOrNil returns the address of the account, or nil if there is no
+// account.
+func addressOrNil(account *AccountId) interface{} {
+	if account == nil {
+		return nil
+	}
+	return account.Address()
+}
+
+// sortedKeys returns the keys of both maps, sorted.
+func sortedKeys(a, b map[string]interface{}) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package xdr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLedgerEntryAccount(t *testing.T) {
+	account := MustAddress("GCO26ZSBD63TKYX45H2C7D2WOFWOUSG5BMTNC3BG4QMXM3PAYI6WHKVZ")
+	sponsor := MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML")
+	signerA := "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A"
+	signerB := "GBFLTCDLOE6YQ74B66RH3S2UW5I2MKZ5VLTM75F4YMIWUIXRIFVNRNIF"
+	signerC := "GDEOVUDLCYTO46D6GD6WH7BFESPBV5RACC6F6NUFCIRU7PL2XONQHVGJ"
+
+	before := LedgerEntry{
+		LastModifiedLedgerSeq: 10,
+		Data: LedgerEntryData{
+			Type: LedgerEntryTypeAccount,
+			Account: &AccountEntry{
+				AccountId:     account,
+				Balance:       100,
+				SeqNum:        1,
+				NumSubEntries: 2,
+				Thresholds:    Thresholds{1, 0, 0, 0},
+				Signers: []Signer{
+					{Key: MustSigner(signerA), Weight: 1},
+					{Key: MustSigner(signerB), Weight: 1},
+				},
+			},
+		},
+	}
+	after := LedgerEntry{
+		LastModifiedLedgerSeq: 20,
+		Data: LedgerEntryData{
+			Type: LedgerEntryTypeAccount,
+			Account: &AccountEntry{
+				AccountId:     account,
+				Balance:       50,
+				SeqNum:        1,
+				NumSubEntries: 2,
+				Flags:         Uint32(AccountFlagsAuthRequiredFlag),
+				HomeDomain:    "example.com",
+				Thresholds:    Thresholds{1, 0, 0, 0},
+				Signers: []Signer{
+					{Key: MustSigner(signerB), Weight: 2},
+					{Key: MustSigner(signerC), Weight: 1},
+				},
+				Ext: AccountEntryExt{
+					V: 1,
+					V1: &AccountEntryExtensionV1{
+						Liabilities: Liabilities{Buying: 0, Selling: 10},
+						Ext: AccountEntryExtensionV1Ext{
+							V: 2,
+							V2: &AccountEntryExtensionV2{
+								NumSponsored:        1,
+								SignerSponsoringIDs: []SponsorshipDescriptor{nil, &sponsor},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	changes, err := DiffLedgerEntry(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerEntryFieldChange{
+		{Field: "lastModifiedLedgerSeq", Before: Uint32(10), After: Uint32(20)},
+		{Field: "balance", Before: Int64(100), After: Int64(50)},
+		{Field: "flags", Before: AccountFlags(0), After: AccountFlagsAuthRequiredFlag},
+		{Field: "homeDomain", Before: String32(""), After: String32("example.com")},
+		{Field: "signers[" + signerA + "]", Before: Uint32(1), After: nil},
+		{Field: "signers[" + signerB + "]", Before: Uint32(1), After: Uint32(2)},
+		{Field: "signers[" + signerC + "]", Before: nil, After: Uint32(1)},
+		{Field: "liabilities.selling", Before: Int64(0), After: Int64(10)},
+		{Field: "numSponsored", Before: Uint32(0), After: Uint32(1)},
+		{Field: "signerSponsoringIDs[" + signerC + "]", Before: nil, After: sponsor.Address()},
+	}, changes)
+
+	// An entry has no changes from itself.
+	changes, err = DiffLedgerEntry(after, after)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffLedgerEntryTrustLineAndData(t *testing.T) {
+	account := MustAddress("GCO26ZSBD63TKYX45H2C7D2WOFWOUSG5BMTNC3BG4QMXM3PAYI6WHKVZ")
+	sponsor := MustAddress("GC3C4AKRBQLHOJ45U4XG35ESVWRDECWO5XLDGYADO6DPR3L7KIDVUMML")
+	asset := MustNewCreditAsset("USD", "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A")
+
+	before := LedgerEntry{
+		Data: LedgerEntryData{
+			Type: LedgerEntryTypeTrustline,
+			TrustLine: &TrustLineEntry{
+				AccountId: account,
+				Asset:     asset,
+				Balance:   10,
+				Limit:     100,
+			},
+		},
+	}
+	// The liabilities extension with zero liabilities is not a change.
+	after := LedgerEntry{
+		Data: LedgerEntryData{
+			Type: LedgerEntryTypeTrustline,
+			TrustLine: &TrustLineEntry{
+				AccountId: account,
+				Asset:     asset,
+				Balance:   10,
+				Limit:     100,
+				Flags:     Uint32(TrustLineFlagsAuthorizedFlag),
+				Ext:       TrustLineEntryExt{V: 1, V1: &TrustLineEntryV1{}},
+			},
+		},
+		Ext: LedgerEntryExt{V: 1, V1: &LedgerEntryExtensionV1{SponsoringId: &sponsor}},
+	}
+	changes, err := DiffLedgerEntry(before, after)
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerEntryFieldChange{
+		{Field: "flags", Before: TrustLineFlags(0), After: TrustLineFlagsAuthorizedFlag},
+		{Field: "sponsoringID", Before: nil, After: sponsor.Address()},
+	}, changes)
+
+	data := func(value string) LedgerEntry {
+		return LedgerEntry{
+			Data: LedgerEntryData{
+				Type: LedgerEntryTypeData,
+				Data: &DataEntry{AccountId: account, DataName: "name", DataValue: DataValue(value)},
+			},
+		}
+	}
+	changes, err = DiffLedgerEntry(data("a"), data("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []LedgerEntryFieldChange{
+		{Field: "dataValue", Before: DataValue("a"), After: DataValue("b")},
+	}, changes)
+
+	_, err = DiffLedgerEntry(before, data("a"))
+	assert.EqualError(t, err, "the ledger entries have different ledger keys")
+}
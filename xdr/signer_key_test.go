@@ -28,6 +28,10 @@ func TestSignerKey_GetAddress(t *testing.T) {
 			"HashX",
 			"XBU2RRGLXH3E5CQHTD3ODLDF2BWDCYUSSBLLZ5GNW7JXHDIYKXZWGTOG",
 		},
+		{
+			"SignedPayload",
+			"PA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAQACAQDAQCQMBYIBEFAWDANBYHRAEISCMKBKFQXDAMRUGY4DUPB6IBZGM",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -64,6 +68,10 @@ func TestSignerKey_SetAddress(t *testing.T) {
 			Name:    "HashX",
 			Address: "XBU2RRGLXH3E5CQHTD3ODLDF2BWDCYUSSBLLZ5GNW7JXHDIYKXZWGTOG",
 		},
+		{
+			Name:    "SignedPayload",
+			Address: "PA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAQACAQDAQCQMBYIBEFAWDANBYHRAEISCMKBKFQXDAMRUGY4DUPB6IBZGM",
+		},
 	}
 
 	for _, kase := range cases {
@@ -80,3 +88,20 @@ func TestSignerKey_SetAddress(t *testing.T) {
 	err := dest.SetAddress("SBU2RRGLXH3E5CQHTD3ODLDF2BWDCYUSSBLLZ5GNW7JXHDIYKXZWHOKR")
 	assert.Error(t, err)
 }
+
+func TestSignerKey_SignedPayload(t *testing.T) {
+	key := MustSigner("PA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAQACAQDAQCQMBYIBEFAWDANBYHRAEISCMKBKFQXDAMRUGY4DUPB6IBZGM")
+	assert.Equal(t, SignerKeyTypeSignerKeyTypeEd25519SignedPayload, key.Type)
+	signedPayload := key.MustEd25519SignedPayload()
+	assert.Equal(t, MustAddress("GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ").MustEd25519(), signedPayload.Ed25519)
+	assert.Len(t, signedPayload.Payload, 32)
+
+	raw, err := key.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded SignerKey
+	assert.NoError(t, decoded.UnmarshalBinary(raw))
+	assert.True(t, key.Equals(decoded))
+
+	decoded.Ed25519SignedPayload.Payload = decoded.Ed25519SignedPayload.Payload[:31]
+	assert.False(t, key.Equals(decoded))
+}
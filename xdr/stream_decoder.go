@@ -0,0 +1,271 @@
+package xdr
+
+import (
+	"io"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// StreamDecoder decodes a LedgerCloseMeta from a reader piece by piece, so
+// that the ledgers of full-history ingestion, whose transaction metas can be
+// hundreds of megabytes, never have to be held in memory as a whole.
+//
+// The pieces are read in the order they are encoded in: the ledger header,
+// the transaction envelopes, the transaction result metas (whose operation
+// metas are passed one by one to a callback), the upgrades and the SCP
+// history entries. The Next methods return io.EOF once all the pieces of
+// their kind have been read. Calling a method for a later kind of piece skips
+// the remaining pieces of the earlier kinds, decoding them without keeping
+// them.
+type StreamDecoder struct {
+	r         io.Reader
+	section   streamSection
+	remaining uint32
+
+	txSetPreviousLedgerHash Hash
+	transactionIndex        int
+}
+
+type streamSection int
+
+const (
+	sectionStart streamSection = iota
+	sectionLedgerHeader
+	sectionTxSet
+	sectionTxProcessing
+	sectionUpgradesProcessing
+	sectionScpInfo
+	sectionEnd
+)
+
+// NewStreamDecoder returns a decoder of the LedgerCloseMeta encoded in r.
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{r: r}
+}
+
+// LedgerHeader decodes the header of the ledger. It can only be called
+// first, the other methods skip the header if it has not been decoded.
+func (d *StreamDecoder) LedgerHeader() (LedgerHeaderHistoryEntry, error) {
+	var header LedgerHeaderHistoryEntry
+	if d.section != sectionStart {
+		return header, errors.New("ledger header already decoded")
+	}
+
+	var version int32
+	if _, err := Unmarshal(d.r, &version); err != nil {
+		return header, errors.Wrap(err, "error decoding LedgerCloseMeta version")
+	}
+	if version != 0 {
+		return header, errors.Errorf("unknown LedgerCloseMeta version %d", version)
+	}
+	if _, err := Unmarshal(d.r, &header); err != nil {
+		return header, errors.Wrap(err, "error decoding ledger header")
+	}
+	d.section = sectionLedgerHeader
+	return header, nil
+}
+
+// TransactionSetPreviousLedgerHash returns the previous ledger hash of the
+// transaction set.
+func (d *StreamDecoder) TransactionSetPreviousLedgerHash() (Hash, error) {
+	if err := d.advanceTo(sectionTxSet); err != nil {
+		return Hash{}, err
+	}
+	return d.txSetPreviousLedgerHash, nil
+}
+
+// NextTransactionEnvelope decodes the next transaction envelope of the
+// transaction set, or returns io.EOF if all have been read.
+func (d *StreamDecoder) NextTransactionEnvelope() (TransactionEnvelope, error) {
+	var envelope TransactionEnvelope
+	if err := d.next(sectionTxSet); err != nil {
+		return envelope, err
+	}
+	if _, err := Unmarshal(d.r, &envelope); err != nil {
+		return envelope, errors.Wrap(err, "error decoding transaction envelope")
+	}
+	return envelope, nil
+}
+
+// NextTransactionResultMeta decodes the next transaction result meta, or
+// returns io.EOF if all have been read. If onOperation is not nil, the
+// operation metas of the transaction are passed to it as they are decoded,
+// with their index, instead of being kept in the operations of the returned
+// TxApplyProcessing, which are left empty. An error returned by onOperation
+// stops the decoding and is returned as is.
+func (d *StreamDecoder) NextTransactionResultMeta(
+	onOperation func(index int, meta OperationMeta) error,
+) (TransactionResultMeta, error) {
+	var meta TransactionResultMeta
+	if err := d.next(sectionTxProcessing); err != nil {
+		return meta, err
+	}
+	if _, err := Unmarshal(d.r, &meta.Result); err != nil {
+		return meta, errors.Wrap(err, "error decoding transaction result")
+	}
+	if _, err := Unmarshal(d.r, &meta.FeeProcessing); err != nil {
+		return meta, errors.Wrap(err, "error decoding fee processing changes")
+	}
+	err := d.decodeTransactionMeta(&meta.TxApplyProcessing, onOperation)
+	d.transactionIndex++
+	return meta, err
+}
+
+// NextUpgradeEntryMeta decodes the next upgrade, or returns io.EOF if all
+// have been read.
+func (d *StreamDecoder) NextUpgradeEntryMeta() (UpgradeEntryMeta, error) {
+	var upgrade UpgradeEntryMeta
+	if err := d.next(sectionUpgradesProcessing); err != nil {
+		return upgrade, err
+	}
+	if _, err := Unmarshal(d.r, &upgrade); err != nil {
+		return upgrade, errors.Wrap(err, "error decoding upgrade")
+	}
+	return upgrade, nil
+}
+
+// NextScpHistoryEntry decodes the next SCP history entry, or returns io.EOF
+// if all have been read, which ends the LedgerCloseMeta.
+func (d *StreamDecoder) NextScpHistoryEntry() (ScpHistoryEntry, error) {
+	var entry ScpHistoryEntry
+	if err := d.next(sectionScpInfo); err != nil {
+		return entry, err
+	}
+	if _, err := Unmarshal(d.r, &entry); err != nil {
+		return entry, errors.Wrap(err, "error decoding SCP history entry")
+	}
+	return entry, nil
+}
+
+// next prepares the decoding of the next piece of section, returning io.EOF
+// if all have been read.
+func (d *StreamDecoder) next(section streamSection) error {
+	if err := d.advanceTo(section); err != nil {
+		return err
+	}
+	if d.section != section || d.remaining == 0 {
+		return io.EOF
+	}
+	d.remaining--
+	return nil
+}
+
+// advanceTo skips the pieces of the sections before section and decodes the
+// length of section if it has not been entered yet. Nothing is done if the
+// decoder is already past section.
+func (d *StreamDecoder) advanceTo(section streamSection) error {
+	if d.section == sectionStart {
+		if _, err := d.LedgerHeader(); err != nil {
+			return err
+		}
+	}
+
+	for d.section < section {
+		if err := d.skipSection(); err != nil {
+			return err
+		}
+
+		d.section++
+		d.remaining = 0
+		switch d.section {
+		case sectionTxSet:
+			if _, err := Unmarshal(d.r, &d.txSetPreviousLedgerHash); err != nil {
+				return errors.Wrap(err, "error decoding transaction set")
+			}
+		case sectionEnd:
+			return nil
+		}
+		if _, err := Unmarshal(d.r, &d.remaining); err != nil {
+			return errors.Wrap(err, "error decoding array length")
+		}
+	}
+	return nil
+}
+
+// skipSection decodes the remaining pieces of the current section without
+// keeping them.
+func (d *StreamDecoder) skipSection() error {
+	for d.remaining > 0 {
+		var err error
+		switch d.section {
+		case sectionTxSet:
+			_, err = d.NextTransactionEnvelope()
+		case sectionTxProcessing:
+			_, err = d.NextTransactionResultMeta(func(int, OperationMeta) error {
+				return nil
+			})
+		case sectionUpgradesProcessing:
+			_, err = d.NextUpgradeEntryMeta()
+		case sectionScpInfo:
+			_, err = d.NextScpHistoryEntry()
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *StreamDecoder) decodeTransactionMeta(
+	meta *TransactionMeta,
+	onOperation func(index int, meta OperationMeta) error,
+) error {
+	if _, err := Unmarshal(d.r, &meta.V); err != nil {
+		return errors.Wrap(err, "error decoding transaction meta version")
+	}
+
+	var operations []OperationMeta
+	var err error
+	switch meta.V {
+	case 0:
+		operations, err = d.decodeOperationMetas(onOperation)
+		meta.Operations = &operations
+	case 1:
+		meta.V1 = &TransactionMetaV1{}
+		if _, err = Unmarshal(d.r, &meta.V1.TxChanges); err != nil {
+			return errors.Wrap(err, "error decoding transaction changes")
+		}
+		meta.V1.Operations, err = d.decodeOperationMetas(onOperation)
+	case 2:
+		meta.V2 = &TransactionMetaV2{}
+		if _, err = Unmarshal(d.r, &meta.V2.TxChangesBefore); err != nil {
+			return errors.Wrap(err, "error decoding transaction changes before")
+		}
+		if meta.V2.Operations, err = d.decodeOperationMetas(onOperation); err != nil {
+			return err
+		}
+		if _, err = Unmarshal(d.r, &meta.V2.TxChangesAfter); err != nil {
+			return errors.Wrap(err, "error decoding transaction changes after")
+		}
+	default:
+		return errors.Errorf("unknown transaction meta version %d", meta.V)
+	}
+	return err
+}
+
+// decodeOperationMetas decodes an array of operation metas, returning them if
+// onOperation is nil or passing them to it otherwise.
+func (d *StreamDecoder) decodeOperationMetas(
+	onOperation func(index int, meta OperationMeta) error,
+) ([]OperationMeta, error) {
+	var count uint32
+	if _, err := Unmarshal(d.r, &count); err != nil {
+		return nil, errors.Wrap(err, "error decoding operation metas length")
+	}
+
+	var operations []OperationMeta
+	for i := 0; i < int(count); i++ {
+		var operation OperationMeta
+		if _, err := Unmarshal(d.r, &operation); err != nil {
+			return nil, errors.Wrapf(err, "error decoding meta of operation %d of transaction %d", i, d.transactionIndex)
+		}
+		if onOperation == nil {
+			operations = append(operations, operation)
+		} else if err := onOperation(i, operation); err != nil {
+			return nil, err
+		}
+	}
+	return operations, nil
+}
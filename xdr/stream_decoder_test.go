@@ -0,0 +1,250 @@
+package xdr
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func streamDecoderTestLedger() LedgerCloseMeta {
+	address := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	key := LedgerKey{
+		Type:    LedgerEntryTypeAccount,
+		Account: &LedgerKeyAccount{AccountId: MustAddress(address)},
+	}
+	changes := LedgerEntryChanges{
+		{Type: LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &key},
+	}
+	envelope := func(bumpTo Int64) TransactionEnvelope {
+		return TransactionEnvelope{
+			Type: EnvelopeTypeEnvelopeTypeTx,
+			V1: &TransactionV1Envelope{
+				Tx: Transaction{
+					SourceAccount: MustMuxedAddress(address),
+					Fee:           100,
+					SeqNum:        1,
+					Operations: []Operation{{
+						Body: OperationBody{
+							Type:           OperationTypeBumpSequence,
+							BumpSequenceOp: &BumpSequenceOp{BumpTo: SequenceNumber(bumpTo)},
+						},
+					}},
+				},
+				Signatures: []DecoratedSignature{},
+			},
+		}
+	}
+	result := func(hash byte) TransactionResultPair {
+		return TransactionResultPair{
+			TransactionHash: Hash{hash},
+			Result: TransactionResult{
+				FeeCharged: 100,
+				Result: TransactionResultResult{
+					Code:    TransactionResultCodeTxSuccess,
+					Results: &[]OperationResult{},
+				},
+			},
+		}
+	}
+	v0Operations := []OperationMeta{{Changes: changes}}
+	baseFee := Uint32(200)
+
+	return LedgerCloseMeta{
+		V0: &LedgerCloseMetaV0{
+			LedgerHeader: LedgerHeaderHistoryEntry{
+				Hash:   Hash{1},
+				Header: LedgerHeader{LedgerSeq: 2, LedgerVersion: 15},
+			},
+			TxSet: TransactionSet{
+				PreviousLedgerHash: Hash{2},
+				Txs:                []TransactionEnvelope{envelope(2), envelope(3), envelope(4)},
+			},
+			TxProcessing: []TransactionResultMeta{
+				{
+					Result:            result(1),
+					FeeProcessing:     changes,
+					TxApplyProcessing: TransactionMeta{V: 0, Operations: &v0Operations},
+				},
+				{
+					Result:        result(2),
+					FeeProcessing: LedgerEntryChanges{},
+					TxApplyProcessing: TransactionMeta{V: 1, V1: &TransactionMetaV1{
+						TxChanges:  changes,
+						Operations: []OperationMeta{{Changes: changes}, {Changes: LedgerEntryChanges{}}},
+					}},
+				},
+				{
+					Result:        result(3),
+					FeeProcessing: changes,
+					TxApplyProcessing: TransactionMeta{V: 2, V2: &TransactionMetaV2{
+						TxChangesBefore: LedgerEntryChanges{},
+						Operations:      []OperationMeta{{Changes: changes}},
+						TxChangesAfter:  changes,
+					}},
+				},
+			},
+			UpgradesProcessing: []UpgradeEntryMeta{{
+				Upgrade: LedgerUpgrade{Type: LedgerUpgradeTypeLedgerUpgradeBaseFee, NewBaseFee: &baseFee},
+				Changes: changes,
+			}},
+			ScpInfo: []ScpHistoryEntry{{
+				V0: &ScpHistoryEntryV0{
+					QuorumSets:     []ScpQuorumSet{},
+					LedgerMessages: LedgerScpMessages{LedgerSeq: 2, Messages: []ScpEnvelope{}},
+				},
+			}},
+		},
+	}
+}
+
+func streamDecoderForLedger(t *testing.T, ledger LedgerCloseMeta) *StreamDecoder {
+	b, err := ledger.MarshalBinary()
+	require.NoError(t, err)
+	return NewStreamDecoder(bytes.NewReader(b))
+}
+
+func assertXDREqual(t *testing.T, expected, actual interface{}) {
+	expectedB64, err := MarshalBase64(expected)
+	require.NoError(t, err)
+	actualB64, err := MarshalBase64(actual)
+	require.NoError(t, err)
+	assert.Equal(t, expectedB64, actualB64)
+}
+
+func TestStreamDecoder(t *testing.T) {
+	ledger := streamDecoderTestLedger()
+	d := streamDecoderForLedger(t, ledger)
+
+	decoded := LedgerCloseMeta{V0: &LedgerCloseMetaV0{}}
+	var err error
+	decoded.V0.LedgerHeader, err = d.LedgerHeader()
+	require.NoError(t, err)
+	decoded.V0.TxSet.PreviousLedgerHash, err = d.TransactionSetPreviousLedgerHash()
+	require.NoError(t, err)
+	for {
+		envelope, err := d.NextTransactionEnvelope()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		decoded.V0.TxSet.Txs = append(decoded.V0.TxSet.Txs, envelope)
+	}
+	for {
+		meta, err := d.NextTransactionResultMeta(nil)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		decoded.V0.TxProcessing = append(decoded.V0.TxProcessing, meta)
+	}
+	for {
+		upgrade, err := d.NextUpgradeEntryMeta()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		decoded.V0.UpgradesProcessing = append(decoded.V0.UpgradesProcessing, upgrade)
+	}
+	for {
+		entry, err := d.NextScpHistoryEntry()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		decoded.V0.ScpInfo = append(decoded.V0.ScpInfo, entry)
+	}
+
+	assertXDREqual(t, ledger, decoded)
+
+	_, err = d.NextTransactionEnvelope()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStreamDecoderOperationMetas(t *testing.T) {
+	ledger := streamDecoderTestLedger()
+	d := streamDecoderForLedger(t, ledger)
+
+	// the header and transaction envelopes are skipped
+	type operation struct {
+		transaction int
+		index       int
+		meta        OperationMeta
+	}
+	operations := []operation{}
+	metas := []TransactionResultMeta{}
+	for {
+		transaction := len(metas)
+		meta, err := d.NextTransactionResultMeta(func(index int, meta OperationMeta) error {
+			operations = append(operations, operation{transaction, index, meta})
+			return nil
+		})
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		metas = append(metas, meta)
+	}
+
+	expected := ledger.V0.TxProcessing
+	require.Len(t, operations, 4)
+	for i, op := range []operation{
+		{0, 0, (*expected[0].TxApplyProcessing.Operations)[0]},
+		{1, 0, expected[1].TxApplyProcessing.V1.Operations[0]},
+		{1, 1, expected[1].TxApplyProcessing.V1.Operations[1]},
+		{2, 0, expected[2].TxApplyProcessing.V2.Operations[0]},
+	} {
+		assert.Equal(t, op.transaction, operations[i].transaction)
+		assert.Equal(t, op.index, operations[i].index)
+		assertXDREqual(t, op.meta, operations[i].meta)
+	}
+
+	// the operations are not kept in the transaction metas
+	require.Len(t, metas, 3)
+	for i := range expected {
+		assertXDREqual(t, expected[i].Result, metas[i].Result)
+		assertXDREqual(t, expected[i].FeeProcessing, metas[i].FeeProcessing)
+	}
+	assert.Empty(t, *metas[0].TxApplyProcessing.Operations)
+	assertXDREqual(t, expected[1].TxApplyProcessing.V1.TxChanges, metas[1].TxApplyProcessing.V1.TxChanges)
+	assert.Empty(t, metas[1].TxApplyProcessing.V1.Operations)
+	assertXDREqual(t, expected[2].TxApplyProcessing.V2.TxChangesAfter, metas[2].TxApplyProcessing.V2.TxChangesAfter)
+	assert.Empty(t, metas[2].TxApplyProcessing.V2.Operations)
+
+	// the upgrades are skipped
+	entry, err := d.NextScpHistoryEntry()
+	require.NoError(t, err)
+	assertXDREqual(t, ledger.V0.ScpInfo[0], entry)
+	_, err = d.NextScpHistoryEntry()
+	assert.Equal(t, io.EOF, err)
+	_, err = d.NextUpgradeEntryMeta()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestStreamDecoderErrors(t *testing.T) {
+	ledger := streamDecoderTestLedger()
+	d := streamDecoderForLedger(t, ledger)
+	_, err := d.LedgerHeader()
+	require.NoError(t, err)
+	_, err = d.LedgerHeader()
+	assert.EqualError(t, err, "ledger header already decoded")
+
+	stop := errors.New("stop")
+	_, err = d.NextTransactionResultMeta(func(int, OperationMeta) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+
+	d = NewStreamDecoder(bytes.NewReader([]byte{0, 0, 0, 1}))
+	_, err = d.NextTransactionEnvelope()
+	assert.EqualError(t, err, "unknown LedgerCloseMeta version 1")
+
+	b, err := ledger.MarshalBinary()
+	require.NoError(t, err)
+	d = NewStreamDecoder(bytes.NewReader(b[:len(b)-10]))
+	_, err = d.NextScpHistoryEntry()
+	assert.Error(t, err)
+}
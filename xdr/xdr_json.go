@@ -0,0 +1,457 @@
+package xdr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	"github.com/stellar/go/support/errors"
+)
+
+//go:generate go run ./internal/jsongen -i xdr_generated.go -o xdr_json_generated.go
+
+// The MarshalJSON and UnmarshalJSON methods of the XDR types encode them in
+// a stable, human-readable JSON format:
+//
+//  - structs are objects with the field names of the XDR definitions,
+//  - unions are objects with their discriminant and their arm, if it is not
+//    void, named as in the XDR definitions,
+//  - enums are strings with the names of their XDR constants,
+//  - account ids, public keys, muxed accounts and signer keys are strkeys,
+//  - asset codes are strings,
+//  - other opaque data, like hashes and signatures, is hex encoded,
+//  - 64 bit integers are strings, so that they are not rounded by JSON
+//    parsers storing numbers as doubles.
+//
+// Typedefs of numbers and strings, like Int64 or String32, and Price keep
+// their default JSON encoding when they are marshaled on their own.
+
+var (
+	publicKeyType    = reflect.TypeOf(PublicKey{})
+	muxedAccountType = reflect.TypeOf(MuxedAccount{})
+	signerKeyType    = reflect.TypeOf(SignerKey{})
+	assetCode4Type   = reflect.TypeOf(AssetCode4{})
+	assetCode12Type  = reflect.TypeOf(AssetCode12{})
+)
+
+type jsonUnion interface {
+	ArmForSwitch(int32) (string, bool)
+	SwitchFieldName() string
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	if err := encodeJSON(&b, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func unmarshalJSON(data []byte, v interface{}) error {
+	return decodeJSON(data, reflect.ValueOf(v).Elem())
+}
+
+func encodeJSON(b *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	if target, ok := jsonTypedefs[t]; ok {
+		return encodeJSON(b, v.Convert(target))
+	}
+
+	switch t {
+	case publicKeyType:
+		pk := v.Interface().(PublicKey)
+		aid := AccountId(pk)
+		address, err := aid.GetAddress()
+		if err != nil {
+			return err
+		}
+		return encodeJSONString(b, address)
+	case muxedAccountType:
+		m := v.Interface().(MuxedAccount)
+		address, err := m.GetAddress()
+		if err != nil {
+			return err
+		}
+		return encodeJSONString(b, address)
+	case signerKeyType:
+		skey := v.Interface().(SignerKey)
+		address, err := skey.GetAddress()
+		if err != nil {
+			return err
+		}
+		return encodeJSONString(b, address)
+	case assetCode4Type, assetCode12Type:
+		code := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(code), v)
+		return encodeJSONString(b, string(bytes.TrimRight(code, "\x00")))
+	}
+
+	if names, ok := jsonEnumNames[t]; ok {
+		name, ok := names[int32(v.Int())]
+		if !ok {
+			return errors.Errorf("invalid value %d for enum %s", v.Int(), t.Name())
+		}
+		return encodeJSONString(b, name)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int32:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint32:
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Int64:
+		return encodeJSONString(b, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint64:
+		return encodeJSONString(b, strconv.FormatUint(v.Uint(), 10))
+	case reflect.String:
+		return encodeJSONString(b, v.String())
+	case reflect.Array, reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			raw := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(raw), v)
+			return encodeJSONString(b, hex.EncodeToString(raw))
+		}
+		b.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := encodeJSON(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case reflect.Ptr:
+		if v.IsNil() {
+			b.WriteString("null")
+			return nil
+		}
+		return encodeJSON(b, v.Elem())
+	case reflect.Struct:
+		return encodeJSONStruct(b, v)
+	default:
+		return errors.Errorf("cannot encode %s to JSON", t)
+	}
+	return nil
+}
+
+func encodeJSONStruct(b *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	names, ok := jsonFieldNames[t]
+	if !ok {
+		return errors.Errorf("cannot encode %s to JSON", t)
+	}
+
+	fields := make([]int, 0, len(names))
+	if u, ok := v.Interface().(jsonUnion); ok {
+		sw, _ := t.FieldByName(u.SwitchFieldName())
+		fields = append(fields, sw.Index[0])
+		arm, ok := u.ArmForSwitch(jsonDiscriminant(v.Field(sw.Index[0])))
+		if !ok {
+			return errors.Errorf("invalid discriminant for union %s", t.Name())
+		}
+		if arm != "" {
+			field, _ := t.FieldByName(arm)
+			if v.Field(field.Index[0]).IsNil() {
+				return errors.Errorf("arm %s of union %s is not set", arm, t.Name())
+			}
+			fields = append(fields, field.Index[0])
+		}
+	} else {
+		for i := range names {
+			fields = append(fields, i)
+		}
+	}
+
+	b.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := encodeJSONString(b, names[field]); err != nil {
+			return err
+		}
+		b.WriteByte(':')
+		if err := encodeJSON(b, v.Field(field)); err != nil {
+			return errors.Wrapf(err, "%s.%s", t.Name(), names[field])
+		}
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+func encodeJSONString(b *bytes.Buffer, s string) error {
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	b.Write(encoded)
+	return nil
+}
+
+func jsonDiscriminant(v reflect.Value) int32 {
+	if v.Kind() == reflect.Uint32 {
+		return int32(v.Uint())
+	}
+	return int32(v.Int())
+}
+
+func decodeJSON(data []byte, v reflect.Value) error {
+	t := v.Type()
+	if target, ok := jsonTypedefs[t]; ok {
+		converted := reflect.New(target).Elem()
+		if err := decodeJSON(data, converted); err != nil {
+			return err
+		}
+		v.Set(converted.Convert(t))
+		return nil
+	}
+
+	switch t {
+	case publicKeyType, muxedAccountType, signerKeyType:
+		var address string
+		if err := json.Unmarshal(data, &address); err != nil {
+			return err
+		}
+		var err error
+		switch t {
+		case publicKeyType:
+			var aid AccountId
+			err = aid.SetAddress(address)
+			v.Set(reflect.ValueOf(PublicKey(aid)))
+		case muxedAccountType:
+			var m MuxedAccount
+			err = m.SetAddress(address)
+			v.Set(reflect.ValueOf(m))
+		case signerKeyType:
+			var skey SignerKey
+			err = skey.SetAddress(address)
+			v.Set(reflect.ValueOf(skey))
+		}
+		return err
+	case assetCode4Type, assetCode12Type:
+		var code string
+		if err := json.Unmarshal(data, &code); err != nil {
+			return err
+		}
+		if len(code) > v.Len() {
+			return errors.Errorf("asset code %q is longer than %d bytes", code, v.Len())
+		}
+		v.Set(reflect.Zero(t))
+		reflect.Copy(v, reflect.ValueOf([]byte(code)))
+		return nil
+	}
+
+	if names, ok := jsonEnumNames[t]; ok {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		for value, n := range names {
+			if n == name {
+				v.SetInt(int64(value))
+				return nil
+			}
+		}
+		return errors.Errorf("invalid name %q for enum %s", name, t.Name())
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		var x bool
+		if err := json.Unmarshal(data, &x); err != nil {
+			return err
+		}
+		v.SetBool(x)
+	case reflect.Int32:
+		var x int32
+		if err := json.Unmarshal(data, &x); err != nil {
+			return err
+		}
+		v.SetInt(int64(x))
+	case reflect.Uint32:
+		var x uint32
+		if err := json.Unmarshal(data, &x); err != nil {
+			return err
+		}
+		v.SetUint(uint64(x))
+	case reflect.Int64:
+		var x int64
+		if err := json.Unmarshal(data, (*jsonInt64)(&x)); err != nil {
+			return err
+		}
+		v.SetInt(x)
+	case reflect.Uint64:
+		var x uint64
+		if err := json.Unmarshal(data, (*jsonUint64)(&x)); err != nil {
+			return err
+		}
+		v.SetUint(x)
+	case reflect.String:
+		var x string
+		if err := json.Unmarshal(data, &x); err != nil {
+			return err
+		}
+		v.SetString(x)
+	case reflect.Array, reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return decodeJSONOpaque(data, v)
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+		if t.Kind() == reflect.Array {
+			if len(elems) != v.Len() {
+				return errors.Errorf("expected %d elements, got %d", v.Len(), len(elems))
+			}
+		} else {
+			v.Set(reflect.MakeSlice(t, len(elems), len(elems)))
+		}
+		for i, elem := range elems {
+			if err := decodeJSON(elem, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr:
+		if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		elem := reflect.New(t.Elem())
+		if err := decodeJSON(data, elem.Elem()); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Struct:
+		return decodeJSONStruct(data, v)
+	default:
+		return errors.Errorf("cannot decode %s from JSON", t)
+	}
+	return nil
+}
+
+func decodeJSONOpaque(data []byte, v reflect.Value) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if v.Kind() == reflect.Array {
+		if len(raw) != v.Len() {
+			return errors.Errorf("expected %d bytes, got %d", v.Len(), len(raw))
+		}
+	} else {
+		v.Set(reflect.MakeSlice(v.Type(), len(raw), len(raw)))
+	}
+	reflect.Copy(v, reflect.ValueOf(raw))
+	return nil
+}
+
+func decodeJSONStruct(data []byte, v reflect.Value) error {
+	t := v.Type()
+	names, ok := jsonFieldNames[t]
+	if !ok {
+		return errors.Errorf("cannot decode %s from JSON", t)
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	v.Set(reflect.Zero(t))
+
+	fields := map[string]int{}
+	for i, name := range names {
+		fields[name] = i
+	}
+	for name := range object {
+		if _, ok := fields[name]; !ok {
+			return errors.Errorf("unknown field %q of %s", name, t.Name())
+		}
+	}
+
+	decodeField := func(i int) error {
+		raw, ok := object[names[i]]
+		if !ok {
+			if t.Field(i).Type.Kind() == reflect.Ptr {
+				return nil
+			}
+			return errors.Errorf("missing field %q of %s", names[i], t.Name())
+		}
+		return errors.Wrapf(decodeJSON(raw, v.Field(i)), "%s.%s", t.Name(), names[i])
+	}
+
+	u, ok := v.Addr().Interface().(jsonUnion)
+	if !ok {
+		for i := range names {
+			if err := decodeField(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	sw, _ := t.FieldByName(u.SwitchFieldName())
+	if err := decodeField(sw.Index[0]); err != nil {
+		return err
+	}
+	arm, ok := u.ArmForSwitch(jsonDiscriminant(v.Field(sw.Index[0])))
+	if !ok {
+		return errors.Errorf("invalid discriminant for union %s", t.Name())
+	}
+	armIndex := -1
+	if arm != "" {
+		field, _ := t.FieldByName(arm)
+		armIndex = field.Index[0]
+		if _, ok := object[names[armIndex]]; !ok {
+			return errors.Errorf("missing field %q of %s", names[armIndex], t.Name())
+		}
+		// The arm is always set, even if it is an optional value which is
+		// null.
+		armValue := v.Field(armIndex)
+		armValue.Set(reflect.New(armValue.Type().Elem()))
+		err := decodeJSON(object[names[armIndex]], armValue.Elem())
+		if err != nil {
+			return errors.Wrapf(err, "%s.%s", t.Name(), names[armIndex])
+		}
+	}
+	for i, name := range names {
+		if _, ok := object[name]; ok && i != sw.Index[0] && i != armIndex {
+			return errors.Errorf("field %q of %s does not match its discriminant", name, t.Name())
+		}
+	}
+	return nil
+}
+
+// jsonInt64 decodes an int64 from a JSON string.
+type jsonInt64 int64
+
+func (i *jsonInt64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	x, err := strconv.ParseInt(s, 10, 64)
+	*i = jsonInt64(x)
+	return err
+}
+
+// jsonUint64 decodes a uint64 from a JSON string.
+type jsonUint64 uint64
+
+func (i *jsonUint64) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	x, err := strconv.ParseUint(s, 10, 64)
+	*i = jsonUint64(x)
+	return err
+}
@@ -0,0 +1,3038 @@
+// Code generated by jsongen from xdr_generated.go. DO NOT EDIT.
+
+package xdr
+
+import "reflect"
+
+// jsonFieldNames are the XDR names of the fields of the structs and unions.
+var jsonFieldNames = map[reflect.Type][]string{
+	reflect.TypeOf(ScpBallot{}):                                    {"counter", "value"},
+	reflect.TypeOf(ScpNomination{}):                                {"quorumSetHash", "votes", "accepted"},
+	reflect.TypeOf(ScpStatementPrepare{}):                          {"quorumSetHash", "ballot", "prepared", "preparedPrime", "nC", "nH"},
+	reflect.TypeOf(ScpStatementConfirm{}):                          {"ballot", "nPrepared", "nCommit", "nH", "quorumSetHash"},
+	reflect.TypeOf(ScpStatementExternalize{}):                      {"commit", "nH", "commitQuorumSetHash"},
+	reflect.TypeOf(ScpStatementPledges{}):                          {"type", "prepare", "confirm", "externalize", "nominate"},
+	reflect.TypeOf(ScpStatement{}):                                 {"nodeID", "slotIndex", "pledges"},
+	reflect.TypeOf(ScpEnvelope{}):                                  {"statement", "signature"},
+	reflect.TypeOf(ScpQuorumSet{}):                                 {"threshold", "validators", "innerSets"},
+	reflect.TypeOf(AssetCode{}):                                    {"type", "assetCode4", "assetCode12"},
+	reflect.TypeOf(AssetAlphaNum4{}):                               {"assetCode", "issuer"},
+	reflect.TypeOf(AssetAlphaNum12{}):                              {"assetCode", "issuer"},
+	reflect.TypeOf(Asset{}):                                        {"type", "alphaNum4", "alphaNum12"},
+	reflect.TypeOf(Price{}):                                        {"n", "d"},
+	reflect.TypeOf(Liabilities{}):                                  {"buying", "selling"},
+	reflect.TypeOf(Signer{}):                                       {"key", "weight"},
+	reflect.TypeOf(AccountEntryExtensionV2Ext{}):                   {"v"},
+	reflect.TypeOf(AccountEntryExtensionV2{}):                      {"numSponsored", "numSponsoring", "signerSponsoringIDs", "ext"},
+	reflect.TypeOf(AccountEntryExtensionV1Ext{}):                   {"v", "v2"},
+	reflect.TypeOf(AccountEntryExtensionV1{}):                      {"liabilities", "ext"},
+	reflect.TypeOf(AccountEntryExt{}):                              {"v", "v1"},
+	reflect.TypeOf(AccountEntry{}):                                 {"accountID", "balance", "seqNum", "numSubEntries", "inflationDest", "flags", "homeDomain", "thresholds", "signers", "ext"},
+	reflect.TypeOf(TrustLineEntryV1Ext{}):                          {"v"},
+	reflect.TypeOf(TrustLineEntryV1{}):                             {"liabilities", "ext"},
+	reflect.TypeOf(TrustLineEntryExt{}):                            {"v", "v1"},
+	reflect.TypeOf(TrustLineEntry{}):                               {"accountID", "asset", "balance", "limit", "flags", "ext"},
+	reflect.TypeOf(OfferEntryExt{}):                                {"v"},
+	reflect.TypeOf(OfferEntry{}):                                   {"sellerID", "offerID", "selling", "buying", "amount", "price", "flags", "ext"},
+	reflect.TypeOf(DataEntryExt{}):                                 {"v"},
+	reflect.TypeOf(DataEntry{}):                                    {"accountID", "dataName", "dataValue", "ext"},
+	reflect.TypeOf(ClaimPredicate{}):                               {"type", "andPredicates", "orPredicates", "notPredicate", "absBefore", "relBefore"},
+	reflect.TypeOf(ClaimantV0{}):                                   {"destination", "predicate"},
+	reflect.TypeOf(Claimant{}):                                     {"type", "v0"},
+	reflect.TypeOf(ClaimableBalanceId{}):                           {"type", "v0"},
+	reflect.TypeOf(ClaimableBalanceEntryExtensionV1Ext{}):          {"v"},
+	reflect.TypeOf(ClaimableBalanceEntryExtensionV1{}):             {"ext", "flags"},
+	reflect.TypeOf(ClaimableBalanceEntryExt{}):                     {"v", "v1"},
+	reflect.TypeOf(ClaimableBalanceEntry{}):                        {"balanceID", "claimants", "asset", "amount", "ext"},
+	reflect.TypeOf(LedgerEntryExtensionV1Ext{}):                    {"v"},
+	reflect.TypeOf(LedgerEntryExtensionV1{}):                       {"sponsoringID", "ext"},
+	reflect.TypeOf(LedgerEntryData{}):                              {"type", "account", "trustLine", "offer", "data", "claimableBalance"},
+	reflect.TypeOf(LedgerEntryExt{}):                               {"v", "v1"},
+	reflect.TypeOf(LedgerEntry{}):                                  {"lastModifiedLedgerSeq", "data", "ext"},
+	reflect.TypeOf(LedgerKeyAccount{}):                             {"accountID"},
+	reflect.TypeOf(LedgerKeyTrustLine{}):                           {"accountID", "asset"},
+	reflect.TypeOf(LedgerKeyOffer{}):                               {"sellerID", "offerID"},
+	reflect.TypeOf(LedgerKeyData{}):                                {"accountID", "dataName"},
+	reflect.TypeOf(LedgerKeyClaimableBalance{}):                    {"balanceID"},
+	reflect.TypeOf(LedgerKey{}):                                    {"type", "account", "trustLine", "offer", "data", "claimableBalance"},
+	reflect.TypeOf(LedgerCloseValueSignature{}):                    {"nodeID", "signature"},
+	reflect.TypeOf(StellarValueExt{}):                              {"v", "lcValueSignature"},
+	reflect.TypeOf(StellarValue{}):                                 {"txSetHash", "closeTime", "upgrades", "ext"},
+	reflect.TypeOf(LedgerHeaderExt{}):                              {"v"},
+	reflect.TypeOf(LedgerHeader{}):                                 {"ledgerVersion", "previousLedgerHash", "scpValue", "txSetResultHash", "bucketListHash", "ledgerSeq", "totalCoins", "feePool", "inflationSeq", "idPool", "baseFee", "baseReserve", "maxTxSetSize", "skipList", "ext"},
+	reflect.TypeOf(LedgerUpgrade{}):                                {"type", "newLedgerVersion", "newBaseFee", "newMaxTxSetSize", "newBaseReserve"},
+	reflect.TypeOf(BucketMetadataExt{}):                            {"v"},
+	reflect.TypeOf(BucketMetadata{}):                               {"ledgerVersion", "ext"},
+	reflect.TypeOf(BucketEntry{}):                                  {"type", "liveEntry", "deadEntry", "metaEntry"},
+	reflect.TypeOf(TransactionSet{}):                               {"previousLedgerHash", "txs"},
+	reflect.TypeOf(TransactionResultPair{}):                        {"transactionHash", "result"},
+	reflect.TypeOf(TransactionResultSet{}):                         {"results"},
+	reflect.TypeOf(TransactionHistoryEntryExt{}):                   {"v"},
+	reflect.TypeOf(TransactionHistoryEntry{}):                      {"ledgerSeq", "txSet", "ext"},
+	reflect.TypeOf(TransactionHistoryResultEntryExt{}):             {"v"},
+	reflect.TypeOf(TransactionHistoryResultEntry{}):                {"ledgerSeq", "txResultSet", "ext"},
+	reflect.TypeOf(LedgerHeaderHistoryEntryExt{}):                  {"v"},
+	reflect.TypeOf(LedgerHeaderHistoryEntry{}):                     {"hash", "header", "ext"},
+	reflect.TypeOf(LedgerScpMessages{}):                            {"ledgerSeq", "messages"},
+	reflect.TypeOf(ScpHistoryEntryV0{}):                            {"quorumSets", "ledgerMessages"},
+	reflect.TypeOf(ScpHistoryEntry{}):                              {"v", "v0"},
+	reflect.TypeOf(LedgerEntryChange{}):                            {"type", "created", "updated", "removed", "state"},
+	reflect.TypeOf(OperationMeta{}):                                {"changes"},
+	reflect.TypeOf(TransactionMetaV1{}):                            {"txChanges", "operations"},
+	reflect.TypeOf(TransactionMetaV2{}):                            {"txChangesBefore", "operations", "txChangesAfter"},
+	reflect.TypeOf(TransactionMeta{}):                              {"v", "operations", "v1", "v2"},
+	reflect.TypeOf(TransactionResultMeta{}):                        {"result", "feeProcessing", "txApplyProcessing"},
+	reflect.TypeOf(UpgradeEntryMeta{}):                             {"upgrade", "changes"},
+	reflect.TypeOf(LedgerCloseMetaV0{}):                            {"ledgerHeader", "txSet", "txProcessing", "upgradesProcessing", "scpInfo"},
+	reflect.TypeOf(LedgerCloseMeta{}):                              {"v", "v0"},
+	reflect.TypeOf(Error{}):                                        {"code", "msg"},
+	reflect.TypeOf(AuthCert{}):                                     {"pubkey", "expiration", "sig"},
+	reflect.TypeOf(Hello{}):                                        {"ledgerVersion", "overlayVersion", "overlayMinVersion", "networkID", "versionStr", "listeningPort", "peerID", "cert", "nonce"},
+	reflect.TypeOf(Auth{}):                                         {"unused"},
+	reflect.TypeOf(PeerAddressIp{}):                                {"type", "ipv4", "ipv6"},
+	reflect.TypeOf(PeerAddress{}):                                  {"ip", "port", "numFailures"},
+	reflect.TypeOf(DontHave{}):                                     {"type", "reqHash"},
+	reflect.TypeOf(SurveyRequestMessage{}):                         {"surveyorPeerID", "surveyedPeerID", "ledgerNum", "encryptionKey", "commandType"},
+	reflect.TypeOf(SignedSurveyRequestMessage{}):                   {"requestSignature", "request"},
+	reflect.TypeOf(SurveyResponseMessage{}):                        {"surveyorPeerID", "surveyedPeerID", "ledgerNum", "commandType", "encryptedBody"},
+	reflect.TypeOf(SignedSurveyResponseMessage{}):                  {"responseSignature", "response"},
+	reflect.TypeOf(PeerStats{}):                                    {"id", "versionStr", "messagesRead", "messagesWritten", "bytesRead", "bytesWritten", "secondsConnected", "uniqueFloodBytesRecv", "duplicateFloodBytesRecv", "uniqueFetchBytesRecv", "duplicateFetchBytesRecv", "uniqueFloodMessageRecv", "duplicateFloodMessageRecv", "uniqueFetchMessageRecv", "duplicateFetchMessageRecv"},
+	reflect.TypeOf(TopologyResponseBody{}):                         {"inboundPeers", "outboundPeers", "totalInboundPeerCount", "totalOutboundPeerCount"},
+	reflect.TypeOf(SurveyResponseBody{}):                           {"type", "topologyResponseBody"},
+	reflect.TypeOf(StellarMessage{}):                               {"type", "error", "hello", "auth", "dontHave", "peers", "txSetHash", "txSet", "transaction", "signedSurveyRequestMessage", "signedSurveyResponseMessage", "qSetHash", "qSet", "envelope", "getSCPLedgerSeq"},
+	reflect.TypeOf(AuthenticatedMessageV0{}):                       {"sequence", "message", "mac"},
+	reflect.TypeOf(AuthenticatedMessage{}):                         {"v", "v0"},
+	reflect.TypeOf(MuxedAccountMed25519{}):                         {"id", "ed25519"},
+	reflect.TypeOf(MuxedAccount{}):                                 {"type", "ed25519", "med25519"},
+	reflect.TypeOf(DecoratedSignature{}):                           {"hint", "signature"},
+	reflect.TypeOf(CreateAccountOp{}):                              {"destination", "startingBalance"},
+	reflect.TypeOf(PaymentOp{}):                                    {"destination", "asset", "amount"},
+	reflect.TypeOf(PathPaymentStrictReceiveOp{}):                   {"sendAsset", "sendMax", "destination", "destAsset", "destAmount", "path"},
+	reflect.TypeOf(PathPaymentStrictSendOp{}):                      {"sendAsset", "sendAmount", "destination", "destAsset", "destMin", "path"},
+	reflect.TypeOf(ManageSellOfferOp{}):                            {"selling", "buying", "amount", "price", "offerID"},
+	reflect.TypeOf(ManageBuyOfferOp{}):                             {"selling", "buying", "buyAmount", "price", "offerID"},
+	reflect.TypeOf(CreatePassiveSellOfferOp{}):                     {"selling", "buying", "amount", "price"},
+	reflect.TypeOf(SetOptionsOp{}):                                 {"inflationDest", "clearFlags", "setFlags", "masterWeight", "lowThreshold", "medThreshold", "highThreshold", "homeDomain", "signer"},
+	reflect.TypeOf(ChangeTrustOp{}):                                {"line", "limit"},
+	reflect.TypeOf(AllowTrustOp{}):                                 {"trustor", "asset", "authorize"},
+	reflect.TypeOf(ManageDataOp{}):                                 {"dataName", "dataValue"},
+	reflect.TypeOf(BumpSequenceOp{}):                               {"bumpTo"},
+	reflect.TypeOf(CreateClaimableBalanceOp{}):                     {"asset", "amount", "claimants"},
+	reflect.TypeOf(ClaimClaimableBalanceOp{}):                      {"balanceID"},
+	reflect.TypeOf(BeginSponsoringFutureReservesOp{}):              {"sponsoredID"},
+	reflect.TypeOf(RevokeSponsorshipOpSigner{}):                    {"accountID", "signerKey"},
+	reflect.TypeOf(RevokeSponsorshipOp{}):                          {"type", "ledgerKey", "signer"},
+	reflect.TypeOf(ClawbackOp{}):                                   {"asset", "from", "amount"},
+	reflect.TypeOf(ClawbackClaimableBalanceOp{}):                   {"balanceID"},
+	reflect.TypeOf(SetTrustLineFlagsOp{}):                          {"trustor", "asset", "clearFlags", "setFlags"},
+	reflect.TypeOf(OperationBody{}):                                {"type", "createAccountOp", "paymentOp", "pathPaymentStrictReceiveOp", "manageSellOfferOp", "createPassiveSellOfferOp", "setOptionsOp", "changeTrustOp", "allowTrustOp", "destination", "manageDataOp", "bumpSequenceOp", "manageBuyOfferOp", "pathPaymentStrictSendOp", "createClaimableBalanceOp", "claimClaimableBalanceOp", "beginSponsoringFutureReservesOp", "revokeSponsorshipOp", "clawbackOp", "clawbackClaimableBalanceOp", "setTrustLineFlagsOp"},
+	reflect.TypeOf(Operation{}):                                    {"sourceAccount", "body"},
+	reflect.TypeOf(OperationIdId{}):                                {"sourceAccount", "seqNum", "opNum"},
+	reflect.TypeOf(OperationId{}):                                  {"type", "id"},
+	reflect.TypeOf(Memo{}):                                         {"type", "text", "id", "hash", "retHash"},
+	reflect.TypeOf(TimeBounds{}):                                   {"minTime", "maxTime"},
+	reflect.TypeOf(TransactionV0Ext{}):                             {"v"},
+	reflect.TypeOf(TransactionV0{}):                                {"sourceAccountEd25519", "fee", "seqNum", "timeBounds", "memo", "operations", "ext"},
+	reflect.TypeOf(TransactionV0Envelope{}):                        {"tx", "signatures"},
+	reflect.TypeOf(TransactionExt{}):                               {"v"},
+	reflect.TypeOf(Transaction{}):                                  {"sourceAccount", "fee", "seqNum", "timeBounds", "memo", "operations", "ext"},
+	reflect.TypeOf(TransactionV1Envelope{}):                        {"tx", "signatures"},
+	reflect.TypeOf(FeeBumpTransactionInnerTx{}):                    {"type", "v1"},
+	reflect.TypeOf(FeeBumpTransactionExt{}):                        {"v"},
+	reflect.TypeOf(FeeBumpTransaction{}):                           {"feeSource", "fee", "innerTx", "ext"},
+	reflect.TypeOf(FeeBumpTransactionEnvelope{}):                   {"tx", "signatures"},
+	reflect.TypeOf(TransactionEnvelope{}):                          {"type", "v0", "v1", "feeBump"},
+	reflect.TypeOf(TransactionSignaturePayloadTaggedTransaction{}): {"type", "tx", "feeBump"},
+	reflect.TypeOf(TransactionSignaturePayload{}):                  {"networkId", "taggedTransaction"},
+	reflect.TypeOf(ClaimOfferAtom{}):                               {"sellerID", "offerID", "assetSold", "amountSold", "assetBought", "amountBought"},
+	reflect.TypeOf(CreateAccountResult{}):                          {"code"},
+	reflect.TypeOf(PaymentResult{}):                                {"code"},
+	reflect.TypeOf(SimplePaymentResult{}):                          {"destination", "asset", "amount"},
+	reflect.TypeOf(PathPaymentStrictReceiveResultSuccess{}):        {"offers", "last"},
+	reflect.TypeOf(PathPaymentStrictReceiveResult{}):               {"code", "success", "noIssuer"},
+	reflect.TypeOf(PathPaymentStrictSendResultSuccess{}):           {"offers", "last"},
+	reflect.TypeOf(PathPaymentStrictSendResult{}):                  {"code", "success", "noIssuer"},
+	reflect.TypeOf(ManageOfferSuccessResultOffer{}):                {"effect", "offer"},
+	reflect.TypeOf(ManageOfferSuccessResult{}):                     {"offersClaimed", "offer"},
+	reflect.TypeOf(ManageSellOfferResult{}):                        {"code", "success"},
+	reflect.TypeOf(ManageBuyOfferResult{}):                         {"code", "success"},
+	reflect.TypeOf(SetOptionsResult{}):                             {"code"},
+	reflect.TypeOf(ChangeTrustResult{}):                            {"code"},
+	reflect.TypeOf(AllowTrustResult{}):                             {"code"},
+	reflect.TypeOf(AccountMergeResult{}):                           {"code", "sourceAccountBalance"},
+	reflect.TypeOf(InflationPayout{}):                              {"destination", "amount"},
+	reflect.TypeOf(InflationResult{}):                              {"code", "payouts"},
+	reflect.TypeOf(ManageDataResult{}):                             {"code"},
+	reflect.TypeOf(BumpSequenceResult{}):                           {"code"},
+	reflect.TypeOf(CreateClaimableBalanceResult{}):                 {"code", "balanceID"},
+	reflect.TypeOf(ClaimClaimableBalanceResult{}):                  {"code"},
+	reflect.TypeOf(BeginSponsoringFutureReservesResult{}):          {"code"},
+	reflect.TypeOf(EndSponsoringFutureReservesResult{}):            {"code"},
+	reflect.TypeOf(RevokeSponsorshipResult{}):                      {"code"},
+	reflect.TypeOf(ClawbackResult{}):                               {"code"},
+	reflect.TypeOf(ClawbackClaimableBalanceResult{}):               {"code"},
+	reflect.TypeOf(SetTrustLineFlagsResult{}):                      {"code"},
+	reflect.TypeOf(OperationResultTr{}):                            {"type", "createAccountResult", "paymentResult", "pathPaymentStrictReceiveResult", "manageSellOfferResult", "createPassiveSellOfferResult", "setOptionsResult", "changeTrustResult", "allowTrustResult", "accountMergeResult", "inflationResult", "manageDataResult", "bumpSeqResult", "manageBuyOfferResult", "pathPaymentStrictSendResult", "createClaimableBalanceResult", "claimClaimableBalanceResult", "beginSponsoringFutureReservesResult", "endSponsoringFutureReservesResult", "revokeSponsorshipResult", "clawbackResult", "clawbackClaimableBalanceResult", "setTrustLineFlagsResult"},
+	reflect.TypeOf(OperationResult{}):                              {"code", "tr"},
+	reflect.TypeOf(InnerTransactionResultResult{}):                 {"code", "results"},
+	reflect.TypeOf(InnerTransactionResultExt{}):                    {"v"},
+	reflect.TypeOf(InnerTransactionResult{}):                       {"feeCharged", "result", "ext"},
+	reflect.TypeOf(InnerTransactionResultPair{}):                   {"transactionHash", "result"},
+	reflect.TypeOf(TransactionResultResult{}):                      {"code", "innerResultPair", "results"},
+	reflect.TypeOf(TransactionResultExt{}):                         {"v"},
+	reflect.TypeOf(TransactionResult{}):                            {"feeCharged", "result", "ext"},
+	reflect.TypeOf(PublicKey{}):                                    {"type", "ed25519"},
+	reflect.TypeOf(SignerKeyEd25519SignedPayload{}):                {"ed25519", "payload"},
+	reflect.TypeOf(SignerKey{}):                                    {"type", "ed25519", "preAuthTx", "hashX", "ed25519SignedPayload"},
+	reflect.TypeOf(Curve25519Secret{}):                             {"key"},
+	reflect.TypeOf(Curve25519Public{}):                             {"key"},
+	reflect.TypeOf(HmacSha256Key{}):                                {"key"},
+	reflect.TypeOf(HmacSha256Mac{}):                                {"mac"},
+}
+
+// jsonEnumNames are the XDR names of the constants of the enums.
+var jsonEnumNames = map[reflect.Type]map[int32]string{
+	reflect.TypeOf(ScpStatementType(0)): {
+		int32(ScpStatementTypeScpStPrepare):     "SCP_ST_PREPARE",
+		int32(ScpStatementTypeScpStConfirm):     "SCP_ST_CONFIRM",
+		int32(ScpStatementTypeScpStExternalize): "SCP_ST_EXTERNALIZE",
+		int32(ScpStatementTypeScpStNominate):    "SCP_ST_NOMINATE",
+	},
+	reflect.TypeOf(AssetType(0)): {
+		int32(AssetTypeAssetTypeNative):           "ASSET_TYPE_NATIVE",
+		int32(AssetTypeAssetTypeCreditAlphanum4):  "ASSET_TYPE_CREDIT_ALPHANUM4",
+		int32(AssetTypeAssetTypeCreditAlphanum12): "ASSET_TYPE_CREDIT_ALPHANUM12",
+	},
+	reflect.TypeOf(ThresholdIndexes(0)): {
+		int32(ThresholdIndexesThresholdMasterWeight): "THRESHOLD_MASTER_WEIGHT",
+		int32(ThresholdIndexesThresholdLow):          "THRESHOLD_LOW",
+		int32(ThresholdIndexesThresholdMed):          "THRESHOLD_MED",
+		int32(ThresholdIndexesThresholdHigh):         "THRESHOLD_HIGH",
+	},
+	reflect.TypeOf(LedgerEntryType(0)): {
+		int32(LedgerEntryTypeAccount):          "ACCOUNT",
+		int32(LedgerEntryTypeTrustline):        "TRUSTLINE",
+		int32(LedgerEntryTypeOffer):            "OFFER",
+		int32(LedgerEntryTypeData):             "DATA",
+		int32(LedgerEntryTypeClaimableBalance): "CLAIMABLE_BALANCE",
+	},
+	reflect.TypeOf(AccountFlags(0)): {
+		int32(AccountFlagsAuthRequiredFlag):        "AUTH_REQUIRED_FLAG",
+		int32(AccountFlagsAuthRevocableFlag):       "AUTH_REVOCABLE_FLAG",
+		int32(AccountFlagsAuthImmutableFlag):       "AUTH_IMMUTABLE_FLAG",
+		int32(AccountFlagsAuthClawbackEnabledFlag): "AUTH_CLAWBACK_ENABLED_FLAG",
+	},
+	reflect.TypeOf(TrustLineFlags(0)): {
+		int32(TrustLineFlagsAuthorizedFlag):                      "AUTHORIZED_FLAG",
+		int32(TrustLineFlagsAuthorizedToMaintainLiabilitiesFlag): "AUTHORIZED_TO_MAINTAIN_LIABILITIES_FLAG",
+		int32(TrustLineFlagsTrustlineClawbackEnabledFlag):        "TRUSTLINE_CLAWBACK_ENABLED_FLAG",
+	},
+	reflect.TypeOf(OfferEntryFlags(0)): {
+		int32(OfferEntryFlagsPassiveFlag): "PASSIVE_FLAG",
+	},
+	reflect.TypeOf(ClaimPredicateType(0)): {
+		int32(ClaimPredicateTypeClaimPredicateUnconditional):      "CLAIM_PREDICATE_UNCONDITIONAL",
+		int32(ClaimPredicateTypeClaimPredicateAnd):                "CLAIM_PREDICATE_AND",
+		int32(ClaimPredicateTypeClaimPredicateOr):                 "CLAIM_PREDICATE_OR",
+		int32(ClaimPredicateTypeClaimPredicateNot):                "CLAIM_PREDICATE_NOT",
+		int32(ClaimPredicateTypeClaimPredicateBeforeAbsoluteTime): "CLAIM_PREDICATE_BEFORE_ABSOLUTE_TIME",
+		int32(ClaimPredicateTypeClaimPredicateBeforeRelativeTime): "CLAIM_PREDICATE_BEFORE_RELATIVE_TIME",
+	},
+	reflect.TypeOf(ClaimantType(0)): {
+		int32(ClaimantTypeClaimantTypeV0): "CLAIMANT_TYPE_V0",
+	},
+	reflect.TypeOf(ClaimableBalanceIdType(0)): {
+		int32(ClaimableBalanceIdTypeClaimableBalanceIdTypeV0): "CLAIMABLE_BALANCE_ID_TYPE_V0",
+	},
+	reflect.TypeOf(ClaimableBalanceFlags(0)): {
+		int32(ClaimableBalanceFlagsClaimableBalanceClawbackEnabledFlag): "CLAIMABLE_BALANCE_CLAWBACK_ENABLED_FLAG",
+	},
+	reflect.TypeOf(EnvelopeType(0)): {
+		int32(EnvelopeTypeEnvelopeTypeTxV0):      "ENVELOPE_TYPE_TX_V0",
+		int32(EnvelopeTypeEnvelopeTypeScp):       "ENVELOPE_TYPE_SCP",
+		int32(EnvelopeTypeEnvelopeTypeTx):        "ENVELOPE_TYPE_TX",
+		int32(EnvelopeTypeEnvelopeTypeAuth):      "ENVELOPE_TYPE_AUTH",
+		int32(EnvelopeTypeEnvelopeTypeScpvalue):  "ENVELOPE_TYPE_SCPVALUE",
+		int32(EnvelopeTypeEnvelopeTypeTxFeeBump): "ENVELOPE_TYPE_TX_FEE_BUMP",
+		int32(EnvelopeTypeEnvelopeTypeOpId):      "ENVELOPE_TYPE_OP_ID",
+	},
+	reflect.TypeOf(StellarValueType(0)): {
+		int32(StellarValueTypeStellarValueBasic):  "STELLAR_VALUE_BASIC",
+		int32(StellarValueTypeStellarValueSigned): "STELLAR_VALUE_SIGNED",
+	},
+	reflect.TypeOf(LedgerUpgradeType(0)): {
+		int32(LedgerUpgradeTypeLedgerUpgradeVersion):      "LEDGER_UPGRADE_VERSION",
+		int32(LedgerUpgradeTypeLedgerUpgradeBaseFee):      "LEDGER_UPGRADE_BASE_FEE",
+		int32(LedgerUpgradeTypeLedgerUpgradeMaxTxSetSize): "LEDGER_UPGRADE_MAX_TX_SET_SIZE",
+		int32(LedgerUpgradeTypeLedgerUpgradeBaseReserve):  "LEDGER_UPGRADE_BASE_RESERVE",
+	},
+	reflect.TypeOf(BucketEntryType(0)): {
+		int32(BucketEntryTypeMetaentry): "METAENTRY",
+		int32(BucketEntryTypeLiveentry): "LIVEENTRY",
+		int32(BucketEntryTypeDeadentry): "DEADENTRY",
+		int32(BucketEntryTypeInitentry): "INITENTRY",
+	},
+	reflect.TypeOf(LedgerEntryChangeType(0)): {
+		int32(LedgerEntryChangeTypeLedgerEntryCreated): "LEDGER_ENTRY_CREATED",
+		int32(LedgerEntryChangeTypeLedgerEntryUpdated): "LEDGER_ENTRY_UPDATED",
+		int32(LedgerEntryChangeTypeLedgerEntryRemoved): "LEDGER_ENTRY_REMOVED",
+		int32(LedgerEntryChangeTypeLedgerEntryState):   "LEDGER_ENTRY_STATE",
+	},
+	reflect.TypeOf(ErrorCode(0)): {
+		int32(ErrorCodeErrMisc): "ERR_MISC",
+		int32(ErrorCodeErrData): "ERR_DATA",
+		int32(ErrorCodeErrConf): "ERR_CONF",
+		int32(ErrorCodeErrAuth): "ERR_AUTH",
+		int32(ErrorCodeErrLoad): "ERR_LOAD",
+	},
+	reflect.TypeOf(IpAddrType(0)): {
+		int32(IpAddrTypeIPv4): "IPv4",
+		int32(IpAddrTypeIPv6): "IPv6",
+	},
+	reflect.TypeOf(MessageType(0)): {
+		int32(MessageTypeErrorMsg):        "ERROR_MSG",
+		int32(MessageTypeAuth):            "AUTH",
+		int32(MessageTypeDontHave):        "DONT_HAVE",
+		int32(MessageTypeGetPeers):        "GET_PEERS",
+		int32(MessageTypePeers):           "PEERS",
+		int32(MessageTypeGetTxSet):        "GET_TX_SET",
+		int32(MessageTypeTxSet):           "TX_SET",
+		int32(MessageTypeTransaction):     "TRANSACTION",
+		int32(MessageTypeGetScpQuorumset): "GET_SCP_QUORUMSET",
+		int32(MessageTypeScpQuorumset):    "SCP_QUORUMSET",
+		int32(MessageTypeScpMessage):      "SCP_MESSAGE",
+		int32(MessageTypeGetScpState):     "GET_SCP_STATE",
+		int32(MessageTypeHello):           "HELLO",
+		int32(MessageTypeSurveyRequest):   "SURVEY_REQUEST",
+		int32(MessageTypeSurveyResponse):  "SURVEY_RESPONSE",
+	},
+	reflect.TypeOf(SurveyMessageCommandType(0)): {
+		int32(SurveyMessageCommandTypeSurveyTopology): "SURVEY_TOPOLOGY",
+	},
+	reflect.TypeOf(OperationType(0)): {
+		int32(OperationTypeCreateAccount):                 "CREATE_ACCOUNT",
+		int32(OperationTypePayment):                       "PAYMENT",
+		int32(OperationTypePathPaymentStrictReceive):      "PATH_PAYMENT_STRICT_RECEIVE",
+		int32(OperationTypeManageSellOffer):               "MANAGE_SELL_OFFER",
+		int32(OperationTypeCreatePassiveSellOffer):        "CREATE_PASSIVE_SELL_OFFER",
+		int32(OperationTypeSetOptions):                    "SET_OPTIONS",
+		int32(OperationTypeChangeTrust):                   "CHANGE_TRUST",
+		int32(OperationTypeAllowTrust):                    "ALLOW_TRUST",
+		int32(OperationTypeAccountMerge):                  "ACCOUNT_MERGE",
+		int32(OperationTypeInflation):                     "INFLATION",
+		int32(OperationTypeManageData):                    "MANAGE_DATA",
+		int32(OperationTypeBumpSequence):                  "BUMP_SEQUENCE",
+		int32(OperationTypeManageBuyOffer):                "MANAGE_BUY_OFFER",
+		int32(OperationTypePathPaymentStrictSend):         "PATH_PAYMENT_STRICT_SEND",
+		int32(OperationTypeCreateClaimableBalance):        "CREATE_CLAIMABLE_BALANCE",
+		int32(OperationTypeClaimClaimableBalance):         "CLAIM_CLAIMABLE_BALANCE",
+		int32(OperationTypeBeginSponsoringFutureReserves): "BEGIN_SPONSORING_FUTURE_RESERVES",
+		int32(OperationTypeEndSponsoringFutureReserves):   "END_SPONSORING_FUTURE_RESERVES",
+		int32(OperationTypeRevokeSponsorship):             "REVOKE_SPONSORSHIP",
+		int32(OperationTypeClawback):                      "CLAWBACK",
+		int32(OperationTypeClawbackClaimableBalance):      "CLAWBACK_CLAIMABLE_BALANCE",
+		int32(OperationTypeSetTrustLineFlags):             "SET_TRUST_LINE_FLAGS",
+	},
+	reflect.TypeOf(RevokeSponsorshipType(0)): {
+		int32(RevokeSponsorshipTypeRevokeSponsorshipLedgerEntry): "REVOKE_SPONSORSHIP_LEDGER_ENTRY",
+		int32(RevokeSponsorshipTypeRevokeSponsorshipSigner):      "REVOKE_SPONSORSHIP_SIGNER",
+	},
+	reflect.TypeOf(MemoType(0)): {
+		int32(MemoTypeMemoNone):   "MEMO_NONE",
+		int32(MemoTypeMemoText):   "MEMO_TEXT",
+		int32(MemoTypeMemoId):     "MEMO_ID",
+		int32(MemoTypeMemoHash):   "MEMO_HASH",
+		int32(MemoTypeMemoReturn): "MEMO_RETURN",
+	},
+	reflect.TypeOf(CreateAccountResultCode(0)): {
+		int32(CreateAccountResultCodeCreateAccountSuccess):      "CREATE_ACCOUNT_SUCCESS",
+		int32(CreateAccountResultCodeCreateAccountMalformed):    "CREATE_ACCOUNT_MALFORMED",
+		int32(CreateAccountResultCodeCreateAccountUnderfunded):  "CREATE_ACCOUNT_UNDERFUNDED",
+		int32(CreateAccountResultCodeCreateAccountLowReserve):   "CREATE_ACCOUNT_LOW_RESERVE",
+		int32(CreateAccountResultCodeCreateAccountAlreadyExist): "CREATE_ACCOUNT_ALREADY_EXIST",
+	},
+	reflect.TypeOf(PaymentResultCode(0)): {
+		int32(PaymentResultCodePaymentSuccess):          "PAYMENT_SUCCESS",
+		int32(PaymentResultCodePaymentMalformed):        "PAYMENT_MALFORMED",
+		int32(PaymentResultCodePaymentUnderfunded):      "PAYMENT_UNDERFUNDED",
+		int32(PaymentResultCodePaymentSrcNoTrust):       "PAYMENT_SRC_NO_TRUST",
+		int32(PaymentResultCodePaymentSrcNotAuthorized): "PAYMENT_SRC_NOT_AUTHORIZED",
+		int32(PaymentResultCodePaymentNoDestination):    "PAYMENT_NO_DESTINATION",
+		int32(PaymentResultCodePaymentNoTrust):          "PAYMENT_NO_TRUST",
+		int32(PaymentResultCodePaymentNotAuthorized):    "PAYMENT_NOT_AUTHORIZED",
+		int32(PaymentResultCodePaymentLineFull):         "PAYMENT_LINE_FULL",
+		int32(PaymentResultCodePaymentNoIssuer):         "PAYMENT_NO_ISSUER",
+	},
+	reflect.TypeOf(PathPaymentStrictReceiveResultCode(0)): {
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveSuccess):          "PATH_PAYMENT_STRICT_RECEIVE_SUCCESS",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveMalformed):        "PATH_PAYMENT_STRICT_RECEIVE_MALFORMED",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveUnderfunded):      "PATH_PAYMENT_STRICT_RECEIVE_UNDERFUNDED",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveSrcNoTrust):       "PATH_PAYMENT_STRICT_RECEIVE_SRC_NO_TRUST",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveSrcNotAuthorized): "PATH_PAYMENT_STRICT_RECEIVE_SRC_NOT_AUTHORIZED",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveNoDestination):    "PATH_PAYMENT_STRICT_RECEIVE_NO_DESTINATION",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveNoTrust):          "PATH_PAYMENT_STRICT_RECEIVE_NO_TRUST",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveNotAuthorized):    "PATH_PAYMENT_STRICT_RECEIVE_NOT_AUTHORIZED",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveLineFull):         "PATH_PAYMENT_STRICT_RECEIVE_LINE_FULL",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveNoIssuer):         "PATH_PAYMENT_STRICT_RECEIVE_NO_ISSUER",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveTooFewOffers):     "PATH_PAYMENT_STRICT_RECEIVE_TOO_FEW_OFFERS",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveOfferCrossSelf):   "PATH_PAYMENT_STRICT_RECEIVE_OFFER_CROSS_SELF",
+		int32(PathPaymentStrictReceiveResultCodePathPaymentStrictReceiveOverSendmax):      "PATH_PAYMENT_STRICT_RECEIVE_OVER_SENDMAX",
+	},
+	reflect.TypeOf(PathPaymentStrictSendResultCode(0)): {
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendSuccess):          "PATH_PAYMENT_STRICT_SEND_SUCCESS",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendMalformed):        "PATH_PAYMENT_STRICT_SEND_MALFORMED",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendUnderfunded):      "PATH_PAYMENT_STRICT_SEND_UNDERFUNDED",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendSrcNoTrust):       "PATH_PAYMENT_STRICT_SEND_SRC_NO_TRUST",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendSrcNotAuthorized): "PATH_PAYMENT_STRICT_SEND_SRC_NOT_AUTHORIZED",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendNoDestination):    "PATH_PAYMENT_STRICT_SEND_NO_DESTINATION",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendNoTrust):          "PATH_PAYMENT_STRICT_SEND_NO_TRUST",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendNotAuthorized):    "PATH_PAYMENT_STRICT_SEND_NOT_AUTHORIZED",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendLineFull):         "PATH_PAYMENT_STRICT_SEND_LINE_FULL",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendNoIssuer):         "PATH_PAYMENT_STRICT_SEND_NO_ISSUER",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendTooFewOffers):     "PATH_PAYMENT_STRICT_SEND_TOO_FEW_OFFERS",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendOfferCrossSelf):   "PATH_PAYMENT_STRICT_SEND_OFFER_CROSS_SELF",
+		int32(PathPaymentStrictSendResultCodePathPaymentStrictSendUnderDestmin):     "PATH_PAYMENT_STRICT_SEND_UNDER_DESTMIN",
+	},
+	reflect.TypeOf(ManageSellOfferResultCode(0)): {
+		int32(ManageSellOfferResultCodeManageSellOfferSuccess):           "MANAGE_SELL_OFFER_SUCCESS",
+		int32(ManageSellOfferResultCodeManageSellOfferMalformed):         "MANAGE_SELL_OFFER_MALFORMED",
+		int32(ManageSellOfferResultCodeManageSellOfferSellNoTrust):       "MANAGE_SELL_OFFER_SELL_NO_TRUST",
+		int32(ManageSellOfferResultCodeManageSellOfferBuyNoTrust):        "MANAGE_SELL_OFFER_BUY_NO_TRUST",
+		int32(ManageSellOfferResultCodeManageSellOfferSellNotAuthorized): "MANAGE_SELL_OFFER_SELL_NOT_AUTHORIZED",
+		int32(ManageSellOfferResultCodeManageSellOfferBuyNotAuthorized):  "MANAGE_SELL_OFFER_BUY_NOT_AUTHORIZED",
+		int32(ManageSellOfferResultCodeManageSellOfferLineFull):          "MANAGE_SELL_OFFER_LINE_FULL",
+		int32(ManageSellOfferResultCodeManageSellOfferUnderfunded):       "MANAGE_SELL_OFFER_UNDERFUNDED",
+		int32(ManageSellOfferResultCodeManageSellOfferCrossSelf):         "MANAGE_SELL_OFFER_CROSS_SELF",
+		int32(ManageSellOfferResultCodeManageSellOfferSellNoIssuer):      "MANAGE_SELL_OFFER_SELL_NO_ISSUER",
+		int32(ManageSellOfferResultCodeManageSellOfferBuyNoIssuer):       "MANAGE_SELL_OFFER_BUY_NO_ISSUER",
+		int32(ManageSellOfferResultCodeManageSellOfferNotFound):          "MANAGE_SELL_OFFER_NOT_FOUND",
+		int32(ManageSellOfferResultCodeManageSellOfferLowReserve):        "MANAGE_SELL_OFFER_LOW_RESERVE",
+	},
+	reflect.TypeOf(ManageOfferEffect(0)): {
+		int32(ManageOfferEffectManageOfferCreated): "MANAGE_OFFER_CREATED",
+		int32(ManageOfferEffectManageOfferUpdated): "MANAGE_OFFER_UPDATED",
+		int32(ManageOfferEffectManageOfferDeleted): "MANAGE_OFFER_DELETED",
+	},
+	reflect.TypeOf(ManageBuyOfferResultCode(0)): {
+		int32(ManageBuyOfferResultCodeManageBuyOfferSuccess):           "MANAGE_BUY_OFFER_SUCCESS",
+		int32(ManageBuyOfferResultCodeManageBuyOfferMalformed):         "MANAGE_BUY_OFFER_MALFORMED",
+		int32(ManageBuyOfferResultCodeManageBuyOfferSellNoTrust):       "MANAGE_BUY_OFFER_SELL_NO_TRUST",
+		int32(ManageBuyOfferResultCodeManageBuyOfferBuyNoTrust):        "MANAGE_BUY_OFFER_BUY_NO_TRUST",
+		int32(ManageBuyOfferResultCodeManageBuyOfferSellNotAuthorized): "MANAGE_BUY_OFFER_SELL_NOT_AUTHORIZED",
+		int32(ManageBuyOfferResultCodeManageBuyOfferBuyNotAuthorized):  "MANAGE_BUY_OFFER_BUY_NOT_AUTHORIZED",
+		int32(ManageBuyOfferResultCodeManageBuyOfferLineFull):          "MANAGE_BUY_OFFER_LINE_FULL",
+		int32(ManageBuyOfferResultCodeManageBuyOfferUnderfunded):       "MANAGE_BUY_OFFER_UNDERFUNDED",
+		int32(ManageBuyOfferResultCodeManageBuyOfferCrossSelf):         "MANAGE_BUY_OFFER_CROSS_SELF",
+		int32(ManageBuyOfferResultCodeManageBuyOfferSellNoIssuer):      "MANAGE_BUY_OFFER_SELL_NO_ISSUER",
+		int32(ManageBuyOfferResultCodeManageBuyOfferBuyNoIssuer):       "MANAGE_BUY_OFFER_BUY_NO_ISSUER",
+		int32(ManageBuyOfferResultCodeManageBuyOfferNotFound):          "MANAGE_BUY_OFFER_NOT_FOUND",
+		int32(ManageBuyOfferResultCodeManageBuyOfferLowReserve):        "MANAGE_BUY_OFFER_LOW_RESERVE",
+	},
+	reflect.TypeOf(SetOptionsResultCode(0)): {
+		int32(SetOptionsResultCodeSetOptionsSuccess):               "SET_OPTIONS_SUCCESS",
+		int32(SetOptionsResultCodeSetOptionsLowReserve):            "SET_OPTIONS_LOW_RESERVE",
+		int32(SetOptionsResultCodeSetOptionsTooManySigners):        "SET_OPTIONS_TOO_MANY_SIGNERS",
+		int32(SetOptionsResultCodeSetOptionsBadFlags):              "SET_OPTIONS_BAD_FLAGS",
+		int32(SetOptionsResultCodeSetOptionsInvalidInflation):      "SET_OPTIONS_INVALID_INFLATION",
+		int32(SetOptionsResultCodeSetOptionsCantChange):            "SET_OPTIONS_CANT_CHANGE",
+		int32(SetOptionsResultCodeSetOptionsUnknownFlag):           "SET_OPTIONS_UNKNOWN_FLAG",
+		int32(SetOptionsResultCodeSetOptionsThresholdOutOfRange):   "SET_OPTIONS_THRESHOLD_OUT_OF_RANGE",
+		int32(SetOptionsResultCodeSetOptionsBadSigner):             "SET_OPTIONS_BAD_SIGNER",
+		int32(SetOptionsResultCodeSetOptionsInvalidHomeDomain):     "SET_OPTIONS_INVALID_HOME_DOMAIN",
+		int32(SetOptionsResultCodeSetOptionsAuthRevocableRequired): "SET_OPTIONS_AUTH_REVOCABLE_REQUIRED",
+	},
+	reflect.TypeOf(ChangeTrustResultCode(0)): {
+		int32(ChangeTrustResultCodeChangeTrustSuccess):        "CHANGE_TRUST_SUCCESS",
+		int32(ChangeTrustResultCodeChangeTrustMalformed):      "CHANGE_TRUST_MALFORMED",
+		int32(ChangeTrustResultCodeChangeTrustNoIssuer):       "CHANGE_TRUST_NO_ISSUER",
+		int32(ChangeTrustResultCodeChangeTrustInvalidLimit):   "CHANGE_TRUST_INVALID_LIMIT",
+		int32(ChangeTrustResultCodeChangeTrustLowReserve):     "CHANGE_TRUST_LOW_RESERVE",
+		int32(ChangeTrustResultCodeChangeTrustSelfNotAllowed): "CHANGE_TRUST_SELF_NOT_ALLOWED",
+	},
+	reflect.TypeOf(AllowTrustResultCode(0)): {
+		int32(AllowTrustResultCodeAllowTrustSuccess):          "ALLOW_TRUST_SUCCESS",
+		int32(AllowTrustResultCodeAllowTrustMalformed):        "ALLOW_TRUST_MALFORMED",
+		int32(AllowTrustResultCodeAllowTrustNoTrustLine):      "ALLOW_TRUST_NO_TRUST_LINE",
+		int32(AllowTrustResultCodeAllowTrustTrustNotRequired): "ALLOW_TRUST_TRUST_NOT_REQUIRED",
+		int32(AllowTrustResultCodeAllowTrustCantRevoke):       "ALLOW_TRUST_CANT_REVOKE",
+		int32(AllowTrustResultCodeAllowTrustSelfNotAllowed):   "ALLOW_TRUST_SELF_NOT_ALLOWED",
+	},
+	reflect.TypeOf(AccountMergeResultCode(0)): {
+		int32(AccountMergeResultCodeAccountMergeSuccess):       "ACCOUNT_MERGE_SUCCESS",
+		int32(AccountMergeResultCodeAccountMergeMalformed):     "ACCOUNT_MERGE_MALFORMED",
+		int32(AccountMergeResultCodeAccountMergeNoAccount):     "ACCOUNT_MERGE_NO_ACCOUNT",
+		int32(AccountMergeResultCodeAccountMergeImmutableSet):  "ACCOUNT_MERGE_IMMUTABLE_SET",
+		int32(AccountMergeResultCodeAccountMergeHasSubEntries): "ACCOUNT_MERGE_HAS_SUB_ENTRIES",
+		int32(AccountMergeResultCodeAccountMergeSeqnumTooFar):  "ACCOUNT_MERGE_SEQNUM_TOO_FAR",
+		int32(AccountMergeResultCodeAccountMergeDestFull):      "ACCOUNT_MERGE_DEST_FULL",
+		int32(AccountMergeResultCodeAccountMergeIsSponsor):     "ACCOUNT_MERGE_IS_SPONSOR",
+	},
+	reflect.TypeOf(InflationResultCode(0)): {
+		int32(InflationResultCodeInflationSuccess): "INFLATION_SUCCESS",
+		int32(InflationResultCodeInflationNotTime): "INFLATION_NOT_TIME",
+	},
+	reflect.TypeOf(ManageDataResultCode(0)): {
+		int32(ManageDataResultCodeManageDataSuccess):         "MANAGE_DATA_SUCCESS",
+		int32(ManageDataResultCodeManageDataNotSupportedYet): "MANAGE_DATA_NOT_SUPPORTED_YET",
+		int32(ManageDataResultCodeManageDataNameNotFound):    "MANAGE_DATA_NAME_NOT_FOUND",
+		int32(ManageDataResultCodeManageDataLowReserve):      "MANAGE_DATA_LOW_RESERVE",
+		int32(ManageDataResultCodeManageDataInvalidName):     "MANAGE_DATA_INVALID_NAME",
+	},
+	reflect.TypeOf(BumpSequenceResultCode(0)): {
+		int32(BumpSequenceResultCodeBumpSequenceSuccess): "BUMP_SEQUENCE_SUCCESS",
+		int32(BumpSequenceResultCodeBumpSequenceBadSeq):  "BUMP_SEQUENCE_BAD_SEQ",
+	},
+	reflect.TypeOf(CreateClaimableBalanceResultCode(0)): {
+		int32(CreateClaimableBalanceResultCodeCreateClaimableBalanceSuccess):       "CREATE_CLAIMABLE_BALANCE_SUCCESS",
+		int32(CreateClaimableBalanceResultCodeCreateClaimableBalanceMalformed):     "CREATE_CLAIMABLE_BALANCE_MALFORMED",
+		int32(CreateClaimableBalanceResultCodeCreateClaimableBalanceLowReserve):    "CREATE_CLAIMABLE_BALANCE_LOW_RESERVE",
+		int32(CreateClaimableBalanceResultCodeCreateClaimableBalanceNoTrust):       "CREATE_CLAIMABLE_BALANCE_NO_TRUST",
+		int32(CreateClaimableBalanceResultCodeCreateClaimableBalanceNotAuthorized): "CREATE_CLAIMABLE_BALANCE_NOT_AUTHORIZED",
+		int32(CreateClaimableBalanceResultCodeCreateClaimableBalanceUnderfunded):   "CREATE_CLAIMABLE_BALANCE_UNDERFUNDED",
+	},
+	reflect.TypeOf(ClaimClaimableBalanceResultCode(0)): {
+		int32(ClaimClaimableBalanceResultCodeClaimClaimableBalanceSuccess):       "CLAIM_CLAIMABLE_BALANCE_SUCCESS",
+		int32(ClaimClaimableBalanceResultCodeClaimClaimableBalanceDoesNotExist):  "CLAIM_CLAIMABLE_BALANCE_DOES_NOT_EXIST",
+		int32(ClaimClaimableBalanceResultCodeClaimClaimableBalanceCannotClaim):   "CLAIM_CLAIMABLE_BALANCE_CANNOT_CLAIM",
+		int32(ClaimClaimableBalanceResultCodeClaimClaimableBalanceLineFull):      "CLAIM_CLAIMABLE_BALANCE_LINE_FULL",
+		int32(ClaimClaimableBalanceResultCodeClaimClaimableBalanceNoTrust):       "CLAIM_CLAIMABLE_BALANCE_NO_TRUST",
+		int32(ClaimClaimableBalanceResultCodeClaimClaimableBalanceNotAuthorized): "CLAIM_CLAIMABLE_BALANCE_NOT_AUTHORIZED",
+	},
+	reflect.TypeOf(BeginSponsoringFutureReservesResultCode(0)): {
+		int32(BeginSponsoringFutureReservesResultCodeBeginSponsoringFutureReservesSuccess):          "BEGIN_SPONSORING_FUTURE_RESERVES_SUCCESS",
+		int32(BeginSponsoringFutureReservesResultCodeBeginSponsoringFutureReservesMalformed):        "BEGIN_SPONSORING_FUTURE_RESERVES_MALFORMED",
+		int32(BeginSponsoringFutureReservesResultCodeBeginSponsoringFutureReservesAlreadySponsored): "BEGIN_SPONSORING_FUTURE_RESERVES_ALREADY_SPONSORED",
+		int32(BeginSponsoringFutureReservesResultCodeBeginSponsoringFutureReservesRecursive):        "BEGIN_SPONSORING_FUTURE_RESERVES_RECURSIVE",
+	},
+	reflect.TypeOf(EndSponsoringFutureReservesResultCode(0)): {
+		int32(EndSponsoringFutureReservesResultCodeEndSponsoringFutureReservesSuccess):      "END_SPONSORING_FUTURE_RESERVES_SUCCESS",
+		int32(EndSponsoringFutureReservesResultCodeEndSponsoringFutureReservesNotSponsored): "END_SPONSORING_FUTURE_RESERVES_NOT_SPONSORED",
+	},
+	reflect.TypeOf(RevokeSponsorshipResultCode(0)): {
+		int32(RevokeSponsorshipResultCodeRevokeSponsorshipSuccess):          "REVOKE_SPONSORSHIP_SUCCESS",
+		int32(RevokeSponsorshipResultCodeRevokeSponsorshipDoesNotExist):     "REVOKE_SPONSORSHIP_DOES_NOT_EXIST",
+		int32(RevokeSponsorshipResultCodeRevokeSponsorshipNotSponsor):       "REVOKE_SPONSORSHIP_NOT_SPONSOR",
+		int32(RevokeSponsorshipResultCodeRevokeSponsorshipLowReserve):       "REVOKE_SPONSORSHIP_LOW_RESERVE",
+		int32(RevokeSponsorshipResultCodeRevokeSponsorshipOnlyTransferable): "REVOKE_SPONSORSHIP_ONLY_TRANSFERABLE",
+	},
+	reflect.TypeOf(ClawbackResultCode(0)): {
+		int32(ClawbackResultCodeClawbackSuccess):            "CLAWBACK_SUCCESS",
+		int32(ClawbackResultCodeClawbackMalformed):          "CLAWBACK_MALFORMED",
+		int32(ClawbackResultCodeClawbackNotClawbackEnabled): "CLAWBACK_NOT_CLAWBACK_ENABLED",
+		int32(ClawbackResultCodeClawbackNoTrust):            "CLAWBACK_NO_TRUST",
+		int32(ClawbackResultCodeClawbackUnderfunded):        "CLAWBACK_UNDERFUNDED",
+	},
+	reflect.TypeOf(ClawbackClaimableBalanceResultCode(0)): {
+		int32(ClawbackClaimableBalanceResultCodeClawbackClaimableBalanceSuccess):            "CLAWBACK_CLAIMABLE_BALANCE_SUCCESS",
+		int32(ClawbackClaimableBalanceResultCodeClawbackClaimableBalanceDoesNotExist):       "CLAWBACK_CLAIMABLE_BALANCE_DOES_NOT_EXIST",
+		int32(ClawbackClaimableBalanceResultCodeClawbackClaimableBalanceNotIssuer):          "CLAWBACK_CLAIMABLE_BALANCE_NOT_ISSUER",
+		int32(ClawbackClaimableBalanceResultCodeClawbackClaimableBalanceNotClawbackEnabled): "CLAWBACK_CLAIMABLE_BALANCE_NOT_CLAWBACK_ENABLED",
+	},
+	reflect.TypeOf(SetTrustLineFlagsResultCode(0)): {
+		int32(SetTrustLineFlagsResultCodeSetTrustLineFlagsSuccess):      "SET_TRUST_LINE_FLAGS_SUCCESS",
+		int32(SetTrustLineFlagsResultCodeSetTrustLineFlagsMalformed):    "SET_TRUST_LINE_FLAGS_MALFORMED",
+		int32(SetTrustLineFlagsResultCodeSetTrustLineFlagsNoTrustLine):  "SET_TRUST_LINE_FLAGS_NO_TRUST_LINE",
+		int32(SetTrustLineFlagsResultCodeSetTrustLineFlagsCantRevoke):   "SET_TRUST_LINE_FLAGS_CANT_REVOKE",
+		int32(SetTrustLineFlagsResultCodeSetTrustLineFlagsInvalidState): "SET_TRUST_LINE_FLAGS_INVALID_STATE",
+	},
+	reflect.TypeOf(OperationResultCode(0)): {
+		int32(OperationResultCodeOpInner):             "opINNER",
+		int32(OperationResultCodeOpBadAuth):           "opBAD_AUTH",
+		int32(OperationResultCodeOpNoAccount):         "opNO_ACCOUNT",
+		int32(OperationResultCodeOpNotSupported):      "opNOT_SUPPORTED",
+		int32(OperationResultCodeOpTooManySubentries): "opTOO_MANY_SUBENTRIES",
+		int32(OperationResultCodeOpExceededWorkLimit): "opEXCEEDED_WORK_LIMIT",
+		int32(OperationResultCodeOpTooManySponsoring): "opTOO_MANY_SPONSORING",
+	},
+	reflect.TypeOf(TransactionResultCode(0)): {
+		int32(TransactionResultCodeTxFeeBumpInnerSuccess): "txFEE_BUMP_INNER_SUCCESS",
+		int32(TransactionResultCodeTxSuccess):             "txSUCCESS",
+		int32(TransactionResultCodeTxFailed):              "txFAILED",
+		int32(TransactionResultCodeTxTooEarly):            "txTOO_EARLY",
+		int32(TransactionResultCodeTxTooLate):             "txTOO_LATE",
+		int32(TransactionResultCodeTxMissingOperation):    "txMISSING_OPERATION",
+		int32(TransactionResultCodeTxBadSeq):              "txBAD_SEQ",
+		int32(TransactionResultCodeTxBadAuth):             "txBAD_AUTH",
+		int32(TransactionResultCodeTxInsufficientBalance): "txINSUFFICIENT_BALANCE",
+		int32(TransactionResultCodeTxNoAccount):           "txNO_ACCOUNT",
+		int32(TransactionResultCodeTxInsufficientFee):     "txINSUFFICIENT_FEE",
+		int32(TransactionResultCodeTxBadAuthExtra):        "txBAD_AUTH_EXTRA",
+		int32(TransactionResultCodeTxInternalError):       "txINTERNAL_ERROR",
+		int32(TransactionResultCodeTxNotSupported):        "txNOT_SUPPORTED",
+		int32(TransactionResultCodeTxFeeBumpInnerFailed):  "txFEE_BUMP_INNER_FAILED",
+		int32(TransactionResultCodeTxBadSponsorship):      "txBAD_SPONSORSHIP",
+	},
+	reflect.TypeOf(CryptoKeyType(0)): {
+		int32(CryptoKeyTypeKeyTypeEd25519):              "KEY_TYPE_ED25519",
+		int32(CryptoKeyTypeKeyTypePreAuthTx):            "KEY_TYPE_PRE_AUTH_TX",
+		int32(CryptoKeyTypeKeyTypeHashX):                "KEY_TYPE_HASH_X",
+		int32(CryptoKeyTypeKeyTypeEd25519SignedPayload): "KEY_TYPE_ED25519_SIGNED_PAYLOAD",
+		int32(CryptoKeyTypeKeyTypeMuxedEd25519):         "KEY_TYPE_MUXED_ED25519",
+	},
+	reflect.TypeOf(PublicKeyType(0)): {
+		int32(PublicKeyTypePublicKeyTypeEd25519): "PUBLIC_KEY_TYPE_ED25519",
+	},
+	reflect.TypeOf(SignerKeyType(0)): {
+		int32(SignerKeyTypeSignerKeyTypeEd25519):              "SIGNER_KEY_TYPE_ED25519",
+		int32(SignerKeyTypeSignerKeyTypePreAuthTx):            "SIGNER_KEY_TYPE_PRE_AUTH_TX",
+		int32(SignerKeyTypeSignerKeyTypeHashX):                "SIGNER_KEY_TYPE_HASH_X",
+		int32(SignerKeyTypeSignerKeyTypeEd25519SignedPayload): "SIGNER_KEY_TYPE_ED25519_SIGNED_PAYLOAD",
+	},
+}
+
+// jsonTypedefs are the types the typedefs of structs and unions are
+// converted to to be encoded.
+var jsonTypedefs = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(AccountId{}): reflect.TypeOf(PublicKey{}),
+	reflect.TypeOf(NodeId{}):    reflect.TypeOf(PublicKey{}),
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Value) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Value) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpBallot) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpBallot) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpStatementType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpStatementType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpNomination) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpNomination) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpStatementPrepare) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpStatementPrepare) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpStatementConfirm) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpStatementConfirm) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpStatementExternalize) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpStatementExternalize) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpStatementPledges) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpStatementPledges) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpStatement) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpStatement) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpEnvelope) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpEnvelope) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpQuorumSet) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpQuorumSet) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountId) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountId) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Thresholds) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Thresholds) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s DataValue) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *DataValue) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AssetCode4) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AssetCode4) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AssetCode12) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AssetCode12) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AssetType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AssetType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AssetCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AssetCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AssetAlphaNum4) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AssetAlphaNum4) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AssetAlphaNum12) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AssetAlphaNum12) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Asset) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Asset) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Liabilities) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Liabilities) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ThresholdIndexes) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ThresholdIndexes) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Signer) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Signer) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountFlags) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountFlags) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountEntryExtensionV2Ext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountEntryExtensionV2Ext) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountEntryExtensionV2) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountEntryExtensionV2) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountEntryExtensionV1Ext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountEntryExtensionV1Ext) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountEntryExtensionV1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountEntryExtensionV1) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TrustLineFlags) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TrustLineFlags) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TrustLineEntryV1Ext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TrustLineEntryV1Ext) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TrustLineEntryV1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TrustLineEntryV1) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TrustLineEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TrustLineEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TrustLineEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TrustLineEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OfferEntryFlags) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OfferEntryFlags) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OfferEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OfferEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OfferEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OfferEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s DataEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *DataEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s DataEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *DataEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimPredicateType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimPredicateType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimantType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimantType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimantV0) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimantV0) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Claimant) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Claimant) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimableBalanceIdType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimableBalanceIdType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimableBalanceId) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimableBalanceId) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimableBalanceFlags) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimableBalanceFlags) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimableBalanceEntryExtensionV1Ext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimableBalanceEntryExtensionV1Ext) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimableBalanceEntryExtensionV1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimableBalanceEntryExtensionV1) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimableBalanceEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimableBalanceEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimableBalanceEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimableBalanceEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryExtensionV1Ext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryExtensionV1Ext) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryExtensionV1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryExtensionV1) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryData) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryData) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerKeyAccount) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerKeyAccount) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerKeyTrustLine) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerKeyTrustLine) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerKeyOffer) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerKeyOffer) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerKeyData) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerKeyData) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerKeyClaimableBalance) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerKeyClaimableBalance) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerKey) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerKey) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s EnvelopeType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *EnvelopeType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s UpgradeType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *UpgradeType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s StellarValueType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *StellarValueType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerCloseValueSignature) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerCloseValueSignature) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s StellarValueExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *StellarValueExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s StellarValue) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *StellarValue) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerHeaderExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerHeaderExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerHeader) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerHeader) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerUpgradeType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerUpgradeType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerUpgrade) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerUpgrade) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BucketEntryType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BucketEntryType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BucketMetadataExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BucketMetadataExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BucketMetadata) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BucketMetadata) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BucketEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BucketEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionSet) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionSet) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionResultPair) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionResultPair) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionResultSet) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionResultSet) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionHistoryEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionHistoryEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionHistoryEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionHistoryEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionHistoryResultEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionHistoryResultEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionHistoryResultEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionHistoryResultEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerHeaderHistoryEntryExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerHeaderHistoryEntryExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerHeaderHistoryEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerHeaderHistoryEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerScpMessages) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerScpMessages) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpHistoryEntryV0) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpHistoryEntryV0) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ScpHistoryEntry) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ScpHistoryEntry) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryChangeType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryChangeType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryChange) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryChange) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerEntryChanges) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerEntryChanges) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationMeta) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationMeta) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionMetaV1) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionMetaV1) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionMetaV2) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionMetaV2) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionMeta) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionMeta) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionResultMeta) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionResultMeta) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s UpgradeEntryMeta) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *UpgradeEntryMeta) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerCloseMetaV0) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerCloseMetaV0) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s LedgerCloseMeta) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *LedgerCloseMeta) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ErrorCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ErrorCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Error) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Error) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AuthCert) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AuthCert) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Hello) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Hello) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Auth) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Auth) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s IpAddrType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *IpAddrType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PeerAddressIp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PeerAddressIp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PeerAddress) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PeerAddress) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s MessageType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *MessageType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s DontHave) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *DontHave) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SurveyMessageCommandType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SurveyMessageCommandType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SurveyRequestMessage) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SurveyRequestMessage) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SignedSurveyRequestMessage) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SignedSurveyRequestMessage) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s EncryptedBody) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *EncryptedBody) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SurveyResponseMessage) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SurveyResponseMessage) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SignedSurveyResponseMessage) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SignedSurveyResponseMessage) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PeerStats) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PeerStats) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PeerStatList) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PeerStatList) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TopologyResponseBody) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TopologyResponseBody) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SurveyResponseBody) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SurveyResponseBody) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s StellarMessage) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *StellarMessage) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AuthenticatedMessageV0) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AuthenticatedMessageV0) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AuthenticatedMessage) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AuthenticatedMessage) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s MuxedAccountMed25519) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *MuxedAccountMed25519) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s MuxedAccount) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *MuxedAccount) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s DecoratedSignature) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *DecoratedSignature) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CreateAccountOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CreateAccountOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PaymentOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PaymentOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictReceiveOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictReceiveOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictSendOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictSendOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageSellOfferOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageSellOfferOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageBuyOfferOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageBuyOfferOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CreatePassiveSellOfferOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CreatePassiveSellOfferOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SetOptionsOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SetOptionsOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ChangeTrustOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ChangeTrustOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AllowTrustOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AllowTrustOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageDataOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageDataOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BumpSequenceOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BumpSequenceOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CreateClaimableBalanceOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CreateClaimableBalanceOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimClaimableBalanceOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimClaimableBalanceOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BeginSponsoringFutureReservesOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BeginSponsoringFutureReservesOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s RevokeSponsorshipType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *RevokeSponsorshipType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s RevokeSponsorshipOpSigner) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *RevokeSponsorshipOpSigner) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s RevokeSponsorshipOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *RevokeSponsorshipOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClawbackOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClawbackOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClawbackClaimableBalanceOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClawbackClaimableBalanceOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SetTrustLineFlagsOp) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SetTrustLineFlagsOp) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationBody) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationBody) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Operation) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Operation) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationIdId) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationIdId) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationId) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationId) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s MemoType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *MemoType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Memo) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Memo) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TimeBounds) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TimeBounds) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionV0Ext) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionV0Ext) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionV0) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionV0) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionV0Envelope) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionV0Envelope) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Transaction) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Transaction) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionV1Envelope) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionV1Envelope) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s FeeBumpTransactionInnerTx) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FeeBumpTransactionInnerTx) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s FeeBumpTransactionExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FeeBumpTransactionExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s FeeBumpTransaction) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FeeBumpTransaction) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s FeeBumpTransactionEnvelope) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *FeeBumpTransactionEnvelope) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionEnvelope) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionEnvelope) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionSignaturePayloadTaggedTransaction) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionSignaturePayloadTaggedTransaction) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionSignaturePayload) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionSignaturePayload) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimOfferAtom) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimOfferAtom) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CreateAccountResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CreateAccountResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CreateAccountResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CreateAccountResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PaymentResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PaymentResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PaymentResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PaymentResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictReceiveResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictReceiveResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SimplePaymentResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SimplePaymentResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictReceiveResultSuccess) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictReceiveResultSuccess) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictReceiveResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictReceiveResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictSendResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictSendResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictSendResultSuccess) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictSendResultSuccess) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PathPaymentStrictSendResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PathPaymentStrictSendResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageSellOfferResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageSellOfferResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageOfferEffect) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageOfferEffect) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageOfferSuccessResultOffer) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageOfferSuccessResultOffer) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageOfferSuccessResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageOfferSuccessResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageSellOfferResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageSellOfferResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageBuyOfferResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageBuyOfferResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageBuyOfferResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageBuyOfferResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SetOptionsResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SetOptionsResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SetOptionsResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SetOptionsResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ChangeTrustResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ChangeTrustResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ChangeTrustResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ChangeTrustResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AllowTrustResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AllowTrustResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AllowTrustResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AllowTrustResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountMergeResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountMergeResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s AccountMergeResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *AccountMergeResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s InflationResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *InflationResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s InflationPayout) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *InflationPayout) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s InflationResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *InflationResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageDataResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageDataResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ManageDataResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ManageDataResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BumpSequenceResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BumpSequenceResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BumpSequenceResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BumpSequenceResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CreateClaimableBalanceResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CreateClaimableBalanceResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CreateClaimableBalanceResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CreateClaimableBalanceResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimClaimableBalanceResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimClaimableBalanceResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClaimClaimableBalanceResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClaimClaimableBalanceResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BeginSponsoringFutureReservesResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BeginSponsoringFutureReservesResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s BeginSponsoringFutureReservesResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *BeginSponsoringFutureReservesResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s EndSponsoringFutureReservesResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *EndSponsoringFutureReservesResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s EndSponsoringFutureReservesResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *EndSponsoringFutureReservesResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s RevokeSponsorshipResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *RevokeSponsorshipResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s RevokeSponsorshipResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *RevokeSponsorshipResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClawbackResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClawbackResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClawbackResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClawbackResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClawbackClaimableBalanceResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClawbackClaimableBalanceResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ClawbackClaimableBalanceResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ClawbackClaimableBalanceResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SetTrustLineFlagsResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SetTrustLineFlagsResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SetTrustLineFlagsResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SetTrustLineFlagsResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationResultTr) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationResultTr) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s OperationResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *OperationResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionResultCode) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionResultCode) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s InnerTransactionResultResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *InnerTransactionResultResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s InnerTransactionResultExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *InnerTransactionResultExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s InnerTransactionResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *InnerTransactionResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s InnerTransactionResultPair) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *InnerTransactionResultPair) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionResultResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionResultResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionResultExt) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionResultExt) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s TransactionResult) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *TransactionResult) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Hash) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Hash) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Uint256) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Uint256) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s CryptoKeyType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *CryptoKeyType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PublicKeyType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PublicKeyType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SignerKeyType) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SignerKeyType) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s PublicKey) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *PublicKey) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SignerKeyEd25519SignedPayload) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SignerKeyEd25519SignedPayload) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SignerKey) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SignerKey) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Signature) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Signature) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s SignatureHint) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *SignatureHint) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s NodeId) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *NodeId) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Curve25519Secret) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Curve25519Secret) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Curve25519Public) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Curve25519Public) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s HmacSha256Key) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *HmacSha256Key) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s HmacSha256Mac) MarshalJSON() ([]byte, error) {
+	return marshalJSON(s)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *HmacSha256Mac) UnmarshalJSON(b []byte) error {
+	return unmarshalJSON(b, s)
+}
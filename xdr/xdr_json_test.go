@@ -0,0 +1,185 @@
+package xdr
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"testing"
+
+	"github.com/stellar/go/gxdr"
+	"github.com/stellar/go/randxdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerEntryJSON(t *testing.T) {
+	entry := LedgerEntry{
+		LastModifiedLedgerSeq: 7,
+		Data: LedgerEntryData{
+			Type: LedgerEntryTypeTrustline,
+			TrustLine: &TrustLineEntry{
+				AccountId: MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB"),
+				Asset: MustNewCreditAsset(
+					"USD", "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H",
+				),
+				Balance: 9007199254740993,
+				Limit:   math.MaxInt64,
+				Flags:   1,
+			},
+		},
+	}
+
+	serialized, err := json.Marshal(entry)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"lastModifiedLedgerSeq": 7,
+		"data": {
+			"type": "TRUSTLINE",
+			"trustLine": {
+				"accountID": "GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB",
+				"asset": {
+					"type": "ASSET_TYPE_CREDIT_ALPHANUM4",
+					"alphaNum4": {
+						"assetCode": "USD",
+						"issuer": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+					}
+				},
+				"balance": "9007199254740993",
+				"limit": "9223372036854775807",
+				"flags": 1,
+				"ext": {"v": 0}
+			}
+		},
+		"ext": {"v": 0}
+	}`, string(serialized))
+
+	var parsed LedgerEntry
+	require.NoError(t, json.Unmarshal(serialized, &parsed))
+	assert.Equal(t, entry, parsed)
+}
+
+func TestTransactionEnvelopeJSON(t *testing.T) {
+	source := MustMuxedAddress("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJUAAAAAAAAAAAACJUQ")
+	envelope := TransactionEnvelope{
+		Type: EnvelopeTypeEnvelopeTypeTx,
+		V1: &TransactionV1Envelope{
+			Tx: Transaction{
+				SourceAccount: source,
+				Fee:           100,
+				SeqNum:        1,
+				Memo:          MemoText("hello"),
+				Operations: []Operation{{
+					Body: OperationBody{
+						Type: OperationTypePayment,
+						PaymentOp: &PaymentOp{
+							Destination: source,
+							Asset:       MustNewNativeAsset(),
+							Amount:      10,
+						},
+					},
+				}},
+			},
+			Signatures: []DecoratedSignature{{
+				Hint:      SignatureHint{1, 2, 3, 4},
+				Signature: Signature{0xca, 0xfe},
+			}},
+		},
+	}
+
+	serialized, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(serialized, &doc))
+	assert.Equal(t, "ENVELOPE_TYPE_TX", doc["type"])
+	v1 := doc["v1"].(map[string]interface{})
+	tx := v1["tx"].(map[string]interface{})
+	assert.Equal(t, source.Address(), tx["sourceAccount"])
+	assert.Equal(t, "1", tx["seqNum"])
+	assert.Nil(t, tx["timeBounds"])
+	assert.Equal(t, map[string]interface{}{"type": "MEMO_TEXT", "text": "hello"}, tx["memo"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"hint":      "01020304",
+		"signature": "cafe",
+	}}, v1["signatures"])
+
+	var parsed TransactionEnvelope
+	require.NoError(t, json.Unmarshal(serialized, &parsed))
+	assert.Equal(t, envelope, parsed)
+}
+
+func TestNestedPriceJSON(t *testing.T) {
+	offer := OfferEntry{
+		SellerId: MustAddress("GAOQJGUAB7NI7K7I62ORBXMN3J4SSWQUQ7FOEPSDJ322W2HMCNWPHXFB"),
+		OfferId:  1,
+		Selling:  MustNewNativeAsset(),
+		Buying:   MustNewCreditAsset("USD", "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+		Amount:   1,
+		Price:    Price{N: 1, D: 2},
+	}
+	serialized, err := json.Marshal(offer)
+	require.NoError(t, err)
+	assert.Contains(t, string(serialized), `"price":{"n":1,"d":2}`)
+
+	// Price keeps its JSON encoding on its own, as it is used in Horizon's
+	// responses.
+	serialized, err = json.Marshal(offer.Price)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"N":1,"D":2}`, string(serialized))
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	var asset Asset
+	for _, testCase := range []struct {
+		json     string
+		expected string
+	}{
+		{`{"type": "ASSET_TYPE_NATIVE", "foo": 1}`, "unknown field"},
+		{`{"type": "ASSET_TYPE_FOO"}`, "ASSET_TYPE_FOO"},
+		{`{"type": "ASSET_TYPE_NATIVE", "alphaNum4": {"assetCode": "USD", "issuer": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"}}`, "alphaNum4"},
+		{`{"type": "ASSET_TYPE_CREDIT_ALPHANUM4"}`, "alphaNum4"},
+		{`{"type": "ASSET_TYPE_CREDIT_ALPHANUM4", "alphaNum4": {"assetCode": "USDUSD", "issuer": "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"}}`, "USDUSD"},
+		{`{"type": "ASSET_TYPE_CREDIT_ALPHANUM4", "alphaNum4": {"assetCode": "USD", "issuer": "GBAD"}}`, "issuer"},
+	} {
+		err := json.Unmarshal([]byte(testCase.json), &asset)
+		if assert.Error(t, err, testCase.json) {
+			assert.Contains(t, err.Error(), testCase.expected)
+		}
+	}
+}
+
+func TestRandJSON(t *testing.T) {
+	gen := randxdr.NewGenerator()
+	presets := []randxdr.Preset{
+		// JSON strings cannot hold arbitrary bytes
+		{Selector: randxdr.IsString, Setter: randxdr.SetAlphanumeric},
+		{
+			Selector: randxdr.FieldMatches(regexp.MustCompile(`assetCode(4|12)?$`)),
+			Setter:   randxdr.SetAlphanumeric,
+		},
+	}
+	for i := 0; i < 1000; i++ {
+		entryShape := &gxdr.LedgerEntry{}
+		gen.Next(entryShape, presets)
+		entry := &LedgerEntry{}
+		require.NoError(t, gxdr.Convert(entryShape, entry))
+		assertJSONRoundTrip(t, entry, &LedgerEntry{})
+
+		envelopeShape := &gxdr.TransactionEnvelope{}
+		gen.Next(envelopeShape, presets)
+		envelope := &TransactionEnvelope{}
+		require.NoError(t, gxdr.Convert(envelopeShape, envelope))
+		assertJSONRoundTrip(t, envelope, &TransactionEnvelope{})
+	}
+}
+
+func assertJSONRoundTrip(t *testing.T, value, parsed interface{}) {
+	serializedJSON, err := json.Marshal(value)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(serializedJSON, parsed), string(serializedJSON))
+
+	expected, err := MarshalBase64(value)
+	require.NoError(t, err)
+	actual, err := MarshalBase64(parsed)
+	require.NoError(t, err, string(serializedJSON))
+	assert.Equal(t, expected, actual, string(serializedJSON))
+}